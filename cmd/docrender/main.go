@@ -0,0 +1,42 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// The docrender command runs a gRPC service that renders package
+// documentation and READMEs for the frontend. Start the frontend with
+// -render_addr set to this service's address to use it.
+package main
+
+import (
+	"context"
+	"flag"
+	"net"
+
+	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/internal/docrender/remote"
+	"golang.org/x/pkgsite/internal/frontend"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+var (
+	// flag used in call to safehtml/template.TrustedSourceFromFlag
+	_        = flag.String("static", "static", "path to folder containing static files")
+	hostAddr = flag.String("host", "localhost:8082", "address to listen on")
+)
+
+func main() {
+	flag.Parse()
+	ctx := context.Background()
+
+	staticSource := template.TrustedSourceFromFlag(flag.Lookup("static").Value)
+	dochtml.LoadTemplates(template.TrustedFSFromTrustedSource(staticSource))
+
+	lis, err := net.Listen("tcp", *hostAddr)
+	if err != nil {
+		log.Fatal(ctx, err)
+	}
+	server := remote.NewServer(frontend.NewLocalRenderer())
+	log.Infof(ctx, "Listening on addr %s", *hostAddr)
+	log.Fatal(ctx, server.Serve(lis))
+}
@@ -10,6 +10,7 @@ import (
 	"flag"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/profiler"
@@ -19,16 +20,25 @@ import (
 	octrace "go.opencensus.io/trace"
 	"golang.org/x/pkgsite/cmd/internal/cmdconfig"
 	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/cache"
 	"golang.org/x/pkgsite/internal/config"
 	"golang.org/x/pkgsite/internal/config/serverconfig"
 	"golang.org/x/pkgsite/internal/dcensus"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docfeedback"
+	"golang.org/x/pkgsite/internal/docrender"
+	"golang.org/x/pkgsite/internal/docrender/remote"
 	"golang.org/x/pkgsite/internal/fetch"
 	"golang.org/x/pkgsite/internal/fetchdatasource"
 	"golang.org/x/pkgsite/internal/frontend"
 	"golang.org/x/pkgsite/internal/frontend/fetchserver"
+	"golang.org/x/pkgsite/internal/frontend/templates"
 	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/memory"
 	"golang.org/x/pkgsite/internal/middleware"
 	"golang.org/x/pkgsite/internal/middleware/timeout"
+	"golang.org/x/pkgsite/internal/osv"
+	"golang.org/x/pkgsite/internal/pageviews"
 	"golang.org/x/pkgsite/internal/postgres"
 	"golang.org/x/pkgsite/internal/proxy"
 	"golang.org/x/pkgsite/internal/queue"
@@ -37,6 +47,8 @@ import (
 	"golang.org/x/pkgsite/internal/static"
 	"golang.org/x/pkgsite/internal/trace"
 	"golang.org/x/pkgsite/internal/vuln"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 var (
@@ -44,6 +56,7 @@ var (
 	workers        = flag.Int("workers", 10, "number of concurrent requests to the fetch service, when running locally")
 	staticFlag     = flag.String("static", "static", "path to folder containing static files served")
 	thirdPartyPath = flag.String("third_party", "third_party", "path to folder containing third-party libraries")
+	overridesFlag  = flag.String("template_overrides", "", "path to folder containing templates that replace those in the static folder")
 	devMode        = flag.Bool("dev", false, "enable developer mode (reload templates on each page load, serve non-minified JS/CSS, etc.)")
 	localMode      = flag.Bool("local", false, "enable local mode (hide irrelevant content and links to go.dev)")
 	disableCSP     = flag.Bool("nocsp", false, "disable Content Security Policy")
@@ -53,8 +66,14 @@ var (
 		"as a direct backend, bypassing the database")
 	bypassLicenseCheck = flag.Bool("bypass_license_check", false, "display all information, even for non-redistributable paths")
 	hostAddr           = flag.String("host", "localhost:8080", "Host address for the server")
+	renderAddr         = flag.String("render_addr", "", "address of a doc-render gRPC service; if empty, render in process")
 )
 
+// defaultAdvisorySyncInterval is how often the mirror of the vulnerability
+// database is synced when private advisories are enabled and no interval is
+// configured.
+const defaultAdvisorySyncInterval = 5 * time.Minute
+
 func main() {
 	flag.Parse()
 	ctx := context.Background()
@@ -63,6 +82,7 @@ func main() {
 		log.Fatal(ctx, err)
 	}
 	cfg.Dump(os.Stderr)
+	cmdconfig.CheckDynamicConfig(ctx, cfg)
 	if cfg.UseProfiler {
 		if err := profiler.Start(profiler.Config{}); err != nil {
 			log.Fatalf(ctx, "profiler.Start: %v", err)
@@ -72,6 +92,11 @@ func main() {
 	var (
 		dsg        func(context.Context) internal.DataSource
 		fetchQueue queue.Queue
+		getAPIKey  func(context.Context, string) (*internal.APIKey, error) // nil when not using a database
+		pageViews  *pageviews.Counter                                      // nil unless counting page views
+		claims     frontend.ClaimStore                                     // nil unless module claims are enabled
+
+		getAdvisories func(context.Context) ([]*internal.Advisory, error) // nil unless private advisories are enabled
 	)
 	if *bypassLicenseCheck {
 		log.Info(ctx, "BYPASSING LICENSE CHECKING: DISPLAYING NON-REDISTRIBUTABLE INFORMATION")
@@ -81,6 +106,7 @@ func main() {
 	expg := cmdconfig.ExperimentGetter(ctx, cfg)
 	log.Infof(ctx, "cmd/frontend: initialized cmdconfig.ExperimentGetter")
 
+	reporter := cmdconfig.Reporter(ctx, cfg)
 	proxyClient, err := proxy.New(*proxyURL, &ochttp.Transport{})
 	if err != nil {
 		log.Fatal(ctx, err)
@@ -104,6 +130,21 @@ func main() {
 		}
 		defer db.Close()
 		dsg = func(context.Context) internal.DataSource { return db }
+		getAPIKey = db.GetAPIKey
+		if cfg.CountPageViews {
+			pageViews = pageviews.NewCounter(ctx, db, time.Minute, reporter)
+		}
+		if cfg.ModuleClaims {
+			claims = db
+		}
+		if cfg.PrivateAdvisories {
+			getAdvisories = db.GetAdvisories
+		}
+		if cfg.CachePopularSearches {
+			db.CachePopularSearches(ctx, time.Minute)
+		}
+		// Modules fetched by the frontend get search documents too.
+		cmdconfig.SearchSynonyms(ctx, cfg)
 		sourceClient := source.NewClient(&http.Client{
 			Transport: new(ochttp.Transport),
 			Timeout:   config.SourceTimeout,
@@ -120,43 +161,140 @@ func main() {
 		}
 	}
 
+	var namespaces []*frontend.Namespace
+	for _, n := range cfg.Namespaces {
+		ds, err := openNamespace(ctx, cfg, n)
+		if err != nil {
+			log.Fatalf(ctx, "namespace %q: %v", n.Name, err)
+		}
+		if db, ok := ds.(*postgres.DB); ok {
+			defer db.Close()
+		}
+		namespaces = append(namespaces, &frontend.Namespace{
+			Namespace:        n,
+			DataSourceGetter: func(context.Context) internal.DataSource { return ds },
+		})
+		log.Infof(ctx, "serving the modules under %s in namespace %q", n.Prefix, n.Name)
+	}
+
 	trace.SetTraceFunction(func(ctx context.Context, name string) (context.Context, trace.Span) {
 		return octrace.StartSpan(ctx, name)
 	})
-	reporter := cmdconfig.Reporter(ctx, cfg)
-	vc, err := vuln.NewClient(cfg.VulnDB)
+	var vc *vuln.Client
+	if cfg.VulnDBSyncInterval > 0 || cfg.VulnDBExtra != "" || getAdvisories != nil {
+		opts := vuln.MirrorOptions{
+			SyncInterval: cfg.VulnDBSyncInterval,
+			ExtraDir:     cfg.VulnDBExtra,
+		}
+		if getAdvisories != nil {
+			// Advisories may be published at any time.
+			if opts.SyncInterval == 0 {
+				opts.SyncInterval = defaultAdvisorySyncInterval
+			}
+			opts.Extra = func(ctx context.Context) ([]*osv.Entry, error) {
+				as, err := getAdvisories(ctx)
+				if err != nil {
+					return nil, err
+				}
+				var entries []*osv.Entry
+				for _, a := range as {
+					entries = append(entries, a.Entry)
+				}
+				return entries, nil
+			}
+		}
+		vc, err = vuln.NewMirrorClient(ctx, cfg.VulnDB, opts)
+	} else {
+		vc, err = vuln.NewClient(cfg.VulnDB)
+	}
 	if err != nil {
-		log.Fatalf(ctx, "vuln.NewClient: %v", err)
+		log.Fatalf(ctx, "vuln client: %v", err)
 	}
 	staticSource := template.TrustedSourceFromFlag(flag.Lookup("static").Value)
+	var staticStatus *static.Status
 	if *devMode {
 		// In dev mode compile TypeScript files into minified JavaScript files
-		// and rebuild them on file changes.
+		// and rebuild them on file changes. Pages show the errors of the
+		// latest build, which they get from staticStatus.
 		if *staticFlag == "" {
 			panic("staticPath is empty in dev mode; cannot rebuild static files")
 		}
 		ctx := context.Background()
+		staticStatus = &static.Status{}
 		if err := static.Build(static.Config{
 			EntryPoint: *staticFlag + "/frontend",
 			Watch:      true,
 			Bundle:     true,
+			OnBuild: func(r *static.Result) {
+				staticStatus.Record(r)
+				if len(r.Errors) > 0 {
+					log.Errorf(ctx, "building static files:\n%s", strings.Join(r.Errors, ""))
+				}
+			},
 		}); err != nil {
 			log.Error(ctx, err)
 		}
 	}
 
+	var overrides *templates.Overrides
+	if *overridesFlag != "" {
+		overrides = templates.NewOverrides(template.TrustedSourceFromFlag(flag.Lookup("template_overrides").Value))
+	}
+
+	var renderer docrender.Renderer
+	if *renderAddr != "" {
+		conn, err := grpc.Dial(*renderAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			log.Fatalf(ctx, "grpc.Dial(%q): %v", *renderAddr, err)
+		}
+		defer conn.Close()
+		renderer = remote.NewClient(conn)
+		log.Infof(ctx, "rendering documentation with %s", *renderAddr)
+	}
+
+	var redisClient *redis.Client
+	var cacher frontend.Cacher
+	var pageCache frontend.PageCache
+	if cfg.RedisCacheHost != "" {
+		addr := cfg.RedisCacheHost + ":" + cfg.RedisCachePort
+		redisClient = redis.NewClient(&redis.Options{Addr: addr})
+		if err := redisClient.Ping(ctx).Err(); err != nil {
+			log.Errorf(ctx, "redis at %s: %v", addr, err)
+		} else {
+			log.Infof(ctx, "connected to redis at %s", addr)
+		}
+		cacher = middleware.NewCacher(redisClient)
+		pageCache = cache.New(redisClient)
+	}
+
 	// TODO: Can we use a separate queue for the fetchServer and for the Server?
 	// It would help differentiate ownership.
 	fetchServer := &fetchserver.FetchServer{
 		Queue:                fetchQueue,
 		TaskIDChangeInterval: config.TaskIDChangeIntervalFrontend,
 	}
+	var docFeedback docfeedback.Filer
+	if cfg.DocFeedback != "" {
+		docFeedback, err = docfeedback.New(cfg.DocFeedback, docfeedback.Options{
+			GitHubToken: cfg.DocFeedbackGitHubToken,
+			HTTPClient:  &http.Client{Transport: new(ochttp.Transport), Timeout: 30 * time.Second},
+			SMTPAddr:    cfg.DocFeedbackSMTPAddr,
+			From:        cfg.DocFeedbackFrom,
+		})
+		if err != nil {
+			log.Fatal(ctx, err)
+		}
+	}
+	// Record the allocations of each request, for /_debug/allocs.
+	allocs := memory.NewAllocRecorder(50)
 	server, err := frontend.NewServer(frontend.ServerConfig{
 		Config:            cfg,
 		FetchServer:       fetchServer,
 		DataSourceGetter:  dsg,
+		Namespaces:        namespaces,
 		Queue:             fetchQueue,
 		TemplateFS:        template.TrustedFSFromTrustedSource(staticSource),
+		TemplateOverrides: overrides,
 		StaticFS:          os.DirFS(*staticFlag),
 		ThirdPartyFS:      os.DirFS(*thirdPartyPath),
 		DevMode:           *devMode,
@@ -164,25 +302,23 @@ func main() {
 		Reporter:          reporter,
 		VulndbClient:      vc,
 		DepsDevHTTPClient: &http.Client{Transport: new(ochttp.Transport)},
+		PageViews:         pageViews,
+		Renderer:          renderer,
+		PageCache:         pageCache,
+		Claims:            claims,
+		DocFeedback:       docFeedback,
+		Allocs:            allocs,
 	})
 	if err != nil {
 		log.Fatalf(ctx, "frontend.NewServer: %v", err)
 	}
 
 	router := dcensus.NewRouter(frontend.TagRoute)
-	var redisClient *redis.Client
-	var cacher frontend.Cacher
-	if cfg.RedisCacheHost != "" {
-		addr := cfg.RedisCacheHost + ":" + cfg.RedisCachePort
-		redisClient = redis.NewClient(&redis.Options{Addr: addr})
-		if err := redisClient.Ping(ctx).Err(); err != nil {
-			log.Errorf(ctx, "redis at %s: %v", addr, err)
-		} else {
-			log.Infof(ctx, "connected to redis at %s", addr)
-		}
-		cacher = middleware.NewCacher(redisClient)
-	}
+	router.RecordAllocs(allocs)
 	server.Install(router.Handle, cacher, cfg.AuthValues)
+	if staticStatus != nil {
+		router.Handle("/_static/build", staticStatus)
+	}
 	views := append(dcensus.ServerViews,
 		postgres.SearchLatencyDistribution,
 		postgres.SearchResponseCount,
@@ -190,8 +326,10 @@ func main() {
 		fetchserver.FetchResponseCount,
 		middleware.CacheResultCount,
 		middleware.CacheErrorCount,
+		middleware.CacheStaleCount,
 		middleware.CacheLatency,
 		middleware.QuotaResultCount,
+		middleware.LegacyURLRedirectCount,
 	)
 	if err := dcensus.Init(cfg, views...); err != nil {
 		log.Fatal(ctx, err)
@@ -213,24 +351,61 @@ func main() {
 	experimenter := cmdconfig.Experimenter(ctx, cfg, expg, reporter)
 	log.Infof(ctx, "cmd/frontend: initialized cmdconfig.Experimenter")
 
+	mw := frontendMiddleware(cfg, cmdconfig.Logger(ctx, cfg, "frontend-log"), redisClient, getAPIKey,
+		experimenter, reporter, panicHandler, !*disableCSP)
+	addr := cfg.HostAddr(*hostAddr)
+	log.Infof(ctx, "Listening on addr %s", addr)
+	log.Fatal(ctx, http.ListenAndServe(addr, mw(router)))
+}
+
+// frontendMiddleware returns the middleware that wraps the handlers of the
+// frontend. redisClient, getAPIKey and reporter may be nil.
+func frontendMiddleware(cfg *config.Config, logger middleware.Logger, redisClient *redis.Client,
+	getAPIKey func(context.Context, string) (*internal.APIKey, error), experimenter *middleware.Experimenter,
+	reporter derrors.Reporter, panicHandler http.Handler, enableCSP bool) middleware.Middleware {
 	ermw := middleware.Identity()
 	if reporter != nil {
 		ermw = middleware.ErrorReporting(reporter)
 	}
-	mw := middleware.Chain(
+	return middleware.Chain(
 		middleware.RequestInfo(),
-		middleware.RequestLog(cmdconfig.Logger(ctx, cfg, "frontend-log")),
-		middleware.AcceptRequests(http.MethodGet, http.MethodPost, http.MethodHead), // accept only GETs, POSTs and HEADs
+		middleware.RequestLog(logger),
+		// DELETE is for withdrawing private advisories.
+		middleware.AcceptRequests(http.MethodGet, http.MethodPost, http.MethodHead, http.MethodDelete),
 		middleware.BetaPkgGoDevRedirect(),
 		middleware.GodocOrgRedirect(),
-		middleware.Quota(cfg.Quota, redisClient),
-		middleware.SecureHeaders(!*disableCSP), // must come before any caching for nonces to work
+		middleware.LegacyURLRedirect(),
+		middleware.Quota(cfg.Quota, redisClient, getAPIKey, frontend.IsAPIRequest),
+		middleware.SecureHeaders(enableCSP), // must come before any caching for nonces to work
 		middleware.Experiment(experimenter),
+		middleware.Debug(serverconfig.GetEnv("GO_DISCOVERY_DEBUG_HEADER_VALUE", "")), // must come after Experiment
+		middleware.ServerTiming(),                                                    // must come after Debug
 		middleware.Panic(panicHandler),
 		ermw,
 		timeout.Timeout(54*time.Second),
 	)
-	addr := cfg.HostAddr(*hostAddr)
-	log.Infof(ctx, "Listening on addr %s", addr)
-	log.Fatal(ctx, http.ListenAndServe(addr, mw(router)))
+}
+
+// openNamespace returns the data source of the modules of the namespace n:
+// its database if it has one, and its module proxy otherwise.
+func openNamespace(ctx context.Context, cfg *config.Config, n *config.Namespace) (internal.DataSource, error) {
+	if n.DBName != "" {
+		ncfg := *cfg
+		ncfg.DBName = n.DBName
+		db, err := cmdconfig.OpenDB(ctx, &ncfg, n.BypassLicenseCheck)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	}
+	proxyClient, err := proxy.New(n.ProxyURL, &ochttp.Transport{})
+	if err != nil {
+		return nil, err
+	}
+	sourceClient := source.NewClient(&http.Client{Transport: &ochttp.Transport{}, Timeout: 1 * time.Minute})
+	return fetchdatasource.Options{
+		Getters:              []fetch.ModuleGetter{fetch.NewProxyModuleGetter(proxyClient, sourceClient)},
+		ProxyClientForLatest: proxyClient,
+		BypassLicenseCheck:   n.BypassLicenseCheck,
+	}.New(), nil
 }
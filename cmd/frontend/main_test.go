@@ -0,0 +1,80 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/cmd/internal/cmdconfig"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend"
+	"golang.org/x/pkgsite/internal/middleware"
+	"golang.org/x/pkgsite/internal/osv"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/static"
+	thirdparty "golang.org/x/pkgsite/third_party"
+)
+
+// TestFrontendMiddleware checks that the requests of the frontend's API get
+// through its middleware.
+func TestFrontendMiddleware(t *testing.T) {
+	ctx := context.Background()
+	cfg := &config.Config{PrivateAdvisories: true}
+	fds := fakedatasource.New()
+	fds.InsertAPIKey("security", &internal.APIKey{ID: 1, Name: "Security", AdvisoryPrefixes: []string{"corp.example.com"}})
+	const id = "CORP-2026-0001"
+	if err := fds.UpsertAdvisory(ctx, &internal.Advisory{Entry: &osv.Entry{
+		ID:       id,
+		Affected: []osv.Affected{{Module: osv.Module{Path: "corp.example.com/mod"}}},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	s, err := frontend.NewServer(frontend.ServerConfig{
+		Config:           cfg,
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+		TemplateFS:       template.TrustedFSFromEmbed(static.FS),
+		StaticFS:         static.FS,
+		ThirdPartyFS:     thirdparty.FS,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	s.Install(mux.Handle, nil, nil)
+	experimenter, err := middleware.NewExperimenter(ctx, time.Minute, func(context.Context) ([]*internal.Experiment, error) {
+		return nil, nil
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := frontendMiddleware(cfg, cmdconfig.Logger(ctx, cfg, "test"), nil, fds.GetAPIKey,
+		experimenter, nil, http.NotFoundHandler(), true)(mux)
+
+	do := func(method, path string) int {
+		t.Helper()
+		r := httptest.NewRequest(method, path, nil)
+		r.Header.Set(config.APIKeyHeader, "security")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+	if got := do(http.MethodDelete, "/advisories/"+id); got != http.StatusNoContent {
+		t.Errorf("DELETE: status = %d, want %d", got, http.StatusNoContent)
+	}
+	if _, err := fds.GetAdvisory(ctx, id); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("after DELETE: got %v, want NotFound", err)
+	}
+	if got := do(http.MethodPut, "/advisories/"+id); got != http.StatusMethodNotAllowed {
+		t.Errorf("PUT: status = %d, want %d", got, http.StatusMethodNotAllowed)
+	}
+}
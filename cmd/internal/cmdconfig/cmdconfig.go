@@ -25,6 +25,7 @@ import (
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/log/stackdriverlogger"
 	"golang.org/x/pkgsite/internal/middleware"
+	"golang.org/x/pkgsite/internal/poller"
 	"golang.org/x/pkgsite/internal/postgres"
 	mrpb "google.golang.org/genproto/googleapis/api/monitoredres"
 )
@@ -88,6 +89,28 @@ func Experimenter(ctx context.Context, cfg *config.Config, getter middleware.Exp
 	return e
 }
 
+// CheckDynamicConfig exits the process if the dynamic config cannot be read or
+// does not conform to its schema, so that a bad config is noticed at
+// deployment instead of being partly ignored. Once the server is running, a
+// bad config is reported each time it is re-read, and the previous config
+// stays in effect.
+func CheckDynamicConfig(ctx context.Context, cfg *config.Config) {
+	if cfg.DynamicConfigLocation == "" {
+		return
+	}
+	dc, err := dynconfig.Read(ctx, cfg.DynamicConfigLocation)
+	if err != nil {
+		log.Fatalf(ctx, "invalid dynamic config (check it with devtools/cmd/configcheck): %v", err)
+	}
+	for _, e := range dc.Experiments {
+		if _, ok := internal.Experiments[e.Name]; !ok {
+			// Not fatal, so that an experiment can be removed from the code
+			// before it is removed from the config.
+			log.Warningf(ctx, "dynamic config has unknown experiment %q", e.Name)
+		}
+	}
+}
+
 // ExperimentGetter returns an ExperimentGetter using the config.
 func ExperimentGetter(ctx context.Context, cfg *config.Config) middleware.ExperimentGetter {
 	if cfg.DynamicConfigLocation == "" {
@@ -117,9 +140,62 @@ func ExperimentGetter(ctx context.Context, cfg *config.Config) middleware.Experi
 	}
 }
 
+// EnqueueThrottles returns a function that reports the enqueue throttles from
+// the dynamic config. The config is re-read every minute.
+func EnqueueThrottles(ctx context.Context, cfg *config.Config) func() []*dynconfig.EnqueueThrottle {
+	if cfg.DynamicConfigLocation == "" {
+		return func() []*dynconfig.EnqueueThrottle { return nil }
+	}
+	p := poller.New([]*dynconfig.EnqueueThrottle(nil),
+		func(ctx context.Context) (any, error) {
+			dc, err := dynconfig.Read(ctx, cfg.DynamicConfigLocation)
+			if err != nil {
+				return nil, err
+			}
+			return dc.EnqueueThrottles, nil
+		},
+		func(err error) { log.Errorf(ctx, "reading enqueue throttles: %v", err) })
+	p.Poll(ctx)
+	p.Start(ctx, time.Minute)
+	return func() []*dynconfig.EnqueueThrottle {
+		return p.Current().([]*dynconfig.EnqueueThrottle)
+	}
+}
+
+// SearchSynonyms keeps the search synonyms used to build search documents
+// up to date with the dynamic config, which is re-read every minute. Both the
+// worker and the frontend, which inserts the modules it fetches, must call
+// it. Search documents built with earlier synonyms are rebuilt by the
+// worker's /update-search-synonyms handler.
+func SearchSynonyms(ctx context.Context, cfg *config.Config) {
+	if cfg.DynamicConfigLocation == "" {
+		return
+	}
+	p := poller.New(nil,
+		func(ctx context.Context) (any, error) {
+			dc, err := dynconfig.Read(ctx, cfg.DynamicConfigLocation)
+			if err != nil {
+				return nil, err
+			}
+			if changed := postgres.SetSearchSynonyms(dc.SearchSynonyms); len(changed) > 0 {
+				log.Infof(ctx, "search synonyms changed for %v", changed)
+			}
+			return dc.SearchSynonyms, nil
+		},
+		func(err error) { log.Errorf(ctx, "reading search synonyms: %v", err) })
+	p.Poll(ctx)
+	p.Start(ctx, time.Minute)
+}
+
+// replicaCheckPeriod is how often the replication lag of read replicas is
+// checked.
+const replicaCheckPeriod = 10 * time.Second
+
 // OpenDB opens the postgres database specified by the config.
 // It first tries the main connection info (DBConnInfo), and if that fails, it uses backup
 // connection info it if exists (DBSecondaryConnInfo).
+// If read replicas are configured (DBReplicaHosts), read-only queries are sent
+// to them.
 func OpenDB(ctx context.Context, cfg *config.Config, bypassLicenseCheck bool) (_ *postgres.DB, err error) {
 	defer derrors.Wrap(&err, "cmdconfig.OpenDB(ctx, cfg)")
 
@@ -146,6 +222,15 @@ func OpenDB(ctx context.Context, cfg *config.Config, bypassLicenseCheck bool) (_
 		}
 		log.Infof(ctx, "connected to secondary host %s", cfg.DBSecondaryHost)
 	}
+	if infos := cfg.DBReplicaConnInfos(); len(infos) > 0 {
+		replicas, err := database.OpenReplicaPool(ocDriver, infos, cfg.DBReplicaMaxLag)
+		if err != nil {
+			return nil, err
+		}
+		replicas.Start(ctx, replicaCheckPeriod)
+		ddb.SetReplicas(replicas)
+		log.Infof(ctx, "using read replicas %v", cfg.DBReplicaHosts)
+	}
 	log.Infof(ctx, "database open finished")
 	if bypassLicenseCheck {
 		return postgres.NewBypassingLicenseCheck(ddb), nil
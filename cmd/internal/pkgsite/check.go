@@ -0,0 +1,269 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pkgsite
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/doc/comment"
+	"go/token"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+)
+
+// A Problem is a problem with the documentation of a package.
+type Problem struct {
+	PackagePath string
+	// Pos is the position of the problem, or the zero Position if the
+	// problem concerns the whole package.
+	Pos     token.Position
+	Message string
+}
+
+func (p *Problem) String() string {
+	if !p.Pos.IsValid() {
+		return fmt.Sprintf("%s: %s", p.PackagePath, p.Message)
+	}
+	return fmt.Sprintf("%s/%s:%d: %s", p.PackagePath, path.Base(p.Pos.Filename), p.Pos.Line, p.Message)
+}
+
+// Check reports problems with the documentation of the packages in the
+// module with the given path and version:
+//   - packages and exported declarations without doc comments,
+//   - doc links, such as "[Name]", that don't refer to anything, and
+//   - examples that cannot be run in the playground.
+//
+// Problems are sorted by package and position.
+func Check(ctx context.Context, ds internal.DataSource, modulePath, version string) ([]*Problem, error) {
+	um, err := ds.GetUnitMeta(ctx, modulePath, modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	root, err := ds.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+	if err != nil {
+		return nil, err
+	}
+	var pkgPaths []string
+	if root.IsPackage() {
+		pkgPaths = append(pkgPaths, root.Path)
+	}
+	for _, sd := range root.Subdirectories {
+		if sd.Name != "" {
+			pkgPaths = append(pkgPaths, sd.Path)
+		}
+	}
+	sort.Strings(pkgPaths)
+
+	var problems []*Problem
+	for _, p := range pkgPaths {
+		u, err := getPackage(ctx, ds, p, um.ModulePath, um.Version)
+		if err != nil {
+			return nil, err
+		}
+		ps, err := checkPackage(u)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", p, err)
+		}
+		problems = append(problems, ps...)
+	}
+	return problems, nil
+}
+
+// A checker collects the problems with the documentation of a package.
+type checker struct {
+	fset     *token.FileSet
+	d        *doc.Package
+	path     string
+	problems []*Problem
+}
+
+func checkPackage(u *internal.Unit) ([]*Problem, error) {
+	docPkg, d, err := docPackage(u)
+	if err != nil {
+		return nil, err
+	}
+	c := &checker{fset: docPkg.Fset, d: d, path: u.Path}
+	if d.Doc == "" {
+		c.report(token.NoPos, "package has no doc comment")
+	}
+	c.checkDocLinks(token.NoPos, "package doc comment", d.Doc)
+	c.checkValues(d.Consts, "const")
+	c.checkValues(d.Vars, "var")
+	c.checkFuncs(d.Funcs)
+	c.checkExamples(d.Examples)
+	for _, t := range d.Types {
+		pos := t.Decl.Pos()
+		if len(t.Decl.Specs) > 0 {
+			pos = t.Decl.Specs[0].Pos()
+		}
+		c.checkDoc(pos, "type "+t.Name, t.Doc)
+		c.checkValues(t.Consts, "const")
+		c.checkValues(t.Vars, "var")
+		c.checkFuncs(t.Funcs)
+		c.checkFuncs(t.Methods)
+		c.checkExamples(t.Examples)
+	}
+	sort.SliceStable(c.problems, func(i, j int) bool {
+		pi, pj := c.problems[i].Pos, c.problems[j].Pos
+		if pi.Filename != pj.Filename {
+			return pi.Filename < pj.Filename
+		}
+		return pi.Line < pj.Line
+	})
+	return c.problems, nil
+}
+
+func (c *checker) report(pos token.Pos, format string, args ...any) {
+	c.problems = append(c.problems, &Problem{
+		PackagePath: c.path,
+		Pos:         c.fset.Position(pos),
+		Message:     fmt.Sprintf(format, args...),
+	})
+}
+
+// checkDoc checks the doc comment of the exported declaration at pos, which
+// is described by what, as in "func F".
+func (c *checker) checkDoc(pos token.Pos, what, text string) {
+	if text == "" {
+		c.report(pos, "exported %s has no doc comment", what)
+		return
+	}
+	c.checkDocLinks(pos, "doc comment of "+what, text)
+}
+
+func (c *checker) checkValues(vals []*doc.Value, kind string) {
+	for _, v := range vals {
+		if v.Doc != "" {
+			c.checkDocLinks(v.Decl.Pos(), fmt.Sprintf("doc comment of %s %s", kind, v.Names[0]), v.Doc)
+			continue
+		}
+		// In a group without a doc comment, each spec needs its own.
+		for _, spec := range v.Decl.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if vs.Doc != nil {
+				continue
+			}
+			for _, n := range vs.Names {
+				if n.IsExported() {
+					c.report(n.Pos(), "exported %s %s has no doc comment", kind, n.Name)
+					break
+				}
+			}
+		}
+	}
+}
+
+func (c *checker) checkFuncs(funcs []*doc.Func) {
+	for _, f := range funcs {
+		if f.Level > 0 {
+			// A method promoted from an embedded type is checked with
+			// that type.
+			continue
+		}
+		what := "func " + f.Name
+		if f.Recv != "" {
+			what = "method " + strings.TrimPrefix(f.Recv, "*") + "." + f.Name
+		}
+		c.checkDoc(f.Decl.Pos(), what, f.Doc)
+		c.checkExamples(f.Examples)
+	}
+}
+
+// checkExamples reports examples that the documentation cannot offer to run,
+// because they are in the package itself rather than its _test package, or
+// they use declarations that are not in the example's file.
+func (c *checker) checkExamples(examples []*doc.Example) {
+	for _, ex := range examples {
+		if ex.Play != nil {
+			continue
+		}
+		pos := token.NoPos
+		if ex.Code != nil {
+			pos = ex.Code.Pos()
+		}
+		c.report(pos, "example Example%s cannot be run in the playground", ex.Name)
+	}
+}
+
+// checkDocLinks reports the doc links in text that don't refer to a package
+// or an exported declaration. The doc comment is described by what.
+func (c *checker) checkDocLinks(pos token.Pos, what, text string) {
+	if text == "" {
+		return
+	}
+	// A doc link that doesn't resolve is left as plain text. So find them by
+	// comparing the links of the real parser with those of a parser that
+	// resolves every symbol.
+	resolved := map[string]int{}
+	forEachDocLink(c.d.Parser().Parse(text).Content, func(l *comment.DocLink) {
+		resolved[plainText(l.Text)]++
+	})
+	all := &comment.Parser{
+		LookupPackage: c.d.Parser().LookupPackage,
+		LookupSym:     func(recv, name string) bool { return true },
+	}
+	forEachDocLink(all.Parse(text).Content, func(l *comment.DocLink) {
+		if !token.IsExported(l.Name) {
+			// Probably not meant as a link, like "[i]".
+			return
+		}
+		t := plainText(l.Text)
+		if resolved[t] > 0 {
+			resolved[t]--
+			return
+		}
+		c.report(pos, "%s links to unknown [%s]", what, t)
+	})
+}
+
+// forEachDocLink calls f for each doc link in blocks.
+func forEachDocLink(blocks []comment.Block, f func(*comment.DocLink)) {
+	var inText func([]comment.Text)
+	inText = func(ts []comment.Text) {
+		for _, t := range ts {
+			switch t := t.(type) {
+			case *comment.DocLink:
+				f(t)
+			case *comment.Link:
+				inText(t.Text)
+			}
+		}
+	}
+	for _, b := range blocks {
+		switch b := b.(type) {
+		case *comment.Paragraph:
+			inText(b.Text)
+		case *comment.Heading:
+			inText(b.Text)
+		case *comment.List:
+			for _, item := range b.Items {
+				forEachDocLink(item.Content, f)
+			}
+		}
+	}
+}
+
+// plainText returns the text of ts without formatting.
+func plainText(ts []comment.Text) string {
+	var b strings.Builder
+	for _, t := range ts {
+		switch t := t.(type) {
+		case comment.Plain:
+			b.WriteString(string(t))
+		case comment.Italic:
+			b.WriteString(string(t))
+		case *comment.Link:
+			b.WriteString(plainText(t.Text))
+		case *comment.DocLink:
+			b.WriteString(plainText(t.Text))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pkgsite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/testenv"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+func TestCheck(t *testing.T) {
+	testenv.MustHaveExecPath(t, "go") // for local modules
+
+	dir, _ := testhelper.WriteTxtarToTempDir(t, `
+-- go.mod --
+module example.com/m
+-- a.go --
+// Package m is a package.
+//
+// See [F] and [T.M].
+package m
+
+// F calls [G] and [Missing].
+func F() {}
+
+func G() {}
+
+// T is a type.
+type T int
+
+func (T) M() {}
+
+const (
+	A = 1
+	b = 2
+)
+
+var (
+	// V is a variable.
+	V int
+	W int
+)
+-- a_test.go --
+package m_test
+
+import "example.com/m"
+
+func ExampleF() { m.F() }
+-- internal_test.go --
+package m
+
+func ExampleG() { G() }
+-- sub/sub.go --
+package sub
+
+// V is a variable.
+var V int
+`)
+	ctx := context.Background()
+	ds, err := BuildDataSource(ctx, ServerConfig{Paths: []string{dir}, UseListedMods: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems, err := Check(ctx, ds, "example.com/m", version.Latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range problems {
+		got = append(got, p.String())
+	}
+	want := []string{
+		"example.com/m/a.go:7: doc comment of func F links to unknown [Missing]",
+		"example.com/m/a.go:9: exported func G has no doc comment",
+		"example.com/m/a.go:14: exported method T.M has no doc comment",
+		"example.com/m/a.go:17: exported const A has no doc comment",
+		"example.com/m/a.go:24: exported var W has no doc comment",
+		"example.com/m/internal_test.go:3: example ExampleG cannot be run in the playground",
+		"example.com/m/sub: package has no doc comment",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
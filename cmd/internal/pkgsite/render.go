@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pkgsite
+
+import (
+	"context"
+	"fmt"
+	"go/doc"
+	"io"
+
+	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/godoc/docllms"
+	"golang.org/x/pkgsite/internal/godoc/docmarkdown"
+	"golang.org/x/pkgsite/internal/stdlib"
+	"golang.org/x/pkgsite/static"
+)
+
+// Render writes the documentation of the package with the given import path
+// and version to w. The format is "html", for the HTML that the server shows
+// on the package's page, "md" (or "markdown") for Markdown whose links go to
+// pkg.go.dev, "json" for JSON in the form of docjson.Package, or "llms" for
+// a digest in the style of llms.txt.
+func Render(ctx context.Context, ds internal.DataSource, w io.Writer, pkgPath, version, format string) error {
+	switch format {
+	case "html", "md", "markdown", "json", "llms":
+	default:
+		return fmt.Errorf("unknown format %q; want html, md, json or llms", format)
+	}
+	u, err := getPackage(ctx, ds, pkgPath, internal.UnknownModulePath, version)
+	if err != nil {
+		return err
+	}
+	if format == "html" {
+		dochtml.LoadTemplates(template.TrustedFSFromEmbed(static.FS))
+		parts, err := godoc.RenderFromUnit(ctx, u, internal.BuildContext{})
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, parts.Body.String()+"\n")
+		return err
+	}
+	var data []byte
+	switch format {
+	case "json":
+		data, err = godoc.RenderJSONFromUnit(u)
+		data = append(data, '\n')
+	case "llms":
+		data, err = godoc.RenderLLMsFromUnit(u, docllms.Options{URL: "https://pkg.go.dev/" + u.Path})
+	default:
+		data, err = godoc.RenderMarkdownFromUnit(u, docmarkdown.Options{DocLinkBaseURL: "https://pkg.go.dev"})
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// getPackage returns the package with the given path, with its
+// documentation.
+func getPackage(ctx context.Context, ds internal.DataSource, pkgPath, modulePath, version string) (*internal.Unit, error) {
+	um, err := ds.GetUnitMeta(ctx, pkgPath, modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	if !um.IsPackage() {
+		return nil, fmt.Errorf("%s is not a package", pkgPath)
+	}
+	u, err := ds.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+	if err != nil {
+		return nil, err
+	}
+	if len(u.Documentation) == 0 || u.Documentation[0].Source == nil {
+		return nil, fmt.Errorf("%s has no documentation", pkgPath)
+	}
+	return u, nil
+}
+
+// docPackage decodes the documentation of u, which must exist, and computes
+// its doc.Package.
+func docPackage(u *internal.Unit) (*godoc.Package, *doc.Package, error) {
+	docPkg, err := godoc.DecodePackage(u.Documentation[0].Source)
+	if err != nil {
+		return nil, nil, err
+	}
+	var innerPath string
+	if u.ModulePath == stdlib.ModulePath {
+		innerPath = u.Path
+	} else if u.Path != u.ModulePath {
+		innerPath = u.Path[len(u.ModulePath)+1:]
+	}
+	d, err := docPkg.DocPackage(innerPath, &godoc.ModuleInfo{
+		ModulePath:      u.ModulePath,
+		ResolvedVersion: u.Version,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return docPkg, d, nil
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pkgsite
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/testenv"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+func TestRender(t *testing.T) {
+	testenv.MustHaveExecPath(t, "go") // for local modules
+
+	dir, _ := testhelper.WriteTxtarToTempDir(t, `
+-- go.mod --
+module example.com/m
+-- p/p.go --
+// Package p is a package.
+//
+// # Usage
+//
+// Call [F].
+package p
+
+// C is a constant.
+const C = 1
+
+// F is a function.
+func F() {}
+
+// T is a type.
+type T struct{ X int }
+
+// New returns a T.
+func New() *T { return nil }
+
+// M is a method.
+func (*T) M() {}
+`)
+	ctx := context.Background()
+	ds, err := BuildDataSource(ctx, ServerConfig{Paths: []string{dir}, UseListedMods: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("markdown", func(t *testing.T) {
+		var b strings.Builder
+		if err := Render(ctx, ds, &b, "example.com/m/p", version.Latest, "md"); err != nil {
+			t.Fatal(err)
+		}
+		want := "# package p\n\n" +
+			"```go\nimport \"example.com/m/p\"\n```\n\n" +
+			"## Overview {#pkg-overview}\n\n" +
+			"Package p is a package.\n\n" +
+			"### Usage {#hdr-Usage}\n\n" +
+			"Call [F](#F).\n\n" +
+			"## Constants {#pkg-constants}\n\n" +
+			"```go\nconst C = 1\n```\n\n" +
+			"C is a constant.\n\n" +
+			"## Functions {#pkg-functions}\n\n" +
+			"### func F {#F}\n\n" +
+			"```go\nfunc F()\n```\n\n" +
+			"F is a function.\n\n" +
+			"## Types {#pkg-types}\n\n" +
+			"### type T {#T}\n\n" +
+			"```go\ntype T struct{ X int }\n```\n\n" +
+			"T is a type.\n\n" +
+			"### func New {#New}\n\n" +
+			"```go\nfunc New() *T\n```\n\n" +
+			"New returns a T.\n\n" +
+			"### func (\\*T) M {#T.M}\n\n" +
+			"```go\nfunc (*T) M()\n```\n\n" +
+			"M is a method.\n"
+		if diff := cmp.Diff(want, b.String()); diff != "" {
+			t.Errorf("mismatch (-want, +got):\n%s", diff)
+		}
+	})
+
+	t.Run("llms", func(t *testing.T) {
+		var b strings.Builder
+		if err := Render(ctx, ds, &b, "example.com/m/p", version.Latest, "llms"); err != nil {
+			t.Fatal(err)
+		}
+		want := "# example.com/m/p\n\n" +
+			"> Package p is a package.\n\n" +
+			"Import with `import \"example.com/m/p\"`. Full documentation: https://pkg.go.dev/example.com/m/p\n\n" +
+			"## Constants\n\n" +
+			"- `const C = 1`: C is a constant.\n\n" +
+			"## Functions\n\n" +
+			"- `func F()`: F is a function.\n\n" +
+			"## Types\n\n" +
+			"- `type T struct`: T is a type.\n" +
+			"  - `func New() *T`: New returns a T.\n" +
+			"  - `func (*T) M()`: M is a method.\n"
+		if diff := cmp.Diff(want, b.String()); diff != "" {
+			t.Errorf("mismatch (-want, +got):\n%s", diff)
+		}
+	})
+
+	t.Run("html", func(t *testing.T) {
+		var b strings.Builder
+		if err := Render(ctx, ds, &b, "example.com/m/p", version.Latest, "html"); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{`id="F"`, `id="T.M"`, "Package p is a package."} {
+			if !strings.Contains(b.String(), want) {
+				t.Errorf("output does not contain %q", want)
+			}
+		}
+	})
+
+	t.Run("bad format", func(t *testing.T) {
+		if err := Render(ctx, ds, &strings.Builder{}, "example.com/m/p", version.Latest, "pdf"); err == nil {
+			t.Error("got nil, want error")
+		}
+	})
+}
@@ -10,16 +10,19 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/fs"
+	"maps"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/cmd/internal/telemetry"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/fetch"
 	"golang.org/x/pkgsite/internal/fetchdatasource"
@@ -42,13 +45,38 @@ type ServerConfig struct {
 	DevMode          bool
 	DevModeStaticDir string
 	GoRepoPath       string
+	GitRepos         []string // Git repositories to serve, each of the form dir[@ref]
+	BaseURL          string   // scheme and host that the server is served at, for absolute links
 
 	Proxy *proxy.Client // client, or nil; controlled by the -proxy flag
+
+	Telemetry *telemetry.Recorder // or nil; controlled by the -telemetry flag
 }
 
 // BuildServer builds a *frontend.Server using the given configuration.
 func BuildServer(ctx context.Context, serverCfg ServerConfig) (*frontend.Server, error) {
-	if len(serverCfg.Paths) == 0 && !serverCfg.UseCache && serverCfg.Proxy == nil {
+	getters, localModules, err := resolveGetters(ctx, serverCfg)
+	if err != nil {
+		return nil, err
+	}
+	return newServer(getters, localModules, serverCfg)
+}
+
+// BuildDataSource builds a data source for the modules described by the
+// given configuration, for commands that read documentation without serving
+// it. The DevMode and DevModeStaticDir fields are ignored.
+func BuildDataSource(ctx context.Context, serverCfg ServerConfig) (*fetchdatasource.FetchDataSource, error) {
+	getters, _, err := resolveGetters(ctx, serverCfg)
+	if err != nil {
+		return nil, err
+	}
+	return newDataSource(getters, serverCfg), nil
+}
+
+// resolveGetters returns the module getters for the given configuration, and
+// the local modules that they serve.
+func resolveGetters(ctx context.Context, serverCfg ServerConfig) ([]fetch.ModuleGetter, []frontend.LocalModule, error) {
+	if len(serverCfg.Paths) == 0 && len(serverCfg.GitRepos) == 0 && !serverCfg.UseCache && serverCfg.Proxy == nil {
 		serverCfg.Paths = []string{"."}
 	}
 
@@ -65,13 +93,13 @@ func BuildServer(ctx context.Context, serverCfg ServerConfig) (*frontend.Server,
 		var err error
 		cfg.dirs, err = getGOPATHModuleDirs(ctx, serverCfg.Paths)
 		if err != nil {
-			return nil, fmt.Errorf("searching GOPATH: %v", err)
+			return nil, nil, fmt.Errorf("searching GOPATH: %v", err)
 		}
 	} else {
 		var err error
 		cfg.dirs, err = getModuleDirs(ctx, serverCfg.Paths)
 		if err != nil {
-			return nil, fmt.Errorf("searching modules: %v", err)
+			return nil, nil, fmt.Errorf("searching modules: %v", err)
 		}
 	}
 
@@ -81,10 +109,10 @@ func BuildServer(ctx context.Context, serverCfg ServerConfig) (*frontend.Server,
 			var err error
 			cfg.modCacheDir, err = defaultCacheDir()
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 			if cfg.modCacheDir == "" {
-				return nil, fmt.Errorf("empty value for GOMODCACHE")
+				return nil, nil, fmt.Errorf("empty value for GOMODCACHE")
 			}
 		}
 	}
@@ -93,15 +121,29 @@ func BuildServer(ctx context.Context, serverCfg ServerConfig) (*frontend.Server,
 		cfg.useLocalStdlib = true
 	}
 
+	var gitModules []frontend.LocalModule
+	for _, arg := range serverCfg.GitRepos {
+		dir, ref := arg, "HEAD"
+		if i := strings.LastIndexByte(arg, '@'); i >= 0 {
+			dir, ref = arg[:i], arg[i+1:]
+		}
+		g, err := fetch.NewGitModuleGetter(ctx, "", dir, ref)
+		if err != nil {
+			return nil, nil, fmt.Errorf("loading Git repository %s: %v", arg, err)
+		}
+		cfg.gitGetters = append(cfg.gitGetters, g)
+		gitModules = append(gitModules, frontend.LocalModule{ModulePath: g.ModulePath(), Dir: dir})
+	}
+
 	getters, err := buildGetters(ctx, cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Collect unique module Paths served by this server.
 	seenModules := make(map[frontend.LocalModule]bool)
 	var allModules []frontend.LocalModule
-	for _, modules := range cfg.dirs {
+	for _, modules := range append(slices.Collect(maps.Values(cfg.dirs)), gitModules) {
 		for _, m := range modules {
 			if seenModules[m] {
 				continue
@@ -114,7 +156,7 @@ func BuildServer(ctx context.Context, serverCfg ServerConfig) (*frontend.Server,
 		return allModules[i].ModulePath < allModules[j].ModulePath
 	})
 
-	return newServer(getters, allModules, cfg.proxy, serverCfg.DevMode, serverCfg.DevModeStaticDir)
+	return getters, allModules, nil
 }
 
 // getModuleDirs returns the set of workspace modules for each directory,
@@ -199,16 +241,18 @@ type getterConfig struct {
 	proxy          *proxy.Client                     // proxy client, or nil
 	useLocalStdlib bool                              // use go/packages for the local stdlib
 	goRepoPath     string                            // repo path for local stdlib
+	gitGetters     []fetch.ModuleGetter              // getters for local Git repositories
 }
 
 // buildGetters constructs module getters based on the given configuration.
 //
 // Getters are returned in the following priority order:
-//  1. local getters for cfg.dirs, in the given order
-//  2. a module cache getter, if cfg.modCacheDir != ""
-//  3. a proxy getter, if cfg.proxy != nil
+//  1. cfg.gitGetters, in the given order
+//  2. local getters for cfg.dirs, in the given order
+//  3. a module cache getter, if cfg.modCacheDir != ""
+//  4. a proxy getter, if cfg.proxy != nil
 func buildGetters(ctx context.Context, cfg getterConfig) ([]fetch.ModuleGetter, error) {
-	var getters []fetch.ModuleGetter
+	getters := slices.Clone(cfg.gitGetters)
 
 	// Load local getters for each directory.
 	for dir, modules := range cfg.dirs {
@@ -227,7 +271,7 @@ func buildGetters(ctx context.Context, cfg getterConfig) ([]fetch.ModuleGetter,
 			getters = append(getters, mg)
 		}
 	}
-	if len(getters) == 0 && len(cfg.dirs) > 0 {
+	if len(getters) == len(cfg.gitGetters) && len(cfg.dirs) > 0 {
 		return nil, fmt.Errorf("failed to load any module(s) at %v", cfg.dirs)
 	}
 
@@ -276,18 +320,34 @@ func getGOROOT() string {
 	return strings.TrimSpace(string(b))
 }
 
-func newServer(getters []fetch.ModuleGetter, localModules []frontend.LocalModule, prox *proxy.Client, devMode bool, staticFlag string) (*frontend.Server, error) {
-	lds := fetchdatasource.Options{
+// newDataSource returns a data source that fetches modules with the given
+// getters. It counts the modules it fetches in serverCfg.Telemetry, in the
+// counter pkgsite/modules-loaded with a bucket for the kind of getter, like
+// "dir" or "proxy".
+func newDataSource(getters []fetch.ModuleGetter, serverCfg ServerConfig) *fetchdatasource.FetchDataSource {
+	opts := fetchdatasource.Options{
 		Getters:              getters,
-		ProxyClientForLatest: prox,
+		ProxyClientForLatest: serverCfg.Proxy,
 		BypassLicenseCheck:   true,
-	}.New()
+	}
+	if rec := serverCfg.Telemetry; rec != nil {
+		opts.OnFetch = func(g fetch.ModuleGetter) {
+			// The String method of a getter starts with its kind.
+			kind, _, _ := strings.Cut(g.String(), "(")
+			rec.Inc("pkgsite/modules-loaded:" + strings.ToLower(kind))
+		}
+	}
+	return opts.New()
+}
+
+func newServer(getters []fetch.ModuleGetter, localModules []frontend.LocalModule, serverCfg ServerConfig) (*frontend.Server, error) {
+	lds := newDataSource(getters, serverCfg)
 
 	// In dev mode, use a dirFS to pick up template/JS/CSS changes without
 	// restarting the server.
 	var staticFS fs.FS
-	if devMode {
-		staticFS = os.DirFS(staticFlag)
+	if serverCfg.DevMode {
+		staticFS = os.DirFS(serverCfg.DevModeStaticDir)
 	} else {
 		staticFS = static.FS
 	}
@@ -302,10 +362,11 @@ func newServer(getters []fetch.ModuleGetter, localModules []frontend.LocalModule
 		DataSourceGetter: func(context.Context) internal.DataSource { return lds },
 		TemplateFS:       template.TrustedFSFromEmbed(static.FS),
 		StaticFS:         staticFS,
-		DevMode:          devMode,
+		DevMode:          serverCfg.DevMode,
 		LocalMode:        true,
 		LocalModules:     localModules,
 		ThirdPartyFS:     thirdparty.FS,
+		BaseURL:          serverCfg.BaseURL,
 	})
 	if err != nil {
 		return nil, err
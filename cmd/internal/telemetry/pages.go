@@ -0,0 +1,87 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package telemetry
+
+import (
+	"net/http"
+	"strings"
+)
+
+// features are the first elements of the paths of the requests for features
+// other than unit pages that pkgsite/page counts, by name.
+var features = map[string]bool{
+	"about":          true,
+	"autocomplete":   true,
+	"badge":          true,
+	"files":          true,
+	"license-policy": true,
+	"modgraph":       true,
+	"play":           true,
+	"sbom":           true,
+	"search":         true,
+	"search-help":    true,
+	"symbol-version": true,
+	"vuln":           true,
+}
+
+// files are the first elements of the paths of requests for static files,
+// which are not counted.
+var files = map[string]bool{
+	"static":         true,
+	"third_party":    true,
+	"favicon.ico":    true,
+	"robots.txt":     true,
+	"opensearch.xml": true,
+}
+
+// tabs are the tabs of unit pages that pkgsite/tab counts.
+var tabs = map[string]bool{
+	"versions":   true,
+	"imports":    true,
+	"importedby": true,
+	"licenses":   true,
+	"source":     true,
+	"diff":       true,
+	"history":    true,
+	"analysis":   true,
+}
+
+// CountPages returns a handler that counts the requests that h serves, in
+// the counter pkgsite/page with a bucket for the feature or "unit" for unit
+// pages, and for unit pages with a tab, pkgsite/tab with a bucket for the tab.
+// Only paths and query params that pkgsite serves are used as buckets, so the
+// counters cannot record what was looked at.
+func (r *Recorder) CountPages(h http.Handler) http.Handler {
+	if r == nil {
+		return h
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.countPage(req)
+		h.ServeHTTP(w, req)
+	})
+}
+
+func (r *Recorder) countPage(req *http.Request) {
+	first, _, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, "/"), "/")
+	switch {
+	case req.URL.Path == "/":
+		r.Inc("pkgsite/page:home")
+	case features[first]:
+		r.Inc("pkgsite/page:" + first)
+	case files[first]:
+		// Not counted.
+	case req.URL.Query().Get("m") == "md":
+		r.Inc("pkgsite/page:unit-markdown")
+	case req.URL.Query().Get("m") == "json":
+		r.Inc("pkgsite/page:unit-json")
+	case req.URL.Query().Get("m") == "llms":
+		r.Inc("pkgsite/page:unit-llms")
+	default:
+		r.Inc("pkgsite/page:unit")
+		if tab := req.URL.Query().Get("tab"); tabs[tab] {
+			r.Inc("pkgsite/tab:" + tab)
+		}
+	}
+}
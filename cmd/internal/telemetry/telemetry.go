@@ -0,0 +1,43 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package telemetry records aggregate usage counters for cmd/pkgsite with
+// golang.org/x/telemetry/counter, when the user opts in with the -telemetry
+// flag.
+//
+// The counters are named "pkgsite/name" or "pkgsite/name:bucket", and are
+// kept in the local Go telemetry directory along with those of the Go
+// toolchain (see https://go.dev/doc/telemetry), where "gotelemetry view"
+// shows them. They hold only counts, not events or identifying information.
+// Nothing is recorded if the user has turned telemetry off with
+// "go telemetry off", and cmd/pkgsite never uploads the counters.
+package telemetry
+
+import "golang.org/x/telemetry/counter"
+
+// A Recorder increments usage counters. A nil *Recorder records nothing, so
+// callers need not check whether the -telemetry flag was given.
+type Recorder struct{}
+
+// Open opens the counter file of the program, and returns a Recorder that
+// increments its counters. Counters are written to the file as they change,
+// so they need not be flushed before the program exits. Open must be called
+// at most once.
+func Open() *Recorder {
+	counter.Open()
+	return &Recorder{}
+}
+
+// Inc increments the counter with the given name.
+func (r *Recorder) Inc(name string) {
+	r.Add(name, 1)
+}
+
+// Add adds n to the counter with the given name.
+func (r *Recorder) Add(name string, n int64) {
+	if r == nil || n == 0 {
+		return
+	}
+	counter.Add(name, n)
+}
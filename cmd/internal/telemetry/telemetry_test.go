@@ -0,0 +1,98 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package telemetry
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/telemetry/counter/countertest"
+)
+
+// telemetryDir is the Go telemetry directory of the tests.
+var telemetryDir string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "telemetry")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	telemetryDir = dir
+	if countertest.SupportedPlatform {
+		countertest.Open(dir)
+	}
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// readCounters returns the counters in the counter files of the tests.
+func readCounters(t *testing.T) map[string]uint64 {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(telemetryDir, "local", "*.count"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]uint64{}
+	for _, f := range files {
+		counters, _, err := countertest.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, n := range counters {
+			got[name] += n
+		}
+	}
+	return got
+}
+
+func TestNilRecorder(t *testing.T) {
+	var r *Recorder
+	r.Inc("pkgsite/x")
+	if r.CountPages(http.NotFoundHandler()) == nil {
+		t.Error("CountPages of a nil Recorder returned nil")
+	}
+}
+
+func TestCountPages(t *testing.T) {
+	if !countertest.SupportedPlatform {
+		t.Skip("counters are not supported on this platform")
+	}
+	r := &Recorder{}
+	h := r.CountPages(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	for _, url := range []string{
+		"/",
+		"/search?q=foo",
+		"/static/frontend/frontend.js",
+		"/favicon.ico",
+		"/example.com/mod",
+		"/example.com/mod?tab=versions",
+		"/example.com/mod?tab=secret",
+		"/example.com/mod?m=md",
+		"/example.com/mod?m=json",
+		"/example.com/mod?m=llms",
+	} {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", url, nil))
+	}
+	want := map[string]uint64{
+		"pkgsite/page:home":          1,
+		"pkgsite/page:search":        1,
+		"pkgsite/page:unit":          3,
+		"pkgsite/page:unit-markdown": 1,
+		"pkgsite/page:unit-json":     1,
+		"pkgsite/page:unit-llms":     1,
+		"pkgsite/tab:versions":       1,
+	}
+	got := readCounters(t)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("counters mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -45,6 +45,40 @@
 // processed. If you clone the repo yourself (https://go.googlesource.com/go),
 // you can provide its location with the -gorepo flag to save a little time.
 //
+// To serve a module as it exists at a commit of a local Git repository, bare
+// or not, use the -git flag with the repository's directory and an optional
+// ref (HEAD by default):
+//
+//	pkgsite -git ~/repos/cue@v0.9.0
+//
+// The module's version is the highest semantic version tag on the commit, or
+// a pseudo-version if there is none.
+//
+// # Subcommands
+//
+// The forms above run the server; they are the same as "pkgsite serve".
+// Two other subcommands use the same flags to find modules, and write their
+// results to standard output instead of serving them:
+//
+//	pkgsite render [-format=html|md|json|llms] PACKAGE[@VERSION]
+//
+// writes the documentation of a package, and
+//
+//	pkgsite check MODULE[@VERSION]
+//
+// reports problems with the documentation of the packages in a module:
+// exported declarations without doc comments, doc links that don't refer to
+// anything, and examples that cannot be run in the playground. It exits with
+// status 1 if there are any.
+//
+// # Telemetry
+//
+// With the -telemetry flag, pkgsite counts the pages it serves, the modules
+// it loads and the features and flags that are used, with Go telemetry
+// counters (see https://go.dev/doc/telemetry). The counters are kept in the
+// Go telemetry directory, are not recorded if Go telemetry is off, and are
+// never uploaded by pkgsite.
+//
 // [workspace]: https://go.dev/ref/mod#workspaces
 package main
 
@@ -59,83 +93,76 @@ import (
 	"time"
 
 	"golang.org/x/pkgsite/cmd/internal/pkgsite"
+	"golang.org/x/pkgsite/cmd/internal/telemetry"
 	"golang.org/x/pkgsite/internal/browser"
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/middleware/timeout"
 	"golang.org/x/pkgsite/internal/proxy"
 	"golang.org/x/pkgsite/internal/stdlib"
+	"golang.org/x/pkgsite/internal/version"
 )
 
 const defaultAddr = "localhost:8080" // default webserver address
 
-var (
-	httpAddr   = flag.String("http", defaultAddr, "HTTP service address to listen for incoming requests on")
-	goRepoPath = flag.String("gorepo", "", "path to Go repo on local filesystem")
-	useProxy   = flag.Bool("proxy", false, "fetch from GOPROXY if not found locally")
-	openFlag   = flag.Bool("open", false, "open a browser window to the server's address")
-	// other flags are bound to ServerConfig below
-)
-
 func main() {
-	var serverCfg pkgsite.ServerConfig
-
-	flag.BoolVar(&serverCfg.GOPATHMode, "gopath_mode", false, "assume that local modules' Paths are relative to GOPATH/src")
-	flag.BoolVar(&serverCfg.UseCache, "cache", false, "fetch from the module cache")
-	flag.StringVar(&serverCfg.CacheDir, "cachedir", "", "module cache directory (defaults to `go env GOMODCACHE`)")
-	flag.BoolVar(&serverCfg.UseListedMods, "list", true, "for each path, serve all modules in build list")
-	flag.BoolVar(&serverCfg.DevMode, "dev", false, "enable developer mode (reload templates on each page load, serve non-minified JS/CSS, etc.)")
-	flag.StringVar(&serverCfg.DevModeStaticDir, "static", "static", "path to folder containing static files served")
-
-	flag.Usage = func() {
-		out := flag.CommandLine.Output()
-		fmt.Fprintf(out, "usage: %s [flags] [PATHS ...]\n", os.Args[0])
-		fmt.Fprintf(out, "    where each PATHS is a single path or a comma-separated list\n")
-		fmt.Fprintf(out, "    (default is current directory if neither -cache nor -proxy is provided)\n")
-		flag.PrintDefaults()
+	args := os.Args[1:]
+	cmd := "serve"
+	if len(args) > 0 {
+		switch args[0] {
+		case "serve", "render", "check":
+			cmd, args = args[0], args[1:]
+		}
 	}
-	flag.Parse()
-
-	serverCfg.UseLocalStdlib = true
-	serverCfg.GoRepoPath = *goRepoPath
-	serverCfg.Paths = collectPaths(flag.Args())
-
-	if serverCfg.UseCache || *useProxy {
-		fmt.Fprintf(os.Stderr, "BYPASSING LICENSE CHECKING: MAY DISPLAY NON-REDISTRIBUTABLE INFORMATION\n")
+	ctx := context.Background()
+	switch cmd {
+	case "serve":
+		serve(ctx, args)
+	case "render":
+		render(ctx, args)
+	case "check":
+		check(ctx, args)
 	}
+}
 
-	if *useProxy {
-		url := os.Getenv("GOPROXY")
-		if url == "" {
-			dief("GOPROXY environment variable is not set")
-		}
-		var err error
-		serverCfg.Proxy, err = proxy.New(url, nil)
-		if err != nil {
-			dief("connecting to proxy: %s", err)
-		}
+func serve(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	httpAddr := fs.String("http", defaultAddr, "HTTP service address to listen for incoming requests on")
+	openFlag := fs.Bool("open", false, "open a browser window to the server's address")
+	mf := addModuleFlags(fs)
+	var devMode bool
+	var devModeStaticDir string
+	fs.BoolVar(&devMode, "dev", false, "enable developer mode (reload templates on each page load, serve non-minified JS/CSS, etc.)")
+	fs.StringVar(&devModeStaticDir, "static", "static", "path to folder containing static files served")
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "usage: %s [serve] [flags] [PATHS ...]\n", os.Args[0])
+		fmt.Fprintf(out, "    where each PATHS is a single path or a comma-separated list\n")
+		fmt.Fprintf(out, "    (default is current directory if neither -cache nor -proxy is provided)\n")
+		fmt.Fprintf(out, "other subcommands are render and check; see %s render -h and %s check -h\n", os.Args[0], os.Args[0])
+		fs.PrintDefaults()
 	}
+	fs.Parse(args)
 
-	if *goRepoPath != "" {
-		stdlib.SetGoRepoPath(*goRepoPath)
+	serverCfg := mf.serverConfig(fs.Args())
+	addr := *httpAddr
+	if addr == "" {
+		addr = ":http"
 	}
+	url := "http://" + addr
 
-	ctx := context.Background()
+	serverCfg.DevMode = devMode
+	serverCfg.DevModeStaticDir = devModeStaticDir
+	serverCfg.BaseURL = url
 	server, err := pkgsite.BuildServer(ctx, serverCfg)
 	if err != nil {
 		dief("%s", err)
 	}
 
-	addr := *httpAddr
-	if addr == "" {
-		addr = ":http"
-	}
-
 	ln, err := net.Listen("tcp", addr)
 	if err != nil {
 		dief("%s", err)
 	}
 
-	url := "http://" + addr
 	log.Infof(ctx, "Listening on addr %s", url)
 
 	if *openFlag {
@@ -149,10 +176,147 @@ func main() {
 	router := http.NewServeMux()
 	server.Install(router.Handle, nil, nil)
 	mw := timeout.Timeout(54 * time.Second)
-	srv := &http.Server{Addr: addr, Handler: mw(router)}
+	srv := &http.Server{Addr: addr, Handler: mw(serverCfg.Telemetry.CountPages(router))}
 	dief("%v", srv.Serve(ln))
 }
 
+func render(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("render", flag.ExitOnError)
+	format := fs.String("format", "md", "output format: html, md (Markdown), json (go/doc JSON) or llms (llms.txt-style digest)")
+	mf := addModuleFlags(fs)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "usage: %s render [flags] PACKAGE[@VERSION]\n", os.Args[0])
+		fmt.Fprintf(out, "    writes the documentation of PACKAGE to standard output\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	// Only the output matters, not the progress of fetching.
+	log.SetLevel("warning")
+
+	pkgPath, vers := splitVersion(fs.Arg(0))
+	serverCfg := mf.serverConfig(nil)
+	if *format == "html" || *format == "md" || *format == "json" || *format == "llms" {
+		serverCfg.Telemetry.Inc("pkgsite/render-format:" + *format)
+	}
+	ds, err := pkgsite.BuildDataSource(ctx, serverCfg)
+	if err != nil {
+		dief("%s", err)
+	}
+	if err := pkgsite.Render(ctx, ds, os.Stdout, pkgPath, vers, *format); err != nil {
+		dief("%s", err)
+	}
+}
+
+func check(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	mf := addModuleFlags(fs)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "usage: %s check [flags] MODULE[@VERSION]\n", os.Args[0])
+		fmt.Fprintf(out, "    reports problems with the documentation of the packages in MODULE\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	// Only the output matters, not the progress of fetching.
+	log.SetLevel("warning")
+
+	modulePath, vers := splitVersion(fs.Arg(0))
+	ds, err := pkgsite.BuildDataSource(ctx, mf.serverConfig(nil))
+	if err != nil {
+		dief("%s", err)
+	}
+	problems, err := pkgsite.Check(ctx, ds, modulePath, vers)
+	if err != nil {
+		dief("%s", err)
+	}
+	for _, p := range problems {
+		fmt.Println(p)
+	}
+	if len(problems) > 0 {
+		os.Exit(1)
+	}
+}
+
+// moduleFlags holds the flags, common to all subcommands, that determine
+// where pkgsite finds modules.
+type moduleFlags struct {
+	fs         *flag.FlagSet
+	goRepoPath string
+	useProxy   bool
+	git        string
+	telemetry  bool
+	cfg        pkgsite.ServerConfig // other flags are bound to its fields
+}
+
+func addModuleFlags(fs *flag.FlagSet) *moduleFlags {
+	mf := &moduleFlags{fs: fs}
+	fs.StringVar(&mf.goRepoPath, "gorepo", "", "path to Go repo on local filesystem")
+	fs.BoolVar(&mf.useProxy, "proxy", false, "fetch from GOPROXY if not found locally")
+	fs.StringVar(&mf.git, "git", "", "comma-separated list of local Git repositories to serve, each of the form `dir[@ref]`")
+	fs.BoolVar(&mf.cfg.GOPATHMode, "gopath_mode", false, "assume that local modules' Paths are relative to GOPATH/src")
+	fs.BoolVar(&mf.cfg.UseCache, "cache", false, "fetch from the module cache")
+	fs.StringVar(&mf.cfg.CacheDir, "cachedir", "", "module cache directory (defaults to `go env GOMODCACHE`)")
+	fs.BoolVar(&mf.cfg.UseListedMods, "list", true, "for each path, serve all modules in build list")
+	fs.BoolVar(&mf.telemetry, "telemetry", false, "count usage with Go telemetry counters, which pkgsite never uploads")
+	return mf
+}
+
+// serverConfig returns the configuration for the modules at paths and those
+// selected by the flags. It exits if the configuration is invalid.
+func (mf *moduleFlags) serverConfig(paths []string) pkgsite.ServerConfig {
+	serverCfg := mf.cfg
+	serverCfg.UseLocalStdlib = true
+	serverCfg.GoRepoPath = mf.goRepoPath
+	serverCfg.Paths = collectPaths(paths)
+	if mf.git != "" {
+		serverCfg.GitRepos = collectPaths([]string{mf.git})
+	}
+
+	if serverCfg.UseCache || mf.useProxy {
+		fmt.Fprintf(os.Stderr, "BYPASSING LICENSE CHECKING: MAY DISPLAY NON-REDISTRIBUTABLE INFORMATION\n")
+	}
+
+	if mf.useProxy {
+		url := os.Getenv("GOPROXY")
+		if url == "" {
+			dief("GOPROXY environment variable is not set")
+		}
+		var err error
+		serverCfg.Proxy, err = proxy.New(url, nil)
+		if err != nil {
+			dief("connecting to proxy: %s", err)
+		}
+	}
+
+	if mf.goRepoPath != "" {
+		stdlib.SetGoRepoPath(mf.goRepoPath)
+	}
+	if mf.telemetry {
+		serverCfg.Telemetry = openTelemetry(mf.fs)
+	}
+	return serverCfg
+}
+
+// openTelemetry opens the usage counters, and counts the use of the
+// subcommand of fs and of the flags that were set.
+func openTelemetry(fs *flag.FlagSet) *telemetry.Recorder {
+	rec := telemetry.Open()
+	rec.Inc("pkgsite/invocations:" + fs.Name())
+	fs.Visit(func(f *flag.Flag) {
+		rec.Inc("pkgsite/flag:" + f.Name)
+	})
+	return rec
+}
+
 func dief(format string, args ...any) {
 	fmt.Fprintf(os.Stderr, format, args...)
 	fmt.Fprintln(os.Stderr)
@@ -166,3 +330,13 @@ func collectPaths(args []string) []string {
 	}
 	return paths
 }
+
+// splitVersion splits an argument of the form path[@version] into its path
+// and version. The version defaults to latest.
+func splitVersion(arg string) (path, vers string) {
+	path, vers, ok := strings.Cut(arg, "@")
+	if !ok || vers == "" {
+		vers = version.Latest
+	}
+	return path, vers
+}
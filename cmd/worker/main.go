@@ -20,11 +20,15 @@ import (
 	"go.opencensus.io/plugin/ochttp"
 	octrace "go.opencensus.io/trace"
 	"golang.org/x/pkgsite/cmd/internal/cmdconfig"
+	"golang.org/x/pkgsite/internal/checksum"
 	"golang.org/x/pkgsite/internal/config"
 	"golang.org/x/pkgsite/internal/config/serverconfig"
 	"golang.org/x/pkgsite/internal/dcensus"
+	"golang.org/x/pkgsite/internal/docrender/readme"
+	"golang.org/x/pkgsite/internal/fetch"
 	"golang.org/x/pkgsite/internal/index"
 	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/memory"
 	"golang.org/x/pkgsite/internal/middleware"
 	mtimeout "golang.org/x/pkgsite/internal/middleware/timeout"
 	"golang.org/x/pkgsite/internal/proxy"
@@ -53,6 +57,7 @@ func main() {
 		log.Fatal(ctx, err)
 	}
 	cfg.Dump(os.Stdout)
+	cmdconfig.CheckDynamicConfig(ctx, cfg)
 
 	if cfg.UseProfiler {
 		if err := profiler.Start(profiler.Config{}); err != nil {
@@ -82,6 +87,26 @@ func main() {
 		Transport: &ochttp.Transport{},
 		Timeout:   config.SourceTimeout,
 	})
+	var checksumDB *checksum.DB
+	if cfg.ChecksumDB != "off" {
+		checksumDB, err = checksum.New(cfg.ChecksumDB, &http.Client{
+			Transport: &ochttp.Transport{},
+			Timeout:   time.Minute,
+		})
+		if err != nil {
+			log.Fatal(ctx, err)
+		}
+	}
+	var contentScanner *fetch.ContentScanner
+	if cfg.ContentPolicy != nil {
+		contentScanner, err = fetch.NewContentScanner(cfg.ContentPolicy)
+		if err != nil {
+			log.Fatal(ctx, err)
+		}
+	}
+	// READMEs are rendered in process when modules are processed, and stored
+	// for the frontend.
+	readmeRenderer := readme.Renderer{}
 	expg := cmdconfig.ExperimentGetter(ctx, cfg)
 	fetchQueue, err := gcpqueue.New(ctx, cfg, queueName, *workers, expg,
 		func(ctx context.Context, modulePath, version string) (int, error) {
@@ -89,6 +114,12 @@ func main() {
 				ProxyClient:  proxyClient,
 				SourceClient: sourceClient,
 				DB:           db,
+
+				ChecksumDB:               checksumDB,
+				RequireVerifiedChecksums: cfg.RequireVerifiedChecksums,
+				ContentScanner:           contentScanner,
+				CommitHosts:              cfg.RepoStatsHosts,
+				ReadmeRenderer:           readmeRenderer,
 			}
 			code, _, err := f.FetchAndUpdateState(ctx, modulePath, version, cfg.AppVersionLabel())
 			return code, err
@@ -104,38 +135,54 @@ func main() {
 	redisCacheClient := getCacheRedis(ctx, cfg)
 	redisBetaCacheClient := getBetaCacheRedis(ctx, cfg)
 	experimenter := cmdconfig.Experimenter(ctx, cfg, expg, reporter)
+	cmdconfig.SearchSynonyms(ctx, cfg)
+	// Record the allocations of each request, for the allocs debug page.
+	allocs := memory.NewAllocRecorder(50)
 	server, err := worker.NewServer(cfg, worker.ServerConfig{
 		DB:                   db,
 		IndexClient:          indexClient,
 		ProxyClient:          proxyClient,
 		SourceClient:         sourceClient,
+		ChecksumDB:           checksumDB,
+		ContentScanner:       contentScanner,
+		ReadmeRenderer:       readmeRenderer,
 		RedisCacheClient:     redisCacheClient,
 		RedisBetaCacheClient: redisBetaCacheClient,
 		Queue:                fetchQueue,
 		Reporter:             reporter,
 		StaticPath:           template.TrustedSourceFromFlag(flag.Lookup("static").Value),
 		GetExperiments:       experimenter.Experiments,
+		GetEnqueueThrottles:  cmdconfig.EnqueueThrottles(ctx, cfg),
+		Allocs:               allocs,
 	})
 	if err != nil {
 		log.Fatal(ctx, err)
 	}
 	router := dcensus.NewRouter(nil)
+	router.RecordAllocs(allocs)
 	server.Install(router.Handle)
 
 	views := append(dcensus.ServerViews,
 		worker.EnqueueResponseCount,
+		worker.EnqueueSkippedCount,
 		worker.ProcessingLag,
 		worker.UnprocessedModules,
 		worker.UnprocessedNewModules,
 		worker.DBProcesses,
 		worker.DBWaitingProcesses,
+		worker.ImportedByChanged,
+		worker.ImportedByUpdated,
+		worker.ImportedByPending,
 		worker.SheddedFetchCount,
+		worker.DeduplicatedFetchCount,
 		worker.FetchLatencyDistribution,
 		worker.FetchResponseCount,
-		worker.FetchPackageCount)
+		worker.FetchPackageCount,
+		worker.VerifyEncodingCount)
 	if err := dcensus.Init(cfg, views...); err != nil {
 		log.Fatal(ctx, err)
 	}
+	fetch.SetVerifyEncodingRecorder(worker.RecordVerifyEncoding)
 
 	iap := middleware.Identity()
 	if aud := os.Getenv("GO_DISCOVERY_IAP_AUDIENCE"); aud != "" {
@@ -0,0 +1,144 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command configcheck checks pkgsite configuration files.
+//
+// Usage:
+//
+//	configcheck dynamic FILE...  # dynamic config, including experiments
+//	configcheck exclude FILE...  # exclusions
+//	configcheck seed FILE...     # seed files for devtools/cmd/seeddb
+//	configcheck schema           # print the JSON Schema for dynamic config
+//
+// It prints each problem with its file and line number, and exits with status
+// 1 if there are any. See doc/config.md for the file formats.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config/dynconfig"
+)
+
+func main() {
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintf(out, "usage:\n")
+		fmt.Fprintf(out, "  configcheck dynamic FILE...\n")
+		fmt.Fprintf(out, "  configcheck exclude FILE...\n")
+		fmt.Fprintf(out, "  configcheck seed FILE...\n")
+		fmt.Fprintf(out, "  configcheck schema\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var check func([]byte) (warnings []string, err error)
+	switch cmd := flag.Arg(0); cmd {
+	case "schema":
+		os.Stdout.Write(dynconfig.Schema)
+		return
+	case "dynamic":
+		check = checkDynamic
+	case "exclude":
+		check = checkExclusions
+	case "seed":
+		check = checkSeeds
+	default:
+		fmt.Fprintf(os.Stderr, "configcheck: unknown command %q\n", cmd)
+		flag.Usage()
+		os.Exit(2)
+	}
+	files := flag.Args()[1:]
+	if len(files) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	ok := true
+	for _, file := range files {
+		if !checkFile(file, check) {
+			ok = false
+		}
+	}
+	if !ok {
+		os.Exit(1)
+	}
+}
+
+// checkFile checks the contents of file with check, and prints the problems
+// it finds. It reports whether the file is valid.
+func checkFile(file string, check func([]byte) ([]string, error)) bool {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		return false
+	}
+	warnings, err := check(data)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "%s: warning: %s\n", file, w)
+	}
+	if err != nil {
+		for _, line := range errorLines(err) {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", file, line)
+		}
+		return false
+	}
+	return true
+}
+
+// errorLines returns the messages of the errors joined in err, one per line.
+func errorLines(err error) []string {
+	var lines []string
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range u.Unwrap() {
+			lines = append(lines, errorLines(e)...)
+		}
+		return lines
+	}
+	return strings.Split(err.Error(), "\n")
+}
+
+func checkDynamic(data []byte) ([]string, error) {
+	if err := dynconfig.Validate(data); err != nil {
+		return nil, err
+	}
+	dc, err := dynconfig.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	var warnings []string
+	for _, e := range dc.Experiments {
+		// Servers accept unknown experiments, so that an experiment can be
+		// removed from the code before it is removed from the config.
+		if _, ok := internal.Experiments[e.Name]; !ok {
+			warnings = append(warnings, fmt.Sprintf("unknown experiment %q", e.Name))
+		}
+	}
+	return warnings, nil
+}
+
+func checkExclusions(data []byte) ([]string, error) {
+	_, err := dynconfig.ParseExclusions(bytes.NewReader(data))
+	return nil, err
+}
+
+func checkSeeds(data []byte) ([]string, error) {
+	mvs, err := internal.ReadSeeds(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(mvs) == 0 {
+		return nil, errors.New("no module versions")
+	}
+	return nil, nil
+}
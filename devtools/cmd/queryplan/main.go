@@ -0,0 +1,162 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command queryplan checks that the plans of the critical search and unit
+// page queries have not regressed. It EXPLAINs each query returned by
+// postgres.PlannedQueries against a seeded database, and fails if a plan
+// scans a table sequentially or its estimated cost exceeds -max_cost.
+//
+// Usage:
+//
+//	go run ./devtools/cmd/queryplan [flags]
+//
+// The database is configured with the usual GO_DISCOVERY_DATABASE_*
+// environment variables. The default arguments refer to modules in
+// tests/search/seed.txt; tests/search/run.sh runs this command after seeding.
+//
+// Sequential scans are disabled while planning, so a sequential scan in a
+// plan means that no index can serve the query, even on a database small
+// enough that the planner would otherwise prefer to read the whole table.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	_ "github.com/jackc/pgx/v4/stdlib" // for pgx driver
+	"golang.org/x/pkgsite/internal/config/serverconfig"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/postgres"
+)
+
+var (
+	maxCost     = flag.Float64("max_cost", 10000, "fail if the estimated total cost of a plan exceeds this")
+	searchQuery = flag.String("search", "strfmt", "package search query to plan")
+	symbolQuery = flag.String("symbol", "Writer", "symbol search query to plan")
+	unitPath    = flag.String("path", "github.com/go-openapi/strfmt", "unit path to plan unit queries with")
+	modulePath  = flag.String("module", "github.com/go-openapi/strfmt", "module path to plan unit queries with")
+	version     = flag.String("version", "v0.20.1", "module version to plan unit queries with")
+	verbose     = flag.Bool("v", false, "print every plan")
+)
+
+func main() {
+	flag.Parse()
+	ctx := context.Background()
+	cfg, err := serverconfig.Init(ctx)
+	if err != nil {
+		log.Fatal(ctx, err)
+	}
+	db, err := database.Open("pgx", cfg.DBConnInfo(), "queryplan")
+	if err != nil {
+		log.Fatalf(ctx, "database.Open for host %s failed with %v", cfg.DBHost, err)
+	}
+	defer db.Close()
+
+	queries, err := postgres.PlannedQueries(postgres.PlanArgs{
+		SearchQuery: *searchQuery,
+		SymbolQuery: *symbolQuery,
+		UnitPath:    *unitPath,
+		ModulePath:  *modulePath,
+		Version:     *version,
+	})
+	if err != nil {
+		log.Fatal(ctx, err)
+	}
+	failed, err := run(ctx, db, queries)
+	if err != nil {
+		log.Fatal(ctx, err)
+	}
+	if failed > 0 {
+		fmt.Printf("QUERY PLAN CHECKS FAILED: %d of %d queries regressed\n", failed, len(queries))
+		os.Exit(1)
+	}
+	fmt.Printf("all %d query plans passed\n", len(queries))
+}
+
+// run explains each query and reports the ones whose plans fail the checks.
+// It returns the number of failures.
+func run(ctx context.Context, db *database.DB, queries []postgres.PlannedQuery) (failed int, err error) {
+	// Make sure the planner's statistics reflect the freshly seeded data.
+	if _, err := db.Exec(ctx, "ANALYZE"); err != nil {
+		return 0, err
+	}
+	err = db.Transact(ctx, sql.LevelDefault, func(tx *database.DB) error {
+		if _, err := tx.Exec(ctx, "SET LOCAL enable_seqscan = off"); err != nil {
+			return err
+		}
+		for _, q := range queries {
+			var data []byte
+			if err := tx.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+q.Query, q.Args...).Scan(&data); err != nil {
+				return fmt.Errorf("%s: %v", q.Name, err)
+			}
+			p, err := parsePlan(data)
+			if err != nil {
+				return fmt.Errorf("%s: %v", q.Name, err)
+			}
+			problems := checkPlan(p, *maxCost)
+			if len(problems) == 0 {
+				fmt.Printf("--- PASSED: %s (cost %.2f)\n", q.Name, p.TotalCost)
+			} else {
+				failed++
+				fmt.Printf("--- FAILED: %s\n", q.Name)
+				for _, prob := range problems {
+					fmt.Printf("\t%s\n", prob)
+				}
+			}
+			if *verbose || len(problems) > 0 {
+				fmt.Printf("%s\n", data)
+			}
+		}
+		return nil
+	})
+	return failed, err
+}
+
+// A plan is a node of the output of EXPLAIN (FORMAT JSON).
+type plan struct {
+	NodeType     string  `json:"Node Type"`
+	RelationName string  `json:"Relation Name"`
+	TotalCost    float64 `json:"Total Cost"`
+	Plans        []*plan `json:"Plans"`
+}
+
+// parsePlan parses the output of EXPLAIN (FORMAT JSON) and returns the root
+// of its plan.
+func parsePlan(data []byte) (*plan, error) {
+	var out []struct {
+		Plan *plan `json:"Plan"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("parsing plan: %v", err)
+	}
+	if len(out) != 1 || out[0].Plan == nil {
+		return nil, fmt.Errorf("parsing plan: want one plan, got %d", len(out))
+	}
+	return out[0].Plan, nil
+}
+
+// checkPlan returns a description of each way in which p has regressed: each
+// sequential scan in it, and a total cost above maxCost.
+func checkPlan(p *plan, maxCost float64) []string {
+	var problems []string
+	var walk func(*plan)
+	walk = func(n *plan) {
+		if n.NodeType == "Seq Scan" {
+			problems = append(problems, fmt.Sprintf("sequential scan on %s", n.RelationName))
+		}
+		for _, c := range n.Plans {
+			walk(c)
+		}
+	}
+	walk(p)
+	if p.TotalCost > maxCost {
+		problems = append(problems, fmt.Sprintf("total cost %.2f exceeds %.2f", p.TotalCost, maxCost))
+	}
+	return problems
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCheckPlan(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		plan    string
+		maxCost float64
+		want    []string
+	}{
+		{
+			name: "index scans",
+			plan: `[{"Plan": {"Node Type": "Nested Loop", "Total Cost": 16.6, "Plans": [
+				{"Node Type": "Index Scan", "Relation Name": "paths", "Total Cost": 8.3},
+				{"Node Type": "Bitmap Heap Scan", "Relation Name": "units", "Total Cost": 8.3}
+			]}}]`,
+			maxCost: 100,
+		},
+		{
+			name: "sequential scan",
+			plan: `[{"Plan": {"Node Type": "Hash Join", "Total Cost": 20, "Plans": [
+				{"Node Type": "Index Scan", "Relation Name": "paths", "Total Cost": 8.3},
+				{"Node Type": "Hash", "Total Cost": 10, "Plans": [
+					{"Node Type": "Seq Scan", "Relation Name": "units", "Total Cost": 10}
+				]}
+			]}}]`,
+			maxCost: 100,
+			want:    []string{"sequential scan on units"},
+		},
+		{
+			name:    "too costly",
+			plan:    `[{"Plan": {"Node Type": "Index Scan", "Relation Name": "search_documents", "Total Cost": 250}}]`,
+			maxCost: 100,
+			want:    []string{"total cost 250.00 exceeds 100.00"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			p, err := parsePlan([]byte(test.plan))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := checkPlan(p, test.maxCost)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePlanErrors(t *testing.T) {
+	for _, data := range []string{`{`, `[]`, `[{}]`} {
+		if _, err := parsePlan([]byte(data)); err == nil {
+			t.Errorf("parsePlan(%q): got nil, want error", data)
+		}
+	}
+}
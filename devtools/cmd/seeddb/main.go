@@ -12,6 +12,7 @@ import (
 	"flag"
 	"log"
 	"net/http"
+	"os"
 	"regexp"
 	"strings"
 	"sync"
@@ -210,20 +211,16 @@ func fetchFunc(ctx context.Context, f *worker.Fetcher, m, v string) (err error)
 //	module@version
 func readSeedFile(seedfile string) (_ []internal.Modver, err error) {
 	defer derrors.Wrap(&err, "readSeedFile %q", seedfile)
-	lines, err := internal.ReadFileLines(seedfile)
+	f, err := os.Open(seedfile)
 	if err != nil {
 		return nil, err
 	}
-	log.Printf("read %d module versions from %s", len(lines), seedfile)
-
-	var modules []internal.Modver
-	for _, l := range lines {
-		mv, err := internal.ParseModver(l)
-		if err != nil {
-			return nil, err
-		}
-		modules = append(modules, mv)
+	defer f.Close()
+	modules, err := internal.ReadSeeds(f)
+	if err != nil {
+		return nil, err
 	}
+	log.Printf("read %d module versions from %s", len(modules), seedfile)
 	return modules, nil
 }
 
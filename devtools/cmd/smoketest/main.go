@@ -0,0 +1,234 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command smoketest checks that a deployed frontend works, by requesting a
+// suite of pages and checking their responses. It is meant to be run once
+// after a deploy; the prober checks a deployment continuously.
+//
+// Usage:
+//
+//	go run ./devtools/cmd/smoketest [flags] [base URL]
+//
+// The base URL defaults to http://localhost:8080. The suite is read from the
+// file given by -suite, or from suite.yaml in this directory if there is none.
+// Each check in the suite is a YAML object with these keys:
+//
+//	name:        the name of the check, required
+//	path:        the path and query to request, relative to the base URL
+//	status:      the expected status code; the default is 200
+//	contentType: a prefix of the expected Content-Type
+//	contains:    a list of strings that the body must contain
+//	json:        whether the body must be valid JSON
+//	requires:    environment variables that must be "true" for the check to
+//	             run, such as the GO_DISCOVERY_ settings of the frontend that
+//	             the check depends on; other checks are skipped
+//
+// The command exits with a non-zero status if any check fails. With -junit,
+// it also writes the results in JUnit XML format, for CI systems.
+package main
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	suiteFile = flag.String("suite", "", "YAML file of checks to run; default is the built-in suite")
+	junitFile = flag.String("junit", "", "if set, write results in JUnit XML format to this file")
+	runFlag   = flag.String("run", "", "if set, only run the checks whose names match this regexp")
+	timeout   = flag.Duration("timeout", 30*time.Second, "timeout for each request")
+)
+
+//go:embed suite.yaml
+var defaultSuite []byte
+
+// A check is a request to make and assertions about its response.
+type check struct {
+	Name        string   `yaml:"name"`
+	Path        string   `yaml:"path"`
+	Status      int      `yaml:"status"`
+	ContentType string   `yaml:"contentType"`
+	Contains    []string `yaml:"contains"`
+	JSON        bool     `yaml:"json"`
+	Requires    []string `yaml:"requires"`
+}
+
+// A result is the outcome of running a check.
+type result struct {
+	check    *check
+	duration time.Duration
+	err      error // nil if the check passed
+}
+
+func main() {
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintf(out, "usage: %s [flags] [base URL]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	baseURL := "http://localhost:8080"
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		baseURL = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data := defaultSuite
+	if *suiteFile != "" {
+		var err error
+		data, err = os.ReadFile(*suiteFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	checks, err := parseSuite(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *runFlag != "" {
+		re, err := regexp.Compile(*runFlag)
+		if err != nil {
+			log.Fatalf("-run: %v", err)
+		}
+		checks = filterChecks(checks, re)
+	}
+	checks, skipped := skipChecks(checks, os.Getenv)
+	for _, c := range skipped {
+		fmt.Printf("skip %s: requires %s=true\n", c.Name, strings.Join(c.Requires, "=true, "))
+	}
+
+	client := &http.Client{Timeout: *timeout}
+	results := runChecks(context.Background(), client, baseURL, checks)
+	failed := 0
+	for _, r := range results {
+		if r.err != nil {
+			failed++
+			fmt.Printf("FAIL %s (%s): %v\n", r.check.Name, r.duration.Round(time.Millisecond), r.err)
+		} else {
+			fmt.Printf("ok   %s (%s)\n", r.check.Name, r.duration.Round(time.Millisecond))
+		}
+	}
+	if *junitFile != "" {
+		if err := writeJUnitFile(*junitFile, baseURL, results); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if failed > 0 {
+		fmt.Printf("%d of %d checks failed\n", failed, len(results))
+		os.Exit(1)
+	}
+	fmt.Printf("all %d checks passed\n", len(results))
+}
+
+// parseSuite parses a suite of checks from YAML.
+func parseSuite(data []byte) ([]*check, error) {
+	var suite struct {
+		Checks []*check `yaml:"checks"`
+	}
+	if err := yaml.Unmarshal(data, &suite); err != nil {
+		return nil, fmt.Errorf("parsing suite: %v", err)
+	}
+	for i, c := range suite.Checks {
+		if c.Name == "" {
+			return nil, fmt.Errorf("check %d has no name", i)
+		}
+		if !strings.HasPrefix(c.Path, "/") {
+			return nil, fmt.Errorf("check %q: path %q does not begin with a slash", c.Name, c.Path)
+		}
+		if c.Status == 0 {
+			c.Status = http.StatusOK
+		}
+	}
+	return suite.Checks, nil
+}
+
+func filterChecks(checks []*check, re *regexp.Regexp) []*check {
+	var cs []*check
+	for _, c := range checks {
+		if re.MatchString(c.Name) {
+			cs = append(cs, c)
+		}
+	}
+	return cs
+}
+
+// skipChecks returns the checks whose required environment variables, as
+// reported by getenv, are all "true", and the others.
+func skipChecks(checks []*check, getenv func(string) string) (run, skipped []*check) {
+	for _, c := range checks {
+		ok := true
+		for _, v := range c.Requires {
+			if getenv(v) != "true" {
+				ok = false
+			}
+		}
+		if ok {
+			run = append(run, c)
+		} else {
+			skipped = append(skipped, c)
+		}
+	}
+	return run, skipped
+}
+
+// runChecks runs each check against the server at baseURL, in order.
+func runChecks(ctx context.Context, client *http.Client, baseURL string, checks []*check) []*result {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	var results []*result
+	for _, c := range checks {
+		start := time.Now()
+		err := runCheck(ctx, client, baseURL+c.Path, c)
+		results = append(results, &result{check: c, duration: time.Since(start), err: err})
+	}
+	return results
+}
+
+// runCheck requests url and checks the response against c.
+func runCheck(ctx context.Context, client *http.Client, url string, c *check) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading body: %v", err)
+	}
+	if resp.StatusCode != c.Status {
+		return fmt.Errorf("GET %s: got status %d, want %d", c.Path, resp.StatusCode, c.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, c.ContentType) {
+		return fmt.Errorf("GET %s: got Content-Type %q, want %q", c.Path, ct, c.ContentType)
+	}
+	for _, s := range c.Contains {
+		if !bytes.Contains(body, []byte(s)) {
+			return fmt.Errorf("GET %s: body does not contain %q", c.Path, s)
+		}
+	}
+	if c.JSON && !json.Valid(body) {
+		return fmt.Errorf("GET %s: body is not valid JSON", c.Path)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDefaultSuite(t *testing.T) {
+	checks, err := parseSuite(defaultSuite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checks) == 0 {
+		t.Fatal("default suite has no checks")
+	}
+}
+
+func TestParseSuiteErrors(t *testing.T) {
+	for _, suite := range []string{
+		"checks: [{path: /}]",
+		"checks: [{name: x, path: search}]",
+		"checks: {",
+	} {
+		if _, err := parseSuite([]byte(suite)); err == nil {
+			t.Errorf("parseSuite(%q): got nil, want error", suite)
+		}
+	}
+}
+
+func TestSkipChecks(t *testing.T) {
+	checks, err := parseSuite([]byte(`
+checks:
+  - {name: always, path: /}
+  - {name: stats, path: /, requires: [STATS]}
+  - {name: stats and llms, path: /, requires: [STATS, LLMS]}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{"STATS": "true", "LLMS": "false"}
+	run, skipped := skipChecks(checks, func(v string) string { return env[v] })
+	names := func(cs []*check) []string {
+		var ns []string
+		for _, c := range cs {
+			ns = append(ns, c.Name)
+		}
+		return ns
+	}
+	if diff := cmp.Diff([]string{"always", "stats"}, names(run)); diff != "" {
+		t.Errorf("run mismatch (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"stats and llms"}, names(skipped)); diff != "" {
+		t.Errorf("skipped mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestRunChecks(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html>hello, world</html>")
+	})
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"a": 1}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	checks, err := parseSuite([]byte(`
+checks:
+  - {name: page, path: /page, contains: [hello, world]}
+  - {name: missing text, path: /page, contains: [goodbye]}
+  - {name: json, path: /json, json: true, contentType: application/json}
+  - {name: not json, path: /page, json: true}
+  - {name: not found, path: /nothing, status: 404}
+  - {name: wrong status, path: /nothing}
+  - {name: wrong content type, path: /page, contentType: image/svg+xml}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := runChecks(context.Background(), server.Client(), server.URL+"/", checks)
+	got := map[string]bool{}
+	for _, r := range results {
+		got[r.check.Name] = r.err == nil
+	}
+	want := map[string]bool{
+		"page":               true,
+		"missing text":       false,
+		"json":               true,
+		"not json":           false,
+		"not found":          true,
+		"wrong status":       false,
+		"wrong content type": false,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	results := []*result{
+		{check: &check{Name: "pass"}, duration: 1500 * time.Millisecond},
+		{check: &check{Name: "fail"}, duration: 250 * time.Millisecond, err: fmt.Errorf(`GET /: body does not contain "x"`)},
+	}
+	var b strings.Builder
+	if err := writeJUnit(&b, "https://example.com", results); err != nil {
+		t.Fatal(err)
+	}
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuite name="smoketest https://example.com" tests="2" failures="1" time="1.750">
+  <testcase name="pass" time="1.500"></testcase>
+  <testcase name="fail" time="0.250">
+    <failure message="GET /: body does not contain &#34;x&#34;"></failure>
+  </testcase>
+</testsuite>
+`
+	if diff := cmp.Diff(want, b.String()); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
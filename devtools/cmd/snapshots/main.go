@@ -0,0 +1,84 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// snapshots reviews the HTML snapshots that failed to match their golden
+// files in the last test run (see internal/testing/htmlsnapshot). It prints
+// the differences between each golden file and the output written next to
+// it, and with -accept, replaces the golden files with the outputs.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/pkgsite/internal/testing/htmlsnapshot"
+)
+
+var accept = flag.Bool("accept", false, "replace the golden files with the test outputs")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags] [DIRS]\n", os.Args[0])
+		fmt.Fprintf(flag.CommandLine.Output(), "DIRS are searched for snapshots; the default is \"internal\".\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	dirs := flag.Args()
+	if len(dirs) == 0 {
+		dirs = []string{"internal"}
+	}
+	n := 0
+	for _, dir := range dirs {
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(path, ".golden") {
+				return nil
+			}
+			got, err := os.ReadFile(htmlsnapshot.GotFile(path))
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			n++
+			if err := review(path, string(got)); err != nil {
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if n == 0 {
+		fmt.Println("no mismatched snapshots")
+	}
+}
+
+// review prints the differences between the golden file and got, and
+// replaces the golden file if -accept was given.
+func review(golden, got string) error {
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("=== %s (-want, +got):\n%s\n", golden, htmlsnapshot.Diff(string(want), got))
+	if !*accept {
+		return nil
+	}
+	if err := os.WriteFile(golden, []byte(got), 0644); err != nil {
+		return err
+	}
+	fmt.Printf("accepted %s\n", golden)
+	return os.Remove(htmlsnapshot.GotFile(golden))
+}
@@ -4,20 +4,84 @@
 
 // Command static compiles TypeScript files into minified
 // JavaScript files and creates linked sourcemaps.
+//
+// With -watch, it rebuilds the files whenever they change, and logs the
+// errors and the duration of each build. With -addr as well, it serves the
+// result of the latest build as JSON at /_static/build.
 package main
 
 import (
+	"flag"
 	"log"
+	"net/http"
+	"strings"
+	"sync"
 
 	"golang.org/x/pkgsite/internal/static"
 )
 
+var (
+	watch = flag.Bool("watch", false, "rebuild the files when they change")
+	addr  = flag.String("addr", "", "in watch mode, address to serve the latest build result on, such as localhost:8082")
+)
+
 func main() {
-	if err := static.Build(static.Config{EntryPoint: "static/frontend", Bundle: true}); err != nil {
-		log.Fatal(err)
+	flag.Parse()
+	if !*watch {
+		if err := static.Build(static.Config{EntryPoint: "static/frontend", Bundle: true}); err != nil {
+			log.Fatal(err)
+		}
+
+		if err := static.Build(static.Config{EntryPoint: "static/worker", Bundle: true}); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
-	if err := static.Build(static.Config{EntryPoint: "static/worker", Bundle: true}); err != nil {
-		log.Fatal(err)
+	// The status has the errors of the latest builds of both directories.
+	var (
+		status  = &static.Status{}
+		mu      sync.Mutex
+		results = map[string]*static.Result{}
+	)
+	dirs := []string{"static/frontend", "static/worker"}
+	for _, dir := range dirs {
+		err := static.Build(static.Config{
+			EntryPoint: dir,
+			Bundle:     true,
+			Watch:      true,
+			OnBuild: func(r *static.Result) {
+				if len(r.Errors) > 0 {
+					log.Printf("%s: build failed in %s:\n%s", dir, r.Duration, strings.Join(r.Errors, ""))
+				} else {
+					log.Printf("%s: built in %s", dir, r.Duration)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				results[dir] = r
+				all := &static.Result{Start: r.Start, Duration: r.Duration}
+				for _, d := range dirs {
+					if dr := results[d]; dr != nil {
+						all.Errors = append(all.Errors, dr.Errors...)
+						all.Warnings = append(all.Warnings, dr.Warnings...)
+					}
+				}
+				status.Record(all)
+			},
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if *addr != "" {
+		log.Printf("serving the latest build result at http://%s/_static/build", *addr)
+		log.Fatal(http.ListenAndServe(*addr, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/_static/build" {
+				http.NotFound(w, r)
+				return
+			}
+			status.ServeHTTP(w, r)
+		})))
 	}
+	select {}
 }
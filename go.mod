@@ -31,6 +31,7 @@ require (
 	golang.org/x/mod v0.22.0
 	golang.org/x/net v0.34.0
 	golang.org/x/sync v0.10.0
+	golang.org/x/telemetry v0.0.0-20240522233618-39ace7a40ae7
 	golang.org/x/text v0.21.0
 	golang.org/x/tools v0.29.0
 	google.golang.org/api v0.126.0
@@ -0,0 +1,30 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"time"
+
+	"golang.org/x/pkgsite/internal/osv"
+)
+
+// Advisory is an advisory for vulnerabilities in modules that the Go
+// vulnerability database does not cover, such as private modules. It is
+// published to the frontend by a client with an API key, and shown like the
+// entries of the Go vulnerability database.
+type Advisory struct {
+	Entry *osv.Entry
+	// Publisher is the name of the API key that last published the advisory.
+	Publisher string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CanPublishAdvisory reports whether the key may publish advisories for the
+// module with the given path: whether the path is one of its
+// AdvisoryPrefixes, or is below one of them.
+func (ak *APIKey) CanPublishAdvisory(modulePath string) bool {
+	return underPrefix(modulePath, ak.AdvisoryPrefixes)
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package analysis defines the reports that external code-quality analyzers,
+// usually run in the CI of a module, post for module versions, and the
+// analyzers that are allowed to post them.
+//
+// An analyzer is added by registering an implementation of Analyzer, usually
+// in the init function of a package imported by the frontend binary. The
+// analyzers gosec and staticcheck are registered by this package.
+package analysis
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// A Report is the result of running an analyzer on a module version.
+type Report struct {
+	// Analyzer is the name of the registered Analyzer that produced the
+	// report.
+	Analyzer string `json:"analyzer"`
+	// Summary is a short description of the result, in plain text.
+	Summary string `json:"summary"`
+	// Metrics are the counts reported by the analyzer, in the order in which
+	// they are displayed.
+	Metrics []Metric `json:"metrics"`
+	// URL links to the full results, for example a CI run. It is optional.
+	URL string `json:"url,omitempty"`
+
+	// The fields below are set by the server, not by the analyzer.
+
+	ModulePath string    `json:"-"`
+	Version    string    `json:"-"`
+	Submitter  string    `json:"-"` // name of the API key that posted the report
+	CreatedAt  time.Time `json:"-"`
+}
+
+// A Metric is a named count in a Report, such as the number of issues of
+// high severity.
+type Metric struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+// An Analyzer is a tool whose reports can be posted.
+type Analyzer interface {
+	// Name identifies the analyzer in reports. It must be unique.
+	Name() string
+	// Title is the name of the analyzer shown to users.
+	Title() string
+	// Check returns an error describing why r, whose Analyzer is Name, is
+	// not a valid report of the analyzer. It may reorder the metrics of r.
+	Check(r *Report) error
+}
+
+var (
+	mu        sync.Mutex
+	analyzers = map[string]Analyzer{}
+)
+
+// Register makes an analyzer available. It panics if an analyzer with the
+// same name is already registered.
+func Register(a Analyzer) {
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := analyzers[a.Name()]; ok {
+		panic(fmt.Sprintf("analysis: analyzer %q registered twice", a.Name()))
+	}
+	analyzers[a.Name()] = a
+}
+
+// Lookup returns the registered analyzer with the given name, or nil if there
+// is none.
+func Lookup(name string) Analyzer {
+	mu.Lock()
+	defer mu.Unlock()
+	return analyzers[name]
+}
+
+// Analyzers returns the registered analyzers, sorted by name.
+func Analyzers() []Analyzer {
+	mu.Lock()
+	defer mu.Unlock()
+	var as []Analyzer
+	for _, a := range analyzers {
+		as = append(as, a)
+	}
+	sort.Slice(as, func(i, j int) bool { return as[i].Name() < as[j].Name() })
+	return as
+}
+
+// MaxSummaryLen is the maximum length of the summary of a report, in
+// characters.
+const MaxSummaryLen = 500
+
+// Validate returns an error describing why r is not a valid report. The
+// error is meant for the author of the analyzer integration.
+func Validate(r *Report) error {
+	a := Lookup(r.Analyzer)
+	if a == nil {
+		return fmt.Errorf("unknown analyzer %q", r.Analyzer)
+	}
+	if !utf8.ValidString(r.Summary) || utf8.RuneCountInString(r.Summary) > MaxSummaryLen {
+		return fmt.Errorf("summary must be valid UTF-8 of at most %d characters", MaxSummaryLen)
+	}
+	if r.URL != "" {
+		u, err := url.Parse(r.URL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("url %q is not an https URL", r.URL)
+		}
+	}
+	seen := map[string]bool{}
+	for _, m := range r.Metrics {
+		if seen[m.Name] {
+			return fmt.Errorf("metric %q appears more than once", m.Name)
+		}
+		seen[m.Name] = true
+		if m.Value < 0 {
+			return fmt.Errorf("metric %q is negative", m.Name)
+		}
+	}
+	return a.Check(r)
+}
+
+// CountsAnalyzer is an Analyzer whose reports consist of counts of findings
+// by category.
+type CountsAnalyzer struct {
+	ID          string
+	DisplayName string
+	// Metrics are the names of the categories, in display order. Reports
+	// need not contain all of them, but may contain no others.
+	Metrics []string
+}
+
+func (a *CountsAnalyzer) Name() string  { return a.ID }
+func (a *CountsAnalyzer) Title() string { return a.DisplayName }
+
+// Check checks that the metrics of r are among a.Metrics, and sorts them in
+// the same order.
+func (a *CountsAnalyzer) Check(r *Report) error {
+	index := map[string]int{}
+	for i, name := range a.Metrics {
+		index[name] = i
+	}
+	for _, m := range r.Metrics {
+		if _, ok := index[m.Name]; !ok {
+			return fmt.Errorf("%s does not report metric %q; want one of %v", a.ID, m.Name, a.Metrics)
+		}
+	}
+	sort.SliceStable(r.Metrics, func(i, j int) bool {
+		return index[r.Metrics[i].Name] < index[r.Metrics[j].Name]
+	})
+	return nil
+}
+
+func init() {
+	// gosec reports issues by severity.
+	Register(&CountsAnalyzer{
+		ID:          "gosec",
+		DisplayName: "gosec",
+		Metrics:     []string{"high", "medium", "low"},
+	})
+	// staticcheck reports problems by check category; see
+	// https://staticcheck.dev/docs/checks.
+	Register(&CountsAnalyzer{
+		ID:          "staticcheck",
+		DisplayName: "Staticcheck",
+		Metrics:     []string{"staticcheck", "simple", "stylecheck", "quickfix", "unused"},
+	})
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidate(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		report  Report
+		wantErr string // substring of the error; empty if valid
+	}{
+		{
+			name:   "valid",
+			report: Report{Analyzer: "gosec", Summary: "ok", URL: "https://ci.example.com/1"},
+		},
+		{
+			name:    "unknown analyzer",
+			report:  Report{Analyzer: "lint"},
+			wantErr: "unknown analyzer",
+		},
+		{
+			name:    "long summary",
+			report:  Report{Analyzer: "gosec", Summary: strings.Repeat("x", MaxSummaryLen+1)},
+			wantErr: "summary",
+		},
+		{
+			name:    "not https",
+			report:  Report{Analyzer: "gosec", URL: "javascript:alert(1)"},
+			wantErr: "https",
+		},
+		{
+			name:    "duplicate metric",
+			report:  Report{Analyzer: "gosec", Metrics: []Metric{{"high", 1}, {"high", 2}}},
+			wantErr: "more than once",
+		},
+		{
+			name:    "negative metric",
+			report:  Report{Analyzer: "gosec", Metrics: []Metric{{"high", -1}}},
+			wantErr: "negative",
+		},
+		{
+			name:    "unknown metric",
+			report:  Report{Analyzer: "staticcheck", Metrics: []Metric{{"high", 1}}},
+			wantErr: `does not report metric "high"`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			err := Validate(&test.report)
+			switch {
+			case test.wantErr == "" && err != nil:
+				t.Errorf("got %v, want no error", err)
+			case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+				t.Errorf("got %v, want error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestCountsAnalyzerCheckSortsMetrics(t *testing.T) {
+	r := &Report{
+		Analyzer: "gosec",
+		Metrics:  []Metric{{"low", 3}, {"high", 1}, {"medium", 2}},
+	}
+	if err := Lookup("gosec").Check(r); err != nil {
+		t.Fatal(err)
+	}
+	want := []Metric{{"high", 1}, {"medium", 2}, {"low", 3}}
+	if diff := cmp.Diff(want, r.Metrics); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
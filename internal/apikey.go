@@ -0,0 +1,46 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"strings"
+	"time"
+)
+
+// APIKey describes a key issued to a client of the JSON endpoints.
+// The key itself is never stored; only its hash is.
+type APIKey struct {
+	ID   int64
+	Name string // who or what the key was issued to
+	// QPS is the number of requests per second allowed for the key.
+	// If it is zero, the default quota for API keys applies.
+	QPS int
+	// AdvisoryPrefixes are the module path prefixes of the modules the key
+	// may publish advisories for.
+	AdvisoryPrefixes []string
+	// AnalysisPrefixes are the module path prefixes of the modules the key
+	// may post analysis reports for.
+	AnalysisPrefixes []string
+	CreatedAt        time.Time
+	RevokedAt        time.Time // zero if the key has not been revoked
+}
+
+// CanReportAnalysis reports whether the key may post analysis reports for the
+// module with the given path: whether the path is one of its
+// AnalysisPrefixes, or is below one of them.
+func (ak *APIKey) CanReportAnalysis(modulePath string) bool {
+	return underPrefix(modulePath, ak.AnalysisPrefixes)
+}
+
+// underPrefix reports whether modulePath is one of prefixes, or is below one
+// of them.
+func underPrefix(modulePath string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if modulePath == p || strings.HasPrefix(modulePath, p+"/") {
+			return true
+		}
+	}
+	return false
+}
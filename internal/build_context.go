@@ -4,7 +4,12 @@
 
 package internal
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
 
 // A BuildContext describes a build context for the Go tool: information needed
 // to build a Go package. For our purposes, we only care about the information
@@ -49,6 +54,20 @@ var BuildContexts = []BuildContext{
 	BuildContextJS,
 }
 
+// ParseBuildContext parses s, of the form "GOOS/GOARCH", as one of
+// BuildContexts.
+func ParseBuildContext(s string) (BuildContext, error) {
+	goos, goarch, ok := strings.Cut(s, "/")
+	if !ok {
+		return BuildContext{}, fmt.Errorf("build context %q is not of the form GOOS/GOARCH", s)
+	}
+	bc := BuildContext{GOOS: goos, GOARCH: goarch}
+	if !slices.Contains(BuildContexts, bc) {
+		return BuildContext{}, fmt.Errorf("build context %q is not one of %v", s, BuildContexts)
+	}
+	return bc, nil
+}
+
 // CompareBuildContexts returns a negative number, 0, or a positive number depending on
 // the relative positions of c1 and c2 in BuildContexts.
 func CompareBuildContexts(c1, c2 BuildContext) int {
@@ -96,3 +115,21 @@ func DocumentationForBuildContext(docs []*Documentation, bc BuildContext) *Docum
 	}
 	return nil
 }
+
+// GetUnitPreferring calls ds.GetUnit for um, fields and bc. If bc is the zero
+// BuildContext and fields include the documentation, the documentation for
+// preferred is selected instead, unless the unit has none, in which case the
+// documentation for the first element of BuildContexts is selected as usual.
+func GetUnitPreferring(ctx context.Context, ds DataSource, um *UnitMeta, fields FieldSet, bc, preferred BuildContext) (*Unit, error) {
+	if bc != (BuildContext{}) || preferred == (BuildContext{}) || fields&WithMain == 0 || !um.IsPackage() {
+		return ds.GetUnit(ctx, um, fields, bc)
+	}
+	u, err := ds.GetUnit(ctx, um, fields, preferred)
+	if err != nil {
+		return nil, err
+	}
+	if len(u.Documentation) > 0 {
+		return u, nil
+	}
+	return ds.GetUnit(ctx, um, fields, bc)
+}
@@ -37,3 +37,20 @@ func TestCompareBuildContexts(t *testing.T) {
 	// Special cases.
 	check(BuildContext{"?", "?"}, BuildContexts[len(BuildContexts)-1], 1) // unknown is last
 }
+
+func TestParseBuildContext(t *testing.T) {
+	for _, bc := range BuildContexts {
+		got, err := ParseBuildContext(bc.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != bc {
+			t.Errorf("ParseBuildContext(%q) = %v, want %v", bc, got, bc)
+		}
+	}
+	for _, s := range []string{"", "windows", "all/all", "plan9/386", "windows/amd64/x"} {
+		if _, err := ParseBuildContext(s); err == nil {
+			t.Errorf("ParseBuildContext(%q): got nil, want error", s)
+		}
+	}
+}
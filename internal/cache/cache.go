@@ -8,6 +8,8 @@ package cache
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -62,26 +64,86 @@ func (c *Cache) Delete(ctx context.Context, keys ...string) (err error) {
 // DeletePrefix deletes all keys beginning with prefix.
 func (c *Cache) DeletePrefix(ctx context.Context, prefix string) (err error) {
 	defer derrors.Wrap(&err, "DeletePrefix(%q)", prefix)
-	iter := c.client.Scan(ctx, 0, prefix+"*", int64(scanCount)).Iterator()
+	_, err = c.deletePrefix(ctx, prefix)
+	return err
+}
+
+// deletePrefix deletes all keys beginning with prefix, and returns the number
+// of keys it deleted.
+func (c *Cache) deletePrefix(ctx context.Context, prefix string) (int, error) {
+	iter := c.client.Scan(ctx, 0, globEscaper.Replace(prefix)+"*", int64(scanCount)).Iterator()
 	var keys []string
+	n := 0
 	for iter.Next(ctx) {
 		keys = append(keys, iter.Val())
 		if len(keys) > scanCount {
 			if err := c.Delete(ctx, keys...); err != nil {
-				return err
+				return n, err
 			}
+			n += len(keys)
 			keys = keys[:0]
 		}
 	}
 	if iter.Err() != nil {
-		return iter.Err()
+		return n, iter.Err()
 	}
 	if len(keys) > 0 {
-		return c.Delete(ctx, keys...)
+		if err := c.Delete(ctx, keys...); err != nil {
+			return n, err
+		}
+		n += len(keys)
 	}
-	return nil
+	return n, nil
 }
 
+// DeletePath deletes the page for the URL path "/"+path, as well as the pages
+// for any URL path of which it is a componentwise prefix, and returns the
+// number of pages deleted. That is, DeletePath(ctx, "example.com/mod") deletes
+// /example.com/mod, /example.com/mod@v1.2.3, /example.com/mod?tab=versions and
+// /example.com/mod/pkg, but not /example.com/module.
+//
+// Page cache keys are request URLs, so DeletePath deletes every cached page
+// that is derived from path or one of its versions, tabs or subdirectories.
+// The stale copies of those pages are deleted too, and counted.
+func (c *Cache) DeletePath(ctx context.Context, path string) (n int, err error) {
+	defer derrors.Wrap(&err, "DeletePath(%q)", path)
+	var errs []error
+	for _, key := range []string{"/" + path, StaleKey("/" + path)} {
+		if m, err := c.client.Unlink(ctx, key).Result(); err != nil {
+			errs = append(errs, err)
+		} else {
+			n += int(m)
+		}
+		// Delete all suffixes of the path followed by a character that marks its end.
+		for _, end := range "/@?#" {
+			m, err := c.deletePrefix(ctx, fmt.Sprintf("%s%c", key, end))
+			n += m
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return n, fmt.Errorf("%d errors, first is %w", len(errs), errs[0])
+	}
+	return n, nil
+}
+
+// staleKeyPrefix begins the keys of the stale copies of pages.
+const staleKeyPrefix = "stale:"
+
+// StaleKey returns the key of the stale copy of the page with the given key.
+// Stale copies outlive the pages they copy, and are served in their place when
+// a page has expired and cannot be generated again.
+func StaleKey(key string) string {
+	return staleKeyPrefix + key
+}
+
+// globEscaper escapes the characters that are special in the patterns of the
+// Redis SCAN command, so that prefixes like "/example.com/mod?" match only
+// themselves.
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
 // The "count" argument to the Redis SCAN command, which is a hint for how much
 // work to perform.
 // Also used as the batch size for Delete calls in DeletePrefix.
@@ -70,7 +70,7 @@ func TestDeletePrefix(t *testing.T) {
 		}
 	}
 
-	all := []string{"a", "b", "c", "a@x", "a/x"}
+	all := []string{"a", "b", "c", "a@x", "a/x", "x?", "x?y", "xy", "x*z"}
 	for _, k := range all {
 		must(t, c.Put(ctx, k, []byte("value"), 0))
 	}
@@ -78,12 +78,48 @@ func TestDeletePrefix(t *testing.T) {
 
 	scanCount = 1
 	must(t, c.DeletePrefix(ctx, "a"))
-	check([]string{"b", "c"})
+	check([]string{"b", "c", "x?", "x?y", "xy", "x*z"})
+	// Glob characters in the prefix match only themselves.
+	must(t, c.DeletePrefix(ctx, "x?"))
+	check([]string{"b", "c", "xy", "x*z"})
 
 	must(t, c.Clear(ctx))
 	check([]string{})
 }
 
+func TestDeletePath(t *testing.T) {
+	ctx := context.Background()
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	c := New(redis.NewClient(&redis.Options{Addr: s.Addr()}))
+
+	for _, k := range []string{
+		"/a.com/m", "/a.com/m@v1.0.0", "/a.com/m?tab=versions", "/a.com/m/pkg", "/a.com/m#x",
+		"/a.com/module", "/a.com", "/search?q=a.com/m",
+		StaleKey("/a.com/m@v1.0.0"), StaleKey("/a.com/module"),
+	} {
+		must(t, c.Put(ctx, k, []byte("value"), 0))
+	}
+	n, err := c.DeletePath(ctx, "a.com/m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 6 {
+		t.Errorf("got %d deleted, want 6", n)
+	}
+	got, err := c.client.Keys(ctx, "*").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	if want := []string{"/a.com", "/a.com/module", "/search?q=a.com/m", "stale:/a.com/module"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
 func must(t *testing.T, err error) {
 	t.Helper()
 	if err != nil {
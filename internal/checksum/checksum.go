@@ -0,0 +1,245 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package checksum computes the go.sum hashes of module versions and checks
+// them against a checksum database, as the go command does.
+package checksum
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/dirhash"
+	"golang.org/x/mod/sumdb/note"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/lru"
+)
+
+// sumGolangOrgKey is the verifier key of sum.golang.org, which the go command
+// also knows.
+const sumGolangOrgKey = "sum.golang.org+033de0ae+Ac4zctda0e5eza+HJyk9SxEdh+s3Ux18htTTAD8OuAn8"
+
+// maxCachedTiles is the maximum number of tiles of the database's tree that
+// are kept in memory. A tile is at most 8KB.
+const maxCachedTiles = 4096
+
+// Hashes returns the go.sum hashes of a module version: the hash of its
+// files, given in contentDir with the layout of a module zip's content
+// directory, and the hash of its go.mod file, whose contents are goMod.
+func Hashes(modulePath, version string, contentDir fs.FS, goMod []byte) (zipHash, goModHash string, err error) {
+	defer derrors.Wrap(&err, "checksum.Hashes(%q, %q)", modulePath, version)
+
+	prefix := modulePath + "@" + version + "/"
+	var files []string
+	err = fs.WalkDir(contentDir, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			files = append(files, prefix+path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+	zipHash, err = dirhash.Hash1(files, func(name string) (io.ReadCloser, error) {
+		return contentDir.Open(strings.TrimPrefix(name, prefix))
+	})
+	if err != nil {
+		return "", "", err
+	}
+	goModHash, err = dirhash.Hash1([]string{"go.mod"}, func(string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(goMod)), nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return zipHash, goModHash, nil
+}
+
+// A DB is a client of a checksum database. It keeps the state that the go
+// command keeps on disk, such as the latest signed tree, in memory.
+type DB struct {
+	name string
+	ops  *clientOps
+}
+
+// New returns a DB for the checksum database described by gosumdb, which has
+// the syntax of the GOSUMDB environment variable: "sum.golang.org", or a
+// verifier key optionally followed by the database URL. Requests to the
+// database are made with httpClient.
+func New(gosumdb string, httpClient *http.Client) (_ *DB, err error) {
+	defer derrors.Wrap(&err, "checksum.New(%q)", gosumdb)
+
+	fields := strings.Fields(gosumdb)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, errors.New("want key and optional URL")
+	}
+	key := fields[0]
+	if key == "sum.golang.org" {
+		key = sumGolangOrgKey
+	}
+	verifier, err := note.NewVerifier(key)
+	if err != nil {
+		return nil, err
+	}
+	url := "https://" + verifier.Name()
+	if len(fields) == 2 {
+		url = strings.TrimSuffix(fields[1], "/")
+	}
+	return &DB{
+		name: verifier.Name(),
+		ops: &clientOps{
+			url:        url,
+			key:        []byte(key),
+			httpClient: httpClient,
+			cache:      lru.New[string, []byte](maxCachedTiles),
+		},
+	}, nil
+}
+
+// Name returns the name of the database, such as "sum.golang.org".
+func (db *DB) Name() string {
+	return db.name
+}
+
+// Verify checks the go.sum hashes of a module version, as returned by Hashes,
+// against the database. It returns internal.ChecksumNotFound if the database
+// doesn't have the module version. An error means that the database could
+// not be consulted.
+func (db *DB) Verify(ctx context.Context, modulePath, version, zipHash, goModHash string) (_ internal.ChecksumStatus, err error) {
+	defer derrors.Wrap(&err, "checksum.Verify(%q, %q)", modulePath, version)
+
+	// A sumdb.Client remembers the result of every lookup, including
+	// failures, so use a new one for each module version. The state worth
+	// keeping is in db.ops.
+	ops := &lookupOps{clientOps: db.ops, ctx: ctx}
+	client := sumdb.NewClient(ops)
+	// Lookup returns the lines for either the module zip or its go.mod file,
+	// from the same record.
+	for _, h := range []struct{ vers, hash string }{
+		{version, zipHash},
+		{version + "/go.mod", goModHash},
+	} {
+		lines, err := client.Lookup(modulePath, h.vers)
+		if err != nil {
+			if ops.notFound.Load() {
+				return internal.ChecksumNotFound, nil
+			}
+			return internal.ChecksumUnchecked, err
+		}
+		if !slices.Contains(lines, modulePath+" "+h.vers+" "+h.hash) {
+			return internal.ChecksumMismatch, nil
+		}
+	}
+	return internal.ChecksumVerified, nil
+}
+
+// clientOps implements the parts of sumdb.ClientOps that are shared by all
+// lookups.
+type clientOps struct {
+	url        string
+	key        []byte
+	httpClient *http.Client
+	cache      *lru.Cache[string, []byte]
+
+	mu     sync.Mutex
+	latest []byte // the latest signed tree
+}
+
+// lookupOps implements sumdb.ClientOps for a single lookup.
+type lookupOps struct {
+	*clientOps
+	ctx context.Context
+	// notFound records whether the database reported that it doesn't have
+	// the module version. The sumdb.Client doesn't preserve the error.
+	notFound atomic.Bool
+}
+
+func (o *lookupOps) ReadRemote(path string) (_ []byte, err error) {
+	req, err := http.NewRequestWithContext(o.ctx, http.MethodGet, o.url+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(resp.Body)
+	case http.StatusNotFound, http.StatusGone:
+		if strings.HasPrefix(path, "/lookup/") {
+			o.notFound.Store(true)
+		}
+		return nil, fmt.Errorf("%s: %s: %w", path, resp.Status, derrors.NotFound)
+	default:
+		return nil, fmt.Errorf("%s: %s", path, resp.Status)
+	}
+}
+
+func (o *lookupOps) ReadConfig(file string) ([]byte, error) {
+	if file == "key" {
+		return o.key, nil
+	}
+	if strings.HasSuffix(file, "/latest") {
+		o.mu.Lock()
+		defer o.mu.Unlock()
+		return o.latest, nil
+	}
+	return nil, fmt.Errorf("unknown config %q", file)
+}
+
+func (o *lookupOps) WriteConfig(file string, old, new []byte) error {
+	if !strings.HasSuffix(file, "/latest") {
+		return fmt.Errorf("cannot write config %q", file)
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if !bytes.Equal(old, o.latest) {
+		return sumdb.ErrWriteConflict
+	}
+	o.latest = new
+	return nil
+}
+
+func (o *lookupOps) ReadCache(file string) ([]byte, error) {
+	if data, ok := o.cache.Get(file); ok {
+		return data, nil
+	}
+	return nil, derrors.NotFound
+}
+
+func (o *lookupOps) WriteCache(file string, data []byte) {
+	// Lookups are not cached: each module version is looked up once per
+	// fetch, and there are too many of them.
+	if strings.Contains(file, "/lookup/") {
+		return
+	}
+	o.cache.Put(file, data)
+}
+
+func (o *lookupOps) Log(msg string) {
+	log.Infof(o.ctx, "checksum database: %s", msg)
+}
+
+func (o *lookupOps) SecurityError(msg string) {
+	// The go command exits in this case. The lookup fails with
+	// sumdb.ErrSecurity, so the module version is left unchecked.
+	log.Errorf(o.ctx, "checksum database: %s", msg)
+}
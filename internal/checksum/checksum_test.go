@@ -0,0 +1,118 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package checksum
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/dirhash"
+	"golang.org/x/mod/sumdb/note"
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestHashes(t *testing.T) {
+	const (
+		modulePath = "golang.org/x/mod"
+		version    = "v0.22.0"
+		goMod      = "module golang.org/x/mod\n\ngo 1.22.0\n\nrequire golang.org/x/tools v0.13.0 // tagx:ignore\n"
+	)
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"go.mod":     goMod,
+		"mod.go":     "package mod\n",
+		"sub/sub.go": "package sub\n",
+	} {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	zipHash, goModHash, err := Hashes(modulePath, version, os.DirFS(dir), []byte(goMod))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantZipHash, err := dirhash.HashDir(dir, modulePath+"@"+version, dirhash.Hash1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if zipHash != wantZipHash {
+		t.Errorf("zip hash = %s, want %s", zipHash, wantZipHash)
+	}
+	// The hash of the go.mod file in go.sum.
+	const wantGoModHash = "h1:6SkKJ3Xj0I0BrPOZoBy3bdMptDDU9oJrpohJ3eWZ1fY="
+	if goModHash != wantGoModHash {
+		t.Errorf("go.mod hash = %s, want %s", goModHash, wantGoModHash)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	const (
+		zipHash   = "h1:zip="
+		goModHash = "h1:mod="
+	)
+	skey, vkey, err := note.GenerateKey(rand.Reader, "sum.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gosum := func(path, vers string) ([]byte, error) {
+		if path != "example.com/m" {
+			return nil, os.ErrNotExist
+		}
+		return fmt.Appendf(nil, "%s %s %s\n%s %s/go.mod %s\n", path, vers, zipHash, path, vers, goModHash), nil
+	}
+	srv := httptest.NewServer(sumdb.NewServer(sumdb.NewTestServer(skey, gosum)))
+	defer srv.Close()
+
+	db, err := New(vkey+" "+srv.URL, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := db.Name(), "sum.example.com"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+	ctx := context.Background()
+	for _, test := range []struct {
+		modulePath, zipHash, goModHash string
+		want                           internal.ChecksumStatus
+	}{
+		{"example.com/m", zipHash, goModHash, internal.ChecksumVerified},
+		{"example.com/m", "h1:other=", goModHash, internal.ChecksumMismatch},
+		{"example.com/m", zipHash, "h1:other=", internal.ChecksumMismatch},
+		{"example.com/unknown", zipHash, goModHash, internal.ChecksumNotFound},
+	} {
+		got, err := db.Verify(ctx, test.modulePath, "v1.0.0", test.zipHash, test.goModHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("Verify(%q, %q, %q) = %q, want %q", test.modulePath, test.zipHash, test.goModHash, got, test.want)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	db, err := New("sum.golang.org", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := db.ops.url, "https://sum.golang.org"; got != want {
+		t.Errorf("url = %q, want %q", got, want)
+	}
+	for _, bad := range []string{"", "notakey", "a b c"} {
+		if _, err := New(bad, nil); err == nil {
+			t.Errorf("New(%q) succeeded, want error", bad)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "time"
+
+// Ways of verifying a ModuleClaim.
+const (
+	// ClaimVerifiedByDNS means that a DNS TXT record of the host of the module
+	// path held the challenge.
+	ClaimVerifiedByDNS = "dns"
+	// ClaimVerifiedByFile means that a file at the root of the module's
+	// repository held the challenge.
+	ClaimVerifiedByFile = "file"
+)
+
+// ModuleClaim is a user's claim to own a module, on deployments that let
+// owners manage their modules. The claimant proves ownership by publishing
+// the challenge, and is identified by a secret that is issued with the
+// claim. Only the hash of the secret is stored.
+type ModuleClaim struct {
+	ID         int64
+	ModulePath string
+	Challenge  string
+	// VerifiedBy is ClaimVerifiedByDNS or ClaimVerifiedByFile, or empty if
+	// the claim has not been verified.
+	VerifiedBy string
+	// ContactURL is a link for users to contact the owner, shown on the
+	// pages of the module. It is set only on verified claims.
+	ContactURL string
+	CreatedAt  time.Time
+	VerifiedAt time.Time // zero if the claim has not been verified
+}
+
+// Verified reports whether the claim has been verified.
+func (c *ModuleClaim) Verified() bool {
+	return c.VerifiedBy != ""
+}
@@ -22,6 +22,11 @@ const (
 	// that a request can bypass the quota server.
 	BypassQuotaAuthHeader = "X-Go-Discovery-Auth-Bypass-Quota"
 
+	// APIKeyHeader is the header key used by clients of the JSON endpoints to
+	// present an API key. Requests with a valid key are subject to the key's
+	// quota instead of the IP-based one.
+	APIKeyHeader = "X-Go-Discovery-API-Key"
+
 	// BypassCacheAuthHeader is the header key used by the frontend server to
 	// know that a request can bypass cache.
 	BypassCacheAuthHeader = "X-Go-Discovery-Auth-Bypass-Cache"
@@ -33,6 +38,12 @@ const (
 	// AllowDebugHeader is the header key used by the frontend server that allows
 	// serving debug pages.
 	AllowDebugHeader = "X-Go-Discovery-Debug"
+
+	// DebugOverridesHeader is the header key holding the debug directives of
+	// a request, which are honored only along with AllowDebugHeader. The
+	// frontend server echoes the directives in effect in the response header
+	// of the same name.
+	DebugOverridesHeader = "X-Go-Discovery-Debug-Overrides"
 )
 
 // Config holds shared configuration values used in instantiating our server
@@ -85,6 +96,13 @@ type Config struct {
 	DBSecondaryHost                                 string // DB host to use if first one is down
 	DBPassword                                      string `json:"-" yaml:"-"`
 
+	// DBReplicaHosts are the hosts of read replicas of the DB. If there are
+	// any, read-only queries are sent to them.
+	DBReplicaHosts []string
+	// DBReplicaMaxLag is the replication lag beyond which a replica is not
+	// used.
+	DBReplicaMaxLag time.Duration
+
 	// Configuration for redis page cache.
 	RedisCacheHost, RedisBetaCacheHost, RedisCachePort string
 
@@ -110,11 +128,101 @@ type Config struct {
 	// benchmarking or other purposes.
 	ServeStats bool
 
+	// BaseURL is the scheme and host that the frontend is served at, such as
+	// "https://pkg.go.dev". Absolute links to the frontend, such as those in
+	// documentation served as Markdown, are made from it rather than from the
+	// Host header of the request, which the client controls.
+	BaseURL string
+
+	// TrustedProxyHops is the number of proxies in front of the frontend that
+	// append the address they received a request from to its
+	// X-Forwarded-For header. The frontend takes the address of the client
+	// from that many entries from the end of the header, since the client
+	// can set the entries before them. If it is zero, the frontend uses the
+	// peer address of the connection.
+	TrustedProxyHops int
+
+	// ServeLLMsTxt determines whether unit pages have a ?m=llms form, which
+	// serves a short plain-text digest of a package's documentation for
+	// language models, in the style of llms.txt.
+	ServeLLMsTxt bool
+
+	// CountPageViews determines whether the frontend counts views of unit
+	// pages, to show the most popular packages on the homepage. It is meant
+	// for private deployments.
+	CountPageViews bool
+
+	// CachePopularSearches determines whether the frontend counts requests
+	// for pages of search results, and serves the most popular ones from
+	// the results computed by the worker's /refresh-popular-searches.
+	CachePopularSearches bool
+
+	// ModuleClaims determines whether the frontend lets the owners of module
+	// paths claim them and manage how their modules are shown. It is meant
+	// for private deployments.
+	ModuleClaims bool
+
+	// DocFeedback is where reports of problems with the documentation on
+	// unit pages are filed: a GitHub repository, as "github.com/owner/repo",
+	// or an email address, as "mailto:address". If it is empty, unit pages do
+	// not offer to report problems.
+	DocFeedback string
+
+	// DocFeedbackGitHubToken authenticates the GitHub API requests that file
+	// issues for DocFeedback.
+	DocFeedbackGitHubToken string `json:"-" yaml:"-"`
+
+	// DocFeedbackSMTPAddr is the mail server, as host:port, and
+	// DocFeedbackFrom is the sender, of email for DocFeedback.
+	DocFeedbackSMTPAddr, DocFeedbackFrom string
+
 	// DisableErrorReporting disables sending errors to the GCP ErrorReporting system.
 	DisableErrorReporting bool
 
 	// VulnDB is the URL of the Go vulnerability DB.
 	VulnDB string
+
+	// VulnDBSyncInterval is how often the frontend syncs its in-memory
+	// mirror of VulnDB, and VulnDBExtra is a directory of OSV entries that
+	// the mirror serves along with those of VulnDB. If both are zero, the
+	// frontend reads VulnDB directly instead of mirroring it.
+	VulnDBSyncInterval time.Duration
+	VulnDBExtra        string
+
+	// PrivateAdvisories enables publishing advisories for vulnerabilities in
+	// modules that VulnDB does not cover, such as private ones, to the
+	// frontend with API keys. They are served with VulnDB by its mirror.
+	PrivateAdvisories bool
+
+	// ChecksumDB is the checksum database that the worker checks fetched
+	// module versions against, in the syntax of the GOSUMDB environment
+	// variable. If it is "off", module versions are not checked.
+	ChecksumDB string
+
+	// RequireVerifiedChecksums makes the worker fail to process module
+	// versions whose checksums don't match the checksum database.
+	RequireVerifiedChecksums bool
+
+	// RepoStatsHosts maps repository hosts to the kind of API they serve
+	// ("github" or "gitlab"). Statistics such as stars and open issues are
+	// fetched only for repositories on these hosts.
+	RepoStatsHosts map[string]string
+
+	// ContentPolicy is the policy that the worker checks the files of the
+	// module versions it fetches against, or nil if there is none.
+	ContentPolicy *ContentPolicy
+
+	// Namespaces are the sets of modules that the frontend serves from
+	// module proxies and databases of their own, rather than from ProxyURL
+	// and the database of this config.
+	Namespaces []*Namespace
+
+	// DefaultBuildContext is the build context, as "GOOS/GOARCH", whose
+	// documentation the frontend shows for a package with documentation for
+	// several build contexts, when the request doesn't name one. If it is
+	// empty, or the package has no documentation for it, the documentation
+	// for the first of the build contexts is shown.
+	DefaultBuildContext string
 }
 
 // MonitoredResource represents the resource that is running the current binary.
@@ -150,6 +258,10 @@ const StatementTimeout = 30 * time.Minute
 // to fetch source code from third party URLs.
 const SourceTimeout = 1 * time.Minute
 
+// RepoStatsTTL is how long repository statistics are considered fresh before
+// the worker fetches them again.
+const RepoStatsTTL = 24 * time.Hour
+
 // TaskIDChangeIntervalFrontend is the time period during which a given module
 // version can be re-enqueued to frontend tasks.
 const TaskIDChangeIntervalFrontend = 30 * time.Minute
@@ -170,6 +282,16 @@ func (c *Config) DBSecondaryConnInfo() string {
 	return c.dbConnInfo(c.DBSecondaryHost)
 }
 
+// DBReplicaConnInfos returns PostgreSQL connection strings for the read
+// replicas, keyed by host.
+func (c *Config) DBReplicaConnInfos() map[string]string {
+	m := map[string]string{}
+	for _, host := range c.DBReplicaHosts {
+		m[host] = c.dbConnInfo(host)
+	}
+	return m
+}
+
 // dbConnInfo returns a PostgresSQL connection string for the given host.
 func (c *Config) dbConnInfo(host string) string {
 	// For the connection string syntax, see
@@ -243,6 +365,7 @@ type QuotaSettings struct {
 	QPS        int  `yaml:"QPS"`        // allowed queries per second, per IP block
 	Burst      int  `yaml:"Burst"`      // maximum requests per second, per block; the size of the token bucket
 	MaxEntries int  `yaml:"MaxEntries"` // maximum number of entries to keep track of
+	APIKeyQPS  int  `yaml:"APIKeyQPS"`  // allowed queries per second, per API key without its own limit
 	// Record data about blocking, but do not actually block.
 	// This is a *bool, so we can distinguish "not present" from "false" in an override
 	RecordOnly *bool `yaml:"RecordOnly"`
@@ -252,6 +375,77 @@ type QuotaSettings struct {
 	HMACKey    []byte   `json:"-" yaml:"-"` // key for obfuscating IPs
 }
 
+// A ContentPolicy describes the files that module versions should not have,
+// such as leaked credentials. The worker records the files of a module
+// version that break the policy, and may refuse to process it.
+type ContentPolicy struct {
+	// MaxFileSize is the size in bytes of the largest allowed file. If it is
+	// zero, there is no limit.
+	MaxFileSize int64 `yaml:"MaxFileSize"`
+
+	// BanExecutables forbids executables and object files, such as ELF,
+	// Mach-O and PE files, whose content cannot be reviewed.
+	BanExecutables bool `yaml:"BanExecutables"`
+
+	// SecretPatterns are the patterns of secrets that files must not hold.
+	SecretPatterns []*SecretPattern `yaml:"SecretPatterns"`
+
+	// Block makes the worker refuse to process module versions that break
+	// the policy, instead of only recording the files that break it.
+	Block bool `yaml:"Block"`
+}
+
+// A SecretPattern is a regular expression, in the syntax of package regexp,
+// that matches a kind of secret, like an access key of a cloud provider.
+type SecretPattern struct {
+	Name   string `yaml:"Name"`
+	Regexp string `yaml:"Regexp"`
+}
+
+// A Namespace is the set of modules whose paths start with a prefix, like
+// the patterns of GOPRIVATE. The frontend serves the modules of a namespace
+// from its own data source, with its own license policy, so that one
+// deployment can show the modules of a private ecosystem along with public
+// ones without mixing them in search results.
+type Namespace struct {
+	// Name identifies the namespace in the "ns" query param of search
+	// requests, like "corp".
+	Name string `yaml:"Name"`
+
+	// Prefix is the path prefix of the modules of the namespace, like
+	// "corp.example.com".
+	Prefix string `yaml:"Prefix"`
+
+	// ProxyURL is the module proxy that serves the modules of the namespace.
+	ProxyURL string `yaml:"ProxyURL"`
+
+	// DBName is the database that holds the modules of the namespace, which
+	// a worker configured with ProxyURL and DBName fills. If it is empty,
+	// modules are fetched from ProxyURL when they are requested.
+	DBName string `yaml:"DBName"`
+
+	// BypassLicenseCheck makes the frontend show all the information about
+	// the modules of the namespace, even if they are not redistributable.
+	BypassLicenseCheck bool `yaml:"BypassLicenseCheck"`
+}
+
+// Contains reports whether the module or package at path is in n.
+func (n *Namespace) Contains(path string) bool {
+	return path == n.Prefix || strings.HasPrefix(path, n.Prefix+"/")
+}
+
+// NamespaceFor returns the namespace of c that contains path, or nil if
+// there is none. If several do, the one with the longest prefix wins.
+func (c *Config) NamespaceFor(path string) *Namespace {
+	var ns *Namespace
+	for _, n := range c.Namespaces {
+		if n.Contains(path) && (ns == nil || len(n.Prefix) > len(ns.Prefix)) {
+			ns = n
+		}
+	}
+	return ns
+}
+
 // Dump outputs the current config information to the given Writer.
 func (c *Config) Dump(w io.Writer) error {
 	fmt.Fprint(w, "config: ")
@@ -0,0 +1,337 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package configschema validates YAML configuration files against JSON
+// Schemas.
+//
+// Only the subset of JSON Schema used by pkgsite's configuration schemas is
+// supported: the keywords type, properties, required, additionalProperties,
+// items, enum, pattern, minLength, minimum and maximum, along with the
+// annotations $schema, $id, title and description. A schema that uses any
+// other keyword is rejected, so that it cannot silently go unenforced.
+package configschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"gopkg.in/yaml.v3"
+)
+
+// A Schema is a parsed JSON Schema.
+type Schema struct {
+	SchemaURI   string `json:"$schema"`
+	ID          string `json:"$id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+
+	Type                 typeList           `json:"type"`
+	Properties           map[string]*Schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *additional        `json:"additionalProperties"`
+	Items                *Schema            `json:"items"`
+	Enum                 []any              `json:"enum"`
+	Pattern              string             `json:"pattern"`
+	MinLength            *int               `json:"minLength"`
+	Minimum              *float64           `json:"minimum"`
+	Maximum              *float64           `json:"maximum"`
+
+	patternRx *regexp.Regexp
+}
+
+// typeList is the value of the type keyword, which is either a single type
+// name or a list of them.
+type typeList []string
+
+func (t *typeList) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = typeList{s}
+		return nil
+	}
+	var ss []string
+	if err := json.Unmarshal(data, &ss); err != nil {
+		return errors.New("type must be a string or a list of strings")
+	}
+	*t = ss
+	return nil
+}
+
+// additional is the value of the additionalProperties keyword, which is
+// either a boolean or a schema for the values of the additional properties.
+type additional struct {
+	allowed bool
+	schema  *Schema
+}
+
+func (a *additional) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.allowed); err == nil {
+		return nil
+	}
+	a.allowed = true
+	return json.Unmarshal(data, &a.schema)
+}
+
+// Parse parses a JSON Schema.
+func Parse(data []byte) (_ *Schema, err error) {
+	defer derrors.Wrap(&err, "configschema.Parse")
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var s Schema
+	if err := dec.Decode(&s); err != nil {
+		return nil, err
+	}
+	if err := s.compile("#"); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// MustParse is like Parse, but panics on error. It is meant for schemas
+// embedded in the binary.
+func MustParse(data []byte) *Schema {
+	s, err := Parse(data)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+var typeNames = []string{"array", "boolean", "integer", "null", "number", "object", "string"}
+
+// compile checks s, whose location in its schema is loc, and compiles its
+// patterns.
+func (s *Schema) compile(loc string) error {
+	for _, t := range s.Type {
+		if !slices.Contains(typeNames, t) {
+			return fmt.Errorf("%s: unknown type %q", loc, t)
+		}
+	}
+	if s.Pattern != "" {
+		rx, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("%s: %v", loc, err)
+		}
+		s.patternRx = rx
+	}
+	for name, p := range s.Properties {
+		if err := p.compile(loc + "/properties/" + name); err != nil {
+			return err
+		}
+	}
+	if s.AdditionalProperties != nil && s.AdditionalProperties.schema != nil {
+		if err := s.AdditionalProperties.schema.compile(loc + "/additionalProperties"); err != nil {
+			return err
+		}
+	}
+	if s.Items != nil {
+		if err := s.Items.compile(loc + "/items"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// An Error describes a value in a YAML document that does not conform to a
+// schema.
+type Error struct {
+	Line int    // line of the value in the document
+	Path string // path to the value, like "experiments[2].rollout"; empty for the document itself
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+	}
+	return fmt.Sprintf("line %d: %s: %s", e.Line, e.Path, e.Msg)
+}
+
+// Validate checks the YAML document yamlData against s. If the document does
+// not conform to s, Validate returns the errors.Join of an *Error for each
+// problem, in document order. An empty document is treated as an empty
+// object.
+func (s *Schema) Validate(yamlData []byte) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(yamlData, &doc); err != nil {
+		return err
+	}
+	var v validator
+	if len(doc.Content) == 0 {
+		v.validate(s, &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: 1}, "")
+	} else {
+		v.validate(s, doc.Content[0], "")
+	}
+	return errors.Join(v.errs...)
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) errorf(n *yaml.Node, path, format string, args ...any) {
+	v.errs = append(v.errs, &Error{Line: n.Line, Path: path, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) validate(s *Schema, n *yaml.Node, path string) {
+	for n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	typ := nodeType(n)
+	if len(s.Type) > 0 && !slices.Contains(s.Type, typ) &&
+		!(typ == "integer" && slices.Contains(s.Type, "number")) {
+		v.errorf(n, path, "got %s, want %s", describe(n, typ), strings.Join(s.Type, " or "))
+		return
+	}
+	if len(s.Enum) > 0 {
+		var val any
+		if err := n.Decode(&val); err != nil || !slices.ContainsFunc(s.Enum, func(e any) bool { return equal(e, val) }) {
+			var want []string
+			for _, e := range s.Enum {
+				want = append(want, fmt.Sprintf("%v", e))
+			}
+			v.errorf(n, path, "got %s, want one of %s", describe(n, typ), strings.Join(want, ", "))
+		}
+	}
+	switch typ {
+	case "string":
+		if s.MinLength != nil && len([]rune(n.Value)) < *s.MinLength {
+			if *s.MinLength == 1 {
+				v.errorf(n, path, "must not be empty")
+			} else {
+				v.errorf(n, path, "must have at least %d characters", *s.MinLength)
+			}
+		}
+		if s.patternRx != nil && !s.patternRx.MatchString(n.Value) {
+			v.errorf(n, path, "%q does not match %s", n.Value, s.Pattern)
+		}
+	case "integer", "number":
+		f, err := strconv.ParseFloat(strings.ReplaceAll(n.Value, "_", ""), 64)
+		if err != nil {
+			// Not a decimal number, like 0x10. Let the consumer of the
+			// document decide.
+			break
+		}
+		if s.Minimum != nil && f < *s.Minimum {
+			v.errorf(n, path, "%s is less than the minimum %v", n.Value, *s.Minimum)
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			v.errorf(n, path, "%s is greater than the maximum %v", n.Value, *s.Maximum)
+		}
+	case "array":
+		if s.Items != nil {
+			for i, c := range n.Content {
+				v.validate(s.Items, c, fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case "object":
+		v.validateObject(s, n, path)
+	}
+}
+
+func (v *validator) validateObject(s *Schema, n *yaml.Node, path string) {
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, val := n.Content[i], n.Content[i+1]
+		name := k.Value
+		p := name
+		if path != "" {
+			p = path + "." + name
+		}
+		if seen[name] {
+			v.errorf(k, p, "duplicate key")
+			continue
+		}
+		seen[name] = true
+		if ps, ok := s.Properties[name]; ok {
+			v.validate(ps, val, p)
+			continue
+		}
+		switch a := s.AdditionalProperties; {
+		case a == nil || (a.allowed && a.schema == nil):
+		case !a.allowed:
+			v.errorf(k, path, "unknown key %q%s", name, knownKeys(s))
+		default:
+			v.validate(a.schema, val, p)
+		}
+	}
+	for _, r := range s.Required {
+		if !seen[r] {
+			v.errorf(n, path, "missing required key %q", r)
+		}
+	}
+}
+
+// knownKeys returns a description of the properties of s, to help with
+// misspelled keys.
+func knownKeys(s *Schema) string {
+	if len(s.Properties) == 0 {
+		return ""
+	}
+	var names []string
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return " (known keys are " + strings.Join(names, ", ") + ")"
+}
+
+// nodeType returns the JSON Schema type of n.
+func nodeType(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "object"
+	case yaml.SequenceNode:
+		return "array"
+	}
+	switch n.ShortTag() {
+	case "!!null":
+		return "null"
+	case "!!bool":
+		return "boolean"
+	case "!!int":
+		return "integer"
+	case "!!float":
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+// describe describes the value of n, whose type is typ, for an error message.
+func describe(n *yaml.Node, typ string) string {
+	switch typ {
+	case "object", "array", "null":
+		return typ
+	case "string":
+		return fmt.Sprintf("string %q", n.Value)
+	default:
+		return fmt.Sprintf("%s %s", typ, n.Value)
+	}
+}
+
+// equal reports whether the enum value e, decoded from JSON, equals the YAML
+// value v.
+func equal(e, v any) bool {
+	if f, ok := e.(float64); ok {
+		switch v := v.(type) {
+		case int:
+			return f == float64(v)
+		case float64:
+			return f == v
+		}
+		return false
+	}
+	return reflect.DeepEqual(e, v)
+}
@@ -0,0 +1,148 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package configschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testSchema = `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "items": {
+      "type": ["array", "null"],
+      "items": {
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["name"],
+        "properties": {
+          "name": {"type": "string", "pattern": "^[a-z]+$"},
+          "size": {"type": "integer", "minimum": 0, "maximum": 10},
+          "color": {"enum": ["red", "blue"]},
+          "weight": {"type": "number"}
+        }
+      }
+    },
+    "words": {
+      "type": "object",
+      "additionalProperties": {
+        "type": "array",
+        "items": {"type": "string", "minLength": 1}
+      }
+    }
+  }
+}`
+
+func TestValidate(t *testing.T) {
+	s, err := Parse([]byte(testSchema))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name string
+		doc  string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"comment", "# nothing\n", nil},
+		{"null list", "items:\n", nil},
+		{
+			"valid",
+			`items:
+- name: a
+  size: 10
+  color: red
+  weight: 2
+- name: b
+  weight: 2.5
+words:
+  x: [y, z]
+`,
+			nil,
+		},
+		{"not an object", "- a\n", []string{"line 1: got array, want object"}},
+		{
+			"unknown key",
+			"itmes: []\n",
+			[]string{`line 1: unknown key "itmes" (known keys are items, words)`},
+		},
+		{
+			"items",
+			`items:
+- size: 11
+  name: A
+- name: b
+  size: x
+  color: green
+- name: c
+  extra: 1
+  weight: 1.5
+`,
+			[]string{
+				"line 2: items[0].size: 11 is greater than the maximum 10",
+				`line 3: items[0].name: "A" does not match ^[a-z]+$`,
+				`line 5: items[1].size: got string "x", want integer`,
+				`line 6: items[1].color: got string "green", want one of red, blue`,
+				`line 8: items[2]: unknown key "extra" (known keys are color, name, size, weight)`,
+			},
+		},
+		{
+			"required",
+			"items:\n- size: 1\n",
+			[]string{`line 2: items[0]: missing required key "name"`},
+		},
+		{
+			"additional properties",
+			"words:\n  x: [\"\"]\n  y: z\n",
+			[]string{
+				"line 2: words.x[0]: must not be empty",
+				`line 3: words.y: got string "z", want array`,
+			},
+		},
+		{
+			"duplicate key",
+			"words:\n  x: []\n  x: []\n",
+			[]string{"line 3: words.x: duplicate key"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var got []string
+			if err := s.Validate([]byte(test.doc)); err != nil {
+				for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+					var se *Error
+					if !errors.As(e, &se) {
+						t.Fatalf("got %T, want *Error", e)
+					}
+					got = append(got, se.Error())
+				}
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	for _, test := range []struct {
+		schema string
+		want   string
+	}{
+		{`{"type": "object", "minProperties": 1}`, "minProperties"},
+		{`{"type": "thing"}`, `unknown type "thing"`},
+		{`{"properties": {"a": {"pattern": "("}}}`, "#/properties/a"},
+	} {
+		_, err := Parse([]byte(test.schema))
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("Parse(%s): got %v, want error containing %q", test.schema, err, test.want)
+		}
+	}
+}
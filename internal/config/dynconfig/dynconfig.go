@@ -9,6 +9,7 @@ package dynconfig
 
 import (
 	"context"
+	_ "embed"
 	"errors"
 	"io"
 	"os"
@@ -16,6 +17,7 @@ import (
 
 	"cloud.google.com/go/storage"
 	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config/configschema"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/log"
 	"gopkg.in/yaml.v3"
@@ -28,6 +30,36 @@ type DynamicConfig struct {
 	// requires careful coordination with the config file contents.
 
 	Experiments []*internal.Experiment
+
+	// EnqueueThrottles restrict which module versions the worker enqueues
+	// for processing.
+	EnqueueThrottles []*EnqueueThrottle `yaml:"enqueueThrottles"`
+
+	// SearchSynonyms map words to the words that replace them in search
+	// documents, such as an organization's internal product names to the
+	// technologies they are built on. They are added to the built-in
+	// synonyms, and replace the built-in entry for the same word. A word
+	// mapped to an empty list is omitted from search documents. See
+	// postgres.SetSearchSynonyms.
+	SearchSynonyms map[string][]string `yaml:"searchSynonyms"`
+}
+
+// An EnqueueThrottle limits how many module versions under a module path
+// prefix are enqueued by each run of the worker's /enqueue handler.
+type EnqueueThrottle struct {
+	// Prefix is a module path prefix. It matches a module path if it is
+	// equal to the path or is a componentwise prefix of it, so "gopkg.in"
+	// matches "gopkg.in/yaml.v2" but not "gopkg.invalid".
+	Prefix string `yaml:"prefix"`
+
+	// Limit is the maximum number of matching module versions to enqueue
+	// per run. Zero means no limit, which exempts a prefix from the throttle
+	// of a shorter one. It is ignored if Deny is true.
+	Limit int `yaml:"limit"`
+
+	// Deny, if true, prevents matching module versions from being enqueued
+	// at all.
+	Deny bool `yaml:"deny"`
 }
 
 // Read reads dynamic configuration from the given location.
@@ -69,10 +101,28 @@ func Read(ctx context.Context, location string) (_ *DynamicConfig, err error) {
 	return Parse(data)
 }
 
-// Parse parses yamlData as a YAML description of DynamicConfig.
+// Schema is the JSON Schema that dynamic config files must conform to.
+//
+//go:embed schema.json
+var Schema []byte
+
+var schema = configschema.MustParse(Schema)
+
+// Validate checks that yamlData conforms to Schema. The errors it returns
+// give the line number of each problem.
+func Validate(yamlData []byte) error {
+	return schema.Validate(yamlData)
+}
+
+// Parse parses yamlData as a YAML description of DynamicConfig. It returns an
+// error if yamlData does not conform to Schema, rather than ignoring the
+// entries that do not.
 func Parse(yamlData []byte) (_ *DynamicConfig, err error) {
 	defer derrors.Wrap(&err, "dynconfig.Parse(data)")
 
+	if err := Validate(yamlData); err != nil {
+		return nil, err
+	}
 	var dc DynamicConfig
 	if err := yaml.Unmarshal(yamlData, &dc); err != nil {
 		return nil, err
@@ -0,0 +1,119 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynconfig
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestParse(t *testing.T) {
+	got, err := Parse([]byte(`
+experiments:
+- name: search-autocomplete
+  rollout: 50
+enqueueThrottles:
+- prefix: github.com/big
+  limit: 10
+- prefix: example.com
+  deny: true
+searchSynonyms:
+  k8s: [kubernetes]
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &DynamicConfig{
+		Experiments: []*internal.Experiment{{Name: "search-autocomplete", Rollout: 50}},
+		EnqueueThrottles: []*EnqueueThrottle{
+			{Prefix: "github.com/big", Limit: 10},
+			{Prefix: "example.com", Deny: true},
+		},
+		SearchSynonyms: map[string][]string{"k8s": {"kubernetes"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, test := range []struct {
+		yaml string
+		want []string
+	}{
+		{
+			"experiments:\n- name: a\n  rollout: 101\n",
+			[]string{"line 3: experiments[0].rollout: 101 is greater than the maximum 100"},
+		},
+		{
+			// Previously, the misspelled key was silently ignored.
+			"experiments:\n- name: a\n  rolout: 100\n",
+			[]string{`line 3: experiments[0]: unknown key "rolout"`},
+		},
+		{
+			"enqueueThrottles:\n- limit: -1\n",
+			[]string{
+				"line 2: enqueueThrottles[0].limit: -1 is less than the minimum 0",
+				`line 2: enqueueThrottles[0]: missing required key "prefix"`,
+			},
+		},
+		{
+			"searchSynonyms:\n  k8s: kubernetes\n",
+			[]string{`line 2: searchSynonyms.k8s: got string "kubernetes", want array or null`},
+		},
+	} {
+		_, err := Parse([]byte(test.yaml))
+		if err == nil {
+			t.Errorf("%q: got nil, want error", test.yaml)
+			continue
+		}
+		for _, w := range test.want {
+			if !strings.Contains(err.Error(), w) {
+				t.Errorf("%q: got %v, want error containing %q", test.yaml, err, w)
+			}
+		}
+	}
+}
+
+func TestParseExclusions(t *testing.T) {
+	got, err := ParseExclusions(strings.NewReader(`# Exclusions.
+
+github.com/golang/go    We want users to view pkg.go.dev/<stdlib> instead
+bad.com/m@v1.2.3	Too big to process
+example.com/ 	spam
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Exclusion{
+		{Pattern: "github.com/golang/go", Reason: "We want users to view pkg.go.dev/<stdlib> instead", Line: 3},
+		{Pattern: "bad.com/m@v1.2.3", Reason: "Too big to process", Line: 4},
+		{Pattern: "example.com/", Reason: "spam", Line: 5},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	_, err = ParseExclusions(strings.NewReader(`a.com/b
+a.com/c@latest reason
+a.com/d reason
+a.com/d again
+`))
+	if err == nil {
+		t.Fatal("got nil, want error")
+	}
+	for _, w := range []string{
+		`line 1: missing reason for "a.com/b"`,
+		`line 2: "a.com/c@latest": "latest" is not a semantic version`,
+		`line 4: "a.com/d" is already excluded on line 3`,
+	} {
+		if !strings.Contains(err.Error(), w) {
+			t.Errorf("got %v, want error containing %q", err, w)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dynconfig
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// An Exclusion is a line of an exclusions file, which lists the module paths
+// and versions that pkgsite does not process or display. The file is read
+// from the location in GO_DISCOVERY_EXCLUDED_FILENAME.
+//
+// Each line of the file has a pattern and the reason for the exclusion,
+// separated by white space. Empty lines and lines starting with "#" are
+// ignored. A pattern is either a path prefix, which excludes every module
+// path that it is a componentwise prefix of, or a module path followed by
+// "@" and a semantic version, which excludes only that module version.
+type Exclusion struct {
+	Pattern string
+	Reason  string
+	Line    int // line number in the file
+}
+
+// ParseExclusions parses an exclusions file. It reports every invalid line,
+// along with its line number, in a single error.
+func ParseExclusions(r io.Reader) ([]*Exclusion, error) {
+	var (
+		exs   []*Exclusion
+		errs  []error
+		lines = map[string]int{} // line number of each pattern
+	)
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pattern, reason := line, ""
+		if i := strings.IndexAny(line, " \t"); i >= 0 {
+			pattern, reason = line[:i], strings.TrimSpace(line[i+1:])
+		}
+		if reason == "" {
+			errs = append(errs, fmt.Errorf("line %d: missing reason for %q", n, pattern))
+			continue
+		}
+		if err := checkExclusionPattern(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %v", n, err))
+			continue
+		}
+		if prev, ok := lines[pattern]; ok {
+			errs = append(errs, fmt.Errorf("line %d: %q is already excluded on line %d", n, pattern, prev))
+			continue
+		}
+		lines[pattern] = n
+		exs = append(exs, &Exclusion{Pattern: pattern, Reason: reason, Line: n})
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return exs, nil
+}
+
+func checkExclusionPattern(pattern string) error {
+	path, version, found := strings.Cut(pattern, "@")
+	if found {
+		if err := module.CheckImportPath(path); err != nil {
+			return err
+		}
+		if !semver.IsValid(version) {
+			return fmt.Errorf("%q: %q is not a semantic version", pattern, version)
+		}
+		return nil
+	}
+	// A prefix may end in a slash, which is redundant.
+	if err := module.CheckImportPath(strings.TrimSuffix(path, "/")); err != nil {
+		return err
+	}
+	return nil
+}
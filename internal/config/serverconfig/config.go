@@ -13,18 +13,23 @@ import (
 	"io"
 	"math/rand"
 	"net/http"
+	"net/url"
 	"os"
 	"path"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 
 	"cloud.google.com/go/storage"
+	"golang.org/x/mod/module"
 	"golang.org/x/net/context/ctxhttp"
+	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/config"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/secrets"
+	"golang.org/x/pkgsite/internal/source"
 	"gopkg.in/yaml.v3"
 )
 
@@ -149,12 +154,15 @@ func Init(ctx context.Context) (_ *config.Config, err error) {
 		DBName:               GetEnv("GO_DISCOVERY_DATABASE_NAME", "discovery-db"),
 		DBSecret:             os.Getenv("GO_DISCOVERY_DATABASE_SECRET"),
 		DBSSL:                GetEnv("GO_DISCOVERY_DATABASE_SSL", "disable"),
+		DBReplicaHosts:       parseCommaList(os.Getenv("GO_DISCOVERY_DATABASE_REPLICA_HOSTS")),
+		DBReplicaMaxLag:      time.Duration(GetEnvInt(ctx, "GO_DISCOVERY_DATABASE_REPLICA_LAG", 10)) * time.Second,
 		RedisCacheHost:       os.Getenv("GO_DISCOVERY_REDIS_HOST"),
 		RedisBetaCacheHost:   os.Getenv("GO_DISCOVERY_REDIS_BETA_HOST"),
 		RedisCachePort:       GetEnv("GO_DISCOVERY_REDIS_PORT", "6379"),
 		Quota: config.QuotaSettings{
 			Enable:     os.Getenv("GO_DISCOVERY_ENABLE_QUOTA") == "true",
 			QPS:        GetEnvInt(ctx, "GO_DISCOVERY_QUOTA_QPS", 10),
+			APIKeyQPS:  GetEnvInt(ctx, "GO_DISCOVERY_QUOTA_API_KEY_QPS", 50),
 			Burst:      20,   // ignored in redis-based quota implementation
 			MaxEntries: 1000, // ignored in redis-based quota implementation
 			RecordOnly: func() *bool {
@@ -166,11 +174,68 @@ func Init(ctx context.Context) (_ *config.Config, err error) {
 		UseProfiler:           os.Getenv("GO_DISCOVERY_USE_PROFILER") == "true",
 		LogLevel:              os.Getenv("GO_DISCOVERY_LOG_LEVEL"),
 		ServeStats:            os.Getenv("GO_DISCOVERY_SERVE_STATS") == "true",
+		BaseURL:               strings.TrimSuffix(GetEnv("GO_DISCOVERY_BASE_URL", "https://pkg.go.dev"), "/"),
+		TrustedProxyHops:      GetEnvInt(ctx, "GO_DISCOVERY_TRUSTED_PROXY_HOPS", 0),
+		ServeLLMsTxt:          os.Getenv("GO_DISCOVERY_SERVE_LLMS_TXT") == "true",
+		CountPageViews:        os.Getenv("GO_DISCOVERY_COUNT_PAGE_VIEWS") == "true",
+		ModuleClaims:          os.Getenv("GO_DISCOVERY_MODULE_CLAIMS") == "true",
+		CachePopularSearches:  os.Getenv("GO_DISCOVERY_CACHE_POPULAR_SEARCHES") == "true",
 		DisableErrorReporting: os.Getenv("GO_DISCOVERY_DISABLE_ERROR_REPORTING") == "true",
+		DocFeedback:           os.Getenv("GO_DISCOVERY_DOC_FEEDBACK"),
+		DocFeedbackSMTPAddr:   os.Getenv("GO_DISCOVERY_DOC_FEEDBACK_SMTP_ADDR"),
+		DocFeedbackFrom:       os.Getenv("GO_DISCOVERY_DOC_FEEDBACK_FROM"),
 		VulnDB:                GetEnv("GO_DISCOVERY_VULN_DB", "https://storage.googleapis.com/go-vulndb"),
+		VulnDBSyncInterval:    time.Duration(GetEnvInt(ctx, "GO_DISCOVERY_VULN_DB_SYNC_MINUTES", 0)) * time.Minute,
+		VulnDBExtra:           os.Getenv("GO_DISCOVERY_VULN_DB_EXTRA"),
+		PrivateAdvisories:     os.Getenv("GO_DISCOVERY_PRIVATE_ADVISORIES") == "true",
+		ChecksumDB:            GetEnv("GO_DISCOVERY_CHECKSUM_DB", "sum.golang.org"),
+
+		RequireVerifiedChecksums: os.Getenv("GO_DISCOVERY_REQUIRE_CHECKSUM_MATCH") == "true",
+		DocFeedbackGitHubToken:   os.Getenv("GO_DISCOVERY_DOC_FEEDBACK_TOKEN"),
 	}
 	log.SetLevel(cfg.LogLevel)
 
+	cfg.RepoStatsHosts = source.DefaultRepoStatsHosts
+	if hosts := os.Getenv("GO_DISCOVERY_REPO_STATS_HOSTS"); hosts != "" {
+		cfg.RepoStatsHosts, err = source.ParseRepoStatsHosts(hosts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if file := os.Getenv("GO_DISCOVERY_CONTENT_POLICY"); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ContentPolicy, err = parseContentPolicy(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if file := os.Getenv("GO_DISCOVERY_NAMESPACES"); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Namespaces, err = parseNamespaces(data, cfg.DBName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if u, err := url.Parse(cfg.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" {
+		return nil, fmt.Errorf("GO_DISCOVERY_BASE_URL: %q is not a URL of the form https://host", cfg.BaseURL)
+	}
+
+	if bc := os.Getenv("GO_DISCOVERY_DEFAULT_BUILD_CONTEXT"); bc != "" {
+		if _, err := internal.ParseBuildContext(bc); err != nil {
+			return nil, err
+		}
+		cfg.DefaultBuildContext = bc
+	}
+
 	bucket := os.Getenv("GO_DISCOVERY_CONFIG_BUCKET")
 	configDynamic := os.Getenv("GO_DISCOVERY_CONFIG_DYNAMIC")
 	exclude := os.Getenv("GO_DISCOVERY_EXCLUDED_FILENAME")
@@ -321,6 +386,7 @@ func processOverrides(ctx context.Context, cfg *config.Config, bytes []byte) {
 	override(ctx, "DBSecondaryHost", &cfg.DBSecondaryHost, ov.DBSecondaryHost)
 	override(ctx, "DBName", &cfg.DBName, ov.DBName)
 	override(ctx, "Quota.QPS", &cfg.Quota.QPS, ov.Quota.QPS)
+	override(ctx, "Quota.APIKeyQPS", &cfg.Quota.APIKeyQPS, ov.Quota.APIKeyQPS)
 	override(ctx, "Quota.Burst", &cfg.Quota.Burst, ov.Quota.Burst)
 	override(ctx, "Quota.MaxEntries", &cfg.Quota.MaxEntries, ov.Quota.MaxEntries)
 	override(ctx, "Quota.RecordOnly", &cfg.Quota.RecordOnly, ov.Quota.RecordOnly)
@@ -375,6 +441,71 @@ func gceMetadata(ctx context.Context, name string) (_ string, err error) {
 	return string(bytes), nil
 }
 
+// parseContentPolicy parses a content policy in YAML, and checks that its
+// secret patterns have unique names and valid regular expressions.
+func parseContentPolicy(data []byte) (_ *config.ContentPolicy, err error) {
+	defer derrors.Wrap(&err, "parseContentPolicy")
+
+	var p config.ContentPolicy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	if p.MaxFileSize < 0 {
+		return nil, fmt.Errorf("negative MaxFileSize %d", p.MaxFileSize)
+	}
+	names := map[string]bool{}
+	for _, sp := range p.SecretPatterns {
+		if sp.Name == "" || names[sp.Name] {
+			return nil, fmt.Errorf("secret pattern %q: missing or duplicate name", sp.Name)
+		}
+		names[sp.Name] = true
+		if _, err := regexp.Compile(sp.Regexp); err != nil {
+			return nil, fmt.Errorf("secret pattern %q: %v", sp.Name, err)
+		}
+	}
+	return &p, nil
+}
+
+// namespaceNameRx matches the valid names of namespaces.
+var namespaceNameRx = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// parseNamespaces parses a YAML list of namespaces, and checks that each
+// has a unique name and prefix, a module proxy, and a database other than
+// the default one, dbName, so that its modules are kept apart.
+func parseNamespaces(data []byte, dbName string) (_ []*config.Namespace, err error) {
+	defer derrors.Wrap(&err, "parseNamespaces")
+
+	var nss []*config.Namespace
+	if err := yaml.Unmarshal(data, &nss); err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	prefixes := map[string]bool{}
+	for _, n := range nss {
+		if !namespaceNameRx.MatchString(n.Name) {
+			return nil, fmt.Errorf("bad namespace name %q", n.Name)
+		}
+		if names[n.Name] {
+			return nil, fmt.Errorf("duplicate namespace name %q", n.Name)
+		}
+		names[n.Name] = true
+		if err := module.CheckImportPath(n.Prefix); err != nil {
+			return nil, fmt.Errorf("namespace %q: bad prefix: %v", n.Name, err)
+		}
+		if prefixes[n.Prefix] {
+			return nil, fmt.Errorf("namespace %q: duplicate prefix %q", n.Name, n.Prefix)
+		}
+		prefixes[n.Prefix] = true
+		if n.ProxyURL == "" {
+			return nil, fmt.Errorf("namespace %q: missing ProxyURL", n.Name)
+		}
+		if n.DBName != "" && n.DBName == dbName {
+			return nil, fmt.Errorf("namespace %q: DBName is the default database %q", n.Name, dbName)
+		}
+	}
+	return nss, nil
+}
+
 func parseCommaList(s string) []string {
 	var a []string
 	for _, p := range strings.Split(s, ",") {
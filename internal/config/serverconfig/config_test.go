@@ -120,3 +120,73 @@ func TestEnvAndApp(t *testing.T) {
 		}
 	}
 }
+
+func TestParseNamespaces(t *testing.T) {
+	got, err := parseNamespaces([]byte(`
+- Name: corp
+  Prefix: corp.example.com
+  ProxyURL: https://proxy.corp.example.com
+  DBName: discovery-db-corp
+- Name: partner
+  Prefix: example.org/partner
+  ProxyURL: https://proxy.example.org
+  BypassLicenseCheck: true
+`), "discovery-db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*config.Namespace{
+		{Name: "corp", Prefix: "corp.example.com", ProxyURL: "https://proxy.corp.example.com", DBName: "discovery-db-corp"},
+		{Name: "partner", Prefix: "example.org/partner", ProxyURL: "https://proxy.example.org", BypassLicenseCheck: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	for _, bad := range []string{
+		`[{Name: Corp, Prefix: corp.example.com, ProxyURL: https://p}]`,
+		`[{Name: corp, Prefix: "corp.example.com/", ProxyURL: https://p}]`,
+		`[{Name: corp, Prefix: corp.example.com}]`,
+		`[{Name: corp, Prefix: corp.example.com, ProxyURL: https://p, DBName: discovery-db}]`,
+		`[{Name: a, Prefix: corp.example.com, ProxyURL: https://p}, {Name: a, Prefix: example.org, ProxyURL: https://p}]`,
+		`[{Name: a, Prefix: corp.example.com, ProxyURL: https://p}, {Name: b, Prefix: corp.example.com, ProxyURL: https://p}]`,
+	} {
+		if _, err := parseNamespaces([]byte(bad), "discovery-db"); err == nil {
+			t.Errorf("%s: got nil error, want error", bad)
+		}
+	}
+}
+
+func TestParseContentPolicy(t *testing.T) {
+	got, err := parseContentPolicy([]byte(`
+MaxFileSize: 1048576
+BanExecutables: true
+SecretPatterns:
+  - Name: aws-access-key
+    Regexp: 'AKIA[0-9A-Z]{16}'
+Block: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &config.ContentPolicy{
+		MaxFileSize:    1 << 20,
+		BanExecutables: true,
+		SecretPatterns: []*config.SecretPattern{{Name: "aws-access-key", Regexp: "AKIA[0-9A-Z]{16}"}},
+		Block:          true,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	for _, bad := range []string{
+		`MaxFileSize: -1`,
+		`SecretPatterns: [{Regexp: x}]`,
+		`SecretPatterns: [{Name: a, Regexp: x}, {Name: a, Regexp: y}]`,
+		`SecretPatterns: [{Name: a, Regexp: "("}]`,
+	} {
+		if _, err := parseContentPolicy([]byte(bad)); err == nil {
+			t.Errorf("%s: got nil error, want error", bad)
+		}
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "fmt"
+
+// An InconsistencyKind is a kind of inconsistency among the rows stored for
+// module versions that the constraints of the database schema do not
+// prevent, and that can make pages fail.
+type InconsistencyKind string
+
+const (
+	// InconsistencyOrphanedUnit is a unit whose path is not in its module.
+	InconsistencyOrphanedUnit InconsistencyKind = "orphaned-unit"
+
+	// InconsistencyMissingSource is the documentation of a redistributable
+	// unit without the source it is rendered from.
+	InconsistencyMissingSource InconsistencyKind = "missing-source"
+
+	// InconsistencyStaleSymbolHistory is a symbol whose history says it was
+	// introduced at a version of its module that is not stored.
+	InconsistencyStaleSymbolHistory InconsistencyKind = "stale-symbol-history"
+
+	// InconsistencyStaleSearchDocument is a search document for a package
+	// at a module version that is not stored, or whose unit is not in that
+	// module version.
+	InconsistencyStaleSearchDocument InconsistencyKind = "stale-search-document"
+)
+
+// InconsistencyKinds are all the kinds of inconsistencies.
+var InconsistencyKinds = []InconsistencyKind{
+	InconsistencyOrphanedUnit,
+	InconsistencyMissingSource,
+	InconsistencyStaleSymbolHistory,
+	InconsistencyStaleSearchDocument,
+}
+
+// ParseInconsistencyKind returns the inconsistency kind named s.
+func ParseInconsistencyKind(s string) (InconsistencyKind, error) {
+	for _, k := range InconsistencyKinds {
+		if string(k) == s {
+			return k, nil
+		}
+	}
+	return "", fmt.Errorf("unknown inconsistency kind %q", s)
+}
+
+// An Inconsistency is an inconsistency found in the rows of a module version.
+type Inconsistency struct {
+	Kind InconsistencyKind
+	// ID identifies the inconsistent row in the table that is checked for
+	// Kind.
+	ID         int64
+	ModulePath string
+	Version    string
+	Path       string // of the unit or package
+	Detail     string // more about the inconsistency, if anything
+}
+
+func (i *Inconsistency) String() string {
+	s := fmt.Sprintf("%s: %s@%s", i.Kind, i.ModulePath, i.Version)
+	if i.Path != "" && i.Path != i.ModulePath {
+		s += " " + i.Path
+	}
+	if i.Detail != "" {
+		s += ": " + i.Detail
+	}
+	return s
+}
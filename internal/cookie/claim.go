@@ -0,0 +1,9 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cookie
+
+// ModuleClaim holds the secret of a module claim. It is scoped to the path of
+// the claim's page, /claim/<id>, so a browser can hold several claims.
+const ModuleClaim = "module-claim"
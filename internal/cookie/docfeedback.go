@@ -0,0 +1,10 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cookie
+
+// DocFeedbackToken holds the token of the last form to report a problem with
+// documentation that the browser was served. A report is only filed if the
+// form carries the same token.
+const DocFeedbackToken = "doc-feedback-token"
@@ -18,6 +18,15 @@ import (
 // a request was redirected from.
 const AlternativeModuleFlash = "tmp-redirected-from-alternative-module"
 
+// SymbolVersionFlash indicates the symbol and version that a request was
+// redirected from because the symbol is not in that version, in the form
+// "Symbol@version".
+const SymbolVersionFlash = "tmp-redirected-from-symbol-version"
+
+// MissingSymbolFlash indicates the symbol that the version switcher could
+// not keep in the URL fragment because it is not in the version switched to.
+const MissingSymbolFlash = "tmp-missing-symbol"
+
 // Extract returns the value of the cookie at name and deletes the cookie.
 func Extract(w http.ResponseWriter, r *http.Request, name string) (_ string, err error) {
 	defer derrors.Wrap(&err, "Extract")
@@ -0,0 +1,9 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cookie
+
+// SearchStdlibOnly is set to "1" when the user has limited package searches
+// to the standard library. It is read by scripts, so it is not HttpOnly.
+const SearchStdlibOnly = "search-stdlib-only"
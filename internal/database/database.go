@@ -22,6 +22,7 @@ import (
 	"github.com/lib/pq"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/middleware/stats"
 )
 
 // DB wraps a sql.DB. The methods it exports correspond closely to those of
@@ -37,7 +38,8 @@ type DB struct {
 	conn       *sql.Conn     // the Conn of the Tx, when tx != nil
 	opts       sql.TxOptions // valid when tx != nil
 	mu         sync.Mutex
-	maxRetries int // max times a single transaction was retried
+	maxRetries int          // max times a single transaction was retried
+	replicas   *ReplicaPool // if non-nil, used for read-only queries outside transactions
 }
 
 // Open creates a new DB  for the given connection string.
@@ -81,13 +83,21 @@ func redactPassword(dbinfo string) string {
 	return passwordRegexp.ReplaceAllLiteralString(dbinfo, "password=REDACTED")
 }
 
-// Close closes the database connection.
+// Close closes the database connection, and the connections to its replicas.
+// It closes all of them even if closing one fails, and returns the errors
+// joined.
 func (db *DB) Close() error {
-	return db.db.Close()
+	var errs []error
+	if db.replicas != nil {
+		errs = append(errs, db.replicas.Close())
+	}
+	errs = append(errs, db.db.Close())
+	return errors.Join(errs...)
 }
 
 // Exec executes a SQL statement and returns the number of rows it affected.
 func (db *DB) Exec(ctx context.Context, query string, args ...any) (_ int64, err error) {
+	defer stats.Time(ctx, stats.StageDB)()
 	defer logQuery(ctx, query, args, db.instanceID, db.IsRetryable())(&err)
 	res, err := db.execResult(ctx, query, args...)
 	if err != nil {
@@ -110,15 +120,24 @@ func (db *DB) execResult(ctx context.Context, query string, args ...any) (res sq
 
 // Query runs the DB query.
 func (db *DB) Query(ctx context.Context, query string, args ...any) (_ *sql.Rows, err error) {
+	defer stats.Time(ctx, stats.StageDB)()
 	defer logQuery(ctx, query, args, db.instanceID, db.IsRetryable())(&err)
 	if db.tx != nil {
 		return db.tx.QueryContext(ctx, query, args...)
 	}
+	if r := db.replicaFor(query); r != nil {
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err == nil {
+			return rows, nil
+		}
+		log.Warningf(ctx, "query on replica %s failed, retrying on primary: %v", r.host, err)
+	}
 	return db.db.QueryContext(ctx, query, args...)
 }
 
 // QueryRow runs the query and returns a single row.
 func (db *DB) QueryRow(ctx context.Context, query string, args ...any) *sql.Row {
+	defer stats.Time(ctx, stats.StageDB)()
 	defer logQuery(ctx, query, args, db.instanceID, db.IsRetryable())(nil)
 	start := time.Now()
 	defer func() {
@@ -131,6 +150,13 @@ func (db *DB) QueryRow(ctx context.Context, query string, args ...any) *sql.Row
 	if db.tx != nil {
 		return db.tx.QueryRowContext(ctx, query, args...)
 	}
+	if r := db.replicaFor(query); r != nil {
+		row := r.db.QueryRowContext(ctx, query, args...)
+		if row.Err() == nil {
+			return row
+		}
+		log.Warningf(ctx, "query on replica %s failed, retrying on primary: %v", r.host, row.Err())
+	}
 	return db.db.QueryRowContext(ctx, query, args...)
 }
 
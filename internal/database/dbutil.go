@@ -175,6 +175,8 @@ func ResetDB(ctx context.Context, db *DB) error {
 			TRUNCATE paths CASCADE;
 			TRUNCATE symbol_names CASCADE;
 			TRUNCATE imports_unique;
+			TRUNCATE imported_by_changes;
+			TRUNCATE autocomplete_completions;
 			TRUNCATE latest_module_versions;`); err != nil {
 			return err
 		}
@@ -184,6 +186,36 @@ func ResetDB(ctx context.Context, db *DB) error {
 		if _, err := tx.Exec(ctx, `TRUNCATE excluded_prefixes;`); err != nil {
 			return err
 		}
+		if _, err := tx.Exec(ctx, `TRUNCATE repo_stats;`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `TRUNCATE api_keys;`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `TRUNCATE unit_views;`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `TRUNCATE host_stats;`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `TRUNCATE feature_readiness;`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `TRUNCATE takedowns;`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `TRUNCATE fetch_claims;`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `TRUNCATE rendered_readmes;`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `TRUNCATE imported_by_history;`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `TRUNCATE advisories;`); err != nil {
+			return err
+		}
 		return nil
 	}); err != nil {
 		return fmt.Errorf("error resetting test DB: %v", err)
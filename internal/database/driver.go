@@ -9,13 +9,20 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"slices"
 
 	"contrib.go.opencensus.io/integrations/ocsql"
 )
 
 // RegisterOCWrapper registers a driver that wraps the OpenCensus driver, which in
-// turn wraps the driver named as the first argument.
+// turn wraps the driver named as the first argument. If the wrapper is
+// already registered, it is returned as is, with the options it was
+// registered with.
 func RegisterOCWrapper(driverName string, opts ...ocsql.TraceOption) (string, error) {
+	name := "ocWrapper-" + driverName
+	if slices.Contains(sql.Drivers(), name) {
+		return name, nil
+	}
 	// Get the driver to wrap.
 	db, err := sql.Open(driverName, "")
 	if err != nil {
@@ -25,7 +32,6 @@ func RegisterOCWrapper(driverName string, opts ...ocsql.TraceOption) (string, er
 	if err := db.Close(); err != nil {
 		return "", err
 	}
-	name := "ocWrapper-" + driverName
 	sql.Register(name, &wrapOCDriver{dri, opts})
 	return name, nil
 }
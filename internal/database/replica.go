@@ -0,0 +1,195 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/poller"
+)
+
+// A ReplicaPool is a set of read replicas of a primary database.
+//
+// A DB with a ReplicaPool sends read-only queries that are not part of a
+// transaction to one of the replicas, if there is one whose replication lag
+// is within the pool's limit. Everything else goes to the primary. If a
+// query on a replica fails, it is retried on the primary.
+type ReplicaPool struct {
+	replicas []*replica
+	maxLag   time.Duration
+	poller   *poller.Poller // current value is a []replicaStatus
+	next     atomic.Uint64  // for choosing replicas in turn
+}
+
+type replica struct {
+	host string // for logging
+	db   *sql.DB
+}
+
+// replicaStatus is the result of checking a replica.
+type replicaStatus struct {
+	healthy bool
+	lag     time.Duration
+}
+
+// OpenReplicaPool opens a connection to each of the replicas in dbinfos, which
+// are keyed by host. Replicas that lag the primary by more than maxLag are not
+// used.
+//
+// The replicas are assumed to be unhealthy until the first check. Call Start
+// to check them periodically.
+func OpenReplicaPool(driverName string, dbinfos map[string]string, maxLag time.Duration) (_ *ReplicaPool, err error) {
+	defer derrors.Wrap(&err, "database.OpenReplicaPool(%q, %d replicas, %s)", driverName, len(dbinfos), maxLag)
+
+	var replicas []*replica
+	for host, dbinfo := range dbinfos {
+		db, err := sql.Open(driverName, dbinfo)
+		if err != nil {
+			for _, r := range replicas {
+				r.db.Close()
+			}
+			return nil, err
+		}
+		replicas = append(replicas, &replica{host: host, db: db})
+	}
+	return newReplicaPool(replicas, maxLag), nil
+}
+
+func newReplicaPool(replicas []*replica, maxLag time.Duration) *ReplicaPool {
+	p := &ReplicaPool{replicas: replicas, maxLag: maxLag}
+	p.poller = poller.New(make([]replicaStatus, len(replicas)), p.check, func(err error) {
+		log.Errorf(context.Background(), "checking replicas: %v", err)
+	})
+	return p
+}
+
+// Start checks the replicas immediately, and then at the given period in a
+// separate goroutine. To stop checking, cancel the context.
+func (p *ReplicaPool) Start(ctx context.Context, period time.Duration) {
+	p.poller.Poll(ctx)
+	p.poller.Start(ctx, period)
+}
+
+// Close closes the connections to the replicas.
+func (p *ReplicaPool) Close() error {
+	var errs []error
+	for _, r := range p.replicas {
+		if err := r.db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("closing replica %s: %w", r.host, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// replicationLagQuery computes how far a replica is behind the primary, in
+// seconds.
+//
+// A replica that is not streaming from the primary, because it has no WAL
+// receiver, has an unknown lag (NULL), as does one that has not replayed any
+// transaction. A replica that has replayed everything it has received is
+// behind by the time since it last heard from the primary, which sends
+// keepalive messages even when it is not written to; so a replica whose
+// connection to an idle primary is stuck falls behind. Otherwise the lag is
+// the age of the last transaction the replica replayed.
+//
+// The database user must be a member of pg_read_all_stats to see the
+// status of the WAL receiver; otherwise every replica is lagging.
+const replicationLagQuery = `
+	SELECT CASE
+		WHEN r.last_msg_receipt_time IS NULL THEN NULL
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn()
+			THEN EXTRACT(EPOCH FROM now() - r.last_msg_receipt_time)
+		ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp())
+	END
+	FROM (SELECT 1) AS one
+	LEFT JOIN pg_stat_wal_receiver r ON r.status = 'streaming'`
+
+// unknownLag is the lag of a replica whose lag cannot be determined. It
+// exceeds any limit.
+const unknownLag = time.Duration(math.MaxInt64)
+
+// replicationLag converts the result of replicationLagQuery to a duration.
+func replicationLag(seconds sql.NullFloat64) time.Duration {
+	if !seconds.Valid {
+		return unknownLag
+	}
+	return time.Duration(seconds.Float64 * float64(time.Second))
+}
+
+// check checks the health and replication lag of each replica.
+func (p *ReplicaPool) check(ctx context.Context) (any, error) {
+	statuses := make([]replicaStatus, len(p.replicas))
+	for i, r := range p.replicas {
+		var lagSeconds sql.NullFloat64
+		if err := r.db.QueryRowContext(ctx, replicationLagQuery).Scan(&lagSeconds); err != nil {
+			log.Warningf(ctx, "replica %s is unhealthy: %v", r.host, err)
+			continue
+		}
+		statuses[i] = replicaStatus{healthy: true, lag: replicationLag(lagSeconds)}
+		switch lag := statuses[i].lag; {
+		case lag == unknownLag:
+			log.Warningf(ctx, "replica %s is not streaming from the primary", r.host)
+		case lag > p.maxLag:
+			log.Warningf(ctx, "replica %s is %s behind the primary", r.host, lag)
+		}
+	}
+	return statuses, nil
+}
+
+// pick returns the next replica that is healthy and not too far behind the
+// primary, or nil if there is none.
+func (p *ReplicaPool) pick() *replica {
+	if p == nil || len(p.replicas) == 0 {
+		return nil
+	}
+	statuses := p.poller.Current().([]replicaStatus)
+	start := p.next.Add(1)
+	for i := range p.replicas {
+		j := int((start + uint64(i)) % uint64(len(p.replicas)))
+		if s := statuses[j]; s.healthy && s.lag <= p.maxLag {
+			return p.replicas[j]
+		}
+	}
+	return nil
+}
+
+// Queries that can be sent to a replica begin with SELECT and do not lock
+// rows.
+var (
+	selectRegexp  = regexp.MustCompile(`(?is)^\s*SELECT\b`)
+	lockingRegexp = regexp.MustCompile(`(?i)\bFOR\s+(NO\s+KEY\s+)?(UPDATE|SHARE|KEY\s+SHARE)\b`)
+)
+
+// isReadOnlyQuery reports whether query only reads, and so can be sent to a
+// replica. It errs on the side of the primary: queries starting with WITH,
+// which may contain data-modifying statements, are not read-only.
+func isReadOnlyQuery(query string) bool {
+	return selectRegexp.MatchString(query) && !lockingRegexp.MatchString(query) &&
+		!strings.Contains(strings.ToLower(query), "pg_advisory")
+}
+
+// replicaFor returns a replica to run query on, or nil if it should run on
+// the primary.
+func (db *DB) replicaFor(query string) *replica {
+	if db.tx != nil || db.replicas == nil || !isReadOnlyQuery(query) {
+		return nil
+	}
+	return db.replicas.pick()
+}
+
+// SetReplicas makes db send read-only queries to the replicas in p.
+func (db *DB) SetReplicas(p *ReplicaPool) {
+	db.replicas = p
+}
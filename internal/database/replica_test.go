@@ -0,0 +1,111 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"golang.org/x/pkgsite/internal/poller"
+)
+
+func TestIsReadOnlyQuery(t *testing.T) {
+	for _, test := range []struct {
+		query string
+		want  bool
+	}{
+		{"SELECT 1", true},
+		{"\n\t\tselect path FROM paths WHERE id = $1", true},
+		{"SELECT * FROM modules FOR UPDATE", false},
+		{"SELECT * FROM modules FOR NO KEY UPDATE", false},
+		{"SELECT * FROM modules for share", false},
+		{"SELECT pg_advisory_xact_lock($1)", false},
+		{"INSERT INTO paths (path) VALUES ($1) RETURNING id", false},
+		{"UPDATE modules SET status = 200", false},
+		{"WITH x AS (DELETE FROM paths RETURNING id) SELECT * FROM x", false},
+		{"SELECTED", false},
+	} {
+		if got := isReadOnlyQuery(test.query); got != test.want {
+			t.Errorf("isReadOnlyQuery(%q) = %t, want %t", test.query, got, test.want)
+		}
+	}
+}
+
+func TestReplicaPoolPick(t *testing.T) {
+	r0 := &replica{host: "r0"}
+	r1 := &replica{host: "r1"}
+	r2 := &replica{host: "r2"}
+	p := newReplicaPool([]*replica{r0, r1, r2}, 10*time.Second)
+
+	// Before the first check, no replica is used.
+	if got := p.pick(); got != nil {
+		t.Fatalf("before check: got %s, want nil", got.host)
+	}
+
+	setStatuses := func(statuses ...replicaStatus) {
+		p.poller = poller.New(statuses, nil, nil)
+	}
+	setStatuses(
+		replicaStatus{healthy: true, lag: time.Second},
+		replicaStatus{healthy: false},
+		replicaStatus{healthy: true, lag: time.Minute}, // too far behind
+	)
+	for i := 0; i < 3; i++ {
+		if got := p.pick(); got != r0 {
+			t.Fatalf("got %v, want r0", got)
+		}
+	}
+
+	setStatuses(
+		replicaStatus{healthy: true, lag: time.Second},
+		replicaStatus{healthy: true, lag: unknownLag}, // not streaming
+		replicaStatus{healthy: false},
+	)
+	for i := 0; i < 3; i++ {
+		if got := p.pick(); got != r0 {
+			t.Fatalf("got %v, want r0", got)
+		}
+	}
+
+	// Healthy replicas are used in turn.
+	setStatuses(
+		replicaStatus{healthy: true},
+		replicaStatus{healthy: true},
+		replicaStatus{healthy: true},
+	)
+	seen := map[*replica]bool{}
+	for i := 0; i < 3; i++ {
+		seen[p.pick()] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("got %d distinct replicas, want 3", len(seen))
+	}
+
+	setStatuses(replicaStatus{}, replicaStatus{}, replicaStatus{})
+	if got := p.pick(); got != nil {
+		t.Errorf("all unhealthy: got %s, want nil", got.host)
+	}
+
+	var nilPool *ReplicaPool
+	if got := nilPool.pick(); got != nil {
+		t.Errorf("nil pool: got %s, want nil", got.host)
+	}
+}
+
+func TestReplicationLag(t *testing.T) {
+	for _, test := range []struct {
+		seconds sql.NullFloat64
+		want    time.Duration
+	}{
+		{sql.NullFloat64{Float64: 1.5, Valid: true}, 1500 * time.Millisecond},
+		{sql.NullFloat64{Float64: 0, Valid: true}, 0},
+		{sql.NullFloat64{}, unknownLag},
+	} {
+		if got := replicationLag(test.seconds); got != test.want {
+			t.Errorf("replicationLag(%v) = %s, want %s", test.seconds, got, test.want)
+		}
+	}
+}
@@ -6,7 +6,11 @@ package internal
 
 import (
 	"context"
+	"slices"
+	"strings"
 	"time"
+
+	"golang.org/x/mod/semver"
 )
 
 // SearchOptions provide information used by db.Search.
@@ -25,6 +29,74 @@ type SearchOptions struct {
 
 	// SymbolFilter is the word in a search query with a # prefix.
 	SymbolFilter string
+
+	// ModulePath and Version, if set, limit the search to the packages and
+	// symbols of that module version.
+	ModulePath string
+	Version    string
+
+	// Filters limit a package search to packages with the given facet
+	// values.
+	Filters SearchFilters
+}
+
+// SearchFilters limit a search to packages with particular facet values. An
+// empty field does not limit the search.
+type SearchFilters struct {
+	License string // a license type, such as "MIT"
+	Host    string // the first element of the module path, such as "github.com"
+	Major   string // the major version of the module, such as "v2"
+}
+
+// IsZero reports whether f does not limit a search.
+func (f SearchFilters) IsZero() bool {
+	return f == SearchFilters{}
+}
+
+// Match reports whether a package in the given module version with the
+// given license types passes the filters.
+func (f SearchFilters) Match(modulePath, version string, licenseTypes []string) bool {
+	host, major := SearchFacetValues(modulePath, version)
+	return (f.License == "" || slices.Contains(licenseTypes, f.License)) &&
+		(f.Host == "" || f.Host == host) &&
+		(f.Major == "" || f.Major == major)
+}
+
+// SearchFacetValues returns the host and major version facet values of a
+// package in the given module version. The host is the first element of the
+// module path. The major version comes from the module path, or from the
+// version if the path has no major version suffix.
+func SearchFacetValues(modulePath, version string) (host, major string) {
+	host, _, _ = strings.Cut(modulePath, "/")
+	major = MajorVersionForModule(modulePath)
+	if major == "" {
+		major = semver.Major(version)
+	}
+	return host, major
+}
+
+// SearchFacets holds the number of packages in the results of a search for
+// each value of a facet.
+type SearchFacets struct {
+	Licenses      []*FacetCount
+	Hosts         []*FacetCount
+	MajorVersions []*FacetCount
+
+	// Approximate is true if the counts cover only some of the results,
+	// because there were too many to count.
+	Approximate bool
+}
+
+// FacetCount is the number of search results with a facet value.
+type FacetCount struct {
+	Value string
+	Count int
+}
+
+// InScope reports whether results from the given module version are in the
+// scope of a search with opts.
+func (opts SearchOptions) InScope(modulePath, version string) bool {
+	return opts.ModulePath == "" || (modulePath == opts.ModulePath && version == opts.Version)
 }
 
 // SearchResult represents a single search result from SearchDocuments.
@@ -54,6 +126,10 @@ type SearchResult struct {
 	// based on the version in search documents.
 	OtherMajor map[string]int
 
+	// Similar is a list of SearchResults for near duplicates of this one,
+	// such as forks and vendored copies, with lower scores.
+	Similar []*SearchResult
+
 	// NumResults is the total number of packages that were returned for this
 	// search.
 	NumResults uint64
@@ -72,6 +148,22 @@ type SearchResult struct {
 	Offset int
 }
 
+// An AutocompleteSuggestion is a possible completion of a partially typed
+// search query.
+type AutocompleteSuggestion struct {
+	// PackagePath is the path of the suggested package, or of the package
+	// that declares Symbol.
+	PackagePath string
+
+	// Symbol is the name of the suggested symbol. It is empty if the
+	// suggestion is a package.
+	Symbol string
+
+	// NumImportedBy is the number of packages that import PackagePath. It is
+	// used to rank suggestions.
+	NumImportedBy int
+}
+
 // DataSource is the interface used by the frontend to interact with module data.
 type DataSource interface {
 	// See the internal/postgres package for further documentation of these
@@ -0,0 +1,43 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "testing"
+
+func TestSearchFacetValues(t *testing.T) {
+	for _, test := range []struct {
+		modulePath, version string
+		wantHost, wantMajor string
+	}{
+		{"github.com/a/b", "v1.2.3", "github.com", "v1"},
+		{"github.com/a/b", "v0.0.0-20200101000000-abcdefabcdef", "github.com", "v0"},
+		{"github.com/a/b", "v3.0.0+incompatible", "github.com", "v3"},
+		{"github.com/a/b/v2", "v2.1.0", "github.com", "v2"},
+		{"gopkg.in/yaml.v3", "v3.0.1", "gopkg.in", "v3"},
+		{"std", "v1.21.0", "std", "v1"},
+	} {
+		host, major := SearchFacetValues(test.modulePath, test.version)
+		if host != test.wantHost || major != test.wantMajor {
+			t.Errorf("SearchFacetValues(%q, %q) = (%q, %q), want (%q, %q)",
+				test.modulePath, test.version, host, major, test.wantHost, test.wantMajor)
+		}
+	}
+}
+
+func TestSearchFiltersMatch(t *testing.T) {
+	f := SearchFilters{License: "MIT", Major: "v2"}
+	if !f.Match("github.com/a/b/v2", "v2.0.0", []string{"Apache-2.0", "MIT"}) {
+		t.Error("got no match, want match")
+	}
+	if f.Match("github.com/a/b", "v1.0.0", []string{"MIT"}) {
+		t.Error("got match for wrong major version, want no match")
+	}
+	if f.Match("github.com/a/b/v2", "v2.0.0", nil) {
+		t.Error("got match for wrong license, want no match")
+	}
+	if !(SearchFilters{}).Match("example.com", "v0.1.0", nil) {
+		t.Error("zero filters: got no match, want match")
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"golang.org/x/pkgsite/internal/config/serverconfig"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/memory"
 )
 
 // KeyStatus is a tag key named "status".
@@ -39,6 +40,7 @@ type Router struct {
 	http.Handler
 	mux    *http.ServeMux
 	tagger RouteTagger
+	allocs *memory.AllocRecorder
 }
 
 // NewRouter creates a new Router, using tagger to tag incoming requests in
@@ -62,10 +64,19 @@ func NewRouter(tagger RouteTagger) *Router {
 func (r *Router) Handle(route string, handler http.Handler) {
 	r.mux.HandleFunc(route, func(w http.ResponseWriter, req *http.Request) {
 		tag := r.tagger(route, req)
+		if r.allocs != nil {
+			defer r.allocs.Start(tag, req.URL.String())()
+		}
 		ochttp.WithRouteTag(handler, tag).ServeHTTP(w, req)
 	})
 }
 
+// RecordAllocs makes r record the heap allocations of each request in rec,
+// under the request's route tag.
+func (r *Router) RecordAllocs(rec *memory.AllocRecorder) {
+	r.allocs = rec
+}
+
 // HandleFunc is a wrapper around Handle for http.HandlerFuncs.
 func (r *Router) HandleFunc(route string, handler http.HandlerFunc) {
 	r.Handle(route, handler)
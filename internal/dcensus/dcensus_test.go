@@ -13,6 +13,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"go.opencensus.io/plugin/ochttp"
 	"go.opencensus.io/stats/view"
+	"golang.org/x/pkgsite/internal/memory"
 )
 
 func TestRouter(t *testing.T) {
@@ -63,3 +64,24 @@ func TestRouter(t *testing.T) {
 		t.Errorf("unexpected route tag counts (-want +got):\n%s", diff)
 	}
 }
+
+func TestRouterRecordAllocs(t *testing.T) {
+	rec := memory.NewAllocRecorder(10)
+	router := NewRouter(nil)
+	router.RecordAllocs(rec)
+	router.HandleFunc("/A/", func(w http.ResponseWriter, r *http.Request) {})
+	router.HandleFunc("/B/", func(w http.ResponseWriter, r *http.Request) {})
+	for _, path := range []string{"/A/x", "/A/y", "/B/z"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+	got := map[string]int{}
+	for _, r := range rec.Routes() {
+		got[r.Route] = r.Requests
+	}
+	if diff := cmp.Diff(map[string]int{"A": 2, "B": 1}, got); diff != "" {
+		t.Errorf("requests by route mismatch (-want +got):\n%s", diff)
+	}
+	if n := len(rec.Top()); n != 3 {
+		t.Errorf("got %d top requests, want 3", n)
+	}
+}
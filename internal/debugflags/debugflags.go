@@ -0,0 +1,141 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package debugflags provides request-scoped overrides of how the frontend
+// serves a request, used by operators to debug production.
+package debugflags
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// RendererReload is the renderer variant that parses the page templates
+// anew for the request, as in dev mode.
+const RendererReload = "reload"
+
+// renderers is the set of valid renderer variants.
+var renderers = map[string]bool{
+	RendererReload: true,
+}
+
+// Flags are the overrides in effect for a request.
+//
+// Flags are parsed from directives, each of which is one of:
+//
+//	nocache             bypass the response caches
+//	experiment:NAME     enable the experiment NAME
+//	experiment:-NAME    disable the experiment NAME
+//	renderer:VARIANT    render pages with the given variant
+//	timing              report how long each stage of the request took
+type Flags struct {
+	// BypassCache reports whether responses should neither be served from
+	// nor stored in a cache.
+	BypassCache bool
+
+	// EnabledExperiments and DisabledExperiments are the experiments that
+	// are forced on and off.
+	EnabledExperiments, DisabledExperiments []string
+
+	// Renderer is the renderer variant, or the empty string for the
+	// default one.
+	Renderer string
+
+	// ShowTiming reports whether the time spent in each stage of serving the
+	// request should be reported in a Server-Timing header and in the page
+	// footer.
+	ShowTiming bool
+}
+
+// Parse parses the directives into Flags. It returns an error if a directive
+// is invalid, or if isExperiment returns false for the name of an experiment
+// in a directive.
+func Parse(directives []string, isExperiment func(string) bool) (*Flags, error) {
+	f := &Flags{}
+	for _, d := range directives {
+		key, value, _ := strings.Cut(strings.TrimSpace(d), ":")
+		switch key {
+		case "nocache":
+			if value != "" {
+				return nil, fmt.Errorf("%q: nocache takes no value", d)
+			}
+			f.BypassCache = true
+		case "experiment":
+			name, disable := strings.CutPrefix(value, "-")
+			if !isExperiment(name) {
+				return nil, fmt.Errorf("%q: unknown experiment %q", d, name)
+			}
+			if disable {
+				f.DisabledExperiments = append(f.DisabledExperiments, name)
+			} else {
+				f.EnabledExperiments = append(f.EnabledExperiments, name)
+			}
+		case "renderer":
+			if !renderers[value] {
+				return nil, fmt.Errorf("%q: unknown renderer %q", d, value)
+			}
+			f.Renderer = value
+		case "timing":
+			if value != "" {
+				return nil, fmt.Errorf("%q: timing takes no value", d)
+			}
+			f.ShowTiming = true
+		case "":
+			// Allow empty directives, as from a trailing comma.
+		default:
+			return nil, fmt.Errorf("%q: unknown directive", d)
+		}
+	}
+	return f, nil
+}
+
+// SkipCache reports whether responses to the request must neither be served
+// from nor stored in a cache. That is so with the nocache directive, and with
+// any directive that changes the response, since the cache key is only the
+// URL.
+func (f *Flags) SkipCache() bool {
+	return f.BypassCache || f.ShowTiming || f.Renderer != "" ||
+		len(f.EnabledExperiments) > 0 || len(f.DisabledExperiments) > 0
+}
+
+// String returns the directives for f, separated by commas.
+func (f *Flags) String() string {
+	if f == nil {
+		return ""
+	}
+	var ds []string
+	if f.BypassCache {
+		ds = append(ds, "nocache")
+	}
+	for _, e := range f.EnabledExperiments {
+		ds = append(ds, "experiment:"+e)
+	}
+	for _, e := range f.DisabledExperiments {
+		ds = append(ds, "experiment:-"+e)
+	}
+	if f.Renderer != "" {
+		ds = append(ds, "renderer:"+f.Renderer)
+	}
+	if f.ShowTiming {
+		ds = append(ds, "timing")
+	}
+	return strings.Join(ds, ", ")
+}
+
+type contextKey struct{}
+
+// NewContext returns a context that stores f.
+func NewContext(ctx context.Context, f *Flags) context.Context {
+	return context.WithValue(ctx, contextKey{}, f)
+}
+
+// FromContext returns the Flags stored in ctx, or the zero Flags if there
+// are none.
+func FromContext(ctx context.Context) *Flags {
+	if f, ok := ctx.Value(contextKey{}).(*Flags); ok {
+		return f
+	}
+	return &Flags{}
+}
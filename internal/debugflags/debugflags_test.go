@@ -0,0 +1,58 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package debugflags
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParse(t *testing.T) {
+	isExperiment := func(name string) bool { return name == "a" || name == "b" }
+	for _, test := range []struct {
+		directives []string
+		want       *Flags
+		wantString string
+	}{
+		{nil, &Flags{}, ""},
+		{[]string{"nocache"}, &Flags{BypassCache: true}, "nocache"},
+		{[]string{"timing", "nocache"}, &Flags{BypassCache: true, ShowTiming: true}, "nocache, timing"},
+		{
+			[]string{" experiment:a", "experiment:-b ", "renderer:reload", ""},
+			&Flags{EnabledExperiments: []string{"a"}, DisabledExperiments: []string{"b"}, Renderer: RendererReload},
+			"experiment:a, experiment:-b, renderer:reload",
+		},
+	} {
+		got, err := Parse(test.directives, isExperiment)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", test.directives, err)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("Parse(%q) mismatch (-want +got):\n%s", test.directives, diff)
+		}
+		if s := got.String(); s != test.wantString {
+			t.Errorf("Parse(%q).String() = %q, want %q", test.directives, s, test.wantString)
+		}
+	}
+
+	for _, d := range []string{"nocache:yes", "experiment:c", "experiment:", "renderer:fancy", "timing:on", "bogus"} {
+		if _, err := Parse([]string{d}, isExperiment); err == nil {
+			t.Errorf("Parse(%q): got nil, want error", d)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	if got := FromContext(ctx); got.BypassCache || got.Renderer != "" {
+		t.Errorf("FromContext(empty) = %+v, want zero Flags", got)
+	}
+	f := &Flags{BypassCache: true}
+	if got := FromContext(NewContext(ctx, f)); got != f {
+		t.Errorf("FromContext = %+v, want %+v", got, f)
+	}
+}
@@ -58,6 +58,10 @@ var (
 	// shouldn't be reprocessed.
 	Cleaned = errors.New("cleaned")
 
+	// DuplicateFetch indicates that the module version was not fetched
+	// because another fetch of it is in progress.
+	DuplicateFetch = errors.New("duplicate fetch")
+
 	// Unknown indicates that the error has unknown semantics.
 	Unknown = errors.New("unknown")
 
@@ -128,6 +132,7 @@ var codes = []struct {
 
 	// Since the following aren't HTTP statuses, pick unused codes.
 	{HasIncompletePackages, 290},
+	{DuplicateFetch, 291},
 	{DBModuleInsertInvalid, 480},
 	{NotFetched, 481},
 	{BadModule, 490},
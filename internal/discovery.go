@@ -49,8 +49,24 @@ type ModuleInfo struct {
 	CommitTime        time.Time
 	IsRedistributable bool
 	// HasGoMod describes whether the module zip has a go.mod file.
-	HasGoMod   bool
+	HasGoMod bool
+	// GoVersion is the version in the go directive of the go.mod file, such
+	// as "1.21", or empty if there is none.
+	GoVersion  string
 	SourceInfo *source.Info
+	// AuthorMetadata holds the links and badges declared by the module's
+	// authors in a pkgsite.yaml file, or nil if there is none.
+	AuthorMetadata *AuthorMetadata
+	// Checksum records where the module version came from and whether its
+	// checksums match those of the checksum database, or is nil if that is
+	// unknown.
+	Checksum *ModuleChecksum
+	// ContentWarnings are the files of the module version that break the
+	// content policy of the worker.
+	ContentWarnings []*ContentWarning
+	// Commit describes the commit that a pseudo-version refers to, as
+	// reported by the repository's host, or is nil if that is unknown.
+	Commit *source.CommitInfo
 
 	// Deprecated describes whether the module is deprecated.
 	Deprecated bool
@@ -62,6 +78,112 @@ type ModuleInfo struct {
 	RetractionRationale string
 }
 
+// ModuleChecksum holds the provenance of a module version and the result of
+// checking it against the checksum database.
+type ModuleChecksum struct {
+	// OriginHash is the commit hash that the module proxy reports the
+	// version was created from, if any.
+	OriginHash string `json:",omitempty"`
+	// ZipHash and GoModHash are the hashes of the module zip and the go.mod
+	// file, in the form used in go.sum files, such as "h1:...".
+	ZipHash   string `json:",omitempty"`
+	GoModHash string `json:",omitempty"`
+	Status    ChecksumStatus
+}
+
+// ChecksumStatus is the result of checking a module version against the
+// checksum database.
+type ChecksumStatus string
+
+const (
+	// ChecksumVerified means that the hashes match those in the checksum
+	// database.
+	ChecksumVerified ChecksumStatus = "verified"
+	// ChecksumMismatch means that the checksum database has different
+	// hashes for the module version.
+	ChecksumMismatch ChecksumStatus = "mismatch"
+	// ChecksumNotFound means that the checksum database doesn't have the
+	// module version, as for private modules.
+	ChecksumNotFound ChecksumStatus = "not_found"
+	// ChecksumUnchecked means that the module version was not checked, for
+	// example because the checksum database was unavailable.
+	ChecksumUnchecked ChecksumStatus = "unchecked"
+)
+
+// Verified reports whether the module version matches the checksum database.
+// It returns false if c is nil.
+func (c *ModuleChecksum) Verified() bool {
+	return c != nil && c.Status == ChecksumVerified
+}
+
+// A ContentWarning describes a file of a module version that breaks a
+// content policy. It does not hold the content that breaks the policy, which
+// may be a secret.
+type ContentWarning struct {
+	// File is the path of the file in the module.
+	File string
+	// Rule is the rule of the policy that the file breaks: one of the
+	// ContentRule constants, or the name of a secret pattern.
+	Rule string
+	// Line is the line of the file that matches a secret pattern, or zero
+	// for the other rules.
+	Line int `json:",omitempty"`
+}
+
+const (
+	// ContentRuleMaxFileSize is the rule broken by files that are too large.
+	ContentRuleMaxFileSize = "max-file-size"
+	// ContentRuleExecutable is the rule broken by executables and object
+	// files.
+	ContentRuleExecutable = "executable"
+)
+
+// AuthorMetadata holds the links and badges that the authors of a module
+// declare in a pkgsite.yaml file at the root of the module, to be shown in
+// the sidebar of the module's pages, and the rendering options they choose.
+type AuthorMetadata struct {
+	Links  []*AuthorLink  `json:",omitempty"`
+	Badges []*AuthorBadge `json:",omitempty"`
+	// NoIssueLinks turns off linking references to issues, such as "#1234",
+	// in the module's README and documentation.
+	NoIssueLinks bool `json:",omitempty"`
+	// DocsPages are patterns, in the syntax of path.Match, that select the
+	// Markdown files under the docs directory of a unit to show in its Docs
+	// tab, by their path relative to that directory. If there are none, all
+	// of them are shown.
+	DocsPages []string `json:",omitempty"`
+	// NoDocsPages turns off the Docs tab.
+	NoDocsPages bool `json:",omitempty"`
+}
+
+// IssueLinksDisabled reports whether the authors of a module have turned off
+// linking references to issues. It returns false if md is nil.
+func (md *AuthorMetadata) IssueLinksDisabled() bool {
+	return md != nil && md.NoIssueLinks
+}
+
+// DocsPagesDisabled reports whether the authors of a module have turned off
+// the Docs tab. It returns false if md is nil.
+func (md *AuthorMetadata) DocsPagesDisabled() bool {
+	return md != nil && md.NoDocsPages
+}
+
+// AuthorLink is a link declared by the authors of a module, such as to its
+// documentation, chat or security policy.
+type AuthorLink struct {
+	Title string
+	URL   string
+}
+
+// AuthorBadge is a status badge declared by the authors of a module.
+type AuthorBadge struct {
+	// Title is the alternative text for the badge image.
+	Title    string
+	ImageURL string
+	// URL is the target of the badge, if any.
+	URL string `json:",omitempty"`
+}
+
 // VersionMap holds metadata associated with module queries for a version.
 type VersionMap struct {
 	ModulePath       string
@@ -153,6 +275,47 @@ type Module struct {
 	// that may be contained in nested subdirectories.
 	Licenses []*licenses.License
 	Units    []*Unit
+	// Requirements holds the requirements in the module's go.mod file.
+	Requirements []*ModuleRequirement
+	// ReleaseNoteSections holds the sections about packages in the release
+	// notes of Go releases found in the standard library. It is empty for
+	// other modules.
+	ReleaseNoteSections []*stdlib.ReleaseNoteSection
+}
+
+// ModuleRequirement is a requirement in the go.mod file of a module version.
+type ModuleRequirement struct {
+	ModulePath string
+	Version    string
+	// Indirect reports whether the requirement is marked "// indirect".
+	Indirect bool
+}
+
+// ModuleDependencies describes a module version and the modules its go.mod
+// file requires, for generating software bills of materials.
+type ModuleDependencies struct {
+	ModulePath string
+	Version    string
+	// LicenseTypes are the types of the module version's top-level licenses.
+	LicenseTypes []string
+	Requirements []*RequiredModule
+}
+
+// RequiredModule is a module version required by another one.
+type RequiredModule struct {
+	ModuleRequirement
+	// LicenseTypes are the types of the required module version's top-level
+	// licenses. It is empty if they are unknown, for example because the
+	// module version has not been processed.
+	LicenseTypes []string
+}
+
+// ImportedByCountAt is the number of packages that imported a package in the
+// week that starts on Week, a Monday, as of the weekly snapshot of
+// imported-by counts.
+type ImportedByCountAt struct {
+	Week  time.Time
+	Count int
 }
 
 // Packages returns all of the units for a module that are packages.
@@ -206,6 +369,15 @@ type ModuleVersionState struct {
 	// NextProcessedAfter is the next time a fetch for this version should be
 	// attempted.
 	NextProcessedAfter time.Time
+	// RetryClass is the class of the result of the last fetch that decides
+	// how it is retried, or empty if it was not classified.
+	RetryClass string
+	// RetryAttempts is the number of consecutive fetches that failed with a
+	// retryable error.
+	RetryAttempts int
+	// MaxRetryAttempts is the number of consecutive retryable failures after
+	// which the version is no longer fetched, or zero for no limit.
+	MaxRetryAttempts int
 
 	// AppVersion is the value of the GAE_VERSION environment variable, which is
 	// set by app engine. It is a timestamp in the format 20190709t112655 that
@@ -232,6 +404,21 @@ type PackageVersionState struct {
 	Version     string
 	Status      int
 	Error       string
+	// Diagnostics describe the problems with the package's files, if the
+	// package failed to load because of them.
+	Diagnostics []*PackageDiagnostic
+}
+
+// A PackageDiagnostic is a problem with a file of a package that prevented
+// the package from being loaded, such as a syntax error.
+type PackageDiagnostic struct {
+	// File is the path of the file relative to the module root. It is empty
+	// if the problem is not with a particular file.
+	File string `json:"file,omitempty"`
+	// Line and Column are 1-based, and zero if unknown.
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
 }
 
 // A Modver holds a module path and version.
@@ -0,0 +1,199 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package docfeedback files reports of problems with the documentation shown
+// for a package, as GitHub issues or as email.
+package docfeedback
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"strings"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// A Report is a report of a problem with the documentation of a unit, with
+// what is needed to reproduce how it was rendered.
+type Report struct {
+	ModulePath string
+	Version    string
+	Path       string // of the unit
+	GOOS       string // of the build context of the documentation
+	GOARCH     string
+	// AppVersion and Toolchain are the app version of the worker and the Go
+	// toolchain that processed the documentation, if they were recorded.
+	AppVersion string
+	Toolchain  string
+	// URL is the URL of the page the report is about.
+	URL string
+	// Description is what the reporter says is wrong.
+	Description string
+}
+
+// MaxDescriptionLen is the maximum length of the description of a report,
+// in bytes.
+const MaxDescriptionLen = 4000
+
+// Title returns the title of the issue for the report.
+func (r *Report) Title() string {
+	return fmt.Sprintf("pkgsite: documentation of %s@%s", r.Path, r.Version)
+}
+
+// Body returns the body of the issue for the report, in Markdown.
+func (r *Report) Body() string {
+	var b strings.Builder
+	b.WriteString(r.Description)
+	b.WriteString("\n\n---\n\n")
+	field := func(name, value string) {
+		if value == "" {
+			value = "unknown"
+		}
+		fmt.Fprintf(&b, "- %s: `%s`\n", name, value)
+	}
+	field("Page", r.URL)
+	field("Module", r.ModulePath)
+	field("Version", r.Version)
+	field("Package", r.Path)
+	field("Build context", r.GOOS+"/"+r.GOARCH)
+	field("Processed by app version", r.AppVersion)
+	field("Processed with", r.Toolchain)
+	return b.String()
+}
+
+// A Filer files reports.
+type Filer interface {
+	// File files the report, and returns the URL of what it filed, or
+	// the empty string if that has no URL.
+	File(ctx context.Context, r *Report) (string, error)
+}
+
+// Options configure a Filer returned by New.
+type Options struct {
+	// GitHubToken authenticates requests to the GitHub API.
+	GitHubToken string
+	// HTTPClient is used for requests to the GitHub API. If nil,
+	// http.DefaultClient is used.
+	HTTPClient *http.Client
+	// SMTPAddr is the address of the mail server that sends email, as
+	// host:port. The server must accept mail from this host without
+	// authentication.
+	SMTPAddr string
+	// From is the sender of email.
+	From string
+}
+
+// New returns a Filer for the target, which is either a GitHub repository,
+// as "github.com/owner/repo", to file issues in, or an email address, as
+// "mailto:address", to send reports to.
+func New(target string, opts Options) (_ Filer, err error) {
+	defer derrors.Wrap(&err, "docfeedback.New(%q)", target)
+
+	if addr, ok := strings.CutPrefix(target, "mailto:"); ok {
+		if _, err := mail.ParseAddress(addr); err != nil {
+			return nil, err
+		}
+		if opts.SMTPAddr == "" || opts.From == "" {
+			return nil, errors.New("sending email needs a mail server and a sender")
+		}
+		return &emailFiler{addr: opts.SMTPAddr, from: opts.From, to: addr, send: smtp.SendMail}, nil
+	}
+	if repo, ok := strings.CutPrefix(target, "github.com/"); ok {
+		owner, name, _ := strings.Cut(repo, "/")
+		if owner == "" || name == "" || strings.Contains(name, "/") {
+			return nil, errors.New("want github.com/owner/repo")
+		}
+		if opts.GitHubToken == "" {
+			return nil, errors.New("filing GitHub issues needs a token")
+		}
+		client := opts.HTTPClient
+		if client == nil {
+			client = http.DefaultClient
+		}
+		return &gitHubFiler{
+			client: client,
+			apiURL: "https://api.github.com",
+			repo:   repo,
+			token:  opts.GitHubToken,
+		}, nil
+	}
+	return nil, errors.New("want github.com/owner/repo or mailto:address")
+}
+
+// gitHubFiler files reports as issues in a GitHub repository.
+type gitHubFiler struct {
+	client *http.Client
+	apiURL string // for testing
+	repo   string // owner/name
+	token  string
+}
+
+func (f *gitHubFiler) File(ctx context.Context, r *Report) (_ string, err error) {
+	defer derrors.Wrap(&err, "gitHubFiler.File(%q)", r.Path)
+
+	body, err := json.Marshal(map[string]string{"title": r.Title(), "body": r.Body()})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		fmt.Sprintf("%s/repos/%s/issues", f.apiURL, f.repo), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+f.token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+	var issue struct {
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.Unmarshal(data, &issue); err != nil {
+		return "", err
+	}
+	return issue.HTMLURL, nil
+}
+
+// emailFiler sends reports as email.
+type emailFiler struct {
+	addr, from, to string
+	send           func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func (f *emailFiler) File(ctx context.Context, r *Report) (_ string, err error) {
+	defer derrors.Wrap(&err, "emailFiler.File(%q)", r.Path)
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", f.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", f.to)
+	// The title holds the path and version from the request, so encode it
+	// to keep it on one header line.
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", r.Title()))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(r.Body(), "\n", "\r\n"))
+	if err := f.send(f.addr, nil, f.from, []string{f.to}, msg.Bytes()); err != nil {
+		return "", err
+	}
+	return "", nil
+}
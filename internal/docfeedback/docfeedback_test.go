@@ -0,0 +1,119 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docfeedback
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+)
+
+var report = &Report{
+	ModulePath:  "example.com/mod",
+	Version:     "v1.2.3",
+	Path:        "example.com/mod/pkg",
+	GOOS:        "linux",
+	GOARCH:      "amd64",
+	AppVersion:  "20260101t000000",
+	Toolchain:   "go1.26.0",
+	URL:         "https://pkg.go.dev/example.com/mod/pkg@v1.2.3",
+	Description: "The example for Foo is missing.",
+}
+
+func TestNew(t *testing.T) {
+	opts := Options{GitHubToken: "tok", SMTPAddr: "localhost:25", From: "pkgsite@example.com"}
+	for _, target := range []string{"github.com/golang/go", "mailto:docs@example.com"} {
+		if _, err := New(target, opts); err != nil {
+			t.Errorf("New(%q): %v", target, err)
+		}
+	}
+	for _, target := range []string{
+		"",
+		"github.com/golang",
+		"github.com/golang/go/issues",
+		"mailto:not an address",
+		"https://example.com",
+	} {
+		if _, err := New(target, opts); err == nil {
+			t.Errorf("New(%q): got nil error", target)
+		}
+	}
+	if _, err := New("github.com/golang/go", Options{}); err == nil {
+		t.Error("New without a GitHub token: got nil error")
+	}
+	if _, err := New("mailto:docs@example.com", Options{}); err == nil {
+		t.Error("New without a mail server: got nil error")
+	}
+}
+
+func TestGitHubFiler(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/docs/issues" || r.Header.Get("Authorization") != "Bearer tok" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		io.WriteString(w, `{"html_url": "https://github.com/owner/docs/issues/7"}`)
+	}))
+	defer srv.Close()
+
+	f, err := New("github.com/owner/docs", Options{GitHubToken: "tok", HTTPClient: srv.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.(*gitHubFiler).apiURL = srv.URL
+	url, err := f.File(context.Background(), report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://github.com/owner/docs/issues/7"; url != want {
+		t.Errorf("got URL %q, want %q", url, want)
+	}
+	if got["title"] != report.Title() {
+		t.Errorf("got title %q, want %q", got["title"], report.Title())
+	}
+	for _, want := range []string{report.Description, "`linux/amd64`", "`20260101t000000`", "`go1.26.0`"} {
+		if !strings.Contains(got["body"], want) {
+			t.Errorf("body does not contain %q:\n%s", want, got["body"])
+		}
+	}
+}
+
+func TestEmailFiler(t *testing.T) {
+	f, err := New("mailto:docs@example.com", Options{SMTPAddr: "mail:25", From: "pkgsite@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg string
+	f.(*emailFiler).send = func(addr string, _ smtp.Auth, from string, to []string, m []byte) error {
+		if addr != "mail:25" || from != "pkgsite@example.com" || len(to) != 1 || to[0] != "docs@example.com" {
+			t.Errorf("send(%q, %q, %q)", addr, from, to)
+		}
+		msg = string(m)
+		return nil
+	}
+	r := *report
+	r.Path = "example.com/mod/pkg\r\nBcc: someone@example.com"
+	if _, err := f.File(context.Background(), &r); err != nil {
+		t.Fatal(err)
+	}
+	header, body, _ := strings.Cut(msg, "\r\n\r\n")
+	if strings.Contains(header, "\r\nBcc:") {
+		t.Errorf("path was not encoded in the header:\n%s", header)
+	}
+	if !strings.Contains(body, r.Description) {
+		t.Errorf("body does not contain the description:\n%s", body)
+	}
+}
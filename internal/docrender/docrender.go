@@ -0,0 +1,225 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package docrender defines an API for rendering package documentation and
+// READMEs to HTML.
+//
+// Rendering is the most CPU-intensive part of serving a unit page. By default
+// the frontend renders in process, but the API can also be served over gRPC
+// (see package remote) so that rendering can be done by a separate service
+// that is scaled independently of the frontend.
+package docrender
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/source"
+)
+
+// A Renderer renders documentation.
+type Renderer interface {
+	ReadmeRenderer
+
+	// RenderUnit renders the documentation of a package.
+	RenderUnit(context.Context, *UnitRequest) (*UnitResponse, error)
+
+	// Outline returns only the outline of a package's documentation.
+	Outline(context.Context, *UnitRequest) (*OutlineResponse, error)
+}
+
+// A ReadmeRenderer renders READMEs and other Markdown files. Package readme
+// implements it in process.
+type ReadmeRenderer interface {
+	// RenderReadme renders a README file.
+	RenderReadme(context.Context, *ReadmeRequest) (*ReadmeResponse, error)
+
+	// RenderDocsPage renders a docs page of a unit.
+	RenderDocsPage(context.Context, *DocsPageRequest) (*ReadmeResponse, error)
+}
+
+// UnitRequest describes the documentation of a package to render.
+type UnitRequest struct {
+	Path       string
+	ModulePath string
+	Version    string
+	// GoVersion is the version in the go directive of the module's go.mod
+	// file, if any.
+	GoVersion  string
+	SourceInfo *source.Info
+
+	// Source is the encoded documentation of the package; see
+	// godoc.Package.Encode. SourceHash is its hash, if known.
+	Source     []byte
+	SourceHash string
+
+	// SymbolHistory maps the name of each symbol to the version at which it
+	// was introduced.
+	SymbolHistory map[string]string
+	BuildContext  internal.BuildContext
+	// NoIssueLinks turns off linking references to issues, such as "#1234",
+	// in doc comments.
+	NoIssueLinks bool
+	// LicenseTypes are the types of the licenses that apply to the package.
+	// Declarations in files that declare other licenses are annotated.
+	LicenseTypes []string
+	// BrokenDocLinks are the doc links of the package that resolve to
+	// nothing. They are rendered as plain text.
+	BrokenDocLinks []*internal.BrokenDocLink
+	// ExpandExamples renders the examples expanded, for browsers that
+	// cannot expand them with JavaScript.
+	ExpandExamples bool
+}
+
+// UnitResponse holds the rendered documentation of a package. The HTML
+// fields have been sanitized by the renderer.
+type UnitResponse struct {
+	Body          string
+	Outline       string
+	MobileOutline string
+	// Links are from the "Links" section of the package documentation.
+	Links []*Link
+	// Files are the files of the package, including test files.
+	Files []*File
+}
+
+// OutlineResponse holds the rendered outline of a package's documentation.
+type OutlineResponse struct {
+	Outline       string
+	MobileOutline string
+}
+
+// ReadmeRequest describes a README to render.
+type ReadmeRequest struct {
+	Readme *internal.Readme
+	// SourceInfo is used to resolve relative links and images, and to link
+	// references to issues.
+	SourceInfo *source.Info
+	// NoIssueLinks turns off linking references to issues, such as "#1234".
+	NoIssueLinks bool
+}
+
+// ReadmeRenderVersion identifies how READMEs are rendered. Increment it when
+// a change to README processing changes the rendered HTML, so that READMEs
+// rendered and stored before the change are not served.
+const ReadmeRenderVersion = 1
+
+// ReadmeHash returns a hash that identifies the rendering of req. Requests
+// with the same hash render to the same ReadmeResponse, so a rendered README
+// can be stored under its hash when a module is processed, and looked up
+// instead of rendered when it is served.
+func ReadmeHash(req *ReadmeRequest) (_ string, err error) {
+	defer derrors.Wrap(&err, "ReadmeHash")
+	return hash("", req)
+}
+
+// hash returns the hash of a request of the given kind.
+func hash(kind string, req any) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if kind != "" {
+		fmt.Fprintf(h, "%s ", kind)
+	}
+	fmt.Fprintf(h, "%d\n", ReadmeRenderVersion)
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// DocsPageRequest describes a docs page to render: a Markdown file under the
+// docs directory of a unit, shown on the Docs tab of the unit page. It is
+// rendered like a README, except that relative links to the other docs pages
+// of the unit are rewritten to stay on the tab.
+type DocsPageRequest struct {
+	ReadmeRequest
+	// Pages maps the file paths of the docs pages of the unit to their URLs;
+	// see DocsPageURLs.
+	Pages map[string]string
+}
+
+// DocsPageHash is like ReadmeHash, for a docs page.
+func DocsPageHash(req *DocsPageRequest) (_ string, err error) {
+	defer derrors.Wrap(&err, "DocsPageHash")
+	return hash("docs page", req)
+}
+
+// DocsPageParam is the query parameter of the Docs tab that selects a page by
+// its name.
+const DocsPageParam = "page"
+
+// DocsPageName returns the name of the docs page at filePath of the unit at
+// unitPath in the module at modulePath: its path relative to the docs
+// directory of the unit.
+func DocsPageName(unitPath, modulePath, filePath string) string {
+	dir := path.Join(internal.Suffix(unitPath, modulePath), "docs") + "/"
+	return strings.TrimPrefix(filePath, dir)
+}
+
+// DocsPageURL returns the URL of the docs page with the given name, relative
+// to the page of its unit.
+func DocsPageURL(name string) string {
+	return "?tab=docs&" + DocsPageParam + "=" + url.QueryEscape(name)
+}
+
+// DocsPageURLs returns the URLs of the docs pages at filePaths of the unit at
+// unitPath in the module at modulePath, keyed by file path.
+func DocsPageURLs(unitPath, modulePath string, filePaths []string) map[string]string {
+	urls := map[string]string{}
+	for _, fp := range filePaths {
+		urls[fp] = DocsPageURL(DocsPageName(unitPath, modulePath, fp))
+	}
+	return urls
+}
+
+// ReadmeResponse holds a rendered README.
+type ReadmeResponse struct {
+	// HTML is the sanitized, rendered README.
+	HTML string
+	// Outline holds the headings of the README.
+	Outline []*Heading
+	// Links are from the "Links" section of the README.
+	Links []*Link
+	// QuickStart holds instructions extracted from the README, or nil.
+	QuickStart *QuickStart
+}
+
+// A Link is a link with a title.
+type Link struct {
+	Href string
+	Text string
+}
+
+// A File is a source file of a package.
+type File struct {
+	Name string
+	// BuildConstraint is the file's build constraint, if any.
+	BuildConstraint string `json:",omitempty"`
+	// Licenses are the license types declared in the file's header, if any.
+	Licenses []string `json:",omitempty"`
+}
+
+// A Heading is a heading in a README, with the headings nested within it.
+type Heading struct {
+	Level    int
+	Text     string
+	ID       string
+	Children []*Heading `json:",omitempty"`
+}
+
+// QuickStart holds instructions for getting started with a module, extracted
+// from its README.
+type QuickStart struct {
+	Command string
+	Code    string
+}
@@ -0,0 +1,31 @@
+// Copyright 2019 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package readme
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestTrimmedEscapedPath(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"a.png", "a.png"},
+		{" a.png   ", "a.png"},
+		{"a b.png", "a%20b.png"},
+		{" a b.png ", "a%20b.png"},
+		{".a/b.gif", ".a/b.gif"},
+	} {
+		u, err := url.Parse(test.in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := trimmedEscapedPath(u)
+		if got != test.want {
+			t.Errorf("escapePath(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
@@ -2,26 +2,28 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-package frontend
+package readme
 
 import (
 	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"path"
 	"strings"
 
 	"github.com/google/safehtml/template"
 	"golang.org/x/pkgsite/internal"
-	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docrender"
 	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/sanitizer"
 	"golang.org/x/pkgsite/internal/source"
 	"rsc.io/markdown"
 )
 
-// ProcessReadme processes the README of unit u, if it has one.
-// Processing includes rendering and sanitizing the HTML or Markdown,
-// and extracting headings and links.
+// Render renders the README described by req. Processing includes rendering
+// and sanitizing the HTML or Markdown, and extracting headings and links.
 //
 // Headings are prefixed with "readme-" and heading levels are adjusted to start
 // at h3 in order to nest them properly within the rest of the page. The
@@ -30,15 +32,12 @@ import (
 //
 // The extracted links are for display outside of the readme contents.
 //
-// This function is exported for use by external tools.
-func ProcessReadme(ctx context.Context, u *internal.Unit) (_ *Readme, err error) {
-	defer derrors.WrapAndReport(&err, "ProcessReadme(%q, %q, %q)", u.Path, u.ModulePath, u.Version)
-	return processReadme(ctx, u.Readme, u.SourceInfo)
-}
-
-func processReadme(ctx context.Context, readme *internal.Readme, info *source.Info) (frontendReadme *Readme, err error) {
+// Relative links to the files in docsPages, a map from file path to URL, are
+// rewritten to those URLs instead of pointing to the repository.
+func Render(ctx context.Context, req *docrender.ReadmeRequest, docsPages map[string]string) (_ *docrender.ReadmeResponse, err error) {
+	readme, info := req.Readme, req.SourceInfo
 	if readme == nil || readme.Contents == "" {
-		return &Readme{}, nil
+		return &docrender.ReadmeResponse{}, nil
 	}
 	if !isMarkdown(readme.Filepath) {
 		t := template.Must(template.New("").Parse(`<pre class="readme">{{.}}</pre>`))
@@ -46,7 +45,7 @@ func processReadme(ctx context.Context, readme *internal.Readme, info *source.In
 		if err != nil {
 			return nil, err
 		}
-		return &Readme{HTML: h}, nil
+		return &docrender.ReadmeResponse{HTML: h.String()}, nil
 	}
 
 	p := markdown.Parser{
@@ -58,23 +57,76 @@ func processReadme(ctx context.Context, readme *internal.Readme, info *source.In
 		Emoji:         true,
 	}
 	doc := p.Parse(readme.Contents)
-	(&linkRewriter{info, readme}).rewriteLinks(doc)
+	qs := extractQuickStart(doc)
+	(&linkRewriter{info, readme, docsPages}).rewriteLinks(doc)
 	rewriteImgSrc(doc, info, readme)
 	rewriteHeadingIDs(doc) // rewrite heading ids before extractTOC extracts them
 	et := &extractTOC{ctx: ctx, removeTitle: true}
 	et.extract(doc)
 	el := &extractLinks{ctx: ctx}
 	el.extract(doc)
+	if !req.NoIssueLinks {
+		linkIssues(doc, info)
+	}
 	transformHeadingsToHTML(doc)
 	var buf bytes.Buffer
 	doc.PrintHTML(&buf)
-	return &Readme{
-		HTML:    sanitizeHTML(&buf),
-		Outline: et.Headings,
-		Links:   el.links,
+	return &docrender.ReadmeResponse{
+		HTML:       string(sanitizer.SanitizeBytes(buf.Bytes())),
+		Outline:    et.Headings,
+		Links:      el.links,
+		QuickStart: qs,
 	}, nil
 }
 
+// maxQuickStartLines is the maximum number of lines of code in a QuickStart.
+const maxQuickStartLines = 20
+
+// extractQuickStart returns the quick start instructions in the code blocks
+// of a README, or nil if there are none.
+func extractQuickStart(doc *markdown.Document) *docrender.QuickStart {
+	var qs docrender.QuickStart
+	walkBlocks(doc.Blocks, func(b markdown.Block) error {
+		cb, ok := b.(*markdown.CodeBlock)
+		if !ok {
+			return nil
+		}
+		lang, _, _ := strings.Cut(strings.TrimSpace(cb.Info), " ")
+		switch strings.ToLower(lang) {
+		case "go", "golang":
+			if qs.Code == "" {
+				lines := cb.Text
+				if len(lines) > maxQuickStartLines {
+					lines = append(lines[:maxQuickStartLines:maxQuickStartLines], "// ...")
+				}
+				qs.Code = strings.TrimSpace(strings.Join(lines, "\n"))
+			}
+		default:
+			if qs.Command == "" {
+				qs.Command = goCommand(cb.Text)
+			}
+		}
+		return nil
+	})
+	if qs.Command == "" && qs.Code == "" {
+		return nil
+	}
+	return &qs
+}
+
+// goCommand returns the first line of lines that runs "go get" or
+// "go install", with any shell prompt removed.
+func goCommand(lines []string) string {
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		line = strings.TrimSpace(strings.TrimLeft(line, "$>%"))
+		if strings.HasPrefix(line, "go get ") || strings.HasPrefix(line, "go install ") {
+			return line
+		}
+	}
+	return ""
+}
+
 // rewriteImgSrc rewrites the HTML in the markdown document to replace img
 // src keys with a value that properly represents the source of the image
 // from the repo.
@@ -162,7 +214,7 @@ func walkBlocks(blocks []markdown.Block, walkFunc func(b markdown.Block) error)
 
 type extractTOC struct {
 	ctx         context.Context
-	Headings    []*Heading
+	Headings    []*docrender.Heading
 	removeTitle bool // omit title from TOC
 }
 
@@ -170,14 +222,14 @@ type extractTOC struct {
 // of the document. It nests the headings based on the h-level hierarchy.
 // See tests for heading levels in TestReadme for behavior.
 func (e *extractTOC) extract(doc *markdown.Document) {
-	var headings []*Heading
+	var headings []*docrender.Heading
 	err := walkBlocks(doc.Blocks, func(b markdown.Block) error {
 		if heading, ok := b.(*markdown.Heading); ok {
 			var textbuf bytes.Buffer
 			for _, t := range heading.Text.Inline {
 				t.PrintText(&textbuf)
 			}
-			section := &Heading{
+			section := &docrender.Heading{
 				Level: heading.Level,
 				Text:  textbuf.String(),
 			}
@@ -193,22 +245,19 @@ func (e *extractTOC) extract(doc *markdown.Document) {
 
 	// We nest the headings by walking through the list we extracted and
 	// establishing parent child relationships based on heading levels.
-	var nested []*Heading
-	for i, h := range headings {
-		if i == 0 {
-			nested = append(nested, h)
-			continue
-		}
-		parent := headings[i-1]
-		for parent != nil && parent.Level >= h.Level {
-			parent = parent.parent
+	// parents holds the headings that the current one may be nested within.
+	var nested, parents []*docrender.Heading
+	for _, h := range headings {
+		for len(parents) > 0 && parents[len(parents)-1].Level >= h.Level {
+			parents = parents[:len(parents)-1]
 		}
-		if parent == nil {
+		if len(parents) == 0 {
 			nested = append(nested, h)
 		} else {
-			h.parent = parent
+			parent := parents[len(parents)-1]
 			parent.Children = append(parent.Children, h)
 		}
+		parents = append(parents, h)
 	}
 	if e.removeTitle {
 		// If there is only one top tevel heading with 1 or more children we
@@ -223,7 +272,7 @@ func (e *extractTOC) extract(doc *markdown.Document) {
 type extractLinks struct {
 	ctx            context.Context
 	inLinksHeading bool
-	links          []link
+	links          []*docrender.Link
 }
 
 // The name of the heading from which we extract links.
@@ -270,9 +319,9 @@ func (e *extractLinks) extract(doc *markdown.Document) {
 					for _, t := range l.Inner {
 						t.PrintText(&linkText)
 					}
-					e.links = append(e.links, link{
+					e.links = append(e.links, &docrender.Link{
 						Href: l.URL,
-						Body: linkText.String(),
+						Text: linkText.String(),
 					})
 				}
 			}
@@ -288,8 +337,9 @@ func (e *extractLinks) extract(doc *markdown.Document) {
 // linkRewriter rewrites links and image targets in a markdown document
 // using translateLink.
 type linkRewriter struct {
-	info   *source.Info
-	readme *internal.Readme
+	info      *source.Info
+	readme    *internal.Readme
+	docsPages map[string]string // file path to URL
 }
 
 func (g *linkRewriter) rewriteLinks(doc *markdown.Document) {
@@ -306,7 +356,9 @@ func (g *linkRewriter) rewriteLinksInline(inlines []markdown.Inline) {
 		switch x := inl.(type) {
 		case *markdown.Link:
 			g.rewriteLinksInline(x.Inner)
-			if d := translateLink(x.URL, g.info, false, g.readme); d != "" {
+			if d := g.docsPageLink(x.URL); d != "" {
+				x.URL = d
+			} else if d := translateLink(x.URL, g.info, false, g.readme); d != "" {
 				x.URL = d
 			}
 		case *markdown.Image:
@@ -323,6 +375,99 @@ func (g *linkRewriter) rewriteLinksInline(inlines []markdown.Inline) {
 	}
 }
 
+// docsPageLink returns the URL of the docs page that dest, a link in the
+// readme, refers to, or the empty string if it doesn't refer to one.
+func (g *linkRewriter) docsPageLink(dest string) string {
+	if len(g.docsPages) == 0 {
+		return ""
+	}
+	u, err := url.Parse(dest)
+	if err != nil || u.IsAbs() || u.Host != "" || u.Path == "" || strings.HasPrefix(u.Path, "/") {
+		return ""
+	}
+	link, ok := g.docsPages[path.Join(path.Dir(g.readme.Filepath), u.Path)]
+	if !ok {
+		return ""
+	}
+	if u.Fragment != "" {
+		link += "#readme-" + u.Fragment
+	}
+	return link
+}
+
+// linkIssues turns references to issues and pull requests in the text of a
+// markdown document into links to the repository described by info. Headings,
+// code and text that is already a link are left alone.
+func linkIssues(doc *markdown.Document, info *source.Info) {
+	walkBlocks(doc.Blocks, func(b markdown.Block) error {
+		switch x := b.(type) {
+		case *markdown.Heading:
+			return errSkipChildren
+		case *markdown.Text:
+			x.Inline = linkIssuesInline(x.Inline, info)
+		}
+		return nil
+	})
+}
+
+func linkIssuesInline(inlines []markdown.Inline, info *source.Info) []markdown.Inline {
+	var out []markdown.Inline
+	for i := 0; i < len(inlines); i++ {
+		switch x := inlines[i].(type) {
+		case *markdown.Plain:
+			// The parser splits text at characters like "(", so join
+			// adjacent plain text to see the whole of each reference.
+			text := x.Text
+			for i+1 < len(inlines) {
+				p, ok := inlines[i+1].(*markdown.Plain)
+				if !ok {
+					break
+				}
+				text += p.Text
+				i++
+			}
+			out = append(out, linkIssuesInPlain(text, info)...)
+		case *markdown.Emph:
+			x.Inner = linkIssuesInline(x.Inner, info)
+			out = append(out, x)
+		case *markdown.Strong:
+			x.Inner = linkIssuesInline(x.Inner, info)
+			out = append(out, x)
+		case *markdown.Del:
+			x.Inner = linkIssuesInline(x.Inner, info)
+			out = append(out, x)
+		default:
+			out = append(out, x)
+		}
+	}
+	return out
+}
+
+// linkIssuesInPlain splits text into plain text and links to the issues it
+// references.
+func linkIssuesInPlain(text string, info *source.Info) []markdown.Inline {
+	var out []markdown.Inline
+	last := 0
+	for _, ref := range source.FindIssueReferences(text) {
+		url := info.IssueURL(ref.Number)
+		if url == "" {
+			continue
+		}
+		if ref.Start > last {
+			out = append(out, &markdown.Plain{Text: text[last:ref.Start]})
+		}
+		out = append(out, &markdown.Link{
+			Inner: []markdown.Inline{&markdown.Plain{Text: text[ref.Start:ref.End]}},
+			URL:   url,
+		})
+		last = ref.End
+	}
+	if last < len(text) {
+		out = append(out, &markdown.Plain{Text: text[last:]})
+	}
+	return out
+}
+
 // transformHeadingsToHTML replaces heading blocks with rendered html
 // blocks for the heading. It converts heading levels above 6 to divs
 // with the h[level] class set on them.
@@ -0,0 +1,88 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package readme
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/docrender"
+)
+
+func TestRenderQuickStart(t *testing.T) {
+	ctx := context.Background()
+	var longCode []string
+	for i := 0; i < maxQuickStartLines; i++ {
+		longCode = append(longCode, fmt.Sprintf("x := %d", i))
+	}
+	for _, test := range []struct {
+		name     string
+		contents string
+		want     *docrender.QuickStart
+	}{
+		{
+			name:     "no code",
+			contents: "# Heading\nSome stuff.\n",
+			want:     nil,
+		},
+		{
+			name: "command and code",
+			contents: "# Install\n\n" +
+				"```sh\n$ export GOFLAGS=-mod=mod\n$ go get example.com/mod@latest\n```\n\n" +
+				"# Use\n\n" +
+				"```go\nmod.Do()\n```\n\n" +
+				"```go\nmod.Other()\n```\n",
+			want: &docrender.QuickStart{Command: "go get example.com/mod@latest", Code: "mod.Do()"},
+		},
+		{
+			name:     "go install without prompt",
+			contents: "```\ngo install example.com/mod/cmd/tool@latest\n```\n",
+			want:     &docrender.QuickStart{Command: "go install example.com/mod/cmd/tool@latest"},
+		},
+		{
+			name:     "long code",
+			contents: "\n```golang\n" + strings.Join(longCode, "\n") + "\nx := 100\n```\n",
+			want:     &docrender.QuickStart{Code: strings.Join(longCode, "\n") + "\n// ..."},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Render(ctx, &docrender.ReadmeRequest{
+				Readme: &internal.Readme{Filepath: "README.md", Contents: test.contents},
+			}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got.QuickStart); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderOutline(t *testing.T) {
+	ctx := context.Background()
+	got, err := Render(ctx, &docrender.ReadmeRequest{
+		Readme: &internal.Readme{
+			Filepath: "README.md",
+			Contents: "# Title\n\n## A\n\n### A.1\n\n## B\n",
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*docrender.Heading{
+		{Level: 2, Text: "A", ID: "readme-a", Children: []*docrender.Heading{
+			{Level: 3, Text: "A.1", ID: "readme-a-1"},
+		}},
+		{Level: 2, Text: "B", ID: "readme-b"},
+	}
+	if diff := cmp.Diff(want, got.Outline); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package readme renders READMEs and other Markdown files of modules to
+// sanitized HTML. It is used by the frontend's in-process renderer, and by
+// the worker to render READMEs when modules are processed.
+package readme
+
+import (
+	"context"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docrender"
+)
+
+// Renderer is a docrender.ReadmeRenderer that renders in the current process.
+type Renderer struct{}
+
+// RenderReadme renders the README described by req.
+func (Renderer) RenderReadme(ctx context.Context, req *docrender.ReadmeRequest) (_ *docrender.ReadmeResponse, err error) {
+	defer derrors.Wrap(&err, "readme.Renderer.RenderReadme")
+	return Render(ctx, req, nil)
+}
+
+// RenderDocsPage renders the docs page described by req.
+func (Renderer) RenderDocsPage(ctx context.Context, req *docrender.DocsPageRequest) (_ *docrender.ReadmeResponse, err error) {
+	defer derrors.Wrap(&err, "readme.Renderer.RenderDocsPage")
+	return Render(ctx, &req.ReadmeRequest, req.Pages)
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package remote serves the docrender API over gRPC, so that rendering can be
+// done by a separate service.
+//
+// The messages of the gRPC service are the Go types of package docrender,
+// encoded as JSON, so no generated protocol buffer code is needed.
+package remote
+
+import (
+	"context"
+	"encoding/json"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docrender"
+	"google.golang.org/grpc"
+)
+
+const (
+	serviceName = "pkgsite.docrender.Renderer"
+
+	// maxMessageSize is the maximum size of a request or response. Encoded
+	// documentation and rendered HTML can both be large.
+	maxMessageSize = 64 * 1024 * 1024
+)
+
+// jsonCodec is a gRPC codec that encodes messages as JSON.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                       { return "json" }
+
+// NewServer returns a gRPC server that serves r. The caller registers any
+// other services and calls Serve.
+func NewServer(r docrender.Renderer, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{
+		grpc.ForceServerCodec(jsonCodec{}),
+		grpc.MaxRecvMsgSize(maxMessageSize),
+		grpc.MaxSendMsgSize(maxMessageSize),
+	}, opts...)
+	s := grpc.NewServer(opts...)
+	s.RegisterService(&serviceDesc, r)
+	return s
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*docrender.Renderer)(nil),
+	Methods: []grpc.MethodDesc{
+		unaryMethod("RenderUnit", docrender.Renderer.RenderUnit),
+		unaryMethod("RenderReadme", docrender.Renderer.RenderReadme),
+		unaryMethod("RenderDocsPage", docrender.Renderer.RenderDocsPage),
+		unaryMethod("Outline", docrender.Renderer.Outline),
+	},
+}
+
+// unaryMethod returns the description of the unary method name, which is
+// implemented by call.
+func unaryMethod[Req, Resp any](name string, call func(docrender.Renderer, context.Context, *Req) (*Resp, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+			req := new(Req)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			if interceptor == nil {
+				return call(srv.(docrender.Renderer), ctx, req)
+			}
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + serviceName + "/" + name}
+			return interceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+				return call(srv.(docrender.Renderer), ctx, req.(*Req))
+			})
+		},
+	}
+}
+
+// client is a docrender.Renderer that calls a remote render service.
+type client struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewClient returns a docrender.Renderer that sends requests over cc to a
+// server created by NewServer.
+func NewClient(cc grpc.ClientConnInterface) docrender.Renderer {
+	return &client{cc: cc}
+}
+
+func (c *client) RenderUnit(ctx context.Context, req *docrender.UnitRequest) (_ *docrender.UnitResponse, err error) {
+	defer derrors.Wrap(&err, "remote.RenderUnit(%q, %q, %q)", req.Path, req.ModulePath, req.Version)
+	return invoke[docrender.UnitResponse](ctx, c.cc, "RenderUnit", req)
+}
+
+func (c *client) RenderReadme(ctx context.Context, req *docrender.ReadmeRequest) (_ *docrender.ReadmeResponse, err error) {
+	defer derrors.Wrap(&err, "remote.RenderReadme")
+	return invoke[docrender.ReadmeResponse](ctx, c.cc, "RenderReadme", req)
+}
+
+func (c *client) RenderDocsPage(ctx context.Context, req *docrender.DocsPageRequest) (_ *docrender.ReadmeResponse, err error) {
+	defer derrors.Wrap(&err, "remote.RenderDocsPage")
+	return invoke[docrender.ReadmeResponse](ctx, c.cc, "RenderDocsPage", req)
+}
+
+func (c *client) Outline(ctx context.Context, req *docrender.UnitRequest) (_ *docrender.OutlineResponse, err error) {
+	defer derrors.Wrap(&err, "remote.Outline(%q, %q, %q)", req.Path, req.ModulePath, req.Version)
+	return invoke[docrender.OutlineResponse](ctx, c.cc, "Outline", req)
+}
+
+// invoke calls the method name with req and returns its response.
+func invoke[Resp any](ctx context.Context, cc grpc.ClientConnInterface, name string, req any) (*Resp, error) {
+	resp := new(Resp)
+	err := cc.Invoke(ctx, "/"+serviceName+"/"+name, req, resp,
+		grpc.ForceCodec(jsonCodec{}),
+		grpc.MaxCallRecvMsgSize(maxMessageSize),
+		grpc.MaxCallSendMsgSize(maxMessageSize))
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
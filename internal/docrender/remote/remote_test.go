@@ -0,0 +1,130 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/docrender"
+	"golang.org/x/pkgsite/internal/source"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeRenderer echoes parts of its requests.
+type fakeRenderer struct{}
+
+func (fakeRenderer) RenderUnit(_ context.Context, req *docrender.UnitRequest) (*docrender.UnitResponse, error) {
+	if req.Path == "" {
+		return nil, errors.New("missing path")
+	}
+	return &docrender.UnitResponse{
+		Body:    "<p>" + string(req.Source) + "</p>",
+		Outline: req.SourceInfo.FileURL("f.go"),
+		Links:   []*docrender.Link{{Href: "https://example.com", Text: req.SymbolHistory["F"]}},
+	}, nil
+}
+
+func (fakeRenderer) RenderReadme(_ context.Context, req *docrender.ReadmeRequest) (*docrender.ReadmeResponse, error) {
+	return &docrender.ReadmeResponse{
+		HTML:    req.Readme.Contents,
+		Outline: []*docrender.Heading{{Level: 1, Text: "A", ID: "readme-a", Children: []*docrender.Heading{{Level: 2, Text: "B", ID: "readme-b"}}}},
+	}, nil
+}
+
+func (fakeRenderer) RenderDocsPage(_ context.Context, req *docrender.DocsPageRequest) (*docrender.ReadmeResponse, error) {
+	return &docrender.ReadmeResponse{HTML: req.Pages[req.Readme.Filepath]}, nil
+}
+
+func (fakeRenderer) Outline(_ context.Context, req *docrender.UnitRequest) (*docrender.OutlineResponse, error) {
+	return &docrender.OutlineResponse{Outline: req.BuildContext.GOOS}, nil
+}
+
+func newTestClient(t *testing.T) docrender.Renderer {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	s := NewServer(fakeRenderer{})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+	cc, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cc.Close() })
+	return NewClient(cc)
+}
+
+func TestClientServer(t *testing.T) {
+	ctx := context.Background()
+	c := newTestClient(t)
+
+	ureq := &docrender.UnitRequest{
+		Path:          "example.com/m/p",
+		ModulePath:    "example.com/m",
+		Version:       "v1.0.0",
+		SourceInfo:    source.NewGitHubInfo("https://github.com/a/b", "", "v1.0.0"),
+		Source:        []byte("source"),
+		SymbolHistory: map[string]string{"F": "v1.0.0"},
+		BuildContext:  internal.BuildContext{GOOS: "linux", GOARCH: "amd64"},
+	}
+	got, err := c.RenderUnit(ctx, ureq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &docrender.UnitResponse{
+		Body:    "<p>source</p>",
+		Outline: "https://github.com/a/b/blob/v1.0.0/f.go",
+		Links:   []*docrender.Link{{Href: "https://example.com", Text: "v1.0.0"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RenderUnit mismatch (-want, +got):\n%s", diff)
+	}
+
+	gotOutline, err := c.Outline(ctx, ureq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&docrender.OutlineResponse{Outline: "linux"}, gotOutline); diff != "" {
+		t.Errorf("Outline mismatch (-want, +got):\n%s", diff)
+	}
+
+	gotReadme, err := c.RenderReadme(ctx, &docrender.ReadmeRequest{Readme: &internal.Readme{Filepath: "README.md", Contents: "# A"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantReadme := &docrender.ReadmeResponse{
+		HTML:    "# A",
+		Outline: []*docrender.Heading{{Level: 1, Text: "A", ID: "readme-a", Children: []*docrender.Heading{{Level: 2, Text: "B", ID: "readme-b"}}}},
+	}
+	if diff := cmp.Diff(wantReadme, gotReadme, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("RenderReadme mismatch (-want, +got):\n%s", diff)
+	}
+
+	gotPage, err := c.RenderDocsPage(ctx, &docrender.DocsPageRequest{
+		ReadmeRequest: docrender.ReadmeRequest{Readme: &internal.Readme{Filepath: "docs/a.md"}},
+		Pages:         map[string]string{"docs/a.md": "?tab=docs&page=a.md"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&docrender.ReadmeResponse{HTML: "?tab=docs&page=a.md"}, gotPage); diff != "" {
+		t.Errorf("RenderDocsPage mismatch (-want, +got):\n%s", diff)
+	}
+
+	_, err = c.RenderUnit(ctx, &docrender.UnitRequest{})
+	if err == nil || !strings.Contains(err.Error(), "missing path") {
+		t.Errorf("RenderUnit with no path: got error %v, want one containing %q", err, "missing path")
+	}
+}
@@ -7,12 +7,20 @@ package internal
 
 const (
 	ExperimentEnableStdFrontendFetch = "enable-std-frontend-fetch"
+	ExperimentParallelDocRender      = "parallel-doc-render"
+	ExperimentReadmeQuickStart       = "readme-quick-start"
+	ExperimentSearchAutocomplete     = "search-autocomplete"
+	ExperimentVerifyDocEncoding      = "verify-doc-encoding"
 )
 
 // Experiments represents all of the active experiments in the codebase and
 // a description of each experiment.
 var Experiments = map[string]string{
 	ExperimentEnableStdFrontendFetch: "Enable frontend fetching for module std.",
+	ExperimentParallelDocRender:      "Encode package files separately so they can be decoded lazily and in parallel, and render the documentation outline concurrently with the body.",
+	ExperimentReadmeQuickStart:       "Show a quick start card extracted from the README on the unit page.",
+	ExperimentSearchAutocomplete:     "Suggest packages and symbols as the user types in the search box.",
+	ExperimentVerifyDocEncoding:      "Check that a sample of packages decode the same way with the documentation encoding that parallel-doc-render would switch to.",
 }
 
 // Experiment holds data associated with an experimental feature for frontend
@@ -0,0 +1,53 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"fmt"
+	"time"
+)
+
+// A DataFeature is data that the worker computes for modules, and that must
+// be backfilled for the modules processed before it was computed. The
+// frontend hides a feature for modules it has not been backfilled for.
+type DataFeature string
+
+const (
+	// FeatureSymbolHistory is the version that introduced each symbol,
+	// shown in the documentation and on the versions and history tabs.
+	FeatureSymbolHistory DataFeature = "symbol-history"
+
+	// FeatureAPIDiff is the list of symbols of each version of a package,
+	// compared on the diff tab.
+	FeatureAPIDiff DataFeature = "api-diff"
+
+	// FeatureScores are the imported-by counts of packages, which search
+	// uses to score them and the unit header displays.
+	FeatureScores DataFeature = "scores"
+)
+
+// DataFeatures are all the data features.
+var DataFeatures = []DataFeature{FeatureSymbolHistory, FeatureAPIDiff, FeatureScores}
+
+// ParseDataFeature returns the data feature named s.
+func ParseDataFeature(s string) (DataFeature, error) {
+	for _, f := range DataFeatures {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown data feature %q", s)
+}
+
+// FeatureReadiness describes the backfill of a data feature.
+type FeatureReadiness struct {
+	Feature DataFeature
+	// ReadyAt is when the backfill of all modules completed. It is zero if
+	// it has not.
+	ReadyAt time.Time
+	// NumModules is the number of modules whose backfill completed
+	// individually.
+	NumModules int
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"gopkg.in/yaml.v3"
+)
+
+// authorMetadataFilename is the name of the file at the root of a module in
+// which its authors can declare links and badges for its pages.
+const authorMetadataFilename = "pkgsite.yaml"
+
+const (
+	// maxAuthorMetadataFileSize is the maximum size of a pkgsite.yaml file.
+	maxAuthorMetadataFileSize = 16 * 1024
+
+	maxAuthorLinks  = 5
+	maxAuthorBadges = 5
+	maxTitleLength  = 40 // in runes
+	maxURLLength    = 512
+
+	maxDocsPatterns      = 10
+	maxDocsPatternLength = 256
+)
+
+// authorMetadataFile is the schema of a pkgsite.yaml file. For example:
+//
+//	links:
+//	  - title: Documentation
+//	    url: https://example.com/docs
+//	  - title: Security policy
+//	    url: https://example.com/security
+//	badges:
+//	  - title: Build status
+//	    image: https://example.com/badge.svg
+//	    url: https://example.com/builds
+//	issue_links: false
+//	docs:
+//	  - "*.md"
+//	  - guides/*.md
+//
+// Setting issue_links to false turns off linking references to issues, such
+// as "#1234", in the module's README and documentation.
+//
+// docs lists patterns, in the syntax of path.Match, for the Markdown files
+// under the docs directory of each unit to show in its Docs tab. Without it,
+// all of them are shown; an empty list turns off the tab.
+type authorMetadataFile struct {
+	Links []struct {
+		Title string `yaml:"title"`
+		URL   string `yaml:"url"`
+	} `yaml:"links"`
+	Badges []struct {
+		Title string `yaml:"title"`
+		Image string `yaml:"image"`
+		URL   string `yaml:"url"`
+	} `yaml:"badges"`
+	IssueLinks *bool     `yaml:"issue_links"`
+	Docs       *[]string `yaml:"docs"`
+}
+
+// extractAuthorMetadata reads the pkgsite.yaml file at the root of the
+// module, if there is one. It returns nil if there is no such file.
+func extractAuthorMetadata(contentDir fs.FS) (_ *internal.AuthorMetadata, err error) {
+	defer derrors.Wrap(&err, "extractAuthorMetadata")
+
+	info, err := fs.Stat(contentDir, authorMetadataFilename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, nil
+	}
+	if info.Size() > maxAuthorMetadataFileSize {
+		return nil, fmt.Errorf("file size %d exceeds limit %d", info.Size(), maxAuthorMetadataFileSize)
+	}
+	data, err := readFSFile(contentDir, authorMetadataFilename, maxAuthorMetadataFileSize)
+	if err != nil {
+		return nil, err
+	}
+	return parseAuthorMetadata(data)
+}
+
+// parseAuthorMetadata parses and validates the contents of a pkgsite.yaml
+// file. Unknown fields, too many entries, and anything but plain titles and
+// https URLs are errors. It returns nil if the file declares nothing and
+// keeps the defaults.
+func parseAuthorMetadata(data []byte) (_ *internal.AuthorMetadata, err error) {
+	defer derrors.Wrap(&err, "parseAuthorMetadata")
+
+	var f authorMetadataFile
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&f); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(f.Links) > maxAuthorLinks {
+		return nil, fmt.Errorf("%d links; at most %d are allowed", len(f.Links), maxAuthorLinks)
+	}
+	if len(f.Badges) > maxAuthorBadges {
+		return nil, fmt.Errorf("%d badges; at most %d are allowed", len(f.Badges), maxAuthorBadges)
+	}
+	md := &internal.AuthorMetadata{}
+	for i, l := range f.Links {
+		if err := checkTitle(l.Title); err != nil {
+			return nil, fmt.Errorf("links[%d]: %v", i, err)
+		}
+		if err := checkURL(l.URL); err != nil {
+			return nil, fmt.Errorf("links[%d]: %v", i, err)
+		}
+		md.Links = append(md.Links, &internal.AuthorLink{Title: l.Title, URL: l.URL})
+	}
+	for i, b := range f.Badges {
+		if err := checkTitle(b.Title); err != nil {
+			return nil, fmt.Errorf("badges[%d]: %v", i, err)
+		}
+		if err := checkURL(b.Image); err != nil {
+			return nil, fmt.Errorf("badges[%d]: image: %v", i, err)
+		}
+		if b.URL != "" {
+			if err := checkURL(b.URL); err != nil {
+				return nil, fmt.Errorf("badges[%d]: %v", i, err)
+			}
+		}
+		md.Badges = append(md.Badges, &internal.AuthorBadge{Title: b.Title, ImageURL: b.Image, URL: b.URL})
+	}
+	md.NoIssueLinks = f.IssueLinks != nil && !*f.IssueLinks
+	if f.Docs != nil {
+		if len(*f.Docs) > maxDocsPatterns {
+			return nil, fmt.Errorf("%d docs patterns; at most %d are allowed", len(*f.Docs), maxDocsPatterns)
+		}
+		for i, p := range *f.Docs {
+			if err := checkDocsPattern(p); err != nil {
+				return nil, fmt.Errorf("docs[%d]: %v", i, err)
+			}
+		}
+		if len(*f.Docs) == 0 {
+			md.NoDocsPages = true
+		} else {
+			md.DocsPages = *f.Docs
+		}
+	}
+	if len(md.Links) == 0 && len(md.Badges) == 0 && !md.NoIssueLinks && len(md.DocsPages) == 0 && !md.NoDocsPages {
+		return nil, nil
+	}
+	return md, nil
+}
+
+// checkTitle reports whether s is a non-empty, reasonably short title made of
+// printable characters.
+func checkTitle(s string) error {
+	if s == "" {
+		return errors.New("missing title")
+	}
+	if n := utf8.RuneCountInString(s); n > maxTitleLength {
+		return fmt.Errorf("title has %d characters; at most %d are allowed", n, maxTitleLength)
+	}
+	for _, r := range s {
+		if r == utf8.RuneError || !unicode.IsPrint(r) {
+			return fmt.Errorf("title %q has a non-printable character", s)
+		}
+	}
+	return nil
+}
+
+// checkDocsPattern reports whether s is a valid pattern for the paths of
+// files relative to a docs directory.
+func checkDocsPattern(s string) error {
+	if s == "" {
+		return errors.New("missing pattern")
+	}
+	if len(s) > maxDocsPatternLength {
+		return fmt.Errorf("pattern has %d bytes; at most %d are allowed", len(s), maxDocsPatternLength)
+	}
+	if _, err := path.Match(s, ""); err != nil {
+		return fmt.Errorf("pattern %q: %v", s, err)
+	}
+	if strings.HasPrefix(s, "/") || slices.Contains(strings.Split(s, "/"), "..") {
+		return fmt.Errorf("pattern %q is not relative to the docs directory", s)
+	}
+	return nil
+}
+
+// checkURL reports whether s is an absolute https URL with a host and no
+// user information.
+func checkURL(s string) error {
+	if s == "" {
+		return errors.New("missing URL")
+	}
+	if len(s) > maxURLLength {
+		return fmt.Errorf("URL has %d bytes; at most %d are allowed", len(s), maxURLLength)
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if u.Scheme != "https" || u.Host == "" || u.User != nil || u.Opaque != "" {
+		return fmt.Errorf("URL %q is not an https URL", s)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestParseAuthorMetadata(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		in   string
+		want *internal.AuthorMetadata
+	}{
+		{
+			name: "empty",
+			in:   "",
+			want: nil,
+		},
+		{
+			name: "links and badges",
+			in: `
+links:
+  - title: Documentation
+    url: https://example.com/docs
+  - title: Security policy
+    url: https://example.com/security
+badges:
+  - title: Build status
+    image: https://example.com/badge.svg
+    url: https://example.com/builds
+  - title: Coverage
+    image: https://example.com/coverage.svg
+`,
+			want: &internal.AuthorMetadata{
+				Links: []*internal.AuthorLink{
+					{Title: "Documentation", URL: "https://example.com/docs"},
+					{Title: "Security policy", URL: "https://example.com/security"},
+				},
+				Badges: []*internal.AuthorBadge{
+					{Title: "Build status", ImageURL: "https://example.com/badge.svg", URL: "https://example.com/builds"},
+					{Title: "Coverage", ImageURL: "https://example.com/coverage.svg"},
+				},
+			},
+		},
+		{
+			name: "issue links off",
+			in:   "issue_links: false\n",
+			want: &internal.AuthorMetadata{NoIssueLinks: true},
+		},
+		{
+			name: "issue links on",
+			in:   "issue_links: true\n",
+			want: nil,
+		},
+		{
+			name: "docs patterns",
+			in:   "docs:\n  - \"*.md\"\n  - guides/*.md\n",
+			want: &internal.AuthorMetadata{DocsPages: []string{"*.md", "guides/*.md"}},
+		},
+		{
+			name: "docs off",
+			in:   "docs: []\n",
+			want: &internal.AuthorMetadata{NoDocsPages: true},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := parseAuthorMetadata([]byte(test.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseAuthorMetadataErrors(t *testing.T) {
+	for _, test := range []struct {
+		name, in string
+	}{
+		{"unknown field", "links:\n  - title: Docs\n    url: https://example.com\n    icon: x\n"},
+		{"unknown section", "scripts:\n  - x\n"},
+		{"not yaml", "links: [\n"},
+		{"missing title", "links:\n  - url: https://example.com\n"},
+		{"long title", "links:\n  - title: " + strings.Repeat("x", maxTitleLength+1) + "\n    url: https://example.com\n"},
+		{"control character in title", "links:\n  - title: \"a\\u0007b\"\n    url: https://example.com\n"},
+		{"missing url", "links:\n  - title: Docs\n"},
+		{"http url", "links:\n  - title: Docs\n    url: http://example.com\n"},
+		{"javascript url", "links:\n  - title: Docs\n    url: javascript:alert(1)\n"},
+		{"relative url", "links:\n  - title: Docs\n    url: /docs\n"},
+		{"url with user", "links:\n  - title: Docs\n    url: https://user@example.com\n"},
+		{"badge without image", "badges:\n  - title: Build\n    url: https://example.com\n"},
+		{"data image", "badges:\n  - title: Build\n    image: data:image/svg+xml;base64,AAAA\n"},
+		{"issue links not a bool", "issue_links: sometimes\n"},
+		{"bad docs pattern", "docs:\n  - \"[a-\"\n"},
+		{"absolute docs pattern", "docs:\n  - /etc/*.md\n"},
+		{"docs pattern outside docs", "docs:\n  - ../*.md\n"},
+		{"too many docs patterns", "docs:\n" + strings.Repeat("  - \"*.md\"\n", maxDocsPatterns+1)},
+		{"too many links", "links:\n" + strings.Repeat("  - title: Docs\n    url: https://example.com\n", maxAuthorLinks+1)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got, err := parseAuthorMetadata([]byte(test.in)); err == nil {
+				t.Errorf("got %+v, want error", got)
+			}
+		})
+	}
+}
+
+func TestExtractAuthorMetadata(t *testing.T) {
+	const contents = "links:\n  - title: Docs\n    url: https://example.com/docs\n"
+	want := &internal.AuthorMetadata{
+		Links: []*internal.AuthorLink{{Title: "Docs", URL: "https://example.com/docs"}},
+	}
+	got, err := extractAuthorMetadata(fstest.MapFS{authorMetadataFilename: {Data: []byte(contents)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	got, err = extractAuthorMetadata(fstest.MapFS{"go.mod": {Data: []byte("module m")}})
+	if err != nil || got != nil {
+		t.Errorf("no file: got (%v, %v), want (nil, nil)", got, err)
+	}
+
+	big := fstest.MapFS{authorMetadataFilename: {Data: make([]byte, maxAuthorMetadataFileSize+1)}}
+	if _, err := extractAuthorMetadata(big); err == nil {
+		t.Error("large file: got no error")
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// ContentScanningModuleGetter is an additional interface that may be
+// implemented by ModuleGetters to check the files of the module versions
+// they get against a content policy.
+type ContentScanningModuleGetter interface {
+	// ScanContent returns the files of the module version at path and
+	// version, whose files are in contentDir, that break the policy. It
+	// returns an error wrapping derrors.BadModule if the module version must
+	// not be processed.
+	ScanContent(ctx context.Context, path, version string, contentDir fs.FS) ([]*internal.ContentWarning, error)
+}
+
+// WithContentScanner returns a ModuleGetter like mg that also checks the
+// files of module versions with s. Only the ModuleGetters of module proxies
+// support it; others are returned as is.
+func WithContentScanner(mg ModuleGetter, s *ContentScanner) ModuleGetter {
+	g, ok := mg.(*proxyModuleGetter)
+	if !ok {
+		return mg
+	}
+	g2 := *g
+	g2.scanner = s
+	return &g2
+}
+
+// ScanContent implements ContentScanningModuleGetter.
+func (g *proxyModuleGetter) ScanContent(ctx context.Context, path, version string, contentDir fs.FS) ([]*internal.ContentWarning, error) {
+	if g.scanner == nil {
+		return nil, nil
+	}
+	return g.scanner.Scan(ctx, path, version, contentDir)
+}
+
+const (
+	// maxScannedFileSize is the size of the largest file whose lines are
+	// matched against secret patterns. Larger files are only checked for
+	// their size and kind.
+	maxScannedFileSize = 10 << 20
+
+	// sniffLen is the length of the start of a file that is looked at to
+	// tell whether it is text.
+	sniffLen = 8000
+)
+
+// A ContentScanner checks the files of module versions against a
+// config.ContentPolicy.
+type ContentScanner struct {
+	policy  *config.ContentPolicy
+	secrets []*secretPattern
+}
+
+type secretPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// NewContentScanner returns a ContentScanner for p.
+func NewContentScanner(p *config.ContentPolicy) (_ *ContentScanner, err error) {
+	defer derrors.Wrap(&err, "NewContentScanner")
+
+	s := &ContentScanner{policy: p}
+	for _, sp := range p.SecretPatterns {
+		re, err := regexp.Compile(sp.Regexp)
+		if err != nil {
+			return nil, fmt.Errorf("secret pattern %q: %v", sp.Name, err)
+		}
+		s.secrets = append(s.secrets, &secretPattern{sp.Name, re})
+	}
+	return s, nil
+}
+
+// Scan returns the files in contentDir, the content of the module version at
+// path and version, that break the policy of s. If the policy blocks module
+// versions that break it and there are any such files, it also returns an
+// error wrapping derrors.BadModule.
+func (s *ContentScanner) Scan(ctx context.Context, path, version string, contentDir fs.FS) (_ []*internal.ContentWarning, err error) {
+	defer derrors.Wrap(&err, "ContentScanner.Scan(%q, %q)", path, version)
+
+	var warnings []*internal.ContentWarning
+	err = fs.WalkDir(contentDir, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		ws, err := s.scanFile(contentDir, name)
+		if err != nil {
+			return err
+		}
+		warnings = append(warnings, ws...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(warnings) == 0 {
+		return nil, nil
+	}
+	msg := fmt.Sprintf("%s@%s: %d content policy violations, like %s (%s)",
+		path, version, len(warnings), warnings[0].File, warnings[0].Rule)
+	if s.policy.Block {
+		return warnings, fmt.Errorf("%s: %w", msg, derrors.BadModule)
+	}
+	log.Warningf(ctx, "%s", msg)
+	return warnings, nil
+}
+
+// scanFile returns the ways the file with the given name in contentDir breaks
+// the policy of s.
+func (s *ContentScanner) scanFile(contentDir fs.FS, name string) (_ []*internal.ContentWarning, err error) {
+	f, err := contentDir.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	var warnings []*internal.ContentWarning
+	if s.policy.MaxFileSize > 0 && info.Size() > s.policy.MaxFileSize {
+		warnings = append(warnings, &internal.ContentWarning{File: name, Rule: internal.ContentRuleMaxFileSize})
+	}
+	if !s.policy.BanExecutables && len(s.secrets) == 0 {
+		return warnings, nil
+	}
+	br := bufio.NewReaderSize(f, sniffLen)
+	head, err := br.Peek(sniffLen)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if s.policy.BanExecutables && isExecutable(head) {
+		warnings = append(warnings, &internal.ContentWarning{File: name, Rule: internal.ContentRuleExecutable})
+	}
+	// Secrets are only looked for in text.
+	if len(s.secrets) == 0 || bytes.IndexByte(head, 0) >= 0 || info.Size() > maxScannedFileSize {
+		return warnings, nil
+	}
+	found := map[string]bool{}
+	scan := bufio.NewScanner(br)
+	scan.Buffer(nil, maxScannedFileSize)
+	for line := 1; scan.Scan(); line++ {
+		for _, sp := range s.secrets {
+			// Report the first match of each pattern in a file only.
+			if !found[sp.name] && sp.re.Match(scan.Bytes()) {
+				found[sp.name] = true
+				warnings = append(warnings, &internal.ContentWarning{File: name, Rule: sp.name, Line: line})
+			}
+		}
+	}
+	if err := scan.Err(); err != nil {
+		return nil, err
+	}
+	return warnings, nil
+}
+
+// executableMagics are the starts of executables and object files.
+var executableMagics = [][]byte{
+	[]byte("\x7fELF"),
+	{0xfe, 0xed, 0xfa, 0xce}, // Mach-O, 32-bit
+	{0xfe, 0xed, 0xfa, 0xcf}, // Mach-O, 64-bit
+	{0xce, 0xfa, 0xed, 0xfe}, // Mach-O, 32-bit, little-endian
+	{0xcf, 0xfa, 0xed, 0xfe}, // Mach-O, 64-bit, little-endian
+	{0xca, 0xfe, 0xba, 0xbe}, // Mach-O universal binary, or Java class
+	[]byte("!<arch>\n"),      // archive, like a Go or C library
+	[]byte("\x00asm"),        // WebAssembly
+}
+
+// isExecutable reports whether the file that starts with head is an
+// executable or an object file.
+func isExecutable(head []byte) bool {
+	for _, m := range executableMagics {
+		if bytes.HasPrefix(head, m) {
+			return true
+		}
+	}
+	// PE files start with an MS-DOS header, whose last field is the offset
+	// of the PE signature.
+	if bytes.HasPrefix(head, []byte("MZ")) && len(head) >= 0x40 {
+		off := int(head[0x3c]) | int(head[0x3d])<<8 | int(head[0x3e])<<16 | int(head[0x3f])<<24
+		if off >= 0x40 && off+4 <= len(head) && bytes.Equal(head[off:off+4], []byte("PE\x00\x00")) {
+			return true
+		}
+	}
+	return false
+}
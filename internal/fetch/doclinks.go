@@ -0,0 +1,71 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"fmt"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/godoc"
+)
+
+// checkDocLinks sets the BrokenDocLinks of each documentation of pkgs, which
+// are the packages of a module, to its doc links whose symbols do not exist.
+func checkDocLinks(pkgs []*goPackage) {
+	// Map each package path to the names of its symbols in any build
+	// context, such as "T" and "T.M".
+	symbols := map[string]map[string]bool{}
+	for _, pkg := range pkgs {
+		names := map[string]bool{}
+		for _, doc := range pkg.docs {
+			for _, s := range doc.API {
+				names[s.Name] = true
+				for _, c := range s.Children {
+					names[c.Name] = true
+				}
+			}
+		}
+		symbols[pkg.path] = names
+	}
+	for _, pkg := range pkgs {
+		for _, doc := range pkg.docs {
+			doc.BrokenDocLinks = nil
+			for _, l := range pkg.docLinks[doc] {
+				var reason string
+				if l.ImportPath == pkg.path {
+					// godoc only reports links to symbols of the package
+					// itself if they do not exist.
+					reason = fmt.Sprintf("The package has no symbol %s.", docLinkSymbol(l))
+				} else {
+					names := symbols[l.ImportPath]
+					if len(names) == 0 || names[docLinkSymbol(l)] {
+						// The package was not loaded, or has no API to check
+						// against, or the link is fine.
+						continue
+					}
+					reason = fmt.Sprintf("Package %s has no symbol %s.", l.ImportPath, docLinkSymbol(l))
+				}
+				doc.BrokenDocLinks = append(doc.BrokenDocLinks, &internal.BrokenDocLink{
+					Text:       l.Text,
+					ImportPath: l.ImportPath,
+					Recv:       l.Recv,
+					Name:       l.Name,
+					File:       l.File,
+					Line:       l.Line,
+					Reason:     reason,
+				})
+			}
+		}
+	}
+}
+
+// docLinkSymbol returns the name of the symbol that l links to, as it is
+// named in the API of its package.
+func docLinkSymbol(l *godoc.DocLink) string {
+	if l.Recv == "" {
+		return l.Name
+	}
+	return l.Recv + "." + l.Name
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/godoc"
+)
+
+func TestCheckDocLinks(t *testing.T) {
+	api := func(names ...string) []*internal.Symbol {
+		var syms []*internal.Symbol
+		for _, n := range names {
+			syms = append(syms, &internal.Symbol{SymbolMeta: internal.SymbolMeta{Name: n}})
+		}
+		return syms
+	}
+	pDoc := &internal.Documentation{API: api("F")}
+	qDoc := &internal.Documentation{API: api("G")}
+	// The methods of T only exist in one build context.
+	qDocWindows := &internal.Documentation{API: []*internal.Symbol{{
+		SymbolMeta: internal.SymbolMeta{Name: "T"},
+		Children:   []*internal.SymbolMeta{{Name: "T.M", ParentName: "T"}},
+	}}}
+	link := func(importPath, recv, name string) *godoc.DocLink {
+		return &godoc.DocLink{Text: name, ImportPath: importPath, Recv: recv, Name: name, File: "p.go", Line: 3}
+	}
+	p := &goPackage{
+		path: "example.com/m/p",
+		docs: []*internal.Documentation{pDoc},
+		docLinks: map[*internal.Documentation][]*godoc.DocLink{pDoc: {
+			link("example.com/m/p", "", "Missing"),
+			link("example.com/m/q", "", "G"),
+			link("example.com/m/q", "T", "M"),
+			link("example.com/m/q", "", "H"),
+			link("example.com/m/cmd", "", "X"),
+		}},
+	}
+	q := &goPackage{
+		path: "example.com/m/q",
+		docs: []*internal.Documentation{qDoc, qDocWindows},
+	}
+	cmd := &goPackage{
+		path: "example.com/m/cmd",
+		docs: []*internal.Documentation{{}},
+	}
+	checkDocLinks([]*goPackage{p, q, cmd})
+
+	want := []*internal.BrokenDocLink{
+		{Text: "Missing", ImportPath: "example.com/m/p", Name: "Missing", File: "p.go", Line: 3,
+			Reason: "The package has no symbol Missing."},
+		{Text: "H", ImportPath: "example.com/m/q", Name: "H", File: "p.go", Line: 3,
+			Reason: "Package example.com/m/q has no symbol H."},
+	}
+	if diff := cmp.Diff(want, pDoc.BrokenDocLinks); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	if len(qDoc.BrokenDocLinks) != 0 {
+		t.Errorf("got broken links %v for a package without links", qDoc.BrokenDocLinks)
+	}
+}
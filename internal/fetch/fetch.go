@@ -54,6 +54,8 @@ type LazyModule struct {
 	licenseDetector  *licenses.Detector
 	contentDir       fs.FS
 	godocModInfo     *godoc.ModuleInfo
+	requirements     []*internal.ModuleRequirement
+	releaseNotes     []*stdlib.ReleaseNoteSection
 	Error            error
 }
 
@@ -115,6 +117,12 @@ func fetchLazyModule(ctx context.Context, modulePath, requestedVersion string, m
 
 	if modulePath == stdlib.ModulePath {
 		lm.ModuleInfo.HasGoMod = true
+		lm.releaseNotes, err = stdlib.ReleaseNoteSections(contentDir)
+		if err != nil {
+			// Missing links to the release notes shouldn't prevent the
+			// standard library from being processed.
+			log.Errorf(ctx, "%v", err)
+		}
 	} else {
 		lm.ModuleInfo.HasGoMod = hasGoModFile(contentDir)
 	}
@@ -126,6 +134,18 @@ func fetchLazyModule(ctx context.Context, modulePath, requestedVersion string, m
 	if err != nil {
 		return lm, err
 	}
+	if cmg, ok := mg.(ChecksumModuleGetter); ok {
+		lm.ModuleInfo.Checksum, err = cmg.Checksum(ctx, info, modulePath, contentDir, goModBytes)
+		if err != nil {
+			return lm, err
+		}
+	}
+	if smg, ok := mg.(ContentScanningModuleGetter); ok {
+		lm.ModuleInfo.ContentWarnings, err = smg.ScanContent(ctx, modulePath, lm.ModuleInfo.Version, contentDir)
+		if err != nil {
+			return lm, err
+		}
+	}
 
 	// If there is no go.mod file in the zip, try other ways to detect
 	// alternative modules:
@@ -162,15 +182,22 @@ func fetchLazyModule(ctx context.Context, modulePath, requestedVersion string, m
 	}
 	lm.licenseDetector = licenses.NewDetectorFS(modulePath, v, contentDir, logf)
 	lm.ModuleInfo.IsRedistributable = lm.licenseDetector.ModuleIsRedistributable()
-	lm.UnitMetas, lm.godocModInfo, lm.failedPackages, err = extractUnitMetas(ctx, lm.ModuleInfo, contentDir)
-	if err != nil {
-		return lm, err
-	}
 	if goModBytes != nil {
-		if err := processGoModFile(goModBytes, &lm.ModuleInfo); err != nil {
+		lm.requirements, err = processGoModFile(goModBytes, &lm.ModuleInfo)
+		if err != nil {
 			return lm, fmt.Errorf("%v: %w", err, derrors.BadModule)
 		}
 	}
+	lm.ModuleInfo.AuthorMetadata, err = extractAuthorMetadata(contentDir)
+	if err != nil {
+		// An invalid pkgsite.yaml file is ignored; it shouldn't prevent the
+		// module from being processed.
+		log.Infof(ctx, "%s@%s: %v", modulePath, lm.ModuleInfo.Version, err)
+	}
+	lm.UnitMetas, lm.godocModInfo, lm.failedPackages, err = extractUnitMetas(ctx, lm.ModuleInfo, contentDir)
+	if err != nil {
+		return lm, err
+	}
 
 	return lm, nil
 }
@@ -182,33 +209,53 @@ func (lm *LazyModule) Unit(ctx context.Context, path string) (*internal.Unit, er
 			unitMeta = um
 		}
 	}
-	u, _, err := lm.unit(ctx, unitMeta)
+	u, pkg, _, err := lm.unit(ctx, unitMeta)
 	if err == nil && u == nil {
 		return nil, fmt.Errorf("unit %v does not exist in module", path)
 	}
+	if pkg != nil {
+		// Only the links within the package can be checked without loading
+		// the rest of the module.
+		checkDocLinks([]*goPackage{pkg})
+	}
 	return u, err
 }
 
-// unit returns the Unit for the given path. It also returns a packageVersionState representing
-// the state of the work of computing the Unit after the LazyModule was computed. PackageVersionStates
-// representing packages that failed while the LazyModule was computed are set on the LazyModule.
-func (lm *LazyModule) unit(ctx context.Context, unitMeta *internal.UnitMeta) (*internal.Unit, *internal.PackageVersionState, error) {
+// unit returns the Unit for the given path, and its package if it is one. It
+// also returns a packageVersionState representing the state of the work of
+// computing the Unit after the LazyModule was computed. PackageVersionStates
+// representing packages that failed while the LazyModule was computed are set
+// on the LazyModule.
+func (lm *LazyModule) unit(ctx context.Context, unitMeta *internal.UnitMeta) (*internal.Unit, *goPackage, *internal.PackageVersionState, error) {
 	readme, err := extractReadme(lm.ModulePath, unitMeta.Path, lm.ModuleInfo.Version, lm.contentDir)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
+	}
+	localizedReadmes, err := extractLocalizedReadmes(lm.ModulePath, unitMeta.Path, lm.contentDir)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	docsPages, err := extractDocsPages(lm.ModulePath, unitMeta.Path, lm.contentDir, lm.AuthorMetadata)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 	// This unit represents the module itself, not a package.
 	if !unitMeta.IsPackage() {
-		return moduleUnit(lm.ModulePath, unitMeta, nil, readme, lm.licenseDetector), nil, nil
+		u := moduleUnit(lm.ModulePath, unitMeta, nil, readme, lm.licenseDetector)
+		u.LocalizedReadmes = localizedReadmes
+		u.DocsPages = docsPages
+		return u, nil, nil, nil
 	}
 	pkg, pvs, err := extractPackage(ctx, lm.ModulePath, unitMeta.Path, lm.contentDir, lm.licenseDetector, lm.SourceInfo, lm.godocModInfo)
 	if err != nil || (pvs != nil && pvs.Status != 200) {
 		// pvs can be non-nil even if err is non-nil.
-		return nil, pvs, err
+		return nil, nil, pvs, err
 	}
 
 	u := moduleUnit(lm.ModulePath, unitMeta, pkg, readme, lm.licenseDetector)
-	return u, pvs, nil
+	u.LocalizedReadmes = localizedReadmes
+	u.DocsPages = docsPages
+	return u, pkg, pvs, nil
 }
 
 func (lm *LazyModule) fetchResult(ctx context.Context) *FetchResult {
@@ -231,12 +278,15 @@ func (lm *LazyModule) fetchResult(ctx context.Context) *FetchResult {
 		return fr
 	}
 	fr.Module.Licenses = lm.licenseDetector.AllLicenses()
+	fr.Module.Requirements = lm.requirements
+	fr.Module.ReleaseNoteSections = lm.releaseNotes
 	// We need to set HasGoMod here rather than on the ModuleInfo when
 	// it's created because the ModuleInfo that goes on the units shouldn't
 	// have HasGoMod set on it.
 	packageVersionStates := append([]*internal.PackageVersionState{}, lm.failedPackages...)
+	var pkgs []*goPackage
 	for _, um := range lm.UnitMetas {
-		unit, pvs, err := lm.unit(ctx, um)
+		unit, pkg, pvs, err := lm.unit(ctx, um)
 		if err != nil {
 			fr.Error = err
 		}
@@ -248,7 +298,13 @@ func (lm *LazyModule) fetchResult(ctx context.Context) *FetchResult {
 			continue
 		}
 		fr.Module.Units = append(fr.Module.Units, unit)
+		if pkg != nil {
+			pkgs = append(pkgs, pkg)
+		}
 	}
+	// Doc links can point to any package of the module, so they can only be
+	// checked once all of them are loaded.
+	checkDocLinks(pkgs)
 	if fr.Error != nil {
 		fr.Status = derrors.ToStatus(fr.Error)
 	}
@@ -316,16 +372,30 @@ func hasGoModFile(contentDir fs.FS) bool {
 	return err == nil && !info.IsDir()
 }
 
-// processGoModFile populates mod with information extracted from the contents of the go.mod file.
-func processGoModFile(goModBytes []byte, mod *internal.ModuleInfo) (err error) {
+// processGoModFile populates mod with information extracted from the contents
+// of the go.mod file, and returns the requirements in it.
+func processGoModFile(goModBytes []byte, mod *internal.ModuleInfo) (_ []*internal.ModuleRequirement, err error) {
 	defer derrors.Wrap(&err, "processGoModFile")
 
 	mf, err := modfile.Parse("go.mod", goModBytes, nil)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	mod.Deprecated, mod.DeprecationComment = extractDeprecatedComment(mf)
-	return nil
+	// The go directive of a go.mod file synthesized by the proxy for a module
+	// without one says nothing about the module.
+	if mf.Go != nil && mod.HasGoMod {
+		mod.GoVersion = mf.Go.Version
+	}
+	var reqs []*internal.ModuleRequirement
+	for _, r := range mf.Require {
+		reqs = append(reqs, &internal.ModuleRequirement{
+			ModulePath: r.Mod.Path,
+			Version:    r.Mod.Version,
+			Indirect:   r.Indirect,
+		})
+	}
+	return reqs, nil
 }
 
 // extractDeprecatedComment looks for "Deprecated" comments in the line comments
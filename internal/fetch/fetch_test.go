@@ -133,7 +133,7 @@ func TestFetchModule(t *testing.T) {
 					sortFetchResult(fr)
 					sortFetchResult(got)
 					opts := []cmp.Option{
-						cmpopts.IgnoreFields(internal.Documentation{}, "Source"),
+						cmpopts.IgnoreFields(internal.Documentation{}, "Source", "SourceHash"),
 						cmpopts.IgnoreFields(internal.PackageVersionState{}, "Error"),
 						cmp.AllowUnexported(source.Info{}),
 						cmpopts.EquateEmpty(),
@@ -293,3 +293,31 @@ func TestExtractDeprecatedComment(t *testing.T) {
 		}
 	}
 }
+
+func TestProcessGoModFileRequirements(t *testing.T) {
+	const goMod = `
+		module example.com/m
+
+		go 1.21
+
+		require (
+			example.com/a v1.2.3
+			example.com/b v0.1.0 // indirect
+		)
+	`
+	mi := &internal.ModuleInfo{HasGoMod: true}
+	got, err := processGoModFile([]byte(goMod), mi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*internal.ModuleRequirement{
+		{ModulePath: "example.com/a", Version: "v1.2.3"},
+		{ModulePath: "example.com/b", Version: "v0.1.0", Indirect: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	if mi.GoVersion != "1.21" {
+		t.Errorf("GoVersion = %q, want %q", mi.GoVersion, "1.21")
+	}
+}
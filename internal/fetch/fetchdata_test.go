@@ -162,6 +162,7 @@ var moduleMultiPackage = &testModule{
 			ModuleInfo: internal.ModuleInfo{
 				ModulePath:        "example.com/multi",
 				HasGoMod:          true,
+				GoVersion:         "1.13",
 				SourceInfo:        source.NewGitHubInfo("https://example.com/multi", "", "v1.0.0"),
 				IsRedistributable: true,
 			},
@@ -336,13 +337,22 @@ var moduleBadPackages = &testModule{
 				PackagePath: "bad.mod/module/illegalchar",
 				ModulePath:  "bad.mod/module",
 				Version:     "v1.0.0",
-				Status:      600, //TODO(jba): this should be InvalidContents (604), not a build context error
+				Status:      604,
+				Diagnostics: []*internal.PackageDiagnostic{
+					{File: "illegalchar/p.go", Line: 5, Column: 24, Message: `illegal character U+0027 ''' in escape sequence`},
+					{File: "illegalchar/p.go", Line: 6, Column: 25, Message: `illegal character U+0027 ''' in escape sequence`},
+					{File: "illegalchar/p.go", Line: 7, Column: 25, Message: `illegal character U+0027 ''' in escape sequence`},
+					{File: "illegalchar/p.go", Line: 8, Column: 24, Message: `illegal character U+0027 ''' in escape sequence`},
+				},
 			},
 			{
 				PackagePath: "bad.mod/module/multiplepkgs",
 				ModulePath:  "bad.mod/module",
 				Version:     "v1.0.0",
-				Status:      600,
+				Status:      604,
+				Diagnostics: []*internal.PackageDiagnostic{
+					{File: "multiplepkgs/b.go", Message: "found packages a (a.go) and b (b.go)"},
+				},
 			},
 		},
 	},
@@ -381,6 +391,10 @@ var moduleBuildConstraints = &testModule{
 							GOOS:     "linux",
 							GOARCH:   "amd64",
 							Synopsis: "Package cpu implements processor feature detection used by the Go standard library.",
+							ExcludedFiles: []*internal.ExcludedFile{
+								{Name: "cpu_arm.go", BuildConstraint: "arm"},
+								{Name: "cpu_arm64.go", BuildConstraint: "arm64"},
+							},
 							API: []*internal.Symbol{
 								{
 									SymbolMeta: internal.SymbolMeta{
@@ -396,6 +410,10 @@ var moduleBuildConstraints = &testModule{
 							GOOS:     "windows",
 							GOARCH:   "amd64",
 							Synopsis: "Package cpu implements processor feature detection used by the Go standard library.",
+							ExcludedFiles: []*internal.ExcludedFile{
+								{Name: "cpu_arm.go", BuildConstraint: "arm"},
+								{Name: "cpu_arm64.go", BuildConstraint: "arm64"},
+							},
 							API: []*internal.Symbol{
 								{
 									SymbolMeta: internal.SymbolMeta{
@@ -411,6 +429,10 @@ var moduleBuildConstraints = &testModule{
 							GOOS:     "darwin",
 							GOARCH:   "amd64",
 							Synopsis: "Package cpu implements processor feature detection used by the Go standard library.",
+							ExcludedFiles: []*internal.ExcludedFile{
+								{Name: "cpu_arm.go", BuildConstraint: "arm"},
+								{Name: "cpu_arm64.go", BuildConstraint: "arm64"},
+							},
 							API: []*internal.Symbol{
 								{
 									SymbolMeta: internal.SymbolMeta{
@@ -426,6 +448,11 @@ var moduleBuildConstraints = &testModule{
 							GOOS:     "js",
 							GOARCH:   "wasm",
 							Synopsis: "Package cpu implements processor feature detection used by the Go standard library.",
+							ExcludedFiles: []*internal.ExcludedFile{
+								{Name: "cpu_arm.go", BuildConstraint: "arm"},
+								{Name: "cpu_arm64.go", BuildConstraint: "arm64"},
+								{Name: "cpu_x86.go", BuildConstraint: "386 || amd64 || amd64p32"},
+							},
 						},
 					},
 					BuildContexts: []internal.BuildContext{
@@ -489,8 +516,9 @@ var moduleBadBuildContext = &testModule{
 						Path: "github.com/bad-context/pkg",
 					},
 					Documentation: []*internal.Documentation{{
-						GOOS:   "linux",
-						GOARCH: "amd64",
+						GOOS:          "linux",
+						GOARCH:        "amd64",
+						ExcludedFiles: []*internal.ExcludedFile{{Name: "js.go", BuildConstraint: "js"}},
 					}},
 					BuildContexts: []internal.BuildContext{internal.BuildContextLinux},
 				},
@@ -507,6 +535,7 @@ var moduleNonRedist = &testModule{
 			ModuleInfo: internal.ModuleInfo{
 				ModulePath:        "example.com/nonredist",
 				HasGoMod:          true,
+				GoVersion:         "1.13",
 				SourceInfo:        source.NewGitHubInfo("https://example.com/nonredist", "", "v1.0.0"),
 				IsRedistributable: true,
 			},
@@ -900,6 +929,7 @@ var moduleStdMaster = &testModule{
 						},
 					},
 					BuildContexts: []internal.BuildContext{internal.BuildContextAll},
+					TestImports:   []string{"fmt", "testing"},
 				},
 				{
 					UnitMeta: internal.UnitMeta{
@@ -1364,6 +1394,10 @@ var moduleStd = &testModule{
 							GOOS:     "js",
 							GOARCH:   "wasm",
 							Synopsis: "Pprof interprets and displays profiles of Go programs.",
+							ExcludedFiles: []*internal.ExcludedFile{{
+								Name:            "readlineui.go",
+								BuildConstraint: "(darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris || windows) && !appengine && !android",
+							}},
 						},
 					},
 					BuildContexts: []internal.BuildContext{
@@ -1512,6 +1546,7 @@ var moduleStd = &testModule{
 					},
 					BuildContexts: []internal.BuildContext{internal.BuildContextAll},
 					Imports:       []string{"errors", "fmt", "reflect", "sync", "time"},
+					TestImports:   []string{"math/rand", "net", "runtime", "strings", "testing"},
 				},
 				{
 					UnitMeta: internal.UnitMeta{
@@ -2065,6 +2100,22 @@ var moduleStd = &testModule{
 						"unicode/utf16",
 						"unicode/utf8",
 					},
+					TestImports: []string{
+						"compress/gzip",
+						"image",
+						"internal/testenv",
+						"log",
+						"math/big",
+						"math/rand",
+						"net",
+						"net/http",
+						"net/http/httptest",
+						"os",
+						"regexp",
+						"runtime",
+						"testing",
+						"time",
+					},
 				},
 				{
 					UnitMeta: internal.UnitMeta{
@@ -2089,13 +2140,15 @@ var moduleStd = &testModule{
 						},
 					},
 					BuildContexts: []internal.BuildContext{internal.BuildContextAll},
+					TestImports:   []string{"fmt", "testing", "time"},
 				},
 				{
 					UnitMeta: internal.UnitMeta{
 						Name: "flag",
 						Path: "flag",
 					},
-					Imports: []string{"errors", "fmt", "io", "os", "reflect", "sort", "strconv", "strings", "time"},
+					Imports:     []string{"errors", "fmt", "io", "os", "reflect", "sort", "strconv", "strings", "time"},
+					TestImports: []string{"bytes", "net/url", "testing"},
 					Documentation: []*internal.Documentation{
 						{
 							GOOS:     internal.All,
@@ -2840,6 +2893,7 @@ var moduleGenerics = &testModule{
 			ModuleInfo: internal.ModuleInfo{
 				ModulePath:        "example.com/generics",
 				HasGoMod:          true,
+				GoVersion:         "1.18",
 				SourceInfo:        source.NewGitHubInfo("https://example.com/generics", "", "v1.0.0"),
 				IsRedistributable: true,
 			},
@@ -2905,7 +2959,7 @@ var moduleGenerics = &testModule{
 // The fetch result's documentation HTML is treated as a set
 // of substrings that should appear in the generated documentation.
 // The substrings are separated by a '~' character.
-func moduleWithExamples(path string, api []*internal.Symbol, source, test string, docSubstrings ...string) *testModule {
+func moduleWithExamples(path string, api []*internal.Symbol, source, test string, testImports []string, docSubstrings ...string) *testModule {
 	return &testModule{
 		mod: &proxytest.Module{
 			ModulePath: path,
@@ -2947,6 +3001,7 @@ package example_test
 							API:      api,
 						}},
 						BuildContexts: []internal.BuildContext{internal.BuildContextAll},
+						TestImports:   testImports,
 					},
 				},
 			},
@@ -2967,7 +3022,7 @@ func Example() {
 	fmt.Println("hello")
 	// Output: hello
 }
-`, "Documentation-exampleButtonsContainer")
+`, []string{"fmt"}, "Documentation-exampleButtonsContainer")
 
 var moduleFuncExample = moduleWithExamples("func.example",
 	[]*internal.Symbol{
@@ -2987,7 +3042,7 @@ var moduleFuncExample = moduleWithExamples("func.example",
 func ExampleF() {
 	example.F()
 }
-`, "Documentation-exampleButtonsContainer")
+`, nil, "Documentation-exampleButtonsContainer")
 
 var moduleTypeExample = moduleWithExamples("type.example",
 	[]*internal.Symbol{
@@ -3008,7 +3063,7 @@ var moduleTypeExample = moduleWithExamples("type.example",
 func ExampleT() {
 	example.T{}
 }
-`, "Documentation-exampleButtonsContainer")
+`, nil, "Documentation-exampleButtonsContainer")
 
 var moduleMethodExample = moduleWithExamples("method.example",
 	[]*internal.Symbol{
@@ -3039,4 +3094,4 @@ func (*T) M() {}
 func ExampleT_M() {
 	new(example.T).M()
 }
-`, "Documentation-exampleButtonsContainer")
+`, nil, "Documentation-exampleButtonsContainer")
@@ -19,6 +19,7 @@ import (
 	"io"
 	"io/fs"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
 	"sort"
@@ -27,7 +28,9 @@ import (
 
 	"golang.org/x/mod/modfile"
 	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/checksum"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/fuzzy"
 	"golang.org/x/pkgsite/internal/log"
@@ -82,13 +85,38 @@ type VolatileModuleGetter interface {
 	HasChanged(context.Context, internal.ModuleInfo) (bool, error)
 }
 
+// ChecksumModuleGetter is an additional interface that may be implemented by
+// ModuleGetters to check the module versions they get against a checksum
+// database.
+type ChecksumModuleGetter interface {
+	// Checksum returns the provenance of the module version described by
+	// info, whose files are in contentDir and whose go.mod file is goMod,
+	// and the result of checking it. It returns nil if there is nothing to
+	// record, and an error wrapping derrors.BadModule if the module version
+	// must not be processed.
+	Checksum(ctx context.Context, info *proxy.VersionInfo, path string, contentDir fs.FS, goMod []byte) (*internal.ModuleChecksum, error)
+}
+
 type proxyModuleGetter struct {
 	prox *proxy.Client
 	src  *source.Client
+
+	sumDB           *checksum.DB // if nil, module versions are not checked
+	requireVerified bool
+
+	scanner *ContentScanner // if nil, the files of module versions are not checked
 }
 
 func NewProxyModuleGetter(p *proxy.Client, s *source.Client) ModuleGetter {
-	return &proxyModuleGetter{p, s}
+	return &proxyModuleGetter{prox: p, src: s}
+}
+
+// NewCheckedProxyModuleGetter returns a ModuleGetter like
+// NewProxyModuleGetter that also checks module versions against the checksum
+// database db. If requireVerified is true, module versions whose checksums
+// don't match the database are rejected as bad modules.
+func NewCheckedProxyModuleGetter(p *proxy.Client, s *source.Client, db *checksum.DB, requireVerified bool) ModuleGetter {
+	return &proxyModuleGetter{prox: p, src: s, sumDB: db, requireVerified: requireVerified}
 }
 
 // Info returns basic information about the module.
@@ -126,6 +154,40 @@ func (g *proxyModuleGetter) String() string {
 	return "Proxy"
 }
 
+// Checksum implements ChecksumModuleGetter. A failure to reach the checksum
+// database leaves the module version unchecked, unless verification is
+// required.
+func (g *proxyModuleGetter) Checksum(ctx context.Context, info *proxy.VersionInfo, path string, contentDir fs.FS, goMod []byte) (*internal.ModuleChecksum, error) {
+	if g.sumDB == nil {
+		return nil, nil
+	}
+	c := &internal.ModuleChecksum{Status: internal.ChecksumUnchecked}
+	if info.Origin != nil {
+		c.OriginHash = info.Origin.Hash
+	}
+	var err error
+	c.ZipHash, c.GoModHash, err = checksum.Hashes(path, info.Version, contentDir, goMod)
+	if err != nil {
+		return nil, err
+	}
+	c.Status, err = g.sumDB.Verify(ctx, path, info.Version, c.ZipHash, c.GoModHash)
+	if err != nil {
+		if g.requireVerified {
+			return nil, err
+		}
+		log.Warningf(ctx, "%v", err)
+		return c, nil
+	}
+	if c.Status == internal.ChecksumMismatch {
+		msg := fmt.Sprintf("%s@%s: checksums don't match %s", path, info.Version, g.sumDB.Name())
+		if g.requireVerified {
+			return nil, fmt.Errorf("%s: %w", msg, derrors.BadModule)
+		}
+		log.Errorf(ctx, "%s", msg)
+	}
+	return c, nil
+}
+
 // Version and commit time are pre specified when fetching a local module, as these
 // fields are normally obtained from a proxy.
 var (
@@ -800,3 +862,189 @@ func (g *modCacheModuleGetter) moduleDir(modulePath string) (string, error) {
 func (g *modCacheModuleGetter) String() string {
 	return fmt.Sprintf("FSProxy(%s)", g.dir)
 }
+
+// A gitModuleGetter is a ModuleGetter whose source is a commit in a local Git
+// repository, which may be bare. The module must be at the root of the
+// repository.
+type gitModuleGetter struct {
+	modulePath string
+	repo       string // absolute path to the repository
+	ref        string // ref used to select the commit
+	commit     string // full hash of the commit
+	version    string
+	time       time.Time
+	goMod      []byte
+	zip        *zip.Reader // contents of the commit
+}
+
+// NewGitModuleGetter returns a ModuleGetter for the module at the commit
+// named by ref in the Git repository at repo. If modulePath is empty, it is
+// read from the go.mod file at that commit.
+//
+// The version of the module is the highest semantic version tag on the
+// commit. If there is none, it is a pseudo-version based on the highest
+// semantic version tag reachable from the commit.
+func NewGitModuleGetter(ctx context.Context, modulePath, repo, ref string) (_ *gitModuleGetter, err error) {
+	defer derrors.Wrap(&err, "NewGitModuleGetter(%q, %q, %q)", modulePath, repo, ref)
+
+	abs, err := filepath.Abs(repo)
+	if err != nil {
+		return nil, err
+	}
+	g := &gitModuleGetter{repo: abs, ref: ref}
+	out, err := g.git(ctx, "rev-parse", "--verify", "--end-of-options", ref+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, derrors.NotFound)
+	}
+	g.commit = strings.TrimSpace(string(out))
+
+	g.goMod, err = g.git(ctx, "show", g.commit+":go.mod")
+	if err != nil {
+		// There is no go.mod file at the commit.
+		g.goMod = nil
+	}
+	if modulePath == "" {
+		modulePath = modfile.ModulePath(g.goMod)
+		if modulePath == "" {
+			return nil, fmt.Errorf("cannot obtain module path for commit %s: %w", g.commit, derrors.BadModule)
+		}
+	}
+	g.modulePath = modulePath
+	if g.goMod == nil {
+		g.goMod = []byte(fmt.Sprintf("module %s\n", modulePath))
+	}
+
+	out, err = g.git(ctx, "show", "-s", "--format=%cI", g.commit)
+	if err != nil {
+		return nil, err
+	}
+	g.time, err = time.Parse(time.RFC3339, strings.TrimSpace(string(out)))
+	if err != nil {
+		return nil, err
+	}
+	g.time = g.time.UTC()
+	if g.version, err = g.resolveVersion(ctx); err != nil {
+		return nil, err
+	}
+
+	data, err := g.git(ctx, "archive", "--format=zip", g.commit)
+	if err != nil {
+		return nil, err
+	}
+	g.zip, err = zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// git runs git with args in the repository and returns its output.
+func (g *gitModuleGetter) git(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", g.repo}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return out, nil
+}
+
+// resolveVersion returns the version of the module at g.commit.
+func (g *gitModuleGetter) resolveVersion(ctx context.Context) (string, error) {
+	_, pathMajor, ok := module.SplitPathVersion(g.modulePath)
+	if !ok {
+		return "", fmt.Errorf("invalid module path %q: %w", g.modulePath, derrors.BadModule)
+	}
+	// highestTag returns the highest tag in the output of git tag that is a
+	// valid version for the module.
+	highestTag := func(out []byte) string {
+		var best string
+		for _, tag := range strings.Fields(string(out)) {
+			if semver.Canonical(tag) != tag || module.CheckPathMajor(tag, pathMajor) != nil {
+				continue
+			}
+			if best == "" || semver.Compare(tag, best) > 0 {
+				best = tag
+			}
+		}
+		return best
+	}
+	out, err := g.git(ctx, "tag", "--points-at", g.commit)
+	if err != nil {
+		return "", err
+	}
+	if v := highestTag(out); v != "" {
+		return v, nil
+	}
+	out, err = g.git(ctx, "tag", "--merged", g.commit)
+	if err != nil {
+		return "", err
+	}
+	return module.PseudoVersion(module.PathMajorPrefix(pathMajor), highestTag(out), g.time, g.commit[:12]), nil
+}
+
+// ModulePath returns the path of the module in the repository.
+func (g *gitModuleGetter) ModulePath() string {
+	return g.modulePath
+}
+
+func (g *gitModuleGetter) check(path, vers string) error {
+	if path != g.modulePath {
+		return fmt.Errorf("given module path %q does not match %q for repository %q: %w",
+			path, g.modulePath, g.repo, derrors.NotFound)
+	}
+	if vers != version.Latest && vers != g.version && vers != g.ref && vers != g.commit {
+		return fmt.Errorf("version %q of %q is not %q: %w", vers, path, g.version, derrors.NotFound)
+	}
+	return nil
+}
+
+// Info returns basic information about the module.
+func (g *gitModuleGetter) Info(ctx context.Context, path, vers string) (*proxy.VersionInfo, error) {
+	if err := g.check(path, vers); err != nil {
+		return nil, err
+	}
+	return &proxy.VersionInfo{
+		Version: g.version,
+		Time:    g.time,
+	}, nil
+}
+
+// Mod returns the contents of the module's go.mod file.
+// If the file does not exist, it returns a synthesized one.
+func (g *gitModuleGetter) Mod(ctx context.Context, path, vers string) ([]byte, error) {
+	if err := g.check(path, vers); err != nil {
+		return nil, err
+	}
+	return g.goMod, nil
+}
+
+// ContentDir returns an fs.FS for the module's contents.
+func (g *gitModuleGetter) ContentDir(ctx context.Context, path, vers string) (fs.FS, error) {
+	if err := g.check(path, vers); err != nil {
+		return nil, err
+	}
+	return g.zip, nil
+}
+
+// SourceInfo returns a source.Info that will link to the files of the
+// commit, under /files/repo/modulePath@version.
+func (g *gitModuleGetter) SourceInfo(ctx context.Context, _, _ string) (*source.Info, error) {
+	return source.FilesInfo(g.fileServingPath()), nil
+}
+
+// SourceFS returns the path under which the files of the commit are
+// served, along with an FS for serving them.
+func (g *gitModuleGetter) SourceFS() (string, fs.FS) {
+	return g.fileServingPath(), g.zip
+}
+
+func (g *gitModuleGetter) fileServingPath() string {
+	return path.Join(filepath.ToSlash(g.repo), g.modulePath+"@"+g.version)
+}
+
+// For testing.
+func (g *gitModuleGetter) String() string {
+	return fmt.Sprintf("Git(%s, %s, %s)", g.modulePath, g.repo, g.ref)
+}
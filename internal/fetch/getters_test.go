@@ -6,17 +6,28 @@ package fetch
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
+	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/mod/sumdb"
+	"golang.org/x/mod/sumdb/note"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/checksum"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/proxy"
+	"golang.org/x/pkgsite/internal/proxy/proxytest"
+	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/testenv"
 	"golang.org/x/pkgsite/internal/testing/testhelper"
 	"golang.org/x/pkgsite/internal/version"
@@ -330,3 +341,187 @@ func TestFSProxyGetter(t *testing.T) {
 		}
 	})
 }
+
+func TestGitModuleGetter(t *testing.T) {
+	testenv.MustHaveExecPath(t, "git")
+	ctx := context.Background()
+
+	dir, _ := testhelper.WriteTxtarToTempDir(t, `
+-- go.mod --
+module example.com/gitmod
+
+go 1.21
+-- p/p.go --
+// Package p is a package.
+package p
+
+const P = 1
+`)
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir,
+			"-c", "user.name=gopher", "-c", "user.email=gopher@example.com"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git("init", "-q")
+	git("add", ".")
+	git("commit", "-q", "-m", "first")
+	git("tag", "v1.0.0")
+
+	g, err := NewGitModuleGetter(ctx, "", dir, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := g.modulePath, "example.com/gitmod"; got != want {
+		t.Errorf("module path: got %q, want %q", got, want)
+	}
+	info, err := g.Info(ctx, g.modulePath, version.Latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := info.Version, "v1.0.0"; got != want {
+		t.Errorf("version: got %q, want %q", got, want)
+	}
+	mod, err := g.Mod(ctx, g.modulePath, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, err := os.ReadFile(filepath.Join(dir, "go.mod")); err != nil {
+		t.Fatal(err)
+	} else if !cmp.Equal(mod, want) {
+		t.Errorf("go.mod: got %q, want %q", mod, want)
+	}
+	fsys, err := g.ContentDir(ctx, g.modulePath, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.ReadFile(fsys, "p/p.go"); err != nil {
+		t.Error(err)
+	}
+	if _, err := g.Info(ctx, "other.com/mod", version.Latest); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("got %v, want NotFound", err)
+	}
+	if _, err := g.Info(ctx, g.modulePath, "v1.1.0"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("got %v, want NotFound", err)
+	}
+
+	// An untagged commit gets a pseudo-version based on the previous tag.
+	if err := os.WriteFile(filepath.Join(dir, "p", "q.go"), []byte("package p\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", ".")
+	git("commit", "-q", "-m", "second")
+	g, err = NewGitModuleGetter(ctx, "", dir, "HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !version.IsPseudo(g.version) || !strings.HasPrefix(g.version, "v1.0.1-0.") {
+		t.Errorf("got version %q, want pseudo-version after v1.0.0", g.version)
+	}
+	if _, err := fs.ReadFile(g.zip, "p/q.go"); err != nil {
+		t.Error(err)
+	}
+	// The commit can be requested by its hash, and resolves to the
+	// pseudo-version.
+	info, err = g.Info(ctx, g.modulePath, g.commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Version != g.version {
+		t.Errorf("version at commit %s: got %q, want %q", g.commit, info.Version, g.version)
+	}
+
+	if _, err := NewGitModuleGetter(ctx, "", dir, "nosuchref"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("got %v, want NotFound", err)
+	}
+}
+
+func TestProxyModuleGetterChecksum(t *testing.T) {
+	ctx := context.Background()
+	files := map[string]string{
+		"go.mod": "module example.com/good\n",
+		"p.go":   "// Package p is a package.\npackage p\n",
+	}
+	badFiles := map[string]string{
+		"go.mod": "module example.com/bad\n",
+		"p.go":   "// Package p is a package.\npackage p\n",
+	}
+	proxyClient, teardownProxy := proxytest.SetupTestClient(t, []*proxytest.Module{
+		{ModulePath: "example.com/good", Version: "v1.0.0", Files: files},
+		{ModulePath: "example.com/bad", Version: "v1.0.0", Files: badFiles},
+	})
+	defer teardownProxy()
+
+	// The checksum database has the right hashes for example.com/good, and
+	// wrong ones for example.com/bad.
+	gosum := func(path, vers string) ([]byte, error) {
+		if path == "example.com/bad" {
+			return fmt.Appendf(nil, "%[1]s %[2]s h1:bad=\n%[1]s %[2]s/go.mod h1:bad=\n", path, vers), nil
+		}
+		zr, err := proxyClient.Zip(ctx, path, vers)
+		if err != nil {
+			return nil, err
+		}
+		contentDir, err := fs.Sub(zr, path+"@"+vers)
+		if err != nil {
+			return nil, err
+		}
+		goMod, err := proxyClient.Mod(ctx, path, vers)
+		if err != nil {
+			return nil, err
+		}
+		zipHash, goModHash, err := checksum.Hashes(path, vers, contentDir, goMod)
+		if err != nil {
+			return nil, err
+		}
+		return fmt.Appendf(nil, "%[1]s %[2]s %[3]s\n%[1]s %[2]s/go.mod %[4]s\n", path, vers, zipHash, goModHash), nil
+	}
+	skey, vkey, err := note.GenerateKey(rand.Reader, "sum.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(sumdb.NewServer(sumdb.NewTestServer(skey, gosum)))
+	defer srv.Close()
+	db, err := checksum.New(vkey+" "+srv.URL, srv.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		modulePath      string
+		requireVerified bool
+		wantStatus      internal.ChecksumStatus
+		wantErr         error
+	}{
+		{"example.com/good", false, internal.ChecksumVerified, nil},
+		{"example.com/good", true, internal.ChecksumVerified, nil},
+		{"example.com/bad", false, internal.ChecksumMismatch, nil},
+		{"example.com/bad", true, "", derrors.BadModule},
+	} {
+		t.Run(fmt.Sprintf("%s,%t", test.modulePath, test.requireVerified), func(t *testing.T) {
+			mg := NewCheckedProxyModuleGetter(proxyClient, source.NewClientForTesting(), db, test.requireVerified)
+			fr := FetchModule(ctx, test.modulePath, "v1.0.0", mg)
+			if test.wantErr != nil {
+				if !errors.Is(fr.Error, test.wantErr) {
+					t.Fatalf("got error %v, want %v", fr.Error, test.wantErr)
+				}
+				return
+			}
+			if fr.Error != nil {
+				t.Fatal(fr.Error)
+			}
+			c := fr.Module.Checksum
+			if c == nil {
+				t.Fatal("no checksum")
+			}
+			if c.Status != test.wantStatus {
+				t.Errorf("got status %q, want %q", c.Status, test.wantStatus)
+			}
+			if !strings.HasPrefix(c.ZipHash, "h1:") || !strings.HasPrefix(c.GoModHash, "h1:") {
+				t.Errorf("got hashes %q and %q, want h1 hashes", c.ZipHash, c.GoModHash)
+			}
+		})
+	}
+}
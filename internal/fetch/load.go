@@ -13,6 +13,7 @@ import (
 	"go/ast"
 	"go/build"
 	"go/parser"
+	"go/scanner"
 	"go/token"
 	"io"
 	"io/fs"
@@ -20,6 +21,7 @@ import (
 	"os"
 	"path"
 	"sort"
+	"strconv"
 	"strings"
 
 	"golang.org/x/pkgsite/internal"
@@ -41,11 +43,62 @@ type BadPackageError struct {
 
 func (bpe *BadPackageError) Error() string { return bpe.Err.Error() }
 
+// maxDiagnostics is the maximum number of diagnostics recorded for a package.
+const maxDiagnostics = 10
+
+// packageDiagnostics returns the diagnostics for err, which was returned
+// when loading the package in the directory innerPath. File names in the
+// diagnostics are relative to the module root. It returns nil if err does
+// not contain a *BadPackageError.
+func packageDiagnostics(innerPath string, err error) []*internal.PackageDiagnostic {
+	var bpe *BadPackageError
+	if !errors.As(err, &bpe) {
+		return nil
+	}
+	var (
+		el  scanner.ErrorList
+		mpe *build.MultiplePackageError
+	)
+	switch {
+	case errors.As(bpe.Err, &el):
+		var diags []*internal.PackageDiagnostic
+		for _, e := range el {
+			if len(diags) == maxDiagnostics {
+				break
+			}
+			diags = append(diags, &internal.PackageDiagnostic{
+				File:    path.Join(innerPath, e.Pos.Filename),
+				Line:    e.Pos.Line,
+				Column:  e.Pos.Column,
+				Message: e.Msg,
+			})
+		}
+		return diags
+	case errors.As(bpe.Err, &mpe):
+		// The files are visited in map order, so order the pair by file name
+		// to keep the diagnostic stable. Point at the second file.
+		pkgs, files := mpe.Packages, mpe.Files
+		if files[1] < files[0] {
+			pkgs = []string{pkgs[1], pkgs[0]}
+			files = []string{files[1], files[0]}
+		}
+		return []*internal.PackageDiagnostic{{
+			File: path.Join(innerPath, files[1]),
+			Message: fmt.Sprintf("found packages %s (%s) and %s (%s)",
+				pkgs[0], files[0], pkgs[1], files[1]),
+		}}
+	default:
+		return []*internal.PackageDiagnostic{{Message: bpe.Err.Error()}}
+	}
+}
+
 // loadPackage loads a Go package by calling loadPackageWithBuildContext, trying
 // several build contexts in turn. It returns a goPackage with documentation
 // information for each build context that results in a valid package, in the
 // same order that the build contexts are listed. If none of them result in a
-// package, then loadPackage returns nil, nil.
+// package, then loadPackage returns nil and the *BadPackageError of the first
+// build context whose files did not make up a valid package, or nil, nil if
+// there was no such build context.
 //
 // If a package is fine except that its documentation is too large, loadPackage
 // returns a goPackage whose err field is a non-nil error with godoc.ErrTooLarge in its chain.
@@ -73,7 +126,10 @@ func loadPackage(ctx context.Context, contentDir fs.FS, goFilePaths []string, in
 	}
 	v1path := internal.V1Path(importPath, modulePath)
 
-	var pkg *goPackage
+	var (
+		pkg    *goPackage
+		badErr error // first BadPackageError
+	)
 	// Parse the package for each build context.
 	// The documentation is determined by the set of matching files, so keep
 	// track of those to avoid duplication.
@@ -101,9 +157,10 @@ func loadPackage(ctx context.Context, contentDir fs.FS, goFilePaths []string, in
 				doc2.API = append(doc2.API, &s2)
 			}
 			pkg.docs = append(pkg.docs, &doc2)
+			pkg.docLinks[&doc2] = pkg.docLinks[doc]
 			continue
 		}
-		name, imports, synopsis, source, api, err := loadPackageForBuildContext(ctx,
+		name, imports, synopsis, source, api, skipped, links, err := loadPackageForBuildContext(ctx,
 			mfiles, innerPath, sourceInfo, modInfo)
 		for _, s := range api {
 			s.GOOS = bc.GOOS
@@ -116,6 +173,9 @@ func loadPackage(ctx context.Context, contentDir fs.FS, goFilePaths []string, in
 			continue
 		case errors.As(err, new(*BadPackageError)):
 			// This build context was bad, but maybe others aren't.
+			if badErr == nil {
+				badErr = err
+			}
 			continue
 		case errors.Is(err, godoc.ErrTooLarge):
 			// The doc for this build context is too large. To keep things
@@ -128,11 +188,13 @@ func loadPackage(ctx context.Context, contentDir fs.FS, goFilePaths []string, in
 				name:    name,
 				imports: imports,
 				docs: []*internal.Documentation{{
-					GOOS:     internal.All,
-					GOARCH:   internal.All,
-					Synopsis: synopsis,
-					Source:   source,
-					API:      api,
+					GOOS:            internal.All,
+					GOARCH:          internal.All,
+					Synopsis:        synopsis,
+					Source:          source,
+					SourceHash:      godoc.SourceHash(source),
+					API:             api,
+					SkippedExamples: skipped,
 				}},
 			}, nil
 		case err != nil:
@@ -141,11 +203,14 @@ func loadPackage(ctx context.Context, contentDir fs.FS, goFilePaths []string, in
 		default:
 			// No error.
 			if pkg == nil {
+				// Use the imports from the first successful build context.
 				pkg = &goPackage{
-					path:    importPath,
-					v1path:  v1path,
-					name:    name,
-					imports: imports, // Use the imports from the first successful build context.
+					path:        importPath,
+					v1path:      v1path,
+					name:        name,
+					imports:     imports,
+					testImports: loadTestImports(mfiles, importPath, imports),
+					docLinks:    map[*internal.Documentation][]*godoc.DocLink{},
 				}
 			}
 			// All the build contexts should use the same package name. Although
@@ -157,16 +222,23 @@ func loadPackage(ctx context.Context, contentDir fs.FS, goFilePaths []string, in
 				}
 			}
 			doc := &internal.Documentation{
-				GOOS:     bc.GOOS,
-				GOARCH:   bc.GOARCH,
-				Synopsis: synopsis,
-				Source:   source,
-				API:      api,
+				GOOS:            bc.GOOS,
+				GOARCH:          bc.GOARCH,
+				Synopsis:        synopsis,
+				Source:          source,
+				SourceHash:      godoc.SourceHash(source),
+				API:             api,
+				SkippedExamples: skipped,
+				ExcludedFiles:   excludedFiles(files, mfiles),
 			}
 			docsByFiles[filesKey] = doc
 			pkg.docs = append(pkg.docs, doc)
+			pkg.docLinks[doc] = links
 		}
 	}
+	if pkg == nil {
+		return nil, badErr
+	}
 	// If all the build contexts succeeded and had the same set of files, then
 	// assume that the package doc is valid for all build contexts. Represent
 	// this with a single Documentation whose GOOS and GOARCH are both "all".
@@ -204,7 +276,10 @@ func loadPackageMeta(ctx context.Context, contentDir fs.FS, goFilePaths []string
 		importPath = innerPath
 	}
 
-	var pkg *packageMeta
+	var (
+		pkg    *packageMeta
+		badErr error // first BadPackageError
+	)
 	// Try to load the package name for each build context. We're okay
 	// as long as all the build contexts that successfully loadPackageName agree
 	// on the package name.
@@ -224,6 +299,9 @@ func loadPackageMeta(ctx context.Context, contentDir fs.FS, goFilePaths []string
 			continue
 		case errors.As(err, new(*BadPackageError)):
 			// This build context was bad, but maybe others aren't.
+			if badErr == nil {
+				badErr = err
+			}
 			continue
 		case err != nil:
 			// Serious error. Fail.
@@ -246,7 +324,9 @@ func loadPackageMeta(ctx context.Context, contentDir fs.FS, goFilePaths []string
 			}
 		}
 	}
-
+	if pkg == nil {
+		return nil, badErr
+	}
 	return pkg, nil
 }
 
@@ -273,8 +353,9 @@ var httpPost = http.Post
 // .go files that have been verified to be of reasonable size and that match
 // the build context.
 //
-// It returns the package name, list of imports, the package synopsis, and the
-// serialized source (AST) for the package.
+// It returns the package name, list of imports, the package synopsis, the
+// serialized source (AST), the API, the skipped examples and the doc links
+// into the module for the package.
 //
 // It returns an error with NotFound in its chain if the directory doesn't
 // contain a Go package or all .go files have been excluded by constraints. A
@@ -284,13 +365,14 @@ var httpPost = http.Post
 // If it returns an error with ErrTooLarge in its chain, the other return values
 // are still valid.
 func loadPackageForBuildContext(ctx context.Context, files map[string][]byte, innerPath string, sourceInfo *source.Info, modInfo *godoc.ModuleInfo) (
-	name string, imports []string, synopsis string, source []byte, api []*internal.Symbol, err error) {
+	name string, imports []string, synopsis string, source []byte, api []*internal.Symbol, skipped []*internal.SkippedExample,
+	links []*godoc.DocLink, err error) {
 	modulePath := modInfo.ModulePath
 	defer derrors.Wrap(&err, "loadPackageWithBuildContext(files, %q, %q, %+v)", innerPath, modulePath, sourceInfo)
 
 	packageName, goFiles, fset, err := loadFilesWithBuildContext(innerPath, files)
 	if err != nil {
-		return "", nil, "", nil, nil, err
+		return "", nil, "", nil, nil, nil, nil, err
 	}
 	docPkg := godoc.NewPackage(fset, modInfo.ModulePackages)
 	for _, pf := range goFiles {
@@ -306,14 +388,56 @@ func loadPackageForBuildContext(ctx context.Context, files map[string][]byte, in
 	// Encode first, because Render messes with the AST.
 	src, err := docPkg.Encode(ctx)
 	if err != nil {
-		return "", nil, "", nil, nil, err
+		return "", nil, "", nil, nil, nil, nil, err
+	}
+	importPath := path.Join(modulePath, innerPath)
+	if modulePath == stdlib.ModulePath {
+		importPath = innerPath
+	}
+	if shouldVerifyEncoding(ctx, importPath) {
+		verifyEncoding(ctx, importPath, docPkg, src)
 	}
 
-	synopsis, imports, api, err = docPkg.DocInfo(ctx, innerPath, sourceInfo, modInfo)
+	synopsis, imports, api, skipped, links, err = docPkg.DocInfo(ctx, innerPath, sourceInfo, modInfo)
 	if err != nil {
-		return "", nil, "", nil, nil, err
+		return "", nil, "", nil, nil, nil, nil, err
 	}
-	return packageName, imports, synopsis, src, api, err
+	return packageName, imports, synopsis, src, api, skipped, links, err
+}
+
+// loadTestImports returns the sorted paths imported by the test files among
+// files that are not imported by the package itself. The package at
+// importPath, which external tests import, is omitted.
+func loadTestImports(files map[string][]byte, importPath string, imports []string) []string {
+	seen := map[string]bool{importPath: true}
+	for _, p := range imports {
+		seen[p] = true
+	}
+	var (
+		fset        = token.NewFileSet()
+		testImports []string
+	)
+	for name, b := range files {
+		if !strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		pf, err := parser.ParseFile(fset, name, b, parser.ImportsOnly)
+		if err != nil {
+			// The file was already parsed when loading the package, so this
+			// shouldn't happen.
+			continue
+		}
+		for _, spec := range pf.Imports {
+			p, err := strconv.Unquote(spec.Path.Value)
+			if err != nil || build.IsLocalImport(p) || seen[p] {
+				continue
+			}
+			seen[p] = true
+			testImports = append(testImports, p)
+		}
+	}
+	sort.Strings(testImports)
+	return testImports
 }
 
 // loadFilesWithBuildContext loads all the given Go files at innerPath. It
@@ -453,6 +577,23 @@ func matchingFiles(goos, goarch string, allFiles map[string][]byte) (matchedFile
 	return matchedFiles, nil
 }
 
+// excludedFiles returns the non-test files of allFiles that are not in
+// matchedFiles, with their build constraints, sorted by name.
+func excludedFiles(allFiles, matchedFiles map[string][]byte) []*internal.ExcludedFile {
+	var efs []*internal.ExcludedFile
+	for name, contents := range allFiles {
+		if _, ok := matchedFiles[name]; ok || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		efs = append(efs, &internal.ExcludedFile{
+			Name:            name,
+			BuildConstraint: godoc.FileBuildConstraint(name, contents),
+		})
+	}
+	sort.Slice(efs, func(i, j int) bool { return efs[i].Name < efs[j].Name })
+	return efs
+}
+
 // readFSFile reads up to limit bytes from path in fsys.
 func readFSFile(fsys fs.FS, path string, limit int64) (_ []byte, err error) {
 	defer derrors.Add(&err, "readFSFile(%q)", path)
@@ -5,9 +5,13 @@
 package fetch
 
 import (
+	"errors"
+	"go/build"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/testing/testhelper"
 )
 
@@ -89,3 +93,81 @@ func TestMatchingFiles(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadTestImports(t *testing.T) {
+	files := map[string][]byte{
+		"p.go": []byte(`package p
+
+import "fmt"
+`),
+		"p_test.go": []byte(`package p
+
+import (
+	"fmt"
+	"testing"
+)
+`),
+		"x_test.go": []byte(`package p_test
+
+import (
+	"testing"
+
+	"example.com/m/p"
+	"example.com/m/internal/testutil"
+	"./local"
+)
+`),
+	}
+	got := loadTestImports(files, "example.com/m/p", []string{"fmt"})
+	want := []string{"example.com/m/internal/testutil", "testing"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPackageDiagnostics(t *testing.T) {
+	// A file with more syntax errors than are recorded.
+	var src strings.Builder
+	src.WriteString("package p\n\nfunc f() {\n")
+	for i := 0; i < maxDiagnostics+5; i++ {
+		src.WriteString("\t_ = '\\0';\n")
+	}
+	src.WriteString("}\n")
+	_, _, _, err := loadFilesWithBuildContext("p", map[string][]byte{"p.go": []byte(src.String())})
+	got := packageDiagnostics("p", err)
+	if len(got) != maxDiagnostics {
+		t.Fatalf("got %d diagnostics, want %d", len(got), maxDiagnostics)
+	}
+	if d := got[0]; d.File != "p/p.go" || d.Line != 4 || d.Message == "" {
+		t.Errorf("got first diagnostic %+v, want one for p/p.go, line 4", d)
+	}
+
+	for _, test := range []struct {
+		name string
+		err  error
+		want []*internal.PackageDiagnostic
+	}{
+		{"not bad", errors.New("oops"), nil},
+		{
+			"multiple packages",
+			&BadPackageError{Err: &build.MultiplePackageError{
+				Dir:      "d",
+				Packages: []string{"a", "b"},
+				Files:    []string{"a.go", "b.go"},
+			}},
+			[]*internal.PackageDiagnostic{{File: "d/b.go", Message: "found packages a (a.go) and b (b.go)"}},
+		},
+		{
+			"other",
+			&BadPackageError{Err: errors.New(`more than one package name ("a" and "b")`)},
+			[]*internal.PackageDiagnostic{{Message: `more than one package name ("a" and "b")`}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := packageDiagnostics("d", test.err)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -32,13 +32,17 @@ type goPackage struct {
 	path              string
 	name              string
 	imports           []string
+	testImports       []string // imported only by tests
 	isRedistributable bool
 	licenseMeta       []*licenses.Metadata // metadata of applicable licenses
 	// v1path is the package path of a package with major version 1 in a given
 	// series.
 	v1path string
 	docs   []*internal.Documentation // doc for different build contexts
-	err    error                     // non-fatal error when loading the package (e.g. documentation is too large)
+	// docLinks are the doc links to symbols of the module in each of docs,
+	// which are checked once all the packages of the module are loaded.
+	docLinks map[*internal.Documentation][]*godoc.DocLink
+	err      error // non-fatal error when loading the package (e.g. documentation is too large)
 }
 
 // rel returns the relative path from the modulePath to the pkgPath
@@ -90,19 +94,21 @@ func extractPackage(ctx context.Context, modulePath, pkgPath string, contentDir
 	var (
 		status error
 		errMsg string
+		diags  []*internal.PackageDiagnostic
 	)
 	pkg, err := loadPackage(ctx, contentDir, goFiles, innerPath, sourceInfo, modInfo)
 	if bpe := (*BadPackageError)(nil); errors.As(err, &bpe) {
 		log.Infof(ctx, "Error loading %s: %v", innerPath, err)
 		status = derrors.PackageInvalidContents
 		errMsg = err.Error()
+		diags = packageDiagnostics(innerPath, err)
 	} else if err != nil {
 		return nil, nil, fmt.Errorf("unexpected error loading package: %v", err)
 	}
 
 	if pkg == nil {
 		// No package.
-		if len(goFiles) > 0 {
+		if len(goFiles) > 0 && status == nil {
 			// There were go files, but no build contexts matched them.
 			status = derrors.PackageBuildContextNotSupported
 		}
@@ -129,6 +135,7 @@ func extractPackage(ctx context.Context, modulePath, pkgPath string, contentDir
 		Version:     modInfo.ResolvedVersion,
 		Status:      derrors.ToStatus(status),
 		Error:       errMsg,
+		Diagnostics: diags,
 	}
 
 	return pkg, pvs, nil
@@ -299,6 +306,7 @@ func extractPackageMetas(ctx context.Context, modulePath, resolvedVersion string
 			var (
 				status error
 				errMsg string
+				diags  []*internal.PackageDiagnostic
 			)
 			pkg, err := loadPackageMeta(ctx, contentDir, goFiles, innerPath, modInfo)
 			if bpe := (*BadPackageError)(nil); errors.As(err, &bpe) {
@@ -308,13 +316,14 @@ func extractPackageMetas(ctx context.Context, modulePath, resolvedVersion string
 				mu.Unlock()
 				status = derrors.PackageInvalidContents
 				errMsg = err.Error()
+				diags = packageDiagnostics(innerPath, err)
 			} else if err != nil {
 				return fmt.Errorf("unexpected error loading package: %v", err)
 			}
 			var pkgPath string
 			if pkg == nil {
 				// No package.
-				if len(goFiles) > 0 {
+				if len(goFiles) > 0 && status == nil {
 					// There were go files, but no build contexts matched them.
 					mu.Lock()
 					incompleteDirs[innerPath] = true
@@ -337,6 +346,7 @@ func extractPackageMetas(ctx context.Context, modulePath, resolvedVersion string
 					Version:     resolvedVersion,
 					Status:      derrors.ToStatus(status),
 					Error:       errMsg,
+					Diagnostics: diags,
 				})
 				mu.Unlock()
 			}
@@ -6,14 +6,18 @@
 package fetch
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path"
+	"regexp"
+	"sort"
 	"strings"
 
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/text/language"
 )
 
 // extractReadme returns the file path and contents the unit's README,
@@ -68,8 +72,7 @@ func extractReadme(modulePath, dir, resolvedVersion string, contentDir fs.FS) (_
 			if readme != nil {
 				// Prefer READMEs written in markdown, since we style these on
 				// the frontend.
-				ext := path.Ext(readme.Filepath)
-				if ext == ".md" || ext == ".markdown" {
+				if isMarkdownReadme(readme.Filepath) {
 					continue
 				}
 			}
@@ -82,6 +85,178 @@ func extractReadme(modulePath, dir, resolvedVersion string, contentDir fs.FS) (_
 	return readme, nil
 }
 
+// maxLocalizedReadmes is the maximum number of localized READMEs extracted
+// for a unit.
+const maxLocalizedReadmes = 20
+
+// extractLocalizedReadmes returns the translations of the unit's README, such
+// as README.zh-CN.md, sorted by language. dir is the directory path prefixed
+// with the modulePath.
+func extractLocalizedReadmes(modulePath, dir string, contentDir fs.FS) (_ []*internal.Readme, err error) {
+	defer derrors.Wrap(&err, "extractLocalizedReadmes(%q, %q)", modulePath, dir)
+
+	innerPath := rel(dir, modulePath)
+	if strings.HasPrefix(innerPath, "_") {
+		return nil, nil
+	}
+	entries, err := fs.ReadDir(contentDir, innerPath)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	byLang := map[string]*internal.Readme{}
+	for _, e := range entries {
+		lang := readmeLang(e.Name())
+		if e.IsDir() || lang == "" {
+			continue
+		}
+		if r := byLang[lang]; r != nil && isMarkdownReadme(r.Filepath) {
+			// As for the main README, prefer markdown.
+			continue
+		}
+		if _, ok := byLang[lang]; !ok && len(byLang) == maxLocalizedReadmes {
+			continue
+		}
+		pathname := path.Join(innerPath, e.Name())
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		if info.Size() > MaxFileSize {
+			return nil, fmt.Errorf("file size %d exceeds max limit %d: %w", info.Size(), MaxFileSize, derrors.ModuleTooLarge)
+		}
+		c, err := readFSFile(contentDir, pathname, MaxFileSize)
+		if err != nil {
+			return nil, err
+		}
+		byLang[lang] = &internal.Readme{
+			Filepath: pathname,
+			Contents: string(c),
+			Lang:     lang,
+		}
+	}
+	var readmes []*internal.Readme
+	for _, r := range byLang {
+		readmes = append(readmes, r)
+	}
+	sort.Slice(readmes, func(i, j int) bool { return readmes[i].Lang < readmes[j].Lang })
+	return readmes, nil
+}
+
+const (
+	// docsDir is the directory of a unit whose Markdown files are shown in
+	// its Docs tab.
+	docsDir = "docs"
+
+	// maxDocsPages is the maximum number of docs pages extracted for a unit.
+	maxDocsPages = 20
+
+	// maxDocsPageSize is the maximum size of a docs page. Larger files are
+	// skipped.
+	maxDocsPageSize = 512 * 1024
+)
+
+// extractDocsPages returns the Markdown files under the docs directory of
+// the unit, in lexical order. dir is the directory path prefixed with the
+// modulePath. If md has patterns for docs pages, only the files whose paths
+// relative to the docs directory match one of them are returned.
+// Directories whose names start with "." or "_" are skipped.
+func extractDocsPages(modulePath, dir string, contentDir fs.FS, md *internal.AuthorMetadata) (_ []*internal.Readme, err error) {
+	defer derrors.Wrap(&err, "extractDocsPages(%q, %q)", modulePath, dir)
+
+	if md.DocsPagesDisabled() {
+		return nil, nil
+	}
+	innerPath := rel(dir, modulePath)
+	if strings.HasPrefix(innerPath, "_") {
+		return nil, nil
+	}
+	var patterns []string
+	if md != nil {
+		patterns = md.DocsPages
+	}
+	root := path.Join(innerPath, docsDir)
+	var pages []*internal.Readme
+	err = fs.WalkDir(contentDir, root, func(pathname string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if pathname == root && errors.Is(err, fs.ErrNotExist) {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() {
+			if pathname != root && (strings.HasPrefix(d.Name(), ".") || strings.HasPrefix(d.Name(), "_")) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !isMarkdownReadme(pathname) || !matchDocsPage(patterns, pathname[len(root)+1:]) {
+			return nil
+		}
+		if len(pages) == maxDocsPages {
+			return fs.SkipAll
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxDocsPageSize {
+			return nil
+		}
+		c, err := readFSFile(contentDir, pathname, maxDocsPageSize)
+		if err != nil {
+			return err
+		}
+		pages = append(pages, &internal.Readme{Filepath: pathname, Contents: string(c)})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// matchDocsPage reports whether name, a path relative to a docs directory,
+// matches one of patterns, or whether there are no patterns.
+func matchDocsPage(patterns []string, name string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// localizedReadmeRegexp matches the names of localized READMEs. It only
+// accepts language tags that start with a two-letter language code, so names
+// like "README.old.md" are not taken for translations.
+var localizedReadmeRegexp = regexp.MustCompile(`^(?i)README\.([a-z]{2}(?:[-_][a-z0-9]{2,8})*)(\.[^.]+)$`)
+
+// readmeLang returns the canonical language tag of a localized README with
+// the given file name, such as "zh-CN" for "README.zh_cn.md", or the empty
+// string if name isn't one.
+func readmeLang(name string) string {
+	m := localizedReadmeRegexp.FindStringSubmatch(name)
+	if m == nil || excludedReadmeExts[m[2]] {
+		return ""
+	}
+	tag, err := language.Parse(strings.ReplaceAll(m[1], "_", "-"))
+	if err != nil {
+		return ""
+	}
+	return tag.String()
+}
+
+func isMarkdownReadme(file string) bool {
+	ext := path.Ext(file)
+	return ext == ".md" || ext == ".markdown"
+}
+
 var excludedReadmeExts = map[string]bool{".go": true, ".vendor": true}
 
 // isReadme reports whether file is README or if the base name of file, with or
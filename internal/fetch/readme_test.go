@@ -7,8 +7,10 @@ package fetch
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io/fs"
 	"testing"
+	"testing/fstest"
 
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/pkgsite/internal"
@@ -224,3 +226,165 @@ func TestIsReadme(t *testing.T) {
 		}
 	}
 }
+
+func TestExtractLocalizedReadmes(t *testing.T) {
+	ctx := context.Background()
+
+	const modulePath = "github.com/my/module"
+	files := map[string]string{
+		"README.md":          "README",
+		"README.zh-CN.md":    "中文",
+		"README.zh-CN.txt":   "中文 (text)",
+		"README.pt_br.md":    "Português",
+		"README.old.md":      "old",
+		"README.ja.markdown": "日本語",
+		"foo/foo.go":         "package foo",
+		"foo/README.fr.md":   "Français",
+	}
+	proxyClient, teardownProxy := proxytest.SetupTestClient(t, []*proxytest.Module{
+		{ModulePath: modulePath, Files: files}})
+	defer teardownProxy()
+	reader, err := proxyClient.Zip(ctx, modulePath, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	contentDir, err := fs.Sub(reader, modulePath+"@v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		dir  string
+		want []*internal.Readme
+	}{
+		{
+			dir: modulePath,
+			want: []*internal.Readme{
+				{Filepath: "README.ja.markdown", Contents: "日本語", Lang: "ja"},
+				{Filepath: "README.pt_br.md", Contents: "Português", Lang: "pt-BR"},
+				{Filepath: "README.zh-CN.md", Contents: "中文", Lang: "zh-CN"},
+			},
+		},
+		{
+			dir: modulePath + "/foo",
+			want: []*internal.Readme{
+				{Filepath: "foo/README.fr.md", Contents: "Français", Lang: "fr"},
+			},
+		},
+		{
+			dir:  modulePath + "/bar",
+			want: nil,
+		},
+	} {
+		got, err := extractLocalizedReadmes(modulePath, test.dir, contentDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%s: mismatch (-want +got):\n%s", test.dir, diff)
+		}
+	}
+}
+
+func TestExtractDocsPages(t *testing.T) {
+	const modulePath = "github.com/my/module"
+	contentDir := fstest.MapFS{
+		"README.md":               {Data: []byte("README")},
+		"docs/index.md":           {Data: []byte("index")},
+		"docs/guide.markdown":     {Data: []byte("guide")},
+		"docs/notes.txt":          {Data: []byte("notes")},
+		"docs/api/errors.md":      {Data: []byte("errors")},
+		"docs/_drafts/next.md":    {Data: []byte("next")},
+		"docs/.vitepress/conf.md": {Data: []byte("config")},
+		"docs/big.md":             {Data: make([]byte, maxDocsPageSize+1)},
+		"foo/foo.go":              {Data: []byte("package foo")},
+		"foo/docs/foo.md":         {Data: []byte("foo")},
+	}
+
+	for _, test := range []struct {
+		name string
+		dir  string
+		md   *internal.AuthorMetadata
+		want []*internal.Readme
+	}{
+		{
+			name: "module",
+			dir:  modulePath,
+			want: []*internal.Readme{
+				{Filepath: "docs/api/errors.md", Contents: "errors"},
+				{Filepath: "docs/guide.markdown", Contents: "guide"},
+				{Filepath: "docs/index.md", Contents: "index"},
+			},
+		},
+		{
+			name: "package",
+			dir:  modulePath + "/foo",
+			want: []*internal.Readme{
+				{Filepath: "foo/docs/foo.md", Contents: "foo"},
+			},
+		},
+		{
+			name: "no docs",
+			dir:  modulePath + "/bar",
+			want: nil,
+		},
+		{
+			name: "patterns",
+			dir:  modulePath,
+			md:   &internal.AuthorMetadata{DocsPages: []string{"*.md", "api/*"}},
+			want: []*internal.Readme{
+				{Filepath: "docs/api/errors.md", Contents: "errors"},
+				{Filepath: "docs/index.md", Contents: "index"},
+			},
+		},
+		{
+			name: "disabled",
+			dir:  modulePath,
+			md:   &internal.AuthorMetadata{NoDocsPages: true},
+			want: nil,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := extractDocsPages(modulePath, test.dir, contentDir, test.md)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	many := fstest.MapFS{}
+	for i := range maxDocsPages + 1 {
+		many[fmt.Sprintf("docs/%02d.md", i)] = &fstest.MapFile{Data: []byte("page")}
+	}
+	got, err := extractDocsPages(modulePath, modulePath, many, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != maxDocsPages {
+		t.Errorf("got %d pages, want %d", len(got), maxDocsPages)
+	}
+}
+
+func TestReadmeLang(t *testing.T) {
+	for _, test := range []struct {
+		name, want string
+	}{
+		{"README.zh-CN.md", "zh-CN"},
+		{"readme.zh_cn.md", "zh-CN"},
+		{"README.ja.markdown", "ja"},
+		{"README.sr-Latn.txt", "sr-Latn"},
+		{"README.md", ""},
+		{"README.de", ""},
+		{"README.old.md", ""},
+		{"README.zz.md", ""},
+		{"README.fr.go", ""},
+		{"NOTREADME.fr.md", ""},
+	} {
+		if got := readmeLang(test.name); got != test.want {
+			t.Errorf("readmeLang(%q) = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"context"
+	"errors"
+	"hash/fnv"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/experiment"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// verifyEncodingPercent is the percentage of packages whose encoding is
+// verified when the verify-doc-encoding experiment is active. Packages are
+// picked by their import path, so that processing a module again verifies
+// the same ones.
+const verifyEncodingPercent = 10
+
+// Results of verifying the encoding of a package.
+const (
+	VerifyEncodingMatch    = "match"
+	VerifyEncodingMismatch = "mismatch"
+	VerifyEncodingError    = "error"
+)
+
+var recordVerifyEncoding func(ctx context.Context, result string)
+
+// SetVerifyEncodingRecorder sets a function to call with the result of each
+// verification of the encoding of a package, for example to record it in a
+// metric.
+func SetVerifyEncodingRecorder(f func(ctx context.Context, result string)) {
+	recordVerifyEncoding = f
+}
+
+// shouldVerifyEncoding reports whether the encoding of the package at
+// importPath should be verified.
+func shouldVerifyEncoding(ctx context.Context, importPath string) bool {
+	if !experiment.IsActive(ctx, internal.ExperimentVerifyDocEncoding) {
+		return false
+	}
+	h := fnv.New32a()
+	h.Write([]byte(importPath))
+	return h.Sum32()%100 < verifyEncodingPercent
+}
+
+// verifyEncoding checks src, the encoding of docPkg, against the other
+// encoding of docPkg, and records the result. Failures are logged but do not
+// affect processing: src is what is stored either way.
+func verifyEncoding(ctx context.Context, importPath string, docPkg *godoc.Package, src []byte) {
+	result := VerifyEncodingMatch
+	if err := docPkg.VerifyEncoding(ctx, src); err != nil {
+		if errors.Is(err, godoc.ErrEncodingMismatch) {
+			result = VerifyEncodingMismatch
+		} else {
+			result = VerifyEncodingError
+		}
+		log.Errorf(ctx, "verifying encoding of %s: %v", importPath, err)
+	}
+	if recordVerifyEncoding != nil {
+		recordVerifyEncoding(ctx, result)
+	}
+}
@@ -29,8 +29,9 @@ import (
 // FetchDataSource implements the internal.DataSource interface, by trying a list of
 // fetch.ModuleGetters to fetch modules and caching the results.
 type FetchDataSource struct {
-	opts  Options
-	cache *lru.Cache[internal.Modver, cacheEntry]
+	opts       Options
+	cache      *lru.Cache[internal.Modver, cacheEntry]
+	parseCache *ParseCache
 }
 
 // Options are parameters for creating a new FetchDataSource.
@@ -41,6 +42,13 @@ type Options struct {
 	// include a ProxyModuleGetter in Getters.
 	ProxyClientForLatest *proxy.Client
 	BypassLicenseCheck   bool
+	// ParseCache holds the parsed units of immutable module versions. If it
+	// is nil, a cache of DefaultParseCacheSize units is used. Share a
+	// ParseCache between FetchDataSources that fetch the same modules.
+	ParseCache *ParseCache
+	// If set, OnFetch is called with the getter that had each module that
+	// was fetched successfully.
+	OnFetch func(fetch.ModuleGetter)
 }
 
 // New creates a new FetchDataSource from the options.
@@ -51,9 +59,14 @@ func (o Options) New() *FetchDataSource {
 	// Copy getters slice so caller doesn't modify us.
 	opts.Getters = make([]fetch.ModuleGetter, len(opts.Getters))
 	copy(opts.Getters, o.Getters)
+	parseCache := opts.ParseCache
+	if parseCache == nil {
+		parseCache = NewParseCache(DefaultParseCacheSize)
+	}
 	return &FetchDataSource{
-		opts:  opts,
-		cache: cache,
+		opts:       opts,
+		cache:      cache,
+		parseCache: parseCache,
 	}
 }
 
@@ -83,28 +96,29 @@ func (ds *FetchDataSource) cachePut(g fetch.ModuleGetter, path, version string,
 	ds.cache.Put(internal.Modver{Path: path, Version: version}, cacheEntry{g, m, err})
 }
 
-// getModule gets the module at the given path and version. It first checks the
-// cache, and if it isn't there it then tries to fetch it.
-func (ds *FetchDataSource) getModule(ctx context.Context, modulePath, vers string) (_ *fetch.LazyModule, err error) {
+// getModule gets the module at the given path and version, and the getter
+// that fetched it. It first checks the cache, and if it isn't there it then
+// tries to fetch it.
+func (ds *FetchDataSource) getModule(ctx context.Context, modulePath, vers string) (_ *fetch.LazyModule, _ fetch.ModuleGetter, err error) {
 	defer derrors.Wrap(&err, "FetchDataSource.getModule(%q, %q)", modulePath, vers)
 
 	g, mod, err := ds.cacheGet(modulePath, vers)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if mod != nil {
 		// For getters supporting invalidation, check whether cached contents have
 		// changed.
 		v, ok := g.(fetch.VolatileModuleGetter)
 		if !ok {
-			return mod, nil
+			return mod, g, nil
 		}
 		hasChanged, err := v.HasChanged(ctx, mod.ModuleInfo)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		if !hasChanged {
-			return mod, nil
+			return mod, g, nil
 		}
 	}
 
@@ -131,7 +145,7 @@ func (ds *FetchDataSource) getModule(ctx context.Context, modulePath, vers strin
 			ds.cachePut(g, modulePath, m.Version, m, err)
 		}
 	}
-	return m, err
+	return m, g, err
 }
 
 // fetch fetches a module using the configured ModuleGetters.
@@ -148,6 +162,9 @@ func (ds *FetchDataSource) fetch(ctx context.Context, modulePath, version string
 			if ds.opts.BypassLicenseCheck {
 				m.IsRedistributable = true
 			}
+			if ds.opts.OnFetch != nil {
+				ds.opts.OnFetch(g)
+			}
 			return m, g, nil
 		}
 		if !errors.Is(m.Error, derrors.NotFound) {
@@ -176,11 +193,12 @@ func (ds *FetchDataSource) findModule(ctx context.Context, pkgPath, modulePath,
 	defer derrors.Wrap(&err, "FetchDataSource.findModule(%q, %q, %q)", pkgPath, modulePath, version)
 
 	if modulePath != internal.UnknownModulePath {
-		return ds.getModule(ctx, modulePath, version)
+		m, _, err := ds.getModule(ctx, modulePath, version)
+		return m, err
 	}
 	pkgPath = strings.TrimLeft(pkgPath, "/")
 	for _, modulePath := range internal.CandidateModulePaths(pkgPath) {
-		m, err := ds.getModule(ctx, modulePath, version)
+		m, _, err := ds.getModule(ctx, modulePath, version)
 		if err == nil {
 			return m, nil
 		}
@@ -207,38 +225,56 @@ func (ds *FetchDataSource) GetUnitMeta(ctx context.Context, path, requestedModul
 func (ds *FetchDataSource) GetUnit(ctx context.Context, um *internal.UnitMeta, fields internal.FieldSet, bc internal.BuildContext) (_ *internal.Unit, err error) {
 	defer derrors.Wrap(&err, "FetchDataSource.GetUnit(%q, %q)", um.Path, um.ModulePath)
 
-	m, err := ds.getModule(ctx, um.ModulePath, um.Version)
+	m, g, err := ds.getModule(ctx, um.ModulePath, um.Version)
 	if err != nil {
 		return nil, err
 	}
-	u, err := ds.findUnit(ctx, m, um.Path)
+	u, err := ds.findUnit(ctx, g, m, um.Path)
 	if u == nil {
 		return nil, fmt.Errorf("import path %s not found in module %s: %w", um.Path, um.ModulePath, derrors.NotFound)
 	}
 	// Return only the Documentation matching the given BuildContext, if any.
-	// Since we cache the module and its units, we have to copy this unit before we modify it.
+	// Since we cache units, we have to copy this unit before we modify it.
 	// It can be a shallow copy, since we're only modifying the Unit.Documentation field.
+	// The Documentation is copied too, because callers may modify it.
 	u2 := *u
 	if d := matchingDoc(u.Documentation, bc); d != nil {
-		u2.Documentation = []*internal.Documentation{d}
+		d2 := *d
+		u2.Documentation = []*internal.Documentation{&d2}
 	} else {
 		u2.Documentation = nil
 	}
 	return &u2, nil
 }
 
-// findUnit returns the unit with the given path in m, or nil if none.
-func (ds *FetchDataSource) findUnit(ctx context.Context, m *fetch.LazyModule, path string) (*internal.Unit, error) {
+// findUnit returns the unit with the given path in m, which was fetched with
+// g, or nil if none. The units of immutable module versions are parsed only
+// once, and must not be modified.
+func (ds *FetchDataSource) findUnit(ctx context.Context, g fetch.ModuleGetter, m *fetch.LazyModule, path string) (*internal.Unit, error) {
+	cacheable := isImmutable(g, m)
+	key := unitKey{
+		Modver:             internal.Modver{Path: m.ModulePath, Version: m.Version},
+		path:               path,
+		bypassLicenseCheck: ds.opts.BypassLicenseCheck,
+	}
+	if cacheable {
+		if u := ds.parseCache.get(key); u != nil {
+			return u, nil
+		}
+	}
 	unit, err := m.Unit(ctx, path)
-	ds.populateUnitSubdirectories(unit, m)
 	if err != nil {
 		return nil, err
 	}
+	ds.populateUnitSubdirectories(unit, m)
 	if ds.opts.BypassLicenseCheck {
 		unit.IsRedistributable = true
 	} else {
 		unit.RemoveNonRedistributableData()
 	}
+	if cacheable {
+		ds.parseCache.put(key, unit)
+	}
 	return unit, nil
 }
 
@@ -375,7 +411,11 @@ func (ds *FetchDataSource) Search(ctx context.Context, q string, opts internal.S
 			if err != nil {
 				return nil, err
 			}
-			results = append(results, rs...)
+			for _, r := range rs {
+				if opts.InScope(r.ModulePath, r.Version) {
+					results = append(results, r)
+				}
+			}
 		}
 	}
 	sort.Slice(results, func(i, j int) bool {
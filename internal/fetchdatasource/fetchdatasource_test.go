@@ -352,6 +352,7 @@ func TestLocalGetUnitMeta(t *testing.T) {
 					Version:           fetch.LocalVersion,
 					IsRedistributable: true,
 					HasGoMod:          true,
+					GoVersion:         "1.12",
 					SourceInfo:        sourceInfo,
 				},
 			},
@@ -367,6 +368,7 @@ func TestLocalGetUnitMeta(t *testing.T) {
 					Version:           fetch.LocalVersion,
 					IsRedistributable: true,
 					HasGoMod:          true,
+					GoVersion:         "1.12",
 					SourceInfo:        sourceInfo,
 				},
 			},
@@ -382,6 +384,7 @@ func TestLocalGetUnitMeta(t *testing.T) {
 					IsRedistributable: true,
 					Version:           fetch.LocalVersion,
 					HasGoMod:          true,
+					GoVersion:         "1.12",
 					SourceInfo:        sourceInfo,
 				},
 			},
@@ -397,6 +400,7 @@ func TestLocalGetUnitMeta(t *testing.T) {
 					Version:           fetch.LocalVersion,
 					IsRedistributable: true,
 					HasGoMod:          true,
+					GoVersion:         "1.12",
 					SourceInfo:        sourceInfo,
 				},
 			},
@@ -580,3 +584,56 @@ func TestCache(t *testing.T) {
 		}
 	}
 }
+
+func TestParseCache(t *testing.T) {
+	ctx, ds, teardown := setup(t, defaultTestModules, true)
+	defer teardown()
+
+	for _, test := range []struct {
+		path, modulePath string
+		wantCached       bool
+	}{
+		// From the proxy.
+		{"example.com/single/pkg", "example.com/single", true},
+		// From a local directory, which may change.
+		{"github.com/my/module/foo", "github.com/my/module", false},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			um, err := ds.GetUnitMeta(ctx, test.path, test.modulePath, version.Latest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			u1, err := ds.GetUnit(ctx, um, internal.AllFields, internal.BuildContext{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			key := unitKey{
+				Modver:             internal.Modver{Path: um.ModulePath, Version: um.Version},
+				path:               um.Path,
+				bypassLicenseCheck: true,
+			}
+			cached := ds.parseCache.get(key)
+			if got := cached != nil; got != test.wantCached {
+				t.Fatalf("cached: got %t, want %t", got, test.wantCached)
+			}
+			if cached == nil {
+				return
+			}
+
+			// A data source sharing the cache uses the parsed unit, and
+			// modifying the returned unit doesn't affect it.
+			ds2 := Options{Getters: ds.opts.Getters, BypassLicenseCheck: true, ParseCache: ds.parseCache}.New()
+			u1.Documentation[0].GOOS = "modified"
+			u2, err := ds2.GetUnit(ctx, um, internal.AllFields, internal.BuildContext{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u2.Documentation[0] == cached.Documentation[0] || u2.Documentation[0].GOOS == "modified" {
+				t.Error("GetUnit returned documentation shared with the cache")
+			}
+			if &u2.Documentation[0].Source[0] != &cached.Documentation[0].Source[0] {
+				t.Error("GetUnit did not use the cached unit")
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetchdatasource
+
+import (
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/fetch"
+	"golang.org/x/pkgsite/internal/lru"
+)
+
+// DefaultParseCacheSize is the number of units held by the ParseCache of a
+// FetchDataSource whose Options don't provide one.
+const DefaultParseCacheSize = 1000
+
+// A ParseCache holds the units parsed from module versions whose contents
+// never change, such as those from the proxy or the module cache. When
+// several served modules depend on the same module version, its packages
+// are parsed only once. A ParseCache may be shared by several
+// FetchDataSources, and is safe for concurrent use.
+type ParseCache struct {
+	units *lru.Cache[unitKey, *internal.Unit]
+}
+
+// NewParseCache returns a ParseCache that holds up to size units, evicting
+// the least recently used ones.
+func NewParseCache(size int) *ParseCache {
+	return &ParseCache{units: lru.New[unitKey, *internal.Unit](size)}
+}
+
+// unitKey identifies a unit in a ParseCache. Units are stored after their
+// non-redistributable data has been removed, unless the license check is
+// bypassed, so the key records which was done.
+type unitKey struct {
+	internal.Modver
+	path               string
+	bypassLicenseCheck bool
+}
+
+func (c *ParseCache) get(k unitKey) *internal.Unit {
+	u, _ := c.units.Get(k)
+	return u
+}
+
+func (c *ParseCache) put(k unitKey, u *internal.Unit) {
+	c.units.Put(k, u)
+}
+
+// isImmutable reports whether the contents of m, fetched with g, never
+// change, so that the units parsed from it can be cached. Modules read from
+// local directories can be edited while they are served.
+func isImmutable(g fetch.ModuleGetter, m *fetch.LazyModule) bool {
+	if _, ok := g.(fetch.VolatileModuleGetter); ok {
+		return false
+	}
+	return m.Version != fetch.LocalVersion
+}
@@ -0,0 +1,176 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/osv"
+	"golang.org/x/pkgsite/internal/vuln"
+)
+
+// maxAdvisorySize is the maximum size of the body of a request that
+// publishes an advisory.
+const maxAdvisorySize = 256 << 10
+
+// handlePublishAdvisory stores an advisory for vulnerabilities in modules
+// that the Go vulnerability database does not cover, for POST requests to
+// /advisories. The body is the advisory as a JSON OSV entry, and the request
+// must present an API key in the config.APIKeyHeader header that may publish
+// advisories for all the modules it affects. An advisory replaces an earlier
+// one with the same ID, if the key may publish that one too.
+//
+// Advisories are served with the Go vulnerability database by the frontend's
+// mirror of it, from its next sync.
+func (s *Server) handlePublishAdvisory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	db, ok := s.getDataSource(ctx).(internal.PostgresDB)
+	if !ok {
+		http.Error(w, "advisories are not supported", http.StatusNotFound)
+		return
+	}
+	ak, ok := requireAPIKey(w, r, db, "advisories")
+	if !ok {
+		return
+	}
+	var e osv.Entry
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdvisorySize)).Decode(&e); err != nil {
+		http.Error(w, fmt.Sprintf("invalid advisory: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := validateAdvisory(&e); err != nil {
+		http.Error(w, fmt.Sprintf("invalid advisory: %v", err), http.StatusBadRequest)
+		return
+	}
+	if m := unpublishableModule(ak, &e); m != "" {
+		http.Error(w, fmt.Sprintf("API key %q may not publish advisories for %s", ak.Name, m), http.StatusForbidden)
+		return
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	e.Published = now
+	prev, err := db.GetAdvisory(ctx, e.ID)
+	switch {
+	case err == nil:
+		if m := unpublishableModule(ak, prev.Entry); m != "" {
+			http.Error(w, fmt.Sprintf("API key %q may not replace %s, which affects %s", ak.Name, e.ID, m), http.StatusForbidden)
+			return
+		}
+		e.Published = prev.Entry.Published
+	case !errors.Is(err, derrors.NotFound):
+		log.Errorf(ctx, "advisories: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	e.Modified = now
+	if err := db.UpsertAdvisory(ctx, &internal.Advisory{Entry: &e, Publisher: ak.Name}); err != nil {
+		log.Errorf(ctx, "advisories: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	log.Infof(ctx, "advisories: stored %s from %q", e.ID, ak.Name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleWithdrawAdvisory deletes the advisory with the given ID, for DELETE
+// requests to /advisories/<ID>. The request must present an API key that may
+// publish the advisory.
+func (s *Server) handleWithdrawAdvisory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	db, ok := s.getDataSource(ctx).(internal.PostgresDB)
+	if !ok {
+		http.Error(w, "advisories are not supported", http.StatusNotFound)
+		return
+	}
+	ak, ok := requireAPIKey(w, r, db, "advisories")
+	if !ok {
+		return
+	}
+	id, ok := vuln.CanonicalPrivateID(strings.TrimPrefix(r.URL.Path, "/advisories/"))
+	if !ok {
+		http.Error(w, "want the ID of an advisory, as in /advisories/PRIV-2026-0001", http.StatusBadRequest)
+		return
+	}
+	a, err := db.GetAdvisory(ctx, id)
+	if errors.Is(err, derrors.NotFound) {
+		http.Error(w, fmt.Sprintf("there is no advisory %s", id), http.StatusNotFound)
+		return
+	}
+	if err == nil {
+		if m := unpublishableModule(ak, a.Entry); m != "" {
+			http.Error(w, fmt.Sprintf("API key %q may not withdraw %s, which affects %s", ak.Name, id, m), http.StatusForbidden)
+			return
+		}
+		err = db.DeleteAdvisory(ctx, id)
+	}
+	if err != nil {
+		log.Errorf(ctx, "advisories: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	log.Infof(ctx, "advisories: deleted %s for %q", id, ak.Name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateAdvisory checks that e can be served as an advisory, and sets the
+// ecosystem of its modules if it is missing.
+func validateAdvisory(e *osv.Entry) error {
+	if id, ok := vuln.CanonicalPrivateID(e.ID); !ok || id != e.ID {
+		return fmt.Errorf("ID %q must be like PRIV-2026-0001: uppercase letters and digits other than GO or CVE, a year, and a number of four or more digits", e.ID)
+	}
+	if e.Summary == "" && e.Details == "" {
+		return errors.New("missing summary and details")
+	}
+	if len(e.Affected) == 0 {
+		return errors.New("no affected modules")
+	}
+	for i := range e.Affected {
+		a := &e.Affected[i]
+		if err := module.CheckPath(a.Module.Path); err != nil {
+			return err
+		}
+		switch a.Module.Ecosystem {
+		case "":
+			a.Module.Ecosystem = osv.GoEcosystem
+		case osv.GoEcosystem:
+		default:
+			return fmt.Errorf("%s: ecosystem is %q, not %q", a.Module.Path, a.Module.Ecosystem, osv.GoEcosystem)
+		}
+		for _, rng := range a.Ranges {
+			if rng.Type != osv.RangeTypeSemver {
+				return fmt.Errorf("%s: range type is %q, not %q", a.Module.Path, rng.Type, osv.RangeTypeSemver)
+			}
+			for _, ev := range rng.Events {
+				for _, v := range []string{ev.Introduced, ev.Fixed} {
+					// OSV versions have no "v" prefix, and "0" is the start of time.
+					if v != "" && v != "0" && !semver.IsValid("v"+v) {
+						return fmt.Errorf("%s: %q is not a semantic version without a leading v", a.Module.Path, v)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// unpublishableModule returns the path of a module affected by e that ak may
+// not publish advisories for, or the empty string if there is none.
+func unpublishableModule(ak *internal.APIKey, e *osv.Entry) string {
+	for _, a := range e.Affected {
+		if !ak.CanPublishAdvisory(a.Module.Path) {
+			return a.Module.Path
+		}
+	}
+	return ""
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/osv"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/vuln"
+)
+
+func TestAdvisories(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	fds.InsertAPIKey("security", &internal.APIKey{ID: 1, Name: "Security", AdvisoryPrefixes: []string{"corp.example.com"}})
+	fds.InsertAPIKey("team", &internal.APIKey{ID: 2, Name: "Team", AdvisoryPrefixes: []string{"corp.example.com/team"}})
+	fds.InsertAPIKey("ci", &internal.APIKey{ID: 3, Name: "CI"})
+	_, mux := newTestServer(t, nil, ServerConfig{
+		Config:           &config.Config{PrivateAdvisories: true},
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	const advisory = `{
+		"id": "CORP-2026-0001",
+		"summary": "Remote code execution in corp.example.com/mod",
+		"affected": [{
+			"package": {"name": "corp.example.com/mod"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.2.3"}]}]
+		}]
+	}`
+	for _, test := range []struct {
+		name, method, path, key, body string
+		wantStatus                    int
+	}{
+		{"no key", "POST", "/advisories", "", advisory, http.StatusUnauthorized},
+		{"unknown key", "POST", "/advisories", "bad", advisory, http.StatusUnauthorized},
+		{"key without prefixes", "POST", "/advisories", "ci", advisory, http.StatusForbidden},
+		{"other prefix", "POST", "/advisories", "security", strings.ReplaceAll(advisory, "corp.example.com/mod", "example.com/mod"), http.StatusForbidden},
+		{"Go ID", "POST", "/advisories", "security", strings.Replace(advisory, "CORP", "GO", 1), http.StatusBadRequest},
+		{"bad version", "POST", "/advisories", "security", strings.Replace(advisory, "1.2.3", "v1.2.3", 1), http.StatusBadRequest},
+		{"no summary", "POST", "/advisories", "security", strings.Replace(advisory, `"summary"`, `"unknown"`, 1), http.StatusBadRequest},
+		{"ok", "POST", "/advisories", "security", advisory, http.StatusNoContent},
+		{"update", "POST", "/advisories", "security", advisory, http.StatusNoContent},
+		{"replace outside prefix", "POST", "/advisories", "team", strings.ReplaceAll(advisory, "corp.example.com/mod", "corp.example.com/team/mod"), http.StatusForbidden},
+		{"withdraw outside prefix", "DELETE", "/advisories/CORP-2026-0001", "team", "", http.StatusForbidden},
+		{"withdraw", "DELETE", "/advisories/corp-2026-0001", "security", "", http.StatusNoContent},
+		{"withdraw again", "DELETE", "/advisories/CORP-2026-0001", "security", "", http.StatusNotFound},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			if test.key != "" {
+				r.Header.Set(config.APIKeyHeader, test.key)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+			if w.Code != test.wantStatus {
+				t.Errorf("status = %d, want %d; body:\n%s", w.Code, test.wantStatus, w.Body)
+			}
+			if test.name == "update" {
+				a, err := fds.GetAdvisory(ctx, "CORP-2026-0001")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if a.Publisher != "Security" || a.Entry.Published.IsZero() || a.Entry.Modified.Before(a.Entry.Published) {
+					t.Errorf("got publisher %q, published %s, modified %s", a.Publisher, a.Entry.Published, a.Entry.Modified)
+				}
+				if got := a.Entry.Affected[0].Module.Ecosystem; got != osv.GoEcosystem {
+					t.Errorf("got ecosystem %q, want %q", got, osv.GoEcosystem)
+				}
+			}
+		})
+	}
+}
+
+func TestPrivateAdvisoryPage(t *testing.T) {
+	vc, err := vuln.NewInMemoryClient([]*osv.Entry{{
+		ID:       "CORP-2026-0001",
+		Summary:  "Remote code execution",
+		Affected: []osv.Affected{{Module: osv.Module{Path: "corp.example.com/mod", Ecosystem: osv.GoEcosystem}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	r := httptest.NewRequest("GET", "/search?q=corp-2026-0001", nil)
+	action, err := determineSearchAction(r, fakedatasource.New(), vc, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/vuln/CORP-2026-0001"; action.redirectURL != want {
+		t.Errorf("search redirects to %q, want %q", action.redirectURL, want)
+	}
+	u := httptest.NewRequest("GET", "/vuln/CORP-2026-0001", nil).URL
+	vp, err := newVulnPage(ctx, u, vc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := vp.page.(*VulnEntryPage).Entry.ID; got != "CORP-2026-0001" {
+		t.Errorf("vuln page shows %s, want CORP-2026-0001", got)
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/analysis"
+	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// maxAnalysisReportSize is the maximum size of the body of a request that
+// posts an analysis report.
+const maxAnalysisReportSize = 64 << 10
+
+// handleAnalysisReport stores the report of an external analyzer for a module
+// version, for POST requests to /analysis/<module>@<version>. The body is a
+// JSON analysis.Report, and the request must present an API key in the
+// config.APIKeyHeader header that may report on the module; see
+// internal.APIKey.CanReportAnalysis. The name of the key is shown with the
+// report. The report is stored in the data source of the namespace of the
+// module, if it is in one.
+//
+// A report replaces any earlier report of the same analyzer for the module
+// version. The cached pages of the module are purged soon after, so that the
+// report shows up on the analysis tab; see pagePurger.
+func (s *Server) handleAnalysisReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	keys, ok := s.getDataSource(ctx).(internal.PostgresDB)
+	if !ok {
+		http.Error(w, "analysis reports are not supported", http.StatusNotFound)
+		return
+	}
+	ak, ok := requireAPIKey(w, r, keys, "analysis")
+	if !ok {
+		return
+	}
+	modulePath, version, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/analysis/"), "@")
+	if !ok || module.Check(modulePath, version) != nil {
+		http.Error(w, "want a module path and a full semantic version, as in /analysis/example.com/mod@v1.2.3", http.StatusBadRequest)
+		return
+	}
+	if !ak.CanReportAnalysis(modulePath) {
+		http.Error(w, fmt.Sprintf("the API key may not post analysis reports for %s", modulePath), http.StatusForbidden)
+		return
+	}
+	db, ok := s.dataSourceForModule(ctx, modulePath).(internal.PostgresDB)
+	if !ok {
+		http.Error(w, "analysis reports are not supported for "+modulePath, http.StatusNotFound)
+		return
+	}
+	var report analysis.Report
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAnalysisReportSize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&report); err != nil {
+		http.Error(w, fmt.Sprintf("invalid report: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := analysis.Validate(&report); err != nil {
+		http.Error(w, fmt.Sprintf("invalid report: %v", err), http.StatusBadRequest)
+		return
+	}
+	report.ModulePath = modulePath
+	report.Version = version
+	report.Submitter = ak.Name
+	if err := db.UpsertAnalysisReport(ctx, &report); err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			http.Error(w, fmt.Sprintf("%s@%s has not been processed", modulePath, version), http.StatusNotFound)
+			return
+		}
+		log.Errorf(ctx, "analysis: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	log.Infof(ctx, "analysis: stored %s report for %s@%s from %q", report.Analyzer, modulePath, version, ak.Name)
+	if s.purger != nil {
+		s.purger.purge(modulePath)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireAPIKey returns the API key presented by r in the config.APIKeyHeader
+// header. If there is no valid key, it writes an error to w and returns
+// false. Errors looking up the key are logged with the given prefix.
+func requireAPIKey(w http.ResponseWriter, r *http.Request, db internal.PostgresDB, prefix string) (*internal.APIKey, bool) {
+	ctx := r.Context()
+	key := r.Header.Get(config.APIKeyHeader)
+	if key == "" {
+		http.Error(w, "missing API key", http.StatusUnauthorized)
+		return nil, false
+	}
+	ak, err := db.GetAPIKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			http.Error(w, "invalid API key", http.StatusUnauthorized)
+			return nil, false
+		}
+		log.Errorf(ctx, "%s: %v", prefix, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return nil, false
+	}
+	return ak, true
+}
+
+// AnalysisDetails contains the reports of external analyzers shown on the
+// analysis tab.
+type AnalysisDetails struct {
+	ModulePath string
+	Version    string
+	Reports    []*AnalysisReport
+}
+
+// AnalysisReport is an analysis.Report prepared for display.
+type AnalysisReport struct {
+	*analysis.Report
+	Title string // title of the analyzer
+	Date  string
+}
+
+// fetchAnalysisDetails returns the reports of external analyzers for the
+// module version of um.
+func fetchAnalysisDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta) (*AnalysisDetails, error) {
+	ad := &AnalysisDetails{ModulePath: um.ModulePath, Version: um.Version}
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return ad, nil
+	}
+	reports, err := db.GetAnalysisReports(ctx, um.ModulePath, um.Version)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reports {
+		title := r.Analyzer
+		// The analyzer may no longer be registered.
+		if a := analysis.Lookup(r.Analyzer); a != nil {
+			title = a.Title()
+		}
+		ad.Reports = append(ad.Reports, &AnalysisReport{
+			Report: r,
+			Title:  title,
+			Date:   absoluteTime(r.CreatedAt),
+		})
+	}
+	return ad, nil
+}
+
+// hasAnalysisReports reports whether there are reports of external analyzers
+// for the module version of um. Errors are logged, and treated as if there
+// were no reports.
+func hasAnalysisReports(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta) bool {
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return false
+	}
+	reports, err := db.GetAnalysisReports(ctx, um.ModulePath, um.Version)
+	if err != nil {
+		log.Errorf(ctx, "hasAnalysisReports(%q, %q): %v", um.ModulePath, um.Version, err)
+		return false
+	}
+	return len(reports) > 0
+}
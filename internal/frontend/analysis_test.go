@@ -0,0 +1,107 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestAnalysisReports(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, sample.Module("example.com/mod", "v1.0.0", sample.Suffix))
+	fds.InsertAPIKey("good", &internal.APIKey{ID: 1, Name: "Example CI", AnalysisPrefixes: []string{"example.com"}})
+	fds.InsertAPIKey("other", &internal.APIKey{ID: 3, Name: "Other CI", AnalysisPrefixes: []string{"example.com/other"}})
+	fds.InsertAPIKey("revoked", &internal.APIKey{ID: 2, Name: "Old CI", RevokedAt: time.Now()})
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	get := func(path string) string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s: status = %d, want 200", path, w.Code)
+		}
+		b, err := io.ReadAll(w.Result().Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	// Before any report is posted, the main page does not link to the
+	// analysis tab.
+	if body := get("/example.com/mod@v1.0.0/foo"); strings.Contains(body, "meta-link-analysis") {
+		t.Error("main page links to the analysis tab, but there are no reports")
+	}
+
+	const report = `{
+		"analyzer": "gosec",
+		"summary": "Found 3 issues.",
+		"metrics": [{"name": "low", "value": 2}, {"name": "high", "value": 1}],
+		"url": "https://ci.example.com/runs/42"
+	}`
+	for _, test := range []struct {
+		name       string
+		path, key  string
+		body       string
+		wantStatus int
+	}{
+		{"no key", "/analysis/example.com/mod@v1.0.0", "", report, http.StatusUnauthorized},
+		{"unknown key", "/analysis/example.com/mod@v1.0.0", "bad", report, http.StatusUnauthorized},
+		{"revoked key", "/analysis/example.com/mod@v1.0.0", "revoked", report, http.StatusUnauthorized},
+		{"key for other modules", "/analysis/example.com/mod@v1.0.0", "other", report, http.StatusForbidden},
+		{"no version", "/analysis/example.com/mod", "good", report, http.StatusBadRequest},
+		{"unknown analyzer", "/analysis/example.com/mod@v1.0.0", "good", `{"analyzer": "lint"}`, http.StatusBadRequest},
+		{"unknown metric", "/analysis/example.com/mod@v1.0.0", "good", `{"analyzer": "gosec", "metrics": [{"name": "critical", "value": 1}]}`, http.StatusBadRequest},
+		{"unknown field", "/analysis/example.com/mod@v1.0.0", "good", `{"analyzer": "gosec", "ModulePath": "example.com/other"}`, http.StatusBadRequest},
+		{"http url", "/analysis/example.com/mod@v1.0.0", "good", `{"analyzer": "gosec", "url": "http://ci.example.com"}`, http.StatusBadRequest},
+		{"unknown version", "/analysis/example.com/mod@v1.1.0", "good", report, http.StatusNotFound},
+		{"ok", "/analysis/example.com/mod@v1.0.0", "good", report, http.StatusNoContent},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", test.path, strings.NewReader(test.body))
+			if test.key != "" {
+				r.Header.Set(config.APIKeyHeader, test.key)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+			if w.Code != test.wantStatus {
+				t.Errorf("status = %d, want %d; body:\n%s", w.Code, test.wantStatus, w.Body)
+			}
+		})
+	}
+
+	if body := get("/example.com/mod@v1.0.0/foo"); !strings.Contains(body, `href="/example.com/mod@v1.0.0/foo?tab=analysis"`) {
+		t.Error("main page does not link to the analysis tab")
+	}
+	body := get("/example.com/mod@v1.0.0/foo?tab=analysis")
+	for _, want := range []string{
+		"Found 3 issues.",
+		"Posted by Example CI",
+		`href="https://ci.example.com/runs/42"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("analysis tab does not contain %q", want)
+		}
+	}
+	// Metrics are shown in the order of the analyzer.
+	if high, low := strings.Index(body, "<dt>high</dt>"), strings.Index(body, "<dt>low</dt>"); high < 0 || low < 0 || high > low {
+		t.Errorf("metrics high (at %d) and low (at %d) are missing or out of order", high, low)
+	}
+}
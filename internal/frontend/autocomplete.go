@@ -0,0 +1,150 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/lru"
+)
+
+const (
+	// minAutocompletePrefix is the shortest query for which suggestions are
+	// returned. Shorter prefixes match too many rows to be useful.
+	minAutocompletePrefix = 2
+
+	// maxAutocompleteSuggestions is the number of suggestions returned.
+	maxAutocompleteSuggestions = 10
+
+	// autocompleteCacheSize is the number of prefixes whose suggestions are
+	// kept in memory, across namespaces. Completions are requested on every keystroke, so the
+	// popular ones are served from the cache rather than the database.
+	autocompleteCacheSize = 5000
+
+	// autocompleteTTL is how long cached suggestions are used.
+	autocompleteTTL = 10 * time.Minute
+)
+
+// An autocompleteSuggestion is a suggestion returned by the /autocomplete
+// endpoint.
+type autocompleteSuggestion struct {
+	// Text is the query to search for.
+	Text string `json:"text"`
+	// URL is the URL of the package or symbol documentation.
+	URL string `json:"url"`
+}
+
+// cachedSuggestions are the suggestions for a prefix, and when they were
+// computed.
+type cachedSuggestions struct {
+	suggestions []autocompleteSuggestion
+	created     time.Time
+}
+
+// autocompleteKey identifies the suggestions for a prefix. Each namespace
+// has its own data source, so the same prefix has different suggestions in
+// each.
+type autocompleteKey struct {
+	namespace string // empty for the default data source
+	prefix    string
+}
+
+// autocompleteCache caches the suggestions for recently requested prefixes.
+type autocompleteCache struct {
+	cache *lru.Cache[autocompleteKey, cachedSuggestions]
+	now   func() time.Time // for testing
+}
+
+func newAutocompleteCache(size int) *autocompleteCache {
+	return &autocompleteCache{cache: lru.New[autocompleteKey, cachedSuggestions](size), now: time.Now}
+}
+
+// get returns the suggestions for key, calling compute and caching the
+// result if they are not in the cache or have expired.
+func (c *autocompleteCache) get(key autocompleteKey, compute func() ([]autocompleteSuggestion, error)) ([]autocompleteSuggestion, error) {
+	if cs, ok := c.cache.Get(key); ok && c.now().Sub(cs.created) < autocompleteTTL {
+		return cs.suggestions, nil
+	}
+	suggestions, err := compute()
+	if err != nil {
+		return nil, err
+	}
+	c.cache.Put(key, cachedSuggestions{suggestions: suggestions, created: c.now()})
+	return suggestions, nil
+}
+
+// serveAutocomplete serves a JSON list of completions for the query in the q
+// parameter.
+func (s *Server) serveAutocomplete(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveAutocomplete(%q)", r.FormValue("q"))
+
+	db, ok := ds.(internal.PostgresDB)
+	if !ok || ds.SearchSupport() != internal.FullSearch {
+		return &serrors.ServerError{Status: http.StatusNotFound}
+	}
+	prefix := strings.TrimSpace(r.FormValue("q"))
+	key := autocompleteKey{prefix: prefix}
+	ns := namespaceFromContext(r.Context())
+	if ns != nil {
+		key.namespace = ns.Name
+	}
+	suggestions := []autocompleteSuggestion{}
+	if len(prefix) >= minAutocompletePrefix {
+		suggestions, err = s.autocomplete.get(key, func() ([]autocompleteSuggestion, error) {
+			return autocompleteSuggestions(r.Context(), db, prefix)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(suggestions)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %v", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	// The suggestions of a namespace must not be stored by shared caches,
+	// which do not know which namespace a response is for.
+	cacheControl := "public"
+	if ns != nil {
+		cacheControl = "private"
+	}
+	w.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", cacheControl, int(autocompleteTTL.Seconds())))
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("w.Write: %v", err)
+	}
+	return nil
+}
+
+// autocompleteSuggestions returns the suggestions for prefix from db.
+func autocompleteSuggestions(ctx context.Context, db internal.PostgresDB, prefix string) ([]autocompleteSuggestion, error) {
+	results, err := db.GetAutocompleteSuggestions(ctx, prefix, maxAutocompleteSuggestions)
+	if err != nil {
+		return nil, err
+	}
+	suggestions := []autocompleteSuggestion{}
+	for _, r := range results {
+		if r.Symbol == "" {
+			suggestions = append(suggestions, autocompleteSuggestion{
+				Text: r.PackagePath,
+				URL:  "/" + r.PackagePath,
+			})
+			continue
+		}
+		suggestions = append(suggestions, autocompleteSuggestion{
+			Text: path.Base(r.PackagePath) + "." + r.Symbol,
+			URL:  "/" + r.PackagePath + "#" + r.Symbol,
+		})
+	}
+	return suggestions, nil
+}
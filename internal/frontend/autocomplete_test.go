@@ -0,0 +1,117 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestAutocompleteSuggestions(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	for _, mod := range []struct {
+		path, suffix string
+		imports      []string
+	}{
+		{"m.com/a", "foo", nil},
+		{"m.com/b", "bar", nil},
+		{"x.com/c", "p", []string{"m.com/b/bar"}},
+	} {
+		m := sample.Module(mod.path, sample.VersionString, mod.suffix)
+		pkg := m.Packages()[0]
+		pkg.Imports = mod.imports
+		pkg.Documentation[0].API = []*internal.Symbol{sample.Function}
+		fds.MustInsertModule(ctx, m)
+	}
+
+	for _, test := range []struct {
+		prefix string
+		want   []autocompleteSuggestion
+	}{
+		{"m.com/", []autocompleteSuggestion{
+			{Text: "m.com/b/bar", URL: "/m.com/b/bar"},
+			{Text: "m.com/a/foo", URL: "/m.com/a/foo"},
+		}},
+		{"Func", []autocompleteSuggestion{
+			{Text: "bar.Function", URL: "/m.com/b/bar#Function"},
+			{Text: "foo.Function", URL: "/m.com/a/foo#Function"},
+			{Text: "p.Function", URL: "/x.com/c/p#Function"},
+		}},
+		{"nothing", []autocompleteSuggestion{}},
+	} {
+		t.Run(test.prefix, func(t *testing.T) {
+			got, err := autocompleteSuggestions(ctx, fds, test.prefix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAutocompleteCache(t *testing.T) {
+	c := newAutocompleteCache(1)
+	now := time.Now()
+	c.now = func() time.Time { return now }
+
+	computed := 0
+	get := func(prefix string) {
+		t.Helper()
+		_, err := c.get(autocompleteKey{prefix: prefix}, func() ([]autocompleteSuggestion, error) {
+			computed++
+			return []autocompleteSuggestion{{Text: prefix}}, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(want int) {
+		t.Helper()
+		if computed != want {
+			t.Errorf("computed %d times, want %d", computed, want)
+		}
+	}
+
+	get("ab")
+	get("ab")
+	check(1)
+	// The cache holds one entry, so "ab" is evicted.
+	get("cd")
+	get("ab")
+	check(3)
+	// Expired entries are recomputed.
+	now = now.Add(autocompleteTTL)
+	get("ab")
+	check(4)
+}
+
+func TestServeAutocomplete(t *testing.T) {
+	_, handler := newTestServer(t, nil, ServerConfig{})
+	for _, q := range []string{"", "a", "example.com"} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/autocomplete?q="+q, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("q=%q: got status %d, want %d", q, w.Code, http.StatusOK)
+		}
+		if got, want := w.Header().Get("Content-Type"), "application/json"; got != want {
+			t.Errorf("q=%q: got Content-Type %q, want %q", q, got, want)
+		}
+		if got, want := strings.TrimSpace(w.Body.String()), "[]"; got != want {
+			t.Errorf("q=%q: got body %q, want %q", q, got, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"fmt"
+
+	"golang.org/x/pkgsite/internal"
+)
+
+// Checksums describes the checksums of a module version that were computed
+// when it was fetched, so that users can check that the module they build
+// with is the one that is documented.
+type Checksums struct {
+	// ZipLine and GoModLine are the lines of a go.sum file for the module
+	// zip and the go.mod file, such as "example.com/m v1.2.3 h1:...".
+	ZipLine, GoModLine string
+
+	// Verified is true if the checksums match those of the checksum
+	// database, and Status says so or what else was found.
+	Verified bool
+	Status   string
+
+	// OriginHash is the hash of the commit that the module proxy reports
+	// the version was created from, and ShortOriginHash its first 12
+	// characters. They are empty if that is unknown.
+	OriginHash, ShortOriginHash string
+}
+
+// moduleChecksums returns the Checksums of the module version of um, or nil
+// if they were not computed.
+func moduleChecksums(um *internal.UnitMeta) *Checksums {
+	c := um.Checksum
+	if c == nil || c.ZipHash == "" || c.GoModHash == "" {
+		return nil
+	}
+	cs := &Checksums{
+		ZipLine:    fmt.Sprintf("%s %s %s", um.ModulePath, um.Version, c.ZipHash),
+		GoModLine:  fmt.Sprintf("%s %s/go.mod %s", um.ModulePath, um.Version, c.GoModHash),
+		Verified:   c.Verified(),
+		OriginHash: c.OriginHash,
+	}
+	switch c.Status {
+	case internal.ChecksumVerified:
+		cs.Status = "Matches the checksum database"
+	case internal.ChecksumMismatch:
+		cs.Status = "Does not match the checksum database"
+	case internal.ChecksumNotFound:
+		cs.Status = "Not in the checksum database"
+	default:
+		cs.Status = "Not checked against the checksum database"
+	}
+	cs.ShortOriginHash = cs.OriginHash
+	if len(cs.ShortOriginHash) > 12 {
+		cs.ShortOriginHash = cs.ShortOriginHash[:12]
+	}
+	return cs
+}
@@ -0,0 +1,61 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestModuleChecksums(t *testing.T) {
+	um := func(c *internal.ModuleChecksum) *internal.UnitMeta {
+		return &internal.UnitMeta{
+			ModuleInfo: internal.ModuleInfo{ModulePath: "example.com/m", Version: "v1.2.3", Checksum: c},
+		}
+	}
+	for _, test := range []struct {
+		name string
+		in   *internal.ModuleChecksum
+		want *Checksums
+	}{
+		{"none", nil, nil},
+		{"no go.mod hash", &internal.ModuleChecksum{ZipHash: "h1:zip="}, nil},
+		{
+			name: "verified",
+			in: &internal.ModuleChecksum{
+				ZipHash:    "h1:zip=",
+				GoModHash:  "h1:mod=",
+				OriginHash: "0123456789abcdef0123456789abcdef01234567",
+				Status:     internal.ChecksumVerified,
+			},
+			want: &Checksums{
+				ZipLine:         "example.com/m v1.2.3 h1:zip=",
+				GoModLine:       "example.com/m v1.2.3/go.mod h1:mod=",
+				Verified:        true,
+				Status:          "Matches the checksum database",
+				OriginHash:      "0123456789abcdef0123456789abcdef01234567",
+				ShortOriginHash: "0123456789ab",
+			},
+		},
+		{
+			name: "not found",
+			in:   &internal.ModuleChecksum{ZipHash: "h1:zip=", GoModHash: "h1:mod=", Status: internal.ChecksumNotFound},
+			want: &Checksums{
+				ZipLine:   "example.com/m v1.2.3 h1:zip=",
+				GoModLine: "example.com/m v1.2.3/go.mod h1:mod=",
+				Status:    "Not in the checksum database",
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := moduleChecksums(um(test.in))
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/cookie"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend/page"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/queue"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+// ClaimStore stores module claims, and the settings that owners make through
+// them. It is implemented by *postgres.DB.
+type ClaimStore interface {
+	CreateModuleClaim(ctx context.Context, modulePath string) (string, *internal.ModuleClaim, error)
+	GetModuleClaim(ctx context.Context, id int64, secret string) (*internal.ModuleClaim, error)
+	VerifyModuleClaim(ctx context.Context, id int64, verifiedBy string) error
+	SetModuleClaimContactURL(ctx context.Context, id int64, contactURL string) error
+	GetModuleContactURL(ctx context.Context, modulePath string) (string, error)
+	HideModuleVersion(ctx context.Context, c *internal.ModuleClaim, version string) error
+	UnhideModuleVersion(ctx context.Context, c *internal.ModuleClaim, version string) error
+	GetHiddenModuleVersions(ctx context.Context, c *internal.ModuleClaim) ([]string, error)
+}
+
+const (
+	// claimChallengePrefix precedes the challenge of a claim in the DNS
+	// record or file that verifies it.
+	claimChallengePrefix = "pkgsite-claim="
+
+	// claimDNSLabel is prepended to the host of a module path to form the
+	// name of the TXT record that verifies a claim.
+	claimDNSLabel = "_pkgsite-challenge"
+
+	// claimFileName is the name of the file at the root of a module that
+	// verifies a claim.
+	claimFileName = ".pkgsite-claim"
+
+	// maxClaimFileSize is the number of bytes of claimFileName that are read.
+	maxClaimFileSize = 1 << 10
+)
+
+// ClaimPage contains data for the page to claim a module and manage its
+// settings.
+type ClaimPage struct {
+	page.BasePage
+	// Claim is the claim being managed, or nil on the page that starts a
+	// claim.
+	Claim *internal.ModuleClaim
+	// DNSName is the name of the TXT record that verifies the claim.
+	DNSName string
+	// FileName is the name of the file that verifies the claim.
+	FileName string
+	// ChallengeValue is the content of the TXT record or file.
+	ChallengeValue string
+	// HiddenVersions are the versions hidden by the owner.
+	HiddenVersions []string
+	// Message reports the result of an action.
+	Message string
+	// MessageIsError reports whether the action failed.
+	MessageIsError bool
+}
+
+// serveNewClaim serves the page to start a claim, for GET requests to
+// /claim.
+func (s *Server) serveNewClaim(w http.ResponseWriter, r *http.Request, _ internal.DataSource) error {
+	w.Header().Set("Cache-Control", "no-store")
+	s.servePage(r.Context(), w, "claim", ClaimPage{BasePage: s.newBasePage(r, "Claim a module")})
+	return nil
+}
+
+// handleNewClaim starts a claim of the module path in the form value
+// "module", for POST requests to /claim. The secret of the claim is stored in
+// a cookie, and the browser is redirected to the page of the claim.
+func (s *Server) handleNewClaim(w http.ResponseWriter, r *http.Request, _ internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "handleNewClaim")
+
+	modulePath := strings.TrimSpace(r.FormValue("module"))
+	if err := module.CheckPath(modulePath); err != nil {
+		return &serrors.ServerError{
+			Status:       http.StatusBadRequest,
+			ResponseText: fmt.Sprintf("Invalid module path: %v", err),
+		}
+	}
+	secret, c, err := s.claims.CreateModuleClaim(r.Context(), modulePath)
+	if err != nil {
+		return err
+	}
+	claimPath := fmt.Sprintf("/claim/%d", c.ID)
+	http.SetCookie(w, &http.Cookie{
+		Name:    cookie.ModuleClaim,
+		Value:   secret,
+		Path:    claimPath,
+		Expires: time.Now().Add(365 * 24 * time.Hour),
+		// TLS is terminated before requests reach the server, so r.TLS is
+		// always nil. Browsers accept secure cookies from localhost too.
+		Secure:   true,
+		HttpOnly: true,
+		// Only forms on this site can act on the claim.
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, claimPath, http.StatusSeeOther)
+	return nil
+}
+
+// serveClaim serves the page of a claim, for requests to /claim/<id>. The
+// page explains how to verify the claim, or shows the settings of the module
+// once the claim is verified.
+//
+// POST requests to /claim/<id>/<action> act on the claim, and then serve the
+// page with a message that reports the result.
+func (s *Server) serveClaim(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveClaim(%q)", r.URL.Path)
+
+	idStr, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/claim/"), "/")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || (action == "") != (r.Method == http.MethodGet) {
+		return &serrors.ServerError{Status: http.StatusNotFound}
+	}
+	ctx := r.Context()
+	c, err := s.getRequestClaim(r, id)
+	if err != nil {
+		return err
+	}
+	var (
+		msg        string
+		msgIsError bool
+	)
+	if action != "" {
+		msg, err = s.doClaimAction(ctx, r, ds, c, action)
+		var cerr *claimError
+		if errors.As(err, &cerr) {
+			msg, msgIsError = cerr.msg, true
+		} else if err != nil {
+			return err
+		}
+		// The action may have changed the claim.
+		if c, err = s.getRequestClaim(r, id); err != nil {
+			return err
+		}
+	}
+	p := ClaimPage{
+		BasePage:       s.newBasePage(r, "Claim of "+c.ModulePath),
+		Claim:          c,
+		DNSName:        claimDNSName(c.ModulePath),
+		FileName:       claimFileName,
+		ChallengeValue: claimChallengePrefix + c.Challenge,
+		Message:        msg,
+		MessageIsError: msgIsError,
+	}
+	if c.Verified() {
+		p.HiddenVersions, err = s.claims.GetHiddenModuleVersions(ctx, c)
+		if err != nil {
+			return err
+		}
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	s.servePage(ctx, w, "claim", p)
+	return nil
+}
+
+// getRequestClaim returns the claim with the given ID, if the request holds
+// its secret.
+func (s *Server) getRequestClaim(r *http.Request, id int64) (*internal.ModuleClaim, error) {
+	ck, err := r.Cookie(cookie.ModuleClaim)
+	if err != nil {
+		// Don't reveal whether the claim exists.
+		return nil, &serrors.ServerError{Status: http.StatusNotFound}
+	}
+	c, err := s.claims.GetModuleClaim(r.Context(), id, ck.Value)
+	if err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			return nil, &serrors.ServerError{Status: http.StatusNotFound}
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// claimError is a failed action on a claim, reported on the page of the
+// claim.
+type claimError struct {
+	msg string
+}
+
+func (e *claimError) Error() string { return e.msg }
+
+func claimErrorf(format string, args ...any) error {
+	return &claimError{fmt.Sprintf(format, args...)}
+}
+
+// doClaimAction performs the action on the claim c, and returns a message
+// that reports the result. If the action fails in a way the owner can fix, the
+// error is a *claimError, to be shown on the page.
+func (s *Server) doClaimAction(ctx context.Context, r *http.Request, ds internal.DataSource, c *internal.ModuleClaim, action string) (string, error) {
+	if action == "verify" {
+		if c.Verified() {
+			return "The claim is already verified.", nil
+		}
+		var err error
+		method := r.FormValue("method")
+		switch method {
+		case internal.ClaimVerifiedByDNS:
+			err = s.verifyClaimByDNS(ctx, c)
+		case internal.ClaimVerifiedByFile:
+			err = s.verifyClaimByFile(ctx, ds, c)
+		default:
+			return "", &serrors.ServerError{Status: http.StatusBadRequest}
+		}
+		if err != nil {
+			return "", err
+		}
+		if err := s.claims.VerifyModuleClaim(ctx, c.ID, method); err != nil {
+			return "", err
+		}
+		log.Infof(ctx, "claim %d of %s verified by %s", c.ID, c.ModulePath, method)
+		return "The claim is verified. You can now manage the settings of the module.", nil
+	}
+
+	if !c.Verified() {
+		return "", &serrors.ServerError{Status: http.StatusForbidden, ResponseText: "The claim is not verified."}
+	}
+	switch action {
+	case "contact":
+		contactURL := strings.TrimSpace(r.FormValue("contact_url"))
+		if contactURL != "" {
+			u, err := url.Parse(contactURL)
+			if err != nil || (u.Scheme != "https" && u.Scheme != "mailto") || (u.Scheme == "https" && u.Host == "") {
+				return "", claimErrorf("The contact link must be an https or mailto URL.")
+			}
+		}
+		if err := s.claims.SetModuleClaimContactURL(ctx, c.ID, contactURL); err != nil {
+			return "", err
+		}
+		s.purgeClaimedModule(ctx, c)
+		if contactURL == "" {
+			return "The contact link was removed.", nil
+		}
+		return "The contact link was saved.", nil
+
+	case "hide", "unhide":
+		v := strings.TrimSpace(r.FormValue("version"))
+		if err := module.Check(c.ModulePath, v); err != nil {
+			return "", claimErrorf("Invalid version: %v", err)
+		}
+		if action == "hide" {
+			if err := s.claims.HideModuleVersion(ctx, c, v); err != nil {
+				return "", err
+			}
+		} else if err := s.claims.UnhideModuleVersion(ctx, c, v); err != nil {
+			if errors.Is(err, derrors.NotFound) {
+				return "", claimErrorf("%s is not hidden.", v)
+			}
+			return "", err
+		}
+		s.purgeClaimedModule(ctx, c)
+		// Processing the version again removes it from the site if it is
+		// hidden, and restores it if not.
+		if err := s.scheduleClaimFetch(ctx, c, v); err != nil {
+			log.Errorf(ctx, "claim %d: scheduling fetch of %s@%s: %v", c.ID, c.ModulePath, v, err)
+		}
+		if action == "hide" {
+			return fmt.Sprintf("%s is hidden.", v), nil
+		}
+		return fmt.Sprintf("%s is no longer hidden. It will be shown again once it has been processed.", v), nil
+
+	case "reprocess":
+		v := strings.TrimSpace(r.FormValue("version"))
+		if v == "" {
+			um, err := ds.GetUnitMeta(ctx, c.ModulePath, c.ModulePath, version.Latest)
+			if err != nil {
+				if errors.Is(err, derrors.NotFound) {
+					return "", claimErrorf("%s has no versions on this site. Enter a version to process.", c.ModulePath)
+				}
+				return "", err
+			}
+			v = um.Version
+		} else if err := module.Check(c.ModulePath, v); err != nil {
+			return "", claimErrorf("Invalid version: %v", err)
+		}
+		if err := s.scheduleClaimFetch(ctx, c, v); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s@%s will be processed again.", c.ModulePath, v), nil
+
+	default:
+		return "", &serrors.ServerError{Status: http.StatusNotFound}
+	}
+}
+
+// claimDNSName returns the name of the TXT record that verifies a claim of
+// modulePath.
+func claimDNSName(modulePath string) string {
+	host, _, _ := strings.Cut(modulePath, "/")
+	return claimDNSLabel + "." + host
+}
+
+// verifyClaimByDNS checks that the TXT record for the host of the claimed
+// module path holds the challenge. Control of the host gives control of every
+// module path under it.
+func (s *Server) verifyClaimByDNS(ctx context.Context, c *internal.ModuleClaim) error {
+	name := claimDNSName(c.ModulePath)
+	records, err := s.lookupTXT(ctx, name)
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return claimErrorf("There is no TXT record for %s.", name)
+		}
+		return claimErrorf("Looking up the TXT record for %s failed: %v", name, err)
+	}
+	if !slices.Contains(records, claimChallengePrefix+c.Challenge) {
+		return claimErrorf("The TXT records for %s do not contain the challenge.", name)
+	}
+	return nil
+}
+
+// verifyClaimByFile checks that claimFileName at the root of the module, on
+// the default branch of its repository, holds the challenge. The repository
+// is the one recorded for the latest version of the module.
+func (s *Server) verifyClaimByFile(ctx context.Context, ds internal.DataSource, c *internal.ModuleClaim) error {
+	um, err := ds.GetUnitMeta(ctx, c.ModulePath, c.ModulePath, version.Latest)
+	if err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			return claimErrorf("%s has not been processed by this site, so its repository is not known. Verify the claim with a DNS record instead.", c.ModulePath)
+		}
+		return err
+	}
+	rawURL := um.SourceInfo.WithCommit("HEAD").RawURL(claimFileName)
+	if rawURL == "" {
+		return claimErrorf("Files cannot be read from the repository of %s. Verify the claim with a DNS record instead.", c.ModulePath)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.claimHTTPClient.Do(req)
+	if err != nil {
+		return claimErrorf("Reading %s failed: %v", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return claimErrorf("Reading %s failed: %s", rawURL, resp.Status)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxClaimFileSize))
+	if err != nil {
+		return claimErrorf("Reading %s failed: %v", rawURL, err)
+	}
+	if strings.TrimSpace(string(b)) != claimChallengePrefix+c.Challenge {
+		return claimErrorf("%s does not contain the challenge.", rawURL)
+	}
+	return nil
+}
+
+// scheduleClaimFetch schedules the version of the claimed module to be
+// processed again.
+func (s *Server) scheduleClaimFetch(ctx context.Context, c *internal.ModuleClaim, v string) error {
+	if s.queue == nil {
+		return claimErrorf("This site cannot process modules.")
+	}
+	_, err := s.queue.ScheduleFetch(ctx, c.ModulePath, v, &queue.Options{
+		// Make the task distinct from earlier ones, so it is not
+		// de-duplicated.
+		Suffix: fmt.Sprintf("claim-%d-%d", c.ID, time.Now().Unix()),
+		Source: queue.SourceFrontendValue,
+	})
+	return err
+}
+
+// purgeClaimedModule purges the cached pages of the claimed module, so that
+// changes to its settings show up right away.
+func (s *Server) purgeClaimedModule(ctx context.Context, c *internal.ModuleClaim) {
+	if s.pageCache == nil {
+		return
+	}
+	if _, err := s.pageCache.DeletePath(ctx, c.ModulePath); err != nil {
+		// The change will show up when the cached pages expire.
+		log.Errorf(ctx, "claim %d: purging cached pages of %s: %v", c.ID, c.ModulePath, err)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/cookie"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+// fakeClaimStore is an in-memory ClaimStore.
+type fakeClaimStore struct {
+	claims  []*internal.ModuleClaim
+	secrets []string
+	hidden  map[string]bool // module@version
+}
+
+func (s *fakeClaimStore) CreateModuleClaim(ctx context.Context, modulePath string) (string, *internal.ModuleClaim, error) {
+	id := int64(len(s.claims) + 1)
+	c := &internal.ModuleClaim{ID: id, ModulePath: modulePath, Challenge: fmt.Sprintf("challenge%d", id), CreatedAt: time.Now()}
+	s.claims = append(s.claims, c)
+	s.secrets = append(s.secrets, fmt.Sprintf("secret%d", id))
+	return s.secrets[id-1], c, nil
+}
+
+func (s *fakeClaimStore) GetModuleClaim(ctx context.Context, id int64, secret string) (*internal.ModuleClaim, error) {
+	if id < 1 || id > int64(len(s.claims)) || s.secrets[id-1] != secret {
+		return nil, derrors.NotFound
+	}
+	c := *s.claims[id-1]
+	return &c, nil
+}
+
+func (s *fakeClaimStore) VerifyModuleClaim(ctx context.Context, id int64, verifiedBy string) error {
+	s.claims[id-1].VerifiedBy = verifiedBy
+	s.claims[id-1].VerifiedAt = time.Now()
+	return nil
+}
+
+func (s *fakeClaimStore) SetModuleClaimContactURL(ctx context.Context, id int64, contactURL string) error {
+	s.claims[id-1].ContactURL = contactURL
+	return nil
+}
+
+func (s *fakeClaimStore) GetModuleContactURL(ctx context.Context, modulePath string) (string, error) {
+	for _, c := range s.claims {
+		if c.ModulePath == modulePath && c.Verified() && c.ContactURL != "" {
+			return c.ContactURL, nil
+		}
+	}
+	return "", nil
+}
+
+func (s *fakeClaimStore) HideModuleVersion(ctx context.Context, c *internal.ModuleClaim, version string) error {
+	s.hidden[c.ModulePath+"@"+version] = true
+	return nil
+}
+
+func (s *fakeClaimStore) UnhideModuleVersion(ctx context.Context, c *internal.ModuleClaim, version string) error {
+	if !s.hidden[c.ModulePath+"@"+version] {
+		return derrors.NotFound
+	}
+	delete(s.hidden, c.ModulePath+"@"+version)
+	return nil
+}
+
+func (s *fakeClaimStore) GetHiddenModuleVersions(ctx context.Context, c *internal.ModuleClaim) ([]string, error) {
+	var vs []string
+	for mv := range s.hidden {
+		if v, ok := strings.CutPrefix(mv, c.ModulePath+"@"); ok {
+			vs = append(vs, v)
+		}
+	}
+	slices.Sort(vs)
+	return vs, nil
+}
+
+// roundTripFunc is an http.RoundTripper implemented by a function.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestModuleClaims(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, sample.Module("example.com/mod", "v1.0.0", sample.Suffix))
+	store := &fakeClaimStore{hidden: map[string]bool{}}
+	s, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+		Claims:           store,
+	})
+	txtRecords := map[string][]string{}
+	s.lookupTXT = func(_ context.Context, name string) ([]string, error) {
+		return txtRecords[name], nil
+	}
+	files := map[string]string{}
+	s.claimHTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		body, ok := files[r.URL.String()]
+		if !ok {
+			return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: http.NoBody}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})}
+
+	do := func(method, path, secret string, form url.Values) *httptest.ResponseRecorder {
+		t.Helper()
+		r := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+		if form != nil {
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		if secret != "" {
+			r.AddCookie(&http.Cookie{Name: cookie.ModuleClaim, Value: secret})
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w
+	}
+	checkPage := func(w *httptest.ResponseRecorder, wants ...string) {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200; body:\n%s", w.Code, w.Body)
+		}
+		for _, want := range wants {
+			if !strings.Contains(w.Body.String(), want) {
+				t.Errorf("page does not contain %q", want)
+			}
+		}
+	}
+	// claim starts a claim of modulePath and returns its path and secret.
+	claim := func(modulePath string) (string, string) {
+		t.Helper()
+		w := do("POST", "/claim", "", url.Values{"module": {modulePath}})
+		if w.Code != http.StatusSeeOther {
+			t.Fatalf("POST /claim: status = %d, want 303", w.Code)
+		}
+		cookies := w.Result().Cookies()
+		if len(cookies) != 1 || cookies[0].Name != cookie.ModuleClaim || !cookies[0].HttpOnly || !cookies[0].Secure {
+			t.Fatalf("POST /claim: got cookies %v, want one secure HttpOnly %s cookie", cookies, cookie.ModuleClaim)
+		}
+		loc := w.Header().Get("Location")
+		if cookies[0].Path != loc {
+			t.Errorf("cookie path = %q, want %q", cookies[0].Path, loc)
+		}
+		return loc, cookies[0].Value
+	}
+
+	checkPage(do("GET", "/claim", "", nil), `action="/claim"`)
+	if w := do("POST", "/claim", "", url.Values{"module": {"not a path"}}); w.Code != http.StatusBadRequest {
+		t.Errorf("POST /claim with an invalid path: status = %d, want 400", w.Code)
+	}
+
+	path, secret := claim("example.com/mod")
+	for _, bad := range []string{"", "wrong"} {
+		if w := do("GET", path, bad, nil); w.Code != http.StatusNotFound {
+			t.Errorf("GET %s with secret %q: status = %d, want 404", path, bad, w.Code)
+		}
+	}
+	checkPage(do("GET", path, secret, nil), "_pkgsite-challenge.example.com", "pkgsite-claim=challenge1")
+
+	// Settings cannot be changed before the claim is verified.
+	if w := do("POST", path+"/hide", secret, url.Values{"version": {"v1.0.0"}}); w.Code != http.StatusForbidden {
+		t.Errorf("hide before verification: status = %d, want 403", w.Code)
+	}
+
+	checkPage(do("POST", path+"/verify", secret, url.Values{"method": {"dns"}}), "do not contain the challenge")
+	txtRecords["_pkgsite-challenge.example.com"] = []string{"v=spf1 -all", "pkgsite-claim=challenge1"}
+	checkPage(do("POST", path+"/verify", secret, url.Values{"method": {"dns"}}), "The claim is verified.")
+
+	checkPage(do("POST", path+"/contact", secret, url.Values{"contact_url": {"javascript:alert(1)"}}), "must be an https or mailto URL")
+	checkPage(do("POST", path+"/contact", secret, url.Values{"contact_url": {"https://example.com/support"}}), "The contact link was saved.")
+	checkPage(do("GET", "/example.com/mod@v1.0.0/foo", "", nil), `href="https://example.com/support"`, "Contact the owner")
+
+	checkPage(do("POST", path+"/hide", secret, url.Values{"version": {"1.0"}}), "Invalid version")
+	checkPage(do("POST", path+"/hide", secret, url.Values{"version": {"v1.0.0"}}), "v1.0.0 is hidden.", `data-test-id="claim-hidden"`)
+	if !store.hidden["example.com/mod@v1.0.0"] {
+		t.Error("v1.0.0 was not hidden")
+	}
+	checkPage(do("POST", path+"/unhide", secret, url.Values{"version": {"v1.0.0"}}), "v1.0.0 is no longer hidden.")
+	checkPage(do("POST", path+"/unhide", secret, url.Values{"version": {"v1.0.0"}}), "v1.0.0 is not hidden.")
+
+	// There is no queue.
+	checkPage(do("POST", path+"/reprocess", secret, url.Values{}), "This site cannot process modules.")
+
+	// Verify a second claim with a file in the repository.
+	path2, secret2 := claim("example.com/mod")
+	checkPage(do("POST", path2+"/verify", secret2, url.Values{"method": {"file"}}), "404 Not Found")
+	files["https://example.com/mod/raw/HEAD/.pkgsite-claim"] = "pkgsite-claim=challenge2\n"
+	checkPage(do("POST", path2+"/verify", secret2, url.Values{"method": {"file"}}), "The claim is verified.")
+	// The secret of one claim does not give access to another.
+	if w := do("GET", path2, secret, nil); w.Code != http.StatusNotFound {
+		t.Errorf("GET %s with the secret of %s: status = %d, want 404", path2, path, w.Code)
+	}
+}
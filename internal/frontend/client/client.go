@@ -7,13 +7,17 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strings"
+	"time"
 
 	"golang.org/x/pkgsite/internal/auth"
 	"golang.org/x/pkgsite/internal/derrors"
@@ -28,21 +32,131 @@ type Client struct {
 
 	// Client used for HTTP requests.
 	httpClient *http.Client
+
+	retries int
+	timeout time.Duration
+}
+
+// Options configure a Client.
+type Options struct {
+	// HTTPClient is used for requests. If nil, http.DefaultClient is used.
+	// To reach an instance behind Identity-Aware Proxy, use a client from
+	// auth.NewClient.
+	HTTPClient *http.Client
+	// Headers are added to every request, such as an Authorization header
+	// or an API key.
+	Headers map[string]string
+	// Retries is the number of times a request is retried after a network
+	// error, a timeout or a response with status 429 or 5xx.
+	Retries int
+	// Timeout, if positive, limits the time of each attempt at a request.
+	Timeout time.Duration
 }
 
 // New creates a new frontend client. This is only used for tests.
+//
+// If the GO_DISCOVERY_FRONTEND_AUTHORIZATION environment variable is set,
+// its value is sent as a bearer token with each request.
 func New(url string) *Client {
-	tok, ok := os.LookupEnv("GO_DISCOVERY_FRONTEND_AUTHORIZATION")
+	return NewWithOptions(url, &Options{})
+}
+
+// NewWithOptions creates a new frontend client with the given options. The
+// GO_DISCOVERY_FRONTEND_AUTHORIZATION environment variable is honored as in
+// New, unless opts.Headers has an Authorization header.
+func NewWithOptions(url string, opts *Options) *Client {
 	c := &Client{
 		url:        url,
-		httpClient: http.DefaultClient,
+		httpClient: opts.HTTPClient,
+		retries:    opts.Retries,
+		timeout:    opts.Timeout,
+	}
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	headers := map[string]string{}
+	if tok, ok := os.LookupEnv("GO_DISCOVERY_FRONTEND_AUTHORIZATION"); ok {
+		headers["Authorization"] = fmt.Sprintf("Bearer %s", tok)
+	}
+	for h, v := range opts.Headers {
+		headers[http.CanonicalHeaderKey(h)] = v
 	}
-	if ok {
-		c.httpClient = auth.NewClientBearer(tok)
+	if len(headers) > 0 {
+		base := c.httpClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		hc := *c.httpClient
+		hc.Transport = &auth.HeadersTransport{Base: base, Headers: headers}
+		c.httpClient = &hc
 	}
 	return c
 }
 
+// Flags are command-line flags that configure a Client.
+type Flags struct {
+	headers headerFlag
+	iap     string
+	retries int
+	timeout time.Duration
+}
+
+// AddFlags defines flags on fs that configure a Client, for commands that
+// need to reach protected instances of the frontend.
+func AddFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{headers: headerFlag{}}
+	fs.Var(f.headers, "header",
+		`add the header "Name: value" to each request to the frontend; may be repeated`)
+	fs.StringVar(&f.iap, "iap", "",
+		`authenticate to the frontend through Identity-Aware Proxy with default credentials; "main" or "exp" selects the project`)
+	fs.IntVar(&f.retries, "retries", 0,
+		"number of times to retry failed requests to the frontend")
+	fs.DurationVar(&f.timeout, "timeout", 0,
+		"time limit for each request to the frontend (0 for none)")
+	return f
+}
+
+// Options returns the Options described by the flags.
+func (f *Flags) Options(ctx context.Context) (_ *Options, err error) {
+	defer derrors.Wrap(&err, "Flags.Options")
+	opts := &Options{
+		Headers: f.headers,
+		Retries: f.retries,
+		Timeout: f.timeout,
+	}
+	switch f.iap {
+	case "":
+	case "main", "exp":
+		opts.HTTPClient, err = auth.NewClient(ctx, nil, f.iap == "exp")
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf(`-iap: got %q, want "main" or "exp"`, f.iap)
+	}
+	return opts, nil
+}
+
+// headerFlag is a flag.Value that collects "Name: value" headers.
+type headerFlag map[string]string
+
+func (h headerFlag) String() string {
+	var hs []string
+	for k, v := range h {
+		hs = append(hs, k+": "+v)
+	}
+	return strings.Join(hs, ", ")
+}
+
+func (h headerFlag) Set(s string) error {
+	name, value, ok := strings.Cut(s, ":")
+	if !ok || strings.TrimSpace(name) == "" {
+		return fmt.Errorf("header %q is not of the form \"Name: value\"", s)
+	}
+	h[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	return nil
+}
+
 // GetVersions returns a VersionsDetails for the specified pkgPath.
 // This is only used for tests.
 func (c *Client) GetVersions(pkgPath string) (_ *versions.VersionsDetails, err error) {
@@ -76,17 +190,42 @@ func (c *Client) Search(q, mode string) (_ *frontend.SearchPage, err error) {
 
 func (c *Client) fetchJSONPage(url string) (_ []byte, err error) {
 	defer derrors.Wrap(&err, "fetchJSONPage(%q)", url)
-	r, err := c.httpClient.Get(url)
+	backoff := 500 * time.Millisecond
+	for attempt := 0; ; attempt++ {
+		body, retry, err := c.get(url)
+		if err == nil || !retry || attempt >= c.retries {
+			return body, err
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// get makes a single attempt at fetching url. It reports whether a failed
+// attempt should be retried.
+func (c *Client) get(url string) (_ []byte, retry bool, err error) {
+	ctx := context.Background()
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+	r, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, true, err
 	}
 	defer r.Body.Close()
 	if r.StatusCode != http.StatusOK {
-		return nil, errors.New(r.Status)
+		retry := r.StatusCode == http.StatusTooManyRequests || r.StatusCode >= 500
+		return nil, retry, errors.New(r.Status)
 	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		return nil, err
+		return nil, true, err
 	}
-	return body, nil
+	return body, false, nil
 }
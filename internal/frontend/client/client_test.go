@@ -0,0 +1,128 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHeaders(t *testing.T) {
+	t.Setenv("GO_DISCOVERY_FRONTEND_AUTHORIZATION", "envtoken")
+	var got http.Header
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header
+		w.Write([]byte("{}"))
+	}))
+	defer ts.Close()
+
+	c := NewWithOptions(ts.URL, &Options{Headers: map[string]string{"x-api-key": "key"}})
+	if _, err := c.fetchJSONPage(ts.URL); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := got.Get("Authorization"), "Bearer envtoken"; got != want {
+		t.Errorf("Authorization: got %q, want %q", got, want)
+	}
+	if got, want := got.Get("X-Api-Key"), "key"; got != want {
+		t.Errorf("X-Api-Key: got %q, want %q", got, want)
+	}
+
+	// A header from the options replaces the one from the environment.
+	c = NewWithOptions(ts.URL, &Options{Headers: map[string]string{"Authorization": "Bearer flagtoken"}})
+	if _, err := c.fetchJSONPage(ts.URL); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := got.Get("Authorization"), "Bearer flagtoken"; got != want {
+		t.Errorf("Authorization: got %q, want %q", got, want)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		status    int
+		retries   int
+		wantCalls int32
+		wantErr   bool
+	}{
+		{"unavailable then ok", http.StatusServiceUnavailable, 2, 2, false},
+		{"no retries", http.StatusServiceUnavailable, 0, 1, true},
+		{"not found is not retried", http.StatusNotFound, 2, 1, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var calls atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Fail the first call only.
+				if calls.Add(1) == 1 {
+					http.Error(w, "failed", test.status)
+					return
+				}
+				w.Write([]byte("{}"))
+			}))
+			defer ts.Close()
+			c := NewWithOptions(ts.URL, &Options{Retries: test.retries})
+			_, err := c.fetchJSONPage(ts.URL)
+			if (err != nil) != test.wantErr {
+				t.Errorf("got error %v, want error: %t", err, test.wantErr)
+			}
+			if got := calls.Load(); got != test.wantCalls {
+				t.Errorf("got %d calls, want %d", got, test.wantCalls)
+			}
+		})
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	done := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	c := NewWithOptions(ts.URL, &Options{Timeout: 10 * time.Millisecond})
+	if _, err := c.fetchJSONPage(ts.URL); err == nil {
+		t.Error("got nil, want timeout error")
+	}
+}
+
+func TestFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	f := AddFlags(fs)
+	if err := fs.Parse([]string{
+		"-header", "Authorization: Bearer tok",
+		"-header", "X-Api-Key:key",
+		"-retries", "3",
+		"-timeout", "5s",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := f.Options(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Options{
+		Headers: map[string]string{"Authorization": "Bearer tok", "X-Api-Key": "key"},
+		Retries: 3,
+		Timeout: 5 * time.Second,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := fs.Parse([]string{"-header", "no colon"}); err == nil {
+		t.Error("malformed header: got nil, want error")
+	}
+}
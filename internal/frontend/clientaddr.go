@@ -0,0 +1,35 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// clientAddr returns the address of the client that made r. If the server is
+// behind s.trustedProxyHops proxies, it is the entry of the X-Forwarded-For
+// header that the outermost of them added; the entries before it come from
+// the client and can't be trusted. Otherwise, or if the header has too few
+// entries, it is the peer address of the connection.
+func (s *Server) clientAddr(r *http.Request) string {
+	if s.trustedProxyHops > 0 {
+		var addrs []string
+		for _, h := range r.Header.Values("X-Forwarded-For") {
+			addrs = append(addrs, strings.Split(h, ",")...)
+		}
+		if i := len(addrs) - s.trustedProxyHops; i >= 0 {
+			if addr := strings.TrimSpace(addrs[i]); addr != "" {
+				return addr
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
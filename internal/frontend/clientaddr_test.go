@@ -0,0 +1,36 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientAddr(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		hops int
+		xff  []string
+		want string
+	}{
+		{"no proxies", 0, []string{"1.2.3.4"}, "192.0.2.1"},
+		{"one proxy", 1, []string{"6.6.6.6, 1.2.3.4"}, "1.2.3.4"},
+		{"two proxies", 2, []string{"6.6.6.6, 1.2.3.4", "10.0.0.1"}, "1.2.3.4"},
+		{"too few entries", 2, []string{"1.2.3.4"}, "192.0.2.1"},
+		{"no header", 1, nil, "192.0.2.1"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			s := &Server{trustedProxyHops: test.hops}
+			r := httptest.NewRequest("GET", "/", nil) // RemoteAddr is 192.0.2.1:1234
+			for _, h := range test.xff {
+				r.Header.Add("X-Forwarded-For", h)
+			}
+			if got := s.clientAddr(r); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/frontend/page"
 	"golang.org/x/pkgsite/internal/frontend/serrors"
 	"golang.org/x/pkgsite/internal/frontend/urlinfo"
@@ -97,3 +99,53 @@ func checkExcluded(ctx context.Context, ds internal.DataSource, fullPath, versio
 	}
 	return nil
 }
+
+// defaultTakedownMessage is shown in place of the pages of a module that is
+// taken down without a message of its own.
+const defaultTakedownMessage = "This module version has been taken down and is no longer available on this site."
+
+var takedownMessageTemplate = template.MakeTrustedTemplate(
+	`<h3 class="Error-message">{{.Path}} is not available.</h3>
+	<p class="Error-message">{{.Message}}</p>`)
+
+// checkModuleAvailable returns an error if the module version modulePath@version
+// may not be served, because it is excluded or taken down. Every handler that
+// serves a page or data for a single module version calls it once the version
+// is resolved. The path is the requested unit path, shown on the tombstone
+// page of a takedown.
+func checkModuleAvailable(ctx context.Context, ds internal.DataSource, path, modulePath, version string) error {
+	if err := checkExcluded(ctx, ds, modulePath, version); err != nil {
+		return err
+	}
+	return checkTakedown(ctx, ds, path, modulePath, version)
+}
+
+// checkTakedown returns an error that serves a tombstone page if the module
+// version modulePath@version is taken down.
+func checkTakedown(ctx context.Context, ds internal.DataSource, path, modulePath, version string) error {
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return nil
+	}
+	t, err := db.GetTakedown(ctx, modulePath, version)
+	if errors.Is(err, derrors.NotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	msg := t.Message
+	if msg == "" {
+		msg = defaultTakedownMessage
+	}
+	return &serrors.ServerError{
+		Status: http.StatusUnavailableForLegalReasons,
+		Epage: &page.ErrorPage{
+			MessageTemplate: takedownMessageTemplate,
+			MessageData: struct{ Path, Message string }{
+				Path:    path + "@" + version,
+				Message: msg,
+			},
+		},
+	}
+}
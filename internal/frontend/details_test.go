@@ -5,7 +5,15 @@
 package frontend
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
 )
 
 func TestStdlibRedirectURL(t *testing.T) {
@@ -28,3 +36,47 @@ func TestStdlibRedirectURL(t *testing.T) {
 		}
 	}
 }
+
+func TestTakedown(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	for _, m := range []struct{ path, version string }{
+		{"example.com/all", "v1.0.0"},
+		{"example.com/one", "v1.0.0"},
+		{"example.com/one", "v1.1.0"},
+	} {
+		fds.MustInsertModule(ctx, sample.Module(m.path, m.version, "pkg"))
+	}
+	fds.AddTakedown(&internal.Takedown{ModulePath: "example.com/all", Message: "Removed at the request of its author."})
+	fds.AddTakedown(&internal.Takedown{ModulePath: "example.com/one", Version: "v1.0.0"})
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	for _, test := range []struct {
+		path        string
+		wantStatus  int
+		wantMessage string
+	}{
+		{"/example.com/all/pkg@v1.0.0", http.StatusUnavailableForLegalReasons, "Removed at the request of its author."},
+		{"/example.com/all/pkg", http.StatusUnavailableForLegalReasons, "Removed at the request of its author."},
+		{"/example.com/one/pkg@v1.0.0?tab=versions", http.StatusUnavailableForLegalReasons, defaultTakedownMessage},
+		{"/example.com/one/pkg@v1.1.0", http.StatusOK, ""},
+		{"/tree/example.com/all@v1.0.0", http.StatusUnavailableForLegalReasons, ""},
+		{"/tree/example.com/one@v1.1.0", http.StatusOK, ""},
+		{"/sbom/example.com/one@v1.0.0", http.StatusUnavailableForLegalReasons, ""},
+		{"/modgraph/example.com/one@v1.0.0", http.StatusUnavailableForLegalReasons, ""},
+		{"/switch-version?path=example.com/one/pkg&module=example.com/one&version=v1.0.0", http.StatusUnavailableForLegalReasons, ""},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if w.Code != test.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, test.wantStatus)
+			}
+			if test.wantMessage != "" && !strings.Contains(w.Body.String(), test.wantMessage) {
+				t.Errorf("body does not contain %q", test.wantMessage)
+			}
+		})
+	}
+}
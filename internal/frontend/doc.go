@@ -6,29 +6,66 @@ package frontend
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"path"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
+	"github.com/google/safehtml"
+	"github.com/google/safehtml/uncheckedconversions"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docrender"
+	"golang.org/x/pkgsite/internal/frontend/page"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
 	"golang.org/x/pkgsite/internal/godoc"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/godoc/docllms"
+	"golang.org/x/pkgsite/internal/godoc/docmarkdown"
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/middleware/stats"
+	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/stdlib"
 )
 
 func renderDocParts(ctx context.Context, u *internal.Unit, docPkg *godoc.Package,
-	nameToVersion map[string]string, bc internal.BuildContext) (_ *dochtml.Parts, err error) {
+	nameToVersion map[string]string, bc internal.BuildContext, expandExamples bool) (_ *dochtml.Parts, err error) {
 	defer derrors.Wrap(&err, "renderDocParts")
-	defer stats.Elapsed(ctx, "renderDocParts")()
+	defer stats.ElapsedIn(ctx, stats.StageRender, "renderDocParts")()
 
+	key, ok := newDocPartsKey(u, nameToVersion, bc, expandExamples)
+	if !ok {
+		return renderDocPartsAt(ctx, u, docPkg, u.Version, u.SourceInfo, nameToVersion, bc, expandExamples)
+	}
+	commit := u.SourceInfo.Commit()
+	if parts, ok := docPartsCache.Get(key); ok {
+		return fillDocParts(parts, u.Version, commit), nil
+	}
+	parts, err := renderDocPartsAt(ctx, u, docPkg, docVersionPlaceholder,
+		u.SourceInfo.WithCommit(docCommitPlaceholder), nameToVersion, bc, expandExamples)
+	if err != nil {
+		return nil, err
+	}
+	docPartsCache.Put(key, parts)
+	return fillDocParts(parts, u.Version, commit), nil
+}
+
+// renderDocPartsAt renders the documentation of u as if it were at the given
+// version and had the given source info.
+func renderDocPartsAt(ctx context.Context, u *internal.Unit, docPkg *godoc.Package, version string,
+	sourceInfo *source.Info, nameToVersion map[string]string, bc internal.BuildContext, expandExamples bool) (*dochtml.Parts, error) {
 	modInfo := &godoc.ModuleInfo{
 		ModulePath:      u.ModulePath,
-		ResolvedVersion: u.Version,
+		ResolvedVersion: version,
 		ModulePackages:  nil, // will be provided by docPkg
+		GoVersion:       u.GoVersion,
+		NoIssueLinks:    u.AuthorMetadata.IssueLinksDisabled(),
+		LicenseTypes:    unitLicenseTypes(u),
+		BrokenDocLinks:  u.Documentation[0].BrokenDocLinks,
 	}
 	var innerPath string
 	if u.ModulePath == stdlib.ModulePath {
@@ -36,25 +73,119 @@ func renderDocParts(ctx context.Context, u *internal.Unit, docPkg *godoc.Package
 	} else if u.Path != u.ModulePath {
 		innerPath = u.Path[len(u.ModulePath)+1:]
 	}
-	return docPkg.Render(ctx, innerPath, u.SourceInfo, modInfo, nameToVersion, bc)
+	return docPkg.Render(ctx, innerPath, sourceInfo, modInfo, nameToVersion, bc, expandExamples)
 }
 
-// sourceFiles returns the .go files for a package.
-func sourceFiles(u *internal.Unit, docPkg *godoc.Package) []*File {
+// serveUnitMarkdown serves the documentation of the package um as Markdown,
+// for the ?m=md form of a package page, as JSON in the form of
+// docjson.Package, for the ?m=json form, or as a digest in the style of
+// llms.txt, for the ?m=llms form. Links to other packages in Markdown are
+// absolute, relative to baseURL, so that the documentation can be used
+// elsewhere.
+func serveUnitMarkdown(ctx context.Context, w http.ResponseWriter, r *http.Request, ds internal.DataSource,
+	um *internal.UnitMeta, bc, defaultBC internal.BuildContext, baseURL string) (err error) {
+	defer derrors.Wrap(&err, "serveUnitMarkdown(%q, %q, %q)", um.Path, um.ModulePath, um.Version)
+	defer stats.Elapsed(ctx, "serveUnitMarkdown")()
+
+	m := r.FormValue("m")
+	if !um.IsPackage() {
+		format := "Markdown"
+		switch m {
+		case "json":
+			format = "JSON"
+		case "llms":
+			format = "plain text"
+		}
+		return &serrors.ServerError{
+			Status: http.StatusBadRequest,
+			Epage:  &page.ErrorPage{MessageData: fmt.Sprintf("Documentation in %s is only available for packages.", format)},
+		}
+	}
+	u, err := internal.GetUnitPreferring(ctx, ds, um, internal.WithMain, bc, defaultBC)
+	if err != nil {
+		return err
+	}
+	docs := cleanDocumentation(u.Documentation)
+	if len(docs) == 0 || docs[0].Source == nil {
+		// The package has no documentation for bc, or it is not
+		// redistributable.
+		return &serrors.ServerError{Status: http.StatusNotFound}
+	}
+	u.Documentation = docs
+	var (
+		data        []byte
+		contentType string
+	)
+	switch m {
+	case "json":
+		data, err = godoc.RenderJSONFromUnit(u)
+		contentType = "application/json"
+	case "llms":
+		// Link to the HTML page of the request.
+		data, err = godoc.RenderLLMsFromUnit(u, docllms.Options{URL: baseURL + r.URL.Path})
+		contentType = "text/plain; charset=utf-8"
+	default:
+		data, err = godoc.RenderMarkdownFromUnit(u, docmarkdown.Options{DocLinkBaseURL: baseURL})
+		contentType = "text/markdown; charset=utf-8"
+	}
+	if err != nil {
+		if errors.Is(err, godoc.ErrInvalidEncodingType) {
+			log.Errorf(ctx, "serveUnitMarkdown(%q, %q, %q): %v", um.Path, um.ModulePath, um.Version, err)
+			return serrors.ErrUnitNotFoundWithoutFetch
+		}
+		return err
+	}
+	w.Header().Set("Content-Type", contentType)
+	if _, err := w.Write(data); err != nil {
+		log.Errorf(ctx, "serveUnitMarkdown: w.Write: %v", err)
+	}
+	return nil
+}
+
+// sourceFiles returns the .go files for a package, given all of its files.
+func sourceFiles(u *internal.Unit, pkgFiles []*docrender.File) []*File {
 	var files []*File
-	for _, f := range docPkg.Files {
+	types := unitLicenseTypes(u)
+	for _, f := range pkgFiles {
 		if strings.HasSuffix(f.Name, "_test.go") {
 			continue
 		}
-		files = append(files, &File{
-			Name: f.Name,
-			URL:  u.SourceInfo.FileURL(path.Join(internal.Suffix(u.Path, u.ModulePath), f.Name)),
-		})
+		file := &File{
+			Name:            f.Name,
+			URL:             u.SourceInfo.FileURL(path.Join(internal.Suffix(u.Path, u.ModulePath), f.Name)),
+			BuildConstraint: f.BuildConstraint,
+		}
+		for _, t := range f.Licenses {
+			if !slices.Contains(types, t) {
+				file.Licenses = f.Licenses
+				break
+			}
+		}
+		files = append(files, file)
 	}
 	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
 	return files
 }
 
+// groupFiles groups files, which are sorted by name, by build constraint.
+// Files without a constraint come first, followed by the other groups in order
+// of constraint.
+func groupFiles(files []*File) []*FileGroup {
+	var groups []*FileGroup
+	byConstraint := map[string]*FileGroup{}
+	for _, f := range files {
+		g := byConstraint[f.BuildConstraint]
+		if g == nil {
+			g = &FileGroup{BuildConstraint: f.BuildConstraint}
+			byConstraint[f.BuildConstraint] = g
+			groups = append(groups, g)
+		}
+		g.Files = append(g.Files, f)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].BuildConstraint < groups[j].BuildConstraint })
+	return groups
+}
+
 // fileSource returns the original filepath in the module zip where the given
 // filePath can be found. For std, the corresponding URL in
 // go.google.source.com/go is returned.
@@ -74,3 +205,85 @@ func fileSource(modulePath, version, filePath string) string {
 	}
 	return fmt.Sprintf("%s/+/refs/tags/%s/%s", root, tag, filePath)
 }
+
+// unitLicenseTypes returns the sorted types of the licenses that apply to u.
+func unitLicenseTypes(u *internal.Unit) []string {
+	var types []string
+	for _, l := range u.Licenses {
+		for _, t := range l.Types {
+			if !slices.Contains(types, t) {
+				types = append(types, t)
+			}
+		}
+	}
+	slices.Sort(types)
+	return types
+}
+
+// commentSafeRegexp matches the app versions and toolchains that can be
+// written in an HTML comment as is.
+var commentSafeRegexp = regexp.MustCompile(`^[A-Za-z0-9._+: ]+(-[A-Za-z0-9._+: ]+)*$`)
+
+// A SkippedExample is an example function of a package that is not displayed
+// correctly with its documentation.
+type SkippedExample struct {
+	Name     string
+	Position string // file:line of the declaration
+	URL      string // link to the declaration in the source, if known
+	Reason   string
+}
+
+// skippedExamples returns the skipped examples of doc, a documentation of u.
+func skippedExamples(u *internal.Unit, doc *internal.Documentation) []*SkippedExample {
+	var exs []*SkippedExample
+	for _, se := range doc.SkippedExamples {
+		exs = append(exs, &SkippedExample{
+			Name:     se.Name,
+			Position: fmt.Sprintf("%s:%d", se.File, se.Line),
+			URL:      u.SourceInfo.LineURL(path.Join(internal.Suffix(u.Path, u.ModulePath), se.File), se.Line),
+			Reason:   se.Reason,
+		})
+	}
+	return exs
+}
+
+// A BuildExclusion is a build constraint that leaves files of a package out
+// of its documentation for a build context.
+type BuildExclusion struct {
+	BuildConstraint string   // such as "!windows"
+	Files           []string // names of the files it excludes
+}
+
+// buildContextExclusions groups the files that doc leaves out by their build
+// constraints, sorted by constraint.
+func buildContextExclusions(doc *internal.Documentation) []*BuildExclusion {
+	var bes []*BuildExclusion
+	byConstraint := map[string]*BuildExclusion{}
+	for _, ef := range doc.ExcludedFiles {
+		if ef.BuildConstraint == "" {
+			continue
+		}
+		be := byConstraint[ef.BuildConstraint]
+		if be == nil {
+			be = &BuildExclusion{BuildConstraint: ef.BuildConstraint}
+			byConstraint[ef.BuildConstraint] = be
+			bes = append(bes, be)
+		}
+		be.Files = append(be.Files, ef.Name)
+	}
+	sort.Slice(bes, func(i, j int) bool { return bes[i].BuildConstraint < bes[j].BuildConstraint })
+	return bes
+}
+
+// docVersionsComment returns an HTML comment naming the app version and the
+// toolchain that processed doc, for tracking down documentation that should
+// be reprocessed. It returns the empty HTML if they were not recorded, or
+// if they could not be safely written in a comment.
+func docVersionsComment(doc *internal.Documentation) safehtml.HTML {
+	if doc == nil || !commentSafeRegexp.MatchString(doc.AppVersion) || !commentSafeRegexp.MatchString(doc.Toolchain) {
+		return safehtml.HTML{}
+	}
+	// The regexp rules out "--" and ">", so the comment cannot be closed early.
+	return uncheckedconversions.HTMLFromStringKnownToSatisfyTypeContract(
+		fmt.Sprintf("<!-- documentation processed by app version %s with %s -->", doc.AppVersion, doc.Toolchain))
+}
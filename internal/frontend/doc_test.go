@@ -5,10 +5,19 @@
 package frontend
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/stdlib"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
 	"golang.org/x/pkgsite/internal/testing/sample"
 )
 
@@ -42,3 +51,175 @@ func TestFileSource(t *testing.T) {
 		})
 	}
 }
+
+func TestGroupFiles(t *testing.T) {
+	file := func(name, constraint string) *File {
+		return &File{Name: name, BuildConstraint: constraint}
+	}
+	a := file("a.go", "")
+	b := file("b_linux.go", "linux")
+	c := file("c.go", "")
+	d := file("d_windows.go", "windows")
+	e := file("e_linux.go", "linux")
+	got := groupFiles([]*File{a, b, c, d, e})
+	want := []*FileGroup{
+		{BuildConstraint: "", Files: []*File{a, c}},
+		{BuildConstraint: "linux", Files: []*File{b, e}},
+		{BuildConstraint: "windows", Files: []*File{d}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestServeUnitMarkdown(t *testing.T) {
+	ctx := context.Background()
+	const src = `
+		// Package p is a package that uses [io.Reader].
+		package p
+
+		// F is a function.
+		func F() {}
+	`
+	m := sample.Module("example.com/mod", sample.VersionString, "p")
+	m.Packages()[0].Documentation = []*internal.Documentation{sample.Documentation(internal.All, internal.All, src)}
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, m)
+	s, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+		BaseURL:          "https://pkg.example.com",
+	})
+	s.serveLLMs = true
+
+	for _, test := range []struct {
+		path            string
+		wantStatus      int
+		wantContentType string
+		want            []string
+	}{
+		{
+			path:            "/example.com/mod/p?m=md",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/markdown; charset=utf-8",
+			want: []string{
+				"# package p\n",
+				"Package p is a package that uses [io.Reader](https://pkg.example.com/io#Reader).",
+				"### func F {#F}",
+			},
+		},
+		{
+			path:       "/example.com/mod?m=md",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			path:            "/example.com/mod/p?m=json",
+			wantStatus:      http.StatusOK,
+			wantContentType: "application/json",
+			want: []string{
+				`"ImportPath": "example.com/mod/p"`,
+				`"Doc": "Package p is a package that uses [io.Reader].\n"`,
+				`"Decl": "func F()"`,
+			},
+		},
+		{
+			path:       "/example.com/mod?m=json",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			path:            "/example.com/mod/p?m=llms",
+			wantStatus:      http.StatusOK,
+			wantContentType: "text/plain; charset=utf-8",
+			want: []string{
+				"# example.com/mod/p\n\n> Package p is a package that uses io.Reader.\n",
+				"Full documentation: https://pkg.example.com/example.com/mod/p\n",
+				"- `func F()`: F is a function.\n",
+			},
+		},
+		{
+			path:       "/example.com/mod?m=llms",
+			wantStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			res := w.Result()
+			if res.StatusCode != test.wantStatus {
+				t.Fatalf("status = %d, want %d", res.StatusCode, test.wantStatus)
+			}
+			if test.wantContentType != "" {
+				if got := res.Header.Get("Content-Type"); got != test.wantContentType {
+					t.Errorf("Content-Type = %q, want %q", got, test.wantContentType)
+				}
+			}
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(string(body), want) {
+					t.Errorf("body does not contain %q:\n%s", want, body)
+				}
+			}
+		})
+	}
+}
+
+func TestDocVersionsComment(t *testing.T) {
+	for _, test := range []struct {
+		appVersion, toolchain string
+		want                  string
+	}{
+		{"20260601t120000", "go1.23.4", "<!-- documentation processed by app version 20260601t120000 with go1.23.4 -->"},
+		{"20260601t120000", "devel go1.24-abc123 X:boringcrypto", "<!-- documentation processed by app version 20260601t120000 with devel go1.24-abc123 X:boringcrypto -->"},
+		{"", "", ""},
+		{"20260601t120000", "", ""},
+		{"20260601t120000", "go1.23 --><script>", ""},
+		{"20260601t120000", "go1.23-", ""},
+	} {
+		doc := &internal.Documentation{AppVersion: test.appVersion, Toolchain: test.toolchain}
+		if got := docVersionsComment(doc).String(); got != test.want {
+			t.Errorf("docVersionsComment(%q, %q) = %q, want %q", test.appVersion, test.toolchain, got, test.want)
+		}
+	}
+}
+
+func TestSkippedExamples(t *testing.T) {
+	u := &internal.Unit{UnitMeta: internal.UnitMeta{
+		Path: "github.com/a/b/c",
+		ModuleInfo: internal.ModuleInfo{
+			ModulePath: "github.com/a/b",
+			SourceInfo: source.NewGitHubInfo("https://github.com/a/b", "", "v1.0.0"),
+		},
+	}}
+	doc := &internal.Documentation{SkippedExamples: []*internal.SkippedExample{
+		{Name: "ExampleG", File: "c_test.go", Line: 12, Reason: "No G."},
+	}}
+	got := skippedExamples(u, doc)
+	want := []*SkippedExample{{
+		Name:     "ExampleG",
+		Position: "c_test.go:12",
+		URL:      "https://github.com/a/b/blob/v1.0.0/c/c_test.go#L12",
+		Reason:   "No G.",
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestBuildContextExclusions(t *testing.T) {
+	doc := &internal.Documentation{ExcludedFiles: []*internal.ExcludedFile{
+		{Name: "a_windows.go", BuildConstraint: "windows"},
+		{Name: "b.go", BuildConstraint: "!linux"},
+		{Name: "c.go", BuildConstraint: "windows"},
+		{Name: "d.go"},
+	}}
+	got := buildContextExclusions(doc)
+	want := []*BuildExclusion{
+		{BuildConstraint: "!linux", Files: []string{"b.go"}},
+		{BuildConstraint: "windows", Files: []string{"a_windows.go", "c.go"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
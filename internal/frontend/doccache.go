@@ -0,0 +1,141 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/google/safehtml"
+	"github.com/google/safehtml/uncheckedconversions"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/lru"
+)
+
+// Many versions of a package have identical documentation source. To render
+// it only once, documentation is rendered with placeholders in place of the
+// version and commit, and cached by a hash of the source. The placeholders
+// are replaced with the actual version and commit when the cached parts are
+// used.
+//
+// The cache is in the memory of each frontend, and saves rendering only. The
+// Redis page cache is unaffected: it still holds a whole page for each URL,
+// so each version of a package takes its own entry there.
+const (
+	docVersionPlaceholder = "v0.0.0-pkgsite-doc-version"
+	docCommitPlaceholder  = "pkgsite-doc-commit"
+
+	// docPartsCacheSize is the number of rendered packages kept in memory.
+	docPartsCacheSize = 256
+)
+
+// docPartsKey identifies the inputs to rendering a package's documentation,
+// other than its version.
+type docPartsKey struct {
+	sourceHash       string
+	path, modulePath string
+	goVersion        string
+	sourceInfo       string // JSON of the source info, with a placeholder commit
+	symbols          string // hash of the versions at which symbols were introduced
+	build            internal.BuildContext
+	expandExamples   bool
+	noIssueLinks     bool
+	licenseTypes     string
+	brokenDocLinks   string // JSON of the broken doc links
+}
+
+var docPartsCache = lru.New[docPartsKey, *dochtml.Parts](docPartsCacheSize)
+
+// placeholderSafe matches the versions and commits that can be substituted
+// for placeholders in rendered HTML: those that don't need to be escaped.
+var placeholderSafe = regexp.MustCompile(`^[A-Za-z0-9._/-]+$`)
+
+// newDocPartsKey returns the cache key for rendering the documentation of u
+// with the given symbol versions, build context and expansion of examples.
+// It returns false if the rendered documentation cannot be shared with other
+// versions.
+func newDocPartsKey(u *internal.Unit, nameToVersion map[string]string, bc internal.BuildContext, expandExamples bool) (docPartsKey, bool) {
+	doc := u.Documentation[0]
+	if !placeholderSafe.MatchString(u.Version) {
+		return docPartsKey{}, false
+	}
+	if c := u.SourceInfo.Commit(); c != "" && !placeholderSafe.MatchString(c) {
+		return docPartsKey{}, false
+	}
+	h := doc.SourceHash
+	if h == "" {
+		// The documentation was fetched before hashes were stored.
+		h = godoc.SourceHash(doc.Source)
+	}
+	si, err := json.Marshal(u.SourceInfo.WithCommit(docCommitPlaceholder))
+	if err != nil {
+		return docPartsKey{}, false
+	}
+	// Whether links to other packages of the module are broken depends on
+	// the version of the module, not just on the source of the package.
+	bdl, err := json.Marshal(doc.BrokenDocLinks)
+	if err != nil {
+		return docPartsKey{}, false
+	}
+	return docPartsKey{
+		sourceHash:     h,
+		path:           u.Path,
+		modulePath:     u.ModulePath,
+		goVersion:      u.GoVersion,
+		sourceInfo:     string(si),
+		symbols:        hashSymbolVersions(nameToVersion),
+		noIssueLinks:   u.AuthorMetadata.IssueLinksDisabled(),
+		licenseTypes:   strings.Join(unitLicenseTypes(u), ","),
+		brokenDocLinks: string(bdl),
+		build:          bc,
+		expandExamples: expandExamples,
+	}, true
+}
+
+// hashSymbolVersions returns a hash of a map from symbol name to the version
+// at which it was introduced.
+func hashSymbolVersions(nameToVersion map[string]string) string {
+	if nameToVersion == nil {
+		return ""
+	}
+	names := make([]string, 0, len(nameToVersion))
+	for n := range nameToVersion {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, n := range names {
+		h.Write([]byte(n + "\x00" + nameToVersion[n] + "\x00"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// fillDocParts returns a copy of parts, which was rendered with placeholders,
+// with the placeholders replaced by version and commit.
+func fillDocParts(parts *dochtml.Parts, version, commit string) *dochtml.Parts {
+	r := strings.NewReplacer(docVersionPlaceholder, version, docCommitPlaceholder, commit)
+	fill := func(h safehtml.HTML) safehtml.HTML {
+		// version and commit match placeholderSafe, so they need no escaping.
+		return uncheckedconversions.HTMLFromStringKnownToSatisfyTypeContract(r.Replace(h.String()))
+	}
+	p := &dochtml.Parts{
+		Body:          fill(parts.Body),
+		Outline:       fill(parts.Outline),
+		MobileOutline: fill(parts.MobileOutline),
+		Links:         slices.Clone(parts.Links),
+	}
+	for i := range p.Links {
+		p.Links[i].Href = r.Replace(p.Links[i].Href)
+		p.Links[i].Text = r.Replace(p.Links[i].Text)
+	}
+	return p
+}
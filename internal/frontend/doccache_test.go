@@ -0,0 +1,80 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestRenderDocPartsCache(t *testing.T) {
+	ctx := context.Background()
+	dochtml.LoadTemplates(template.TrustedFSFromTrustedSource(template.TrustedSourceFromConstant("../../static")))
+	const src = `
+		// Package p is a package.
+		package p
+
+		// F is a function.
+		func F() {}
+	`
+	doc := sample.Documentation("linux", "amd64", src)
+	unitAt := func(version string) *internal.Unit {
+		u := sample.UnitForPackage(sample.PackagePath, sample.ModulePath, version, "p", true)
+		u.Documentation = []*internal.Documentation{doc}
+		return u
+	}
+	decode := func() *godoc.Package {
+		docPkg, err := godoc.DecodePackage(doc.Source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return docPkg
+	}
+	partStrings := func(p *dochtml.Parts) []string {
+		return []string{p.Body.String(), p.Outline.String(), p.MobileOutline.String()}
+	}
+	bc := internal.BuildContext{GOOS: "linux", GOARCH: "amd64"}
+	nameToVersion := map[string]string{"F": "v1.0.0"}
+
+	for i, version := range []string{"v1.0.0", "v1.1.0", "v1.2.0+incompatible"} {
+		u := unitAt(version)
+		key, cacheable := newDocPartsKey(u, nameToVersion, bc, false)
+		if want := !strings.Contains(version, "+"); cacheable != want {
+			t.Fatalf("%s: cacheable = %t, want %t", version, cacheable, want)
+		}
+		if _, ok := docPartsCache.Get(key); cacheable && i > 0 && !ok {
+			t.Errorf("%s: rendered docs not cached by source hash", version)
+		}
+		got, err := renderDocParts(ctx, u, decode(), nameToVersion, bc, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, err := renderDocPartsAt(ctx, u, decode(), version, u.SourceInfo, nameToVersion, bc, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(partStrings(want), partStrings(got)); diff != "" {
+			t.Errorf("%s: mismatch (-want, +got):\n%s", version, diff)
+		}
+		if !strings.Contains(got.Body.String(), "/blob/"+version+"/") {
+			t.Errorf("%s: body does not link to source at version", version)
+		}
+	}
+
+	// Documentation with its examples expanded is cached separately.
+	collapsed, _ := newDocPartsKey(unitAt("v1.0.0"), nameToVersion, bc, false)
+	expanded, _ := newDocPartsKey(unitAt("v1.0.0"), nameToVersion, bc, true)
+	if collapsed == expanded {
+		t.Error("examples expanded or not: got the same cache key")
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/cookie"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docfeedback"
+	"golang.org/x/pkgsite/internal/frontend/page"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/frontend/versions"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// DocFeedbackPage contains data for the page to report a problem with the
+// documentation of a unit.
+type DocFeedbackPage struct {
+	page.BasePage
+	// Report holds what is sent with the report, other than the
+	// description.
+	Report *docfeedback.Report
+	// MaxDescriptionLen is the maximum length of the description.
+	MaxDescriptionLen int
+	// Token is the token of the form; see newDocFeedbackToken.
+	Token string
+	// Filed is true once the report was filed, and IssueURL is the URL of
+	// the issue for it, if it has one.
+	Filed    bool
+	IssueURL string
+}
+
+const (
+	// docFeedbackLimit is the number of reports that a client can file in
+	// docFeedbackWindow.
+	docFeedbackLimit  = 5
+	docFeedbackWindow = time.Hour
+
+	// docFeedbackTokenTTL is how long a form to report a problem can be
+	// submitted after it is served.
+	docFeedbackTokenTTL = time.Hour
+)
+
+// docFeedbackURL returns the URL of the page to report a problem with the
+// documentation of the unit at the build context.
+func docFeedbackURL(um *internal.UnitMeta, goos, goarch string) string {
+	return "/report-doc-issue?" + url.Values{
+		"module":  {um.ModulePath},
+		"version": {um.Version},
+		"path":    {um.Path},
+		"GOOS":    {goos},
+		"GOARCH":  {goarch},
+	}.Encode()
+}
+
+// serveDocFeedback serves the page to report a problem with the
+// documentation of a unit, for GET requests to /report-doc-issue, and files
+// the report, for POST requests.
+//
+// The form carries a token that is also set in a cookie, so that only forms
+// served by this site can file reports, and each client can file at most
+// docFeedbackLimit reports in docFeedbackWindow.
+func (s *Server) serveDocFeedback(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveDocFeedback")
+
+	ctx := r.Context()
+	report, err := docFeedbackReport(ctx, r, ds, s.defaultBC, s.baseURL)
+	if err != nil {
+		return err
+	}
+	p := DocFeedbackPage{
+		BasePage:          s.newBasePage(r, "Report an issue with "+report.Path),
+		Report:            report,
+		MaxDescriptionLen: docfeedback.MaxDescriptionLen,
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	if r.Method != http.MethodPost {
+		p.Token, err = newDocFeedbackToken()
+		if err != nil {
+			return err
+		}
+		http.SetCookie(w, &http.Cookie{
+			Name:   cookie.DocFeedbackToken,
+			Value:  p.Token,
+			Path:   "/report-doc-issue",
+			MaxAge: int(docFeedbackTokenTTL.Seconds()),
+			// TLS is terminated before requests reach the server, so r.TLS is
+			// always nil. Browsers accept secure cookies from localhost too.
+			Secure:   true,
+			HttpOnly: true,
+			SameSite: http.SameSiteStrictMode,
+		})
+		s.servePage(ctx, w, "report-doc-issue", p)
+		return nil
+	}
+	if !validDocFeedbackToken(r) {
+		return &serrors.ServerError{
+			Status:       http.StatusForbidden,
+			ResponseText: "The form has expired. Please go back, reload the page and try again.",
+		}
+	}
+	report.Description = strings.TrimSpace(r.FormValue("description"))
+	if report.Description == "" || len(report.Description) > docfeedback.MaxDescriptionLen {
+		return &serrors.ServerError{
+			Status:       http.StatusBadRequest,
+			ResponseText: fmt.Sprintf("The description must be between 1 and %d bytes.", docfeedback.MaxDescriptionLen),
+		}
+	}
+	if !s.feedbackLimiter.allow(s.clientAddr(r)) {
+		return &serrors.ServerError{
+			Status:       http.StatusTooManyRequests,
+			ResponseText: "You have filed too many reports. Please try again later.",
+		}
+	}
+	p.IssueURL, err = s.docFeedback.File(ctx, report)
+	if err != nil {
+		log.Errorf(ctx, "filing documentation report for %s@%s: %v", report.Path, report.Version, err)
+		return &serrors.ServerError{
+			Status:       http.StatusServiceUnavailable,
+			ResponseText: "The report could not be filed. Please try again later.",
+		}
+	}
+	p.Filed = true
+	s.servePage(ctx, w, "report-doc-issue", p)
+	return nil
+}
+
+// newDocFeedbackToken returns a new random token for a form to report a
+// problem with documentation.
+func newDocFeedbackToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// validDocFeedbackToken reports whether the token in the form of r is the one
+// in its cookie. Other sites can't read or set the cookie, so a form posted
+// from them can't carry its token.
+func validDocFeedbackToken(r *http.Request) bool {
+	c, err := r.Cookie(cookie.DocFeedbackToken)
+	if err != nil || c.Value == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(c.Value), []byte(r.PostFormValue("token"))) == 1
+}
+
+// docFeedbackReport returns a report for the unit and build context in the
+// form values of the request, or defaultBC if they name none, with the
+// versions that processed its documentation. The values are checked against
+// the data source, so a report can only be about a unit that is shown. The
+// URL of the report is relative to baseURL.
+func docFeedbackReport(ctx context.Context, r *http.Request, ds internal.DataSource, defaultBC internal.BuildContext, baseURL string) (*docfeedback.Report, error) {
+	modulePath, version, path := r.FormValue("module"), r.FormValue("version"), r.FormValue("path")
+	if modulePath == "" || version == "" || path == "" {
+		return nil, &serrors.ServerError{Status: http.StatusBadRequest}
+	}
+	um, err := ds.GetUnitMeta(ctx, path, modulePath, version)
+	if err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			return nil, &serrors.ServerError{Status: http.StatusNotFound}
+		}
+		return nil, err
+	}
+	if err := checkModuleAvailable(ctx, ds, um.Path, um.ModulePath, um.Version); err != nil {
+		return nil, err
+	}
+	report := &docfeedback.Report{
+		ModulePath: um.ModulePath,
+		Version:    um.Version,
+		Path:       um.Path,
+		URL:        baseURL + versions.ConstructUnitURL(um.Path, um.ModulePath, um.Version),
+	}
+	bc := internal.BuildContext{GOOS: r.FormValue("GOOS"), GOARCH: r.FormValue("GOARCH")}
+	unit, err := internal.GetUnitPreferring(ctx, ds, um, internal.WithMain, bc, defaultBC)
+	if err != nil {
+		return nil, err
+	}
+	if docs := cleanDocumentation(unit.Documentation); len(docs) > 0 {
+		doc := docs[0]
+		report.GOOS, report.GOARCH = doc.GOOS, doc.GOARCH
+		report.AppVersion, report.Toolchain = doc.AppVersion, doc.Toolchain
+	}
+	return report, nil
+}
+
+// A reportLimiter limits the number of reports that each client can file in a
+// window of time. The counts are kept in memory, so each frontend instance
+// limits the reports it receives.
+type reportLimiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time // replaced in tests
+
+	mu     sync.Mutex
+	start  time.Time      // start of the current window
+	counts map[string]int // reports filed in the current window, by client
+}
+
+// maxReportLimiterClients bounds the number of clients a reportLimiter
+// tracks in a window. Clients beyond it are denied until the window ends.
+const maxReportLimiterClients = 10000
+
+func newReportLimiter(limit int, window time.Duration) *reportLimiter {
+	return &reportLimiter{limit: limit, window: window, now: time.Now}
+}
+
+// allow reports whether client can file a report, and if so counts it.
+func (l *reportLimiter) allow(client string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now := l.now(); l.counts == nil || now.Sub(l.start) >= l.window {
+		l.start = now
+		l.counts = map[string]int{}
+	}
+	n, ok := l.counts[client]
+	if n >= l.limit || (!ok && len(l.counts) >= maxReportLimiterClients) {
+		return false
+	}
+	l.counts[client] = n + 1
+	return true
+}
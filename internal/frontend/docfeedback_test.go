@@ -0,0 +1,175 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/cookie"
+	"golang.org/x/pkgsite/internal/docfeedback"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+// fakeFiler records the reports it files.
+type fakeFiler struct {
+	reports []*docfeedback.Report
+	err     error
+}
+
+func (f *fakeFiler) File(ctx context.Context, r *docfeedback.Report) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	f.reports = append(f.reports, r)
+	return "https://github.com/owner/docs/issues/1", nil
+}
+
+func TestDocFeedback(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, sample.Module("example.com/mod", "v1.0.0", sample.Suffix))
+	filer := &fakeFiler{}
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+		DocFeedback:      filer,
+		BaseURL:          "https://pkg.example.com",
+	})
+
+	// do sends a request with the cookies that earlier responses set.
+	var cookies []*http.Cookie
+	do := func(method, path string, form url.Values) *httptest.ResponseRecorder {
+		t.Helper()
+		r := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+		if form != nil {
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+		for _, c := range cookies {
+			r.AddCookie(c)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		cookies = append(cookies, w.Result().Cookies()...)
+		return w
+	}
+	checkPage := func(w *httptest.ResponseRecorder, wants ...string) {
+		t.Helper()
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200; body:\n%s", w.Code, w.Body)
+		}
+		for _, want := range wants {
+			if !strings.Contains(w.Body.String(), want) {
+				t.Errorf("page does not contain %q", want)
+			}
+		}
+	}
+
+	// The unit page links to the report page, which shows what is sent.
+	um := &internal.UnitMeta{Path: "example.com/mod/foo", ModuleInfo: internal.ModuleInfo{ModulePath: "example.com/mod", Version: "v1.0.0"}}
+	reportURL := docFeedbackURL(um, sample.GOOS, sample.GOARCH)
+	checkPage(do("GET", "/example.com/mod/foo", nil), `href="`+html.EscapeString(reportURL)+`"`)
+	form := url.Values{
+		"module":      {"example.com/mod"},
+		"version":     {"v1.0.0"},
+		"path":        {"example.com/mod/foo"},
+		"GOOS":        {sample.GOOS},
+		"GOARCH":      {sample.GOARCH},
+		"description": {"  The example for Foo is missing.\n"},
+	}
+	// A form that was not served by the site is rejected.
+	if w := do("POST", "/report-doc-issue", form); w.Code != http.StatusForbidden {
+		t.Errorf("POST without a token: status = %d, want 403", w.Code)
+	}
+
+	checkPage(do("GET", reportURL, nil), `data-test-id="doc-feedback-context"`, sample.GOOS+"/"+sample.GOARCH)
+	if len(cookies) != 1 || cookies[0].Name != cookie.DocFeedbackToken {
+		t.Fatalf("got cookies %v, want one %s cookie", cookies, cookie.DocFeedbackToken)
+	}
+	form.Set("token", "forged")
+	if w := do("POST", "/report-doc-issue", form); w.Code != http.StatusForbidden {
+		t.Errorf("POST with a wrong token: status = %d, want 403", w.Code)
+	}
+	form.Set("token", cookies[0].Value)
+	form.Del("description")
+	if w := do("POST", "/report-doc-issue", form); w.Code != http.StatusBadRequest {
+		t.Errorf("POST without a description: status = %d, want 400", w.Code)
+	}
+	form.Set("description", "  The example for Foo is missing.\n")
+	checkPage(do("POST", "/report-doc-issue", form), `data-test-id="doc-feedback-filed"`, "https://github.com/owner/docs/issues/1")
+	if len(filer.reports) != 1 {
+		t.Fatalf("got %d reports, want 1", len(filer.reports))
+	}
+	got := filer.reports[0]
+	want := docfeedback.Report{
+		ModulePath:  "example.com/mod",
+		Version:     "v1.0.0",
+		Path:        "example.com/mod/foo",
+		GOOS:        sample.GOOS,
+		GOARCH:      sample.GOARCH,
+		URL:         "https://pkg.example.com/example.com/mod@v1.0.0/foo",
+		Description: "The example for Foo is missing.",
+	}
+	if *got != want {
+		t.Errorf("got report %+v, want %+v", *got, want)
+	}
+
+	// Reports can only be made about units that are shown.
+	form.Set("path", "example.com/mod/nope")
+	if w := do("POST", "/report-doc-issue", form); w.Code != http.StatusNotFound {
+		t.Errorf("POST for a missing unit: status = %d, want 404", w.Code)
+	}
+
+	form.Set("path", "example.com/mod/foo")
+	filer.err = errors.New("GitHub is down")
+	if w := do("POST", "/report-doc-issue", form); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("POST when filing fails: status = %d, want 503", w.Code)
+	}
+
+	// Each client can file only docFeedbackLimit reports in a window. Two
+	// were counted above.
+	filer.err = nil
+	for range docFeedbackLimit - 2 {
+		do("POST", "/report-doc-issue", form)
+	}
+	if w := do("POST", "/report-doc-issue", form); w.Code != http.StatusTooManyRequests {
+		t.Errorf("POST over the limit: status = %d, want 429", w.Code)
+	}
+}
+
+func TestReportLimiter(t *testing.T) {
+	now := time.Now()
+	l := newReportLimiter(2, time.Hour)
+	l.now = func() time.Time { return now }
+	for i, want := range []bool{true, true, false} {
+		if got := l.allow("a"); got != want {
+			t.Errorf("report %d of a: got %t, want %t", i, got, want)
+		}
+	}
+	if !l.allow("b") {
+		t.Error("b is limited by the reports of a")
+	}
+	now = now.Add(time.Hour)
+	if !l.allow("a") {
+		t.Error("a is still limited in the next window")
+	}
+}
+
+func TestDocFeedbackDisabled(t *testing.T) {
+	_, handler := newTestServer(t, nil, ServerConfig{})
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/report-doc-issue?module=m&version=v1.0.0&path=m", nil))
+	if w.Code == http.StatusOK {
+		t.Error("the report page is served without a filer")
+	}
+}
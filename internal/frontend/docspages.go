@@ -0,0 +1,139 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/google/safehtml"
+	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docrender"
+	"golang.org/x/pkgsite/internal/frontend/page"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/middleware/stats"
+)
+
+// docsPageParam is the query parameter of the Docs tab that selects a page by
+// its path relative to the docs directory.
+const docsPageParam = docrender.DocsPageParam
+
+// DocsDetails contains the pages under the docs directory of a unit and the
+// rendered contents of one of them.
+type DocsDetails struct {
+	// Pages are the pages for the navigation, in lexical order.
+	Pages []*DocsPage
+
+	// Page is the selected page.
+	Page *DocsPage
+
+	// HTML is the rendered and sanitized contents of Page.
+	HTML safehtml.HTML
+
+	// SourceURL is the URL of Page in the repository, or empty if the
+	// repository is unknown.
+	SourceURL string
+}
+
+// A DocsPage is a Markdown file under the docs directory of a unit.
+type DocsPage struct {
+	// Name is the path of the file relative to the docs directory, such as
+	// "guides/install.md".
+	Name string
+	// Title is Name without its extension.
+	Title    string
+	URL      string
+	Selected bool
+}
+
+// fetchDocsDetails returns the docs pages of the unit um, with the one named
+// by name rendered with rd, or the default page if name is empty.
+func fetchDocsDetails(ctx context.Context, ds internal.DataSource, rd docrender.Renderer, um *internal.UnitMeta, name string) (_ *DocsDetails, err error) {
+	defer derrors.Wrap(&err, "fetchDocsDetails(%q, %q, %q, %q)", um.Path, um.ModulePath, um.Version, name)
+	defer stats.Elapsed(ctx, "fetchDocsDetails")()
+
+	unit, err := ds.GetUnit(ctx, um, internal.WithDocsPages, internal.BuildContext{})
+	if err != nil {
+		return nil, err
+	}
+	details := &DocsDetails{Pages: docsPages(um, unit.DocsPages)}
+	if len(details.Pages) == 0 {
+		// Rejected by isValidTabForUnit.
+		return details, nil
+	}
+	selected := defaultDocsPage(details.Pages)
+	if name != "" {
+		selected = -1
+		for i, p := range details.Pages {
+			if p.Name == name {
+				selected = i
+			}
+		}
+		if selected < 0 {
+			return nil, &serrors.ServerError{
+				Status: http.StatusNotFound,
+				Epage: &page.ErrorPage{
+					MessageTemplate: template.MakeTrustedTemplate(
+						`<h3 class="Error-message">{{.Page}} is not a docs page of {{.Path}}.</h3>`),
+					MessageData: struct{ Page, Path string }{name, um.Path},
+				},
+			}
+		}
+	}
+	details.Page = details.Pages[selected]
+	details.Page.Selected = true
+
+	// Links between the pages stay on this tab.
+	var filePaths []string
+	for _, p := range unit.DocsPages {
+		filePaths = append(filePaths, p.Filepath)
+	}
+	r := unit.DocsPages[selected]
+	resp, err := renderDocsPage(ctx, ds, rd, &docrender.DocsPageRequest{
+		ReadmeRequest: docrender.ReadmeRequest{
+			Readme:       r,
+			SourceInfo:   um.SourceInfo,
+			NoIssueLinks: um.AuthorMetadata.IssueLinksDisabled(),
+		},
+		Pages: docrender.DocsPageURLs(um.Path, um.ModulePath, filePaths),
+	})
+	if err != nil {
+		return nil, err
+	}
+	details.HTML = trustedRenderedHTML(resp.HTML)
+	details.SourceURL = um.SourceInfo.FileURL(r.Filepath)
+	return details, nil
+}
+
+// docsPages returns the navigation entries for pages, the docs pages of the
+// unit um, in the same order.
+func docsPages(um *internal.UnitMeta, pages []*internal.Readme) []*DocsPage {
+	var ps []*DocsPage
+	for _, p := range pages {
+		name := docrender.DocsPageName(um.Path, um.ModulePath, p.Filepath)
+		ps = append(ps, &DocsPage{
+			Name:  name,
+			Title: strings.TrimSuffix(name, path.Ext(name)),
+			URL:   docrender.DocsPageURL(name),
+		})
+	}
+	return ps
+}
+
+// defaultDocsPage returns the index of the page to show when none is
+// requested: index.md or README.md at the top of the docs directory if
+// there is one, or else the first page.
+func defaultDocsPage(pages []*DocsPage) int {
+	for i, p := range pages {
+		if strings.EqualFold(p.Title, "index") || strings.EqualFold(p.Title, "README") {
+			return i
+		}
+	}
+	return 0
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/docrender"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestFetchDocsDetails(t *testing.T) {
+	ctx := context.Background()
+	m := sample.Module(sample.ModulePath, sample.VersionString, "p")
+	pkg := m.Packages()[0]
+	pkg.DocsPages = []*internal.Readme{
+		{Filepath: "p/docs/guide/setup.md", Contents: "# Setup\n\n## Install\n"},
+		{Filepath: "p/docs/index.md", Contents: "# Index\n\nSee [setup](guide/setup.md#install).\n\n<script>alert(1)</script>\n"},
+	}
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, m)
+	um, err := fds.GetUnitMeta(ctx, pkg.Path, sample.ModulePath, sample.VersionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := fetchDocsDetails(ctx, fds, NewLocalRenderer(), um, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPages := []*DocsPage{
+		{Name: "guide/setup.md", Title: "guide/setup", URL: "?tab=docs&page=guide%2Fsetup.md"},
+		{Name: "index.md", Title: "index", URL: "?tab=docs&page=index.md", Selected: true},
+	}
+	if diff := cmp.Diff(wantPages, got.Pages); diff != "" {
+		t.Errorf("pages mismatch (-want, +got):\n%s", diff)
+	}
+	if got.Page != got.Pages[1] {
+		t.Errorf("got page %q, want index.md", got.Page.Name)
+	}
+	if want := "https://" + sample.ModulePath + "/blob/" + sample.VersionString + "/p/docs/index.md"; got.SourceURL != want {
+		t.Errorf("got source URL %q, want %q", got.SourceURL, want)
+	}
+	html := got.HTML.String()
+	if want := `href="?tab=docs&amp;page=guide%2Fsetup.md#readme-install"`; !strings.Contains(html, want) {
+		t.Errorf("HTML does not link to the other page with %s:\n%s", want, html)
+	}
+	if strings.Contains(html, "<script") {
+		t.Errorf("HTML is not sanitized:\n%s", html)
+	}
+
+	got, err = fetchDocsDetails(ctx, fds, NewLocalRenderer(), um, "guide/setup.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Page.Name != "guide/setup.md" || !got.Page.Selected {
+		t.Errorf("got page %+v, want guide/setup.md selected", got.Page)
+	}
+
+	_, err = fetchDocsDetails(ctx, fds, NewLocalRenderer(), um, "missing.md")
+	var serr *serrors.ServerError
+	if !errors.As(err, &serr) || serr.Status != http.StatusNotFound {
+		t.Errorf("missing page: got %v, want a %d error", err, http.StatusNotFound)
+	}
+}
+
+func TestDefaultDocsPage(t *testing.T) {
+	for _, test := range []struct {
+		names []string
+		want  int
+	}{
+		{[]string{"a.md", "b.md"}, 0},
+		{[]string{"a.md", "README.md"}, 1},
+		{[]string{"a.md", "index.markdown", "z.md"}, 1},
+		{[]string{"a.md", "sub/index.md"}, 0},
+	} {
+		var pages []*DocsPage
+		for _, n := range test.names {
+			pages = append(pages, &DocsPage{Name: n, Title: strings.TrimSuffix(n, n[strings.LastIndex(n, "."):])})
+		}
+		if got := defaultDocsPage(pages); got != test.want {
+			t.Errorf("defaultDocsPage(%q) = %d, want %d", test.names, got, test.want)
+		}
+	}
+}
+
+func TestFetchDocsDetailsStored(t *testing.T) {
+	ctx := context.Background()
+	m := sample.Module(sample.ModulePath, sample.VersionString, "p")
+	pkg := m.Packages()[0]
+	pkg.DocsPages = []*internal.Readme{{Filepath: "p/docs/index.md", Contents: "# Index\n"}}
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, m)
+	um, err := fds.GetUnitMeta(ctx, pkg.Path, sample.ModulePath, sample.VersionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := docrender.DocsPageHash(&docrender.DocsPageRequest{
+		ReadmeRequest: docrender.ReadmeRequest{Readme: pkg.DocsPages[0], SourceInfo: um.SourceInfo},
+		Pages:         map[string]string{"p/docs/index.md": "?tab=docs&page=index.md"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := fds.InsertRenderedReadmes(ctx, map[string][]byte{hash: []byte(`{"HTML": "<h1>Stored</h1>"}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := fetchDocsDetails(ctx, fds, NewLocalRenderer(), um, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<h1>Stored</h1>"; got.HTML.String() != want {
+		t.Errorf("got HTML %q, want stored %q", got.HTML, want)
+	}
+}
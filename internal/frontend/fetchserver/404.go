@@ -117,6 +117,11 @@ func (s *FetchServer) ServePathNotFoundPage(w http.ResponseWriter, r *http.Reque
 	case http.StatusInternalServerError:
 		return pathNotFoundError(ctx, fullPath, requestedVersion)
 	default:
+		if fr.packageFailure != nil {
+			// The path is a package that we could not process. Refetching the
+			// module would not help.
+			return serrors.PackageFailureError(fullPath, requestedVersion, fr.packageFailure)
+		}
 		if u := githubPathRedirect(fullPath); u != "" {
 			http.Redirect(w, r, u, http.StatusFound)
 			return
@@ -272,18 +277,37 @@ func previousFetchStatusAndResponse(ctx context.Context, db internal.PostgresDB,
 	if err != nil {
 		return nil, err
 	}
-	var fetchResults []*fetchResult
+	var (
+		fetchResults []*fetchResult
+		failure      *serrors.PackageFailure
+	)
 	for _, vm := range vms {
 		fr := fetchResultFromVersionMap(vm)
 		fetchResults = append(fetchResults, fr)
 		if vm.Status == http.StatusOK || vm.Status == 290 {
 			fr.err = errPathDoesNotExistInModule
 		}
+		if vm.Status == derrors.ToStatus(derrors.HasIncompletePackages) && failure == nil {
+			// The path may be a package of the module that could not be
+			// processed.
+			pvs, err := db.GetPackageVersionState(ctx, fullPath, vm.ModulePath, vm.ResolvedVersion)
+			if err != nil && !errors.Is(err, derrors.NotFound) {
+				return nil, err
+			}
+			failure = serrors.NewPackageFailure(pvs, nil)
+		}
 	}
 	if len(fetchResults) == 0 {
 		return nil, derrors.NotFound
 	}
-	return resultFromFetchRequest(fetchResults, fullPath, requestedVersion)
+	fr, err := resultFromFetchRequest(fetchResults, fullPath, requestedVersion)
+	if err != nil {
+		return nil, err
+	}
+	if fr.status == http.StatusNotFound {
+		fr.packageFailure = failure
+	}
+	return fr, nil
 }
 
 func fetchResultFromVersionMap(vm *internal.VersionMap) *fetchResult {
@@ -10,7 +10,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/go-redis/redis/v8"
@@ -488,6 +490,76 @@ func TestEmptyDirectoryBetweenNestedModulesRedirect(t *testing.T) {
 	}
 }
 
+func TestPackageFailure(t *testing.T) {
+	ctx := context.Background()
+	defer postgres.ResetTestDB(testDB, t)
+
+	const (
+		modulePath = "example.com/incomplete"
+		pkgPath    = modulePath + "/broken"
+	)
+	postgres.MustInsertModule(ctx, t, testDB, sample.Module(modulePath, sample.VersionString, "good"))
+	// The paths were requested before, so they were also tried as module paths.
+	for _, vm := range []*internal.VersionMap{
+		{ModulePath: modulePath, Status: derrors.ToStatus(derrors.HasIncompletePackages)},
+		{ModulePath: pkgPath, Status: http.StatusNotFound},
+		{ModulePath: modulePath + "/missing", Status: http.StatusNotFound},
+	} {
+		vm.RequestedVersion = version.Latest
+		vm.ResolvedVersion = sample.VersionString
+		vm.GoModPath = vm.ModulePath
+		if err := testDB.UpsertVersionMap(ctx, vm); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := testDB.InsertIndexVersions(ctx, []*internal.IndexVersion{
+		{Path: modulePath, Version: sample.VersionString, Timestamp: time.Now()},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := testDB.UpdateModuleVersionState(ctx, &postgres.ModuleVersionStateForUpdate{
+		ModulePath: modulePath,
+		Version:    sample.VersionString,
+		Timestamp:  time.Now(),
+		Status:     derrors.ToStatus(derrors.HasIncompletePackages),
+		GoModPath:  modulePath,
+		PackageVersionStates: []*internal.PackageVersionState{
+			{PackagePath: modulePath + "/good", ModulePath: modulePath, Version: sample.VersionString, Status: 200},
+			{
+				PackagePath: pkgPath,
+				ModulePath:  modulePath,
+				Version:     sample.VersionString,
+				Status:      derrors.ToStatus(derrors.PackageInvalidContents),
+				Diagnostics: []*internal.PackageDiagnostic{
+					{File: "broken/b.go", Line: 3, Column: 1, Message: "expected declaration, found x"},
+				},
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, handler, _ := newTestServerWithFetch(t, nil, nil)
+	for _, test := range []struct {
+		path     string
+		contains string
+	}{
+		{pkgPath, "broken/b.go:3:1: expected declaration, found x"},
+		{modulePath + "/missing", "could not be found"},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", "/"+test.path, nil))
+			if w.Code != http.StatusNotFound {
+				t.Errorf("got status code = %d, want %d", w.Code, http.StatusNotFound)
+			}
+			if !strings.Contains(w.Body.String(), test.contains) {
+				t.Errorf("body does not contain %q:\n%s", test.contains, w.Body)
+			}
+		})
+	}
+}
+
 func TestServerErrors(t *testing.T) {
 	_, _, handler, _ := newTestServerWithFetch(t, nil, nil)
 	for _, test := range []struct {
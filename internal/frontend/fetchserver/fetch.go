@@ -130,6 +130,10 @@ type fetchResult struct {
 
 	// Only populated if the fetchResult had a 2xx status.
 	resolvedVersion string
+
+	// packageFailure explains why the requested path has no documentation,
+	// if it is a package that could not be processed.
+	packageFailure *serrors.PackageFailure
 }
 
 func (s *FetchServer) fetchAndPoll(ctx context.Context, ds internal.DataSource, modulePath, fullPath, requestedVersion string) (status int, responseText string) {
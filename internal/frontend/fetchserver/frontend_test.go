@@ -25,6 +25,8 @@ type testModule struct {
 	redistributable bool
 	versions        []string
 	packages        []testPackage
+	authorMetadata  *internal.AuthorMetadata
+	checksum        *internal.ModuleChecksum
 }
 
 type testPackage struct {
@@ -49,6 +51,8 @@ func insertTestModules(ctx context.Context, t *testing.T, mods []testModule) {
 			m := sample.Module(mod.path, ver, suffixes...)
 			m.SourceInfo = source.NewGitHubInfo(sample.RepositoryURL, "", ver)
 			m.IsRedistributable = mod.redistributable
+			m.AuthorMetadata = mod.authorMetadata
+			m.Checksum = mod.checksum
 			if !m.IsRedistributable {
 				m.Licenses = nil
 			}
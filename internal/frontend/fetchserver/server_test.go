@@ -168,6 +168,17 @@ var testModules = []testModule{
 		path:            "github.com/links/mod",
 		redistributable: true,
 		versions:        []string{"v1.0.0"},
+		authorMetadata: &internal.AuthorMetadata{
+			Links: []*internal.AuthorLink{{Title: "Chat", URL: "https://example.com/chat"}},
+			Badges: []*internal.AuthorBadge{
+				{Title: "Build", ImageURL: "https://example.com/badge.svg", URL: "https://example.com/ci"},
+			},
+		},
+		checksum: &internal.ModuleChecksum{
+			ZipHash:   "h1:zip=",
+			GoModHash: "h1:gomod=",
+			Status:    internal.ChecksumVerified,
+		},
 		packages: []testPackage{
 			{
 				suffix:         "",
@@ -987,6 +998,16 @@ var linksTestCases = []serverTestCase{
 		urlPath:        "/github.com/links/mod",
 		wantStatusCode: http.StatusOK,
 		want: in("",
+			// Links from pkgsite.yaml.
+			checkLink("Chat", "https://example.com/chat"),
+			in(`[data-test-id="author-badges"]`,
+				in("a", href("https://example.com/ci")),
+				in("img", attr("src", "https://example.com/badge.svg"), attr("alt", "Build"))),
+			// The module version matches the checksum database.
+			in(`[data-test-id="UnitHeader-checksum"]`, hasText("Verified")),
+			in(`[data-test-id="unit-checksums"]`,
+				hasText("Matches the checksum database"),
+				hasText("github.com/links/mod v1.0.0/go.mod h1:gomod=")),
 			// Module readme links.
 			checkLink("title1", "http://url1"),
 			checkLink("title2", "about:invalid#zGoSafez"),
@@ -997,7 +1018,9 @@ var linksTestCases = []serverTestCase{
 		urlPath:        "/github.com/links/mod/no_readme",
 		wantStatusCode: http.StatusOK,
 		want: in("",
-			// Package doc links are first.
+			// Links from pkgsite.yaml apply to every package in the module.
+			checkLink("Chat", "https://example.com/chat"),
+			// Then package doc links.
 			checkLink("pkg.go.dev", "https://pkg.go.dev"),
 			// Then module readmes.
 			checkLink("title1", "http://url1"),
@@ -1009,11 +1032,13 @@ var linksTestCases = []serverTestCase{
 		urlPath:        "/github.com/links/mod/has_readme",
 		wantStatusCode: http.StatusOK,
 		want: in("",
-			// Package readme links are first.
+			// Links from pkgsite.yaml are first.
+			checkLink("Chat", "https://example.com/chat"),
+			// Package readme links are second.
 			checkLink("pkg title", "http://url2"),
-			// Package doc links are second.
+			// Package doc links are third.
 			checkLink("pkg.go.dev", "https://pkg.go.dev"),
-			// Module readme links are third.
+			// Module readme links are last.
 			checkLink("title1", "http://url1"),
 			checkLink("title2", "about:invalid#zGoSafez"),
 		),
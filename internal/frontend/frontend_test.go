@@ -35,17 +35,23 @@ type testPackage struct {
 	docs           []*internal.Documentation
 }
 
-func newTestServer(t *testing.T, cacher Cacher) (*Server, http.Handler) {
+// newTestServer returns a Server for cfg, and a handler that serves its
+// routes with cacher. If cfg has no DataSourceGetter, the server serves from an
+// empty fake data source. The templates and static files are always the
+// embedded ones.
+func newTestServer(t *testing.T, cacher Cacher, cfg ServerConfig) (*Server, http.Handler) {
 	t.Helper()
 
-	s, err := NewServer(ServerConfig{
-		DataSourceGetter: func(context.Context) internal.DataSource { return fakedatasource.New() },
-		TemplateFS:       template.TrustedFSFromEmbed(static.FS),
-		// Use the embedded FSs here to make sure they're tested.
-		// Integration tests will use the actual directories.
-		StaticFS:     static.FS,
-		ThirdPartyFS: thirdparty.FS,
-	})
+	if cfg.DataSourceGetter == nil {
+		ds := fakedatasource.New()
+		cfg.DataSourceGetter = func(context.Context) internal.DataSource { return ds }
+	}
+	cfg.TemplateFS = template.TrustedFSFromEmbed(static.FS)
+	// Use the embedded FSs here to make sure they're tested.
+	// Integration tests will use the actual directories.
+	cfg.StaticFS = static.FS
+	cfg.ThirdPartyFS = thirdparty.FS
+	s, err := NewServer(cfg)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -56,7 +62,7 @@ func newTestServer(t *testing.T, cacher Cacher) (*Server, http.Handler) {
 }
 
 func TestHTMLInjection(t *testing.T) {
-	_, handler := newTestServer(t, nil)
+	_, handler := newTestServer(t, nil, ServerConfig{})
 	w := httptest.NewRecorder()
 	handler.ServeHTTP(w, httptest.NewRequest("GET", "/<em>UHOH</em>", nil))
 	if strings.Contains(w.Body.String(), "<em>") {
@@ -130,7 +136,7 @@ func TestTagRoute(t *testing.T) {
 }
 
 func TestInstallFS(t *testing.T) {
-	s, handler := newTestServer(t, nil)
+	s, handler := newTestServer(t, nil, ServerConfig{})
 	s.InstallFS("/dir", os.DirFS("."))
 	// Request this file.
 	w := httptest.NewRecorder()
@@ -142,3 +148,23 @@ func TestInstallFS(t *testing.T) {
 		t.Errorf("body does not contain %q", want)
 	}
 }
+
+func TestIsAPIRequest(t *testing.T) {
+	for _, test := range []struct {
+		url  string
+		want bool
+	}{
+		{"/autocomplete?q=http", true},
+		{"/analysis/example.com/mod@v1.0.0", true},
+		{"/advisories/GO-2026-0001", true},
+		{"/example.com/mod?m=json", true},
+		{"/example.com/mod?m=llms", true},
+		{"/example.com/mod", false},
+		{"/example.com/mod?tab=versions", false},
+		{"/search?q=http", false},
+	} {
+		if got := IsAPIRequest(httptest.NewRequest("GET", test.url, nil)); got != test.want {
+			t.Errorf("IsAPIRequest(%q) = %t, want %t", test.url, got, test.want)
+		}
+	}
+}
@@ -51,6 +51,10 @@ type Homepage struct {
 	// LocalModules holds locally-hosted modules, for quick navigation.
 	// Empty in production.
 	LocalModules []LocalModule
+
+	// PopularPackages holds the most viewed unit paths. It is empty unless
+	// page views are counted.
+	PopularPackages []string
 }
 
 // LocalModule holds information about a locally-hosted module.
@@ -61,11 +65,20 @@ type LocalModule struct {
 	Dir        string `json:"Dir"`
 }
 
+// randomTipIndex returns the index of a random search tip. Tests replace it
+// to render the homepage deterministically.
+var randomTipIndex = func() int { return rand.Intn(len(searchTips)) }
+
 func (s *Server) serveHomepage(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var popular []string
+	if s.pageViews != nil {
+		popular = s.pageViews.Popular()
+	}
 	s.servePage(ctx, w, "homepage", Homepage{
-		BasePage:     s.newBasePage(r, "Go Packages"),
-		SearchTips:   searchTips,
-		TipIndex:     rand.Intn(len(searchTips)),
-		LocalModules: s.localModules,
+		BasePage:        s.newBasePage(r, "Go Packages"),
+		SearchTips:      searchTips,
+		TipIndex:        randomTipIndex(),
+		LocalModules:    s.localModules,
+		PopularPackages: popular,
 	})
 }
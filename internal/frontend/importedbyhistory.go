@@ -0,0 +1,159 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/safehtml"
+	"github.com/google/safehtml/uncheckedconversions"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// The size of the sparkline of imported-by counts, in pixels.
+const (
+	sparklineWidth  = 64
+	sparklineHeight = 16
+)
+
+// ImportedByTrend describes how the number of importers of a package changed
+// over the weeks of its recorded history, for display next to its
+// imported-by count.
+type ImportedByTrend struct {
+	// Sparkline is an inline SVG image of the weekly counts.
+	Sparkline safehtml.HTML
+	// Description summarizes the trend in words, for the sparkline's
+	// tooltip and for screen readers.
+	Description string
+	// DataURL is the URL of the weekly counts as JSON.
+	DataURL string
+}
+
+// getImportedByTrend returns the trend of the imported-by counts of the
+// package pkgPath, or nil if fewer than two weeks are recorded. The history
+// is stored only in the database, so nil is always returned for other data
+// sources.
+func getImportedByTrend(ctx context.Context, ds internal.DataSource, pkgPath string) *ImportedByTrend {
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return nil
+	}
+	history, err := db.GetImportedByHistory(ctx, pkgPath)
+	if err != nil {
+		// The trend is not essential to the page, so don't fail.
+		log.Errorf(ctx, "getImportedByTrend: %v", err)
+		return nil
+	}
+	return newImportedByTrend(pkgPath, history)
+}
+
+func newImportedByTrend(pkgPath string, history []*internal.ImportedByCountAt) *ImportedByTrend {
+	if len(history) < 2 {
+		return nil
+	}
+	counts := make([]int, len(history))
+	for i, h := range history {
+		counts[i] = h.Count
+	}
+	first, last := history[0], history[len(history)-1]
+	pr := message.NewPrinter(language.English)
+	return &ImportedByTrend{
+		Sparkline: sparkline(counts),
+		Description: pr.Sprintf("Imported by %d packages in the week of %s, and %d in the week of %s",
+			first.Count, absoluteTime(first.Week), last.Count, absoluteTime(last.Week)),
+		DataURL: importedByHistoryURL(pkgPath),
+	}
+}
+
+// sparkline returns an SVG line chart of counts, which must have at least
+// two elements, scaled to fit between the smallest and largest of them.
+func sparkline(counts []int) safehtml.HTML {
+	lo, hi := slices.Min(counts), slices.Max(counts)
+	const pad = 1 // leave room for the width of the line
+	var points []string
+	for i, c := range counts {
+		x := float64(i) * sparklineWidth / float64(len(counts)-1)
+		y := sparklineHeight / 2.0
+		if hi > lo {
+			y = pad + float64(hi-c)*(sparklineHeight-2*pad)/float64(hi-lo)
+		}
+		points = append(points, fmt.Sprintf("%.1f,%.1f", x, y))
+	}
+	// The SVG contains only numbers computed above, so it is safe.
+	return uncheckedconversions.HTMLFromStringKnownToSatisfyTypeContract(fmt.Sprintf(
+		`<svg class="UnitHeader-sparkline" width="%[1]d" height="%[2]d" viewBox="0 0 %[1]d %[2]d" `+
+			`aria-hidden="true" focusable="false"><polyline points="%[3]s" fill="none" `+
+			`stroke="currentColor" stroke-width="1.5" stroke-linejoin="round"/></svg>`,
+		sparklineWidth, sparklineHeight, strings.Join(points, " ")))
+}
+
+// importedByHistoryURL returns the URL of the JSON history of the
+// imported-by counts of the package pkgPath.
+func importedByHistoryURL(pkgPath string) string {
+	return "/imported-by-history?" + url.Values{"path": {pkgPath}}.Encode()
+}
+
+// importedByHistory is the response to a request to /imported-by-history.
+type importedByHistory struct {
+	Path  string               `json:"path"`
+	Weeks []importedByWeekJSON `json:"weeks"`
+}
+
+type importedByWeekJSON struct {
+	// Week is the Monday that starts the week, as YYYY-MM-DD.
+	Week  string `json:"week"`
+	Count int    `json:"count"`
+}
+
+// serveImportedByHistory serves the weekly imported-by counts of the package
+// in the "path" query param, oldest first, as a JSON importedByHistory. The
+// weeks are empty if none have been recorded for the path.
+func (s *Server) serveImportedByHistory(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveImportedByHistory(%q)", r.URL.RawQuery)
+
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return &serrors.ServerError{Status: http.StatusNotFound}
+	}
+	pkgPath := r.FormValue("path")
+	if pkgPath == "" {
+		return &serrors.ServerError{
+			Status:       http.StatusBadRequest,
+			ResponseText: "A package path is required, as in /imported-by-history?path=example.com/mod/pkg.",
+		}
+	}
+	history, err := db.GetImportedByHistory(r.Context(), pkgPath)
+	if err != nil {
+		return err
+	}
+	resp := importedByHistory{Path: pkgPath, Weeks: []importedByWeekJSON{}}
+	for _, h := range history {
+		resp.Weeks = append(resp.Weeks, importedByWeekJSON{
+			Week:  h.Week.In(time.UTC).Format(time.DateOnly),
+			Count: h.Count,
+		})
+	}
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %v", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("w.Write: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestSparkline(t *testing.T) {
+	for _, test := range []struct {
+		counts []int
+		want   string
+	}{
+		{[]int{1, 3, 2}, `points="0.0,15.0 32.0,1.0 64.0,8.0"`},
+		{[]int{5, 5}, `points="0.0,8.0 64.0,8.0"`},
+	} {
+		got := sparkline(test.counts).String()
+		if !strings.Contains(got, test.want) {
+			t.Errorf("sparkline(%v) = %s, want it to contain %s", test.counts, got, test.want)
+		}
+	}
+}
+
+func TestImportedByHistory(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, sample.Module("example.com/mod", "v1.0.0", "pkg", "other"))
+	week := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	fds.SetImportedByHistory("example.com/mod/pkg", []*internal.ImportedByCountAt{
+		{Week: week, Count: 1000},
+		{Week: week.AddDate(0, 0, 7), Count: 1200},
+	})
+	fds.SetImportedByHistory("example.com/mod/other", []*internal.ImportedByCountAt{
+		{Week: week, Count: 3},
+	})
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+	get := func(target string) (int, string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		b, err := io.ReadAll(w.Result().Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, string(b)
+	}
+
+	// Only packages with at least two recorded weeks show a sparkline.
+	const trend = `data-test-id="UnitHeader-importedbyTrend"`
+	_, body := get("/example.com/mod/pkg")
+	if !strings.Contains(body, trend) {
+		t.Errorf("page of pkg does not contain %s", trend)
+	}
+	if want := "Imported by 1,000 packages in the week of Mar  2, 2026, and 1,200 in the week of Mar  9, 2026"; !strings.Contains(body, want) {
+		t.Errorf("page of pkg does not contain %q", want)
+	}
+	if _, body := get("/example.com/mod/other"); strings.Contains(body, trend) {
+		t.Errorf("page of other contains %s", trend)
+	}
+
+	code, body := get(importedByHistoryURL("example.com/mod/pkg"))
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	var got importedByHistory
+	if err := json.Unmarshal([]byte(body), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := importedByHistory{
+		Path: "example.com/mod/pkg",
+		Weeks: []importedByWeekJSON{
+			{Week: "2026-03-02", Count: 1000},
+			{Week: "2026-03-09", Count: 1200},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	if _, body := get(importedByHistoryURL("example.com/none")); body != `{"path":"example.com/none","weeks":[]}` {
+		t.Errorf("got %s for a path without history", body)
+	}
+	if code, _ := get("/imported-by-history"); code != http.StatusBadRequest {
+		t.Errorf("without a path: status = %d, want %d", code, http.StatusBadRequest)
+	}
+}
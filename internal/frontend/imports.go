@@ -6,6 +6,7 @@ package frontend
 
 import (
 	"context"
+	"slices"
 	"strings"
 
 	"golang.org/x/pkgsite/internal"
@@ -22,15 +23,30 @@ type ImportsDetails struct {
 
 	// ExternalImports is the collection of package imports that are not in
 	// the Go standard library and are not part of the same module
-	ExternalImports []string
+	ExternalImports []*Import
 
 	// InternalImports is an array of packages representing the package's
 	// imports that are part of the same module.
-	InternalImports []string
+	InternalImports []*Import
 
 	// StdLib is an array of packages representing the package's imports
 	// that are in the Go standard library.
-	StdLib []string
+	StdLib []*Import
+
+	// NumImports is the number of packages imported by the package, and
+	// NumTestImports the number imported only by its tests.
+	NumImports, NumTestImports int
+}
+
+// Import is a package imported by a package or by its tests.
+type Import struct {
+	Path string
+
+	// Synopsis is the synopsis of the imported package, if it is known.
+	Synopsis string
+
+	// TestOnly reports whether the package is imported only by tests.
+	TestOnly bool
 }
 
 // fetchImportsDetails fetches imports for the package version specified by
@@ -47,23 +63,39 @@ func fetchImportsDetails(ctx context.Context, ds internal.DataSource, pkgPath, m
 		return nil, err
 	}
 
-	var externalImports, moduleImports, std []string
-	for _, p := range u.Imports {
-		if stdlib.Contains(p) {
-			std = append(std, p)
-		} else if strings.HasPrefix(p+"/", modulePath+"/") {
-			moduleImports = append(moduleImports, p)
-		} else {
-			externalImports = append(externalImports, p)
+	// Synopses come from search_documents, so they are only available
+	// with a database. They are not essential, so show the imports without
+	// them if they can't be read.
+	var synopses map[string]string
+	if db, ok := ds.(internal.PostgresDB); ok {
+		synopses, err = db.GetPackageSynopses(ctx, append(slices.Clip(u.Imports), u.TestImports...))
+		if err != nil {
+			log.Errorf(ctx, "imports of %s: %v", pkgPath, err)
+			synopses = nil
 		}
 	}
 
-	return &ImportsDetails{
-		ModulePath:      modulePath,
-		ExternalImports: externalImports,
-		InternalImports: moduleImports,
-		StdLib:          std,
-	}, nil
+	details := &ImportsDetails{
+		ModulePath:     modulePath,
+		NumImports:     len(u.Imports),
+		NumTestImports: len(u.TestImports),
+	}
+	add := func(paths []string, testOnly bool) {
+		for _, p := range paths {
+			imp := &Import{Path: p, Synopsis: synopses[p], TestOnly: testOnly}
+			if stdlib.Contains(p) {
+				details.StdLib = append(details.StdLib, imp)
+			} else if strings.HasPrefix(p+"/", modulePath+"/") {
+				details.InternalImports = append(details.InternalImports, imp)
+			} else {
+				details.ExternalImports = append(details.ExternalImports, imp)
+			}
+		}
+	}
+	// List the imports of the package before those of its tests.
+	add(u.Imports, false)
+	add(u.TestImports, true)
+	return details, nil
 }
 
 // ImportedByDetails contains information for the collection of packages that
@@ -6,6 +6,7 @@ package frontend
 
 import (
 	"context"
+	"errors"
 	"path"
 	"testing"
 
@@ -19,6 +20,7 @@ func TestFetchImportsDetails(t *testing.T) {
 	for _, test := range []struct {
 		name        string
 		imports     []string
+		testImports []string
 		wantDetails *ImportsDetails
 	}{
 		{
@@ -29,17 +31,30 @@ func TestFetchImportsDetails(t *testing.T) {
 				"context",
 			},
 			wantDetails: &ImportsDetails{
-				ExternalImports: []string{"pa.th/import/1"},
-				InternalImports: []string{sample.PackagePath},
-				StdLib:          []string{"context"},
+				ExternalImports: []*Import{{Path: "pa.th/import/1"}},
+				InternalImports: []*Import{{Path: sample.PackagePath, Synopsis: sample.Doc.Synopsis}},
+				StdLib:          []*Import{{Path: "context"}},
+				NumImports:      3,
 			},
 		},
 		{
 			name:    "want expected imports details with multiple",
 			imports: []string{"pa.th/import/1", "pa.th/import/2", "pa.th/import/3"},
 			wantDetails: &ImportsDetails{
-				ExternalImports: []string{"pa.th/import/1", "pa.th/import/2", "pa.th/import/3"},
+				ExternalImports: []*Import{{Path: "pa.th/import/1"}, {Path: "pa.th/import/2"}, {Path: "pa.th/import/3"}},
 				StdLib:          nil,
+				NumImports:      3,
+			},
+		},
+		{
+			name:        "test-only imports",
+			imports:     []string{"context", "pa.th/import/1"},
+			testImports: []string{"pa.th/import/2", "testing"},
+			wantDetails: &ImportsDetails{
+				ExternalImports: []*Import{{Path: "pa.th/import/1"}, {Path: "pa.th/import/2", TestOnly: true}},
+				StdLib:          []*Import{{Path: "context"}, {Path: "testing", TestOnly: true}},
+				NumImports:      2,
+				NumTestImports:  2,
 			},
 		},
 	} {
@@ -52,6 +67,7 @@ func TestFetchImportsDetails(t *testing.T) {
 			// The first unit is the module and the second one is the package.
 			pkg := module.Units[1]
 			pkg.Imports = test.imports
+			pkg.TestImports = test.testImports
 
 			fds.MustInsertModule(ctx, module)
 
@@ -69,6 +85,39 @@ func TestFetchImportsDetails(t *testing.T) {
 	}
 }
 
+// synopsisErrorDataSource is a data source whose package synopses can't be
+// read.
+type synopsisErrorDataSource struct {
+	*fakedatasource.FakeDataSource
+}
+
+func (synopsisErrorDataSource) GetPackageSynopses(context.Context, []string) (map[string]string, error) {
+	return nil, errors.New("synopses unavailable")
+}
+
+func TestFetchImportsDetailsSynopsisError(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	module := sample.Module(sample.ModulePath, sample.VersionString, sample.Suffix)
+	pkg := module.Units[1]
+	pkg.Imports = []string{sample.PackagePath, "context"}
+	fds.MustInsertModule(ctx, module)
+
+	got, err := fetchImportsDetails(ctx, synopsisErrorDataSource{fds}, pkg.Path, pkg.ModulePath, pkg.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ImportsDetails{
+		ModulePath:      module.ModulePath,
+		InternalImports: []*Import{{Path: sample.PackagePath}},
+		StdLib:          []*Import{{Path: "context"}},
+		NumImports:      2,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestFetchImportedByDetails(t *testing.T) {
 	fds := fakedatasource.New()
 	ctx := context.Background()
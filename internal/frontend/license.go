@@ -5,14 +5,23 @@
 package frontend
 
 import (
-	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/google/safehtml"
 	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/frontend/versions"
 	"golang.org/x/pkgsite/internal/licenses"
+	"golang.org/x/pkgsite/internal/log"
 )
 
 // License contains information used for a single license section.
@@ -20,6 +29,18 @@ type License struct {
 	*licenses.License
 	Anchor safehtml.Identifier
 	Source string
+
+	// Text is the contents of the license file for display, with line
+	// terminators normalized to "\n". The embedded License.Contents are
+	// unmodified, and are served by RawURL.
+	Text string
+
+	// RawURL is the URL of the unmodified contents of the license file.
+	RawURL string
+
+	// Confidence describes how much of the file matches known license text:
+	// "High", "Medium" or "Low".
+	Confidence string
 }
 
 // LicensesDetails contains license information for a package or module.
@@ -42,11 +63,107 @@ func fetchLicensesDetails(ctx context.Context, ds internal.DataSource, um *inter
 	if err != nil {
 		return nil, err
 	}
-	return &LicensesDetails{IsRedistributable: u.IsRedistributable, Licenses: transformLicenses(um.ModulePath, um.Version, u.LicenseContents)}, nil
+	ls := transformLicenses(um.ModulePath, um.Version, u.LicenseContents)
+	for i := range ls {
+		ls[i].RawURL = licenseRawURL(um, ls[i].FilePath)
+	}
+	return &LicensesDetails{IsRedistributable: u.IsRedistributable, Licenses: ls}, nil
+}
+
+// licenseRawURL returns the URL of the raw contents of the license file at
+// filePath, served from the page of um at its resolved version.
+func licenseRawURL(um *internal.UnitMeta, filePath string) string {
+	return versions.ConstructUnitURL(um.Path, um.ModulePath, um.Version) + "?" + url.Values{"tab": {tabLicenses}, "m": {"raw"}, "file": {filePath}}.Encode()
+}
+
+// Confidence thresholds, as the percentage of a license file that matches
+// known license text. The license detector reports files below the medium
+// threshold as UNKNOWN.
+const (
+	highLicenseConfidence   = 95
+	mediumLicenseConfidence = 75
+)
+
+// licenseConfidence describes the confidence of the detection of a license
+// file, from the percentage of the file that matches known license text.
+func licenseConfidence(percent float64) string {
+	switch {
+	case percent >= highLicenseConfidence:
+		return "High"
+	case percent >= mediumLicenseConfidence:
+		return "Medium"
+	default:
+		return "Low"
+	}
+}
+
+// serveLicenseRaw serves the unmodified contents of a license file that
+// applies to um, for the ?m=raw&file=<path> form of a unit page. It serves a
+// 404 if the unit is not redistributable, since its licenses tab is not
+// shown either, or if no license at that path applies to the unit.
+func serveLicenseRaw(ctx context.Context, w http.ResponseWriter, r *http.Request, ds internal.DataSource, um *internal.UnitMeta) (err error) {
+	defer derrors.Wrap(&err, "serveLicenseRaw(%q, %q, %q)", um.Path, um.ModulePath, um.Version)
+
+	filePath := r.FormValue("file")
+	if filePath == "" {
+		return &serrors.ServerError{
+			Status:       http.StatusBadRequest,
+			ResponseText: "A raw license requires the path of the license file, as in ?m=raw&file=LICENSE.",
+		}
+	}
+	u, err := ds.GetUnit(ctx, um, internal.WithMain|internal.WithLicenses, internal.BuildContext{})
+	if err != nil {
+		return err
+	}
+	if !u.IsRedistributable {
+		return &serrors.ServerError{Status: http.StatusNotFound}
+	}
+	for _, l := range u.LicenseContents {
+		if l.FilePath != filePath {
+			continue
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(filePath)))
+		if _, err := w.Write(l.Contents); err != nil {
+			log.Errorf(ctx, "serveLicenseRaw: w.Write: %v", err)
+		}
+		return nil
+	}
+	return &serrors.ServerError{
+		Status:       http.StatusNotFound,
+		ResponseText: fmt.Sprintf("No license file %q applies to %s.", filePath, um.Path),
+	}
+}
+
+// serveLicensesJSON serves how the license files that apply to um were
+// detected, as a JSON array of licenses.Detection sorted by file path, for
+// requests to the licenses tab with m=json. It serves no license text, so it
+// serves modules that are not redistributable too.
+func serveLicensesJSON(ctx context.Context, w http.ResponseWriter, ds internal.DataSource, um *internal.UnitMeta) (err error) {
+	defer derrors.Wrap(&err, "serveLicensesJSON(%q, %q, %q)", um.Path, um.ModulePath, um.Version)
+
+	u, err := ds.GetUnit(ctx, um, internal.WithMain|internal.WithLicenses, internal.BuildContext{})
+	if err != nil {
+		return err
+	}
+	detections := []*licenses.Detection{}
+	for _, l := range u.Licenses {
+		detections = append(detections, l.Detection())
+	}
+	sort.Slice(detections, func(i, j int) bool { return detections[i].FilePath < detections[j].FilePath })
+	data, err := json.Marshal(detections)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(data); err != nil {
+		log.Errorf(ctx, "serveLicensesJSON: w.Write: %v", err)
+	}
+	return nil
 }
 
 // transformLicenses transforms licenses.License into a License
-// by adding an anchor field.
+// by adding anchor, display and detection fields.
 func transformLicenses(modulePath, requestedVersion string, dbLicenses []*licenses.License) []License {
 	licenses := make([]License, len(dbLicenses))
 	var filePaths []string
@@ -55,11 +172,12 @@ func transformLicenses(modulePath, requestedVersion string, dbLicenses []*licens
 	}
 	anchors := licenseAnchors(filePaths)
 	for i, l := range dbLicenses {
-		l.Contents = bytes.ReplaceAll(l.Contents, []byte("\r"), nil)
 		licenses[i] = License{
-			Anchor:  anchors[i],
-			License: l,
-			Source:  fileSource(modulePath, requestedVersion, l.FilePath),
+			Anchor:     anchors[i],
+			License:    l,
+			Source:     fileSource(modulePath, requestedVersion, l.FilePath),
+			Text:       strings.ReplaceAll(string(l.Contents), "\r", ""),
+			Confidence: licenseConfidence(l.Coverage.Percent),
 		}
 	}
 	return licenses
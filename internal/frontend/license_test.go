@@ -5,13 +5,16 @@
 package frontend
 
 import (
-	"bytes"
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"sort"
 	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/licensecheck"
 	"github.com/google/safehtml"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/licenses"
@@ -139,15 +142,19 @@ func TestFetchLicensesDetails(t *testing.T) {
 		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
-			wantDetails := &LicensesDetails{IsRedistributable: true,
-				Licenses: transformLicenses(test.modulePath, test.version, test.want)}
-			got, err := fetchLicensesDetails(ctx, fds, &internal.UnitMeta{
+			um := &internal.UnitMeta{
 				Path: test.fullPath,
 				ModuleInfo: internal.ModuleInfo{
 					ModulePath: test.modulePath,
 					Version:    test.version,
 				},
-			})
+			}
+			wantDetails := &LicensesDetails{IsRedistributable: true,
+				Licenses: transformLicenses(test.modulePath, test.version, test.want)}
+			for i := range wantDetails.Licenses {
+				wantDetails.Licenses[i].RawURL = licenseRawURL(um, wantDetails.Licenses[i].FilePath)
+			}
+			got, err := fetchLicensesDetails(ctx, fds, um)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -157,10 +164,116 @@ func TestFetchLicensesDetails(t *testing.T) {
 				t.Errorf("mismatch (-want +got):\n%s", diff)
 			}
 			for _, l := range got.Licenses {
-				if bytes.Contains(l.Contents, []byte("\r")) {
-					t.Errorf("license %s contains \\r line terminators", l.Metadata.FilePath)
+				if strings.Contains(l.Text, "\r") {
+					t.Errorf("license %s text contains \\r line terminators", l.Metadata.FilePath)
 				}
 			}
 		})
 	}
 }
+
+func TestLicenseConfidence(t *testing.T) {
+	for _, test := range []struct {
+		percent float64
+		want    string
+	}{
+		{100, "High"},
+		{95, "High"},
+		{94.9, "Medium"},
+		{75, "Medium"},
+		{69.4, "Low"},
+		{0, "Low"},
+	} {
+		if got := licenseConfidence(test.percent); got != test.want {
+			t.Errorf("licenseConfidence(%g) = %q, want %q", test.percent, got, test.want)
+		}
+	}
+}
+
+func TestServeLicenseRaw(t *testing.T) {
+	ctx := context.Background()
+	crlf := strings.ReplaceAll(testhelper.MITLicense, "\n", "\r\n")
+	m := sample.Module("example.com/mod", "v1.0.0", "pkg")
+	m.Licenses = []*licenses.License{{
+		Metadata: &licenses.Metadata{Types: []string{"MIT"}, FilePath: "LICENSE"},
+		Contents: []byte(crlf),
+	}}
+	for _, u := range m.Units {
+		u.Licenses = []*licenses.Metadata{m.Licenses[0].Metadata}
+	}
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, m)
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	for _, test := range []struct {
+		path       string
+		wantStatus int
+	}{
+		{"/example.com/mod@v1.0.0/pkg?tab=licenses&m=raw&file=LICENSE", http.StatusOK},
+		{"/example.com/mod@v1.0.0?tab=licenses&m=raw&file=LICENSE", http.StatusOK},
+		{"/example.com/mod@v1.0.0/pkg?tab=licenses&m=raw&file=COPYING", http.StatusNotFound},
+		{"/example.com/mod@v1.0.0/pkg?tab=licenses&m=raw", http.StatusBadRequest},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			res := w.Result()
+			if res.StatusCode != test.wantStatus {
+				t.Fatalf("status = %d, want %d", res.StatusCode, test.wantStatus)
+			}
+			if test.wantStatus != http.StatusOK {
+				return
+			}
+			if got, want := res.Header.Get("Content-Disposition"), `attachment; filename="LICENSE"`; got != want {
+				t.Errorf("Content-Disposition = %q, want %q", got, want)
+			}
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			// The contents are served as stored, with their line terminators.
+			if string(b) != crlf {
+				t.Errorf("body = %q, want %q", b, crlf)
+			}
+		})
+	}
+}
+
+func TestServeLicensesJSON(t *testing.T) {
+	ctx := context.Background()
+	m := sample.Module("example.com/mod", "v1.0.0", "pkg")
+	m.Licenses = []*licenses.License{
+		{
+			Metadata: &licenses.Metadata{Types: []string{"MIT"}, FilePath: "LICENSE", Coverage: licensecheck.Coverage{Percent: 100}},
+			Contents: []byte(testhelper.MITLicense),
+		},
+		{
+			Metadata: &licenses.Metadata{Types: []string{"UNKNOWN"}, FilePath: "A/LICENSE", Coverage: licensecheck.Coverage{Percent: 10}},
+			Contents: []byte("unknown"),
+		},
+	}
+	for _, u := range m.Units {
+		u.Licenses = []*licenses.Metadata{m.Licenses[0].Metadata, m.Licenses[1].Metadata}
+	}
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, m)
+	_, handler := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/example.com/mod@v1.0.0/pkg?tab=licenses&m=json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got, want := w.Header().Get("Content-Type"), "application/json"; got != want {
+		t.Errorf("Content-Type = %q, want %q", got, want)
+	}
+	want := `[{"filePath":"A/LICENSE","types":["UNKNOWN"],"coverage":10},` +
+		`{"filePath":"LICENSE","types":["MIT"],"coverage":100}]`
+	if got := w.Body.String(); got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"golang.org/x/mod/semver"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docrender"
+	"golang.org/x/pkgsite/internal/experiment"
 	"golang.org/x/pkgsite/internal/frontend/serrors"
 	"golang.org/x/pkgsite/internal/godoc"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
@@ -50,6 +52,23 @@ type MainDetails struct {
 	// are displayed on the right sidebar.
 	ReadmeLinks []link
 
+	// ReadmeLang is the language tag of the displayed readme, if it is a
+	// localized one.
+	ReadmeLang string
+
+	// ReadmeLanguages are the languages the readme is available in. It is
+	// empty unless the unit has localized readmes.
+	ReadmeLanguages []*ReadmeLanguage
+
+	// ReadmeNegotiated is true if the readme was chosen using the
+	// Accept-Language header of the request, so the response varies with it.
+	ReadmeNegotiated bool
+
+	// QuickStart holds instructions extracted from the readme, shown at the
+	// top of the page. It is nil unless the readme-quick-start experiment is
+	// active.
+	QuickStart *QuickStart
+
 	// DocLinks are from the "Links" section of the Go package documentation,
 	// and are displayed on the right sidebar.
 	DocLinks []link
@@ -59,10 +78,23 @@ type MainDetails struct {
 	// See https://golang.org/issue/42968.
 	ModuleReadmeLinks []link
 
+	// DocsPages are the Markdown files under the docs directory of the unit,
+	// shown in its Docs tab. They are listed on the right sidebar.
+	DocsPages []*DocsPage
+
+	// AuthorLinks and AuthorBadges are declared by the module's authors in a
+	// pkgsite.yaml file. They are displayed on the right sidebar.
+	AuthorLinks  []link
+	AuthorBadges []*internal.AuthorBadge
+
 	// ImportedByCount is the number of packages that import this path.
 	// When the count is > limit it will read as 'limit+'. This field
-	// is not supported when using a datasource proxy.
+	// is not supported when using a datasource proxy. It is empty if the
+	// counts have not been backfilled for the module.
 	ImportedByCount string
+	// ImportedByTrend is the weekly history of ImportedByCount, or nil if
+	// fewer than two weeks are recorded.
+	ImportedByTrend *ImportedByTrend
 
 	DocBody       safehtml.HTML
 	DocOutline    safehtml.HTML
@@ -76,15 +108,42 @@ type MainDetails struct {
 	// GOOS and GOARCH are the build context for the doc.
 	GOOS, GOARCH string
 
+	// DocAppVersion and DocToolchain are the app version of the worker and
+	// the Go toolchain that processed the doc, if they were recorded.
+	DocAppVersion, DocToolchain string
+
+	// DocFeedbackURL is the URL of the page to report a problem with the
+	// doc, if reports can be made.
+	DocFeedbackURL string
+
+	// SkippedExamples are the examples in the package's test files that are
+	// not displayed correctly with the doc, so their authors can fix them.
+	SkippedExamples []*SkippedExample
+
+	// DocVersionsComment is an HTML comment with DocAppVersion and
+	// DocToolchain, written at the end of the doc.
+	DocVersionsComment safehtml.HTML
+
 	// BuildContexts holds the values for build contexts available for the doc.
 	BuildContexts []internal.BuildContext
 
+	// BuildExclusions explain which files of the package the doc leaves out
+	// because of their build constraints.
+	BuildExclusions []*BuildExclusion
+
 	// SourceFiles contains .go files for the package.
 	SourceFiles []*File
 
+	// SourceFileGroups contains SourceFiles grouped by build constraint.
+	SourceFileGroups []*FileGroup
+
 	// RepositoryURL is the URL to the repository containing the package.
 	RepositoryURL string
 
+	// RepoStats holds statistics about the repository, or nil if they are
+	// not available.
+	RepoStats *RepoStats
+
 	// SourceURL is the URL to the source of the package.
 	SourceURL string
 
@@ -94,6 +153,22 @@ type MainDetails struct {
 	// ModFileURL is an URL to the mod file.
 	ModFileURL string
 
+	// SBOMURL is the URL of the software bill of materials of the module
+	// version, or empty if it is not available.
+	SBOMURL string
+
+	// ModGraphURL is the URL of the dependency graph of the module version,
+	// or empty if it is not available.
+	ModGraphURL string
+
+	// Checksums are the checksums of the module version, or nil if they
+	// are unknown.
+	Checksums *Checksums
+
+	// HasAnalysis is true if external analyzers have posted reports for the
+	// module version.
+	HasAnalysis bool
+
 	// IsTaggedVersion is true if the version is not a psuedorelease.
 	IsTaggedVersion bool
 
@@ -102,19 +177,36 @@ type MainDetails struct {
 
 	// IsRedistributable is whether the unit is redistributable.
 	IsRedistributable bool
+
+	// PackageFailure explains why the unit has no documentation, if it has
+	// Go files that could not be processed as a package.
+	PackageFailure *serrors.PackageFailure
 }
 
 // File is a source file for a package.
 type File struct {
 	Name string
 	URL  string
+	// BuildConstraint is the file's build constraint, such as
+	// "linux && amd64", or empty if the file is built on all platforms.
+	BuildConstraint string
+	// Licenses are the license types declared in the file's header, if they
+	// differ from those of the package.
+	Licenses []string
+}
+
+// A FileGroup is a list of source files with the same build constraint.
+type FileGroup struct {
+	BuildConstraint string
+	Files           []*File
 }
 
 func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta,
-	requestedVersion string, expandReadme bool, bc internal.BuildContext) (_ *MainDetails, err error) {
+	requestedVersion string, expandReadme, expandExamples bool, readmeLang, acceptLanguage string,
+	bc, defaultBC internal.BuildContext, rd docrender.Renderer) (_ *MainDetails, err error) {
 	defer stats.Elapsed(ctx, "fetchMainDetails")()
 
-	unit, err := ds.GetUnit(ctx, um, internal.WithMain, bc)
+	unit, err := internal.GetUnitPreferring(ctx, ds, um, internal.WithMain, bc, defaultBC)
 	if err != nil {
 		return nil, err
 	}
@@ -126,17 +218,26 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 	if err != nil {
 		return nil, err
 	}
-	readme, err := readmeContent(ctx, unit)
+	selectedReadme, readmeLangs, langNegotiated := selectReadme(unit, readmeLang, acceptLanguage)
+	readme, err := readmeContent(ctx, ds, rd, unit, selectedReadme)
 	if err != nil {
 		return nil, err
 	}
+	var readmeLangTag string
+	if selectedReadme != nil {
+		readmeLangTag = selectedReadme.Lang
+	}
 	var (
 		docParts           = &dochtml.Parts{}
 		docLinks, modLinks []link
 		files              []*File
 		synopsis           string
 		goos, goarch       string
+		docAppVersion      string
+		docToolchain       string
+		skipped            []*SkippedExample
 		buildContexts      []internal.BuildContext
+		buildExclusions    []*BuildExclusion
 	)
 
 	unit.Documentation = cleanDocumentation(unit.Documentation)
@@ -146,14 +247,23 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 		doc = unit.Documentation[0]
 	}
 
+	ready := readyFeatures(ctx, ds, um.ModulePath)
 	if doc != nil {
+		if !ready[internal.FeatureSymbolHistory] {
+			// Don't annotate symbols with the versions that introduced
+			// them, since those would be wrong.
+			unit.SymbolHistory = nil
+		}
 		synopsis = doc.Synopsis
 		goos = doc.GOOS
 		goarch = doc.GOARCH
+		docAppVersion = doc.AppVersion
+		docToolchain = doc.Toolchain
+		skipped = skippedExamples(unit, doc)
 		buildContexts = unit.BuildContexts
-		end := stats.Elapsed(ctx, "DecodePackage")
-		docPkg, err := godoc.DecodePackage(doc.Source)
-		end()
+		buildExclusions = buildContextExclusions(doc)
+		var pkgFiles []*docrender.File
+		docParts, docLinks, pkgFiles, err = getHTML(ctx, rd, unit, bc, expandExamples)
 		if err != nil {
 			if errors.Is(err, godoc.ErrInvalidEncodingType) {
 				// Instead of returning a 500, return a 404 so the user can
@@ -163,18 +273,18 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 			}
 			return nil, err
 		}
-
-		docParts, err = getHTML(ctx, unit, docPkg, unit.SymbolHistory, bc)
-		// If err  is ErrTooLarge, then docBody will have an appropriate message.
-		if err != nil && !errors.Is(err, dochtml.ErrTooLarge) {
+		end := stats.Elapsed(ctx, "sourceFiles")
+		files = sourceFiles(unit, pkgFiles)
+		end()
+	}
+	var failure *serrors.PackageFailure
+	if doc == nil && unit.IsRedistributable {
+		// Diagnostics may quote the source, so only show them for
+		// redistributable units.
+		failure, err = packageFailure(ctx, ds, um)
+		if err != nil {
 			return nil, err
 		}
-		for _, l := range docParts.Links {
-			docLinks = append(docLinks, link{Href: l.Href, Body: l.Text})
-		}
-		end = stats.Elapsed(ctx, "sourceFiles")
-		files = sourceFiles(unit, docPkg)
-		end()
 	}
 	// If the unit is not a module, fetch the module readme to extract its
 	// links.
@@ -186,7 +296,7 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 			return nil, err
 		}
 		if err == nil {
-			rm, err := processReadme(ctx, modReadme, um.SourceInfo)
+			rm, err := renderReadme(ctx, ds, rd, modReadme, um.SourceInfo, um.AuthorMetadata.IssueLinksDisabled())
 			if err != nil {
 				return nil, err
 			}
@@ -194,6 +304,22 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 		}
 	}
 
+	var (
+		authorLinks  []link
+		authorBadges []*internal.AuthorBadge
+	)
+	if md := um.AuthorMetadata; md != nil {
+		for _, l := range md.Links {
+			authorLinks = append(authorLinks, link{Href: l.URL, Body: l.Title})
+		}
+		authorBadges = md.Badges
+	}
+
+	var quickStart *QuickStart
+	if experiment.IsActive(ctx, internal.ExperimentReadmeQuickStart) {
+		quickStart = readme.QuickStart
+	}
+
 	versionType, err := version.ParseType(um.Version)
 	if err != nil {
 		return nil, err
@@ -201,36 +327,103 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 	isTaggedVersion := versionType != version.TypePseudo
 	isStableVersion := semver.Major(um.Version) != "v0" && versionType == version.TypeRelease
 	pr := message.NewPrinter(language.English)
+	var (
+		importedByCount string
+		importedByTrend *ImportedByTrend
+	)
+	if ready[internal.FeatureScores] {
+		importedByCount = pr.Sprint(unit.NumImportedBy)
+		if unit.IsPackage() {
+			importedByTrend = getImportedByTrend(ctx, ds, um.Path)
+		}
+	}
 	return &MainDetails{
-		ExpandReadme:      expandReadme,
-		Directories:       unitDirectories(append(subdirectories, nestedModules...)),
-		Licenses:          transformLicenseMetadata(unit.Licenses),
-		CommitTime:        absoluteTime(um.CommitTime),
-		Readme:            readme.HTML,
-		ReadmeOutline:     readme.Outline,
-		ReadmeLinks:       readme.Links,
-		DocLinks:          docLinks,
-		ModuleReadmeLinks: modLinks,
-		DocOutline:        docParts.Outline,
-		DocBody:           docParts.Body,
-		DocSynopsis:       synopsis,
-		GOOS:              goos,
-		GOARCH:            goarch,
-		BuildContexts:     buildContexts,
-		SourceFiles:       files,
-		RepositoryURL:     um.SourceInfo.RepoURL(),
-		SourceURL:         um.SourceInfo.DirectoryURL(internal.Suffix(um.Path, um.ModulePath)),
-		MobileOutline:     docParts.MobileOutline,
-		NumImports:        pr.Sprint(unit.NumImports),
-		ImportedByCount:   pr.Sprint(unit.NumImportedBy),
-		IsPackage:         unit.IsPackage(),
-		ModFileURL:        um.SourceInfo.ModuleURL() + "/go.mod",
-		IsTaggedVersion:   isTaggedVersion,
-		IsStableVersion:   isStableVersion,
-		IsRedistributable: unit.IsRedistributable,
+		ExpandReadme:       expandReadme,
+		Directories:        unitDirectories(append(subdirectories, nestedModules...)),
+		Licenses:           transformLicenseMetadata(unit.Licenses),
+		CommitTime:         absoluteTime(um.CommitTime),
+		Readme:             readme.HTML,
+		ReadmeOutline:      readme.Outline,
+		ReadmeLinks:        readme.Links,
+		ReadmeLang:         readmeLangTag,
+		ReadmeLanguages:    readmeLangs,
+		ReadmeNegotiated:   langNegotiated,
+		QuickStart:         quickStart,
+		DocLinks:           docLinks,
+		ModuleReadmeLinks:  modLinks,
+		DocsPages:          docsPages(um, unit.DocsPages),
+		AuthorLinks:        authorLinks,
+		AuthorBadges:       authorBadges,
+		DocOutline:         docParts.Outline,
+		DocBody:            docParts.Body,
+		DocSynopsis:        synopsis,
+		GOOS:               goos,
+		GOARCH:             goarch,
+		DocAppVersion:      docAppVersion,
+		DocToolchain:       docToolchain,
+		SkippedExamples:    skipped,
+		DocVersionsComment: docVersionsComment(doc),
+		BuildContexts:      buildContexts,
+		BuildExclusions:    buildExclusions,
+		SourceFiles:        files,
+		SourceFileGroups:   groupFiles(files),
+		RepositoryURL:      um.SourceInfo.RepoURL(),
+		RepoStats:          getRepoStats(ctx, ds, um.SourceInfo.RepoURL()),
+		SourceURL:          um.SourceInfo.DirectoryURL(internal.Suffix(um.Path, um.ModulePath)),
+		MobileOutline:      docParts.MobileOutline,
+		NumImports:         pr.Sprint(unit.NumImports),
+		ImportedByCount:    importedByCount,
+		ImportedByTrend:    importedByTrend,
+		IsPackage:          unit.IsPackage(),
+		ModFileURL:         um.SourceInfo.ModuleURL() + "/go.mod",
+		SBOMURL:            sbomURL(ds, um),
+		ModGraphURL:        modGraphURL(ds, um),
+		Checksums:          moduleChecksums(um),
+		HasAnalysis:        hasAnalysisReports(ctx, ds, um),
+		IsTaggedVersion:    isTaggedVersion,
+		IsStableVersion:    isStableVersion,
+		IsRedistributable:  unit.IsRedistributable,
+		PackageFailure:     failure,
 	}, nil
 }
 
+// packageFailure returns the reason that the unit um has no documentation,
+// if its Go files could not be processed as a package. It returns nil if
+// there is no such reason, or if ds does not record package states.
+func packageFailure(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta) (*serrors.PackageFailure, error) {
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return nil, nil
+	}
+	pvs, err := db.GetPackageVersionState(ctx, um.Path, um.ModulePath, um.Version)
+	if err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return serrors.NewPackageFailure(pvs, um.SourceInfo), nil
+}
+
+// readyFeatures returns the data features that have been backfilled for the
+// module. Data sources that don't record the backfills compute every feature
+// when they load a module, so all features are ready for them.
+func readyFeatures(ctx context.Context, ds internal.DataSource, modulePath string) map[internal.DataFeature]bool {
+	db, ok := ds.(internal.PostgresDB)
+	if ok {
+		ready, err := db.GetReadyFeatures(ctx, modulePath)
+		if err == nil {
+			return ready
+		}
+		log.Errorf(ctx, "readyFeatures(%q): %v", modulePath, err)
+	}
+	ready := map[internal.DataFeature]bool{}
+	for _, f := range internal.DataFeatures {
+		ready[f] = true
+	}
+	return ready
+}
+
 func cleanDocumentation(docs []*internal.Documentation) []*internal.Documentation {
 	// If there is more than one row but the first is all/all, ignore the others.
 	// Should never happen;  temporary fix until the DB is cleaned up.
@@ -255,26 +448,29 @@ func cleanDocumentation(docs []*internal.Documentation) []*internal.Documentatio
 	return docs
 }
 
-// readmeContent renders the readme to html and collects the headings
-// into an outline.
-func readmeContent(ctx context.Context, u *internal.Unit) (_ *Readme, err error) {
+// readmeContent renders readme, one of the READMEs of u, to html and collects
+// the headings into an outline.
+func readmeContent(ctx context.Context, ds internal.DataSource, rd docrender.Renderer, u *internal.Unit, readme *internal.Readme) (_ *Readme, err error) {
 	defer derrors.Wrap(&err, "readmeContent(%q, %q, %q)", u.Path, u.ModulePath, u.Version)
 	defer stats.Elapsed(ctx, "readmeContent")()
 	if !u.IsRedistributable {
 		return &Readme{}, nil
 	}
-	return ProcessReadme(ctx, u)
+	return renderReadme(ctx, ds, rd, readme, u.SourceInfo, u.AuthorMetadata.IssueLinksDisabled())
 }
 
 const missingDocReplacement = `<p>Documentation is missing.</p>`
 
-func getHTML(ctx context.Context, u *internal.Unit, docPkg *godoc.Package,
-	nameToVersion map[string]string, bc internal.BuildContext) (_ *dochtml.Parts, err error) {
+// getHTML renders the documentation of u with rd, with its examples expanded
+// if expandExamples is true. It returns the rendered documentation, its
+// links, and the package's files.
+func getHTML(ctx context.Context, rd docrender.Renderer, u *internal.Unit,
+	bc internal.BuildContext, expandExamples bool) (_ *dochtml.Parts, _ []link, _ []*docrender.File, err error) {
 	defer derrors.Wrap(&err, "getHTML(%s)", u.Path)
 
 	if len(u.Documentation[0].Source) > 0 {
-		return renderDocParts(ctx, u, docPkg, nameToVersion, bc)
+		return renderUnitDoc(ctx, rd, u, bc, expandExamples)
 	}
 	log.Errorf(ctx, "unit %s (%s@%s) missing documentation source", u.Path, u.ModulePath, u.Version)
-	return &dochtml.Parts{Body: template.MustParseAndExecuteToHTML(missingDocReplacement)}, nil
+	return &dochtml.Parts{Body: template.MustParseAndExecuteToHTML(missingDocReplacement)}, nil, nil, nil
 }
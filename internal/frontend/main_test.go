@@ -6,6 +6,9 @@ package frontend
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -61,3 +64,84 @@ func TestGetImportedByCount(t *testing.T) {
 		})
 	}
 }
+
+func TestPackageFailure(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, sample.Module("example.com/mod", "v1.0.0", "broken/sub"))
+	fds.InsertPackageVersionState(&internal.PackageVersionState{
+		PackagePath: "example.com/mod/broken",
+		ModulePath:  "example.com/mod",
+		Version:     "v1.0.0",
+		Status:      604,
+		Diagnostics: []*internal.PackageDiagnostic{
+			{File: "broken/b.go", Line: 3, Column: 1, Message: "expected declaration, found x"},
+		},
+	})
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	for _, test := range []struct {
+		path string
+		want []string
+	}{
+		{
+			"/example.com/mod/broken@v1.0.0",
+			[]string{
+				"Why is documentation missing?",
+				"The package&#39;s files do not make up a valid Go package.",
+				">broken/b.go:3:1</a>: expected declaration, found x</code>",
+			},
+		},
+		{"/example.com/mod/broken/sub@v1.0.0", nil},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			body := w.Body.String()
+			for _, want := range test.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+			if test.want == nil && strings.Contains(body, "Why is documentation missing?") {
+				t.Error("body explains missing documentation, but there is none missing")
+			}
+		})
+	}
+}
+
+func TestImportedByCountNotReady(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	for _, path := range []string{"example.com/ready", "example.com/notready"} {
+		fds.MustInsertModule(ctx, sample.Module(path, "v1.0.0", "pkg"))
+	}
+	fds.SetFeatureNotReady("example.com/notready", internal.FeatureScores)
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	for _, test := range []struct {
+		path string
+		want bool
+	}{
+		{"/example.com/ready/pkg@v1.0.0", true},
+		{"/example.com/notready/pkg@v1.0.0", false},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			if got := strings.Contains(w.Body.String(), "UnitHeader-importedby"); got != test.want {
+				t.Errorf("shows imported-by count: got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/modgraph"
+)
+
+// serveModGraph serves the dependency graph of a module version, for requests
+// to /modgraph/<module>@<version>. The format query param selects SVG (the
+// default) or Graphviz DOT ("dot"), and the depth query param selects how
+// many levels of requirements are shown, from 1 (the default) to
+// modgraph.MaxDepth. Each node links to the unit page of its module version.
+func (s *Server) serveModGraph(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveModGraph(%q)", r.URL.Path)
+
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return serrors.DatasourceNotSupportedError()
+	}
+	modulePath, version, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/modgraph/"), "@")
+	if !ok || module.Check(modulePath, version) != nil {
+		return &serrors.ServerError{
+			Status:       http.StatusBadRequest,
+			ResponseText: "A module graph requires a module path and a full semantic version, as in /modgraph/example.com/mod@v1.2.3.",
+		}
+	}
+	format := r.FormValue("format")
+	switch format {
+	case "":
+		format = "svg"
+	case "svg", "dot":
+	default:
+		return &serrors.ServerError{
+			Status:       http.StatusBadRequest,
+			ResponseText: fmt.Sprintf("Unknown graph format %q; use %q or %q.", format, "svg", "dot"),
+		}
+	}
+	depth := 1
+	if d := r.FormValue("depth"); d != "" {
+		depth, err = strconv.Atoi(d)
+		if err != nil || depth < 1 || depth > modgraph.MaxDepth {
+			return &serrors.ServerError{
+				Status:       http.StatusBadRequest,
+				ResponseText: fmt.Sprintf("The depth must be a number from 1 to %d.", modgraph.MaxDepth),
+			}
+		}
+	}
+	ctx := r.Context()
+	if err := checkModuleAvailable(ctx, ds, modulePath, modulePath, version); err != nil {
+		return err
+	}
+	g, err := modgraph.Build(ctx, modulePath, version, depth, db.GetModuleRequirements)
+	if err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			return &serrors.ServerError{
+				Status:       http.StatusNotFound,
+				ResponseText: fmt.Sprintf("%s@%s has not been processed.", modulePath, version),
+			}
+		}
+		return err
+	}
+	url := func(n *modgraph.Node) string {
+		return "/" + n.ModulePath + "@" + n.Version
+	}
+	var buf bytes.Buffer
+	if format == "dot" {
+		err = g.WriteDOT(&buf, url)
+		w.Header().Set("Content-Type", "text/vnd.graphviz; charset=utf-8")
+	} else {
+		err = g.WriteSVG(&buf, url)
+		w.Header().Set("Content-Type", "image/svg+xml")
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// modGraphURL returns the URL of the dependency graph of the module version of
+// um, or the empty string if ds cannot produce one.
+func modGraphURL(ds internal.DataSource, um *internal.UnitMeta) string {
+	if _, ok := ds.(internal.PostgresDB); !ok {
+		return ""
+	}
+	return "/modgraph/" + um.ModulePath + "@" + um.Version
+}
@@ -0,0 +1,73 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestServeModGraph(t *testing.T) {
+	ctx := context.Background()
+	m := sample.Module("example.com/mod", "v1.0.0")
+	m.Requirements = []*internal.ModuleRequirement{{ModulePath: "example.com/dep", Version: "v1.2.3"}}
+	dep := sample.Module("example.com/dep", "v1.2.3")
+	dep.Requirements = []*internal.ModuleRequirement{{ModulePath: "example.com/deeper", Version: "v0.1.0", Indirect: true}}
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, m)
+	fds.MustInsertModule(ctx, dep)
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	for _, test := range []struct {
+		path            string
+		wantStatus      int
+		wantContentType string
+		want, dontWant  string
+	}{
+		{"/modgraph/example.com/mod@v1.0.0", http.StatusOK, "image/svg+xml", `href="/example.com/dep@v1.2.3"`, "example.com/deeper"},
+		{"/modgraph/example.com/mod@v1.0.0?format=dot", http.StatusOK, "text/vnd.graphviz; charset=utf-8", `n0 -> n1;`, "example.com/deeper"},
+		{"/modgraph/example.com/mod@v1.0.0?format=dot&depth=2", http.StatusOK, "text/vnd.graphviz; charset=utf-8", `n1 -> n2 [style=dashed];`, ""},
+		{"/modgraph/example.com/mod@v1.0.0?format=png", http.StatusBadRequest, "", "", ""},
+		{"/modgraph/example.com/mod@v1.0.0?depth=4", http.StatusBadRequest, "", "", ""},
+		{"/modgraph/example.com/mod@v1.0.0?depth=x", http.StatusBadRequest, "", "", ""},
+		{"/modgraph/example.com/mod@latest", http.StatusBadRequest, "", "", ""},
+		{"/modgraph/example.com/mod@v1.1.0", http.StatusNotFound, "", "", ""},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			res := w.Result()
+			if res.StatusCode != test.wantStatus {
+				t.Fatalf("status = %d, want %d", res.StatusCode, test.wantStatus)
+			}
+			if test.wantStatus != http.StatusOK {
+				return
+			}
+			if got := res.Header.Get("Content-Type"); got != test.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, test.wantContentType)
+			}
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(b), test.want) {
+				t.Errorf("body does not contain %q:\n%s", test.want, b)
+			}
+			if test.dontWant != "" && strings.Contains(string(b), test.dontWant) {
+				t.Errorf("body contains %q:\n%s", test.dontWant, b)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+)
+
+// A Namespace is a set of modules that the server serves from a data source
+// of their own. See config.Namespace.
+type Namespace struct {
+	*config.Namespace
+
+	// DataSourceGetter should return the DataSource of the modules of the
+	// namespace on each call. It should be goroutine-safe.
+	DataSourceGetter func(context.Context) internal.DataSource
+}
+
+// pathEndpoints are the endpoints served by errorHandler whose URL paths
+// end with the path of a module or package, like "/status/example.com/mod".
+var pathEndpoints = []string{"/fetch/", "/modgraph/", "/sbom/", "/status/", "/tree/"}
+
+// namespaceForRequest returns the namespace of the modules that r is about,
+// or nil if they are in none. The namespace of search requests is in the
+// "ns" query param, which the search forms on the pages of a namespace set.
+// Other requests are about the module or package in their URL path or in
+// their "path" query param. It reports false if the "ns" query param names
+// no namespace.
+func (s *Server) namespaceForRequest(r *http.Request) (*Namespace, bool) {
+	if len(s.namespaces) == 0 {
+		return nil, true
+	}
+	q := r.URL.Query()
+	if name := q.Get("ns"); name != "" {
+		for _, n := range s.namespaces {
+			if n.Name == name {
+				return n, true
+			}
+		}
+		return nil, false
+	}
+	urlPath := r.URL.Path
+	for _, e := range pathEndpoints {
+		if p, ok := strings.CutPrefix(urlPath, e); ok {
+			urlPath = p
+			break
+		}
+	}
+	// Remove the version, as in "/example.com/mod@v1.0.0/pkg".
+	urlPath, _, _ = strings.Cut(strings.TrimPrefix(urlPath, "/"), "@")
+	for _, p := range []string{q.Get("path"), urlPath, rawSearchQuery(r)} {
+		if ns := s.namespaceForPath(p); ns != nil {
+			return ns, true
+		}
+	}
+	return nil, true
+}
+
+// namespaceForPath returns the namespace with the longest prefix that
+// contains the module or package path, or nil if none does.
+func (s *Server) namespaceForPath(path string) *Namespace {
+	var ns *Namespace
+	for _, n := range s.namespaces {
+		if n.Contains(path) && (ns == nil || len(n.Prefix) > len(ns.Prefix)) {
+			ns = n
+		}
+	}
+	return ns
+}
+
+// serveFetch serves requests to fetch modules with the fetch server. The
+// modules of namespaces are not fetched on request, since the fetch server
+// queues them for the worker of the default data source: they are either
+// fetched from the module proxy of their namespace when they are requested,
+// or processed by a worker of their own.
+func (s *Server) serveFetch(w http.ResponseWriter, r *http.Request, ds internal.DataSource) error {
+	if namespaceFromContext(r.Context()) != nil {
+		return &serrors.ServerError{Status: http.StatusNotFound}
+	}
+	return s.fetchServer.ServeFetch(w, r, ds)
+}
+
+type namespaceKey struct{}
+
+// newNamespaceContext returns a context that holds ns.
+func newNamespaceContext(ctx context.Context, ns *Namespace) context.Context {
+	return context.WithValue(ctx, namespaceKey{}, ns)
+}
+
+// namespaceFromContext returns the namespace in ctx, or nil if there is
+// none.
+func namespaceFromContext(ctx context.Context) *Namespace {
+	ns, _ := ctx.Value(namespaceKey{}).(*Namespace)
+	return ns
+}
+
+// dataSource returns the DataSource of the namespace in ctx, or the default
+// one if there is none.
+func (s *Server) dataSource(ctx context.Context) internal.DataSource {
+	if ns := namespaceFromContext(ctx); ns != nil {
+		return ns.DataSourceGetter(ctx)
+	}
+	return s.getDataSource(ctx)
+}
+
+// dataSourceForModule returns the DataSource of the namespace that contains
+// modulePath, or the default one if none does. It is for handlers that
+// errorHandler does not serve, so that the namespace of the request is not
+// in their context.
+func (s *Server) dataSourceForModule(ctx context.Context, modulePath string) internal.DataSource {
+	if ns := s.namespaceForPath(modulePath); ns != nil {
+		return ns.DataSourceGetter(ctx)
+	}
+	return s.getDataSource(ctx)
+}
@@ -0,0 +1,147 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestNamespaceForRequest(t *testing.T) {
+	s := &Server{namespaces: []*Namespace{
+		{Namespace: &config.Namespace{Name: "corp", Prefix: "corp.example.com"}},
+		{Namespace: &config.Namespace{Name: "secret", Prefix: "corp.example.com/secret"}},
+	}}
+	for _, test := range []struct {
+		target string
+		want   string // name of the namespace
+		wantOK bool
+	}{
+		{"/", "", true},
+		{"/example.com/mod", "", true},
+		{"/corp.example.com", "corp", true},
+		{"/corp.example.com/mod@v1.0.0/pkg", "corp", true},
+		{"/corp.example.com@v1.0.0", "corp", true},
+		{"/corp.example.com/secret/mod", "secret", true},
+		{"/corp.example.com.evil/mod", "", true},
+		{"/github.com/corp.example.com", "", true},
+		{"/status/corp.example.com/mod", "corp", true},
+		{"/switch-version?path=corp.example.com/mod/pkg&module=corp.example.com/mod&version=v1.0.0", "corp", true},
+		{"/search?q=corp.example.com/mod", "corp", true},
+		{"/search?q=http&ns=secret", "secret", true},
+		{"/search?q=http", "", true},
+		{"/search?q=http&ns=bogus", "", false},
+	} {
+		ns, ok := s.namespaceForRequest(httptest.NewRequest("GET", test.target, nil))
+		var got string
+		if ns != nil {
+			got = ns.Name
+		}
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("%s: got %q, %t; want %q, %t", test.target, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestNamespaces(t *testing.T) {
+	ctx := context.Background()
+	publicDS := fakedatasource.New()
+	publicDS.MustInsertModule(ctx, sample.Module("example.com/mod", sample.VersionString, "pkg"))
+	corpDS := fakedatasource.New()
+	corpDS.MustInsertModule(ctx, sample.Module("corp.example.com/mod", sample.VersionString, "pkg"))
+
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return publicDS },
+		Namespaces: []*Namespace{{
+			Namespace:        &config.Namespace{Name: "corp", Prefix: "corp.example.com"},
+			DataSourceGetter: func(context.Context) internal.DataSource { return corpDS },
+		}},
+	})
+
+	get := func(target string) (int, string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		b, err := io.ReadAll(w.Result().Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, string(b)
+	}
+
+	for _, test := range []struct {
+		target     string
+		wantStatus int
+	}{
+		{"/example.com/mod/pkg", http.StatusOK},
+		{"/corp.example.com/mod/pkg", http.StatusOK},
+		{"/search?q=synopsis&ns=bogus", http.StatusNotFound},
+	} {
+		if got, _ := get(test.target); got != test.wantStatus {
+			t.Errorf("%s: status = %d, want %d", test.target, got, test.wantStatus)
+		}
+	}
+
+	// Suggestions come from the data source of the namespace in the "ns"
+	// param, even after the same prefix was completed for another one.
+	for _, test := range []struct {
+		target           string
+		want, notWant    string
+		wantCacheControl string
+	}{
+		{"/autocomplete?q=pkg", `"/example.com/mod/pkg"`, "corp.example.com", "public, max-age=600"},
+		{"/autocomplete?q=pkg&ns=corp", `"/corp.example.com/mod/pkg"`, `"/example.com/mod/pkg"`, "private, max-age=600"},
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", test.target, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want %d", test.target, w.Code, http.StatusOK)
+		}
+		body := w.Body.String()
+		if !strings.Contains(body, test.want) {
+			t.Errorf("%s: suggestions %s do not contain %q", test.target, body, test.want)
+		}
+		if strings.Contains(body, test.notWant) {
+			t.Errorf("%s: suggestions %s contain %q", test.target, body, test.notWant)
+		}
+		if got := w.Header().Get("Cache-Control"); got != test.wantCacheControl {
+			t.Errorf("%s: Cache-Control = %q, want %q", test.target, got, test.wantCacheControl)
+		}
+	}
+
+	// Searches from the pages of a namespace stay in it, and other searches
+	// do not see its modules.
+	_, body := get("/corp.example.com/mod/pkg")
+	if want := `<input name="ns" value="corp" hidden>`; !strings.Contains(body, want) {
+		t.Errorf("page of namespace does not contain %q", want)
+	}
+	for _, test := range []struct {
+		target        string
+		want, notWant string
+	}{
+		{"/search?q=synopsis&m=package", `href="/example.com/mod/pkg"`, "corp.example.com/mod"},
+		{"/search?q=synopsis&m=package&ns=corp", `href="/corp.example.com/mod/pkg"`, `href="/example.com/mod/pkg"`},
+	} {
+		code, body := get(test.target)
+		if code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want %d", test.target, code, http.StatusOK)
+		}
+		if !strings.Contains(body, test.want) {
+			t.Errorf("%s: results do not contain %q", test.target, test.want)
+		}
+		if strings.Contains(body, test.notWant) {
+			t.Errorf("%s: results contain %q", test.target, test.notWant)
+		}
+	}
+}
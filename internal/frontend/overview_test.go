@@ -5,34 +5,12 @@
 package frontend
 
 import (
-	"net/url"
 	"testing"
 
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/stdlib"
 )
 
-func TestTrimmedEscapedPath(t *testing.T) {
-	for _, test := range []struct {
-		in, want string
-	}{
-		{"a.png", "a.png"},
-		{" a.png   ", "a.png"},
-		{"a b.png", "a%20b.png"},
-		{" a b.png ", "a%20b.png"},
-		{".a/b.gif", ".a/b.gif"},
-	} {
-		u, err := url.Parse(test.in)
-		if err != nil {
-			t.Fatal(err)
-		}
-		got := trimmedEscapedPath(u)
-		if got != test.want {
-			t.Errorf("escapePath(%q) = %q, want %q", test.in, got, test.want)
-		}
-	}
-}
-
 func TestPackageSubdir(t *testing.T) {
 	for _, test := range []struct {
 		pkgPath, modulePath string
@@ -9,6 +9,7 @@ import (
 	"github.com/google/safehtml"
 	"github.com/google/safehtml/template"
 	"golang.org/x/pkgsite/internal/experiment"
+	"golang.org/x/pkgsite/internal/middleware/stats"
 )
 
 // BasePage contains fields shared by all pages when rendering templates.
@@ -57,6 +58,15 @@ type BasePage struct {
 	// SearchModeSymbol is the value of const searchModeSymbol. It is used in
 	// the search bar dropdown.
 	SearchModeSymbol string
+
+	// Namespace is the name of the namespace of the modules that the page
+	// is about, if any. Searches from the page are limited to it.
+	Namespace string
+
+	// Timings are the times spent in each stage of the request before the
+	// page was constructed. They are displayed in the footer when the
+	// "timing" debug directive is set.
+	Timings []stats.StageTiming
 }
 
 func (p *BasePage) SetBasePage(bp BasePage) {
@@ -0,0 +1,39 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"net/http"
+
+	"golang.org/x/pkgsite/internal/frontend/urlinfo"
+)
+
+// countPageViews returns a handler that serves requests with h, and counts a
+// view of the unit for each successful GET of a unit page.
+func (s *Server) countPageViews(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w2 := &viewsResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(w2, r)
+		if r.Method != http.MethodGet || w2.status != http.StatusOK || r.URL.Path == "/" {
+			return
+		}
+		info, err := urlinfo.ParseDetailsURLPath(r.URL.Path)
+		if err != nil {
+			return
+		}
+		s.pageViews.Record(info.FullPath)
+	})
+}
+
+// viewsResponseWriter records the status of a response.
+type viewsResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (rw *viewsResponseWriter) WriteHeader(code int) {
+	rw.status = code
+	rw.ResponseWriter.WriteHeader(code)
+}
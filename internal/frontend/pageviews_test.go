@@ -0,0 +1,66 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/pageviews"
+)
+
+// viewStore is a pageviews.Store that records the views added to it.
+type viewStore struct {
+	views map[string]int64
+}
+
+func (s *viewStore) AddUnitViews(_ context.Context, _ time.Time, counts map[string]int64) error {
+	for p, n := range counts {
+		s.views[p] += n
+	}
+	return nil
+}
+
+func (s *viewStore) GetPopularUnitPaths(context.Context, time.Time, int) ([]string, error) {
+	return nil, nil
+}
+
+func (s *viewStore) DeleteUnitViewsBefore(context.Context, time.Time) error {
+	return nil
+}
+
+func TestCountPageViews(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := &viewStore{views: map[string]int64{}}
+	s := &Server{pageViews: pageviews.NewCounter(ctx, store, time.Hour, nil)}
+	handler := s.countPageViews(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/example.com/missing" {
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}))
+	for _, req := range []struct{ method, path string }{
+		{"GET", "/"},
+		{"GET", "/example.com/pkg"},
+		{"GET", "/example.com/pkg@v1.2.3"},
+		{"GET", "/example.com/pkg?tab=versions"},
+		{"GET", "/example.com/missing"},
+		{"HEAD", "/example.com/pkg"},
+		{"GET", "/std"},
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(req.method, req.path, nil))
+	}
+	if err := s.pageViews.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int64{"example.com/pkg": 3, "std": 1}
+	if diff := cmp.Diff(want, store.views); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
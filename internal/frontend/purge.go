@@ -0,0 +1,112 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// purgeResult is the response of the purge-cache endpoint.
+type purgeResult struct {
+	Path    string `json:"path"`
+	Deleted int    `json:"deleted"`
+}
+
+// handlePurgeCache deletes the cached pages for a path and everything below it,
+// for requests to /_admin/purge-cache?path=<path>&reason=<reason>. It is used
+// after a takedown or a reprocess, when cached pages must not be served until
+// they expire. The pages deleted are those of the path itself and of its
+// versions, tabs and subdirectories; see cache.Cache.DeletePath. Search
+// results are not purged: they expire on their own after a few hours.
+//
+// To purge all major versions of a module, purge its series path: purging
+// example.com/mod also purges example.com/mod/v2.
+//
+// Each purge is logged, along with its reason and the address of the
+// requester; see Server.clientAddr. The handler must only be installed behind authentication.
+func (s *Server) handlePurgeCache(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if s.pageCache == nil {
+		http.Error(w, "there is no page cache", http.StatusNotFound)
+		return
+	}
+	path := strings.Trim(r.FormValue("path"), "/")
+	reason := r.FormValue("reason")
+	switch {
+	case path == "":
+		http.Error(w, "missing path", http.StatusBadRequest)
+		return
+	case path == "search":
+		// The page cache keys of search results begin with /search?.
+		http.Error(w, "search results cannot be purged", http.StatusBadRequest)
+		return
+	case reason == "":
+		http.Error(w, "missing reason", http.StatusBadRequest)
+		return
+	}
+	requester := s.clientAddr(r)
+	n, err := s.pageCache.DeletePath(ctx, path)
+	if err != nil {
+		log.Errorf(ctx, "purge-cache: path %q, reason %q, requested by %s: deleted %d pages, then failed: %v",
+			path, reason, requester, n, err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Infof(ctx, "purge-cache: path %q, reason %q, requested by %s: deleted %d pages", path, reason, requester, n)
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(purgeResult{Path: path, Deleted: n}); err != nil {
+		log.Errorf(ctx, "purge-cache: writing response: %v", err)
+	}
+}
+
+// purgeDelay is how long a pagePurger waits before it purges a path.
+const purgeDelay = 10 * time.Second
+
+// A pagePurger purges the cached pages of paths in the background, for
+// requests that change what the pages show but must not wait for the purge.
+// Requests to purge a path while a purge of it is pending are merged into
+// that purge, so a burst of them purges the path once.
+type pagePurger struct {
+	cache PageCache
+	delay time.Duration
+
+	mu      sync.Mutex
+	pending map[string]bool // paths with a purge scheduled
+}
+
+func newPagePurger(cache PageCache, delay time.Duration) *pagePurger {
+	return &pagePurger{cache: cache, delay: delay, pending: map[string]bool{}}
+}
+
+// purge schedules a purge of the cached pages of path, as by
+// PageCache.DeletePath, after p.delay. Errors are logged; the pages are
+// then served until they expire.
+func (p *pagePurger) purge(path string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pending[path] {
+		return
+	}
+	p.pending[path] = true
+	time.AfterFunc(p.delay, func() {
+		// A request to purge path from now on may come after the purge has
+		// read what it changed, so it schedules another purge.
+		p.mu.Lock()
+		delete(p.pending, path)
+		p.mu.Unlock()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if _, err := p.cache.DeletePath(ctx, path); err != nil {
+			log.Errorf(ctx, "purging cached pages of %s: %v", path, err)
+		}
+	})
+}
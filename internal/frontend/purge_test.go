@@ -0,0 +1,150 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/config"
+)
+
+// fakePageCache is a PageCache that holds a set of page keys.
+type fakePageCache map[string]bool
+
+// DeletePath deletes the keys for path and the paths below it, like
+// cache.Cache.DeletePath.
+func (c fakePageCache) DeletePath(_ context.Context, path string) (int, error) {
+	n := 0
+	for k := range c {
+		rest, ok := strings.CutPrefix(k, "/"+path)
+		if ok && (rest == "" || strings.ContainsAny(rest[:1], "/@?")) {
+			delete(c, k)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func TestPurgeCache(t *testing.T) {
+	const debugValue = "secret"
+	t.Setenv("GO_DISCOVERY_DEBUG_HEADER_VALUE", debugValue)
+	pages := fakePageCache{}
+	for _, k := range []string{
+		"/a.com/m", "/a.com/m@v1.0.0?tab=versions", "/a.com/m/pkg",
+		"/a.com/module", "/search?q=a.com/m",
+	} {
+		pages[k] = true
+	}
+	s, handler := newTestServer(t, nil, ServerConfig{})
+	s.pageCache = pages
+
+	purge := func(debug string, form url.Values) *httptest.ResponseRecorder {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodPost, "/_admin/purge-cache", strings.NewReader(form.Encode()))
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if debug != "" {
+			r.Header.Set(config.AllowDebugHeader, debug)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+	form := func(path, reason string) url.Values {
+		return url.Values{"path": {path}, "reason": {reason}}
+	}
+
+	for _, test := range []struct {
+		name  string
+		debug string
+		form  url.Values
+		want  int
+	}{
+		{"unauthenticated", "", form("a.com/m", "takedown"), http.StatusNotFound},
+		{"wrong secret", "wrong", form("a.com/m", "takedown"), http.StatusNotFound},
+		{"no path", debugValue, form("", "takedown"), http.StatusBadRequest},
+		{"no reason", debugValue, form("a.com/m", ""), http.StatusBadRequest},
+		{"search", debugValue, form("search", "takedown"), http.StatusBadRequest},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if w := purge(test.debug, test.form); w.Code != test.want {
+				t.Errorf("got status %d, want %d", w.Code, test.want)
+			}
+		})
+	}
+	if n := len(pages); n != 5 {
+		t.Fatalf("rejected requests deleted pages: %d left, want 5", n)
+	}
+
+	w := purge(debugValue, form("/a.com/m", "takedown"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want 200; body: %s", w.Code, w.Body)
+	}
+	if got, want := strings.TrimSpace(w.Body.String()), `{"path":"a.com/m","deleted":3}`; got != want {
+		t.Errorf("got response %s, want %s", got, want)
+	}
+	got := slices.Sorted(maps.Keys(pages))
+	if want := []string{"/a.com/module", "/search?q=a.com/m"}; !cmp.Equal(got, want) {
+		t.Errorf("remaining keys: got %v, want %v", got, want)
+	}
+}
+
+// countingPageCache is a PageCache that counts the purges of each path.
+type countingPageCache struct {
+	mu     sync.Mutex
+	purges map[string]int
+}
+
+func (c *countingPageCache) DeletePath(_ context.Context, path string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.purges[path]++
+	return 0, nil
+}
+
+func (c *countingPageCache) count(path string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.purges[path]
+}
+
+func TestPagePurger(t *testing.T) {
+	const delay = 50 * time.Millisecond
+	pages := &countingPageCache{purges: map[string]int{}}
+	p := newPagePurger(pages, delay)
+	for range 3 {
+		p.purge("a.com/m")
+	}
+	p.purge("b.com/m")
+	if n := pages.count("a.com/m"); n != 0 {
+		t.Fatalf("purged %d times before the delay, want 0", n)
+	}
+	waitFor := func(path string, want int) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(delay) {
+			if pages.count(path) >= want {
+				break
+			}
+		}
+		if got := pages.count(path); got != want {
+			t.Errorf("%s purged %d times, want %d", path, got, want)
+		}
+	}
+	waitFor("a.com/m", 1)
+	waitFor("b.com/m", 1)
+
+	// A purge after the scheduled one has run is scheduled again.
+	p.purge("a.com/m")
+	waitFor("a.com/m", 2)
+}
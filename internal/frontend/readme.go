@@ -5,11 +5,16 @@
 package frontend
 
 import (
-	"bytes"
+	"context"
 
 	"github.com/google/safehtml"
-	"github.com/google/safehtml/uncheckedconversions"
-	"golang.org/x/pkgsite/internal/sanitizer"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docrender"
+	"golang.org/x/pkgsite/internal/docrender/readme"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
 )
 
 // Heading holds data about a heading and nested headings within a readme.
@@ -26,20 +31,143 @@ type Heading struct {
 	ID string
 	// Children are nested headings.
 	Children []*Heading
-	// parent is the heading this heading is nested within. Nil for top
-	// level headings.
-	parent *Heading
 }
 
 // Readme holds the result of processing a REAME file.
 type Readme struct {
-	HTML    safehtml.HTML // rendered HTML
-	Outline []*Heading    // document headings
-	Links   []link        // links from the "Links" section
+	HTML       safehtml.HTML // rendered HTML
+	Outline    []*Heading    // document headings
+	Links      []link        // links from the "Links" section
+	QuickStart *QuickStart   // quick start instructions, or nil
 }
 
-// sanitizeHTML sanitizes HTML from a bytes.Buffer so that it is safe.
-func sanitizeHTML(b *bytes.Buffer) safehtml.HTML {
-	s := string(sanitizer.SanitizeBytes(b.Bytes()))
-	return uncheckedconversions.HTMLFromStringKnownToSatisfyTypeContract(s)
+// QuickStart holds instructions for getting started with a module, extracted
+// from the code blocks of its README.
+type QuickStart struct {
+	// Command is the first "go get" or "go install" command in the README,
+	// without any shell prompt.
+	Command string
+	// Code is the first Go code block in the README, truncated to
+	// maxQuickStartLines lines.
+	Code string
+}
+
+// ProcessReadme processes the README of unit u, if it has one.
+// Processing includes rendering and sanitizing the HTML or Markdown,
+// and extracting headings and links; see readme.Render.
+//
+// This function is exported for use by external tools.
+func ProcessReadme(ctx context.Context, u *internal.Unit) (_ *Readme, err error) {
+	defer derrors.WrapAndReport(&err, "ProcessReadme(%q, %q, %q)", u.Path, u.ModulePath, u.Version)
+	return processReadme(ctx, u.Readme, u.SourceInfo, u.AuthorMetadata.IssueLinksDisabled())
+}
+
+// processReadme processes r. Unless noIssueLinks is true, references
+// to issues such as "#1234" are linked to the repository described by info.
+func processReadme(ctx context.Context, r *internal.Readme, info *source.Info, noIssueLinks bool) (*Readme, error) {
+	resp, err := readme.Render(ctx, &docrender.ReadmeRequest{
+		Readme:       r,
+		SourceInfo:   info,
+		NoIssueLinks: noIssueLinks,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return readmeFromResponse(resp), nil
+}
+
+// readmeLangParam is the query parameter that selects one of a unit's
+// localized READMEs by language tag. The value readmeLangDefault selects
+// the main README.
+const (
+	readmeLangParam   = "readme-lang"
+	readmeLangDefault = "default"
+)
+
+// ReadmeLanguage is an entry of the README language switcher.
+type ReadmeLanguage struct {
+	// Param is the value of the readme-lang query parameter that selects
+	// the README.
+	Param string
+	// Lang is the BCP 47 tag of the README, or empty for the main README
+	// if its language is unknown.
+	Lang string
+	// Name is the name of the language in that language, such as "日本語".
+	Name     string
+	Selected bool
+}
+
+// selectReadme chooses which of u's READMEs to display: the one named by
+// param, the value of the readme-lang query parameter, if there is one, or
+// else the best match for acceptLanguage, the value of the Accept-Language
+// header. The main README is the default.
+//
+// It also returns the languages for the switcher, which is empty if u has no
+// localized READMEs, and whether the choice depended on acceptLanguage.
+func selectReadme(u *internal.Unit, param, acceptLanguage string) (_ *internal.Readme, _ []*ReadmeLanguage, negotiated bool) {
+	if u.Readme == nil || len(u.LocalizedReadmes) == 0 {
+		return u.Readme, nil, false
+	}
+	// The main README is assumed to be in English, unless one of the
+	// localized READMEs is.
+	mainTag := language.English
+	tags := []language.Tag{mainTag}
+	for _, r := range u.LocalizedReadmes {
+		t, err := language.Parse(r.Lang)
+		if err != nil {
+			t = language.Und
+		}
+		if b, _ := t.Base(); b.String() == "en" {
+			mainTag = language.Und
+		}
+		tags = append(tags, t)
+	}
+	tags[0] = mainTag
+
+	selected := -1
+	if param == readmeLangDefault {
+		selected = 0
+	} else if param != "" {
+		for i, r := range u.LocalizedReadmes {
+			if r.Lang == param {
+				selected = i + 1
+			}
+		}
+	}
+	if selected < 0 {
+		negotiated = true
+		selected = 0
+		if desired, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil && len(desired) > 0 {
+			_, i, conf := language.NewMatcher(tags).Match(desired...)
+			if conf != language.No {
+				selected = i
+			}
+		}
+	}
+
+	langs := []*ReadmeLanguage{{
+		Param:    readmeLangDefault,
+		Name:     "Default",
+		Selected: selected == 0,
+	}}
+	if mainTag != language.Und {
+		langs[0].Lang = mainTag.String()
+		langs[0].Name = display.Tags(mainTag).Name(mainTag)
+	}
+	for i, r := range u.LocalizedReadmes {
+		name := display.Tags(tags[i+1]).Name(tags[i+1])
+		if name == "" {
+			name = r.Lang
+		}
+		langs = append(langs, &ReadmeLanguage{
+			Param:    r.Lang,
+			Lang:     r.Lang,
+			Name:     name,
+			Selected: selected == i+1,
+		})
+	}
+	if selected == 0 {
+		return u.Readme, langs, negotiated
+	}
+	return u.LocalizedReadmes[selected-1], langs, negotiated
 }
@@ -6,15 +6,18 @@ package frontend
 
 import (
 	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"unicode"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/google/go-cmp/cmp/cmpopts"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/experiment"
 	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
 	"golang.org/x/pkgsite/internal/testing/sample"
 )
 
@@ -490,9 +493,7 @@ func TestReadme(t *testing.T) {
 			if diff := cmp.Diff(test.wantHTML, gotHTML); diff != "" {
 				t.Errorf("Readme(%v) html mismatch (-want +got):\n%s", test.unit.UnitMeta, diff)
 			}
-			if diff := cmp.Diff(test.wantOutline, readme.Outline, cmp.Options{
-				cmpopts.IgnoreUnexported(Heading{}),
-			}); diff != "" {
+			if diff := cmp.Diff(test.wantOutline, readme.Outline); diff != "" {
 				t.Errorf("Readme(%v) outline mismatch (-want +got):\n%s", test.unit.UnitMeta, diff)
 			}
 		})
@@ -612,8 +613,165 @@ func TestReadmeLinks(t *testing.T) {
 	}
 }
 
+func TestReadmeIssueLinks(t *testing.T) {
+	ctx := experiment.NewContext(context.Background())
+	unit := sample.UnitEmpty(sample.PackagePath, sample.ModulePath, sample.VersionString)
+	unit.SourceInfo = source.NewGitHubInfo("https://github.com/a/b", "", "v1.0.0")
+	unit.Readme = &internal.Readme{
+		Filepath: "README.md",
+		Contents: unindent(`
+			# Fixes for #77
+
+			Fixes #123 (and *GH-45*). Not [#99](https://example.com), ` + "`#100`" + `, step #1 or C#12.
+		`),
+	}
+	got, err := ProcessReadme(ctx, unit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<h3 class="h1" id="readme-fixes-for-77">Fixes for #77</h3>
+<p>Fixes <a href="https://github.com/a/b/issues/123" rel="nofollow">#123</a> (and <em><a href="https://github.com/a/b/issues/45" rel="nofollow">GH-45</a></em>). Not <a href="https://example.com" rel="nofollow">#99</a>, <code>#100</code>, step #1 or C#12.</p>
+`
+	if diff := cmp.Diff(want, got.HTML.String()); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	unit.AuthorMetadata = &internal.AuthorMetadata{NoIssueLinks: true}
+	got, err = ProcessReadme(ctx, unit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := got.HTML.String(); strings.Contains(h, "/issues/") {
+		t.Errorf("issue links are off, but got %s", h)
+	}
+}
+
 // unindent removes indentation from s. It assumes that s starts with an initial
 // newline followed by one or more indented lines.
+func TestSelectReadme(t *testing.T) {
+	main := &internal.Readme{Filepath: "README.md", Contents: "hello"}
+	ja := &internal.Readme{Filepath: "README.ja.md", Contents: "こんにちは", Lang: "ja"}
+	zh := &internal.Readme{Filepath: "README.zh-CN.md", Contents: "你好", Lang: "zh-CN"}
+	u := &internal.Unit{Readme: main, LocalizedReadmes: []*internal.Readme{ja, zh}}
+
+	for _, test := range []struct {
+		name, param, acceptLanguage string
+		want                        *internal.Readme
+		wantNegotiated              bool
+	}{
+		{"no preference", "", "", main, true},
+		{"accept english", "", "en-US,en;q=0.9", main, true},
+		{"accept japanese", "", "ja-JP,ja;q=0.9,en;q=0.8", ja, true},
+		{"accept chinese", "", "zh-CN", zh, true},
+		{"accept unavailable", "", "fr", main, true},
+		{"malformed header", "", "!!", main, true},
+		{"param", "zh-CN", "ja", zh, false},
+		{"param default", "default", "ja", main, false},
+		{"unknown param", "de", "ja", ja, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, langs, negotiated := selectReadme(u, test.param, test.acceptLanguage)
+			if got != test.want {
+				t.Errorf("got README %q, want %q", got.Filepath, test.want.Filepath)
+			}
+			if negotiated != test.wantNegotiated {
+				t.Errorf("negotiated = %t, want %t", negotiated, test.wantNegotiated)
+			}
+			var gotLangs []string
+			for _, l := range langs {
+				s := l.Param + ":" + l.Name
+				if l.Selected {
+					s += "*"
+				}
+				gotLangs = append(gotLangs, s)
+			}
+			wantLangs := []string{"default:English", "ja:日本語", "zh-CN:中文 (中国)"}
+			for i, r := range []*internal.Readme{main, ja, zh} {
+				if r == test.want {
+					wantLangs[i] += "*"
+				}
+			}
+			if diff := cmp.Diff(wantLangs, gotLangs); diff != "" {
+				t.Errorf("languages mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("no localized READMEs", func(t *testing.T) {
+		u := &internal.Unit{Readme: main}
+		got, langs, negotiated := selectReadme(u, "", "ja")
+		if got != main || langs != nil || negotiated {
+			t.Errorf("got (%v, %v, %t), want (main README, nil, false)", got, langs, negotiated)
+		}
+	})
+
+	t.Run("english translation", func(t *testing.T) {
+		en := &internal.Readme{Filepath: "README.en.md", Contents: "hello", Lang: "en"}
+		u := &internal.Unit{Readme: main, LocalizedReadmes: []*internal.Readme{en}}
+		got, langs, _ := selectReadme(u, "", "en")
+		if got != en {
+			t.Errorf("got README %q, want README.en.md", got.Filepath)
+		}
+		if langs[0].Name != "Default" || langs[0].Lang != "" {
+			t.Errorf("main README language = %+v, want unknown", langs[0])
+		}
+	})
+}
+
+func TestServeLocalizedReadme(t *testing.T) {
+	ctx := context.Background()
+	m := sample.Module("example.com/mod", sample.VersionString)
+	u := m.Units[0]
+	u.Readme = &internal.Readme{Filepath: "README.md", Contents: "Hello, world."}
+	u.LocalizedReadmes = []*internal.Readme{{Filepath: "README.ja.md", Contents: "こんにちは世界", Lang: "ja"}}
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, m)
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	for _, test := range []struct {
+		path, acceptLanguage string
+		want, notWant        string
+		wantVary             string
+	}{
+		{"/example.com/mod", "", "Hello, world.", "こんにちは世界", "Accept-Language"},
+		{"/example.com/mod", "ja", "こんにちは世界", "Hello, world.", "Accept-Language"},
+		{"/example.com/mod?readme-lang=default", "ja", "Hello, world.", "こんにちは世界", ""},
+		{"/example.com/mod?readme-lang=ja", "", "こんにちは世界", "Hello, world.", ""},
+	} {
+		t.Run(test.path+" "+test.acceptLanguage, func(t *testing.T) {
+			r := httptest.NewRequest("GET", test.path, nil)
+			if test.acceptLanguage != "" {
+				r.Header.Set("Accept-Language", test.acceptLanguage)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+			res := w.Result()
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", res.StatusCode, http.StatusOK)
+			}
+			if got := res.Header.Get("Vary"); got != test.wantVary {
+				t.Errorf("Vary = %q, want %q", got, test.wantVary)
+			}
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := string(b)
+			if !strings.Contains(body, test.want) {
+				t.Errorf("body does not contain %q", test.want)
+			}
+			if strings.Contains(body, test.notWant) {
+				t.Errorf("body contains %q", test.notWant)
+			}
+			if !strings.Contains(body, `data-test-id="UnitReadme-languages"`) {
+				t.Error("body does not contain the README language switcher")
+			}
+		})
+	}
+}
+
 func unindent(s string) string {
 	i := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsSpace(r) })
 	if i < 0 {
@@ -0,0 +1,43 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"net/http"
+	"regexp"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/stdlib"
+)
+
+// goReleaseRx matches Go releases, which have release notes.
+var goReleaseRx = regexp.MustCompile(`^go1(\.[0-9]+)?$`)
+
+// serveGoReleaseNotes redirects to the release notes of the Go release in the
+// "version" query param, like "go1.16", at the section about the standard
+// library package at the "path" query param if there is one. The "added in"
+// annotations of symbols in the documentation of the standard library link
+// to it.
+func (s *Server) serveGoReleaseNotes(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveGoReleaseNotes(%q)", r.URL.RawQuery)
+
+	goVersion, pkgPath := r.FormValue("version"), r.FormValue("path")
+	if !goReleaseRx.MatchString(goVersion) || pkgPath == "" || !stdlib.Contains(pkgPath) {
+		return &serrors.ServerError{Status: http.StatusBadRequest}
+	}
+	var anchor string
+	if db, ok := ds.(internal.PostgresDB); ok {
+		anchor, err = db.GetReleaseNoteAnchor(r.Context(), goVersion, pkgPath)
+		if err != nil {
+			// The top of the release notes is better than nothing.
+			log.Errorf(r.Context(), "%v", err)
+		}
+	}
+	http.Redirect(w, r, stdlib.ReleaseNotesURL(goVersion, anchor), http.StatusFound)
+	return nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/stdlib"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestServeGoReleaseNotes(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	m := sample.Module(stdlib.ModulePath, "v1.14.6", "encoding/json")
+	m.ReleaseNoteSections = []*stdlib.ReleaseNoteSection{
+		{GoVersion: "go1.14", PackagePath: "encoding/json", Anchor: "encoding/json"},
+	}
+	fds.MustInsertModule(ctx, m)
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	for _, test := range []struct {
+		query        string
+		wantStatus   int
+		wantLocation string
+	}{
+		{"version=go1.14&path=encoding/json", http.StatusFound, "https://go.dev/doc/go1.14#encoding/json"},
+		{"version=go1.14&path=net/http", http.StatusFound, "https://go.dev/doc/go1.14"},
+		{"version=go1.13&path=encoding/json", http.StatusFound, "https://go.dev/doc/go1.13"},
+		{"version=go1.14.2&path=encoding/json", http.StatusBadRequest, ""},
+		{"version=go1.14&path=github.com/a/b", http.StatusBadRequest, ""},
+		{"version=go1.14", http.StatusBadRequest, ""},
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/go-release-notes?"+test.query, nil))
+		if w.Code != test.wantStatus {
+			t.Errorf("%s: status = %d, want %d", test.query, w.Code, test.wantStatus)
+			continue
+		}
+		if got := w.Header().Get("Location"); got != test.wantLocation {
+			t.Errorf("%s: Location = %q, want %q", test.query, got, test.wantLocation)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/safehtml"
+	"github.com/google/safehtml/uncheckedconversions"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docrender"
+	"golang.org/x/pkgsite/internal/docrender/readme"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/licenses"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/middleware/stats"
+	"golang.org/x/pkgsite/internal/source"
+)
+
+// localRenderer is a docrender.Renderer that renders in process.
+type localRenderer struct {
+	readme.Renderer
+}
+
+// NewLocalRenderer returns a docrender.Renderer that renders in the current
+// process. The documentation templates must have been loaded with
+// dochtml.LoadTemplates.
+func NewLocalRenderer() docrender.Renderer {
+	return localRenderer{}
+}
+
+func (localRenderer) RenderUnit(ctx context.Context, req *docrender.UnitRequest) (_ *docrender.UnitResponse, err error) {
+	defer derrors.Wrap(&err, "localRenderer.RenderUnit(%q, %q, %q)", req.Path, req.ModulePath, req.Version)
+	parts, docPkg, err := renderUnitRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	resp := &docrender.UnitResponse{
+		Body:          parts.Body.String(),
+		Outline:       parts.Outline.String(),
+		MobileOutline: parts.MobileOutline.String(),
+	}
+	for _, l := range parts.Links {
+		resp.Links = append(resp.Links, &docrender.Link{Href: l.Href, Text: l.Text})
+	}
+	for _, f := range docPkg.Files {
+		resp.Files = append(resp.Files, &docrender.File{Name: f.Name, BuildConstraint: f.BuildConstraint, Licenses: f.Licenses})
+	}
+	return resp, nil
+}
+
+func (localRenderer) Outline(ctx context.Context, req *docrender.UnitRequest) (_ *docrender.OutlineResponse, err error) {
+	defer derrors.Wrap(&err, "localRenderer.Outline(%q, %q, %q)", req.Path, req.ModulePath, req.Version)
+	parts, _, err := renderUnitRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &docrender.OutlineResponse{
+		Outline:       parts.Outline.String(),
+		MobileOutline: parts.MobileOutline.String(),
+	}, nil
+}
+
+// renderUnitRequest renders the documentation described by req. It also
+// returns the decoded package, whose AST has been destroyed by rendering.
+func renderUnitRequest(ctx context.Context, req *docrender.UnitRequest) (*dochtml.Parts, *godoc.Package, error) {
+	docPkg, err := godoc.DecodePackage(req.Source)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := &internal.Unit{
+		UnitMeta: internal.UnitMeta{
+			Path: req.Path,
+			ModuleInfo: internal.ModuleInfo{
+				ModulePath: req.ModulePath,
+				Version:    req.Version,
+				GoVersion:  req.GoVersion,
+				SourceInfo: req.SourceInfo,
+				AuthorMetadata: &internal.AuthorMetadata{
+					NoIssueLinks: req.NoIssueLinks,
+				},
+			},
+		},
+		Licenses: []*licenses.Metadata{{Types: req.LicenseTypes}},
+		Documentation: []*internal.Documentation{{
+			GOOS:           req.BuildContext.GOOS,
+			GOARCH:         req.BuildContext.GOARCH,
+			Source:         req.Source,
+			SourceHash:     req.SourceHash,
+			BrokenDocLinks: req.BrokenDocLinks,
+		}},
+	}
+	parts, err := renderDocParts(ctx, u, docPkg, req.SymbolHistory, req.BuildContext, req.ExpandExamples)
+	if err != nil {
+		return nil, nil, err
+	}
+	return parts, docPkg, nil
+}
+
+func fromRenderHeadings(rhs []*docrender.Heading) []*Heading {
+	var hs []*Heading
+	for _, rh := range rhs {
+		hs = append(hs, &Heading{
+			Level:    rh.Level,
+			Text:     rh.Text,
+			ID:       rh.ID,
+			Children: fromRenderHeadings(rh.Children),
+		})
+	}
+	return hs
+}
+
+// trustedRenderedHTML converts HTML produced by a docrender.Renderer to
+// safehtml.HTML. Renderers sanitize the HTML they return.
+func trustedRenderedHTML(s string) safehtml.HTML {
+	return uncheckedconversions.HTMLFromStringKnownToSatisfyTypeContract(s)
+}
+
+// renderUnitDoc renders the documentation of u with rd, with its examples
+// expanded if expandExamples is true. It returns the rendered documentation,
+// its links, and the package's files.
+func renderUnitDoc(ctx context.Context, rd docrender.Renderer, u *internal.Unit, bc internal.BuildContext, expandExamples bool) (*dochtml.Parts, []link, []*docrender.File, error) {
+	doc := u.Documentation[0]
+	resp, err := rd.RenderUnit(ctx, &docrender.UnitRequest{
+		Path:           u.Path,
+		ModulePath:     u.ModulePath,
+		Version:        u.Version,
+		GoVersion:      u.GoVersion,
+		SourceInfo:     u.SourceInfo,
+		Source:         doc.Source,
+		SourceHash:     doc.SourceHash,
+		SymbolHistory:  u.SymbolHistory,
+		BuildContext:   bc,
+		NoIssueLinks:   u.AuthorMetadata.IssueLinksDisabled(),
+		LicenseTypes:   unitLicenseTypes(u),
+		BrokenDocLinks: doc.BrokenDocLinks,
+		ExpandExamples: expandExamples,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	parts := &dochtml.Parts{
+		Body:          trustedRenderedHTML(resp.Body),
+		Outline:       trustedRenderedHTML(resp.Outline),
+		MobileOutline: trustedRenderedHTML(resp.MobileOutline),
+	}
+	var links []link
+	for _, l := range resp.Links {
+		links = append(links, link{Href: l.Href, Body: l.Text})
+	}
+	return parts, links, resp.Files, nil
+}
+
+// renderReadme renders r with rd, unless a rendering of it that was
+// stored when its module was processed can be read from ds.
+func renderReadme(ctx context.Context, ds internal.DataSource, rd docrender.Renderer, r *internal.Readme, info *source.Info, noIssueLinks bool) (*Readme, error) {
+	if r == nil || r.Contents == "" {
+		return &Readme{}, nil
+	}
+	req := &docrender.ReadmeRequest{
+		Readme:       r,
+		SourceInfo:   info,
+		NoIssueLinks: noIssueLinks,
+	}
+	resp := storedReadme(ctx, ds, docrender.ReadmeHash, req)
+	if resp == nil {
+		var err error
+		resp, err = rd.RenderReadme(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return readmeFromResponse(resp), nil
+}
+
+// renderDocsPage renders the docs page described by req with rd, unless a
+// rendering of it that was stored when its module was processed can be read
+// from ds.
+func renderDocsPage(ctx context.Context, ds internal.DataSource, rd docrender.Renderer, req *docrender.DocsPageRequest) (*docrender.ReadmeResponse, error) {
+	if resp := storedReadme(ctx, ds, docrender.DocsPageHash, req); resp != nil {
+		return resp, nil
+	}
+	return rd.RenderDocsPage(ctx, req)
+}
+
+// readmeFromResponse converts a rendered README to a Readme.
+func readmeFromResponse(resp *docrender.ReadmeResponse) *Readme {
+	r := &Readme{
+		HTML:    trustedRenderedHTML(resp.HTML),
+		Outline: fromRenderHeadings(resp.Outline),
+	}
+	for _, l := range resp.Links {
+		r.Links = append(r.Links, link{Href: l.Href, Body: l.Text})
+	}
+	if qs := resp.QuickStart; qs != nil {
+		r.QuickStart = &QuickStart{Command: qs.Command, Code: qs.Code}
+	}
+	return r
+}
+
+// storedReadme returns the rendering of req, a README or docs page request
+// with the given hash function, that the worker stored when it processed the
+// module, or nil if ds does not store renderings or there is none for req.
+// Errors are logged, since the README can still be rendered.
+func storedReadme[Req any](ctx context.Context, ds internal.DataSource, hash func(Req) (string, error), req Req) *docrender.ReadmeResponse {
+	defer stats.Elapsed(ctx, "storedReadme")()
+
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return nil
+	}
+	h, err := hash(req)
+	if err != nil {
+		log.Errorf(ctx, "storedReadme: %v", err)
+		return nil
+	}
+	data, err := db.GetRenderedReadme(ctx, h)
+	if err != nil {
+		if !errors.Is(err, derrors.NotFound) {
+			log.Errorf(ctx, "storedReadme: %v", err)
+		}
+		return nil
+	}
+	var resp docrender.ReadmeResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		log.Errorf(ctx, "storedReadme: %v", err)
+		return nil
+	}
+	return &resp
+}
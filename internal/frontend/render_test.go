@@ -0,0 +1,137 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/docrender"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestRenderUnitDoc(t *testing.T) {
+	ctx := context.Background()
+	dochtml.LoadTemplates(template.TrustedFSFromTrustedSource(template.TrustedSourceFromConstant("../../static")))
+	const src = `
+		// Package p is a package.
+		//
+		// # Links
+		//
+		//   - Example, https://example.com
+		package p
+
+		// F is a function, unlike [io.Nope].
+		func F() {}
+	`
+	u := sample.UnitForPackage(sample.PackagePath, sample.ModulePath, sample.VersionString, "p", true)
+	u.Documentation = []*internal.Documentation{sample.Documentation("linux", "amd64", src)}
+	u.Documentation[0].BrokenDocLinks = []*internal.BrokenDocLink{{Text: "io.Nope", ImportPath: "io", Name: "Nope"}}
+	bc := internal.BuildContext{GOOS: "linux", GOARCH: "amd64"}
+
+	docPkg, err := godoc.DecodePackage(u.Documentation[0].Source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wantFiles []*docrender.File
+	for _, f := range docPkg.Files {
+		wantFiles = append(wantFiles, &docrender.File{Name: f.Name, BuildConstraint: f.BuildConstraint})
+	}
+	want, err := renderDocParts(ctx, u, docPkg, nil, bc, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, gotLinks, gotFiles, err := renderUnitDoc(ctx, NewLocalRenderer(), u, bc, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	partStrings := func(p *dochtml.Parts) []string {
+		return []string{p.Body.String(), p.Outline.String(), p.MobileOutline.String()}
+	}
+	if diff := cmp.Diff(partStrings(want), partStrings(got)); diff != "" {
+		t.Errorf("parts mismatch (-want, +got):\n%s", diff)
+	}
+	if strings.Contains(got.Body.String(), "#Nope") {
+		t.Error("broken doc link is rendered as a link")
+	}
+	wantLinks := []link{{Href: "https://example.com", Body: "Example"}}
+	if diff := cmp.Diff(wantLinks, gotLinks); diff != "" {
+		t.Errorf("links mismatch (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(wantFiles, gotFiles); diff != "" {
+		t.Errorf("files mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestRenderReadme(t *testing.T) {
+	ctx := context.Background()
+	readme := &internal.Readme{
+		Filepath: "README.md",
+		Contents: "# Title\n\n## Section\n\nText.\n\n## Links\n\n- [Docs](https://example.com/docs)\n",
+	}
+	info := sample.ModuleInfo(sample.ModulePath, sample.VersionString).SourceInfo
+	want, err := processReadme(ctx, readme, info, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := renderReadme(ctx, nil, NewLocalRenderer(), readme, info, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want.HTML.String(), got.HTML.String()); diff != "" {
+		t.Errorf("html mismatch (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want.Outline, got.Outline); diff != "" {
+		t.Errorf("outline mismatch (-want, +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(want.Links, got.Links); diff != "" {
+		t.Errorf("links mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestRenderStoredReadme(t *testing.T) {
+	ctx := context.Background()
+	readme := &internal.Readme{Filepath: "README.md", Contents: "# Title\n"}
+	info := sample.ModuleInfo(sample.ModulePath, sample.VersionString).SourceInfo
+	hash, err := docrender.ReadmeHash(&docrender.ReadmeRequest{Readme: readme, SourceInfo: info})
+	if err != nil {
+		t.Fatal(err)
+	}
+	fds := fakedatasource.New()
+	if err := fds.InsertRenderedReadmes(ctx, map[string][]byte{
+		hash: []byte(`{"HTML": "<h1>Stored</h1>", "Outline": [{"Level": 1, "Text": "Stored", "ID": "readme-stored"}]}`),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := renderReadme(ctx, fds, NewLocalRenderer(), readme, info, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "<h1>Stored</h1>"; got.HTML.String() != want {
+		t.Errorf("got HTML %q, want stored %q", got.HTML, want)
+	}
+	if len(got.Outline) != 1 || got.Outline[0].ID != "readme-stored" {
+		t.Errorf("got outline %+v, want stored outline", got.Outline)
+	}
+
+	// A README rendered differently, here without issue links, is not read
+	// from storage.
+	got, err = renderReadme(ctx, fds, NewLocalRenderer(), readme, info, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got.HTML.String(), "Stored") {
+		t.Errorf("got stored HTML %q for a different rendering", got.HTML)
+	}
+}
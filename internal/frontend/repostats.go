@@ -0,0 +1,53 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// RepoStats contains statistics about a unit's repository, formatted for
+// display in the unit sidebar.
+type RepoStats struct {
+	Stars      string
+	OpenIssues string
+	LastCommit string
+}
+
+// getRepoStats returns the repository statistics for repoURL, or nil if they
+// are not available. Statistics are stored only in the database, so nil is
+// always returned for other data sources.
+func getRepoStats(ctx context.Context, ds internal.DataSource, repoURL string) *RepoStats {
+	db, ok := ds.(internal.PostgresDB)
+	if !ok || repoURL == "" {
+		return nil
+	}
+	rs, err := db.GetRepoStats(ctx, repoURL)
+	if err != nil {
+		if !errors.Is(err, derrors.NotFound) {
+			// The stats are not essential to the page, so don't fail.
+			log.Errorf(ctx, "getRepoStats: %v", err)
+		}
+		return nil
+	}
+	return newRepoStats(rs)
+}
+
+func newRepoStats(rs *source.RepoStats) *RepoStats {
+	pr := message.NewPrinter(language.English)
+	return &RepoStats{
+		Stars:      pr.Sprint(rs.Stars),
+		OpenIssues: pr.Sprint(rs.OpenIssues),
+		LastCommit: absoluteTime(rs.LastCommitTime),
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/source"
+)
+
+func TestNewRepoStats(t *testing.T) {
+	got := newRepoStats(&source.RepoStats{
+		Stars:          12345,
+		OpenIssues:     7,
+		LastCommitTime: time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC),
+	})
+	want := &RepoStats{Stars: "12,345", OpenIssues: "7", LastCommit: "Mar  4, 2024"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
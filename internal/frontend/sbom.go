@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/sbom"
+)
+
+// sbomFormatParam is the query param that selects the format of an SBOM.
+const sbomFormatParam = "format"
+
+// serveSBOM serves a software bill of materials for a module version, for
+// requests to /sbom/<module>@<version>. The format query param selects SPDX
+// (the default) or CycloneDX.
+func (s *Server) serveSBOM(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveSBOM(%q)", r.URL.Path)
+
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return serrors.DatasourceNotSupportedError()
+	}
+	modulePath, version, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/sbom/"), "@")
+	if !ok || module.Check(modulePath, version) != nil {
+		return &serrors.ServerError{
+			Status:       http.StatusBadRequest,
+			ResponseText: "An SBOM requires a module path and a full semantic version, as in /sbom/example.com/mod@v1.2.3.",
+		}
+	}
+	format := r.FormValue(sbomFormatParam)
+	if format == "" {
+		format = sbom.FormatSPDX
+	}
+	if sbom.ContentType(format) == "" {
+		return &serrors.ServerError{
+			Status:       http.StatusBadRequest,
+			ResponseText: fmt.Sprintf("Unknown SBOM format %q; use %q or %q.", format, sbom.FormatSPDX, sbom.FormatCycloneDX),
+		}
+	}
+	ctx := r.Context()
+	if err := checkModuleAvailable(ctx, ds, modulePath, modulePath, version); err != nil {
+		return err
+	}
+	md, err := db.GetModuleDependencies(ctx, modulePath, version)
+	if err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			return &serrors.ServerError{
+				Status:       http.StatusNotFound,
+				ResponseText: fmt.Sprintf("%s@%s has not been processed.", modulePath, version),
+			}
+		}
+		return err
+	}
+	// The namespace is made from the configured base URL, since the Host
+	// header is chosen by the client.
+	namespace := &url.URL{
+		Path:     r.URL.Path,
+		RawQuery: url.Values{sbomFormatParam: {format}}.Encode(),
+	}
+	b, err := sbom.Generate(md, format, s.baseURL+namespace.String(), time.Now())
+	if err != nil {
+		return err
+	}
+	filename := strings.ReplaceAll(modulePath, "/", "_") + "@" + version + sbom.FileExtension(format)
+	w.Header().Set("Content-Type", sbom.ContentType(format))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	_, err = w.Write(b)
+	return err
+}
+
+// sbomURL returns the URL of the SBOM of the module version of um, or the
+// empty string if ds cannot produce one.
+func sbomURL(ds internal.DataSource, um *internal.UnitMeta) string {
+	if _, ok := ds.(internal.PostgresDB); !ok {
+		return ""
+	}
+	return "/sbom/" + um.ModulePath + "@" + um.Version
+}
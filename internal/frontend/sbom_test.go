@@ -0,0 +1,100 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestServeSBOM(t *testing.T) {
+	ctx := context.Background()
+	m := sample.Module("example.com/mod", "v1.0.0")
+	m.Requirements = []*internal.ModuleRequirement{{ModulePath: "example.com/dep", Version: "v1.2.3"}}
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, m)
+	fds.MustInsertModule(ctx, sample.Module("example.com/dep", "v1.2.3"))
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+		BaseURL:          "https://pkg.example.com",
+	})
+
+	for _, test := range []struct {
+		path            string
+		wantStatus      int
+		wantContentType string
+		wantFilename    string
+	}{
+		{"/sbom/example.com/mod@v1.0.0", http.StatusOK, "application/spdx+json", "example.com_mod@v1.0.0.spdx.json"},
+		{"/sbom/example.com/mod@v1.0.0?format=spdx", http.StatusOK, "application/spdx+json", "example.com_mod@v1.0.0.spdx.json"},
+		{"/sbom/example.com/mod@v1.0.0?format=cyclonedx", http.StatusOK, "application/vnd.cyclonedx+json", "example.com_mod@v1.0.0.cdx.json"},
+		{"/sbom/example.com/mod@v1.0.0?format=xml", http.StatusBadRequest, "", ""},
+		{"/sbom/example.com/mod", http.StatusBadRequest, "", ""},
+		{"/sbom/example.com/mod@latest", http.StatusBadRequest, "", ""},
+		{"/sbom/example.com/mod@v1.1.0", http.StatusNotFound, "", ""},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			res := w.Result()
+			if res.StatusCode != test.wantStatus {
+				t.Fatalf("status = %d, want %d", res.StatusCode, test.wantStatus)
+			}
+			if test.wantStatus != http.StatusOK {
+				return
+			}
+			if got := res.Header.Get("Content-Type"); got != test.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, test.wantContentType)
+			}
+			if got, want := res.Header.Get("Content-Disposition"), `attachment; filename="`+test.wantFilename+`"`; got != want {
+				t.Errorf("Content-Disposition = %q, want %q", got, want)
+			}
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !json.Valid(b) {
+				t.Errorf("body is not valid JSON:\n%s", b)
+			}
+		})
+	}
+
+	t.Run("namespace", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/sbom/example.com/mod@v1.0.0", nil)
+		r.Host = "attacker.example"
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		var doc struct {
+			DocumentNamespace string `json:"documentNamespace"`
+		}
+		if err := json.NewDecoder(w.Result().Body).Decode(&doc); err != nil {
+			t.Fatal(err)
+		}
+		if want := "https://pkg.example.com/sbom/example.com/mod@v1.0.0?format=spdx"; doc.DocumentNamespace != want {
+			t.Errorf("documentNamespace = %q, want %q", doc.DocumentNamespace, want)
+		}
+	})
+
+	t.Run("unit page link", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", "/example.com/mod@v1.0.0", nil))
+		b, err := io.ReadAll(w.Result().Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `href="/sbom/example.com/mod@v1.0.0"`; !strings.Contains(string(b), want) {
+			t.Errorf("unit page does not contain %q", want)
+		}
+	})
+}
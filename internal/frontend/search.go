@@ -9,7 +9,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"path"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -18,6 +20,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/google/safehtml/template"
+	"golang.org/x/mod/module"
 	"golang.org/x/mod/semver"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
@@ -36,7 +39,7 @@ import (
 // /search?q=<query>. If <query> is an exact match for a package path, the user
 // will be redirected to the details page.
 func (s *Server) serveSearch(w http.ResponseWriter, r *http.Request, ds internal.DataSource) error {
-	action, err := determineSearchAction(r, ds, s.vulnClient)
+	action, err := determineSearchAction(r, ds, s.vulnClient, s.defaultBC)
 	if err != nil {
 		return err
 	}
@@ -59,7 +62,7 @@ type searchAction struct {
 	page        interface{ SetBasePage(pagepkg.BasePage) }
 }
 
-func determineSearchAction(r *http.Request, ds internal.DataSource, vulnClient *vuln.Client) (*searchAction, error) {
+func determineSearchAction(r *http.Request, ds internal.DataSource, vulnClient *vuln.Client, defaultBC internal.BuildContext) (*searchAction, error) {
 	if r.Method != http.MethodGet && r.Method != http.MethodHead {
 		return nil, &serrors.ServerError{Status: http.StatusMethodNotAllowed}
 	}
@@ -115,28 +118,57 @@ func determineSearchAction(r *http.Request, ds internal.DataSource, vulnClient *
 			},
 		}
 	}
+	scope, err := searchScopeFromRequest(r)
+	if err != nil {
+		return nil, &serrors.ServerError{
+			Status: http.StatusBadRequest,
+			Epage: &pagepkg.ErrorPage{
+				MessageTemplate: template.MakeTrustedTemplate(
+					`<h3 class="Error-message">Searches can only be limited to a module path and version, as in example.com/mod@v1.2.3.</h3>`),
+			},
+		}
+	}
 	var mode string
 	if searchSupport == internal.BasicSearch {
 		mode = searchModePackage
 	} else {
 		mode = searchMode(r)
 	}
-	if path := searchRequestRedirectPath(ctx, ds, cq, mode, vulnClient != nil); path != "" {
-		return &searchAction{redirectURL: path}, nil
-	}
-	action, err := searchVulnAlias(ctx, mode, cq, vulnClient)
-	if action != nil || err != nil {
-		return action, err
+	if scope == nil {
+		if path := searchRequestRedirectPath(ctx, ds, cq, mode, vulnClient != nil); path != "" {
+			return &searchAction{redirectURL: path}, nil
+		}
+		action, err := searchVulnAlias(ctx, mode, cq, vulnClient)
+		if action != nil || err != nil {
+			return action, err
+		}
+		action, err = searchPrivateAdvisory(ctx, cq, vulnClient)
+		if action != nil || err != nil {
+			return action, err
+		}
+		action, err = searchVulnModule(ctx, mode, cq, vulnClient)
+		if action != nil || err != nil {
+			return action, err
+		}
+	} else if mode == searchModeVuln {
+		// Vulnerabilities are not searched within a module version.
+		mode = searchModePackage
 	}
-	action, err = searchVulnModule(ctx, mode, cq, vulnClient)
-	if action != nil || err != nil {
-		return action, err
+	searchFilters, err := searchFiltersFromRequest(r)
+	if err != nil {
+		return nil, &serrors.ServerError{
+			Status: http.StatusBadRequest,
+			Epage: &pagepkg.ErrorPage{
+				MessageTemplate: template.MakeTrustedTemplate(
+					`<h3 class="Error-message">Invalid search filter.</h3>`),
+			},
+		}
 	}
 	var symbol string
 	if len(filters) > 0 {
 		symbol = filters[0]
 	}
-	page, err := fetchSearchPage(ctx, ds, cq, symbol, pageParams, mode == searchModeSymbol, vulnClient)
+	page, err := fetchSearchPage(ctx, ds, cq, symbol, scope, searchFilters, pageParams, mode == searchModeSymbol, vulnClient, defaultBC)
 	if err != nil {
 		// Instead of returning a 500, return a 408, since symbol searches may time
 		// out for very popular symbols, and package searches can also time out.
@@ -152,6 +184,14 @@ func determineSearchAction(r *http.Request, ds internal.DataSource, vulnClient *
 		return nil, fmt.Errorf("fetchSearchPage(ctx, db, %q): %v", cq, err)
 	}
 	page.SearchMode = mode
+	if mode == searchModePackage && scope == nil {
+		page.StdlibOnly = searchStdlibOnly(r)
+		page.StdlibToggleURL = stdlibToggleURL(pageParams.baseURL, !page.StdlibOnly)
+		if page.StdlibOnly {
+			// The host facet has only the standard library.
+			page.Facets = slices.DeleteFunc(page.Facets, func(f *searchFacet) bool { return f.Param == searchHostParam })
+		}
+	}
 	return &searchAction{
 		title:    fmt.Sprintf("%s - Search Results", cq),
 		template: "search",
@@ -192,8 +232,42 @@ const (
 	// contains a symbol. For example, searching for "#unmarshal json" indicates
 	// that unmarshal is a symbol.
 	symbolSearchFilter = "#"
+
+	// searchScopeParam is the query param that limits a search to one module
+	// version. Its value has the form module@version.
+	searchScopeParam = "in"
 )
 
+// A searchScope limits a search to the packages and symbols of one module
+// version.
+type searchScope struct {
+	ModulePath string
+	Version    string
+}
+
+func (s *searchScope) String() string {
+	return s.ModulePath + "@" + s.Version
+}
+
+// searchScopeFromRequest returns the scope of the search request r, or nil if
+// the search is not limited to a module version.
+func searchScopeFromRequest(r *http.Request) (*searchScope, error) {
+	in := strings.TrimSpace(r.FormValue(searchScopeParam))
+	if in == "" {
+		return nil, nil
+	}
+	modulePath, v, ok := strings.Cut(in, "@")
+	if !ok || !semver.IsValid(v) || semver.Canonical(v) != v {
+		return nil, fmt.Errorf("invalid search scope %q", in)
+	}
+	if modulePath != stdlib.ModulePath {
+		if err := module.Check(modulePath, v); err != nil {
+			return nil, err
+		}
+	}
+	return &searchScope{ModulePath: modulePath, Version: v}, nil
+}
+
 // SearchPage contains all of the data that the search template needs to
 // populate.
 type SearchPage struct {
@@ -203,25 +277,52 @@ type SearchPage struct {
 	// This is used if the user clicks on the package tab.
 	PackageTabQuery string
 
+	// Scope is the module version the search is limited to, or nil.
+	Scope *searchScope
+	// ScopeURL is the URL of the module page for Scope, and UnscopedURL is
+	// the URL of the same search across all modules.
+	ScopeURL    string
+	UnscopedURL string
+
 	Pagination pagination
 	Results    []*SearchResult
+
+	// Facets break down the results of a package search by license, host
+	// and major version, for narrowing it. FacetsApproximate is true if the
+	// counts cover only some of the results.
+	Facets            []*searchFacet
+	FacetsApproximate bool
+
+	// StdlibOnly is true if the package search is limited to the standard
+	// library, and StdlibToggleURL is the same search with the limit toggled.
+	// StdlibToggleURL is empty if the limit does not apply to the search.
+	StdlibOnly      bool
+	StdlibToggleURL string
 }
 
 // SearchResult contains data needed to display a single search result.
 type SearchResult struct {
-	Name           string
-	PackagePath    string
+	Name        string
+	PackagePath string
+	// URLPath is the path of the result's page on this site. It has a
+	// version for searches within a module version.
+	URLPath        string
 	ModulePath     string
 	Version        string
 	ChipText       string
 	Synopsis       string
 	DisplayVersion string
+	// Incompatible reports whether Version is a +incompatible version: one
+	// with a major version of 2 or more, from a module path without a major
+	// version suffix.
+	Incompatible   bool
 	Licenses       []string
 	CommitTime     string
 	NumImportedBy  string
 	Symbols        *subResult
 	SameModule     *subResult // package paths in the same module
 	OtherMajor     *subResult // package paths in lower major versions
+	Similar        *subResult // package paths of near duplicates, such as forks
 	SymbolName     string
 	SymbolKind     string
 	SymbolSynopsis string
@@ -238,26 +339,40 @@ type subResult struct {
 
 // fetchSearchPage fetches data matching the search query from the database and
 // returns a SearchPage.
-func fetchSearchPage(ctx context.Context, ds internal.DataSource, cq, symbol string,
-	pageParams paginationParams, searchSymbols bool, vulnClient *vuln.Client) (*SearchPage, error) {
+func fetchSearchPage(ctx context.Context, ds internal.DataSource, cq, symbol string, scope *searchScope,
+	filters internal.SearchFilters, pageParams paginationParams, searchSymbols bool, vulnClient *vuln.Client,
+	defaultBC internal.BuildContext) (*SearchPage, error) {
 	maxResultCount := maxSearchOffset + pageParams.limit
 
 	// Pageless search: always start from the beginning.
 	offset := 0
-	dbresults, err := ds.Search(ctx, cq, internal.SearchOptions{
+	opts := internal.SearchOptions{
 		MaxResults:     pageParams.limit,
 		Offset:         offset,
 		MaxResultCount: maxResultCount,
 		SearchSymbols:  searchSymbols,
 		SymbolFilter:   symbol,
-	})
+	}
+	if scope != nil {
+		opts.ModulePath = scope.ModulePath
+		opts.Version = scope.Version
+	}
+	// Facets are only for package searches across all modules. They are
+	// computed while searching.
+	withFacets := !searchSymbols && scope == nil
+	var waitForFacets func() ([]*searchFacet, bool)
+	if withFacets {
+		opts.Filters = filters
+		waitForFacets = startSearchFacets(ctx, ds, cq, opts, pageParams.baseURL)
+	}
+	dbresults, err := ds.Search(ctx, cq, opts)
 	if err != nil {
 		return nil, err
 	}
 
 	var results []*SearchResult
 	for _, r := range dbresults {
-		sr := newSearchResult(r, searchSymbols, message.NewPrinter(language.English))
+		sr := newSearchResult(r, searchSymbols, scope != nil, defaultBC, message.NewPrinter(language.English))
 		results = append(results, sr)
 	}
 
@@ -278,6 +393,9 @@ func fetchSearchPage(ctx context.Context, ds internal.DataSource, cq, symbol str
 		// the LowerMajor list are not removed from the top-level slice,
 		// so we don't add them up.
 		numPageResults += 1 + len(r.SameModule)
+		for _, s := range r.Similar {
+			numPageResults += 1 + len(s.SameModule)
+		}
 	}
 
 	pgs := newPagination(pageParams, numPageResults, numResults)
@@ -286,10 +404,23 @@ func fetchSearchPage(ctx context.Context, ds internal.DataSource, cq, symbol str
 		Results:         results,
 		Pagination:      pgs,
 	}
+	if scope != nil {
+		sp.Scope = scope
+		sp.ScopeURL = versions.ConstructUnitURL(scope.ModulePath, scope.ModulePath, scope.Version)
+		q := pageParams.baseURL.Query()
+		q.Del(searchScopeParam)
+		sp.UnscopedURL = (&url.URL{Path: "/search", RawQuery: q.Encode()}).String()
+	}
+	if waitForFacets != nil {
+		sp.Facets, sp.FacetsApproximate = waitForFacets()
+	}
 	return sp, nil
 }
 
-func newSearchResult(r *internal.SearchResult, searchSymbols bool, pr *message.Printer) *SearchResult {
+// newSearchResult returns the SearchResult to display for r. If versioned
+// is true, the result links to the page of its version rather than to the
+// latest one.
+func newSearchResult(r *internal.SearchResult, searchSymbols, versioned bool, defaultBC internal.BuildContext, pr *message.Printer) *SearchResult {
 	// For commands, change the name from "main" to the last component of the import path.
 	chipText := ""
 	name := r.Name
@@ -302,14 +433,20 @@ func newSearchResult(r *internal.SearchResult, searchSymbols bool, pr *message.P
 		moduleDesc = "Related packages in the standard library"
 		chipText = "standard library"
 	}
+	urlPath := "/" + r.PackagePath
+	if versioned {
+		urlPath = versions.ConstructUnitURL(r.PackagePath, r.ModulePath, r.Version)
+	}
 	sr := &SearchResult{
 		Name:           name,
 		PackagePath:    r.PackagePath,
+		URLPath:        urlPath,
 		ModulePath:     r.ModulePath,
 		Version:        r.Version,
 		ChipText:       chipText,
 		Synopsis:       r.Synopsis,
 		DisplayVersion: versions.DisplayVersion(r.ModulePath, r.Version, r.Version),
+		Incompatible:   version.IsIncompatible(r.Version),
 		Licenses:       r.Licenses,
 		CommitTime:     elapsedTime(r.CommitTime),
 		NumImportedBy:  pr.Sprint(r.NumImportedBy),
@@ -318,6 +455,7 @@ func newSearchResult(r *internal.SearchResult, searchSymbols bool, pr *message.P
 		// prefer to show a tagged, lower major version over an untagged
 		// higher major version.
 		OtherMajor: modulePaths("Other major versions:", r.OtherMajor),
+		Similar:    similarPaths(r.Similar),
 	}
 	if searchSymbols {
 		sr.SymbolName = r.SymbolName
@@ -325,14 +463,18 @@ func newSearchResult(r *internal.SearchResult, searchSymbols bool, pr *message.P
 		sr.SymbolSynopsis = symbolSynopsis(r)
 		sr.SymbolGOOS = r.SymbolGOOS
 		sr.SymbolGOARCH = r.SymbolGOARCH
-		// If the GOOS is "all" or "linux", it doesn't need to be
-		// specified as a query param. "linux" is the default GOOS when a
-		// package has multiple build contexts, since it is first item
-		// listed in internal.BuildContexts.
-		if r.SymbolGOOS == internal.All || r.SymbolGOOS == "linux" {
-			sr.SymbolLink = fmt.Sprintf("/%s#%s", r.PackagePath, r.SymbolName)
+		// If the GOOS is "all" or the default GOOS, it doesn't need to be
+		// specified as a query param. The default GOOS when a package has
+		// multiple build contexts is that of defaultBC, or else "linux",
+		// since it is first item listed in internal.BuildContexts.
+		defaultGOOS := defaultBC.GOOS
+		if defaultGOOS == "" {
+			defaultGOOS = internal.BuildContexts[0].GOOS
+		}
+		if r.SymbolGOOS == internal.All || r.SymbolGOOS == defaultGOOS {
+			sr.SymbolLink = fmt.Sprintf("%s#%s", urlPath, r.SymbolName)
 		} else {
-			sr.SymbolLink = fmt.Sprintf("/%s?GOOS=%s#%s", r.PackagePath, r.SymbolGOOS, r.SymbolName)
+			sr.SymbolLink = fmt.Sprintf("%s?GOOS=%s#%s", urlPath, r.SymbolGOOS, r.SymbolName)
 		}
 	}
 	return sr
@@ -401,6 +543,23 @@ func searchVulnAlias(ctx context.Context, mode, cq string, vc *vuln.Client) (_ *
 	return &searchAction{redirectURL: "/vuln/" + goID}, nil
 }
 
+// searchPrivateAdvisory redirects to the page of the advisory whose ID is cq,
+// if cq has the form of the ID of an advisory that is not in the Go
+// vulnerability database and there is such an advisory.
+func searchPrivateAdvisory(ctx context.Context, cq string, vc *vuln.Client) (_ *searchAction, err error) {
+	defer derrors.Wrap(&err, "searchPrivateAdvisory(%q)", cq)
+
+	id, ok := vuln.CanonicalPrivateID(cq)
+	if !ok || vc == nil {
+		return nil, nil
+	}
+	e, err := vc.ByID(ctx, id)
+	if err != nil || e == nil {
+		return nil, err
+	}
+	return &searchAction{redirectURL: "/vuln/" + id}, nil
+}
+
 // searchMode reports whether the search performed should be in package or
 // symbol search mode.
 func searchMode(r *http.Request) string {
@@ -509,6 +668,26 @@ func packagePaths(heading string, rs []*internal.SearchResult) *subResult {
 	}
 }
 
+// similarPaths returns a subResult linking to the packages in rs, which are
+// near duplicates of a search result.
+func similarPaths(rs []*internal.SearchResult) *subResult {
+	if len(rs) == 0 {
+		return nil
+	}
+	heading := "Show 1 similar package"
+	if len(rs) > 1 {
+		heading = fmt.Sprintf("Show %d similar packages", len(rs))
+	}
+	var links []link
+	for _, r := range rs {
+		links = append(links, link{Href: r.PackagePath, Body: r.PackagePath})
+	}
+	return &subResult{
+		Heading: heading,
+		Links:   links,
+	}
+}
+
 func modulePaths(heading string, modulePathToMajor map[string]int) *subResult {
 	if len(modulePathToMajor) == 0 {
 		return nil
@@ -536,10 +715,8 @@ func modulePaths(heading string, modulePathToMajor map[string]int) *subResult {
 
 // isCapitalized reports whether the first letter of s is capitalized.
 func isCapitalized(s string) bool {
-	if len(s) == 0 {
-		return false
-	}
-	return unicode.IsUpper(rune(s[0]))
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsUpper(r)
 }
 
 // elapsedTime takes a date and returns human-readable,
@@ -94,6 +94,11 @@ func TestDetermineSearchAction(t *testing.T) {
 			query:      "q=foo&limit=" + fmt.Sprint(maxSearchPageSize+1),
 			wantStatus: http.StatusBadRequest,
 		},
+		{
+			name:       "invalid major version filter",
+			query:      "q=foo&major=2",
+			wantStatus: http.StatusBadRequest,
+		},
 		// Some redirections; see more at TestSearchRequestRedirectPath.
 		{
 			name:         "Go vuln report",
@@ -140,6 +145,22 @@ func TestDetermineSearchAction(t *testing.T) {
 			query:        "q=foo",
 			wantTemplate: "search",
 		},
+		{
+			// Searches within a module version don't redirect.
+			name:         "known unit in version",
+			query:        "q=golang.org/x/tools&in=" + url.QueryEscape("golang.org/x/tools@"+sample.VersionString),
+			wantTemplate: "search",
+		},
+		{
+			name:         "vuln alias in version",
+			query:        "q=GHSA-cccc-ffff-gggg&in=" + url.QueryEscape("std@"+sample.VersionString),
+			wantTemplate: "search",
+		},
+		{
+			name:       "bad scope",
+			query:      "q=foo&in=golang.org/x/tools@latest",
+			wantStatus: http.StatusBadRequest,
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			req := buildSearchRequest(t, test.method, test.query)
@@ -147,7 +168,7 @@ func TestDetermineSearchAction(t *testing.T) {
 			if test.ds != nil {
 				ds = test.ds
 			}
-			gotAction, err := determineSearchAction(req, ds, vc)
+			gotAction, err := determineSearchAction(req, ds, vc, internal.BuildContext{})
 			if err != nil {
 				var serr *serrors.ServerError
 				if !errors.As(err, &serr) {
@@ -168,6 +189,66 @@ func TestDetermineSearchAction(t *testing.T) {
 	}
 }
 
+func TestSearchScopeFromRequest(t *testing.T) {
+	for _, test := range []struct {
+		in      string
+		want    *searchScope
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"example.com/mod@v1.2.3", &searchScope{ModulePath: "example.com/mod", Version: "v1.2.3"}, false},
+		{"std@v1.21.0", &searchScope{ModulePath: "std", Version: "v1.21.0"}, false},
+		{"example.com/mod/v2@v2.0.0-20200101000000-abcdefabcdef", &searchScope{ModulePath: "example.com/mod/v2", Version: "v2.0.0-20200101000000-abcdefabcdef"}, false},
+		{"example.com/mod", nil, true},
+		{"example.com/mod@latest", nil, true},
+		{"example.com/mod@v1.2", nil, true},
+		{"example.com/mod/v2@v1.0.0", nil, true},
+		{"@v1.0.0", nil, true},
+	} {
+		r := httptest.NewRequest("GET", "/search?q=x&in="+url.QueryEscape(test.in), nil)
+		got, err := searchScopeFromRequest(r)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%q: got error %v, want error: %t", test.in, err, test.wantErr)
+			continue
+		}
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("%q: got %+v, want %+v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestFetchSearchPageInVersion(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	for _, v := range []string{"v1.0.0", "v1.1.0"} {
+		m := sample.Module("example.com/mod", v, "foo")
+		m.Packages()[0].Documentation[0].Synopsis = "foo " + v
+		fds.MustInsertModule(ctx, m)
+	}
+	scope := &searchScope{ModulePath: "example.com/mod", Version: "v1.0.0"}
+	baseURL, err := url.Parse("/search?q=foo&in=example.com%2Fmod%40v1.0.0&m=package")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := fetchSearchPage(ctx, fds, "foo", "", scope, internal.SearchFilters{}, paginationParams{baseURL: baseURL, limit: 20, page: 1}, false, nil, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Results) != 1 {
+		t.Fatalf("got %d results, want 1", len(got.Results))
+	}
+	r := got.Results[0]
+	if r.Version != "v1.0.0" || r.URLPath != "/example.com/mod@v1.0.0/foo" {
+		t.Errorf("got version %q and URL path %q, want v1.0.0 and /example.com/mod@v1.0.0/foo", r.Version, r.URLPath)
+	}
+	if want := "/example.com/mod@v1.0.0"; got.ScopeURL != want {
+		t.Errorf("ScopeURL = %q, want %q", got.ScopeURL, want)
+	}
+	if want := "/search?m=package&q=foo"; got.UnscopedURL != want {
+		t.Errorf("UnscopedURL = %q, want %q", got.UnscopedURL, want)
+	}
+}
+
 func buildSearchRequest(t *testing.T, method, query string) *http.Request {
 	if method == "" {
 		method = "GET"
@@ -353,6 +434,7 @@ func TestFetchSearchPage(t *testing.T) {
 					{
 						Name:           moduleBar.Packages()[0].Name,
 						PackagePath:    moduleBar.Packages()[0].Path,
+						URLPath:        "/" + moduleBar.Packages()[0].Path,
 						ModulePath:     moduleBar.ModulePath,
 						Version:        "v1.0.0",
 						Synopsis:       moduleBar.Packages()[0].Documentation[0].Synopsis,
@@ -361,6 +443,17 @@ func TestFetchSearchPage(t *testing.T) {
 						CommitTime:     elapsedTime(moduleBar.CommitTime),
 					},
 				},
+				Facets: []*searchFacet{
+					{Name: "License", Param: "license", Values: []*searchFacetValue{
+						{Value: "MIT", Label: "MIT", Count: "1", URL: "/search?license=MIT"},
+					}},
+					{Name: "Host", Param: "host", Values: []*searchFacetValue{
+						{Value: "github.com", Label: "github.com", Count: "1", URL: "/search?host=github.com"},
+					}},
+					{Name: "Major version", Param: "major", Values: []*searchFacetValue{
+						{Value: "v1", Label: "v1", Count: "1", URL: "/search?major=v1"},
+					}},
+				},
 			},
 		},
 		{
@@ -383,6 +476,7 @@ func TestFetchSearchPage(t *testing.T) {
 					{
 						Name:           moduleFoo.Packages()[0].Name,
 						PackagePath:    moduleFoo.Packages()[0].Path,
+						URLPath:        "/" + moduleFoo.Packages()[0].Path,
 						ModulePath:     moduleFoo.ModulePath,
 						Version:        "v1.0.0",
 						Synopsis:       moduleFoo.Packages()[0].Documentation[0].Synopsis,
@@ -392,11 +486,22 @@ func TestFetchSearchPage(t *testing.T) {
 						Vulns:          []vuln.Vuln{{ID: "test", Details: "summary"}},
 					},
 				},
+				Facets: []*searchFacet{
+					{Name: "License", Param: "license", Values: []*searchFacetValue{
+						{Value: "MIT", Label: "MIT", Count: "1", URL: "/search?license=MIT"},
+					}},
+					{Name: "Host", Param: "host", Values: []*searchFacetValue{
+						{Value: "github.com", Label: "github.com", Count: "1", URL: "/search?host=github.com"},
+					}},
+					{Name: "Major version", Param: "major", Values: []*searchFacetValue{
+						{Value: "v1", Label: "v1", Count: "1", URL: "/search?major=v1"},
+					}},
+				},
 			},
 		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
-			got, err := fetchSearchPage(ctx, fds, test.query, "", paginationParams{limit: 20, page: 1}, false, vc)
+			got, err := fetchSearchPage(ctx, fds, test.query, "", nil, internal.SearchFilters{}, paginationParams{limit: 20, page: 1}, false, vc, internal.BuildContext{})
 			if err != nil {
 				t.Fatalf("fetchSearchPage(db, %q): %v", test.query, err)
 			}
@@ -434,6 +539,7 @@ func TestNewSearchResult(t *testing.T) {
 			want: SearchResult{
 				Name:           "pkg",
 				PackagePath:    "m.com/pkg",
+				URLPath:        "/m.com/pkg",
 				ModulePath:     "m.com",
 				Version:        "v1.0.0",
 				DisplayVersion: "v1.0.0",
@@ -453,6 +559,7 @@ func TestNewSearchResult(t *testing.T) {
 			want: SearchResult{
 				Name:           "cmd",
 				PackagePath:    "m.com/cmd",
+				URLPath:        "/m.com/cmd",
 				ModulePath:     "m.com",
 				Version:        "v1.0.0",
 				DisplayVersion: "v1.0.0",
@@ -460,6 +567,26 @@ func TestNewSearchResult(t *testing.T) {
 				NumImportedBy:  "1,234",
 			},
 		},
+		{
+			name: "incompatible",
+			tag:  language.English,
+			in: internal.SearchResult{
+				Name:        "pkg",
+				PackagePath: "m.com/pkg",
+				ModulePath:  "m.com",
+				Version:     "v2.1.0+incompatible",
+			},
+			want: SearchResult{
+				Name:           "pkg",
+				PackagePath:    "m.com/pkg",
+				URLPath:        "/m.com/pkg",
+				ModulePath:     "m.com",
+				Version:        "v2.1.0+incompatible",
+				DisplayVersion: "v2.1.0+incompatible",
+				Incompatible:   true,
+				NumImportedBy:  "0",
+			},
+		},
 		{
 			name: "stdlib",
 			tag:  language.English,
@@ -472,6 +599,7 @@ func TestNewSearchResult(t *testing.T) {
 			want: SearchResult{
 				Name:           "math",
 				PackagePath:    "math",
+				URLPath:        "/math",
 				ModulePath:     "std",
 				Version:        "v1.14.0",
 				DisplayVersion: "go1.14",
@@ -492,16 +620,47 @@ func TestNewSearchResult(t *testing.T) {
 			want: SearchResult{
 				Name:           "pkg",
 				PackagePath:    "m.com/pkg",
+				URLPath:        "/m.com/pkg",
 				ModulePath:     "m.com",
 				Version:        "v1.0.0",
 				DisplayVersion: "v1.0.0",
 				NumImportedBy:  "3.456",
 			},
 		},
+		{
+			name: "similar",
+			tag:  language.English,
+			in: internal.SearchResult{
+				Name:        "pkg",
+				PackagePath: "m.com/pkg",
+				ModulePath:  "m.com",
+				Version:     "v1.0.0",
+				Similar: []*internal.SearchResult{
+					{PackagePath: "fork.com/pkg"},
+					{PackagePath: "other.com/vendor/m.com/pkg"},
+				},
+			},
+			want: SearchResult{
+				Name:           "pkg",
+				PackagePath:    "m.com/pkg",
+				URLPath:        "/m.com/pkg",
+				ModulePath:     "m.com",
+				Version:        "v1.0.0",
+				DisplayVersion: "v1.0.0",
+				NumImportedBy:  "0",
+				Similar: &subResult{
+					Heading: "Show 2 similar packages",
+					Links: []link{
+						{Href: "fork.com/pkg", Body: "fork.com/pkg"},
+						{Href: "other.com/vendor/m.com/pkg", Body: "other.com/vendor/m.com/pkg"},
+					},
+				},
+			},
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			pr := message.NewPrinter(test.tag)
-			got := newSearchResult(&test.in, false, pr)
+			got := newSearchResult(&test.in, false, false, internal.BuildContext{}, pr)
 			test.want.CommitTime = "unknown"
 			if diff := cmp.Diff(&test.want, got); diff != "" {
 				t.Errorf("mismatch (-want, +got):\n%s", diff)
@@ -510,6 +669,34 @@ func TestNewSearchResult(t *testing.T) {
 	}
 }
 
+func TestNewSearchResultSymbolLink(t *testing.T) {
+	pr := message.NewPrinter(language.English)
+	for _, test := range []struct {
+		goos      string
+		defaultBC internal.BuildContext
+		want      string
+	}{
+		{internal.All, internal.BuildContext{}, "/m.com/pkg#F"},
+		{"linux", internal.BuildContext{}, "/m.com/pkg#F"},
+		{"windows", internal.BuildContext{}, "/m.com/pkg?GOOS=windows#F"},
+		{"windows", internal.BuildContextWindows, "/m.com/pkg#F"},
+		{"linux", internal.BuildContextWindows, "/m.com/pkg?GOOS=linux#F"},
+	} {
+		r := &internal.SearchResult{
+			Name:        "pkg",
+			PackagePath: "m.com/pkg",
+			ModulePath:  "m.com",
+			Version:     "v1.0.0",
+			SymbolName:  "F",
+			SymbolGOOS:  test.goos,
+		}
+		got := newSearchResult(r, true, false, test.defaultBC, pr).SymbolLink
+		if got != test.want {
+			t.Errorf("GOOS %q, default %v: got %q, want %q", test.goos, test.defaultBC, got, test.want)
+		}
+	}
+}
+
 func TestSearchRequestRedirectPath(t *testing.T) {
 	// Experiments need to be set in the context, for DB work, and as
 	// a middleware, for request handling.
@@ -837,6 +1024,11 @@ func TestShouldDefaultToSymbolSearch(t *testing.T) {
 		{"yaml.v2", false},
 		{"gopkg.in", false},
 		{"Unmarshal", true},
+		{"Δέλτα", true},
+		{"δέλτα", false},
+		{"X関数", true},
+		{"関数", false},
+		{"écrire", false},
 	} {
 		t.Run(test.q, func(t *testing.T) {
 			got := shouldDefaultToSymbolSearch(test.q)
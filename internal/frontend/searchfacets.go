@@ -0,0 +1,168 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/stdlib"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+)
+
+// The query params that filter package search results by facet value.
+const (
+	searchLicenseParam = "license"
+	searchHostParam    = "host"
+	searchMajorParam   = "major"
+)
+
+// maxSearchFilterLength is the maximum length of a facet value in a search
+// filter.
+const maxSearchFilterLength = 100
+
+var majorVersionRegexp = regexp.MustCompile(`^v[0-9]+$`)
+
+// searchFiltersFromRequest returns the facet filters of the search request r.
+// A search limited to the standard library filters by its host.
+func searchFiltersFromRequest(r *http.Request) (internal.SearchFilters, error) {
+	f := internal.SearchFilters{
+		License: strings.TrimSpace(r.FormValue(searchLicenseParam)),
+		Host:    strings.TrimSpace(r.FormValue(searchHostParam)),
+		Major:   strings.TrimSpace(r.FormValue(searchMajorParam)),
+	}
+	for _, v := range []string{f.License, f.Host, f.Major} {
+		if len(v) > maxSearchFilterLength {
+			return internal.SearchFilters{}, fmt.Errorf("search filter %q too long", v)
+		}
+	}
+	if f.Major != "" && !majorVersionRegexp.MatchString(f.Major) {
+		return internal.SearchFilters{}, fmt.Errorf("invalid major version %q", f.Major)
+	}
+	if searchStdlibOnly(r) {
+		// The standard library is the only module with host "std".
+		f.Host = stdlib.ModulePath
+	}
+	return f, nil
+}
+
+// searchFacet is a facet of package search results, displayed in the
+// sidebar of the search page.
+type searchFacet struct {
+	Name   string // displayed name, such as "License"
+	Param  string // query param that filters by the facet
+	Values []*searchFacetValue
+}
+
+// searchFacetValue is a value of a facet, with the number of results that
+// have it.
+type searchFacetValue struct {
+	Value string
+	Label string
+	Count string
+	// URL is the search narrowed to this value or, if Selected, the search
+	// without the filter.
+	URL      string
+	Selected bool
+}
+
+// searchFacetsTimeout bounds the time it takes to compute the facets of a
+// search, so that they do not slow down the search page.
+const searchFacetsTimeout = time.Second
+
+// startSearchFacets starts fetching the facets of the results of a package
+// search for cq with opts, alongside the search itself, and returns a
+// function that waits for them. See fetchSearchFacets for what it returns.
+func startSearchFacets(ctx context.Context, ds internal.DataSource, cq string, opts internal.SearchOptions, baseURL *url.URL) func() ([]*searchFacet, bool) {
+	type result struct {
+		facets      []*searchFacet
+		approximate bool
+	}
+	c := make(chan result, 1)
+	go func() {
+		facets, approximate := fetchSearchFacets(ctx, ds, cq, opts, baseURL)
+		c <- result{facets, approximate}
+	}()
+	return func() ([]*searchFacet, bool) {
+		r := <-c
+		return r.facets, r.approximate
+	}
+}
+
+// fetchSearchFacets returns the facets of the results of a package search
+// for cq with opts, or nil if ds cannot compute them within
+// searchFacetsTimeout, and whether their counts are approximate. Facets only
+// help narrow a search, so errors are logged rather than returned.
+func fetchSearchFacets(ctx context.Context, ds internal.DataSource, cq string, opts internal.SearchOptions, baseURL *url.URL) (_ []*searchFacet, approximate bool) {
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return nil, false
+	}
+	ctx, cancel := context.WithTimeout(ctx, searchFacetsTimeout)
+	defer cancel()
+	sf, err := db.GetSearchFacets(ctx, cq, opts)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			log.Warningf(ctx, "search facets for %q took longer than %s", cq, searchFacetsTimeout)
+		} else {
+			log.Errorf(ctx, "%v", err)
+		}
+		return nil, false
+	}
+	return newSearchFacets(sf, opts.Filters, baseURL), sf.Approximate
+}
+
+// newSearchFacets returns the facets to display for sf, the facet counts of
+// a search with the given filters whose URL is baseURL. Facets with no values
+// are omitted.
+func newSearchFacets(sf *internal.SearchFacets, filters internal.SearchFilters, baseURL *url.URL) []*searchFacet {
+	pr := message.NewPrinter(language.English)
+	var facets []*searchFacet
+	add := func(name, param, selected string, counts []*internal.FacetCount, label func(string) string) {
+		if len(counts) == 0 {
+			return
+		}
+		f := &searchFacet{Name: name, Param: param}
+		for _, c := range counts {
+			v := &searchFacetValue{
+				Value:    c.Value,
+				Label:    label(c.Value),
+				Count:    pr.Sprint(c.Count),
+				Selected: c.Value == selected,
+			}
+			q := url.Values{}
+			if baseURL != nil {
+				q = baseURL.Query()
+			}
+			q.Del("page")
+			if v.Selected {
+				q.Del(param)
+			} else {
+				q.Set(param, c.Value)
+			}
+			v.URL = (&url.URL{Path: "/search", RawQuery: q.Encode()}).String()
+			f.Values = append(f.Values, v)
+		}
+		facets = append(facets, f)
+	}
+	add("License", searchLicenseParam, filters.License, sf.Licenses, func(v string) string { return v })
+	add("Host", searchHostParam, filters.Host, sf.Hosts, func(v string) string {
+		if v == stdlib.ModulePath {
+			return "Standard library"
+		}
+		return v
+	})
+	add("Major version", searchMajorParam, filters.Major, sf.MajorVersions, func(v string) string { return v })
+	return facets
+}
@@ -0,0 +1,174 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestSearchFiltersFromRequest(t *testing.T) {
+	for _, test := range []struct {
+		query   string
+		want    internal.SearchFilters
+		wantErr bool
+	}{
+		{"q=foo", internal.SearchFilters{}, false},
+		{"q=foo&license=MIT&host=github.com&major=v2",
+			internal.SearchFilters{License: "MIT", Host: "github.com", Major: "v2"}, false},
+		{"q=foo&stdlib=1", internal.SearchFilters{Host: "std"}, false},
+		{"q=foo&stdlib=1&host=github.com&license=BSD-3-Clause",
+			internal.SearchFilters{License: "BSD-3-Clause", Host: "std"}, false},
+		{"q=foo&stdlib=0", internal.SearchFilters{}, false},
+		{"q=foo&major=2", internal.SearchFilters{}, true},
+		{"q=foo&host=" + strings.Repeat("a", maxSearchFilterLength+1), internal.SearchFilters{}, true},
+	} {
+		r := httptest.NewRequest("GET", "/search?"+test.query, nil)
+		got, err := searchFiltersFromRequest(r)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v, want error %t", test.query, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: got %+v, want %+v", test.query, got, test.want)
+		}
+	}
+}
+
+func TestNewSearchFacets(t *testing.T) {
+	baseURL, err := url.Parse("/search?q=yaml&m=package&page=2&major=v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sf := &internal.SearchFacets{
+		Licenses: []*internal.FacetCount{{Value: "MIT", Count: 1200}},
+		Hosts: []*internal.FacetCount{
+			{Value: "github.com", Count: 30},
+			{Value: "std", Count: 1},
+		},
+		MajorVersions: []*internal.FacetCount{{Value: "v2", Count: 31}},
+	}
+	got := newSearchFacets(sf, internal.SearchFilters{Major: "v2"}, baseURL)
+	want := []*searchFacet{
+		{Name: "License", Param: "license", Values: []*searchFacetValue{
+			{Value: "MIT", Label: "MIT", Count: "1,200", URL: "/search?license=MIT&m=package&major=v2&q=yaml"},
+		}},
+		{Name: "Host", Param: "host", Values: []*searchFacetValue{
+			{Value: "github.com", Label: "github.com", Count: "30", URL: "/search?host=github.com&m=package&major=v2&q=yaml"},
+			{Value: "std", Label: "Standard library", Count: "1", URL: "/search?host=std&m=package&major=v2&q=yaml"},
+		}},
+		{Name: "Major version", Param: "major", Values: []*searchFacetValue{
+			{Value: "v2", Label: "v2", Count: "31", URL: "/search?m=package&q=yaml", Selected: true},
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	if got := newSearchFacets(&internal.SearchFacets{}, internal.SearchFilters{}, baseURL); got != nil {
+		t.Errorf("got %v for no counts, want nil", got)
+	}
+}
+
+func TestServeSearchFacets(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	for _, modulePath := range []string{"github.com/a/yaml", "gitlab.com/b/yaml/v2"} {
+		m := sample.Module(modulePath, sample.VersionString, "p")
+		m.Packages()[0].Documentation[0].Synopsis = "Package p parses yaml."
+		fds.MustInsertModule(ctx, m)
+	}
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	for _, test := range []struct {
+		query         string
+		want, wantNot []string
+	}{
+		{
+			query: "q=yaml",
+			want: []string{
+				`data-test-id="search-facets"`,
+				"github.com/a/yaml/p",
+				"gitlab.com/b/yaml/v2/p",
+				`href="/search?host=gitlab.com&amp;q=yaml"`,
+				`href="/search?major=v2&amp;q=yaml"`,
+			},
+		},
+		{
+			query:   "q=yaml&host=gitlab.com",
+			want:    []string{"gitlab.com/b/yaml/v2/p", `href="/search?q=yaml" aria-current="true"`},
+			wantNot: []string{"github.com/a/yaml/p"},
+		},
+	} {
+		t.Run(test.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", "/search?"+test.query, nil))
+			res := w.Result()
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", res.StatusCode, http.StatusOK)
+			}
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := string(b)
+			for _, want := range test.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+			for _, notWant := range test.wantNot {
+				if strings.Contains(body, notWant) {
+					t.Errorf("body contains %q", notWant)
+				}
+			}
+		})
+	}
+}
+
+// slowFacetsDataSource is a data source whose facets are never ready.
+type slowFacetsDataSource struct {
+	*fakedatasource.FakeDataSource
+}
+
+func (slowFacetsDataSource) GetSearchFacets(ctx context.Context, q string, opts internal.SearchOptions) (*internal.SearchFacets, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestStartSearchFacets(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	m := sample.Module("github.com/a/yaml", sample.VersionString, "p")
+	m.Packages()[0].Documentation[0].Synopsis = "Package p parses yaml."
+	fds.MustInsertModule(ctx, m)
+	opts := internal.SearchOptions{MaxResults: 10}
+
+	facets, _ := startSearchFacets(ctx, fds, "yaml", opts, nil)()
+	if len(facets) == 0 {
+		t.Error("got no facets")
+	}
+
+	// Facets that take too long are left out.
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	facets, _ = startSearchFacets(ctx, slowFacetsDataSource{fds}, "yaml", opts, nil)()
+	if facets != nil {
+		t.Errorf("slow facets: got %v, want nil", facets)
+	}
+}
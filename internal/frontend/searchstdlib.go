@@ -0,0 +1,75 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/pkgsite/internal/cookie"
+)
+
+// searchStdlibParam is the query param that limits a package search to the
+// standard library when it is "1". Any other value turns the limit off.
+const searchStdlibParam = "stdlib"
+
+// stdlibOnlyCookieAge is how long the standard library toggle is remembered.
+const stdlibOnlyCookieAge = 365 * 24 * time.Hour
+
+// searchStdlibOnly reports whether the search request r is limited to the
+// standard library.
+func searchStdlibOnly(r *http.Request) bool {
+	return r.FormValue(searchStdlibParam) == "1"
+}
+
+// withSearchStdlibOnly remembers the standard library toggle of package
+// searches in a cookie. A search whose URL has the stdlib param sets or
+// clears the cookie. A package search without the param is redirected to one
+// with it if the cookie is set, so that the results depend only on the URL,
+// which is the key of the page cache. The handler must therefore wrap the
+// cache, not be wrapped by it.
+func withSearchStdlibOnly(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		_, err := r.Cookie(cookie.SearchStdlibOnly)
+		hasCookie := err == nil
+		switch {
+		case q.Has(searchStdlibParam):
+			if q.Get(searchStdlibParam) == "1" {
+				http.SetCookie(w, &http.Cookie{
+					Name:     cookie.SearchStdlibOnly,
+					Value:    "1",
+					Path:     "/",
+					MaxAge:   int(stdlibOnlyCookieAge.Seconds()),
+					SameSite: http.SameSiteLaxMode,
+				})
+			} else if hasCookie {
+				http.SetCookie(w, &http.Cookie{Name: cookie.SearchStdlibOnly, Path: "/", MaxAge: -1})
+			}
+		case hasCookie && q.Get("q") != "" && !q.Has(searchScopeParam) && searchMode(r) == searchModePackage:
+			q.Set(searchStdlibParam, "1")
+			http.Redirect(w, r, (&url.URL{Path: r.URL.Path, RawQuery: q.Encode()}).String(), http.StatusFound)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// stdlibToggleURL returns the URL of the search at baseURL with the standard
+// library limit turned on or off.
+func stdlibToggleURL(baseURL *url.URL, on bool) string {
+	q := url.Values{}
+	if baseURL != nil {
+		q = baseURL.Query()
+	}
+	q.Del("page")
+	if on {
+		q.Set(searchStdlibParam, "1")
+	} else {
+		q.Set(searchStdlibParam, "0")
+	}
+	return (&url.URL{Path: "/search", RawQuery: q.Encode()}).String()
+}
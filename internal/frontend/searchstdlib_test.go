@@ -0,0 +1,118 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/cookie"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestWithSearchStdlibOnly(t *testing.T) {
+	served := false
+	h := withSearchStdlibOnly(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served = true
+	}))
+	for _, test := range []struct {
+		name         string
+		url          string
+		cookie       bool
+		wantLocation string // empty if the request is served
+		wantCookie   string // value of the Set-Cookie header, if any
+	}{
+		{"no cookie", "/search?q=json", false, "", ""},
+		{"cookie", "/search?q=json", true, "/search?q=json&stdlib=1", ""},
+		{"turn on", "/search?q=json&stdlib=1", false, "", "search-stdlib-only=1; Path=/; Max-Age=31536000; SameSite=Lax"},
+		{"turn off", "/search?q=json&stdlib=0", true, "", "search-stdlib-only=; Path=/; Max-Age=0"},
+		{"already off", "/search?q=json&stdlib=0", false, "", ""},
+		{"symbol search", "/search?q=json&m=symbol", true, "", ""},
+		{"scoped search", "/search?q=json&in=example.com/mod@v1.0.0", true, "", ""},
+		{"empty query", "/search?q=", true, "", ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			served = false
+			r := httptest.NewRequest("GET", test.url, nil)
+			if test.cookie {
+				r.AddCookie(&http.Cookie{Name: cookie.SearchStdlibOnly, Value: "1"})
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if got := w.Header().Get("Location"); got != test.wantLocation {
+				t.Errorf("got location %q, want %q", got, test.wantLocation)
+			}
+			if served != (test.wantLocation == "") {
+				t.Errorf("served = %t, want %t", served, test.wantLocation == "")
+			}
+			if got := w.Header().Get("Set-Cookie"); got != test.wantCookie {
+				t.Errorf("got Set-Cookie %q, want %q", got, test.wantCookie)
+			}
+		})
+	}
+}
+
+func TestSearchStdlibOnly(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	for _, m := range []*internal.Module{
+		sample.Module("std", "v1.20.0", "encoding/json"),
+		sample.Module("example.com/json", "v1.0.0", "json"),
+	} {
+		for _, u := range m.Units {
+			if u.Documentation != nil {
+				u.Documentation[0].Synopsis = "Package json implements JSON."
+			}
+		}
+		fds.MustInsertModule(ctx, m)
+	}
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	for _, test := range []struct {
+		url        string
+		want       []string
+		dontWant   []string
+		wantToggle string
+	}{
+		{
+			"/search?q=json&m=package",
+			[]string{"encoding/json", "example.com/json/json"},
+			nil,
+			"/search?m=package&amp;q=json&amp;stdlib=1",
+		},
+		{
+			"/search?q=json&m=package&stdlib=1",
+			[]string{"encoding/json", "Showing only the standard library."},
+			[]string{"example.com/json/json"},
+			"/search?m=package&amp;q=json&amp;stdlib=0",
+		},
+	} {
+		t.Run(test.url, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", test.url, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+			}
+			body := w.Body.String()
+			for _, want := range append(test.want, test.wantToggle) {
+				if !strings.Contains(body, want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+			for _, dw := range test.dontWant {
+				if strings.Contains(body, dw) {
+					t.Errorf("body contains %q", dw)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package serrors
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend/page"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+// A PackageFailure explains why a package that was found in a module has no
+// documentation.
+type PackageFailure struct {
+	// Reason is a sentence describing the problem.
+	Reason string
+	// Diagnostics point at the problems in the package's files, if known.
+	Diagnostics []*Diagnostic
+}
+
+// A Diagnostic is a problem with a file of a package.
+type Diagnostic struct {
+	// Position is the file, line and column of the problem, such as
+	// "p/p.go:5:24". It is empty if the problem is not with a particular file.
+	Position string
+	// URL links to the position in the module's repository, if it is known.
+	URL     string
+	Message string
+}
+
+// NewPackageFailure returns a PackageFailure for pvs, the state of a package
+// after its module was processed. It returns nil if the package was processed
+// successfully. If si is not nil, it is used to link the diagnostics to the
+// module's source.
+func NewPackageFailure(pvs *internal.PackageVersionState, si *source.Info) *PackageFailure {
+	if pvs == nil || pvs.Status < 600 {
+		return nil
+	}
+	f := &PackageFailure{Reason: packageFailureReason(pvs)}
+	for _, d := range pvs.Diagnostics {
+		sd := &Diagnostic{Message: d.Message}
+		switch {
+		case d.File == "":
+		case d.Line == 0:
+			sd.Position = d.File
+			sd.URL = si.FileURL(d.File)
+		case d.Column == 0:
+			sd.Position = fmt.Sprintf("%s:%d", d.File, d.Line)
+			sd.URL = si.LineURL(d.File, d.Line)
+		default:
+			sd.Position = fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+			sd.URL = si.LineURL(d.File, d.Line)
+		}
+		f.Diagnostics = append(f.Diagnostics, sd)
+	}
+	return f
+}
+
+func packageFailureReason(pvs *internal.PackageVersionState) string {
+	switch pvs.Status {
+	case derrors.ToStatus(derrors.PackageBuildContextNotSupported):
+		var bcs []string
+		for _, bc := range internal.BuildContexts {
+			bcs = append(bcs, bc.String())
+		}
+		return "None of the package's files are built for the platforms documentation is rendered for (" +
+			strings.Join(bcs, ", ") + "), because of their build constraints or file names."
+	case derrors.ToStatus(derrors.PackageMaxFileSizeLimitExceeded):
+		return "The package has a file that is too large to be processed."
+	case derrors.ToStatus(derrors.PackageInvalidContents):
+		if len(pvs.Diagnostics) == 0 && pvs.Error != "" {
+			return "The package's files do not make up a valid Go package: " + pvs.Error
+		}
+		return "The package's files do not make up a valid Go package."
+	case derrors.ToStatus(derrors.PackageBadImportPath):
+		return "The package's import path is not valid."
+	default:
+		return "The package could not be processed."
+	}
+}
+
+// PackageFailureError returns a 404 for the package at fullPath, explaining
+// why it has no documentation.
+func PackageFailureError(fullPath, requestedVersion string, f *PackageFailure) error {
+	p := fullPath
+	if requestedVersion != version.Latest {
+		p = fullPath + "@" + requestedVersion
+	}
+	return &ServerError{
+		Status: http.StatusNotFound,
+		Epage: &page.ErrorPage{
+			MessageTemplate: template.MakeTrustedTemplate(`
+					<h3 class="Error-message">There is no documentation for {{.Path}}.</h3>
+					<h4 class="Error-message">Why is documentation missing?</h4>
+					<p class="Error-message">{{.Failure.Reason}}</p>
+					{{with .Failure.Diagnostics}}
+					  <ul class="Error-diagnostics">
+					    {{range .}}
+					      <li><code>
+					        {{- if .URL}}<a href="{{.URL}}">{{.Position}}</a>: {{else if .Position}}{{.Position}}: {{end -}}
+					        {{.Message -}}
+					      </code></li>
+					    {{end}}
+					  </ul>
+					{{end}}
+					<p class="Error-message">
+					  Once the problem is fixed in a new version of the module,
+					  <a href="/about#adding-a-package">request that version</a> to see its documentation.
+					</p>`),
+			MessageData: struct {
+				Path    string
+				Failure *PackageFailure
+			}{p, f},
+		},
+	}
+}
@@ -10,8 +10,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
 	"io"
 	"io/fs"
+	"net"
 	"net/http"
 	hpprof "net/http/pprof"
 	"os"
@@ -22,7 +24,10 @@ import (
 	"github.com/google/safehtml/template"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/debugflags"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docfeedback"
+	"golang.org/x/pkgsite/internal/docrender"
 	"golang.org/x/pkgsite/internal/experiment"
 	pagepkg "golang.org/x/pkgsite/internal/frontend/page"
 	"golang.org/x/pkgsite/internal/frontend/serrors"
@@ -33,6 +38,7 @@ import (
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/memory"
 	"golang.org/x/pkgsite/internal/middleware/stats"
+	"golang.org/x/pkgsite/internal/pageviews"
 	"golang.org/x/pkgsite/internal/queue"
 	"golang.org/x/pkgsite/internal/version"
 	"golang.org/x/pkgsite/internal/vuln"
@@ -41,10 +47,13 @@ import (
 // Server can be installed to serve the go discovery frontend.
 type Server struct {
 	fetchServer FetchServerInterface
-	// getDataSource should never be called from a handler. It is called only in Server.errorHandler.
+	// getDataSource should never be called from a handler. It is called only
+	// in Server.dataSource, which Server.errorHandler calls.
 	getDataSource      func(context.Context) internal.DataSource
+	namespaces         []*Namespace
 	queue              queue.Queue
 	templateFS         template.TrustedFS
+	templateOverrides  *templates.Overrides
 	staticFS           fs.FS
 	thirdPartyFS       fs.FS
 	devMode            bool
@@ -54,12 +63,30 @@ type Server struct {
 	appVersionLabel    string
 	googleTagManagerID string
 	serveStats         bool
+	serveLLMs          bool
+	serveAdvisories    bool
+	defaultBC          internal.BuildContext // shown when a request names no build context
+	baseURL            string                // scheme and host of absolute links to the server
+	trustedProxyHops   int                   // see config.Config.TrustedProxyHops
 	reporter           derrors.Reporter
 	fileMux            *http.ServeMux
 	vulnClient         *vuln.Client
 	versionID          string
 	instanceID         string
 	depsDevHTTPClient  *http.Client
+	autocomplete       *autocompleteCache
+	pageViews          *pageviews.Counter
+	renderer           docrender.Renderer
+	pageCache          PageCache
+	claims             ClaimStore
+	docFeedback        docfeedback.Filer
+	feedbackLimiter    *reportLimiter
+	purger             *pagePurger // nil if pageCache is
+	allocs             *memory.AllocRecorder
+	// lookupTXT and claimHTTPClient are used to verify claims. They are
+	// replaced in tests.
+	lookupTXT       func(ctx context.Context, name string) ([]string, error)
+	claimHTTPClient *http.Client
 
 	mu        sync.Mutex // Protects all fields below
 	templates map[string]*template.Template
@@ -83,23 +110,53 @@ type ServerConfig struct {
 	FetchServer FetchServerInterface
 	// DataSourceGetter should return a DataSource on each call.
 	// It should be goroutine-safe.
-	DataSourceGetter  func(context.Context) internal.DataSource
+	DataSourceGetter func(context.Context) internal.DataSource
+	// Namespaces are the sets of modules that are served from the data
+	// sources of their own instead of the one of DataSourceGetter.
+	Namespaces        []*Namespace
 	Queue             queue.Queue
-	TemplateFS        template.TrustedFS // for loading templates safely
-	StaticFS          fs.FS              // for static/ directory
-	ThirdPartyFS      fs.FS              // for third_party/ directory
+	TemplateFS        template.TrustedFS   // for loading templates safely
+	TemplateOverrides *templates.Overrides // if non-nil, shadows templates in TemplateFS
+	StaticFS          fs.FS                // for static/ directory
+	ThirdPartyFS      fs.FS                // for third_party/ directory
 	DevMode           bool
 	LocalMode         bool
 	LocalModules      []LocalModule
 	Reporter          derrors.Reporter
 	VulndbClient      *vuln.Client
 	DepsDevHTTPClient *http.Client
+	// PageViews, if non-nil, counts views of unit pages.
+	PageViews *pageviews.Counter
+	// Renderer, if non-nil, renders documentation and READMEs. If nil,
+	// rendering is done in process.
+	Renderer docrender.Renderer
+	// PageCache, if non-nil, is the cache of pages that can be purged with
+	// /_admin/purge-cache.
+	PageCache PageCache
+	// Claims, if non-nil, lets owners of module paths claim them and manage
+	// their settings at /claim.
+	Claims ClaimStore
+	// DocFeedback, if non-nil, files the reports of problems with
+	// documentation that users make from unit pages.
+	DocFeedback docfeedback.Filer
+	// Allocs, if non-nil, holds the heap allocations of requests, which are
+	// shown on /_debug/allocs.
+	Allocs *memory.AllocRecorder
+	// BaseURL, if non-empty, is the scheme and host that the server is
+	// served at, overriding Config.BaseURL. Absolute links to the server are
+	// made from it, or from defaultBaseURL if neither is set.
+	BaseURL string
 }
 
 // NewServer creates a new Server for the given database and template directory.
 func NewServer(scfg ServerConfig) (_ *Server, err error) {
 	defer derrors.Wrap(&err, "NewServer(...)")
-	ts, err := templates.ParsePageTemplates(scfg.TemplateFS)
+	if scfg.TemplateOverrides != nil {
+		if err := scfg.TemplateOverrides.Validate(scfg.StaticFS); err != nil {
+			return nil, err
+		}
+	}
+	ts, err := templates.ParsePageTemplates(scfg.TemplateFS, scfg.TemplateOverrides)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing templates: %v", err)
 	}
@@ -107,8 +164,10 @@ func NewServer(scfg ServerConfig) (_ *Server, err error) {
 	s := &Server{
 		fetchServer:       scfg.FetchServer,
 		getDataSource:     scfg.DataSourceGetter,
+		namespaces:        scfg.Namespaces,
 		queue:             scfg.Queue,
 		templateFS:        scfg.TemplateFS,
+		templateOverrides: scfg.TemplateOverrides,
 		staticFS:          scfg.StaticFS,
 		thirdPartyFS:      scfg.ThirdPartyFS,
 		devMode:           scfg.DevMode,
@@ -119,6 +178,20 @@ func NewServer(scfg ServerConfig) (_ *Server, err error) {
 		fileMux:           http.NewServeMux(),
 		vulnClient:        scfg.VulndbClient,
 		depsDevHTTPClient: scfg.DepsDevHTTPClient,
+		autocomplete:      newAutocompleteCache(autocompleteCacheSize),
+		pageViews:         scfg.PageViews,
+		renderer:          scfg.Renderer,
+		pageCache:         scfg.PageCache,
+		claims:            scfg.Claims,
+		docFeedback:       scfg.DocFeedback,
+		feedbackLimiter:   newReportLimiter(docFeedbackLimit, docFeedbackWindow),
+		allocs:            scfg.Allocs,
+		baseURL:           defaultBaseURL,
+		lookupTXT:         net.DefaultResolver.LookupTXT,
+		claimHTTPClient:   &http.Client{Timeout: 10 * time.Second},
+	}
+	if s.renderer == nil {
+		s.renderer = NewLocalRenderer()
 	}
 	if s.depsDevHTTPClient == nil {
 		s.depsDevHTTPClient = http.DefaultClient
@@ -127,8 +200,26 @@ func NewServer(scfg ServerConfig) (_ *Server, err error) {
 		s.appVersionLabel = scfg.Config.AppVersionLabel()
 		s.googleTagManagerID = scfg.Config.GoogleTagManagerID
 		s.serveStats = scfg.Config.ServeStats
+		s.serveLLMs = scfg.Config.ServeLLMsTxt
+		s.serveAdvisories = scfg.Config.PrivateAdvisories
 		s.versionID = scfg.Config.VersionID
 		s.instanceID = scfg.Config.InstanceID
+		s.trustedProxyHops = scfg.Config.TrustedProxyHops
+		if scfg.Config.BaseURL != "" {
+			s.baseURL = scfg.Config.BaseURL
+		}
+		if bc := scfg.Config.DefaultBuildContext; bc != "" {
+			s.defaultBC, err = internal.ParseBuildContext(bc)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	if s.pageCache != nil {
+		s.purger = newPagePurger(s.pageCache, purgeDelay)
+	}
+	if scfg.BaseURL != "" {
+		s.baseURL = strings.TrimSuffix(scfg.BaseURL, "/")
 	}
 	errorPageBytes, err := s.renderErrorPage(context.Background(), http.StatusInternalServerError, "error", nil)
 	if err != nil {
@@ -138,6 +229,17 @@ func NewServer(scfg ServerConfig) (_ *Server, err error) {
 	return s, nil
 }
 
+// defaultBaseURL is the base URL of servers that are not configured with
+// one.
+const defaultBaseURL = "https://pkg.go.dev"
+
+// A PageCache holds the pages cached by a Cacher, so that they can be purged.
+type PageCache interface {
+	// DeletePath deletes the cached pages for path and for its versions,
+	// tabs and subdirectories. It returns the number of pages deleted.
+	DeletePath(ctx context.Context, path string) (int, error)
+}
+
 // A Cacher is used to create request caches for http handlers.
 type Cacher interface {
 	// Cache returns a new middleware that caches every request.
@@ -148,6 +250,38 @@ type Cacher interface {
 	// authValues is the set of values that could be set on the authHeader in
 	// order to bypass the cache.
 	Cache(name string, expirer func(r *http.Request) time.Duration, authValues []string) func(http.Handler) http.Handler
+	// CacheStaleOnError is like Cache, but also keeps a stale copy of each
+	// page for staleTTL, and serves it in place of 5xx errors, with a banner
+	// that says the page may be stale.
+	CacheStaleOnError(name string, expirer func(r *http.Request) time.Duration, staleTTL time.Duration, authValues []string) func(http.Handler) http.Handler
+}
+
+// apiPathPrefixes are the path prefixes of the routes of the frontend that
+// serve JSON, or that accept reports and advisories from clients.
+var apiPathPrefixes = []string{
+	"/autocomplete",
+	"/symbol-version",
+	"/imported-by-history",
+	"/tree/",
+	"/analysis/",
+	"/advisories",
+}
+
+// IsAPIRequest reports whether r is for an API route of the frontend: one
+// that serves JSON, accepts reports or advisories, or serves a unit page in
+// a format for programs rather than browsers. Clients of those routes can
+// present API keys.
+func IsAPIRequest(r *http.Request) bool {
+	for _, p := range apiPathPrefixes {
+		if strings.HasPrefix(r.URL.Path, p) {
+			return true
+		}
+	}
+	switch r.URL.Query().Get("m") {
+	case "json", "md", "llms":
+		return true
+	}
+	return false
 }
 
 // Install registers server routes using the given handler registration func.
@@ -158,20 +292,31 @@ func (s *Server) Install(handle func(string, http.Handler), cacher Cacher, authV
 		detailHandler http.Handler = s.errorHandler(s.serveDetails)
 		fetchHandler  http.Handler
 		searchHandler http.Handler = s.errorHandler(s.serveSearch)
+		treeHandler   http.Handler = s.errorHandler(s.serveTree)
 		vulnHandler   http.Handler = s.errorHandler(s.serveVuln)
 	)
 	if s.fetchServer != nil {
-		fetchHandler = s.errorHandler(s.fetchServer.ServeFetch)
+		fetchHandler = s.errorHandler(s.serveFetch)
 	}
 	if cacher != nil {
 		// The cache middleware uses the URL string as the key for content served
 		// by the handlers it wraps. Be careful not to wrap the handler it returns
 		// with a handler that rewrites the URL in a way that could cause key
 		// collisions, like http.StripPrefix.
-		detailHandler = cacher.Cache("details", detailsTTL, authValues)(detailHandler)
+		// Unit pages stay available while the database is briefly
+		// unavailable, by serving stale copies in place of errors.
+		detailHandler = cacher.CacheStaleOnError("details", detailsTTL, detailsStaleTTL, authValues)(detailHandler)
 		searchHandler = cacher.Cache("search", searchTTL, authValues)(searchHandler)
+		treeHandler = cacher.Cache("tree", treeTTL, authValues)(treeHandler)
 		vulnHandler = cacher.Cache("vuln", vulnTTL, authValues)(vulnHandler)
 	}
+	// The standard library toggle of searches redirects based on a cookie, so
+	// it must be outside the cache.
+	searchHandler = withSearchStdlibOnly(searchHandler)
+	if s.pageViews != nil {
+		// Count views outside the cache, so that cached pages are counted too.
+		detailHandler = s.countPageViews(detailHandler)
+	}
 	// Each AppEngine instance is created in response to a start request, which
 	// is an empty HTTP GET request to /_ah/start when scaling is set to manual
 	// or basic, and /_ah/warmup when scaling is automatic and min_instances is
@@ -199,10 +344,32 @@ func (s *Server) Install(handle func(string, http.Handler), cacher Cacher, authV
 	handle("GET /play/fmt", http.HandlerFunc(s.handleFmt))
 	handle("/play/share", http.HandlerFunc(s.proxyPlayground))
 	handle("GET /search", searchHandler)
+	handle("GET /autocomplete", s.errorHandler(s.serveAutocomplete))
+	handle("GET /symbol-version", s.errorHandler(s.serveSymbolVersion))
+	handle("GET /imported-by-history", s.errorHandler(s.serveImportedByHistory))
+	handle("GET /switch-version", s.errorHandler(s.serveSwitchVersion))
+	handle("GET /go-release-notes", s.errorHandler(s.serveGoReleaseNotes))
 	handle("GET /search-help", s.staticPageHandler("search-help", "Search Help"))
 	handle("GET /license-policy", s.licensePolicyHandler())
 	handle("GET /about", s.staticPageHandler("about", "About"))
 	handle("GET /badge/", http.HandlerFunc(s.badgeHandler))
+	handle("GET /status/", s.errorHandler(s.serveModuleStatus))
+	handle("GET /sbom/", s.errorHandler(s.serveSBOM))
+	handle("GET /modgraph/", s.errorHandler(s.serveModGraph))
+	handle("GET /tree/", treeHandler)
+	handle("POST /analysis/", http.HandlerFunc(s.handleAnalysisReport))
+	if s.serveAdvisories {
+		handle("POST /advisories", http.HandlerFunc(s.handlePublishAdvisory))
+		handle("DELETE /advisories/", http.HandlerFunc(s.handleWithdrawAdvisory))
+	}
+	if s.claims != nil {
+		handle("GET /claim", s.errorHandler(s.serveNewClaim))
+		handle("POST /claim", s.errorHandler(s.handleNewClaim))
+		handle("/claim/", s.errorHandler(s.serveClaim))
+	}
+	if s.docFeedback != nil {
+		handle("/report-doc-issue", s.errorHandler(s.serveDocFeedback))
+	}
 	handle("GET /C", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Package "C" is a special case: redirect to /cmd/cgo.
 		// (This is what golang.org/C does.)
@@ -229,26 +396,29 @@ Disallow: /fetch/*
 Sitemap: https://pkg.go.dev/sitemap/index.xml
 `))
 	}))
+	handle("POST /_admin/purge-cache", ifDebug(s.handlePurgeCache))
 	s.installDebugHandlers(handle)
 }
 
+// ifDebug returns a handler that calls h only for requests whose
+// config.AllowDebugHeader header holds the secret debug value, and serves a
+// 404 otherwise.
+func ifDebug(h func(http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dbg := r.Header.Get(config.AllowDebugHeader)
+		if dbg == "" || dbg != os.Getenv("GO_DISCOVERY_DEBUG_HEADER_VALUE") {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		h(w, r)
+	})
+}
+
 // installDebugHandlers installs handlers for debugging. Most of the handlers
 // are provided by the net/http/pprof package. Although that package installs
 // them on the default ServeMux in its init function, we must install them
 // on our own ServeMux.
 func (s *Server) installDebugHandlers(handle func(string, http.Handler)) {
-
-	ifDebug := func(h func(http.ResponseWriter, *http.Request)) http.HandlerFunc {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			dbg := r.Header.Get(config.AllowDebugHeader)
-			if dbg == "" || dbg != os.Getenv("GO_DISCOVERY_DEBUG_HEADER_VALUE") {
-				http.Error(w, "not found", http.StatusNotFound)
-				return
-			}
-			h(w, r)
-		})
-	}
-
 	handle("/_debug/pprof/", ifDebug(hpprof.Index))
 	handle("/_debug/pprof/cmdline", ifDebug(hpprof.Cmdline))
 	handle("/_debug/pprof/profile", ifDebug(hpprof.Profile))
@@ -303,6 +473,42 @@ func (s *Server) installDebugHandlers(handle func(string, http.Handler)) {
 		fmt.Fprintf(w, "</body></html>\n")
 
 	}))
+
+	handle("/_debug/allocs", ifDebug(s.serveAllocs))
+}
+
+// serveAllocs serves the heap allocations of requests recorded in s.allocs:
+// the totals for each route, and the requests that allocated the most.
+func (s *Server) serveAllocs(w http.ResponseWriter, _ *http.Request) {
+	if s.allocs == nil {
+		http.Error(w, "allocations are not being recorded", http.StatusNotFound)
+		return
+	}
+	route := func(r string) string {
+		if r == "" {
+			return "/"
+		}
+		return html.EscapeString(r)
+	}
+	fmt.Fprintf(w, "<html><body style='font-family: sans-serif'>\n")
+	fmt.Fprintf(w, "<p>Allocations are read from process-wide counters, so they include those of concurrent requests.</p>\n")
+
+	fmt.Fprintf(w, "<h3>Routes</h3>\n<table>\n")
+	fmt.Fprintf(w, "<tr><th>Route</th> <th>Requests</th> <th>Total</th> <th>Mean</th> <th>Max</th> <th>Objects</th></tr>\n")
+	for _, r := range s.allocs.Routes() {
+		fmt.Fprintf(w, "<tr><td>%s</td> <td align='right'>%d</td> <td align='right'>%s</td> <td align='right'>%s</td> <td align='right'>%s</td> <td align='right'>%d</td></tr>\n",
+			route(r.Route), r.Requests, memory.Format(r.Total.Bytes), memory.Format(r.MeanBytes()), memory.Format(r.MaxBytes), r.Total.Objects)
+	}
+	fmt.Fprintf(w, "</table>\n")
+
+	fmt.Fprintf(w, "<h3>Top requests</h3>\n<table>\n")
+	fmt.Fprintf(w, "<tr><th>URL</th> <th>Route</th> <th>Bytes</th> <th>Objects</th> <th>Duration</th> <th>Concurrent</th> <th>Start</th></tr>\n")
+	for _, r := range s.allocs.Top() {
+		fmt.Fprintf(w, "<tr><td>%s</td> <td>%s</td> <td align='right'>%s</td> <td align='right'>%d</td> <td align='right'>%s</td> <td align='right'>%d</td> <td>%s</td></tr>\n",
+			html.EscapeString(r.URL), route(r.Route), memory.Format(r.Bytes), r.Objects, r.Duration.Round(time.Millisecond), r.Concurrent, r.Start.Format(time.RFC3339))
+	}
+	fmt.Fprintf(w, "</table>\n")
+	fmt.Fprintf(w, "</body></html>\n")
 }
 
 // InstallFS adds path under the /files handler, serving the files in fsys.
@@ -321,6 +527,9 @@ const (
 	longTTL = 10 * time.Minute
 	// tinyTTL is used to cache crawled pages.
 	tinyTTL = 1 * time.Minute
+	// detailsStaleTTL is how long stale copies of details pages are kept, to
+	// be served if the page cannot be generated again after it expires.
+	detailsStaleTTL = 24 * time.Hour
 	// symbolSearchTTL is used for most symbol searches.
 	symbolSearchTTL = 24 * time.Hour
 	// slowSymbolSearchTTL is for symbol searches that are known to be slow.
@@ -460,7 +669,16 @@ func (s *Server) newBasePage(r *http.Request, title string) pagepkg.BasePage {
 	} else {
 		searchPrompt = "Search packages or symbols"
 	}
+	var namespace string
+	if ns := namespaceFromContext(r.Context()); ns != nil {
+		namespace = ns.Name
+		searchPrompt += " in " + ns.Prefix
+	}
 
+	var timings []stats.StageTiming
+	if debugflags.FromContext(r.Context()).ShowTiming {
+		timings = stats.Timings(r.Context())
+	}
 	return pagepkg.BasePage{
 		HTMLTitle:          title,
 		Query:              q,
@@ -476,6 +694,8 @@ func (s *Server) newBasePage(r *http.Request, title string) pagepkg.BasePage {
 		// indicates that we should use heuristics to determine whether the
 		// user wants to search for symbols or packages.
 		SearchMode: "",
+		Namespace:  namespace,
+		Timings:    timings,
 	}
 }
 
@@ -499,8 +719,16 @@ func (s *Server) PanicHandler() (_ http.HandlerFunc, err error) {
 
 func (s *Server) errorHandler(f func(w http.ResponseWriter, r *http.Request, ds internal.DataSource) error) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		ns, ok := s.namespaceForRequest(r)
+		if !ok {
+			s.serveError(w, r, &serrors.ServerError{Status: http.StatusNotFound})
+			return
+		}
+		if ns != nil {
+			r = r.WithContext(newNamespaceContext(r.Context(), ns))
+		}
 		// Obtain a DataSource to use for this request.
-		ds := s.getDataSource(r.Context())
+		ds := s.dataSource(r.Context())
 		if err := f(w, r, ds); err != nil {
 			s.serveError(w, r, err)
 		}
@@ -593,7 +821,7 @@ func (s *Server) renderErrorPage(ctx context.Context, status int, templateName s
 		templateName = "error"
 	}
 
-	etmpl, err := s.findTemplate(templateName)
+	etmpl, err := s.findTemplate(ctx, templateName)
 	if err != nil {
 		return nil, err
 	}
@@ -629,19 +857,30 @@ func (s *Server) servePage(ctx context.Context, w http.ResponseWriter, templateN
 func (s *Server) renderPage(ctx context.Context, templateName string, page any) ([]byte, error) {
 	defer stats.Elapsed(ctx, "renderPage")()
 
-	tmpl, err := s.findTemplate(templateName)
+	tmpl, err := s.findTemplate(ctx, templateName)
 	if err != nil {
 		return nil, err
 	}
 	return executeTemplate(ctx, templateName, tmpl, page)
 }
 
-func (s *Server) findTemplate(templateName string) (*template.Template, error) {
+func (s *Server) findTemplate(ctx context.Context, templateName string) (*template.Template, error) {
+	if debugflags.FromContext(ctx).Renderer == debugflags.RendererReload {
+		// Parse the templates for this request only.
+		ts, err := templates.ParsePageTemplates(s.templateFS, s.templateOverrides)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing templates: %v", err)
+		}
+		if tmpl := ts[templateName]; tmpl != nil {
+			return tmpl, nil
+		}
+		return nil, fmt.Errorf("BUG: templates[%q] not found", templateName)
+	}
 	if s.devMode {
 		s.mu.Lock()
 		defer s.mu.Unlock()
 		var err error
-		s.templates, err = templates.ParsePageTemplates(s.templateFS)
+		s.templates, err = templates.ParsePageTemplates(s.templateFS, s.templateOverrides)
 		if err != nil {
 			return nil, fmt.Errorf("error parsing templates: %v", err)
 		}
@@ -654,6 +893,7 @@ func (s *Server) findTemplate(templateName string) (*template.Template, error) {
 }
 
 func executeTemplate(ctx context.Context, templateName string, tmpl *template.Template, data any) ([]byte, error) {
+	defer stats.Time(ctx, stats.StageTemplate)()
 	var buf bytes.Buffer
 	if err := tmpl.Execute(&buf, data); err != nil {
 		log.Errorf(ctx, "Error executing page template %q: %v", templateName, err)
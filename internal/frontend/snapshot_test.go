@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/htmlsnapshot"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+var update = flag.Bool("update", false, "update goldens instead of checking against them")
+
+func TestPageSnapshots(t *testing.T) {
+	ctx := context.Background()
+	defer func(f func() int) { randomTipIndex = f }(randomTipIndex)
+	randomTipIndex = func() int { return 0 }
+
+	fds := fakedatasource.New()
+	for i, v := range []string{"v1.0.0", "v1.1.0"} {
+		m := sample.Module(sample.ModulePath, v, "foo", "foo/bar")
+		// Dates are displayed, so they must not depend on when the test runs.
+		m.CommitTime = time.Date(2024, 1, 2+i, 0, 0, 0, 0, time.UTC)
+		for _, u := range m.Units {
+			u.CommitTime = m.CommitTime
+		}
+		fds.MustInsertModule(ctx, m)
+		fds.InsertModuleVersionState(&internal.ModuleVersionState{
+			ModulePath: sample.ModulePath,
+			Version:    v,
+			Status:     http.StatusOK,
+		})
+	}
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+		// Keep the pages independent of deps.dev.
+		DepsDevHTTPClient: &http.Client{Transport: failingTransport{}},
+	})
+
+	for _, test := range []struct {
+		name, path string
+		wantStatus int
+	}{
+		{"homepage", "/", http.StatusOK},
+		{"about", "/about", http.StatusOK},
+		{"search-help", "/search-help", http.StatusOK},
+		{"license-policy", "/license-policy", http.StatusOK},
+		{"subrepo", "/golang.org/x", http.StatusOK},
+		{"badge", "/badge/", http.StatusOK},
+		{"search", "/search?q=foo", http.StatusOK},
+		{"unit-main", "/" + sample.ModulePath + "/foo", http.StatusOK},
+		{"unit-versions", "/" + sample.ModulePath + "/foo?tab=versions", http.StatusOK},
+		{"unit-licenses", "/" + sample.ModulePath + "/foo?tab=licenses", http.StatusOK},
+		{"unit-imports", "/" + sample.ModulePath + "/foo?tab=imports", http.StatusOK},
+		{"unit-importedby", "/" + sample.ModulePath + "/foo?tab=importedby", http.StatusOK},
+		{"unit-source", "/" + sample.ModulePath + "/foo?tab=source", http.StatusOK},
+		{"unit-diff", "/" + sample.ModulePath + "/foo?tab=diff&from=v1.0.0&to=v1.1.0", http.StatusOK},
+		{"unit-analysis", "/" + sample.ModulePath + "/foo?tab=analysis", http.StatusOK},
+		{"status", "/status/" + sample.ModulePath + "@v1.1.0", http.StatusOK},
+		{"module", "/" + sample.ModulePath, http.StatusOK},
+		// The fake data source has no symbol history, so this is an error page.
+		{"unit-history-error", "/" + sample.ModulePath + "/foo?tab=history&symbol=Type", http.StatusNotFound},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if w.Code != test.wantStatus {
+				t.Fatalf("GET %s: got status %d, want %d", test.path, w.Code, test.wantStatus)
+			}
+			htmlsnapshot.Compare(t, w.Body.Bytes(), "snapshots/"+strings.ReplaceAll(test.name, "/", "-")+".golden", *update)
+		})
+	}
+}
+
+// failingTransport is an http.RoundTripper that fails every request.
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("no network in tests")
+}
@@ -0,0 +1,47 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"fmt"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/stdlib"
+)
+
+// Snippets holds text that users can copy to start using a unit.
+type Snippets struct {
+	// Import is an import declaration for the unit, such as
+	// `import "example.com/m/v2/pkg"`. It is empty if the unit is not
+	// an importable package.
+	Import string
+
+	// Get is the go command that adds the unit at the displayed version to a
+	// module, such as "go get example.com/m/v2/pkg@v2.1.0", or installs it
+	// if it is a command. It is empty for the standard library.
+	Get string
+}
+
+// unitSnippets returns the Snippets for um. The unit's path already carries
+// any major version suffix, and its resolved version is used verbatim so
+// that pseudo-versions and +incompatible versions are reproduced exactly.
+func unitSnippets(um *internal.UnitMeta) *Snippets {
+	s := &Snippets{}
+	if um.IsPackage() && !um.IsCommand() {
+		s.Import = fmt.Sprintf("import %q", um.Path)
+	}
+	if um.ModulePath == stdlib.ModulePath {
+		return s
+	}
+	switch {
+	case um.IsCommand():
+		s.Get = fmt.Sprintf("go install %s@%s", um.Path, um.Version)
+	case um.IsPackage():
+		s.Get = fmt.Sprintf("go get %s@%s", um.Path, um.Version)
+	default:
+		s.Get = fmt.Sprintf("go get %s@%s", um.ModulePath, um.Version)
+	}
+	return s
+}
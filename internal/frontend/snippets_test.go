@@ -0,0 +1,77 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/stdlib"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestUnitSnippets(t *testing.T) {
+	for _, test := range []struct {
+		name                          string
+		path, modulePath, version, pn string
+		want                          *Snippets
+	}{
+		{
+			name: "package",
+			path: "example.com/m/pkg", modulePath: "example.com/m", version: "v1.2.3", pn: "pkg",
+			want: &Snippets{
+				Import: `import "example.com/m/pkg"`,
+				Get:    "go get example.com/m/pkg@v1.2.3",
+			},
+		},
+		{
+			name: "major version suffix",
+			path: "example.com/m/v2/pkg", modulePath: "example.com/m/v2", version: "v2.1.0", pn: "pkg",
+			want: &Snippets{
+				Import: `import "example.com/m/v2/pkg"`,
+				Get:    "go get example.com/m/v2/pkg@v2.1.0",
+			},
+		},
+		{
+			name: "pseudo-version",
+			path: "example.com/m", modulePath: "example.com/m", version: "v0.0.0-20200101120000-0123456789ab", pn: "m",
+			want: &Snippets{
+				Import: `import "example.com/m"`,
+				Get:    "go get example.com/m@v0.0.0-20200101120000-0123456789ab",
+			},
+		},
+		{
+			name: "incompatible",
+			path: "example.com/m", modulePath: "example.com/m", version: "v3.0.0+incompatible", pn: "m",
+			want: &Snippets{
+				Import: `import "example.com/m"`,
+				Get:    "go get example.com/m@v3.0.0+incompatible",
+			},
+		},
+		{
+			name: "command",
+			path: "example.com/m/v2/cmd/tool", modulePath: "example.com/m/v2", version: "v2.0.0", pn: "main",
+			want: &Snippets{Get: "go install example.com/m/v2/cmd/tool@v2.0.0"},
+		},
+		{
+			name: "directory",
+			path: "example.com/m/v2/dir", modulePath: "example.com/m/v2", version: "v2.0.0",
+			want: &Snippets{Get: "go get example.com/m/v2@v2.0.0"},
+		},
+		{
+			name: "stdlib",
+			path: "net/http", modulePath: stdlib.ModulePath, version: "v1.22.0", pn: "http",
+			want: &Snippets{Import: `import "net/http"`},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			um := sample.UnitMeta(test.path, test.modulePath, test.version, test.pn, true)
+			got := unitSnippets(um)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/frontend/versions"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/middleware/stats"
+)
+
+// SourceDetails contains the source files of a package, each with an index
+// of its top-level declarations.
+type SourceDetails struct {
+	// SourceURL is the URL of the package's directory in its repository, or
+	// empty if the repository is unknown.
+	SourceURL string
+
+	// GOOS and GOARCH are the build context of the files.
+	GOOS, GOARCH string
+
+	// FileGroups holds the files grouped by build constraint.
+	FileGroups []*SourceFileGroup
+}
+
+// A SourceFileGroup is a list of source files with the same build constraint.
+type SourceFileGroup struct {
+	BuildConstraint string
+	Files           []*SourceFile
+}
+
+// A SourceFile is a source file of a package, with the exported declarations
+// in it.
+type SourceFile struct {
+	Name string
+	// URL is the URL of the file in its repository, or empty if the
+	// repository is unknown.
+	URL   string
+	Decls []*SourceDecl
+}
+
+// A SourceDecl is an exported top-level declaration in a source file.
+type SourceDecl struct {
+	// Name is the name of the declaration. The names of methods are
+	// qualified by their receiver type, as in "T.M".
+	Name string
+	Kind internal.SymbolKind
+	// DocURL is the URL of the declaration's documentation on the main page
+	// of the package.
+	DocURL string
+	// SourceURL is the URL of the declaration's line in its file, or empty if
+	// the repository is unknown.
+	SourceURL string
+}
+
+// fetchSourceDetails returns the source files of the package um with their
+// declarations, for the build context bc, or defaultBC if bc is zero. The
+// declarations are read from the stored documentation, so they don't include
+// unexported ones.
+func fetchSourceDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, requestedVersion string, bc, defaultBC internal.BuildContext) (_ *SourceDetails, err error) {
+	defer derrors.Wrap(&err, "fetchSourceDetails(%q, %q, %q)", um.Path, um.ModulePath, um.Version)
+	defer stats.Elapsed(ctx, "fetchSourceDetails")()
+
+	unit, err := internal.GetUnitPreferring(ctx, ds, um, internal.WithMain, bc, defaultBC)
+	if err != nil {
+		return nil, err
+	}
+	details := &SourceDetails{
+		SourceURL: um.SourceInfo.DirectoryURL(internal.Suffix(um.Path, um.ModulePath)),
+	}
+	docs := cleanDocumentation(unit.Documentation)
+	if len(docs) == 0 {
+		return details, nil
+	}
+	doc := docs[0]
+	details.GOOS = doc.GOOS
+	details.GOARCH = doc.GOARCH
+	// Test files don't contribute to the file groups, so don't decode them.
+	docPkg, err := godoc.DecodePackageFiles(doc.Source, func(f *godoc.File) bool {
+		return !strings.HasSuffix(f.Name, "_test.go")
+	})
+	if err != nil {
+		if errors.Is(err, godoc.ErrInvalidEncodingType) {
+			// As on the main page, return a 404 so the user can reprocess
+			// the documentation.
+			log.Errorf(ctx, "fetchSourceDetails(%q, %q, %q): %v", um.Path, um.ModulePath, um.Version, err)
+			return nil, serrors.ErrUnitNotFoundWithoutFetch
+		}
+		return nil, err
+	}
+	details.FileGroups, err = sourceFileGroups(um, docPkg, versions.ConstructUnitURL(um.Path, um.ModulePath, requestedVersion))
+	if err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// sourceFileGroups returns the non-test files of docPkg, sorted by name and
+// grouped by build constraint as groupFiles does. The declarations link to
+// their documentation on the page at urlPath.
+func sourceFileGroups(um *internal.UnitMeta, docPkg *godoc.Package, urlPath string) ([]*SourceFileGroup, error) {
+	dir := internal.Suffix(um.Path, um.ModulePath)
+	fileDecls, err := docPkg.FileDecls()
+	if err != nil {
+		return nil, err
+	}
+	var groups []*SourceFileGroup
+	byConstraint := map[string]*SourceFileGroup{}
+	for _, f := range docPkg.Files {
+		name := path.Base(f.Name)
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		sf := &SourceFile{
+			Name: name,
+			URL:  um.SourceInfo.FileURL(path.Join(dir, name)),
+		}
+		for _, d := range fileDecls[name] {
+			sf.Decls = append(sf.Decls, &SourceDecl{
+				Name:      d.Name,
+				Kind:      d.Kind,
+				DocURL:    urlPath + "#" + d.Name,
+				SourceURL: um.SourceInfo.LineURL(path.Join(dir, name), d.Line),
+			})
+		}
+		g := byConstraint[f.BuildConstraint]
+		if g == nil {
+			g = &SourceFileGroup{BuildConstraint: f.BuildConstraint}
+			byConstraint[f.BuildConstraint] = g
+			groups = append(groups, g)
+		}
+		g.Files = append(g.Files, sf)
+	}
+	for _, g := range groups {
+		sort.Slice(g.Files, func(i, j int) bool { return g.Files[i].Name < g.Files[j].Name })
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].BuildConstraint < groups[j].BuildConstraint })
+	return groups, nil
+}
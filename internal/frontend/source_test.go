@@ -0,0 +1,113 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestFetchSourceDetails(t *testing.T) {
+	ctx := context.Background()
+	const src = `
+		// Package p is a package.
+		package p
+
+		// C is a constant.
+		const C = 1
+
+		// T is a type.
+		type T int
+
+		// M is a method.
+		func (T) M() {}
+
+		func unexported() {}
+	`
+	m := sample.Module(sample.ModulePath, sample.VersionString, "p")
+	pkg := m.Packages()[0]
+	pkg.Documentation = []*internal.Documentation{sample.Documentation(internal.All, internal.All, src)}
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, m)
+	um, err := fds.GetUnitMeta(ctx, pkg.Path, sample.ModulePath, sample.VersionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := fetchSourceDetails(ctx, fds, um, sample.VersionString, internal.BuildContext{}, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		repo    = "https://" + sample.ModulePath
+		docURL  = "/" + sample.ModulePath + "@" + sample.VersionString + "/p"
+		fileURL = repo + "/blob/" + sample.VersionString + "/p/sample.go"
+	)
+	want := &SourceDetails{
+		SourceURL: repo + "/tree/" + sample.VersionString + "/p",
+		GOOS:      internal.All,
+		GOARCH:    internal.All,
+		FileGroups: []*SourceFileGroup{{
+			Files: []*SourceFile{{
+				Name: "sample.go",
+				URL:  fileURL,
+				Decls: []*SourceDecl{
+					{Name: "C", Kind: internal.SymbolKindConstant, DocURL: docURL + "#C", SourceURL: fileURL + "#L6"},
+					{Name: "T", Kind: internal.SymbolKindType, DocURL: docURL + "#T", SourceURL: fileURL + "#L9"},
+					{Name: "T.M", Kind: internal.SymbolKindMethod, DocURL: docURL + "#T.M", SourceURL: fileURL + "#L12"},
+				},
+			}},
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestFetchSourceDetailsBuildContext(t *testing.T) {
+	ctx := context.Background()
+	const src = `
+		// Package p is a package.
+		package p
+	`
+	m := sample.Module(sample.ModulePath, sample.VersionString, "p")
+	pkg := m.Packages()[0]
+	pkg.Documentation = []*internal.Documentation{
+		sample.Documentation("windows", "amd64", src),
+		sample.Documentation("darwin", "amd64", src),
+	}
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, m)
+	um, err := fds.GetUnitMeta(ctx, pkg.Path, sample.ModulePath, sample.VersionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		bc, defaultBC internal.BuildContext
+		want          string
+	}{
+		{internal.BuildContext{}, internal.BuildContext{}, "windows"},
+		{internal.BuildContext{}, internal.BuildContextDarwin, "darwin"},
+		// The package has no documentation for the default.
+		{internal.BuildContext{}, internal.BuildContextJS, "windows"},
+		// The requested build context takes precedence over the default.
+		{internal.BuildContextWindows, internal.BuildContextDarwin, "windows"},
+		{internal.BuildContext{GOOS: "darwin"}, internal.BuildContext{}, "darwin"},
+	} {
+		got, err := fetchSourceDetails(ctx, fds, um, sample.VersionString, test.bc, test.defaultBC)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.GOOS != test.want {
+			t.Errorf("bc %v, default %v: got GOOS %q, want %q", test.bc, test.defaultBC, got.GOOS, test.want)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend/page"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+)
+
+// The processing states of a module version shown on the status page.
+const (
+	moduleStateQueued     = "queued"
+	moduleStateProcessing = "processing"
+	moduleStateFailed     = "failed"
+	moduleStateDone       = "done"
+)
+
+// StatusPage contains data for the module processing status page.
+type StatusPage struct {
+	page.BasePage
+	ModulePath string
+	Version    string
+	// State is one of "queued", "processing", "failed" or "done".
+	State string
+	// Reason explains a failure, or a problem with a processed module. It is
+	// written for users and never contains the error recorded by the worker.
+	Reason string
+	// BrokenDocLinks are the doc links in the documentation of a processed
+	// module that do not resolve to a symbol, for its authors to fix.
+	BrokenDocLinks []*BrokenDocLink
+}
+
+// A BrokenDocLink is a doc link in a package of a module that does not
+// resolve to a symbol.
+type BrokenDocLink struct {
+	PackagePath string
+	Text        string // text of the link, with the brackets
+	Position    string // file:line of the documented declaration, relative to the module
+	Reason      string
+}
+
+// serveModuleStatus serves the processing status of a module version, for
+// requests to /status/<module>@<version>.
+func (s *Server) serveModuleStatus(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveModuleStatus(%q)", r.URL.Path)
+
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return &serrors.ServerError{Status: http.StatusNotFound}
+	}
+	modulePath, version, ok := strings.Cut(strings.TrimPrefix(r.URL.Path, "/status/"), "@")
+	if !ok || module.Check(modulePath, version) != nil {
+		return &serrors.ServerError{
+			Status:       http.StatusBadRequest,
+			ResponseText: "The status page requires a module path and a full semantic version, as in /status/example.com/mod@v1.2.3.",
+		}
+	}
+	if err := checkModuleAvailable(r.Context(), ds, modulePath, modulePath, version); err != nil {
+		return err
+	}
+	var state, reason string
+	mvs, err := db.GetModuleVersionState(r.Context(), modulePath, version)
+	switch {
+	case err == nil:
+		state, reason = moduleProcessingState(mvs)
+	case errors.Is(err, derrors.NotFound):
+		// A version requested through frontend fetch has no state until its
+		// first fetch finishes, but a worker claims it while fetching it.
+		claimed, err := db.IsFetchClaimed(r.Context(), modulePath, version)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return &serrors.ServerError{
+				Status:       http.StatusNotFound,
+				ResponseText: fmt.Sprintf("%s@%s has not been requested.", modulePath, version),
+			}
+		}
+		state = moduleStateQueued
+	default:
+		return err
+	}
+	var brokenLinks []*BrokenDocLink
+	if state == moduleStateDone {
+		links, err := db.GetBrokenDocLinks(r.Context(), modulePath, version)
+		if err != nil {
+			return err
+		}
+		brokenLinks = brokenDocLinks(modulePath, links)
+	}
+	// The state changes as the module is processed, so don't let browsers or
+	// proxies cache it.
+	w.Header().Set("Cache-Control", "no-store")
+	s.servePage(r.Context(), w, "status", StatusPage{
+		BasePage:       s.newBasePage(r, fmt.Sprintf("Status of %s@%s", modulePath, version)),
+		ModulePath:     modulePath,
+		Version:        version,
+		State:          state,
+		Reason:         reason,
+		BrokenDocLinks: brokenLinks,
+	})
+	return nil
+}
+
+// brokenDocLinks returns the broken doc links of the packages of a module,
+// given by package path, sorted by package.
+func brokenDocLinks(modulePath string, links map[string][]*internal.BrokenDocLink) []*BrokenDocLink {
+	var paths []string
+	for p := range links {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	var bls []*BrokenDocLink
+	for _, p := range paths {
+		for _, l := range links[p] {
+			bls = append(bls, &BrokenDocLink{
+				PackagePath: p,
+				Text:        "[" + l.Text + "]",
+				Position:    fmt.Sprintf("%s:%d", path.Join(internal.Suffix(p, modulePath), l.File), l.Line),
+				Reason:      l.Reason,
+			})
+		}
+	}
+	return bls
+}
+
+// moduleProcessingState returns the state of the module version described by
+// mvs, and a reason suitable for users if there was a problem.
+func moduleProcessingState(mvs *internal.ModuleVersionState) (state, reason string) {
+	switch mvs.Status {
+	case 0:
+		// Versions from the index are inserted with a timestamp and wait to
+		// be fetched. Versions requested through frontend fetch have no
+		// timestamp, and are inserted once the worker has started on them.
+		if mvs.IndexTimestamp == nil {
+			return moduleStateProcessing, ""
+		}
+		return moduleStateQueued, ""
+	case http.StatusOK:
+		return moduleStateDone, ""
+	case derrors.ToStatus(derrors.HasIncompletePackages):
+		return moduleStateDone, "Some packages in the module could not be processed."
+	case derrors.ToStatus(derrors.ReprocessStatusOK),
+		derrors.ToStatus(derrors.ReprocessHasIncompletePackages),
+		derrors.ToStatus(derrors.ReprocessBadModule),
+		derrors.ToStatus(derrors.ReprocessAlternative),
+		derrors.ToStatus(derrors.ReprocessDBModuleInsertInvalid):
+		return moduleStateQueued, ""
+	case http.StatusBadRequest:
+		return moduleStateFailed, "The module path or version is invalid."
+	case http.StatusForbidden:
+		return moduleStateFailed, "The module version is excluded from this site."
+	case http.StatusNotFound:
+		return moduleStateFailed, "The module version could not be found on the module proxy."
+	case derrors.ToStatus(derrors.DBModuleInsertInvalid):
+		return moduleStateFailed, "The module version contains data that could not be stored."
+	case derrors.ToStatus(derrors.BadModule):
+		return moduleStateFailed, "The module version could not be processed. It may contain no packages, or its zip file may be invalid."
+	case derrors.ToStatus(derrors.AlternativeModule):
+		if mvs.GoModPath != "" && module.CheckPath(mvs.GoModPath) == nil {
+			return moduleStateFailed, fmt.Sprintf("The module's go.mod file declares a different module path, %s.", mvs.GoModPath)
+		}
+		return moduleStateFailed, "The module's go.mod file declares a different module path."
+	case derrors.ToStatus(derrors.ModuleTooLarge):
+		return moduleStateFailed, "The module version is too large to process."
+	case derrors.ToStatus(derrors.Cleaned):
+		return moduleStateFailed, "The module version was removed from this site."
+	default:
+		if mvs.MaxRetryAttempts > 0 && mvs.RetryAttempts >= mvs.MaxRetryAttempts {
+			return moduleStateFailed, fmt.Sprintf("Processing the module version failed %d times in a row, so it will not be retried automatically.", mvs.RetryAttempts)
+		}
+		return moduleStateFailed, "Something went wrong while processing the module version. It will be retried automatically."
+	}
+}
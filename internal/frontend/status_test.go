@@ -0,0 +1,158 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestModuleProcessingState(t *testing.T) {
+	now := time.Now()
+	for _, test := range []struct {
+		name       string
+		mvs        internal.ModuleVersionState
+		wantState  string
+		wantReason bool
+	}{
+		{"from index", internal.ModuleVersionState{IndexTimestamp: &now}, moduleStateQueued, false},
+		{"from fetch", internal.ModuleVersionState{}, moduleStateProcessing, false},
+		{"ok", internal.ModuleVersionState{Status: http.StatusOK}, moduleStateDone, false},
+		{"incomplete", internal.ModuleVersionState{Status: derrors.ToStatus(derrors.HasIncompletePackages)}, moduleStateDone, true},
+		{"reprocess", internal.ModuleVersionState{Status: derrors.ToStatus(derrors.ReprocessBadModule)}, moduleStateQueued, false},
+		{"not found", internal.ModuleVersionState{Status: http.StatusNotFound}, moduleStateFailed, true},
+		{"proxy error", internal.ModuleVersionState{Status: derrors.ToStatus(derrors.ProxyError)}, moduleStateFailed, true},
+		{"retries exhausted", internal.ModuleVersionState{Status: http.StatusInternalServerError, RetryAttempts: 3, MaxRetryAttempts: 3}, moduleStateFailed, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			state, reason := moduleProcessingState(&test.mvs)
+			if state != test.wantState || (reason != "") != test.wantReason {
+				t.Errorf("got (%q, %q), want state %q and reason: %t", state, reason, test.wantState, test.wantReason)
+			}
+		})
+	}
+}
+
+func TestServeModuleStatus(t *testing.T) {
+	fds := fakedatasource.New()
+	fds.InsertModuleVersionState(&internal.ModuleVersionState{
+		ModulePath: "example.com/done",
+		Version:    "v1.0.0",
+		Status:     http.StatusOK,
+	})
+	m := sample.Module("example.com/done", "v1.0.0", "a")
+	for _, u := range m.Units {
+		if u.Path == "example.com/done/a" {
+			u.Documentation[0].BrokenDocLinks = []*internal.BrokenDocLink{{
+				Text:       "Missing",
+				ImportPath: "example.com/done/a",
+				Name:       "Missing",
+				File:       "a.go",
+				Line:       5,
+				Reason:     "The package has no symbol Missing.",
+			}}
+		}
+	}
+	fds.MustInsertModule(context.Background(), m)
+	fds.InsertModuleVersionState(&internal.ModuleVersionState{
+		ModulePath: "example.com/failed",
+		Version:    "v1.0.0",
+		Status:     http.StatusInternalServerError,
+		Error:      "pq: connection to 10.0.0.1 refused",
+	})
+	fds.InsertModuleVersionState(&internal.ModuleVersionState{
+		ModulePath: "example.com/processing",
+		Version:    "v1.0.0",
+	})
+	fds.InsertFetchClaim("example.com/fetching", "v1.0.0")
+	fds.InsertModuleVersionState(&internal.ModuleVersionState{
+		ModulePath: "example.com/takendown",
+		Version:    "v1.0.0",
+		Status:     http.StatusOK,
+	})
+	fds.AddTakedown(&internal.Takedown{ModulePath: "example.com/takendown", Version: "v1.0.0"})
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	for _, test := range []struct {
+		path       string
+		wantStatus int
+		want       []string
+		notWant    []string
+	}{
+		{
+			path:       "/status/example.com/done@v1.0.0",
+			wantStatus: http.StatusOK,
+			want: []string{
+				"Done.",
+				`href="/example.com/done@v1.0.0"`,
+				"<code>[Missing]</code> in example.com/done/a at a/a.go:5: The package has no symbol Missing.",
+			},
+			notWant: []string{`http-equiv="refresh"`},
+		},
+		{
+			path:       "/status/example.com/failed@v1.0.0",
+			wantStatus: http.StatusOK,
+			want:       []string{"Failed.", "It will be retried automatically."},
+			notWant:    []string{"10.0.0.1", "Broken doc links"},
+		},
+		{
+			path:       "/status/example.com/processing@v1.0.0",
+			wantStatus: http.StatusOK,
+			want:       []string{"Processing.", `http-equiv="refresh"`},
+		},
+		{
+			path:       "/status/example.com/fetching@v1.0.0",
+			wantStatus: http.StatusOK,
+			want:       []string{"Queued.", `http-equiv="refresh"`},
+		},
+		{
+			path:       "/status/example.com/takendown@v1.0.0",
+			wantStatus: http.StatusUnavailableForLegalReasons,
+			notWant:    []string{"Done."},
+		},
+		{
+			path:       "/status/example.com/unknown@v1.0.0",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			path:       "/status/example.com/done@latest",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			path:       "/status/example.com/done",
+			wantStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if w.Code != test.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, test.wantStatus)
+			}
+			body := w.Body.String()
+			for _, want := range test.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+			for _, notWant := range test.notWant {
+				if strings.Contains(body, notWant) {
+					t.Errorf("body contains %q", notWant)
+				}
+			}
+		})
+	}
+}
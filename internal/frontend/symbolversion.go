@@ -0,0 +1,115 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/cookie"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/frontend/versions"
+)
+
+// symbolNameRx matches the names of symbols, which are also their anchors in
+// the documentation, like "F", "T" and "T.M".
+var symbolNameRx = regexp.MustCompile(`^[\pL_][\pL\pN_]*(\.[\pL_][\pL\pN_]*)?$`)
+
+// symbolVersion is the response to a request to /symbol-version.
+type symbolVersion struct {
+	// URL is the link to the symbol at Version.
+	URL     string `json:"url"`
+	Version string `json:"version"`
+}
+
+// serveSymbolVersion finds the version nearest to the "version" query param
+// whose package at the "path" query param, in the module at the "module"
+// query param, has the symbol named in the "symbol" query param. The unit
+// page requests it when the symbol in its URL fragment is not in the
+// documentation, as happens with links to symbols that were renamed or
+// removed, and follows the returned link.
+//
+// The response is a JSON symbolVersion, or a 404 if no version of the
+// package other than the requested one has the symbol. It sets a cookie that
+// makes the page at the returned link explain the redirect.
+func (s *Server) serveSymbolVersion(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveSymbolVersion(%q)", r.URL.RawQuery)
+
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return &serrors.ServerError{Status: http.StatusNotFound}
+	}
+	pkgPath, modulePath, version, name := r.FormValue("path"), r.FormValue("module"), r.FormValue("version"), r.FormValue("symbol")
+	if pkgPath == "" || modulePath == "" || version == "" || !symbolNameRx.MatchString(name) {
+		return &serrors.ServerError{Status: http.StatusBadRequest}
+	}
+	ctx := r.Context()
+	um, err := ds.GetUnitMeta(ctx, pkgPath, modulePath, version)
+	if err != nil {
+		return err
+	}
+	if !um.IsPackage() {
+		return &serrors.ServerError{Status: http.StatusNotFound}
+	}
+	if err := checkModuleAvailable(ctx, ds, um.Path, um.ModulePath, um.Version); err != nil {
+		return err
+	}
+	v, link, err := versions.NearestSymbolVersion(ctx, db, um, name)
+	if err != nil {
+		return err
+	}
+	if v == "" {
+		return &serrors.ServerError{Status: http.StatusNotFound}
+	}
+	data, err := json.Marshal(symbolVersion{
+		URL:     link,
+		Version: versions.LinkVersion(um.ModulePath, v, v),
+	})
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %v", err)
+	}
+	// The cookie is for the page of the link, not counting its query and
+	// fragment.
+	linkPath, _, _ := strings.Cut(link, "?")
+	linkPath, _, _ = strings.Cut(linkPath, "#")
+	cookie.Set(w, cookie.SymbolVersionFlash, name+"@"+um.Version, linkPath)
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("w.Write: %v", err)
+	}
+	return nil
+}
+
+// SymbolRedirect describes a redirect to the documentation of a symbol at a
+// different version than the one requested, because the symbol is not in the
+// requested version.
+type SymbolRedirect struct {
+	Symbol string
+
+	// FromVersion is the requested version, as displayed, and FromURL is
+	// the URL of the package at that version.
+	FromVersion string
+	FromURL     string
+}
+
+// symbolRedirect returns the SymbolRedirect in the flash cookie value val,
+// which is in the form set by serveSymbolVersion, for the page of um. It
+// returns nil if val is empty or malformed.
+func symbolRedirect(val string, um *internal.UnitMeta) *SymbolRedirect {
+	name, version, ok := strings.Cut(val, "@")
+	if !ok || !symbolNameRx.MatchString(name) || version == "" {
+		return nil
+	}
+	return &SymbolRedirect{
+		Symbol:      name,
+		FromVersion: versions.LinkVersion(um.ModulePath, version, version),
+		FromURL:     versions.ConstructUnitURL(um.Path, um.ModulePath, version),
+	}
+}
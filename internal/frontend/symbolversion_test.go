@@ -0,0 +1,96 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestServeSymbolVersion(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	for _, v := range []string{"v1.0.0", "v1.1.0"} {
+		m := sample.Module("example.com/mod", v, "pkg")
+		d := sample.Documentation(internal.All, internal.All, sample.DocContents)
+		d.API = []*internal.Symbol{sample.Constant}
+		if v == "v1.0.0" {
+			d.API = append(d.API, sample.Function)
+		}
+		m.Packages()[0].Documentation = []*internal.Documentation{d}
+		fds.MustInsertModule(ctx, m)
+	}
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	get := func(target string, cookies ...*http.Cookie) *http.Response {
+		r := httptest.NewRequest("GET", target, nil)
+		for _, c := range cookies {
+			r.AddCookie(c)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w.Result()
+	}
+	query := func(version, symbol string) string {
+		return "/symbol-version?" + url.Values{
+			"path":    {"example.com/mod/pkg"},
+			"module":  {"example.com/mod"},
+			"version": {version},
+			"symbol":  {symbol},
+		}.Encode()
+	}
+
+	for _, test := range []struct {
+		target     string
+		wantStatus int
+	}{
+		{query("v1.0.0", sample.Function.Name), http.StatusNotFound},
+		{query("v1.1.0", "Missing"), http.StatusNotFound},
+		{query("v1.1.0", "section-readme"), http.StatusBadRequest},
+	} {
+		if res := get(test.target); res.StatusCode != test.wantStatus {
+			t.Errorf("%s: status = %d, want %d", test.target, res.StatusCode, test.wantStatus)
+		}
+	}
+
+	res := get(query("v1.1.0", sample.Function.Name))
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", res.StatusCode)
+	}
+	var got symbolVersion
+	if err := json.NewDecoder(res.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	want := symbolVersion{URL: "/example.com/mod@v1.0.0/pkg#Function", Version: "v1.0.0"}
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	cookies := res.Cookies()
+	if len(cookies) != 1 || cookies[0].Path != "/example.com/mod@v1.0.0/pkg" {
+		t.Fatalf("got cookies %v, want one for the page of the link", cookies)
+	}
+
+	// The page of the link explains the redirect.
+	b, err := io.ReadAll(get("/example.com/mod@v1.0.0/pkg", cookies...).Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `Function is not in version
+      <a href="/example.com/mod@v1.1.0/pkg"`; !strings.Contains(string(b), want) {
+		t.Errorf("page does not contain %q", want)
+	}
+}
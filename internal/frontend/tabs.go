@@ -11,6 +11,7 @@ import (
 
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docrender"
 	"golang.org/x/pkgsite/internal/frontend/versions"
 	"golang.org/x/pkgsite/internal/vuln"
 )
@@ -41,6 +42,11 @@ const (
 	tabImports    = "imports"
 	tabImportedBy = "importedby"
 	tabLicenses   = "licenses"
+	tabSource     = "source"
+	tabDocs       = "docs"
+	tabDiff       = "diff"
+	tabHistory    = "history"
+	tabAnalysis   = "analysis"
 )
 
 var (
@@ -65,6 +71,34 @@ var (
 			Name:         tabLicenses,
 			TemplateName: "unit/licenses",
 		},
+		{
+			Name:         tabSource,
+			TemplateName: "unit/source",
+		},
+		{
+			// The docs tab is reached from the links on the main page, and
+			// has no link in the unit header.
+			Name:         tabDocs,
+			TemplateName: "unit/docs",
+		},
+		{
+			// The diff tab is reached from the versions tab, and has no link in
+			// the unit header.
+			Name:         tabDiff,
+			TemplateName: "unit/diff",
+		},
+		{
+			// The history tab is reached from the symbols on the versions
+			// tab, and has no link in the unit header.
+			Name:         tabHistory,
+			TemplateName: "unit/history",
+		},
+		{
+			// The analysis tab is reached from the links on the main page,
+			// and has no link in the unit header.
+			Name:         tabAnalysis,
+			TemplateName: "unit/analysis",
+		},
 	}
 	unitTabLookup = make(map[string]TabSettings, len(unitTabs))
 )
@@ -78,21 +112,57 @@ func init() {
 // fetchDetailsForUnit returns tab details by delegating to the correct detail
 // handler.
 func fetchDetailsForUnit(ctx context.Context, r *http.Request, tab string, ds internal.DataSource, um *internal.UnitMeta,
-	requestedVersion string, bc internal.BuildContext,
-	vc *vuln.Client) (_ any, err error) {
+	requestedVersion string, bc, defaultBC internal.BuildContext,
+	vc *vuln.Client, rd docrender.Renderer) (_ any, err error) {
 	defer derrors.Wrap(&err, "fetchDetailsForUnit(r, %q, ds, um=%q,%q,%q)", tab, um.Path, um.ModulePath, um.Version)
 	switch tab {
 	case tabMain:
 		_, expandReadme := r.URL.Query()["readme"]
-		return fetchMainDetails(ctx, ds, um, requestedVersion, expandReadme, bc)
+		// Expanding examples is done in the browser, but the examples
+		// query parameter does it for browsers without JavaScript.
+		_, expandExamples := r.URL.Query()["examples"]
+		return fetchMainDetails(ctx, ds, um, requestedVersion, expandReadme, expandExamples,
+			r.FormValue(readmeLangParam), r.Header.Get("Accept-Language"), bc, defaultBC, rd)
 	case tabVersions:
-		return versions.FetchVersionsDetails(ctx, ds, um, vc)
+		var switchLink func(path, modulePath, version string) string
+		if vs, ok := versionSwitchFromRequest(r); ok {
+			switchLink = func(path, modulePath, version string) string {
+				return versionSwitchURL(path, modulePath, version, vs)
+			}
+		}
+		f, err := versions.ParseFilter(r.URL.Query())
+		if err != nil {
+			return nil, err
+		}
+		return versions.FetchVersionsDetails(ctx, ds, um, f, vc, switchLink)
 	case tabImports:
 		return fetchImportsDetails(ctx, ds, um.Path, um.ModulePath, um.Version)
 	case tabImportedBy:
 		return fetchImportedByDetails(ctx, ds, um.Path, um.ModulePath)
 	case tabLicenses:
 		return fetchLicensesDetails(ctx, ds, um)
+	case tabSource:
+		if !um.IsPackage() {
+			// Rejected by isValidTabForUnit.
+			return nil, nil
+		}
+		return fetchSourceDetails(ctx, ds, um, requestedVersion, bc, defaultBC)
+	case tabDocs:
+		return fetchDocsDetails(ctx, ds, rd, um, r.FormValue(docsPageParam))
+	case tabDiff:
+		if !um.IsPackage() || um.IsCommand() {
+			// Rejected by isValidTabForUnit.
+			return nil, nil
+		}
+		return versions.FetchDiffDetails(ctx, ds, um, r.FormValue("from"), r.FormValue("to"))
+	case tabHistory:
+		if !um.IsPackage() || um.IsCommand() {
+			// Rejected by isValidTabForUnit.
+			return nil, nil
+		}
+		return versions.FetchSymbolHistoryDetails(ctx, ds, um, r.FormValue("symbol"), bc, defaultBC)
+	case tabAnalysis:
+		return fetchAnalysisDetails(ctx, ds, um)
 	}
 	return nil, fmt.Errorf("BUG: unable to fetch details: unknown tab %q", tab)
 }
@@ -0,0 +1,131 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package templates
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/template/parse"
+
+	"github.com/google/safehtml/template"
+)
+
+// Overrides is a directory of templates that shadow the page templates of
+// the same path, so that a deployment can change parts of the site, such as
+// the header, footer or homepage, without modifying the static directory.
+//
+// Paths in the directory are relative to the static directory: for example,
+// the file frontend/homepage/homepage.tmpl replaces the homepage template.
+// Each overriding file must define every template that the file it replaces
+// defines; Validate checks this.
+type Overrides struct {
+	dir     string
+	trusted template.TrustedFS
+	fsys    fs.FS
+}
+
+// NewOverrides returns the Overrides for the templates in dir.
+func NewOverrides(dir template.TrustedSource) *Overrides {
+	return &Overrides{
+		dir:     dir.String(),
+		trusted: template.TrustedFSFromTrustedSource(dir),
+		fsys:    os.DirFS(dir.String()),
+	}
+}
+
+// parse parses the templates in o that match pattern into t, after those of
+// the static directory, so that their definitions take precedence.
+func (o *Overrides) parse(t *template.Template, pattern string) error {
+	if o == nil {
+		return nil
+	}
+	files, err := fs.Glob(o.fsys, pattern)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return nil
+	}
+	if _, err := t.ParseFS(o.trusted, files...); err != nil {
+		return fmt.Errorf("ParseFS(%q): %v", path.Join(o.dir, pattern), err)
+	}
+	return nil
+}
+
+// Validate checks the templates in o against the templates in staticFS,
+// the static directory. It returns an error if a template in o does not
+// replace one in staticFS, or does not define a template that the one it
+// replaces defines.
+func (o *Overrides) Validate(staticFS fs.FS) error {
+	var errs []string
+	err := fs.WalkDir(o.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(p) != ".tmpl" {
+			return nil
+		}
+		want, err := definedTemplates(staticFS, p)
+		if err != nil {
+			if os.IsNotExist(err) {
+				errs = append(errs, fmt.Sprintf("%s: no such template in the static directory", p))
+				return nil
+			}
+			return err
+		}
+		got, err := definedTemplates(o.fsys, p)
+		if err != nil {
+			return err
+		}
+		var missing []string
+		for name := range want {
+			if !got[name] {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			errs = append(errs, fmt.Sprintf("%s: missing definitions of %s", p, strings.Join(missing, ", ")))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("template overrides %s: %v", o.dir, err)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("template overrides %s:\n%s", o.dir, strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+// definedTemplates returns the names of the templates defined in the file
+// name of fsys, either with define or block. The template named after the
+// file itself is included only if it has content.
+func definedTemplates(fsys fs.FS, name string) (map[string]bool, error) {
+	b, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	base := path.Base(name)
+	tree := parse.New(base)
+	// Functions are checked when the templates are parsed for serving.
+	tree.Mode = parse.SkipFuncCheck
+	trees := map[string]*parse.Tree{}
+	if _, err := tree.Parse(string(b), "", "", trees); err != nil {
+		return nil, err
+	}
+	names := map[string]bool{}
+	for n, t := range trees {
+		if n == base && parse.IsEmptyTree(t.Root) {
+			continue
+		}
+		names[n] = true
+	}
+	return names, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package templates
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/safehtml/template"
+)
+
+func TestParsePageTemplatesOverrides(t *testing.T) {
+	staticFS := template.TrustedFSFromTrustedSource(template.TrustedSourceFromConstant("../../../static"))
+	overrides := NewOverrides(template.TrustedSourceFromConstant("testdata/overrides"))
+	if err := overrides.Validate(os.DirFS("../../../static")); err != nil {
+		t.Fatal(err)
+	}
+	ts, err := ParsePageTemplates(staticFS, overrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := ts["homepage"].ExecuteTemplate(&buf, "main", nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "<h1>Example Corp Packages</h1>"; got != want {
+		t.Errorf("homepage main: got %q, want %q", got, want)
+	}
+	// Other pages are unaffected.
+	if ts["about"].Lookup("main") == nil {
+		t.Error("about: main is not defined")
+	}
+}
+
+func TestValidateOverrides(t *testing.T) {
+	staticFS := fstest.MapFS{
+		"frontend/frontend.tmpl":          {Data: []byte(`<html>{{block "title" .}}{{end}}{{block "main" .}}{{end}}</html>`)},
+		"frontend/homepage/homepage.tmpl": {Data: []byte(`{{define "main"}}home{{end}}{{define "pre-footer"}}{{end}}`)},
+	}
+	for _, test := range []struct {
+		name  string
+		files fstest.MapFS
+		want  []string // substrings of the error; none if empty
+	}{
+		{
+			name: "valid",
+			files: fstest.MapFS{
+				"frontend/homepage/homepage.tmpl": {Data: []byte(`{{define "main"}}{{f .}}{{end}}{{define "pre-footer"}}x{{end}}`)},
+				"frontend/homepage/README.md":     {Data: []byte("not a template")},
+			},
+		},
+		{
+			name: "missing block",
+			files: fstest.MapFS{
+				"frontend/frontend.tmpl": {Data: []byte(`<html>{{block "main" .}}{{end}}</html>`)},
+			},
+			want: []string{"frontend/frontend.tmpl: missing definitions of title"},
+		},
+		{
+			name: "missing page",
+			files: fstest.MapFS{
+				"frontend/frontend.tmpl": {Data: []byte(`{{define "title"}}{{end}}{{define "main"}}{{end}}`)},
+			},
+			want: []string{"frontend/frontend.tmpl: missing definitions of frontend.tmpl"},
+		},
+		{
+			name: "unknown file",
+			files: fstest.MapFS{
+				"frontend/homepage/home.tmpl": {Data: []byte(`{{define "main"}}{{end}}`)},
+			},
+			want: []string{"frontend/homepage/home.tmpl: no such template"},
+		},
+		{
+			name: "syntax error",
+			files: fstest.MapFS{
+				"frontend/homepage/homepage.tmpl": {Data: []byte(`{{define "main"}}`)},
+			},
+			want: []string{"homepage.tmpl"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			o := &Overrides{dir: "overrides", fsys: test.files}
+			err := o.Validate(staticFS)
+			if len(test.want) == 0 {
+				if err != nil {
+					t.Fatalf("got %v, want nil", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("got nil, want error")
+			}
+			for _, w := range test.want {
+				if !strings.Contains(err.Error(), w) {
+					t.Errorf("got %q, want it to contain %q", err, w)
+				}
+			}
+		})
+	}
+}
@@ -47,22 +47,32 @@ func stripScheme(url string) string {
 //
 // Templates in directories prefixed with an underscore are considered helper
 // templates and parsed together with the files in each base directory.
-func ParsePageTemplates(fsys template.TrustedFS) (map[string]*template.Template, error) {
+//
+// If overrides is non-nil, its templates shadow those of fsys.
+func ParsePageTemplates(fsys template.TrustedFS, overrides *Overrides) (map[string]*template.Template, error) {
 	templates := make(map[string]*template.Template)
 	htmlSets := [][]string{
 		{"about"},
 		{"badge"},
+		{"claim"},
 		{"error"},
 		{"fetch"},
 		{"homepage"},
 		{"license-policy"},
+		{"report-doc-issue"},
 		{"search"},
 		{"search-help"},
+		{"status"},
 		{"subrepo"},
+		{"unit/analysis", "unit"},
+		{"unit/diff", "unit"},
+		{"unit/docs", "unit"},
+		{"unit/history", "unit"},
 		{"unit/importedby", "unit"},
 		{"unit/imports", "unit"},
 		{"unit/licenses", "unit"},
 		{"unit/main", "unit"},
+		{"unit/source", "unit"},
 		{"unit/versions", "unit"},
 		{"vuln"},
 		{"vuln/main", "vuln"},
@@ -71,18 +81,29 @@ func ParsePageTemplates(fsys template.TrustedFS) (map[string]*template.Template,
 	}
 
 	for _, set := range htmlSets {
-		t, err := template.New("frontend.tmpl").Funcs(templateFuncs).ParseFS(fsys, "frontend/*.tmpl")
+		baseGlob := "frontend/*.tmpl"
+		t, err := template.New("frontend.tmpl").Funcs(templateFuncs).ParseFS(fsys, baseGlob)
 		if err != nil {
 			return nil, fmt.Errorf("ParseFS: %v", err)
 		}
+		if err := overrides.parse(t, baseGlob); err != nil {
+			return nil, err
+		}
 		helperGlob := "shared/*/*.tmpl"
 		if _, err := t.ParseFS(fsys, helperGlob); err != nil {
 			return nil, fmt.Errorf("ParseFS(%q): %v", helperGlob, err)
 		}
+		if err := overrides.parse(t, helperGlob); err != nil {
+			return nil, err
+		}
 		for _, f := range set {
-			if _, err := t.ParseFS(fsys, path.Join("frontend", f, "*.tmpl")); err != nil {
+			glob := path.Join("frontend", f, "*.tmpl")
+			if _, err := t.ParseFS(fsys, glob); err != nil {
 				return nil, fmt.Errorf("ParseFS(%v): %v", f, err)
 			}
+			if err := overrides.parse(t, glob); err != nil {
+				return nil, err
+			}
 		}
 		templates[set[0]] = t
 	}
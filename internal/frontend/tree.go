@@ -0,0 +1,245 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/frontend/urlinfo"
+	"golang.org/x/pkgsite/internal/frontend/versions"
+	"golang.org/x/pkgsite/internal/stdlib"
+)
+
+// packageTree is the response to a request to /tree/.
+type packageTree struct {
+	ModulePath string `json:"modulePath"`
+	Version    string `json:"version"`
+	// Breadcrumb links to the directories from the module root, or from the
+	// standard library, down to Root.
+	Breadcrumb []*treeLink `json:"breadcrumb"`
+	Root       *treeNode   `json:"root"`
+}
+
+type treeLink struct {
+	Text string `json:"text"`
+	URL  string `json:"url,omitempty"`
+}
+
+// A treeNode is a package, a directory or a nested module in a packageTree.
+type treeNode struct {
+	Path string `json:"path"`
+	// Name is the name of the package at Path, or empty if there is none.
+	Name       string `json:"name,omitempty"`
+	Synopsis   string `json:"synopsis,omitempty"`
+	URL        string `json:"url"`
+	IsModule   bool   `json:"isModule,omitempty"`
+	IsInternal bool   `json:"isInternal,omitempty"`
+	// NumPackages is the number of packages below the node, whether or not
+	// they are in Children. A node with packages but no children was cut off
+	// by the depth of the request, and its own tree can be requested next.
+	NumPackages int         `json:"numPackages"`
+	Children    []*treeNode `json:"children,omitempty"`
+}
+
+// serveTree serves the tree of the packages below a unit as a JSON
+// packageTree, for requests to /tree/<path>[@<version>], where the path and
+// version are as in the URL of the unit page. It lets clients like IDEs and
+// the directories section of the unit page load the packages of large
+// modules lazily.
+//
+// The depth query param limits how many levels of the tree are included; by
+// default, all are. The q query param keeps only the packages and nested
+// modules whose paths below the unit or synopses contain it, ignoring case,
+// along with the directories above them.
+func (s *Server) serveTree(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveTree(%q)", r.URL.Path)
+
+	info, err := urlinfo.ExtractURLPathInfo(strings.TrimPrefix(r.URL.Path, "/tree"))
+	if err != nil {
+		return &serrors.ServerError{
+			Status:       http.StatusBadRequest,
+			ResponseText: "A package tree requires the path of a module or directory, and optionally its version, as in /tree/example.com/mod@v1.2.3.",
+		}
+	}
+	depth := 0
+	if d := r.FormValue("depth"); d != "" {
+		depth, err = strconv.Atoi(d)
+		if err != nil || depth < 1 {
+			return &serrors.ServerError{
+				Status:       http.StatusBadRequest,
+				ResponseText: "The depth must be a positive number.",
+			}
+		}
+	}
+	ctx := r.Context()
+	um, err := ds.GetUnitMeta(ctx, info.FullPath, info.ModulePath, info.RequestedVersion)
+	if err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			return &serrors.ServerError{Status: http.StatusNotFound}
+		}
+		return err
+	}
+	if err := checkModuleAvailable(ctx, ds, um.Path, um.ModulePath, um.Version); err != nil {
+		return err
+	}
+	tree, err := buildPackageTree(ctx, ds, um, info.RequestedVersion, strings.ToLower(r.FormValue("q")))
+	if err != nil {
+		return err
+	}
+	pruneTree(tree.Root, depth)
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %v", err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("w.Write: %v", err)
+	}
+	return nil
+}
+
+// buildPackageTree returns the tree of the packages and nested modules below
+// the unit of um, keeping only those that match q if it is not empty.
+func buildPackageTree(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, requestedVersion, q string) (*packageTree, error) {
+	u, err := ds.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+	if err != nil {
+		return nil, err
+	}
+	linkVersion := versions.LinkVersion(um.ModulePath, requestedVersion, um.Version)
+	unitURL := func(p string) string {
+		return versions.ConstructUnitURL(p, um.ModulePath, linkVersion)
+	}
+	tree := &packageTree{
+		ModulePath: um.ModulePath,
+		Version:    um.Version,
+		Root: &treeNode{
+			Path:       um.Path,
+			URL:        unitURL(um.Path),
+			IsInternal: isInternalPath(internal.Suffix(um.Path, um.ModulePath)),
+		},
+	}
+	bc := breadcrumbPath(um.Path, um.ModulePath, requestedVersion)
+	for _, l := range bc.Links {
+		tree.Breadcrumb = append(tree.Breadcrumb, &treeLink{Text: l.Body, URL: l.Href})
+	}
+	tree.Breadcrumb = append(tree.Breadcrumb, &treeLink{Text: bc.Current, URL: tree.Root.URL})
+
+	matches := func(suffix, synopsis string) bool {
+		return q == "" ||
+			strings.Contains(strings.ToLower(suffix), q) ||
+			strings.Contains(strings.ToLower(synopsis), q)
+	}
+	nodes := map[string]*treeNode{um.Path: tree.Root}
+	// node returns the node for p, which is below the unit, creating it and
+	// the directories above it as needed.
+	var node func(p string) *treeNode
+	node = func(p string) *treeNode {
+		if n := nodes[p]; n != nil {
+			return n
+		}
+		n := &treeNode{
+			Path:       p,
+			URL:        unitURL(p),
+			IsInternal: isInternalPath(internal.Suffix(p, um.ModulePath)),
+		}
+		nodes[p] = n
+		// In the standard library, top-level directories have no parent path.
+		parent := path.Dir(p)
+		if parent == "." {
+			parent = um.Path
+		}
+		pn := node(parent)
+		pn.Children = append(pn.Children, n)
+		return n
+	}
+	below := func(p string) bool {
+		if um.Path == stdlib.ModulePath {
+			return p != um.Path
+		}
+		return strings.HasPrefix(p, um.Path+"/")
+	}
+	for _, pm := range u.Subdirectories {
+		if pm.Path != um.Path && (!below(pm.Path) || !matches(internal.Suffix(pm.Path, um.Path), pm.Synopsis)) {
+			continue
+		}
+		n := node(pm.Path)
+		n.Name = pm.Name
+		n.Synopsis = pm.Synopsis
+	}
+	if um.ModulePath != stdlib.ModulePath {
+		mods, err := getNestedModules(ctx, ds, um, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range mods {
+			p := um.Path + "/" + m.Suffix
+			if nodes[p] != nil || !matches(m.Suffix, "") {
+				continue
+			}
+			n := node(p)
+			n.URL = m.URL
+			n.IsModule = true
+		}
+	}
+	countAndSort(tree.Root)
+	return tree, nil
+}
+
+// countAndSort sets the number of packages below n and the nodes below it,
+// and sorts their children by path. Nested modules are not counted.
+func countAndSort(n *treeNode) {
+	n.NumPackages = 0
+	for _, c := range n.Children {
+		countAndSort(c)
+		n.NumPackages += c.NumPackages
+		if c.Name != "" {
+			n.NumPackages++
+		}
+	}
+	sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Path < n.Children[j].Path })
+}
+
+// pruneTree removes the nodes more than depth levels below n, if depth is
+// positive.
+func pruneTree(n *treeNode, depth int) {
+	if depth <= 0 {
+		return
+	}
+	if depth == 1 {
+		for _, c := range n.Children {
+			c.Children = nil
+		}
+		return
+	}
+	for _, c := range n.Children {
+		pruneTree(c, depth-1)
+	}
+}
+
+// isInternalPath reports whether the path suffix, relative to its module,
+// has an internal element.
+func isInternalPath(suffix string) bool {
+	return suffix == "internal" ||
+		strings.HasPrefix(suffix, "internal/") ||
+		strings.HasSuffix(suffix, "/internal") ||
+		strings.Contains(suffix, "/internal/")
+}
+
+// treeTTL assigns the cache TTL for requests to /tree/.
+func treeTTL(r *http.Request) time.Duration {
+	return detailsTTLForPath(r.Context(), strings.TrimPrefix(r.URL.Path, "/tree"), "")
+}
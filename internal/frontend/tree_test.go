@@ -0,0 +1,151 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestServeTree(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, sample.Module("example.com/mod", "v1.2.0", "a", "a/b", "d/e", "internal/c"))
+	fds.MustInsertModule(ctx, sample.Module("example.com/mod/nested", "v1.0.0", "n"))
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	get := func(target string) (int, *packageTree) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+		var tree packageTree
+		if err := json.Unmarshal(w.Body.Bytes(), &tree); err != nil {
+			t.Fatal(err)
+		}
+		return w.Code, &tree
+	}
+	// paths returns the paths of the nodes of the tree at n, in depth-first
+	// order.
+	var paths func(n *treeNode) []string
+	paths = func(n *treeNode) []string {
+		ps := []string{n.Path}
+		for _, c := range n.Children {
+			ps = append(ps, paths(c)...)
+		}
+		return ps
+	}
+
+	for _, test := range []struct {
+		target    string
+		wantPaths []string
+	}{
+		{
+			"/tree/example.com/mod@v1.2.0",
+			[]string{
+				"example.com/mod",
+				"example.com/mod/a",
+				"example.com/mod/a/b",
+				"example.com/mod/d",
+				"example.com/mod/d/e",
+				"example.com/mod/internal",
+				"example.com/mod/internal/c",
+				"example.com/mod/nested",
+			},
+		},
+		{
+			"/tree/example.com/mod@v1.2.0?depth=1",
+			[]string{
+				"example.com/mod",
+				"example.com/mod/a",
+				"example.com/mod/d",
+				"example.com/mod/internal",
+				"example.com/mod/nested",
+			},
+		},
+		{
+			"/tree/example.com/mod@v1.2.0/a",
+			[]string{"example.com/mod/a", "example.com/mod/a/b"},
+		},
+		{
+			"/tree/example.com/mod@v1.2.0?q=D/E",
+			[]string{"example.com/mod", "example.com/mod/d", "example.com/mod/d/e"},
+		},
+		{
+			"/tree/example.com/mod@v1.2.0?q=nest",
+			[]string{"example.com/mod", "example.com/mod/nested"},
+		},
+	} {
+		code, tree := get(test.target)
+		if code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want %d", test.target, code, http.StatusOK)
+		}
+		if diff := cmp.Diff(test.wantPaths, paths(tree.Root)); diff != "" {
+			t.Errorf("%s: paths mismatch (-want, +got):\n%s", test.target, diff)
+		}
+	}
+
+	_, tree := get("/tree/example.com/mod?depth=1")
+	if tree.Version != "v1.2.0" {
+		t.Errorf("version = %q, want %q", tree.Version, "v1.2.0")
+	}
+	if got, want := tree.Root.NumPackages, 4; got != want {
+		t.Errorf("root has %d packages, want %d", got, want)
+	}
+	a := tree.Root.Children[0]
+	wantA := &treeNode{
+		Path:        "example.com/mod/a",
+		Name:        "a",
+		URL:         "/example.com/mod@v1.2.0/a",
+		NumPackages: 1,
+	}
+	if diff := cmp.Diff(wantA, a, cmpopts.IgnoreFields(treeNode{}, "Synopsis")); diff != "" {
+		t.Errorf("node mismatch (-want, +got):\n%s", diff)
+	}
+	if a.Synopsis == "" {
+		t.Errorf("%s has no synopsis", a.Path)
+	}
+	if n := tree.Root.Children[2]; !n.IsInternal {
+		t.Errorf("%s is not internal", n.Path)
+	}
+	if n := tree.Root.Children[3]; !n.IsModule {
+		t.Errorf("%s is not a module", n.Path)
+	}
+
+	_, tree = get("/tree/example.com/mod@v1.2.0/a/b")
+	wantBreadcrumb := []*treeLink{
+		{Text: "example.com/mod", URL: "/example.com/mod@v1.2.0"},
+		{Text: "a", URL: "/example.com/mod/a@v1.2.0"},
+		{Text: "b", URL: "/example.com/mod@v1.2.0/a/b"},
+	}
+	if diff := cmp.Diff(wantBreadcrumb, tree.Breadcrumb); diff != "" {
+		t.Errorf("breadcrumb mismatch (-want, +got):\n%s", diff)
+	}
+
+	for _, target := range []string{
+		"/tree/example.com/mod@v1.2.0?depth=0",
+		"/tree/example.com/mod@v1.2.0?depth=x",
+	} {
+		if code, _ := get(target); code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want %d", target, code, http.StatusBadRequest)
+		}
+	}
+	if code, _ := get("/tree/example.com/mod@v1.2.0/missing"); code != http.StatusNotFound {
+		t.Errorf("missing: status = %d, want %d", code, http.StatusNotFound)
+	}
+}
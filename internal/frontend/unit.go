@@ -62,6 +62,10 @@ type UnitPage struct {
 	// LatestURL is a url pointing to the latest version of a unit.
 	LatestURL string
 
+	// Commit describes the commit of the unit's module version, if it is a
+	// pseudo-version whose commit is known.
+	Commit *versions.CommitSummary
+
 	// IsLatestMinor is true if the version displayed is the latest minor of the unit.
 	// Used to determine the canonical URL for search engines and robots meta directives.
 	IsLatestMinor bool
@@ -93,6 +97,20 @@ type UnitPage struct {
 	// (see static/frontend/unit/_header.tmpl).
 	RedirectedFromPath string
 
+	// SymbolRedirect describes the redirect to the current page from a
+	// version without the symbol in the URL fragment, if there was one.
+	// A banner explains it.
+	SymbolRedirect *SymbolRedirect
+
+	// MissingSymbol is the symbol in the URL fragment of the page that the
+	// version switcher came from, if it is not in this version. A banner
+	// explains that it was dropped.
+	MissingSymbol string
+
+	// RequestedCommit is the commit hash in the URL, if the page was
+	// requested at a commit. A banner shows the version it resolved to.
+	RequestedCommit string
+
 	// Details contains data specific to the type of page being rendered.
 	Details any
 
@@ -105,6 +123,14 @@ type UnitPage struct {
 	// IsGoProject is true if the package is from the standard library or a
 	// golang.org sub-repository.
 	IsGoProject bool
+
+	// Snippets holds the import declaration and go command for the unit,
+	// for users to copy.
+	Snippets *Snippets
+
+	// OwnerContactURL is the link to contact the owner of the module, set by
+	// the owner through a verified claim.
+	OwnerContactURL string
 }
 
 // serveUnitPage serves a unit page for a path.
@@ -129,25 +155,50 @@ func (s *Server) serveUnitPage(ctx context.Context, w http.ResponseWriter, r *ht
 		if !errors.Is(err, derrors.NotFound) {
 			return err
 		}
+		if namespaceFromContext(ctx) != nil {
+			// The fetch server only fetches modules outside namespaces.
+			return &serrors.ServerError{Status: http.StatusNotFound}
+		}
 		db, ok := ds.(internal.PostgresDB)
 		if !ok || s.fetchServer == nil {
 			return serrors.DatasourceNotSupportedError()
 		}
 		return s.fetchServer.ServePathNotFoundPage(w, r, db, info.FullPath, info.ModulePath, info.RequestedVersion)
 	}
+	// The owner of the module may have hidden the version. That excludes the
+	// module path at the version, so check the resolved module version, not
+	// only the requested path.
+	if err := checkModuleAvailable(ctx, ds, um.Path, um.ModulePath, um.Version); err != nil {
+		return err
+	}
 
 	makeDepsDevURL := depsDevURLGenerator(ctx, s.depsDevHTTPClient, um)
 
 	// Use GOOS and GOARCH query parameters to create a build context, which
 	// affects the documentation and synopsis. Omitting both results in an empty
-	// build context, which will match the first (and preferred) build context.
+	// build context, which will match the server's default build context, or
+	// else the first (and preferred) build context.
 	// It's also okay to provide just one (e.g. GOOS=windows), which will select
 	// the first doc with that value, ignoring the other one.
 	bc := internal.BuildContext{GOOS: r.FormValue("GOOS"), GOARCH: r.FormValue("GOARCH")}
-	d, err := fetchDetailsForUnit(ctx, r, tab, ds, um, info.RequestedVersion, bc, s.vulnClient)
+	if tab == tabLicenses {
+		switch r.FormValue("m") {
+		case "raw":
+			return serveLicenseRaw(ctx, w, r, ds, um)
+		case "json":
+			return serveLicensesJSON(ctx, w, ds, um)
+		}
+	}
+	if m := r.FormValue("m"); m == "md" || m == "json" || (m == "llms" && s.serveLLMs) {
+		return serveUnitMarkdown(ctx, w, r, ds, um, bc, s.defaultBC, s.baseURL)
+	}
+	d, err := fetchDetailsForUnit(ctx, r, tab, ds, um, info.RequestedVersion, bc, s.defaultBC, s.vulnClient, s.renderer)
 	if err != nil {
 		return err
 	}
+	if main, ok := d.(*MainDetails); ok && main.ReadmeNegotiated {
+		w.Header().Add("Vary", "Accept-Language")
+	}
 	if s.shouldServeJSON(r) {
 		return s.serveJSONPage(w, r, d)
 	}
@@ -201,6 +252,17 @@ func (s *Server) serveUnitPage(ctx context.Context, w http.ResponseWriter, r *ht
 		// Don't fail, but don't display a banner either.
 		log.Errorf(ctx, "extracting AlternativeModuleFlash cookie: %v", err)
 	}
+	symbolRedirectVal, err := cookie.Extract(w, r, cookie.SymbolVersionFlash)
+	if err != nil {
+		log.Errorf(ctx, "extracting SymbolVersionFlash cookie: %v", err)
+	}
+	missingSymbol, err := cookie.Extract(w, r, cookie.MissingSymbolFlash)
+	if err != nil {
+		log.Errorf(ctx, "extracting MissingSymbolFlash cookie: %v", err)
+	}
+	if !symbolNameRx.MatchString(missingSymbol) {
+		missingSymbol = ""
+	}
 	title := pageTitle(um)
 	basePage := s.newBasePage(r, title)
 	tabSettings := unitTabLookup[tab]
@@ -220,14 +282,21 @@ func (s *Server) serveUnitPage(ctx context.Context, w http.ResponseWriter, r *ht
 		DisplayVersion:        versions.DisplayVersion(um.ModulePath, info.RequestedVersion, um.Version),
 		LinkVersion:           lv,
 		LatestURL:             versions.ConstructUnitURL(um.Path, um.ModulePath, version.Latest),
-		LatestMinorClass:      latestMinorClass(lv, latestInfo),
+		Commit:                versions.NewCommitSummary(um.Version, um.Commit),
+		LatestMinorClass:      latestMinorClass(um.ModulePath, lv, latestInfo),
 		LatestMajorVersionURL: latestInfo.MajorUnitPath,
 		PageLabels:            pageLabels(um),
 		PageType:              pageType(um),
 		RedirectedFromPath:    redirectPath,
+		SymbolRedirect:        symbolRedirect(symbolRedirectVal, um),
+		MissingSymbol:         missingSymbol,
 		DepsDevURL:            makeDepsDevURL(),
 		IsGoProject:           isGoProject(um.ModulePath),
 		IsLatestMinor:         lv == latestInfo.MinorVersion,
+		Snippets:              unitSnippets(um),
+	}
+	if version.IsCommitHash(info.RequestedVersion) {
+		page.RequestedCommit = info.RequestedVersion
 	}
 
 	// Show the banner if there was no error getting the latest major version,
@@ -241,11 +310,22 @@ func (s *Server) serveUnitPage(ctx context.Context, w http.ResponseWriter, r *ht
 	main, ok := d.(*MainDetails)
 	if ok {
 		page.MetaDescription = metaDescription(main.DocSynopsis)
+		if s.docFeedback != nil && main.IsPackage {
+			main.DocFeedbackURL = docFeedbackURL(um, main.GOOS, main.GOARCH)
+		}
 	}
 
 	// Get vulnerability information.
 	page.Vulns = vuln.VulnsForPackage(ctx, um.ModulePath, um.Version, um.Path, s.vulnClient)
 
+	if s.claims != nil {
+		page.OwnerContactURL, err = s.claims.GetModuleContactURL(ctx, um.ModulePath)
+		if err != nil {
+			// Don't fail, but don't show the link either.
+			log.Errorf(ctx, "GetModuleContactURL(%q): %v", um.ModulePath, err)
+		}
+	}
+
 	s.servePage(ctx, w, tabSettings.TemplateName, page)
 	return nil
 }
@@ -269,14 +349,20 @@ func (s *Server) serveJSONPage(w http.ResponseWriter, r *http.Request, d any) (e
 	return nil
 }
 
-func latestMinorClass(version string, latest internal.LatestInfo) string {
+// latestMinorClass returns the CSS class of the badge that says whether
+// version of modulePath is the latest.
+func latestMinorClass(modulePath, v string, latest internal.LatestInfo) string {
 	c := "DetailsHeader-badge"
 	switch {
 	case latest.MinorVersion == "":
 		c += "--unknown"
-	case latest.MinorVersion == version && !latest.UnitExistsAtMinor:
+	case version.IsIncompatible(v) && latest.MajorModulePath != "" && latest.MajorModulePath != modulePath:
+		// A later major version has adopted modules, so an incompatible
+		// version is never the latest. The major version banner links to it.
+		c += "--unknown"
+	case latest.MinorVersion == v && !latest.UnitExistsAtMinor:
 		c += "--notAtLatest"
-	case latest.MinorVersion == version:
+	case latest.MinorVersion == v:
 		c += "--latest"
 	default:
 		c += "--goToLatest"
@@ -304,7 +390,13 @@ func isValidTabForUnit(tab string, um *internal.UnitMeta, details any) bool {
 	if tab == tabLicenses && !(details.(*LicensesDetails).IsRedistributable) {
 		return false
 	}
-	if !um.IsPackage() && (tab == tabImports || tab == tabImportedBy) {
+	if tab == tabDocs && len(details.(*DocsDetails).Pages) == 0 {
+		return false
+	}
+	if !um.IsPackage() && (tab == tabImports || tab == tabImportedBy || tab == tabSource) {
+		return false
+	}
+	if (!um.IsPackage() || um.IsCommand()) && (tab == tabDiff || tab == tabHistory) {
 		return false
 	}
 	return true
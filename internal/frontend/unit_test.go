@@ -5,10 +5,18 @@
 package frontend
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/frontend/versions"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
 	"golang.org/x/pkgsite/internal/testing/sample"
 )
 
@@ -77,6 +85,9 @@ func TestIsValidTab(t *testing.T) {
 		tabImports,
 		tabImportedBy,
 		tabLicenses,
+		tabSource,
+		tabDiff,
+		tabHistory,
 	}
 	for _, test := range []struct {
 		name     string
@@ -99,19 +110,19 @@ func TestIsValidTab(t *testing.T) {
 		{
 			name:     "package",
 			um:       sample.UnitMeta(sample.ModulePath+"/go/packages", sample.ModulePath, sample.VersionString, "packages", true),
-			wantTabs: []string{tabMain, tabVersions, tabImports, tabImportedBy, tabLicenses},
+			wantTabs: []string{tabMain, tabVersions, tabImports, tabImportedBy, tabLicenses, tabSource, tabDiff, tabHistory},
 			details:  &LicensesDetails{IsRedistributable: true},
 		},
 		{
 			name:     "command",
 			um:       sample.UnitMeta(sample.ModulePath+"/cmd", sample.ModulePath, sample.VersionString, "main", true),
-			wantTabs: []string{tabMain, tabVersions, tabImports, tabImportedBy, tabLicenses},
+			wantTabs: []string{tabMain, tabVersions, tabImports, tabImportedBy, tabLicenses, tabSource},
 			details:  &LicensesDetails{IsRedistributable: true},
 		},
 		{
 			name:     "non-redist pkg",
 			um:       sample.UnitMeta(sample.ModulePath+"/go/packages", sample.ModulePath, sample.VersionString, "packages", false),
-			wantTabs: []string{tabMain, tabVersions, tabImports, tabImportedBy},
+			wantTabs: []string{tabMain, tabVersions, tabImports, tabImportedBy, tabSource, tabDiff, tabHistory},
 			details:  &LicensesDetails{IsRedistributable: false},
 		},
 	} {
@@ -129,6 +140,15 @@ func TestIsValidTab(t *testing.T) {
 			})
 		}
 	}
+
+	// The docs tab is valid for any unit with docs pages.
+	um := sample.UnitMeta(sample.ModulePath, sample.ModulePath, sample.VersionString, "", true)
+	if isValidTabForUnit(tabDocs, um, &DocsDetails{}) {
+		t.Error("docs tab without pages is valid")
+	}
+	if !isValidTabForUnit(tabDocs, um, &DocsDetails{Pages: []*DocsPage{{Name: "index.md"}}}) {
+		t.Error("docs tab with pages is not valid")
+	}
 }
 
 func TestMetaDescription(t *testing.T) {
@@ -154,3 +174,126 @@ func TestMetaDescription(t *testing.T) {
 		}
 	}
 }
+
+func TestLatestMinorClass(t *testing.T) {
+	const modulePath = "github.com/a/b"
+	for _, test := range []struct {
+		name    string
+		version string
+		latest  internal.LatestInfo
+		want    string
+	}{
+		{
+			name:    "unknown",
+			version: "v1.0.0",
+			want:    "DetailsHeader-badge--unknown",
+		},
+		{
+			name:    "latest",
+			version: "v1.0.0",
+			latest:  internal.LatestInfo{MinorVersion: "v1.0.0", UnitExistsAtMinor: true, MajorModulePath: modulePath},
+			want:    "DetailsHeader-badge--latest",
+		},
+		{
+			name:    "not at latest",
+			version: "v1.0.0",
+			latest:  internal.LatestInfo{MinorVersion: "v1.0.0", MajorModulePath: modulePath},
+			want:    "DetailsHeader-badge--notAtLatest",
+		},
+		{
+			name:    "go to latest",
+			version: "v1.0.0",
+			latest:  internal.LatestInfo{MinorVersion: "v1.1.0", UnitExistsAtMinor: true, MajorModulePath: modulePath},
+			want:    "DetailsHeader-badge--goToLatest",
+		},
+		{
+			name:    "incompatible latest",
+			version: "v2.0.0+incompatible",
+			latest:  internal.LatestInfo{MinorVersion: "v2.0.0+incompatible", UnitExistsAtMinor: true, MajorModulePath: modulePath},
+			want:    "DetailsHeader-badge--latest",
+		},
+		{
+			name:    "incompatible with later major module",
+			version: "v2.0.0+incompatible",
+			latest:  internal.LatestInfo{MinorVersion: "v2.0.0+incompatible", UnitExistsAtMinor: true, MajorModulePath: modulePath + "/v3"},
+			want:    "DetailsHeader-badge--unknown",
+		},
+		{
+			name:    "compatible with later major module",
+			version: "v1.0.0",
+			latest:  internal.LatestInfo{MinorVersion: "v1.0.0", UnitExistsAtMinor: true, MajorModulePath: modulePath + "/v3"},
+			want:    "DetailsHeader-badge--latest",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := latestMinorClass(modulePath, test.version, test.latest)
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+// commitDataSource resolves commit hashes to versions, as version_map does
+// in the database.
+type commitDataSource struct {
+	*fakedatasource.FakeDataSource
+	commits map[string]string // from commit hash to version
+}
+
+func (ds commitDataSource) GetUnitMeta(ctx context.Context, path, requestedModulePath, requestedVersion string) (*internal.UnitMeta, error) {
+	if v, ok := ds.commits[requestedVersion]; ok {
+		requestedVersion = v
+	}
+	return ds.FakeDataSource.GetUnitMeta(ctx, path, requestedModulePath, requestedVersion)
+}
+
+func TestServePseudoVersionCommit(t *testing.T) {
+	ctx := context.Background()
+	const pseudo = "v0.0.0-20240601100000-abcdef123456"
+	m := sample.Module("example.com/mod", pseudo)
+	m.Commit = &source.CommitInfo{
+		Hash:       "abcdef1234567890abcdef1234567890abcdef12",
+		Subject:    "Fix race in pool",
+		AuthorTime: time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC),
+		URL:        "https://example.com/mod/commit/abcdef1234567890abcdef1234567890abcdef12",
+	}
+	fds := fakedatasource.New()
+	fds.MustInsertModule(ctx, m)
+	ds := commitDataSource{fds, map[string]string{m.Commit.Hash: pseudo}}
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return ds },
+	})
+
+	commit := `>abcdef123456</a> — ‘Fix race in pool’ (2024-06-01)`
+	for _, test := range []struct {
+		path string
+		want []string
+	}{
+		{"/example.com/mod@" + pseudo, []string{`data-test-id="UnitHeader-commit"`, commit}},
+		{"/example.com/mod@" + pseudo + "?tab=versions", []string{`class="Version-commit"`, commit}},
+		{"/example.com/mod@" + m.Commit.Hash, []string{
+			`data-test-id="UnitHeader-commitBanner"`,
+			`href="/example.com/mod@` + pseudo + `"`,
+		}},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			res := w.Result()
+			if res.StatusCode != http.StatusOK {
+				t.Fatalf("status = %d, want %d", res.StatusCode, http.StatusOK)
+			}
+			b, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body := string(b)
+			for _, want := range test.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("body does not contain %q", want)
+				}
+			}
+		})
+	}
+}
@@ -201,5 +201,10 @@ func IsSupportedVersion(fullPath, requestedVersion string) bool {
 	if _, ok := internal.DefaultBranches[requestedVersion]; ok {
 		return !stdlib.Contains(fullPath) || requestedVersion == "master"
 	}
+	if version.IsCommitHash(requestedVersion) {
+		// The proxy resolves commits of modules, but not of the standard
+		// library.
+		return !stdlib.Contains(fullPath)
+	}
 	return requestedVersion == version.Latest || semver.IsValid(requestedVersion)
 }
@@ -186,11 +186,14 @@ func TestIsSupportedVersion(t *testing.T) {
 		{sample.ModulePath, "latest", true},
 		{sample.ModulePath, "master", true},
 		{sample.ModulePath, "main", true},
+		{sample.ModulePath, "8f7fa2680c82f1f0e9f1c6b6a7e3d5c4b3a29180", true},
+		{sample.ModulePath, "8f7fa2680c82", false},
 		{"net/http", "v1.2.3", true}, // IsSupportedVersion expects the goTag is already converted to semver
 		{"net/http", "v1.2.3.bad", false},
 		{"net/http", "latest", true},
 		{"net/http", "master", true},
 		{"net/http", "main", false},
+		{"net/http", "8f7fa2680c82f1f0e9f1c6b6a7e3d5c4b3a29180", false},
 	}
 	for _, test := range tests {
 		got := IsSupportedVersion(test.path, test.version)
@@ -0,0 +1,227 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package versions
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/google/safehtml/template"
+	"golang.org/x/mod/semver"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/frontend/page"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/stdlib"
+)
+
+// DiffDetails contains the changes to the exported API of a package between
+// two versions of its module, used to populate the diff view of the versions
+// tab.
+type DiffDetails struct {
+	// From and To are the compared versions, as displayed.
+	From, To string
+
+	// FromLink and ToLink link to the package at the compared versions.
+	FromLink, ToLink string
+
+	// Added, Removed and Changed hold the symbols that were added, removed,
+	// or whose synopsis changed between From and To, sorted by name.
+	Added, Removed, Changed []*DiffSymbol
+}
+
+// DiffSymbol is a symbol that differs between the two versions of a
+// DiffDetails.
+type DiffSymbol struct {
+	Name string
+	Kind internal.SymbolKind
+
+	// Synopsis is the synopsis of the symbol at the To version, or at the
+	// From version if the symbol was removed.
+	Synopsis string
+
+	// OldSynopsis is the synopsis at the From version of a changed symbol.
+	OldSynopsis string
+
+	// Link is the link to the symbol in the documentation of the version
+	// that has it, preferring To.
+	Link string
+}
+
+// FetchDiffDetails returns the changes to the API of the package um between
+// the versions from and to of its module. The versions are as displayed on
+// the versions tab, so for the standard library they are Go tags.
+func FetchDiffDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, from, to string) (*DiffDetails, error) {
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return nil, serrors.DatasourceNotSupportedError()
+	}
+	if err := checkFeatureReady(ctx, db, um, internal.FeatureAPIDiff, "API changes"); err != nil {
+		return nil, err
+	}
+	fromVersion, err := diffVersion(um.ModulePath, from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := diffVersion(um.ModulePath, to)
+	if err != nil {
+		return nil, err
+	}
+	mis, err := db.GetVersionsForPath(ctx, um.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range []string{fromVersion, toVersion} {
+		if !hasVersion(mis, um.ModulePath, v) {
+			return nil, &serrors.ServerError{
+				Status: http.StatusNotFound,
+				Epage: &page.ErrorPage{
+					MessageTemplate: template.MakeTrustedTemplate(
+						`<h3 class="Error-message">{{.Path}} does not exist at version {{.Version}}.</h3>`),
+					MessageData: struct{ Path, Version string }{um.Path, LinkVersion(um.ModulePath, v, v)},
+				},
+			}
+		}
+	}
+	sh, err := db.GetSymbolsAtVersions(ctx, um.Path, um.ModulePath, []string{fromVersion, toVersion})
+	if err != nil {
+		return nil, err
+	}
+	fromLink := ConstructUnitURL(um.Path, um.ModulePath, fromVersion)
+	toLink := ConstructUnitURL(um.Path, um.ModulePath, toVersion)
+	dd := diffSymbols(sh.SymbolsAtVersion(fromVersion), sh.SymbolsAtVersion(toVersion), fromLink, toLink)
+	dd.From = LinkVersion(um.ModulePath, fromVersion, fromVersion)
+	dd.To = LinkVersion(um.ModulePath, toVersion, toVersion)
+	dd.FromLink = fromLink
+	dd.ToLink = toLink
+	return dd, nil
+}
+
+// checkFeatureReady returns a not-found error, explaining that the data
+// described by what is not available yet, if the feature has not been
+// backfilled for the module of um.
+func checkFeatureReady(ctx context.Context, db internal.PostgresDB, um *internal.UnitMeta, f internal.DataFeature, what string) error {
+	ready, err := db.GetReadyFeatures(ctx, um.ModulePath)
+	if err != nil {
+		return err
+	}
+	if ready[f] {
+		return nil
+	}
+	return &serrors.ServerError{
+		Status: http.StatusNotFound,
+		Epage: &page.ErrorPage{
+			MessageTemplate: template.MakeTrustedTemplate(
+				`<h3 class="Error-message">The {{.What}} of {{.Path}} are not available yet.</h3>`),
+			MessageData: struct{ What, Path string }{what, um.Path},
+		},
+	}
+}
+
+// diffVersion returns the semantic version corresponding to the version v of
+// modulePath, as displayed on the versions tab.
+func diffVersion(modulePath, v string) (string, error) {
+	sv := v
+	if modulePath == stdlib.ModulePath {
+		sv = stdlib.VersionForTag(v)
+	}
+	if v == "" || !semver.IsValid(sv) {
+		return "", serrors.InvalidVersionError(modulePath, v)
+	}
+	return sv, nil
+}
+
+// diffVersions returns the versions in lists that can be compared on the
+// diff view, in the order they are displayed. It returns nil if there are
+// fewer than two.
+func diffVersions(modulePath string, lists []*VersionList) []string {
+	var vs []string
+	for _, vl := range lists {
+		for _, v := range vl.Versions {
+			if _, err := diffVersion(modulePath, v.Version); err == nil {
+				vs = append(vs, v.Version)
+			}
+		}
+	}
+	if len(vs) < 2 {
+		return nil
+	}
+	return vs
+}
+
+// hasVersion reports whether mis has the version v of modulePath.
+func hasVersion(mis []*internal.ModuleInfo, modulePath, v string) bool {
+	for _, mi := range mis {
+		if mi.ModulePath == modulePath && mi.Version == v {
+			return true
+		}
+	}
+	return false
+}
+
+// diffSymbols compares the symbols of a package at two versions. Symbols
+// whose synopsis differs by build context are compared using the first
+// build context in internal.BuildContexts that has them.
+func diffSymbols(from, to map[string]map[internal.SymbolMeta]*internal.SymbolBuildContexts, fromLink, toLink string) *DiffDetails {
+	dd := &DiffDetails{}
+	for name, toMetas := range to {
+		tm, builds := preferredSymbol(toMetas)
+		ds := &DiffSymbol{
+			Name:     name,
+			Kind:     tm.Kind,
+			Synopsis: tm.Synopsis,
+			Link:     symbolLink(toLink, name, builds),
+		}
+		fromMetas, ok := from[name]
+		if !ok {
+			dd.Added = append(dd.Added, ds)
+			continue
+		}
+		if fm, _ := preferredSymbol(fromMetas); fm.Synopsis != tm.Synopsis {
+			ds.OldSynopsis = fm.Synopsis
+			dd.Changed = append(dd.Changed, ds)
+		}
+	}
+	for name, fromMetas := range from {
+		if _, ok := to[name]; ok {
+			continue
+		}
+		fm, builds := preferredSymbol(fromMetas)
+		dd.Removed = append(dd.Removed, &DiffSymbol{
+			Name:     name,
+			Kind:     fm.Kind,
+			Synopsis: fm.Synopsis,
+			Link:     symbolLink(fromLink, name, builds),
+		})
+	}
+	for _, dss := range [][]*DiffSymbol{dd.Added, dd.Removed, dd.Changed} {
+		sort.Slice(dss, func(i, j int) bool { return dss[i].Name < dss[j].Name })
+	}
+	return dd
+}
+
+// preferredSymbol returns the SymbolMeta of metas that supports the earliest
+// build context in internal.BuildContexts, along with all the build contexts
+// it supports. If none does, it returns the SymbolMeta with the smallest
+// synopsis, so that the choice is deterministic.
+func preferredSymbol(metas map[internal.SymbolMeta]*internal.SymbolBuildContexts) (internal.SymbolMeta, []internal.BuildContext) {
+	for _, b := range internal.BuildContexts {
+		for sm, us := range metas {
+			if us.SupportsBuild(b) {
+				return sm, us.BuildContexts()
+			}
+		}
+	}
+	var (
+		best  internal.SymbolMeta
+		found bool
+	)
+	for sm := range metas {
+		if !found || sm.Synopsis < best.Synopsis {
+			best, found = sm, true
+		}
+	}
+	return best, metas[best].BuildContexts()
+}
@@ -0,0 +1,125 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package versions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestFetchDiffDetails(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+
+	const modulePath = "example.com/m"
+	m1 := sample.Module(modulePath, "v1.0.0", "pkg")
+	m1.Packages()[0].Documentation[0].API = []*internal.Symbol{sample.Constant, sample.Variable, sample.Function}
+	fds.MustInsertModule(ctx, m1)
+
+	m2 := sample.Module(modulePath, "v1.1.0", "pkg")
+	function := *sample.Function
+	function.Synopsis = "func Function(ctx context.Context) error"
+	m2.Packages()[0].Documentation[0].API = []*internal.Symbol{sample.Constant, &function, sample.Type}
+	fds.MustInsertModule(ctx, m2)
+
+	um := &m2.Packages()[0].UnitMeta
+	got, err := FetchDiffDetails(ctx, fds, um, "v1.0.0", "v1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const (
+		fromLink = "/example.com/m@v1.0.0/pkg"
+		toLink   = "/example.com/m@v1.1.0/pkg"
+	)
+	want := &DiffDetails{
+		From:     "v1.0.0",
+		To:       "v1.1.0",
+		FromLink: fromLink,
+		ToLink:   toLink,
+		Added: []*DiffSymbol{
+			{Name: "New", Kind: internal.SymbolKindFunction, Synopsis: "func New() *Type", Link: toLink + "#New"},
+			{Name: "Type", Kind: internal.SymbolKindType, Synopsis: "type Type struct", Link: toLink + "#Type"},
+			{Name: "Type.Field", Kind: internal.SymbolKindField, Synopsis: "field", Link: toLink + "#Type.Field"},
+			{Name: "Type.Method", Kind: internal.SymbolKindMethod, Synopsis: "method", Link: toLink + "#Type.Method"},
+		},
+		Removed: []*DiffSymbol{
+			{Name: "Variable", Kind: internal.SymbolKindVariable, Synopsis: "var Variable", Link: fromLink + "#Variable"},
+		},
+		Changed: []*DiffSymbol{
+			{
+				Name:        "Function",
+				Kind:        internal.SymbolKindFunction,
+				Synopsis:    "func Function(ctx context.Context) error",
+				OldSynopsis: "func Function() error",
+				Link:        toLink + "#Function",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, test := range []struct {
+		from, to   string
+		wantStatus int
+	}{
+		{"", "v1.1.0", http.StatusBadRequest},
+		{"v1.0.0", "bad", http.StatusBadRequest},
+		{"v1.0.0", "v1.2.0", http.StatusNotFound},
+	} {
+		_, err := FetchDiffDetails(ctx, fds, um, test.from, test.to)
+		var serr *serrors.ServerError
+		if !errors.As(err, &serr) || serr.Status != test.wantStatus {
+			t.Errorf("FetchDiffDetails(%q, %q): got %v, want status %d", test.from, test.to, err, test.wantStatus)
+		}
+	}
+
+	// Until the API diff is backfilled for the module, it is not offered.
+	for _, ready := range []bool{true, false} {
+		if !ready {
+			fds.SetFeatureNotReady(modulePath, internal.FeatureAPIDiff)
+		}
+		_, err = FetchDiffDetails(ctx, fds, um, "v1.0.0", "v1.1.0")
+		var serr *serrors.ServerError
+		if ready != (err == nil) || (!ready && (!errors.As(err, &serr) || serr.Status != http.StatusNotFound)) {
+			t.Errorf("FetchDiffDetails, ready=%t: got %v", ready, err)
+		}
+		vd, err := FetchVersionsDetails(ctx, fds, um, Filter{}, nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := vd.DiffVersions != nil; got != ready {
+			t.Errorf("ready=%t: got DiffVersions %v", ready, vd.DiffVersions)
+		}
+	}
+}
+
+func TestDiffVersion(t *testing.T) {
+	for _, test := range []struct {
+		modulePath, v, want string
+		wantErr             bool
+	}{
+		{"example.com/m", "v1.2.3", "v1.2.3", false},
+		{"example.com/m", "v2.0.0+incompatible", "v2.0.0+incompatible", false},
+		{"example.com/m", "go1.21.0", "", true},
+		{"example.com/m", "", "", true},
+		{"std", "go1.21.0", "v1.21.0", false},
+		{"std", "go1.22rc1", "v1.22.0-rc.1", false},
+		{"std", "master", "", true},
+	} {
+		got, err := diffVersion(test.modulePath, test.v)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("diffVersion(%q, %q) = (%q, %v), want (%q, error: %t)", test.modulePath, test.v, got, err, test.want, test.wantErr)
+		}
+	}
+}
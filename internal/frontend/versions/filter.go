@@ -0,0 +1,183 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package versions
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/frontend/page"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+// minVersionsForFilter is the number of versions of a module from which the
+// versions tab has a form to filter them.
+const minVersionsForFilter = 10
+
+// maxFilterMatchLen is the greatest length of the text that versions can be
+// filtered by.
+const maxFilterMatchLen = 100
+
+// Values of Filter.Prerelease.
+const (
+	PrereleaseExclude = "exclude"
+	PrereleaseOnly    = "only"
+)
+
+// Filter selects the versions of the current module that the versions tab
+// shows. The zero Filter selects all of them. Filters are given by query
+// params, so that filtered lists have stable URLs.
+type Filter struct {
+	// Major is a major version, such as "v2", or empty for all of them.
+	// Incompatible versions belong to the major version of their semver.
+	Major string
+	// Prerelease is PrereleaseExclude to show only releases,
+	// PrereleaseOnly to show only prereleases and pseudo-versions, or
+	// empty for both.
+	Prerelease string
+	// Since and Until are the first and last days of the commit times of
+	// the versions, or zero for no bound. Versions with an unknown commit
+	// time are not shown if either is set.
+	Since, Until time.Time
+	// Match is text that the version or the subject of its commit must
+	// contain, ignoring case, or empty.
+	Match string
+}
+
+// ParseFilter returns the filter given by the query params major, pre,
+// since, until and match of a request for the versions tab. Empty params
+// are ignored, so that submitting the tab's form with some fields blank
+// works. The error is a *serrors.ServerError with status 400 if a param
+// is invalid.
+func ParseFilter(q url.Values) (Filter, error) {
+	f := Filter{
+		Major:      strings.TrimSpace(q.Get("major")),
+		Prerelease: q.Get("pre"),
+		Match:      strings.TrimSpace(q.Get("match")),
+	}
+	badRequest := func(format string, args ...any) error {
+		return &serrors.ServerError{
+			Status: http.StatusBadRequest,
+			Epage:  &page.ErrorPage{MessageData: fmt.Sprintf(format, args...)},
+		}
+	}
+	if f.Major != "" && !isMajor(f.Major) {
+		return Filter{}, badRequest("%q is not a major version, like v2.", f.Major)
+	}
+	switch f.Prerelease {
+	case "", PrereleaseExclude, PrereleaseOnly:
+	default:
+		return Filter{}, badRequest("The pre param must be %q or %q.", PrereleaseExclude, PrereleaseOnly)
+	}
+	for _, d := range []struct {
+		param string
+		t     *time.Time
+	}{{"since", &f.Since}, {"until", &f.Until}} {
+		s := q.Get(d.param)
+		if s == "" {
+			continue
+		}
+		t, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			return Filter{}, badRequest("The %s param must be a date, like 2006-01-02.", d.param)
+		}
+		*d.t = t
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && f.Until.Before(f.Since) {
+		return Filter{}, badRequest("The until date is before the since date.")
+	}
+	if len(f.Match) > maxFilterMatchLen {
+		return Filter{}, badRequest("The match param is longer than %d bytes.", maxFilterMatchLen)
+	}
+	return f, nil
+}
+
+// isMajor reports whether s is a major version of a module, like "v2", or of
+// the standard library, like "go1".
+func isMajor(s string) bool {
+	for _, prefix := range []string{"v", "go"} {
+		if n, ok := strings.CutPrefix(s, prefix); ok && n != "" && strings.Trim(n, "0123456789") == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// IsZero reports whether f selects all versions.
+func (f Filter) IsZero() bool {
+	return f == Filter{}
+}
+
+// SinceDate and UntilDate return Since and Until as YYYY-MM-DD, or empty if
+// they are zero, for the fields of the filter form.
+func (f Filter) SinceDate() string { return dateOnly(f.Since) }
+func (f Filter) UntilDate() string { return dateOnly(f.Until) }
+
+func dateOnly(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+// matches reports whether f selects the version mi, whose major version is
+// major.
+func (f Filter) matches(mi *internal.ModuleInfo, major string) bool {
+	if f.Major != "" && f.Major != major {
+		return false
+	}
+	if f.Prerelease != "" {
+		typ, err := version.ParseType(mi.Version)
+		if err != nil {
+			return false
+		}
+		if (typ == version.TypeRelease) != (f.Prerelease == PrereleaseExclude) {
+			return false
+		}
+	}
+	if !f.Since.IsZero() || !f.Until.IsZero() {
+		if mi.CommitTime.IsZero() {
+			return false
+		}
+		day := mi.CommitTime.UTC().Truncate(24 * time.Hour)
+		if (!f.Since.IsZero() && day.Before(f.Since)) || (!f.Until.IsZero() && day.After(f.Until)) {
+			return false
+		}
+	}
+	if f.Match != "" {
+		m := strings.ToLower(f.Match)
+		texts := []string{mi.Version, LinkVersion(mi.ModulePath, mi.Version, mi.Version)}
+		if mi.Commit != nil {
+			texts = append(texts, mi.Commit.Subject)
+		}
+		found := false
+		for _, t := range texts {
+			if strings.Contains(strings.ToLower(t), m) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterDetails describes the form for filtering the versions tab.
+type FilterDetails struct {
+	Filter Filter
+	// Majors are the major versions of the current module, newest first,
+	// for the choices of the form.
+	Majors []string
+	// NumVersions is the number of versions of the current module, and
+	// NumShown the number that the filter selects.
+	NumVersions, NumShown int
+}
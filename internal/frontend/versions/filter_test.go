@@ -0,0 +1,190 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package versions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestParseFilter(t *testing.T) {
+	date := func(s string) time.Time {
+		d, err := time.Parse(time.DateOnly, s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	for _, test := range []struct {
+		query string
+		want  Filter
+	}{
+		{"", Filter{}},
+		{"tab=versions&major=&pre=&since=&until=&match=", Filter{}},
+		{"major=v2&pre=exclude", Filter{Major: "v2", Prerelease: PrereleaseExclude}},
+		{"major=go1&pre=only", Filter{Major: "go1", Prerelease: PrereleaseOnly}},
+		{"since=2024-01-01&until=2024-12-31", Filter{Since: date("2024-01-01"), Until: date("2024-12-31")}},
+		{"match=+RC+", Filter{Match: "RC"}},
+	} {
+		q, err := url.ParseQuery(test.query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := ParseFilter(q)
+		if err != nil {
+			t.Errorf("ParseFilter(%q): %v", test.query, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseFilter(%q) = %+v, want %+v", test.query, got, test.want)
+		}
+	}
+
+	for _, query := range []string{
+		"major=2",
+		"major=v",
+		"major=v2.1",
+		"pre=yes",
+		"since=yesterday",
+		"until=2024-13-01",
+		"since=2024-02-01&until=2024-01-01",
+		"match=" + string(make([]byte, maxFilterMatchLen+1)),
+	} {
+		q, err := url.ParseQuery(query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = ParseFilter(q)
+		var serr *serrors.ServerError
+		if !errors.As(err, &serr) || serr.Status != http.StatusBadRequest {
+			t.Errorf("ParseFilter(%q): got %v, want status %d", query, err, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	commitTime := time.Date(2024, 6, 15, 13, 0, 0, 0, time.UTC)
+	mi := func(v string) *internal.ModuleInfo {
+		return &internal.ModuleInfo{ModulePath: "example.com/mod", Version: v, CommitTime: commitTime}
+	}
+	pseudo := mi("v1.2.1-0.20240615130000-abcdef123456")
+	pseudo.Commit = &source.CommitInfo{Subject: "Fix the Frobnicator"}
+	noTime := mi("v1.0.0")
+	noTime.CommitTime = time.Time{}
+	std := &internal.ModuleInfo{ModulePath: "std", Version: "v1.21.0-rc.2", CommitTime: commitTime}
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	for _, test := range []struct {
+		f     Filter
+		mi    *internal.ModuleInfo
+		major string
+		want  bool
+	}{
+		{Filter{}, mi("v1.0.0"), "v1", true},
+		{Filter{Major: "v1"}, mi("v1.0.0"), "v1", true},
+		{Filter{Major: "v2"}, mi("v1.0.0"), "v1", false},
+		{Filter{Prerelease: PrereleaseExclude}, mi("v1.0.0"), "v1", true},
+		{Filter{Prerelease: PrereleaseExclude}, mi("v1.1.0-rc.1"), "v1", false},
+		{Filter{Prerelease: PrereleaseExclude}, pseudo, "v1", false},
+		{Filter{Prerelease: PrereleaseOnly}, mi("v1.0.0"), "v1", false},
+		{Filter{Prerelease: PrereleaseOnly}, pseudo, "v1", true},
+		{Filter{Since: day(2024, 6, 15)}, mi("v1.0.0"), "v1", true},
+		{Filter{Since: day(2024, 6, 16)}, mi("v1.0.0"), "v1", false},
+		{Filter{Until: day(2024, 6, 15)}, mi("v1.0.0"), "v1", true},
+		{Filter{Until: day(2024, 6, 14)}, mi("v1.0.0"), "v1", false},
+		{Filter{Since: day(2024, 1, 1)}, noTime, "v1", false},
+		{Filter{Match: "1.1"}, mi("v1.1.0"), "v1", true},
+		{Filter{Match: "1.1"}, mi("v1.2.0"), "v1", false},
+		{Filter{Match: "frob"}, pseudo, "v1", true},
+		{Filter{Match: "go1.21rc2"}, std, "go1", true},
+	} {
+		t.Run(fmt.Sprintf("%+v/%s", test.f, test.mi.Version), func(t *testing.T) {
+			if got := test.f.matches(test.mi, test.major); got != test.want {
+				t.Errorf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestFetchVersionsDetailsFiltered(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	var pkg *internal.Unit
+	for _, v := range []string{
+		"v1.0.0", "v1.1.0-rc.1", "v1.1.0", "v1.2.0", "v1.3.0", "v1.4.0",
+		"v1.5.0", "v1.6.0", "v1.7.0-beta", "v1.7.0", "v2.0.0+incompatible",
+	} {
+		m := sample.Module(modulePath1, v, sample.Suffix)
+		fds.MustInsertModule(ctx, m)
+		pkg = m.Packages()[0]
+	}
+
+	for _, test := range []struct {
+		name          string
+		f             Filter
+		wantVersions  []string
+		wantFiltering *FilterDetails
+	}{
+		{
+			name: "no filter",
+			wantVersions: []string{
+				"v1.7.0", "v1.7.0-beta", "v1.6.0", "v1.5.0", "v1.4.0", "v1.3.0",
+				"v1.2.0", "v1.1.0", "v1.1.0-rc.1", "v1.0.0", "v2.0.0+incompatible",
+			},
+		},
+		{
+			name:         "prereleases",
+			f:            Filter{Prerelease: PrereleaseOnly},
+			wantVersions: []string{"v1.7.0-beta", "v1.1.0-rc.1"},
+		},
+		{
+			name:         "major",
+			f:            Filter{Major: "v2"},
+			wantVersions: []string{"v2.0.0+incompatible"},
+		},
+		{
+			name:         "match",
+			f:            Filter{Match: "v1.1", Prerelease: PrereleaseExclude},
+			wantVersions: []string{"v1.1.0"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			vd, err := FetchVersionsDetails(ctx, fds, &pkg.UnitMeta, test.f, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, vl := range append(vd.ThisModule, vd.IncompatibleModules...) {
+				for _, v := range vl.Versions {
+					got = append(got, v.Version)
+				}
+			}
+			if diff := cmp.Diff(test.wantVersions, got); diff != "" {
+				t.Errorf("versions mismatch (-want, +got):\n%s", diff)
+			}
+			want := &FilterDetails{
+				Filter:      test.f,
+				Majors:      []string{"v2", "v1"},
+				NumVersions: 11,
+				NumShown:    len(test.wantVersions),
+			}
+			if diff := cmp.Diff(want, vd.Filtering); diff != "" {
+				t.Errorf("Filtering mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
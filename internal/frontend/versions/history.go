@@ -0,0 +1,239 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package versions
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/safehtml/template"
+	"golang.org/x/mod/semver"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/frontend/page"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/lru"
+	"golang.org/x/pkgsite/internal/stdlib"
+	"golang.org/x/sync/errgroup"
+)
+
+// SymbolHistoryDetails contains the signature and documentation of a symbol
+// across the versions of its module, used to populate the history tab.
+type SymbolHistoryDetails struct {
+	// Symbol is the name of the symbol, such as "Type.Method".
+	Symbol string
+
+	// Introduced is the version at which the symbol was added to the
+	// package, if known. It may be older than the oldest entry.
+	Introduced string
+
+	// Entries are the versions at which the symbol was introduced or its
+	// synopsis or documentation changed, from oldest to newest.
+	Entries []*SymbolHistoryEntry
+
+	// Current is the index in Entries of the entry that applies to the
+	// version of the page.
+	Current int
+}
+
+// SymbolHistoryEntry describes a symbol at one version.
+type SymbolHistoryEntry struct {
+	// Version is the version, as displayed.
+	Version string
+
+	// Link is the link to the symbol in the documentation at Version.
+	Link string
+
+	Synopsis string
+	Doc      string
+
+	// SynopsisChanged and DocChanged report whether the synopsis or
+	// documentation differ from the previous entry.
+	SynopsisChanged, DocChanged bool
+}
+
+const (
+	// maxSymbolHistoryVersions is the number of most recent versions of a
+	// module whose documentation is examined for the history tab.
+	maxSymbolHistoryVersions = 30
+
+	// symbolDocsConcurrency limits the number of versions whose
+	// documentation is decoded at once.
+	symbolDocsConcurrency = 4
+)
+
+// symbolDocsKey identifies the documentation of a package at a version.
+type symbolDocsKey struct {
+	path, version string
+	build         internal.BuildContext
+	defaultBuild  internal.BuildContext // selects the documentation if build is zero
+}
+
+// symbolDocsCache holds the symbol docs of recently decoded packages. The
+// documentation of a package version does not change, so entries do not
+// expire.
+var symbolDocsCache = lru.New[symbolDocsKey, map[string]string](1000)
+
+// FetchSymbolHistoryDetails returns the history of the symbol named name in
+// the package um, over the most recent versions of its module. Its docs are
+// those for bc, or for defaultBC if bc is zero.
+func FetchSymbolHistoryDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, name string, bc, defaultBC internal.BuildContext) (*SymbolHistoryDetails, error) {
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return nil, serrors.DatasourceNotSupportedError()
+	}
+	if err := checkFeatureReady(ctx, db, um, internal.FeatureSymbolHistory, "symbol versions"); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, &serrors.ServerError{
+			Status: http.StatusBadRequest,
+			Epage: &page.ErrorPage{
+				MessageTemplate: template.MakeTrustedTemplate(
+					`<h3 class="Error-message">A symbol must be provided.</h3>`),
+			},
+		}
+	}
+	mis, err := db.GetVersionsForPath(ctx, um.Path)
+	if err != nil {
+		return nil, err
+	}
+	var vs []string
+	for _, mi := range mis {
+		if mi.ModulePath == um.ModulePath {
+			vs = append(vs, mi.Version)
+		}
+	}
+	sort.Slice(vs, func(i, j int) bool { return semver.Compare(vs[i], vs[j]) > 0 })
+	if len(vs) > maxSymbolHistoryVersions {
+		vs = vs[:maxSymbolHistoryVersions]
+	}
+	sh, err := db.GetSymbolsAtVersions(ctx, um.Path, um.ModulePath, vs)
+	if err != nil {
+		return nil, err
+	}
+	// Keep the versions that have the symbol, oldest first.
+	var present []string
+	for i := len(vs) - 1; i >= 0; i-- {
+		if _, ok := sh.SymbolsAtVersion(vs[i])[name]; ok {
+			present = append(present, vs[i])
+		}
+	}
+	if len(present) == 0 {
+		return nil, &serrors.ServerError{
+			Status: http.StatusNotFound,
+			Epage: &page.ErrorPage{
+				MessageTemplate: template.MakeTrustedTemplate(
+					`<h3 class="Error-message">{{.Symbol}} is not in any recent version of {{.Path}}.</h3>`),
+				MessageData: struct{ Symbol, Path string }{name, um.Path},
+			},
+		}
+	}
+	docs, err := symbolDocsAtVersions(ctx, ds, um, present, bc, defaultBC)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &SymbolHistoryDetails{Symbol: name}
+	if hist, err := db.GetSymbolHistory(ctx, um.Path, um.ModulePath); err == nil {
+		for _, v := range hist.Versions() {
+			if _, ok := hist.SymbolsAtVersion(v)[name]; ok {
+				details.Introduced = LinkVersion(um.ModulePath, v, v)
+				break
+			}
+		}
+	}
+	var (
+		prev      *SymbolHistoryEntry
+		entryVers []string
+	)
+	for i, v := range present {
+		sm, builds := preferredSymbol(sh.SymbolsAtVersion(v)[name])
+		e := &SymbolHistoryEntry{
+			Version:  LinkVersion(um.ModulePath, v, v),
+			Link:     symbolLink(ConstructUnitURL(um.Path, um.ModulePath, v), name, builds),
+			Synopsis: sm.Synopsis,
+			Doc:      docs[i][name],
+		}
+		if prev != nil {
+			e.SynopsisChanged = e.Synopsis != prev.Synopsis
+			e.DocChanged = e.Doc != prev.Doc
+			if !e.SynopsisChanged && !e.DocChanged {
+				continue
+			}
+		}
+		details.Entries = append(details.Entries, e)
+		entryVers = append(entryVers, v)
+		prev = e
+	}
+	// The current entry is the latest one at or before the page's version.
+	for i, v := range entryVers {
+		if semver.Compare(v, um.Version) <= 0 {
+			details.Current = i
+		}
+	}
+	return details, nil
+}
+
+// symbolDocsAtVersions returns the symbol docs of the package um at each of
+// the given versions of its module. It decodes the documentation of several
+// versions at once, and caches the results.
+func symbolDocsAtVersions(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, vs []string, bc, defaultBC internal.BuildContext) ([]map[string]string, error) {
+	docs := make([]map[string]string, len(vs))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(symbolDocsConcurrency)
+	for i, v := range vs {
+		key := symbolDocsKey{path: um.Path, version: v, build: bc, defaultBuild: defaultBC}
+		if d, ok := symbolDocsCache.Get(key); ok {
+			docs[i] = d
+			continue
+		}
+		g.Go(func() error {
+			d, err := symbolDocs(ctx, ds, um, v, bc, defaultBC)
+			if err != nil {
+				return err
+			}
+			symbolDocsCache.Put(key, d)
+			docs[i] = d
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// symbolDocs decodes the documentation of the package um at version v and
+// returns its symbol docs.
+func symbolDocs(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, v string, bc, defaultBC internal.BuildContext) (map[string]string, error) {
+	vum := *um
+	vum.Version = v
+	u, err := internal.GetUnitPreferring(ctx, ds, &vum, internal.WithMain, bc, defaultBC)
+	if err != nil {
+		return nil, err
+	}
+	if len(u.Documentation) == 0 {
+		// The package has no documentation for bc at this version.
+		return nil, nil
+	}
+	// Symbol docs don't come from test files, so don't decode them.
+	docPkg, err := godoc.DecodePackageFiles(u.Documentation[0].Source, func(f *godoc.File) bool {
+		return !strings.HasSuffix(f.Name, "_test.go")
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s@%s: %v", um.Path, v, err)
+	}
+	var innerPath string
+	if um.ModulePath == stdlib.ModulePath {
+		innerPath = um.Path
+	} else if um.Path != um.ModulePath {
+		innerPath = strings.TrimPrefix(um.Path, um.ModulePath+"/")
+	}
+	return docPkg.SymbolDocs(innerPath, &godoc.ModuleInfo{ModulePath: um.ModulePath, ResolvedVersion: v})
+}
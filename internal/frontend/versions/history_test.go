@@ -0,0 +1,94 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package versions
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestFetchSymbolHistoryDetails(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+
+	const modulePath = "example.com/history"
+	insert := func(version, synopsis, doc string) *internal.Module {
+		m := sample.Module(modulePath, version, "pkg")
+		d := sample.Documentation(internal.All, internal.All, "package pkg\n\n// "+doc+"\nfunc Function() error { return nil }\n")
+		function := *sample.Function
+		function.Synopsis = synopsis
+		d.API = []*internal.Symbol{sample.Constant, &function}
+		m.Packages()[0].Documentation = []*internal.Documentation{d}
+		fds.MustInsertModule(ctx, m)
+		return m
+	}
+	insert("v1.0.0", "func Function() error", "Function does things.")
+	m := insert("v1.1.0", "func Function() error", "Function does things.")
+	insert("v1.2.0", "func Function() error", "Function does more things.")
+	insert("v1.3.0", "func Function(ctx context.Context) error", "Function does more things.")
+
+	um := &m.Packages()[0].UnitMeta
+	got, err := FetchSymbolHistoryDetails(ctx, fds, um, "Function", internal.BuildContext{}, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	link := func(v string) string { return "/" + modulePath + "@" + v + "/pkg#Function" }
+	want := &SymbolHistoryDetails{
+		Symbol: "Function",
+		Entries: []*SymbolHistoryEntry{
+			{
+				Version:  "v1.0.0",
+				Link:     link("v1.0.0"),
+				Synopsis: "func Function() error",
+				Doc:      "Function does things.",
+			},
+			{
+				Version:    "v1.2.0",
+				Link:       link("v1.2.0"),
+				Synopsis:   "func Function() error",
+				Doc:        "Function does more things.",
+				DocChanged: true,
+			},
+			{
+				Version:         "v1.3.0",
+				Link:            link("v1.3.0"),
+				Synopsis:        "func Function(ctx context.Context) error",
+				Doc:             "Function does more things.",
+				SynopsisChanged: true,
+			},
+		},
+		Current: 0,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+
+	// The docs are cached.
+	if _, ok := symbolDocsCache.Get(symbolDocsKey{path: um.Path, version: "v1.2.0"}); !ok {
+		t.Error("docs for v1.2.0 not cached")
+	}
+
+	for _, test := range []struct {
+		symbol     string
+		wantStatus int
+	}{
+		{"", http.StatusBadRequest},
+		{"Missing", http.StatusNotFound},
+	} {
+		_, err := FetchSymbolHistoryDetails(ctx, fds, um, test.symbol, internal.BuildContext{}, internal.BuildContext{})
+		var serr *serrors.ServerError
+		if !errors.As(err, &serr) || serr.Status != test.wantStatus {
+			t.Errorf("FetchSymbolHistoryDetails(%q): got %v, want status %d", test.symbol, err, test.wantStatus)
+		}
+	}
+}
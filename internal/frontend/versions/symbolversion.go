@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package versions
+
+import (
+	"context"
+	"sort"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/pkgsite/internal"
+)
+
+// maxSymbolVersionCandidates is the number of versions nearest to a requested
+// one that NearestSymbolVersion searches for a symbol.
+const maxSymbolVersionCandidates = 30
+
+// NearestSymbolVersion returns the version of the module of um nearest to
+// um.Version at which the package um.Path has the symbol named name, and a
+// link to the symbol in the documentation at that version. Of two versions
+// at the same distance, the newer is preferred.
+//
+// It returns empty strings if the package has the symbol at um.Version, since
+// there is nothing to redirect to, or if none of the versions searched has
+// it, or if the symbols of the module are not available yet.
+func NearestSymbolVersion(ctx context.Context, db internal.PostgresDB, um *internal.UnitMeta, name string) (version, link string, err error) {
+	ready, err := db.GetReadyFeatures(ctx, um.ModulePath)
+	if err != nil {
+		return "", "", err
+	}
+	if !ready[internal.FeatureSymbolHistory] {
+		return "", "", nil
+	}
+	mis, err := db.GetVersionsForPath(ctx, um.Path)
+	if err != nil {
+		return "", "", err
+	}
+	var vs []string
+	for _, mi := range mis {
+		if mi.ModulePath == um.ModulePath && mi.Version != um.Version {
+			vs = append(vs, mi.Version)
+		}
+	}
+	candidates := nearestVersions(vs, um.Version, maxSymbolVersionCandidates)
+	sh, err := db.GetSymbolsAtVersions(ctx, um.Path, um.ModulePath, append(candidates, um.Version))
+	if err != nil {
+		return "", "", err
+	}
+	if _, ok := sh.SymbolsAtVersion(um.Version)[name]; ok {
+		return "", "", nil
+	}
+	for _, v := range candidates {
+		if metas, ok := sh.SymbolsAtVersion(v)[name]; ok {
+			_, builds := preferredSymbol(metas)
+			return v, symbolLink(ConstructUnitURL(um.Path, um.ModulePath, v), name, builds), nil
+		}
+	}
+	return "", "", nil
+}
+
+// nearestVersions returns at most n of the versions vs, ordered by their
+// distance from v in semver order, with the newer of two versions at the same
+// distance first. The versions in vs must differ from v.
+func nearestVersions(vs []string, v string, n int) []string {
+	vs = append([]string(nil), vs...)
+	sort.Slice(vs, func(i, j int) bool { return semver.Compare(vs[i], vs[j]) < 0 })
+	// vs[:i] are older than v, and vs[i:] are newer.
+	i := sort.Search(len(vs), func(i int) bool { return semver.Compare(vs[i], v) > 0 })
+	var near []string
+	for older, newer := i-1, i; len(near) < n && (older >= 0 || newer < len(vs)); {
+		if newer < len(vs) {
+			near = append(near, vs[newer])
+			newer++
+		}
+		if older >= 0 && len(near) < n {
+			near = append(near, vs[older])
+			older--
+		}
+	}
+	return near
+}
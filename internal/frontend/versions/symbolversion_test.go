@@ -0,0 +1,81 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package versions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestNearestVersions(t *testing.T) {
+	vs := []string{"v1.3.0", "v1.0.0", "v1.4.0", "v1.1.0", "v2.0.0"}
+	for _, test := range []struct {
+		v    string
+		n    int
+		want []string
+	}{
+		{"v1.2.0", 10, []string{"v1.3.0", "v1.1.0", "v1.4.0", "v1.0.0", "v2.0.0"}},
+		{"v1.2.0", 3, []string{"v1.3.0", "v1.1.0", "v1.4.0"}},
+		{"v0.1.0", 2, []string{"v1.0.0", "v1.1.0"}},
+		{"v3.0.0", 2, []string{"v2.0.0", "v1.4.0"}},
+	} {
+		got := nearestVersions(vs, test.v, test.n)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("nearestVersions(%q, %d) mismatch (-want +got):\n%s", test.v, test.n, diff)
+		}
+	}
+}
+
+func TestNearestSymbolVersion(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+
+	const modulePath = "example.com/renamed"
+	insert := func(version string, api ...*internal.Symbol) *internal.UnitMeta {
+		m := sample.Module(modulePath, version, "pkg")
+		d := sample.Documentation(internal.All, internal.All, sample.DocContents)
+		d.API = api
+		m.Packages()[0].Documentation = []*internal.Documentation{d}
+		fds.MustInsertModule(ctx, m)
+		return &m.Packages()[0].UnitMeta
+	}
+	renamed := *sample.Function
+	renamed.Name = "Renamed"
+	v1 := insert("v1.0.0", sample.Constant)
+	insert("v1.1.0", sample.Constant, sample.Function)
+	v12 := insert("v1.2.0", sample.Constant, sample.Function)
+	v13 := insert("v1.3.0", sample.Constant, &renamed)
+
+	for _, test := range []struct {
+		um          *internal.UnitMeta
+		name        string
+		wantVersion string
+		wantLink    string
+	}{
+		// Not yet added: the nearest newer version.
+		{v1, sample.Function.Name, "v1.1.0", "/example.com/renamed@v1.1.0/pkg#Function"},
+		// Renamed: the nearest older version.
+		{v13, sample.Function.Name, "v1.2.0", "/example.com/renamed@v1.2.0/pkg#Function"},
+		{v12, renamed.Name, "v1.3.0", "/example.com/renamed@v1.3.0/pkg#Renamed"},
+		// Present at the version.
+		{v12, sample.Function.Name, "", ""},
+		// In no version.
+		{v12, "Missing", "", ""},
+	} {
+		gotVersion, gotLink, err := NearestSymbolVersion(ctx, fds, test.um, test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotVersion != test.wantVersion || gotLink != test.wantLink {
+			t.Errorf("%s, %s: got (%q, %q), want (%q, %q)", test.um.Version, test.name,
+				gotVersion, gotLink, test.wantVersion, test.wantLink)
+		}
+	}
+}
@@ -8,16 +8,19 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 	"unicode"
 
+	"golang.org/x/mod/module"
 	"golang.org/x/mod/semver"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/fetch"
 	"golang.org/x/pkgsite/internal/frontend/serrors"
 	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/stdlib"
 	"golang.org/x/pkgsite/internal/version"
 	"golang.org/x/pkgsite/internal/vuln"
@@ -38,6 +41,16 @@ type VersionsDetails struct {
 	// OtherModules is the slice of VersionLists with a different module path
 	// from the current package.
 	OtherModules []string
+
+	// DiffVersions are the versions of ThisModule that can be selected to
+	// compare the API of the package. It is empty if the unit is not a
+	// package, or has fewer than two such versions.
+	DiffVersions []string
+
+	// Filtering describes the form for filtering the versions of
+	// ThisModule and IncompatibleModules. It is nil if the module has too
+	// few versions to need it, and no filter was requested.
+	Filtering *FilterDetails
 }
 
 // VersionListKey identifies a version list on the versions tab. We have a
@@ -85,9 +98,55 @@ type VersionSummary struct {
 	IsMinor             bool
 	Symbols             [][]*Symbol
 	Vulns               []vuln.Vuln
+	// Commit describes the commit of a pseudo-version, or is nil.
+	Commit *CommitSummary
+	// SwitchLink, if set, links to this version through the version
+	// switcher, which carries the tab and anchor of the page that the
+	// versions tab was opened from.
+	SwitchLink string
 }
 
-func FetchVersionsDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, vc *vuln.Client) (*VersionsDetails, error) {
+// CommitSummary describes the commit that a pseudo-version refers to, for
+// display.
+type CommitSummary struct {
+	// ShortHash is the hash of the commit, abbreviated as in the
+	// pseudo-version.
+	ShortHash string
+	Subject   string
+	// Date is the day the commit was authored, in the form 2006-01-02.
+	Date string
+	// URL is the commit's page on its repository's host, or empty.
+	URL string
+}
+
+// NewCommitSummary returns a summary of ci, the commit of the module version
+// v, or nil if v is not a pseudo-version or ci is nil.
+func NewCommitSummary(v string, ci *source.CommitInfo) *CommitSummary {
+	if ci == nil || !version.IsPseudo(v) {
+		return nil
+	}
+	cs := &CommitSummary{
+		ShortHash: ci.Hash,
+		Subject:   ci.Subject,
+	}
+	if rev, err := module.PseudoVersionRev(v); err == nil && strings.HasPrefix(ci.Hash, rev) {
+		cs.ShortHash = rev
+	}
+	if !ci.AuthorTime.IsZero() {
+		cs.Date = ci.AuthorTime.In(time.UTC).Format("2006-01-02")
+	}
+	if strings.HasPrefix(ci.URL, "https://") {
+		cs.URL = ci.URL
+	}
+	return cs
+}
+
+// FetchVersionsDetails returns the versions tab of the unit um, with the
+// versions of its module that f selects. If switchLink is non-nil, it sets
+// the SwitchLink of each version to switchLink of the path, module path and
+// version of the unit at that version.
+func FetchVersionsDetails(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, f Filter, vc *vuln.Client,
+	switchLink func(unitPath, modulePath, version string) string) (*VersionsDetails, error) {
 	db, ok := ds.(internal.PostgresDB)
 	if !ok {
 		// The proxydatasource does not support the imported by page.
@@ -98,25 +157,48 @@ func FetchVersionsDetails(ctx context.Context, ds internal.DataSource, um *inter
 		return nil, err
 	}
 
+	ready, err := db.GetReadyFeatures(ctx, um.ModulePath)
+	if err != nil {
+		return nil, err
+	}
+	// Without a backfilled symbol history, the symbols of older versions
+	// would be missing, so list none.
 	sh := internal.NewSymbolHistory()
-	if !um.IsCommand() {
+	if !um.IsCommand() && ready[internal.FeatureSymbolHistory] {
 		sh, err = db.GetSymbolHistory(ctx, um.Path, um.ModulePath)
 		if err != nil {
 			return nil, err
 		}
 	}
 	linkify := func(mi *internal.ModuleInfo) string {
-		// Here we have only version information, but need to construct the full
-		// import path of the package corresponding to this version.
-		var versionPath string
-		if mi.ModulePath == stdlib.ModulePath {
-			versionPath = um.Path
-		} else {
-			versionPath = pathInVersion(internal.V1Path(um.Path, um.ModulePath), mi)
-		}
+		versionPath := unitPathInVersion(um.Path, um.ModulePath, mi)
 		return ConstructUnitURL(versionPath, mi.ModulePath, LinkVersion(mi.ModulePath, mi.Version, mi.Version))
 	}
-	return buildVersionDetails(ctx, um.ModulePath, um.Path, versions, sh, linkify, vc)
+	var switchLinkify func(mi *internal.ModuleInfo) string
+	if switchLink != nil {
+		switchLinkify = func(mi *internal.ModuleInfo) string {
+			return switchLink(unitPathInVersion(um.Path, um.ModulePath, mi), mi.ModulePath, mi.Version)
+		}
+	}
+	vd, err := buildVersionDetails(ctx, um.ModulePath, um.Path, versions, sh, f, linkify, switchLinkify, vc)
+	if err != nil {
+		return nil, err
+	}
+	if um.IsPackage() && !um.IsCommand() && ready[internal.FeatureAPIDiff] {
+		vd.DiffVersions = diffVersions(um.ModulePath, vd.ThisModule)
+	}
+	return vd, nil
+}
+
+// unitPathInVersion returns the path of the unit at unitPath, in the module
+// at modulePath, in the module version mi. Here we have only version
+// information, but need to construct the full import path of the package
+// corresponding to this version.
+func unitPathInVersion(unitPath, modulePath string, mi *internal.ModuleInfo) string {
+	if mi.ModulePath == stdlib.ModulePath {
+		return unitPath
+	}
+	return pathInVersion(internal.V1Path(unitPath, modulePath), mi)
 }
 
 // pathInVersion constructs the full import path of the package corresponding
@@ -144,10 +226,13 @@ func pathInVersion(v1Path string, mi *internal.ModuleInfo) string {
 // versions tab, organizing major versions into those that have the same module
 // path as the package version under consideration, and those that don't.  The
 // given versions MUST be sorted first by module path and then by semver.
+// Versions with the current module path that f does not select are left out.
+// If switchLinkify is non-nil, it returns the SwitchLink of each version.
 func buildVersionDetails(ctx context.Context, currentModulePath, packagePath string,
 	modInfos []*internal.ModuleInfo,
 	sh *internal.SymbolHistory,
-	linkify func(v *internal.ModuleInfo) string,
+	f Filter,
+	linkify, switchLinkify func(v *internal.ModuleInfo) string,
 	vc *vuln.Client,
 ) (*VersionsDetails, error) {
 	// lists organizes versions by VersionListKey.
@@ -155,6 +240,7 @@ func buildVersionDetails(ctx context.Context, currentModulePath, packagePath str
 	// seenLists tracks the order in which we encounter entries of each version
 	// list. We want to preserve this order.
 	var seenLists []VersionListKey
+	filtering := &FilterDetails{Filter: f}
 	for _, mi := range modInfos {
 		// Try to resolve the most appropriate major version for this version. If
 		// we detect a +incompatible version (when the path version does not match
@@ -178,6 +264,16 @@ func buildVersionDetails(ctx context.Context, currentModulePath, packagePath str
 		} else if major != "v0" && !strings.HasPrefix(major, "go") {
 			major = "v1"
 		}
+		if mi.ModulePath == currentModulePath {
+			filtering.NumVersions++
+			if !slices.Contains(filtering.Majors, major) {
+				filtering.Majors = append(filtering.Majors, major)
+			}
+			if !f.matches(mi, major) {
+				continue
+			}
+			filtering.NumShown++
+		}
 		key := VersionListKey{
 			ModulePath:   mi.ModulePath,
 			Major:        major,
@@ -194,6 +290,10 @@ func buildVersionDetails(ctx context.Context, currentModulePath, packagePath str
 			IsMinor:             isMinor(mi.Version),
 			Retracted:           mi.Retracted,
 			RetractionRationale: shortRationale(mi.RetractionRationale),
+			Commit:              NewCommitSummary(mi.Version, mi.Commit),
+		}
+		if switchLinkify != nil {
+			vs.SwitchLink = switchLinkify(mi)
 		}
 		if sv := sh.SymbolsAtVersion(mi.Version); sv != nil {
 			vs.Symbols = symbolsForVersion(linkify(mi), sv)
@@ -247,6 +347,9 @@ func buildVersionDetails(ctx context.Context, currentModulePath, packagePath str
 		details.ThisModule[0].Versions[0].Version == "master" {
 		details.ThisModule[0].Versions = details.ThisModule[0].Versions[:1]
 	}
+	if filtering.NumVersions >= minVersionsForFilter || !f.IsZero() {
+		details.Filtering = filtering
+	}
 	return &details, nil
 }
 
@@ -283,6 +386,11 @@ func formatVersion(v string) string {
 	if len(v) <= maxLen {
 		return v
 	}
+	if version.IsIncompatible(v) {
+		// Shorten the rest of the version, but keep the suffix so that it is
+		// always clear that the version is incompatible.
+		return formatVersion(version.TrimIncompatible(v)) + "+incompatible"
+	}
 	vType, err := version.ParseType(v)
 	if err != nil {
 		log.Errorf(context.TODO(), "formatVersion(%q): error parsing version: %v", v, err)
@@ -366,7 +474,7 @@ func pseudoVersionBase(v string) string {
 // from a pseudo version string. It assumes the pseudo version is correctly
 // formatted.
 func pseudoVersionRev(v string) string {
-	v = strings.TrimSuffix(v, "+incompatible")
+	v = version.TrimIncompatible(v)
 	j := strings.LastIndex(v, "-")
 	return v[j+1:]
 }
@@ -7,10 +7,12 @@ package versions
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/osv"
+	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/stdlib"
 	"golang.org/x/pkgsite/internal/testing/fakedatasource"
 	"golang.org/x/pkgsite/internal/testing/sample"
@@ -157,6 +159,7 @@ func TestFetchPackageVersionsDetails(t *testing.T) {
 						stdlib, compatible,
 					),
 				},
+				DiffVersions: []string{"go1.22rc1", "go1.21.0", "go1.20", "go1.12.5", "go1.11.6"},
 			},
 		},
 		{
@@ -187,6 +190,7 @@ func TestFetchPackageVersionsDetails(t *testing.T) {
 					makeList(v1Path, modulePath1, "v2", []string{"v2.1.0+incompatible"}, notStdlib, incompatible),
 				},
 				OtherModules: []string{"test.com", modulePath2},
+				DiffVersions: []string{"v1.3.0", "v1.2.3", "v1.2.1"},
 			},
 		},
 		{
@@ -205,6 +209,7 @@ func TestFetchPackageVersionsDetails(t *testing.T) {
 					makeList(v2Path, modulePath2, "v2", []string{"v2.2.1-alpha.1", "v2.0.0"}, notStdlib, compatible),
 				},
 				OtherModules: []string{modulePath1},
+				DiffVersions: []string{"v2.2.1-alpha.1", "v2.0.0"},
 			},
 		},
 		{
@@ -227,7 +232,7 @@ func TestFetchPackageVersionsDetails(t *testing.T) {
 				fds.MustInsertModule(ctx, v)
 			}
 
-			got, err := FetchVersionsDetails(ctx, fds, &tc.pkg.UnitMeta, vc)
+			got, err := FetchVersionsDetails(ctx, fds, &tc.pkg.UnitMeta, Filter{}, vc, nil)
 			if err != nil {
 				t.Fatalf("FetchVersionsDetails(ctx, db, %q, %q): %v", tc.pkg.Path, tc.pkg.ModulePath, err)
 			}
@@ -276,6 +281,9 @@ func TestFormatVersion(t *testing.T) {
 		{"v1.0.0-longprereleasestring", "v1.0.0-longprereleases..."},
 		{"v1.0.0-pre-release.0.20200420093620-87861123c523", "v1.0.0-pre-rele...-8786112"},
 		{"v0.0.0-20190101-123456789012", "v0.0.0-20190101-123456..."}, // prelease version that looks like pseudoversion
+		{"v2.0.0+incompatible", "v2.0.0+incompatible"},
+		{"v2.0.0-20190311183353-d8887717615a+incompatible", "v2.0.0-...-d888771+incompatible"},
+		{"v2.0.0-longprereleasestring+incompatible", "v2.0.0-longprereleases...+incompatible"},
 	}
 
 	for _, test := range tests {
@@ -305,6 +313,45 @@ func TestPseudoVersionBase(t *testing.T) {
 	}
 }
 
+func TestNewCommitSummary(t *testing.T) {
+	ci := &source.CommitInfo{
+		Hash:       "d8887717615a0123456789abcdef0123456789ab",
+		Subject:    "Fix race in pool",
+		AuthorTime: time.Date(2019, 3, 11, 18, 33, 53, 0, time.UTC),
+		URL:        "https://github.com/owner/repo/commit/d8887717615a0123456789abcdef0123456789ab",
+	}
+	for _, test := range []struct {
+		name    string
+		version string
+		ci      *source.CommitInfo
+		want    *CommitSummary
+	}{
+		{
+			"pseudo", "v1.2.4-0.20190311183353-d8887717615a", ci,
+			&CommitSummary{
+				ShortHash: "d8887717615a",
+				Subject:   "Fix race in pool",
+				Date:      "2019-03-11",
+				URL:       "https://github.com/owner/repo/commit/d8887717615a0123456789abcdef0123456789ab",
+			},
+		},
+		{
+			"insecure URL", "v0.0.0-20190311183353-d8887717615a",
+			&source.CommitInfo{Hash: ci.Hash, Subject: ci.Subject, URL: "http://example.com/c"},
+			&CommitSummary{ShortHash: "d8887717615a", Subject: "Fix race in pool"},
+		},
+		{"release", "v1.2.3", ci, nil},
+		{"unknown commit", "v0.0.0-20190311183353-d8887717615a", nil, nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := NewCommitSummary(test.version, test.ci)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestIsMinor(t *testing.T) {
 	for _, test := range []struct {
 		version string
@@ -0,0 +1,148 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/cookie"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/frontend/serrors"
+	"golang.org/x/pkgsite/internal/frontend/versions"
+)
+
+// maxAnchorLen is the length of the longest anchor that the version switcher
+// carries.
+const maxAnchorLen = 200
+
+// versionSwitch is what the version switcher carries from the page that the
+// versions tab was opened from to the page of another version: the tab of
+// that page, and its URL fragment if the tab is the main one.
+type versionSwitch struct {
+	tab    string
+	anchor string
+}
+
+// versionSwitchFromRequest returns the versionSwitch in the "from" and
+// "anchor" query params of r, and whether there is one. Tabs that are not
+// about the unit at a single version, like the versions tab itself, are not
+// carried.
+func versionSwitchFromRequest(r *http.Request) (versionSwitch, bool) {
+	vs := versionSwitch{tab: r.FormValue("from"), anchor: r.FormValue("anchor")}
+	switch vs.tab {
+	case tabMain:
+		if len(vs.anchor) > maxAnchorLen {
+			vs.anchor = ""
+		}
+	case tabImports, tabImportedBy, tabLicenses, tabSource:
+		vs.anchor = ""
+	default:
+		return versionSwitch{}, false
+	}
+	return vs, vs != versionSwitch{}
+}
+
+// versionSwitchURL returns the URL of the version switcher that leads from
+// the page described by vs to the unit at path in the module at modulePath,
+// at version.
+func versionSwitchURL(path, modulePath, version string, vs versionSwitch) string {
+	q := url.Values{
+		"path":    {path},
+		"module":  {modulePath},
+		"version": {version},
+	}
+	if vs.tab != tabMain {
+		q.Set("from", vs.tab)
+	}
+	if vs.anchor != "" {
+		q.Set("anchor", vs.anchor)
+	}
+	return "/switch-version?" + q.Encode()
+}
+
+// serveSwitchVersion redirects to the page of the unit at the "path" query
+// param, in the module at the "module" query param, at the "version" query
+// param, with the tab and anchor in the "from" and "anchor" query params.
+// The versions tab links to it when it is opened from a tab or anchor that
+// the page of another version should keep.
+//
+// A tab that the unit does not have at the version is replaced by the main
+// one. A symbol anchor is dropped if the stored API of the package shows
+// that it does not have the symbol at the version, and a banner on the page
+// says so.
+func (s *Server) serveSwitchVersion(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveSwitchVersion(%q)", r.URL.RawQuery)
+
+	pkgPath, modulePath, version := r.FormValue("path"), r.FormValue("module"), r.FormValue("version")
+	if pkgPath == "" || modulePath == "" || version == "" {
+		return &serrors.ServerError{Status: http.StatusBadRequest}
+	}
+	vs, _ := versionSwitchFromRequest(r)
+	ctx := r.Context()
+	um, err := ds.GetUnitMeta(ctx, pkgPath, modulePath, version)
+	if errors.Is(err, derrors.NotFound) {
+		return &serrors.ServerError{Status: http.StatusNotFound}
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkModuleAvailable(ctx, ds, um.Path, um.ModulePath, um.Version); err != nil {
+		return err
+	}
+	u := &url.URL{Path: versions.ConstructUnitURL(um.Path, um.ModulePath, um.Version)}
+	if !um.IsPackage() && (vs.tab == tabImports || vs.tab == tabImportedBy || vs.tab == tabSource) {
+		vs.tab = tabMain
+	}
+	if vs.tab != tabMain {
+		u.RawQuery = url.Values{"tab": {vs.tab}}.Encode()
+	}
+	if vs.anchor != "" {
+		ok, err := hasAnchor(ctx, ds, um, vs.anchor)
+		if err != nil {
+			return err
+		}
+		if ok {
+			u.Fragment = vs.anchor
+		} else {
+			cookie.Set(w, cookie.MissingSymbolFlash, vs.anchor, u.Path)
+		}
+	}
+	http.Redirect(w, r, u.String(), http.StatusFound)
+	return nil
+}
+
+// hasAnchor reports whether the documentation of um may have the anchor. Only
+// symbol anchors can be checked, against the symbols stored for um; other
+// anchors, and symbols of modules whose symbols are not available, are
+// assumed to be there.
+func hasAnchor(ctx context.Context, ds internal.DataSource, um *internal.UnitMeta, anchor string) (bool, error) {
+	if !symbolNameRx.MatchString(anchor) {
+		return true, nil
+	}
+	if !um.IsPackage() {
+		return false, nil
+	}
+	db, ok := ds.(internal.PostgresDB)
+	if !ok {
+		return true, nil
+	}
+	ready, err := db.GetReadyFeatures(ctx, um.ModulePath)
+	if err != nil {
+		return false, err
+	}
+	if !ready[internal.FeatureSymbolHistory] {
+		return true, nil
+	}
+	sh, err := db.GetSymbolsAtVersions(ctx, um.Path, um.ModulePath, []string{um.Version})
+	if err != nil {
+		return false, err
+	}
+	_, ok = sh.SymbolsAtVersion(um.Version)[anchor]
+	return ok, nil
+}
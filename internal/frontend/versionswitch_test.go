@@ -0,0 +1,156 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"html"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/fakedatasource"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestVersionSwitchFromRequest(t *testing.T) {
+	for _, test := range []struct {
+		query  string
+		want   versionSwitch
+		wantOK bool
+	}{
+		{"", versionSwitch{}, false},
+		{"from=&anchor=Function", versionSwitch{anchor: "Function"}, true},
+		{"anchor=T.M", versionSwitch{anchor: "T.M"}, true},
+		{"from=imports", versionSwitch{tab: tabImports}, true},
+		{"from=imports&anchor=Function", versionSwitch{tab: tabImports}, true},
+		{"from=versions&anchor=Function", versionSwitch{}, false},
+		{"from=bogus", versionSwitch{}, false},
+		{"anchor=" + strings.Repeat("a", maxAnchorLen+1), versionSwitch{}, false},
+	} {
+		r := httptest.NewRequest("GET", "/example.com/mod?tab=versions&"+test.query, nil)
+		got, ok := versionSwitchFromRequest(r)
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("%q: got %+v, %t; want %+v, %t", test.query, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestServeSwitchVersion(t *testing.T) {
+	ctx := context.Background()
+	fds := fakedatasource.New()
+	for _, v := range []string{"v1.0.0", "v1.1.0"} {
+		m := sample.Module("example.com/mod", v, "pkg")
+		d := sample.Documentation(internal.All, internal.All, sample.DocContents)
+		d.API = []*internal.Symbol{sample.Constant}
+		if v == "v1.1.0" {
+			d.API = append(d.API, sample.Function)
+		}
+		m.Packages()[0].Documentation = []*internal.Documentation{d}
+		fds.MustInsertModule(ctx, m)
+	}
+	_, mux := newTestServer(t, nil, ServerConfig{
+		DataSourceGetter: func(context.Context) internal.DataSource { return fds },
+	})
+
+	get := func(target string, cookies ...*http.Cookie) *http.Response {
+		r := httptest.NewRequest("GET", target, nil)
+		for _, c := range cookies {
+			r.AddCookie(c)
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w.Result()
+	}
+	switchURL := func(path, version string, vs versionSwitch) string {
+		return versionSwitchURL(path, "example.com/mod", version, vs)
+	}
+
+	for _, test := range []struct {
+		target       string
+		wantStatus   int
+		wantLocation string
+	}{
+		{
+			switchURL("example.com/mod/pkg", "v1.1.0", versionSwitch{anchor: sample.Function.Name}),
+			http.StatusFound,
+			"/example.com/mod@v1.1.0/pkg#Function",
+		},
+		{
+			switchURL("example.com/mod/pkg", "v1.0.0", versionSwitch{anchor: "section-readme"}),
+			http.StatusFound,
+			"/example.com/mod@v1.0.0/pkg#section-readme",
+		},
+		{
+			switchURL("example.com/mod/pkg", "v1.0.0", versionSwitch{tab: tabImports}),
+			http.StatusFound,
+			"/example.com/mod@v1.0.0/pkg?tab=imports",
+		},
+		{
+			// The module root is not a package.
+			switchURL("example.com/mod", "v1.0.0", versionSwitch{tab: tabImports}),
+			http.StatusFound,
+			"/example.com/mod@v1.0.0",
+		},
+		{
+			switchURL("example.com/mod/pkg", "v1.2.0", versionSwitch{anchor: sample.Function.Name}),
+			http.StatusNotFound,
+			"",
+		},
+		{
+			"/switch-version?path=example.com/mod/pkg",
+			http.StatusBadRequest,
+			"",
+		},
+	} {
+		res := get(test.target)
+		if res.StatusCode != test.wantStatus {
+			t.Errorf("%s: status = %d, want %d", test.target, res.StatusCode, test.wantStatus)
+			continue
+		}
+		if got := res.Header.Get("Location"); got != test.wantLocation {
+			t.Errorf("%s: Location = %q, want %q", test.target, got, test.wantLocation)
+		}
+		if cookies := res.Cookies(); len(cookies) != 0 {
+			t.Errorf("%s: got cookies %v, want none", test.target, cookies)
+		}
+	}
+
+	// The symbol is not in v1.0.0, so the anchor is dropped, and the page
+	// says so.
+	res := get(switchURL("example.com/mod/pkg", "v1.0.0", versionSwitch{anchor: sample.Function.Name}))
+	if res.StatusCode != http.StatusFound {
+		t.Fatalf("status = %d, want %d", res.StatusCode, http.StatusFound)
+	}
+	if got, want := res.Header.Get("Location"), "/example.com/mod@v1.0.0/pkg"; got != want {
+		t.Errorf("Location = %q, want %q", got, want)
+	}
+	cookies := res.Cookies()
+	if len(cookies) != 1 || cookies[0].Path != "/example.com/mod@v1.0.0/pkg" {
+		t.Fatalf("got cookies %v, want one for the page switched to", cookies)
+	}
+	b, err := io.ReadAll(get("/example.com/mod@v1.0.0/pkg", cookies...).Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Function is not in version v1.0.0."; !strings.Contains(string(b), want) {
+		t.Errorf("page does not contain %q", want)
+	}
+
+	// The versions tab links through the version switcher when it carries an
+	// anchor.
+	b, err = io.ReadAll(get("/example.com/mod/pkg?tab=versions&" + url.Values{"anchor": {"Function"}}.Encode()).Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := html.EscapeString(switchURL("example.com/mod/pkg", "v1.0.0", versionSwitch{anchor: "Function"}))
+	if !strings.Contains(string(b), want) {
+		t.Errorf("versions tab does not contain %q", want)
+	}
+}
@@ -93,6 +93,10 @@ func newVulnPage(ctx context.Context, url *url.URL, vc *vuln.Client) (*vulnPage,
 			title:    "Vulnerability Reports"}, nil
 	default: // the path should be "/<ID>", e.g. "/GO-2021-0001".
 		id, ok := vuln.CanonicalGoID(strings.TrimPrefix(path, "/"))
+		if !ok {
+			// It may be the ID of an advisory for a private module.
+			id, ok = vuln.CanonicalPrivateID(strings.TrimPrefix(path, "/"))
+		}
 		if !ok {
 			if url.Query().Has("q") {
 				return nil, derrors.NotFound
@@ -323,3 +323,44 @@ func init() {
 			return x
 		})
 }
+
+func TestSections(t *testing.T) {
+	want := []any{"bar", nil, uint64(1 << 63), []byte("Luke Luck likes lakes"), true}
+	data, err := EncodeSections(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSectionDecoder(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Len(); got != len(want) {
+		t.Fatalf("Len() = %d, want %d", got, len(want))
+	}
+	// Decode the sections in reverse order, to check that they are
+	// independent of one another.
+	for i := len(want) - 1; i >= 0; i-- {
+		got, err := s.Decode(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !cmp.Equal(got, want[i]) {
+			t.Errorf("section %d: got %v, want %v", i, got, want[i])
+		}
+	}
+	_, err = s.Decode(len(want))
+	checkMessage(t, err, "out of range")
+
+	// Claim that the first section is longer than all the data.
+	e := NewEncoder()
+	e.StartList(1)
+	e.EncodeUint(uint64(len(data) + 1))
+	_, err = NewSectionDecoder(append(e.buf, data...))
+	checkMessage(t, err, "past end")
+
+	// Claim more sections than there are bytes.
+	e = NewEncoder()
+	e.StartList(1 << 40)
+	_, err = NewSectionDecoder(e.buf)
+	checkMessage(t, err, "longer than data")
+}
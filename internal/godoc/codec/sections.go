@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package codec
+
+import "fmt"
+
+// EncodeSections encodes each of xs with its own Encoder, and returns the
+// encodings preceded by an index of their lengths. Because each value is
+// encoded separately, a SectionDecoder can decode any of them without
+// decoding the others. The price is that values in different sections cannot
+// share struct pointers.
+//
+// The index is encoded as a list of uints, one for the length of each
+// section, and the sections follow it in order.
+func EncodeSections(xs []any) (_ []byte, err error) {
+	var sections [][]byte
+	for _, x := range xs {
+		e := NewEncoder()
+		if err := e.Encode(x); err != nil {
+			return nil, err
+		}
+		sections = append(sections, e.Bytes())
+	}
+	defer handlePanic(&err)
+	index := NewEncoder()
+	index.StartList(len(sections))
+	for _, s := range sections {
+		index.EncodeUint(uint64(len(s)))
+	}
+	data := index.buf
+	for _, s := range sections {
+		data = append(data, s...)
+	}
+	return data, nil
+}
+
+// A SectionDecoder decodes the values encoded by EncodeSections.
+// Its methods may be called concurrently.
+type SectionDecoder struct {
+	data   []byte
+	starts []int // offsets of the sections in data, plus len(data)
+}
+
+// NewSectionDecoder reads the index at the start of data, which must have
+// been returned by EncodeSections. It does not decode any values.
+func NewSectionDecoder(data []byte) (_ *SectionDecoder, err error) {
+	defer handlePanic(&err)
+	d := NewDecoder(data)
+	n := d.StartList()
+	if n < 0 {
+		return nil, fmt.Errorf("codec.NewSectionDecoder: missing index")
+	}
+	// Each length takes at least one byte, so a count larger than the rest of
+	// the data is corrupt. Check before allocating.
+	if n > len(data)-d.i {
+		return nil, fmt.Errorf("codec.NewSectionDecoder: index of %d sections is longer than data", n)
+	}
+	lens := make([]uint64, n)
+	for i := range lens {
+		lens[i] = d.DecodeUint()
+	}
+	starts := make([]int, n+1)
+	starts[0] = d.i
+	for i, l := range lens {
+		if l > uint64(len(data)-starts[i]) {
+			return nil, fmt.Errorf("codec.NewSectionDecoder: section %d extends past end of data", i)
+		}
+		starts[i+1] = starts[i] + int(l)
+	}
+	return &SectionDecoder{data: data, starts: starts}, nil
+}
+
+// Len returns the number of sections.
+func (s *SectionDecoder) Len() int {
+	return len(s.starts) - 1
+}
+
+// Decode decodes the value in section i.
+func (s *SectionDecoder) Decode(i int) (any, error) {
+	if i < 0 || i >= s.Len() {
+		return nil, fmt.Errorf("codec.SectionDecoder.Decode: section %d out of range [0, %d)", i, s.Len())
+	}
+	return NewDecoder(s.data[s.starts[i]:s.starts[i+1]]).Decode()
+}
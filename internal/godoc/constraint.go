@@ -0,0 +1,122 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"path"
+	"strings"
+)
+
+// FileBuildConstraint returns the build constraint of the Go file named
+// filename with contents src, as for File.BuildConstraint. If src cannot be
+// parsed, only the constraint implied by the file name is returned.
+func FileBuildConstraint(filename string, src []byte) string {
+	f, err := parser.ParseFile(token.NewFileSet(), filename, src, parser.PackageClauseOnly|parser.ParseComments)
+	if err != nil {
+		if x := nameConstraint(filename); x != nil {
+			return x.String()
+		}
+		return ""
+	}
+	return buildConstraint(filename, f)
+}
+
+// buildConstraint returns the build constraint of the file f named filename,
+// as a boolean expression of build tags, or "" if the file has none.
+//
+// The constraint combines the GOOS and GOARCH implied by the file name, such
+// as "linux && amd64" for x_linux_amd64.go, with the file's //go:build line,
+// or its // +build lines if it has no //go:build line.
+func buildConstraint(filename string, f *ast.File) string {
+	x := nameConstraint(filename)
+	if y := commentConstraint(f); y != nil {
+		if x == nil {
+			x = y
+		} else {
+			x = &constraint.AndExpr{X: x, Y: y}
+		}
+	}
+	if x == nil {
+		return ""
+	}
+	return x.String()
+}
+
+// commentConstraint returns the constraint in the comments before the
+// package clause of f, or nil if there is none.
+func commentConstraint(f *ast.File) constraint.Expr {
+	var plusBuild constraint.Expr
+	for _, cg := range f.Comments {
+		if cg.Pos() >= f.Package {
+			break
+		}
+		for _, c := range cg.List {
+			if constraint.IsGoBuild(c.Text) {
+				x, err := constraint.Parse(c.Text)
+				if err != nil {
+					return nil
+				}
+				return x
+			}
+			if constraint.IsPlusBuild(c.Text) {
+				x, err := constraint.Parse(c.Text)
+				if err != nil {
+					continue
+				}
+				if plusBuild == nil {
+					plusBuild = x
+				} else {
+					plusBuild = &constraint.AndExpr{X: plusBuild, Y: x}
+				}
+			}
+		}
+	}
+	return plusBuild
+}
+
+// nameConstraint returns the constraint implied by the GOOS and GOARCH
+// suffixes of filename, or nil if there are none. It follows the rules of
+// the go/build package.
+func nameConstraint(filename string) constraint.Expr {
+	name, _, _ := strings.Cut(path.Base(filename), ".")
+	// The part of the name before the first underscore is never a suffix.
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return nil
+	}
+	l := strings.Split(name[i:], "_")
+	if n := len(l); n > 0 && l[n-1] == "test" {
+		l = l[:n-1]
+	}
+	n := len(l)
+	switch {
+	case n >= 2 && knownOS[l[n-2]] && knownArch[l[n-1]]:
+		return &constraint.AndExpr{X: &constraint.TagExpr{Tag: l[n-2]}, Y: &constraint.TagExpr{Tag: l[n-1]}}
+	case n >= 1 && (knownOS[l[n-1]] || knownArch[l[n-1]]):
+		return &constraint.TagExpr{Tag: l[n-1]}
+	}
+	return nil
+}
+
+// knownOS and knownArch are the GOOS and GOARCH values recognized in file
+// names, from go/build.
+var knownOS = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true,
+	"freebsd": true, "hurd": true, "illumos": true, "ios": true, "js": true,
+	"linux": true, "nacl": true, "netbsd": true, "openbsd": true, "plan9": true,
+	"solaris": true, "wasip1": true, "windows": true, "zos": true,
+}
+
+var knownArch = map[string]bool{
+	"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true,
+	"arm64": true, "arm64be": true, "loong64": true, "mips": true, "mipsle": true,
+	"mips64": true, "mips64le": true, "mips64p32": true, "mips64p32le": true,
+	"ppc": true, "ppc64": true, "ppc64le": true, "riscv": true, "riscv64": true,
+	"s390": true, "s390x": true, "sparc": true, "sparc64": true, "wasm": true,
+}
@@ -0,0 +1,96 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"go/ast"
+	"go/token"
+	"path"
+
+	"golang.org/x/pkgsite/internal"
+)
+
+// A Decl is an exported top-level declaration in a source file.
+type Decl struct {
+	// Name is the name of the declaration. The names of methods are
+	// qualified by their receiver type, as in "T.M".
+	Name string
+	Kind internal.SymbolKind
+	// Line is the line of the name in the file.
+	Line int
+}
+
+// FileDecls returns the exported top-level declarations of each file of the
+// package, in source order, keyed by the base name of the file. Methods are
+// included if their receiver type is exported.
+//
+// FileDecls must be called before the package is rendered, because rendering
+// modifies the AST.
+func (p *Package) FileDecls() (map[string][]*Decl, error) {
+	if err := p.decodeFiles(); err != nil {
+		return nil, err
+	}
+	m := map[string][]*Decl{}
+	for _, f := range p.Files {
+		var decls []*Decl
+		add := func(id *ast.Ident, name string, kind internal.SymbolKind) {
+			decls = append(decls, &Decl{Name: name, Kind: kind, Line: p.Fset.Position(id.Pos()).Line})
+		}
+		for _, d := range f.AST.Decls {
+			switch d := d.(type) {
+			case *ast.FuncDecl:
+				if !d.Name.IsExported() {
+					continue
+				}
+				if d.Recv == nil || len(d.Recv.List) == 0 {
+					add(d.Name, d.Name.Name, internal.SymbolKindFunction)
+					continue
+				}
+				if recv := receiverTypeName(d.Recv.List[0].Type); ast.IsExported(recv) {
+					add(d.Name, recv+"."+d.Name.Name, internal.SymbolKindMethod)
+				}
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						if spec.Name.IsExported() {
+							add(spec.Name, spec.Name.Name, internal.SymbolKindType)
+						}
+					case *ast.ValueSpec:
+						kind := internal.SymbolKindVariable
+						if d.Tok == token.CONST {
+							kind = internal.SymbolKindConstant
+						}
+						for _, n := range spec.Names {
+							if n.IsExported() {
+								add(n, n.Name, kind)
+							}
+						}
+					}
+				}
+			}
+		}
+		m[path.Base(f.Name)] = decls
+	}
+	return m, nil
+}
+
+// receiverTypeName returns the name of the type of a method receiver, without
+// a pointer or type parameters.
+func receiverTypeName(x ast.Expr) string {
+	switch x := x.(type) {
+	case *ast.Ident:
+		return x.Name
+	case *ast.StarExpr:
+		return receiverTypeName(x.X)
+	case *ast.IndexExpr:
+		return receiverTypeName(x.X)
+	case *ast.IndexListExpr:
+		return receiverTypeName(x.X)
+	case *ast.ParenExpr:
+		return receiverTypeName(x.X)
+	}
+	return ""
+}
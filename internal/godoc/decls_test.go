@@ -0,0 +1,88 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestFileDecls(t *testing.T) {
+	const (
+		a = `package p
+
+const (
+	C1 = 1
+	c2 = 2
+)
+
+var V, w int
+
+type T struct{ F int }
+
+type t int
+
+func F() {}
+
+func f() {}
+
+func (T) M() {}
+
+func (*T) m() {}
+
+func (t) N() {}
+`
+		b = `package p
+
+type G[K comparable, V any] map[K]V
+
+func (g *G[K, V]) Get(k K) V { return g[k] }
+`
+	)
+	fset := token.NewFileSet()
+	p := NewPackage(fset, nil)
+	for _, f := range []struct{ name, src string }{{"a.go", a}, {"b_linux.go", b}} {
+		af, err := parser.ParseFile(fset, f.name, f.src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.AddFile(af, true)
+	}
+	// Check the declarations of the encoded package, which is what the
+	// frontend has.
+	data, err := p.Encode(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2, err := DecodePackage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := p2.FileDecls()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]*Decl{
+		"a.go": {
+			{Name: "C1", Kind: internal.SymbolKindConstant, Line: 4},
+			{Name: "V", Kind: internal.SymbolKindVariable, Line: 8},
+			{Name: "T", Kind: internal.SymbolKindType, Line: 10},
+			{Name: "F", Kind: internal.SymbolKindFunction, Line: 14},
+			{Name: "T.M", Kind: internal.SymbolKindMethod, Line: 18},
+		},
+		"b_linux.go": {
+			{Name: "G", Kind: internal.SymbolKindType, Line: 3},
+			{Name: "G.Get", Kind: internal.SymbolKindMethod, Line: 5},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -26,10 +26,13 @@ import (
 	"github.com/google/safehtml/legacyconversions"
 	"github.com/google/safehtml/template"
 	"github.com/google/safehtml/uncheckedconversions"
+	"golang.org/x/mod/semver"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/godoc/dochtml/internal/render"
 	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/stdlib"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -47,6 +50,19 @@ type ModuleInfo struct {
 	ResolvedVersion string
 	// ModulePackages is the set of all full package paths in the module.
 	ModulePackages map[string]bool
+	// GoVersion is the version in the go directive of the module's go.mod
+	// file, such as "1.21". If set, links to standard library packages go to
+	// the documentation for that release of Go rather than the latest one.
+	GoVersion string
+	// NoIssueLinks reports whether the module's authors have turned off
+	// linking references to issues, such as "#1234", in documentation.
+	NoIssueLinks bool
+	// LicenseTypes are the types of the licenses that apply to the package,
+	// such as "MIT".
+	LicenseTypes []string
+	// BrokenDocLinks are the doc links of the package to symbols that do not
+	// exist, which are displayed as text.
+	BrokenDocLinks []*internal.BrokenDocLink
 }
 
 // RenderOptions are options for Render.
@@ -60,11 +76,31 @@ type RenderOptions struct {
 	FileLinkFunc     func(file string) (url string)
 	SourceLinkFunc   func(ast.Node) string
 	SinceVersionFunc func(name string) string
+	// SinceVersionURLFunc optionally returns a URL that explains the
+	// version returned by SinceVersionFunc, such as the release notes of a
+	// Go release. The version is linked to it.
+	SinceVersionURLFunc func(name string) string
+	// IsGeneratedFunc optionally reports whether a declaration is in a
+	// generated file.
+	IsGeneratedFunc func(ast.Node) bool
+	// FileLicensesFunc optionally returns the license types declared by the
+	// file of a declaration, if they differ from those of the module.
+	FileLicensesFunc func(ast.Node) []string
+	// IssueURLFunc optionally returns a URL for the issue or pull request
+	// with the given number. If set, references such as "#1234" in doc
+	// comments are linked to it.
+	IssueURLFunc func(number string) string
 	// ModInfo optionally specifies information about the module the package
 	// belongs to in order to render module-related documentation.
 	ModInfo      *ModuleInfo
 	Limit        int64 // If zero, a default limit of 10 megabytes is used.
 	BuildContext internal.BuildContext
+	// Concurrent reports whether to render the outlines concurrently with
+	// the body.
+	Concurrent bool
+	// ExpandExamples reports whether to render the examples expanded, for
+	// browsers that cannot expand them with JavaScript.
+	ExpandExamples bool
 }
 
 // TemplateData holds the data passed to the HTML templates in this package.
@@ -74,6 +110,7 @@ type TemplateData struct {
 	Consts, Vars, Funcs, Types []*item
 	Examples                   *examples
 	NoteHeaders                map[string]noteHeader
+	HasGenerated               bool // some item is from a generated file
 }
 
 // Parts contains HTML for each part of the documentation.
@@ -112,6 +149,9 @@ func Render(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Render
 		html, err = executeToHTMLWithLimit(t, data, opt.Limit)
 		return html
 	}
+	if opt.Concurrent {
+		return renderConcurrently(funcs, data, links, opt.Limit)
+	}
 
 	parts := &Parts{
 		Body:          exec(bodyTemplate),
@@ -127,6 +167,37 @@ func Render(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Render
 	return parts, nil
 }
 
+// renderConcurrently executes the body and outline templates at the same
+// time. That is safe because the outline templates only read the parts of
+// the AST that rendering the body leaves alone.
+func renderConcurrently(funcs template.FuncMap, data TemplateData, links func() []render.Link, limit int64) (*Parts, error) {
+	exec := func(tmpl *template.Template, html *safehtml.HTML) func() error {
+		t := template.Must(tmpl.Clone()).Funcs(funcs)
+		return func() error {
+			var err error
+			*html, err = executeToHTMLWithLimit(t, data, limit)
+			return err
+		}
+	}
+
+	parts := &Parts{}
+	var g errgroup.Group
+	g.Go(exec(outlineTemplate, &parts.Outline))
+	g.Go(exec(sidenavTemplate, &parts.MobileOutline))
+	bodyErr := exec(bodyTemplate, &parts.Body)()
+	outlineErr := g.Wait()
+	if bodyErr != nil {
+		return nil, bodyErr
+	}
+	if outlineErr != nil {
+		return nil, outlineErr
+	}
+	// links must be called after body, because the call to
+	// render_doc_extract_links in body.tmpl creates the links.
+	parts.Links = links()
+	return parts, nil
+}
+
 // An item is rendered as one piece of documentation. It is essentially a union
 // of the Value, Type and Func types from internal/doc, along with additional
 // information for HTML rendering, like class names.
@@ -138,7 +209,10 @@ type item struct {
 	HeaderStart                  string     // text of header, before source link
 	Examples                     []*example // for types and functions; empty for vars and consts
 	IsDeprecated                 bool
-	Consts, Vars, Funcs, Methods []*item // for types
+	IsGenerated                  bool         // declared in a generated file
+	Licenses                     []string     // of the declaring file, if different from the module's
+	Consts, Vars, Funcs, Methods []*item      // for types
+	Promoted                     []*promotion // for struct types, fields and methods of embedded types
 	// HTML-specific values, for types and functions
 	Kind        string // for data-kind attribute
 	HeaderClass string // class for header
@@ -151,6 +225,7 @@ func packageToItems(p *doc.Package, exmap map[string][]*example) (consts, vars,
 	for _, t := range p.Types {
 		types = append(types, typeToItem(t, exmap))
 	}
+	addPromotions(p, types)
 	return consts, vars, funcs, types
 }
 
@@ -217,6 +292,36 @@ func typeToItem(t *doc.Type, exmap map[string][]*example) *item {
 	}
 }
 
+// markGenerated sets IsGenerated on each item, and on the items of each
+// type, whose declaration isGenerated reports is in a generated file.
+// It reports whether it marked any item.
+func markGenerated(items []*item, isGenerated func(ast.Node) bool) bool {
+	found := false
+	for _, it := range items {
+		if isGenerated(it.Decl) {
+			it.IsGenerated = true
+			found = true
+		}
+		for _, sub := range [][]*item{it.Consts, it.Vars, it.Funcs, it.Methods} {
+			if markGenerated(sub, isGenerated) {
+				found = true
+			}
+		}
+	}
+	return found
+}
+
+// markLicenses sets Licenses on each item, and on the items of each type, to
+// the license types that fileLicenses returns for its declaration.
+func markLicenses(items []*item, fileLicenses func(ast.Node) []string) {
+	for _, it := range items {
+		it.Licenses = fileLicenses(it.Decl)
+		for _, sub := range [][]*item{it.Consts, it.Vars, it.Funcs, it.Methods} {
+			markLicenses(sub, fileLicenses)
+		}
+	}
+}
+
 func docIsEmpty(p *doc.Package) bool {
 	return p.Doc == "" &&
 		len(p.Examples) == 0 &&
@@ -236,14 +341,14 @@ func renderInfo(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Re
 	p = &p2
 
 	// When rendering documentation for commands, display
-	// the package comment and notes, but no declarations.
+	// the package comment, package examples and notes, but no declarations.
+	// Examples of declarations go with them.
 	if p.Name == "main" {
 		// Clear top-level declarations.
 		p.Consts = nil
 		p.Types = nil
 		p.Vars = nil
 		p.Funcs = nil
-		p.Examples = nil
 	}
 
 	// Remove everything from the notes section that is not a bug. This
@@ -269,6 +374,8 @@ func renderInfo(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Re
 			}
 			return "/" + versionedPath + search
 		},
+		IssueURL:        opt.IssueURLFunc,
+		IsBrokenDocLink: isBrokenDocLinkFunc(p, opt.ModInfo),
 	})
 
 	fileLink := func(name string) safehtml.HTML {
@@ -280,6 +387,12 @@ func renderInfo(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Re
 	sinceVersion := func(name string) safehtml.HTML {
 		return safehtml.HTMLEscaped(opt.SinceVersionFunc(name))
 	}
+	sinceVersionURL := func(name string) string {
+		if opt.SinceVersionURLFunc == nil {
+			return ""
+		}
+		return opt.SinceVersionURLFunc(name)
+	}
 	funcs := map[string]any{
 		"render_short_synopsis":    r.ShortSynopsis,
 		"render_synopsis":          r.Synopsis,
@@ -290,6 +403,8 @@ func renderInfo(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Re
 		"file_link":                fileLink,
 		"source_link":              sourceLink,
 		"since_version":            sinceVersion,
+		"since_version_url":        sinceVersionURL,
+		"expand_examples":          func() bool { return opt.ExpandExamples },
 	}
 	examples := collectExamples(p)
 	data := TemplateData{
@@ -299,6 +414,18 @@ func renderInfo(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Re
 		NoteHeaders: buildNoteHeaders(p.Notes),
 	}
 	data.Consts, data.Vars, data.Funcs, data.Types = packageToItems(p, examples.Map)
+	if opt.IsGeneratedFunc != nil {
+		for _, items := range [][]*item{data.Consts, data.Vars, data.Funcs, data.Types} {
+			if markGenerated(items, opt.IsGeneratedFunc) {
+				data.HasGenerated = true
+			}
+		}
+	}
+	if opt.FileLicensesFunc != nil {
+		for _, items := range [][]*item{data.Consts, data.Vars, data.Funcs, data.Types} {
+			markLicenses(items, opt.FileLicensesFunc)
+		}
+	}
 	return funcs, data, r.Links
 }
 
@@ -446,11 +573,43 @@ func buildNoteHeaders(notes map[string][]*doc.Note) map[string]noteHeader {
 	return headers
 }
 
+// isBrokenDocLinkFunc returns a function that reports whether the doc link
+// of p to the symbol recv.name of the package at importPath is one of the
+// broken links in modInfo. An empty importPath refers to p itself. It returns
+// nil if there are no broken links.
+func isBrokenDocLinkFunc(p *doc.Package, modInfo *ModuleInfo) func(importPath, recv, name string) bool {
+	if modInfo == nil || len(modInfo.BrokenDocLinks) == 0 {
+		return nil
+	}
+	type target struct{ importPath, recv, name string }
+	broken := map[target]bool{}
+	for _, l := range modInfo.BrokenDocLinks {
+		broken[target{l.ImportPath, l.Recv, l.Name}] = true
+	}
+	return func(importPath, recv, name string) bool {
+		if importPath == "" {
+			importPath = p.ImportPath
+		}
+		return broken[target{importPath, recv, name}]
+	}
+}
+
 // versionedPkgPath transforms package paths to contain the same version as the
 // current module if the package belongs to the module. As a special case,
 // versionedPkgPath will not add versions to standard library packages.
+//
+// Standard library packages imported by other modules are given the version
+// of Go in the module's go directive, if there is one.
 func versionedPkgPath(pkgPath string, modInfo *ModuleInfo) string {
-	if modInfo == nil || !modInfo.ModulePackages[pkgPath] {
+	if modInfo == nil {
+		return pkgPath
+	}
+	if !modInfo.ModulePackages[pkgPath] {
+		if modInfo.ModulePath != stdlib.ModulePath && stdlib.Contains(pkgPath) {
+			if tag := goReleaseTag(modInfo.GoVersion); tag != "" {
+				return pkgPath + "@" + tag
+			}
+		}
 		return pkgPath
 	}
 	// We don't need to do anything special here for standard library packages
@@ -459,3 +618,21 @@ func versionedPkgPath(pkgPath string, modInfo *ModuleInfo) string {
 	innerPkgPath := pkgPath[len(modInfo.ModulePath):]
 	return fmt.Sprintf("%s@%s%s", modInfo.ModulePath, modInfo.ResolvedVersion, innerPkgPath)
 }
+
+// goReleaseTag returns the tag of the Go release for goVersion, a version from
+// a go directive, or "" if it doesn't name a release. Prereleases are not
+// linked to, since their documentation may not be available.
+func goReleaseTag(goVersion string) string {
+	if goVersion == "" {
+		return ""
+	}
+	v := stdlib.VersionForTag("go" + goVersion)
+	if v == "" || semver.Prerelease(v) != "" {
+		return ""
+	}
+	tag, err := stdlib.TagForVersion(v)
+	if err != nil {
+		return ""
+	}
+	return tag
+}
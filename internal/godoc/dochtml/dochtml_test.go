@@ -23,6 +23,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/safehtml"
 	"github.com/google/safehtml/template"
 	"golang.org/x/net/html"
 	"golang.org/x/pkgsite/internal/godoc/dochtml/internal/render"
@@ -64,6 +65,9 @@ func TestRender(t *testing.T) {
 			t.Run("duplicate ids", func(t *testing.T) {
 				testDuplicateIDs(t, bodyDoc)
 			})
+			t.Run("accessibility", func(t *testing.T) {
+				testAccessibility(t, bodyDoc)
+			})
 			if pkg == "everydecl" {
 				t.Run("ids-and-kinds", func(t *testing.T) {
 					// Check that the id and data-kind labels are right.
@@ -89,6 +93,11 @@ func TestRenderDeprecated(t *testing.T) {
 		t.Fatal(err)
 	}
 	compareWithGolden(t, parts, "deprecated-on", *update)
+	bodyDoc, err := html.Parse(strings.NewReader(parts.Body.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	testAccessibility(t, bodyDoc)
 }
 
 func compareWithGolden(t *testing.T, parts *Parts, name string, update bool) {
@@ -170,7 +179,7 @@ func main() {
 1
 </span></pre>
 </div>
-<div class="Documentation-exampleButtonsContainer">
+<div class="Documentation-exampleButtonsContainer js-exampleButtons" hidden="">
         <p class="Documentation-exampleError" role="alert" aria-atomic="true"></p>
         <button class="Documentation-exampleShareButton" aria-label="Share Code">Share</button>
         <button class="Documentation-exampleFormatButton" aria-label="Format Code">Format</button>
@@ -187,6 +196,45 @@ func main() {
 	}
 }
 
+func TestExpandExamples(t *testing.T) {
+	LoadTemplates(templateFS)
+	ctx := context.Background()
+	for _, expand := range []bool{false, true} {
+		t.Run(fmt.Sprintf("expand=%t", expand), func(t *testing.T) {
+			fset, d := mustLoadPackage("example_test")
+			opts := testRenderOptions
+			opts.ExpandExamples = expand
+			parts, err := Render(ctx, fset, d, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			htmlDoc, err := html.Parse(strings.NewReader(parts.Body.String()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			count := 0
+			walk(htmlDoc, func(n *html.Node) {
+				if n.Data != "details" || attr(n, "class") != "Documentation-exampleDetails js-exampleContainer" {
+					return
+				}
+				count++
+				open := false
+				for _, a := range n.Attr {
+					if a.Key == "open" {
+						open = true
+					}
+				}
+				if open != expand {
+					t.Errorf("%s: open = %t, want %t", attr(n, "id"), open, expand)
+				}
+			})
+			if count == 0 {
+				t.Error("no examples")
+			}
+		})
+	}
+}
+
 func TestLinkHTML(t *testing.T) {
 	for _, test := range []struct {
 		name string
@@ -272,6 +320,49 @@ func TestVersionedPkgPath(t *testing.T) {
 			},
 			want: "golang.org/x/pkgsite",
 		},
+		{
+			name:    "std imports use the go directive version",
+			pkgPath: "net/http",
+			modInfo: &ModuleInfo{
+				ModulePath:      "cloud.google.com/go",
+				ResolvedVersion: "v0.60.0",
+				ModulePackages:  map[string]bool{"cloud.google.com/go/civil": true},
+				GoVersion:       "1.21",
+			},
+			want: "net/http@go1.21.0",
+		},
+		{
+			name:    "std imports use the go directive version before go1.21",
+			pkgPath: "net/http",
+			modInfo: &ModuleInfo{
+				ModulePath:      "cloud.google.com/go",
+				ResolvedVersion: "v0.60.0",
+				ModulePackages:  map[string]bool{"cloud.google.com/go/civil": true},
+				GoVersion:       "1.16",
+			},
+			want: "net/http@go1.16",
+		},
+		{
+			name:    "std imports are not versioned for prerelease go directives",
+			pkgPath: "net/http",
+			modInfo: &ModuleInfo{
+				ModulePath:      "cloud.google.com/go",
+				ResolvedVersion: "v0.60.0",
+				ModulePackages:  map[string]bool{"cloud.google.com/go/civil": true},
+				GoVersion:       "1.22rc1",
+			},
+			want: "net/http",
+		},
+		{
+			name:    "std imports are not versioned without a go directive",
+			pkgPath: "net/http",
+			modInfo: &ModuleInfo{
+				ModulePath:      "cloud.google.com/go",
+				ResolvedVersion: "v0.60.0",
+				ModulePackages:  map[string]bool{"cloud.google.com/go/civil": true},
+			},
+			want: "net/http",
+		},
 		{
 			name:    "imports from other modules with shared prefixes are not versioned",
 			pkgPath: "golang.org/x/pkgsite",
@@ -352,6 +443,127 @@ func TestVersionedPkgPath(t *testing.T) {
 	}
 }
 
+func TestRenderGenerated(t *testing.T) {
+	LoadTemplates(templateFS)
+	ctx := context.Background()
+	fset, d := mustLoadPackage("everydecl")
+
+	countGenerated := func(parts *Parts) int {
+		n := 0
+		for _, h := range []safehtml.HTML{parts.Body, parts.Outline, parts.MobileOutline} {
+			n += strings.Count(h.String(), "Documentation-generated")
+		}
+		return n
+	}
+
+	parts, err := Render(ctx, fset, d, testRenderOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := countGenerated(parts); n != 0 {
+		t.Errorf("without IsGeneratedFunc: got %d generated markers, want 0", n)
+	}
+
+	// Treat every function and method as generated.
+	opts := testRenderOptions
+	opts.IsGeneratedFunc = func(n ast.Node) bool {
+		_, ok := n.(*ast.FuncDecl)
+		return ok
+	}
+	parts, err = Render(ctx, fset, d, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := countGenerated(parts); n == 0 {
+		t.Error("with IsGeneratedFunc: got no generated markers")
+	}
+	if !strings.Contains(parts.Body.String(), "js-generatedToggle") {
+		t.Error("with IsGeneratedFunc: body has no toggle")
+	}
+}
+
+func TestRenderFileLicenses(t *testing.T) {
+	LoadTemplates(templateFS)
+	ctx := context.Background()
+	fset, d := mustLoadPackage("everydecl")
+
+	opts := testRenderOptions
+	opts.FileLicensesFunc = func(n ast.Node) []string {
+		if _, ok := n.(*ast.FuncDecl); ok {
+			return []string{"Apache-2.0", "MIT"}
+		}
+		return nil
+	}
+	parts, err := Render(ctx, fset, d, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := parts.Body.String()
+	if !strings.Contains(body, "licensed under Apache-2.0, MIT,") {
+		t.Errorf("body has no license note:\n%s", body)
+	}
+	if got, want := strings.Count(body, "Documentation-licenseNote"), len(d.Funcs); got < want {
+		t.Errorf("got %d license notes, want at least %d", got, want)
+	}
+}
+
+func TestRenderCommand(t *testing.T) {
+	LoadTemplates(templateFS)
+	ctx := context.Background()
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for name, src := range map[string]string{
+		"main.go": `
+// Lint finds problems in Go code.
+//
+// Usage:
+//
+//	lint [packages]
+package main
+
+func Check() {}
+
+func main() {}
+`,
+		"main_test.go": `
+package main
+
+func Example() {
+	main()
+}
+
+func ExampleCheck() {
+	Check()
+}
+`,
+	} {
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, f)
+	}
+	d, err := doc.NewFromFiles(fset, files, "example.com/module/cmd/lint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts, err := Render(ctx, fset, d, testRenderOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := parts.Body.String()
+	for _, want := range []string{"Lint finds problems in Go code.", "lint [packages]", `id="example-package"`} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body does not contain %q", want)
+		}
+	}
+	for _, notWant := range []string{"func Check", "example-Check"} {
+		if strings.Contains(body, notWant) {
+			t.Errorf("body contains %q", notWant)
+		}
+	}
+}
+
 func TestTooLarge(t *testing.T) {
 	tmpl := template.Must(template.New("").Parse(`{{.}}`))
 	_, err := executeToHTMLWithLimit(tmpl, "a little too large", 10)
@@ -380,6 +592,61 @@ func testDuplicateIDs(t *testing.T, htmlDoc *html.Node) {
 	}
 }
 
+// testAccessibility checks the structure that screen readers and keyboard
+// users depend on: headings don't skip levels, sections are labeled by a
+// heading in the document, and every skip link leads somewhere.
+func testAccessibility(t *testing.T, htmlDoc *html.Node) {
+	ids := map[string]bool{}
+	walk(htmlDoc, func(n *html.Node) {
+		if id := attr(n, "id"); id != "" {
+			ids[id] = true
+		}
+	})
+	// The documentation appears below the h2 of the unit page's
+	// Documentation section.
+	level := 2
+	var skipLinks int
+	walk(htmlDoc, func(n *html.Node) {
+		if n.Type != html.ElementNode {
+			return
+		}
+		switch n.Data {
+		case "h1", "h2", "h3", "h4", "h5", "h6":
+			l := int(n.Data[1] - '0')
+			if l > level+1 {
+				t.Errorf("<%s id=%q> follows an h%d", n.Data, attr(n, "id"), level)
+			}
+			level = l
+		case "section":
+			label := attr(n, "aria-labelledby")
+			if label == "" {
+				t.Errorf("<section class=%q> has no aria-labelledby", attr(n, "class"))
+			} else if !ids[label] {
+				t.Errorf("<section class=%q> is labeled by missing id %q", attr(n, "class"), label)
+			}
+		case "nav":
+			if attr(n, "aria-label") == "" {
+				t.Errorf("<nav class=%q> has no aria-label", attr(n, "class"))
+			}
+			if attr(n, "class") != "Documentation-skipLinks" {
+				return
+			}
+			walk(n, func(a *html.Node) {
+				if a.Type != html.ElementNode || a.Data != "a" {
+					return
+				}
+				skipLinks++
+				if href := attr(a, "href"); !ids[strings.TrimPrefix(href, "#")] {
+					t.Errorf("skip link to %q has no target", href)
+				}
+			})
+		}
+	})
+	if skipLinks == 0 {
+		t.Error("no skip links")
+	}
+}
+
 func testIDsAndKinds(t *testing.T, htmlDoc *html.Node) {
 	type attrs struct {
 		ID, Kind string // export fields for cmp
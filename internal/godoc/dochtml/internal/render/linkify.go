@@ -24,6 +24,7 @@ import (
 	"github.com/google/safehtml/legacyconversions"
 	"github.com/google/safehtml/template"
 	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/source"
 )
 
 /*
@@ -63,6 +64,9 @@ type link struct {
 type heading struct {
 	ID    safe.Identifier // if empty, the title is not linked
 	Title safe.HTML
+	// Sub reports whether the heading is in the documentation of a
+	// declaration, whose header is an h4, and so is rendered one level lower.
+	Sub bool
 }
 
 var (
@@ -90,8 +94,12 @@ var (
 
 	// expects a heading
 	headingTemplate = template.Must(template.New("heading").Parse(`
-	  {{- if .ID.String -}}
+	  {{- if and .ID.String .Sub -}}
+		<h5 id="{{.ID}}">{{.Title}} <a class="Documentation-idLink" href="#{{.ID}}" title="Go to {{.Title}}" aria-label="Go to {{.Title}}">¶</a></h5>
+	  {{- else if .ID.String -}}
 		<h4 id="{{.ID}}">{{.Title}} <a class="Documentation-idLink" href="#{{.ID}}" title="Go to {{.Title}}" aria-label="Go to {{.Title}}">¶</a></h4>
+	  {{- else if .Sub -}}
+	    <h5>{{.Title}}</h5>
 	  {{- else -}}
 	    <h4>{{.Title}}</h4>
 	  {{- end}}`))
@@ -124,7 +132,11 @@ func (r *Renderer) formatDocHTML(text string, decl ast.Decl, extractLinks bool)
 	if extractLinks {
 		r.removeLinks(doc)
 	}
+	if r.issueURL != nil {
+		r.linkIssues(doc.Content)
+	}
 	hscope := newHeadingScope(headingIDSuffix(decl))
+	hscope.sub = decl != nil
 	h := r.blocksToHTML(doc.Content, true, hscope)
 	if len(hscope.headings) > 0 {
 		h = safe.HTMLConcat(ExecuteToHTML(tocTemplate, hscope.headings), h)
@@ -132,6 +144,59 @@ func (r *Renderer) formatDocHTML(text string, decl ast.Decl, extractLinks bool)
 	return h
 }
 
+// linkIssues turns references to issues and pull requests in the paragraphs
+// and lists of blocks into links. Headings are left alone, and so is text
+// that is already a link.
+func (r *Renderer) linkIssues(blocks []comment.Block) {
+	for _, b := range blocks {
+		switch b := b.(type) {
+		case *comment.Paragraph:
+			b.Text = r.linkIssuesInText(b.Text)
+		case *comment.List:
+			for _, item := range b.Items {
+				r.linkIssues(item.Content)
+			}
+		}
+	}
+}
+
+func (r *Renderer) linkIssuesInText(texts []comment.Text) []comment.Text {
+	var out []comment.Text
+	for _, t := range texts {
+		if p, ok := t.(comment.Plain); ok {
+			out = append(out, r.linkIssuesInPlain(string(p))...)
+		} else {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// linkIssuesInPlain splits s into plain text and links to the issues it
+// references.
+func (r *Renderer) linkIssuesInPlain(s string) []comment.Text {
+	var out []comment.Text
+	last := 0
+	for _, ref := range source.FindIssueReferences(s) {
+		url := r.issueURL(ref.Number)
+		if url == "" {
+			continue
+		}
+		if ref.Start > last {
+			out = append(out, comment.Plain(s[last:ref.Start]))
+		}
+		out = append(out, &comment.Link{
+			Text: []comment.Text{comment.Plain(s[ref.Start:ref.End])},
+			URL:  url,
+		})
+		last = ref.End
+	}
+	if last < len(s) {
+		out = append(out, comment.Plain(s[last:]))
+	}
+	return out
+}
+
 // removeLinks removes the "Links" section from doc.
 // Pkgsite has a convention where a "Links" heading in a doc comment provides links
 // that are rendered in a separate place in the UI.
@@ -311,13 +376,14 @@ This approach will always produce unique, stable IDs when it produces IDs at all
 type headingScope struct {
 	suffix    string         // ID suffix; unique for the package; empty for package doc
 	createIDs bool           // create IDs for the headings
+	sub       bool           // headings are below a declaration's header
 	headings  []heading      // headings for this scope, in order
 	ids       map[string]int // count of seen IDs
 }
 
 func newHeadingScope(suffix string, createIDs bool) *headingScope {
 	return &headingScope{
-		suffix:    badAnchorRx.ReplaceAllString(suffix, "_"),
+		suffix:    sanitizeID(suffix),
 		createIDs: createIDs,
 		ids:       map[string]int{},
 	}
@@ -330,23 +396,38 @@ func (r *Renderer) newHeading(h *comment.Heading, hs *headingScope) heading {
 
 // addHeading constructs a heading and adds it to hs.
 func (hs *headingScope) addHeading(ch *comment.Heading, html safe.HTML) heading {
-	h := heading{Title: html}
+	h := heading{Title: html, Sub: hs.sub}
 	if hs.createIDs {
-		h.ID = safe.IdentifierFromConstantPrefix("hdr", hs.newHeadingID(ch))
+		// The ID may contain non-ASCII letters and digits, which
+		// safe.IdentifierFromConstantPrefix rejects. The "hdr-" prefix still
+		// guards against DOM clobbering.
+		h.ID = legacyconversions.RiskilyAssumeIdentifier("hdr-" + hs.newHeadingID(ch))
 	}
 	hs.headings = append(hs.headings, h)
 	return h
 }
 
-var badAnchorRx = regexp.MustCompile(`[^a-zA-Z0-9-]`)
+// sanitizeID returns s with every rune that is not a letter, digit or
+// underscore replaced by an underscore.
+//
+// Unlike [comment.Heading.DefaultID], it keeps non-ASCII letters and digits,
+// so that IDs for non-ASCII identifiers and headings are distinct. For ASCII
+// text the two agree, keeping existing links working.
+func sanitizeID(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, s)
+}
 
 // newHeadingID constructs a unique heading ID from the argument, which is the text
 // of a heading.
 func (hs *headingScope) newHeadingID(ch *comment.Heading) string {
-	// Start with the heading's default ID.
-	// We can only construct a [safe.Identifier] by adding a prefix, so remove the
-	// default's prefix.
-	id := strings.TrimPrefix(ch.DefaultID(), "hdr-")
+	// Start with the heading's text, as [comment.Heading.DefaultID] does, but
+	// without its prefix; addHeading adds it.
+	id := sanitizeID(strings.TrimSpace(textsToString(ch.Text)))
 	if hs.suffix != "" {
 		id += "-" + hs.suffix
 	}
@@ -418,6 +499,9 @@ func (r *Renderer) textToHTML(t comment.Text) safe.HTML {
 	case *comment.Link:
 		return ExecuteToHTML(linkTemplate, link{"", t.URL, r.textsToHTML(t.Text)})
 	case *comment.DocLink:
+		if r.isBrokenDocLink != nil && r.isBrokenDocLink(t.ImportPath, t.Recv, t.Name) {
+			return r.textsToHTML(t.Text)
+		}
 		url := r.docLinkURL(t)
 		return ExecuteToHTML(linkTemplate, link{"", url, r.textsToHTML(t.Text)})
 	default:
@@ -20,6 +20,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/safehtml"
 	safe "github.com/google/safehtml"
+	"github.com/google/safehtml/legacyconversions"
 	"github.com/google/safehtml/testconversions"
 	"golang.org/x/tools/txtar"
 )
@@ -178,6 +179,34 @@ func TestDeclHTML(t *testing.T) {
 	}
 }
 
+func TestDeclHTMLUnicode(t *testing.T) {
+	pkg, fset := mustLoadPackage("unicode")
+	for _, test := range []struct {
+		symbol string
+		want   string
+	}{
+		{
+			symbol: "Δέλτα",
+			want: `type Δέλτα struct {
+<span id="Δέλτα.Πεδίο" data-kind="field">	Πεδίο <a href="/builtin#int">int</a>
+</span>}`,
+		},
+		{
+			symbol: "X関数",
+			want:   `func X関数(d <a href="#%ce%94%ce%ad%ce%bb%cf%84%ce%b1">Δέλτα</a>)`,
+		},
+	} {
+		t.Run(test.symbol, func(t *testing.T) {
+			decl := declForName(t, pkg, test.symbol)
+			r := New(context.Background(), fset, pkg, nil)
+			got := r.DeclHTML("", decl).Decl.String()
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
 func declForName(t *testing.T, pkg *doc.Package, symbol string) ast.Decl {
 
 	inVals := func(vals []*doc.Value) ast.Decl {
@@ -514,6 +543,32 @@ More text.`
 	}
 }
 
+func TestIssueLinks(t *testing.T) {
+	doc := `
+Package p fixes #123 (see also GH-45).
+
+  - Workaround for #678.
+
+Not a reference: step #1, C#12, https://example.com/#90.
+
+	code #123`
+
+	want := `<p>Package p fixes <a href="https://github.com/a/b/issues/123">#123</a> (see also <a href="https://github.com/a/b/issues/45">GH-45</a>).
+</p><ul class="Documentation-bulletList">
+  <li>Workaround for <a href="https://github.com/a/b/issues/678">#678</a>.</li>
+</ul><p>Not a reference: step #1, C#12, <a href="https://example.com/#90">https://example.com/#90</a>.
+</p><pre>code #123
+</pre>`
+
+	r := New(context.Background(), nil, pkgTime, &Options{
+		IssueURL: func(number string) string { return "https://github.com/a/b/issues/" + number },
+	})
+	got := r.formatDocHTML(doc, nil, false).String()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got)\n%s", diff)
+	}
+}
+
 func TestHeadingIDSuffix(t *testing.T) {
 	for _, test := range []struct {
 		decl    string
@@ -568,7 +623,7 @@ func TestAddHeading(t *testing.T) {
 		t.Helper()
 		var want []heading
 		for _, id := range ids {
-			want = append(want, heading{safe.IdentifierFromConstantPrefix("hdr", id), html})
+			want = append(want, heading{ID: legacyconversions.RiskilyAssumeIdentifier("hdr-" + id), Title: html})
 		}
 		if !slices.Equal(hs.headings, want) {
 			t.Errorf("\ngot  %v\nwant %v", hs.headings, want)
@@ -595,10 +650,20 @@ func TestAddHeading(t *testing.T) {
 	addHeading(hs, "h")
 	check(hs, "h", "h-1")
 
-	// Check that invalid ID characters are removed from both suffix and input.
+	// Check that invalid ID characters are removed from both suffix and input,
+	// but non-ASCII letters are kept.
 	hs = newHeadingScope("a.b𝜽", true)
 	addHeading(hs, "h.i𝜽")
-	check(hs, "h_i_-a_b_")
+	addHeading(hs, "a-b")
+	check(hs, "h_i𝜽-a_b𝜽", "a_b-a_b𝜽")
+
+	// Check that headings in different non-ASCII scopes don't collide.
+	hs = newHeadingScope("Δέλτα", true)
+	addHeading(hs, "見出し")
+	check(hs, "見出し-Δέλτα")
+	hs = newHeadingScope("型", true)
+	addHeading(hs, "見出し")
+	check(hs, "見出し-型")
 
 	// Check no link (empty ID).
 	hs = newHeadingScope("", false)
@@ -608,3 +673,19 @@ func TestAddHeading(t *testing.T) {
 		t.Errorf("got %v, want %v", hs.headings, want)
 	}
 }
+
+func TestBrokenDocLinks(t *testing.T) {
+	doc := `See [Month], [Weekday] and [io.Reader].`
+	want := `<p>See Month, <a href="#Weekday">Weekday</a> and <a href="/io#Reader">io.Reader</a>.
+</p>`
+
+	r := New(context.Background(), nil, pkgTime, &Options{
+		IsBrokenDocLink: func(importPath, recv, name string) bool {
+			return importPath == "" && recv == "" && name == "Month"
+		},
+	})
+	got := r.formatDocHTML(doc, nil, false).String()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got)\n%s", diff)
+	}
+}
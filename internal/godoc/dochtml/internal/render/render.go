@@ -29,11 +29,15 @@ type Renderer struct {
 	fset          *token.FileSet
 	pids          *packageIDs
 	packageURL    func(string) string
+	issueURL      func(string) string
 	ctx           context.Context
 	docTmpl       *template.Template
 	exampleTmpl   *template.Template
 	links         []Link // Links removed from package overview to be displayed elsewhere.
 	commentParser *comment.Parser
+
+	// isBrokenDocLink reports whether a doc link should be rendered as text.
+	isBrokenDocLink func(importPath, recv, name string) bool
 }
 
 type Options struct {
@@ -49,6 +53,21 @@ type Options struct {
 	//
 	// Only relevant for HTML formatting.
 	PackageURL func(pkgPath string) (url string)
+
+	// IssueURL optionally specifies a function that returns a URL for the
+	// issue or pull request with the given number, or the empty string if
+	// there is none. If set, references such as "#1234" in doc comments are
+	// linked to the URL.
+	//
+	// Only relevant for HTML formatting.
+	IssueURL func(number string) (url string)
+
+	// IsBrokenDocLink optionally reports whether the doc link to the symbol
+	// recv.name of the package at importPath, which is empty for the package
+	// itself, is known to be broken. Broken links are rendered as text.
+	//
+	// Only relevant for HTML formatting.
+	IsBrokenDocLink func(importPath, recv, name string) bool
 }
 
 // docDataTmpl renders documentation. It expects a docData.
@@ -85,7 +104,8 @@ var exampleTmpl = template.Must(template.New("").Parse(`
 
 func New(ctx context.Context, fset *token.FileSet, pkg *doc.Package, opts *Options) *Renderer {
 	var others []*doc.Package
-	var packageURL func(string) string
+	var packageURL, issueURL func(string) string
+	var isBrokenDocLink func(importPath, recv, name string) bool
 	if opts != nil {
 		if len(opts.RelatedPackages) > 0 {
 			others = opts.RelatedPackages
@@ -93,17 +113,21 @@ func New(ctx context.Context, fset *token.FileSet, pkg *doc.Package, opts *Optio
 		if opts.PackageURL != nil {
 			packageURL = opts.PackageURL
 		}
+		issueURL = opts.IssueURL
+		isBrokenDocLink = opts.IsBrokenDocLink
 	}
 	pids := newPackageIDs(pkg, others...)
 
 	return &Renderer{
-		fset:          fset,
-		pids:          pids,
-		packageURL:    packageURL,
-		docTmpl:       docDataTmpl,
-		exampleTmpl:   exampleTmpl,
-		ctx:           ctx,
-		commentParser: pkg.Parser(),
+		fset:            fset,
+		pids:            pids,
+		packageURL:      packageURL,
+		issueURL:        issueURL,
+		isBrokenDocLink: isBrokenDocLink,
+		docTmpl:         docDataTmpl,
+		exampleTmpl:     exampleTmpl,
+		ctx:             ctx,
+		commentParser:   pkg.Parser(),
 	}
 }
 
@@ -0,0 +1,17 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package unicode has identifiers that are not ASCII.
+package unicode
+
+// Δέλτα is a Greek type.
+type Δέλτα struct {
+	Πεδίο int
+}
+
+// Μέθοδος is a method on [Δέλτα].
+func (Δέλτα) Μέθοδος() {}
+
+// X関数 takes a [Δέλτα].
+func X関数(d Δέλτα) {}
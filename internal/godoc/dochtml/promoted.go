@@ -0,0 +1,181 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dochtml
+
+import (
+	"go/ast"
+	"go/doc"
+	"slices"
+)
+
+// A promotion holds the fields and methods that a struct type gets from one
+// of the types embedded in it, directly or through other embedded types.
+type promotion struct {
+	TypeName string   // name of the embedded type that declares the fields and methods
+	Fields   []string // names of the promoted fields
+	Methods  []*item  // the promoted methods, as items of TypeName
+}
+
+// addPromotions sets the Promoted field of each struct type item in types to
+// the fields and methods promoted from the embedded types declared in p.
+//
+// Only types declared in the package are considered, since those are the only
+// ones whose fields and methods are known here. Methods promoted from
+// unexported embedded types are already among a type's methods.
+func addPromotions(p *doc.Package, types []*item) {
+	docTypes := map[string]*doc.Type{}
+	for _, t := range p.Types {
+		docTypes[t.Name] = t
+	}
+	typeItems := map[string]*item{}
+	for _, it := range types {
+		typeItems[it.Name] = it
+	}
+	for _, it := range types {
+		if t := docTypes[it.Name]; t != nil {
+			it.Promoted = promotions(t, docTypes, typeItems)
+		}
+	}
+}
+
+// promotions returns the promotions of t, in the order of the embedded types
+// in breadth-first order.
+//
+// As in the Go spec, a field or method at a shallower depth of embedding
+// shadows one with the same name at a greater depth, and a name declared more
+// than once at the same depth is not promoted at all.
+func promotions(t *doc.Type, docTypes map[string]*doc.Type, typeItems map[string]*item) []*promotion {
+	// seen holds the names of the selectors at shallower depths.
+	seen := map[string]bool{}
+	for _, name := range fieldNames(t) {
+		seen[name] = true
+	}
+	for _, m := range t.Methods {
+		seen[m.Name] = true
+	}
+	visited := map[string]bool{t.Name: true}
+
+	var proms []*promotion
+	level := embeddedTypes(t, docTypes)
+	for len(level) > 0 {
+		// Count the selectors at this depth, to find the ambiguous ones. A type
+		// embedded more than once at the same depth makes all its selectors
+		// ambiguous.
+		counts := map[string]int{}
+		var types, next []*doc.Type
+		for _, e := range level {
+			if visited[e.Name] {
+				continue
+			}
+			for _, name := range fieldNames(e) {
+				counts[name]++
+			}
+			for _, m := range typeItems[e.Name].Methods {
+				counts[m.Name]++
+			}
+			if !slices.Contains(types, e) {
+				types = append(types, e)
+				next = append(next, embeddedTypes(e, docTypes)...)
+			}
+		}
+		for _, e := range types {
+			visited[e.Name] = true
+			prom := &promotion{TypeName: e.Name}
+			for _, name := range fieldNames(e) {
+				if !seen[name] && counts[name] == 1 {
+					prom.Fields = append(prom.Fields, name)
+				}
+			}
+			for _, m := range typeItems[e.Name].Methods {
+				if !seen[m.Name] && counts[m.Name] == 1 {
+					prom.Methods = append(prom.Methods, m)
+				}
+			}
+			if len(prom.Fields) > 0 || len(prom.Methods) > 0 {
+				proms = append(proms, prom)
+			}
+		}
+		for name := range counts {
+			seen[name] = true
+		}
+		level = next
+	}
+	return proms
+}
+
+// fieldNames returns the names of the exported fields of t, including those of
+// its embedded fields, if t is a struct type.
+func fieldNames(t *doc.Type) []string {
+	st := structType(t)
+	if st == nil {
+		return nil
+	}
+	var names []string
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			if id := embeddedIdent(f.Type); id != nil && ast.IsExported(id.Name) {
+				names = append(names, id.Name)
+			}
+			continue
+		}
+		for _, id := range f.Names {
+			if id.IsExported() {
+				names = append(names, id.Name)
+			}
+		}
+	}
+	return names
+}
+
+// embeddedTypes returns the types in docTypes that are embedded in t, if t is
+// a struct type.
+func embeddedTypes(t *doc.Type, docTypes map[string]*doc.Type) []*doc.Type {
+	st := structType(t)
+	if st == nil {
+		return nil
+	}
+	var ts []*doc.Type
+	for _, f := range st.Fields.List {
+		if len(f.Names) > 0 {
+			continue
+		}
+		// Embedded types from other packages are selector expressions, for which
+		// embeddedIdent returns nil.
+		if id := embeddedIdent(f.Type); id != nil {
+			if e := docTypes[id.Name]; e != nil {
+				ts = append(ts, e)
+			}
+		}
+	}
+	return ts
+}
+
+// structType returns the struct type declared by t, or nil if t is not a
+// struct type.
+func structType(t *doc.Type) *ast.StructType {
+	for _, spec := range t.Decl.Specs {
+		if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Name == t.Name {
+			st, _ := ts.Type.(*ast.StructType)
+			return st
+		}
+	}
+	return nil
+}
+
+// embeddedIdent returns the name of the type of an embedded field declared in
+// the same package, or nil if the type is declared in another package.
+func embeddedIdent(expr ast.Expr) *ast.Ident {
+	switch x := expr.(type) {
+	case *ast.Ident:
+		return x
+	case *ast.StarExpr:
+		return embeddedIdent(x.X)
+	case *ast.IndexExpr:
+		return embeddedIdent(x.X)
+	case *ast.IndexListExpr:
+		return embeddedIdent(x.X)
+	}
+	return nil
+}
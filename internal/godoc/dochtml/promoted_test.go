@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dochtml
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPromotions(t *testing.T) {
+	const src = `
+package p
+
+import "io"
+
+type Inner struct {
+	X, Y int
+}
+
+func (Inner) Close() error { return nil }
+
+type Middle struct {
+	Inner
+	Z int
+}
+
+func (*Middle) Reset() {}
+
+// Outer shadows Y and gets Inner through Middle.
+type Outer struct {
+	*Middle
+	Y string
+}
+
+// Ambiguous embeds two types that both have a field X.
+type Ambiguous struct {
+	Inner
+	Other
+}
+
+type Other struct {
+	X int
+}
+
+// External embeds a type from another package.
+type External struct {
+	io.Reader
+}
+
+// Cycle embeds itself through a pointer.
+type Cycle struct {
+	*Cycle
+	N int
+}
+
+type NotStruct int
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := doc.NewFromFiles(fset, []*ast.File{f}, "p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, _, types := packageToItems(p, nil)
+
+	type prom struct {
+		TypeName string
+		Fields   []string
+		Methods  []string
+	}
+	got := map[string][]prom{}
+	for _, it := range types {
+		for _, pr := range it.Promoted {
+			var methods []string
+			for _, m := range pr.Methods {
+				methods = append(methods, m.FullName)
+			}
+			got[it.Name] = append(got[it.Name], prom{pr.TypeName, pr.Fields, methods})
+		}
+	}
+	want := map[string][]prom{
+		"Middle": {
+			{TypeName: "Inner", Fields: []string{"X", "Y"}, Methods: []string{"Inner.Close"}},
+		},
+		"Outer": {
+			{TypeName: "Middle", Fields: []string{"Inner", "Z"}, Methods: []string{"Middle.Reset"}},
+			{TypeName: "Inner", Fields: []string{"X"}, Methods: []string{"Inner.Close"}},
+		},
+		"Ambiguous": {
+			{TypeName: "Inner", Fields: []string{"Y"}, Methods: []string{"Inner.Close"}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -66,6 +66,8 @@ var tmpl = map[string]any{
 	"file_link":                func() string { return "" },
 	"source_link":              func(string, any) string { return "" },
 	"since_version":            func(string) safehtml.HTML { return safehtml.HTML{} },
+	"since_version_url":        func(string) string { return "" },
+	"expand_examples":          func() bool { return false },
 	"play_url":                 func(*doc.Example) string { return "" },
 	"safe_id":                  render.SafeGoID,
 }
@@ -0,0 +1,222 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package docjson renders Go package documentation as JSON, for tools that
+// expect the machine-readable output of "go doc" rather than a web page.
+//
+// The JSON mirrors the types of go/doc, with the same field names: a Package
+// has Values, Types and Funcs, and they have the same fields as their
+// counterparts in go/doc. Declarations, which are ASTs in go/doc, are Go
+// source instead, and each declaration has the position where it starts.
+package docjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/doc"
+	"go/printer"
+	"go/token"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/godoc/docmarkdown"
+)
+
+// Package is the documentation of a package, like a doc.Package.
+type Package struct {
+	Name       string
+	ImportPath string
+	Doc        string
+	Imports    []string
+	Filenames  []string
+	Notes      map[string][]*Note `json:",omitempty"`
+
+	Consts []*Value
+	Vars   []*Value
+	Types  []*Type
+	Funcs  []*Func
+
+	Examples []*Example `json:",omitempty"`
+}
+
+// Value is the documentation of a group of constants or variables, like a
+// doc.Value.
+type Value struct {
+	Doc   string
+	Names []string
+	Decl  string
+	Pos   Position
+}
+
+// Type is the documentation of a type, like a doc.Type.
+type Type struct {
+	Doc  string
+	Name string
+	Decl string
+	Pos  Position
+
+	Consts  []*Value
+	Vars    []*Value
+	Funcs   []*Func
+	Methods []*Func
+
+	Examples []*Example `json:",omitempty"`
+}
+
+// Func is the documentation of a function or method, like a doc.Func.
+type Func struct {
+	Doc  string
+	Name string
+	Decl string
+	Pos  Position
+
+	// Recv, Orig and Level are only set for methods.
+	Recv  string `json:",omitempty"`
+	Orig  string `json:",omitempty"`
+	Level int    `json:",omitempty"`
+
+	Examples []*Example `json:",omitempty"`
+}
+
+// Note is a marked comment, like a doc.Note.
+type Note struct {
+	UID  string
+	Body string
+	Pos  Position
+}
+
+// Example is an example function, like a doc.Example. Code is its body, or
+// the whole program if it can run on its own.
+type Example struct {
+	Name        string
+	Suffix      string
+	Doc         string
+	Code        string
+	Output      string
+	Unordered   bool `json:",omitempty"`
+	EmptyOutput bool `json:",omitempty"`
+}
+
+// Position is a position in a file of the package. Filename is relative to
+// the directory of the package.
+type Position struct {
+	Filename string
+	Line     int
+	Column   int
+}
+
+// Render renders the documentation of p as JSON.
+func Render(fset *token.FileSet, p *doc.Package) (_ []byte, err error) {
+	defer derrors.Wrap(&err, "docjson.Render")
+
+	c := &converter{fset: fset}
+	jp := &Package{
+		Name:       p.Name,
+		ImportPath: p.ImportPath,
+		Doc:        p.Doc,
+		Imports:    p.Imports,
+		Filenames:  p.Filenames,
+		Consts:     c.values(p.Consts),
+		Vars:       c.values(p.Vars),
+		Types:      []*Type{},
+		Funcs:      c.funcs(p.Funcs),
+		Examples:   c.examples(p.Examples),
+	}
+	for _, t := range p.Types {
+		jp.Types = append(jp.Types, &Type{
+			Doc:      t.Doc,
+			Name:     t.Name,
+			Decl:     c.source(t.Decl),
+			Pos:      c.position(t.Decl.Pos()),
+			Consts:   c.values(t.Consts),
+			Vars:     c.values(t.Vars),
+			Funcs:    c.funcs(t.Funcs),
+			Methods:  c.funcs(t.Methods),
+			Examples: c.examples(t.Examples),
+		})
+	}
+	if len(p.Notes) > 0 {
+		jp.Notes = map[string][]*Note{}
+		for marker, notes := range p.Notes {
+			for _, n := range notes {
+				jp.Notes[marker] = append(jp.Notes[marker], &Note{UID: n.UID, Body: n.Body, Pos: c.position(n.Pos)})
+			}
+		}
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return json.MarshalIndent(jp, "", "\t")
+}
+
+type converter struct {
+	fset *token.FileSet
+	err  error // first error encountered
+}
+
+func (c *converter) position(pos token.Pos) Position {
+	p := c.fset.Position(pos)
+	return Position{Filename: p.Filename, Line: p.Line, Column: p.Column}
+}
+
+// source returns the Go source of n.
+func (c *converter) source(n any) string {
+	var b bytes.Buffer
+	if err := printer.Fprint(&b, c.fset, n); err != nil && c.err == nil {
+		c.err = err
+	}
+	return b.String()
+}
+
+// values converts vals. The result is never nil, so that it is an empty list
+// in JSON rather than null.
+func (c *converter) values(vals []*doc.Value) []*Value {
+	vs := []*Value{}
+	for _, v := range vals {
+		vs = append(vs, &Value{
+			Doc:   v.Doc,
+			Names: v.Names,
+			Decl:  c.source(v.Decl),
+			Pos:   c.position(v.Decl.Pos()),
+		})
+	}
+	return vs
+}
+
+// funcs converts funcs. Like values, it never returns nil.
+func (c *converter) funcs(funcs []*doc.Func) []*Func {
+	fs := []*Func{}
+	for _, f := range funcs {
+		fs = append(fs, &Func{
+			Doc:      f.Doc,
+			Name:     f.Name,
+			Decl:     c.source(f.Decl),
+			Pos:      c.position(f.Decl.Pos()),
+			Recv:     f.Recv,
+			Orig:     f.Orig,
+			Level:    f.Level,
+			Examples: c.examples(f.Examples),
+		})
+	}
+	return fs
+}
+
+func (c *converter) examples(examples []*doc.Example) []*Example {
+	var exs []*Example
+	for _, ex := range examples {
+		code, err := docmarkdown.ExampleCode(c.fset, ex)
+		if err != nil && c.err == nil {
+			c.err = err
+		}
+		exs = append(exs, &Example{
+			Name:        ex.Name,
+			Suffix:      ex.Suffix,
+			Doc:         ex.Doc,
+			Code:        code,
+			Output:      ex.Output,
+			Unordered:   ex.Unordered,
+			EmptyOutput: ex.EmptyOutput,
+		})
+	}
+	return exs
+}
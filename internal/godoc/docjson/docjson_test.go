@@ -0,0 +1,120 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docjson
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRender(t *testing.T) {
+	const (
+		src = `// Package p is a package.
+package p
+
+import "io"
+
+// C is a constant.
+const C = 1
+
+// F is a function.
+func F(r io.Reader) { panic(r) }
+
+// T is a type.
+type T struct{ X int }
+
+// New returns a T.
+func New() *T { return nil }
+
+// M is a method.
+func (*T) M() {}
+
+// BUG(jba): M does nothing.
+`
+		testSrc = "package p_test\n\n" +
+			"import \"example.com/p\"\n\n" +
+			"func ExampleT_M() {\n\tvar t p.T\n\tt.M()\n\t// Output:\n}\n"
+	)
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, f := range []struct{ name, src string }{{"p.go", src}, {"p_test.go", testSrc}} {
+		af, err := parser.ParseFile(fset, f.name, f.src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, af)
+	}
+	d, err := doc.NewFromFiles(fset, files, "example.com/p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := Render(fset, d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Package
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := Package{
+		Name:       "p",
+		ImportPath: "example.com/p",
+		Doc:        "Package p is a package.\n",
+		Imports:    []string{"io"},
+		Filenames:  []string{"p.go"},
+		Notes: map[string][]*Note{
+			"BUG": {{UID: "jba", Body: "M does nothing.\n", Pos: Position{"p.go", 21, 1}}},
+		},
+		Consts: []*Value{{
+			Doc:   "C is a constant.\n",
+			Names: []string{"C"},
+			Decl:  "const C = 1",
+			Pos:   Position{"p.go", 7, 1},
+		}},
+		Vars: []*Value{},
+		Types: []*Type{{
+			Doc:    "T is a type.\n",
+			Name:   "T",
+			Decl:   "type T struct{ X int }",
+			Pos:    Position{"p.go", 13, 1},
+			Consts: []*Value{},
+			Vars:   []*Value{},
+			Funcs: []*Func{{
+				Doc:  "New returns a T.\n",
+				Name: "New",
+				Decl: "func New() *T",
+				Pos:  Position{"p.go", 16, 1},
+			}},
+			Methods: []*Func{{
+				Doc:  "M is a method.\n",
+				Name: "M",
+				Decl: "func (*T) M()",
+				Pos:  Position{"p.go", 19, 1},
+				Recv: "*T",
+				Orig: "*T",
+				Examples: []*Example{{
+					Name:        "T_M",
+					Code:        "package main\n\nimport (\n\t\"example.com/p\"\n)\n\nfunc main() {\n\tvar t p.T\n\tt.M()\n}\n",
+					EmptyOutput: true,
+				}},
+			}},
+		}},
+		Funcs: []*Func{{
+			Doc:  "F is a function.\n",
+			Name: "F",
+			Decl: "func F(r io.Reader)",
+			Pos:  Position{"p.go", 10, 1},
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,159 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"go/doc"
+	"go/doc/comment"
+	"go/token"
+	"path"
+	"strings"
+)
+
+// A DocLink is a doc link, such as [Foo] or [example.com/mod/pkg.Foo], in a
+// doc comment of a package, to a symbol of a package in the same module.
+// Whether a symbol of another package exists can only be decided once all
+// the packages of the module have been loaded.
+type DocLink struct {
+	Text       string // text between the brackets
+	ImportPath string // path of the package linked to
+	Recv       string // type of the method linked to, if any
+	Name       string // name of the symbol linked to
+	File       string // name of the file containing the comment
+	Line       int    // line of the declaration the comment documents
+}
+
+// moduleDocLinks returns the doc links in the doc comments of d that point to
+// symbols of other packages in the module described by modInfo, and the
+// links to symbols of d itself that do not exist. Links in the package
+// comment are reported at pkgClause, the package clause it documents.
+func (p *Package) moduleDocLinks(d *doc.Package, modInfo *ModuleInfo, pkgClause token.Pos) []*DocLink {
+	var links []*DocLink
+	seen := map[DocLink]bool{}
+	add := func(l DocLink) {
+		if !seen[l] {
+			seen[l] = true
+			links = append(links, &l)
+		}
+	}
+	check := func(text string, pos token.Pos) {
+		if text == "" {
+			return
+		}
+		position := p.Fset.Position(pos)
+		at := DocLink{File: path.Base(position.Filename), Line: position.Line}
+		pr := d.Parser()
+		lookupSym := pr.LookupSym
+		// The parser leaves links to symbols of d that it cannot find
+		// as text, so record them as it looks them up.
+		pr.LookupSym = func(recv, name string) bool {
+			if lookupSym(recv, name) {
+				return true
+			}
+			l := at
+			l.Text, l.ImportPath, l.Recv, l.Name = symbolText(recv, name), d.ImportPath, recv, name
+			add(l)
+			return false
+		}
+		walkDocLinks(pr.Parse(text).Content, func(dl *comment.DocLink) {
+			if dl.Name == "" {
+				return
+			}
+			l := at
+			l.Text, l.ImportPath, l.Recv, l.Name = docLinkText(dl), dl.ImportPath, dl.Recv, dl.Name
+			switch {
+			case dl.ImportPath == "" || dl.ImportPath == d.ImportPath:
+				// A link qualified with the name or path of d itself, such
+				// as [p.Foo], is not looked up by the parser.
+				if !lookupSym(dl.Recv, dl.Name) {
+					l.ImportPath = d.ImportPath
+					add(l)
+				}
+			case modInfo.ModulePackages[dl.ImportPath]:
+				add(l)
+			}
+		})
+	}
+
+	check(d.Doc, pkgClause)
+	values := func(vs []*doc.Value) {
+		for _, v := range vs {
+			check(v.Doc, v.Decl.Pos())
+		}
+	}
+	funcs := func(fs []*doc.Func) {
+		for _, f := range fs {
+			check(f.Doc, f.Decl.Pos())
+		}
+	}
+	values(d.Consts)
+	values(d.Vars)
+	funcs(d.Funcs)
+	for _, t := range d.Types {
+		check(t.Doc, t.Decl.Pos())
+		values(t.Consts)
+		values(t.Vars)
+		funcs(t.Funcs)
+		funcs(t.Methods)
+	}
+	return links
+}
+
+// packageDocClause returns the package clause of the first file of p with a
+// package comment, or token.NoPos if there is none. Call it before computing
+// the documentation of p, since go/doc removes the package comments from the
+// files.
+func (p *Package) packageDocClause() token.Pos {
+	for _, f := range p.Files {
+		if f.AST.Doc != nil && !strings.HasSuffix(f.Name, "_test.go") {
+			return f.AST.Package
+		}
+	}
+	return token.NoPos
+}
+
+// walkDocLinks calls f for each doc link in blocks.
+func walkDocLinks(blocks []comment.Block, f func(*comment.DocLink)) {
+	texts := func(ts []comment.Text) {
+		for _, t := range ts {
+			if dl, ok := t.(*comment.DocLink); ok {
+				f(dl)
+			}
+		}
+	}
+	for _, b := range blocks {
+		switch b := b.(type) {
+		case *comment.Paragraph:
+			texts(b.Text)
+		case *comment.Heading:
+			texts(b.Text)
+		case *comment.List:
+			for _, item := range b.Items {
+				walkDocLinks(item.Content, f)
+			}
+		}
+	}
+}
+
+// docLinkText returns the text between the brackets of dl.
+func docLinkText(dl *comment.DocLink) string {
+	var b strings.Builder
+	for _, t := range dl.Text {
+		switch t := t.(type) {
+		case comment.Plain:
+			b.WriteString(string(t))
+		case comment.Italic:
+			b.WriteString(string(t))
+		}
+	}
+	return b.String()
+}
+
+func symbolText(recv, name string) string {
+	if recv == "" {
+		return name
+	}
+	return recv + "." + name
+}
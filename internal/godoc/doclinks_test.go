@@ -0,0 +1,55 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestModuleDocLinks(t *testing.T) {
+	const src = `// Package p links to [F], [Missing], [p.Gone], [q.G], [io.Reader] and [example.com/m/q.T.M].
+package p
+
+import "example.com/m/q"
+
+// F links to [T.M], [T.N] and [q.H].
+func F() { q.G() }
+
+type T int
+
+func (T) M() {}
+`
+	fset := token.NewFileSet()
+	p := NewPackage(fset, nil)
+	af, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.AddFile(af, true)
+	modInfo := &ModuleInfo{
+		ModulePath:     "example.com/m",
+		ModulePackages: map[string]bool{"example.com/m/p": true, "example.com/m/q": true},
+	}
+	_, _, _, _, got, err := p.DocInfo(context.Background(), "p", nil, modInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*DocLink{
+		{Text: "Missing", ImportPath: "example.com/m/p", Name: "Missing", File: "p.go", Line: 2},
+		{Text: "p.Gone", ImportPath: "example.com/m/p", Name: "Gone", File: "p.go", Line: 2},
+		{Text: "q.G", ImportPath: "example.com/m/q", Name: "G", File: "p.go", Line: 2},
+		{Text: "example.com/m/q.T.M", ImportPath: "example.com/m/q", Recv: "T", Name: "M", File: "p.go", Line: 2},
+		{Text: "T.N", ImportPath: "example.com/m/p", Recv: "T", Name: "N", File: "p.go", Line: 7},
+		{Text: "q.H", ImportPath: "example.com/m/q", Name: "H", File: "p.go", Line: 7},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
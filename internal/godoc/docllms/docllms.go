@@ -0,0 +1,293 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package docllms renders a digest of Go package documentation in the style
+// of llms.txt (https://llmstxt.org), for feeding into language models and
+// other tools that work best with short plain text.
+//
+// The digest is Markdown: the import path as the title, the synopsis as a
+// blockquote, and then a list of the exported API, one line per declaration
+// with its signature and the first sentence of its doc comment, followed by
+// a few examples. Full doc comments and unexported details are left out to
+// keep it small; the documentation page has them.
+package docllms
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/printer"
+	"go/token"
+	"strings"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/godoc/docmarkdown"
+)
+
+// DefaultMaxExamples is the number of examples in a digest if
+// Options.MaxExamples is zero.
+const DefaultMaxExamples = 3
+
+// Options are options for Render.
+type Options struct {
+	// URL is the URL of the package's documentation page, such as
+	// "https://pkg.go.dev/example.com/mod/pkg". If it is not empty, the
+	// digest links to it.
+	URL string
+
+	// MaxExamples is the greatest number of examples in the digest. Package
+	// examples come first, then those of functions and types, in the order of
+	// the documentation. If it is zero, DefaultMaxExamples is used; if it is
+	// negative, there are none.
+	MaxExamples int
+}
+
+// Render renders a digest of the documentation of p.
+func Render(fset *token.FileSet, p *doc.Package, opt Options) (_ []byte, err error) {
+	defer derrors.Wrap(&err, "docllms.Render")
+
+	r := &renderer{fset: fset, p: p, opt: opt}
+	r.render()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return append(bytes.TrimRight(r.buf.Bytes(), "\n"), '\n'), nil
+}
+
+type renderer struct {
+	fset *token.FileSet
+	p    *doc.Package
+	opt  Options
+	buf  bytes.Buffer
+	err  error // first error encountered
+}
+
+func (r *renderer) render() {
+	p := r.p
+	r.printf("# %s\n\n", p.ImportPath)
+	if s := p.Synopsis(p.Doc); s != "" {
+		r.printf("> %s\n\n", s)
+	}
+	r.printf("Import with `import %q`", p.ImportPath)
+	if p.Name != pathBase(p.ImportPath) {
+		r.printf(" (package %s)", p.Name)
+	}
+	r.printf(".")
+	if r.opt.URL != "" {
+		r.printf(" Full documentation: %s", r.opt.URL)
+	}
+	r.printf("\n\n")
+
+	if len(p.Consts) > 0 {
+		r.printf("## Constants\n\n")
+		r.values(p.Consts, "")
+		r.printf("\n")
+	}
+	if len(p.Vars) > 0 {
+		r.printf("## Variables\n\n")
+		r.values(p.Vars, "")
+		r.printf("\n")
+	}
+	if len(p.Funcs) > 0 {
+		r.printf("## Functions\n\n")
+		r.funcs(p.Funcs, "")
+		r.printf("\n")
+	}
+	if len(p.Types) > 0 {
+		r.printf("## Types\n\n")
+		for _, t := range p.Types {
+			r.item("", typeSignature(r.fset, t), t.Doc)
+			r.values(t.Consts, "  ")
+			r.values(t.Vars, "  ")
+			r.funcs(t.Funcs, "  ")
+			r.funcs(t.Methods, "  ")
+		}
+		r.printf("\n")
+	}
+	r.examples()
+}
+
+func (r *renderer) printf(format string, args ...any) {
+	fmt.Fprintf(&r.buf, format, args...)
+}
+
+// item writes a list item for a declaration with the given signature and
+// doc comment, indented by indent.
+func (r *renderer) item(indent, sig, text string) {
+	r.printf("%s- `%s`", indent, sig)
+	if s := r.p.Synopsis(text); s != "" {
+		r.printf(": %s", s)
+	}
+	r.printf("\n")
+}
+
+// maxValueDecl is the greatest length of the declaration of a single
+// constant or variable in the digest.
+const maxValueDecl = 80
+
+// values writes an item for each group of constants or variables. The
+// signature of a group, or of a long declaration, lists only the names.
+func (r *renderer) values(vals []*doc.Value, indent string) {
+	for _, v := range vals {
+		kw := v.Decl.Tok.String()
+		if len(v.Names) == 1 && len(v.Decl.Specs) == 1 {
+			if sig := kw + " " + r.oneLine(v.Decl); len(sig) <= maxValueDecl {
+				r.item(indent, sig, v.Doc)
+				continue
+			}
+		}
+		r.item(indent, kw+" ("+strings.Join(v.Names, ", ")+")", v.Doc)
+	}
+}
+
+func (r *renderer) funcs(funcs []*doc.Func, indent string) {
+	for _, f := range funcs {
+		// Print only the signature, in case the body was kept.
+		d := *f.Decl
+		d.Doc = nil
+		d.Body = nil
+		r.item(indent, r.oneLine(&d), f.Doc)
+	}
+}
+
+// oneLine returns the source of the declaration node on one line. Values
+// are printed without the keyword of their declaration.
+func (r *renderer) oneLine(node ast.Node) string {
+	if d, ok := node.(*ast.GenDecl); ok && len(d.Specs) == 1 {
+		node = d.Specs[0]
+	}
+	var b bytes.Buffer
+	if err := printer.Fprint(&b, r.fset, node); err != nil && r.err == nil {
+		r.err = err
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// typeSignature returns the declaration of t without the fields of a struct
+// or the methods of an interface, which are described by its doc comment and
+// listed on its documentation page.
+func typeSignature(fset *token.FileSet, t *doc.Type) string {
+	for _, s := range t.Decl.Specs {
+		ts, ok := s.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != t.Name {
+			continue
+		}
+		sig := "type " + t.Name
+		if ts.TypeParams != nil {
+			sig += "[" + fieldList(fset, ts.TypeParams) + "]"
+		}
+		if ts.Assign.IsValid() {
+			sig += " ="
+		}
+		switch ts.Type.(type) {
+		case *ast.StructType:
+			return sig + " struct"
+		case *ast.InterfaceType:
+			return sig + " interface"
+		}
+		var b bytes.Buffer
+		if err := printer.Fprint(&b, fset, ts.Type); err != nil {
+			return sig
+		}
+		return sig + " " + strings.Join(strings.Fields(b.String()), " ")
+	}
+	return "type " + t.Name
+}
+
+// fieldList returns the source of the type parameters in l, without
+// brackets.
+func fieldList(fset *token.FileSet, l *ast.FieldList) string {
+	var parts []string
+	for _, f := range l.List {
+		var b bytes.Buffer
+		if err := printer.Fprint(&b, fset, f.Type); err != nil {
+			continue
+		}
+		var names []string
+		for _, n := range f.Names {
+			names = append(names, n.Name)
+		}
+		parts = append(parts, strings.Join(names, ", ")+" "+b.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// examples writes up to opt.MaxExamples examples.
+func (r *renderer) examples() {
+	n := r.opt.MaxExamples
+	if n == 0 {
+		n = DefaultMaxExamples
+	}
+	type example struct {
+		name string
+		ex   *doc.Example
+	}
+	var exs []example
+	add := func(name string, examples []*doc.Example) {
+		for _, ex := range examples {
+			n := name
+			if ex.Suffix != "" {
+				n += " (" + ex.Suffix + ")"
+			}
+			exs = append(exs, example{n, ex})
+		}
+	}
+	add("package", r.p.Examples)
+	for _, f := range r.p.Funcs {
+		add(f.Name, f.Examples)
+	}
+	for _, t := range r.p.Types {
+		add(t.Name, t.Examples)
+		for _, f := range t.Funcs {
+			add(f.Name, f.Examples)
+		}
+		for _, m := range t.Methods {
+			add(t.Name+"."+m.Name, m.Examples)
+		}
+	}
+	if len(exs) > n {
+		exs = exs[:max(n, 0)]
+	}
+	if len(exs) == 0 {
+		return
+	}
+	r.printf("## Examples\n\n")
+	for _, e := range exs {
+		r.printf("### %s\n\n", e.name)
+		code, err := docmarkdown.ExampleCode(r.fset, e.ex)
+		if err != nil {
+			if r.err == nil {
+				r.err = err
+			}
+			continue
+		}
+		r.code("go", code)
+		if e.ex.Output != "" {
+			r.printf("Output:\n\n")
+			r.code("", e.ex.Output)
+		}
+	}
+}
+
+// code writes a fenced code block.
+func (r *renderer) code(lang, text string) {
+	// The fence must be longer than any run of backquotes in the text.
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	r.printf("%s%s\n%s\n%s\n\n", fence, lang, strings.TrimRight(text, "\n"), fence)
+}
+
+// pathBase returns the last element of the import path, ignoring a major
+// version suffix, which is the usual package name.
+func pathBase(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	base := elems[len(elems)-1]
+	if len(elems) > 1 && len(base) > 1 && base[0] == 'v' && strings.Trim(base[1:], "0123456789") == "" {
+		base = elems[len(elems)-2]
+	}
+	return base
+}
@@ -0,0 +1,134 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docllms
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRender(t *testing.T) {
+	const (
+		src = `
+// Package p is a package. It does things.
+//
+// # Usage
+//
+// Call [F].
+package p
+
+// C is a constant.
+const C = 1
+
+// The errors of the package.
+var (
+	ErrA = errors.New("a")
+	ErrB = errors.New("b")
+)
+
+// F is a function.
+// It does nothing.
+func F(a int,
+	b string) error { return nil }
+
+// T is a type.
+type T struct{ X int }
+
+// New returns a T.
+func New() *T { return nil }
+
+// M is a method.
+func (*T) M() {}
+
+// L is a list.
+type L[E any] []E
+`
+		testSrc = "package p_test\n\n" +
+			"import (\n\t\"fmt\"\n\n\t\"example.com/p\"\n)\n\n" +
+			"func ExampleF() {\n\tp.F(1, \"x\")\n\tfmt.Println(\"done\")\n\t// Output: done\n}\n\n" +
+			"func ExampleT_M_second() {\n\tvar t p.T\n\tt.M()\n}\n"
+	)
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, f := range []struct{ name, src string }{{"p.go", src}, {"p_test.go", testSrc}} {
+		af, err := parser.ParseFile(fset, f.name, f.src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, af)
+	}
+	d, err := doc.NewFromFiles(fset, files, "example.com/p")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const api = "# example.com/p\n\n" +
+		"> Package p is a package.\n\n" +
+		"Import with `import \"example.com/p\"`. Full documentation: https://pkg.go.dev/example.com/p\n\n" +
+		"## Constants\n\n" +
+		"- `const C = 1`: C is a constant.\n\n" +
+		"## Variables\n\n" +
+		"- `var (ErrA, ErrB)`: The errors of the package.\n\n" +
+		"## Functions\n\n" +
+		"- `func F(a int, b string) error`: F is a function.\n\n" +
+		"## Types\n\n" +
+		"- `type L[E any] []E`: L is a list.\n" +
+		"- `type T struct`: T is a type.\n" +
+		"  - `func New() *T`: New returns a T.\n" +
+		"  - `func (*T) M()`: M is a method.\n"
+	for _, test := range []struct {
+		name string
+		opt  Options
+		want string
+	}{
+		{
+			name: "default",
+			opt:  Options{URL: "https://pkg.go.dev/example.com/p"},
+			want: api + "\n" +
+				"## Examples\n\n" +
+				"### F\n\n" +
+				"```go\npackage main\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/p\"\n)\n\nfunc main() {\n\tp.F(1, \"x\")\n\tfmt.Println(\"done\")\n}\n```\n\n" +
+				"Output:\n\n" +
+				"```\ndone\n```\n\n" +
+				"### T.M (second)\n\n" +
+				"```go\npackage main\n\nimport (\n\t\"example.com/p\"\n)\n\nfunc main() {\n\tvar t p.T\n\tt.M()\n}\n```\n",
+		},
+		{
+			name: "no examples",
+			opt:  Options{URL: "https://pkg.go.dev/example.com/p", MaxExamples: -1},
+			want: api,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := Render(fset, d, test.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, string(got)); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPathBase(t *testing.T) {
+	for _, test := range []struct {
+		path, want string
+	}{
+		{"fmt", "fmt"},
+		{"example.com/mod/pkg", "pkg"},
+		{"example.com/mod/v2", "mod"},
+		{"gopkg.in/yaml.v3", "yaml.v3"},
+	} {
+		if got := pathBase(test.path); got != test.want {
+			t.Errorf("pathBase(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
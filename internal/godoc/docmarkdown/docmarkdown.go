@@ -0,0 +1,229 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package docmarkdown renders Go package documentation as Markdown, for
+// embedding it in wikis and other tools that consume Markdown.
+//
+// The output is CommonMark, except that headings end with an ID in the
+// "{#id}" syntax that go/doc/comment also uses. The IDs are the same as those
+// of the HTML documentation, so links within the page work in processors
+// that support the syntax, and are harmless in others.
+package docmarkdown
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/format"
+	"go/printer"
+	"go/token"
+	"strings"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Options are options for Render.
+type Options struct {
+	// DocLinkBaseURL is the URL that links to other packages are relative
+	// to, such as "https://pkg.go.dev". If it is empty, the links are paths,
+	// like those in the HTML documentation.
+	DocLinkBaseURL string
+}
+
+// Render renders the documentation of p as Markdown.
+//
+// The documentation has the same structure as the HTML documentation: an
+// overview, then sections for constants, variables, functions and types, with
+// a heading for each function, type and method, followed by its examples.
+func Render(fset *token.FileSet, p *doc.Package, opt Options) (_ []byte, err error) {
+	defer derrors.Wrap(&err, "docmarkdown.Render")
+
+	r := &renderer{fset: fset, p: p, opt: opt}
+	r.render()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return append(bytes.TrimRight(r.buf.Bytes(), "\n"), '\n'), nil
+}
+
+type renderer struct {
+	fset *token.FileSet
+	p    *doc.Package
+	opt  Options
+	buf  bytes.Buffer
+	err  error // first error encountered
+}
+
+func (r *renderer) render() {
+	p := r.p
+	r.printf("# package %s\n\n", p.Name)
+	r.code("go", fmt.Sprintf("import %q", p.ImportPath))
+
+	r.heading(2, "Overview", "pkg-overview")
+	r.doc(p.Doc, 3)
+	r.examples("", p.Examples)
+
+	if len(p.Consts) > 0 {
+		r.heading(2, "Constants", "pkg-constants")
+		r.values(p.Consts)
+	}
+	if len(p.Vars) > 0 {
+		r.heading(2, "Variables", "pkg-variables")
+		r.values(p.Vars)
+	}
+	if len(p.Funcs) > 0 {
+		r.heading(2, "Functions", "pkg-functions")
+		r.funcs("", p.Funcs)
+	}
+	if len(p.Types) > 0 {
+		r.heading(2, "Types", "pkg-types")
+		for _, t := range p.Types {
+			r.heading(3, "type "+t.Name, t.Name)
+			r.decl(t.Decl)
+			r.doc(t.Doc, 4)
+			r.examples(t.Name, t.Examples)
+			r.values(t.Consts)
+			r.values(t.Vars)
+			r.funcs("", t.Funcs)
+			r.funcs(t.Name, t.Methods)
+		}
+	}
+}
+
+func (r *renderer) printf(format string, args ...any) {
+	fmt.Fprintf(&r.buf, format, args...)
+}
+
+func (r *renderer) heading(level int, text, id string) {
+	// The only Markdown punctuation in the text can be the "*" of a pointer
+	// receiver.
+	text = strings.ReplaceAll(text, "*", `\*`)
+	r.printf("%s %s {#%s}\n\n", strings.Repeat("#", level), text, id)
+}
+
+// code writes a fenced code block.
+func (r *renderer) code(lang, text string) {
+	// The fence must be longer than any run of backquotes in the text.
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+	r.printf("%s%s\n%s\n%s\n\n", fence, lang, strings.TrimRight(text, "\n"), fence)
+}
+
+// doc writes a doc comment. Its headings are at the given level.
+func (r *renderer) doc(text string, headingLevel int) {
+	if text == "" {
+		return
+	}
+	pr := r.p.Printer()
+	pr.HeadingLevel = headingLevel
+	pr.DocLinkBaseURL = r.opt.DocLinkBaseURL
+	r.buf.Write(pr.Markdown(r.p.Parser().Parse(text)))
+	r.buf.WriteByte('\n')
+}
+
+func (r *renderer) decl(decl ast.Decl) {
+	var b bytes.Buffer
+	if err := printer.Fprint(&b, r.fset, decl); err != nil && r.err == nil {
+		r.err = err
+	}
+	r.code("go", b.String())
+}
+
+func (r *renderer) values(vals []*doc.Value) {
+	for _, v := range vals {
+		r.decl(v.Decl)
+		r.doc(v.Doc, 4)
+	}
+}
+
+// funcs writes funcs, which are methods of the type recvType if it is not
+// empty.
+func (r *renderer) funcs(recvType string, funcs []*doc.Func) {
+	for _, f := range funcs {
+		if recvType != "" {
+			id := recvType + "." + f.Name
+			r.heading(3, fmt.Sprintf("func (%s) %s", f.Recv, f.Name), id)
+			r.decl(f.Decl)
+			r.doc(f.Doc, 4)
+			r.examples(id, f.Examples)
+			continue
+		}
+		r.heading(3, "func "+f.Name, f.Name)
+		r.decl(f.Decl)
+		r.doc(f.Doc, 4)
+		r.examples(f.Name, f.Examples)
+	}
+}
+
+// examples writes the examples of the declaration with the given ID, or of
+// the package if id is empty.
+func (r *renderer) examples(id string, examples []*doc.Example) {
+	for _, ex := range examples {
+		suffix := cases.Title(language.English, cases.NoLower).String(ex.Suffix)
+		title := "Example"
+		if suffix != "" {
+			title += " (" + suffix + ")"
+		}
+		r.heading(4, title, exampleID(id, suffix))
+		r.doc(ex.Doc, 5)
+		code, err := ExampleCode(r.fset, ex)
+		if err != nil {
+			if r.err == nil {
+				r.err = err
+			}
+			continue
+		}
+		r.code("go", code)
+		if ex.Output != "" {
+			if ex.Unordered {
+				r.printf("Unordered output:\n\n")
+			} else {
+				r.printf("Output:\n\n")
+			}
+			r.code("", ex.Output)
+		}
+	}
+}
+
+// exampleID returns the ID of an example, as in the HTML documentation.
+func exampleID(id, suffix string) string {
+	if id == "" {
+		id = "package"
+	}
+	if suffix == "" {
+		return "example-" + id
+	}
+	return "example-" + id + "-" + suffix
+}
+
+// ExampleCode returns the code of ex: a whole program if it can be run in
+// the playground, or else the body of the example function.
+func ExampleCode(fset *token.FileSet, ex *doc.Example) (string, error) {
+	var b bytes.Buffer
+	if ex.Play != nil {
+		if err := format.Node(&b, fset, ex.Play); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+	n := &printer.CommentedNode{Node: ex.Code, Comments: ex.Comments}
+	if err := format.Node(&b, fset, n); err != nil {
+		return "", err
+	}
+	src := b.String()
+	// Remove the braces of the body, and unindent it.
+	if strings.HasPrefix(src, "{\n") && strings.HasSuffix(src, "\n}") {
+		lines := strings.Split(strings.Trim(src[2:len(src)-2], "\n"), "\n")
+		for i, l := range lines {
+			lines[i] = strings.TrimPrefix(l, "\t")
+		}
+		src = strings.Join(lines, "\n")
+	}
+	return src, nil
+}
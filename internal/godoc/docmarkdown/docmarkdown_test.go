@@ -0,0 +1,96 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package docmarkdown
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRender(t *testing.T) {
+	const (
+		src = `
+// Package p is a package.
+//
+// # Usage
+//
+// Call [F] or [io.Reader].
+package p
+
+// C is a constant.
+const C = 1
+
+// F is a function.
+func F() {}
+
+// T is a type.
+type T struct{ X int }
+
+// New returns a T.
+func New() *T { return nil }
+
+// M is a method.
+func (*T) M() {}
+`
+		testSrc = "package p_test\n\n" +
+			"import (\n\t\"fmt\"\n\n\t\"example.com/p\"\n)\n\n" +
+			"func ExampleF() {\n\tp.F()\n\tfmt.Println(\"done\")\n\t// Output: done\n}\n\n" +
+			"func ExampleT_M_second() {\n\tvar t p.T\n\tt.M()\n}\n"
+	)
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, f := range []struct{ name, src string }{{"p.go", src}, {"p_test.go", testSrc}} {
+		af, err := parser.ParseFile(fset, f.name, f.src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, af)
+	}
+	d, err := doc.NewFromFiles(fset, files, "example.com/p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := Render(fset, d, Options{DocLinkBaseURL: "https://pkg.go.dev"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# package p\n\n" +
+		"```go\nimport \"example.com/p\"\n```\n\n" +
+		"## Overview {#pkg-overview}\n\n" +
+		"Package p is a package.\n\n" +
+		"### Usage {#hdr-Usage}\n\n" +
+		"Call [F](#F) or [io.Reader](https://pkg.go.dev/io#Reader).\n\n" +
+		"## Constants {#pkg-constants}\n\n" +
+		"```go\nconst C = 1\n```\n\n" +
+		"C is a constant.\n\n" +
+		"## Functions {#pkg-functions}\n\n" +
+		"### func F {#F}\n\n" +
+		"```go\nfunc F()\n```\n\n" +
+		"F is a function.\n\n" +
+		"#### Example {#example-F}\n\n" +
+		"```go\npackage main\n\nimport (\n\t\"fmt\"\n\n\t\"example.com/p\"\n)\n\nfunc main() {\n\tp.F()\n\tfmt.Println(\"done\")\n}\n```\n\n" +
+		"Output:\n\n" +
+		"```\ndone\n```\n\n" +
+		"## Types {#pkg-types}\n\n" +
+		"### type T {#T}\n\n" +
+		"```go\ntype T struct{ X int }\n```\n\n" +
+		"T is a type.\n\n" +
+		"### func New {#New}\n\n" +
+		"```go\nfunc New() *T\n```\n\n" +
+		"New returns a T.\n\n" +
+		"### func (\\*T) M {#T.M}\n\n" +
+		"```go\nfunc (*T) M()\n```\n\n" +
+		"M is a method.\n\n" +
+		"#### Example (Second) {#example-T.M-Second}\n\n" +
+		"```go\npackage main\n\nimport (\n\t\"example.com/p\"\n)\n\nfunc main() {\n\tvar t p.T\n\tt.M()\n}\n```\n"
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
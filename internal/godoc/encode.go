@@ -7,13 +7,23 @@ package godoc
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"encoding/gob"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"go/ast"
 	"go/token"
 	"io"
+	"maps"
+	"reflect"
+	"runtime"
 
+	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/experiment"
 	"golang.org/x/pkgsite/internal/godoc/codec"
+	"golang.org/x/sync/errgroup"
 )
 
 // The encoding type identifies the encoding being used, to distinguish them
@@ -21,11 +31,19 @@ import (
 const (
 	encodingTypeLen  = 4 // all encoding types must be this many bytes
 	fastEncodingType = "AST2"
+	// In the files encoding, the AST of each file follows the package as a
+	// separately encoded byte slice. It is no longer written, but data
+	// written with it can still be decoded.
+	filesEncodingType = "AST3"
+	// In the split encoding, the ASTs of the files are encoded as sections,
+	// so that the files can be decoded lazily, in parallel and selectively.
+	splitEncodingType = "AST4"
 )
 
 // ErrInvalidEncodingType is returned when the data to DecodePackage has an
 // invalid encoding type.
-var ErrInvalidEncodingType = fmt.Errorf("want initial bytes to be %q but they aren't", fastEncodingType)
+var ErrInvalidEncodingType = fmt.Errorf("want initial bytes to be %q, %q or %q but they aren't",
+	fastEncodingType, filesEncodingType, splitEncodingType)
 
 // Encode encodes a Package into a byte slice.
 // During its operation, Encode modifies the AST,
@@ -33,22 +51,126 @@ var ErrInvalidEncodingType = fmt.Errorf("want initial bytes to be %q but they ar
 // rendering before it returns.
 func (p *Package) Encode(ctx context.Context) (_ []byte, err error) {
 	defer derrors.Wrap(&err, "godoc.Package.Encode()")
+	if experiment.IsActive(ctx, internal.ExperimentParallelDocRender) {
+		return p.splitEncode()
+	}
 	return p.fastEncode()
 }
 
 // DecodePackage decodes a byte slice encoded with Package.Encode into a Package.
 func DecodePackage(data []byte) (_ *Package, err error) {
-	defer derrors.Wrap(&err, "DecodePackage()")
+	return DecodePackageFiles(data, nil)
+}
+
+// DecodePackageFiles is like DecodePackage, but the returned Package has
+// only the files for which keep returns true, or all files if keep is nil.
+// For example, callers that don't need examples can skip test files.
+//
+// If the data was encoded so that files can be decoded separately, the ASTs
+// of the other files are never decoded.
+func DecodePackageFiles(data []byte, keep func(*File) bool) (_ *Package, err error) {
+	defer derrors.Wrap(&err, "DecodePackageFiles()")
 
 	if len(data) < encodingTypeLen {
 		return nil, ErrInvalidEncodingType
 	}
+	var p *Package
 	switch string(data[:encodingTypeLen]) {
 	case fastEncodingType:
-		return fastDecodePackage(data[encodingTypeLen:])
+		p, err = fastDecodePackage(data[encodingTypeLen:])
+	case filesEncodingType:
+		p, err = filesDecodePackage(data[encodingTypeLen:])
+	case splitEncodingType:
+		p, err = splitDecodePackage(data[encodingTypeLen:])
 	default:
 		return nil, ErrInvalidEncodingType
 	}
+	if err != nil {
+		return nil, err
+	}
+	if keep != nil {
+		p.keepFiles(keep)
+	}
+	return p, nil
+}
+
+// SourceHash returns a hash of the encoded package data, as returned by
+// Encode. Packages with the same documentation source have the same hash,
+// regardless of the version they belong to.
+func SourceHash(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
+
+// ErrEncodingMismatch is returned by VerifyEncoding when the two encodings of
+// a package decode to different packages.
+var ErrEncodingMismatch = errors.New("encodings decode to different packages")
+
+// VerifyEncoding checks data, the result of calling Encode on p, against the
+// encoding that Encode would not have used: if the experiment that selects
+// the split encoding is active, that is the fast encoding, and otherwise the
+// split encoding. It encodes p with that encoding, decodes both, and returns
+// an error wrapping ErrEncodingMismatch if they differ. That way a change of
+// encoding can be checked on real packages before any are written with it.
+//
+// Like Encode, VerifyEncoding must be called before p is rendered.
+func (p *Package) VerifyEncoding(ctx context.Context, data []byte) (err error) {
+	defer derrors.Wrap(&err, "godoc.Package.VerifyEncoding()")
+
+	var other []byte
+	if experiment.IsActive(ctx, internal.ExperimentParallelDocRender) {
+		other, err = p.fastEncode()
+	} else {
+		other, err = p.splitEncode()
+	}
+	if err != nil {
+		return err
+	}
+	p1, err := decodeAll(data)
+	if err != nil {
+		return err
+	}
+	p2, err := decodeAll(other)
+	if err != nil {
+		return err
+	}
+	fsb1, err := fsetToBytes(p1.Fset)
+	if err != nil {
+		return err
+	}
+	fsb2, err := fsetToBytes(p2.Fset)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(fsb1, fsb2) {
+		return fmt.Errorf("file sets: %w", ErrEncodingMismatch)
+	}
+	// The decoded ASTs are compared, rather than encodings of them, because
+	// encodings of maps, like those of scopes, are not deterministic.
+	if len(p1.Files) != len(p2.Files) {
+		return fmt.Errorf("got %d and %d files: %w", len(p1.Files), len(p2.Files), ErrEncodingMismatch)
+	}
+	for i, f1 := range p1.Files {
+		if !reflect.DeepEqual(f1, p2.Files[i]) {
+			return fmt.Errorf("%s: %w", f1.Name, ErrEncodingMismatch)
+		}
+	}
+	if !maps.Equal(p1.ModulePackagePaths, p2.ModulePackagePaths) {
+		return fmt.Errorf("module package paths: %w", ErrEncodingMismatch)
+	}
+	return nil
+}
+
+// decodeAll decodes data and the ASTs of all of its files.
+func decodeAll(data []byte) (*Package, error) {
+	p, err := DecodePackage(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.decodeFiles(); err != nil {
+		return nil, err
+	}
+	return p, nil
 }
 
 func (p *Package) fastEncode() (_ []byte, err error) {
@@ -101,6 +223,208 @@ func fastDecodePackage(data []byte) (_ *Package, err error) {
 	}, nil
 }
 
+// splitEncode encodes p like fastEncode, except that the ASTs of the files
+// are encoded with codec.EncodeSections, and so can be decoded independently
+// of one another.
+func (p *Package) splitEncode() (_ []byte, err error) {
+	defer derrors.Wrap(&err, "godoc.Package.splitEncode()")
+
+	var buf bytes.Buffer
+	io.WriteString(&buf, splitEncodingType)
+	enc := codec.NewEncoder()
+	fsb, err := fsetToBytes(p.Fset)
+	if err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(fsb); err != nil {
+		return nil, err
+	}
+	ep := encPackage{ModulePackagePaths: p.ModulePackagePaths}
+	var asts []any
+	for _, f := range p.Files {
+		f2 := *f
+		f2.AST = nil
+		ep.Files = append(ep.Files, &f2)
+		asts = append(asts, f.AST)
+	}
+	if err := enc.Encode(&ep); err != nil {
+		return nil, err
+	}
+	sections, err := codec.EncodeSections(asts)
+	if err != nil {
+		return nil, err
+	}
+	if err := enc.Encode(sections); err != nil {
+		return nil, err
+	}
+	buf.Write(enc.Bytes())
+	return buf.Bytes(), nil
+}
+
+// splitDecodePackage decodes data encoded by splitEncode. The ASTs of the
+// files are not decoded until they are needed; see Package.decodeFiles.
+func splitDecodePackage(data []byte) (_ *Package, err error) {
+	defer derrors.Wrap(&err, "splitDecodePackage()")
+
+	dec := codec.NewDecoder(data)
+	x, err := dec.Decode()
+	if err != nil {
+		return nil, err
+	}
+	fsetBytes, ok := x.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("first decoded value is %T, wanted []byte", x)
+	}
+	fset, err := fsetFromBytes(fsetBytes)
+	if err != nil {
+		return nil, err
+	}
+	x, err = dec.Decode()
+	if err != nil {
+		return nil, err
+	}
+	ep, ok := x.(*encPackage)
+	if !ok {
+		return nil, fmt.Errorf("second decoded value is %T, wanted *encPackage", x)
+	}
+	x, err = dec.Decode()
+	if err != nil {
+		return nil, err
+	}
+	sectionBytes, ok := x.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("third decoded value is %T, wanted []byte", x)
+	}
+	sections, err := codec.NewSectionDecoder(sectionBytes)
+	if err != nil {
+		return nil, err
+	}
+	if sections.Len() != len(ep.Files) {
+		return nil, fmt.Errorf("got %d encoded ASTs for %d files", sections.Len(), len(ep.Files))
+	}
+	p := &Package{
+		Fset:       fset,
+		encPackage: *ep,
+		sections:   sections,
+	}
+	for i := range ep.Files {
+		p.fileSections = append(p.fileSections, i)
+	}
+	return p, nil
+}
+
+// filesDecodePackage decodes data in the files encoding, which splitEncode
+// wrote before it used sections. Like splitDecodePackage, it leaves the ASTs
+// of the files to Package.decodeFiles.
+func filesDecodePackage(data []byte) (_ *Package, err error) {
+	defer derrors.Wrap(&err, "filesDecodePackage()")
+
+	dec := codec.NewDecoder(data)
+	x, err := dec.Decode()
+	if err != nil {
+		return nil, err
+	}
+	fsetBytes, ok := x.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("first decoded value is %T, wanted []byte", x)
+	}
+	fset, err := fsetFromBytes(fsetBytes)
+	if err != nil {
+		return nil, err
+	}
+	x, err = dec.Decode()
+	if err != nil {
+		return nil, err
+	}
+	ep, ok := x.(*encPackage)
+	if !ok {
+		return nil, fmt.Errorf("second decoded value is %T, wanted *encPackage", x)
+	}
+	files := make(encodedFiles, len(ep.Files))
+	p := &Package{
+		Fset:       fset,
+		encPackage: *ep,
+		sections:   files,
+	}
+	for i := range ep.Files {
+		x, err := dec.Decode()
+		if err != nil {
+			return nil, err
+		}
+		b, ok := x.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("decoded value for file %d is %T, wanted []byte", i, x)
+		}
+		files[i] = b
+		p.fileSections = append(p.fileSections, i)
+	}
+	return p, nil
+}
+
+// A fileDecoder decodes the encoded ASTs of the files of a package, given
+// their indexes.
+type fileDecoder interface {
+	Decode(i int) (any, error)
+}
+
+// encodedFiles holds the ASTs of files in the files encoding, each encoded
+// with its own Encoder.
+type encodedFiles [][]byte
+
+func (fs encodedFiles) Decode(i int) (any, error) {
+	return codec.NewDecoder(fs[i]).Decode()
+}
+
+// keepFiles removes the files of p for which keep returns false.
+func (p *Package) keepFiles(keep func(*File) bool) {
+	var files []*File
+	var fileSections []int
+	for i, f := range p.Files {
+		if !keep(f) {
+			continue
+		}
+		files = append(files, f)
+		if p.sections != nil {
+			fileSections = append(fileSections, p.fileSections[i])
+		}
+	}
+	p.Files = files
+	p.fileSections = fileSections
+}
+
+// decodeFiles decodes the ASTs of p's files, if they have not been decoded
+// already. The files are decoded in parallel.
+func (p *Package) decodeFiles() (err error) {
+	defer derrors.Wrap(&err, "decodeFiles()")
+
+	if p.sections == nil {
+		return nil
+	}
+	var g errgroup.Group
+	g.SetLimit(runtime.GOMAXPROCS(0))
+	for i, f := range p.Files {
+		section := p.fileSections[i]
+		g.Go(func() error {
+			x, err := p.sections.Decode(section)
+			if err != nil {
+				return fmt.Errorf("%s: %v", f.Name, err)
+			}
+			af, ok := x.(*ast.File)
+			if !ok {
+				return fmt.Errorf("%s: decoded value is %T, wanted *ast.File", f.Name, x)
+			}
+			f.AST = af
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	p.sections = nil
+	p.fileSections = nil
+	return nil
+}
+
 // token.FileSet uses some unexported types in its encoding, so we can't use our
 // own codec from it. Instead we use gob and encode the resulting bytes.
 func fsetToBytes(fset *token.FileSet) ([]byte, error) {
@@ -3907,7 +3907,7 @@ func init() {
 		func(d *codec.Decoder) any { var x map[string]bool; decode_map_string_bool(d, &x); return x })
 }
 
-// Fields of File: Name AST
+// Fields of File: Name AST Generated BuildConstraint Licenses
 
 func encode_File(e *codec.Encoder, x *File) {
 	if !e.StartStruct(x == nil, x) {
@@ -3921,6 +3921,18 @@ func encode_File(e *codec.Encoder, x *File) {
 		e.EncodeUint(1)
 		encode_ast_File(e, x.AST)
 	}
+	if x.Generated {
+		e.EncodeUint(2)
+		e.EncodeBool(x.Generated)
+	}
+	if x.BuildConstraint != "" {
+		e.EncodeUint(3)
+		e.EncodeString(x.BuildConstraint)
+	}
+	if x.Licenses != nil {
+		e.EncodeUint(4)
+		encode_slice_string(e, x.Licenses)
+	}
 	e.EndStruct()
 }
 
@@ -3945,6 +3957,12 @@ func decode_File(d *codec.Decoder, p **File) {
 			x.Name = d.DecodeString()
 		case 1:
 			decode_ast_File(d, &x.AST)
+		case 2:
+			x.Generated = d.DecodeBool()
+		case 3:
+			x.BuildConstraint = d.DecodeString()
+		case 4:
+			decode_slice_string(d, &x.Licenses)
 		default:
 			d.UnknownField("File", n)
 		}
@@ -4071,6 +4089,35 @@ func init() {
 		})
 }
 
+func encode_slice_string(e *codec.Encoder, s []string) {
+	if s == nil {
+		e.EncodeNil()
+		return
+	}
+	e.StartList(len(s))
+	for _, x := range s {
+		e.EncodeString(x)
+	}
+}
+
+func decode_slice_string(d *codec.Decoder, p *[]string) {
+	n := d.StartList()
+	if n < 0 {
+		return
+	}
+	s := make([]string, n)
+	for i := 0; i < n; i++ {
+		s[i] = d.DecodeString()
+	}
+	*p = s
+}
+
+func init() {
+	codec.Register([]string(nil),
+		func(e *codec.Encoder, x any) { encode_slice_string(e, x.([]string)) },
+		func(d *codec.Decoder) any { var x []string; decode_slice_string(d, &x); return x })
+}
+
 func encode_slice_ast_Decl(e *codec.Encoder, s []ast.Decl) {
 	if s == nil {
 		e.EncodeNil()
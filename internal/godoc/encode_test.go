@@ -7,6 +7,7 @@ package godoc
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/parser"
@@ -20,33 +21,202 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/experiment"
+	"golang.org/x/pkgsite/internal/godoc/codec"
 )
 
 var packageToTest string = filepath.Join(runtime.GOROOT(), "src", "net", "http")
 
 func TestEncodeDecodePackage(t *testing.T) {
-	p, err := packageForDir(packageToTest, true)
+	for _, split := range []bool{false, true} {
+		t.Run(fmt.Sprintf("split=%t", split), func(t *testing.T) {
+			ctx := context.Background()
+			if split {
+				ctx = experiment.NewContext(ctx, internal.ExperimentParallelDocRender)
+			}
+			p, err := packageForDir(packageToTest, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var want, got bytes.Buffer
+			printPackage(&want, p)
+			data, err := p.Encode(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p2, err := DecodePackage(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p2.decodeFiles(); err != nil {
+				t.Fatal(err)
+			}
+			printPackage(&got, p2)
+			// Diff the textual output of printPackage, because cmp.Diff takes too long
+			// on the Packages themselves.
+			if diff := cmp.Diff(want.String(), got.String()); diff != "" {
+				t.Errorf("package differs after decoding (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVerifyEncoding(t *testing.T) {
+	for _, split := range []bool{false, true} {
+		t.Run(fmt.Sprintf("split=%t", split), func(t *testing.T) {
+			ctx := context.Background()
+			if split {
+				ctx = experiment.NewContext(ctx, internal.ExperimentParallelDocRender)
+			}
+			p, err := packageForDir(filepath.Join("testdata", "p"), false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := p.Encode(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p.VerifyEncoding(ctx, data); err != nil {
+				t.Fatal(err)
+			}
+			// Change the package after encoding it, so that its other
+			// encoding no longer matches.
+			p.Files[0].Generated = !p.Files[0].Generated
+			if err := p.VerifyEncoding(ctx, data); !errors.Is(err, ErrEncodingMismatch) {
+				t.Errorf("got error %v, want %v", err, ErrEncodingMismatch)
+			}
+		})
+	}
+}
+
+func TestSplitDecodeIsLazy(t *testing.T) {
+	ctx := experiment.NewContext(context.Background(), internal.ExperimentParallelDocRender)
+	p, err := packageForDir(filepath.Join("testdata", "p"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := p.Encode(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data[:encodingTypeLen]); got != splitEncodingType {
+		t.Fatalf("encoding type = %q, want %q", got, splitEncodingType)
+	}
+	p2, err := DecodePackage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p2.Files) != len(p.Files) {
+		t.Fatalf("got %d files, want %d", len(p2.Files), len(p.Files))
+	}
+	for i, f := range p2.Files {
+		if f.AST != nil {
+			t.Errorf("%s: AST decoded before it was needed", f.Name)
+		}
+		if f.Name != p.Files[i].Name {
+			t.Errorf("file %d: got name %q, want %q", i, f.Name, p.Files[i].Name)
+		}
+	}
+	if err := p2.decodeFiles(); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range p2.Files {
+		if f.AST == nil {
+			t.Errorf("%s: AST not decoded", f.Name)
+		}
+	}
+}
+
+func TestDecodeFilesEncoding(t *testing.T) {
+	p, err := packageForDir(filepath.Join("testdata", "p"), false)
 	if err != nil {
 		t.Fatal(err)
 	}
 	var want, got bytes.Buffer
 	printPackage(&want, p)
-	data, err := p.Encode(context.Background())
+
+	// Encode p as splitEncode did before it used sections.
+	var buf bytes.Buffer
+	io.WriteString(&buf, filesEncodingType)
+	enc := codec.NewEncoder()
+	fsb, err := fsetToBytes(p.Fset)
 	if err != nil {
 		t.Fatal(err)
 	}
-	p2, err := DecodePackage(data)
+	if err := enc.Encode(fsb); err != nil {
+		t.Fatal(err)
+	}
+	ep := encPackage{ModulePackagePaths: p.ModulePackagePaths}
+	for _, f := range p.Files {
+		f2 := *f
+		f2.AST = nil
+		ep.Files = append(ep.Files, &f2)
+	}
+	if err := enc.Encode(&ep); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range p.Files {
+		fenc := codec.NewEncoder()
+		if err := fenc.Encode(f.AST); err != nil {
+			t.Fatal(err)
+		}
+		if err := enc.Encode(fenc.Bytes()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buf.Write(enc.Bytes())
+
+	p2, err := DecodePackage(buf.Bytes())
 	if err != nil {
 		t.Fatal(err)
 	}
+	if err := p2.decodeFiles(); err != nil {
+		t.Fatal(err)
+	}
 	printPackage(&got, p2)
-	// Diff the textual output of printPackage, because cmp.Diff takes too long
-	// on the Packages themselves.
 	if diff := cmp.Diff(want.String(), got.String()); diff != "" {
 		t.Errorf("package differs after decoding (-want, +got):\n%s", diff)
 	}
 }
 
+func TestDecodePackageFiles(t *testing.T) {
+	notTest := func(f *File) bool { return !strings.HasSuffix(f.Name, "_test.go") }
+	for _, split := range []bool{false, true} {
+		t.Run(fmt.Sprintf("split=%t", split), func(t *testing.T) {
+			ctx := context.Background()
+			if split {
+				ctx = experiment.NewContext(ctx, internal.ExperimentParallelDocRender)
+			}
+			p, err := packageForDir(filepath.Join("testdata", "p"), false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := p.Encode(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p2, err := DecodePackageFiles(data, notTest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := p2.decodeFiles(); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range p2.Files {
+				if f.AST == nil {
+					t.Errorf("%s: AST not decoded", f.Name)
+				}
+				got = append(got, filepath.Base(f.Name))
+			}
+			if want := []string{"p.go"}; !cmp.Equal(got, want) {
+				t.Errorf("got files %v, want %v", got, want)
+			}
+		})
+	}
+}
+
 func TestObjectIdentity(t *testing.T) {
 	// Check that encoding and decoding preserves object identity.
 	ctx := context.Background()
@@ -74,17 +244,22 @@ func main() { a = 1 }
 	}
 	compareObjs(f)
 
-	p := NewPackage(fset, nil)
-	p.AddFile(f, false)
-	data, err := p.Encode(ctx)
-	if err != nil {
-		t.Fatal(err)
-	}
-	p, err = DecodePackage(data)
-	if err != nil {
-		t.Fatal(err)
+	for _, ctx := range []context.Context{ctx, experiment.NewContext(ctx, internal.ExperimentParallelDocRender)} {
+		p := NewPackage(fset, nil)
+		p.AddFile(f, false)
+		data, err := p.Encode(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err = DecodePackage(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.decodeFiles(); err != nil {
+			t.Fatal(err)
+		}
+		compareObjs(p.Files[0].AST)
 	}
-	compareObjs(p.Files[0].AST)
 }
 
 func packageForDir(dir string, removeNodes bool) (*Package, error) {
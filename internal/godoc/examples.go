@@ -0,0 +1,126 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+)
+
+// outputRx matches the text of an example output comment.
+// Keep consistent with outputPrefix in GOROOT/src/go/doc/example.go.
+var outputRx = regexp.MustCompile(`(?i)^[[:space:]]*(unordered )?output:`)
+
+// skippedExamples returns the example functions in the test files of p that
+// are not displayed correctly in d, the documentation computed from p.
+//
+// Examples that do not compile are not reported, since that would require
+// the types of the package's dependencies, which are not available when a
+// module is fetched. Type-checking without them reports errors in valid
+// code, such as calls to methods promoted from embedded fields of imported
+// types.
+func (p *Package) skippedExamples(d *doc.Package) []*internal.SkippedExample {
+	shown := map[string]bool{}
+	dochtml.WalkExamples(d, func(_ string, ex *doc.Example) {
+		shown[ex.Name] = true
+	})
+	var skipped []*internal.SkippedExample
+	for _, f := range p.Files {
+		if !strings.HasSuffix(f.Name, "_test.go") {
+			continue
+		}
+		for _, decl := range f.AST.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Recv != nil || !isExampleName(fd.Name.Name) {
+				continue
+			}
+			var reason string
+			if name := strings.TrimPrefix(fd.Name.Name, "Example"); shown[name] {
+				reason = misplacedOutputReason(fd, f.AST.Comments)
+			} else {
+				reason = skippedExampleReason(fd, name)
+			}
+			if reason == "" {
+				continue
+			}
+			pos := p.Fset.Position(fd.Pos())
+			skipped = append(skipped, &internal.SkippedExample{
+				Name:   fd.Name.Name,
+				File:   path.Base(pos.Filename),
+				Line:   pos.Line,
+				Reason: reason,
+			})
+		}
+	}
+	return skipped
+}
+
+// isExampleName reports whether name is the name of an example function.
+// Keep consistent with isTest in GOROOT/src/go/doc/example.go.
+func isExampleName(name string) bool {
+	const prefix = "Example"
+	if !strings.HasPrefix(name, prefix) {
+		return false
+	}
+	if len(name) == len(prefix) {
+		return true
+	}
+	r, _ := utf8.DecodeRuneInString(name[len(prefix):])
+	return !unicode.IsLower(r)
+}
+
+// skippedExampleReason explains why go/doc did not associate the example
+// function fd, whose name without the "Example" prefix is name, with the
+// package or one of its identifiers.
+func skippedExampleReason(fd *ast.FuncDecl, name string) string {
+	if fd.Type.Params.NumFields() > 0 || fd.Type.Results.NumFields() > 0 {
+		return "An example function must not have parameters or results."
+	}
+	id := name
+	if i := strings.LastIndexByte(name, '_'); i >= 0 {
+		if r, _ := utf8.DecodeRuneInString(name[i+1:]); unicode.IsLower(r) {
+			id = name[:i]
+		}
+	}
+	if id == "" || strings.HasPrefix(id, "_") {
+		return "The suffix of an example name must start with a lowercase letter."
+	}
+	return fmt.Sprintf("The package has no exported identifier %s for the example to belong to.",
+		strings.Replace(id, "_", ".", 1))
+}
+
+// misplacedOutputReason returns a reason to report the displayed example
+// function fd if one of its comments is an output comment but not its last
+// one. go/doc then ignores the output, and the displayed code stops at that
+// comment. It returns the empty string if fd has no such comment.
+func misplacedOutputReason(fd *ast.FuncDecl, comments []*ast.CommentGroup) string {
+	if fd.Body == nil {
+		return ""
+	}
+	var inBody []*ast.CommentGroup
+	for _, cg := range comments {
+		if cg.Pos() > fd.Body.Lbrace && cg.End() < fd.Body.Rbrace {
+			inBody = append(inBody, cg)
+		}
+	}
+	if len(inBody) == 0 || outputRx.MatchString(inBody[len(inBody)-1].Text()) {
+		return ""
+	}
+	for _, cg := range inBody {
+		if outputRx.MatchString(cg.Text()) {
+			return "Its output comment is not the last comment in the function, so the output is not checked and the code after it is not displayed."
+		}
+	}
+	return ""
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestSkippedExamples(t *testing.T) {
+	const (
+		src = `package p
+
+type T int
+
+func (T) M() {}
+
+func F() {}
+`
+		test = `package p_test
+
+import "fmt"
+
+func Example() {}
+
+func ExampleF() {
+	fmt.Println("hi")
+	// Output: hi
+}
+
+func ExampleT_M() {}
+
+func ExampleT_suffix() {}
+
+func ExampleG() {}
+
+func ExampleT_N() {}
+
+func Example_Upper() {}
+
+func ExampleF_params(s string) {}
+
+func ExampleT() {
+	// Output:
+	// 1
+	fmt.Println(1)
+	// done
+}
+
+func Examples() {}
+
+// Examples are not type-checked; see skippedExamples.
+func ExampleT_promoted() {
+	var s struct{ fmt.Stringer }
+	fmt.Println(s.String())
+}
+`
+	)
+	fset := token.NewFileSet()
+	p := NewPackage(fset, nil)
+	for _, f := range []struct{ name, src string }{{"p.go", src}, {"p_test.go", test}} {
+		af, err := parser.ParseFile(fset, f.name, f.src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.AddFile(af, true)
+	}
+	_, _, _, got, _, err := p.DocInfo(context.Background(), "p", nil, &ModuleInfo{ModulePath: "example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*internal.SkippedExample{
+		{Name: "ExampleG", File: "p_test.go", Line: 16,
+			Reason: "The package has no exported identifier G for the example to belong to."},
+		{Name: "ExampleT_N", File: "p_test.go", Line: 18,
+			Reason: "The package has no exported identifier T.N for the example to belong to."},
+		{Name: "Example_Upper", File: "p_test.go", Line: 20,
+			Reason: "The suffix of an example name must start with a lowercase letter."},
+		{Name: "ExampleF_params", File: "p_test.go", Line: 22,
+			Reason: "An example function must not have parameters or results."},
+		{Name: "ExampleT", File: "p_test.go", Line: 24,
+			Reason: "Its output comment is not the last comment in the function, so the output is not checked and the code after it is not displayed."},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
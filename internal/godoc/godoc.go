@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/licenses"
 )
 
 var ErrTooLarge = dochtml.ErrTooLarge
@@ -22,6 +23,11 @@ type Package struct {
 	Fset *token.FileSet
 	encPackage
 	renderCalled bool
+	// If the ASTs of Files have not been decoded yet, sections holds
+	// their encodings, and fileSections[i] is the section of Files[i].
+	// See splitDecodePackage and filesDecodePackage.
+	sections     fileDecoder
+	fileSections []int
 }
 
 // encPackage holds the fields of Package that can be directly encoded.
@@ -43,8 +49,17 @@ type encPackage struct { // fields that can be directly encoded
 
 // A File contains everything needed about a source file to render documentation.
 type File struct {
-	Name string // full file pathname relative to zip content directory
-	AST  *ast.File
+	Name      string // full file pathname relative to zip content directory
+	AST       *ast.File
+	Generated bool // file has a "Code generated ... DO NOT EDIT." comment
+	// BuildConstraint is the file's build constraint, from its name and its
+	// //go:build line, such as "linux && amd64". It is empty if the file is
+	// built on all platforms.
+	BuildConstraint string
+	// Licenses are the license types declared by the file's header comment,
+	// as files copied from other projects often do. It is empty if the
+	// header declares no license.
+	Licenses []string
 }
 
 // NewPackage returns a new Package with the given fset and set of module package paths.
@@ -61,17 +76,37 @@ func NewPackage(fset *token.FileSet, modPaths map[string]bool) *Package {
 // are unsuitable for anything other than the methods of this package.
 func (p *Package) AddFile(f *ast.File, removeNodes bool) {
 	filename := p.Fset.Position(f.Package).Filename
+	bc := buildConstraint(filename, f)
+	lics := licenses.DetectHeader(fileHeader(f))
 	// Don't trim anything from a test file or one in a XXX_test package; it
 	// may be part of a playable example.
 	if removeNodes && !strings.HasSuffix(filename, "_test.go") && !strings.HasSuffix(f.Name.Name, "_test") {
 		removeUnusedASTNodes(f)
 	}
 	p.Files = append(p.Files, &File{
-		Name: filename,
-		AST:  f,
+		Name:            filename,
+		AST:             f,
+		Generated:       ast.IsGenerated(f),
+		BuildConstraint: bc,
+		Licenses:        lics,
 	})
 }
 
+// fileHeader returns the text of the comments of f that precede its package
+// clause, other than the package documentation.
+func fileHeader(f *ast.File) string {
+	var b strings.Builder
+	for _, cg := range f.Comments {
+		if cg.Pos() >= f.Package {
+			break
+		}
+		if cg != f.Doc {
+			b.WriteString(cg.Text())
+		}
+	}
+	return b.String()
+}
+
 // removeUnusedASTNodes removes parts of the AST not needed for documentation.
 // It doesn't remove unexported consts, vars or types, although it probably could.
 func removeUnusedASTNodes(pf *ast.File) {
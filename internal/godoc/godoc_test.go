@@ -6,6 +6,7 @@ package godoc
 
 import (
 	"bytes"
+	"context"
 	"go/format"
 	"go/parser"
 	"go/token"
@@ -110,3 +111,102 @@ func (t) U()
 		t.Errorf("mismatch (-want, +got):\n%s", diff)
 	}
 }
+
+func TestGeneratedFile(t *testing.T) {
+	for _, test := range []struct {
+		src  string
+		want bool
+	}{
+		{"// Code generated by stringer. DO NOT EDIT.\n\npackage p\n", true},
+		{"// Package p does things.\npackage p\n", false},
+	} {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "f.go", test.src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := NewPackage(fset, nil)
+		p.AddFile(f, true)
+		data, err := p.Encode(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		p2, err := DecodePackage(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p2.Files[0].Generated; got != test.want {
+			t.Errorf("%q: got Generated %t, want %t", test.src, got, test.want)
+		}
+	}
+}
+
+func TestFileLicenses(t *testing.T) {
+	for _, test := range []struct {
+		src  string
+		want []string
+	}{
+		{"// SPDX-License-Identifier: Apache-2.0\n\n// Package p does things.\npackage p\n", []string{"Apache-2.0"}},
+		{"// Copyright 2026 The Go Authors. All rights reserved.\n\npackage p\n", nil},
+		// The package doc comment is not a license header.
+		{"// SPDX-License-Identifier: MIT\npackage p\n", nil},
+	} {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "f.go", test.src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := NewPackage(fset, nil)
+		p.AddFile(f, true)
+		data, err := p.Encode(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		p2, err := DecodePackage(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, p2.Files[0].Licenses); diff != "" {
+			t.Errorf("%q: mismatch (-want, +got):\n%s", test.src, diff)
+		}
+	}
+}
+
+func TestBuildConstraint(t *testing.T) {
+	for _, test := range []struct {
+		filename, src string
+		want          string
+	}{
+		{"f.go", "package p\n", ""},
+		{"linux.go", "package p\n", ""},
+		{"f_linux.go", "package p\n", "linux"},
+		{"f_amd64.go", "package p\n", "amd64"},
+		{"f_linux_amd64.go", "package p\n", "linux && amd64"},
+		{"f_linux_test.go", "package p\n", "linux"},
+		{"f_other.go", "package p\n", ""},
+		{"f.go", "//go:build linux || darwin\n\npackage p\n", "linux || darwin"},
+		{"f_arm64.go", "//go:build linux || darwin\n\npackage p\n", "arm64 && (linux || darwin)"},
+		{"f.go", "// +build linux darwin\n// +build cgo\n\npackage p\n", "(linux || darwin) && cgo"},
+		{"f.go", "//go:build linux\n// +build linux darwin\n\npackage p\n", "linux"},
+		{"f.go", "// Package p does things.\npackage p\n\n//go:build linux\n", ""},
+	} {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, test.filename, test.src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := NewPackage(fset, nil)
+		p.AddFile(f, true)
+		data, err := p.Encode(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		p2, err := DecodePackage(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p2.Files[0].BuildConstraint; got != test.want {
+			t.Errorf("%s, %q: got %q, want %q", test.filename, test.src, got, test.want)
+		}
+	}
+}
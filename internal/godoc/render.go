@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"go/ast"
 	"go/doc"
+	"net/url"
 	"path"
+	"slices"
 	"sort"
 	"strings"
 
@@ -18,7 +20,11 @@ import (
 	"golang.org/x/mod/semver"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/experiment"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
+	"golang.org/x/pkgsite/internal/godoc/docjson"
+	"golang.org/x/pkgsite/internal/godoc/docllms"
+	"golang.org/x/pkgsite/internal/godoc/docmarkdown"
 	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/stdlib"
 )
@@ -41,24 +47,56 @@ const (
 // It is a variable for testing.
 var MaxDocumentationHTML = 40 * megabyte
 
-// DocInfo returns information extracted from the package's documentation.
+// DocInfo returns information extracted from the package's documentation,
+// including the examples that could not be displayed with it, and the doc
+// links to symbols of packages in its module.
 // This destroys p's AST; do not call any methods of p after it returns.
 func (p *Package) DocInfo(ctx context.Context, innerPath string, sourceInfo *source.Info, modInfo *ModuleInfo) (
-	synopsis string, imports []string, api []*internal.Symbol, err error) {
+	synopsis string, imports []string, api []*internal.Symbol, skipped []*internal.SkippedExample, links []*DocLink, err error) {
 	// This is mostly copied from internal/fetch/fetch.go.
 	defer derrors.Wrap(&err, "godoc.Package.DocInfo(%q, %q, %q)", modInfo.ModulePath, modInfo.ResolvedVersion, innerPath)
 
 	p.renderCalled = true
+	if err := p.decodeFiles(); err != nil {
+		return "", nil, nil, nil, nil, err
+	}
+	pkgClause := p.packageDocClause()
 	d, err := p.DocPackage(innerPath, modInfo)
 	if err != nil {
-		return "", nil, nil, err
+		return "", nil, nil, nil, nil, err
 	}
 
+	links = p.moduleDocLinks(d, modInfo, pkgClause)
 	api, err = dochtml.GetSymbols(d, p.Fset)
 	if err != nil {
-		return "", nil, nil, err
+		return "", nil, nil, nil, nil, err
 	}
-	return d.Synopsis(d.Doc), cleanImports(d.Imports, d.ImportPath), api, nil
+	return d.Synopsis(d.Doc), cleanImports(d.Imports, d.ImportPath), api, p.skippedExamples(d), links, nil
+}
+
+// PackageDocText returns the package comment of the package encoded in
+// source, as plain text. Test files are not decoded.
+func PackageDocText(source []byte) (_ string, err error) {
+	defer derrors.Wrap(&err, "godoc.PackageDocText")
+	p, err := DecodePackageFiles(source, func(f *File) bool {
+		return !strings.HasSuffix(f.Name, "_test.go")
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := p.decodeFiles(); err != nil {
+		return "", err
+	}
+	var files []*ast.File
+	for _, f := range p.Files {
+		files = append(files, f.AST)
+	}
+	// The import path only affects links, which are rendered as text.
+	d, err := doc.NewFromFiles(p.Fset, files, "main")
+	if err != nil {
+		return "", err
+	}
+	return string(d.Text(d.Doc)), nil
 }
 
 // cleanImports cleans import paths, in the sense of path.Clean.
@@ -114,6 +152,9 @@ func (p *Package) DocPackage(innerPath string, modInfo *ModuleInfo) (_ *doc.Pack
 	if noFiltering {
 		m |= doc.AllDecls
 	}
+	if err := p.decodeFiles(); err != nil {
+		return nil, err
+	}
 	var allGoFiles []*ast.File
 	for _, f := range p.Files {
 		allGoFiles = append(allGoFiles, f.AST)
@@ -161,15 +202,54 @@ func (p *Package) renderOptions(innerPath string, sourceInfo *source.Info, modIn
 		}
 		return sourceInfo.FileURL(path.Join(innerPath, filename))
 	}
+	generated := map[string]bool{}
+	for _, f := range p.Files {
+		if f.Generated {
+			generated[f.Name] = true
+		}
+	}
+	isGeneratedFunc := func(n ast.Node) bool {
+		return generated[p.Fset.Position(n.Pos()).Filename]
+	}
+	fileLicenses := map[string][]string{}
+	for _, f := range p.Files {
+		if !licensesCovered(f.Licenses, modInfo.LicenseTypes) {
+			fileLicenses[f.Name] = f.Licenses
+		}
+	}
+	fileLicensesFunc := func(n ast.Node) []string {
+		return fileLicenses[p.Fset.Position(n.Pos()).Filename]
+	}
 
+	var issueURLFunc func(string) string
+	if sourceInfo != nil && !modInfo.NoIssueLinks {
+		issueURLFunc = sourceInfo.IssueURL
+	}
+
+	sinceVersion := sinceVersionFunc(modInfo.ModulePath, nameToVersion)
 	return dochtml.RenderOptions{
-		FileLinkFunc:     fileLinkFunc,
-		SourceLinkFunc:   sourceLinkFunc,
-		ModInfo:          modInfo,
-		SinceVersionFunc: sinceVersionFunc(modInfo.ModulePath, nameToVersion),
-		Limit:            int64(MaxDocumentationHTML),
-		BuildContext:     bc,
+		FileLinkFunc:        fileLinkFunc,
+		SourceLinkFunc:      sourceLinkFunc,
+		IsGeneratedFunc:     isGeneratedFunc,
+		FileLicensesFunc:    fileLicensesFunc,
+		IssueURLFunc:        issueURLFunc,
+		ModInfo:             modInfo,
+		SinceVersionFunc:    sinceVersion,
+		SinceVersionURLFunc: sinceVersionURLFunc(modInfo.ModulePath, innerPath, sinceVersion),
+		Limit:               int64(MaxDocumentationHTML),
+		BuildContext:        bc,
+	}
+}
+
+// licensesCovered reports whether each of the license types declared by a
+// file is one of the module's license types.
+func licensesCovered(fileTypes, moduleTypes []string) bool {
+	for _, t := range fileTypes {
+		if !slices.Contains(moduleTypes, t) {
+			return false
+		}
 	}
+	return true
 }
 
 // sinceVersionFunc returns a func that reports the version when the symbol
@@ -214,11 +294,33 @@ func sinceVersionFunc(modulePath string, nameToVersion map[string]string) func(n
 	}
 }
 
-// Render renders the documentation for the package.
+// sinceVersionURLFunc returns a func that returns the URL that explains the
+// version reported by sinceVersion for a symbol, or nil if there is none.
+// Symbols of the standard library link to the release notes of the Go
+// release that introduced them, at the section about the package at pkgPath
+// if there is one; see the /go-release-notes handler of the frontend.
+func sinceVersionURLFunc(modulePath, pkgPath string, sinceVersion func(string) string) func(string) string {
+	if modulePath != stdlib.ModulePath {
+		return nil
+	}
+	return func(name string) string {
+		tag := sinceVersion(name)
+		if tag == "" {
+			return ""
+		}
+		return "/go-release-notes?" + url.Values{
+			"path":    {pkgPath},
+			"version": {stdlib.ReleaseForTag(tag)},
+		}.Encode()
+	}
+}
+
+// Render renders the documentation for the package. If expandExamples is
+// true, the examples are rendered expanded.
 // Rendering destroys p's AST; do not call any methods of p after it returns.
 func (p *Package) Render(ctx context.Context, innerPath string,
 	sourceInfo *source.Info, modInfo *ModuleInfo, nameToVersion map[string]string,
-	bc internal.BuildContext) (_ *dochtml.Parts, err error) {
+	bc internal.BuildContext, expandExamples bool) (_ *dochtml.Parts, err error) {
 	p.renderCalled = true
 
 	d, err := p.DocPackage(innerPath, modInfo)
@@ -227,6 +329,8 @@ func (p *Package) Render(ctx context.Context, innerPath string,
 	}
 
 	opts := p.renderOptions(innerPath, sourceInfo, modInfo, nameToVersion, bc)
+	opts.Concurrent = experiment.IsActive(ctx, internal.ExperimentParallelDocRender)
+	opts.ExpandExamples = expandExamples
 	parts, err := dochtml.Render(ctx, p.Fset, d, opts)
 	if errors.Is(err, ErrTooLarge) {
 		return &dochtml.Parts{Body: template.MustParseAndExecuteToHTML(DocTooLargeReplacement)}, nil
@@ -237,24 +341,99 @@ func (p *Package) Render(ctx context.Context, innerPath string,
 	return parts, nil
 }
 
+// RenderMarkdown renders the documentation for the package as Markdown.
+// Rendering destroys p's AST; do not call any methods of p after it returns.
+func (p *Package) RenderMarkdown(innerPath string, modInfo *ModuleInfo, opt docmarkdown.Options) (_ []byte, err error) {
+	p.renderCalled = true
+
+	d, err := p.DocPackage(innerPath, modInfo)
+	if err != nil {
+		return nil, err
+	}
+	return docmarkdown.Render(p.Fset, d, opt)
+}
+
+// RenderJSON renders the documentation for the package as JSON, in the form
+// of docjson.Package.
+// Rendering destroys p's AST; do not call any methods of p after it returns.
+func (p *Package) RenderJSON(innerPath string, modInfo *ModuleInfo) (_ []byte, err error) {
+	p.renderCalled = true
+
+	d, err := p.DocPackage(innerPath, modInfo)
+	if err != nil {
+		return nil, err
+	}
+	return docjson.Render(p.Fset, d)
+}
+
+// RenderLLMs renders a digest of the documentation for the package, in the
+// style of llms.txt; see docllms.
+// Rendering destroys p's AST; do not call any methods of p after it returns.
+func (p *Package) RenderLLMs(innerPath string, modInfo *ModuleInfo, opt docllms.Options) (_ []byte, err error) {
+	p.renderCalled = true
+
+	d, err := p.DocPackage(innerPath, modInfo)
+	if err != nil {
+		return nil, err
+	}
+	return docllms.Render(p.Fset, d, opt)
+}
+
 // RenderFromUnit is a convenience function that first decodes the source
 // in the unit, which must exist, and then calls Render.
 func RenderFromUnit(ctx context.Context, u *internal.Unit,
 	bc internal.BuildContext) (_ *dochtml.Parts, err error) {
-	docPkg, err := DecodePackage(u.Documentation[0].Source)
+	docPkg, innerPath, modInfo, err := decodeUnit(u)
 	if err != nil {
 		return nil, err
 	}
+	return docPkg.Render(ctx, innerPath, u.SourceInfo, modInfo, nil, bc, false)
+}
+
+// RenderMarkdownFromUnit is like RenderFromUnit, but calls RenderMarkdown.
+func RenderMarkdownFromUnit(u *internal.Unit, opt docmarkdown.Options) (_ []byte, err error) {
+	docPkg, innerPath, modInfo, err := decodeUnit(u)
+	if err != nil {
+		return nil, err
+	}
+	return docPkg.RenderMarkdown(innerPath, modInfo, opt)
+}
+
+// RenderJSONFromUnit is like RenderFromUnit, but calls RenderJSON.
+func RenderJSONFromUnit(u *internal.Unit) (_ []byte, err error) {
+	docPkg, innerPath, modInfo, err := decodeUnit(u)
+	if err != nil {
+		return nil, err
+	}
+	return docPkg.RenderJSON(innerPath, modInfo)
+}
+
+// RenderLLMsFromUnit is like RenderFromUnit, but calls RenderLLMs.
+func RenderLLMsFromUnit(u *internal.Unit, opt docllms.Options) (_ []byte, err error) {
+	docPkg, innerPath, modInfo, err := decodeUnit(u)
+	if err != nil {
+		return nil, err
+	}
+	return docPkg.RenderLLMs(innerPath, modInfo, opt)
+}
+
+// decodeUnit decodes the source in the unit, which must exist, and returns
+// it with the arguments for rendering it.
+func decodeUnit(u *internal.Unit) (_ *Package, innerPath string, _ *ModuleInfo, err error) {
+	docPkg, err := DecodePackage(u.Documentation[0].Source)
+	if err != nil {
+		return nil, "", nil, err
+	}
 	modInfo := &ModuleInfo{
 		ModulePath:      u.ModulePath,
 		ResolvedVersion: u.Version,
 		ModulePackages:  nil, // will be provided by docPkg
+		BrokenDocLinks:  u.Documentation[0].BrokenDocLinks,
 	}
-	var innerPath string
 	if u.ModulePath == stdlib.ModulePath {
 		innerPath = u.Path
 	} else if u.Path != u.ModulePath {
 		innerPath = u.Path[len(u.ModulePath)+1:]
 	}
-	return docPkg.Render(ctx, innerPath, u.SourceInfo, modInfo, nil, bc)
+	return docPkg, innerPath, modInfo, nil
 }
@@ -7,15 +7,21 @@ package godoc
 import (
 	"context"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/safehtml"
 	"github.com/google/safehtml/template"
 	"golang.org/x/net/html"
 	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/experiment"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
 	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/stdlib"
 	"golang.org/x/pkgsite/internal/testing/htmlcheck"
 )
 
@@ -44,14 +50,14 @@ func TestDocInfo(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			wantSyn, wantImports, _, err := p.DocInfo(ctx, name, si, mi)
+			wantSyn, wantImports, _, _, _, err := p.DocInfo(ctx, name, si, mi)
 			if err != nil {
 				t.Fatal(err)
 			}
 
 			check := func(p *Package) {
 				t.Helper()
-				gotSyn, gotImports, _, err := p.DocInfo(ctx, name, si, mi)
+				gotSyn, gotImports, _, _, _, err := p.DocInfo(ctx, name, si, mi)
 				if err != nil {
 					t.Fatal(err)
 				}
@@ -90,6 +96,31 @@ func TestDocInfo(t *testing.T) {
 
 }
 
+func TestPackageDocText(t *testing.T) {
+	p, err := packageForDir(filepath.Join("testdata", "p"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := p.Encode(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := PackageDocText(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `Package p is for testing godoc.Render. There are a lot of other things to say,
+but that's the gist of it.
+
+# Links
+
+- pkg.go.dev, https://pkg.go.dev
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
 func TestRenderParts_SinceVersion(t *testing.T) {
 	dochtml.LoadTemplates(templateFS)
 	ctx := context.Background()
@@ -119,7 +150,7 @@ func TestRenderParts_SinceVersion(t *testing.T) {
 		// TF is a method.
 		"T.M": "v1.4.0",
 	}
-	parts, err := p.Render(ctx, "p", si, mi, nameToVersion, internal.BuildContext{})
+	parts, err := p.Render(ctx, "p", si, mi, nameToVersion, internal.BuildContext{}, false)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -191,3 +222,111 @@ func TestCleanImports(t *testing.T) {
 		}
 	}
 }
+
+func TestRenderParallel(t *testing.T) {
+	dochtml.LoadTemplates(templateFS)
+	si := source.NewGitHubInfo("a.com/M", "", "abcde")
+
+	// Encode a single Package both ways, because the order of the files
+	// from packageForDir varies. Use a package without OS-specific
+	// files, because which of several declarations of the same symbol
+	// go/doc keeps can vary too.
+	p, err := packageForDir(filepath.Join(runtime.GOROOT(), "src", "go", "ast"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	render := func(ctx context.Context) *dochtml.Parts {
+		t.Helper()
+		data, err := p.Encode(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := DecodePackage(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		mi := &ModuleInfo{ModulePath: "std", ResolvedVersion: "v1.22.0"}
+		parts, err := p.Render(ctx, "go/ast", si, mi, nil, internal.BuildContext{}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parts
+	}
+
+	ctx := context.Background()
+	want := render(ctx)
+	got := render(experiment.NewContext(ctx, internal.ExperimentParallelDocRender))
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(safehtml.HTML{})); diff != "" {
+		t.Errorf("mismatch (-sequential, +parallel):\n%s", diff)
+	}
+}
+
+// Compare the latency of decoding and rendering large packages with and
+// without the parallel-doc-render experiment. The p95-ns/op metric is the
+// 95th percentile of the per-iteration latencies.
+//
+// The parallel version can only be faster with more than one CPU. Run on a
+// single-CPU linux/amd64 VM 10/17/2026, the two were the same within noise
+// (p95 175-210ms for net, 1.3s for cmd/compile/internal/ssa).
+func BenchmarkDecodeAndRender(b *testing.B) {
+	dochtml.LoadTemplates(templateFS)
+	si := source.NewGitHubInfo("a.com/M", "", "abcde")
+	for _, pkg := range []string{"net", "cmd/compile/internal/ssa"} {
+		for _, parallel := range []bool{false, true} {
+			name := "sequential"
+			ctx := context.Background()
+			if parallel {
+				name = "parallel"
+				ctx = experiment.NewContext(ctx, internal.ExperimentParallelDocRender)
+			}
+			b.Run(pkg+"/"+name, func(b *testing.B) {
+				p, err := packageForDir(filepath.Join(runtime.GOROOT(), "src", pkg), true)
+				if err != nil {
+					b.Fatal(err)
+				}
+				data, err := p.Encode(ctx)
+				if err != nil {
+					b.Fatal(err)
+				}
+				durs := make([]time.Duration, b.N)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					start := time.Now()
+					p, err := DecodePackage(data)
+					if err != nil {
+						b.Fatal(err)
+					}
+					mi := &ModuleInfo{ModulePath: "std", ResolvedVersion: "v1.22.0"}
+					if _, err := p.Render(ctx, pkg, si, mi, nil, internal.BuildContext{}, false); err != nil {
+						b.Fatal(err)
+					}
+					durs[i] = time.Since(start)
+				}
+				b.StopTimer()
+				slices.Sort(durs)
+				b.ReportMetric(float64(durs[len(durs)*95/100].Nanoseconds()), "p95-ns/op")
+			})
+		}
+	}
+}
+
+func TestSinceVersionURLFunc(t *testing.T) {
+	sinceVersion := sinceVersionFunc(stdlib.ModulePath, map[string]string{
+		"Marshal":       "v1.0.0",
+		"Valid":         "v1.9.0",
+		"Decoder.Token": "v1.21.0",
+	})
+	f := sinceVersionURLFunc(stdlib.ModulePath, "encoding/json", sinceVersion)
+	for name, want := range map[string]string{
+		"Marshal":       "",
+		"Valid":         "/go-release-notes?path=encoding%2Fjson&version=go1.9",
+		"Decoder.Token": "/go-release-notes?path=encoding%2Fjson&version=go1.21",
+	} {
+		if got := f(name); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+	if f := sinceVersionURLFunc("a.com/M", "a.com/M/p", sinceVersion); f != nil {
+		t.Error("got a func for a module other than the standard library, want nil")
+	}
+}
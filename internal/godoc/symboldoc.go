@@ -0,0 +1,79 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"go/ast"
+	"go/doc"
+	"strings"
+)
+
+// SymbolDocs returns the doc comment of each exported symbol of the package,
+// formatted as plain text and keyed by symbol name. Methods, fields and
+// interface methods are keyed by "Type.Name", as in the symbol tables.
+// Constants and variables declared in a group share the group's comment.
+func (p *Package) SymbolDocs(innerPath string, modInfo *ModuleInfo) (_ map[string]string, err error) {
+	d, err := p.DocPackage(innerPath, modInfo)
+	if err != nil {
+		return nil, err
+	}
+	docs := map[string]string{}
+	add := func(name, comment string) {
+		docs[name] = strings.TrimSpace(string(d.Text(comment)))
+	}
+	addValues := func(values []*doc.Value) {
+		for _, v := range values {
+			for _, name := range v.Names {
+				add(name, v.Doc)
+			}
+		}
+	}
+	addValues(d.Consts)
+	addValues(d.Vars)
+	for _, f := range d.Funcs {
+		add(f.Name, f.Doc)
+	}
+	for _, t := range d.Types {
+		add(t.Name, t.Doc)
+		addValues(t.Consts)
+		addValues(t.Vars)
+		for _, f := range t.Funcs {
+			add(f.Name, f.Doc)
+		}
+		for _, m := range t.Methods {
+			add(t.Name+"."+m.Name, m.Doc)
+		}
+		for _, f := range typeFields(t) {
+			comment := f.Doc
+			if comment == nil {
+				comment = f.Comment
+			}
+			for _, n := range f.Names {
+				if n.IsExported() {
+					add(t.Name+"."+n.Name, comment.Text())
+				}
+			}
+		}
+	}
+	return docs, nil
+}
+
+// typeFields returns the fields of t if it is a struct type, or its methods
+// if it is an interface type.
+func typeFields(t *doc.Type) []*ast.Field {
+	for _, spec := range t.Decl.Specs {
+		ts, ok := spec.(*ast.TypeSpec)
+		if !ok || ts.Name.Name != t.Name {
+			continue
+		}
+		switch x := ts.Type.(type) {
+		case *ast.StructType:
+			return x.Fields.List
+		case *ast.InterfaceType:
+			return x.Methods.List
+		}
+	}
+	return nil
+}
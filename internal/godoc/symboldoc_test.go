@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"context"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSymbolDocs(t *testing.T) {
+	const src = `
+// Package p is a package.
+package p
+
+// Constants.
+const (
+	A = 1
+	B = 2
+)
+
+// V is a variable.
+var V int
+
+// F does [T] things.
+func F() {}
+
+// T is a type.
+type T struct {
+	// X is a field.
+	X int
+	Y string // Y is also a field.
+	z int
+}
+
+// NewT returns a T.
+func NewT() *T { return nil }
+
+// M is a method.
+func (T) M() {}
+
+// I is an interface.
+type I interface {
+	// N is an interface method.
+	N()
+}
+
+func Undocumented() {}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := NewPackage(fset, nil)
+	p.AddFile(f, true)
+	data, err := p.Encode(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err = DecodePackage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := p.SymbolDocs("p", &ModuleInfo{ModulePath: "example.com/m", ResolvedVersion: "v1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"A":            "Constants.",
+		"B":            "Constants.",
+		"V":            "V is a variable.",
+		"F":            "F does T things.",
+		"T":            "T is a type.",
+		"T.X":          "X is a field.",
+		"T.Y":          "Y is also a field.",
+		"NewT":         "NewT returns a T.",
+		"T.M":          "M is a method.",
+		"I":            "I is an interface.",
+		"I.N":          "N is an interface method.",
+		"Undocumented": "",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
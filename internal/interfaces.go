@@ -4,7 +4,12 @@
 
 package internal
 
-import "context"
+import (
+	"context"
+
+	"golang.org/x/pkgsite/internal/analysis"
+	"golang.org/x/pkgsite/internal/source"
+)
 
 // PostgresDB provides an interface satisfied by *(internal/postgres.DB) so that
 // packages in pkgsite can use the database if it exists without needing a
@@ -13,14 +18,36 @@ type PostgresDB interface {
 	DataSource
 
 	IsExcluded(ctx context.Context, path, version string) bool
+	DeleteAdvisory(ctx context.Context, id string) (err error)
+	GetAdvisory(ctx context.Context, id string) (_ *Advisory, err error)
+	GetAnalysisReports(ctx context.Context, modulePath, version string) (_ []*analysis.Report, err error)
+	GetAPIKey(ctx context.Context, key string) (_ *APIKey, err error)
+	GetAutocompleteSuggestions(ctx context.Context, prefix string, limit int) (_ []*AutocompleteSuggestion, err error)
+	GetBrokenDocLinks(ctx context.Context, modulePath, version string) (_ map[string][]*BrokenDocLink, err error)
 	GetImportedBy(ctx context.Context, pkgPath, modulePath string, limit int) (paths []string, err error)
 	GetImportedByCount(ctx context.Context, pkgPath, modulePath string) (_ int, err error)
+	GetImportedByHistory(ctx context.Context, pkgPath string) (_ []*ImportedByCountAt, err error)
+	GetReadyFeatures(ctx context.Context, modulePath string) (_ map[DataFeature]bool, err error)
+	GetReleaseNoteAnchor(ctx context.Context, goVersion, pkgPath string) (_ string, err error)
+	GetRenderedReadme(ctx context.Context, hash string) (_ []byte, err error)
+	GetPackageSynopses(ctx context.Context, paths []string) (_ map[string]string, err error)
+	GetRepoStats(ctx context.Context, repoURL string) (_ *source.RepoStats, err error)
+	GetSearchFacets(ctx context.Context, q string, opts SearchOptions) (_ *SearchFacets, err error)
+	GetModuleDependencies(ctx context.Context, modulePath, version string) (_ *ModuleDependencies, err error)
+	GetModuleRequirements(ctx context.Context, mvs []Modver) (_ map[Modver][]*ModuleRequirement, err error)
+	GetModuleVersionState(ctx context.Context, modulePath, resolvedVersion string) (_ *ModuleVersionState, err error)
+	GetPackageVersionState(ctx context.Context, pkgPath, modulePath, resolvedVersion string) (_ *PackageVersionState, err error)
 	GetLatestMajorPathForV1Path(ctx context.Context, v1path string) (_ string, _ int, err error)
 	GetStdlibPathsWithSuffix(ctx context.Context, suffix string) (paths []string, err error)
 	GetSymbolHistory(ctx context.Context, packagePath, modulePath string) (_ *SymbolHistory, err error)
+	GetSymbolsAtVersions(ctx context.Context, packagePath, modulePath string, versions []string) (_ *SymbolHistory, err error)
+	GetTakedown(ctx context.Context, modulePath, version string) (_ *Takedown, err error)
 	GetVersionMap(ctx context.Context, modulePath, requestedVersion string) (_ *VersionMap, err error)
 	GetVersionMaps(ctx context.Context, paths []string, requestedVersion string) (_ []*VersionMap, err error)
 	GetVersionsForPath(ctx context.Context, path string) (_ []*ModuleInfo, err error)
 	InsertModule(ctx context.Context, m *Module, lmv *LatestModuleVersions) (isLatest bool, err error)
+	IsFetchClaimed(ctx context.Context, modulePath, version string) (_ bool, err error)
+	UpsertAdvisory(ctx context.Context, a *Advisory) (err error)
+	UpsertAnalysisReport(ctx context.Context, r *analysis.Report) (err error)
 	UpsertVersionMap(ctx context.Context, vm *VersionMap) (err error)
 }
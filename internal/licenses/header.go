@@ -0,0 +1,72 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licenses
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var spdxIdentifierRegexp = regexp.MustCompile(`SPDX-License-Identifier:[ \t]*([^\n]+)`)
+
+// DetectHeader returns the sorted license types declared by header, the
+// comment at the top of a source file. A header declares licenses with an
+// SPDX-License-Identifier line, or by consisting of the text of a license,
+// as is common for files copied from other projects. DetectHeader returns
+// nil if header declares no license; in particular, a header that only
+// refers to a license elsewhere, like "governed by a BSD-style license that
+// can be found in the LICENSE file", declares none.
+func DetectHeader(header string) []string {
+	if strings.TrimSpace(header) == "" {
+		return nil
+	}
+	if m := spdxIdentifierRegexp.FindStringSubmatch(header); m != nil {
+		return spdxTypes(m[1])
+	}
+	cov := scanner().Scan([]byte(header))
+	if cov.Percent < float64(coverageThreshold) {
+		return nil
+	}
+	types := map[string]bool{}
+	for _, m := range cov.Match {
+		ts := exceptionTypes[m.ID]
+		if ts == nil {
+			ts = []string{m.ID}
+		}
+		for _, t := range ts {
+			types[t] = true
+		}
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(types))
+}
+
+// spdxTypes returns the sorted license identifiers in an SPDX license
+// expression, such as "(MIT OR Apache-2.0)". Exceptions, which follow WITH,
+// are omitted, since they only relax the license they modify.
+func spdxTypes(expr string) []string {
+	expr = strings.NewReplacer("(", " ", ")", " ").Replace(expr)
+	// A comment may end with the closing marker of a block comment.
+	expr = strings.TrimSuffix(strings.TrimSpace(expr), "*/")
+	types := map[string]bool{}
+	fields := strings.Fields(expr)
+	for i := 0; i < len(fields); i++ {
+		switch f := fields[i]; strings.ToUpper(f) {
+		case "AND", "OR":
+		case "WITH":
+			i++ // skip the exception
+		default:
+			types[strings.TrimSuffix(f, "+")] = true
+		}
+	}
+	if len(types) == 0 {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(types))
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package licenses
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDetectHeader(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		header string
+		want   []string
+	}{
+		{"empty", "", nil},
+		{
+			"reference",
+			"Copyright 2026 The Go Authors. All rights reserved.\nUse of this source code is governed by a BSD-style\nlicense that can be found in the LICENSE file.\n",
+			nil,
+		},
+		{"spdx", "Copyright 2020 Someone\nSPDX-License-Identifier: Apache-2.0\n", []string{"Apache-2.0"}},
+		{"spdx expression", "SPDX-License-Identifier: (MIT OR GPL-2.0-or-later WITH Classpath-exception-2.0)\n", []string{"GPL-2.0-or-later", "MIT"}},
+		{"spdx block comment", "SPDX-License-Identifier: BSD-3-Clause */", []string{"BSD-3-Clause"}},
+		{"license text", mitLicense, []string{"MIT"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := DetectHeader(test.header)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -68,6 +68,28 @@ type Metadata struct {
 	Coverage licensecheck.Coverage
 }
 
+// A Detection is a machine-readable summary of how a single license file was
+// classified. Its JSON encoding is stable and suitable for use by tools.
+type Detection struct {
+	// FilePath is the '/'-separated path to the license file in the module
+	// zip, relative to the contents directory.
+	FilePath string `json:"filePath"`
+	// Types is the set of license types detected in the file.
+	Types []string `json:"types"`
+	// Coverage is the percentage of the file's text, in normalized words,
+	// that matches a known license.
+	Coverage float64 `json:"coverage"`
+}
+
+// Detection returns the Detection for the license file described by m.
+func (m *Metadata) Detection() *Detection {
+	return &Detection{
+		FilePath: m.FilePath,
+		Types:    m.Types,
+		Coverage: m.Coverage.Percent,
+	}
+}
+
 // A License is a classified license file path and its contents.
 type License struct {
 	*Metadata
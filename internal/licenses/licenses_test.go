@@ -7,6 +7,7 @@ package licenses
 import (
 	"archive/zip"
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/fs"
@@ -411,6 +412,25 @@ func TestDetectFile(t *testing.T) {
 	}
 }
 
+func TestDetection(t *testing.T) {
+	md := &Metadata{
+		Types:    []string{"MIT"},
+		FilePath: "foo/LICENSE",
+		Coverage: lc.Coverage{
+			Percent: 97.5,
+			Match:   []lc.Match{{ID: "MIT", Start: 0, End: 100}},
+		},
+	}
+	got, err := json.Marshal(md.Detection())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"filePath":"foo/LICENSE","types":["MIT"],"coverage":97.5}`
+	if string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
 func TestDetectFiles(t *testing.T) {
 	defer func(m int64) { maxLicenseSize = m }(maxLicenseSize)
 	maxLicenseSize = int64(len(mitLicense) * 10)
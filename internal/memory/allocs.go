@@ -0,0 +1,169 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package memory
+
+import (
+	"runtime/metrics"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Allocs are heap allocations, in bytes and in number of objects.
+type Allocs struct {
+	Bytes   uint64
+	Objects uint64
+}
+
+var allocMetrics = []string{"/gc/heap/allocs:bytes", "/gc/heap/allocs:objects"}
+
+// ReadAllocs returns the cumulative heap allocations of the process, as
+// reported by runtime/metrics.
+func ReadAllocs() Allocs {
+	samples := make([]metrics.Sample, len(allocMetrics))
+	for i, name := range allocMetrics {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+	var a Allocs
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		a.Bytes = samples[0].Value.Uint64()
+	}
+	if samples[1].Value.Kind() == metrics.KindUint64 {
+		a.Objects = samples[1].Value.Uint64()
+	}
+	return a
+}
+
+// RouteAllocs are the allocations of all the requests to a route.
+type RouteAllocs struct {
+	Route    string
+	Requests int
+	Total    Allocs
+	MaxBytes uint64 // the most bytes allocated by a single request
+}
+
+// MeanBytes returns the average number of bytes allocated by a request to
+// the route.
+func (r RouteAllocs) MeanBytes() uint64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return r.Total.Bytes / uint64(r.Requests)
+}
+
+// RequestAllocs are the allocations of a single request.
+type RequestAllocs struct {
+	Route    string
+	URL      string
+	Start    time.Time
+	Duration time.Duration
+	Allocs
+	// Concurrent is the largest number of other requests that were being
+	// served when the request started or finished. Allocation counters are
+	// process-wide, so if Concurrent is not zero, Allocs includes some of the
+	// allocations of those requests.
+	Concurrent int
+}
+
+// An AllocRecorder records the heap allocations made while serving requests.
+// It aggregates them by route and keeps the requests that allocated the
+// most. It is safe for concurrent use.
+type AllocRecorder struct {
+	maxTop   int
+	inFlight atomic.Int64
+
+	mu     sync.Mutex
+	routes map[string]*RouteAllocs
+	top    []*RequestAllocs // sorted by decreasing Bytes
+}
+
+// NewAllocRecorder returns an AllocRecorder that keeps the maxTop requests
+// that allocated the most.
+func NewAllocRecorder(maxTop int) *AllocRecorder {
+	return &AllocRecorder{
+		maxTop: maxTop,
+		routes: map[string]*RouteAllocs{},
+	}
+}
+
+// Start starts measuring a request for url on route. Call the returned
+// function when the request has been served. Invoke like so:
+//
+//	defer rec.Start(route, r.URL.String())()
+func (r *AllocRecorder) Start(route, url string) func() {
+	concurrent := int(r.inFlight.Add(1) - 1)
+	start := time.Now()
+	before := ReadAllocs()
+	return func() {
+		after := ReadAllocs()
+		concurrent = max(concurrent, int(r.inFlight.Add(-1)))
+		r.add(&RequestAllocs{
+			Route:    route,
+			URL:      url,
+			Start:    start,
+			Duration: time.Since(start),
+			Allocs: Allocs{
+				Bytes:   after.Bytes - before.Bytes,
+				Objects: after.Objects - before.Objects,
+			},
+			Concurrent: concurrent,
+		})
+	}
+}
+
+func (r *AllocRecorder) add(ra *RequestAllocs) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rt := r.routes[ra.Route]
+	if rt == nil {
+		rt = &RouteAllocs{Route: ra.Route}
+		r.routes[ra.Route] = rt
+	}
+	rt.Requests++
+	rt.Total.Bytes += ra.Bytes
+	rt.Total.Objects += ra.Objects
+	rt.MaxBytes = max(rt.MaxBytes, ra.Bytes)
+
+	if len(r.top) == r.maxTop && (r.maxTop == 0 || r.top[len(r.top)-1].Bytes >= ra.Bytes) {
+		return
+	}
+	i := sort.Search(len(r.top), func(i int) bool { return r.top[i].Bytes < ra.Bytes })
+	if len(r.top) < r.maxTop {
+		r.top = append(r.top, nil)
+	}
+	copy(r.top[i+1:], r.top[i:])
+	r.top[i] = ra
+}
+
+// Routes returns the allocations of each route, with the route that
+// allocated the most first.
+func (r *AllocRecorder) Routes() []RouteAllocs {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rs []RouteAllocs
+	for _, rt := range r.routes {
+		rs = append(rs, *rt)
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		if rs[i].Total.Bytes != rs[j].Total.Bytes {
+			return rs[i].Total.Bytes > rs[j].Total.Bytes
+		}
+		return rs[i].Route < rs[j].Route
+	})
+	return rs
+}
+
+// Top returns the requests that allocated the most, with the largest first.
+func (r *AllocRecorder) Top() []RequestAllocs {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var rs []RequestAllocs
+	for _, ra := range r.top {
+		rs = append(rs, *ra)
+	}
+	return rs
+}
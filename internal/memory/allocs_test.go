@@ -0,0 +1,54 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package memory
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var sink []byte
+
+func TestAllocRecorder(t *testing.T) {
+	rec := NewAllocRecorder(2)
+	for _, test := range []struct {
+		route, url string
+		size       int
+	}{
+		{"a", "/a1", 1 << 20},
+		{"b", "/b1", 4 << 20},
+		{"a", "/a2", 2 << 20},
+		{"c", "/c1", 1 << 10},
+	} {
+		done := rec.Start(test.route, test.url)
+		sink = make([]byte, test.size)
+		done()
+	}
+	sink = nil
+
+	var gotRoutes []string
+	for _, r := range rec.Routes() {
+		gotRoutes = append(gotRoutes, r.Route)
+	}
+	if diff := cmp.Diff([]string{"b", "a", "c"}, gotRoutes); diff != "" {
+		t.Errorf("Routes mismatch (-want +got):\n%s", diff)
+	}
+	a := rec.Routes()[1]
+	if a.Requests != 2 {
+		t.Errorf("route a: got %d requests, want 2", a.Requests)
+	}
+	if a.MaxBytes < 2<<20 || a.Total.Bytes < 3<<20 {
+		t.Errorf("route a: got max %d, total %d bytes; want at least %d, %d", a.MaxBytes, a.Total.Bytes, 2<<20, 3<<20)
+	}
+
+	var gotTop []string
+	for _, r := range rec.Top() {
+		gotTop = append(gotTop, r.URL)
+	}
+	if diff := cmp.Diff([]string{"/b1", "/a2"}, gotTop); diff != "" {
+		t.Errorf("Top mismatch (-want +got):\n%s", diff)
+	}
+}
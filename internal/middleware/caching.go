@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"golang.org/x/pkgsite/internal/config"
 	"golang.org/x/pkgsite/internal/cookie"
 	"golang.org/x/pkgsite/internal/dcensus"
+	"golang.org/x/pkgsite/internal/debugflags"
 	"golang.org/x/pkgsite/internal/log"
 )
 
@@ -45,6 +47,11 @@ var (
 		"Errors retrieving from cache.",
 		stats.UnitDimensionless,
 	)
+	cacheStaleServed = stats.Int64(
+		"go-discovery/cache/stale_count",
+		"Stale pages served in place of errors.",
+		stats.UnitDimensionless,
+	)
 
 	CacheResultCount = &view.View{
 		Name:        "go-discovery/cache/result_count",
@@ -67,6 +74,13 @@ var (
 		Description: "cache errors, by cache name",
 		TagKeys:     []tag.Key{keyCacheName, keyCacheOperation},
 	}
+	CacheStaleCount = &view.View{
+		Name:        "go-discovery/cache/stale_count",
+		Measure:     cacheStaleServed,
+		Aggregation: view.Count(),
+		Description: "stale pages served in place of errors, by cache name",
+		TagKeys:     []tag.Key{keyCacheName},
+	}
 
 	// To avoid test flakiness, when TestMode is true, cache writes are
 	// synchronous.
@@ -87,12 +101,21 @@ func recordCacheError(ctx context.Context, name, operation string) {
 	}, cacheErrors.M(1))
 }
 
+func recordCacheStale(ctx context.Context, name string) {
+	stats.RecordWithTags(ctx, []tag.Mutator{
+		tag.Upsert(keyCacheName, name),
+	}, cacheStaleServed.M(1))
+}
+
 type cache struct {
 	name       string
 	authValues []string
 	cache      *icache.Cache
 	delegate   http.Handler
 	expirer    Expirer
+	// staleTTL is the TTL of the stale copies of pages. If it is zero, no
+	// stale copies are kept.
+	staleTTL time.Duration
 }
 
 // An Expirer computes the TTL that should be used when caching a page.
@@ -123,6 +146,18 @@ type cacher struct {
 // authValues is the set of values that could be set on the authHeader in
 // order to bypass the cache.
 func (c *cacher) Cache(name string, expirer func(r *http.Request) time.Duration, authValues []string) func(http.Handler) http.Handler {
+	return c.CacheStaleOnError(name, expirer, 0, authValues)
+}
+
+// CacheStaleOnError is like Cache, but the middleware also keeps a stale copy
+// of each page it caches, for staleTTL, which should be much longer than the
+// TTLs of the expirer. If the handler fails with a 5xx status once a page has
+// expired, the stale copy is served in its place, with a banner that says the
+// page may be out of date. That keeps pages available while the database is
+// briefly unavailable.
+//
+// If staleTTL is zero, CacheStaleOnError is the same as Cache.
+func (c *cacher) CacheStaleOnError(name string, expirer func(r *http.Request) time.Duration, staleTTL time.Duration, authValues []string) func(http.Handler) http.Handler {
 	return func(h http.Handler) http.Handler {
 		return &cache{
 			name:       name,
@@ -130,6 +165,7 @@ func (c *cacher) Cache(name string, expirer func(r *http.Request) time.Duration,
 			cache:      icache.New(c.client),
 			delegate:   h,
 			expirer:    expirer,
+			staleTTL:   staleTTL,
 		}
 	}
 }
@@ -143,11 +179,21 @@ func (c *cache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	}
-	// If the flash cookie is set, bypass the cache.
-	if _, err := r.Cookie(cookie.AlternativeModuleFlash); err == nil {
+	// Bypass the cache if there are debug directives. Pages for other
+	// experiments or renderers must not be served to others, nor cached pages
+	// to requests for them. Timed pages must also bypass it, both to measure
+	// the real work and because they contain a timing footer.
+	if debugflags.FromContext(r.Context()).SkipCache() {
 		c.delegate.ServeHTTP(w, r)
 		return
 	}
+	// If a flash cookie is set, bypass the cache.
+	for _, name := range []string{cookie.AlternativeModuleFlash, cookie.SymbolVersionFlash} {
+		if _, err := r.Cookie(name); err == nil {
+			c.delegate.ServeHTTP(w, r)
+			return
+		}
+	}
 	ctx := r.Context()
 	key := r.URL.String()
 	start := time.Now()
@@ -161,8 +207,15 @@ func (c *cache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	rec := newRecorder(w)
+	rec.holdErrors = c.staleTTL > 0
 	c.delegate.ServeHTTP(rec, r)
-	if rec.bufErr == nil && (rec.statusCode == 0 || rec.statusCode == http.StatusOK) {
+	if rec.held {
+		c.serveStale(ctx, w, key, rec)
+		return
+	}
+	// The cache key is only the URL, so responses that vary with request
+	// headers are not cached.
+	if rec.bufErr == nil && (rec.statusCode == 0 || rec.statusCode == http.StatusOK) && rec.Header().Get("Vary") == "" {
 		ttl := c.expirer(r)
 		if TestMode {
 			c.put(ctx, key, rec, ttl)
@@ -212,6 +265,67 @@ func (c *cache) put(ctx context.Context, key string, rec *cacheRecorder, ttl tim
 		recordCacheError(ctx, c.name, "SET")
 		log.Warningf(ctx, "cache set %q: %v", key, err)
 	}
+	if c.staleTTL > 0 {
+		staleKey := icache.StaleKey(key)
+		if err := c.cache.Put(setCtx, staleKey, rec.buf.Bytes(), c.staleTTL); err != nil {
+			recordCacheError(ctx, c.name, "SET")
+			log.Warningf(ctx, "cache set %q: %v", staleKey, err)
+		}
+	}
+}
+
+// serveStale serves the stale copy of the page with the given key in place of
+// the error response held by rec. If there is no stale copy, it serves the
+// error.
+func (c *cache) serveStale(ctx context.Context, w http.ResponseWriter, key string, rec *cacheRecorder) {
+	var page []byte
+	reader, ok := c.get(ctx, icache.StaleKey(key))
+	if ok {
+		var err error
+		page, err = io.ReadAll(reader)
+		if err != nil {
+			log.Errorf(ctx, "cache: reading stale copy of %q: %v", key, err)
+			recordCacheError(ctx, c.name, "UNZIP")
+			ok = false
+		}
+	}
+	if !ok {
+		w.WriteHeader(rec.statusCode)
+		if _, err := w.Write(rec.heldBody.Bytes()); err != nil {
+			log.Errorf(ctx, "cache: writing error response for %q: %v", key, err)
+		}
+		return
+	}
+	log.Warningf(ctx, "serving stale copy of %q in place of status %d", key, rec.statusCode)
+	recordCacheStale(ctx, c.name)
+	// The handler may have set headers for its error response.
+	w.Header().Del("Content-Length")
+	// Don't let browsers or proxies keep the stale page.
+	w.Header().Set("Cache-Control", "no-store")
+	if _, err := w.Write(withStaleBanner(page)); err != nil {
+		log.Errorf(ctx, "cache: writing stale copy of %q: %v", key, err)
+	}
+}
+
+// staleBanner is inserted at the start of the body of stale pages. Its style
+// is in static/frontend/frontend.css.
+const staleBanner = `<div class="StaleBanner" role="status">` +
+	`This is a saved copy of the page, and its data may be stale. ` +
+	`The latest data could not be loaded; please try again later.</div>`
+
+// withStaleBanner returns page with staleBanner inserted after its opening body
+// tag. If page has no body tag, it is returned unchanged.
+func withStaleBanner(page []byte) []byte {
+	i := bytes.Index(page, []byte("<body"))
+	if i < 0 {
+		return page
+	}
+	j := bytes.IndexByte(page[i:], '>')
+	if j < 0 {
+		return page
+	}
+	at := i + j + 1
+	return slices.Concat(page[:at], []byte(staleBanner), page[at:])
 }
 
 func newRecorder(w http.ResponseWriter) *cacheRecorder {
@@ -223,6 +337,9 @@ func newRecorder(w http.ResponseWriter) *cacheRecorder {
 // cacheRecorder is an http.ResponseWriter that collects http bytes for later
 // writing to the cache. Along the way it collects any error, along with the
 // resulting HTTP status code. We only cache 200 OK responses.
+//
+// If holdErrors is set, a response with a 5xx status is held in heldBody
+// instead of being written, so that a stale page can be served in its place.
 type cacheRecorder struct {
 	http.ResponseWriter
 	statusCode int
@@ -230,9 +347,18 @@ type cacheRecorder struct {
 	bufErr    error
 	buf       *bytes.Buffer
 	zipWriter *gzip.Writer
+
+	holdErrors  bool
+	wroteHeader bool
+	held        bool
+	heldBody    bytes.Buffer
 }
 
 func (r *cacheRecorder) Write(b []byte) (int, error) {
+	if r.held {
+		return r.heldBody.Write(b)
+	}
+	r.wroteHeader = true
 	n, err := r.ResponseWriter.Write(b)
 	// Only try writing to the buffer if we haven't yet encountered an error.
 	if r.bufErr == nil {
@@ -257,5 +383,13 @@ func (r *cacheRecorder) WriteHeader(statusCode int) {
 		// middleware thinks the response is not OK, we will capture this.
 		r.statusCode = statusCode
 	}
+	if r.held {
+		return
+	}
+	if r.holdErrors && !r.wroteHeader && statusCode >= 500 {
+		r.held = true
+		return
+	}
+	r.wroteHeader = true
 	r.ResponseWriter.WriteHeader(statusCode)
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"go.opencensus.io/stats/view"
 	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/debugflags"
 )
 
 func TestCache(t *testing.T) {
@@ -49,7 +50,7 @@ func TestCache(t *testing.T) {
 
 	c := redis.NewClient(&redis.Options{Addr: s.Addr()})
 	mux := http.NewServeMux()
-	mux.Handle("/A", NewCacher(c).Cache("A", ttl(1*time.Minute), []string{"yes"})(handler))
+	mux.Handle("/A", Debug("secret")(NewCacher(c).Cache("A", ttl(1*time.Minute), []string{"yes"})(handler)))
 	mux.Handle("/B", handler)
 	ts := httptest.NewServer(mux)
 	view.Register(CacheResultCount)
@@ -62,6 +63,7 @@ func TestCache(t *testing.T) {
 		body          string
 		status        int
 		bypass        bool
+		debug         string // debug directives
 		wantHitCounts map[bool]int
 		wantBody      string
 		wantStatus    int
@@ -135,6 +137,34 @@ func TestCache(t *testing.T) {
 			wantBody:      "6",
 			wantStatus:    http.StatusOK,
 		},
+		{
+			label: "bypassing the cache with a debug directive",
+			path:  "A",
+			body:  "7",
+			debug: "nocache",
+			// hitCounts should not be modified.
+			wantHitCounts: map[bool]int{false: 3, true: 2},
+			wantBody:      "7",
+			wantStatus:    http.StatusOK,
+		},
+		{
+			label: "overriding the renderer bypasses the cache",
+			path:  "A",
+			body:  "8",
+			debug: "renderer:" + debugflags.RendererReload,
+			// hitCounts should not be modified.
+			wantHitCounts: map[bool]int{false: 3, true: 2},
+			wantBody:      "8",
+			wantStatus:    http.StatusOK,
+		},
+		{
+			label:         "debug responses are not cached",
+			path:          "A",
+			body:          "9",
+			wantHitCounts: map[bool]int{false: 3, true: 3},
+			wantBody:      "4",
+			wantStatus:    http.StatusOK,
+		},
 	}
 
 	for _, test := range tests {
@@ -148,6 +178,10 @@ func TestCache(t *testing.T) {
 		if test.bypass {
 			req.Header.Set(config.BypassCacheAuthHeader, "yes")
 		}
+		if test.debug != "" {
+			req.Header.Set(config.AllowDebugHeader, "secret")
+			req.Header.Set(config.DebugOverridesHeader, test.debug)
+		}
 		resp, err := ts.Client().Do(req)
 		if err != nil {
 			t.Fatal(err)
@@ -183,3 +217,141 @@ func TestCache(t *testing.T) {
 		}
 	}
 }
+
+func TestCacheVary(t *testing.T) {
+	TestMode = true
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	n := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n++
+		if r.URL.Path == "/vary" {
+			w.Header().Set("Vary", "Accept-Language")
+		}
+		fmt.Fprint(w, n)
+	})
+	c := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	ts := httptest.NewServer(NewCacher(c).Cache("vary", ttl(time.Minute), nil)(handler))
+	defer ts.Close()
+
+	get := func(path string) string {
+		t.Helper()
+		resp, err := ts.Client().Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+	// A response with a Vary header is not cached.
+	if got, want := get("/vary"), "1"; got != want {
+		t.Errorf("first GET /vary = %q, want %q", got, want)
+	}
+	if got, want := get("/vary"), "2"; got != want {
+		t.Errorf("second GET /vary = %q, want %q", got, want)
+	}
+	// Other responses are.
+	if got, want := get("/plain"), "3"; got != want {
+		t.Errorf("first GET /plain = %q, want %q", got, want)
+	}
+	if got, want := get("/plain"), "3"; got != want {
+		t.Errorf("second GET /plain = %q, want %q", got, want)
+	}
+}
+
+func TestCacheStaleOnError(t *testing.T) {
+	TestMode = true
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// The handler serves status with body, unless status is 0.
+	var (
+		status int
+		body   string
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != 0 {
+			w.WriteHeader(status)
+		}
+		fmt.Fprint(w, body)
+	})
+	c := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	ts := httptest.NewServer(NewCacher(c).CacheStaleOnError("stale", ttl(time.Minute), time.Hour, nil)(handler))
+	defer ts.Close()
+
+	get := func(path string) (int, string, http.Header) {
+		t.Helper()
+		resp, err := ts.Client().Get(ts.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(b), resp.Header
+	}
+
+	const page = "<html><body class=x><p>fresh</p></body></html>"
+	status, body = 0, page
+	if code, got, _ := get("/p"); code != http.StatusOK || got != page {
+		t.Fatalf("first GET = %d %q, want 200 %q", code, got, page)
+	}
+
+	// Once the page expires, an error is replaced by the stale copy.
+	s.FastForward(2 * time.Minute)
+	status, body = http.StatusInternalServerError, "error"
+	code, got, header := get("/p")
+	if want := "<html><body class=x>" + staleBanner + "<p>fresh</p></body></html>"; code != http.StatusOK || got != want {
+		t.Errorf("GET after error = %d %q, want 200 %q", code, got, want)
+	}
+	if got, want := header.Get("Cache-Control"), "no-store"; got != want {
+		t.Errorf("Cache-Control = %q, want %q", got, want)
+	}
+
+	// Errors other than 5xx are served.
+	status, body = http.StatusNotFound, "not found"
+	if code, got, _ := get("/p"); code != http.StatusNotFound || got != body {
+		t.Errorf("GET after 404 = %d %q, want 404 %q", code, got, body)
+	}
+
+	// Without a stale copy, the error is served.
+	status, body = http.StatusServiceUnavailable, "unavailable"
+	if code, got, _ := get("/other"); code != http.StatusServiceUnavailable || got != body {
+		t.Errorf("GET of uncached page = %d %q, want 503 %q", code, got, body)
+	}
+
+	// Once the stale copy expires too, the error is served.
+	s.FastForward(time.Hour)
+	status, body = http.StatusInternalServerError, "error"
+	if code, got, _ := get("/p"); code != http.StatusInternalServerError || got != body {
+		t.Errorf("GET after stale copy expired = %d %q, want 500 %q", code, got, body)
+	}
+}
+
+func TestWithStaleBanner(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"<html><body>x</body></html>", "<html><body>" + staleBanner + "x</body></html>"},
+		{`<body class="a">x`, `<body class="a">` + staleBanner + "x"},
+		{"no body", "no body"},
+		{"<body", "<body"},
+	} {
+		if got := string(withStaleBanner([]byte(test.in))); got != test.want {
+			t.Errorf("withStaleBanner(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/debugflags"
+	"golang.org/x/pkgsite/internal/experiment"
+)
+
+const debugQueryParamKey = "debug"
+
+// Debug returns a Middleware that applies the debug directives of a request,
+// described in debugflags.Flags. Directives are read from the
+// config.DebugOverridesHeader header, separated by commas, and from the
+// values of the "debug" query parameter.
+//
+// Directives are honored only if the config.AllowDebugHeader header of the
+// request is authValue, and are ignored otherwise or if authValue is empty.
+// The directives in effect are echoed in the config.DebugOverridesHeader
+// response header.
+//
+// Debug must come after the Experiment middleware, so that it can override
+// the experiments it sets.
+func Debug(authValue string) Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if authValue == "" || r.Header.Get(config.AllowDebugHeader) != authValue {
+				h.ServeHTTP(w, r)
+				return
+			}
+			directives := r.URL.Query()[debugQueryParamKey]
+			if hv := r.Header.Get(config.DebugOverridesHeader); hv != "" {
+				directives = append(directives, strings.Split(hv, ",")...)
+			}
+			if len(directives) == 0 {
+				h.ServeHTTP(w, r)
+				return
+			}
+			flags, err := debugflags.Parse(directives, func(name string) bool {
+				_, ok := internal.Experiments[name]
+				return ok
+			})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			ctx := r.Context()
+			var exps []string
+			for _, e := range experiment.FromContext(ctx).Active() {
+				if !slices.Contains(flags.DisabledExperiments, e) {
+					exps = append(exps, e)
+				}
+			}
+			exps = append(exps, flags.EnabledExperiments...)
+			ctx = experiment.NewContext(ctx, exps...)
+			ctx = debugflags.NewContext(ctx, flags)
+			w.Header().Set(config.DebugOverridesHeader, flags.String())
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/debugflags"
+	"golang.org/x/pkgsite/internal/experiment"
+)
+
+func TestDebug(t *testing.T) {
+	const (
+		authValue = "secret"
+		exp       = internal.ExperimentEnableStdFrontendFetch
+	)
+	var (
+		gotFlags *debugflags.Flags
+		gotExp   bool
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotFlags = debugflags.FromContext(r.Context())
+		gotExp = experiment.IsActive(r.Context(), exp)
+	})
+	// Enable the experiment for all requests, as the Experiment middleware
+	// would.
+	withExperiment := func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r.WithContext(experiment.NewContext(r.Context(), exp)))
+		})
+	}
+	mw := Chain(withExperiment, Debug(authValue))(handler)
+
+	for _, test := range []struct {
+		name       string
+		url        string
+		auth       string
+		header     string
+		wantCode   int
+		wantEcho   string
+		wantCache  bool // want BypassCache
+		wantActive bool // want exp active
+	}{
+		{
+			name:       "no auth",
+			url:        "/?debug=nocache&debug=experiment:-" + exp,
+			wantCode:   http.StatusOK,
+			wantActive: true,
+		},
+		{
+			name:       "wrong auth",
+			url:        "/?debug=nocache",
+			auth:       "guess",
+			wantCode:   http.StatusOK,
+			wantActive: true,
+		},
+		{
+			name:       "query",
+			url:        "/?debug=nocache&debug=experiment:-" + exp,
+			auth:       authValue,
+			wantCode:   http.StatusOK,
+			wantEcho:   "nocache, experiment:-" + exp,
+			wantCache:  true,
+			wantActive: false,
+		},
+		{
+			name:       "header",
+			url:        "/",
+			auth:       authValue,
+			header:     "renderer:reload, nocache",
+			wantCode:   http.StatusOK,
+			wantEcho:   "nocache, renderer:reload",
+			wantCache:  true,
+			wantActive: true,
+		},
+		{
+			name:     "invalid",
+			url:      "/?debug=experiment:unknown",
+			auth:     authValue,
+			wantCode: http.StatusBadRequest,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			gotFlags, gotExp = nil, false
+			r := httptest.NewRequest("GET", test.url, nil)
+			if test.auth != "" {
+				r.Header.Set(config.AllowDebugHeader, test.auth)
+			}
+			if test.header != "" {
+				r.Header.Set(config.DebugOverridesHeader, test.header)
+			}
+			w := httptest.NewRecorder()
+			mw.ServeHTTP(w, r)
+			if w.Code != test.wantCode {
+				t.Fatalf("got status %d, want %d", w.Code, test.wantCode)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+			if got := w.Header().Get(config.DebugOverridesHeader); got != test.wantEcho {
+				t.Errorf("echoed %q, want %q", got, test.wantEcho)
+			}
+			if gotFlags.BypassCache != test.wantCache {
+				t.Errorf("BypassCache = %t, want %t", gotFlags.BypassCache, test.wantCache)
+			}
+			if gotExp != test.wantActive {
+				t.Errorf("experiment active = %t, want %t", gotExp, test.wantActive)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+var (
+	keyLegacyForm  = tag.MustNewKey("legacy.form")
+	legacyRedirect = stats.Int64(
+		"go-discovery/legacy-url/redirect_count",
+		"Requests for legacy forms of URLs that were redirected.",
+		stats.UnitDimensionless,
+	)
+
+	// LegacyURLRedirectCount counts the requests redirected by
+	// LegacyURLRedirect, by legacy form. Once a form no longer gets traffic,
+	// its redirect can be removed.
+	LegacyURLRedirectCount = &view.View{
+		Name:        "go-discovery/legacy-url/redirect_count",
+		Measure:     legacyRedirect,
+		Aggregation: view.Count(),
+		Description: "legacy URL redirects, by legacy form",
+		TagKeys:     []tag.Key{keyLegacyForm},
+	}
+)
+
+func recordLegacyRedirect(ctx context.Context, form string) {
+	stats.RecordWithTags(ctx, []tag.Mutator{
+		tag.Upsert(keyLegacyForm, form),
+	}, legacyRedirect.M(1))
+}
+
+// legacyTabs maps the values of the tab query parameter that unit pages no
+// longer have to the IDs of the sections of the main tab that replaced them.
+// The main tab itself is the default, so its canonical URL has no tab.
+var legacyTabs = map[string]string{
+	"main":           "",
+	"doc":            "section-documentation",
+	"overview":       "section-readme",
+	"subdirectories": "section-directories",
+}
+
+// LegacyURLRedirect permanently redirects requests for legacy forms of URLs,
+// such as the tabs of old versions of the unit page, and the godoc.org query
+// parameters that links written for godoc.org carried over to pkg.go.dev, to
+// their canonical URLs. It records each redirected form in
+// LegacyURLRedirectCount.
+func LegacyURLRedirect() Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				h.ServeHTTP(w, r)
+				return
+			}
+			u, forms := canonicalURL(r.URL)
+			if len(forms) == 0 {
+				h.ServeHTTP(w, r)
+				return
+			}
+			for _, f := range forms {
+				recordLegacyRedirect(r.Context(), f)
+			}
+			http.Redirect(w, r, u.String(), http.StatusMovedPermanently)
+		})
+	}
+}
+
+// canonicalURL returns the canonical form of u, and the names of the legacy
+// forms that u has. If it has none, canonicalURL returns u and no names.
+func canonicalURL(u *url.URL) (_ *url.URL, forms []string) {
+	if u.RawQuery == "" || u.Path == "/" || strings.HasPrefix(u.Path, "/badge/") {
+		return u, nil
+	}
+	q := u.Query()
+	cu := &url.URL{Path: u.Path}
+
+	// godoc.org badges: /<path>?status.svg
+	_, isSVG := q["status.svg"]
+	_, isPNG := q["status.png"]
+	if isSVG || isPNG {
+		if isSVG {
+			forms = append(forms, "status.svg")
+		} else {
+			forms = append(forms, "status.png")
+		}
+		return &url.URL{Path: "/badge" + u.Path}, forms
+	}
+
+	// godoc.org tabs: /<path>?imports and /<path>?importers
+	if _, ok := q["imports"]; ok && q.Get("imports") == "" {
+		q.Del("imports")
+		q.Set("tab", "imports")
+		forms = append(forms, "imports")
+	} else if _, ok := q["importers"]; ok && q.Get("importers") == "" {
+		q.Del("importers")
+		q.Set("tab", "importedby")
+		forms = append(forms, "importers")
+	}
+
+	// Old tabs of the unit page: /<path>?tab=doc
+	if section, ok := legacyTabs[q.Get("tab")]; ok {
+		forms = append(forms, "tab="+q.Get("tab"))
+		q.Del("tab")
+		cu.Fragment = section
+	}
+
+	if len(forms) == 0 {
+		return u, nil
+	}
+	cu.RawQuery = q.Encode()
+	return cu, forms
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLegacyURLRedirect(t *testing.T) {
+	for _, test := range []struct {
+		from      string
+		to        string // empty if not redirected
+		wantForms []string
+	}{
+		{from: "/net/http"},
+		{from: "/net/http?tab=versions"},
+		{from: "/"},
+		{from: "/search?q=http"},
+		{from: "/badge/net/http?status.svg"},
+		{from: "/net/http?tab=main", to: "/net/http", wantForms: []string{"tab=main"}},
+		{from: "/net/http?tab=doc", to: "/net/http#section-documentation", wantForms: []string{"tab=doc"}},
+		{from: "/net/http?tab=overview&GOOS=linux", to: "/net/http?GOOS=linux#section-readme", wantForms: []string{"tab=overview"}},
+		{from: "/net/http?tab=subdirectories", to: "/net/http#section-directories", wantForms: []string{"tab=subdirectories"}},
+		{from: "/net/http?imports", to: "/net/http?tab=imports", wantForms: []string{"imports"}},
+		{from: "/net/http?importers", to: "/net/http?tab=importedby", wantForms: []string{"importers"}},
+		{from: "/net/http?importers&tab=doc", to: "/net/http?tab=importedby", wantForms: []string{"importers"}},
+		{from: "/net/http?status.svg", to: "/badge/net/http", wantForms: []string{"status.svg"}},
+		{from: "/net/http?status.png", to: "/badge/net/http", wantForms: []string{"status.png"}},
+	} {
+		t.Run(test.from, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, test.from, nil)
+			_, gotForms := canonicalURL(r.URL)
+			if diff := cmp.Diff(test.wantForms, gotForms); diff != "" {
+				t.Errorf("forms mismatch (-want, +got):\n%s", diff)
+			}
+
+			w := httptest.NewRecorder()
+			h := LegacyURLRedirect()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			h.ServeHTTP(w, r)
+			if test.to == "" {
+				if w.Code != http.StatusOK {
+					t.Errorf("got status %d, want %d", w.Code, http.StatusOK)
+				}
+				return
+			}
+			if w.Code != http.StatusMovedPermanently {
+				t.Fatalf("got status %d, want %d", w.Code, http.StatusMovedPermanently)
+			}
+			if got := w.Header().Get("Location"); got != test.to {
+				t.Errorf("redirected to %q, want %q", got, test.to)
+			}
+		})
+	}
+}
@@ -9,10 +9,12 @@ import (
 	"crypto/hmac"
 	"crypto/sha256"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -20,7 +22,9 @@ import (
 	"go.opencensus.io/stats"
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
+	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/log"
 )
 
@@ -65,10 +69,24 @@ func ipKey(s string) string {
 // Quota implements a simple IP-based rate limiter. Each set of incoming IP
 // addresses with the same low-order byte gets settings.QPS requests per second.
 //
+// Requests for API routes, as reported by isAPIRequest, that present an API
+// key in the config.APIKeyHeader header are instead limited per key, to the
+// key's own QPS or settings.APIKeyQPS, and do not count against the quota of
+// their IP addresses. getAPIKey looks up a key; it should return an error
+// wrapping derrors.NotFound for unknown or revoked keys, which are rejected
+// with a 401 (Unauthorized), or in record-only mode just recorded and
+// limited by IP address. If getAPIKey is nil, API keys are ignored.
+//
 // Information is kept in a redis instance.
 //
 // If a request is disallowed, a 429 (TooManyRequests) will be served.
-func Quota(settings config.QuotaSettings, client *redis.Client) Middleware {
+func Quota(settings config.QuotaSettings, client *redis.Client,
+	getAPIKey func(context.Context, string) (*internal.APIKey, error), isAPIRequest func(*http.Request) bool) Middleware {
+	recordOnly := settings.RecordOnly == nil || *settings.RecordOnly
+	var keys *apiKeyCache
+	if getAPIKey != nil {
+		keys = newAPIKeyCache(getAPIKey, apiKeyCacheTTL)
+	}
 	return func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			ctx := r.Context()
@@ -86,13 +104,44 @@ func Quota(settings config.QuotaSettings, client *redis.Client) Middleware {
 					return
 				}
 			}
-			header := r.Header.Get("X-Godoc-Forwarded-For")
-			if header == "" {
-				header = r.Header.Get("X-Forwarded-For")
+			var (
+				blocked bool
+				reason  string
+			)
+			var (
+				ak  *internal.APIKey
+				err error
+			)
+			if isAPIRequest != nil && isAPIRequest(r) {
+				ak, err = lookupAPIKey(ctx, keys, r.Header.Get(config.APIKeyHeader))
+			}
+			switch {
+			case errors.Is(err, derrors.NotFound):
+				recordQuotaMetric(ctx, "invalid api key")
+				if !recordOnly {
+					const unauth = http.StatusUnauthorized
+					http.Error(w, http.StatusText(unauth), unauth)
+					return
+				}
+			case err != nil:
+				// Fail open, falling back to the IP-based quota.
+				log.Errorf(ctx, "quota: %v", err)
+			}
+			if ak != nil {
+				qps := ak.QPS
+				if qps <= 0 {
+					qps = settings.APIKeyQPS
+				}
+				blocked, reason = enforceAPIKeyQuota(ctx, client, qps, ak.ID)
+			} else {
+				header := r.Header.Get("X-Godoc-Forwarded-For")
+				if header == "" {
+					header = r.Header.Get("X-Forwarded-For")
+				}
+				blocked, reason = enforceQuota(ctx, client, settings.QPS, header, settings.HMACKey)
 			}
-			blocked, reason := enforceQuota(ctx, client, settings.QPS, header, settings.HMACKey)
 			recordQuotaMetric(ctx, reason)
-			if blocked && settings.RecordOnly != nil && !*settings.RecordOnly {
+			if blocked && !recordOnly {
 				const tmr = http.StatusTooManyRequests
 				http.Error(w, http.StatusText(tmr), tmr)
 				return
@@ -102,6 +151,15 @@ func Quota(settings config.QuotaSettings, client *redis.Client) Middleware {
 	}
 }
 
+// lookupAPIKey returns the API key with value key from keys. It returns nil
+// and no error if key is empty or keys is nil.
+func lookupAPIKey(ctx context.Context, keys *apiKeyCache, key string) (*internal.APIKey, error) {
+	if key == "" || keys == nil {
+		return nil, nil
+	}
+	return keys.get(ctx, key)
+}
+
 func enforceQuota(ctx context.Context, client *redis.Client, qps int, header string, hmacKey []byte) (blocked bool, reason string) {
 	// Fail open if header is missing or can't be parsed.
 	if header == "" {
@@ -114,6 +172,19 @@ func enforceQuota(ctx context.Context, client *redis.Client, qps int, header str
 	mac := hmac.New(sha256.New, hmacKey)
 	io.WriteString(mac, key)
 	rrateKey := string(mac.Sum(nil))
+	return limit(ctx, client, rrateKey, qps)
+}
+
+// enforceAPIKeyQuota limits the API key with the given ID to qps requests
+// per second. Its reasons are distinct from those of enforceQuota.
+func enforceAPIKeyQuota(ctx context.Context, client *redis.Client, qps int, id int64) (blocked bool, reason string) {
+	blocked, reason = limit(ctx, client, fmt.Sprintf("apikey:%d", id), qps)
+	return blocked, "api key " + reason
+}
+
+// limit reports whether a request for rrateKey exceeds qps requests per second.
+// It fails open if redis cannot be reached.
+func limit(ctx context.Context, client *redis.Client, rrateKey string, qps int) (blocked bool, reason string) {
 	res, err := rrate.NewLimiter(client.WithTimeout(15*time.Millisecond)).Allow(ctx, rrateKey, rrate.PerSecond(qps))
 	if err != nil {
 		var nerr *net.OpError
@@ -129,3 +200,61 @@ func enforceQuota(ctx context.Context, client *redis.Client, qps int, header str
 	}
 	return true, "blocked"
 }
+
+// apiKeyCacheTTL is how long the result of looking up an API key is cached.
+// It bounds the time it takes for a revocation to take effect.
+const apiKeyCacheTTL = time.Minute
+
+// maxAPIKeyCacheEntries bounds the size of an apiKeyCache, so that requests
+// with many different invalid keys cannot exhaust memory.
+const maxAPIKeyCacheEntries = 10000
+
+// An apiKeyCache caches the results of looking up API keys, including keys
+// that were not found.
+type apiKeyCache struct {
+	getAPIKey func(context.Context, string) (*internal.APIKey, error)
+	ttl       time.Duration
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]apiKeyCacheEntry // keyed by the hash of the key
+}
+
+type apiKeyCacheEntry struct {
+	key     *internal.APIKey // nil if the key was not found
+	expires time.Time
+}
+
+func newAPIKeyCache(getAPIKey func(context.Context, string) (*internal.APIKey, error), ttl time.Duration) *apiKeyCache {
+	return &apiKeyCache{
+		getAPIKey: getAPIKey,
+		ttl:       ttl,
+		entries:   map[[sha256.Size]byte]apiKeyCacheEntry{},
+	}
+}
+
+// get returns the API key for key, or an error wrapping derrors.NotFound if
+// there is none. Other errors are not cached.
+func (c *apiKeyCache) get(ctx context.Context, key string) (*internal.APIKey, error) {
+	h := sha256.Sum256([]byte(key))
+	now := time.Now()
+	c.mu.Lock()
+	e, ok := c.entries[h]
+	c.mu.Unlock()
+	if !ok || now.After(e.expires) {
+		ak, err := c.getAPIKey(ctx, key)
+		if err != nil && !errors.Is(err, derrors.NotFound) {
+			return nil, err
+		}
+		e = apiKeyCacheEntry{key: ak, expires: now.Add(c.ttl)}
+		c.mu.Lock()
+		if len(c.entries) >= maxAPIKeyCacheEntries {
+			clear(c.entries)
+		}
+		c.entries[h] = e
+		c.mu.Unlock()
+	}
+	if e.key == nil {
+		return nil, derrors.NotFound
+	}
+	return e.key, nil
+}
@@ -11,12 +11,18 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/go-redis/redis/v8"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/derrors"
 )
 
 func TestIPKey(t *testing.T) {
@@ -83,3 +89,123 @@ func TestEnforceQuota(t *testing.T) {
 	}
 	t.Error(failReason)
 }
+
+func TestQuotaAPIKey(t *testing.T) {
+	s, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	c := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer c.Close()
+
+	getAPIKey := func(_ context.Context, key string) (*internal.APIKey, error) {
+		switch key {
+		case "one":
+			return &internal.APIKey{ID: 1, QPS: 1}, nil
+		case "default":
+			return &internal.APIKey{ID: 2}, nil
+		case "limited":
+			return &internal.APIKey{ID: 3, QPS: 1}, nil
+		default:
+			return nil, derrors.NotFound
+		}
+	}
+	isAPIRequest := func(r *http.Request) bool { return r.URL.Path == "/api" }
+	newHandler := func(recordOnly bool) http.Handler {
+		settings := config.QuotaSettings{
+			Enable:     true,
+			QPS:        1,
+			APIKeyQPS:  100,
+			RecordOnly: &recordOnly,
+			HMACKey:    []byte{1, 2, 3, 4},
+		}
+		return Quota(settings, c, getAPIKey, isAPIRequest)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	}
+	get := func(h http.Handler, path, ip, key string) int {
+		r := httptest.NewRequest("GET", path, nil)
+		r.Header.Set("X-Forwarded-For", ip)
+		if key != "" {
+			r.Header.Set(config.APIKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	h := newHandler(false)
+	if got := get(h, "/api", "1.2.3.4", "unknown"); got != http.StatusUnauthorized {
+		t.Errorf("unknown key: got %d, want %d", got, http.StatusUnauthorized)
+	}
+	// Keys are only checked on API routes.
+	if got := get(h, "/page", "1.2.4.4", "unknown"); got != http.StatusOK {
+		t.Errorf("unknown key, not an API route: got %d, want %d", got, http.StatusOK)
+	}
+	// Each key has its own quota, separate from that of its IP address.
+	// The quota is per second, so only check requests that fit well within it.
+	if got := get(h, "/api", "1.2.3.4", "one"); got != http.StatusOK {
+		t.Errorf("key with QPS 1: got %d, want %d", got, http.StatusOK)
+	}
+	if got := get(h, "/api", "1.2.3.4", ""); got != http.StatusOK {
+		t.Errorf("no key: got %d, want %d", got, http.StatusOK)
+	}
+	for i := range 10 {
+		if got := get(h, "/api", "1.2.3.4", "default"); got != http.StatusOK {
+			t.Fatalf("key with default QPS, request %d: got %d, want %d", i, got, http.StatusOK)
+		}
+	}
+	// A key that exceeds its quota is blocked, even from another IP address.
+	if got := get(h, "/api", "1.2.5.4", "limited"); got != http.StatusOK {
+		t.Errorf("first request with key with QPS 1: got %d, want %d", got, http.StatusOK)
+	}
+	if got := get(h, "/api", "1.2.6.4", "limited"); got != http.StatusTooManyRequests {
+		t.Errorf("second request with key with QPS 1: got %d, want %d", got, http.StatusTooManyRequests)
+	}
+
+	// In record-only mode, nothing is rejected.
+	h = newHandler(true)
+	if got := get(h, "/api", "1.2.7.4", "unknown"); got != http.StatusOK {
+		t.Errorf("record only, unknown key: got %d, want %d", got, http.StatusOK)
+	}
+	if got := get(h, "/api", "1.2.7.4", "limited"); got != http.StatusOK {
+		t.Errorf("record only, key over its quota: got %d, want %d", got, http.StatusOK)
+	}
+}
+
+func TestAPIKeyCache(t *testing.T) {
+	ctx := context.Background()
+	var calls int
+	revoked := false
+	c := newAPIKeyCache(func(_ context.Context, key string) (*internal.APIKey, error) {
+		calls++
+		if key != "k" || revoked {
+			return nil, derrors.NotFound
+		}
+		return &internal.APIKey{ID: 1}, nil
+	}, time.Hour)
+
+	for range 2 {
+		if _, err := c.get(ctx, "k"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.get(ctx, "bad"); !errors.Is(err, derrors.NotFound) {
+			t.Fatalf("got %v, want NotFound", err)
+		}
+	}
+	if calls != 2 {
+		t.Errorf("got %d lookups, want 2", calls)
+	}
+
+	// Revocation takes effect once the entry expires.
+	revoked = true
+	if _, err := c.get(ctx, "k"); err != nil {
+		t.Fatalf("before expiry: got %v, want the cached key", err)
+	}
+	for h, e := range c.entries {
+		e.expires = time.Time{}
+		c.entries[h] = e
+	}
+	if _, err := c.get(ctx, "k"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("after revocation: got %v, want NotFound", err)
+	}
+}
@@ -18,8 +18,11 @@ var scriptHashes = []string{
 	"'sha256-QqhlxKosyquihHG/Jahbski3BB1pDss2/CDgLzKKbmE='",
 	"'sha256-karKh1IrXOF1g+uoSxK+k9BuciCwYY/ytGuQVUiRzcM='",
 	"'sha256-mxm3e8M0u3nPPBmLIBgGuMvGUIL5LGv+HzV3bLAIBgw='",
+	"'sha256-xFFNVOXb1fSlpZYV9sscitE0Gf+mjgmgWuO2bDrqkRE='",
 	// From static/frontend/search/search.tmpl
 	"'sha256-+iS8jRq15Ez/Kzz0/G+SNc0geLNvTyf2NZC7MyJgpRE='",
+	// From static/frontend/unit/history/history.tmpl
+	"'sha256-edV3joiMGIppt+5mbgLzjk0AnN+VgMyFjyaF4XsqoUQ='",
 	// From static/frontend/unit/main/main.tmpl
 	"'sha256-UiVwSVJIK9udADqG5GZe+nRUXWK9wEot2vrxL4D2pQs='",
 	// From static/frontend/unit/unit.tmpl
@@ -0,0 +1,116 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// A Stage is a part of serving a request whose time is tracked separately.
+type Stage string
+
+const (
+	StageDB       Stage = "db"       // database queries
+	StageDecode   Stage = "decode"   // decoding stored documentation
+	StageRender   Stage = "render"   // rendering documentation to HTML
+	StageTemplate Stage = "template" // executing page templates
+)
+
+// A StageTiming is the total time spent in a Stage during a request.
+type StageTiming struct {
+	Stage    Stage
+	Duration time.Duration
+	Count    int // number of times the stage was entered
+}
+
+// Millis returns the duration of t in milliseconds, with a fractional part.
+func (t StageTiming) Millis() float64 {
+	return float64(t.Duration.Microseconds()) / 1000
+}
+
+// timings accumulates StageTimings for a request. It is safe for concurrent
+// use, since parts of a request may be served in parallel.
+type timings struct {
+	mu     sync.Mutex
+	stages []StageTiming // in the order first seen
+}
+
+type timingsKey struct{}
+
+// NewTimingContext returns a context in which calls to Time and ElapsedIn
+// are recorded, so that they can be retrieved with Timings.
+func NewTimingContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, timingsKey{}, &timings{})
+}
+
+// Time records the time spent in stage until the returned function is
+// called. Invoke like so:
+//
+//	defer Time(ctx, StageDB)()
+//
+// Times for the same stage are summed. If ctx was not created by
+// NewTimingContext, Time does nothing.
+func Time(ctx context.Context, stage Stage) func() {
+	ts, ok := ctx.Value(timingsKey{}).(*timings)
+	if !ok {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		ts.add(stage, time.Since(start))
+	}
+}
+
+// ElapsedIn is like Elapsed, but also records the elapsed time as part of
+// stage, as with Time.
+func ElapsedIn(ctx context.Context, stage Stage, name string) func() {
+	end := Elapsed(ctx, name)
+	endStage := Time(ctx, stage)
+	return func() {
+		endStage()
+		end()
+	}
+}
+
+func (ts *timings) add(stage Stage, d time.Duration) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	for i := range ts.stages {
+		if ts.stages[i].Stage == stage {
+			ts.stages[i].Duration += d
+			ts.stages[i].Count++
+			return
+		}
+	}
+	ts.stages = append(ts.stages, StageTiming{Stage: stage, Duration: d, Count: 1})
+}
+
+// Timings returns the stage timings recorded so far in ctx, in the order in
+// which the stages were first entered.
+func Timings(ctx context.Context) []StageTiming {
+	ts, ok := ctx.Value(timingsKey{}).(*timings)
+	if !ok {
+		return nil
+	}
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return append([]StageTiming(nil), ts.stages...)
+}
+
+// ServerTimingHeader formats the timings as the value of a Server-Timing
+// HTTP header, followed by a metric for total, the time spent on the whole
+// request.
+func ServerTimingHeader(ts []StageTiming, total time.Duration) string {
+	var metrics []string
+	for _, t := range ts {
+		metrics = append(metrics, fmt.Sprintf("%s;dur=%.3f;desc=\"%d calls\"", t.Stage, t.Millis(), t.Count))
+	}
+	metrics = append(metrics, fmt.Sprintf("total;dur=%.3f", StageTiming{Duration: total}.Millis()))
+	return strings.Join(metrics, ", ")
+}
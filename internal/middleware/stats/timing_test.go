@@ -0,0 +1,44 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestTimings(t *testing.T) {
+	// Without a timing context, nothing is recorded.
+	ctx := context.Background()
+	Time(ctx, StageDB)()
+	if got := Timings(ctx); got != nil {
+		t.Errorf("got %v, want nil", got)
+	}
+
+	ctx = NewTimingContext(ctx)
+	Time(ctx, StageDB)()
+	ElapsedIn(ctx, StageRender, "render")()
+	Time(ctx, StageDB)()
+	got := Timings(ctx)
+	want := []StageTiming{{Stage: StageDB, Count: 2}, {Stage: StageRender, Count: 1}}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(StageTiming{}, "Duration")); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestServerTimingHeader(t *testing.T) {
+	got := ServerTimingHeader([]StageTiming{
+		{Stage: StageDB, Duration: 1500 * time.Microsecond, Count: 3},
+		{Stage: StageTemplate, Duration: 2 * time.Millisecond, Count: 1},
+	}, 10*time.Millisecond)
+	want := `db;dur=1.500;desc="3 calls", template;dur=2.000;desc="1 calls", total;dur=10.000`
+	if got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"golang.org/x/pkgsite/internal/debugflags"
+	"golang.org/x/pkgsite/internal/middleware/stats"
+)
+
+// ServerTiming returns a Middleware that records how long each stage of a
+// request takes, as described in stats.Time, and reports the stages in a
+// Server-Timing response header. It does so only for requests with the
+// "timing" debug directive, so it must come after the Debug middleware.
+func ServerTiming() Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !debugflags.FromContext(r.Context()).ShowTiming {
+				h.ServeHTTP(w, r)
+				return
+			}
+			ctx := stats.NewTimingContext(r.Context())
+			tw := &timingResponseWriter{ResponseWriter: w, ctx: ctx, start: time.Now()}
+			h.ServeHTTP(tw, r.WithContext(ctx))
+		})
+	}
+}
+
+// timingResponseWriter is an http.ResponseWriter that adds a Server-Timing
+// header just before the header is written.
+type timingResponseWriter struct {
+	http.ResponseWriter
+	ctx         context.Context
+	start       time.Time
+	wroteHeader bool
+}
+
+func (tw *timingResponseWriter) WriteHeader(statusCode int) {
+	if !tw.wroteHeader {
+		tw.wroteHeader = true
+		tw.Header().Set("Server-Timing",
+			stats.ServerTimingHeader(stats.Timings(tw.ctx), time.Since(tw.start)))
+	}
+	tw.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (tw *timingResponseWriter) Write(b []byte) (int, error) {
+	if !tw.wroteHeader {
+		tw.WriteHeader(http.StatusOK)
+	}
+	return tw.ResponseWriter.Write(b)
+}
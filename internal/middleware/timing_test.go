@@ -0,0 +1,48 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/debugflags"
+	"golang.org/x/pkgsite/internal/middleware/stats"
+)
+
+func TestServerTiming(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stats.Time(r.Context(), stats.StageDB)()
+		_, _ = w.Write([]byte("ok"))
+	})
+	withFlags := func(f *debugflags.Flags) Middleware {
+		return func(h http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				h.ServeHTTP(w, r.WithContext(debugflags.NewContext(r.Context(), f)))
+			})
+		}
+	}
+
+	for _, test := range []struct {
+		flags *debugflags.Flags
+		want  string // prefix of Server-Timing header
+	}{
+		{&debugflags.Flags{}, ""},
+		{&debugflags.Flags{ShowTiming: true}, `db;dur=`},
+	} {
+		mw := Chain(withFlags(test.flags), ServerTiming())(handler)
+		w := httptest.NewRecorder()
+		mw.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		got := w.Result().Header.Get("Server-Timing")
+		if (test.want == "") != (got == "") || !strings.HasPrefix(got, test.want) {
+			t.Errorf("%+v: got Server-Timing %q, want prefix %q", test.flags, got, test.want)
+		}
+		if test.want != "" && !strings.Contains(got, "total;dur=") {
+			t.Errorf("%+v: got Server-Timing %q, want total", test.flags, got)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package modgraph builds the dependency graphs of module versions from the
+// requirements in their go.mod files, and renders them in the Graphviz DOT
+// language and as SVG.
+package modgraph
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// MaxDepth is the largest depth of a graph that Build accepts.
+const MaxDepth = 3
+
+// MaxNodes is the largest number of nodes in a graph. Build stops adding
+// nodes when it is reached, and marks the graph as truncated.
+const MaxNodes = 250
+
+// A Graph is the dependency graph of a module version, up to some depth.
+// It is not the build list of the module version: each node is a module
+// version required by the go.mod file of another node, so a module may
+// appear at several versions.
+type Graph struct {
+	// Nodes are the module versions in the graph. The first node is the
+	// root, and nodes are in breadth-first order from it.
+	Nodes []*Node
+	Edges []*Edge
+
+	// Truncated reports whether nodes were left out of the graph because it
+	// had MaxNodes nodes.
+	Truncated bool
+}
+
+// A Node is a module version in a Graph.
+type Node struct {
+	ModulePath string
+	Version    string
+
+	// Depth is the length of the shortest path to the node from the root.
+	Depth int
+
+	// Expanded reports whether the requirements of the module version are
+	// in the graph. They are not for nodes at the depth limit, and for
+	// missing nodes.
+	Expanded bool
+
+	// Missing reports whether the module version has not been processed,
+	// so its requirements are unknown.
+	Missing bool
+}
+
+// An Edge is a requirement of one node by another.
+type Edge struct {
+	From, To int // indexes into Graph.Nodes
+
+	// Indirect reports whether the requirement is marked "// indirect".
+	Indirect bool
+}
+
+// A RequirementsGetter returns the requirements of the module versions mvs.
+// Module versions that are unknown are not in the map.
+// internal.PostgresDB.GetModuleRequirements is a RequirementsGetter.
+type RequirementsGetter func(ctx context.Context, mvs []internal.Modver) (map[internal.Modver][]*internal.ModuleRequirement, error)
+
+// Build returns the dependency graph of modulePath@version to the given depth,
+// which must be between 1 and MaxDepth. Requirements of nodes at depth
+// depth are not followed. It returns an error wrapping derrors.NotFound if
+// the root module version is unknown. It calls get once for each level of
+// the graph.
+func Build(ctx context.Context, modulePath, version string, depth int, get RequirementsGetter) (_ *Graph, err error) {
+	defer derrors.Wrap(&err, "modgraph.Build(ctx, %q, %q, %d)", modulePath, version, depth)
+
+	if depth < 1 || depth > MaxDepth {
+		return nil, errors.New("depth out of range")
+	}
+	g := &Graph{}
+	index := map[internal.Modver]int{}
+	add := func(mv internal.Modver, d int) int {
+		if i, ok := index[mv]; ok {
+			return i
+		}
+		if len(g.Nodes) >= MaxNodes {
+			g.Truncated = true
+			return -1
+		}
+		index[mv] = len(g.Nodes)
+		g.Nodes = append(g.Nodes, &Node{ModulePath: mv.Path, Version: mv.Version, Depth: d})
+		return len(g.Nodes) - 1
+	}
+	add(internal.Modver{Path: modulePath, Version: version}, 0)
+	// Nodes are added in breadth-first order, so the nodes of each level
+	// follow those of the level before it.
+	for start := 0; start < len(g.Nodes) && g.Nodes[start].Depth < depth; {
+		end := len(g.Nodes)
+		var mvs []internal.Modver
+		for _, n := range g.Nodes[start:end] {
+			mvs = append(mvs, internal.Modver{Path: n.ModulePath, Version: n.Version})
+		}
+		reqs, err := get(ctx, mvs)
+		if err != nil {
+			return nil, err
+		}
+		for i := start; i < end; i++ {
+			n := g.Nodes[i]
+			rs, ok := reqs[mvs[i-start]]
+			if !ok {
+				if i == 0 {
+					return nil, derrors.NotFound
+				}
+				n.Missing = true
+				continue
+			}
+			n.Expanded = true
+			for _, r := range rs {
+				j := add(internal.Modver{Path: r.ModulePath, Version: r.Version}, n.Depth+1)
+				if j < 0 {
+					continue
+				}
+				g.Edges = append(g.Edges, &Edge{From: i, To: j, Indirect: r.Indirect})
+			}
+		}
+		start = end
+	}
+	return g, nil
+}
@@ -0,0 +1,194 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// testGetter returns a RequirementsGetter for the requirements in reqs, which
+// maps a module version to the module versions it requires. A required
+// module version whose path ends in "-ind" is indirect. It counts its calls
+// in *calls if calls is non-nil.
+func testGetter(reqs map[string][]string, calls *int) RequirementsGetter {
+	return func(_ context.Context, mvs []internal.Modver) (map[internal.Modver][]*internal.ModuleRequirement, error) {
+		if calls != nil {
+			*calls++
+		}
+		m := map[internal.Modver][]*internal.ModuleRequirement{}
+		for _, mv := range mvs {
+			rs, ok := reqs[mv.String()]
+			if !ok {
+				continue
+			}
+			m[mv] = nil
+			for _, r := range rs {
+				path, version, _ := strings.Cut(r, "@")
+				m[mv] = append(m[mv], &internal.ModuleRequirement{
+					ModulePath: path,
+					Version:    version,
+					Indirect:   strings.HasSuffix(path, "-ind"),
+				})
+			}
+		}
+		return m, nil
+	}
+}
+
+var testReqs = map[string][]string{
+	"m.com/root@v1.0.0":           {"m.com/a@v1.0.0", "m.com/b-ind@v0.1.0"},
+	"m.com/a@v1.0.0":              {"m.com/b-ind@v0.1.0", "m.com/c@v2.0.0+incompatible"},
+	"m.com/b-ind@v0.1.0":          {"m.com/root@v1.0.0"},
+	"m.com/c@v2.0.0+incompatible": {"m.com/d@v1.0.0"},
+}
+
+func TestBuild(t *testing.T) {
+	ctx := context.Background()
+	var calls int
+	get := testGetter(testReqs, &calls)
+
+	got, err := Build(ctx, "m.com/root", "v1.0.0", 2, get)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// One call for each of the two levels that are expanded.
+	if calls != 2 {
+		t.Errorf("got %d calls of the RequirementsGetter, want 2", calls)
+	}
+	want := &Graph{
+		Nodes: []*Node{
+			{ModulePath: "m.com/root", Version: "v1.0.0", Depth: 0, Expanded: true},
+			{ModulePath: "m.com/a", Version: "v1.0.0", Depth: 1, Expanded: true},
+			{ModulePath: "m.com/b-ind", Version: "v0.1.0", Depth: 1, Expanded: true},
+			{ModulePath: "m.com/c", Version: "v2.0.0+incompatible", Depth: 2},
+		},
+		Edges: []*Edge{
+			{From: 0, To: 1},
+			{From: 0, To: 2, Indirect: true},
+			{From: 1, To: 2, Indirect: true},
+			{From: 1, To: 3},
+			{From: 2, To: 0},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	got, err = Build(ctx, "m.com/a", "v1.0.0", 3, get)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := got.Nodes[len(got.Nodes)-1]; n.ModulePath != "m.com/d" || !n.Missing || n.Expanded {
+		t.Errorf("last node = %+v, want missing m.com/d", n)
+	}
+
+	if _, err := Build(ctx, "m.com/none", "v1.0.0", 1, get); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("unknown root: got %v, want NotFound", err)
+	}
+	if _, err := Build(ctx, "m.com/root", "v1.0.0", MaxDepth+1, get); err == nil {
+		t.Error("depth too large: got nil, want error")
+	}
+}
+
+func TestBuildTruncated(t *testing.T) {
+	var rs []string
+	for i := range MaxNodes + 10 {
+		rs = append(rs, fmt.Sprintf("m.com/r%d@v1.0.0", i))
+	}
+	g, err := Build(context.Background(), "m.com/root", "v1.0.0", 1,
+		testGetter(map[string][]string{"m.com/root@v1.0.0": rs}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g.Truncated || len(g.Nodes) != MaxNodes || len(g.Edges) != MaxNodes-1 {
+		t.Errorf("got %d nodes, %d edges, truncated %t; want %d, %d, true",
+			len(g.Nodes), len(g.Edges), g.Truncated, MaxNodes, MaxNodes-1)
+	}
+}
+
+func testURL(n *Node) string {
+	return "/" + n.Label()
+}
+
+func TestWriteDOT(t *testing.T) {
+	g, err := Build(context.Background(), "m.com/a", "v1.0.0", 3, testGetter(testReqs, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := g.WriteDOT(&buf, testURL); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph "m.com/a@v1.0.0" {
+	rankdir=LR;
+	node [shape=box, fontname="monospace", fontsize=10];
+	n0 [label="m.com/a@v1.0.0", URL="/m.com/a@v1.0.0", style=bold];
+	n1 [label="m.com/b-ind@v0.1.0", URL="/m.com/b-ind@v0.1.0"];
+	n2 [label="m.com/c@v2.0.0+incompatible", URL="/m.com/c@v2.0.0+incompatible"];
+	n3 [label="m.com/root@v1.0.0", URL="/m.com/root@v1.0.0"];
+	n4 [label="m.com/d@v1.0.0", URL="/m.com/d@v1.0.0", color=gray, fontcolor=gray];
+	n0 -> n1 [style=dashed];
+	n0 -> n2;
+	n1 -> n3;
+	n2 -> n4;
+	n3 -> n0;
+	n3 -> n1 [style=dashed];
+}
+`
+	if diff := cmp.Diff(want, buf.String()); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestWriteSVG(t *testing.T) {
+	g, err := Build(context.Background(), "m.com/root", "v1.0.0", 3, testGetter(testReqs, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := g.WriteSVG(&buf, testURL); err != nil {
+		t.Fatal(err)
+	}
+	// The image must be well-formed XML, with a link for each node.
+	var links []string
+	dec := xml.NewDecoder(&buf)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v", err)
+		}
+		if se, ok := tok.(xml.StartElement); ok && se.Name.Local == "a" {
+			for _, a := range se.Attr {
+				if a.Name.Local == "href" {
+					links = append(links, a.Value)
+				}
+			}
+		}
+	}
+	want := []string{
+		"/m.com/root@v1.0.0",
+		"/m.com/a@v1.0.0",
+		"/m.com/b-ind@v0.1.0",
+		"/m.com/c@v2.0.0+incompatible",
+		"/m.com/d@v1.0.0",
+	}
+	if diff := cmp.Diff(want, links); diff != "" {
+		t.Errorf("links mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package modgraph
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// A URLFunc returns the URL that a node of a rendered graph links to.
+type URLFunc func(*Node) string
+
+// Label returns the label of n in a rendered graph.
+func (n *Node) Label() string {
+	return n.ModulePath + "@" + n.Version
+}
+
+// truncatedNote describes a truncated graph.
+var truncatedNote = fmt.Sprintf("Only the first %d modules are shown.", MaxNodes)
+
+// WriteDOT writes g to w in the Graphviz DOT language. Each node links to the
+// URL that url returns for it.
+func (g *Graph) WriteDOT(w io.Writer, url URLFunc) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %s {\n", dotQuote(g.Nodes[0].Label()))
+	fmt.Fprintf(bw, "\trankdir=LR;\n")
+	fmt.Fprintf(bw, "\tnode [shape=box, fontname=\"monospace\", fontsize=10];\n")
+	if g.Truncated {
+		fmt.Fprintf(bw, "\tlabel=%s;\n", dotQuote(truncatedNote))
+	}
+	for i, n := range g.Nodes {
+		attrs := []string{"label=" + dotQuote(n.Label()), "URL=" + dotQuote(url(n))}
+		switch {
+		case i == 0:
+			attrs = append(attrs, "style=bold")
+		case n.Missing:
+			attrs = append(attrs, "color=gray", "fontcolor=gray")
+		}
+		fmt.Fprintf(bw, "\tn%d [%s];\n", i, strings.Join(attrs, ", "))
+	}
+	for _, e := range g.Edges {
+		if e.Indirect {
+			fmt.Fprintf(bw, "\tn%d -> n%d [style=dashed];\n", e.From, e.To)
+		} else {
+			fmt.Fprintf(bw, "\tn%d -> n%d;\n", e.From, e.To)
+		}
+	}
+	fmt.Fprintf(bw, "}\n")
+	return bw.Flush()
+}
+
+// dotQuote returns s as a DOT quoted string.
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Dimensions of the SVG rendering, in pixels. Labels are in a monospace font,
+// so their widths can be computed without font metrics.
+const (
+	svgPadding    = 10
+	svgCharWidth  = 7
+	svgFontSize   = 12
+	svgNodeHeight = 24
+	svgNodePad    = 8  // between a label and the side of its box
+	svgRowGap     = 8  // between boxes in a column
+	svgColumnGap  = 60 // between columns
+)
+
+// WriteSVG writes g to w as an SVG image. The nodes are laid out from left to
+// right in columns by depth, and each one links to the URL that url returns
+// for it. Requirements marked "// indirect" are drawn dashed, and module
+// versions that have not been processed are grayed out.
+//
+// The image uses only presentation attributes, not style sheets or scripts,
+// so it can be served under a strict content security policy.
+func (g *Graph) WriteSVG(w io.Writer, url URLFunc) error {
+	// Lay out the nodes.
+	type box struct{ x, y, width int }
+	var (
+		columns [][]int // node indexes by depth
+		boxes   = make([]box, len(g.Nodes))
+	)
+	for i, n := range g.Nodes {
+		for len(columns) <= n.Depth {
+			columns = append(columns, nil)
+		}
+		columns[n.Depth] = append(columns[n.Depth], i)
+	}
+	x, maxRows := svgPadding, 0
+	for _, col := range columns {
+		width := 0
+		for _, i := range col {
+			width = max(width, len(g.Nodes[i].Label())*svgCharWidth+2*svgNodePad)
+		}
+		for row, i := range col {
+			boxes[i] = box{x, svgPadding + row*(svgNodeHeight+svgRowGap), width}
+		}
+		x += width + svgColumnGap
+		maxRows = max(maxRows, len(col))
+	}
+	width := x - svgColumnGap + svgPadding
+	height := svgPadding + maxRows*(svgNodeHeight+svgRowGap) - svgRowGap + svgPadding
+	noteY := height
+	if g.Truncated {
+		height += svgFontSize + svgPadding
+		width = max(width, 2*svgPadding+len(truncatedNote)*svgCharWidth)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %[1]d %[2]d"`, width, height)
+	fmt.Fprintf(bw, ` font-family="monospace" font-size="%d">`+"\n", svgFontSize)
+	fmt.Fprintf(bw, "<title>Dependencies of %s</title>\n", html.EscapeString(g.Nodes[0].Label()))
+	fmt.Fprintf(bw, `<defs><marker id="arrow" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto">`+
+		`<path d="M0,0 L10,5 L0,10 z" fill="#555"/></marker></defs>`+"\n")
+	fmt.Fprintf(bw, "<rect width=\"100%%\" height=\"100%%\" fill=\"white\"/>\n")
+	for _, e := range g.Edges {
+		from, to := boxes[e.From], boxes[e.To]
+		x1, y1 := from.x+from.width, from.y+svgNodeHeight/2
+		x2, y2 := to.x, to.y+svgNodeHeight/2
+		dash := ""
+		if e.Indirect {
+			dash = ` stroke-dasharray="4,3"`
+		}
+		fmt.Fprintf(bw, `<path d="M%d,%d C%d,%d %d,%d %d,%d" fill="none" stroke="#555"%s marker-end="url(#arrow)"/>`+"\n",
+			x1, y1, x1+svgColumnGap/2, y1, x2-svgColumnGap/2, y2, x2, y2, dash)
+	}
+	for i, n := range g.Nodes {
+		b := boxes[i]
+		stroke, strokeWidth, textColor := "#555", 1, "#202124"
+		switch {
+		case i == 0:
+			strokeWidth = 2
+		case n.Missing:
+			stroke, textColor = "#aaa", "#777"
+		}
+		fmt.Fprintf(bw, `<a href="%s">`, html.EscapeString(url(n)))
+		fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" rx="3" fill="white" stroke="%s" stroke-width="%d"/>`,
+			b.x, b.y, b.width, svgNodeHeight, stroke, strokeWidth)
+		fmt.Fprintf(bw, `<text x="%d" y="%d" fill="%s">%s</text>`,
+			b.x+svgNodePad, b.y+svgNodeHeight/2+svgFontSize/3, textColor, html.EscapeString(n.Label()))
+		fmt.Fprintf(bw, "</a>\n")
+	}
+	if g.Truncated {
+		fmt.Fprintf(bw, "<text x=\"%d\" y=\"%d\" fill=\"#777\">%s</text>\n", svgPadding, noteY+svgFontSize, truncatedNote)
+	}
+	fmt.Fprintf(bw, "</svg>\n")
+	return bw.Flush()
+}
@@ -19,7 +19,9 @@ func (m *Module) RemoveNonRedistributableData() {
 func (u *Unit) RemoveNonRedistributableData() {
 	if !u.IsRedistributable {
 		u.Readme = nil
+		u.LocalizedReadmes = nil
 		u.Documentation = nil
+		u.DocsPages = nil
 	}
 }
 
@@ -0,0 +1,159 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package pageviews counts views of unit pages.
+//
+// Views are aggregated in memory by unit path and day, and periodically added
+// to a Store. Nothing about the viewer or the request is recorded, only the
+// number of views of each path on each day.
+package pageviews
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/poller"
+)
+
+const (
+	// PopularWindow is the period over which views are summed to determine
+	// the most popular units.
+	PopularWindow = 30 * 24 * time.Hour
+
+	// Retention is how long daily view counts are kept.
+	Retention = 90 * 24 * time.Hour
+
+	// NumPopular is the number of popular unit paths that are kept.
+	NumPopular = 20
+)
+
+// A Store stores daily view counts.
+type Store interface {
+	// AddUnitViews adds counts, a map from unit path to number of views, to
+	// the views of each path on day.
+	AddUnitViews(ctx context.Context, day time.Time, counts map[string]int64) error
+
+	// GetPopularUnitPaths returns the limit most viewed unit paths since the
+	// given day, most viewed first.
+	GetPopularUnitPaths(ctx context.Context, since time.Time, limit int) ([]string, error)
+
+	// DeleteUnitViewsBefore deletes the view counts of days before day.
+	DeleteUnitViewsBefore(ctx context.Context, day time.Time) error
+}
+
+// A Counter counts unit page views.
+type Counter struct {
+	store Store
+	now   func() time.Time // for testing
+
+	mu     sync.Mutex
+	counts map[dayPath]int64 // views not yet added to the store
+
+	popular *poller.Poller // of []string
+}
+
+type dayPath struct {
+	day  time.Time
+	path string
+}
+
+// NewCounter returns a Counter that adds its counts to store, and reloads the
+// popular unit paths from it, every period.
+func NewCounter(ctx context.Context, store Store, period time.Duration, rep derrors.Reporter) *Counter {
+	c := &Counter{
+		store:  store,
+		now:    time.Now,
+		counts: map[dayPath]int64{},
+	}
+	c.popular = poller.New(
+		[]string(nil),
+		c.poll,
+		func(err error) {
+			log.Error(ctx, err)
+			if rep != nil {
+				rep.Report(fmt.Errorf("counting page views: %v", err), nil, nil)
+			}
+		})
+	// Load the popular paths now, so they are available before the first
+	// period ends.
+	c.popular.Poll(ctx)
+	c.popular.Start(ctx, period)
+	return c
+}
+
+// Record records a view of the unit page for path.
+func (c *Counter) Record(path string) {
+	k := dayPath{day: day(c.now()), path: path}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[k]++
+}
+
+// Popular returns the most viewed unit paths in the last PopularWindow, most
+// viewed first, as of the last time they were loaded from the store.
+func (c *Counter) Popular() []string {
+	// The poller's value is replaced, never modified, so it is safe to
+	// return without copying as long as callers don't modify it.
+	return c.popular.Current().([]string)
+}
+
+// Flush adds the views recorded since the last flush to the store.
+func (c *Counter) Flush(ctx context.Context) (err error) {
+	defer derrors.Wrap(&err, "Counter.Flush")
+
+	c.mu.Lock()
+	counts := c.counts
+	c.counts = map[dayPath]int64{}
+	c.mu.Unlock()
+
+	byDay := map[time.Time]map[string]int64{}
+	for k, n := range counts {
+		if byDay[k.day] == nil {
+			byDay[k.day] = map[string]int64{}
+		}
+		byDay[k.day][k.path] += n
+	}
+	for d, m := range byDay {
+		if err := c.store.AddUnitViews(ctx, d, m); err != nil {
+			c.restore(byDay)
+			return err
+		}
+		delete(byDay, d)
+	}
+	return nil
+}
+
+// restore adds counts that could not be stored back to c, so that they are
+// stored by the next flush.
+func (c *Counter) restore(byDay map[time.Time]map[string]int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for d, m := range byDay {
+		for p, n := range m {
+			c.counts[dayPath{d, p}] += n
+		}
+	}
+}
+
+// poll flushes the recorded views, deletes expired counts, and returns the
+// current popular paths.
+func (c *Counter) poll(ctx context.Context) (any, error) {
+	if err := c.Flush(ctx); err != nil {
+		return nil, err
+	}
+	today := day(c.now())
+	if err := c.store.DeleteUnitViewsBefore(ctx, today.Add(-Retention)); err != nil {
+		return nil, err
+	}
+	return c.store.GetPopularUnitPaths(ctx, today.Add(-PopularWindow), NumPopular)
+}
+
+// day returns the start of the UTC day containing t.
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(24 * time.Hour)
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package pageviews
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// fakeStore is a Store that keeps counts in memory.
+type fakeStore struct {
+	views map[time.Time]map[string]int64
+	fail  bool // if true, AddUnitViews fails
+}
+
+func (s *fakeStore) AddUnitViews(_ context.Context, day time.Time, counts map[string]int64) error {
+	if s.fail {
+		return errors.New("fail")
+	}
+	if s.views[day] == nil {
+		s.views[day] = map[string]int64{}
+	}
+	for p, n := range counts {
+		s.views[day][p] += n
+	}
+	return nil
+}
+
+func (s *fakeStore) GetPopularUnitPaths(_ context.Context, since time.Time, limit int) ([]string, error) {
+	totals := map[string]int64{}
+	for d, m := range s.views {
+		if d.Before(since) {
+			continue
+		}
+		for p, n := range m {
+			totals[p] += n
+		}
+	}
+	var paths []string
+	for p := range totals {
+		paths = append(paths, p)
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		if totals[paths[i]] != totals[paths[j]] {
+			return totals[paths[i]] > totals[paths[j]]
+		}
+		return paths[i] < paths[j]
+	})
+	if len(paths) > limit {
+		paths = paths[:limit]
+	}
+	return paths, nil
+}
+
+func (s *fakeStore) DeleteUnitViewsBefore(_ context.Context, day time.Time) error {
+	for d := range s.views {
+		if d.Before(day) {
+			delete(s.views, d)
+		}
+	}
+	return nil
+}
+
+func TestCounter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	store := &fakeStore{views: map[time.Time]map[string]int64{}}
+	c := NewCounter(ctx, store, time.Hour, nil)
+	now := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	c.Record("a.com/b")
+	c.Record("c.com/d")
+	now = now.Add(2 * time.Hour) // the next day
+	c.Record("c.com/d")
+
+	// Views are kept if the store fails.
+	store.fail = true
+	if err := c.Flush(ctx); err == nil {
+		t.Fatal("got nil error, want error")
+	}
+	store.fail = false
+	if err := c.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	want := map[time.Time]map[string]int64{
+		time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC): {"a.com/b": 1, "c.com/d": 1},
+		time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC): {"c.com/d": 1},
+	}
+	if diff := cmp.Diff(want, store.views); diff != "" {
+		t.Errorf("stored views mismatch (-want, +got):\n%s", diff)
+	}
+
+	c.popular.Poll(ctx)
+	if diff := cmp.Diff([]string{"c.com/d", "a.com/b"}, c.Popular()); diff != "" {
+		t.Errorf("Popular mismatch (-want, +got):\n%s", diff)
+	}
+
+	// Old views expire.
+	now = now.Add(Retention)
+	c.popular.Poll(ctx)
+	if len(store.views) != 1 {
+		t.Errorf("got %d days of views, want 1", len(store.views))
+	}
+	if got := c.Popular(); len(got) != 0 {
+		t.Errorf("got popular paths %v, want none", got)
+	}
+}
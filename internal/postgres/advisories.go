@@ -0,0 +1,105 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/osv"
+)
+
+// UpsertAdvisory stores a, replacing any advisory with the same ID.
+func (db *DB) UpsertAdvisory(ctx context.Context, a *internal.Advisory) (err error) {
+	defer derrors.WrapStack(&err, "UpsertAdvisory(ctx, %q)", a.Entry.ID)
+
+	entry, err := json.Marshal(a.Entry)
+	if err != nil {
+		return err
+	}
+	_, err = db.db.Exec(ctx, `
+		INSERT INTO advisories (id, entry, publisher)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (id) DO UPDATE SET
+			entry=excluded.entry,
+			publisher=excluded.publisher,
+			updated_at=CURRENT_TIMESTAMP`,
+		a.Entry.ID, entry, a.Publisher)
+	return err
+}
+
+// GetAdvisory returns the advisory with the given ID. It returns an error
+// wrapping derrors.NotFound if there is none.
+func (db *DB) GetAdvisory(ctx context.Context, id string) (_ *internal.Advisory, err error) {
+	defer derrors.WrapStack(&err, "GetAdvisory(ctx, %q)", id)
+
+	a, err := scanAdvisory(db.db.QueryRow(ctx, `
+		SELECT entry, publisher, created_at, updated_at
+		FROM advisories
+		WHERE id = $1`, id).Scan)
+	if err == sql.ErrNoRows {
+		return nil, derrors.NotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// GetAdvisories returns all advisories, sorted by ID.
+func (db *DB) GetAdvisories(ctx context.Context) (_ []*internal.Advisory, err error) {
+	defer derrors.WrapStack(&err, "GetAdvisories(ctx)")
+
+	var as []*internal.Advisory
+	err = db.db.RunQuery(ctx, `
+		SELECT entry, publisher, created_at, updated_at
+		FROM advisories
+		ORDER BY id`,
+		func(rows *sql.Rows) error {
+			a, err := scanAdvisory(rows.Scan)
+			if err != nil {
+				return err
+			}
+			as = append(as, a)
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
+// DeleteAdvisory deletes the advisory with the given ID. It returns an error
+// wrapping derrors.NotFound if there is none.
+func (db *DB) DeleteAdvisory(ctx context.Context, id string) (err error) {
+	defer derrors.WrapStack(&err, "DeleteAdvisory(ctx, %q)", id)
+
+	n, err := db.db.Exec(ctx, `DELETE FROM advisories WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return derrors.NotFound
+	}
+	return nil
+}
+
+func scanAdvisory(scan func(dest ...any) error) (*internal.Advisory, error) {
+	var (
+		a     internal.Advisory
+		entry []byte
+	)
+	if err := scan(&entry, &a.Publisher, &a.CreatedAt, &a.UpdatedAt); err != nil {
+		return nil, err
+	}
+	a.Entry = &osv.Entry{}
+	if err := json.Unmarshal(entry, a.Entry); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/osv"
+)
+
+func TestAdvisories(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	entry := &osv.Entry{
+		ID:       "CORP-2026-0001",
+		Modified: time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		Summary:  "Bad thing",
+		Affected: []osv.Affected{{Module: osv.Module{Path: "corp.example.com/mod", Ecosystem: "Go"}}},
+	}
+	if err := testDB.UpsertAdvisory(ctx, &internal.Advisory{Entry: entry, Publisher: "security"}); err != nil {
+		t.Fatal(err)
+	}
+	updated := *entry
+	updated.Summary = "Worse thing"
+	if err := testDB.UpsertAdvisory(ctx, &internal.Advisory{Entry: &updated, Publisher: "other"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := testDB.GetAdvisory(ctx, entry.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&updated, got.Entry); diff != "" {
+		t.Errorf("GetAdvisory entry mismatch (-want, +got):\n%s", diff)
+	}
+	if got.Publisher != "other" {
+		t.Errorf("got publisher %q, want %q", got.Publisher, "other")
+	}
+	all, err := testDB.GetAdvisories(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].Entry.ID != entry.ID {
+		t.Errorf("GetAdvisories: got %v, want one advisory", all)
+	}
+
+	if err := testDB.DeleteAdvisory(ctx, entry.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testDB.GetAdvisory(ctx, entry.ID); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("GetAdvisory(deleted): got %v, want NotFound", err)
+	}
+	if err := testDB.DeleteAdvisory(ctx, entry.ID); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("DeleteAdvisory twice: got %v, want NotFound", err)
+	}
+}
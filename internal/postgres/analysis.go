@@ -0,0 +1,72 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"golang.org/x/pkgsite/internal/analysis"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// UpsertAnalysisReport stores r as the report of r.Analyzer for the module
+// version r.ModulePath@r.Version, replacing any earlier one. It returns an
+// error wrapping derrors.NotFound if the module version is not in the
+// database.
+func (db *DB) UpsertAnalysisReport(ctx context.Context, r *analysis.Report) (err error) {
+	defer derrors.WrapStack(&err, "UpsertAnalysisReport(ctx, %q, %q, %q)", r.ModulePath, r.Version, r.Analyzer)
+
+	metrics, err := json.Marshal(r.Metrics)
+	if err != nil {
+		return err
+	}
+	n, err := db.db.Exec(ctx, `
+		INSERT INTO analysis_reports (module_id, analyzer, summary, metrics, url, submitter, created_at)
+		SELECT id, $3, $4, $5, $6, $7, CURRENT_TIMESTAMP
+		FROM modules
+		WHERE module_path = $1 AND version = $2
+		ON CONFLICT (module_id, analyzer) DO UPDATE SET
+			summary=excluded.summary,
+			metrics=excluded.metrics,
+			url=excluded.url,
+			submitter=excluded.submitter,
+			created_at=excluded.created_at`,
+		r.ModulePath, r.Version, r.Analyzer, r.Summary, metrics, r.URL, r.Submitter)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return derrors.NotFound
+	}
+	return nil
+}
+
+// GetAnalysisReports returns the reports for the module version
+// modulePath@version, sorted by analyzer.
+func (db *DB) GetAnalysisReports(ctx context.Context, modulePath, version string) (_ []*analysis.Report, err error) {
+	defer derrors.WrapStack(&err, "GetAnalysisReports(ctx, %q, %q)", modulePath, version)
+
+	var reports []*analysis.Report
+	err = db.db.RunQuery(ctx, `
+		SELECT a.analyzer, a.summary, a.metrics, a.url, a.submitter, a.created_at
+		FROM analysis_reports a
+		INNER JOIN modules m ON m.id = a.module_id
+		WHERE m.module_path = $1 AND m.version = $2
+		ORDER BY a.analyzer`,
+		func(rows *sql.Rows) error {
+			r := &analysis.Report{ModulePath: modulePath, Version: version}
+			if err := rows.Scan(&r.Analyzer, &r.Summary, jsonbScanner{&r.Metrics}, &r.URL, &r.Submitter, &r.CreatedAt); err != nil {
+				return err
+			}
+			reports = append(reports, r)
+			return nil
+		}, modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
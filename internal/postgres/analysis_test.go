@@ -0,0 +1,79 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/pkgsite/internal/analysis"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestAnalysisReports(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.Module("example.com/mod", "v1.0.0", sample.Suffix)
+	MustInsertModule(ctx, t, testDB, m)
+
+	staticcheck := &analysis.Report{
+		ModulePath: m.ModulePath,
+		Version:    m.Version,
+		Analyzer:   "staticcheck",
+		Summary:    "2 problems",
+		Metrics:    []analysis.Metric{{Name: "staticcheck", Value: 2}},
+		Submitter:  "ci",
+	}
+	gosec := &analysis.Report{
+		ModulePath: m.ModulePath,
+		Version:    m.Version,
+		Analyzer:   "gosec",
+		Summary:    "no issues",
+		Metrics:    []analysis.Metric{{Name: "high", Value: 0}},
+		URL:        "https://ci.example.com/run/1",
+		Submitter:  "ci",
+	}
+	for _, r := range []*analysis.Report{staticcheck, gosec} {
+		if err := testDB.UpsertAnalysisReport(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A later report of the same analyzer replaces the earlier one.
+	gosec.Summary = "1 issue"
+	gosec.Metrics = []analysis.Metric{{Name: "high", Value: 1}}
+	if err := testDB.UpsertAnalysisReport(ctx, gosec); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := testDB.GetAnalysisReports(ctx, m.ModulePath, m.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*analysis.Report{gosec, staticcheck}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(analysis.Report{}, "CreatedAt")); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	got, err = testDB.GetAnalysisReports(ctx, m.ModulePath, "v1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %d reports for a version without reports, want 0", len(got))
+	}
+
+	other := *gosec
+	other.Version = "v1.1.0"
+	if err := testDB.UpsertAnalysisReport(ctx, &other); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("UpsertAnalysisReport for an unknown version: got %v, want NotFound", err)
+	}
+}
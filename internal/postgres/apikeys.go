@@ -0,0 +1,145 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// secretLen is the number of random bytes in a secret, such as an API key.
+const secretLen = 32
+
+// newSecret returns a new random secret.
+func newSecret() (string, error) {
+	b := make([]byte, secretLen)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashSecret returns the hash of a secret that is stored in place of the
+// secret, such as an API key. Secrets are long random strings, so an unsalted
+// hash is sufficient.
+func hashSecret(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
+
+// CreateAPIKey issues a new API key for name, allowing qps requests per
+// second. If qps is zero, the default quota for API keys applies. The key
+// may publish advisories for the modules under advisoryPrefixes, and post
+// analysis reports for the modules under analysisPrefixes.
+//
+// It returns the key along with its description. The key cannot be
+// retrieved later, so it must be handed to the client now.
+func (db *DB) CreateAPIKey(ctx context.Context, name string, qps int, advisoryPrefixes, analysisPrefixes []string) (key string, _ *internal.APIKey, err error) {
+	defer derrors.WrapStack(&err, "CreateAPIKey(ctx, %q, %d, %q, %q)", name, qps, advisoryPrefixes, analysisPrefixes)
+
+	key, err = newSecret()
+	if err != nil {
+		return "", nil, err
+	}
+	if advisoryPrefixes == nil {
+		advisoryPrefixes = []string{}
+	}
+	if analysisPrefixes == nil {
+		analysisPrefixes = []string{}
+	}
+	ak := &internal.APIKey{Name: name, QPS: qps, AdvisoryPrefixes: advisoryPrefixes, AnalysisPrefixes: analysisPrefixes}
+	err = db.db.QueryRow(ctx, `
+		INSERT INTO api_keys (key_hash, name, qps, advisory_prefixes, analysis_prefixes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at`,
+		hashSecret(key), name, qps, pq.Array(advisoryPrefixes), pq.Array(analysisPrefixes)).Scan(&ak.ID, &ak.CreatedAt)
+	if err != nil {
+		return "", nil, err
+	}
+	return key, ak, nil
+}
+
+// GetAPIKey returns the description of key. It returns an error wrapping
+// derrors.NotFound if the key was never issued or has been revoked.
+func (db *DB) GetAPIKey(ctx context.Context, key string) (_ *internal.APIKey, err error) {
+	defer derrors.WrapStack(&err, "GetAPIKey(ctx)")
+
+	ak, err := scanAPIKey(db.db.QueryRow(ctx, `
+		SELECT id, name, qps, advisory_prefixes, analysis_prefixes, created_at, revoked_at
+		FROM api_keys
+		WHERE key_hash = $1 AND revoked_at IS NULL`,
+		hashSecret(key)).Scan)
+	if err == sql.ErrNoRows {
+		return nil, derrors.NotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return ak, nil
+}
+
+// GetAPIKeys returns the descriptions of all issued API keys, including
+// revoked ones, from newest to oldest.
+func (db *DB) GetAPIKeys(ctx context.Context) (_ []*internal.APIKey, err error) {
+	defer derrors.WrapStack(&err, "GetAPIKeys(ctx)")
+
+	var aks []*internal.APIKey
+	err = db.db.RunQuery(ctx, `
+		SELECT id, name, qps, advisory_prefixes, analysis_prefixes, created_at, revoked_at
+		FROM api_keys
+		ORDER BY id DESC`,
+		func(rows *sql.Rows) error {
+			ak, err := scanAPIKey(rows.Scan)
+			if err != nil {
+				return err
+			}
+			aks = append(aks, ak)
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return aks, nil
+}
+
+// RevokeAPIKey revokes the API key with the given ID. It returns an error
+// wrapping derrors.NotFound if there is no such key or it is already revoked.
+func (db *DB) RevokeAPIKey(ctx context.Context, id int64) (err error) {
+	defer derrors.WrapStack(&err, "RevokeAPIKey(ctx, %d)", id)
+
+	n, err := db.db.Exec(ctx, `
+		UPDATE api_keys
+		SET revoked_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND revoked_at IS NULL`, id)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return derrors.NotFound
+	}
+	return nil
+}
+
+func scanAPIKey(scan func(dest ...any) error) (*internal.APIKey, error) {
+	var (
+		ak        internal.APIKey
+		revokedAt pq.NullTime
+	)
+	if err := scan(&ak.ID, &ak.Name, &ak.QPS, pq.Array(&ak.AdvisoryPrefixes), pq.Array(&ak.AnalysisPrefixes), &ak.CreatedAt, &revokedAt); err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		ak.RevokedAt = revokedAt.Time
+	}
+	return &ak, nil
+}
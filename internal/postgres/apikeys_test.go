@@ -0,0 +1,62 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+func TestAPIKeys(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	key, created, err := testDB.CreateAPIKey(ctx, "example", 20, []string{"corp.example.com"}, []string{"corp.example.com/ci"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key == "" {
+		t.Fatal("got empty key")
+	}
+	got, err := testDB.GetAPIKey(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != created.ID || got.Name != "example" || got.QPS != 20 {
+		t.Errorf("GetAPIKey: got %+v, want ID %d, name %q, QPS 20", got, created.ID, "example")
+	}
+	if !got.CanPublishAdvisory("corp.example.com/mod") || got.CanPublishAdvisory("example.com/mod") {
+		t.Errorf("GetAPIKey: got advisory prefixes %q, want [corp.example.com]", got.AdvisoryPrefixes)
+	}
+	if !got.CanReportAnalysis("corp.example.com/ci/tool") || got.CanReportAnalysis("corp.example.com/mod") {
+		t.Errorf("GetAPIKey: got analysis prefixes %q, want [corp.example.com/ci]", got.AnalysisPrefixes)
+	}
+	if _, err := testDB.GetAPIKey(ctx, key+"x"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("GetAPIKey(unknown key): got %v, want NotFound", err)
+	}
+
+	if err := testDB.RevokeAPIKey(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testDB.GetAPIKey(ctx, key); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("GetAPIKey(revoked key): got %v, want NotFound", err)
+	}
+	if err := testDB.RevokeAPIKey(ctx, created.ID); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("RevokeAPIKey twice: got %v, want NotFound", err)
+	}
+
+	all, err := testDB.GetAPIKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].RevokedAt.IsZero() {
+		t.Errorf("GetAPIKeys: got %+v, want one revoked key", all)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware/stats"
+)
+
+// GetAutocompleteSuggestions returns up to limit packages and symbols that
+// complete prefix, most imported first. Only the packages and symbols stored
+// by the last RefreshAutocompleteCompletions are suggested.
+//
+// A package matches if its path or its name begins with prefix. A symbol
+// matches if its name begins with prefix. Matching is case-sensitive, so that
+// it can use the prefix indexes on those columns.
+func (db *DB) GetAutocompleteSuggestions(ctx context.Context, prefix string, limit int) (_ []*internal.AutocompleteSuggestion, err error) {
+	defer derrors.WrapStack(&err, "GetAutocompleteSuggestions(ctx, %q, %d)", prefix, limit)
+	defer stats.Elapsed(ctx, "GetAutocompleteSuggestions")()
+
+	query := `
+		SELECT package_path, symbol_name, imported_by_count
+		FROM autocomplete_completions
+		WHERE (symbol_name = '' AND (package_path LIKE $1 OR name LIKE $1))
+			OR symbol_name LIKE $1
+		ORDER BY imported_by_count DESC, symbol_name, package_path
+		LIMIT $2`
+
+	var suggestions []*internal.AutocompleteSuggestion
+	collect := func(rows *sql.Rows) error {
+		var s internal.AutocompleteSuggestion
+		if err := rows.Scan(&s.PackagePath, &s.Symbol, &s.NumImportedBy); err != nil {
+			return err
+		}
+		suggestions = append(suggestions, &s)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, escapeLike(prefix)+"%", limit); err != nil {
+		return nil, err
+	}
+	return suggestions, nil
+}
+
+// RefreshAutocompleteCompletions replaces the packages and symbols that
+// GetAutocompleteSuggestions suggests with the limit most imported packages
+// and the most imported symbols, of which there are between limit and
+// limit*len(internal.BuildContexts). It returns the number of completions
+// stored.
+func (db *DB) RefreshAutocompleteCompletions(ctx context.Context, limit int) (_ int64, err error) {
+	defer derrors.WrapStack(&err, "RefreshAutocompleteCompletions(ctx, %d)", limit)
+
+	var n int64
+	err = db.db.Transact(ctx, sql.LevelDefault, func(tx *database.DB) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM autocomplete_completions`); err != nil {
+			return err
+		}
+		np, err := tx.Exec(ctx, `
+			INSERT INTO autocomplete_completions
+				(package_path, name, symbol_name, module_path, imported_by_count)
+			SELECT package_path, name, '', module_path, imported_by_count
+			FROM search_documents
+			ORDER BY imported_by_count DESC, package_path
+			LIMIT $1`, limit)
+		if err != nil {
+			return err
+		}
+		// symbol_search_documents has a row for each build context in which
+		// a symbol is defined. Read enough of the most imported rows to find
+		// at least limit distinct symbols, without grouping the whole table.
+		ns, err := tx.Exec(ctx, `
+			INSERT INTO autocomplete_completions
+				(package_path, name, symbol_name, module_path, imported_by_count)
+			SELECT ssd.package_path, '', ssd.symbol_name, sd.module_path, max(ssd.imported_by_count)
+			FROM (
+				SELECT package_path, symbol_name, imported_by_count
+				FROM symbol_search_documents
+				ORDER BY imported_by_count DESC
+				LIMIT $1
+			) ssd
+			INNER JOIN search_documents sd ON sd.package_path = ssd.package_path
+			GROUP BY ssd.package_path, ssd.symbol_name, sd.module_path`,
+			limit*len(internal.BuildContexts))
+		if err != nil {
+			return err
+		}
+		n = np + ns
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// likeEscaper escapes the characters that are special in a LIKE pattern, using
+// the default escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike returns s as a LIKE pattern that matches only s.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
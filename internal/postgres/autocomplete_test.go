@@ -0,0 +1,140 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetAutocompleteSuggestions(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.Module("example.com/encoding", sample.VersionString, "json", "jsonx")
+	for _, u := range m.Packages() {
+		u.Documentation[0].API = []*internal.Symbol{{
+			SymbolMeta: internal.SymbolMeta{
+				Name:     "Marshal",
+				Synopsis: "func Marshal(v any) ([]byte, error)",
+				Section:  internal.SymbolSectionFunctions,
+				Kind:     internal.SymbolKindFunction,
+			},
+			GOOS:   internal.All,
+			GOARCH: internal.All,
+		}}
+	}
+	MustInsertModule(ctx, t, testDB, m)
+	if got, err := testDB.GetAutocompleteSuggestions(ctx, "jsonx", 10); err != nil || len(got) != 0 {
+		t.Fatalf("before refresh: got %v, %v; want no suggestions", got, err)
+	}
+	if _, err := testDB.RefreshAutocompleteCompletions(ctx, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	pkg := func(path string) *internal.AutocompleteSuggestion {
+		return &internal.AutocompleteSuggestion{PackagePath: path}
+	}
+	sym := func(path, name string) *internal.AutocompleteSuggestion {
+		return &internal.AutocompleteSuggestion{PackagePath: path, Symbol: name}
+	}
+	for _, test := range []struct {
+		prefix string
+		limit  int
+		want   []*internal.AutocompleteSuggestion
+	}{
+		{"example.com/enc", 10, []*internal.AutocompleteSuggestion{
+			pkg("example.com/encoding/json"), pkg("example.com/encoding/jsonx"),
+		}},
+		{"example.com/enc", 1, []*internal.AutocompleteSuggestion{
+			pkg("example.com/encoding/json"),
+		}},
+		{"jsonx", 10, []*internal.AutocompleteSuggestion{
+			pkg("example.com/encoding/jsonx"),
+		}},
+		{"Mar", 10, []*internal.AutocompleteSuggestion{
+			sym("example.com/encoding/json", "Marshal"), sym("example.com/encoding/jsonx", "Marshal"),
+		}},
+		{"Mar", 1, []*internal.AutocompleteSuggestion{
+			sym("example.com/encoding/json", "Marshal"),
+		}},
+		// LIKE wildcards in the prefix match only themselves.
+		{"jso_", 10, nil},
+		{"%", 10, nil},
+	} {
+		t.Run(test.prefix, func(t *testing.T) {
+			got, err := testDB.GetAutocompleteSuggestions(ctx, test.prefix, test.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRefreshAutocompleteCompletions(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.Module("example.com/mod", sample.VersionString, "a", "b")
+	for _, u := range m.Packages() {
+		u.Documentation[0].API = []*internal.Symbol{
+			newSymbol("Dial", internal.SymbolKindFunction, internal.SymbolSectionFunctions),
+		}
+	}
+	MustInsertModule(ctx, t, testDB, m)
+	// Two packages, and the Dial function of each.
+	n, err := testDB.RefreshAutocompleteCompletions(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 4 {
+		t.Errorf("got %d completions, want 4", n)
+	}
+	got, err := testDB.GetAutocompleteSuggestions(ctx, "Dia", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d suggestions for Dia, want 2", len(got))
+	}
+
+	// Taking down the module removes its completions.
+	if _, err := testDB.CreateTakedown(ctx, "example.com/mod", "", "alice", "ticket", ""); err != nil {
+		t.Fatal(err)
+	}
+	got, err = testDB.GetAutocompleteSuggestions(ctx, "example.com/mod", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %d suggestions for a taken-down module, want none", len(got))
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"abc", "abc"},
+		{"a_b", `a\_b`},
+		{"100%", `100\%`},
+		{`a\b`, `a\\b`},
+	} {
+		if got := escapeLike(test.in); got != test.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
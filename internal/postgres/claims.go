@@ -0,0 +1,201 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// CreateModuleClaim records a new, unverified claim to own modulePath.
+//
+// It returns the secret that identifies the claimant, along with the claim.
+// The secret cannot be retrieved later, so it must be handed to the claimant
+// now.
+func (db *DB) CreateModuleClaim(ctx context.Context, modulePath string) (secret string, _ *internal.ModuleClaim, err error) {
+	defer derrors.WrapStack(&err, "CreateModuleClaim(ctx, %q)", modulePath)
+
+	secret, err = newSecret()
+	if err != nil {
+		return "", nil, err
+	}
+	challenge, err := newSecret()
+	if err != nil {
+		return "", nil, err
+	}
+	c := &internal.ModuleClaim{ModulePath: modulePath, Challenge: challenge}
+	err = db.db.QueryRow(ctx, `
+		INSERT INTO module_claims (module_path, challenge, secret_hash)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at`,
+		modulePath, challenge, hashSecret(secret)).Scan(&c.ID, &c.CreatedAt)
+	if err != nil {
+		return "", nil, err
+	}
+	return secret, c, nil
+}
+
+// GetModuleClaim returns the claim with the given ID. It returns an error
+// wrapping derrors.NotFound if there is no such claim, or if secret is not the
+// secret that was issued with it.
+func (db *DB) GetModuleClaim(ctx context.Context, id int64, secret string) (_ *internal.ModuleClaim, err error) {
+	defer derrors.WrapStack(&err, "GetModuleClaim(ctx, %d)", id)
+
+	var (
+		c          internal.ModuleClaim
+		verifiedAt pq.NullTime
+	)
+	err = db.db.QueryRow(ctx, `
+		SELECT id, module_path, challenge, verified_by, contact_url, created_at, verified_at
+		FROM module_claims
+		WHERE id = $1 AND secret_hash = $2`,
+		id, hashSecret(secret)).Scan(&c.ID, &c.ModulePath, &c.Challenge, &c.VerifiedBy, &c.ContactURL, &c.CreatedAt, &verifiedAt)
+	switch err {
+	case nil:
+		if verifiedAt.Valid {
+			c.VerifiedAt = verifiedAt.Time
+		}
+		return &c, nil
+	case sql.ErrNoRows:
+		return nil, derrors.NotFound
+	default:
+		return nil, err
+	}
+}
+
+// VerifyModuleClaim marks the claim with the given ID as verified by
+// verifiedBy, one of internal.ClaimVerifiedByDNS and
+// internal.ClaimVerifiedByFile.
+func (db *DB) VerifyModuleClaim(ctx context.Context, id int64, verifiedBy string) (err error) {
+	defer derrors.WrapStack(&err, "VerifyModuleClaim(ctx, %d, %q)", id, verifiedBy)
+
+	n, err := db.db.Exec(ctx, `
+		UPDATE module_claims
+		SET verified_by = $2, verified_at = CURRENT_TIMESTAMP
+		WHERE id = $1`, id, verifiedBy)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return derrors.NotFound
+	}
+	return nil
+}
+
+// SetModuleClaimContactURL sets the contact URL of the verified claim with the
+// given ID. It returns an error wrapping derrors.NotFound if there is no such
+// verified claim.
+func (db *DB) SetModuleClaimContactURL(ctx context.Context, id int64, contactURL string) (err error) {
+	defer derrors.WrapStack(&err, "SetModuleClaimContactURL(ctx, %d, %q)", id, contactURL)
+
+	n, err := db.db.Exec(ctx, `
+		UPDATE module_claims
+		SET contact_url = $2
+		WHERE id = $1 AND verified_by != ''`, id, contactURL)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return derrors.NotFound
+	}
+	return nil
+}
+
+// GetModuleContactURL returns the contact URL of the owner of modulePath,
+// from the most recently verified claim that has one. It returns the empty
+// string if there is none.
+func (db *DB) GetModuleContactURL(ctx context.Context, modulePath string) (_ string, err error) {
+	defer derrors.WrapStack(&err, "GetModuleContactURL(ctx, %q)", modulePath)
+
+	var contactURL string
+	err = db.db.QueryRow(ctx, `
+		SELECT contact_url
+		FROM module_claims
+		WHERE module_path = $1 AND verified_by != '' AND contact_url != ''
+		ORDER BY verified_at DESC
+		LIMIT 1`, modulePath).Scan(&contactURL)
+	switch err {
+	case nil:
+		return contactURL, nil
+	case sql.ErrNoRows:
+		return "", nil
+	default:
+		return "", err
+	}
+}
+
+// claimCreator is the value of excluded_prefixes.created_by for the patterns
+// that hide the versions of a claimed module.
+func claimCreator(id int64) string {
+	return fmt.Sprintf("claim:%d", id)
+}
+
+// HideModuleVersion hides version of the module of the verified claim c, by
+// excluding it from processing and serving. It does nothing if the version is
+// already excluded.
+func (db *DB) HideModuleVersion(ctx context.Context, c *internal.ModuleClaim, version string) (err error) {
+	defer derrors.WrapStack(&err, "HideModuleVersion(ctx, %d, %q)", c.ID, version)
+
+	if !c.Verified() {
+		return fmt.Errorf("claim %d is not verified: %w", c.ID, derrors.InvalidArgument)
+	}
+	_, err = db.db.Exec(ctx, `
+		INSERT INTO excluded_prefixes (prefix, created_by, reason)
+		VALUES ($1, $2, 'hidden by the module owner')
+		ON CONFLICT (prefix) DO NOTHING`,
+		c.ModulePath+"@"+version, claimCreator(c.ID))
+	if err != nil {
+		return err
+	}
+	db.expoller.Poll(ctx)
+	return nil
+}
+
+// UnhideModuleVersion undoes HideModuleVersion. It returns an error wrapping
+// derrors.NotFound if the version was not hidden with the claim c.
+func (db *DB) UnhideModuleVersion(ctx context.Context, c *internal.ModuleClaim, version string) (err error) {
+	defer derrors.WrapStack(&err, "UnhideModuleVersion(ctx, %d, %q)", c.ID, version)
+
+	n, err := db.db.Exec(ctx, `
+		DELETE FROM excluded_prefixes
+		WHERE prefix = $1 AND created_by = $2`,
+		c.ModulePath+"@"+version, claimCreator(c.ID))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return derrors.NotFound
+	}
+	db.expoller.Poll(ctx)
+	return nil
+}
+
+// GetHiddenModuleVersions returns the versions hidden with the claim c, in
+// sorted order.
+func (db *DB) GetHiddenModuleVersions(ctx context.Context, c *internal.ModuleClaim) (_ []string, err error) {
+	defer derrors.WrapStack(&err, "GetHiddenModuleVersions(ctx, %d)", c.ID)
+
+	patterns, err := database.Collect1[string](ctx, db.db, `
+		SELECT prefix FROM excluded_prefixes WHERE created_by = $1`, claimCreator(c.ID))
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, p := range patterns {
+		if v, ok := strings.CutPrefix(p, c.ModulePath+"@"); ok {
+			versions = append(versions, v)
+		}
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+func TestModuleClaims(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	const modulePath = "example.com/mod"
+	secret, c, err := testDB.CreateModuleClaim(ctx, modulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testDB.GetModuleClaim(ctx, c.ID, "wrong"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("GetModuleClaim with wrong secret: got %v, want NotFound", err)
+	}
+	got, err := testDB.GetModuleClaim(ctx, c.ID, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ModulePath != modulePath || got.Challenge != c.Challenge || got.Verified() {
+		t.Errorf("got %+v, want an unverified claim of %s with challenge %q", got, modulePath, c.Challenge)
+	}
+
+	// Settings can only be changed on verified claims.
+	if err := testDB.SetModuleClaimContactURL(ctx, c.ID, "https://example.com/contact"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("SetModuleClaimContactURL on unverified claim: got %v, want NotFound", err)
+	}
+	if err := testDB.HideModuleVersion(ctx, got, "v1.0.0"); !errors.Is(err, derrors.InvalidArgument) {
+		t.Errorf("HideModuleVersion on unverified claim: got %v, want InvalidArgument", err)
+	}
+
+	if err := testDB.VerifyModuleClaim(ctx, c.ID, internal.ClaimVerifiedByDNS); err != nil {
+		t.Fatal(err)
+	}
+	got, err = testDB.GetModuleClaim(ctx, c.ID, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.VerifiedBy != internal.ClaimVerifiedByDNS || got.VerifiedAt.IsZero() {
+		t.Errorf("got %+v, want a claim verified by DNS", got)
+	}
+
+	if url, err := testDB.GetModuleContactURL(ctx, modulePath); err != nil || url != "" {
+		t.Errorf("GetModuleContactURL before it is set = %q, %v; want empty", url, err)
+	}
+	const contact = "https://example.com/contact"
+	if err := testDB.SetModuleClaimContactURL(ctx, c.ID, contact); err != nil {
+		t.Fatal(err)
+	}
+	if url, err := testDB.GetModuleContactURL(ctx, modulePath); err != nil || url != contact {
+		t.Errorf("GetModuleContactURL = %q, %v; want %q", url, err, contact)
+	}
+
+	for _, v := range []string{"v1.1.0", "v1.0.0", "v1.0.0"} {
+		if err := testDB.HideModuleVersion(ctx, got, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !testDB.IsExcluded(ctx, modulePath, "v1.0.0") {
+		t.Error("hidden version is not excluded")
+	}
+	hidden, err := testDB.GetHiddenModuleVersions(ctx, got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"v1.0.0", "v1.1.0"}; !cmp.Equal(hidden, want) {
+		t.Errorf("GetHiddenModuleVersions = %v, want %v", hidden, want)
+	}
+	if err := testDB.UnhideModuleVersion(ctx, got, "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if testDB.IsExcluded(ctx, modulePath, "v1.0.0") {
+		t.Error("unhidden version is still excluded")
+	}
+	if err := testDB.UnhideModuleVersion(ctx, got, "v1.0.0"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("UnhideModuleVersion of a version that is not hidden: got %v, want NotFound", err)
+	}
+}
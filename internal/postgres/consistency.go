@@ -0,0 +1,200 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// A consistencyCheck finds the inconsistencies of one kind among a batch of
+// rows of a table, and repairs them.
+type consistencyCheck struct {
+	// table is checked in batches ordered by its integer column key.
+	table, key string
+
+	// query selects the ID, module path, version, path and detail of each
+	// inconsistency among the rows whose key is in ($1, $2].
+	query string
+
+	// repair repairs an inconsistency.
+	repair func(ctx context.Context, tx *database.DB, inc *internal.Inconsistency) error
+}
+
+var consistencyChecks = map[internal.InconsistencyKind]consistencyCheck{
+	internal.InconsistencyOrphanedUnit: {
+		table: "units",
+		key:   "id",
+		query: `
+			SELECT u.id, m.module_path, m.version, p.path, ''
+			FROM units u
+			INNER JOIN paths p ON p.id = u.path_id
+			INNER JOIN modules m ON m.id = u.module_id
+			WHERE
+				u.id > $1 AND u.id <= $2
+				AND m.module_path != 'std'
+				AND p.path != m.module_path
+				AND left(p.path, length(m.module_path) + 1) != m.module_path || '/'
+			ORDER BY u.id`,
+		// The unit cannot be served, so delete it, along with its
+		// documentation and search document.
+		repair: func(ctx context.Context, tx *database.DB, inc *internal.Inconsistency) error {
+			_, err := tx.Exec(ctx, `DELETE FROM units WHERE id = $1`, inc.ID)
+			return err
+		},
+	},
+	internal.InconsistencyMissingSource: {
+		table: "documentation",
+		key:   "id",
+		query: `
+			SELECT d.id, m.module_path, m.version, p.path, d.goos::text || '/' || d.goarch::text
+			FROM documentation d
+			INNER JOIN units u ON u.id = d.unit_id
+			INNER JOIN paths p ON p.id = u.path_id
+			INNER JOIN modules m ON m.id = u.module_id
+			WHERE
+				d.id > $1 AND d.id <= $2
+				AND d.source IS NULL
+				AND u.redistributable
+			ORDER BY d.id`,
+		// Only processing the module version again can restore the source.
+		repair: func(ctx context.Context, tx *database.DB, inc *internal.Inconsistency) error {
+			return markForReprocessing(ctx, tx, inc.ModulePath, inc.Version, false)
+		},
+	},
+	internal.InconsistencyStaleSymbolHistory: {
+		table: "symbol_history",
+		key:   "id",
+		query: `
+			SELECT sh.id, mp.path, sh.since_version, pp.path, 'symbol ' || s.name
+			FROM symbol_history sh
+			INNER JOIN paths mp ON mp.id = sh.module_path_id
+			INNER JOIN paths pp ON pp.id = sh.package_path_id
+			INNER JOIN symbol_names s ON s.id = sh.symbol_name_id
+			WHERE
+				sh.id > $1 AND sh.id <= $2
+				AND NOT EXISTS (
+					SELECT 1 FROM modules m
+					WHERE m.module_path = mp.path AND m.version = sh.since_version
+				)
+			ORDER BY sh.id`,
+		// The version that introduced the symbol can only be recomputed by
+		// processing the release versions of the module again, which
+		// replace a row only with an earlier version, so delete the row
+		// first.
+		repair: func(ctx context.Context, tx *database.DB, inc *internal.Inconsistency) error {
+			if _, err := tx.Exec(ctx, `DELETE FROM symbol_history WHERE id = $1`, inc.ID); err != nil {
+				return err
+			}
+			return markForReprocessing(ctx, tx, inc.ModulePath, "", true)
+		},
+	},
+	internal.InconsistencyStaleSearchDocument: {
+		table: "search_documents",
+		key:   "package_path_id",
+		query: `
+			SELECT sd.package_path_id, sd.module_path, sd.version, sd.package_path, ''
+			FROM search_documents sd
+			WHERE
+				sd.package_path_id > $1 AND sd.package_path_id <= $2
+				AND NOT EXISTS (
+					SELECT 1
+					FROM units u
+					INNER JOIN modules m ON m.id = u.module_id
+					WHERE
+						u.id = sd.unit_id
+						AND m.module_path = sd.module_path
+						AND m.version = sd.version
+				)
+			ORDER BY sd.package_path_id`,
+		// Search results for the document would lead to a missing page.
+		// Processing the latest version of the package again adds it back.
+		repair: func(ctx context.Context, tx *database.DB, inc *internal.Inconsistency) error {
+			_, err := tx.Exec(ctx, `DELETE FROM search_documents WHERE package_path_id = $1`, inc.ID)
+			return err
+		},
+	},
+}
+
+// CheckConsistency looks for inconsistencies of the given kind among at most
+// limit rows of the table checked for it, in order, starting after the row
+// whose ID is after. It returns the inconsistencies it found, and the ID of
+// the last row it checked, to pass as after to check the next batch. That is
+// 0 if there were no rows left to check.
+//
+// If repair is true, each inconsistency is also repaired, by deleting the
+// inconsistent rows or by marking module versions to be reprocessed.
+func (db *DB) CheckConsistency(ctx context.Context, kind internal.InconsistencyKind, after int64, limit int, repair bool) (_ []*internal.Inconsistency, last int64, err error) {
+	defer derrors.WrapStack(&err, "CheckConsistency(ctx, %q, %d, %d, %t)", kind, after, limit, repair)
+
+	check, ok := consistencyChecks[kind]
+	if !ok {
+		return nil, 0, fmt.Errorf("no check for %q: %w", kind, derrors.InvalidArgument)
+	}
+	var max sql.NullInt64
+	if err := db.db.QueryRow(ctx, fmt.Sprintf(`
+		SELECT max(%[1]s) FROM (
+			SELECT %[1]s FROM %[2]s WHERE %[1]s > $1 ORDER BY %[1]s LIMIT $2
+		) b`, check.key, check.table),
+		after, limit).Scan(&max); err != nil {
+		return nil, 0, err
+	}
+	if !max.Valid {
+		return nil, 0, nil
+	}
+	var incs []*internal.Inconsistency
+	collect := func(rows *sql.Rows) error {
+		inc := &internal.Inconsistency{Kind: kind}
+		if err := rows.Scan(&inc.ID, &inc.ModulePath, &inc.Version, &inc.Path, &inc.Detail); err != nil {
+			return err
+		}
+		incs = append(incs, inc)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, check.query, collect, after, max.Int64); err != nil {
+		return nil, 0, err
+	}
+	if repair {
+		for _, inc := range incs {
+			if err := db.db.Transact(ctx, sql.LevelDefault, func(tx *database.DB) error {
+				return check.repair(ctx, tx, inc)
+			}); err != nil {
+				return nil, 0, fmt.Errorf("repairing %s: %w", inc, err)
+			}
+			log.Infof(ctx, "repaired %s", inc)
+		}
+	}
+	return incs, max.Int64, nil
+}
+
+// markForReprocessing marks version of the module at modulePath to be
+// reprocessed, if it was processed successfully. If version is empty, it
+// marks the module's release versions that were processed successfully,
+// except incompatible ones, which is what symbol history is computed from.
+func markForReprocessing(ctx context.Context, tx *database.DB, modulePath, version string, releaseOnly bool) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE module_version_states
+		SET
+			status = (
+				CASE WHEN status=200 THEN 520
+					 WHEN status=290 THEN 521
+					 END
+				),
+			next_processed_after = CURRENT_TIMESTAMP,
+			last_processed_at = NULL
+		WHERE
+			module_path = $1
+			AND ($2 = '' OR version = $2)
+			AND (status = 200 OR status = 290)
+			AND (NOT $3 OR (right(sort_version, 1) = '~' AND NOT incompatible))`,
+		modulePath, version, releaseOnly)
+	return err
+}
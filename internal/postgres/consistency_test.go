@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	MustInsertModule(ctx, t, testDB, sample.Module("mod.com", "v1.2.3", "A"))
+	MustInsertModule(ctx, t, testDB, sample.Module("other.com", "v1.0.0", "B"))
+
+	// Move the unit mod.com/A to the module other.com, which leaves its
+	// search document pointing at a unit that is not in mod.com.
+	if _, err := testDB.db.Exec(ctx, `
+		UPDATE units
+		SET module_id = (SELECT id FROM modules WHERE module_path = 'other.com')
+		WHERE path_id = (SELECT id FROM paths WHERE path = 'mod.com/A')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testDB.db.Exec(ctx, `
+		UPDATE documentation SET source = NULL
+		WHERE unit_id = (
+			SELECT u.id FROM units u INNER JOIN paths p ON p.id = u.path_id
+			WHERE p.path = 'other.com/B'
+		)`); err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(kind internal.InconsistencyKind, repair bool) []string {
+		t.Helper()
+		var got []string
+		after := int64(0)
+		for {
+			incs, last, err := testDB.CheckConsistency(ctx, kind, after, 1, repair)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if last == 0 {
+				return got
+			}
+			if last <= after {
+				t.Fatalf("%s: last %d is not after %d", kind, last, after)
+			}
+			for _, inc := range incs {
+				got = append(got, inc.String())
+			}
+			after = last
+		}
+	}
+
+	for _, test := range []struct {
+		kind internal.InconsistencyKind
+		want []string
+	}{
+		{internal.InconsistencyOrphanedUnit, []string{"orphaned-unit: other.com@v1.0.0 mod.com/A"}},
+		{internal.InconsistencyMissingSource, []string{"missing-source: other.com@v1.0.0 other.com/B: all/all"}},
+		{internal.InconsistencyStaleSymbolHistory, nil},
+		{internal.InconsistencyStaleSearchDocument, []string{"stale-search-document: mod.com@v1.2.3 mod.com/A"}},
+	} {
+		if diff := cmp.Diff(test.want, check(test.kind, false)); diff != "" {
+			t.Errorf("%s mismatch (-want, +got):\n%s", test.kind, diff)
+		}
+	}
+
+	// Repairing the orphaned unit and the stale search document deletes them,
+	// so they are not found again.
+	for _, kind := range []internal.InconsistencyKind{
+		internal.InconsistencyOrphanedUnit,
+		internal.InconsistencyStaleSearchDocument,
+	} {
+		if got := check(kind, true); len(got) != 1 {
+			t.Errorf("%s: repaired %v, want one", kind, got)
+		}
+		if got := check(kind, false); len(got) != 0 {
+			t.Errorf("%s: after repair, found %v", kind, got)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/lib/pq"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/database"
 	"golang.org/x/pkgsite/internal/derrors"
@@ -31,7 +32,12 @@ func (db *DB) GetNestedModules(ctx context.Context, modulePath string) (_ []*int
 			m.commit_time,
 			m.redistributable,
 			m.has_go_mod,
-			m.source_info
+			m.go_version,
+			m.source_info,
+			m.author_metadata,
+			m.checksum,
+			m.content_warnings,
+			m.commit
 		FROM
 			modules m
 		WHERE
@@ -122,6 +128,34 @@ func (db *DB) GetImportedByCount(ctx context.Context, pkgPath, modulePath string
 	}
 }
 
+// GetPackageSynopses returns the synopses of the packages in paths, keyed by
+// package path. Packages that are not in search_documents are omitted.
+func (db *DB) GetPackageSynopses(ctx context.Context, paths []string) (_ map[string]string, err error) {
+	defer derrors.WrapStack(&err, "GetPackageSynopses(ctx, %d paths)", len(paths))
+	defer stats.Elapsed(ctx, "GetPackageSynopses")()
+
+	synopses := map[string]string{}
+	if len(paths) == 0 {
+		return synopses, nil
+	}
+	query := `
+		SELECT package_path, synopsis
+		FROM search_documents
+		WHERE package_path = ANY($1)`
+	err = db.db.RunQuery(ctx, query, func(rows *sql.Rows) error {
+		var path, synopsis string
+		if err := rows.Scan(&path, &synopsis); err != nil {
+			return err
+		}
+		synopses[path] = synopsis
+		return nil
+	}, pq.Array(paths))
+	if err != nil {
+		return nil, err
+	}
+	return synopses, nil
+}
+
 // GetModuleInfo fetches a module version from the database with the primary key
 // (module_path, version).
 func (db *DB) GetModuleInfo(ctx context.Context, modulePath, resolvedVersion string) (_ *internal.ModuleInfo, err error) {
@@ -134,7 +168,12 @@ func (db *DB) GetModuleInfo(ctx context.Context, modulePath, resolvedVersion str
 			commit_time,
 			redistributable,
 			has_go_mod,
-			source_info
+			go_version,
+			source_info,
+			author_metadata,
+			checksum,
+			content_warnings,
+			commit
 		FROM
 			modules
 		WHERE
@@ -189,7 +228,8 @@ func (s jsonbScanner) Scan(value any) (err error) {
 func scanModuleInfo(scan func(dest ...any) error) (*internal.ModuleInfo, error) {
 	var mi internal.ModuleInfo
 	if err := scan(&mi.ModulePath, &mi.Version, &mi.CommitTime,
-		&mi.IsRedistributable, &mi.HasGoMod, jsonbScanner{&mi.SourceInfo}); err != nil {
+		&mi.IsRedistributable, &mi.HasGoMod, database.NullIsEmpty(&mi.GoVersion), jsonbScanner{&mi.SourceInfo},
+		jsonbScanner{&mi.AuthorMetadata}, jsonbScanner{&mi.Checksum}, jsonbScanner{&mi.ContentWarnings}, jsonbScanner{&mi.Commit}); err != nil {
 		return nil, err
 	}
 	return &mi, nil
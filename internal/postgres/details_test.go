@@ -282,6 +282,51 @@ func TestGetImportedBy(t *testing.T) {
 	}
 }
 
+func TestGetPackageSynopses(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	testDB, release := acquire(t)
+	defer release()
+
+	m := sample.Module("path.to/foo", "v1.1.0", "bar")
+	MustInsertModule(ctx, t, testDB, m)
+	pkg := m.Packages()[0]
+
+	got, err := testDB.GetPackageSynopses(ctx, []string{pkg.Path, "path.to/unknown"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{pkg.Path: pkg.Documentation[0].Synopsis}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetUnitTestImports(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	testDB, release := acquire(t)
+	defer release()
+
+	m := sample.Module("path.to/foo", "v1.1.0", "bar")
+	pkg := m.Packages()[0]
+	pkg.Imports = []string{"fmt"}
+	pkg.TestImports = []string{"testing", "path.to/foo/internal/testutil"}
+	MustInsertModule(ctx, t, testDB, m)
+
+	got, err := testDB.GetUnit(ctx, &pkg.UnitMeta, internal.WithImports, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(pkg.Imports, got.Imports); diff != "" {
+		t.Errorf("Imports mismatch (-want +got):\n%s", diff)
+	}
+	want := []string{"path.to/foo/internal/testutil", "testing"}
+	if diff := cmp.Diff(want, got.TestImports, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("TestImports mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestJSONBScanner(t *testing.T) {
 	t.Parallel()
 	type S struct{ A int }
@@ -0,0 +1,62 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware/stats"
+)
+
+// GetBrokenDocLinks returns the broken doc links of the packages of the
+// module version, by package path. Packages without broken links are
+// omitted. A link that is broken in several build contexts is returned once.
+func (db *DB) GetBrokenDocLinks(ctx context.Context, modulePath, version string) (_ map[string][]*internal.BrokenDocLink, err error) {
+	defer derrors.WrapStack(&err, "GetBrokenDocLinks(ctx, %q, %q)", modulePath, version)
+	defer stats.Elapsed(ctx, "GetBrokenDocLinks")()
+
+	query := `
+		SELECT p.path, d.broken_doc_links
+		FROM documentation d
+		INNER JOIN units u
+		ON u.id = d.unit_id
+		INNER JOIN paths p
+		ON p.id = u.path_id
+		INNER JOIN modules m
+		ON m.id = u.module_id
+		WHERE m.module_path = $1
+			AND m.version = $2
+			AND d.broken_doc_links IS NOT NULL
+		ORDER BY p.path, d.goos, d.goarch`
+	type key struct {
+		path string
+		link internal.BrokenDocLink
+	}
+	seen := map[key]bool{}
+	links := map[string][]*internal.BrokenDocLink{}
+	collect := func(rows *sql.Rows) error {
+		var (
+			path string
+			ls   []*internal.BrokenDocLink
+		)
+		if err := rows.Scan(&path, jsonbScanner{&ls}); err != nil {
+			return err
+		}
+		for _, l := range ls {
+			if k := (key{path, *l}); !seen[k] {
+				seen[k] = true
+				links[path] = append(links[path], l)
+			}
+		}
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, modulePath, version); err != nil {
+		return nil, err
+	}
+	return links, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetBrokenDocLinks(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	links := []*internal.BrokenDocLink{{
+		Text:       "Missing",
+		ImportPath: "mod.com/A",
+		Name:       "Missing",
+		File:       "a.go",
+		Line:       3,
+		Reason:     "The package has no symbol Missing.",
+	}}
+	m := sample.Module("mod.com", "v1.2.3", "A", "B")
+	for _, u := range m.Units {
+		if u.Path == "mod.com/A" {
+			u.Documentation[0].BrokenDocLinks = links
+		}
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	got, err := testDB.GetBrokenDocLinks(ctx, "mod.com", "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]*internal.BrokenDocLink{"mod.com/A": links}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// DocumentationVersions holds the number of documentation rows processed by
+// a worker app version and Go toolchain. Both are empty for documentation
+// processed before they were recorded.
+type DocumentationVersions struct {
+	AppVersion string `json:"appVersion"`
+	Toolchain  string `json:"toolchain"`
+	NumRows    int    `json:"numRows"`
+}
+
+// GetDocumentationVersions returns the number of documentation rows for each
+// app version and toolchain that processed them, newest app version first.
+func (db *DB) GetDocumentationVersions(ctx context.Context) (_ []*DocumentationVersions, err error) {
+	defer derrors.WrapStack(&err, "GetDocumentationVersions(ctx)")
+
+	query := `
+		SELECT app_version, toolchain, count(*)
+		FROM documentation
+		GROUP BY 1, 2
+		ORDER BY 1 DESC NULLS LAST, 2 DESC NULLS LAST`
+	var dvs []*DocumentationVersions
+	err = db.db.RunQuery(ctx, query, func(rows *sql.Rows) error {
+		var dv DocumentationVersions
+		if err := rows.Scan(database.NullIsEmpty(&dv.AppVersion), database.NullIsEmpty(&dv.Toolchain), &dv.NumRows); err != nil {
+			return err
+		}
+		dvs = append(dvs, &dv)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dvs, nil
+}
+
+// GetModulesWithOutdatedDocumentation returns up to limit module versions,
+// sorted by path and version, that have documentation processed by an app
+// version older than appVersion, or by a toolchain other than toolchain.
+// Documentation whose app version was not recorded is always outdated. If
+// toolchain is empty, the toolchain is not considered.
+func (db *DB) GetModulesWithOutdatedDocumentation(ctx context.Context, appVersion, toolchain string, limit int) (_ []internal.Modver, err error) {
+	defer derrors.WrapStack(&err, "GetModulesWithOutdatedDocumentation(ctx, %q, %q, %d)", appVersion, toolchain, limit)
+
+	query := `
+		SELECT DISTINCT m.module_path, m.version
+		FROM documentation d
+		INNER JOIN units u ON u.id = d.unit_id
+		INNER JOIN modules m ON m.id = u.module_id
+		WHERE
+			d.app_version IS NULL
+			OR d.app_version < $1
+			OR ($2 != '' AND d.toolchain IS DISTINCT FROM $2)
+		ORDER BY 1, 2
+		LIMIT $3`
+	var mvs []internal.Modver
+	err = db.db.RunQuery(ctx, query, func(rows *sql.Rows) error {
+		var mv internal.Modver
+		if err := rows.Scan(&mv.Path, &mv.Version); err != nil {
+			return err
+		}
+		mvs = append(mvs, mv)
+		return nil
+	}, appVersion, toolchain, limit)
+	if err != nil {
+		return nil, err
+	}
+	return mvs, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestDocumentationVersions(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		modulePath, appVersion, toolchain string
+	}{
+		{"example.com/old", "20260101t000000", "go1.23.0"},
+		{"example.com/new", "20260601t000000", "go1.23.0"},
+		{"example.com/newtoolchain", "20260601t000000", "go1.24.0"},
+		{"example.com/unrecorded", "", ""},
+	} {
+		m := sample.Module(test.modulePath, sample.VersionString, "")
+		for _, u := range m.Units {
+			for _, d := range u.Documentation {
+				d.AppVersion = test.appVersion
+				d.Toolchain = test.toolchain
+			}
+		}
+		MustInsertModule(ctx, t, testDB, m)
+	}
+
+	gotVersions, err := testDB.GetDocumentationVersions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantVersions := []*DocumentationVersions{
+		{AppVersion: "20260601t000000", Toolchain: "go1.24.0", NumRows: 1},
+		{AppVersion: "20260601t000000", Toolchain: "go1.23.0", NumRows: 1},
+		{AppVersion: "20260101t000000", Toolchain: "go1.23.0", NumRows: 1},
+		{NumRows: 1},
+	}
+	if diff := cmp.Diff(wantVersions, gotVersions); diff != "" {
+		t.Errorf("GetDocumentationVersions mismatch (-want, +got):\n%s", diff)
+	}
+
+	got, err := testDB.GetUnit(ctx, newUnitMeta("example.com/old", "example.com/old", sample.VersionString), internal.AllFields, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := got.Documentation[0]; d.AppVersion != "20260101t000000" || d.Toolchain != "go1.23.0" {
+		t.Errorf("GetUnit: got app version %q and toolchain %q, want %q and %q", d.AppVersion, d.Toolchain, "20260101t000000", "go1.23.0")
+	}
+
+	for _, test := range []struct {
+		appVersion, toolchain string
+		want                  []string
+	}{
+		{"20260601t000000", "", []string{"example.com/old", "example.com/unrecorded"}},
+		{"20260601t000000", "go1.24.0", []string{"example.com/new", "example.com/old", "example.com/unrecorded"}},
+		{"20260101t000000", "", []string{"example.com/unrecorded"}},
+	} {
+		mvs, err := testDB.GetModulesWithOutdatedDocumentation(ctx, test.appVersion, test.toolchain, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, mv := range mvs {
+			got = append(got, mv.Path)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("GetModulesWithOutdatedDocumentation(%q, %q) mismatch (-want, +got):\n%s", test.appVersion, test.toolchain, diff)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"hash/fnv"
+	"math/bits"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// maxDuplicateDistance is the largest number of bits in which the simhashes
+// of two packages may differ for the packages to be considered near
+// duplicates.
+const maxDuplicateDistance = 3
+
+// minDuplicateSymbols is the minimum number of exported symbols a package must
+// have to be considered by duplicate detection.
+const minDuplicateSymbols = 4
+
+// simhash computes a 64-bit locality-sensitive hash of a package from its
+// synopsis and exported symbol names. Packages with similar synopses and
+// symbol sets have hashes that differ in only a few bits.
+func simhash(synopsis string, symbols []string) uint64 {
+	var counts [64]int
+	add := func(feature string, weight int) {
+		h := fnv.New64a()
+		h.Write([]byte(feature))
+		v := h.Sum64()
+		for i := range counts {
+			if v&(1<<i) != 0 {
+				counts[i] += weight
+			} else {
+				counts[i] -= weight
+			}
+		}
+	}
+	for _, w := range strings.Fields(strings.ToLower(synopsis)) {
+		add("w:"+w, 1)
+	}
+	// Symbols are weighted more heavily than words of the synopsis, because
+	// they change less between forks.
+	for _, s := range symbols {
+		add("s:"+s, 2)
+	}
+	var sh uint64
+	for i, c := range counts {
+		if c > 0 {
+			sh |= 1 << i
+		}
+	}
+	return sh
+}
+
+// exportedSymbolNames returns the sorted names of the exported symbols in
+// the first documentation of pkg, including methods and fields.
+func exportedSymbolNames(pkg *internal.Unit) []string {
+	if len(pkg.Documentation) == 0 {
+		return nil
+	}
+	var names []string
+	for _, s := range pkg.Documentation[0].API {
+		names = append(names, s.Name)
+		for _, c := range s.Children {
+			names = append(names, c.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UpdateDuplicateGroups computes the duplicate group of packages whose group
+// is unknown, for up to limit package names. Since forks and vendored copies
+// keep their package name, only packages with the same name are compared.
+// Every package with one of those names is assigned a group: the path of the
+// most imported package among its near duplicates.
+//
+// It returns the number of package names processed.
+func (db *DB) UpdateDuplicateGroups(ctx context.Context, limit int) (nNames int, err error) {
+	defer derrors.WrapStack(&err, "UpdateDuplicateGroups(ctx, %d)", limit)
+
+	names, err := database.Collect1[string](ctx, db.db, `
+		SELECT DISTINCT name
+		FROM search_documents
+		WHERE dup_group IS NULL AND simhash IS NOT NULL
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range names {
+		if err := db.updateDuplicateGroupsForName(ctx, name); err != nil {
+			return nNames, err
+		}
+		nNames++
+	}
+	return nNames, nil
+}
+
+// A dupCandidate is a package considered by duplicate detection.
+type dupCandidate struct {
+	path            string
+	simhash         uint64
+	importedByCount int
+}
+
+func (db *DB) updateDuplicateGroupsForName(ctx context.Context, name string) (err error) {
+	defer derrors.WrapStack(&err, "updateDuplicateGroupsForName(ctx, %q)", name)
+
+	var cands []dupCandidate
+	err = db.db.RunQuery(ctx, `
+		SELECT package_path, simhash, imported_by_count
+		FROM search_documents
+		WHERE name = $1 AND simhash IS NOT NULL
+	`, func(rows *sql.Rows) error {
+		var (
+			c  dupCandidate
+			sh int64
+		)
+		if err := rows.Scan(&c.path, &sh, &c.importedByCount); err != nil {
+			return err
+		}
+		c.simhash = uint64(sh)
+		cands = append(cands, c)
+		return nil
+	}, name)
+	if err != nil {
+		return err
+	}
+	groups := groupDuplicates(cands)
+	var paths, dupGroups []any
+	for p, g := range groups {
+		paths = append(paths, p)
+		dupGroups = append(dupGroups, g)
+	}
+	return db.db.BulkUpdate(ctx, "search_documents",
+		[]string{"package_path", "dup_group"}, []string{"TEXT", "TEXT"},
+		[][]any{paths, dupGroups})
+}
+
+// groupDuplicates assigns each candidate to a group of near duplicates and
+// returns a map from each candidate's path to the path of its group's
+// canonical package.
+//
+// Candidates are visited from most to least imported, so the canonical
+// package of a group is its most imported member. Each candidate joins the
+// first group whose canonical simhash is within maxDuplicateDistance bits of
+// its own, or starts a new group.
+func groupDuplicates(cands []dupCandidate) map[string]string {
+	sort.Slice(cands, func(i, j int) bool {
+		ci, cj := cands[i], cands[j]
+		if ci.importedByCount != cj.importedByCount {
+			return ci.importedByCount > cj.importedByCount
+		}
+		if len(ci.path) != len(cj.path) {
+			return len(ci.path) < len(cj.path)
+		}
+		return ci.path < cj.path
+	})
+
+	// If two hashes differ in at most maxDuplicateDistance bits, then at least
+	// one of maxDuplicateDistance+1 disjoint bands of the hashes is identical.
+	// Index the canonical packages by band to avoid comparing every pair.
+	const nBands = maxDuplicateDistance + 1
+	band := func(h uint64, b int) uint64 {
+		const width = 64 / nBands
+		return (h >> (b * width)) & (1<<width - 1)
+	}
+	var canonicals []dupCandidate
+	index := make([]map[uint64][]int, nBands) // band value to indexes into canonicals
+	for b := range index {
+		index[b] = map[uint64][]int{}
+	}
+
+	groups := map[string]string{}
+	for _, c := range cands {
+		group := ""
+	search:
+		for b := range nBands {
+			for _, i := range index[b][band(c.simhash, b)] {
+				if bits.OnesCount64(c.simhash^canonicals[i].simhash) <= maxDuplicateDistance {
+					group = canonicals[i].path
+					break search
+				}
+			}
+		}
+		if group == "" {
+			group = c.path
+			canonicals = append(canonicals, c)
+			for b := range nBands {
+				v := band(c.simhash, b)
+				index[b][v] = append(index[b][v], len(canonicals)-1)
+			}
+		}
+		groups[c.path] = group
+	}
+	return groups
+}
+
+// getDuplicateGroups returns a map from the package path of each result to
+// its duplicate group. Packages whose group is not known are omitted.
+func (db *DB) getDuplicateGroups(ctx context.Context, results []*SearchResult) (_ map[string]string, err error) {
+	defer derrors.WrapStack(&err, "getDuplicateGroups(ctx, [%d results])", len(results))
+
+	var paths []string
+	for _, r := range results {
+		paths = append(paths, r.PackagePath)
+	}
+	groups := map[string]string{}
+	err = db.db.RunQuery(ctx, `
+		SELECT package_path, dup_group
+		FROM search_documents
+		WHERE package_path = ANY($1) AND dup_group IS NOT NULL
+	`, func(rows *sql.Rows) error {
+		var p, g string
+		if err := rows.Scan(&p, &g); err != nil {
+			return err
+		}
+		groups[p] = g
+		return nil
+	}, pq.Array(paths))
+	if err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// collapseDuplicates moves each result that is in the same duplicate group as
+// a higher-scoring result into that result's Similar list. The results must
+// be sorted by score.
+func collapseDuplicates(rs []*SearchResult, groups map[string]string) []*SearchResult {
+	var results []*SearchResult
+	first := map[string]*SearchResult{} // group to its highest-scoring result
+	for _, r := range rs {
+		g, ok := groups[r.PackagePath]
+		if !ok {
+			results = append(results, r)
+			continue
+		}
+		if f := first[g]; f != nil {
+			f.Similar = append(f.Similar, r)
+			continue
+		}
+		first[g] = r
+		results = append(results, r)
+	}
+	return results
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"math/bits"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestSimhash(t *testing.T) {
+	t.Parallel()
+	symbols := []string{"Client", "Client.Do", "Client.Get", "NewClient", "Option", "WithTimeout", "Request", "Response"}
+	orig := simhash("Package api is a client for the Example API.", symbols)
+
+	// A fork with the same symbols and a lightly edited synopsis is a near duplicate.
+	fork := simhash("Package api is a client for the Example API", symbols)
+	if d := bits.OnesCount64(orig ^ fork); d > maxDuplicateDistance {
+		t.Errorf("fork: distance = %d, want <= %d", d, maxDuplicateDistance)
+	}
+
+	// An unrelated package is not.
+	other := simhash("Package yaml implements YAML support.", []string{"Marshal", "Unmarshal", "Decoder", "Encoder", "Node"})
+	if d := bits.OnesCount64(orig ^ other); d <= maxDuplicateDistance {
+		t.Errorf("other: distance = %d, want > %d", d, maxDuplicateDistance)
+	}
+}
+
+func TestGroupDuplicates(t *testing.T) {
+	t.Parallel()
+	cands := []dupCandidate{
+		{path: "github.com/fork/api", simhash: 0b0111, importedByCount: 1},
+		{path: "github.com/orig/api", simhash: 0b0000, importedByCount: 100},
+		{path: "github.com/user/vendor/github.com/orig/api", simhash: 0b0001, importedByCount: 0},
+		{path: "github.com/other/api", simhash: 0xffff_0000_0000_0000, importedByCount: 5},
+		{path: "github.com/far/api", simhash: 0b1111, importedByCount: 0},
+	}
+	got := groupDuplicates(cands)
+	want := map[string]string{
+		"github.com/orig/api":                        "github.com/orig/api",
+		"github.com/fork/api":                        "github.com/orig/api",
+		"github.com/user/vendor/github.com/orig/api": "github.com/orig/api",
+		"github.com/other/api":                       "github.com/other/api",
+		// Four bits away from the canonical package, even though it is close
+		// to another member of the group.
+		"github.com/far/api": "github.com/far/api",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestCollapseDuplicates(t *testing.T) {
+	t.Parallel()
+	rs := []*SearchResult{
+		{PackagePath: "a", Score: 10},
+		{PackagePath: "b", Score: 9},
+		{PackagePath: "a-fork", Score: 8},
+		{PackagePath: "c", Score: 7},
+		{PackagePath: "a-vendored", Score: 6},
+	}
+	groups := map[string]string{
+		"a":          "a",
+		"a-fork":     "a",
+		"a-vendored": "a",
+		"c":          "c",
+	}
+	got := collapseDuplicates(rs, groups)
+	want := []*SearchResult{
+		{PackagePath: "a", Score: 10, Similar: []*SearchResult{
+			{PackagePath: "a-fork", Score: 8},
+			{PackagePath: "a-vendored", Score: 6},
+		}},
+		{PackagePath: "b", Score: 9},
+		{PackagePath: "c", Score: 7},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestUpdateDuplicateGroups(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	for _, mp := range []string{"example.com/orig", "example.com/fork"} {
+		m := sample.Module(mp, sample.VersionString, "api")
+		MustInsertModule(ctx, t, testDB, m)
+	}
+	n, err := testDB.UpdateDuplicateGroups(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("got %d names, want 1", n)
+	}
+	results := []*SearchResult{{PackagePath: "example.com/orig/api"}, {PackagePath: "example.com/fork/api"}}
+	groups, err := testDB.getDuplicateGroups(ctx, results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if groups["example.com/orig/api"] != groups["example.com/fork/api"] {
+		t.Errorf("got groups %v, want the same group for both packages", groups)
+	}
+}
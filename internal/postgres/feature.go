@@ -0,0 +1,138 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// GetReadyFeatures returns the data features that have been backfilled for
+// the module, either for it alone or for all modules.
+func (db *DB) GetReadyFeatures(ctx context.Context, modulePath string) (_ map[internal.DataFeature]bool, err error) {
+	defer derrors.WrapStack(&err, "GetReadyFeatures(ctx, %q)", modulePath)
+
+	ready := map[internal.DataFeature]bool{}
+	err = db.db.RunQuery(ctx, `
+		SELECT DISTINCT feature
+		FROM feature_readiness
+		WHERE module_path = $1 OR module_path = ''`,
+		func(rows *sql.Rows) error {
+			var f string
+			if err := rows.Scan(&f); err != nil {
+				return err
+			}
+			ready[internal.DataFeature(f)] = true
+			return nil
+		}, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	return ready, nil
+}
+
+// SetFeatureReady records that the data feature has been backfilled for the
+// module, or for all modules if modulePath is empty.
+func (db *DB) SetFeatureReady(ctx context.Context, f internal.DataFeature, modulePath string) (err error) {
+	defer derrors.WrapStack(&err, "SetFeatureReady(ctx, %q, %q)", f, modulePath)
+
+	_, err = db.db.Exec(ctx, `
+		INSERT INTO feature_readiness (feature, module_path)
+		VALUES ($1, $2)
+		ON CONFLICT (feature, module_path) DO UPDATE
+		SET ready_at = CURRENT_TIMESTAMP`,
+		f, modulePath)
+	return err
+}
+
+// ClearFeatureReady removes the record that the data feature has been
+// backfilled for the module, or for all modules if modulePath is empty. It
+// returns derrors.NotFound if there is no such record.
+func (db *DB) ClearFeatureReady(ctx context.Context, f internal.DataFeature, modulePath string) (err error) {
+	defer derrors.WrapStack(&err, "ClearFeatureReady(ctx, %q, %q)", f, modulePath)
+
+	n, err := db.db.Exec(ctx, `
+		DELETE FROM feature_readiness
+		WHERE feature = $1 AND module_path = $2`,
+		f, modulePath)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return derrors.NotFound
+	}
+	return nil
+}
+
+// GetFeatureReadiness returns the state of the backfill of each data
+// feature, in the order of internal.DataFeatures.
+func (db *DB) GetFeatureReadiness(ctx context.Context) (_ []*internal.FeatureReadiness, err error) {
+	defer derrors.WrapStack(&err, "GetFeatureReadiness(ctx)")
+
+	byFeature := map[internal.DataFeature]*internal.FeatureReadiness{}
+	var frs []*internal.FeatureReadiness
+	for _, f := range internal.DataFeatures {
+		fr := &internal.FeatureReadiness{Feature: f}
+		byFeature[f] = fr
+		frs = append(frs, fr)
+	}
+	err = db.db.RunQuery(ctx, `
+		SELECT
+			feature,
+			max(ready_at) FILTER (WHERE module_path = ''),
+			count(*) FILTER (WHERE module_path <> '')
+		FROM feature_readiness
+		GROUP BY feature`,
+		func(rows *sql.Rows) error {
+			var (
+				f       string
+				readyAt pq.NullTime
+				n       int
+			)
+			if err := rows.Scan(&f, &readyAt, &n); err != nil {
+				return err
+			}
+			fr := byFeature[internal.DataFeature(f)]
+			if fr == nil {
+				// A feature that no longer exists.
+				return nil
+			}
+			fr.ReadyAt = readyAt.Time
+			fr.NumModules = n
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return frs, nil
+}
+
+// newModuleFeatures are the data features that InsertModule computes for
+// each version, so that they are complete for a module with a single version.
+var newModuleFeatures = []string{string(internal.FeatureSymbolHistory), string(internal.FeatureAPIDiff)}
+
+// setNewModuleFeaturesReady records that newModuleFeatures are ready for
+// modulePath if version is its only version. The symbol history of such a
+// module was computed when the version was inserted, so it does not need to
+// be backfilled.
+func setNewModuleFeaturesReady(ctx context.Context, tx *database.DB, modulePath, version string) (err error) {
+	defer derrors.WrapStack(&err, "setNewModuleFeaturesReady(ctx, tx, %q, %q)", modulePath, version)
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO feature_readiness (feature, module_path)
+		SELECT f, $1
+		FROM unnest($3::TEXT[]) f
+		WHERE NOT EXISTS (
+			SELECT 1 FROM modules WHERE module_path = $1 AND version <> $2
+		)
+		ON CONFLICT (feature, module_path) DO NOTHING`,
+		modulePath, version, pq.Array(newModuleFeatures))
+	return err
+}
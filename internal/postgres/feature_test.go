@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestFeatureReadiness(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	checkReady := func(modulePath string, want ...internal.DataFeature) {
+		t.Helper()
+		got, err := testDB.GetReadyFeatures(ctx, modulePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantMap := map[internal.DataFeature]bool{}
+		for _, f := range want {
+			wantMap[f] = true
+		}
+		if diff := cmp.Diff(wantMap, got); diff != "" {
+			t.Errorf("GetReadyFeatures(%q) mismatch (-want +got):\n%s", modulePath, diff)
+		}
+	}
+
+	// Inserting the first version of a module computes its whole symbol
+	// history; inserting another one doesn't.
+	const (
+		newPath = "example.com/new"
+		oldPath = "example.com/old"
+	)
+	MustInsertModule(ctx, t, testDB, sample.Module(newPath, "v1.0.0", "pkg"))
+	checkReady(newPath, internal.FeatureSymbolHistory, internal.FeatureAPIDiff)
+	if _, err := testDB.db.Exec(ctx, `DELETE FROM feature_readiness`); err != nil {
+		t.Fatal(err)
+	}
+	MustInsertModule(ctx, t, testDB, sample.Module(newPath, "v1.1.0", "pkg"))
+	checkReady(newPath)
+
+	if err := testDB.SetFeatureReady(ctx, internal.FeatureSymbolHistory, oldPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := testDB.SetFeatureReady(ctx, internal.FeatureScores, ""); err != nil {
+		t.Fatal(err)
+	}
+	checkReady(oldPath, internal.FeatureSymbolHistory, internal.FeatureScores)
+	checkReady(newPath, internal.FeatureScores)
+
+	frs, err := testDB.GetFeatureReadiness(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*internal.FeatureReadiness{
+		{Feature: internal.FeatureSymbolHistory, NumModules: 1},
+		{Feature: internal.FeatureAPIDiff},
+		{Feature: internal.FeatureScores},
+	}
+	if diff := cmp.Diff(want, frs, cmpopts.IgnoreFields(internal.FeatureReadiness{}, "ReadyAt")); diff != "" {
+		t.Errorf("GetFeatureReadiness mismatch (-want +got):\n%s", diff)
+	}
+	if frs[2].ReadyAt.IsZero() {
+		t.Error("scores: got zero ReadyAt, want the time of SetFeatureReady")
+	}
+
+	if err := testDB.ClearFeatureReady(ctx, internal.FeatureScores, ""); err != nil {
+		t.Fatal(err)
+	}
+	checkReady(oldPath, internal.FeatureSymbolHistory)
+	if err := testDB.ClearFeatureReady(ctx, internal.FeatureScores, ""); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("ClearFeatureReady again: got %v, want NotFound", err)
+	}
+}
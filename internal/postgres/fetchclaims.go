@@ -0,0 +1,72 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// ClaimFetch claims the fetch of the module version for ttl, so that other
+// workers skip it. If the module version is already claimed by a fetch whose
+// claim has not expired, it returns false.
+//
+// Otherwise it returns true and a function that releases the claim, which
+// must be called when the fetch finishes. The claim is released even if ctx
+// is done by then.
+func (db *DB) ClaimFetch(ctx context.Context, modulePath, version string, ttl time.Duration) (_ bool, release func(), err error) {
+	defer derrors.WrapStack(&err, "ClaimFetch(ctx, %q, %q, %s)", modulePath, version, ttl)
+
+	token, err := newSecret()
+	if err != nil {
+		return false, nil, err
+	}
+	var got string
+	err = db.db.QueryRow(ctx, `
+		INSERT INTO fetch_claims (module_path, version, token, expires_at)
+		VALUES ($1, $2, $3, CURRENT_TIMESTAMP + $4 * INTERVAL '1 second')
+		ON CONFLICT (module_path, version) DO UPDATE
+		SET token = excluded.token, expires_at = excluded.expires_at
+		WHERE fetch_claims.expires_at < CURRENT_TIMESTAMP
+		RETURNING token`,
+		modulePath, version, token, ttl.Seconds()).Scan(&got)
+	switch err {
+	case nil:
+	case sql.ErrNoRows:
+		// The conflicting claim has not expired.
+		return false, nil, nil
+	default:
+		return false, nil, err
+	}
+	release = func() {
+		ctx := context.WithoutCancel(ctx)
+		if _, err := db.db.Exec(ctx, `
+			DELETE FROM fetch_claims
+			WHERE module_path = $1 AND version = $2 AND token = $3`,
+			modulePath, version, token); err != nil {
+			// The claim will expire.
+			log.Errorf(ctx, "releasing fetch claim of %s@%s: %v", modulePath, version, err)
+		}
+	}
+	return true, release, nil
+}
+
+// IsFetchClaimed reports whether the fetch of the module version is claimed
+// by a fetch whose claim has not expired.
+func (db *DB) IsFetchClaimed(ctx context.Context, modulePath, version string) (_ bool, err error) {
+	defer derrors.WrapStack(&err, "IsFetchClaimed(ctx, %q, %q)", modulePath, version)
+
+	var claimed bool
+	err = db.db.QueryRow(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM fetch_claims
+			WHERE module_path = $1 AND version = $2 AND expires_at >= CURRENT_TIMESTAMP
+		)`, modulePath, version).Scan(&claimed)
+	return claimed, err
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClaimFetch(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	claim := func(version string, ttl time.Duration, want bool) func() {
+		t.Helper()
+		got, release, err := testDB.ClaimFetch(ctx, "example.com/m", version, ttl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("ClaimFetch(%q): got %t, want %t", version, got, want)
+		}
+		return release
+	}
+
+	isClaimed := func(version string, want bool) {
+		t.Helper()
+		got, err := testDB.IsFetchClaimed(ctx, "example.com/m", version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("IsFetchClaimed(%q): got %t, want %t", version, got, want)
+		}
+	}
+
+	isClaimed("v1.0.0", false)
+	release1 := claim("v1.0.0", time.Minute, true)
+	isClaimed("v1.0.0", true)
+	claim("v1.0.0", time.Minute, false)
+	// Other versions can be claimed.
+	claim("v1.1.0", time.Minute, true)()
+	release1()
+	release2 := claim("v1.0.0", time.Minute, true)
+
+	// A claim that expired can be taken over, and releasing it then doesn't
+	// release the new claim.
+	release2()
+	releaseExpired := claim("v1.0.0", -time.Minute, true)
+	isClaimed("v1.0.0", false)
+	release3 := claim("v1.0.0", time.Minute, true)
+	releaseExpired()
+	claim("v1.0.0", time.Minute, false)
+	release3()
+}
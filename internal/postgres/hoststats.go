@@ -0,0 +1,133 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// HostStats holds statistics about the modules served from a hosting domain,
+// such as github.com.
+type HostStats struct {
+	Host string `json:"host"`
+	// NumModules is the number of distinct module paths on the host.
+	NumModules int `json:"numModules"`
+	// NumVersions is the number of module versions that have been processed.
+	NumVersions int `json:"numVersions"`
+	// NumFailedVersions is the number of processed module versions whose
+	// last fetch failed with a 5xx status.
+	NumFailedVersions int `json:"numFailedVersions"`
+	// AvgDocSize is the average size in bytes of the encoded documentation
+	// of the host's packages.
+	AvgDocSize int       `json:"avgDocSize"`
+	ComputedAt time.Time `json:"computedAt"`
+}
+
+// FailureRate returns the fraction of processed versions that failed to fetch.
+func (hs *HostStats) FailureRate() float64 {
+	if hs.NumVersions == 0 {
+		return 0
+	}
+	return float64(hs.NumFailedVersions) / float64(hs.NumVersions)
+}
+
+// MarshalJSON encodes hs with its failure rate, as "failureRate".
+func (hs *HostStats) MarshalJSON() ([]byte, error) {
+	type stats HostStats // without this method
+	return json.Marshal(struct {
+		*stats
+		FailureRate float64 `json:"failureRate"`
+	}{(*stats)(hs), hs.FailureRate()})
+}
+
+// UpdateHostStats recomputes the statistics for every host, and returns the
+// number of hosts. Hosts that no longer have any modules are removed.
+func (db *DB) UpdateHostStats(ctx context.Context) (_ int, err error) {
+	defer derrors.WrapStack(&err, "UpdateHostStats(ctx)")
+
+	// The host of a module is the first element of its path. Statuses 500
+	// and 550-599 are fetch failures; the 52x and 54x statuses are modules
+	// waiting to be reprocessed.
+	query := `
+		WITH states AS (
+			SELECT
+				split_part(module_path, '/', 1) AS host,
+				count(DISTINCT module_path) AS num_modules,
+				count(*) AS num_versions,
+				count(*) FILTER (WHERE status = 500 OR (status >= 550 AND status < 600)) AS num_failed
+			FROM module_version_states
+			WHERE status != 0
+			GROUP BY 1
+		), docs AS (
+			SELECT
+				split_part(m.module_path, '/', 1) AS host,
+				avg(octet_length(d.source)) AS avg_doc_size
+			FROM documentation d
+			INNER JOIN units u ON u.id = d.unit_id
+			INNER JOIN modules m ON m.id = u.module_id
+			GROUP BY 1
+		)
+		INSERT INTO host_stats (host, num_modules, num_versions, num_failed_versions, avg_doc_size, computed_at)
+		SELECT s.host, s.num_modules, s.num_versions, s.num_failed, COALESCE(round(d.avg_doc_size), 0), CURRENT_TIMESTAMP
+		FROM states s
+		LEFT JOIN docs d ON d.host = s.host
+		ON CONFLICT (host) DO UPDATE SET
+			num_modules=excluded.num_modules,
+			num_versions=excluded.num_versions,
+			num_failed_versions=excluded.num_failed_versions,
+			avg_doc_size=excluded.avg_doc_size,
+			computed_at=excluded.computed_at`
+	var n int64
+	err = db.db.Transact(ctx, sql.LevelDefault, func(tx *database.DB) error {
+		var err error
+		n, err = tx.Exec(ctx, query)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			DELETE FROM host_stats
+			WHERE host NOT IN (
+				SELECT DISTINCT split_part(module_path, '/', 1)
+				FROM module_version_states
+				WHERE status != 0
+			)`)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(n), nil
+}
+
+// GetHostStats returns the statistics for the limit hosts with the most
+// modules, in decreasing order of module count.
+func (db *DB) GetHostStats(ctx context.Context, limit int) (_ []*HostStats, err error) {
+	defer derrors.WrapStack(&err, "GetHostStats(ctx, %d)", limit)
+
+	query := `
+		SELECT host, num_modules, num_versions, num_failed_versions, avg_doc_size, computed_at
+		FROM host_stats
+		ORDER BY num_modules DESC, host
+		LIMIT $1`
+	var stats []*HostStats
+	err = db.db.RunQuery(ctx, query, func(rows *sql.Rows) error {
+		var hs HostStats
+		if err := rows.Scan(&hs.Host, &hs.NumModules, &hs.NumVersions, &hs.NumFailedVersions, &hs.AvgDocSize, &hs.ComputedAt); err != nil {
+			return err
+		}
+		stats = append(stats, &hs)
+		return nil
+	}, limit)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestHostStats(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	for _, mvs := range []*ModuleVersionStateForUpdate{
+		{ModulePath: "github.com/a/b", Version: "v1.0.0", Status: 200},
+		{ModulePath: "github.com/a/b", Version: "v1.1.0", Status: 500},
+		{ModulePath: "github.com/c/d", Version: "v1.0.0", Status: 404},
+		{ModulePath: "github.com/e/f", Version: "v1.0.0", Status: 520},
+		{ModulePath: "example.org/g", Version: "v1.0.0", Status: 550},
+	} {
+		must(t, testDB.InsertIndexVersions(ctx, []*internal.IndexVersion{
+			{Path: mvs.ModulePath, Version: mvs.Version, Timestamp: sample.NowTruncated()},
+		}))
+		mvs.Timestamp = sample.NowTruncated()
+		must(t, testDB.UpdateModuleVersionState(ctx, mvs))
+	}
+	// Not yet processed, so not counted.
+	must(t, testDB.InsertIndexVersions(ctx, []*internal.IndexVersion{
+		{Path: "gitlab.com/h/i", Version: "v1.0.0", Timestamp: sample.NowTruncated()},
+	}))
+
+	n, err := testDB.UpdateHostStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("UpdateHostStats: got %d hosts, want 2", n)
+	}
+	got, err := testDB.GetHostStats(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*HostStats{
+		{Host: "github.com", NumModules: 3, NumVersions: 4, NumFailedVersions: 1},
+		{Host: "example.org", NumModules: 1, NumVersions: 1, NumFailedVersions: 1},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(HostStats{}, "ComputedAt")); diff != "" {
+		t.Errorf("GetHostStats mismatch (-want, +got):\n%s", diff)
+	}
+	if got, want := got[0].FailureRate(), 0.25; got != want {
+		t.Errorf("FailureRate: got %g, want %g", got, want)
+	}
+
+	got, err = testDB.GetHostStats(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Host != "github.com" {
+		t.Errorf("GetHostStats(ctx, 1): got %v, want github.com only", got)
+	}
+}
+
+func TestHostStatsJSON(t *testing.T) {
+	hs := &HostStats{Host: "github.com", NumModules: 3, NumVersions: 4, NumFailedVersions: 1}
+	b, err := json.Marshal(hs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["host"] != "github.com" || got["numFailedVersions"] != 1.0 || got["failureRate"] != 0.25 {
+		t.Errorf("got %s, want the host's statistics and a failureRate of 0.25", b)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// ImportedByUpdate describes a run of UpdateImportedByCountsIncrementally.
+type ImportedByUpdate struct {
+	// NumChanged is the number of packages whose importers may have changed.
+	NumChanged int
+	// NumUpdated is the number of packages whose imported_by_count changed.
+	NumUpdated int64
+	// NumPending is the number of changes that remain to be processed.
+	NumPending int
+}
+
+// UpdateImportedByCountsIncrementally updates imported_by_count and
+// imported_by_count_updated_at for the packages whose importers may have
+// changed since the last run.
+//
+// A trigger on imports_unique records the imported package of every import
+// that is added or removed in the imported_by_changes table.
+// UpdateImportedByCountsIncrementally removes at most limit of those records,
+// batchSize at a time, and recomputes the counts of the packages they name.
+// Unlike UpdateSearchDocumentsImportedByCount, it only reads the importers of
+// those packages.
+func (db *DB) UpdateImportedByCountsIncrementally(ctx context.Context, batchSize, limit int) (_ *ImportedByUpdate, err error) {
+	defer derrors.WrapStack(&err, "UpdateImportedByCountsIncrementally(ctx, %d, %d)", batchSize, limit)
+	defer internal.RequestState(ctx, "updating imported-by counts incrementally")()
+
+	update := &ImportedByUpdate{}
+	for processed := 0; processed < limit; {
+		n := min(batchSize, limit-processed)
+		var (
+			nChanges, nChanged int
+			nUpdated           int64
+		)
+		// Removing the changes and updating the counts in one transaction
+		// means that a failure leaves the changes to be retried. A change
+		// that is recorded while the transaction runs is not removed, so the
+		// next run will see it.
+		err := db.db.Transact(ctx, sql.LevelDefault, func(tx *database.DB) error {
+			paths, err := database.Collect1[string](ctx, tx, `
+				DELETE FROM imported_by_changes
+				WHERE id IN (SELECT id FROM imported_by_changes ORDER BY id LIMIT $1)
+				RETURNING package_path`, n)
+			if err != nil {
+				return err
+			}
+			nChanges = len(paths)
+			slices.Sort(paths)
+			paths = slices.Compact(paths)
+			nChanged = len(paths)
+			counts, err := importedByCountsChangedFor(ctx, tx, paths)
+			if err != nil {
+				return err
+			}
+			if len(counts) == 0 {
+				return nil
+			}
+			if err := insertImportedByCounts(ctx, tx, counts, len(counts)); err != nil {
+				return err
+			}
+			nUpdated, err = updateImportedByCounts(ctx, tx)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if nChanges == 0 {
+			break
+		}
+		processed += nChanges
+		update.NumChanged += nChanged
+		update.NumUpdated += nUpdated
+		internal.RequestState(ctx, fmt.Sprintf("updating imported-by counts: %d/%d changes", processed, limit))
+	}
+	if err := db.db.QueryRow(ctx, `SELECT COUNT(*) FROM imported_by_changes`).Scan(&update.NumPending); err != nil {
+		return nil, err
+	}
+	log.Infof(ctx, "update-imported-by-counts-incremental: %d packages changed, %d counts updated, %d changes pending",
+		update.NumChanged, update.NumUpdated, update.NumPending)
+	return update, nil
+}
+
+// importedByCountsChangedFor recomputes the imported-by counts of the given
+// packages, and returns the ones that differ from the counts in
+// search_documents. Packages that are not in search_documents are omitted.
+func importedByCountsChangedFor(ctx context.Context, tx *database.DB, pkgPaths []string) (_ map[string]int, err error) {
+	defer derrors.WrapStack(&err, "importedByCountsChangedFor(ctx, tx, %d paths)", len(pkgPaths))
+
+	if len(pkgPaths) == 0 {
+		return nil, nil
+	}
+	curCounts := map[string]int{}
+	err = tx.RunQuery(ctx, `
+		SELECT package_path, imported_by_count
+		FROM search_documents
+		WHERE package_path = ANY($1)
+	`, func(rows *sql.Rows) error {
+		var (
+			p string
+			c int
+		)
+		if err := rows.Scan(&p, &c); err != nil {
+			return err
+		}
+		curCounts[p] = c
+		return nil
+	}, pq.Array(pkgPaths))
+	if err != nil {
+		return nil, err
+	}
+
+	// As in computeImportedByCounts, only importers that are in
+	// search_documents count.
+	newCounts := map[string]int{}
+	err = tx.RunQuery(ctx, `
+		SELECT DISTINCT iu.from_path, iu.from_module_path, iu.to_path
+		FROM imports_unique iu
+		WHERE iu.to_path = ANY($1)
+		AND EXISTS (SELECT 1 FROM search_documents sd WHERE sd.package_path = iu.from_path)
+	`, func(rows *sql.Rows) error {
+		var from, fromMod, to string
+		if err := rows.Scan(&from, &fromMod, &to); err != nil {
+			return err
+		}
+		if isExternalImport(fromMod, to) {
+			newCounts[to]++
+		}
+		return nil
+	}, pq.Array(pkgPaths))
+	if err != nil {
+		return nil, err
+	}
+
+	changed := map[string]int{}
+	for p, cc := range curCounts {
+		if nc := newCounts[p]; nc != cc {
+			changed[p] = nc
+		}
+	}
+	return changed, nil
+}
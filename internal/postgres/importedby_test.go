@@ -0,0 +1,97 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestUpdateImportedByCountsIncrementally(t *testing.T) {
+	ctx := context.Background()
+	testDB, release := acquire(t)
+	defer release()
+
+	insertPackageVersion := func(suffix, version string, imports ...string) *internal.Module {
+		t.Helper()
+		m := sample.Module("mod.com/"+suffix, version, suffix)
+		pkg := m.Units[1]
+		pkg.Imports = nil
+		for _, imp := range imports {
+			pkg.Imports = append(pkg.Imports, fmt.Sprintf("mod.com/%s/%[1]s", imp))
+		}
+		MustInsertModule(ctx, t, testDB, m)
+		return m
+	}
+	update := func(batchSize, limit int) *ImportedByUpdate {
+		t.Helper()
+		u, err := testDB.UpdateImportedByCountsIncrementally(ctx, batchSize, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u
+	}
+	checkCount := func(suffix string, want int) {
+		t.Helper()
+		path := fmt.Sprintf("mod.com/%s/%[1]s", suffix)
+		sd, err := getSearchDocument(ctx, testDB, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sd.importedByCount != want {
+			t.Errorf("importedByCount for %q = %d, want %d", path, sd.importedByCount, want)
+		}
+	}
+
+	insertPackageVersion("A", "v1.0.0")
+	insertPackageVersion("B", "v1.0.0", "A")
+	insertPackageVersion("C", "v1.0.0", "A", "B")
+	got := update(100, 1000)
+	if want := (ImportedByUpdate{NumChanged: 2, NumUpdated: 2, NumPending: 0}); *got != want {
+		t.Errorf("got %+v, want %+v", *got, want)
+	}
+	checkCount("A", 2)
+	checkCount("B", 1)
+	checkCount("C", 0)
+
+	// Nothing has changed since the last run.
+	if got := update(100, 1000); got.NumChanged != 0 {
+		t.Errorf("got %+v, want no changes", *got)
+	}
+
+	// A new version of C no longer imports A. Inserting it removes C's old
+	// imports and adds its new ones, so there are three changes.
+	insertPackageVersion("C", "v1.1.0", "B")
+	got = update(1, 2)
+	if want := (ImportedByUpdate{NumChanged: 2, NumUpdated: 1, NumPending: 1}); *got != want {
+		t.Errorf("got %+v, want %+v", *got, want)
+	}
+	update(1, 1)
+	checkCount("A", 1)
+	checkCount("B", 1)
+
+	// The incremental counts agree with a full recomputation.
+	n, err := testDB.UpdateSearchDocumentsImportedByCount(ctx, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("full recomputation updated %d counts, want 0", n)
+	}
+
+	// A full recomputation removes the changes it counted.
+	insertPackageVersion("D", "v1.0.0", "A")
+	if _, err := testDB.UpdateSearchDocumentsImportedByCount(ctx, 100); err != nil {
+		t.Fatal(err)
+	}
+	checkCount("A", 2)
+	if got := update(100, 1000); got.NumChanged != 0 || got.NumPending != 0 {
+		t.Errorf("after full recomputation: got %+v, want no changes", *got)
+	}
+}
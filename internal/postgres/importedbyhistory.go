@@ -0,0 +1,91 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware/stats"
+)
+
+// ImportedByHistoryWeeks is the number of weekly snapshots of imported-by
+// counts that are kept.
+const ImportedByHistoryWeeks = 52
+
+// RecordImportedByHistory records the imported_by_count of every package in
+// search_documents for the week that contains t, replacing any earlier
+// snapshot for that week, and deletes the snapshots older than
+// ImportedByHistoryWeeks. Packages that are not imported are recorded only if
+// they were imported at the previous snapshot, so that a drop to zero shows
+// up. It returns the number of counts recorded.
+func (db *DB) RecordImportedByHistory(ctx context.Context, t time.Time) (_ int64, err error) {
+	defer derrors.WrapStack(&err, "RecordImportedByHistory(ctx, %s)", t.Format(time.DateOnly))
+
+	var n int64
+	err = db.db.Transact(ctx, sql.LevelDefault, func(tx *database.DB) error {
+		var err error
+		n, err = tx.Exec(ctx, `
+			WITH this AS (
+				SELECT date_trunc('week', $1::date)::date AS week
+			), previous AS (
+				SELECT h.package_path
+				FROM imported_by_history h
+				WHERE h.imported_by_count > 0
+					AND h.week = (
+						SELECT max(week) FROM imported_by_history
+						WHERE week < (SELECT week FROM this))
+			)
+			INSERT INTO imported_by_history (package_path, week, imported_by_count)
+			SELECT sd.package_path, (SELECT week FROM this), sd.imported_by_count
+			FROM search_documents sd
+			WHERE sd.imported_by_count > 0
+				OR sd.package_path IN (SELECT package_path FROM previous)
+			ON CONFLICT (package_path, week) DO UPDATE
+			SET imported_by_count = excluded.imported_by_count`, t)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			DELETE FROM imported_by_history
+			WHERE week <= date_trunc('week', $1::date)::date - $2::int * 7`,
+			t, ImportedByHistoryWeeks)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// GetImportedByHistory returns the recorded imported-by counts of the
+// package pkgPath, oldest first.
+func (db *DB) GetImportedByHistory(ctx context.Context, pkgPath string) (_ []*internal.ImportedByCountAt, err error) {
+	defer derrors.WrapStack(&err, "GetImportedByHistory(ctx, %q)", pkgPath)
+	defer stats.Elapsed(ctx, "GetImportedByHistory")()
+
+	var history []*internal.ImportedByCountAt
+	collect := func(rows *sql.Rows) error {
+		var c internal.ImportedByCountAt
+		if err := rows.Scan(&c.Week, &c.Count); err != nil {
+			return err
+		}
+		history = append(history, &c)
+		return nil
+	}
+	err = db.db.RunQuery(ctx, `
+		SELECT week, imported_by_count
+		FROM imported_by_history
+		WHERE package_path = $1
+		ORDER BY week`, collect, pkgPath)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestImportedByHistory(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	for _, m := range []string{"a.com/a", "b.com/b"} {
+		MustInsertModule(ctx, t, testDB, sample.Module(m, sample.VersionString, ""))
+	}
+	setCount := func(path string, n int) {
+		t.Helper()
+		if _, err := testDB.db.Exec(ctx, `UPDATE search_documents SET imported_by_count = $1 WHERE package_path = $2`, n, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+	record := func(day time.Time, want int64) {
+		t.Helper()
+		got, err := testDB.RecordImportedByHistory(ctx, day)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("RecordImportedByHistory(%s) = %d, want %d", day.Format(time.DateOnly), got, want)
+		}
+	}
+	check := func(path string, want []*internal.ImportedByCountAt) {
+		t.Helper()
+		got, err := testDB.GetImportedByHistory(ctx, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("GetImportedByHistory(%q) mismatch (-want, +got):\n%s", path, diff)
+		}
+	}
+
+	// 2026-03-02 is a Monday.
+	week1 := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	week2 := week1.AddDate(0, 0, 7)
+	setCount("a.com/a", 3)
+	record(week1.AddDate(0, 0, 2), 1)
+	// A second snapshot in the same week replaces the first.
+	setCount("a.com/a", 4)
+	setCount("b.com/b", 1)
+	record(week1.AddDate(0, 0, 6), 2)
+	// A count that drops to zero is recorded once.
+	setCount("b.com/b", 0)
+	record(week2, 2)
+	record(week2.AddDate(0, 0, 7), 1)
+
+	check("a.com/a", []*internal.ImportedByCountAt{
+		{Week: week1, Count: 4},
+		{Week: week2, Count: 4},
+		{Week: week2.AddDate(0, 0, 7), Count: 4},
+	})
+	check("b.com/b", []*internal.ImportedByCountAt{
+		{Week: week1, Count: 1},
+		{Week: week2, Count: 0},
+	})
+	check("c.com/c", nil)
+
+	// Old snapshots are deleted.
+	record(week1.AddDate(0, 0, 7*ImportedByHistoryWeeks), 1)
+	got, err := testDB.GetImportedByHistory(ctx, "a.com/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || !got[0].Week.Equal(week2) {
+		t.Errorf("after %d weeks, got %v, want 3 snapshots starting %s", ImportedByHistoryWeeks, got, week2.Format(time.DateOnly))
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/database"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/godoc"
 	"golang.org/x/pkgsite/internal/licenses"
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/stdlib"
@@ -106,6 +107,12 @@ func (db *DB) saveModule(ctx context.Context, m *internal.Module, lmv *internal.
 		if err := insertLicenses(ctx, tx, m, moduleID); err != nil {
 			return err
 		}
+		if err := insertModuleRequirements(ctx, tx, moduleID, m.Requirements); err != nil {
+			return err
+		}
+		if err := insertReleaseNoteSections(ctx, tx, m.ReleaseNoteSections); err != nil {
+			return err
+		}
 		pathToUnitID, pathToDocs, err := db.insertUnits(ctx, tx, m, moduleID, pathToID)
 		if err != nil {
 			return err
@@ -139,6 +146,9 @@ func (db *DB) saveModule(ctx context.Context, m *internal.Module, lmv *internal.
 		if err := insertSymbols(ctx, tx, m.ModulePath, m.Version, isLatest, pathToID, pathToUnitID, pathToDocs); err != nil {
 			return err
 		}
+		if err := setNewModuleFeaturesReady(ctx, tx, m.ModulePath, m.Version); err != nil {
+			return err
+		}
 		if !isLatest {
 			return nil
 		}
@@ -185,6 +195,15 @@ func (db *DB) saveModule(ctx context.Context, m *internal.Module, lmv *internal.
 			log.Infof(ctx, "%s@%s: not inserting into search documents", m.ModulePath, m.Version)
 			return nil
 		}
+		// Keep a module that is taken down out of search.
+		takenDown, err := isTakenDown(ctx, tx, m.ModulePath, m.Version)
+		if err != nil {
+			return err
+		}
+		if takenDown {
+			log.Infof(ctx, "%s@%s: taken down; not inserting into search documents", m.ModulePath, m.Version)
+			return nil
+		}
 		// Insert the module's packages into search_documents.
 		if err := upsertSearchDocuments(ctx, tx, m); err != nil {
 			return err
@@ -235,6 +254,22 @@ func insertModule(ctx context.Context, db *database.DB, m *internal.Module) (_ i
 	if err != nil {
 		return 0, err
 	}
+	authorMetadataJSON, err := json.Marshal(m.AuthorMetadata)
+	if err != nil {
+		return 0, err
+	}
+	checksumJSON, err := json.Marshal(m.Checksum)
+	if err != nil {
+		return 0, err
+	}
+	contentWarningsJSON, err := json.Marshal(m.ContentWarnings)
+	if err != nil {
+		return 0, err
+	}
+	commitJSON, err := json.Marshal(m.Commit)
+	if err != nil {
+		return 0, err
+	}
 	versionType, err := version.ParseType(m.Version)
 	if err != nil {
 		return 0, err
@@ -251,13 +286,23 @@ func insertModule(ctx context.Context, db *database.DB, m *internal.Module) (_ i
 			source_info,
 			redistributable,
 			has_go_mod,
-			incompatible)
-		VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+			incompatible,
+			go_version,
+			author_metadata,
+			checksum,
+			content_warnings,
+			commit)
+		VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15)
 		ON CONFLICT
 			(module_path, version)
 		DO UPDATE SET
 			source_info=excluded.source_info,
-			redistributable=excluded.redistributable
+			redistributable=excluded.redistributable,
+			go_version=excluded.go_version,
+			author_metadata=excluded.author_metadata,
+			checksum=excluded.checksum,
+			content_warnings=excluded.content_warnings,
+			commit=excluded.commit
 		RETURNING id`,
 		m.ModulePath,
 		m.Version,
@@ -269,6 +314,11 @@ func insertModule(ctx context.Context, db *database.DB, m *internal.Module) (_ i
 		m.IsRedistributable,
 		m.HasGoMod,
 		version.IsIncompatible(m.Version),
+		m.GoVersion,
+		authorMetadataJSON,
+		checksumJSON,
+		contentWarningsJSON,
+		commitJSON,
 	).Scan(&moduleID)
 	if err != nil {
 		return 0, err
@@ -353,14 +403,18 @@ func (pdb *DB) insertUnits(ctx context.Context, tx *database.DB,
 	})
 	for _, u := range m.Units {
 		sort.Strings(u.Imports)
+		sort.Strings(u.TestImports)
 	}
 	var (
-		paths         []string
-		unitValues    []any
-		pathToReadme  = map[string]*internal.Readme{}
-		pathToImports = map[string][]string{}
-		pathIDToPath  = map[int]string{}
-		pathToAllDocs = map[string][]*internal.Documentation{}
+		paths             []string
+		unitValues        []any
+		pathToReadme      = map[string]*internal.Readme{}
+		pathToLocalized   = map[string][]*internal.Readme{}
+		pathToDocsPages   = map[string][]*internal.Readme{}
+		pathToImports     = map[string][]string{}
+		pathToTestImports = map[string][]string{}
+		pathIDToPath      = map[int]string{}
+		pathToAllDocs     = map[string][]*internal.Documentation{}
 	)
 	pathToPkgDocs = map[string][]*internal.Documentation{}
 	for _, u := range m.Units {
@@ -398,6 +452,12 @@ func (pdb *DB) insertUnits(ctx context.Context, tx *database.DB,
 		if u.Readme != nil {
 			pathToReadme[u.Path] = u.Readme
 		}
+		if len(u.LocalizedReadmes) > 0 {
+			pathToLocalized[u.Path] = u.LocalizedReadmes
+		}
+		if len(u.DocsPages) > 0 {
+			pathToDocsPages[u.Path] = u.DocsPages
+		}
 		for _, d := range u.Documentation {
 			if d.Source == nil {
 				return nil, nil, fmt.Errorf("insertUnits: unit %q missing source files for %q, %q", u.Path, d.GOOS, d.GOARCH)
@@ -412,6 +472,9 @@ func (pdb *DB) insertUnits(ctx context.Context, tx *database.DB,
 		if len(u.Imports) > 0 {
 			pathToImports[u.Path] = u.Imports
 		}
+		if len(u.TestImports) > 0 {
+			pathToTestImports[u.Path] = u.TestImports
+		}
 		paths = append(paths, u.Path)
 	}
 	pathIDToUnitID, err := insertUnits(ctx, tx, unitValues)
@@ -425,10 +488,19 @@ func (pdb *DB) insertUnits(ctx context.Context, tx *database.DB,
 	if err := insertReadmes(ctx, tx, paths, pathToUnitID, pathToReadme); err != nil {
 		return nil, nil, err
 	}
+	if err := insertLocalizedReadmes(ctx, tx, paths, pathToUnitID, pathToLocalized); err != nil {
+		return nil, nil, err
+	}
+	if err := insertDocsPages(ctx, tx, paths, pathToUnitID, pathToDocsPages); err != nil {
+		return nil, nil, err
+	}
 	if err := insertDocs(ctx, tx, paths, pathToUnitID, pathToAllDocs); err != nil {
 		return nil, nil, err
 	}
-	if err := insertImports(ctx, tx, paths, pathToUnitID, pathToImports); err != nil {
+	if err := insertImports(ctx, tx, "imports", paths, pathToUnitID, pathToImports); err != nil {
+		return nil, nil, err
+	}
+	if err := insertImports(ctx, tx, "test_imports", paths, pathToUnitID, pathToTestImports); err != nil {
 		return nil, nil, err
 	}
 	return pathToUnitID, pathToPkgDocs, nil
@@ -513,7 +585,20 @@ func insertDocs(ctx context.Context, db *database.DB,
 					if doc.GOOS == "" || doc.GOARCH == "" {
 						ch <- database.RowItem{Err: errors.New("empty GOOS or GOARCH")}
 					}
-					ch <- database.RowItem{Values: []any{unitID, doc.GOOS, doc.GOARCH, doc.Synopsis, doc.Source}}
+					skipped, err := listJSON(doc.SkippedExamples)
+					if err != nil {
+						ch <- database.RowItem{Err: err}
+					}
+					broken, err := listJSON(doc.BrokenDocLinks)
+					if err != nil {
+						ch <- database.RowItem{Err: err}
+					}
+					excluded, err := listJSON(doc.ExcludedFiles)
+					if err != nil {
+						ch <- database.RowItem{Err: err}
+					}
+					ch <- database.RowItem{Values: []any{unitID, doc.GOOS, doc.GOARCH, doc.Synopsis, doc.Source, sourceHash(doc),
+						nullIfEmpty(doc.AppVersion), nullIfEmpty(doc.Toolchain), skipped, broken, excluded}}
 				}
 			}
 			close(ch)
@@ -522,11 +607,44 @@ func insertDocs(ctx context.Context, db *database.DB,
 	}
 
 	uniqueCols := []string{"unit_id", "goos", "goarch"}
-	docCols := append(uniqueCols, "synopsis", "source")
+	docCols := append(uniqueCols, "synopsis", "source", "source_hash", "app_version", "toolchain", "skipped_examples", "broken_doc_links", "excluded_files")
 	return db.CopyUpsert(ctx, "documentation",
 		docCols, database.CopyFromChan(generateRows()), uniqueCols, "id")
 }
 
+// sourceHash returns the hash of doc's source, computing it if it was not
+// set when the documentation was fetched.
+func sourceHash(doc *internal.Documentation) any {
+	if doc.Source == nil {
+		return nil
+	}
+	if doc.SourceHash != "" {
+		return doc.SourceHash
+	}
+	return godoc.SourceHash(doc.Source)
+}
+
+// listJSON returns the JSON encoding of xs, such as the skipped examples of a
+// documentation, or nil if it is empty, so that it is stored as NULL.
+func listJSON[T any](xs []T) (any, error) {
+	if len(xs) == 0 {
+		return nil, nil
+	}
+	b, err := json.Marshal(xs)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// nullIfEmpty returns nil if s is empty, so that it is stored as NULL.
+func nullIfEmpty(s string) any {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
 // getDocIDsForPath returns a map of the unit path to documentation.id to
 // documentation, for all of the docs in pathToDocs. This will be used to
 // insert data into the documentation_symbols.documentation_id column.
@@ -570,11 +688,14 @@ func getDocIDsForPath(ctx context.Context, db *database.DB,
 	return pathToDocIDToDoc, nil
 }
 
+// insertImports inserts the imports in pathToImports into table, which is
+// either imports or test_imports.
 func insertImports(ctx context.Context, tx *database.DB,
+	table string,
 	paths []string,
 	pathToUnitID map[string]int,
 	pathToImports map[string][]string) (err error) {
-	defer derrors.WrapStack(&err, "insertImports")
+	defer derrors.WrapStack(&err, "insertImports(%q)", table)
 
 	importPathSet := map[string]bool{}
 	for _, pkgPath := range paths {
@@ -603,7 +724,7 @@ func insertImports(ctx context.Context, tx *database.DB,
 		}
 	}
 	importCols := []string{"unit_id", "to_path_id"}
-	return tx.BulkUpsert(ctx, "imports", importCols, importValues, importCols)
+	return tx.BulkUpsert(ctx, table, importCols, importValues, importCols)
 }
 
 func insertReadmes(ctx context.Context, db *database.DB,
@@ -632,6 +753,102 @@ func insertReadmes(ctx context.Context, db *database.DB,
 	return db.BulkUpsert(ctx, "readmes", readmeCols, readmeValues, []string{"unit_id"})
 }
 
+// insertLocalizedReadmes replaces the localized READMEs of the units with
+// those in pathToLocalized.
+func insertLocalizedReadmes(ctx context.Context, db *database.DB,
+	paths []string,
+	pathToUnitID map[string]int,
+	pathToLocalized map[string][]*internal.Readme) (err error) {
+	defer derrors.WrapStack(&err, "insertLocalizedReadmes")
+
+	var (
+		unitIDs []int
+		values  []any
+	)
+	for _, path := range paths {
+		unitID := pathToUnitID[path]
+		unitIDs = append(unitIDs, unitID)
+		for _, r := range pathToLocalized[path] {
+			contents := makeValidUnicode(r.Contents)
+			if len(contents) == 0 {
+				continue
+			}
+			values = append(values, unitID, r.Lang, r.Filepath, contents)
+		}
+	}
+	// Remove the READMEs of a previous insertion of the module that are no
+	// longer there.
+	if _, err := db.Exec(ctx, `DELETE FROM localized_readmes WHERE unit_id = ANY($1)`, pq.Array(unitIDs)); err != nil {
+		return err
+	}
+	cols := []string{"unit_id", "lang", "file_path", "contents"}
+	return db.BulkInsert(ctx, "localized_readmes", cols, values, "")
+}
+
+// insertDocsPages replaces the docs pages of the units with those in
+// pathToDocsPages.
+func insertDocsPages(ctx context.Context, db *database.DB,
+	paths []string,
+	pathToUnitID map[string]int,
+	pathToDocsPages map[string][]*internal.Readme) (err error) {
+	defer derrors.WrapStack(&err, "insertDocsPages")
+
+	var (
+		unitIDs []int
+		values  []any
+	)
+	for _, path := range paths {
+		unitID := pathToUnitID[path]
+		unitIDs = append(unitIDs, unitID)
+		for _, r := range pathToDocsPages[path] {
+			contents := makeValidUnicode(r.Contents)
+			if len(contents) == 0 {
+				continue
+			}
+			values = append(values, unitID, r.Filepath, contents)
+		}
+	}
+	// Remove the pages of a previous insertion of the module that are no
+	// longer there.
+	if _, err := db.Exec(ctx, `DELETE FROM docs_pages WHERE unit_id = ANY($1)`, pq.Array(unitIDs)); err != nil {
+		return err
+	}
+	cols := []string{"unit_id", "file_path", "contents"}
+	return db.BulkInsert(ctx, "docs_pages", cols, values, "")
+}
+
+// insertModuleRequirements replaces the go.mod requirements of the module
+// with the given ID by reqs.
+func insertModuleRequirements(ctx context.Context, db *database.DB, moduleID int, reqs []*internal.ModuleRequirement) (err error) {
+	defer derrors.WrapStack(&err, "insertModuleRequirements(ctx, %d)", moduleID)
+
+	// A go.mod file can require a module more than once, in which case the
+	// go command uses the highest version.
+	var (
+		paths  []string
+		byPath = map[string]*internal.ModuleRequirement{}
+	)
+	for _, r := range reqs {
+		prev, ok := byPath[r.ModulePath]
+		if !ok {
+			paths = append(paths, r.ModulePath)
+		}
+		if !ok || semver.Compare(r.Version, prev.Version) > 0 {
+			byPath[r.ModulePath] = r
+		}
+	}
+	var values []any
+	for _, p := range paths {
+		r := byPath[p]
+		values = append(values, moduleID, r.ModulePath, r.Version, r.Indirect)
+	}
+	if _, err := db.Exec(ctx, `DELETE FROM module_requirements WHERE module_id = $1`, moduleID); err != nil {
+		return err
+	}
+	cols := []string{"module_id", "module_path", "version", "indirect"}
+	return db.BulkInsert(ctx, "module_requirements", cols, values, "")
+}
+
 // ReconcileSearch reconciles the search data for modulePath. If the module is
 // alternative or has no good versions, it removes search data. Otherwise, if
 // the latest good version doesn't match the version in search_documents,
@@ -681,6 +898,14 @@ func (db *DB) ReconcileSearch(ctx context.Context, modulePath, version string, s
 			log.Debugf(ctx, "ReconcileSearch(%q): alternative or no good version; removed from search_documents and imports_unique", modulePath)
 			return nil
 		}
+		takenDown, err := isTakenDown(ctx, tx, modulePath, lmv.GoodVersion)
+		if err != nil {
+			return err
+		}
+		if takenDown {
+			log.Debugf(ctx, "ReconcileSearch(%q): good version %s is taken down; doing nothing", modulePath, lmv.GoodVersion)
+			return nil
+		}
 		// Is the latest good version in search_documents, or is there a longer module path?
 		var x int
 		switch err := tx.QueryRow(ctx, `
@@ -159,18 +159,22 @@ func TestInsertModuleLicenseCheck(t *testing.T) {
 			} else {
 				db = testDB
 			}
-			checkHasRedistData := func(readme string, doc []byte, want bool) {
+			checkHasRedistData := func(readme string, localized []*internal.Readme, doc []byte, want bool) {
 				t.Helper()
 				if got := readme != ""; got != want {
 					t.Errorf("readme: got %t, want %t", got, want)
 				}
+				if got := len(localized) > 0; got != want {
+					t.Errorf("localized readmes: got %t, want %t", got, want)
+				}
 				if got := doc != nil; got != want {
 					t.Errorf("doc: got %t, want %t", got, want)
 				}
 			}
 
 			mod := sample.Module(sample.ModulePath, sample.VersionString, "")
-			checkHasRedistData(mod.Units[0].Readme.Contents, mod.Units[0].Documentation[0].Source, true)
+			mod.Units[0].LocalizedReadmes = []*internal.Readme{{Filepath: "README.fr.md", Contents: "bonjour", Lang: "fr"}}
+			checkHasRedistData(mod.Units[0].Readme.Contents, mod.Units[0].LocalizedReadmes, mod.Units[0].Documentation[0].Source, true)
 			mod.IsRedistributable = false
 			mod.Units[0].IsRedistributable = false
 
@@ -191,7 +195,7 @@ func TestInsertModuleLicenseCheck(t *testing.T) {
 			if u.Documentation != nil {
 				source = u.Documentation[0].Source
 			}
-			checkHasRedistData(readme, source, bypass)
+			checkHasRedistData(readme, u.LocalizedReadmes, source, bypass)
 		})
 	}
 }
@@ -359,6 +363,203 @@ func TestPostgres_ReadAndWriteModuleOtherColumns(t *testing.T) {
 	}
 }
 
+func TestInsertModuleAuthorMetadata(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.DefaultModule()
+	m.AuthorMetadata = &internal.AuthorMetadata{
+		Links: []*internal.AuthorLink{{Title: "Chat", URL: "https://example.com/chat"}},
+		Badges: []*internal.AuthorBadge{
+			{Title: "Build", ImageURL: "https://example.com/badge.svg", URL: "https://example.com/ci"},
+		},
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	um, err := testDB.GetUnitMeta(ctx, m.ModulePath, m.ModulePath, m.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(m.AuthorMetadata, um.AuthorMetadata); diff != "" {
+		t.Errorf("GetUnitMeta: mismatch (-want, +got):\n%s", diff)
+	}
+	mi, err := testDB.GetModuleInfo(ctx, m.ModulePath, m.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(m.AuthorMetadata, mi.AuthorMetadata); diff != "" {
+		t.Errorf("GetModuleInfo: mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestInsertModuleChecksum(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.DefaultModule()
+	m.Checksum = &internal.ModuleChecksum{
+		OriginHash: "abc123",
+		ZipHash:    "h1:zip=",
+		GoModHash:  "h1:mod=",
+		Status:     internal.ChecksumVerified,
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	um, err := testDB.GetUnitMeta(ctx, m.ModulePath, m.ModulePath, m.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(m.Checksum, um.Checksum); diff != "" {
+		t.Errorf("GetUnitMeta: mismatch (-want, +got):\n%s", diff)
+	}
+	mi, err := testDB.GetModuleInfo(ctx, m.ModulePath, m.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(m.Checksum, mi.Checksum); diff != "" {
+		t.Errorf("GetModuleInfo: mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestInsertModuleContentWarnings(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.DefaultModule()
+	m.ContentWarnings = []*internal.ContentWarning{
+		{File: "testdata/tool", Rule: internal.ContentRuleExecutable},
+		{File: "p.go", Rule: "aws-access-key", Line: 3},
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	mi, err := testDB.GetModuleInfo(ctx, m.ModulePath, m.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(m.ContentWarnings, mi.ContentWarnings); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestInsertModuleCommit(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.Module(sample.ModulePath, "v0.0.0-20240601100000-abcdef123456", sample.Suffix)
+	m.Commit = &source.CommitInfo{
+		Hash:       "abcdef1234567890abcdef1234567890abcdef12",
+		Subject:    "Fix race in pool",
+		AuthorTime: time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC),
+		URL:        "https://github.com/owner/repo/commit/abcdef1234567890abcdef1234567890abcdef12",
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	um, err := testDB.GetUnitMeta(ctx, m.ModulePath, m.ModulePath, m.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(m.Commit, um.Commit); diff != "" {
+		t.Errorf("GetUnitMeta: mismatch (-want, +got):\n%s", diff)
+	}
+	versions, err := testDB.GetVersionsForPath(ctx, m.ModulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 {
+		t.Fatalf("got %d versions, want 1", len(versions))
+	}
+	if diff := cmp.Diff(m.Commit, versions[0].Commit); diff != "" {
+		t.Errorf("GetVersionsForPath: mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestInsertModuleLocalizedReadmes(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.DefaultModule()
+	want := []*internal.Readme{
+		{Filepath: "README.ja.md", Contents: "こんにちは", Lang: "ja"},
+		{Filepath: "README.zh-CN.md", Contents: "你好", Lang: "zh-CN"},
+	}
+	m.Units[0].LocalizedReadmes = want
+	MustInsertModule(ctx, t, testDB, m)
+
+	u, err := testDB.GetUnit(ctx, newUnitMeta(m.ModulePath, m.ModulePath, m.Version), internal.WithMain, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, u.LocalizedReadmes); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	// Reinserting the module without them removes them.
+	m.Units[0].LocalizedReadmes = nil
+	MustInsertModule(ctx, t, testDB, m)
+	u, err = testDB.GetUnit(ctx, newUnitMeta(m.ModulePath, m.ModulePath, m.Version), internal.WithMain, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(u.LocalizedReadmes) != 0 {
+		t.Errorf("got %d localized READMEs after reinsert, want 0", len(u.LocalizedReadmes))
+	}
+}
+
+func TestInsertModuleDocsPages(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.DefaultModule()
+	pages := []*internal.Readme{
+		{Filepath: "docs/guide.md", Contents: "# Guide"},
+		{Filepath: "docs/index.md", Contents: "# Index"},
+	}
+	m.Units[0].DocsPages = pages
+	MustInsertModule(ctx, t, testDB, m)
+	um := newUnitMeta(m.ModulePath, m.ModulePath, m.Version)
+
+	// WithMain reads only the file paths.
+	u, err := testDB.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*internal.Readme{{Filepath: "docs/guide.md"}, {Filepath: "docs/index.md"}}
+	if diff := cmp.Diff(want, u.DocsPages); diff != "" {
+		t.Errorf("WithMain: mismatch (-want, +got):\n%s", diff)
+	}
+
+	u, err = testDB.GetUnit(ctx, um, internal.WithDocsPages, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(pages, u.DocsPages); diff != "" {
+		t.Errorf("WithDocsPages: mismatch (-want, +got):\n%s", diff)
+	}
+
+	// Reinserting the module without them removes them.
+	m.Units[0].DocsPages = nil
+	MustInsertModule(ctx, t, testDB, m)
+	u, err = testDB.GetUnit(ctx, um, internal.WithDocsPages, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(u.DocsPages) != 0 {
+		t.Errorf("got %d docs pages after reinsert, want 0", len(u.DocsPages))
+	}
+}
+
 func TestInsertModuleLatest(t *testing.T) {
 	// Check the first return value of InsertModule, which is whether the
 	// inserted module is the latest good version. Also check that
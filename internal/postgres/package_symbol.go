@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	"github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/database"
 	"golang.org/x/pkgsite/internal/derrors"
@@ -97,3 +98,42 @@ func collectSymbolHistory(check func(sh *internal.SymbolHistory, sm internal.Sym
 		return nil
 	}
 }
+
+// GetSymbolsAtVersions returns the symbols of the package at packagePath in the
+// module at modulePath, at each of the given versions of the module. Versions
+// at which the package does not exist are omitted from the result.
+func (db *DB) GetSymbolsAtVersions(ctx context.Context, packagePath, modulePath string, versions []string,
+) (_ *internal.SymbolHistory, err error) {
+	defer derrors.WrapStack(&err, "GetSymbolsAtVersions(ctx, %q, %q, %q)", packagePath, modulePath, versions)
+	defer stats.Elapsed(ctx, "GetSymbolsAtVersions")()
+
+	query := squirrel.Select(
+		"s1.name AS symbol_name",
+		"s2.name AS parent_symbol_name",
+		"ps.section",
+		"ps.type",
+		"ps.synopsis",
+		"m.version",
+		"d.goos",
+		"d.goarch").
+		From("modules m").
+		Join("units u on u.module_id = m.id").
+		Join("documentation d ON d.unit_id = u.id").
+		Join("documentation_symbols ds ON ds.documentation_id = d.id").
+		Join("package_symbols ps ON ps.id = ds.package_symbol_id").
+		Join("paths p1 ON u.path_id = p1.id").
+		Join("symbol_names s1 ON ps.symbol_name_id = s1.id").
+		Join("symbol_names s2 ON ps.parent_symbol_name_id = s2.id").
+		Where(squirrel.Eq{"p1.path": packagePath}).
+		Where(squirrel.Eq{"m.module_path": modulePath}).
+		Where("m.version = ANY(?)", pq.Array(versions))
+	q, args, err := query.PlaceholderFormat(squirrel.Dollar).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	sh, collect := collectSymbolHistory(func(*internal.SymbolHistory, internal.SymbolMeta, string, internal.BuildContext) error { return nil })
+	if err := db.db.RunQuery(ctx, q, collect, args...); err != nil {
+		return nil, err
+	}
+	return sh, nil
+}
@@ -0,0 +1,68 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// AddUnitViews adds counts, a map from unit path to number of views, to the
+// views of each path on day.
+func (db *DB) AddUnitViews(ctx context.Context, day time.Time, counts map[string]int64) (err error) {
+	defer derrors.WrapStack(&err, "AddUnitViews(ctx, %s, %d paths)", day.Format(time.DateOnly), len(counts))
+
+	// Sort the paths so that concurrent calls lock rows in the same order.
+	var paths []string
+	for p := range counts {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	var values []any
+	for _, p := range paths {
+		values = append(values, p, day, counts[p])
+	}
+	return db.db.BulkInsert(ctx, "unit_views", []string{"path", "day", "views"}, values,
+		`ON CONFLICT (path, day) DO UPDATE SET views = unit_views.views + excluded.views`)
+}
+
+// GetPopularUnitPaths returns the limit unit paths with the most views on or
+// after the given day, most viewed first.
+func (db *DB) GetPopularUnitPaths(ctx context.Context, since time.Time, limit int) (_ []string, err error) {
+	defer derrors.WrapStack(&err, "GetPopularUnitPaths(ctx, %s, %d)", since.Format(time.DateOnly), limit)
+
+	var paths []string
+	collect := func(rows *sql.Rows) error {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return err
+		}
+		paths = append(paths, p)
+		return nil
+	}
+	err = db.db.RunQuery(ctx, `
+		SELECT path
+		FROM unit_views
+		WHERE day >= $1
+		GROUP BY path
+		ORDER BY sum(views) DESC, path
+		LIMIT $2`, collect, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+// DeleteUnitViewsBefore deletes the view counts of days before day.
+func (db *DB) DeleteUnitViewsBefore(ctx context.Context, day time.Time) (err error) {
+	defer derrors.WrapStack(&err, "DeleteUnitViewsBefore(ctx, %s)", day.Format(time.DateOnly))
+
+	_, err = db.db.Exec(ctx, `DELETE FROM unit_views WHERE day < $1`, day)
+	return err
+}
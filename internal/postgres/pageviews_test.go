@@ -0,0 +1,54 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnitViews(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	day1 := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	for _, a := range []struct {
+		day    time.Time
+		counts map[string]int64
+	}{
+		{day1, map[string]int64{"a.com/a": 5, "b.com/b": 1}},
+		{day2, map[string]int64{"b.com/b": 3, "c.com/c": 2}},
+		{day2, map[string]int64{"b.com/b": 3}},
+	} {
+		if err := testDB.AddUnitViews(ctx, a.day, a.counts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checkPopular := func(since time.Time, limit int, want []string) {
+		t.Helper()
+		got, err := testDB.GetPopularUnitPaths(ctx, since, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("GetPopularUnitPaths(%s, %d) mismatch (-want, +got):\n%s", since, limit, diff)
+		}
+	}
+	checkPopular(day1, 10, []string{"b.com/b", "a.com/a", "c.com/c"})
+	checkPopular(day1, 1, []string{"b.com/b"})
+	checkPopular(day2, 10, []string{"b.com/b", "c.com/c"})
+
+	if err := testDB.DeleteUnitViewsBefore(ctx, day2); err != nil {
+		t.Fatal(err)
+	}
+	checkPopular(day1, 10, []string{"b.com/b", "c.com/c"})
+}
@@ -0,0 +1,410 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/poller"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// PopularSearchWindow is the period over which requests for pages of
+	// search results are summed to determine the most popular ones.
+	PopularSearchWindow = 7 * 24 * time.Hour
+
+	// searchQueryCountRetention is how long daily counts of requests for
+	// pages of search results are kept.
+	searchQueryCountRetention = 30 * 24 * time.Hour
+
+	// maxPopularSearchAge is how long after they were computed cached
+	// results are served, so that results are not served long after the
+	// worker stops refreshing them.
+	maxPopularSearchAge = 24 * time.Hour
+
+	// maxPopularSearchQueryLen is the length in bytes of the longest
+	// normalized query that is counted. Longer queries are rarely repeated.
+	maxPopularSearchQueryLen = 100
+
+	// maxPendingSearchPages is the number of distinct pages of search results
+	// whose requests a frontend counts between flushes. Requests for other
+	// pages are not counted until the next flush.
+	maxPendingSearchPages = 10000
+
+	// maxSearchQueryCountsPerDay is the number of pages of search results
+	// whose counts are kept for each day before the current one. The counts
+	// of less requested pages are deleted.
+	maxSearchQueryCountsPerDay = 10000
+)
+
+// A searchPage identifies a page of search results that can be cached: the
+// first page of a package search across all modules, without filters.
+type searchPage struct {
+	query          string // normalized
+	maxResults     int
+	maxResultCount int
+}
+
+// cacheableSearchPage returns the page of search results for q and opts,
+// and whether it can be cached.
+func cacheableSearchPage(q string, opts SearchOptions) (searchPage, bool) {
+	if opts.SearchSymbols || opts.SymbolFilter != "" || opts.ModulePath != "" ||
+		!opts.Filters.IsZero() || opts.Offset != 0 {
+		return searchPage{}, false
+	}
+	// Search is case-insensitive and ignores extra spaces, so queries that
+	// differ only in those ways share a page, as do queries that differ only
+	// in compatible forms of characters, such as full-width letters.
+	query := strings.Join(strings.Fields(strings.ToLower(norm.NFKC.String(q))), " ")
+	if query == "" || len(query) > maxPopularSearchQueryLen {
+		return searchPage{}, false
+	}
+	return searchPage{query, opts.MaxResults, opts.MaxResultCount}, true
+}
+
+// popularSearchCache counts requests for pages of search results, and knows
+// which pages have cached results.
+type popularSearchCache struct {
+	now func() time.Time // for testing
+
+	mu         sync.Mutex
+	counts     map[searchPage]int64 // not yet added to the database
+	maxPending int                  // maximum size of counts
+
+	cached *poller.Poller // of map[searchPage]bool
+}
+
+// CachePopularSearches makes db count requests for pages of search results,
+// and serve the results of the most popular pages from those computed by
+// RefreshPopularSearches. Every period, it adds the counts to the database
+// and reloads which pages have cached results.
+//
+// It must be called before db is used concurrently.
+func (db *DB) CachePopularSearches(ctx context.Context, period time.Duration) {
+	c := &popularSearchCache{
+		now:        time.Now,
+		counts:     map[searchPage]int64{},
+		maxPending: maxPendingSearchPages,
+	}
+	c.cached = poller.New(
+		map[searchPage]bool(nil),
+		func(ctx context.Context) (any, error) {
+			if err := db.flushSearchQueryCounts(ctx); err != nil {
+				return nil, err
+			}
+			return db.getCachedSearchPages(ctx)
+		},
+		func(err error) {
+			log.Errorf(ctx, "popular search cache: %v", err)
+		})
+	db.popularSearches = c
+	c.cached.Poll(ctx)
+	c.cached.Start(ctx, period)
+}
+
+// record counts a request for page, unless c.maxPending other pages are
+// already counted.
+func (c *popularSearchCache) record(page searchPage) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.counts[page]; !ok && len(c.counts) >= c.maxPending {
+		return
+	}
+	c.counts[page]++
+}
+
+// isCached reports whether page had cached results when they were last
+// loaded.
+func (c *popularSearchCache) isCached(page searchPage) bool {
+	return c.cached.Current().(map[searchPage]bool)[page]
+}
+
+// searchPopular returns the cached results of the search for q with opts,
+// or nil if there are none, and counts the request. It never fails: if the
+// cached results cannot be read, the search is run as if they did not exist.
+func (db *DB) searchPopular(ctx context.Context, q string, opts SearchOptions) []*SearchResult {
+	c := db.popularSearches
+	if c == nil {
+		return nil
+	}
+	page, ok := cacheableSearchPage(q, opts)
+	if !ok {
+		return nil
+	}
+	c.record(page)
+	if !c.isCached(page) {
+		return nil
+	}
+	results, err := db.getPopularSearchResults(ctx, page)
+	if err != nil {
+		log.Errorf(ctx, "%v", err)
+		return nil
+	}
+	// Paths may have been excluded, and modules taken down, since the results
+	// were computed.
+	down, err := db.takenDownModules(ctx, results)
+	if err != nil {
+		log.Errorf(ctx, "%v", err)
+		return nil
+	}
+	var filtered []*SearchResult
+	for _, r := range results {
+		if !down[internal.Modver{Path: r.ModulePath}] &&
+			!down[internal.Modver{Path: r.ModulePath, Version: r.Version}] &&
+			!db.IsExcluded(ctx, r.PackagePath, "") {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered
+}
+
+// takenDownModules returns the takedowns in effect for the modules of
+// results, as module versions whose version is empty for a takedown of all
+// versions.
+func (db *DB) takenDownModules(ctx context.Context, results []*SearchResult) (_ map[internal.Modver]bool, err error) {
+	defer derrors.WrapStack(&err, "takenDownModules(ctx, %d results)", len(results))
+
+	var paths []string
+	for _, r := range results {
+		paths = append(paths, r.ModulePath)
+	}
+	down := map[internal.Modver]bool{}
+	collect := func(rows *sql.Rows) error {
+		var mv internal.Modver
+		if err := rows.Scan(&mv.Path, &mv.Version); err != nil {
+			return err
+		}
+		down[mv] = true
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, `
+		SELECT module_path, version
+		FROM takedowns
+		WHERE module_path = ANY($1) AND lifted_at IS NULL`,
+		collect, pq.Array(paths)); err != nil {
+		return nil, err
+	}
+	return down, nil
+}
+
+// ClearPopularSearchResults deletes all cached results of popular searches,
+// so that the searches are run until RefreshPopularSearches computes them
+// again.
+func (db *DB) ClearPopularSearchResults(ctx context.Context) (err error) {
+	defer derrors.WrapStack(&err, "ClearPopularSearchResults(ctx)")
+	_, err = db.db.Exec(ctx, `DELETE FROM popular_search_results`)
+	return err
+}
+
+// getPopularSearchResults returns the cached results of page, or nil if there
+// are none.
+func (db *DB) getPopularSearchResults(ctx context.Context, page searchPage) (_ []*SearchResult, err error) {
+	defer derrors.WrapStack(&err, "getPopularSearchResults(ctx, %+v)", page)
+
+	var data []byte
+	err = db.db.QueryRow(ctx, `
+		SELECT results
+		FROM popular_search_results
+		WHERE query = $1 AND max_results = $2 AND max_result_count = $3
+			AND refreshed_at > CURRENT_TIMESTAMP - make_interval(secs => $4)
+			AND bypass_license_check = $5`,
+		page.query, page.maxResults, page.maxResultCount, maxPopularSearchAge.Seconds(),
+		db.bypassLicenseCheck).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var results []*SearchResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// getCachedSearchPages returns the pages of search results with cached
+// results that can be served. Results computed with a different license check
+// than db's cannot be.
+func (db *DB) getCachedSearchPages(ctx context.Context) (_ map[searchPage]bool, err error) {
+	defer derrors.WrapStack(&err, "getCachedSearchPages(ctx)")
+
+	pages := map[searchPage]bool{}
+	collect := func(rows *sql.Rows) error {
+		var p searchPage
+		if err := rows.Scan(&p.query, &p.maxResults, &p.maxResultCount); err != nil {
+			return err
+		}
+		pages[p] = true
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, `
+		SELECT query, max_results, max_result_count
+		FROM popular_search_results
+		WHERE refreshed_at > CURRENT_TIMESTAMP - make_interval(secs => $1)
+			AND bypass_license_check = $2`,
+		collect, maxPopularSearchAge.Seconds(), db.bypassLicenseCheck); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
+// flushSearchQueryCounts adds the requests for pages of search results
+// counted since the last flush to the counts of the current day.
+func (db *DB) flushSearchQueryCounts(ctx context.Context) (err error) {
+	defer derrors.WrapStack(&err, "flushSearchQueryCounts(ctx)")
+
+	c := db.popularSearches
+	c.mu.Lock()
+	counts := c.counts
+	c.counts = map[searchPage]int64{}
+	c.mu.Unlock()
+	if len(counts) == 0 {
+		return nil
+	}
+	// Sort the pages so that concurrent calls lock rows in the same order.
+	pages := make([]searchPage, 0, len(counts))
+	for p := range counts {
+		pages = append(pages, p)
+	}
+	sort.Slice(pages, func(i, j int) bool {
+		a, b := pages[i], pages[j]
+		if a.query != b.query {
+			return a.query < b.query
+		}
+		if a.maxResults != b.maxResults {
+			return a.maxResults < b.maxResults
+		}
+		return a.maxResultCount < b.maxResultCount
+	})
+	day := c.now().UTC().Truncate(24 * time.Hour)
+	var values []any
+	for _, p := range pages {
+		values = append(values, p.query, p.maxResults, p.maxResultCount, day, counts[p])
+	}
+	err = db.db.BulkInsert(ctx, "search_query_counts",
+		[]string{"query", "max_results", "max_result_count", "day", "count"}, values,
+		`ON CONFLICT (query, max_results, max_result_count, day)
+		DO UPDATE SET count = search_query_counts.count + excluded.count`)
+	if err != nil {
+		// Count the requests in the next flush.
+		c.mu.Lock()
+		for p, n := range counts {
+			c.counts[p] += n
+		}
+		c.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// RefreshPopularSearches computes the results of the limit most requested
+// pages of search results in the last PopularSearchWindow, and stores them to
+// be served by the frontends that cache popular searches and check licenses
+// as db does. Cached results of
+// pages that are no longer among them are deleted, as are expired counts and
+// the counts of past days beyond maxSearchQueryCountsPerDay. It returns the
+// number of pages whose results were computed.
+func (db *DB) RefreshPopularSearches(ctx context.Context, limit int) (_ int, err error) {
+	defer derrors.WrapStack(&err, "RefreshPopularSearches(ctx, %d)", limit)
+
+	// Use the time of the database, which sets refreshed_at.
+	var start time.Time
+	if err := db.db.QueryRow(ctx, `SELECT CURRENT_TIMESTAMP`).Scan(&start); err != nil {
+		return 0, err
+	}
+	today := start.UTC().Truncate(24 * time.Hour)
+	if _, err := db.db.Exec(ctx, `DELETE FROM search_query_counts WHERE day < $1`,
+		today.Add(-searchQueryCountRetention)); err != nil {
+		return 0, err
+	}
+	if err := db.pruneSearchQueryCounts(ctx, today, maxSearchQueryCountsPerDay); err != nil {
+		return 0, err
+	}
+	var pages []searchPage
+	collect := func(rows *sql.Rows) error {
+		var p searchPage
+		if err := rows.Scan(&p.query, &p.maxResults, &p.maxResultCount); err != nil {
+			return err
+		}
+		pages = append(pages, p)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, `
+		SELECT query, max_results, max_result_count
+		FROM search_query_counts
+		WHERE day >= $1
+		GROUP BY query, max_results, max_result_count
+		ORDER BY sum(count) DESC, query
+		LIMIT $2`,
+		collect, today.Add(-PopularSearchWindow), limit); err != nil {
+		return 0, err
+	}
+	for _, p := range pages {
+		results, err := db.searchUncached(ctx, p.query, SearchOptions{
+			MaxResults:     p.maxResults,
+			MaxResultCount: p.maxResultCount,
+		})
+		if err != nil {
+			return 0, err
+		}
+		data, err := json.Marshal(results)
+		if err != nil {
+			return 0, err
+		}
+		if _, err := db.db.Exec(ctx, `
+			INSERT INTO popular_search_results
+				(query, max_results, max_result_count, results, bypass_license_check, refreshed_at)
+			VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP)
+			ON CONFLICT (query, max_results, max_result_count)
+			DO UPDATE SET results = excluded.results,
+				bypass_license_check = excluded.bypass_license_check,
+				refreshed_at = excluded.refreshed_at`,
+			p.query, p.maxResults, p.maxResultCount, data, db.bypassLicenseCheck); err != nil {
+			return 0, err
+		}
+	}
+	// Every page that is still popular was refreshed after start.
+	if _, err := db.db.Exec(ctx, `DELETE FROM popular_search_results WHERE refreshed_at < $1`, start); err != nil {
+		return 0, err
+	}
+	return len(pages), nil
+}
+
+// pruneSearchQueryCounts deletes the counts of each day before the given one,
+// except those of the keep most requested pages of the day.
+func (db *DB) pruneSearchQueryCounts(ctx context.Context, before time.Time, keep int) (err error) {
+	defer derrors.WrapStack(&err, "pruneSearchQueryCounts(ctx, %s, %d)", before, keep)
+
+	n, err := db.db.Exec(ctx, `
+		DELETE FROM search_query_counts c
+		USING (
+			SELECT query, max_results, max_result_count, day,
+				row_number() OVER (PARTITION BY day ORDER BY count DESC, query) AS rank
+			FROM search_query_counts
+			WHERE day < $1
+		) r
+		WHERE c.query = r.query AND c.max_results = r.max_results
+			AND c.max_result_count = r.max_result_count AND c.day = r.day
+			AND r.rank > $2`,
+		before, keep)
+	if err != nil {
+		return err
+	}
+	log.Infof(ctx, "pruned %d search query counts", n)
+	return nil
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestCacheableSearchPage(t *testing.T) {
+	opts := SearchOptions{MaxResults: 25, MaxResultCount: 125}
+	for _, test := range []struct {
+		q      string
+		opts   SearchOptions
+		want   searchPage
+		wantOK bool
+	}{
+		{"yaml", opts, searchPage{"yaml", 25, 125}, true},
+		{"  HTTP   Router ", opts, searchPage{"http router", 25, 125}, true},
+		{"ＹＡＭＬ", opts, searchPage{"yaml", 25, 125}, true},
+		{strings.Repeat("a", maxPopularSearchQueryLen), opts, searchPage{strings.Repeat("a", maxPopularSearchQueryLen), 25, 125}, true},
+		{strings.Repeat("a", maxPopularSearchQueryLen+1), opts, searchPage{}, false},
+		{"yaml", SearchOptions{MaxResults: 10}, searchPage{"yaml", 10, 0}, true},
+		{"   ", opts, searchPage{}, false},
+		{"yaml", SearchOptions{MaxResults: 25, Offset: 25}, searchPage{}, false},
+		{"yaml", SearchOptions{MaxResults: 25, SearchSymbols: true}, searchPage{}, false},
+		{"yaml", SearchOptions{MaxResults: 25, ModulePath: "example.com/mod"}, searchPage{}, false},
+		{"yaml", SearchOptions{MaxResults: 25, Filters: internal.SearchFilters{License: "MIT"}}, searchPage{}, false},
+	} {
+		got, ok := cacheableSearchPage(test.q, test.opts)
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("cacheableSearchPage(%q, %+v) = %+v, %t; want %+v, %t",
+				test.q, test.opts, got, ok, test.want, test.wantOK)
+		}
+	}
+}
+
+func TestPopularSearchCacheRecord(t *testing.T) {
+	c := &popularSearchCache{counts: map[searchPage]int64{}, maxPending: 2}
+	for _, q := range []string{"a", "b", "c", "a"} {
+		c.record(searchPage{query: q})
+	}
+	want := map[searchPage]int64{{query: "a"}: 2, {query: "b"}: 1}
+	if diff := cmp.Diff(want, c.counts, cmp.AllowUnexported(searchPage{})); diff != "" {
+		t.Errorf("counts mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestPopularSearches(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	m := sample.Module("example.com/mod", "v1.2.3", "pkg")
+	MustInsertModule(ctx, t, testDB, m)
+
+	// Use a separate DB, so that the cache does not outlive the test.
+	db := New(testDB.Underlying())
+	db.CachePopularSearches(ctx, time.Hour)
+
+	opts := SearchOptions{MaxResults: 10, MaxResultCount: 10}
+	want, err := db.Search(ctx, m.ModulePath, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(want) == 0 {
+		t.Fatal("got no results")
+	}
+	// The same page, with a query that differs only in case.
+	if _, err := db.Search(ctx, "EXAMPLE.com/mod", opts); err != nil {
+		t.Fatal(err)
+	}
+	// A page that is not cached.
+	if _, err := db.Search(ctx, m.ModulePath, SearchOptions{MaxResults: 10, Offset: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.flushSearchQueryCounts(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err := testDB.db.QueryRow(ctx, `
+		SELECT sum(count) FROM search_query_counts WHERE query = $1`, m.ModulePath).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Errorf("got %d requests counted, want 2", count)
+	}
+
+	n, err := testDB.RefreshPopularSearches(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("refreshed %d pages, want 1", n)
+	}
+	db.popularSearches.cached.Poll(ctx)
+
+	// Results computed with the license check are not served by a DB that
+	// bypasses it.
+	bypassDB := NewBypassingLicenseCheck(testDB.Underlying())
+	bypassDB.CachePopularSearches(ctx, time.Hour)
+	if got := bypassDB.searchPopular(ctx, m.ModulePath, opts); got != nil {
+		t.Errorf("DB bypassing the license check: got %d cached results, want none", len(got))
+	}
+
+	// Remove the search documents, so that only the cache has results.
+	if _, err := testDB.db.Exec(ctx, `DELETE FROM search_documents`); err != nil {
+		t.Fatal(err)
+	}
+	got, err := db.Search(ctx, m.ModulePath, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("cached results mismatch (-want, +got):\n%s", diff)
+	}
+	got, err = testDB.Search(ctx, m.ModulePath, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("DB without the cache: got %d results, want 0", len(got))
+	}
+
+	// Cached results leave out modules that have been taken down since.
+	if _, err := testDB.CreateTakedown(ctx, m.ModulePath, "", "test", "spam", ""); err != nil {
+		t.Fatal(err)
+	}
+	if got := db.searchPopular(ctx, m.ModulePath, opts); len(got) != 0 {
+		t.Errorf("after takedown: got %d cached results, want 0", len(got))
+	}
+
+	if err := testDB.ClearPopularSearchResults(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if results, err := testDB.getPopularSearchResults(ctx, searchPage{m.ModulePath, 10, 10}); err != nil || results != nil {
+		t.Errorf("after clearing: got %v, %v; want nil, nil", results, err)
+	}
+}
+
+func TestPruneSearchQueryCounts(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.Add(-24 * time.Hour)
+	var values []any
+	for _, c := range []struct {
+		query string
+		day   time.Time
+		count int
+	}{
+		{"a", yesterday, 3},
+		{"b", yesterday, 2},
+		{"c", yesterday, 1},
+		{"a", today, 1},
+		{"b", today, 1},
+	} {
+		values = append(values, c.query, 10, 10, c.day, c.count)
+	}
+	if err := testDB.db.BulkInsert(ctx, "search_query_counts",
+		[]string{"query", "max_results", "max_result_count", "day", "count"}, values, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := testDB.pruneSearchQueryCounts(ctx, today, 1); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	collect := func(rows *sql.Rows) error {
+		var q string
+		var day time.Time
+		if err := rows.Scan(&q, &day); err != nil {
+			return err
+		}
+		got = append(got, fmt.Sprintf("%s %s", q, day.UTC().Format(time.DateOnly)))
+		return nil
+	}
+	if err := testDB.db.RunQuery(ctx, `SELECT query, day FROM search_query_counts ORDER BY day, query`, collect); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"a " + yesterday.Format(time.DateOnly),
+		"a " + today.Format(time.DateOnly),
+		"b " + today.Format(time.DateOnly),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
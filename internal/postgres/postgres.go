@@ -22,6 +22,8 @@ type DB struct {
 	bypassLicenseCheck bool
 	expoller           *poller.Poller
 	cancel             func()
+	// popularSearches is non-nil if the DB caches popular searches.
+	popularSearches *popularSearchCache
 }
 
 // New returns a new postgres DB.
@@ -0,0 +1,72 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/postgres/search"
+)
+
+// A PlannedQuery is a query on a critical path of the frontend, along with
+// the arguments to plan it with.
+type PlannedQuery struct {
+	Name  string
+	Query string
+	Args  []any
+}
+
+// PlanArgs are the values that PlannedQueries substitutes for query
+// arguments. They should refer to data that exists in the database whose
+// plans are being checked, so that the planner's estimates are realistic.
+type PlanArgs struct {
+	// SearchQuery is a package search query.
+	SearchQuery string
+	// SymbolQuery is a symbol name without a package, such as "Writer".
+	SymbolQuery string
+	// UnitPath, ModulePath and Version identify a unit.
+	UnitPath   string
+	ModulePath string
+	Version    string
+}
+
+// PlannedQueries returns the search and unit page queries whose plans are
+// checked by devtools/cmd/queryplan. The queries are built by the same code
+// that serves those pages, so that a change to them is caught by the check.
+func PlannedQueries(a PlanArgs) (_ []PlannedQuery, err error) {
+	defer derrors.Wrap(&err, "PlannedQueries(%+v)", a)
+
+	const limit = 10
+	unitMeta, unitMetaArgs, err := unitMetaQuery(a.UnitPath, a.ModulePath, a.Version).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	return []PlannedQuery{
+		{
+			Name:  "deep-search",
+			Query: deepSearchQuery(""),
+			Args:  []any{a.SearchQuery, limit, 0},
+		},
+		{
+			Name:  "symbol-search",
+			Query: search.SymbolQuery(search.SearchTypeSymbol),
+			Args:  []any{a.SymbolQuery, limit},
+		},
+		{
+			Name:  "field-or-method-search",
+			Query: search.SymbolQuery(search.SearchTypeFieldOrMethod),
+			Args:  []any{a.SymbolQuery, limit},
+		},
+		{
+			Name:  "unit-meta",
+			Query: unitMeta,
+			Args:  unitMetaArgs,
+		},
+		{
+			Name:  "unit-id",
+			Query: unitIDQuery,
+			Args:  []any{a.UnitPath, a.ModulePath, a.Version},
+		},
+	}, nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestPlannedQueries(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.DefaultModule()
+	MustInsertModule(ctx, t, testDB, m)
+
+	queries, err := PlannedQueries(PlanArgs{
+		SearchQuery: "foo",
+		SymbolQuery: "Func",
+		UnitPath:    sample.PackagePath,
+		ModulePath:  m.ModulePath,
+		Version:     m.Version,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each query must be valid and accept its arguments.
+	for _, q := range queries {
+		if _, err := testDB.db.Exec(ctx, "EXPLAIN "+q.Query, q.Args...); err != nil {
+			t.Errorf("%s: %v", q.Name, err)
+		}
+	}
+}
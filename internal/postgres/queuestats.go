@@ -0,0 +1,79 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// FailureClass summarizes the module versions whose last fetch failed with
+// the same status.
+type FailureClass struct {
+	Status int `json:"status"`
+	Count  int `json:"count"`
+	// Latest is the module version of the class that failed most recently.
+	LatestModulePath string    `json:"latestModulePath"`
+	LatestVersion    string    `json:"latestVersion"`
+	LatestError      string    `json:"latestError"`
+	LatestAt         time.Time `json:"latestAt"`
+}
+
+// GetRecentFailureClasses groups the module versions whose last fetch failed
+// at or after since by their status, most common first. As for host stats,
+// statuses 500 and 550-599 are failures.
+func (db *DB) GetRecentFailureClasses(ctx context.Context, since time.Time) (_ []*FailureClass, err error) {
+	defer derrors.WrapStack(&err, "GetRecentFailureClasses(ctx, %s)", since)
+
+	query := `
+		SELECT DISTINCT ON (status)
+			status,
+			count(*) OVER (PARTITION BY status),
+			module_path,
+			version,
+			error,
+			last_processed_at
+		FROM module_version_states
+		WHERE (status = 500 OR (status >= 550 AND status < 600))
+			AND last_processed_at >= $1
+		ORDER BY status, last_processed_at DESC`
+	var classes []*FailureClass
+	err = db.db.RunQuery(ctx, query, func(rows *sql.Rows) error {
+		var c FailureClass
+		if err := rows.Scan(&c.Status, &c.Count, &c.LatestModulePath, &c.LatestVersion,
+			&c.LatestError, &c.LatestAt); err != nil {
+			return fmt.Errorf("rows.Scan(): %v", err)
+		}
+		classes = append(classes, &c)
+		return nil
+	}, since)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(classes, func(i, j int) bool { return classes[i].Count > classes[j].Count })
+	return classes, nil
+}
+
+// GetMostRetriedVersions returns up to limit module versions processed at
+// or after since that have been tried more than once, most tried first.
+func (db *DB) GetMostRetriedVersions(ctx context.Context, since time.Time, limit int) (_ []*internal.ModuleVersionState, err error) {
+	defer derrors.WrapStack(&err, "GetMostRetriedVersions(ctx, %s, %d)", since, limit)
+
+	queryFormat := `
+		SELECT %s
+		FROM
+			module_version_states
+		WHERE try_count > 1
+			AND last_processed_at >= $1
+		ORDER BY try_count DESC, last_processed_at DESC
+		LIMIT $2`
+	return db.queryModuleVersionStates(ctx, queryFormat, since, limit)
+}
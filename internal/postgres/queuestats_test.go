@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestQueueStats(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	since := time.Now().Add(-time.Hour)
+	update := func(mvs *ModuleVersionStateForUpdate) {
+		t.Helper()
+		mvs.Timestamp = sample.NowTruncated()
+		must(t, testDB.UpdateModuleVersionState(ctx, mvs))
+	}
+	for _, mvs := range []*ModuleVersionStateForUpdate{
+		{ModulePath: "a.com/m", Version: "v1.0.0", Status: 200},
+		{ModulePath: "b.com/m", Version: "v1.0.0", Status: 500, FetchErr: errors.New("b failed")},
+		{ModulePath: "c.com/m", Version: "v1.0.0", Status: 500, FetchErr: errors.New("c failed")},
+		{ModulePath: "d.com/m", Version: "v1.0.0", Status: 550, FetchErr: errors.New("d timed out")},
+		{ModulePath: "e.com/m", Version: "v1.0.0", Status: 404},
+		{ModulePath: "f.com/m", Version: "v1.0.0", Status: 520},
+	} {
+		must(t, testDB.InsertIndexVersions(ctx, []*internal.IndexVersion{
+			{Path: mvs.ModulePath, Version: mvs.Version, Timestamp: sample.NowTruncated()},
+		}))
+		update(mvs)
+	}
+	// Retry c.com/m twice and d.com/m once more.
+	update(&ModuleVersionStateForUpdate{ModulePath: "c.com/m", Version: "v1.0.0", Status: 500, FetchErr: errors.New("c failed again")})
+	update(&ModuleVersionStateForUpdate{ModulePath: "c.com/m", Version: "v1.0.0", Status: 500, FetchErr: errors.New("c failed again")})
+	update(&ModuleVersionStateForUpdate{ModulePath: "d.com/m", Version: "v1.0.0", Status: 550, FetchErr: errors.New("d timed out")})
+
+	classes, err := testDB.GetRecentFailureClasses(ctx, since)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantClasses := []*FailureClass{
+		{Status: 500, Count: 2, LatestModulePath: "c.com/m", LatestVersion: "v1.0.0", LatestError: "c failed again"},
+		{Status: 550, Count: 1, LatestModulePath: "d.com/m", LatestVersion: "v1.0.0", LatestError: "d timed out"},
+	}
+	if diff := cmp.Diff(wantClasses, classes, cmpopts.IgnoreFields(FailureClass{}, "LatestAt")); diff != "" {
+		t.Errorf("GetRecentFailureClasses mismatch (-want, +got):\n%s", diff)
+	}
+	classes, err = testDB.GetRecentFailureClasses(ctx, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(classes) != 0 {
+		t.Errorf("GetRecentFailureClasses with future time: got %d classes, want none", len(classes))
+	}
+
+	retried, err := testDB.GetMostRetriedVersions(ctx, since, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, v := range retried {
+		got = append(got, v.ModulePath)
+		if v.ModulePath == "c.com/m" && v.TryCount != 3 {
+			t.Errorf("c.com/m: got TryCount %d, want 3", v.TryCount)
+		}
+	}
+	if want := []string{"c.com/m", "d.com/m"}; !cmp.Equal(got, want) {
+		t.Errorf("GetMostRetriedVersions: got %v, want %v", got, want)
+	}
+}
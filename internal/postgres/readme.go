@@ -0,0 +1,252 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docrender"
+	"golang.org/x/pkgsite/internal/source"
+)
+
+// ReprocessReadmes re-runs README processing on up to limit stored READMEs
+// whose unit IDs are greater than afterUnitID, in order of unit ID. The
+// README sections of each unit's search document are recomputed from the
+// README contents in the database, without refetching the module or
+// touching its documentation.
+//
+// Only READMEs of units whose path is the module path and that have a search
+// document are considered, since those are the only ones that
+// UpsertSearchDocument summarizes.
+//
+// ReprocessReadmes returns the number of READMEs processed and the largest
+// unit ID seen, which can be passed as afterUnitID to process the next batch.
+// When there are no more READMEs, n is zero.
+func (db *DB) ReprocessReadmes(ctx context.Context, afterUnitID, limit int) (lastUnitID, n int, err error) {
+	defer derrors.WrapStack(&err, "ReprocessReadmes(ctx, %d, %d)", afterUnitID, limit)
+	return db.upsertStoredSearchDocuments(ctx, "INNER", "p.path = m.module_path", afterUnitID, limit)
+}
+
+// A ReadmeToRender is a stored README or docs page of a unit, with the
+// information about its module that affects how it is rendered.
+type ReadmeToRender struct {
+	// ModuleID identifies the module version of the unit.
+	ModuleID     int
+	Readme       *internal.Readme
+	SourceInfo   *source.Info
+	NoIssueLinks bool
+	// DocsPages is set if Readme is a docs page of the unit. It maps the file
+	// paths of the docs pages of the unit to their URLs, as in
+	// docrender.DocsPageRequest.
+	DocsPages map[string]string
+}
+
+// GetReadmesToRender returns the READMEs, including the localized ones, and
+// the docs pages of up to limit redistributable units whose IDs are greater
+// than afterUnitID, in order of unit ID, so that they can be rendered and
+// stored with InsertRenderedReadmes. Those of module versions that are taken
+// down are left out.
+//
+// It also returns the largest unit ID seen, which can be passed as
+// afterUnitID to get the next batch. When there are no more READMEs,
+// lastUnitID is zero.
+func (db *DB) GetReadmesToRender(ctx context.Context, afterUnitID, limit int) (_ []*ReadmeToRender, lastUnitID int, err error) {
+	defer derrors.WrapStack(&err, "GetReadmesToRender(ctx, %d, %d)", afterUnitID, limit)
+
+	return db.getReadmesToRender(ctx, `
+		AND u.id > $1
+		AND (u.redistributable OR $2)
+		ORDER BY u.id
+		LIMIT $3`, afterUnitID, db.bypassLicenseCheck, limit)
+}
+
+// GetModuleReadmesToRender is like GetReadmesToRender, but returns the
+// READMEs and docs pages of the redistributable units of a module version.
+func (db *DB) GetModuleReadmesToRender(ctx context.Context, modulePath, resolvedVersion string) (_ []*ReadmeToRender, err error) {
+	defer derrors.WrapStack(&err, "GetModuleReadmesToRender(ctx, %q, %q)", modulePath, resolvedVersion)
+
+	rs, _, err := db.getReadmesToRender(ctx, `
+		AND m.module_path = $1
+		AND m.version = $2
+		AND (u.redistributable OR $3)
+		ORDER BY u.id`, modulePath, resolvedVersion, db.bypassLicenseCheck)
+	return rs, err
+}
+
+// getReadmesToRender returns the READMEs, localized READMEs and docs pages of
+// the units selected by where, which adds conditions to the query, and its
+// args, along with the largest unit ID seen. Units of module versions that
+// are taken down are left out.
+func (db *DB) getReadmesToRender(ctx context.Context, where string, args ...any) (_ []*ReadmeToRender, lastUnitID int, err error) {
+	query := `
+		SELECT
+			u.id,
+			p.path,
+			m.id,
+			m.module_path,
+			m.source_info,
+			m.author_metadata,
+			r.file_path,
+			r.contents
+		FROM units u
+		INNER JOIN paths p
+		ON p.id = u.path_id
+		INNER JOIN modules m
+		ON m.id = u.module_id
+		LEFT JOIN readmes r
+		ON r.unit_id = u.id
+		WHERE (r.unit_id IS NOT NULL OR EXISTS (SELECT 1 FROM docs_pages d WHERE d.unit_id = u.id))
+		AND NOT EXISTS (
+			SELECT 1 FROM takedowns t
+			WHERE t.module_path = m.module_path
+			    AND (t.version = '' OR t.version = m.version)
+			    AND t.lifted_at IS NULL
+		)` + where
+
+	// unitToRender is a unit whose READMEs or docs pages are rendered.
+	type unitToRender struct {
+		path, modulePath string
+		r                ReadmeToRender // without a README
+		docsPages        map[string]string
+	}
+	var (
+		rs      []*ReadmeToRender
+		unitIDs []int64
+		byUnit  = map[int]*unitToRender{}
+	)
+	collect := func(rows *sql.Rows) error {
+		var (
+			u                  unitToRender
+			md                 *internal.AuthorMetadata
+			filePath, contents sql.NullString
+		)
+		if err := rows.Scan(&lastUnitID, &u.path, &u.r.ModuleID, &u.modulePath,
+			jsonbScanner{&u.r.SourceInfo}, jsonbScanner{&md}, &filePath, &contents); err != nil {
+			return err
+		}
+		u.r.NoIssueLinks = md.IssueLinksDisabled()
+		if filePath.Valid {
+			r := u.r
+			r.Readme = &internal.Readme{Filepath: filePath.String, Contents: contents.String}
+			rs = append(rs, &r)
+		}
+		unitIDs = append(unitIDs, int64(lastUnitID))
+		byUnit[lastUnitID] = &u
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, args...); err != nil {
+		return nil, 0, err
+	}
+	if len(unitIDs) == 0 {
+		return nil, 0, nil
+	}
+
+	collect = func(rows *sql.Rows) error {
+		var (
+			unitID int
+			lr     internal.Readme
+		)
+		if err := rows.Scan(&unitID, &lr.Filepath, &lr.Contents, &lr.Lang); err != nil {
+			return err
+		}
+		r := byUnit[unitID].r
+		r.Readme = &lr
+		rs = append(rs, &r)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, `
+		SELECT unit_id, file_path, contents, lang
+		FROM localized_readmes
+		WHERE unit_id = ANY($1)
+		ORDER BY unit_id, lang`, collect, pq.Array(unitIDs)); err != nil {
+		return nil, 0, err
+	}
+
+	collect = func(rows *sql.Rows) error {
+		var (
+			unitID int
+			dp     internal.Readme
+		)
+		if err := rows.Scan(&unitID, &dp.Filepath, &dp.Contents); err != nil {
+			return err
+		}
+		// All the docs pages of a unit share its map of URLs, which is
+		// complete once they have all been read.
+		u := byUnit[unitID]
+		if u.docsPages == nil {
+			u.docsPages = map[string]string{}
+		}
+		u.docsPages[dp.Filepath] = docrender.DocsPageURL(docrender.DocsPageName(u.path, u.modulePath, dp.Filepath))
+		r := u.r
+		r.Readme = &dp
+		r.DocsPages = u.docsPages
+		rs = append(rs, &r)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, `
+		SELECT unit_id, file_path, contents
+		FROM docs_pages
+		WHERE unit_id = ANY($1)
+		ORDER BY unit_id, file_path`, collect, pq.Array(unitIDs)); err != nil {
+		return nil, 0, err
+	}
+	return rs, lastUnitID, nil
+}
+
+// GetRenderedReadme returns the rendered README stored under hash by
+// InsertRenderedReadmes. It returns an error that wraps derrors.NotFound if
+// there is none.
+func (db *DB) GetRenderedReadme(ctx context.Context, hash string) (_ []byte, err error) {
+	defer derrors.WrapStack(&err, "GetRenderedReadme(ctx, %q)", hash)
+
+	var rendered []byte
+	err = db.db.QueryRow(ctx, `SELECT rendered FROM rendered_readmes WHERE readme_hash = $1`, hash).Scan(&rendered)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, derrors.NotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rendered, nil
+}
+
+// A RenderedReadme is a README rendered by the worker.
+type RenderedReadme struct {
+	// Hash is the docrender.ReadmeHash of the request that rendered it.
+	Hash string
+	// ModuleID identifies the module version whose README was rendered.
+	// The rendering is deleted along with that module version.
+	ModuleID int
+	// Rendered is the JSON-encoded docrender.ReadmeResponse.
+	Rendered []byte
+}
+
+// InsertRenderedReadmes stores rendered READMEs. Since a hash always
+// identifies the same rendering, READMEs that are already stored are left
+// alone, even if they were stored for another module version.
+func (db *DB) InsertRenderedReadmes(ctx context.Context, rendered []*RenderedReadme) (err error) {
+	defer derrors.WrapStack(&err, "InsertRenderedReadmes(ctx, %d READMEs)", len(rendered))
+
+	if len(rendered) == 0 {
+		return nil
+	}
+	// Insert in a consistent order, to avoid deadlocks between concurrent
+	// inserts of the same READMEs.
+	rendered = slices.Clone(rendered)
+	sort.Slice(rendered, func(i, j int) bool { return rendered[i].Hash < rendered[j].Hash })
+	var values []any
+	for _, r := range rendered {
+		values = append(values, r.Hash, r.ModuleID, string(r.Rendered))
+	}
+	return db.db.BulkInsert(ctx, "rendered_readmes", []string{"readme_hash", "module_id", "rendered"}, values, database.OnConflictDoNothing)
+}
@@ -0,0 +1,220 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestReprocessReadmes(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.Module("mod.com", "v1.2.3", "", "A")
+	m.Units[0].Readme = &internal.Readme{
+		Filepath: "README.md",
+		Contents: "# Reticulator\n\nSplines are reticulated here.",
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	// Clear the search tokens, as if they had been computed by an older
+	// README processor.
+	if _, err := testDB.db.Exec(ctx, `UPDATE search_documents SET tsv_search_tokens = ''`); err != nil {
+		t.Fatal(err)
+	}
+
+	last, n, err := testDB.ReprocessReadmes(ctx, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only the module root's README is summarized in search.
+	if n != 1 {
+		t.Errorf("got %d READMEs processed, want 1", n)
+	}
+	var found bool
+	err = testDB.db.QueryRow(ctx, `
+		SELECT tsv_search_tokens @@ to_tsquery('reticulated')
+		FROM search_documents
+		WHERE package_path = $1`, "mod.com").Scan(&found)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("README contents were not reindexed")
+	}
+
+	_, n, err = testDB.ReprocessReadmes(ctx, last, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("after unit %d: got %d READMEs processed, want 0", last, n)
+	}
+}
+
+func TestRenderedReadmes(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.Module("mod.com", "v1.2.3", "", "A")
+	m.Units[0].Readme = &internal.Readme{Filepath: "README.md", Contents: "# Module"}
+	m.Units[0].LocalizedReadmes = []*internal.Readme{{Filepath: "README.fr.md", Contents: "# Module FR", Lang: "fr"}}
+	m.Units[1].Readme = &internal.Readme{Filepath: "A/README.md", Contents: "# A"}
+	MustInsertModule(ctx, t, testDB, m)
+
+	readmes := func(rs []*ReadmeToRender) []*internal.Readme {
+		var got []*internal.Readme
+		for _, r := range rs {
+			if r.SourceInfo == nil {
+				t.Errorf("%s: no source info", r.Readme.Filepath)
+			}
+			got = append(got, r.Readme)
+		}
+		return got
+	}
+	want := []*internal.Readme{m.Units[0].Readme, m.Units[1].Readme, m.Units[0].LocalizedReadmes[0]}
+
+	rs, err := testDB.GetModuleReadmesToRender(ctx, "mod.com", "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, readmes(rs)); diff != "" {
+		t.Errorf("GetModuleReadmesToRender mismatch (-want, +got):\n%s", diff)
+	}
+
+	rs, last, err := testDB.GetReadmesToRender(ctx, 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*internal.Readme{want[0], want[2]}, readmes(rs)); diff != "" {
+		t.Errorf("GetReadmesToRender mismatch (-want, +got):\n%s", diff)
+	}
+	rs, last, err = testDB.GetReadmesToRender(ctx, last, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]*internal.Readme{want[1]}, readmes(rs)); diff != "" {
+		t.Errorf("GetReadmesToRender mismatch (-want, +got):\n%s", diff)
+	}
+	if rs, _, err = testDB.GetReadmesToRender(ctx, last, 10); err != nil || len(rs) != 0 {
+		t.Errorf("after unit %d: got %d READMEs, %v; want none", last, len(rs), err)
+	}
+
+	if _, err := testDB.GetRenderedReadme(ctx, "h1"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("got error %v, want NotFound", err)
+	}
+	moduleID := rs[0].ModuleID
+	if err := testDB.InsertRenderedReadmes(ctx, []*RenderedReadme{{Hash: "h1", ModuleID: moduleID, Rendered: []byte(`{"HTML": "1"}`)}}); err != nil {
+		t.Fatal(err)
+	}
+	// Inserting a rendering again leaves it alone.
+	if err := testDB.InsertRenderedReadmes(ctx, []*RenderedReadme{{Hash: "h1", ModuleID: moduleID, Rendered: []byte(`{"HTML": "2"}`)}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := testDB.GetRenderedReadme(ctx, "h1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"HTML": "1"}`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// Renderings are deleted along with their module version.
+	if err := testDB.DeleteModule(ctx, "mod.com", "v1.2.3"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testDB.GetRenderedReadme(ctx, "h1"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("after DeleteModule: got error %v, want NotFound", err)
+	}
+}
+
+func TestRenderedReadmesTakedown(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.Module("mod.com", "v1.2.3", "")
+	m.Units[0].Readme = &internal.Readme{Filepath: "README.md", Contents: "# Module"}
+	MustInsertModule(ctx, t, testDB, m)
+	rs, err := testDB.GetModuleReadmesToRender(ctx, "mod.com", "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 {
+		t.Fatalf("got %d READMEs, want 1", len(rs))
+	}
+	if err := testDB.InsertRenderedReadmes(ctx, []*RenderedReadme{{Hash: "h1", ModuleID: rs[0].ModuleID, Rendered: []byte(`{}`)}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := testDB.CreateTakedown(ctx, "mod.com", "v1.2.3", "alice", "ticket", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testDB.GetRenderedReadme(ctx, "h1"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("after takedown: got error %v, want NotFound", err)
+	}
+	// The READMEs of a module version that is taken down are not rendered
+	// again.
+	if rs, err := testDB.GetModuleReadmesToRender(ctx, "mod.com", "v1.2.3"); err != nil || len(rs) != 0 {
+		t.Errorf("GetModuleReadmesToRender after takedown: got %d READMEs, %v; want none", len(rs), err)
+	}
+	if rs, _, err := testDB.GetReadmesToRender(ctx, 0, 10); err != nil || len(rs) != 0 {
+		t.Errorf("GetReadmesToRender after takedown: got %d READMEs, %v; want none", len(rs), err)
+	}
+}
+
+func TestReadmesToRenderDocsPages(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	// Unit A has docs pages but no README.
+	m := sample.Module("mod.com", "v1.2.3", "", "A")
+	m.Units[0].Readme = &internal.Readme{Filepath: "README.md", Contents: "# Module"}
+	m.Units[1].Readme = nil
+	m.Units[1].DocsPages = []*internal.Readme{
+		{Filepath: "A/docs/guide.md", Contents: "# Guide"},
+		{Filepath: "A/docs/ref/api.md", Contents: "# API"},
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	rs, err := testDB.GetModuleReadmesToRender(ctx, "mod.com", "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []*internal.Readme
+	for _, r := range rs {
+		got = append(got, r.Readme)
+	}
+	want := []*internal.Readme{m.Units[0].Readme, m.Units[1].DocsPages[0], m.Units[1].DocsPages[1]}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("GetModuleReadmesToRender mismatch (-want, +got):\n%s", diff)
+	}
+	if rs[0].DocsPages != nil {
+		t.Errorf("README: got docs pages %v, want none", rs[0].DocsPages)
+	}
+	wantPages := map[string]string{
+		"A/docs/guide.md":   "?tab=docs&page=guide.md",
+		"A/docs/ref/api.md": "?tab=docs&page=ref%2Fapi.md",
+	}
+	for _, r := range rs[1:] {
+		if diff := cmp.Diff(wantPages, r.DocsPages); diff != "" {
+			t.Errorf("%s: docs pages mismatch (-want, +got):\n%s", r.Readme.Filepath, diff)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/stdlib"
+)
+
+// GetReleaseNoteAnchor returns the anchor of the section about the package
+// at pkgPath in the release notes of the Go release goVersion, like "go1.16",
+// or the empty string if there is none.
+func (db *DB) GetReleaseNoteAnchor(ctx context.Context, goVersion, pkgPath string) (_ string, err error) {
+	defer derrors.WrapStack(&err, "GetReleaseNoteAnchor(ctx, %q, %q)", goVersion, pkgPath)
+
+	var anchor string
+	err = db.db.QueryRow(ctx, `
+		SELECT anchor
+		FROM go_release_note_sections
+		WHERE go_version = $1 AND package_path = $2`,
+		goVersion, pkgPath).Scan(&anchor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return anchor, nil
+}
+
+// insertReleaseNoteSections records the sections of Go release notes, replacing
+// those recorded for the same releases and packages.
+func insertReleaseNoteSections(ctx context.Context, db *database.DB, sections []*stdlib.ReleaseNoteSection) (err error) {
+	defer derrors.WrapStack(&err, "insertReleaseNoteSections(ctx, %d sections)", len(sections))
+
+	if len(sections) == 0 {
+		return nil
+	}
+	var values []any
+	for _, s := range sections {
+		values = append(values, s.GoVersion, s.PackagePath, s.Anchor)
+	}
+	return db.BulkInsert(ctx, "go_release_note_sections",
+		[]string{"go_version", "package_path", "anchor"}, values,
+		`ON CONFLICT (go_version, package_path) DO UPDATE SET anchor = excluded.anchor`)
+}
@@ -0,0 +1,48 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/stdlib"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetReleaseNoteAnchor(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.Module(stdlib.ModulePath, "v1.14.6", "encoding/json")
+	m.ReleaseNoteSections = []*stdlib.ReleaseNoteSection{
+		{GoVersion: "go1.14", PackagePath: "encoding/json", Anchor: "encoding_json"},
+	}
+	MustInsertModule(ctx, t, testDB, m)
+	// Reprocessing replaces the anchor.
+	m = sample.Module(stdlib.ModulePath, "v1.14.7", "encoding/json")
+	m.ReleaseNoteSections = []*stdlib.ReleaseNoteSection{
+		{GoVersion: "go1.14", PackagePath: "encoding/json", Anchor: "encoding/json"},
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	for _, test := range []struct {
+		goVersion, pkgPath, want string
+	}{
+		{"go1.14", "encoding/json", "encoding/json"},
+		{"go1.14", "net/http", ""},
+		{"go1.13", "encoding/json", ""},
+	} {
+		got, err := testDB.GetReleaseNoteAnchor(ctx, test.goVersion, test.pkgPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("GetReleaseNoteAnchor(%q, %q) = %q, want %q", test.goVersion, test.pkgPath, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/source"
+)
+
+// GetRepoStats returns the most recently fetched statistics for the
+// repository at repoURL. It returns an error wrapping derrors.NotFound if there
+// are no statistics for the repository, or if its host did not know about it.
+func (db *DB) GetRepoStats(ctx context.Context, repoURL string) (_ *source.RepoStats, err error) {
+	defer derrors.WrapStack(&err, "GetRepoStats(ctx, %q)", repoURL)
+
+	rs := &source.RepoStats{RepoURL: repoURL}
+	var lastCommit *time.Time
+	err = db.db.QueryRow(ctx, `
+		SELECT stars, open_issues, last_commit_at, fetched_at
+		FROM repo_stats
+		WHERE repo_url = $1 AND status = $2`,
+		repoURL, http.StatusOK).Scan(&rs.Stars, &rs.OpenIssues, database.NullPtr(&lastCommit), &rs.FetchedAt)
+	switch err {
+	case nil:
+		if lastCommit != nil {
+			rs.LastCommitTime = *lastCommit
+		}
+		return rs, nil
+	case sql.ErrNoRows:
+		return nil, derrors.NotFound
+	default:
+		return nil, err
+	}
+}
+
+// UpsertRepoStats inserts or updates the statistics for rs.RepoURL.
+func (db *DB) UpsertRepoStats(ctx context.Context, rs *source.RepoStats) (err error) {
+	defer derrors.WrapStack(&err, "UpsertRepoStats(ctx, %q)", rs.RepoURL)
+
+	var lastCommit *time.Time
+	if !rs.LastCommitTime.IsZero() {
+		lastCommit = &rs.LastCommitTime
+	}
+	_, err = db.db.Exec(ctx, `
+		INSERT INTO repo_stats (repo_url, status, stars, open_issues, last_commit_at, fetched_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (repo_url) DO UPDATE SET
+			status=excluded.status,
+			stars=excluded.stars,
+			open_issues=excluded.open_issues,
+			last_commit_at=excluded.last_commit_at,
+			fetched_at=excluded.fetched_at`,
+		rs.RepoURL, http.StatusOK, rs.Stars, rs.OpenIssues, lastCommit, rs.FetchedAt)
+	return err
+}
+
+// UpsertRepoStatsNotFound records that the host of repoURL does not know
+// about the repository, so that it is not fetched again until the TTL has
+// passed.
+func (db *DB) UpsertRepoStatsNotFound(ctx context.Context, repoURL string) (err error) {
+	defer derrors.WrapStack(&err, "UpsertRepoStatsNotFound(ctx, %q)", repoURL)
+
+	_, err = db.db.Exec(ctx, `
+		INSERT INTO repo_stats (repo_url, status)
+		VALUES ($1, $2)
+		ON CONFLICT (repo_url) DO UPDATE SET
+			status=excluded.status,
+			stars=0,
+			open_issues=0,
+			last_commit_at=NULL,
+			fetched_at=CURRENT_TIMESTAMP`,
+		repoURL, http.StatusNotFound)
+	return err
+}
+
+// GetRepoURLsForStatsUpdate returns up to limit repository URLs of the latest
+// versions of modules that are hosted on one of hosts, and whose statistics
+// have never been fetched or were fetched before the given time.
+func (db *DB) GetRepoURLsForStatsUpdate(ctx context.Context, hosts []string, fetchedBefore time.Time, limit int) (_ []string, err error) {
+	defer derrors.WrapStack(&err, "GetRepoURLsForStatsUpdate(ctx, %v, %s, %d)", hosts, fetchedBefore, limit)
+
+	if len(hosts) == 0 {
+		return nil, nil
+	}
+	var prefixes []string
+	for _, h := range hosts {
+		prefixes = append(prefixes, "https://"+h+"/%")
+	}
+	sort.Strings(prefixes)
+	query := `
+		SELECT DISTINCT m.source_info->>'RepoURL'
+		FROM latest_module_versions l
+		INNER JOIN paths p ON p.id = l.module_path_id
+		INNER JOIN modules m ON m.module_path = p.path AND m.version = l.good_version
+		LEFT JOIN repo_stats r ON r.repo_url = m.source_info->>'RepoURL'
+		WHERE l.good_version != ''
+		AND m.source_info->>'RepoURL' LIKE ANY($1)
+		AND (r.fetched_at IS NULL OR r.fetched_at < $2)
+		LIMIT $3`
+	return database.Collect1[string](ctx, db.db, query, pq.Array(prefixes), fetchedBefore, limit)
+}
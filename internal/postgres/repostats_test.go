@@ -0,0 +1,74 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestRepoStats(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	for _, m := range []string{"github.com/a/b", "github.com/c/d", "gitlab.com/e/f", "example.org/g"} {
+		MustInsertModule(ctx, t, testDB, sample.Module(m, sample.VersionString, ""))
+	}
+	hosts := []string{"github.com", "gitlab.com"}
+	now := sample.NowTruncated()
+
+	got, err := testDB.GetRepoURLsForStatsUpdate(ctx, hosts, now, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"https://github.com/a/b", "https://github.com/c/d", "https://gitlab.com/e/f"}
+	if diff := cmp.Diff(want, got, cmpopts.SortSlices(func(a, b string) bool { return a < b })); diff != "" {
+		t.Errorf("GetRepoURLsForStatsUpdate mismatch (-want, +got):\n%s", diff)
+	}
+
+	rs := &source.RepoStats{
+		RepoURL:        "https://github.com/a/b",
+		Stars:          5,
+		OpenIssues:     2,
+		LastCommitTime: now.Add(-time.Hour),
+		FetchedAt:      now,
+	}
+	if err := testDB.UpsertRepoStats(ctx, rs); err != nil {
+		t.Fatal(err)
+	}
+	if err := testDB.UpsertRepoStatsNotFound(ctx, "https://github.com/c/d"); err != nil {
+		t.Fatal(err)
+	}
+	gotStats, err := testDB.GetRepoStats(ctx, rs.RepoURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(rs, gotStats); diff != "" {
+		t.Errorf("GetRepoStats mismatch (-want, +got):\n%s", diff)
+	}
+	if _, err := testDB.GetRepoStats(ctx, "https://github.com/c/d"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("GetRepoStats for unknown repo: got %v, want NotFound", err)
+	}
+
+	// Only the repo that has never been fetched needs an update.
+	got, err = testDB.GetRepoURLsForStatsUpdate(ctx, hosts, now.Add(-time.Minute), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"https://gitlab.com/e/f"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetRepoURLsForStatsUpdate after fetch mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -10,7 +10,9 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
+	"github.com/lib/pq"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/config/serverconfig"
 	"golang.org/x/pkgsite/internal/derrors"
@@ -133,7 +135,8 @@ func (db *DB) UpdateModuleVersionStatesWithStatus(ctx context.Context, status in
 			SET
 				status = $2,
 				next_processed_after = CURRENT_TIMESTAMP,
-				last_processed_at = NULL
+				last_processed_at = NULL,
+				retry_attempts = 0
 			WHERE
 				app_version < $1
 				AND status = $3;`
@@ -158,6 +161,26 @@ const largeModulePackageThreshold = 1500
 // var for testing.
 var largeModulesLimit = serverconfig.GetEnvInt(context.Background(), "GO_DISCOVERY_LARGE_MODULES_LIMIT", 100)
 
+// DeferModuleVersions keeps the module versions mvs out of the results of
+// GetNextModulesToFetch for the duration d, by moving their
+// next_processed_after forward. It returns the number of module versions
+// updated.
+func (db *DB) DeferModuleVersions(ctx context.Context, mvs []internal.Modver, d time.Duration) (_ int64, err error) {
+	defer derrors.WrapStack(&err, "DeferModuleVersions(ctx, %d module versions, %s)", len(mvs), d)
+
+	var paths, versions []string
+	for _, mv := range mvs {
+		paths = append(paths, mv.Path)
+		versions = append(versions, mv.Version)
+	}
+	return db.db.Exec(ctx, `
+		UPDATE module_version_states s
+		SET next_processed_after = CURRENT_TIMESTAMP + make_interval(secs => $3)
+		FROM unnest($1::TEXT[], $2::TEXT[]) AS t(module_path, version)
+		WHERE s.module_path = t.module_path AND s.version = t.version`,
+		pq.Array(paths), pq.Array(versions), d.Seconds())
+}
+
 // GetNextModulesToFetch returns the next batch of modules that need to be
 // processed. We prioritize modules based on (1) whether it has status zero
 // (never processed), (2) whether it is the latest version, (3) if it is an
@@ -165,6 +188,8 @@ var largeModulesLimit = serverconfig.GetEnvInt(context.Background(), "GO_DISCOVE
 // time-consuming modules until the end and process them at a slower rate to
 // reduce database load and timeouts. We also want to leave alternative modules
 // towards the end, since these will incur unnecessary deletes otherwise.
+// Versions that have failed as many times in a row as their
+// max_retry_attempts are left out.
 func (db *DB) GetNextModulesToFetch(ctx context.Context, limit int) (_ []*internal.ModuleVersionState, err error) {
 	defer derrors.WrapStack(&err, "GetNextModulesToFetch(ctx, %d)", limit)
 	queryFmt := nextModulesToProcessQuery
@@ -236,7 +261,8 @@ const nextModulesToProcessQuery = `
 		FROM module_version_states
 	) s
 	WHERE next_processed_after < CURRENT_TIMESTAMP
-		AND (status = 0 OR status >= 500)
+		AND (status = 0 OR (status >= 500
+			AND (max_retry_attempts IS NULL OR retry_attempts < max_retry_attempts)))
 	ORDER BY
 		CASE
 			-- new modules
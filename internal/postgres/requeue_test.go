@@ -290,3 +290,39 @@ func compareModules(t *testing.T, got, want []*internal.ModuleVersionState) {
 		t.Fatalf("mismatch (-want, +got):\n%s", diff)
 	}
 }
+
+func TestDeferModuleVersions(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	var ivs []*internal.IndexVersion
+	for _, p := range []string{"example.com/a", "example.com/b"} {
+		ivs = append(ivs, &internal.IndexVersion{Path: p, Version: "v1.0.0", Timestamp: time.Now().Add(-time.Hour)})
+	}
+	if err := testDB.InsertIndexVersions(ctx, ivs); err != nil {
+		t.Fatal(err)
+	}
+	n, err := testDB.DeferModuleVersions(ctx, []internal.Modver{
+		{Path: "example.com/a", Version: "v1.0.0"},
+		{Path: "example.com/none", Version: "v1.0.0"},
+	}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("deferred %d module versions, want 1", n)
+	}
+	mvs, err := testDB.GetNextModulesToFetch(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, mv := range mvs {
+		got = append(got, mv.ModulePath)
+	}
+	if want := []string{"example.com/b"}; !cmp.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware/stats"
+)
+
+// topLevelLicenseTypesExpr is an SQL expression for the sorted, distinct
+// types of the top-level licenses of the module with ID %s.
+const topLevelLicenseTypesExpr = `ARRAY(
+	SELECT DISTINCT t
+	FROM licenses l, unnest(l.types) t
+	WHERE l.module_id = %s AND position('/' in l.file_path) = 0
+	ORDER BY t)`
+
+// GetModuleDependencies returns the module version modulePath@version and the
+// modules required by its go.mod file, with the types of their top-level
+// licenses. It returns an error wrapping derrors.NotFound if the module
+// version is not in the database.
+func (db *DB) GetModuleDependencies(ctx context.Context, modulePath, version string) (_ *internal.ModuleDependencies, err error) {
+	defer derrors.WrapStack(&err, "GetModuleDependencies(ctx, %q, %q)", modulePath, version)
+	defer stats.Elapsed(ctx, "GetModuleDependencies")()
+
+	md := &internal.ModuleDependencies{ModulePath: modulePath, Version: version}
+	var moduleID int
+	err = db.db.QueryRow(ctx, `
+		SELECT m.id, `+fmt.Sprintf(topLevelLicenseTypesExpr, "m.id")+`
+		FROM modules m
+		WHERE m.module_path = $1 AND m.version = $2`,
+		modulePath, version).Scan(&moduleID, pq.Array(&md.LicenseTypes))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, derrors.NotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT r.module_path, r.version, r.indirect,
+			COALESCE((
+				SELECT ` + fmt.Sprintf(topLevelLicenseTypesExpr, "rm.id") + `
+				FROM modules rm
+				WHERE rm.module_path = r.module_path AND rm.version = r.version
+			), '{}')
+		FROM module_requirements r
+		WHERE r.module_id = $1
+		ORDER BY r.module_path`
+	collect := func(rows *sql.Rows) error {
+		var rm internal.RequiredModule
+		if err := rows.Scan(&rm.ModulePath, &rm.Version, &rm.Indirect, pq.Array(&rm.LicenseTypes)); err != nil {
+			return err
+		}
+		md.Requirements = append(md.Requirements, &rm)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, moduleID); err != nil {
+		return nil, err
+	}
+	return md, nil
+}
+
+// GetModuleRequirements returns the modules required by the go.mod files of
+// the module versions mvs, in one query. Module versions that are not in the
+// database are not in the map; those that require nothing map to nil.
+func (db *DB) GetModuleRequirements(ctx context.Context, mvs []internal.Modver) (_ map[internal.Modver][]*internal.ModuleRequirement, err error) {
+	defer derrors.WrapStack(&err, "GetModuleRequirements(ctx, %d module versions)", len(mvs))
+	defer stats.Elapsed(ctx, "GetModuleRequirements")()
+
+	var paths, versions []string
+	for _, mv := range mvs {
+		paths = append(paths, mv.Path)
+		versions = append(versions, mv.Version)
+	}
+	query := `
+		SELECT m.module_path, m.version, r.module_path, r.version, r.indirect
+		FROM unnest($1::TEXT[], $2::TEXT[]) AS t(module_path, version)
+		INNER JOIN modules m
+		ON m.module_path = t.module_path AND m.version = t.version
+		LEFT JOIN module_requirements r
+		ON r.module_id = m.id
+		ORDER BY m.module_path, m.version, r.module_path`
+	reqs := map[internal.Modver][]*internal.ModuleRequirement{}
+	collect := func(rows *sql.Rows) error {
+		var (
+			mv                  internal.Modver
+			reqPath, reqVersion sql.NullString
+			indirect            sql.NullBool
+		)
+		if err := rows.Scan(&mv.Path, &mv.Version, &reqPath, &reqVersion, &indirect); err != nil {
+			return err
+		}
+		if !reqPath.Valid {
+			// A module version without requirements.
+			reqs[mv] = nil
+			return nil
+		}
+		reqs[mv] = append(reqs[mv], &internal.ModuleRequirement{
+			ModulePath: reqPath.String,
+			Version:    reqVersion.String,
+			Indirect:   indirect.Bool,
+		})
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, pq.Array(paths), pq.Array(versions)); err != nil {
+		return nil, err
+	}
+	return reqs, nil
+}
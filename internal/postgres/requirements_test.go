@@ -0,0 +1,107 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetModuleDependencies(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	dep := sample.Module("example.com/dep", "v1.2.3", sample.Suffix)
+	MustInsertModule(ctx, t, testDB, dep)
+
+	m := sample.Module("example.com/mod", "v1.0.0", sample.Suffix)
+	m.Requirements = []*internal.ModuleRequirement{
+		{ModulePath: "example.com/unknown", Version: "v0.1.0", Indirect: true},
+		{ModulePath: "example.com/dep", Version: "v1.2.3"},
+		{ModulePath: "example.com/dep", Version: "v1.0.0"},
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	got, err := testDB.GetModuleDependencies(ctx, m.ModulePath, m.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &internal.ModuleDependencies{
+		ModulePath:   "example.com/mod",
+		Version:      "v1.0.0",
+		LicenseTypes: []string{sample.LicenseType},
+		Requirements: []*internal.RequiredModule{
+			{
+				ModuleRequirement: internal.ModuleRequirement{ModulePath: "example.com/dep", Version: "v1.2.3"},
+				LicenseTypes:      []string{sample.LicenseType},
+			},
+			{
+				ModuleRequirement: internal.ModuleRequirement{ModulePath: "example.com/unknown", Version: "v0.1.0", Indirect: true},
+				LicenseTypes:      []string{},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	// Reinserting the module replaces its requirements.
+	m.Requirements = nil
+	MustInsertModule(ctx, t, testDB, m)
+	got, err = testDB.GetModuleDependencies(ctx, m.ModulePath, m.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Requirements) != 0 {
+		t.Errorf("got %d requirements after reinsertion, want 0", len(got.Requirements))
+	}
+
+	if _, err := testDB.GetModuleDependencies(ctx, "example.com/none", "v1.0.0"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("got %v, want NotFound", err)
+	}
+}
+
+func TestGetModuleRequirements(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	dep := sample.Module("example.com/dep", "v1.2.3", sample.Suffix)
+	MustInsertModule(ctx, t, testDB, dep)
+	m := sample.Module("example.com/mod", "v1.0.0", sample.Suffix)
+	m.Requirements = []*internal.ModuleRequirement{
+		{ModulePath: "example.com/unknown", Version: "v0.1.0", Indirect: true},
+		{ModulePath: "example.com/dep", Version: "v1.2.3"},
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	got, err := testDB.GetModuleRequirements(ctx, []internal.Modver{
+		{Path: "example.com/mod", Version: "v1.0.0"},
+		{Path: "example.com/dep", Version: "v1.2.3"},
+		{Path: "example.com/unknown", Version: "v0.1.0"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[internal.Modver][]*internal.ModuleRequirement{
+		{Path: "example.com/mod", Version: "v1.0.0"}: {
+			{ModulePath: "example.com/dep", Version: "v1.2.3"},
+			{ModulePath: "example.com/unknown", Version: "v0.1.0", Indirect: true},
+		},
+		{Path: "example.com/dep", Version: "v1.2.3"}: nil,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
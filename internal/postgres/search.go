@@ -22,6 +22,7 @@ import (
 	"golang.org/x/pkgsite/internal/database"
 	"golang.org/x/pkgsite/internal/dcensus"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/godoc"
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/postgres/search"
 	"golang.org/x/pkgsite/internal/stdlib"
@@ -90,6 +91,11 @@ var pkgSearchers = map[string]searcher{
 	"deep":    (*DB).deepSearch,
 }
 
+// The searchers used by Search for a package search with filters.
+var filteredPkgSearchers = map[string]searcher{
+	"deep": (*DB).deepSearch,
+}
+
 var symbolSearchers = map[string]searcher{
 	"symbol": (*DB).symbolSearch,
 }
@@ -124,8 +130,22 @@ func (db *DB) SearchSupport() internal.SearchSupport { return internal.FullSearc
 // The gap in this optimization is search terms that are very frequent, but
 // rarely relevant: "int" or "package", for example. In these cases we'll pay
 // the penalty of a deep search that scans nearly every package.
+//
+// If db caches popular searches (see CachePopularSearches), the results of
+// the most popular pages of search results are served from the cache.
 func (db *DB) Search(ctx context.Context, q string, opts SearchOptions) (_ []*SearchResult, err error) {
 	defer derrors.WrapStack(&err, "DB.Search(ctx, %q, %+v)", q, opts)
+	if results := db.searchPopular(ctx, q, opts); results != nil {
+		return results, nil
+	}
+	return db.searchUncached(ctx, q, opts)
+}
+
+// searchUncached runs the search for q with opts.
+func (db *DB) searchUncached(ctx context.Context, q string, opts SearchOptions) (_ []*SearchResult, err error) {
+	if opts.ModulePath != "" {
+		return db.searchModuleVersion(ctx, q, opts)
+	}
 	if !opts.SearchSymbols {
 		const (
 			limitMultiplier1 = 3
@@ -152,9 +172,13 @@ func (db *DB) search(ctx context.Context, q string, opts SearchOptions, limit in
 	defer derrors.WrapStack(&err, "search(limit=%d)", limit)
 
 	var searchers map[string]searcher
-	if opts.SearchSymbols {
+	switch {
+	case opts.SearchSymbols:
 		searchers = symbolSearchers
-	} else {
+	case !opts.Filters.IsZero():
+		// Popular search can't apply filters.
+		searchers = filteredPkgSearchers
+	default:
 		searchers = pkgSearchers
 	}
 	resp, err := db.hedgedSearch(ctx, q, limit, opts, searchers, nil)
@@ -170,6 +194,14 @@ func (db *DB) search(ctx context.Context, q string, opts SearchOptions, limit in
 	}
 	if !opts.SearchSymbols {
 		results = groupSearchResults(results)
+		// Duplicate detection only improves the presentation of results, so
+		// don't fail the search if it fails.
+		groups, err := db.getDuplicateGroups(ctx, results)
+		if err != nil {
+			log.Errorf(ctx, "%v", err)
+		} else {
+			results = collapseDuplicates(results, groups)
+		}
 	}
 	if len(results) > opts.MaxResults {
 		results = results[:opts.MaxResults]
@@ -271,27 +303,8 @@ const hllRegisterCount = 128
 // deepSearch searches all packages for the query. It is slower, but results
 // are always valid.
 func (db *DB) deepSearch(ctx context.Context, q string, limit int, opts SearchOptions) searchResponse {
-	query := fmt.Sprintf(`
-		SELECT *, COUNT(*) OVER() AS total
-		FROM (
-			SELECT
-				package_path,
-				version,
-				module_path,
-				commit_time,
-				imported_by_count,
-				(%s) AS score
-				FROM
-					search_documents
-				WHERE tsv_search_tokens @@ websearch_to_tsquery($1)
-				ORDER BY
-					score DESC,
-					commit_time DESC,
-					package_path
-		) r
-		WHERE r.score > 0.1
-		LIMIT $2
-		OFFSET $3`, scoreExpr)
+	filter, filterArgs := searchFilterClause(opts.Filters, 4)
+	query := deepSearchQuery(filter)
 
 	var results []*SearchResult
 	collect := func(rows *sql.Rows) error {
@@ -303,7 +316,8 @@ func (db *DB) deepSearch(ctx context.Context, q string, limit int, opts SearchOp
 		results = append(results, &r)
 		return nil
 	}
-	err := db.db.RunQuery(ctx, query, collect, q, limit, opts.Offset)
+	args := append([]any{q, limit, opts.Offset}, filterArgs...)
+	err := db.db.RunQuery(ctx, query, collect, args...)
 	if err != nil {
 		results = nil
 	}
@@ -322,6 +336,33 @@ func (db *DB) deepSearch(ctx context.Context, q string, limit int, opts SearchOp
 	}
 }
 
+// deepSearchQuery returns the query run by deepSearch, with filter appended
+// to its WHERE clause. Its arguments are the query, limit and offset, followed
+// by those of filter.
+func deepSearchQuery(filter string) string {
+	return fmt.Sprintf(`
+		SELECT *, COUNT(*) OVER() AS total
+		FROM (
+			SELECT
+				package_path,
+				version,
+				module_path,
+				commit_time,
+				imported_by_count,
+				(%s) AS score
+				FROM
+					search_documents
+				WHERE tsv_search_tokens @@ websearch_to_tsquery($1)%s
+				ORDER BY
+					score DESC,
+					commit_time DESC,
+					package_path
+		) r
+		WHERE r.score > 0.1
+		LIMIT $2
+		OFFSET $3`, scoreExpr, filter)
+}
+
 func (db *DB) popularSearch(ctx context.Context, searchQuery string, limit int, opts SearchOptions) searchResponse {
 	query := `
 		SELECT
@@ -519,6 +560,9 @@ func numRows(rs []*SearchResult) int {
 	n := 0
 	for _, r := range rs {
 		n += 1 + len(r.SameModule)
+		for _, s := range r.Similar {
+			n += 1 + len(s.SameModule)
+		}
 	}
 	return n
 }
@@ -545,7 +589,8 @@ var upsertSearchStatement = fmt.Sprintf(`
 		tsv_path_tokens,
 		tsv_search_tokens,
 		hll_register,
-		hll_leading_zeros
+		hll_leading_zeros,
+		simhash
 	)
 	SELECT
 		p1.path,
@@ -570,7 +615,8 @@ var upsertSearchStatement = fmt.Sprintf(`
 			SETWEIGHT(TO_TSVECTOR($7), 'D')
 		),
 		hll_hash(p1.path) & (%d - 1),
-		hll_zeros(hll_hash(p1.path))
+		hll_zeros(hll_hash(p1.path)),
+		$8
 	FROM units u
 	INNER JOIN modules m ON u.module_id = m.id
 	INNER JOIN paths p1 ON p1.id = u.path_id
@@ -597,6 +643,18 @@ var upsertSearchStatement = fmt.Sprintf(`
 		tsv_path_tokens=excluded.tsv_path_tokens,
 		tsv_search_tokens=excluded.tsv_search_tokens,
 		-- the hll fields are functions of path, so they don't change
+		-- $9 is false if the simhash wasn't computed; keep the old one.
+		simhash=(
+			CASE WHEN $9
+			THEN excluded.simhash
+			ELSE search_documents.simhash
+			END),
+		-- If the simhash changed, the duplicate group must be recomputed.
+		dup_group=(
+			CASE WHEN $9 AND excluded.simhash IS DISTINCT FROM search_documents.simhash
+			THEN NULL
+			ELSE search_documents.dup_group
+			END),
 		version_updated_at=(
 			CASE WHEN excluded.version = search_documents.version
 			THEN search_documents.version_updated_at
@@ -652,6 +710,7 @@ func upsertSearchDocuments(ctx context.Context, ddb *database.DB, mod *internal.
 			args.ReadmeFilePath = pkg.Readme.Filepath
 			args.ReadmeContents = pkg.Readme.Contents
 		}
+		args.Symbols = exportedSymbolNames(pkg)
 		if err := UpsertSearchDocument(ctx, ddb, args); err != nil {
 			return err
 		}
@@ -666,6 +725,10 @@ type UpsertSearchDocumentArgs struct {
 	Synopsis       string
 	ReadmeFilePath string
 	ReadmeContents string
+	// Symbols are the names of the package's exported symbols, used with the
+	// synopsis to detect near-duplicate packages. If nil, the package's
+	// existing simhash is left unchanged.
+	Symbols []string
 }
 
 // UpsertSearchDocument inserts a row in search_documents for the given package.
@@ -680,19 +743,103 @@ func UpsertSearchDocument(ctx context.Context, ddb *database.DB, args UpsertSear
 		args.ReadmeFilePath = ""
 		args.ReadmeContents = ""
 	}
+	commandDoc, err := getCommandDocText(ctx, ddb, args.PackagePath, args.ModulePath, args.Version)
+	if err != nil {
+		return err
+	}
 	pathTokens := strings.Join(GeneratePathTokens(args.PackagePath), " ")
-	sectionB, sectionC, sectionD := SearchDocumentSections(args.Synopsis, args.ReadmeFilePath, args.ReadmeContents)
-	_, err = ddb.Exec(ctx, upsertSearchStatement, args.PackagePath, args.ModulePath, args.Version, pathTokens, sectionB, sectionC, sectionD)
+	sectionB, sectionC, sectionD := SearchDocumentSections(args.Synopsis, args.ReadmeFilePath, args.ReadmeContents, commandDoc)
+	// Packages with few symbols are too easily mistaken for one another, so
+	// they get no simhash and are never treated as duplicates.
+	var sh *int64
+	if len(args.Symbols) >= minDuplicateSymbols {
+		h := int64(simhash(args.Synopsis, args.Symbols))
+		sh = &h
+	}
+	_, err = ddb.Exec(ctx, upsertSearchStatement, args.PackagePath, args.ModulePath, args.Version, pathTokens, sectionB, sectionC, sectionD,
+		sh, args.Symbols != nil)
 	return err
 }
 
-// GetPackagesForSearchDocumentUpsert fetches search information for packages in search_documents
-// whose update time is before the given time.
-func (db *DB) GetPackagesForSearchDocumentUpsert(ctx context.Context, before time.Time, limit int) (argsList []UpsertSearchDocumentArgs, err error) {
-	defer derrors.WrapStack(&err, "GetPackagesForSearchDocumentUpsert(ctx, %s, %d)", before, limit)
+// getCommandDocText returns the package documentation of the given package
+// as plain text, if the package is a redistributable command. Otherwise it
+// returns the empty string.
+func getCommandDocText(ctx context.Context, ddb *database.DB, packagePath, modulePath, version string) (_ string, err error) {
+	defer derrors.WrapStack(&err, "getCommandDocText(ctx, ddb, %q, %q, %q)", packagePath, modulePath, version)
 
-	query := `
+	var source []byte
+	err = ddb.QueryRow(ctx, `
+		SELECT d.source
+		FROM units u
+		INNER JOIN paths p ON p.id = u.path_id
+		INNER JOIN modules m ON m.id = u.module_id
+		INNER JOIN documentation d ON d.unit_id = u.id
+		WHERE
+			p.path = $1
+			AND m.module_path = $2
+			AND m.version = $3
+			AND u.name = 'main'
+			AND u.redistributable
+		ORDER BY d.goos, d.goarch
+		LIMIT 1`, packagePath, modulePath, version).Scan(&source)
+	switch {
+	case err == sql.ErrNoRows:
+		return "", nil
+	case err != nil:
+		return "", err
+	}
+	text, err := godoc.PackageDocText(source)
+	if err != nil {
+		// Index the command without its documentation, as if it had none.
+		log.Warningf(ctx, "%s@%s: %v", packagePath, version, err)
+		return "", nil
+	}
+	return text, nil
+}
+
+// RepopulateSearchDocuments upserts the search documents of up to limit
+// packages whose documents were last updated before the given time and whose
+// unit IDs are greater than afterUnitID, in order of unit ID, from the data
+// stored for them.
+//
+// It returns the largest unit ID seen, which can be passed as afterUnitID to
+// process the next batch, and the number of search documents upserted. When
+// there are no more, n is zero.
+func (db *DB) RepopulateSearchDocuments(ctx context.Context, before time.Time, afterUnitID, limit int) (lastUnitID, n int, err error) {
+	defer derrors.WrapStack(&err, "RepopulateSearchDocuments(ctx, %s, %d, %d)", before, afterUnitID, limit)
+	return db.upsertStoredSearchDocuments(ctx, "LEFT", "sd.updated_at < $3", afterUnitID, limit, before)
+}
+
+// upsertStoredSearchDocuments upserts the search documents selected by
+// getSearchDocumentArgs from the data stored for them, and returns the
+// largest unit ID seen and the number of documents upserted.
+func (db *DB) upsertStoredSearchDocuments(ctx context.Context, readmeJoin, cond string, afterUnitID, limit int, args ...any) (lastUnitID, n int, err error) {
+	argsList, lastUnitID, err := db.getSearchDocumentArgs(ctx, readmeJoin, cond, afterUnitID, limit, args...)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, args := range argsList {
+		// Symbols is nil, so the existing simhash is left alone.
+		if err := UpsertSearchDocument(ctx, db.db, args); err != nil {
+			return 0, 0, err
+		}
+	}
+	log.Infof(ctx, "upserted %d search documents with unit IDs in (%d, %d]", len(argsList), afterUnitID, lastUnitID)
+	return lastUnitID, len(argsList), nil
+}
+
+// getSearchDocumentArgs returns the arguments to UpsertSearchDocument for up
+// to limit packages in search_documents whose unit IDs are greater than
+// afterUnitID, in order of unit ID, and the largest unit ID seen.
+//
+// readmeJoin is the kind of join of the package's README, "LEFT" or "INNER",
+// and cond is an SQL condition on units u, paths p, modules m,
+// search_documents sd and readmes r that selects the packages. Its
+// parameters, args, are numbered from $3.
+func (db *DB) getSearchDocumentArgs(ctx context.Context, readmeJoin, cond string, afterUnitID, limit int, args ...any) (argsList []UpsertSearchDocumentArgs, lastUnitID int, err error) {
+	query := fmt.Sprintf(`
 		SELECT
+			u.id,
 			sd.package_path,
 			sd.module_path,
 			sd.version,
@@ -700,26 +847,27 @@ func (db *DB) GetPackagesForSearchDocumentUpsert(ctx context.Context, before tim
 			sd.redistributable,
 			r.file_path,
 			r.contents
-		FROM modules m
-		INNER JOIN units u
-		ON m.id = u.module_id
+		FROM units u
 		INNER JOIN paths p
 		ON p.id = u.path_id
-		LEFT JOIN readmes r
-		ON u.id = r.unit_id
+		INNER JOIN modules m
+		ON m.id = u.module_id
 		INNER JOIN search_documents sd
 		ON sd.package_path = p.path
 		    AND sd.module_path = m.module_path
 		    AND sd.version = m.version
-		WHERE sd.updated_at < $1
-		LIMIT $2`
+		%s JOIN readmes r
+		ON r.unit_id = u.id
+		WHERE u.id > $1 AND %s
+		ORDER BY u.id
+		LIMIT $2`, readmeJoin, cond)
 
 	collect := func(rows *sql.Rows) error {
 		var (
 			a      UpsertSearchDocumentArgs
 			redist bool
 		)
-		if err := rows.Scan(&a.PackagePath, &a.ModulePath, &a.Version, &a.Synopsis, &redist,
+		if err := rows.Scan(&lastUnitID, &a.PackagePath, &a.ModulePath, &a.Version, &a.Synopsis, &redist,
 			database.NullIsEmpty(&a.ReadmeFilePath), database.NullIsEmpty(&a.ReadmeContents)); err != nil {
 			return err
 		}
@@ -731,17 +879,19 @@ func (db *DB) GetPackagesForSearchDocumentUpsert(ctx context.Context, before tim
 		argsList = append(argsList, a)
 		return nil
 	}
-	if err := db.db.RunQuery(ctx, query, collect, before, limit); err != nil {
-		return nil, err
+	if err := db.db.RunQuery(ctx, query, collect, append([]any{afterUnitID, limit}, args...)...); err != nil {
+		return nil, 0, err
 	}
-	return argsList, nil
+	return argsList, lastUnitID, nil
 }
 
 // UpdateSearchDocumentsImportedByCount updates imported_by_count and
 // imported_by_count_updated_at.
 //
 // It does so by completely recalculating the imported-by counts
-// from the imports_unique table.
+// from the imports_unique table. The changes in imported_by_changes that were
+// recorded before it read that table are then reflected in the counts, so it
+// removes them once the counts are updated.
 //
 // UpdateSearchDocumentsImportedByCount returns the number of rows updated.
 func (db *DB) UpdateSearchDocumentsImportedByCount(ctx context.Context, batchSize int) (nUpdated int64, err error) {
@@ -749,6 +899,10 @@ func (db *DB) UpdateSearchDocumentsImportedByCount(ctx context.Context, batchSiz
 
 	log.Infof(ctx, "updating imported-by counts, batch size = %d", batchSize)
 
+	var lastChangeID int64
+	if err := db.db.QueryRow(ctx, `SELECT COALESCE(max(id), 0) FROM imported_by_changes`).Scan(&lastChangeID); err != nil {
+		return 0, err
+	}
 	curCounts, err := db.getSearchPackages(ctx)
 	if err != nil {
 		return 0, err
@@ -771,7 +925,20 @@ func (db *DB) UpdateSearchDocumentsImportedByCount(ctx context.Context, batchSiz
 		pct = len(changedCounts) * 100 / len(curCounts)
 	}
 	log.Debugf(ctx, "update-imported-by-counts: %d changed (%d%%)", len(changedCounts), pct)
-	return db.UpdateSearchDocumentsImportedByCountWithCounts(ctx, changedCounts, batchSize)
+	nUpdated, err = db.UpdateSearchDocumentsImportedByCountWithCounts(ctx, changedCounts, batchSize)
+	if err != nil {
+		return nUpdated, err
+	}
+	// Otherwise the changes would accumulate when the incremental update is
+	// not scheduled. A change that commits out of id order after the counts
+	// were computed may be removed without being counted; the next full
+	// update counts it.
+	n, err := db.db.Exec(ctx, `DELETE FROM imported_by_changes WHERE id <= $1`, lastChangeID)
+	if err != nil {
+		return nUpdated, err
+	}
+	log.Infof(ctx, "update-imported-by-counts: removed %d imported-by changes", n)
+	return nUpdated, nil
 }
 
 func (db *DB) UpdateSearchDocumentsImportedByCountWithCounts(ctx context.Context, counts map[string]int, batchSize int) (nUpdated int64, err error) {
@@ -841,13 +1008,9 @@ func (db *DB) computeImportedByCounts(ctx context.Context, curCounts map[string]
 		if _, ok := curCounts[from]; !ok {
 			return nil
 		}
-		// Don't count an importer if it's in the same module as what it's importing.
-		// Approximate that check by seeing if from_module_path is a prefix of to_path.
-		// (In some cases, e.g. when to_path is in a nested module, that is not correct.)
-		if (fromMod == stdlib.ModulePath && stdlib.Contains(to)) || strings.HasPrefix(to+"/", fromMod+"/") {
-			return nil
+		if isExternalImport(fromMod, to) {
+			newCounts[to]++
 		}
-		newCounts[to]++
 		return nil
 	})
 	if err != nil {
@@ -856,6 +1019,18 @@ func (db *DB) computeImportedByCounts(ctx context.Context, curCounts map[string]
 	return newCounts, nil
 }
 
+// isExternalImport reports whether an import of toPath by a package in the
+// module fromModulePath counts towards the imported-by count of toPath.
+// An importer doesn't count if it's in the same module as what it's importing.
+// Approximate that check by seeing if fromModulePath is a prefix of toPath.
+// (In some cases, e.g. when toPath is in a nested module, that is not correct.)
+func isExternalImport(fromModulePath, toPath string) bool {
+	if fromModulePath == stdlib.ModulePath && stdlib.Contains(toPath) {
+		return false
+	}
+	return !strings.HasPrefix(toPath+"/", fromModulePath+"/")
+}
+
 // insertImportedByCounts creates a temporary table and inserts at most limit
 // rows into it, where each row is a key and value from the counts map. The
 // inserted keys are deleted from counts.
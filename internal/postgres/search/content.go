@@ -26,10 +26,21 @@ package search
 
 // querySearchMultiWordExact is used when the search query is multiple elements.
 %s
+
+// querySearchFieldOrMethod is used when the search query is only one word,
+// with no dots. In this case, the word must match the name of a field or
+// method without its receiver.
+%s
+
+// querySearchMultiWordTokens is used when the search query is multiple
+// elements, some of which may be the words of a symbol name.
+%s
 `,
 	formatQuery("querySearchSymbol", SymbolQuery(SearchTypeSymbol)),
 	formatQuery("querySearchPackageDotSymbol", SymbolQuery(SearchTypePackageDotSymbol)),
-	formatQuery("querySearchMultiWordExact", SymbolQuery(SearchTypeMultiWordExact)))
+	formatQuery("querySearchMultiWordExact", SymbolQuery(SearchTypeMultiWordExact)),
+	formatQuery("querySearchFieldOrMethod", SymbolQuery(SearchTypeFieldOrMethod)),
+	formatQuery("querySearchMultiWordTokens", SymbolQuery(SearchTypeMultiWordTokens)))
 
 func formatQuery(name, query string) string {
 	return fmt.Sprintf("const %s = `%s`", name, query)
@@ -17,10 +17,13 @@ WITH ssd AS (
 		ssd.symbol_name_id,
 		ssd.goos,
 		ssd.goarch,
-		ssd.imported_by_count AS score
+		(
+			ln(exp(1) + ssd.imported_by_count)
+			* CASE WHEN ssd.symbol_name = $1 THEN 2 ELSE 1 END
+		) AS score
 	FROM symbol_search_documents ssd
 	WHERE 
-		lower(symbol_name) = lower($1)
+		lower(symbol_name COLLATE "und-x-icu") = lower($1::text COLLATE "und-x-icu")
 	ORDER BY
 		score DESC,
 		package_path
@@ -39,7 +42,8 @@ SELECT
 	ssd.goos,
 	ssd.goarch,
 	ps.type AS symbol_kind,
-	ps.synopsis AS symbol_synopsis
+	ps.synopsis AS symbol_synopsis,
+	ssd.score
 FROM ssd
 INNER JOIN symbol_names s ON s.id=ssd.symbol_name_id
 INNER JOIN search_documents sd ON sd.unit_id = ssd.unit_id
@@ -57,10 +61,13 @@ WITH ssd AS (
 		ssd.symbol_name_id,
 		ssd.goos,
 		ssd.goarch,
-		ssd.imported_by_count AS score
+		(
+			ln(exp(1) + ssd.imported_by_count)
+			* CASE WHEN ssd.symbol_name = $1 THEN 2 ELSE 1 END
+		) AS score
 	FROM symbol_search_documents ssd
 	WHERE 
-		lower(symbol_name) = lower($1)
+		lower(symbol_name COLLATE "und-x-icu") = lower($1::text COLLATE "und-x-icu")
 		AND (
 			ssd.uuid_package_name=uuid_generate_v5(uuid_nil(), split_part($3, '.', 1)) OR
 			ssd.uuid_package_path=uuid_generate_v5(uuid_nil(), split_part($3, '.', 1))
@@ -83,7 +90,8 @@ SELECT
 	ssd.goos,
 	ssd.goarch,
 	ps.type AS symbol_kind,
-	ps.synopsis AS symbol_synopsis
+	ps.synopsis AS symbol_synopsis,
+	ssd.score
 FROM ssd
 INNER JOIN symbol_names s ON s.id=ssd.symbol_name_id
 INNER JOIN search_documents sd ON sd.unit_id = ssd.unit_id
@@ -105,11 +113,12 @@ WITH ssd AS (
 				sd.tsv_path_tokens,
 				to_tsquery('symbols', quote_literal(replace($3, '_', '-')))
 			) * sd.ln_imported_by_count
+			* CASE WHEN ssd.symbol_name = $1 THEN 2 ELSE 1 END
 		) AS score
 	FROM symbol_search_documents ssd
 	INNER JOIN search_documents sd ON sd.package_path_id = ssd.package_path_id
 	WHERE
-		lower(symbol_name) = lower($1)
+		lower(symbol_name COLLATE "und-x-icu") = lower($1::text COLLATE "und-x-icu")
 		AND sd.tsv_path_tokens @@ to_tsquery('symbols', quote_literal(replace($3, '_', '-')))
 	ORDER BY score DESC
 	LIMIT $2
@@ -127,7 +136,101 @@ SELECT
 	ssd.goos,
 	ssd.goarch,
 	ps.type AS symbol_kind,
-	ps.synopsis AS symbol_synopsis
+	ps.synopsis AS symbol_synopsis,
+	ssd.score
+FROM ssd
+INNER JOIN symbol_names s ON s.id=ssd.symbol_name_id
+INNER JOIN search_documents sd ON sd.unit_id = ssd.unit_id
+INNER JOIN package_symbols ps ON ps.id=ssd.package_symbol_id
+ORDER BY score DESC;`
+
+// querySearchFieldOrMethod is used when the search query is only one word,
+// with no dots. In this case, the word must match the name of a field or
+// method without its receiver.
+const querySearchFieldOrMethod = `
+WITH ssd AS (
+	SELECT
+		ssd.unit_id,
+		ssd.package_symbol_id,
+		ssd.symbol_name_id,
+		ssd.goos,
+		ssd.goarch,
+		(
+			ln(exp(1) + ssd.imported_by_count) * 0.25
+			* CASE WHEN split_part(ssd.symbol_name, '.', 2) = $1 THEN 2 ELSE 1 END
+		) AS score
+	FROM symbol_search_documents ssd
+	WHERE 
+		lower(split_part(symbol_name, '.', 2) COLLATE "und-x-icu") = lower($1::text COLLATE "und-x-icu")
+	ORDER BY
+		score DESC,
+		package_path
+	LIMIT $2
+)
+SELECT
+	s.name AS symbol_name,
+	sd.package_path,
+	sd.module_path,
+	sd.version,
+	sd.name,
+	sd.synopsis,
+	sd.license_types,
+	sd.commit_time,
+	sd.imported_by_count,
+	ssd.goos,
+	ssd.goarch,
+	ps.type AS symbol_kind,
+	ps.synopsis AS symbol_synopsis,
+	ssd.score
+FROM ssd
+INNER JOIN symbol_names s ON s.id=ssd.symbol_name_id
+INNER JOIN search_documents sd ON sd.unit_id = ssd.unit_id
+INNER JOIN package_symbols ps ON ps.id=ssd.package_symbol_id
+ORDER BY score DESC;`
+
+// querySearchMultiWordTokens is used when the search query is multiple
+// elements, some of which may be the words of a symbol name.
+const querySearchMultiWordTokens = `
+WITH ssd AS (
+	SELECT
+		ssd.unit_id,
+		ssd.package_symbol_id,
+		ssd.symbol_name_id,
+		ssd.goos,
+		ssd.goarch,
+		(
+			CASE WHEN $3 = '' THEN ln(exp(1) + ssd.imported_by_count)
+			ELSE ts_rank(
+				'{0.1, 0.2, 1.0, 1.0}',
+				sd.tsv_path_tokens,
+				to_tsquery('symbols', quote_literal(replace($3, '_', '-')))
+			) * sd.ln_imported_by_count
+			END
+			* CASE WHEN length(ssd.tsv_symbol_name_tokens) = $4 THEN 2 ELSE 1 END
+		) AS score
+	FROM symbol_search_documents ssd
+	INNER JOIN search_documents sd ON sd.package_path_id = ssd.package_path_id
+	WHERE
+		ssd.tsv_symbol_name_tokens @@ to_tsquery('symbols', $1)
+		AND ($3 = '' OR sd.tsv_path_tokens @@ to_tsquery('symbols', quote_literal(replace($3, '_', '-'))))
+	ORDER BY score DESC
+	LIMIT $2
+)
+SELECT
+	s.name AS symbol_name,
+	sd.package_path,
+	sd.module_path,
+	sd.version,
+	sd.name,
+	sd.synopsis,
+	sd.license_types,
+	sd.commit_time,
+	sd.imported_by_count,
+	ssd.goos,
+	ssd.goarch,
+	ps.type AS symbol_kind,
+	ps.synopsis AS symbol_synopsis,
+	ssd.score
 FROM ssd
 INNER JOIN symbol_names s ON s.id=ssd.symbol_name_id
 INNER JOIN search_documents sd ON sd.unit_id = ssd.unit_id
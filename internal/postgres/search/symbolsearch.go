@@ -15,29 +15,70 @@ import (
 // used for symbol search.
 const SymbolTextSearchConfiguration = "symbols"
 
+// The database is created with LC_CTYPE 'C', under which lower only changes
+// ASCII letters. Symbol names are compared after folding their case with an
+// ICU collation instead, so that non-ASCII names such as "Δέλτα" match
+// regardless of case. lowerSymbolName must match the expression indexed in
+// symbol_search_documents.
+const (
+	lowerSymbolName = `lower(symbol_name COLLATE "und-x-icu")`
+	lowerQuery      = `lower($1::text COLLATE "und-x-icu")`
+)
+
+// lowerFieldOrMethodName is the name of a field or method without its
+// receiver, folded in the same way as lowerSymbolName. It is empty for
+// symbols that are not fields or methods. It must match the expression indexed
+// in symbol_search_documents.
+const lowerFieldOrMethodName = `lower(split_part(symbol_name, '.', 2) COLLATE "und-x-icu")`
+
+// Symbol search results are scored by the popularity of their package, as
+// ln(e + imported_by_count), so that a package imported by many others ranks
+// above an obscure one without the counts completely dominating the other
+// factors below.
+const (
+	// exactMatchBoost multiplies the score of a symbol whose name matches the
+	// query exactly, including case.
+	exactMatchBoost = 2
+
+	// fieldOrMethodWeight multiplies the score of a field or method found by
+	// its name alone, so that even with exactMatchBoost it ranks below
+	// top-level symbols of the same name in similarly popular packages.
+	fieldOrMethodWeight = 0.25
+)
+
+// popularityScore is the score of a symbol search document based on the
+// popularity of its package.
+const popularityScore = `ln(exp(1) + ssd.imported_by_count)`
+
 // SymbolQuery returns a symbol search query to be used in internal/postgres.
 // Each query that is returned accepts the following args:
 // $1 = query
 // $2 = limit
-// $3 = only used by multi-word-exact for path tokens
+// $3 = only used by multi-word-exact and multi-word-tokens for path tokens
+// $4 = only used by multi-word-tokens for the number of words in $1
+//
+// For SearchTypeMultiWordTokens, $1 is the words of the symbol name, as
+// returned by SymbolTokens, joined by " & ", and $3 may be empty.
 func SymbolQuery(st SearchType) string {
 	switch st {
 	case SearchTypeMultiWordExact:
 		return fmt.Sprintf(baseQuery, multiwordCTE)
+	case SearchTypeMultiWordTokens:
+		return fmt.Sprintf(baseQuery, multiwordTokensCTE)
 	case SearchTypePackageDotSymbol:
 		// When $1 is either <package>.<symbol> OR
 		// <package>.<type>.<methodOrField>, only match on the exact
 		// symbol name.
-		return fmt.Sprintf(baseQuery, fmt.Sprintf(symbolCTE, filterPackageDotSymbol))
+		return fmt.Sprintf(baseQuery, fmt.Sprintf(symbolCTE, symbolScore, filterPackageDotSymbol))
 	case SearchTypeSymbol:
 		// When $1 is the full symbol name, either <symbol> or
 		// <type>.<methodOrField>, match on just the identifier name.
-		//
-		// Matching on just <field> and <method> is too slow at the moment (can
-		// take several seconds to return results), but we
-		// might want to add support for that later. For example, searching for
-		// "Begin" should return "DB.Begin".
-		return fmt.Sprintf(baseQuery, fmt.Sprintf(symbolCTE, filterSymbol))
+		return fmt.Sprintf(baseQuery, fmt.Sprintf(symbolCTE, symbolScore, filterSymbol))
+	case SearchTypeFieldOrMethod:
+		// When $1 is the name of a field or method without its receiver,
+		// match on the part of the symbol name after the dot. For example,
+		// searching for "Begin" returns "DB.Begin".
+		return fmt.Sprintf(baseQuery, fmt.Sprintf(symbolCTE, fieldOrMethodScore, filterFieldOrMethod))
 	}
 	return ""
 }
@@ -49,7 +90,7 @@ const symbolCTE = `
 		ssd.symbol_name_id,
 		ssd.goos,
 		ssd.goarch,
-		ssd.imported_by_count AS score
+		%s AS score
 	FROM symbol_search_documents ssd
 	WHERE %s
 	ORDER BY
@@ -58,19 +99,35 @@ const symbolCTE = `
 	LIMIT $2
 `
 
-const filterSymbol = `
-		lower(symbol_name) = lower($1)`
+// symbolScore is the score of a symbol whose full name matches $1.
+var symbolScore = fmt.Sprintf(`(
+			%s
+			* CASE WHEN ssd.symbol_name = $1 THEN %d ELSE 1 END
+		)`, popularityScore, exactMatchBoost)
+
+// fieldOrMethodScore is the score of a field or method whose name without
+// its receiver matches $1.
+var fieldOrMethodScore = fmt.Sprintf(`(
+			%s * %g
+			* CASE WHEN split_part(ssd.symbol_name, '.', 2) = $1 THEN %d ELSE 1 END
+		)`, popularityScore, fieldOrMethodWeight, exactMatchBoost)
+
+var filterSymbol = `
+		` + lowerSymbolName + ` = ` + lowerQuery
+
+var filterFieldOrMethod = `
+		` + lowerFieldOrMethodName + ` = ` + lowerQuery
 
 // TODO(golang/go#44142): Filtering on package path currently only works for
 // standard library packages, since non-standard library packages will have a
 // dot.
 var filterPackageDotSymbol = fmt.Sprintf(`
-		lower(symbol_name) = lower($1)
+		%[2]s = %[3]s
 		AND (
 			ssd.uuid_package_name=%[1]s OR
 			ssd.uuid_package_path=%[1]s
 		)`,
-	"uuid_generate_v5(uuid_nil(), split_part($3, '.', 1))")
+	"uuid_generate_v5(uuid_nil(), split_part($3, '.', 1))", lowerSymbolName, lowerQuery)
 
 var multiwordCTE = fmt.Sprintf(`
 	SELECT
@@ -85,15 +142,46 @@ var multiwordCTE = fmt.Sprintf(`
 				sd.tsv_path_tokens,
 				%[1]s
 			) * sd.ln_imported_by_count
+			* CASE WHEN ssd.symbol_name = $1 THEN %[4]d ELSE 1 END
 		) AS score
 	FROM symbol_search_documents ssd
 	INNER JOIN search_documents sd ON sd.package_path_id = ssd.package_path_id
 	WHERE
-		lower(symbol_name) = lower($1)
+		%[2]s = %[3]s
 		AND sd.tsv_path_tokens @@ %[1]s
 	ORDER BY score DESC
 	LIMIT $2
-`, toTSQuery("$3"))
+`, toTSQuery("$3"), lowerSymbolName, lowerQuery, exactMatchBoost)
+
+// multiwordTokensCTE matches symbols whose names contain all of the words in
+// $1, in packages whose path tokens match $3, if any. A symbol whose name has
+// no other words, like Client.Do for "client & do", is boosted above one
+// with more, like Client.DoRequest.
+var multiwordTokensCTE = fmt.Sprintf(`
+	SELECT
+		ssd.unit_id,
+		ssd.package_symbol_id,
+		ssd.symbol_name_id,
+		ssd.goos,
+		ssd.goarch,
+		(
+			CASE WHEN $3 = '' THEN %[2]s
+			ELSE ts_rank(
+				'{0.1, 0.2, 1.0, 1.0}',
+				sd.tsv_path_tokens,
+				%[1]s
+			) * sd.ln_imported_by_count
+			END
+			* CASE WHEN length(ssd.tsv_symbol_name_tokens) = $4 THEN %[3]d ELSE 1 END
+		) AS score
+	FROM symbol_search_documents ssd
+	INNER JOIN search_documents sd ON sd.package_path_id = ssd.package_path_id
+	WHERE
+		ssd.tsv_symbol_name_tokens @@ to_tsquery('%[4]s', $1)
+		AND ($3 = '' OR sd.tsv_path_tokens @@ %[1]s)
+	ORDER BY score DESC
+	LIMIT $2
+`, toTSQuery("$3"), popularityScore, exactMatchBoost, SymbolTextSearchConfiguration)
 
 const baseQuery = `
 WITH ssd AS (%s)
@@ -110,7 +198,8 @@ SELECT
 	ssd.goos,
 	ssd.goarch,
 	ps.type AS symbol_kind,
-	ps.synopsis AS symbol_synopsis
+	ps.synopsis AS symbol_synopsis,
+	ssd.score
 FROM ssd
 INNER JOIN symbol_names s ON s.id=ssd.symbol_name_id
 INNER JOIN search_documents sd ON sd.unit_id = ssd.unit_id
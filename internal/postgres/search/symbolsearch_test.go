@@ -26,6 +26,35 @@ func TestProcessArg(t *testing.T) {
 	}
 }
 
+func TestSymbolTokens(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want []string
+	}{
+		{"Client", []string{"client"}},
+		{"Client.Do", []string{"client", "do"}},
+		{"ReadAll", []string{"read", "all"}},
+		{"HTTPClient", []string{"http", "client"}},
+		{"ServeHTTP", []string{"serve", "http"}},
+		{"URLs", []string{"urls"}},
+		{"IDsFor", []string{"ids", "for"}},
+		{"Int64", []string{"int64"}},
+		{"Float64bits", []string{"float64bits"}},
+		{"Sha256Sum", []string{"sha256", "sum"}},
+		{"MAX_SIZE", []string{"max", "size"}},
+		{"errNotFound", []string{"err", "not", "found"}},
+		{"ΔέλταΈψιλον", []string{"δέλτα", "έψιλον"}},
+		{"http", []string{"http"}},
+		{"a-b", []string{"a", "b"}},
+		{"_", nil},
+	} {
+		got := SymbolTokens(test.in)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("SymbolTokens(%q) mismatch (-want, +got):\n%s", test.in, diff)
+		}
+	}
+}
+
 func TestParseInputType(t *testing.T) {
 	for _, test := range []struct {
 		name, q string
@@ -59,6 +88,8 @@ func TestGenerateQuery(t *testing.T) {
 		{"querySearchSymbol", SymbolQuery(SearchTypeSymbol), querySearchSymbol},
 		{"querySearchPackageDotSymbol", SymbolQuery(SearchTypePackageDotSymbol), querySearchPackageDotSymbol},
 		{"querySearchMultiWordExact", SymbolQuery(SearchTypeMultiWordExact), querySearchMultiWordExact},
+		{"querySearchFieldOrMethod", SymbolQuery(SearchTypeFieldOrMethod), querySearchFieldOrMethod},
+		{"querySearchMultiWordTokens", SymbolQuery(SearchTypeMultiWordTokens), querySearchMultiWordTokens},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			if diff := cmp.Diff(test.want, test.q); diff != "" {
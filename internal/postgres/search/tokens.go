@@ -0,0 +1,58 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SymbolTokens returns the words of a symbol name or search query word, in
+// lower case. Words are separated by any character that is not a letter or
+// digit, such as the dot in "Client.Do" or an underscore, and by camelCase
+// and acronym boundaries. Digits belong to the word before them. An acronym
+// followed by a lowercase "s" is taken as a plural, so "URLs" is one word.
+//
+// For example, the words of "HTTPClient.Do" are "http", "client" and "do",
+// and those of "Float64bits" are "float64bits".
+func SymbolTokens(name string) []string {
+	var (
+		tokens []string
+		cur    []rune
+	)
+	flush := func() {
+		if len(cur) > 0 {
+			tokens = append(tokens, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+	rs := []rune(name)
+	for i, r := range rs {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			flush()
+			continue
+		}
+		if i > 0 && unicode.IsUpper(r) {
+			prev := rs[i-1]
+			switch {
+			case unicode.IsLower(prev) || unicode.IsDigit(prev):
+				// "clientDo": a word starts at "D".
+				flush()
+			case unicode.IsUpper(prev) && i+1 < len(rs) && unicode.IsLower(rs[i+1]) && !isPlural(rs, i+1):
+				// "HTTPClient": the acronym ends before "C".
+				flush()
+			}
+		}
+		cur = append(cur, r)
+	}
+	flush()
+	return tokens
+}
+
+// isPlural reports whether the lowercase letter at rs[i], which follows an
+// uppercase one, is a lone "s" ending a word, as in "URLs" or "IDsFor".
+func isPlural(rs []rune, i int) bool {
+	return rs[i] == 's' && (i+1 == len(rs) || !unicode.IsLower(rs[i+1]))
+}
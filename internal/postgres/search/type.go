@@ -94,6 +94,15 @@ const (
 	// token combinations. In that case, multiple queries are run in parallel
 	// and the results are combined.
 	SearchTypeMultiWordExact
+	// SearchTypeFieldOrMethod is used for InputTypeNoDot (input is
+	// <fieldOrMethod>), alongside SearchTypeSymbol.
+	SearchTypeFieldOrMethod
+
+	// SearchTypeMultiWordTokens is used for InputTypeMultiWord alongside
+	// SearchTypeMultiWordExact, when consecutive words of the search query
+	// may be the words of one symbol name, as in "http client do" for
+	// Client.Do in net/http.
+	SearchTypeMultiWordTokens
 )
 
 // String returns the name of the search type as a string.
@@ -107,6 +116,10 @@ func (st SearchType) String() string {
 		return "SearchTypeMultiWordOr"
 	case SearchTypeMultiWordExact:
 		return "SearchTypeMultiWordExact"
+	case SearchTypeFieldOrMethod:
+		return "SearchTypeFieldOrMethod"
+	case SearchTypeMultiWordTokens:
+		return "SearchTypeMultiWordTokens"
 	default:
 		// This should never happen.
 		return "?unknown?"
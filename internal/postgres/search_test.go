@@ -1063,6 +1063,47 @@ func TestUpsertSearchDocumentLongerModulePath(t *testing.T) {
 	}
 }
 
+func TestUpsertSearchDocumentCommandDoc(t *testing.T) {
+	// Verify that the documentation of a command, but not of other packages,
+	// is indexed.
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	m := sample.Module(sample.ModulePath, sample.VersionString, "cmd/lint", "lib")
+	for _, u := range m.Packages() {
+		name := "lib"
+		if strings.HasSuffix(u.Path, "/cmd/lint") {
+			name = "main"
+		}
+		u.Name = name
+		u.Documentation = []*internal.Documentation{sample.Documentation(internal.All, internal.All, `
+// Lint finds problems. It reports unreachable code.
+package `+name)}
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	for _, test := range []struct {
+		path string
+		want bool
+	}{
+		{sample.ModulePath + "/cmd/lint", true},
+		{sample.ModulePath + "/lib", false},
+	} {
+		var got bool
+		if err := testDB.db.QueryRow(ctx, `
+			SELECT tsv_search_tokens @@ to_tsquery('unreachable')
+			FROM search_documents
+			WHERE package_path = $1`, test.path).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("%s: documentation indexed: got %t, want %t", test.path, got, test.want)
+		}
+	}
+}
+
 func TestUpdateSearchDocumentsImportedByCount(t *testing.T) {
 	// Dont' run in parallel because it changes countBatchSize.
 	ctx := context.Background()
@@ -1218,7 +1259,7 @@ func TestUpdateSearchDocumentsImportedByCount(t *testing.T) {
 	})
 }
 
-func TestGetPackagesForSearchDocumentUpsert(t *testing.T) {
+func TestRepopulateSearchDocuments(t *testing.T) {
 	t.Parallel()
 	testDB, release := acquire(t)
 	defer release()
@@ -1243,9 +1284,15 @@ func TestGetPackagesForSearchDocumentUpsert(t *testing.T) {
 		MustInsertModule(ctx, t, bypassDB, m)
 	}
 
+	// getArgs gets the packages selected by RepopulateSearchDocuments.
+	getArgs := func(db *DB, before time.Time) ([]UpsertSearchDocumentArgs, error) {
+		args, _, err := db.getSearchDocumentArgs(ctx, "LEFT", "sd.updated_at < $3", 0, 10, before)
+		return args, err
+	}
+
 	// We are asking for all packages in search_documents updated before now, which is
 	// all the non-internal packages.
-	got, err := testDB.GetPackagesForSearchDocumentUpsert(ctx, time.Now(), 10)
+	got, err := getArgs(testDB, time.Now())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1277,11 +1324,11 @@ func TestGetPackagesForSearchDocumentUpsert(t *testing.T) {
 		},
 	}
 	if diff := cmp.Diff(want, got); diff != "" {
-		t.Fatalf("testDB.GetPackagesForSearchDocumentUpsert mismatch(-want +got):\n%s", diff)
+		t.Fatalf("getArgs(testDB) mismatch(-want +got):\n%s", diff)
 	}
 
 	// Reading with license bypass should return the non-redistributable fields.
-	got, err = bypassDB.GetPackagesForSearchDocumentUpsert(ctx, time.Now(), 10)
+	got, err = getArgs(bypassDB, time.Now())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1297,12 +1344,35 @@ func TestGetPackagesForSearchDocumentUpsert(t *testing.T) {
 	}
 
 	// pkgPaths should be an empty slice, all packages were inserted more recently than yesterday.
-	got, err = testDB.GetPackagesForSearchDocumentUpsert(ctx, time.Now().Add(-24*time.Hour), 10)
+	got, err = getArgs(testDB, time.Now().Add(-24*time.Hour))
 	if err != nil {
 		t.Fatal(err)
 	}
 	if len(got) != 0 {
-		t.Fatalf("expected testDB.GetPackagesForSearchDocumentUpsert to return an empty slice; got %v", got)
+		t.Fatalf("expected getArgs to return an empty slice; got %v", got)
+	}
+
+	// Repopulating in batches of two covers all three packages.
+	before := time.Now()
+	last, n, err := testDB.RepopulateSearchDocuments(ctx, before, 0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("first batch: got %d documents, want 2", n)
+	}
+	if _, n, err = testDB.RepopulateSearchDocuments(ctx, before, last, 2); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("second batch: got %d documents, want 1", n)
+	}
+	// Every document has been updated since.
+	if _, n, err = testDB.RepopulateSearchDocuments(ctx, before, 0, 2); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("after repopulating: got %d documents, want 0", n)
 	}
 }
 
@@ -5,8 +5,11 @@
 package postgres
 
 import (
+	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"unicode"
 
 	"github.com/russross/blackfriday/v2"
@@ -18,7 +21,8 @@ const (
 )
 
 // SearchDocumentSections computes the B and C sections of a Postgres search
-// document from a package synopsis and a README.
+// document from a package synopsis, a README and, for commands, the package
+// documentation.
 // By "B section" and "C section" we mean the portion of the tsvector with weight
 // "B" and "C", respectively.
 //
@@ -29,20 +33,32 @@ const (
 // Each section is limited to maxSectionWords words, and in addition the
 // D section is limited to an initial fraction of the README, determined
 // by maxReadmeFraction.
-func SearchDocumentSections(synopsis, readmeFilename, readme string) (b, c, d string) {
-	return searchDocumentSections(synopsis, readmeFilename, readme, maxSectionWords, maxReadmeFraction)
+//
+// The synopsis of a command is the first sentence of its documentation, and
+// commands are often documented at length instead of in a README. So
+// commandDoc, less its first sentence, is used in place of an empty README,
+// and otherwise follows the README.
+func SearchDocumentSections(synopsis, readmeFilename, readme, commandDoc string) (b, c, d string) {
+	return searchDocumentSections(synopsis, readmeFilename, readme, commandDoc, maxSectionWords, maxReadmeFraction)
 }
 
-func searchDocumentSections(synopsis, readmeFilename, readme string, maxSecWords int, maxReadmeFrac float64) (b, c, d string) {
+func searchDocumentSections(synopsis, readmeFilename, readme, commandDoc string, maxSecWords int, maxReadmeFrac float64) (b, c, d string) {
 	var readmeFirst, readmeRest string
 	if isMarkdown(readmeFilename) {
 		readme = processMarkdown(readme)
 	}
+	if i := sentenceEndIndex(commandDoc); i >= 0 {
+		commandDoc = commandDoc[i+1:]
+	}
+	if strings.TrimSpace(readme) == "" {
+		readme, commandDoc = commandDoc, ""
+	}
 	if i := sentenceEndIndex(readme); i > 0 {
 		readmeFirst, readmeRest = readme[:i+1], readme[i+1:]
 	} else {
 		readmeRest = readme
 	}
+	readmeRest += "\n" + commandDoc
 	sw := processWords(synopsis)
 	rwf := processWords(readmeFirst)
 	rwr := processWords(readmeRest)
@@ -124,6 +140,9 @@ func processWords(s string) []string {
 //	"deleteMe": nil					 // removes "deleteMe"
 //	"rand": []string{"random"}			 // replace "rand" with "random"
 //	"utf-8": []string{"utf-8", "utf8"}  // add "utf8" whenever "utf-8" is seen
+//
+// These are the built-in replacements. Deployments can add to them or
+// override them with SetSearchSynonyms.
 var summaryReplacements = map[string][]string{
 	"postgres":   {"postgres", "postgresql"},
 	"postgresql": {"postgres", "postgresql"},
@@ -137,15 +156,78 @@ var summaryReplacements = map[string][]string{
 	"utf-8":      {"utf-8", "utf8"},
 }
 
+// searchSynonyms holds the replacements currently applied by processWord:
+// summaryReplacements together with those passed to SetSearchSynonyms.
+var searchSynonyms atomic.Pointer[map[string][]string]
+
+func init() {
+	searchSynonyms.Store(&summaryReplacements)
+}
+
+// SetSearchSynonyms sets the word replacements used to build search
+// documents to the built-in ones, summaryReplacements, together with extra.
+// An entry of extra replaces the built-in one for the same word, and a word
+// with no replacements is omitted from search documents. Words and
+// replacements are lowercased, like the text they are applied to.
+//
+// SetSearchSynonyms returns the sorted words whose replacements changed.
+// Search documents built before the change can be rebuilt with
+// DB.UpdateSearchSynonyms. It is safe to call SetSearchSynonyms concurrently
+// with building search documents.
+func SetSearchSynonyms(extra map[string][]string) (changed []string) {
+	syns := mergeSynonyms(extra)
+	old := searchSynonyms.Swap(&syns)
+	return changedSynonyms(*old, syns)
+}
+
+// SearchSynonyms returns the word replacements currently used to build
+// search documents. The result must not be modified.
+func SearchSynonyms() map[string][]string {
+	return *searchSynonyms.Load()
+}
+
+// mergeSynonyms returns summaryReplacements with the entries of extra added,
+// lowercased.
+func mergeSynonyms(extra map[string][]string) map[string][]string {
+	syns := maps.Clone(summaryReplacements)
+	for w, rs := range extra {
+		lrs := []string{}
+		for _, r := range rs {
+			lrs = append(lrs, strings.ToLower(r))
+		}
+		syns[strings.ToLower(w)] = lrs
+	}
+	return syns
+}
+
+// changedSynonyms returns the sorted words whose replacements differ between
+// prev and cur, including those in only one of them.
+func changedSynonyms(prev, cur map[string][]string) []string {
+	var words []string
+	for w, rs := range prev {
+		if crs, ok := cur[w]; !ok || !slices.Equal(rs, crs) {
+			words = append(words, w)
+		}
+	}
+	for w := range cur {
+		if _, ok := prev[w]; !ok {
+			words = append(words, w)
+		}
+	}
+	slices.Sort(words)
+	return words
+}
+
 // processWord performs processing on s, returning zero or more words.
-// Its main purpose is to apply summaryReplacements to replace
+// Its main purpose is to apply the current search synonyms to replace
 // certain words with synonyms or additional search terms.
 func processWord(s string) []string {
 	s = strings.TrimFunc(s, unicode.IsPunct)
 	if s == "" {
 		return nil
 	}
-	if rs, ok := summaryReplacements[s]; ok {
+	syns := SearchSynonyms()
+	if rs, ok := syns[s]; ok {
 		return rs
 	}
 	if !hyphenSplit(s) {
@@ -158,7 +240,7 @@ func processWord(s string) []string {
 	}
 	result := []string{s} // Include the full hyphenated word.
 	for _, w := range ws {
-		if rs, ok := summaryReplacements[w]; ok {
+		if rs, ok := syns[w]; ok {
 			result = append(result, rs...)
 		}
 		// We don't need to include the parts; the Postgres text-search processor will do that.
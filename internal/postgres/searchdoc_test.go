@@ -17,6 +17,7 @@ func TestSearchDocumentSections(t *testing.T) {
 		synopsis            string
 		readmeFilename      string
 		readmeContents      string
+		commandDoc          string
 		wantB, wantC, wantD string
 	}{
 		{
@@ -24,6 +25,7 @@ func TestSearchDocumentSections(t *testing.T) {
 			"This is a synopsis.",
 			"foo.md",
 			`Package blackfriday is a [markdown](http://foo) processor. That _is_ all that it is.`,
+			"",
 
 			"this is a synopsis",
 			"package blackfriday is a markdown processor",
@@ -34,6 +36,7 @@ func TestSearchDocumentSections(t *testing.T) {
 			"This synopsis is too long so we'll truncate it.",
 			"README",
 			"This README doesn't have a sentence end so the whole thing is D",
+			"",
 
 			"this synopsis is too long so",
 			"",
@@ -53,13 +56,36 @@ Go configuration with fangs!
 [![GoDoc](https://godoc.org/github.com/spf13/viper?status.svg)](https://godoc.org/github.com/spf13/viper)
 
 Many Go projects are built using Viper including:`,
+			"",
 
 			"go configuration with fangs", // first sentence of README promoted
 			"",
 			"many go projects are",
 		},
+		{
+			"command without README",
+			"Staticcheck lints Go code.",
+			"",
+			"",
+			"Staticcheck lints Go code. It finds bugs and performance issues.\n\nUsage:\n\n\tstaticcheck [flags] packages",
+
+			"staticcheck lints go code",
+			"it finds bugs and performance issues",
+			"usage: staticcheck",
+		},
+		{
+			"command with README",
+			"Staticcheck lints Go code.",
+			"README",
+			"The staticcheck linter. See the docs.",
+			"Staticcheck lints Go code. It finds bugs.",
+
+			"staticcheck lints go code",
+			"the staticcheck linter",
+			"see the",
+		},
 	} {
-		gotB, gotC, gotD := searchDocumentSections(test.synopsis, test.readmeFilename, test.readmeContents, 6, 0.5)
+		gotB, gotC, gotD := searchDocumentSections(test.synopsis, test.readmeFilename, test.readmeContents, test.commandDoc, 6, 0.5)
 		if gotB != test.wantB {
 			t.Errorf("%s, B: got %q, want %q", test.name, gotB, test.wantB)
 		}
@@ -95,6 +121,32 @@ func TestProcessWords(t *testing.T) {
 	}
 }
 
+func TestSetSearchSynonyms(t *testing.T) {
+	// Not parallel: the search synonyms are global.
+	defer SetSearchSynonyms(nil)
+
+	changed := SetSearchSynonyms(map[string][]string{
+		"AcmeDB": {"acmedb", "Postgres"},
+		"rand":   {"rand", "random"},
+		"redis":  {},
+	})
+	if diff := cmp.Diff([]string{"acmedb", "rand", "redis"}, changed); diff != "" {
+		t.Errorf("changed words mismatch (-want, +got):\n%s", diff)
+	}
+	got := processWords("An AcmeDB client with rand and redis support")
+	want := []string{"an", "acmedb", "postgres", "client", "with", "rand", "random", "and", "support"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("processWords mismatch (-want, +got):\n%s", diff)
+	}
+
+	if changed := SetSearchSynonyms(map[string][]string{"rand": {"rand", "random"}}); !cmp.Equal(changed, []string{"acmedb", "redis"}) {
+		t.Errorf("got changed words %v, want [acmedb redis]", changed)
+	}
+	if changed := SetSearchSynonyms(nil); !cmp.Equal(changed, []string{"rand"}) {
+		t.Errorf("got changed words %v, want [rand]", changed)
+	}
+}
+
 func TestProcessMarkdown(t *testing.T) {
 	t.Parallel()
 	const (
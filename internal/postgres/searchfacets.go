@@ -0,0 +1,158 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware/stats"
+)
+
+const (
+	// maxFacetDocuments is the maximum number of matching search documents
+	// whose facet values are counted.
+	maxFacetDocuments = 10000
+
+	// maxFacetValues is the maximum number of values returned for each
+	// facet.
+	maxFacetValues = 10
+)
+
+// The SQL expressions for the facet values of a search document. They must
+// agree with internal.SearchFacetValues.
+const (
+	hostFacetExpr = `split_part(module_path, '/', 1)`
+
+	// A gopkg.in path has a major version suffix like ".v2", others like
+	// "/v2". Without one, the major version is that of the version.
+	majorFacetExpr = `COALESCE(
+			CASE WHEN module_path LIKE 'gopkg.in/%'
+			THEN substring(module_path from '\.(v[0-9]+)$')
+			ELSE substring(module_path from '/(v[0-9]+)$')
+			END,
+			split_part(version, '.', 1))`
+)
+
+// searchFilterClause returns a condition on search_documents that applies
+// the filters f, to be added to a WHERE clause with AND, and its arguments,
+// which are numbered starting at firstArg. The condition is empty if f is
+// zero.
+func searchFilterClause(f internal.SearchFilters, firstArg int) (string, []any) {
+	var (
+		conds []string
+		args  []any
+	)
+	add := func(format, value string) {
+		args = append(args, value)
+		conds = append(conds, fmt.Sprintf(format, firstArg+len(args)-1))
+	}
+	if f.License != "" {
+		add("$%d = ANY(license_types)", f.License)
+	}
+	if f.Host != "" {
+		add(hostFacetExpr+" = $%d", f.Host)
+	}
+	if f.Major != "" {
+		add("("+majorFacetExpr+") = $%d", f.Major)
+	}
+	if len(conds) == 0 {
+		return "", nil
+	}
+	return " AND " + strings.Join(conds, " AND "), args
+}
+
+// GetSearchFacets returns the number of packages matching a package search
+// for q with each license type, module host and major version, among the
+// packages that pass opts.Filters. It considers the same packages as deep
+// search, but at most maxFacetDocuments of them, in which case the result
+// is approximate.
+func (db *DB) GetSearchFacets(ctx context.Context, q string, opts internal.SearchOptions) (_ *internal.SearchFacets, err error) {
+	defer derrors.WrapStack(&err, "GetSearchFacets(ctx, %q, %+v)", q, opts.Filters)
+	defer stats.Elapsed(ctx, "GetSearchFacets")()
+	return db.getSearchFacets(ctx, q, opts, maxFacetDocuments)
+}
+
+// getSearchFacets is GetSearchFacets, counting the facet values of at most
+// maxDocs search documents.
+func (db *DB) getSearchFacets(ctx context.Context, q string, opts internal.SearchOptions, maxDocs int) (*internal.SearchFacets, error) {
+	// One more document than is counted is fetched, to tell whether the
+	// counts are approximate. The fetched CTE is referenced twice, so it is
+	// evaluated once, and the counted documents are among the fetched ones.
+	filter, filterArgs := searchFilterClause(opts.Filters, 3)
+	query := fmt.Sprintf(`
+		WITH fetched AS (
+			SELECT module_path, version, license_types
+			FROM search_documents
+			WHERE tsv_search_tokens @@ websearch_to_tsquery($1)
+				AND (%s) > 0.1
+				%s
+			LIMIT $2 + 1
+		), matches AS (
+			SELECT * FROM fetched LIMIT $2
+		)
+		SELECT 'license', l, COUNT(*)
+			FROM matches, unnest(license_types) AS l
+			WHERE l <> ''
+			GROUP BY l
+		UNION ALL
+		SELECT 'host', %s, COUNT(*) FROM matches GROUP BY 2
+		UNION ALL
+		SELECT 'major', %s, COUNT(*) FROM matches GROUP BY 2
+		UNION ALL
+		SELECT 'total', '', COUNT(*) FROM fetched`,
+		scoreExpr, filter, hostFacetExpr, majorFacetExpr)
+
+	facets := &internal.SearchFacets{}
+	collect := func(rows *sql.Rows) error {
+		var (
+			facet string
+			fc    internal.FacetCount
+		)
+		if err := rows.Scan(&facet, &fc.Value, &fc.Count); err != nil {
+			return err
+		}
+		switch facet {
+		case "license":
+			facets.Licenses = append(facets.Licenses, &fc)
+		case "host":
+			facets.Hosts = append(facets.Hosts, &fc)
+		case "major":
+			facets.MajorVersions = append(facets.MajorVersions, &fc)
+		case "total":
+			facets.Approximate = fc.Count > maxDocs
+		}
+		return nil
+	}
+	args := append([]any{q, maxDocs}, filterArgs...)
+	if err := db.db.RunQuery(ctx, query, collect, args...); err != nil {
+		return nil, err
+	}
+	facets.Licenses = topFacetValues(facets.Licenses)
+	facets.Hosts = topFacetValues(facets.Hosts)
+	facets.MajorVersions = topFacetValues(facets.MajorVersions)
+	return facets, nil
+}
+
+// topFacetValues sorts fcs by decreasing count and returns at most
+// maxFacetValues of them.
+func topFacetValues(fcs []*internal.FacetCount) []*internal.FacetCount {
+	slices.SortFunc(fcs, func(a, b *internal.FacetCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	if len(fcs) > maxFacetValues {
+		fcs = fcs[:maxFacetValues]
+	}
+	return fcs
+}
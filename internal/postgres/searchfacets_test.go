@@ -0,0 +1,120 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetSearchFacets(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	for _, mv := range []struct{ modulePath, version string }{
+		{"github.com/a/yaml", "v1.0.0"},
+		{"gitlab.com/b/yaml/v2", "v2.0.0"},
+		{"gopkg.in/yaml.v3", "v3.0.0"},
+	} {
+		MustInsertModule(ctx, t, testDB, sample.Module(mv.modulePath, mv.version, "p"))
+	}
+
+	for _, test := range []struct {
+		name    string
+		filters internal.SearchFilters
+		want    *internal.SearchFacets
+	}{
+		{
+			name: "no filters",
+			want: &internal.SearchFacets{
+				Licenses: []*internal.FacetCount{{Value: "MIT", Count: 3}},
+				Hosts: []*internal.FacetCount{
+					{Value: "github.com", Count: 1},
+					{Value: "gitlab.com", Count: 1},
+					{Value: "gopkg.in", Count: 1},
+				},
+				MajorVersions: []*internal.FacetCount{
+					{Value: "v1", Count: 1},
+					{Value: "v2", Count: 1},
+					{Value: "v3", Count: 1},
+				},
+			},
+		},
+		{
+			name:    "host",
+			filters: internal.SearchFilters{Host: "gitlab.com"},
+			want: &internal.SearchFacets{
+				Licenses:      []*internal.FacetCount{{Value: "MIT", Count: 1}},
+				Hosts:         []*internal.FacetCount{{Value: "gitlab.com", Count: 1}},
+				MajorVersions: []*internal.FacetCount{{Value: "v2", Count: 1}},
+			},
+		},
+		{
+			name:    "unknown license",
+			filters: internal.SearchFilters{License: "BSD-3-Clause"},
+			want:    &internal.SearchFacets{},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := testDB.GetSearchFacets(ctx, "yaml", internal.SearchOptions{Filters: test.filters})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	// Search applies the same filters.
+	got, err := testDB.Search(ctx, "yaml", SearchOptions{
+		MaxResults:     10,
+		MaxResultCount: 100,
+		Filters:        internal.SearchFilters{Major: "v3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].PackagePath != "gopkg.in/yaml.v3/p" {
+		var paths []string
+		for _, r := range got {
+			paths = append(paths, r.PackagePath)
+		}
+		t.Errorf("got %v, want [gopkg.in/yaml.v3/p]", paths)
+	}
+
+	// When more documents match than are counted, only the counted ones
+	// contribute to the counts.
+	facets, err := testDB.getSearchFacets(ctx, "yaml", internal.SearchOptions{}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !facets.Approximate {
+		t.Error("got exact counts, want approximate")
+	}
+	if want := []*internal.FacetCount{{Value: "MIT", Count: 2}}; !cmp.Equal(facets.Licenses, want) {
+		t.Errorf("got licenses %v, want MIT for 2 packages", facets.Licenses)
+	}
+}
+
+func TestSearchFilterClause(t *testing.T) {
+	if got, args := searchFilterClause(internal.SearchFilters{}, 4); got != "" || args != nil {
+		t.Errorf("got (%q, %v) for no filters, want empty", got, args)
+	}
+	got, args := searchFilterClause(internal.SearchFilters{License: "MIT", Major: "v2"}, 4)
+	want := " AND $4 = ANY(license_types) AND (" + majorFacetExpr + ") = $5"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if diff := cmp.Diff([]any{"MIT", "v2"}, args); diff != "" {
+		t.Errorf("args mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -375,6 +375,35 @@ func TestInsertSymbolHistory_MultiGOOS(t *testing.T) {
 	}
 }
 
+func TestGetSymbolsAtVersions(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	api10 := []*internal.Symbol{sample.Constant, sample.Variable}
+	api11 := []*internal.Symbol{sample.Constant, sample.Function}
+	api12 := []*internal.Symbol{sample.Constant, sample.Function, sample.Type}
+	mod10 := moduleWithSymbols(t, "v1.0.0", api10)
+	MustInsertModule(ctx, t, testDB, mod10)
+	MustInsertModule(ctx, t, testDB, moduleWithSymbols(t, "v1.1.0", api11))
+	MustInsertModule(ctx, t, testDB, moduleWithSymbols(t, "v1.2.0", api12))
+
+	got, err := testDB.GetSymbolsAtVersions(ctx, mod10.Packages()[0].Path, mod10.ModulePath, []string{"v1.0.0", "v1.2.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := symbolHistoryFromAPI(api10, "v1.0.0")
+	updateSymbols(api12, func(s *internal.SymbolMeta) error {
+		want.AddSymbol(*s, "v1.2.0", internal.BuildContextAll)
+		return nil
+	})
+	if diff := cmp.Diff(want, got,
+		cmp.AllowUnexported(internal.SymbolBuildContexts{}, internal.SymbolHistory{})); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func moduleWithSymbols(t *testing.T, version string, symbols []*internal.Symbol) *internal.Module {
 	mod := sample.Module(sample.ModulePath, version, "")
 	if len(mod.Packages()) != 1 {
@@ -89,10 +89,104 @@ func upsertSymbolSearchDocuments(ctx context.Context, tx *database.DB,
 			package_path = excluded.package_path,
 			imported_by_count = excluded.imported_by_count,
 			symbol_name = excluded.symbol_name;`
-	_, err = tx.Exec(ctx, q, modulePath, v)
+	if _, err := tx.Exec(ctx, q, modulePath, v); err != nil {
+		return err
+	}
+	return updateSymbolNameTokens(ctx, tx, modulePath, v)
+}
+
+// updateSymbolNameTokens sets the words of the symbol names of the symbol
+// search documents for the packages of modulePath@v, as split by
+// search.SymbolTokens, so that multi-word searches can match them.
+func updateSymbolNameTokens(ctx context.Context, tx *database.DB, modulePath, v string) (err error) {
+	defer derrors.Wrap(&err, "updateSymbolNameTokens(ctx, ddb, %q, %q)", modulePath, v)
+
+	var (
+		nameIDs []int64
+		tokens  []string
+	)
+	collect := func(rows *sql.Rows) error {
+		var (
+			id   int64
+			name string
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			return err
+		}
+		nameIDs = append(nameIDs, id)
+		tokens = append(tokens, strings.Join(search.SymbolTokens(name), " "))
+		return nil
+	}
+	if err := tx.RunQuery(ctx, `
+		SELECT DISTINCT ssd.symbol_name_id, ssd.symbol_name
+		FROM symbol_search_documents ssd
+		INNER JOIN search_documents sd ON sd.package_path_id = ssd.package_path_id
+		WHERE sd.module_path = $1 AND sd.version = $2`,
+		collect, modulePath, v); err != nil {
+		return err
+	}
+	if len(nameIDs) == 0 {
+		return nil
+	}
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		UPDATE symbol_search_documents ssd
+		SET tsv_symbol_name_tokens = to_tsvector('%s', t.tokens)
+		FROM unnest($3::bigint[], $4::text[]) AS t(symbol_name_id, tokens), search_documents sd
+		WHERE
+			ssd.symbol_name_id = t.symbol_name_id
+			AND ssd.package_path_id = sd.package_path_id
+			AND sd.module_path = $1 AND sd.version = $2`, search.SymbolTextSearchConfiguration),
+		modulePath, v, pq.Array(nameIDs), pq.Array(tokens))
 	return err
 }
 
+// BackfillSymbolNameTokens sets the words of the symbol names of at most limit
+// symbol search documents whose IDs are greater than afterID and whose words
+// were not set, such as those of module versions processed before migration
+// 000181 added them. It returns the largest ID it saw and the number of
+// documents it updated.
+func (db *DB) BackfillSymbolNameTokens(ctx context.Context, afterID, limit int) (lastID, n int, err error) {
+	defer derrors.WrapStack(&err, "BackfillSymbolNameTokens(ctx, %d, %d)", afterID, limit)
+
+	var (
+		ids    []int64
+		tokens []string
+	)
+	collect := func(rows *sql.Rows) error {
+		var (
+			id   int64
+			name string
+		)
+		if err := rows.Scan(&id, &name); err != nil {
+			return err
+		}
+		ids = append(ids, id)
+		tokens = append(tokens, strings.Join(search.SymbolTokens(name), " "))
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, `
+		SELECT id, symbol_name
+		FROM symbol_search_documents
+		WHERE id > $1 AND tsv_symbol_name_tokens IS NULL
+		ORDER BY id
+		LIMIT $2`,
+		collect, afterID, limit); err != nil {
+		return 0, 0, err
+	}
+	if len(ids) == 0 {
+		return 0, 0, nil
+	}
+	if _, err := db.db.Exec(ctx, fmt.Sprintf(`
+		UPDATE symbol_search_documents ssd
+		SET tsv_symbol_name_tokens = to_tsvector('%s', t.tokens)
+		FROM unnest($1::bigint[], $2::text[]) AS t(id, tokens)
+		WHERE ssd.id = t.id`, search.SymbolTextSearchConfiguration),
+		pq.Array(ids), pq.Array(tokens)); err != nil {
+		return 0, 0, err
+	}
+	return int(ids[len(ids)-1]), len(ids), nil
+}
+
 // symbolSearch searches all symbols in the symbol_search_documents table for
 // the query.
 //
@@ -113,7 +207,7 @@ func (db *DB) symbolSearch(ctx context.Context, q string, limit int, opts Search
 	case search.InputTypeMultiWord:
 		results, err = runSymbolSearchMultiWord(ctx, db.db, q, limit, opts.SymbolFilter)
 	case search.InputTypeNoDot:
-		results, err = runSymbolSearch(ctx, db.db, search.SearchTypeSymbol, q, limit)
+		results, err = runSymbolSearchNoDot(ctx, db.db, q, limit)
 	case search.InputTypeTwoDots:
 		results, err = runSymbolSearchPackageDotSymbol(ctx, db.db, q, limit)
 	default:
@@ -128,21 +222,7 @@ func (db *DB) symbolSearch(ctx context.Context, q string, limit int, opts Search
 		}
 		return sr
 	}
-	sort.Slice(results, func(i, j int) bool {
-		if results[i].NumImportedBy != results[j].NumImportedBy {
-			return results[i].NumImportedBy > results[j].NumImportedBy
-		}
-
-		// If two packages have the same imported by count, return them in
-		// alphabetical order by package path.
-		if results[i].PackagePath != results[j].PackagePath {
-			return results[i].PackagePath < results[j].PackagePath
-		}
-
-		// If one package has multiple matching symbols, return them by
-		// alphabetical order of symbol name.
-		return results[i].SymbolName < results[j].SymbolName
-	})
+	sort.Slice(results, func(i, j int) bool { return lessSymbolResult(results[i], results[j]) })
 	if len(results) > limit {
 		results = results[0:limit]
 	}
@@ -153,6 +233,27 @@ func (db *DB) symbolSearch(ctx context.Context, q string, limit int, opts Search
 	return sr
 }
 
+// lessSymbolResult reports whether a should be ranked above b in symbol
+// search results.
+func lessSymbolResult(a, b *SearchResult) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.NumImportedBy != b.NumImportedBy {
+		return a.NumImportedBy > b.NumImportedBy
+	}
+
+	// If two packages have the same score and imported by count, return them
+	// in alphabetical order by package path.
+	if a.PackagePath != b.PackagePath {
+		return a.PackagePath < b.PackagePath
+	}
+
+	// If one package has multiple matching symbols, return them by
+	// alphabetical order of symbol name.
+	return a.SymbolName < b.SymbolName
+}
+
 // runSymbolSearchMultiWord executes a symbol search for SearchTypeMultiWord.
 func runSymbolSearchMultiWord(ctx context.Context, ddb *database.DB, q string, limit int,
 	symbolFilter string) (_ []*SearchResult, err error) {
@@ -161,7 +262,8 @@ func runSymbolSearchMultiWord(ctx context.Context, ddb *database.DB, q string, l
 	defer stats.Elapsed(ctx, "runSymbolSearchMultiWord")()
 
 	symbolToPathTokens := multiwordSearchCombinations(q, symbolFilter)
-	if len(symbolToPathTokens) == 0 {
+	tokenSearches := multiwordTokenSearches(q, symbolFilter)
+	if len(symbolToPathTokens) == 0 && len(tokenSearches) == 0 {
 		// There are no words in the query that could be a symbol name.
 		return nil, derrors.NotFound
 	}
@@ -171,7 +273,7 @@ func runSymbolSearchMultiWord(ctx context.Context, ddb *database.DB, q string, l
 		return nil, derrors.NotFound
 	}
 	group, searchCtx := errgroup.WithContext(ctx)
-	resultsArray := make([][]*SearchResult, len(symbolToPathTokens))
+	resultsArray := make([][]*SearchResult, len(symbolToPathTokens)+len(tokenSearches))
 	count := 0
 	for symbol, pathTokens := range symbolToPathTokens {
 		symbol := symbol
@@ -188,6 +290,18 @@ func runSymbolSearchMultiWord(ctx context.Context, ddb *database.DB, q string, l
 			return nil
 		})
 	}
+	for j, ts := range tokenSearches {
+		i := len(symbolToPathTokens) + j
+		group.Go(func() error {
+			st := search.SearchTypeMultiWordTokens
+			r, err := runSymbolSearch(searchCtx, ddb, st, ts.symbolTokens, limit, ts.pathTokens, ts.numTokens)
+			if err != nil {
+				return err
+			}
+			resultsArray[i] = r
+			return nil
+		})
+	}
 	if err := group.Wait(); err != nil {
 		return nil, err
 	}
@@ -206,7 +320,7 @@ func mergedResults(resultsArray [][]*SearchResult, limit int) []*SearchResult {
 			}
 		}
 	}
-	sort.Slice(results, func(i, j int) bool { return results[i].NumImportedBy > results[j].NumImportedBy })
+	sort.Slice(results, func(i, j int) bool { return lessSymbolResult(results[i], results[j]) })
 	if len(results) > limit {
 		results = results[0:limit]
 	}
@@ -254,6 +368,64 @@ func multiwordSearchCombinations(q, symbolFilter string) map[string]string {
 	return symbolToPathTokens
 }
 
+// maxTokenSearchWords is the maximum number of words in a query for which
+// multiwordTokenSearches returns searches. A query of n words has
+// n*(n-1)/2 runs of two or more consecutive words.
+const maxTokenSearchWords = 4
+
+// A tokenSearch is a search for symbols whose names contain the words in
+// symbolTokens, in packages whose path tokens match pathTokens.
+type tokenSearch struct {
+	symbolTokens string // joined by " & "
+	numTokens    int
+	pathTokens   string // joined by " & ", or empty
+}
+
+// multiwordTokenSearches returns the searches for a multi-word query in which
+// two or more consecutive words may be the words of one symbol name, as split
+// by search.SymbolTokens, and the other words are path elements. For example,
+// "http client do" may be a search for Client.Do in a package whose path
+// contains "http", or for HTTPClient.Do in any package.
+//
+// Like multiwordSearchCombinations, it returns nil if the query has too many
+// words, or if a symbolFilter says which word is the symbol name.
+func multiwordTokenSearches(q, symbolFilter string) []tokenSearch {
+	words := strings.Fields(q)
+	if symbolFilter != "" || len(words) > maxTokenSearchWords {
+		return nil
+	}
+	var searches []tokenSearch
+	for i := range words {
+	runs:
+		for j := i + 2; j <= len(words); j++ {
+			var tokens []string
+			seen := map[string]bool{}
+			for _, w := range words[i:j] {
+				if strings.Contains(w, "/") || strings.Contains(w, "-") || commonHostnames[w] {
+					continue runs
+				}
+				for _, t := range search.SymbolTokens(w) {
+					if !seen[t] {
+						seen[t] = true
+						tokens = append(tokens, t)
+					}
+				}
+			}
+			if len(tokens) < 2 {
+				continue
+			}
+			pathTokens := append(append([]string{}, words[:i]...), words[j:]...)
+			sort.Strings(pathTokens)
+			searches = append(searches, tokenSearch{
+				symbolTokens: strings.Join(tokens, " & "),
+				numTokens:    len(tokens),
+				pathTokens:   strings.Join(pathTokens, " & "),
+			})
+		}
+	}
+	return searches
+}
+
 // runSymbolSearchOneDot is used when q contains only 1 dot, so the search must
 // either be for <package>.<symbol> or <type>.<methodOrFieldName>.
 //
@@ -294,6 +466,37 @@ func runSymbolSearchOneDot(ctx context.Context, ddb *database.DB, q string, limi
 	return mergedResults(resultsArray, limit), nil
 }
 
+// runSymbolSearchNoDot is used when q contains no dots, so the search must
+// either be for <symbol> or for <fieldOrMethodName> without its type.
+//
+// As with runSymbolSearchOneDot, the search is split into two parallel
+// queries.
+func runSymbolSearchNoDot(ctx context.Context, ddb *database.DB, q string, limit int) (_ []*SearchResult, err error) {
+	defer derrors.Wrap(&err, "runSymbolSearchNoDot(ctx, ddb, %q, %d)", q, limit)
+	defer stats.Elapsed(ctx, "runSymbolSearchNoDot")()
+
+	group, searchCtx := errgroup.WithContext(ctx)
+	searchTypes := []search.SearchType{
+		search.SearchTypeSymbol,
+		search.SearchTypeFieldOrMethod,
+	}
+	resultsArray := make([][]*SearchResult, len(searchTypes))
+	for i, st := range searchTypes {
+		group.Go(func() error {
+			results, err := runSymbolSearch(searchCtx, ddb, st, q, limit)
+			if err != nil {
+				return err
+			}
+			resultsArray[i] = results
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+	return mergedResults(resultsArray, limit), nil
+}
+
 func runSymbolSearchPackageDotSymbol(ctx context.Context, ddb *database.DB, q string, limit int) (_ []*SearchResult, err error) {
 	pkg, symbol, err := splitPackageAndSymbolNames(q)
 	if err != nil {
@@ -337,7 +540,8 @@ func runSymbolSearch(ctx context.Context, ddb *database.DB,
 			&r.SymbolGOOS,
 			&r.SymbolGOARCH,
 			&r.SymbolKind,
-			&r.SymbolSynopsis); err != nil {
+			&r.SymbolSynopsis,
+			&r.Score); err != nil {
 			return fmt.Errorf("symbolSearch: rows.Scan(): %v", err)
 		}
 		results = append(results, &r)
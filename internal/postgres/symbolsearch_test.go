@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/testing/sample"
 )
@@ -55,15 +56,11 @@ func TestSymbolSearch(t *testing.T) {
 			q:    sample.Variable.Name,
 			want: checkResult(sample.Variable.SymbolMeta),
 		},
-		/*
-			TODO(golang/go#44142): Add support for searching just by field or
-			method name.
-			{
-				name: "test search by <methodName>",
-				q:    "Method",
-				want: checkResult(sample.Method),
-			},
-		*/
+		{
+			name: "test search by <methodName>",
+			q:    "Method",
+			want: checkResult(sample.Method),
+		},
 		{
 			name: "test search by <package>.<type>.<methodName>",
 			q:    "foo.Type.Method",
@@ -99,6 +96,16 @@ func TestSymbolSearch(t *testing.T) {
 			q:    "module_name/foo function",
 			want: checkResult(sample.Function.SymbolMeta),
 		},
+		{
+			name: "test search by the words of <type>.<methodName>",
+			q:    "type method",
+			want: checkResult(sample.Method),
+		},
+		{
+			name: "test search by <package> space the words of <type>.<methodName>",
+			q:    "foo type method",
+			want: checkResult(sample.Method),
+		},
 		{
 			name: "test invalid to_tsquery input returns no results instead of error",
 			q:    "foo:function",
@@ -116,13 +123,121 @@ func TestSymbolSearch(t *testing.T) {
 			if len(resp.results) == 0 && test.want != nil {
 				t.Fatalf("expected results")
 			}
-			if diff := cmp.Diff(test.want, resp.results); diff != "" {
+			if diff := cmp.Diff(test.want, resp.results, cmpopts.IgnoreFields(SearchResult{}, "Score")); diff != "" {
 				t.Errorf("mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
 }
 
+// newSymbol returns a symbol with the given name, kind and section, in all
+// build contexts.
+func newSymbol(name string, kind internal.SymbolKind, section internal.SymbolSection) *internal.Symbol {
+	return &internal.Symbol{
+		SymbolMeta: internal.SymbolMeta{
+			Name:     name,
+			Synopsis: name,
+			Section:  section,
+			Kind:     kind,
+		},
+		GOOS:   internal.All,
+		GOARCH: internal.All,
+	}
+}
+
+func TestSymbolSearchRanking(t *testing.T) {
+	ctx := context.Background()
+	testDB, release := acquire(t)
+	defer release()
+
+	insert := func(modulePath string, api ...*internal.Symbol) {
+		m := sample.Module(modulePath, sample.VersionString, "p")
+		m.Packages()[0].Documentation[0].API = api
+		MustInsertModule(ctx, t, testDB, m)
+	}
+	method := newSymbol("Client.New", internal.SymbolKindMethod, internal.SymbolSectionTypes)
+	method.ParentName = "Client"
+	insert("example.com/popular", newSymbol("New", internal.SymbolKindFunction, internal.SymbolSectionFunctions))
+	insert("example.com/obscure", newSymbol("New", internal.SymbolKindFunction, internal.SymbolSectionFunctions))
+	insert("example.com/lowercase", newSymbol("NEW", internal.SymbolKindFunction, internal.SymbolSectionFunctions))
+	insert("example.com/method", method)
+	for path, count := range map[string]int{
+		"example.com/popular/p":   1000,
+		"example.com/obscure/p":   0,
+		"example.com/lowercase/p": 0,
+		"example.com/method/p":    0,
+	} {
+		if _, err := testDB.db.Exec(ctx,
+			`UPDATE symbol_search_documents SET imported_by_count = $2 WHERE package_path = $1`,
+			path, count); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := SearchOptions{MaxResultCount: 100}
+	resp, err := testDB.hedgedSearch(ctx, "New", 10, opts, symbolSearchers, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range resp.results {
+		got = append(got, r.PackagePath+"."+r.SymbolName)
+	}
+	want := []string{
+		// Popular packages rank first.
+		"example.com/popular/p.New",
+		// Exact matches rank above case-insensitive matches.
+		"example.com/obscure/p.New",
+		"example.com/lowercase/p.NEW",
+		// Methods found by name alone rank below top-level symbols.
+		"example.com/method/p.Client.New",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSymbolSearchUnicode(t *testing.T) {
+	ctx := context.Background()
+	testDB, release := acquire(t)
+	defer release()
+
+	greek := newSymbol("Δέλτα", internal.SymbolKindType, internal.SymbolSectionTypes)
+	japanese := newSymbol("X関数", internal.SymbolKindFunction, internal.SymbolSectionFunctions)
+	m := sample.DefaultModule()
+	m.Packages()[0].Documentation[0].API = []*internal.Symbol{greek, japanese}
+	MustInsertModule(ctx, t, testDB, m)
+
+	for _, test := range []struct {
+		q    string
+		want string // symbol name, or empty for no results
+	}{
+		{"Δέλτα", greek.Name},
+		{"δέλτα", greek.Name},
+		{"ΔΈΛΤΑ", greek.Name},
+		{"foo.Δέλτα", greek.Name},
+		{"foo δέλτα", greek.Name},
+		{"X関数", japanese.Name},
+		{"x関数", japanese.Name},
+		{"関数", ""},
+	} {
+		t.Run(test.q, func(t *testing.T) {
+			opts := SearchOptions{MaxResultCount: 100}
+			resp, err := testDB.hedgedSearch(ctx, test.q, 2, opts, symbolSearchers, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got string
+			if len(resp.results) > 0 {
+				got = resp.results[0].SymbolName
+			}
+			if got != test.want {
+				t.Errorf("got symbol %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
 // TestUpsertSymbolSearch_UniqueConstraints tests for this upsert error:
 // ERROR: ON CONFLICT DO UPDATE command cannot affect row a second time
 // (SQLSTATE 21000)
@@ -141,6 +256,54 @@ func TestUpsertSymbolSearch_UniqueConstraints(t *testing.T) {
 	MustInsertModule(ctx, t, testDB, m2)
 }
 
+func TestBackfillSymbolNameTokens(t *testing.T) {
+	ctx := context.Background()
+	testDB, release := acquire(t)
+	defer release()
+
+	m := sample.DefaultModule()
+	m.Packages()[0].Documentation[0].API = sample.API
+	MustInsertModule(ctx, t, testDB, m)
+
+	// Clear the words, as for documents inserted before migration 000181.
+	var total int
+	if err := testDB.db.QueryRow(ctx, `
+		WITH u AS (UPDATE symbol_search_documents SET tsv_symbol_name_tokens = NULL RETURNING 1)
+		SELECT count(*) FROM u`).Scan(&total); err != nil {
+		t.Fatal(err)
+	}
+	if total < 2 {
+		t.Fatalf("got %d symbol search documents, want at least 2", total)
+	}
+
+	var after, got int
+	for {
+		last, n, err := testDB.BackfillSymbolNameTokens(ctx, after, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n == 0 {
+			break
+		}
+		if last <= after {
+			t.Fatalf("BackfillSymbolNameTokens(ctx, %d, 1) returned last ID %d", after, last)
+		}
+		after = last
+		got += n
+	}
+	if got != total {
+		t.Errorf("backfilled %d documents, want %d", got, total)
+	}
+	var missing int
+	if err := testDB.db.QueryRow(ctx, `
+		SELECT count(*) FROM symbol_search_documents WHERE tsv_symbol_name_tokens IS NULL`).Scan(&missing); err != nil {
+		t.Fatal(err)
+	}
+	if missing != 0 {
+		t.Errorf("%d documents still have no symbol name tokens", missing)
+	}
+}
+
 func TestMultiwordSearchCombinations(t *testing.T) {
 	for _, test := range []struct {
 		q, filter string
@@ -203,3 +366,59 @@ func TestMultiwordSearchCombinations(t *testing.T) {
 		})
 	}
 }
+
+func TestMultiwordTokenSearches(t *testing.T) {
+	for _, test := range []struct {
+		q, filter string
+		want      []tokenSearch
+	}{
+		{
+			q: "client do",
+			want: []tokenSearch{
+				{symbolTokens: "client & do", numTokens: 2},
+			},
+		},
+		{
+			q: "http client do",
+			want: []tokenSearch{
+				{symbolTokens: "http & client", numTokens: 2, pathTokens: "do"},
+				{symbolTokens: "http & client & do", numTokens: 3},
+				{symbolTokens: "client & do", numTokens: 2, pathTokens: "http"},
+			},
+		},
+		{
+			q:    "net/http Client.Do",
+			want: nil,
+		},
+		{
+			q: "net/http Client do",
+			want: []tokenSearch{
+				{symbolTokens: "client & do", numTokens: 2, pathTokens: "net/http"},
+			},
+		},
+		{
+			q:    "github.com ReadAll",
+			want: nil,
+		},
+		{
+			q:    "read read",
+			want: nil,
+		},
+		{
+			q:      "http client do",
+			filter: "do",
+			want:   nil,
+		},
+		{
+			q:    "a b c d e",
+			want: nil,
+		},
+	} {
+		t.Run(test.q, func(t *testing.T) {
+			got := multiwordTokenSearches(test.q, test.filter)
+			if diff := cmp.Diff(test.want, got, cmp.AllowUnexported(tokenSearch{})); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"strings"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// UpdateSearchSynonyms brings the search documents up to date with the
+// current search synonyms (see SetSearchSynonyms). It compares them with
+// the synonyms the search documents were last built with, rebuilds the
+// search documents whose synopsis or README contains a word whose
+// replacements changed, batchSize at a time, and then records the current
+// synonyms. It returns the changed words and the number of search documents
+// rebuilt.
+func (db *DB) UpdateSearchSynonyms(ctx context.Context, batchSize int) (words []string, n int, err error) {
+	defer derrors.WrapStack(&err, "UpdateSearchSynonyms(ctx, %d)", batchSize)
+
+	syns := SearchSynonyms()
+	stored, err := db.getStoredSearchSynonyms(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	words = changedSynonyms(stored, syns)
+	if len(words) == 0 {
+		return nil, 0, nil
+	}
+	after := ""
+	for {
+		argsList, err := db.getSearchDocumentsWithWords(ctx, words, after, batchSize)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(argsList) == 0 {
+			break
+		}
+		for _, args := range argsList {
+			// Symbols is nil, so the existing simhash is left alone.
+			if err := UpsertSearchDocument(ctx, db.db, args); err != nil {
+				return nil, 0, err
+			}
+		}
+		n += len(argsList)
+		after = argsList[len(argsList)-1].PackagePath
+	}
+	if err := db.storeSearchSynonyms(ctx, syns); err != nil {
+		return nil, 0, err
+	}
+	log.Infof(ctx, "rebuilt %d search documents for changed synonyms %v", n, words)
+	return words, n, nil
+}
+
+// getStoredSearchSynonyms returns the synonyms that the search documents were
+// last built with.
+func (db *DB) getStoredSearchSynonyms(ctx context.Context) (_ map[string][]string, err error) {
+	defer derrors.WrapStack(&err, "getStoredSearchSynonyms(ctx)")
+
+	syns := map[string][]string{}
+	err = db.db.RunQuery(ctx, `SELECT word, replacements FROM search_synonyms`, func(rows *sql.Rows) error {
+		var (
+			w  string
+			rs []string
+		)
+		if err := rows.Scan(&w, pq.Array(&rs)); err != nil {
+			return err
+		}
+		syns[w] = rs
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(syns) == 0 {
+		return summaryReplacements, nil
+	}
+	return syns, nil
+}
+
+// storeSearchSynonyms records syns as the synonyms that the search documents
+// were last built with.
+func (db *DB) storeSearchSynonyms(ctx context.Context, syns map[string][]string) (err error) {
+	defer derrors.WrapStack(&err, "storeSearchSynonyms(ctx)")
+
+	var vals []any
+	for w, rs := range syns {
+		if rs == nil {
+			rs = []string{}
+		}
+		vals = append(vals, w, pq.Array(rs))
+	}
+	return db.db.Transact(ctx, sql.LevelDefault, func(tx *database.DB) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM search_synonyms`); err != nil {
+			return err
+		}
+		return tx.BulkInsert(ctx, "search_synonyms", []string{"word", "replacements"}, vals, "")
+	})
+}
+
+// getSearchDocumentsWithWords returns the arguments to rebuild up to limit
+// search documents, in order of package path after afterPath, whose synopsis
+// or README contains one of words.
+func (db *DB) getSearchDocumentsWithWords(ctx context.Context, words []string, afterPath string, limit int) (_ []UpsertSearchDocumentArgs, err error) {
+	defer derrors.WrapStack(&err, "getSearchDocumentsWithWords(ctx, %d words, %q, %d)", len(words), afterPath, limit)
+
+	query := `
+		SELECT
+			sd.package_path,
+			sd.module_path,
+			sd.version,
+			sd.synopsis,
+			sd.redistributable,
+			r.file_path,
+			r.contents
+		FROM search_documents sd
+		INNER JOIN paths p
+		ON p.path = sd.package_path
+		INNER JOIN modules m
+		ON m.module_path = sd.module_path
+		    AND m.version = sd.version
+		INNER JOIN units u
+		ON u.path_id = p.id
+		    AND u.module_id = m.id
+		LEFT JOIN readmes r
+		ON r.unit_id = u.id
+		WHERE sd.package_path > $1
+		    AND (sd.synopsis ~* $2 OR r.contents ~* $2)
+		ORDER BY sd.package_path
+		LIMIT $3`
+
+	var argsList []UpsertSearchDocumentArgs
+	collect := func(rows *sql.Rows) error {
+		var (
+			a      UpsertSearchDocumentArgs
+			redist bool
+		)
+		if err := rows.Scan(&a.PackagePath, &a.ModulePath, &a.Version, &a.Synopsis, &redist,
+			database.NullIsEmpty(&a.ReadmeFilePath), database.NullIsEmpty(&a.ReadmeContents)); err != nil {
+			return err
+		}
+		if !redist && !db.bypassLicenseCheck {
+			a.Synopsis = ""
+			a.ReadmeFilePath = ""
+			a.ReadmeContents = ""
+		}
+		argsList = append(argsList, a)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, afterPath, wordsRegexp(words), limit); err != nil {
+		return nil, err
+	}
+	return argsList, nil
+}
+
+// wordsRegexp returns a Postgres regular expression that matches any of
+// words as a whole word.
+func wordsRegexp(words []string) string {
+	var quoted []string
+	for _, w := range words {
+		quoted = append(quoted, regexp.QuoteMeta(w))
+	}
+	return `\m(` + strings.Join(quoted, "|") + `)\M`
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestUpdateSearchSynonyms(t *testing.T) {
+	// Not parallel: the search synonyms are global.
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+	defer SetSearchSynonyms(nil)
+
+	for _, path := range []string{"example.com/acme", "example.com/other"} {
+		m := sample.Module(path, sample.VersionString, "")
+		m.Units[0].Documentation[0].Synopsis = "A client for " + path[len("example.com/"):] + "."
+		MustInsertModule(ctx, t, testDB, m)
+	}
+
+	// The search documents were built with the built-in synonyms.
+	words, n, err := testDB.UpdateSearchSynonyms(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(words) != 0 || n != 0 {
+		t.Fatalf("got %v, %d; want no changes", words, n)
+	}
+
+	SetSearchSynonyms(map[string][]string{"acme": {"acme", "widgets"}})
+	words, n, err = testDB.UpdateSearchSynonyms(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"acme"}, words); diff != "" {
+		t.Errorf("words mismatch (-want, +got):\n%s", diff)
+	}
+	if n != 1 {
+		t.Errorf("got %d search documents rebuilt, want 1", n)
+	}
+	rs, err := testDB.Search(ctx, "widgets", SearchOptions{MaxResults: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs) != 1 || rs[0].PackagePath != "example.com/acme" {
+		t.Errorf("search for widgets: got %v, want example.com/acme", rs)
+	}
+
+	// Nothing has changed since the last update.
+	words, n, err = testDB.UpdateSearchSynonyms(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(words) != 0 || n != 0 {
+		t.Errorf("got %v, %d; want no changes", words, n)
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// CreateTakedown takes down version of the module at modulePath, or all of
+// its versions if version is empty, and removes it from search and deletes
+// its rendered READMEs. The module's data is kept, so that the takedown can
+// be lifted.
+func (db *DB) CreateTakedown(ctx context.Context, modulePath, version, user, reason, message string) (_ *internal.Takedown, err error) {
+	defer derrors.WrapStack(&err, "CreateTakedown(ctx, %q, %q, %q)", modulePath, version, user)
+
+	t := &internal.Takedown{
+		ModulePath: modulePath,
+		Version:    version,
+		Reason:     reason,
+		Message:    message,
+		CreatedBy:  user,
+	}
+	err = db.db.Transact(ctx, sql.LevelDefault, func(tx *database.DB) error {
+		if err := tx.QueryRow(ctx, `
+			INSERT INTO takedowns (module_path, version, reason, message, created_by)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING id, created_at`,
+			modulePath, version, reason, message, user).Scan(&t.ID, &t.CreatedAt); err != nil {
+			return err
+		}
+		n, err := tx.Exec(ctx, `
+			DELETE FROM search_documents
+			WHERE module_path = $1 AND ($2 = '' OR version = $2)`,
+			modulePath, version)
+		if err != nil {
+			return err
+		}
+		log.Infof(ctx, "took down %s@%s; deleted %d rows from search_documents", modulePath, version, n)
+		// Completions are not stored by version. Those of the versions that
+		// are not taken down are restored by the next
+		// RefreshAutocompleteCompletions, from search_documents.
+		n, err = tx.Exec(ctx, `DELETE FROM autocomplete_completions WHERE module_path = $1`, modulePath)
+		if err != nil {
+			return err
+		}
+		log.Infof(ctx, "took down %s@%s; deleted %d rows from autocomplete_completions", modulePath, version, n)
+		// Rendered READMEs are only a cache, so unlike the rest of the
+		// module's data they need not be kept.
+		n, err = tx.Exec(ctx, `
+			DELETE FROM rendered_readmes
+			WHERE module_id IN (
+				SELECT id FROM modules
+				WHERE module_path = $1 AND ($2 = '' OR version = $2)
+			)`,
+			modulePath, version)
+		if err != nil {
+			return err
+		}
+		log.Infof(ctx, "took down %s@%s; deleted %d rows from rendered_readmes", modulePath, version, n)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// LiftTakedown lifts the takedown with the given ID. It returns the lifted
+// takedown, or an error wrapping derrors.NotFound if there is no such
+// takedown in effect.
+//
+// The module is not restored in search; see ReconcileSearch.
+func (db *DB) LiftTakedown(ctx context.Context, id int64, user string) (_ *internal.Takedown, err error) {
+	defer derrors.WrapStack(&err, "LiftTakedown(ctx, %d, %q)", id, user)
+
+	t, err := scanTakedown(db.db.QueryRow(ctx, `
+		UPDATE takedowns
+		SET lifted_at = CURRENT_TIMESTAMP, lifted_by = $2
+		WHERE id = $1 AND lifted_at IS NULL
+		RETURNING `+takedownColumns,
+		id, user).Scan)
+	if err == sql.ErrNoRows {
+		return nil, derrors.NotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// GetTakedown returns the takedown in effect for version of the module at
+// modulePath. It returns an error wrapping derrors.NotFound if there is none.
+// A takedown of all versions takes precedence over one of version.
+func (db *DB) GetTakedown(ctx context.Context, modulePath, version string) (_ *internal.Takedown, err error) {
+	defer derrors.WrapStack(&err, "GetTakedown(ctx, %q, %q)", modulePath, version)
+
+	t, err := getTakedown(ctx, db.db, modulePath, version)
+	if err == sql.ErrNoRows {
+		return nil, derrors.NotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func getTakedown(ctx context.Context, db *database.DB, modulePath, version string) (*internal.Takedown, error) {
+	return scanTakedown(db.QueryRow(ctx, `
+		SELECT `+takedownColumns+`
+		FROM takedowns
+		WHERE module_path = $1 AND (version = '' OR version = $2) AND lifted_at IS NULL
+		ORDER BY version, id
+		LIMIT 1`,
+		modulePath, version).Scan)
+}
+
+// isTakenDown reports whether version of the module at modulePath is taken
+// down.
+func isTakenDown(ctx context.Context, db *database.DB, modulePath, version string) (bool, error) {
+	_, err := getTakedown(ctx, db, modulePath, version)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// GetTakedowns returns all takedowns, including lifted ones, from newest to
+// oldest.
+func (db *DB) GetTakedowns(ctx context.Context) (_ []*internal.Takedown, err error) {
+	defer derrors.WrapStack(&err, "GetTakedowns(ctx)")
+
+	var ts []*internal.Takedown
+	err = db.db.RunQuery(ctx, `
+		SELECT `+takedownColumns+`
+		FROM takedowns
+		ORDER BY id DESC`,
+		func(rows *sql.Rows) error {
+			t, err := scanTakedown(rows.Scan)
+			if err != nil {
+				return err
+			}
+			ts = append(ts, t)
+			return nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+const takedownColumns = `id, module_path, version, reason, message, created_by, created_at, lifted_by, lifted_at`
+
+func scanTakedown(scan func(dest ...any) error) (*internal.Takedown, error) {
+	var (
+		t        internal.Takedown
+		liftedAt pq.NullTime
+	)
+	if err := scan(&t.ID, &t.ModulePath, &t.Version, &t.Reason, &t.Message, &t.CreatedBy, &t.CreatedAt,
+		database.NullIsEmpty(&t.LiftedBy), &liftedAt); err != nil {
+		return nil, err
+	}
+	if liftedAt.Valid {
+		t.LiftedAt = liftedAt.Time
+	}
+	return &t, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestTakedown(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	const modulePath = "example.com/taken"
+	numSearchDocs := func() int {
+		t.Helper()
+		var n int
+		if err := testDB.db.QueryRow(ctx, `SELECT count(*) FROM search_documents WHERE module_path = $1`,
+			modulePath).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+	checkTakenDown := func(version string, want bool) {
+		t.Helper()
+		_, err := testDB.GetTakedown(ctx, modulePath, version)
+		if got := err == nil; got != want {
+			t.Errorf("GetTakedown(%q): got error %v, want taken down %t", version, err, want)
+		}
+		if err != nil && !errors.Is(err, derrors.NotFound) {
+			t.Fatal(err)
+		}
+	}
+
+	MustInsertModule(ctx, t, testDB, sample.Module(modulePath, "v1.0.0", "pkg"))
+	if numSearchDocs() == 0 {
+		t.Fatal("no search documents before takedown")
+	}
+	td, err := testDB.CreateTakedown(ctx, modulePath, "", "alice", "ticket 1", "gone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTakenDown("v1.0.0", true)
+	if n := numSearchDocs(); n != 0 {
+		t.Errorf("got %d search documents after takedown, want 0", n)
+	}
+
+	// A new version of a module that is taken down stays out of search.
+	MustInsertModule(ctx, t, testDB, sample.Module(modulePath, "v1.1.0", "pkg"))
+	checkTakenDown("v1.1.0", true)
+	if n := numSearchDocs(); n != 0 {
+		t.Errorf("got %d search documents after inserting a new version, want 0", n)
+	}
+
+	lifted, err := testDB.LiftTakedown(ctx, td.ID, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lifted.LiftedBy != "bob" || lifted.LiftedAt.IsZero() {
+		t.Errorf("got lifted by %q at %v, want bob at a non-zero time", lifted.LiftedBy, lifted.LiftedAt)
+	}
+	if _, err := testDB.LiftTakedown(ctx, td.ID, "bob"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("LiftTakedown again: got %v, want NotFound", err)
+	}
+	checkTakenDown("v1.1.0", false)
+	if err := testDB.ReconcileSearch(ctx, modulePath, "", 0); err != nil {
+		t.Fatal(err)
+	}
+	if numSearchDocs() == 0 {
+		t.Error("no search documents after lifting the takedown")
+	}
+
+	// Taking down a single version.
+	if _, err := testDB.CreateTakedown(ctx, modulePath, "v1.0.0", "alice", "ticket 2", ""); err != nil {
+		t.Fatal(err)
+	}
+	checkTakenDown("v1.0.0", true)
+	checkTakenDown("v1.1.0", false)
+
+	got, err := testDB.GetTakedowns(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*internal.Takedown{
+		{ModulePath: modulePath, Version: "v1.0.0", Reason: "ticket 2", CreatedBy: "alice"},
+		{ModulePath: modulePath, Reason: "ticket 1", Message: "gone", CreatedBy: "alice", LiftedBy: "bob"},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(internal.Takedown{}, "ID", "CreatedAt", "LiftedAt")); diff != "" {
+		t.Errorf("GetTakedowns mismatch (-want +got):\n%s", diff)
+	}
+}
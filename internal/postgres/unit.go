@@ -54,38 +54,11 @@ func (db *DB) GetUnitMeta(ctx context.Context, fullPath, requestedModulePath, re
 	return db.getUnitMetaWithKnownVersion(ctx, fullPath, modulePath, v, lmv)
 }
 
-func (db *DB) getUnitMetaWithKnownVersion(ctx context.Context, fullPath, modulePath, version string, lmv *internal.LatestModuleVersions) (_ *internal.UnitMeta, err error) {
+func (db *DB) getUnitMetaWithKnownVersion(ctx context.Context, fullPath, modulePath, vers string, lmv *internal.LatestModuleVersions) (_ *internal.UnitMeta, err error) {
 	defer derrors.WrapStack(&err, "getUnitMetaWithKnownVersion")
 	defer stats.Elapsed(ctx, "getUnitMetaWithKnownVersion")()
 
-	query := squirrel.Select(
-		"m.module_path",
-		"m.version",
-		"m.commit_time",
-		"m.source_info",
-		"m.has_go_mod",
-		"m.redistributable",
-		"u.name").
-		From("modules m").
-		Join("units u on u.module_id = m.id").
-		Join("paths p ON p.id = u.path_id").Where(squirrel.Eq{"p.path": fullPath}).
-		PlaceholderFormat(squirrel.Dollar)
-
-	if internal.DefaultBranches[version] || stdlib.SupportedBranches[version] {
-		query = query.
-			Join("version_map vm ON m.id = vm.module_id").
-			Where("vm.requested_version = ?", version)
-	} else {
-		query = query.Where(squirrel.Eq{"version": version})
-	}
-	if modulePath == internal.UnknownModulePath {
-		// If we don't know the module, look for the one  with the longest series path.
-		query = query.OrderBy("m.series_path DESC").Limit(1)
-	} else {
-		query = query.Where(squirrel.Eq{"m.module_path": modulePath})
-	}
-
-	q, args, err := query.ToSql()
+	q, args, err := unitMetaQuery(fullPath, modulePath, vers).ToSql()
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +71,10 @@ func (db *DB) getUnitMetaWithKnownVersion(ctx context.Context, fullPath, moduleP
 		&um.CommitTime,
 		jsonbScanner{&um.SourceInfo},
 		&um.HasGoMod,
+		database.NullIsEmpty(&um.GoVersion),
+		jsonbScanner{&um.AuthorMetadata},
+		jsonbScanner{&um.Checksum},
+		jsonbScanner{&um.Commit},
 		&um.ModuleInfo.IsRedistributable,
 		&um.Name)
 	if err == sql.ErrNoRows {
@@ -123,6 +100,43 @@ func (db *DB) getUnitMetaWithKnownVersion(ctx context.Context, fullPath, moduleP
 	return &um, nil
 }
 
+// unitMetaQuery returns the query that getUnitMetaWithKnownVersion uses to
+// look up fullPath in modulePath at vers.
+func unitMetaQuery(fullPath, modulePath, vers string) squirrel.SelectBuilder {
+	query := squirrel.Select(
+		"m.module_path",
+		"m.version",
+		"m.commit_time",
+		"m.source_info",
+		"m.has_go_mod",
+		"m.go_version",
+		"m.author_metadata",
+		"m.checksum",
+		"m.commit",
+		"m.redistributable",
+		"u.name").
+		From("modules m").
+		Join("units u on u.module_id = m.id").
+		Join("paths p ON p.id = u.path_id").Where(squirrel.Eq{"p.path": fullPath}).
+		PlaceholderFormat(squirrel.Dollar)
+
+	// Branches and commits are resolved to versions through version_map.
+	if internal.DefaultBranches[vers] || stdlib.SupportedBranches[vers] || version.IsCommitHash(vers) {
+		query = query.
+			Join("version_map vm ON m.id = vm.module_id").
+			Where("vm.requested_version = ?", vers)
+	} else {
+		query = query.Where(squirrel.Eq{"version": vers})
+	}
+	if modulePath == internal.UnknownModulePath {
+		// If we don't know the module, look for the one  with the longest series path.
+		query = query.OrderBy("m.series_path DESC").Limit(1)
+	} else {
+		query = query.Where(squirrel.Eq{"m.module_path": modulePath})
+	}
+	return query
+}
+
 // getLatestUnitVersion gets the latest version of requestedModulePath that contains fullPath.
 // See GetUnitMeta for more details.
 func (db *DB) getLatestUnitVersion(ctx context.Context, fullPath, requestedModulePath string) (
@@ -238,7 +252,8 @@ func (db *DB) GetUnit(ctx context.Context, um *internal.UnitMeta, fields interna
 		}
 	}
 	if fields&internal.WithImports == 0 &&
-		fields&internal.WithLicenses == 0 {
+		fields&internal.WithLicenses == 0 &&
+		fields&internal.WithDocsPages == 0 {
 		return u, nil
 	}
 
@@ -250,7 +265,7 @@ func (db *DB) GetUnit(ctx context.Context, um *internal.UnitMeta, fields interna
 	u.IsRedistributable = isRedistributable
 
 	if fields&internal.WithImports != 0 {
-		imports, err := db.getImports(ctx, unitID)
+		imports, err := db.getImports(ctx, "imports", unitID)
 		if err != nil {
 			return nil, err
 		}
@@ -258,6 +273,13 @@ func (db *DB) GetUnit(ctx context.Context, um *internal.UnitMeta, fields interna
 			u.Imports = imports
 			u.NumImports = len(imports)
 		}
+		testImports, err := db.getImports(ctx, "test_imports", unitID)
+		if err != nil {
+			return nil, err
+		}
+		if len(testImports) > 0 {
+			u.TestImports = testImports
+		}
 	}
 	if fields&internal.WithLicenses != 0 {
 		lics, err := db.getLicenses(ctx, u.Path, u.ModulePath, unitID)
@@ -266,6 +288,12 @@ func (db *DB) GetUnit(ctx context.Context, um *internal.UnitMeta, fields interna
 		}
 		u.LicenseContents = lics
 	}
+	if fields&internal.WithDocsPages != 0 {
+		u.DocsPages, err = getDocsPages(ctx, db.db, unitID, true)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if db.bypassLicenseCheck {
 		u.IsRedistributable = true
 	} else {
@@ -274,21 +302,22 @@ func (db *DB) GetUnit(ctx context.Context, um *internal.UnitMeta, fields interna
 	return u, nil
 }
 
+const unitIDQuery = `
+	SELECT u.id, u.redistributable
+	FROM units u
+	INNER JOIN paths p ON (p.id = u.path_id)
+	INNER JOIN modules m ON (u.module_id = m.id)
+	WHERE
+		p.path = $1
+		AND m.module_path = $2
+		AND m.version = $3;`
+
 func (db *DB) getUnitID(ctx context.Context, fullPath, modulePath, resolvedVersion string) (_ int, _ bool, err error) {
 	defer derrors.WrapStack(&err, "getUnitID(ctx, %q, %q, %q)", fullPath, modulePath, resolvedVersion)
 	defer stats.Elapsed(ctx, "getUnitID")()
 	var unitID int
 	var isRedistributable bool
-	query := `
-		SELECT u.id, u.redistributable
-		FROM units u
-		INNER JOIN paths p ON (p.id = u.path_id)
-		INNER JOIN modules m ON (u.module_id = m.id)
-		WHERE
-			p.path = $1
-			AND m.module_path = $2
-			AND m.version = $3;`
-	err = db.db.QueryRow(ctx, query, fullPath, modulePath, resolvedVersion).Scan(&unitID, &isRedistributable)
+	err = db.db.QueryRow(ctx, unitIDQuery, fullPath, modulePath, resolvedVersion).Scan(&unitID, &isRedistributable)
 	switch err {
 	case sql.ErrNoRows:
 		return 0, false, derrors.NotFound
@@ -299,13 +328,14 @@ func (db *DB) getUnitID(ctx context.Context, fullPath, modulePath, resolvedVersi
 	}
 }
 
-// getImports returns the imports corresponding to unitID.
-func (db *DB) getImports(ctx context.Context, unitID int) (_ []string, err error) {
-	defer derrors.WrapStack(&err, "getImports(ctx, %d)", unitID)
+// getImports returns the imports corresponding to unitID from table, which
+// is either imports or test_imports.
+func (db *DB) getImports(ctx context.Context, table string, unitID int) (_ []string, err error) {
+	defer derrors.WrapStack(&err, "getImports(ctx, %q, %d)", table, unitID)
 	defer stats.Elapsed(ctx, "getImports")()
 	query := `
 		SELECT p.path
-		FROM paths p INNER JOIN imports i ON p.id = i.to_path_id
+		FROM paths p INNER JOIN ` + table + ` i ON p.id = i.to_path_id
 		WHERE i.unit_id = $1`
 	return database.Collect1[string](ctx, db.db, query, unitID)
 }
@@ -474,6 +504,12 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta, b
 			r.contents,
 			d.synopsis,
 			d.source,
+			d.source_hash,
+			d.app_version,
+			d.toolchain,
+			d.skipped_examples,
+			d.broken_doc_links,
+			d.excluded_files,
 			COALESCE((
 				SELECT COUNT(unit_id)
 				FROM imports
@@ -492,7 +528,7 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta, b
 		ON r.unit_id = u.id
 
 		LEFT JOIN (
-			SELECT synopsis, source, goos, goarch, unit_id
+			SELECT synopsis, source, source_hash, app_version, toolchain, skipped_examples, broken_doc_links, excluded_files, goos, goarch, unit_id
 			FROM documentation d
 			WHERE d.GOOS = $3 AND d.GOARCH = $4
         ) d
@@ -516,6 +552,12 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta, b
 		database.NullIsEmpty(&r.Contents),
 		database.NullIsEmpty(&doc.Synopsis),
 		&doc.Source,
+		database.NullIsEmpty(&doc.SourceHash),
+		database.NullIsEmpty(&doc.AppVersion),
+		database.NullIsEmpty(&doc.Toolchain),
+		jsonbScanner{&doc.SkippedExamples},
+		jsonbScanner{&doc.BrokenDocLinks},
+		jsonbScanner{&doc.ExcludedFiles},
 		&u.NumImports,
 		&u.NumImportedBy,
 	)
@@ -533,6 +575,16 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta, b
 		return nil, err
 	}
 	end()
+	if u.Readme != nil {
+		u.LocalizedReadmes, err = getLocalizedReadmes(ctx, db.db, unitID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	u.DocsPages, err = getDocsPages(ctx, db.db, unitID, false)
+	if err != nil {
+		return nil, err
+	}
 	// Get other info.
 	pkgs, err := db.getPackagesInUnit(ctx, um.Path, moduleID)
 	if err != nil {
@@ -552,6 +604,58 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta, b
 	return &u, nil
 }
 
+// getLocalizedReadmes returns the localized READMEs of the unit with the
+// given ID, sorted by language.
+func getLocalizedReadmes(ctx context.Context, db *database.DB, unitID int) (_ []*internal.Readme, err error) {
+	defer derrors.WrapStack(&err, "getLocalizedReadmes(ctx, %d)", unitID)
+
+	var readmes []*internal.Readme
+	collect := func(rows *sql.Rows) error {
+		var r internal.Readme
+		if err := rows.Scan(&r.Lang, &r.Filepath, &r.Contents); err != nil {
+			return err
+		}
+		readmes = append(readmes, &r)
+		return nil
+	}
+	if err := db.RunQuery(ctx, `
+		SELECT lang, file_path, contents
+		FROM localized_readmes
+		WHERE unit_id = $1
+		ORDER BY lang`, collect, unitID); err != nil {
+		return nil, err
+	}
+	return readmes, nil
+}
+
+// getDocsPages returns the docs pages of the unit with the given ID, sorted
+// by file path. Unless withContents is true, only their file paths are set.
+func getDocsPages(ctx context.Context, db *database.DB, unitID int, withContents bool) (_ []*internal.Readme, err error) {
+	defer derrors.WrapStack(&err, "getDocsPages(ctx, %d, %t)", unitID, withContents)
+
+	contents := "''"
+	if withContents {
+		contents = "contents"
+	}
+	var pages []*internal.Readme
+	collect := func(rows *sql.Rows) error {
+		var r internal.Readme
+		if err := rows.Scan(&r.Filepath, &r.Contents); err != nil {
+			return err
+		}
+		pages = append(pages, &r)
+		return nil
+	}
+	if err := db.RunQuery(ctx, `
+		SELECT file_path, `+contents+`
+		FROM docs_pages
+		WHERE unit_id = $1
+		ORDER BY file_path`, collect, unitID); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
 type dbPath struct {
 	id              int64
 	path            string
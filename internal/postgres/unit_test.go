@@ -63,6 +63,14 @@ func testGetUnitMeta(t *testing.T, ctx context.Context) {
 			t.Fatal(err)
 		}
 	}
+	const metadataCommit = "d50f0e9b2506a1c3e7f4b2d6a8c0e9f1b3d5a7c9"
+	if err := testDB.UpsertVersionMap(ctx, &internal.VersionMap{
+		ModulePath:       "cloud.google.com/go/compute/metadata",
+		RequestedVersion: metadataCommit,
+		ResolvedVersion:  "v0.0.0-20181115181204-d50f0e9b2506",
+	}); err != nil {
+		t.Fatal(err)
+	}
 
 	type teststruct struct {
 		name                  string
@@ -167,6 +175,12 @@ func testGetUnitMeta(t *testing.T, ctx context.Context) {
 			version: "master",
 			want:    wantUnitMeta("m.com/b", "v2.0.0+incompatible", ""),
 		},
+		{
+			name:    "module at commit",
+			path:    "cloud.google.com/go/compute/metadata",
+			version: metadataCommit,
+			want:    wantUnitMeta("cloud.google.com/go/compute/metadata", "v0.0.0-20181115181204-d50f0e9b2506", "metadata"),
+		},
 		{
 			name: "prefer pubsublite nested module",
 			path: "cloud.google.com/go/pubsublite",
@@ -717,6 +731,8 @@ func TestGetUnitBuildContext(t *testing.T) {
 	pkg := m.Packages()[0]
 	linuxDoc := sample.Documentation("linux", "amd64", `package p; var L int`)
 	windowsDoc := sample.Documentation("windows", "amd64", `package p; var W int`)
+	linuxDoc.ExcludedFiles = []*internal.ExcludedFile{{Name: "w_windows.go", BuildConstraint: "windows"}}
+	windowsDoc.ExcludedFiles = []*internal.ExcludedFile{{Name: "l_linux.go", BuildConstraint: "linux"}}
 	pkg.Documentation = []*internal.Documentation{linuxDoc, windowsDoc}
 	MustInsertModule(ctx, t, testDB, m)
 
@@ -57,7 +57,12 @@ func getPathVersions(ctx context.Context, db *DB, path string, versionTypes ...v
 		m.commit_time,
 		m.redistributable,
 		m.has_go_mod,
-		m.source_info
+		m.go_version,
+		m.source_info,
+		m.author_metadata,
+		m.checksum,
+		m.content_warnings,
+		m.commit
 	FROM modules m
 	INNER JOIN units u
 		ON u.module_id = m.id
@@ -0,0 +1,297 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware/stats"
+)
+
+// searchModuleVersion searches the packages, or the symbols if
+// opts.SearchSymbols is set, of the module version opts.ModulePath@opts.Version.
+//
+// The search_documents and symbol_search_documents tables only have the
+// latest version of each package, so searchModuleVersion reads the units and
+// documentation tables instead. Every word of the query must appear in a
+// result. There is no text search index on those tables, but a module
+// version has few enough packages that they can be matched and ranked here.
+func (db *DB) searchModuleVersion(ctx context.Context, q string, opts SearchOptions) (_ []*SearchResult, err error) {
+	defer derrors.WrapStack(&err, "searchModuleVersion(ctx, %q, %q, %q)", q, opts.ModulePath, opts.Version)
+	defer stats.Elapsed(ctx, "searchModuleVersion")()
+
+	words := strings.Fields(strings.ToLower(q))
+	if len(words) == 0 {
+		return nil, nil
+	}
+	// A taken-down module version is not served, so none of its packages or
+	// symbols may be found either.
+	takenDown, err := isTakenDown(ctx, db.db, opts.ModulePath, opts.Version)
+	if err != nil {
+		return nil, err
+	}
+	if takenDown {
+		return nil, nil
+	}
+	var results []*SearchResult
+	if opts.SearchSymbols {
+		results, err = db.moduleVersionSymbols(ctx, opts.ModulePath, opts.Version, words)
+		if err != nil {
+			return nil, err
+		}
+		results = rankModuleVersionSymbols(results, words, strings.ToLower(opts.SymbolFilter))
+	} else {
+		results, err = db.moduleVersionPackages(ctx, opts.ModulePath, opts.Version)
+		if err != nil {
+			return nil, err
+		}
+		results = rankModuleVersionPackages(results, words)
+	}
+	var filtered []*SearchResult
+	for _, r := range results {
+		if !db.IsExcluded(ctx, r.PackagePath, r.Version) {
+			filtered = append(filtered, r)
+		}
+	}
+	for _, r := range filtered {
+		r.NumResults = uint64(len(filtered))
+	}
+	if len(filtered) > opts.MaxResults {
+		filtered = filtered[:opts.MaxResults]
+	}
+	return filtered, nil
+}
+
+// moduleVersionPackages returns a search result for every package of the
+// module version. The synopses of packages that are not redistributable are
+// left empty, unless the license check is bypassed.
+func (db *DB) moduleVersionPackages(ctx context.Context, modulePath, version string) (_ []*SearchResult, err error) {
+	// A package has one documentation row per build context. They all have
+	// the same synopsis in practice; prefer the one for all build contexts,
+	// then the one for linux, which is the default.
+	query := `
+		SELECT DISTINCT ON (p.path)
+			p.path,
+			u.name,
+			COALESCE(d.synopsis, ''),
+			u.license_types,
+			m.commit_time,
+			COALESCE(sd.imported_by_count, 0),
+			u.redistributable
+		FROM modules m
+		INNER JOIN units u ON u.module_id = m.id
+		INNER JOIN paths p ON p.id = u.path_id
+		LEFT JOIN documentation d ON d.unit_id = u.id
+		LEFT JOIN search_documents sd ON sd.package_path_id = u.path_id
+		WHERE m.module_path = $1 AND m.version = $2 AND u.name != ''
+		ORDER BY p.path, d.goos = 'all' DESC, d.goos = 'linux' DESC`
+	var results []*SearchResult
+	collect := func(rows *sql.Rows) error {
+		var (
+			r        = SearchResult{ModulePath: modulePath, Version: version}
+			synopsis string
+			redist   bool
+		)
+		if err := rows.Scan(
+			&r.PackagePath,
+			&r.Name,
+			&synopsis,
+			pq.Array(&r.Licenses),
+			&r.CommitTime,
+			&r.NumImportedBy,
+			&redist); err != nil {
+			return err
+		}
+		if redist || db.bypassLicenseCheck {
+			r.Synopsis = synopsis
+		}
+		results = append(results, &r)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, modulePath, version); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// moduleVersionSymbols returns a search result for every symbol of the module
+// version whose name contains one of words, or the part of a word after its
+// first dot, ignoring case. The symbols of packages that are not
+// redistributable are left out, unless the license check is bypassed.
+func (db *DB) moduleVersionSymbols(ctx context.Context, modulePath, version string, words []string) (_ []*SearchResult, err error) {
+	var patterns []string
+	for _, w := range words {
+		patterns = append(patterns, "%"+escapeLike(w)+"%")
+		if _, after, ok := strings.Cut(w, "."); ok && after != "" {
+			// The word may be qualified by a package name, as in
+			// "json.Marshal".
+			patterns = append(patterns, "%"+escapeLike(after)+"%")
+		}
+	}
+	query := `
+		SELECT DISTINCT ON (p.path, s.name)
+			s.name,
+			p.path,
+			u.name,
+			COALESCE(d.synopsis, ''),
+			u.license_types,
+			m.commit_time,
+			COALESCE(sd.imported_by_count, 0),
+			d.goos,
+			d.goarch,
+			ps.type,
+			ps.synopsis
+		FROM modules m
+		INNER JOIN units u ON u.module_id = m.id
+		INNER JOIN paths p ON p.id = u.path_id
+		INNER JOIN documentation d ON d.unit_id = u.id
+		INNER JOIN documentation_symbols ds ON ds.documentation_id = d.id
+		INNER JOIN package_symbols ps ON ps.id = ds.package_symbol_id
+		INNER JOIN symbol_names s ON s.id = ps.symbol_name_id
+		LEFT JOIN search_documents sd ON sd.package_path_id = u.path_id
+		WHERE m.module_path = $1 AND m.version = $2 AND s.name ILIKE ANY($3)
+			AND (u.redistributable OR $4)
+		ORDER BY p.path, s.name, d.goos = 'all' DESC, d.goos = 'linux' DESC`
+	var results []*SearchResult
+	collect := func(rows *sql.Rows) error {
+		r := SearchResult{ModulePath: modulePath, Version: version}
+		if err := rows.Scan(
+			&r.SymbolName,
+			&r.PackagePath,
+			&r.Name,
+			&r.Synopsis,
+			pq.Array(&r.Licenses),
+			&r.CommitTime,
+			&r.NumImportedBy,
+			&r.SymbolGOOS,
+			&r.SymbolGOARCH,
+			&r.SymbolKind,
+			&r.SymbolSynopsis); err != nil {
+			return err
+		}
+		results = append(results, &r)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, modulePath, version, pq.Array(patterns), db.bypassLicenseCheck); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Scores for how well a word of the query matches a result of a search within
+// a module version.
+const (
+	wordMatchExact    = 3    // the word is the name of the package or symbol
+	wordMatchMember   = 2    // the word is the name of a method or field
+	wordMatchName     = 1    // the word is part of the name of the package or symbol
+	wordMatchPath     = 0.5  // the word is part of the package path
+	wordMatchSynopsis = 0.25 // the word is part of the package synopsis
+)
+
+// rankModuleVersionPackages returns the packages in results that match all
+// of words, which are lower case, with their scores set, best first.
+func rankModuleVersionPackages(results []*SearchResult, words []string) []*SearchResult {
+	var ranked []*SearchResult
+	for _, r := range results {
+		var (
+			name     = strings.ToLower(r.Name)
+			pkgPath  = strings.ToLower(r.PackagePath)
+			base     = path.Base(pkgPath)
+			synopsis = strings.ToLower(r.Synopsis)
+		)
+		score, ok := scoreWords(words, func(w string) float64 {
+			switch {
+			case w == name || w == base || w == pkgPath:
+				return wordMatchExact
+			case strings.Contains(base, w):
+				return wordMatchName
+			case strings.Contains(pkgPath, w):
+				return wordMatchPath
+			case strings.Contains(synopsis, w):
+				return wordMatchSynopsis
+			}
+			return 0
+		})
+		if !ok {
+			continue
+		}
+		r.Score = score
+		ranked = append(ranked, r)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		// Prefer packages nearer the root of the module.
+		if len(a.PackagePath) != len(b.PackagePath) {
+			return len(a.PackagePath) < len(b.PackagePath)
+		}
+		return a.PackagePath < b.PackagePath
+	})
+	return ranked
+}
+
+// rankModuleVersionSymbols returns the symbols in results that match all of
+// words, and whose names contain symbolFilter, with their scores set, best
+// first. The words and symbolFilter are lower case.
+func rankModuleVersionSymbols(results []*SearchResult, words []string, symbolFilter string) []*SearchResult {
+	var ranked []*SearchResult
+	for _, r := range results {
+		var (
+			name      = strings.ToLower(r.Name)
+			symbol    = strings.ToLower(r.SymbolName)
+			qualified = name + "." + symbol
+			pkgPath   = strings.ToLower(r.PackagePath)
+		)
+		if !strings.Contains(symbol, symbolFilter) {
+			continue
+		}
+		score, ok := scoreWords(words, func(w string) float64 {
+			switch {
+			case w == symbol || w == qualified:
+				return wordMatchExact
+			case strings.HasSuffix(symbol, "."+w):
+				return wordMatchMember
+			case strings.Contains(qualified, w):
+				return wordMatchName
+			case strings.Contains(pkgPath, w):
+				return wordMatchPath
+			}
+			// A word qualified by the package name, as in "json.Marsh".
+			if pkg, member, ok := strings.Cut(w, "."); ok && pkg == name && strings.Contains(symbol, member) {
+				return wordMatchName
+			}
+			return 0
+		})
+		if !ok {
+			continue
+		}
+		r.Score = score
+		ranked = append(ranked, r)
+	}
+	sort.Slice(ranked, func(i, j int) bool { return lessSymbolResult(ranked[i], ranked[j]) })
+	return ranked
+}
+
+// scoreWords returns the sum of the scores of words, as computed by match.
+// It returns false if match returns zero for any word.
+func scoreWords(words []string, match func(word string) float64) (float64, bool) {
+	var score float64
+	for _, w := range words {
+		s := match(w)
+		if s == 0 {
+			return 0, false
+		}
+		score += s
+	}
+	return score, true
+}
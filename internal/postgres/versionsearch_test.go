@@ -0,0 +1,218 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestSearchModuleVersion(t *testing.T) {
+	ctx := context.Background()
+	testDB, release := acquire(t)
+	defer release()
+
+	const modulePath = "example.com/mod"
+	function := func(name string) *internal.Symbol {
+		return newSymbol(name, internal.SymbolKindFunction, internal.SymbolSectionFunctions)
+	}
+	m1 := sample.Module(modulePath, "v1.0.0", "client", "server")
+	m1.Packages()[0].Documentation[0].API = []*internal.Symbol{function("Dial")}
+	MustInsertModule(ctx, t, testDB, m1)
+	m2 := sample.Module(modulePath, "v1.1.0", "client", "server", "server/middleware")
+	m2.Packages()[0].Documentation[0].API = []*internal.Symbol{function("Dial"), function("DialContext")}
+	MustInsertModule(ctx, t, testDB, m2)
+
+	for _, test := range []struct {
+		name string
+		q    string
+		opts SearchOptions
+		want []string
+	}{
+		{
+			name: "packages in old version",
+			q:    "server",
+			opts: SearchOptions{MaxResults: 10, ModulePath: modulePath, Version: "v1.0.0"},
+			want: []string{"example.com/mod/server"},
+		},
+		{
+			name: "packages in new version",
+			q:    "server",
+			opts: SearchOptions{MaxResults: 10, ModulePath: modulePath, Version: "v1.1.0"},
+			want: []string{"example.com/mod/server", "example.com/mod/server/middleware"},
+		},
+		{
+			name: "symbols in old version",
+			q:    "Dial",
+			opts: SearchOptions{MaxResults: 10, SearchSymbols: true, ModulePath: modulePath, Version: "v1.0.0"},
+			want: []string{"example.com/mod/client.Dial"},
+		},
+		{
+			name: "symbols in new version",
+			q:    "client.Dial",
+			opts: SearchOptions{MaxResults: 10, SearchSymbols: true, ModulePath: modulePath, Version: "v1.1.0"},
+			want: []string{"example.com/mod/client.Dial", "example.com/mod/client.DialContext"},
+		},
+		{
+			name: "no such version",
+			q:    "server",
+			opts: SearchOptions{MaxResults: 10, ModulePath: modulePath, Version: "v2.0.0"},
+			want: nil,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			results, err := testDB.Search(ctx, test.q, test.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, r := range results {
+				if r.Version != test.opts.Version {
+					t.Errorf("%s: got version %s, want %s", r.PackagePath, r.Version, test.opts.Version)
+				}
+				if test.opts.SearchSymbols {
+					got = append(got, r.PackagePath+"."+r.SymbolName)
+				} else {
+					got = append(got, r.PackagePath)
+				}
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSearchModuleVersionNonRedistributable(t *testing.T) {
+	ctx := context.Background()
+	testDB, release := acquire(t)
+	defer release()
+
+	const modulePath = "example.com/nonredist"
+	m := sample.Module(modulePath, "v1.0.0", "client")
+	m.IsRedistributable = false
+	for _, u := range m.Units {
+		u.IsRedistributable = false
+	}
+	m.Packages()[0].Documentation[0].API = []*internal.Symbol{
+		newSymbol("Dial", internal.SymbolKindFunction, internal.SymbolSectionFunctions),
+	}
+	// Insert the documentation, as a database that bypasses the license
+	// check does, so that there is something to withhold.
+	bypassDB := NewBypassingLicenseCheck(testDB.db)
+	MustInsertModule(ctx, t, bypassDB, m)
+
+	for _, db := range []*DB{testDB, bypassDB} {
+		t.Run(fmt.Sprintf("bypass=%t", db.bypassLicenseCheck), func(t *testing.T) {
+			opts := SearchOptions{MaxResults: 10, ModulePath: modulePath, Version: "v1.0.0"}
+			results, err := db.Search(ctx, "client", opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d package results, want 1", len(results))
+			}
+			if got := results[0].Synopsis != ""; got != db.bypassLicenseCheck {
+				t.Errorf("got synopsis %q, want one only when bypassing the license check", results[0].Synopsis)
+			}
+
+			opts.SearchSymbols = true
+			results, err = db.Search(ctx, "Dial", opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := len(results) > 0; got != db.bypassLicenseCheck {
+				t.Errorf("got %d symbol results, want some only when bypassing the license check", len(results))
+			}
+		})
+	}
+}
+
+func TestSearchModuleVersionTakedown(t *testing.T) {
+	ctx := context.Background()
+	testDB, release := acquire(t)
+	defer release()
+
+	const modulePath = "example.com/takendown"
+	m := sample.Module(modulePath, "v1.0.0", "client")
+	m.Packages()[0].Documentation[0].API = []*internal.Symbol{
+		newSymbol("Dial", internal.SymbolKindFunction, internal.SymbolSectionFunctions),
+	}
+	MustInsertModule(ctx, t, testDB, m)
+	if _, err := testDB.CreateTakedown(ctx, modulePath, "v1.0.0", "alice", "ticket", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := SearchOptions{MaxResults: 10, ModulePath: modulePath, Version: "v1.0.0"}
+	results, err := testDB.Search(ctx, "client", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("got %d package results, want none", len(results))
+	}
+	opts.SearchSymbols = true
+	results, err = testDB.Search(ctx, "Dial", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Errorf("got %d symbol results, want none", len(results))
+	}
+}
+
+func TestRankModuleVersionPackages(t *testing.T) {
+	results := []*SearchResult{
+		{Name: "a", PackagePath: "example.com/mod/http/a", Synopsis: "Package a does things."},
+		{Name: "http", PackagePath: "example.com/mod/http", Synopsis: "Package http serves."},
+		{Name: "httputil", PackagePath: "example.com/mod/httputil", Synopsis: "Package httputil helps."},
+		{Name: "b", PackagePath: "example.com/mod/b", Synopsis: "Package b is unrelated."},
+		{Name: "c", PackagePath: "example.com/mod/c", Synopsis: "Package c speaks HTTP."},
+	}
+	var got []string
+	for _, r := range rankModuleVersionPackages(results, []string{"http"}) {
+		got = append(got, r.PackagePath)
+	}
+	want := []string{
+		"example.com/mod/http",
+		"example.com/mod/httputil",
+		"example.com/mod/http/a",
+		"example.com/mod/c",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRankModuleVersionSymbols(t *testing.T) {
+	results := []*SearchResult{
+		{Name: "p", PackagePath: "example.com/mod/p", SymbolName: "NewClient"},
+		{Name: "p", PackagePath: "example.com/mod/p", SymbolName: "Client"},
+		{Name: "q", PackagePath: "example.com/mod/q", SymbolName: "Server.Client"},
+		{Name: "q", PackagePath: "example.com/mod/q", SymbolName: "Server"},
+	}
+	for _, test := range []struct {
+		words  []string
+		filter string
+		want   []string
+	}{
+		{[]string{"client"}, "", []string{"p.Client", "q.Server.Client", "p.NewClient"}},
+		{[]string{"p.client"}, "", []string{"p.Client", "p.NewClient"}},
+		{[]string{"q", "client"}, "client", []string{"q.Server.Client"}},
+	} {
+		var got []string
+		for _, r := range rankModuleVersionSymbols(results, test.words, test.filter) {
+			got = append(got, r.Name+"."+r.SymbolName)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%q, %q: mismatch (-want +got):\n%s", test.words, test.filter, diff)
+		}
+	}
+}
@@ -7,6 +7,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"sort"
@@ -29,7 +30,8 @@ func (db *DB) InsertIndexVersions(ctx context.Context, versions []*internal.Inde
 			(module_path, version)
 		DO UPDATE SET
 			index_timestamp=excluded.index_timestamp,
-			next_processed_after=CURRENT_TIMESTAMP`
+			next_processed_after=CURRENT_TIMESTAMP,
+			retry_attempts=0`
 	return insertIndexVersions(ctx, db.db, versions, conflictAction)
 }
 
@@ -115,6 +117,23 @@ type ModuleVersionStateForUpdate struct {
 	GoModPath            string
 	FetchErr             error
 	PackageVersionStates []*internal.PackageVersionState
+	// Retry is how a failed fetch is retried. If it is nil, failed fetches
+	// are retried after a delay that doubles up to an hour, without limit.
+	Retry *RetryPolicy
+}
+
+// A RetryPolicy describes how fetches that failed with one class of errors
+// are retried. Only statuses of 500 and above are retried.
+type RetryPolicy struct {
+	// Class names the class of errors, for module_version_states.retry_class.
+	Class string
+	// Delay is the time until the first retry, which doubles for each later
+	// retry up to MaxDelay. If it is zero, the error is not retryable and the
+	// count of retry attempts is reset.
+	Delay, MaxDelay time.Duration
+	// MaxAttempts is the number of consecutive retryable failures after which
+	// the version is no longer fetched, or zero for no limit.
+	MaxAttempts int
 }
 
 // UpdateModuleVersionState inserts or updates the module_version_state table with
@@ -156,6 +175,20 @@ func updateModuleVersionState(ctx context.Context, db *database.DB, numPackages
 	if mvs.FetchErr != nil {
 		sqlErrorMsg = mvs.FetchErr.Error()
 	}
+	var (
+		retryClass      string
+		retryable       bool
+		delay, maxDelay float64 // seconds
+		maxAttempts     *int
+	)
+	if r := mvs.Retry; r != nil {
+		retryClass = r.Class
+		retryable = r.Delay > 0
+		delay, maxDelay = r.Delay.Seconds(), max(r.Delay, r.MaxDelay).Seconds()
+		if retryable && r.MaxAttempts > 0 {
+			maxAttempts = &r.MaxAttempts
+		}
+	}
 
 	affected, err := db.Exec(ctx, `
 		UPDATE module_version_states
@@ -167,8 +200,17 @@ func updateModuleVersionState(ctx context.Context, db *database.DB, numPackages
 			num_packages=$6,
 			try_count=try_count+1,
 			last_processed_at=CURRENT_TIMESTAMP,
-			-- back off exponentially until 1 hour, then at constant 1-hour intervals
+			retry_class=$9,
+			retry_attempts=CASE WHEN $10 THEN retry_attempts+1 ELSE 0 END,
+			max_retry_attempts=$13,
 			next_processed_after=CASE
+				-- back off exponentially from the delay of the policy, up to its
+				-- maximum; retry_attempts is the count before this fetch
+				WHEN $10 THEN
+					CURRENT_TIMESTAMP + LEAST(
+						make_interval(secs => $11 * power(2, LEAST(retry_attempts, 30))),
+						make_interval(secs => $12))
+				-- back off exponentially until 1 hour, then at constant 1-hour intervals
 				WHEN last_processed_at IS NULL THEN
 					CURRENT_TIMESTAMP + INTERVAL '1 minute'
 				WHEN 2*(next_processed_after - last_processed_at) < INTERVAL '1 hour' THEN
@@ -186,7 +228,12 @@ func updateModuleVersionState(ctx context.Context, db *database.DB, numPackages
 		sqlErrorMsg,
 		numPackages,
 		mvs.ModulePath,
-		mvs.Version)
+		mvs.Version,
+		retryClass,
+		retryable,
+		delay,
+		maxDelay,
+		maxAttempts)
 	if err != nil {
 		return err
 	}
@@ -240,7 +287,14 @@ func upsertPackageVersionStates(ctx context.Context, db *database.DB, packageVer
 	})
 	var vals []any
 	for _, pvs := range packageVersionStates {
-		vals = append(vals, pvs.PackagePath, pvs.ModulePath, pvs.Version, pvs.Status, pvs.Error)
+		var diags []byte // NULL if there are no diagnostics
+		if len(pvs.Diagnostics) > 0 {
+			diags, err = json.Marshal(pvs.Diagnostics)
+			if err != nil {
+				return err
+			}
+		}
+		vals = append(vals, pvs.PackagePath, pvs.ModulePath, pvs.Version, pvs.Status, pvs.Error, diags)
 	}
 	return db.BulkInsert(ctx, "package_version_states",
 		[]string{
@@ -249,6 +303,7 @@ func upsertPackageVersionStates(ctx context.Context, db *database.DB, packageVer
 			"version",
 			"status",
 			"error",
+			"diagnostics",
 		},
 		vals,
 		`ON CONFLICT (module_path, package_path, version)
@@ -258,7 +313,8 @@ func upsertPackageVersionStates(ctx context.Context, db *database.DB, packageVer
 					module_path=excluded.module_path,
 					version=excluded.version,
 					status=excluded.status,
-					error=excluded.error`)
+					error=excluded.error,
+					diagnostics=excluded.diagnostics`)
 }
 
 // LatestIndexTimestamp returns the last timestamp successfully inserted into
@@ -296,7 +352,10 @@ const moduleVersionStateColumns = `
 			app_version,
 			has_go_mod,
 			go_mod_path,
-			num_packages`
+			num_packages,
+			retry_class,
+			retry_attempts,
+			max_retry_attempts`
 
 // scanModuleVersionState constructs an *internal.ModuleModuleVersionState from the given
 // scanner. It expects columns to be in the order of moduleVersionStateColumns.
@@ -307,12 +366,14 @@ func scanModuleVersionState(scan func(dest ...any) error) (*internal.ModuleVersi
 		lastProcessedAt pq.NullTime
 		numPackages     sql.NullInt64
 		hasGoMod        sql.NullBool
+		maxAttempts     sql.NullInt64
 	)
 	if err := scan(&v.ModulePath, &v.Version, &indexTimestamp, &v.CreatedAt, &v.Status, &v.Error,
 		&v.TryCount, &v.LastProcessedAt, &v.NextProcessedAfter, &v.AppVersion, &hasGoMod, &v.GoModPath,
-		&numPackages); err != nil {
+		&numPackages, &v.RetryClass, &v.RetryAttempts, &maxAttempts); err != nil {
 		return nil, err
 	}
+	v.MaxRetryAttempts = int(maxAttempts.Int64)
 	if indexTimestamp.Valid {
 		it := indexTimestamp.Time
 		v.IndexTimestamp = &it
@@ -417,7 +478,8 @@ func (db *DB) GetPackageVersionStatesForModule(ctx context.Context, modulePath,
 			module_path,
 			version,
 			status,
-			error
+			error,
+			diagnostics
 		FROM
 			package_version_states
 		WHERE
@@ -428,7 +490,7 @@ func (db *DB) GetPackageVersionStatesForModule(ctx context.Context, modulePath,
 	collect := func(rows *sql.Rows) error {
 		var s internal.PackageVersionState
 		if err := rows.Scan(&s.PackagePath, &s.ModulePath, &s.Version,
-			&s.Status, &s.Error); err != nil {
+			&s.Status, &s.Error, jsonbScanner{&s.Diagnostics}); err != nil {
 			return fmt.Errorf("rows.Scan(): %v", err)
 		}
 		states = append(states, &s)
@@ -451,7 +513,8 @@ func (db *DB) GetPackageVersionState(ctx context.Context, pkgPath, modulePath, r
 			module_path,
 			version,
 			status,
-			error
+			error,
+			diagnostics
 		FROM
 			package_version_states
 		WHERE
@@ -462,7 +525,7 @@ func (db *DB) GetPackageVersionState(ctx context.Context, pkgPath, modulePath, r
 	var pvs internal.PackageVersionState
 	err = db.db.QueryRow(ctx, query, pkgPath, modulePath, resolvedVersion).Scan(
 		&pvs.PackagePath, &pvs.ModulePath, &pvs.Version,
-		&pvs.Status, &pvs.Error)
+		&pvs.Status, &pvs.Error, jsonbScanner{&pvs.Diagnostics})
 	switch err {
 	case nil:
 		return &pvs, nil
@@ -138,6 +138,9 @@ func TestModuleVersionState(t *testing.T) {
 			PackagePath: "foo.com/bar/foo",
 			Version:     "v1.0.0",
 			Status:      500,
+			Diagnostics: []*internal.PackageDiagnostic{
+				{File: "foo/foo.go", Line: 3, Column: 1, Message: "expected declaration, found x"},
+			},
 		}
 	)
 	mvs := &ModuleVersionStateForUpdate{
@@ -363,6 +366,77 @@ func TestUpdateModuleVersionStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateModuleVersionStateRetry(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	testDB, release := acquire(t)
+	defer release()
+
+	const modulePath, version = "m.com", "v1.2.3"
+	must(t, testDB.InsertIndexVersions(ctx,
+		[]*internal.IndexVersion{{Path: modulePath, Version: version, Timestamp: time.Now()}}))
+	policy := &RetryPolicy{Class: "proxy", Delay: time.Minute, MaxDelay: 3 * time.Minute, MaxAttempts: 3}
+
+	// update records a fetch with the given status and policy, and returns the
+	// resulting state and the delay until the next fetch.
+	update := func(status int, p *RetryPolicy) (*internal.ModuleVersionState, time.Duration) {
+		t.Helper()
+		must(t, testDB.UpdateModuleVersionState(ctx, &ModuleVersionStateForUpdate{
+			ModulePath: modulePath,
+			Version:    version,
+			Status:     status,
+			Retry:      p,
+		}))
+		got, err := testDB.GetModuleVersionState(ctx, modulePath, version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Make the version due, so that GetNextModulesToFetch can return it.
+		if _, err := testDB.db.Exec(ctx, `
+			UPDATE module_version_states
+			SET next_processed_after = CURRENT_TIMESTAMP - INTERVAL '1 second'
+			WHERE module_path = $1 AND version = $2`, modulePath, version); err != nil {
+			t.Fatal(err)
+		}
+		return got, got.NextProcessedAfter.Sub(*got.LastProcessedAt)
+	}
+	due := func() bool {
+		t.Helper()
+		mvs, err := testDB.GetNextModulesToFetch(ctx, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(mvs) == 1
+	}
+
+	for i, wantDelay := range []time.Duration{time.Minute, 2 * time.Minute, 3 * time.Minute} {
+		got, delay := update(551, policy)
+		if got.RetryClass != "proxy" || got.RetryAttempts != i+1 || got.MaxRetryAttempts != 3 {
+			t.Errorf("attempt %d: got class %q, attempts %d of %d; want %q, %d of 3",
+				i+1, got.RetryClass, got.RetryAttempts, got.MaxRetryAttempts, "proxy", i+1)
+		}
+		if delay < wantDelay-time.Second || delay > wantDelay+time.Second {
+			t.Errorf("attempt %d: delay %s, want %s", i+1, delay, wantDelay)
+		}
+		if wantDue := i < 2; due() != wantDue {
+			t.Errorf("attempt %d: due = %t, want %t", i+1, !wantDue, wantDue)
+		}
+	}
+
+	// Requeuing the version makes it due again.
+	must(t, testDB.UpdateModuleVersionStatesWithStatus(ctx, 551, "zzz"))
+	if !due() {
+		t.Error("after requeuing: not due")
+	}
+
+	// A permanent result resets the attempts.
+	got, _ := update(http.StatusOK, &RetryPolicy{Class: "permanent"})
+	if got.RetryClass != "permanent" || got.RetryAttempts != 0 || got.MaxRetryAttempts != 0 {
+		t.Errorf("after success: got class %q, attempts %d of %d; want %q, 0 of 0",
+			got.RetryClass, got.RetryAttempts, got.MaxRetryAttempts, "permanent")
+	}
+}
+
 func TestHasGoMod(t *testing.T) {
 	ptr := func(b bool) *bool { return &b }
 
@@ -43,6 +43,18 @@ type Client struct {
 type VersionInfo struct {
 	Version string
 	Time    time.Time
+	// Origin describes where the proxy got the version from. Not all proxies
+	// report it, so it may be nil.
+	Origin *Origin
+}
+
+// Origin describes the version control source of a module version, as
+// reported by the go command in the .info file.
+type Origin struct {
+	VCS  string `json:",omitempty"` // such as "git"
+	URL  string `json:",omitempty"` // the repository URL
+	Ref  string `json:",omitempty"` // such as "refs/tags/v1.2.3"
+	Hash string `json:",omitempty"` // the commit hash
 }
 
 // Setting this header to true prevents the proxy from fetching uncached
@@ -162,6 +162,36 @@ func TestInfo(t *testing.T) {
 	}
 }
 
+func TestInfoOrigin(t *testing.T) {
+	ctx := context.Background()
+
+	proxyServer := proxytest.NewServer(nil)
+	proxyServer.AddRoute(
+		fmt.Sprintf("/%s/@v/%s.info", "module.com/origin", sample.VersionString),
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"Version": %q, "Time": "2019-01-30T00:00:00Z", "Origin": {"VCS": "git", "URL": "https://module.com/origin", "Ref": "refs/tags/%[1]s", "Hash": "abc123"}}`, sample.VersionString)
+		})
+	client, teardownProxy, err := proxytest.NewClientForServer(proxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardownProxy()
+
+	info, err := client.Info(ctx, "module.com/origin", sample.VersionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &proxy.Origin{
+		VCS:  "git",
+		URL:  "https://module.com/origin",
+		Ref:  "refs/tags/" + sample.VersionString,
+		Hash: "abc123",
+	}
+	if diff := cmp.Diff(want, info.Origin); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
 func TestInfo_Errors(t *testing.T) {
 	ctx := context.Background()
 
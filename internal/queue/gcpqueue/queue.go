@@ -13,12 +13,14 @@ import (
 	"hash/fnv"
 	"io"
 	"math"
+	"sort"
 	"strings"
 	"time"
 
 	cloudtasks "cloud.google.com/go/cloudtasks/apiv2"
 	taskspb "cloud.google.com/go/cloudtasks/apiv2/cloudtaskspb"
 	"golang.org/x/pkgsite/internal/config/serverconfig"
+	"google.golang.org/api/iterator"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/durationpb"
@@ -140,6 +142,62 @@ func (q *gcp) ScheduleFetch(ctx context.Context, modulePath, version string, opt
 	return enqueued, nil
 }
 
+// maxStatsTasks is the most tasks that Stats will list. Listing is paged,
+// so a long queue would take a while.
+const maxStatsTasks = 5000
+
+// Stats lists the tasks in the queue to report its depth and the tasks that
+// have been attempted more than once. It stops counting at maxStatsTasks.
+func (q *gcp) Stats(ctx context.Context) (_ *queue.Stats, err error) {
+	defer derrors.WrapStack(&err, "queue.Stats")
+
+	stats := &queue.Stats{}
+	it := q.client.ListTasks(ctx, &taskspb.ListTasksRequest{
+		Parent:       q.queueName,
+		ResponseView: taskspb.Task_BASIC,
+		PageSize:     1000,
+	})
+	for {
+		task, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if stats.Depth == maxStatsTasks {
+			stats.DepthIsLowerBound = true
+			break
+		}
+		stats.Depth++
+		if ta := taskAttempts(task); ta != nil && ta.Attempts > 1 {
+			stats.Retried = append(stats.Retried, ta)
+		}
+	}
+	sort.Slice(stats.Retried, func(i, j int) bool { return stats.Retried[i].Attempts > stats.Retried[j].Attempts })
+	return stats, nil
+}
+
+// taskAttempts returns the module version that task fetches and the number
+// of times it has been dispatched, or nil if task isn't a fetch task.
+func taskAttempts(task *taskspb.Task) *queue.TaskAttempts {
+	u := task.GetHttpRequest().GetUrl()
+	_, mv, ok := strings.Cut(u, "/fetch/")
+	if !ok {
+		return nil
+	}
+	mv, _, _ = strings.Cut(mv, "?")
+	modulePath, version, ok := strings.Cut(mv, "/@v/")
+	if !ok {
+		return nil
+	}
+	return &queue.TaskAttempts{
+		ModulePath: modulePath,
+		Version:    version,
+		Attempts:   int(task.DispatchCount),
+	}
+}
+
 func (q *gcp) newTaskRequest(modulePath, version string, opts *queue.Options) *taskspb.CreateTaskRequest {
 	taskID := newTaskID(modulePath, version)
 	relativeURI := fmt.Sprintf("/fetch/%s/@v/%s", modulePath, version)
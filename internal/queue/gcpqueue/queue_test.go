@@ -79,3 +79,28 @@ func TestNewTaskRequest(t *testing.T) {
 	}
 
 }
+
+func TestTaskAttempts(t *testing.T) {
+	cfg := config.Config{
+		ProjectID:      "Project",
+		LocationID:     "us-central1",
+		QueueURL:       "http://1.2.3.4:8000",
+		ServiceAccount: "sa",
+		QueueAudience:  "qa",
+	}
+	gcp, err := newGCP(&cfg, nil, "queueID")
+	if err != nil {
+		t.Fatal(err)
+	}
+	task := gcp.newTaskRequest("example.com/mod", "v1.2.3", &queue.Options{DisableProxyFetch: true}).Task
+	task.DispatchCount = 3
+	got := taskAttempts(task)
+	want := &queue.TaskAttempts{ModulePath: "example.com/mod", Version: "v1.2.3", Attempts: 3}
+	if !cmp.Equal(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	if got := taskAttempts(&taskspb.Task{}); got != nil {
+		t.Errorf("task without an HTTP request: got %+v, want nil", got)
+	}
+}
@@ -9,6 +9,7 @@ package queue
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/pkgsite/internal"
@@ -21,6 +22,31 @@ type Queue interface {
 	ScheduleFetch(ctx context.Context, modulePath, version string, opts *Options) (bool, error)
 }
 
+// Stats describes the tasks in a Queue.
+type Stats struct {
+	// Depth is the number of tasks in the queue, including running ones.
+	Depth int `json:"depth"`
+	// DepthIsLowerBound reports whether the queue stopped counting tasks
+	// at Depth, so that it may hold more.
+	DepthIsLowerBound bool `json:"depthIsLowerBound,omitempty"`
+	// Retried lists the tasks in the queue that have been attempted more
+	// than once. It is empty for queues that don't retry.
+	Retried []*TaskAttempts `json:"retried,omitempty"`
+}
+
+// TaskAttempts is the number of times the task to fetch a module version
+// has been attempted.
+type TaskAttempts struct {
+	ModulePath string `json:"modulePath"`
+	Version    string `json:"version"`
+	Attempts   int    `json:"attempts"`
+}
+
+// A StatsReporter is a Queue that can report statistics about its tasks.
+type StatsReporter interface {
+	Stats(ctx context.Context) (*Stats, error)
+}
+
 // Options is used to provide option arguments for a task queue.
 type Options struct {
 	// DisableProxyFetch reports whether proxyfetch should be set to off when
@@ -53,6 +79,7 @@ type InMemory struct {
 	queue       chan internal.Modver
 	done        chan struct{}
 	experiments []string
+	running     atomic.Int64 // fetches taken from queue but not finished
 }
 
 type InMemoryProcessFunc func(context.Context, string, string) (int, error)
@@ -69,8 +96,10 @@ func NewInMemory(ctx context.Context, workerCount int, experiments []string, pro
 	sem := make(chan struct{}, workerCount)
 	go func() {
 		for v := range q.queue {
+			q.running.Add(1)
 			select {
 			case <-ctx.Done():
+				q.running.Add(-1)
 				return
 			case sem <- struct{}{}:
 			}
@@ -79,6 +108,7 @@ func NewInMemory(ctx context.Context, workerCount int, experiments []string, pro
 			// goroutine and wait for it to finish.
 			go func(v internal.Modver) {
 				defer func() { <-sem }()
+				defer q.running.Add(-1)
 
 				log.Infof(ctx, "Fetch requested: %s (workerCount = %d)", v, cap(sem))
 
@@ -110,6 +140,11 @@ func (q *InMemory) ScheduleFetch(ctx context.Context, modulePath, version string
 	return true, nil
 }
 
+// Stats reports the number of fetches waiting or in progress.
+func (q *InMemory) Stats(ctx context.Context) (*Stats, error) {
+	return &Stats{Depth: len(q.queue) + int(q.running.Load())}, nil
+}
+
 // WaitForTesting waits for all queued requests to finish. It should only be
 // used by test code.
 func (q *InMemory) WaitForTesting(ctx context.Context) {
@@ -0,0 +1,101 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sbom
+
+import (
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+)
+
+// The parts of a CycloneDX 1.5 document written by this package; see
+// https://cyclonedx.org/docs/1.5/json/.
+type (
+	cdxDoc struct {
+		BOMFormat    string          `json:"bomFormat"`
+		SpecVersion  string          `json:"specVersion"`
+		Version      int             `json:"version"`
+		Metadata     cdxMetadata     `json:"metadata"`
+		Components   []cdxComponent  `json:"components"`
+		Dependencies []cdxDependency `json:"dependencies"`
+	}
+
+	cdxMetadata struct {
+		Timestamp string       `json:"timestamp"`
+		Tools     cdxTools     `json:"tools"`
+		Component cdxComponent `json:"component"`
+	}
+
+	cdxTools struct {
+		Components []cdxTool `json:"components"`
+	}
+
+	cdxTool struct {
+		Type string `json:"type"`
+		Name string `json:"name"`
+	}
+
+	cdxComponent struct {
+		Type     string       `json:"type"`
+		BOMRef   string       `json:"bom-ref"`
+		Name     string       `json:"name"`
+		Version  string       `json:"version"`
+		PURL     string       `json:"purl"`
+		Scope    string       `json:"scope,omitempty"`
+		Licenses []cdxLicense `json:"licenses,omitempty"`
+	}
+
+	cdxLicense struct {
+		License cdxLicenseChoice `json:"license"`
+	}
+
+	cdxLicenseChoice struct {
+		ID string `json:"id"`
+	}
+
+	cdxDependency struct {
+		Ref       string   `json:"ref"`
+		DependsOn []string `json:"dependsOn"`
+	}
+)
+
+func cycloneDXDocument(md *internal.ModuleDependencies, created time.Time) *cdxDoc {
+	main := newCDXComponent(md.ModulePath, md.Version, md.LicenseTypes)
+	doc := &cdxDoc{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: cdxMetadata{
+			Timestamp: created.UTC().Format(time.RFC3339),
+			Tools:     cdxTools{Components: []cdxTool{{Type: "application", Name: toolName}}},
+			Component: main,
+		},
+		Components: []cdxComponent{},
+	}
+	mainDep := cdxDependency{Ref: main.BOMRef, DependsOn: []string{}}
+	for _, r := range md.Requirements {
+		c := newCDXComponent(r.ModulePath, r.Version, r.LicenseTypes)
+		c.Scope = "required"
+		doc.Components = append(doc.Components, c)
+		mainDep.DependsOn = append(mainDep.DependsOn, c.BOMRef)
+	}
+	doc.Dependencies = []cdxDependency{mainDep}
+	return doc
+}
+
+func newCDXComponent(modulePath, version string, licenseTypes []string) cdxComponent {
+	purl := packageURL(modulePath, version)
+	c := cdxComponent{
+		Type:    "library",
+		BOMRef:  purl,
+		Name:    modulePath,
+		Version: version,
+		PURL:    purl,
+	}
+	for _, id := range licenseIDs(licenseTypes) {
+		c.Licenses = append(c.Licenses, cdxLicense{cdxLicenseChoice{ID: id}})
+	}
+	return c
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sbom generates software bills of materials (SBOMs) for module
+// versions, in the SPDX and CycloneDX formats, from their go.mod
+// requirements and licenses.
+package sbom
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+)
+
+// The supported SBOM formats.
+const (
+	FormatSPDX      = "spdx"
+	FormatCycloneDX = "cyclonedx"
+)
+
+// ContentType returns the media type of documents in the given format.
+func ContentType(format string) string {
+	switch format {
+	case FormatSPDX:
+		return "application/spdx+json"
+	case FormatCycloneDX:
+		return "application/vnd.cyclonedx+json"
+	default:
+		return ""
+	}
+}
+
+// FileExtension returns the conventional extension of files holding
+// documents in the given format.
+func FileExtension(format string) string {
+	switch format {
+	case FormatSPDX:
+		return ".spdx.json"
+	case FormatCycloneDX:
+		return ".cdx.json"
+	default:
+		return ""
+	}
+}
+
+// toolName identifies pkgsite as the creator of SBOMs.
+const toolName = "pkgsite"
+
+// Generate returns an SBOM for md in the given format. The namespace is a URI
+// that identifies the document, and created is the time it was created.
+func Generate(md *internal.ModuleDependencies, format, namespace string, created time.Time) ([]byte, error) {
+	var doc any
+	switch format {
+	case FormatSPDX:
+		doc = spdxDocument(md, namespace, created)
+	case FormatCycloneDX:
+		doc = cycloneDXDocument(md, created)
+	default:
+		return nil, fmt.Errorf("unknown SBOM format %q", format)
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// packageURL returns the package URL (purl) of a module version; see
+// https://github.com/package-url/purl-spec.
+func packageURL(modulePath, version string) string {
+	segs := strings.Split(modulePath, "/")
+	for i, s := range segs {
+		segs[i] = purlEscape(s)
+	}
+	return "pkg:golang/" + strings.Join(segs, "/") + "@" + purlEscape(version)
+}
+
+// purlEscape percent-encodes s for use in a purl. Unlike in URL paths, a '+'
+// must be encoded.
+func purlEscape(s string) string {
+	return strings.ReplaceAll(url.PathEscape(s), "+", "%2B")
+}
+
+// licenseIDRegexp matches the syntax of SPDX license identifiers.
+var licenseIDRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.+-]*$`)
+
+// licenseIDs returns the license types that can be written as SPDX license
+// identifiers. The types detected by pkgsite are SPDX identifiers, except for
+// UNKNOWN.
+func licenseIDs(types []string) []string {
+	var ids []string
+	for _, t := range types {
+		if t != "UNKNOWN" && licenseIDRegexp.MatchString(t) {
+			ids = append(ids, t)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sbom
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+)
+
+var (
+	testDeps = &internal.ModuleDependencies{
+		ModulePath:   "example.com/mod",
+		Version:      "v1.0.0",
+		LicenseTypes: []string{"Apache-2.0", "MIT"},
+		Requirements: []*internal.RequiredModule{
+			{
+				ModuleRequirement: internal.ModuleRequirement{ModulePath: "example.com/a", Version: "v2.0.0+incompatible"},
+				LicenseTypes:      []string{"BSD-3-Clause"},
+			},
+			{
+				ModuleRequirement: internal.ModuleRequirement{ModulePath: "example.com/b", Version: "v0.1.0", Indirect: true},
+				LicenseTypes:      []string{"UNKNOWN"},
+			},
+		},
+	}
+	testCreated = time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+)
+
+func TestGenerateSPDX(t *testing.T) {
+	b, err := Generate(testDeps, FormatSPDX, "https://pkg.go.dev/sbom/example.com/mod@v1.0.0", testCreated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got spdxDoc
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	pkg := func(id, name, version, declared, purl string) spdxPackage {
+		return spdxPackage{
+			SPDXID:           id,
+			Name:             name,
+			VersionInfo:      version,
+			DownloadLocation: "NOASSERTION",
+			LicenseConcluded: "NOASSERTION",
+			LicenseDeclared:  declared,
+			CopyrightText:    "NOASSERTION",
+			ExternalRefs:     []spdxExternalRef{{"PACKAGE-MANAGER", "purl", purl}},
+		}
+	}
+	want := spdxDoc{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              "example.com/mod@v1.0.0",
+		DocumentNamespace: "https://pkg.go.dev/sbom/example.com/mod@v1.0.0",
+		CreationInfo: spdxCreationInfo{
+			Created:  "2026-01-02T03:04:05Z",
+			Creators: []string{"Tool: pkgsite"},
+		},
+		Packages: []spdxPackage{
+			pkg("SPDXRef-Package-0", "example.com/mod", "v1.0.0", "Apache-2.0 AND MIT", "pkg:golang/example.com/mod@v1.0.0"),
+			pkg("SPDXRef-Package-1", "example.com/a", "v2.0.0+incompatible", "BSD-3-Clause", "pkg:golang/example.com/a@v2.0.0%2Bincompatible"),
+			pkg("SPDXRef-Package-2", "example.com/b", "v0.1.0", "NOASSERTION", "pkg:golang/example.com/b@v0.1.0"),
+		},
+		Relationships: []spdxRelationship{
+			{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Package-0"},
+			{"SPDXRef-Package-0", "DEPENDS_ON", "SPDXRef-Package-1"},
+			{"SPDXRef-Package-0", "DEPENDS_ON", "SPDXRef-Package-2"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestGenerateCycloneDX(t *testing.T) {
+	b, err := Generate(testDeps, FormatCycloneDX, "", testCreated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got cdxDoc
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	main := cdxComponent{
+		Type:    "library",
+		BOMRef:  "pkg:golang/example.com/mod@v1.0.0",
+		Name:    "example.com/mod",
+		Version: "v1.0.0",
+		PURL:    "pkg:golang/example.com/mod@v1.0.0",
+		Licenses: []cdxLicense{
+			{cdxLicenseChoice{ID: "Apache-2.0"}},
+			{cdxLicenseChoice{ID: "MIT"}},
+		},
+	}
+	want := cdxDoc{
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata: cdxMetadata{
+			Timestamp: "2026-01-02T03:04:05Z",
+			Tools:     cdxTools{Components: []cdxTool{{Type: "application", Name: "pkgsite"}}},
+			Component: main,
+		},
+		Components: []cdxComponent{
+			{
+				Type:     "library",
+				BOMRef:   "pkg:golang/example.com/a@v2.0.0%2Bincompatible",
+				Name:     "example.com/a",
+				Version:  "v2.0.0+incompatible",
+				PURL:     "pkg:golang/example.com/a@v2.0.0%2Bincompatible",
+				Scope:    "required",
+				Licenses: []cdxLicense{{cdxLicenseChoice{ID: "BSD-3-Clause"}}},
+			},
+			{
+				Type:    "library",
+				BOMRef:  "pkg:golang/example.com/b@v0.1.0",
+				Name:    "example.com/b",
+				Version: "v0.1.0",
+				PURL:    "pkg:golang/example.com/b@v0.1.0",
+				Scope:   "required",
+			},
+		},
+		Dependencies: []cdxDependency{{
+			Ref:       "pkg:golang/example.com/mod@v1.0.0",
+			DependsOn: []string{"pkg:golang/example.com/a@v2.0.0%2Bincompatible", "pkg:golang/example.com/b@v0.1.0"},
+		}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestGenerateUnknownFormat(t *testing.T) {
+	if _, err := Generate(testDeps, "xml", "", testCreated); err == nil {
+		t.Error("got nil error, want one")
+	}
+}
+
+func TestPackageURL(t *testing.T) {
+	for _, test := range []struct {
+		modulePath, version, want string
+	}{
+		{"golang.org/x/text", "v0.3.0", "pkg:golang/golang.org/x/text@v0.3.0"},
+		{"example.com/a", "v2.0.0+incompatible", "pkg:golang/example.com/a@v2.0.0%2Bincompatible"},
+		{"example.com/a b", "v1.0.0", "pkg:golang/example.com/a%20b@v1.0.0"},
+	} {
+		if got := packageURL(test.modulePath, test.version); got != test.want {
+			t.Errorf("packageURL(%q, %q) = %q, want %q", test.modulePath, test.version, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sbom
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+)
+
+// The parts of an SPDX 2.3 document written by this package; see
+// https://spdx.github.io/spdx-spec/v2.3/.
+type (
+	spdxDoc struct {
+		SPDXVersion       string             `json:"spdxVersion"`
+		DataLicense       string             `json:"dataLicense"`
+		SPDXID            string             `json:"SPDXID"`
+		Name              string             `json:"name"`
+		DocumentNamespace string             `json:"documentNamespace"`
+		CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+		Packages          []spdxPackage      `json:"packages"`
+		Relationships     []spdxRelationship `json:"relationships"`
+	}
+
+	spdxCreationInfo struct {
+		Created  string   `json:"created"`
+		Creators []string `json:"creators"`
+	}
+
+	spdxPackage struct {
+		SPDXID           string            `json:"SPDXID"`
+		Name             string            `json:"name"`
+		VersionInfo      string            `json:"versionInfo"`
+		DownloadLocation string            `json:"downloadLocation"`
+		FilesAnalyzed    bool              `json:"filesAnalyzed"`
+		LicenseConcluded string            `json:"licenseConcluded"`
+		LicenseDeclared  string            `json:"licenseDeclared"`
+		CopyrightText    string            `json:"copyrightText"`
+		ExternalRefs     []spdxExternalRef `json:"externalRefs"`
+	}
+
+	spdxExternalRef struct {
+		ReferenceCategory string `json:"referenceCategory"`
+		ReferenceType     string `json:"referenceType"`
+		ReferenceLocator  string `json:"referenceLocator"`
+	}
+
+	spdxRelationship struct {
+		SPDXElementID      string `json:"spdxElementId"`
+		RelationshipType   string `json:"relationshipType"`
+		RelatedSPDXElement string `json:"relatedSpdxElement"`
+	}
+)
+
+const (
+	spdxDocumentID  = "SPDXRef-DOCUMENT"
+	spdxNoAssertion = "NOASSERTION"
+)
+
+func spdxDocument(md *internal.ModuleDependencies, namespace string, created time.Time) *spdxDoc {
+	doc := &spdxDoc{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            spdxDocumentID,
+		Name:              md.ModulePath + "@" + md.Version,
+		DocumentNamespace: namespace,
+		CreationInfo: spdxCreationInfo{
+			Created:  created.UTC().Format(time.RFC3339),
+			Creators: []string{"Tool: " + toolName},
+		},
+	}
+	mainID := spdxPackageID(0)
+	doc.Packages = append(doc.Packages, newSPDXPackage(mainID, md.ModulePath, md.Version, md.LicenseTypes))
+	doc.Relationships = append(doc.Relationships, spdxRelationship{spdxDocumentID, "DESCRIBES", mainID})
+	for i, r := range md.Requirements {
+		id := spdxPackageID(i + 1)
+		doc.Packages = append(doc.Packages, newSPDXPackage(id, r.ModulePath, r.Version, r.LicenseTypes))
+		doc.Relationships = append(doc.Relationships, spdxRelationship{mainID, "DEPENDS_ON", id})
+	}
+	return doc
+}
+
+func spdxPackageID(i int) string {
+	return fmt.Sprintf("SPDXRef-Package-%d", i)
+}
+
+func newSPDXPackage(id, modulePath, version string, licenseTypes []string) spdxPackage {
+	declared := spdxNoAssertion
+	if ids := licenseIDs(licenseTypes); len(ids) > 0 && len(ids) == len(licenseTypes) {
+		declared = strings.Join(ids, " AND ")
+	}
+	return spdxPackage{
+		SPDXID:           id,
+		Name:             modulePath,
+		VersionInfo:      version,
+		DownloadLocation: spdxNoAssertion,
+		LicenseConcluded: spdxNoAssertion,
+		LicenseDeclared:  declared,
+		CopyrightText:    spdxNoAssertion,
+		ExternalRefs: []spdxExternalRef{{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  packageURL(modulePath, version),
+		}},
+	}
+}
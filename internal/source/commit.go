@@ -0,0 +1,87 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package source
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// CommitInfo describes a commit, as reported by the API of the site that
+// hosts its repository.
+type CommitInfo struct {
+	// Hash is the full hash of the commit.
+	Hash string
+	// Subject is the first line of the commit message.
+	Subject string
+	// AuthorTime is when the commit was authored.
+	AuthorTime time.Time
+	// URL is the URL of the commit's page on the host, if it reported one.
+	URL string `json:",omitempty"`
+}
+
+// maxCommitSubjectLength is the maximum length of CommitInfo.Subject, in
+// bytes. Longer subjects are truncated.
+const maxCommitSubjectLength = 200
+
+// FetchCommitInfo retrieves information about the commit with the given
+// hash, which may be abbreviated, in the repository at repoURL. The hosts map
+// is as for FetchRepoStats. If the repository's host is not in hosts, or the
+// host reports that the commit does not exist, FetchCommitInfo returns an
+// error wrapping derrors.NotFound.
+func FetchCommitInfo(ctx context.Context, client *Client, hosts map[string]string, repoURL, hash string) (_ *CommitInfo, err error) {
+	defer derrors.Wrap(&err, "source.FetchCommitInfo(ctx, client, %q, %q)", repoURL, hash)
+
+	apiURL, kind, err := repoAPIURL(hosts, repoURL)
+	if err != nil {
+		return nil, err
+	}
+	var ci CommitInfo
+	switch kind {
+	case RepoStatsGitHub:
+		var r struct {
+			SHA     string `json:"sha"`
+			HTMLURL string `json:"html_url"`
+			Commit  struct {
+				Message string `json:"message"`
+				Author  struct {
+					Date time.Time `json:"date"`
+				} `json:"author"`
+			} `json:"commit"`
+		}
+		if err := getJSON(ctx, client, apiURL+"/commits/"+url.PathEscape(hash), &r); err != nil {
+			return nil, err
+		}
+		ci = CommitInfo{Hash: r.SHA, Subject: r.Commit.Message, AuthorTime: r.Commit.Author.Date, URL: r.HTMLURL}
+	case RepoStatsGitLab:
+		var r struct {
+			ID           string    `json:"id"`
+			Title        string    `json:"title"`
+			AuthoredDate time.Time `json:"authored_date"`
+			WebURL       string    `json:"web_url"`
+		}
+		if err := getJSON(ctx, client, apiURL+"/repository/commits/"+url.PathEscape(hash), &r); err != nil {
+			return nil, err
+		}
+		ci = CommitInfo{Hash: r.ID, Subject: r.Title, AuthorTime: r.AuthoredDate, URL: r.WebURL}
+	}
+	ci.Subject = commitSubject(ci.Subject)
+	return &ci, nil
+}
+
+// commitSubject returns the subject line of the commit message msg,
+// truncated to maxCommitSubjectLength.
+func commitSubject(msg string) string {
+	subject, _, _ := strings.Cut(msg, "\n")
+	subject = strings.TrimSpace(subject)
+	if len(subject) > maxCommitSubjectLength {
+		subject = strings.ToValidUTF8(subject[:maxCommitSubjectLength], "") + "…"
+	}
+	return subject
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+func TestFetchCommitInfo(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/owner/repo/commits/abcdef123456", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{
+			"sha": "abcdef1234567890abcdef1234567890abcdef12",
+			"html_url": "https://github.com/owner/repo/commit/abcdef1234567890abcdef1234567890abcdef12",
+			"commit": {
+				"message": "Fix race in pool\n\nThe pool was not locked.",
+				"author": {"date": "2024-06-01T10:00:00Z"}
+			}
+		}`)
+	})
+	mux.HandleFunc("/api/v4/projects/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fproj/repository/commits/0123456789ab" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{
+			"id": "0123456789abcdef0123456789abcdef01234567",
+			"title": "Add feature",
+			"authored_date": "2024-02-03T04:05:06Z",
+			"web_url": "https://gitlab.com/group/proj/-/commit/0123456789abcdef0123456789abcdef01234567"
+		}`)
+	})
+	ts := httptest.NewTLSServer(mux)
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "https://")
+	client := NewClient(ts.Client())
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		kind, path, hash string
+		want             *CommitInfo
+	}{
+		{
+			RepoStatsGitHub, "owner/repo", "abcdef123456",
+			&CommitInfo{
+				Hash:       "abcdef1234567890abcdef1234567890abcdef12",
+				Subject:    "Fix race in pool",
+				AuthorTime: time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC),
+				URL:        "https://github.com/owner/repo/commit/abcdef1234567890abcdef1234567890abcdef12",
+			},
+		},
+		{
+			RepoStatsGitLab, "group/proj", "0123456789ab",
+			&CommitInfo{
+				Hash:       "0123456789abcdef0123456789abcdef01234567",
+				Subject:    "Add feature",
+				AuthorTime: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC),
+				URL:        "https://gitlab.com/group/proj/-/commit/0123456789abcdef0123456789abcdef01234567",
+			},
+		},
+	} {
+		t.Run(test.kind, func(t *testing.T) {
+			got, err := FetchCommitInfo(ctx, client, map[string]string{host: test.kind}, "https://"+host+"/"+test.path, test.hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := FetchCommitInfo(ctx, client, map[string]string{host: RepoStatsGitHub}, "https://"+host+"/owner/repo", "000000000000")
+		if !errors.Is(err, derrors.NotFound) {
+			t.Errorf("got %v, want NotFound", err)
+		}
+	})
+}
+
+func TestCommitSubject(t *testing.T) {
+	for _, test := range []struct {
+		msg, want string
+	}{
+		{"Fix race in pool", "Fix race in pool"},
+		{"  Fix race in pool  \n\nDetails.", "Fix race in pool"},
+		{strings.Repeat("x", maxCommitSubjectLength+10), strings.Repeat("x", maxCommitSubjectLength) + "…"},
+		{"", ""},
+	} {
+		if got := commitSubject(test.msg); got != test.want {
+			t.Errorf("commitSubject(%q) = %q, want %q", test.msg, got, test.want)
+		}
+	}
+}
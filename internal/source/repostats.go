@@ -0,0 +1,176 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package source
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// Kinds of repository hosting APIs understood by FetchRepoStats.
+const (
+	RepoStatsGitHub = "github"
+	RepoStatsGitLab = "gitlab"
+)
+
+// DefaultRepoStatsHosts is the set of hosts for which repository statistics
+// are fetched when no other configuration is provided. It maps a host name to
+// the kind of API that the host serves.
+var DefaultRepoStatsHosts = map[string]string{
+	"github.com": RepoStatsGitHub,
+	"gitlab.com": RepoStatsGitLab,
+}
+
+// RepoStats holds basic statistics about a repository, as reported by the API
+// of the site that hosts it.
+type RepoStats struct {
+	// RepoURL is the URL of the repository, as returned by Info.RepoURL.
+	RepoURL string
+
+	// Stars is the number of users who have starred the repository.
+	Stars int
+
+	// OpenIssues is the number of open issues. On GitHub this also includes
+	// open pull requests.
+	OpenIssues int
+
+	// LastCommitTime is the time of the most recent push or activity
+	// reported by the host. It is the zero time if the host did not report it.
+	LastCommitTime time.Time
+
+	// FetchedAt is the time the statistics were retrieved from the host.
+	FetchedAt time.Time
+}
+
+// FetchRepoStats retrieves statistics for the repository at repoURL. The hosts
+// map associates host names with API kinds (RepoStatsGitHub or
+// RepoStatsGitLab); see DefaultRepoStatsHosts. If the repository's host is not
+// in hosts, or the host reports that the repository does not exist,
+// FetchRepoStats returns an error wrapping derrors.NotFound.
+func FetchRepoStats(ctx context.Context, client *Client, hosts map[string]string, repoURL string) (_ *RepoStats, err error) {
+	defer derrors.Wrap(&err, "source.FetchRepoStats(ctx, client, %q)", repoURL)
+
+	apiURL, kind, err := repoAPIURL(hosts, repoURL)
+	if err != nil {
+		return nil, err
+	}
+	rs := &RepoStats{RepoURL: repoURL, FetchedAt: time.Now()}
+	switch kind {
+	case RepoStatsGitHub:
+		var r struct {
+			StargazersCount int       `json:"stargazers_count"`
+			OpenIssuesCount int       `json:"open_issues_count"`
+			PushedAt        time.Time `json:"pushed_at"`
+		}
+		if err := getJSON(ctx, client, apiURL, &r); err != nil {
+			return nil, err
+		}
+		rs.Stars = r.StargazersCount
+		rs.OpenIssues = r.OpenIssuesCount
+		rs.LastCommitTime = r.PushedAt
+	case RepoStatsGitLab:
+		var r struct {
+			StarCount       int       `json:"star_count"`
+			OpenIssuesCount int       `json:"open_issues_count"`
+			LastActivityAt  time.Time `json:"last_activity_at"`
+		}
+		if err := getJSON(ctx, client, apiURL, &r); err != nil {
+			return nil, err
+		}
+		rs.Stars = r.StarCount
+		rs.OpenIssues = r.OpenIssuesCount
+		rs.LastCommitTime = r.LastActivityAt
+	}
+	return rs, nil
+}
+
+// repoAPIURL returns the URL of the API resource for the repository at
+// repoURL, and the kind of the API, using hosts as described for
+// FetchRepoStats.
+func repoAPIURL(hosts map[string]string, repoURL string) (apiURL, kind string, err error) {
+	u, err := url.Parse(repoURL)
+	if err != nil {
+		return "", "", err
+	}
+	repoPath := strings.Trim(u.Path, "/")
+	kind = hosts[u.Host]
+	if kind == "" || repoPath == "" {
+		return "", "", derrors.NotFound
+	}
+	base := repoStatsAPIBase(u.Host, kind)
+	switch kind {
+	case RepoStatsGitHub:
+		owner, repo, _ := strings.Cut(repoPath, "/")
+		repo, _, _ = strings.Cut(repo, "/")
+		if repo == "" {
+			return "", "", derrors.NotFound
+		}
+		return fmt.Sprintf("%s/repos/%s/%s", base, url.PathEscape(owner), url.PathEscape(repo)), kind, nil
+	case RepoStatsGitLab:
+		return fmt.Sprintf("%s/projects/%s", base, url.PathEscape(repoPath)), kind, nil
+	default:
+		return "", "", fmt.Errorf("unknown repo stats API kind %q for host %q", kind, u.Host)
+	}
+}
+
+// getJSON gets apiURL and decodes the JSON response into v. It returns an
+// error wrapping derrors.NotFound if the response has status 404.
+func getJSON(ctx context.Context, client *Client, apiURL string, v any) error {
+	resp, err := client.doURL(ctx, "GET", apiURL, false)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return derrors.NotFound
+	default:
+		return fmt.Errorf("%s: status %s", apiURL, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// repoStatsAPIBase returns the base URL of the API for the given host and
+// kind, without a trailing slash.
+func repoStatsAPIBase(host, kind string) string {
+	switch {
+	case kind == RepoStatsGitHub && host == "github.com":
+		return "https://api.github.com"
+	case kind == RepoStatsGitHub:
+		// GitHub Enterprise Server.
+		return "https://" + host + "/api/v3"
+	default:
+		return "https://" + host + "/api/v4"
+	}
+}
+
+// ParseRepoStatsHosts parses a comma-separated list of host=kind pairs, such
+// as "github.com=github,gitlab.example.com=gitlab".
+func ParseRepoStatsHosts(s string) (map[string]string, error) {
+	hosts := map[string]string{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		host, kind, ok := strings.Cut(entry, "=")
+		if !ok || host == "" {
+			return nil, fmt.Errorf("bad repo stats host entry %q: want host=kind", entry)
+		}
+		if kind != RepoStatsGitHub && kind != RepoStatsGitLab {
+			return nil, fmt.Errorf("bad repo stats host entry %q: unknown kind %q", entry, kind)
+		}
+		hosts[host] = kind
+	}
+	return hosts, nil
+}
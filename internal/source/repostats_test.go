@@ -0,0 +1,94 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package source
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+func TestFetchRepoStats(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/owner/repo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"stargazers_count": 12, "open_issues_count": 3, "pushed_at": "2024-01-02T03:04:05Z"}`)
+	})
+	mux.HandleFunc("/api/v4/projects/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/group%2Fsub%2Fproj" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `{"star_count": 7, "open_issues_count": 1, "last_activity_at": "2024-05-06T07:08:09Z"}`)
+	})
+	ts := httptest.NewTLSServer(mux)
+	defer ts.Close()
+	host := strings.TrimPrefix(ts.URL, "https://")
+	client := NewClient(ts.Client())
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		kind, path string
+		want       *RepoStats
+	}{
+		{
+			RepoStatsGitHub, "owner/repo",
+			&RepoStats{Stars: 12, OpenIssues: 3, LastCommitTime: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)},
+		},
+		{
+			RepoStatsGitLab, "group/sub/proj",
+			&RepoStats{Stars: 7, OpenIssues: 1, LastCommitTime: time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)},
+		},
+	} {
+		t.Run(test.kind, func(t *testing.T) {
+			repoURL := "https://" + host + "/" + test.path
+			got, err := FetchRepoStats(ctx, client, map[string]string{host: test.kind}, repoURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			test.want.RepoURL = repoURL
+			if diff := cmp.Diff(test.want, got, cmpopts.IgnoreFields(RepoStats{}, "FetchedAt")); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := FetchRepoStats(ctx, client, map[string]string{host: RepoStatsGitHub}, "https://"+host+"/owner/missing")
+		if !errors.Is(err, derrors.NotFound) {
+			t.Errorf("got %v, want NotFound", err)
+		}
+	})
+	t.Run("unsupported host", func(t *testing.T) {
+		_, err := FetchRepoStats(ctx, client, DefaultRepoStatsHosts, "https://example.org/owner/repo")
+		if !errors.Is(err, derrors.NotFound) {
+			t.Errorf("got %v, want NotFound", err)
+		}
+	})
+}
+
+func TestParseRepoStatsHosts(t *testing.T) {
+	got, err := ParseRepoStatsHosts("github.com=github, git.example.com=gitlab,")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"github.com": RepoStatsGitHub, "git.example.com": RepoStatsGitLab}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	for _, bad := range []string{"github.com", "=github", "github.com=svn"} {
+		if _, err := ParseRepoStatsHosts(bad); err == nil {
+			t.Errorf("%q: got nil error, want error", bad)
+		}
+	}
+}
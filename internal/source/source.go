@@ -62,6 +62,24 @@ func (i *Info) RepoURL() string {
 	})
 }
 
+// Commit returns the tag or ID of the commit corresponding to the version.
+func (i *Info) Commit() string {
+	if i == nil {
+		return ""
+	}
+	return i.commit
+}
+
+// WithCommit returns a copy of i that refers to the given commit.
+func (i *Info) WithCommit(commit string) *Info {
+	if i == nil {
+		return nil
+	}
+	c := *i
+	c.commit = commit
+	return &c
+}
+
 // ModuleURL returns a URL for the home page of the module.
 func (i *Info) ModuleURL() string {
 	return i.DirectoryURL("")
@@ -140,6 +158,57 @@ func (i *Info) RawURL(pathname string) string {
 	})
 }
 
+// IssueURL returns a URL for the issue or pull request with the given number
+// in the repository, or the empty string if the repository's host is not
+// known to have issues.
+func (i *Info) IssueURL(number string) string {
+	if i == nil || i.templates.Issue == "" {
+		return ""
+	}
+	return expand(i.templates.Issue, map[string]string{
+		"repo":  i.repoURL,
+		"issue": number,
+	})
+}
+
+// An IssueReference is a reference to an issue or pull request in text, such
+// as "#1234" or "GH-1234".
+type IssueReference struct {
+	Start, End int    // byte offsets of the reference in the text
+	Number     string // the issue number, such as "1234"
+}
+
+var issueReferenceRegexp = regexp.MustCompile(`(#|GH-)([1-9][0-9]{0,6})`)
+
+// FindIssueReferences returns the references to issues and pull requests in
+// text.
+//
+// The pattern is conservative, to avoid linking text that only looks like a
+// reference. A reference must be at the start of text or follow a space or
+// an opening parenthesis, and must be at the end of text or be followed by a
+// space or punctuation. A reference with "#" must have at least two digits,
+// because "#1" and "#2" are more often used to number things in prose.
+func FindIssueReferences(text string) []IssueReference {
+	var refs []IssueReference
+	for _, m := range issueReferenceRegexp.FindAllStringSubmatchIndex(text, -1) {
+		start, end := m[0], m[1]
+		if start > 0 {
+			if c := text[start-1]; c != ' ' && c != '\t' && c != '\n' && c != '(' {
+				continue
+			}
+		}
+		if end < len(text) && !strings.ContainsRune(" \t\n.,;:!?)", rune(text[end])) {
+			continue
+		}
+		number := text[m[4]:m[5]]
+		if text[m[2]:m[3]] == "#" && len(number) < 2 {
+			continue
+		}
+		refs = append(refs, IssueReference{Start: start, End: end, Number: number})
+	}
+	return refs
+}
+
 // map of common urlTemplates
 var urlTemplatesByKind = map[string]urlTemplates{
 	"github":    githubURLTemplates,
@@ -793,12 +862,14 @@ func fdioTransformCommit(commit string, isHash bool) string {
 //   - {file}       - Path to file containing the identifier, relative to repo root ("mypkg/file.go").
 //   - {base}       - Base name of file containing the identifier, including file extension ("file.go").
 //   - {line}       - Line number for the identifier ("41").
+//   - {issue}      - Number of an issue or pull request ("1234").
 type urlTemplates struct {
 	Repo      string `json:",omitempty"` // Optional URL template for the repository home page, with {repo}. If left empty, a default template "{repo}" is used.
 	Directory string // URL template for a directory, with {repo}, {importPath}, {commit}, {dir}.
 	File      string // URL template for a file, with {repo}, {importPath}, {commit}, {file}, {base}.
 	Line      string // URL template for a line, with {repo}, {importPath}, {commit}, {file}, {base}, {line}.
 	Raw       string // Optional URL template for the raw contents of a file, with {repo}, {commit}, {file}.
+	Issue     string `json:",omitempty"` // Optional URL template for an issue or pull request, with {repo}, {issue}.
 }
 
 var (
@@ -807,6 +878,7 @@ var (
 		File:      "{repo}/blob/{commit}/{file}",
 		Line:      "{repo}/blob/{commit}/{file}#L{line}",
 		Raw:       "{repo}/raw/{commit}/{file}",
+		Issue:     "{repo}/issues/{issue}",
 	}
 
 	bitbucketURLTemplates = urlTemplates{
@@ -820,6 +892,7 @@ var (
 		File:      "{repo}/src/{commit}/{file}",
 		Line:      "{repo}/src/{commit}/{file}#L{line}",
 		Raw:       "{repo}/raw/{commit}/{file}",
+		Issue:     "{repo}/issues/{issue}",
 	}
 	googlesourceURLTemplates = urlTemplates{
 		Directory: "{repo}/+/{commit}/{dir}",
@@ -832,6 +905,7 @@ var (
 		File:      "{repo}/-/blob/{commit}/{file}",
 		Line:      "{repo}/-/blob/{commit}/{file}#L{line}",
 		Raw:       "{repo}/-/raw/{commit}/{file}",
+		Issue:     "{repo}/-/issues/{issue}",
 	}
 	fdioURLTemplates = urlTemplates{
 		Directory: "{repo}/tree/{dir}?{commit}",
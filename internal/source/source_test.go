@@ -524,6 +524,7 @@ func TestURLTemplates(t *testing.T) {
 		check(p.templates.File, "commit")
 		check(p.templates.Line, "commit", "line")
 		check(p.templates.Raw, "commit", "file")
+		check(p.templates.Issue, "repo", "issue")
 	}
 }
 
@@ -587,3 +588,68 @@ func TestFilesInfo(t *testing.T) {
 	check(info.ModuleURL(), "/files/Users/bob/")
 	check(info.FileURL("dir/a.go"), "/files/Users/bob/dir/a.go")
 }
+
+func TestWithCommit(t *testing.T) {
+	info := NewGitHubInfo("https://github.com/a/b", "", "v1.0.0")
+	got := info.WithCommit("v1.1.0")
+	if got.Commit() != "v1.1.0" {
+		t.Errorf("Commit() = %q, want %q", got.Commit(), "v1.1.0")
+	}
+	if info.Commit() != "v1.0.0" {
+		t.Errorf("WithCommit modified the original Info")
+	}
+	if got, want := got.FileURL("f.go"), "https://github.com/a/b/blob/v1.1.0/f.go"; got != want {
+		t.Errorf("FileURL = %q, want %q", got, want)
+	}
+	var nilInfo *Info
+	if nilInfo.WithCommit("c") != nil || nilInfo.Commit() != "" {
+		t.Error("nil Info: want nil and empty commit")
+	}
+}
+
+func TestIssueURL(t *testing.T) {
+	for _, test := range []struct {
+		info *Info
+		want string
+	}{
+		{NewGitHubInfo("https://github.com/a/b", "", "v1.0.0"), "https://github.com/a/b/issues/123"},
+		{&Info{repoURL: "https://gitlab.com/a/b", templates: gitlabURLTemplates}, "https://gitlab.com/a/b/-/issues/123"},
+		{&Info{repoURL: "https://codeberg.org/a/b", templates: giteaURLTemplates}, "https://codeberg.org/a/b/issues/123"},
+		{&Info{repoURL: "https://go.googlesource.com/a", templates: googlesourceURLTemplates}, ""},
+		{nil, ""},
+	} {
+		if got := test.info.IssueURL("123"); got != test.want {
+			t.Errorf("%s: got %q, want %q", test.info.RepoURL(), got, test.want)
+		}
+	}
+}
+
+func TestFindIssueReferences(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want []string
+	}{
+		{"Fixes #1234.", []string{"1234"}},
+		{"#12 and GH-345", []string{"12", "345"}},
+		{"(see #99, #100)", []string{"99", "100"}},
+		{"GH-1", []string{"1"}},
+		{"step #1", nil},
+		{"#0123", nil},
+		{"C#12", nil},
+		{"https://example.com/page#123", nil},
+		{"#1234abc", nil},
+		{"#12345678", nil},
+		{"color #ffffff", nil},
+	} {
+		var got []string
+		for _, r := range FindIssueReferences(test.in) {
+			if r.Start < 0 || r.End > len(test.in) || !strings.HasSuffix(test.in[r.Start:r.End], r.Number) {
+				t.Errorf("%q: bad reference %+v", test.in, r)
+			}
+			got = append(got, r.Number)
+		}
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("FindIssueReferences(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
@@ -4,6 +4,8 @@
 
 package static
 
+import "time"
+
 type Config struct {
 	// EntryPoint is a directory in which to build TypeScript
 	// sources.
@@ -17,4 +19,20 @@ type Config struct {
 	// the output is unminified, and changes to any TypeScript
 	// files will force a rebuild of the JavaScript output.
 	Watch bool
+
+	// OnBuild, if non-nil, is called with the result of every build in
+	// watch mode, starting with the first.
+	OnBuild func(*Result)
+}
+
+// A Result is the outcome of a build in watch mode.
+type Result struct {
+	Start    time.Time
+	Duration time.Duration
+
+	// Errors and Warnings are the messages reported by the build,
+	// formatted with their source locations. If there are errors, the
+	// output files were not written, and the previous ones remain.
+	Errors   []string `json:",omitempty"`
+	Warnings []string `json:",omitempty"`
 }
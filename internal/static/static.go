@@ -13,6 +13,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/evanw/esbuild/pkg/api"
 )
@@ -49,6 +50,9 @@ func Build(config Config) error {
 	options.MinifyWhitespace = true
 	options.Sourcemap = api.SourceMapLinked
 	if config.Watch {
+		if config.OnBuild != nil {
+			options.Plugins = []api.Plugin{resultPlugin(config.OnBuild)}
+		}
 		ctx, err := api.Context(options)
 		if err != nil {
 			return err
@@ -65,6 +69,30 @@ func Build(config Config) error {
 	return nil
 }
 
+// resultPlugin returns an esbuild plugin that calls onBuild with the result
+// of each build.
+func resultPlugin(onBuild func(*Result)) api.Plugin {
+	return api.Plugin{
+		Name: "result",
+		Setup: func(build api.PluginBuild) {
+			var start time.Time
+			build.OnStart(func() (api.OnStartResult, error) {
+				start = time.Now()
+				return api.OnStartResult{}, nil
+			})
+			build.OnEnd(func(result *api.BuildResult) (api.OnEndResult, error) {
+				onBuild(&Result{
+					Start:    start,
+					Duration: time.Since(start),
+					Errors:   api.FormatMessages(result.Errors, api.FormatMessagesOptions{Kind: api.ErrorMessage}),
+					Warnings: api.FormatMessages(result.Warnings, api.FormatMessagesOptions{Kind: api.WarningMessage}),
+				})
+				return api.OnEndResult{}, nil
+			})
+		},
+	}
+}
+
 // getEntry walks the given directory and collects entry file paths
 // for esbuild. It ignores test files and files prefixed with an underscore.
 // Underscore prefixed files are assumed to be imported by and bundled together
@@ -0,0 +1,47 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package static
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// A Status records the result of the latest build in watch mode. Pass its
+// Record method as Config.OnBuild.
+//
+// A Status is an http.Handler that serves the latest result as JSON, so that
+// pages served in development can show build errors instead of running stale
+// JavaScript. Before the first build, it serves null.
+type Status struct {
+	mu     sync.Mutex
+	result *Result
+}
+
+// Record makes r the latest result.
+func (s *Status) Record(r *Result) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.result = r
+}
+
+// Latest returns the latest result, or nil if there has been no build.
+func (s *Status) Latest() *Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.result
+}
+
+func (s *Status) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, err := json.Marshal(s.Latest())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(data)
+}
@@ -0,0 +1,154 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stdlib
+
+import (
+	"archive/zip"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// ReleaseNotesDir is the directory of the content of the standard library
+// (see ContentDir) that holds the release notes found in the Go repository.
+const ReleaseNotesDir = "doc"
+
+// releaseNotesFileRx matches the names of the files of release notes of Go
+// releases in the Go repository, like "go1.16.html". Since Go 1.22, release
+// notes are kept in the website repository instead.
+var releaseNotesFileRx = regexp.MustCompile(`^(go1\.[0-9]+)\.html$`)
+
+// releaseNotesSectionRx matches the start of the section of release notes
+// about the minor changes to a package, like
+//
+//	<dl id="net/http"><dt><a href="/pkg/net/http/">net/http</a></dt>
+var releaseNotesSectionRx = regexp.MustCompile(`<dl id="([^"]+)">\s*<dt>\s*<a href="/pkg/([^"#]+?)/?"`)
+
+// A ReleaseNoteSection is the section of the release notes of a Go release
+// about the changes to a package.
+type ReleaseNoteSection struct {
+	// GoVersion is the Go release, like "go1.16".
+	GoVersion   string
+	PackagePath string
+	// Anchor is the ID of the section in the release notes.
+	Anchor string
+}
+
+// ReleaseNotesURL returns the URL of the release notes of the Go release of
+// tag, like "go1.16" or "go1.21.3", at the section with the given anchor, or
+// at the top if anchor is empty.
+func ReleaseNotesURL(tag, anchor string) string {
+	u := "https://go.dev/doc/" + ReleaseForTag(tag)
+	if anchor != "" {
+		u += "#" + anchor
+	}
+	return u
+}
+
+// ReleaseForTag returns the Go release of tag, without its patch version:
+// "go1.21" for "go1.21.3" and "go1.16" for "go1.16". Prereleases belong to
+// the release they precede.
+func ReleaseForTag(tag string) string {
+	parts := strings.SplitN(tag, ".", 3)
+	if len(parts) < 2 {
+		return tag
+	}
+	minor := parts[1]
+	if i := strings.IndexAny(minor, "abr"); i >= 0 {
+		// go1.22rc1, go1.21beta2
+		minor = minor[:i]
+	}
+	return parts[0] + "." + minor
+}
+
+// ReleaseNoteSections returns the sections about packages in the release
+// notes in the ReleaseNotesDir directory of contentDir, which is the
+// content of the standard library returned by ContentDir. It returns none
+// if there are no release notes.
+func ReleaseNoteSections(contentDir fs.FS) (_ []*ReleaseNoteSection, err error) {
+	defer derrors.Wrap(&err, "ReleaseNoteSections")
+
+	dirents, err := fs.ReadDir(contentDir, ReleaseNotesDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var sections []*ReleaseNoteSection
+	for _, e := range dirents {
+		m := releaseNotesFileRx.FindStringSubmatch(e.Name())
+		if m == nil || !e.Type().IsRegular() {
+			continue
+		}
+		b, err := fs.ReadFile(contentDir, path.Join(ReleaseNotesDir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		sections = append(sections, parseReleaseNotes(m[1], string(b))...)
+	}
+	return sections, nil
+}
+
+// parseReleaseNotes returns the sections about packages in the release notes
+// of goVersion.
+func parseReleaseNotes(goVersion, notes string) []*ReleaseNoteSection {
+	seen := map[string]bool{}
+	var sections []*ReleaseNoteSection
+	for _, m := range releaseNotesSectionRx.FindAllStringSubmatch(notes, -1) {
+		anchor, pkgPath := m[1], m[2]
+		// A package can have more than one section; link to the first.
+		if seen[pkgPath] {
+			continue
+		}
+		seen[pkgPath] = true
+		sections = append(sections, &ReleaseNoteSection{
+			GoVersion:   goVersion,
+			PackagePath: pkgPath,
+			Anchor:      anchor,
+		})
+	}
+	sort.Slice(sections, func(i, j int) bool { return sections[i].PackagePath < sections[j].PackagePath })
+	return sections
+}
+
+// addReleaseNotes adds the release notes in the doc directory of the Go
+// repository in repoDir to z, in the ReleaseNotesDir directory under
+// dirpath.
+func addReleaseNotes(z *zip.Writer, repoDir, dirpath string) (err error) {
+	defer derrors.Wrap(&err, "addReleaseNotes(zip, %q, %q)", repoDir, dirpath)
+
+	docDir := filepath.Join(repoDir, "doc")
+	dirents, err := os.ReadDir(docDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range dirents {
+		if !releaseNotesFileRx.MatchString(e.Name()) || !e.Type().IsRegular() {
+			continue
+		}
+		f, err := os.Open(filepath.Join(docDir, e.Name()))
+		if err != nil {
+			return err
+		}
+		if err := writeZipFile(z, path.Join(dirpath, ReleaseNotesDir, e.Name()), f); err != nil {
+			_ = f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
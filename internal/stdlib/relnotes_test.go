@@ -0,0 +1,82 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package stdlib
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/testenv"
+)
+
+func TestReleaseForTag(t *testing.T) {
+	for _, test := range []struct {
+		tag, want string
+	}{
+		{"go1", "go1"},
+		{"go1.16", "go1.16"},
+		{"go1.16.4", "go1.16"},
+		{"go1.21.0", "go1.21"},
+		{"go1.22rc1", "go1.22"},
+		{"go1.21beta2", "go1.21"},
+	} {
+		if got := ReleaseForTag(test.tag); got != test.want {
+			t.Errorf("ReleaseForTag(%q) = %q, want %q", test.tag, got, test.want)
+		}
+	}
+}
+
+func TestReleaseNoteSections(t *testing.T) {
+	ctx := context.Background()
+	testenv.MustHaveExecPath(t, "git")
+	defer WithTestData()()
+	for _, test := range []struct {
+		version string
+		want    []*ReleaseNoteSection
+	}{
+		{"v1.12.5", nil},
+		{"v1.14.6", []*ReleaseNoteSection{
+			{GoVersion: "go1.14", PackagePath: "context", Anchor: "context"},
+			{GoVersion: "go1.14", PackagePath: "encoding/json", Anchor: "encoding/json"},
+		}},
+	} {
+		t.Run(test.version, func(t *testing.T) {
+			cdir, _, _, err := ContentDir(ctx, test.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := ReleaseNoteSections(cdir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseReleaseNotes(t *testing.T) {
+	const notes = `
+<dl id="net_http"><dt><a href="/pkg/net/http/">net/http</a></dt>
+  <dd>The first section.</dd>
+</dl>
+<dl id="net/http"><dt><a href="/pkg/net/http/">net/http</a></dt>
+  <dd>A second section about the same package.</dd>
+</dl>
+<dl id="go/types">
+  <dt><a href="/pkg/go/types">go/types</a></dt>
+</dl>
+<h3 id="runtime">Runtime</h3>
+`
+	want := []*ReleaseNoteSection{
+		{GoVersion: "go1.8", PackagePath: "go/types", Anchor: "go/types"},
+		{GoVersion: "go1.8", PackagePath: "net/http", Anchor: "net_http"},
+	}
+	if diff := cmp.Diff(want, parseReleaseNotes("go1.8", notes)); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -368,6 +368,9 @@ func zipInternal(ctx context.Context, requestedVersion string) (_ *zip.Reader, r
 	if err := addFiles(z, libDir, prefixPath, true); err != nil {
 		return nil, "", time.Time{}, "", err
 	}
+	if err := addReleaseNotes(z, dir, prefixPath); err != nil {
+		return nil, "", time.Time{}, "", err
+	}
 	if err := z.Close(); err != nil {
 		return nil, "", time.Time{}, "", err
 	}
@@ -0,0 +1,24 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "time"
+
+// A Takedown hides a module, or one version of it, from the site, usually
+// because of a legal request. Unlike an excluded module, a module that is
+// taken down keeps its data, and its pages explain that it is unavailable.
+type Takedown struct {
+	ID         int64
+	ModulePath string
+	Version    string // empty if all versions are taken down
+	Reason     string // why it was taken down, for the record
+	// Message is shown in place of the module's pages. If it is empty, a
+	// default message is shown.
+	Message   string
+	CreatedBy string
+	CreatedAt time.Time
+	LiftedBy  string
+	LiftedAt  time.Time // zero if the takedown is in effect
+}
@@ -8,14 +8,17 @@ package fakedatasource
 import (
 	"context"
 	"fmt"
+	"slices"
 	"sort"
 	"strings"
 
 	"golang.org/x/mod/module"
 	"golang.org/x/mod/semver"
 	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/analysis"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/licenses"
+	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/version"
 )
 
@@ -23,15 +26,41 @@ var errNotImplemented = fmt.Errorf("not implemented: %w", derrors.Unsupported)
 
 // FakeDataSource provides a fake implementation of the internal.DataSource interface.
 type FakeDataSource struct {
-	modules    map[module.Version]*internal.Module
-	importedBy map[string][]string
+	modules       map[module.Version]*internal.Module
+	importedBy    map[string][]string
+	versionStates map[module.Version]*internal.ModuleVersionState
+	fetchClaims   map[module.Version]bool
+
+	packageVersionStates map[packageVersion]*internal.PackageVersionState
+	apiKeys              map[string]*internal.APIKey
+	analysisReports      map[module.Version][]*analysis.Report
+	unreadyFeatures      map[string]map[internal.DataFeature]bool
+	takedowns            []*internal.Takedown
+	renderedReadmes      map[string][]byte
+	importedByHistory    map[string][]*internal.ImportedByCountAt
+	advisories           map[string]*internal.Advisory
+}
+
+// packageVersion identifies a package at a version of a module.
+type packageVersion struct {
+	pkgPath, modulePath, version string
 }
 
 // New returns an initialized FakeDataSource.
 func New() *FakeDataSource {
 	return &FakeDataSource{
-		modules:    make(map[module.Version]*internal.Module),
-		importedBy: make(map[string][]string),
+		modules:       make(map[module.Version]*internal.Module),
+		importedBy:    make(map[string][]string),
+		versionStates: make(map[module.Version]*internal.ModuleVersionState),
+		fetchClaims:   make(map[module.Version]bool),
+
+		packageVersionStates: make(map[packageVersion]*internal.PackageVersionState),
+		apiKeys:              make(map[string]*internal.APIKey),
+		analysisReports:      make(map[module.Version][]*analysis.Report),
+		unreadyFeatures:      make(map[string]map[internal.DataFeature]bool),
+		renderedReadmes:      make(map[string][]byte),
+		importedByHistory:    make(map[string][]*internal.ImportedByCountAt),
+		advisories:           make(map[string]*internal.Advisory),
 	}
 }
 
@@ -304,6 +333,9 @@ func (ds *FakeDataSource) Search(ctx context.Context, q string, opts internal.Se
 	terms := strings.Fields(q)
 
 	for _, m := range ds.modules {
+		if !opts.InScope(m.ModulePath, m.Version) {
+			continue
+		}
 		for _, u := range m.Units {
 			var containsAllTerms bool
 			if len(terms) > 0 {
@@ -329,6 +361,9 @@ func (ds *FakeDataSource) Search(ctx context.Context, q string, opts internal.Se
 				for _, licence := range u.Licenses {
 					result.Licenses = append(result.Licenses, licence.Types...)
 				}
+				if !opts.Filters.Match(m.ModulePath, m.Version, result.Licenses) {
+					continue
+				}
 				results = append(results, result)
 			}
 
@@ -337,10 +372,100 @@ func (ds *FakeDataSource) Search(ctx context.Context, q string, opts internal.Se
 	return results, nil
 }
 
+// GetSearchFacets counts the results of Search by facet value.
+func (ds *FakeDataSource) GetSearchFacets(ctx context.Context, q string, opts internal.SearchOptions) (*internal.SearchFacets, error) {
+	results, err := ds.Search(ctx, q, opts)
+	if err != nil {
+		return nil, err
+	}
+	licenses := map[string]int{}
+	hosts := map[string]int{}
+	majors := map[string]int{}
+	for _, r := range results {
+		for _, l := range r.Licenses {
+			licenses[l]++
+		}
+		host, major := internal.SearchFacetValues(r.ModulePath, r.Version)
+		hosts[host]++
+		majors[major]++
+	}
+	return &internal.SearchFacets{
+		Licenses:      facetCounts(licenses),
+		Hosts:         facetCounts(hosts),
+		MajorVersions: facetCounts(majors),
+	}, nil
+}
+
+// facetCounts returns the counts in m, by decreasing count.
+func facetCounts(m map[string]int) []*internal.FacetCount {
+	var fcs []*internal.FacetCount
+	for v, n := range m {
+		fcs = append(fcs, &internal.FacetCount{Value: v, Count: n})
+	}
+	sort.Slice(fcs, func(i, j int) bool {
+		if fcs[i].Count != fcs[j].Count {
+			return fcs[i].Count > fcs[j].Count
+		}
+		return fcs[i].Value < fcs[j].Value
+	})
+	return fcs
+}
+
 func (ds *FakeDataSource) IsExcluded(ctx context.Context, path, version string) bool {
 	return false
 }
 
+// GetAutocompleteSuggestions returns up to limit packages whose path or name
+// begins with prefix, and symbols whose name begins with prefix, most imported
+// first.
+func (ds *FakeDataSource) GetAutocompleteSuggestions(ctx context.Context, prefix string, limit int) ([]*internal.AutocompleteSuggestion, error) {
+	less := func(si, sj *internal.AutocompleteSuggestion) bool {
+		if si.NumImportedBy != sj.NumImportedBy {
+			return si.NumImportedBy > sj.NumImportedBy
+		}
+		if si.Symbol != sj.Symbol {
+			return si.Symbol < sj.Symbol
+		}
+		return si.PackagePath < sj.PackagePath
+	}
+	// Keep only the best limit suggestions while scanning, in order.
+	seen := map[internal.AutocompleteSuggestion]bool{}
+	var suggestions []*internal.AutocompleteSuggestion
+	add := func(s internal.AutocompleteSuggestion) {
+		if seen[s] {
+			return
+		}
+		seen[s] = true
+		i := sort.Search(len(suggestions), func(i int) bool { return less(&s, suggestions[i]) })
+		if i >= limit {
+			return
+		}
+		suggestions = slices.Insert(suggestions, i, &s)
+		if len(suggestions) > limit {
+			suggestions = suggestions[:limit]
+		}
+	}
+	for _, m := range ds.modules {
+		for _, u := range m.Units {
+			if !u.IsPackage() {
+				continue
+			}
+			n := len(ds.importedBy[u.Path])
+			if strings.HasPrefix(u.Path, prefix) || strings.HasPrefix(u.Name, prefix) {
+				add(internal.AutocompleteSuggestion{PackagePath: u.Path, NumImportedBy: n})
+			}
+			for _, d := range u.Documentation {
+				for _, s := range d.API {
+					if strings.HasPrefix(s.Name, prefix) {
+						add(internal.AutocompleteSuggestion{PackagePath: u.Path, Symbol: s.Name, NumImportedBy: n})
+					}
+				}
+			}
+		}
+	}
+	return suggestions, nil
+}
+
 // GetImportedBy returns the set of packages importing the given pkgPath.
 func (ds *FakeDataSource) GetImportedBy(ctx context.Context, pkgPath, modulePath string, limit int) (paths []string, err error) {
 	importedBy := append([]string{}, ds.importedBy[pkgPath]...)
@@ -355,6 +480,288 @@ func (ds *FakeDataSource) GetImportedByCount(ctx context.Context, pkgPath, modul
 	return 0, nil
 }
 
+// SetImportedByHistory sets the history of imported-by counts that
+// GetImportedByHistory returns for the package.
+func (ds *FakeDataSource) SetImportedByHistory(pkgPath string, history []*internal.ImportedByCountAt) {
+	ds.importedByHistory[pkgPath] = history
+}
+
+// GetImportedByHistory returns the history set with SetImportedByHistory.
+func (ds *FakeDataSource) GetImportedByHistory(ctx context.Context, pkgPath string) ([]*internal.ImportedByCountAt, error) {
+	return ds.importedByHistory[pkgPath], nil
+}
+
+// GetPackageSynopses returns the synopses of the packages in paths at the
+// latest version of the module that contains them.
+func (ds *FakeDataSource) GetPackageSynopses(ctx context.Context, paths []string) (map[string]string, error) {
+	synopses := map[string]string{}
+	for _, p := range paths {
+		m := ds.findModule(p, internal.UnknownModulePath, version.Latest)
+		if m == nil {
+			continue
+		}
+		if u := findUnit(m, p); u != nil && len(u.Documentation) > 0 {
+			synopses[p] = u.Documentation[0].Synopsis
+		}
+	}
+	return synopses, nil
+}
+
+// GetRepoStats always returns derrors.NotFound: the fake does not store
+// repository statistics.
+func (ds *FakeDataSource) GetRepoStats(ctx context.Context, repoURL string) (*source.RepoStats, error) {
+	return nil, derrors.NotFound
+}
+
+// InsertModuleVersionState adds the module version state to the
+// FakeDataSource, replacing any existing state for the same module version.
+func (ds *FakeDataSource) InsertModuleVersionState(mvs *internal.ModuleVersionState) {
+	ds.versionStates[module.Version{Path: mvs.ModulePath, Version: mvs.Version}] = mvs
+}
+
+// InsertFetchClaim records that a worker is fetching the module version.
+func (ds *FakeDataSource) InsertFetchClaim(modulePath, version string) {
+	ds.fetchClaims[module.Version{Path: modulePath, Version: version}] = true
+}
+
+// IsFetchClaimed reports whether InsertFetchClaim was called for the module
+// version.
+func (ds *FakeDataSource) IsFetchClaimed(ctx context.Context, modulePath, version string) (bool, error) {
+	return ds.fetchClaims[module.Version{Path: modulePath, Version: version}], nil
+}
+
+// GetModuleDependencies returns the module version modulePath@version and the
+// modules it requires, with the types of their top-level licenses.
+func (ds *FakeDataSource) GetModuleDependencies(ctx context.Context, modulePath, version string) (*internal.ModuleDependencies, error) {
+	m := ds.modules[module.Version{Path: modulePath, Version: version}]
+	if m == nil {
+		return nil, derrors.NotFound
+	}
+	md := &internal.ModuleDependencies{
+		ModulePath:   modulePath,
+		Version:      version,
+		LicenseTypes: topLevelLicenseTypes(m),
+	}
+	for _, r := range m.Requirements {
+		rm := &internal.RequiredModule{ModuleRequirement: *r}
+		if dep := ds.modules[module.Version{Path: r.ModulePath, Version: r.Version}]; dep != nil {
+			rm.LicenseTypes = topLevelLicenseTypes(dep)
+		}
+		md.Requirements = append(md.Requirements, rm)
+	}
+	sort.Slice(md.Requirements, func(i, j int) bool {
+		return md.Requirements[i].ModulePath < md.Requirements[j].ModulePath
+	})
+	return md, nil
+}
+
+// GetModuleRequirements returns the modules required by the module versions
+// mvs that are in the FakeDataSource.
+func (ds *FakeDataSource) GetModuleRequirements(ctx context.Context, mvs []internal.Modver) (map[internal.Modver][]*internal.ModuleRequirement, error) {
+	reqs := map[internal.Modver][]*internal.ModuleRequirement{}
+	for _, mv := range mvs {
+		m := ds.modules[module.Version{Path: mv.Path, Version: mv.Version}]
+		if m == nil {
+			continue
+		}
+		rs := slices.Clone(m.Requirements)
+		sort.Slice(rs, func(i, j int) bool { return rs[i].ModulePath < rs[j].ModulePath })
+		reqs[mv] = rs
+	}
+	return reqs, nil
+}
+
+// topLevelLicenseTypes returns the sorted, distinct types of the licenses at
+// the root of m.
+func topLevelLicenseTypes(m *internal.Module) []string {
+	var types []string
+	for _, l := range m.Licenses {
+		if !strings.Contains(l.FilePath, "/") {
+			types = append(types, l.Types...)
+		}
+	}
+	sort.Strings(types)
+	return slices.Compact(types)
+}
+
+// GetBrokenDocLinks returns the broken doc links of the packages of the
+// module version, by package path.
+func (ds *FakeDataSource) GetBrokenDocLinks(ctx context.Context, modulePath, version string) (map[string][]*internal.BrokenDocLink, error) {
+	m := ds.modules[module.Version{Path: modulePath, Version: version}]
+	if m == nil {
+		return nil, nil
+	}
+	links := map[string][]*internal.BrokenDocLink{}
+	for _, u := range m.Units {
+		seen := map[internal.BrokenDocLink]bool{}
+		for _, d := range u.Documentation {
+			for _, l := range d.BrokenDocLinks {
+				if !seen[*l] {
+					seen[*l] = true
+					links[u.Path] = append(links[u.Path], l)
+				}
+			}
+		}
+	}
+	return links, nil
+}
+
+// GetModuleVersionState returns the state inserted with
+// InsertModuleVersionState for the module version.
+func (ds *FakeDataSource) GetModuleVersionState(ctx context.Context, modulePath, resolvedVersion string) (*internal.ModuleVersionState, error) {
+	mvs, ok := ds.versionStates[module.Version{Path: modulePath, Version: resolvedVersion}]
+	if !ok {
+		return nil, derrors.NotFound
+	}
+	return mvs, nil
+}
+
+// InsertPackageVersionState adds the package version state to the
+// FakeDataSource, replacing any existing state for the same package version.
+func (ds *FakeDataSource) InsertPackageVersionState(pvs *internal.PackageVersionState) {
+	ds.packageVersionStates[packageVersion{pvs.PackagePath, pvs.ModulePath, pvs.Version}] = pvs
+}
+
+// GetPackageVersionState returns the state inserted with
+// InsertPackageVersionState for the package version.
+func (ds *FakeDataSource) GetPackageVersionState(ctx context.Context, pkgPath, modulePath, resolvedVersion string) (*internal.PackageVersionState, error) {
+	pvs, ok := ds.packageVersionStates[packageVersion{pkgPath, modulePath, resolvedVersion}]
+	if !ok {
+		return nil, derrors.NotFound
+	}
+	return pvs, nil
+}
+
+// InsertAPIKey adds the API key with value key to the FakeDataSource.
+func (ds *FakeDataSource) InsertAPIKey(key string, ak *internal.APIKey) {
+	ds.apiKeys[key] = ak
+}
+
+// GetAPIKey returns the API key inserted with InsertAPIKey, unless it has
+// been revoked.
+func (ds *FakeDataSource) GetAPIKey(ctx context.Context, key string) (*internal.APIKey, error) {
+	ak, ok := ds.apiKeys[key]
+	if !ok || !ak.RevokedAt.IsZero() {
+		return nil, derrors.NotFound
+	}
+	return ak, nil
+}
+
+// UpsertAnalysisReport stores r for its module version, replacing any report
+// of the same analyzer.
+func (ds *FakeDataSource) UpsertAnalysisReport(ctx context.Context, r *analysis.Report) error {
+	mv := module.Version{Path: r.ModulePath, Version: r.Version}
+	if ds.modules[mv] == nil {
+		return derrors.NotFound
+	}
+	reports := slices.DeleteFunc(ds.analysisReports[mv], func(r2 *analysis.Report) bool {
+		return r2.Analyzer == r.Analyzer
+	})
+	reports = append(reports, r)
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Analyzer < reports[j].Analyzer })
+	ds.analysisReports[mv] = reports
+	return nil
+}
+
+// UpsertAdvisory stores a, replacing any advisory with the same ID.
+func (ds *FakeDataSource) UpsertAdvisory(ctx context.Context, a *internal.Advisory) error {
+	ds.advisories[a.Entry.ID] = a
+	return nil
+}
+
+// GetAdvisory returns the advisory stored with UpsertAdvisory.
+func (ds *FakeDataSource) GetAdvisory(ctx context.Context, id string) (*internal.Advisory, error) {
+	a, ok := ds.advisories[id]
+	if !ok {
+		return nil, derrors.NotFound
+	}
+	return a, nil
+}
+
+// DeleteAdvisory deletes the advisory stored with UpsertAdvisory.
+func (ds *FakeDataSource) DeleteAdvisory(ctx context.Context, id string) error {
+	if _, ok := ds.advisories[id]; !ok {
+		return derrors.NotFound
+	}
+	delete(ds.advisories, id)
+	return nil
+}
+
+// GetAnalysisReports returns the reports stored with UpsertAnalysisReport for
+// the module version.
+func (ds *FakeDataSource) GetAnalysisReports(ctx context.Context, modulePath, version string) ([]*analysis.Report, error) {
+	return ds.analysisReports[module.Version{Path: modulePath, Version: version}], nil
+}
+
+// SetFeatureNotReady makes GetReadyFeatures omit the data feature for the
+// module. All features are ready for the other modules.
+func (ds *FakeDataSource) SetFeatureNotReady(modulePath string, f internal.DataFeature) {
+	if ds.unreadyFeatures[modulePath] == nil {
+		ds.unreadyFeatures[modulePath] = map[internal.DataFeature]bool{}
+	}
+	ds.unreadyFeatures[modulePath][f] = true
+}
+
+// GetReadyFeatures returns the data features that are ready for the module.
+func (ds *FakeDataSource) GetReadyFeatures(ctx context.Context, modulePath string) (map[internal.DataFeature]bool, error) {
+	ready := map[internal.DataFeature]bool{}
+	for _, f := range internal.DataFeatures {
+		if !ds.unreadyFeatures[modulePath][f] {
+			ready[f] = true
+		}
+	}
+	return ready, nil
+}
+
+// GetReleaseNoteAnchor returns the anchor of the section about the package at
+// pkgPath in the release notes of goVersion, as found in the inserted
+// versions of the standard library.
+func (ds *FakeDataSource) GetReleaseNoteAnchor(ctx context.Context, goVersion, pkgPath string) (string, error) {
+	for _, m := range ds.modules {
+		for _, s := range m.ReleaseNoteSections {
+			if s.GoVersion == goVersion && s.PackagePath == pkgPath {
+				return s.Anchor, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// InsertRenderedReadmes stores rendered READMEs, keyed by their hashes, for
+// GetRenderedReadme.
+func (ds *FakeDataSource) InsertRenderedReadmes(ctx context.Context, rendered map[string][]byte) error {
+	for h, r := range rendered {
+		ds.renderedReadmes[h] = r
+	}
+	return nil
+}
+
+// GetRenderedReadme returns the rendered README stored under hash by
+// InsertRenderedReadmes.
+func (ds *FakeDataSource) GetRenderedReadme(ctx context.Context, hash string) ([]byte, error) {
+	r, ok := ds.renderedReadmes[hash]
+	if !ok {
+		return nil, derrors.NotFound
+	}
+	return r, nil
+}
+
+// AddTakedown makes GetTakedown return t for the versions it takes down.
+func (ds *FakeDataSource) AddTakedown(t *internal.Takedown) {
+	ds.takedowns = append(ds.takedowns, t)
+}
+
+// GetTakedown returns the takedown added with AddTakedown for the module
+// version, if it is in effect.
+func (ds *FakeDataSource) GetTakedown(ctx context.Context, modulePath, version string) (*internal.Takedown, error) {
+	for _, t := range ds.takedowns {
+		if t.ModulePath == modulePath && (t.Version == "" || t.Version == version) && t.LiftedAt.IsZero() {
+			return t, nil
+		}
+	}
+	return nil, derrors.NotFound
+}
+
 func (ds *FakeDataSource) GetLatestMajorPathForV1Path(ctx context.Context, v1path string) (string, int, error) {
 	return "", 0, errNotImplemented
 }
@@ -367,6 +774,32 @@ func (ds *FakeDataSource) GetSymbolHistory(ctx context.Context, packagePath, mod
 	return &internal.SymbolHistory{}, nil
 }
 
+// GetSymbolsAtVersions returns the symbols in the documentation of the
+// package at each of the given versions.
+func (ds *FakeDataSource) GetSymbolsAtVersions(ctx context.Context, packagePath, modulePath string, versions []string) (*internal.SymbolHistory, error) {
+	sh := internal.NewSymbolHistory()
+	for _, v := range versions {
+		m := ds.getModule(modulePath, v)
+		if m == nil {
+			continue
+		}
+		u := findUnit(m, packagePath)
+		if u == nil {
+			continue
+		}
+		for _, d := range u.Documentation {
+			build := internal.BuildContext{GOOS: d.GOOS, GOARCH: d.GOARCH}
+			for _, s := range d.API {
+				sh.AddSymbol(s.SymbolMeta, v, build)
+				for _, c := range s.Children {
+					sh.AddSymbol(*c, v, build)
+				}
+			}
+		}
+	}
+	return sh, nil
+}
+
 func (ds *FakeDataSource) GetVersionMap(ctx context.Context, modulePath, requestedVersion string) (*internal.VersionMap, error) {
 	return nil, errNotImplemented
 }
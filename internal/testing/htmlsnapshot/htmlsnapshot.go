@@ -0,0 +1,140 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package htmlsnapshot supports snapshot tests of rendered HTML. A snapshot
+// test normalizes HTML and compares it with a golden file in the testdata
+// directory of the package under test.
+//
+// When a snapshot does not match, the normalized HTML is written next to the
+// golden file, with the extension ".got" in place of ".golden". Use
+//
+//	go run ./devtools/cmd/snapshots
+//
+// to review the differences, and its -accept flag, or the -update flag of the
+// test, to replace the golden files.
+package htmlsnapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/net/html"
+)
+
+// Normalize returns a canonical form of the HTML document read from r, which
+// ignores differences that do not affect how the document is displayed.
+// Each element, text node and comment is written on its own line, indented by
+// its depth in the document, without end tags. The attributes of an element
+// are sorted by name. Runs of whitespace in text are collapsed to a single
+// space, and text that is only whitespace is omitted, except in pre and
+// textarea elements, where text is quoted instead. Text is escaped, except
+// in script and style elements.
+func Normalize(r io.Reader) (string, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	writeNode(&b, doc, 0, false)
+	return b.String(), nil
+}
+
+func writeNode(b *strings.Builder, n *html.Node, depth int, pre bool) {
+	raw := n.Parent != nil && n.Parent.Type == html.ElementNode && (n.Parent.Data == "script" || n.Parent.Data == "style")
+	indent := strings.Repeat("  ", depth)
+	switch n.Type {
+	case html.DocumentNode:
+		depth-- // the children of the document are not indented
+	case html.DoctypeNode:
+		fmt.Fprintf(b, "%s<!DOCTYPE %s>\n", indent, n.Data)
+	case html.CommentNode:
+		fmt.Fprintf(b, "%s<!--%s-->\n", indent, strings.Join(strings.Fields(n.Data), " "))
+	case html.TextNode:
+		if pre {
+			fmt.Fprintf(b, "%s%s\n", indent, strconv.Quote(n.Data))
+		} else if text := strings.Join(strings.Fields(n.Data), " "); text != "" {
+			if !raw {
+				text = html.EscapeString(text)
+			}
+			fmt.Fprintf(b, "%s%s\n", indent, text)
+		}
+	case html.ElementNode:
+		attrs := slices.Clone(n.Attr)
+		slices.SortFunc(attrs, func(a, b html.Attribute) int { return strings.Compare(a.Key, b.Key) })
+		fmt.Fprintf(b, "%s<%s", indent, n.Data)
+		for _, a := range attrs {
+			fmt.Fprintf(b, " %s=%q", a.Key, a.Val)
+		}
+		b.WriteString(">\n")
+		pre = pre || n.Data == "pre" || n.Data == "textarea"
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writeNode(b, c, depth+1, pre)
+	}
+}
+
+// Compare normalizes the HTML document got and compares it with the golden
+// file testdata/filename. If update is true, it writes the golden file
+// instead.
+func Compare(t *testing.T, got []byte, filename string, update bool) {
+	t.Helper()
+	norm, err := Normalize(bytes.NewReader(got))
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden := filepath.Join("testdata", filename)
+	gotFile := GotFile(golden)
+	if update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(golden, []byte(norm), 0644); err != nil {
+			t.Fatal(err)
+		}
+		removeIfExists(t, gotFile)
+		t.Logf("wrote %s", golden)
+		return
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("%v; run the test with -update to create it", err)
+	}
+	if string(want) == norm {
+		removeIfExists(t, gotFile)
+		return
+	}
+	if err := os.WriteFile(gotFile, []byte(norm), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Errorf("%s: mismatch (-want, +got):\n%s\nThe output is in %s. Review all mismatches with `go run ./devtools/cmd/snapshots`, or rerun the test with -update to accept them.",
+		golden, Diff(string(want), norm), gotFile)
+}
+
+// GotFile returns the name of the file holding the mismatched output for
+// the golden file named golden.
+func GotFile(golden string) string {
+	return strings.TrimSuffix(golden, ".golden") + ".got"
+}
+
+// Diff returns a line-by-line diff of two normalized documents.
+func Diff(want, got string) string {
+	return cmp.Diff(strings.Split(want, "\n"), strings.Split(got, "\n"))
+}
+
+func removeIfExists(t *testing.T, filename string) {
+	t.Helper()
+	if err := os.Remove(filename); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package htmlsnapshot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	const want = `<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <title>
+      T
+    <script>
+      if (a < b) {}
+  <body>
+    <!--a comment-->
+    <p class="c" id="x">
+      Some
+      <b>
+        bold
+      text &lt;here&gt;.
+    <pre>
+      "a\n  b"
+`
+	for _, in := range []string{
+		`<!DOCTYPE html><html lang="en"><head><title>T</title><script>if (a < b) {}</script></head><body><!-- a comment --><p id="x" class="c">Some <b>bold</b> text &lt;here&gt;.</p><pre>a
+  b</pre></body></html>`,
+		`<!DOCTYPE html>
+<html lang="en">
+  <head>
+    <title>T</title>
+    <script>
+      if (a < b) {}
+    </script>
+  </head>
+  <body>
+    <!--
+      a comment
+    -->
+    <p class="c"   id="x">
+      Some
+      <b>bold</b>
+      text &lt;here&gt;.
+    </p>
+    <pre>a
+  b</pre>
+  </body>
+</html>`,
+	} {
+		got, err := Normalize(strings.NewReader(in))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("Normalize(%q) mismatch:\n%s", in, Diff(want, got))
+		}
+	}
+}
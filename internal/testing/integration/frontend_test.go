@@ -8,19 +8,11 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
-	"time"
 
 	"github.com/go-redis/redis/v8"
-	"github.com/google/safehtml/template"
-	"golang.org/x/pkgsite/internal"
-	"golang.org/x/pkgsite/internal/config"
-	"golang.org/x/pkgsite/internal/experiment"
 	"golang.org/x/pkgsite/internal/fetch"
-	"golang.org/x/pkgsite/internal/frontend"
 	"golang.org/x/pkgsite/internal/frontend/fetchserver"
-	"golang.org/x/pkgsite/internal/middleware"
 	"golang.org/x/pkgsite/internal/postgres"
 	"golang.org/x/pkgsite/internal/proxy"
 	"golang.org/x/pkgsite/internal/proxy/proxytest"
@@ -29,58 +21,11 @@ import (
 	"golang.org/x/pkgsite/internal/testing/htmlcheck"
 )
 
-// TODO(https://github.com/golang/go/issues/40096): factor out this code reduce
-// duplication
+// setupFrontend starts a frontend that serves data from testDB, like the
+// frontend of an Env.
 func setupFrontend(ctx context.Context, t *testing.T, q queue.Queue, rc *redis.Client) *httptest.Server {
 	t.Helper()
-	const staticDir = "../../../static"
-	fs := &fetchserver.FetchServer{
-		Queue:                q,
-		TaskIDChangeInterval: 10 * time.Minute,
-	}
-	s, err := frontend.NewServer(frontend.ServerConfig{
-		FetchServer:      fs,
-		DataSourceGetter: func(context.Context) internal.DataSource { return testDB },
-		TemplateFS:       template.TrustedFSFromTrustedSource(template.TrustedSourceFromConstant(staticDir)),
-		StaticFS:         os.DirFS(staticDir),
-		ThirdPartyFS:     os.DirFS("../../../third_party"),
-		Queue:            q,
-		Config:           &config.Config{ServeStats: true},
-	})
-	if err != nil {
-		t.Fatal(err)
-	}
-	mux := http.NewServeMux()
-	var cacher frontend.Cacher
-	if rc != nil {
-		cacher = middleware.NewCacher(rc)
-	}
-	s.Install(mux.Handle, cacher, nil)
-
-	// Get experiments from the context. Fully roll them out.
-	expNames := experiment.FromContext(ctx).Active()
-	var exps []*internal.Experiment
-	for _, n := range expNames {
-		exps = append(exps, &internal.Experiment{
-			Name:    n,
-			Rollout: 100,
-		})
-	}
-	getter := func(context.Context) ([]*internal.Experiment, error) {
-		return exps, nil
-	}
-
-	experimenter, err := middleware.NewExperimenter(ctx, 1*time.Minute, getter, nil)
-	if err != nil {
-		t.Fatal(err)
-	}
-	enableCSP := true
-	mw := middleware.Chain(
-		middleware.AcceptRequests(http.MethodGet, http.MethodPost),
-		middleware.SecureHeaders(enableCSP),
-		middleware.Experiment(experimenter),
-	)
-	return httptest.NewServer(mw(mux))
+	return newFrontendServer(ctx, t, testDB, q, rc)
 }
 
 // TODO(https://github.com/golang/go/issues/40098): factor out this code reduce
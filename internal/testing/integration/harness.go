@@ -0,0 +1,276 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// github.com/alicebob/miniredis/v2 pulls in
+// github.com/yuin/gopher-lua which uses a non
+// build-tag-guarded use of the syscall package.
+//go:build !plan9
+
+// Package integration holds end-to-end tests of pkgsite, and an in-process
+// harness for writing them.
+//
+// An Env runs a module proxy and index, a worker and a frontend in the test
+// process, backed by a Postgres test database and an in-memory Redis. Tests
+// drive it from the outside, through the HTTP servers of the worker and
+// frontend, so that they cover full flows, such as processing a module and
+// then finding it in search and viewing its pages, without the scripts and
+// seeded databases of the tests directory.
+//
+// The database is set up by postgres.RunDBTests in TestMain, as for other
+// tests that need one; see doc/postgres.md for running Postgres in docker.
+package integration
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/safehtml/template"
+	"github.com/google/safehtml/template/uncheckedconversions"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/cache"
+	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/docrender/readme"
+	"golang.org/x/pkgsite/internal/experiment"
+	"golang.org/x/pkgsite/internal/frontend"
+	"golang.org/x/pkgsite/internal/frontend/fetchserver"
+	"golang.org/x/pkgsite/internal/index"
+	"golang.org/x/pkgsite/internal/middleware"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/proxy/proxytest"
+	"golang.org/x/pkgsite/internal/queue"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/worker"
+	"golang.org/x/pkgsite/static"
+	thirdparty "golang.org/x/pkgsite/third_party"
+)
+
+// An Env is an in-process pkgsite for end-to-end tests.
+type Env struct {
+	DB *postgres.DB
+	// Proxy serves the modules of the Env. Modules added to it can be
+	// processed with Fetch.
+	Proxy *proxytest.Server
+	// Fetcher is the fetcher of the worker.
+	Fetcher *worker.Fetcher
+	// Queue runs the fetches scheduled by the worker and the frontend.
+	Queue *queue.InMemory
+	// Redis is the cache of the frontend, which the worker invalidates.
+	Redis *redis.Client
+
+	Worker   *httptest.Server
+	Frontend *httptest.Server
+}
+
+// NewEnv starts an Env whose proxy serves modules and whose index lists them,
+// and which stores its data in db. The experiments active in ctx are fully
+// rolled out in the frontend. The servers are stopped and db is reset when
+// the test ends.
+func NewEnv(ctx context.Context, t *testing.T, db *postgres.DB, modules []*proxytest.Module) *Env {
+	t.Helper()
+	t.Cleanup(func() { postgres.ResetTestDB(db, t) })
+	// Make cache writes synchronous, so that pages are cached when they
+	// have been served.
+	middleware.TestMode = true
+
+	e := &Env{DB: db, Proxy: proxytest.NewServer(modules)}
+	proxyClient, teardownProxy, err := proxytest.NewClientForServer(e.Proxy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(teardownProxy)
+
+	var indexVersions []*internal.IndexVersion
+	for _, m := range modules {
+		indexVersions = append(indexVersions, &internal.IndexVersion{
+			Path:      m.ModulePath,
+			Version:   m.Version,
+			Timestamp: time.Now(),
+		})
+	}
+	indexClient, teardownIndex := index.SetupTestIndex(t, indexVersions)
+	t.Cleanup(teardownIndex)
+
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(mr.Close)
+	e.Redis = redis.NewClient(&redis.Options{Addr: mr.Addr()})
+
+	sourceClient := source.NewClient(http.DefaultClient)
+	e.Fetcher = &worker.Fetcher{
+		ProxyClient:    proxyClient,
+		SourceClient:   sourceClient,
+		DB:             db,
+		Cache:          cache.New(e.Redis),
+		ReadmeRenderer: readme.Renderer{},
+	}
+	// TODO: it would be better if InMemory made http requests
+	// back to worker, rather than calling fetch itself.
+	e.Queue = queue.NewInMemory(ctx, 10, nil, func(ctx context.Context, mpath, version string) (int, error) {
+		code, _, err := e.Fetcher.FetchAndUpdateState(ctx, mpath, version, "test")
+		return code, err
+	})
+
+	workerServer, err := worker.NewServer(&config.Config{}, worker.ServerConfig{
+		DB:               db,
+		IndexClient:      indexClient,
+		ProxyClient:      proxyClient,
+		SourceClient:     sourceClient,
+		RedisCacheClient: e.Redis,
+		Queue:            e.Queue,
+		StaticPath:       staticPath(),
+		ReadmeRenderer:   e.Fetcher.ReadmeRenderer,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	workerMux := http.NewServeMux()
+	workerServer.Install(workerMux.Handle)
+	e.Worker = httptest.NewServer(workerMux)
+	t.Cleanup(e.Worker.Close)
+
+	e.Frontend = newFrontendServer(ctx, t, db, e.Queue, e.Redis)
+	t.Cleanup(e.Frontend.Close)
+	return e
+}
+
+// staticPath returns the static directory of the repo. It is found from the
+// location of this file, so that an Env can be started from tests in any
+// directory.
+func staticPath() template.TrustedSource {
+	_, file, _, ok := runtime.Caller(0)
+	if !ok {
+		panic("unable to determine the location of the static directory")
+	}
+	dir := filepath.Join(filepath.Dir(file), "..", "..", "..", "static")
+	return uncheckedconversions.TrustedSourceFromStringKnownToSatisfyTypeContract(dir)
+}
+
+// newFrontendServer starts a frontend that serves data from ds, schedules
+// fetches on q, and caches pages in rc if it is not nil. The experiments
+// active in ctx are fully rolled out.
+func newFrontendServer(ctx context.Context, t *testing.T, ds internal.DataSource, q queue.Queue, rc *redis.Client) *httptest.Server {
+	t.Helper()
+	fs := &fetchserver.FetchServer{
+		Queue:                q,
+		TaskIDChangeInterval: 10 * time.Minute,
+	}
+	s, err := frontend.NewServer(frontend.ServerConfig{
+		FetchServer:      fs,
+		DataSourceGetter: func(context.Context) internal.DataSource { return ds },
+		TemplateFS:       template.TrustedFSFromEmbed(static.FS),
+		StaticFS:         static.FS,
+		ThirdPartyFS:     thirdparty.FS,
+		Queue:            q,
+		Config:           &config.Config{ServeStats: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := http.NewServeMux()
+	var cacher frontend.Cacher
+	if rc != nil {
+		cacher = middleware.NewCacher(rc)
+	}
+	s.Install(mux.Handle, cacher, nil)
+
+	var exps []*internal.Experiment
+	for _, n := range experiment.FromContext(ctx).Active() {
+		exps = append(exps, &internal.Experiment{
+			Name:    n,
+			Rollout: 100,
+		})
+	}
+	getter := func(context.Context) ([]*internal.Experiment, error) {
+		return exps, nil
+	}
+	experimenter, err := middleware.NewExperimenter(ctx, 1*time.Minute, getter, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enableCSP := true
+	mw := middleware.Chain(
+		middleware.AcceptRequests(http.MethodGet, http.MethodPost),
+		middleware.SecureHeaders(enableCSP),
+		middleware.Experiment(experimenter),
+	)
+	return httptest.NewServer(mw(mux))
+}
+
+// ProcessIndex processes the modules of the index the way the worker does in
+// production: it polls the index, enqueues the module versions it found, and
+// waits for them to be fetched. Since waiting closes the Queue, it can only be
+// called once; use Fetch to process more modules afterwards.
+func (e *Env) ProcessIndex(ctx context.Context, t *testing.T) {
+	t.Helper()
+	e.GetWorker(t, "/poll?limit=1000")
+	e.GetWorker(t, "/enqueue?limit=1000")
+	e.Queue.WaitForTesting(ctx)
+}
+
+// Fetch processes a version of a module served by the Proxy, and fails the
+// test if that does not succeed.
+func (e *Env) Fetch(ctx context.Context, t *testing.T, modulePath, version string) {
+	t.Helper()
+	code, _, err := e.Fetcher.FetchAndUpdateState(ctx, modulePath, version, "test")
+	if err != nil {
+		t.Fatalf("Fetch(%q, %q): %v", modulePath, version, err)
+	}
+	if code != http.StatusOK {
+		t.Fatalf("Fetch(%q, %q): status %d, want %d", modulePath, version, code, http.StatusOK)
+	}
+}
+
+// Get requests urlPath from the frontend, and returns the body of the
+// response. It fails the test if the response is not a 200.
+func (e *Env) Get(t *testing.T, urlPath string) string {
+	t.Helper()
+	code, body := e.get(t, e.Frontend.URL+urlPath)
+	if code != http.StatusOK {
+		t.Fatalf("GET %s: status %d, want %d", urlPath, code, http.StatusOK)
+	}
+	return body
+}
+
+// GetStatus requests urlPath from the frontend, and returns the status of the
+// response.
+func (e *Env) GetStatus(t *testing.T, urlPath string) int {
+	t.Helper()
+	code, _ := e.get(t, e.Frontend.URL+urlPath)
+	return code
+}
+
+// GetWorker requests urlPath from the worker, and returns the body of the
+// response. It fails the test if the response is not a 200.
+func (e *Env) GetWorker(t *testing.T, urlPath string) string {
+	t.Helper()
+	code, body := e.get(t, e.Worker.URL+urlPath)
+	if code != http.StatusOK {
+		t.Fatalf("GET worker %s: status %d, want %d: %s", urlPath, code, http.StatusOK, body)
+	}
+	return body
+}
+
+func (e *Env) get(t *testing.T, url string) (int, string) {
+	t.Helper()
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading response of %s: %v", url, err)
+	}
+	return resp.StatusCode, string(body)
+}
@@ -11,8 +11,6 @@ package integration
 
 import (
 	"context"
-	"fmt"
-	"io"
 	"net/http"
 	"sort"
 	"strings"
@@ -22,12 +20,9 @@ import (
 	"github.com/go-redis/redis/v8"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/safehtml/template"
-	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/docrender"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
-	"golang.org/x/pkgsite/internal/index"
-	"golang.org/x/pkgsite/internal/middleware"
 	"golang.org/x/pkgsite/internal/postgres"
-	"golang.org/x/pkgsite/internal/proxy"
 	"golang.org/x/pkgsite/internal/proxy/proxytest"
 )
 
@@ -41,29 +36,8 @@ func TestEndToEndProcessing(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	defer postgres.ResetTestDB(testDB, t)
-
-	middleware.TestMode = true
-
-	proxyClient, proxyServer, indexClient, teardownClients := setupProxyAndIndex(t)
-	defer teardownClients()
-
-	redisCacheClient, td := newRedisClient(t)
-	defer td()
-
-	workerHTTP, fetcher, queue := setupWorker(ctx, t, proxyClient, indexClient, redisCacheClient)
-	frontendHTTP := setupFrontend(ctx, t, queue, redisCacheClient)
-	if _, err := doGet(workerHTTP.URL + "/poll?limit=1000"); err != nil {
-		t.Fatal(err)
-	}
-	// TODO: This should really be made deterministic.
-	time.Sleep(100 * time.Millisecond)
-	if _, err := doGet(workerHTTP.URL + "/enqueue?limit=1000"); err != nil {
-		t.Fatal(err)
-	}
-	// TODO: This should really be made deterministic.
-	time.Sleep(200 * time.Millisecond)
-	queue.WaitForTesting(ctx)
+	env := NewEnv(ctx, t, testDB, testModules)
+	env.ProcessIndex(ctx, t)
 
 	var wantKeys []string
 	for _, test := range []struct {
@@ -81,11 +55,8 @@ func TestEndToEndProcessing(t *testing.T) {
 	} {
 		t.Run(strings.ReplaceAll(test.url, "/", "_"), func(t *testing.T) {
 			wantKeys = append(wantKeys, "/"+test.url)
-			body, err := doGet(frontendHTTP.URL + "/" + test.url)
-			if err != nil {
-				t.Fatalf("%s: %v", test.url, err)
-			}
-			if !strings.Contains(string(body), test.want) {
+			body := env.Get(t, "/"+test.url)
+			if !strings.Contains(body, test.want) {
 				t.Errorf("%q not found in body", test.want)
 				t.Logf("%s", body)
 			}
@@ -93,7 +64,7 @@ func TestEndToEndProcessing(t *testing.T) {
 	}
 
 	// Test cache invalidation.
-	keys := cacheKeys(t, redisCacheClient)
+	keys := cacheKeys(t, env.Redis)
 	sort.Strings(wantKeys)
 	if !cmp.Equal(keys, wantKeys) {
 		t.Errorf("cache keys: got %v, want %v", keys, wantKeys)
@@ -102,11 +73,8 @@ func TestEndToEndProcessing(t *testing.T) {
 	// Process a newer version of a module, and verify that the cache has been invalidated.
 	modulePath := "example.com/single"
 	version := "v1.2.3"
-	proxyServer.AddModule(proxytest.FindModule(testModules, modulePath, "v1.0.0").ChangeVersion(version))
-	_, _, err := fetcher.FetchAndUpdateState(ctx, modulePath, version, "test")
-	if err != nil {
-		t.Fatal(err)
-	}
+	env.Proxy.AddModule(proxytest.FindModule(testModules, modulePath, "v1.0.0").ChangeVersion(version))
+	env.Fetch(ctx, t, modulePath, version)
 
 	// All the keys with modulePath should be gone, but the others should remain.
 	wantKeys = nil
@@ -116,7 +84,7 @@ func TestEndToEndProcessing(t *testing.T) {
 			wantKeys = append(wantKeys, k)
 		}
 	}
-	keys = cacheKeys(t, redisCacheClient)
+	keys = cacheKeys(t, env.Redis)
 	if len(keys) == 0 {
 		keys = nil
 	}
@@ -125,53 +93,53 @@ func TestEndToEndProcessing(t *testing.T) {
 	}
 }
 
-func cacheKeys(t *testing.T, client *redis.Client) []string {
-	keys, err := client.Keys(context.Background(), "*").Result()
+// TestFetchSearchAndView follows a module from the index to search results
+// and its pages.
+func TestFetchSearchAndView(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	env := NewEnv(ctx, t, testDB, []*proxytest.Module{
+		proxytest.FindModule(testModules, "example.com/single", "v1.0.0"),
+	})
+	env.ProcessIndex(ctx, t)
+
+	if body := env.Get(t, "/search?q=sample&m=package"); !strings.Contains(body, "example.com/single/pkg") {
+		t.Errorf("search results do not contain example.com/single/pkg:\n%s", body)
+	}
+	if body := env.Get(t, "/example.com/single"); !strings.Contains(body, "This is the README") {
+		t.Errorf("module page does not contain its README:\n%s", body)
+	}
+	// The worker rendered and stored the README when it processed the
+	// module.
+	rs, err := env.DB.GetModuleReadmesToRender(ctx, "example.com/single", "v1.0.0")
 	if err != nil {
 		t.Fatal(err)
 	}
-	sort.Strings(keys)
-	return keys
-}
-
-// doGet executes an HTTP GET request for url and returns the response body, or
-// an error if anything went wrong or the response status code was not 200 OK.
-func doGet(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+	if len(rs) != 1 {
+		t.Fatalf("got %d READMEs, want 1", len(rs))
+	}
+	hash, err := docrender.ReadmeHash(&docrender.ReadmeRequest{
+		Readme:       rs[0].Readme,
+		SourceInfo:   rs[0].SourceInfo,
+		NoIssueLinks: rs[0].NoIssueLinks,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("http.Get(%q): %v", url, err)
+		t.Fatal(err)
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("http.Get(%q): status: %d, want %d", url, resp.StatusCode, http.StatusOK)
+	if _, err := env.DB.GetRenderedReadme(ctx, hash); err != nil {
+		t.Errorf("README was not stored: %v", err)
 	}
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("io.ReadAll(): %v", err)
+	if code := env.GetStatus(t, "/example.com/missing"); code != http.StatusNotFound {
+		t.Errorf("missing module: got status %d, want %d", code, http.StatusNotFound)
 	}
-	return body, nil
 }
 
-func setupProxyAndIndex(t *testing.T) (*proxy.Client, *proxytest.Server, *index.Client, func()) {
-	t.Helper()
-	proxyServer := proxytest.NewServer(testModules)
-	proxyClient, teardownProxy, err := proxytest.NewClientForServer(proxyServer)
+func cacheKeys(t *testing.T, client *redis.Client) []string {
+	keys, err := client.Keys(context.Background(), "*").Result()
 	if err != nil {
 		t.Fatal(err)
 	}
-
-	var indexVersions []*internal.IndexVersion
-	for _, m := range testModules {
-		indexVersions = append(indexVersions, &internal.IndexVersion{
-			Path:      m.ModulePath,
-			Version:   m.Version,
-			Timestamp: time.Now(),
-		})
-	}
-	indexClient, teardownIndex := index.SetupTestIndex(t, indexVersions)
-	teardown := func() {
-		teardownProxy()
-		teardownIndex()
-	}
-	return proxyClient, proxyServer, indexClient, teardown
+	sort.Strings(keys)
+	return keys
 }
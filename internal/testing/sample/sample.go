@@ -383,10 +383,11 @@ func Documentation(goos, goarch, fileContents string) *internal.Documentation {
 	}
 
 	return &internal.Documentation{
-		GOOS:     goos,
-		GOARCH:   goarch,
-		Synopsis: fmt.Sprintf("This is a package synopsis for GOOS=%s, GOARCH=%s", goos, goarch),
-		Source:   src,
+		GOOS:       goos,
+		GOARCH:     goarch,
+		Synopsis:   fmt.Sprintf("This is a package synopsis for GOOS=%s, GOARCH=%s", goos, goarch),
+		Source:     src,
+		SourceHash: godoc.SourceHash(src),
 	}
 }
 
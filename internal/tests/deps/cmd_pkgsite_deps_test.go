@@ -13,7 +13,8 @@ import (
 	"golang.org/x/pkgsite/internal/testenv"
 )
 
-// non-test packages are allowed to depend on licensecheck and safehtml, x/ repos, and markdown.
+// non-test packages are allowed to depend on licensecheck and safehtml, x/ repos, and markdown,
+// and on yaml to read the pkgsite.yaml files of modules.
 var allowedModDeps = map[string]bool{
 	"github.com/google/licensecheck": true,
 	"github.com/google/safehtml":     true,
@@ -21,8 +22,10 @@ var allowedModDeps = map[string]bool{
 	"golang.org/x/net":               true,
 	"golang.org/x/pkgsite":           true,
 	"golang.org/x/sync":              true,
+	"golang.org/x/telemetry":         true,
 	"golang.org/x/text":              true,
 	"golang.org/x/tools":             true,
+	"gopkg.in/yaml.v3":               true,
 	"rsc.io/markdown":                true,
 }
 
@@ -20,7 +20,7 @@ import (
 func TestCheckFrontendTemplates(t *testing.T) {
 	// Perform additional checks on parsed templates.
 	staticFS := template.TrustedFSFromEmbed(static.FS)
-	templates, err := templates.ParsePageTemplates(staticFS)
+	templates, err := templates.ParsePageTemplates(staticFS, nil)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -43,6 +43,10 @@ func TestCheckFrontendTemplates(t *testing.T) {
 			[]string{"unit-outline", "unit-readme", "unit-doc", "unit-files", "unit-directories"},
 			frontend.MainDetails{},
 		},
+		{"unit/diff", nil, frontend.UnitPage{}},
+		{"unit/diff", []string{"diff"}, versions.DiffDetails{}},
+		{"unit/history", nil, frontend.UnitPage{}},
+		{"unit/history", []string{"history"}, versions.SymbolHistoryDetails{}},
 		{"unit/importedby", nil, frontend.UnitPage{}},
 		{"unit/importedby", []string{"importedby"}, frontend.ImportedByDetails{}},
 		{"unit/imports", nil, frontend.UnitPage{}},
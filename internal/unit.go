@@ -45,6 +45,7 @@ type Unit struct {
 	Documentation   []*Documentation // at most one on read
 	Subdirectories  []*PackageMeta
 	Imports         []string
+	TestImports     []string // imported only by the package's tests
 	LicenseContents []*licenses.License
 	Symbols         map[BuildContext][]*Symbol
 	NumImports      int
@@ -57,6 +58,15 @@ type Unit struct {
 	// SymbolHistory is a map of symbolName to the version when the symbol was
 	// first added to the package.
 	SymbolHistory map[string]string
+
+	// LocalizedReadmes are translations of Readme, such as README.zh-CN.md,
+	// sorted by language.
+	LocalizedReadmes []*Readme
+
+	// DocsPages are the Markdown files under the docs directory of the unit,
+	// such as docs/guide.md, in lexical order. Reading a unit with WithMain
+	// sets only their Filepaths; WithDocsPages reads their contents.
+	DocsPages []*Readme
 }
 
 // Documentation is the rendered documentation for a given package
@@ -68,13 +78,67 @@ type Documentation struct {
 	GOARCH   string
 	Synopsis string
 	Source   []byte // encoded ast.Files; see godoc.Package.Encode
-	API      []*Symbol
+	// SourceHash is a hash of Source, computed when the package is fetched;
+	// see godoc.SourceHash.
+	SourceHash string
+	API        []*Symbol
+	// AppVersion is the version label of the worker that processed the
+	// documentation, and Toolchain is the Go version of that worker's
+	// binary, as reported by runtime.Version. Both are empty for
+	// documentation processed before they were recorded.
+	AppVersion string
+	Toolchain  string
+	// SkippedExamples are the example functions in the package's test files
+	// that are not displayed correctly with its documentation.
+	SkippedExamples []*SkippedExample
+	// BrokenDocLinks are the doc links in the package's doc comments, such
+	// as [Foo] or [pkg.Foo], that do not resolve to a symbol.
+	BrokenDocLinks []*BrokenDocLink
+	// ExcludedFiles are the non-test .go files of the package that are not
+	// built in this build context, so its documentation leaves them out.
+	ExcludedFiles []*ExcludedFile
+}
+
+// A SkippedExample is an example function that is not displayed correctly
+// with the documentation of its package, because of a mistake in its
+// declaration or in the placement of its output comment. Examples that do
+// not compile are not detected.
+type SkippedExample struct {
+	Name   string // name of the function, such as "ExampleFoo_bar"
+	File   string // name of the file containing it
+	Line   int    // line of its declaration
+	Reason string // explanation of the mistake, as a sentence
+}
+
+// A BrokenDocLink is a doc link in a doc comment, such as [Foo] or
+// [example.com/mod/pkg.Foo], to a symbol that does not exist. It is
+// displayed as plain text.
+type BrokenDocLink struct {
+	Text       string // text between the brackets
+	ImportPath string // path of the package linked to
+	Recv       string // type of the method linked to, if any
+	Name       string // name of the symbol linked to
+	File       string // name of the file containing the comment
+	Line       int    // line of the declaration the comment documents
+	Reason     string // explanation of the mistake, as a sentence
+}
+
+// An ExcludedFile is a .go file of a package that is left out of its
+// documentation for a build context because of its build constraint.
+type ExcludedFile struct {
+	Name string // name of the file
+	// BuildConstraint is the file's build constraint, from its name and its
+	// //go:build line, such as "!windows".
+	BuildConstraint string
 }
 
 // Readme is a README at the specified filepath.
 type Readme struct {
 	Filepath string
 	Contents string
+	// Lang is the BCP 47 language tag of a localized README, such as
+	// "zh-CN", or empty for the unit's main README.
+	Lang string `json:",omitempty"`
 }
 
 // PackageMeta represents the metadata of a package in a module version.
@@ -113,4 +177,5 @@ const (
 	WithMain FieldSet = 1 << iota
 	WithImports
 	WithLicenses
+	WithDocsPages
 )
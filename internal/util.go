@@ -6,9 +6,13 @@ package internal
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"strings"
 
+	"golang.org/x/mod/module"
 	"golang.org/x/pkgsite/internal/derrors"
 )
 
@@ -37,3 +41,56 @@ func ReadFileLines(filename string) (lines []string, err error) {
 	}
 	return lines, nil
 }
+
+// ReadSeeds reads a seed file, which lists the module versions to process
+// when populating a database, one per line, in the form M@V. The version V
+// is a version query, or "all" for every version of the module. Blank lines
+// and lines beginning with '#' are ignored.
+//
+// ReadSeeds reports every invalid line, along with its line number, in a
+// single error.
+func ReadSeeds(r io.Reader) ([]Modver, error) {
+	var (
+		mvs   []Modver
+		errs  []error
+		lines = map[Modver]int{} // line number of each module version
+	)
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		mv, err := ParseModver(line)
+		if err == nil {
+			err = checkSeed(mv)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("line %d: %v", n, err))
+			continue
+		}
+		if prev, ok := lines[mv]; ok {
+			errs = append(errs, fmt.Errorf("line %d: %s is already listed on line %d", n, mv, prev))
+			continue
+		}
+		lines[mv] = n
+		mvs = append(mvs, mv)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return mvs, nil
+}
+
+func checkSeed(mv Modver) error {
+	if err := module.CheckImportPath(mv.Path); err != nil {
+		return err
+	}
+	if mv.Version == "" || strings.ContainsAny(mv.Version, " \t") {
+		return fmt.Errorf("%s: bad version %q", mv, mv.Version)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadSeeds(t *testing.T) {
+	got, err := ReadSeeds(strings.NewReader(`# Seeds.
+
+std@all
+golang.org/x/tools@v0.1.0
+github.com/julieqiu/api-demo@main
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Modver{
+		{Path: "std", Version: "all"},
+		{Path: "golang.org/x/tools", Version: "v0.1.0"},
+		{Path: "github.com/julieqiu/api-demo", Version: "main"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	_, err = ReadSeeds(strings.NewReader(`golang.org/x/tools
+golang.org/x/tools@v0.1.0
+golang.org/x/tools@v0.1.0
+-bad@v1.0.0
+`))
+	if err == nil {
+		t.Fatal("got nil, want error")
+	}
+	for _, w := range []string{
+		"line 1: ",
+		"line 3: golang.org/x/tools@v0.1.0 is already listed on line 2",
+		"line 4: ",
+	} {
+		if !strings.Contains(err.Error(), w) {
+			t.Errorf("got %v, want error containing %q", err, w)
+		}
+	}
+}
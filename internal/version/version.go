@@ -53,11 +53,24 @@ func IsPseudo(v string) bool {
 	return strings.Count(v, "-") >= 2 && pseudoVersionRE.MatchString(v)
 }
 
+var commitHashRE = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// IsCommitHash reports whether v is a full Git commit hash. The proxy resolves
+// such a version to a pseudo-version, or to a tag on the commit.
+func IsCommitHash(v string) bool {
+	return commitHashRE.MatchString(v)
+}
+
 // IsIncompatible reports whether a valid version v is an incompatible version.
 func IsIncompatible(v string) bool {
 	return strings.HasSuffix(v, "+incompatible")
 }
 
+// TrimIncompatible returns v without its "+incompatible" suffix, if any.
+func TrimIncompatible(v string) string {
+	return strings.TrimSuffix(v, "+incompatible")
+}
+
 // ParseType returns the Type of a given a version.
 func ParseType(version string) (Type, error) {
 	if !semver.IsValid(version) {
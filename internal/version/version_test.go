@@ -152,6 +152,38 @@ func TestParseVersionType(t *testing.T) {
 	}
 }
 
+func TestTrimIncompatible(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"v1.2.3", "v1.2.3"},
+		{"v2.0.0+incompatible", "v2.0.0"},
+		{"v2.0.0-20190124233150-8f7fa2680c82+incompatible", "v2.0.0-20190124233150-8f7fa2680c82"},
+	} {
+		if got := TrimIncompatible(test.in); got != test.want {
+			t.Errorf("TrimIncompatible(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestIsCommitHash(t *testing.T) {
+	for _, test := range []struct {
+		in   string
+		want bool
+	}{
+		{"8f7fa2680c82f1f0e9f1c6b6a7e3d5c4b3a29180", true},
+		{"8f7fa2680c82", false},
+		{"8F7FA2680C82F1F0E9F1C6B6A7E3D5C4B3A29180", false},
+		{"8f7fa2680c82f1f0e9f1c6b6a7e3d5c4b3a2918g", false},
+		{"master", false},
+		{"v1.2.3", false},
+	} {
+		if got := IsCommitHash(test.in); got != test.want {
+			t.Errorf("IsCommitHash(%q) = %t, want %t", test.in, got, test.want)
+		}
+	}
+}
+
 func TestLatestOf(t *testing.T) {
 	for _, test := range []struct {
 		name     string
@@ -240,6 +272,34 @@ func TestLatest(t *testing.T) {
 			versions: []string{pseudo, "v2.0.0+incompatible"},
 			want:     "v2.0.0+incompatible",
 		},
+		{
+			name: "go.mod added after incompatible",
+			// The module gained a go.mod file at v1.1.0, after v2.0.0 was
+			// tagged without one.
+			versions: []string{"v1.0.0", "v2.0.0+incompatible", "v1.1.0"},
+			hasGoMod: func(v string) (bool, error) { return v == "v1.1.0", nil },
+			want:     "v1.1.0",
+		},
+		{
+			name: "go.mod removed after compatible",
+			// Only the older compatible version has a go.mod file. Only the
+			// latest compatible version matters.
+			versions: []string{"v1.0.0", "v1.1.0", "v2.0.0+incompatible"},
+			hasGoMod: func(v string) (bool, error) { return v == "v1.0.0", nil },
+			want:     "v2.0.0+incompatible",
+		},
+		{
+			name:     "incompatible pre-release",
+			versions: []string{"v1.2.3", "v3.0.0-rc.1+incompatible"},
+			hasGoMod: func(v string) (bool, error) { return false, nil },
+			want:     "v1.2.3",
+		},
+		{
+			name:     "incompatible pseudo",
+			versions: []string{"v1.2.3", "v2.0.0-20190124233150-8f7fa2680c82+incompatible"},
+			hasGoMod: func(v string) (bool, error) { return false, nil },
+			want:     "v1.2.3",
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 
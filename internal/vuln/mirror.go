@@ -0,0 +1,316 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vuln
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/osv"
+	"golang.org/x/pkgsite/internal/poller"
+	"golang.org/x/sync/errgroup"
+)
+
+// MirrorOptions are options for NewMirrorClient.
+type MirrorOptions struct {
+	// SyncInterval is how often the mirror is synced with its source. If it
+	// is zero, the mirror is synced only when it is created.
+	SyncInterval time.Duration
+
+	// ExtraDir is a directory of OSV entries to serve along with those of
+	// the source, such as advisories for private modules. Each entry is in a
+	// file named for its ID, like "PRIV-2026-0001.json". The directory is
+	// read at every sync. If ExtraDir is empty, there are no extra entries.
+	ExtraDir string
+
+	// Extra returns more entries to serve along with those of the source,
+	// such as the advisories published to the frontend. It is called at
+	// every sync. If it is nil, there are no such entries.
+	Extra func(context.Context) ([]*osv.Entry, error)
+}
+
+// NewMirrorClient returns a client that reads from an in-memory mirror of
+// the vulnerability database in src, a URL as for NewClient. It is meant for
+// deployments that cannot reach vuln.go.dev, and read a copy of it from a
+// local directory or an internal server instead.
+//
+// The mirror is synced before NewMirrorClient returns, and then every
+// opts.SyncInterval until ctx is done. A sync checks that the database is
+// consistent: that its indexes agree with its entries, and that it is not
+// older than the last copy. If a sync fails, the error is logged and the
+// mirror keeps serving the last copy.
+func NewMirrorClient(ctx context.Context, src string, opts MirrorOptions) (_ *Client, err error) {
+	defer derrors.Wrap(&err, "vuln.NewMirrorClient(%q)", src)
+
+	s, err := NewSource(src)
+	if err != nil {
+		return nil, err
+	}
+	ms, err := newMirrorSource(ctx, s, opts)
+	if err != nil {
+		return nil, err
+	}
+	return newClient(ms), nil
+}
+
+// mirrorSource serves an in-memory copy of another source.
+type mirrorSource struct {
+	src      source
+	extraDir string
+	extra    func(context.Context) ([]*osv.Entry, error)
+	poller   *poller.Poller // the current value is a *mirror
+}
+
+// A mirror is a copy of a vulnerability database.
+type mirror struct {
+	// modified is the modified time of the source database.
+	modified time.Time
+	// entries are the entries of the source database, by ID.
+	entries map[string]*osv.Entry
+	// data is what the mirror serves: the source database with the extra
+	// entries.
+	data *inMemorySource
+}
+
+func newMirrorSource(ctx context.Context, src source, opts MirrorOptions) (*mirrorSource, error) {
+	ms := &mirrorSource{src: src, extraDir: opts.ExtraDir, extra: opts.Extra}
+	m, err := ms.sync(ctx, &mirror{})
+	if err != nil {
+		return nil, err
+	}
+	ms.poller = poller.New(m,
+		func(ctx context.Context) (any, error) {
+			return ms.sync(ctx, ms.current())
+		},
+		func(err error) {
+			log.Errorf(ctx, "syncing vulnerability database mirror: %v", err)
+		})
+	if opts.SyncInterval > 0 {
+		ms.poller.Start(ctx, opts.SyncInterval)
+	}
+	return ms, nil
+}
+
+func (ms *mirrorSource) current() *mirror {
+	return ms.poller.Current().(*mirror)
+}
+
+func (ms *mirrorSource) get(ctx context.Context, endpoint string) ([]byte, error) {
+	return ms.current().data.get(ctx, endpoint)
+}
+
+// sync returns a new copy of the source database, reusing the entries of
+// prev that have not been modified since.
+func (ms *mirrorSource) sync(ctx context.Context, prev *mirror) (_ *mirror, err error) {
+	defer derrors.Wrap(&err, "sync")
+
+	var db DBMeta
+	if err := getJSON(ctx, ms.src, dbEndpoint, &db); err != nil {
+		return nil, err
+	}
+	if db.Modified.Before(prev.modified) {
+		return nil, fmt.Errorf("database was modified at %s, before the mirrored copy (%s)",
+			db.Modified.Format(time.RFC3339), prev.modified.Format(time.RFC3339))
+	}
+	var vulns []VulnMeta
+	if err := getJSON(ctx, ms.src, vulnsEndpoint, &vulns); err != nil {
+		return nil, err
+	}
+	var modules []*ModuleMeta
+	if err := getJSON(ctx, ms.src, modulesEndpoint, &modules); err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string]*osv.Entry, len(vulns))
+	var fetch []VulnMeta
+	for _, v := range vulns {
+		if _, ok := entries[v.ID]; ok {
+			return nil, fmt.Errorf("%s is listed twice in %s", v.ID, vulnsEndpoint)
+		}
+		if e := prev.entries[v.ID]; e != nil && e.Modified.Equal(v.Modified) {
+			entries[v.ID] = e
+			continue
+		}
+		entries[v.ID] = nil
+		fetch = append(fetch, v)
+	}
+	fetched := make([]*osv.Entry, len(fetch))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(10)
+	for i, v := range fetch {
+		g.Go(func() error {
+			var e osv.Entry
+			if err := getJSON(gctx, ms.src, path.Join(idDir, v.ID), &e); err != nil {
+				return err
+			}
+			if e.ID != v.ID {
+				return fmt.Errorf("entry %s has ID %q", v.ID, e.ID)
+			}
+			if !e.Modified.Equal(v.Modified) {
+				return fmt.Errorf("entry %s was modified at %s, but %s says %s", v.ID,
+					e.Modified.Format(time.RFC3339), vulnsEndpoint, v.Modified.Format(time.RFC3339))
+			}
+			fetched[i] = &e
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	for i, v := range fetch {
+		entries[v.ID] = fetched[i]
+	}
+	if err := checkModules(modules, entries); err != nil {
+		return nil, err
+	}
+
+	extra, err := readEntries(ms.extraDir)
+	if err != nil {
+		return nil, err
+	}
+	if ms.extra != nil {
+		more, err := ms.extra(ctx)
+		if err != nil {
+			return nil, err
+		}
+		extra = append(extra, more...)
+	}
+	all := make([]*osv.Entry, 0, len(entries)+len(extra))
+	for _, e := range entries {
+		all = append(all, e)
+	}
+	for _, e := range extra {
+		if entries[e.ID] != nil {
+			return nil, fmt.Errorf("extra entry %s is also in the database", e.ID)
+		}
+		all = append(all, e)
+	}
+	data, err := newInMemorySource(all)
+	if err != nil {
+		return nil, err
+	}
+	if err := bumpModified(data, prev.data); err != nil {
+		return nil, err
+	}
+	return &mirror{modified: db.Modified, entries: entries, data: data}, nil
+}
+
+// checkModules checks that the modules index of a database lists exactly the
+// affected modules of its entries.
+func checkModules(modules []*ModuleMeta, entries map[string]*osv.Entry) error {
+	type modVuln struct{ mod, id string }
+	listed := map[modVuln]bool{}
+	for _, m := range modules {
+		for _, v := range m.Vulns {
+			if _, ok := entries[v.ID]; !ok {
+				return fmt.Errorf("%s lists %s for %s, but it is not in %s", modulesEndpoint, v.ID, m.Path, vulnsEndpoint)
+			}
+			listed[modVuln{m.Path, v.ID}] = true
+		}
+	}
+	affected := map[modVuln]bool{}
+	for id, e := range entries {
+		for _, a := range e.Affected {
+			mv := modVuln{a.Module.Path, id}
+			if !listed[mv] {
+				return fmt.Errorf("%s does not list %s for %s", modulesEndpoint, id, a.Module.Path)
+			}
+			affected[mv] = true
+		}
+	}
+	for mv := range listed {
+		if !affected[mv] {
+			return fmt.Errorf("%s lists %s for %s, which it does not affect", modulesEndpoint, mv.id, mv.mod)
+		}
+	}
+	return nil
+}
+
+// readEntries reads the OSV entries in the files of dir with the extension
+// ".json". It returns nil if dir is empty.
+func readEntries(dir string) (_ []*osv.Entry, err error) {
+	defer derrors.Wrap(&err, "readEntries(%q)", dir)
+
+	if dir == "" {
+		return nil, nil
+	}
+	des, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []*osv.Entry
+	for _, de := range des {
+		id, ok := strings.CutSuffix(de.Name(), ".json")
+		if !ok || de.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, de.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var e osv.Entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("%s: %w", de.Name(), err)
+		}
+		if e.ID != id {
+			return nil, fmt.Errorf("%s: entry has ID %q", de.Name(), e.ID)
+		}
+		entries = append(entries, &e)
+	}
+	return entries, nil
+}
+
+// bumpModified makes sure that the modified time of data is after that of
+// prev if their indexes differ.
+//
+// A Client clears its cache only when the modified time of the database
+// changes. The modified time of data is that of its latest entry, which
+// doesn't change if an extra entry is removed, or added with an earlier
+// modified time.
+func bumpModified(data, prev *inMemorySource) error {
+	if prev == nil ||
+		(bytes.Equal(data.data[vulnsEndpoint], prev.data[vulnsEndpoint]) &&
+			bytes.Equal(data.data[modulesEndpoint], prev.data[modulesEndpoint])) {
+		return nil
+	}
+	var db, prevDB DBMeta
+	if err := json.Unmarshal(data.data[dbEndpoint], &db); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(prev.data[dbEndpoint], &prevDB); err != nil {
+		return err
+	}
+	if db.Modified.After(prevDB.Modified) {
+		return nil
+	}
+	db.Modified = prevDB.Modified.Add(time.Second)
+	b, err := json.Marshal(db)
+	if err != nil {
+		return err
+	}
+	data.data[dbEndpoint] = b
+	return nil
+}
+
+// getJSON unmarshals the contents of endpoint in src into v.
+func getJSON(ctx context.Context, src source, endpoint string, v any) error {
+	data, err := src.get(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%s: %w", endpoint, err)
+	}
+	return nil
+}
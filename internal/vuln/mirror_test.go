@@ -0,0 +1,200 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vuln
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/osv"
+)
+
+// countingSource counts the gets of the endpoints of a source.
+type countingSource struct {
+	source
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (s *countingSource) get(ctx context.Context, endpoint string) ([]byte, error) {
+	s.mu.Lock()
+	s.counts[endpoint]++
+	s.mu.Unlock()
+	return s.source.get(ctx, endpoint)
+}
+
+func TestMirror(t *testing.T) {
+	ctx := context.Background()
+	upstream, err := newInMemorySource([]*osv.Entry{&testOSV1, &testOSV2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := &countingSource{source: upstream, counts: map[string]int{}}
+	extraDir := t.TempDir()
+	private := osv.Entry{
+		ID:       "PRIV-2000-0001",
+		Modified: jan1999,
+		Affected: []osv.Affected{{Module: osv.Module{Path: "corp.example.com/mod"}}},
+	}
+	writeEntry(t, extraDir, &private)
+
+	ms, err := newMirrorSource(ctx, src, MirrorOptions{ExtraDir: extraDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newClient(ms)
+	checkIDs := func(want string) {
+		t.Helper()
+		// The cache is consulted only after the modified time is reread.
+		c.modifiedFetched = c.modifiedFetched.Add(-2 * modifiedStaleDur)
+		got, err := c.IDs(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if g := strings.Join(got, " "); g != want {
+			t.Errorf("got IDs %q, want %q", g, want)
+		}
+	}
+	checkIDs("GO-1999-0001 GO-2000-0002 PRIV-2000-0001")
+	got, err := c.ByPackage(ctx, &PackageRequest{Module: "corp.example.com/mod", Version: "v1.0.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ID != private.ID {
+		t.Errorf("ByPackage(corp.example.com/mod) = %v, want %s", got, private.ID)
+	}
+
+	// Unmodified entries are not fetched again.
+	upstream3, err := newInMemorySource([]*osv.Entry{&testOSV1, &testOSV2, &testOSV3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src.source = upstream3
+	ms.poller.Poll(ctx)
+	checkIDs("GO-1999-0001 GO-2000-0002 GO-2000-0003 PRIV-2000-0001")
+	for id, want := range map[string]int{testOSV1.ID: 1, testOSV3.ID: 1} {
+		if got := src.counts[idDir+"/"+id]; got != want {
+			t.Errorf("%s fetched %d times, want %d", id, got, want)
+		}
+	}
+
+	// Removing an extra entry is noticed, although no modified time changes.
+	if err := os.Remove(filepath.Join(extraDir, private.ID+".json")); err != nil {
+		t.Fatal(err)
+	}
+	ms.poller.Poll(ctx)
+	checkIDs("GO-1999-0001 GO-2000-0002 GO-2000-0003")
+}
+
+func TestMirrorIntegrity(t *testing.T) {
+	ctx := context.Background()
+	for _, test := range []struct {
+		name    string
+		corrupt func(*inMemorySource)
+		want    string
+	}{
+		{
+			name: "older database",
+			corrupt: func(s *inMemorySource) {
+				s.data[dbEndpoint] = []byte(`{"modified":"1990-01-01T00:00:00Z"}`)
+			},
+			want: "before the mirrored copy",
+		},
+		{
+			name: "missing entry",
+			corrupt: func(s *inMemorySource) {
+				delete(s.data, idDir+"/"+testOSV3.ID)
+			},
+			want: "no data found",
+		},
+		{
+			name: "wrong ID",
+			corrupt: func(s *inMemorySource) {
+				s.data[idDir+"/"+testOSV3.ID] = s.data[idDir+"/"+testOSV2.ID]
+			},
+			want: `has ID "GO-2000-0002"`,
+		},
+		{
+			name: "wrong modules index",
+			corrupt: func(s *inMemorySource) {
+				s.data[modulesEndpoint] = []byte(`[{"path":"stdlib","vulns":[{"id":"GO-1999-0001"}]}]`)
+			},
+			want: "does not list",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			upstream, err := newInMemorySource([]*osv.Entry{&testOSV1, &testOSV2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			src := &countingSource{source: upstream, counts: map[string]int{}}
+			ms, err := newMirrorSource(ctx, src, MirrorOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			before := ms.current()
+
+			next, err := newInMemorySource([]*osv.Entry{&testOSV1, &testOSV2, &testOSV3})
+			if err != nil {
+				t.Fatal(err)
+			}
+			test.corrupt(next)
+			src.source = next
+			_, err = ms.sync(ctx, before)
+			if err == nil || !strings.Contains(err.Error(), test.want) {
+				t.Fatalf("got error %v, want one containing %q", err, test.want)
+			}
+			// The mirror keeps serving the last copy.
+			ms.poller.Poll(ctx)
+			if ms.current() != before {
+				t.Error("mirror changed after a failed sync")
+			}
+		})
+	}
+}
+
+func writeEntry(t *testing.T, dir string, e *osv.Entry) {
+	t.Helper()
+	data, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, e.ID+".json"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMirrorExtra(t *testing.T) {
+	ctx := context.Background()
+	upstream, err := newInMemorySource([]*osv.Entry{&testOSV1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra := []*osv.Entry{{ID: "CORP-2026-0001", Affected: []osv.Affected{{Module: osv.Module{Path: "corp.example.com/mod"}}}}}
+	ms, err := newMirrorSource(ctx, upstream, MirrorOptions{
+		Extra: func(context.Context) ([]*osv.Entry, error) { return extra, nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := newClient(ms).ByID(ctx, "CORP-2026-0001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e == nil {
+		t.Fatal("extra entry not found")
+	}
+
+	// An extra entry with the ID of an entry of the database fails the sync.
+	extra = append(extra, &osv.Entry{ID: testOSV1.ID})
+	if _, err := ms.sync(ctx, ms.current()); err == nil {
+		t.Error("sync succeeded with a duplicate ID")
+	}
+}
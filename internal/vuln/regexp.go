@@ -15,13 +15,17 @@ const (
 	cveRE = "^CVE-[0-9]{4}-[0-9]+$"
 	// Regexp adapted from https://github.com/github/advisory-database.
 	ghsaRE = "^(GHSA)((-[23456789cfghjmpqrvwx]{4}){3})$"
+	// IDs of advisories that are not in the Go vulnerability database
+	// have the form of Go IDs, with another prefix.
+	privateRE = "^([A-Z][A-Z0-9]{1,15})-[0-9]{4}-[0-9]{4,}$"
 )
 
 // Case-insensitive regexps for vuln IDs/aliases.
 var (
-	goID   = regexp.MustCompile(ci + goRE)
-	cveID  = regexp.MustCompile(ci + cveRE)
-	ghsaID = regexp.MustCompile(ci + ghsaRE)
+	goID      = regexp.MustCompile(ci + goRE)
+	cveID     = regexp.MustCompile(ci + cveRE)
+	ghsaID    = regexp.MustCompile(ci + ghsaRE)
+	privateID = regexp.MustCompile(ci + privateRE)
 )
 
 // CanonicalGoID returns the canonical form of the given Go ID string
@@ -35,6 +39,24 @@ func CanonicalGoID(id string) (_ string, ok bool) {
 	return "", false
 }
 
+// CanonicalPrivateID returns the canonical form of the given ID of an
+// advisory that is not in the Go vulnerability database, such as one for a
+// private module, by correcting the case. Such IDs look like Go IDs with
+// another prefix, as in PRIV-2026-0001; the prefixes GO and CVE are taken.
+//
+// If no canonical form can be found, it returns false.
+func CanonicalPrivateID(id string) (_ string, ok bool) {
+	parts := privateID.FindStringSubmatch(id)
+	if len(parts) != 2 {
+		return "", false
+	}
+	switch strings.ToUpper(parts[1]) {
+	case "GO", "CVE":
+		return "", false
+	}
+	return strings.ToUpper(id), true
+}
+
 // CanonicalAlias returns the canonical form of the given alias ID string
 // (a CVE or GHSA id) by correcting the case.
 //
@@ -119,3 +119,50 @@ func TestCanonicalAlias(t *testing.T) {
 		})
 	}
 }
+
+func TestCanonicalPrivateID(t *testing.T) {
+	tests := []struct {
+		id     string
+		wantID string
+		wantOK bool
+	}{
+		{
+			id:     "PRIV-2026-0001",
+			wantID: "PRIV-2026-0001",
+			wantOK: true,
+		},
+		{
+			id:     "corp2-2026-00012",
+			wantID: "CORP2-2026-00012",
+			wantOK: true,
+		},
+		{
+			id:     "GO-2026-0001",
+			wantID: "",
+			wantOK: false,
+		},
+		{
+			id:     "cve-2026-0001",
+			wantID: "",
+			wantOK: false,
+		},
+		{
+			id:     "GHSA-cfgh-2345-rwxq",
+			wantID: "",
+			wantOK: false,
+		},
+		{
+			id:     "PRIV-2026",
+			wantID: "",
+			wantOK: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			gotID, gotOK := CanonicalPrivateID(tt.id)
+			if gotID != tt.wantID || gotOK != tt.wantOK {
+				t.Errorf("CanonicalPrivateID(%s) = (%s, %t), want (%s, %t)", tt.id, gotID, gotOK, tt.wantID, tt.wantOK)
+			}
+		})
+	}
+}
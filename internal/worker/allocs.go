@@ -0,0 +1,43 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/memory"
+)
+
+// allocsStatus is the content of the allocs page.
+type allocsStatus struct {
+	Env    string                 `json:"-"`
+	Routes []memory.RouteAllocs   `json:"routes"`
+	Top    []memory.RequestAllocs `json:"top"`
+}
+
+// doAllocsPage displays the heap allocations of the requests served by this
+// instance: the totals for each route, and the requests that allocated the
+// most. With the query param "format=json", it writes them as JSON instead.
+func (s *Server) doAllocsPage(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "doAllocsPage")
+	status := &allocsStatus{
+		Env:    env(s.cfg),
+		Routes: []memory.RouteAllocs{},
+		Top:    []memory.RequestAllocs{},
+	}
+	if s.allocs != nil {
+		status.Routes = append(status.Routes, s.allocs.Routes()...)
+		status.Top = append(status.Top, s.allocs.Top()...)
+	}
+	if r.FormValue("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	}
+	return renderPage(r.Context(), w, status, s.templates[allocsTemplate])
+}
@@ -0,0 +1,116 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// handleListAPIKeys lists the issued API keys, without the keys themselves.
+func (s *Server) handleListAPIKeys(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "handleListAPIKeys")
+	aks, err := s.db.GetAPIKeys(r.Context())
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tQPS\tADVISORIES\tANALYSIS\tCREATED\tREVOKED")
+	for _, ak := range aks {
+		revoked := "-"
+		if !ak.RevokedAt.IsZero() {
+			revoked = ak.RevokedAt.Format(time.RFC3339)
+		}
+		qps := "default"
+		if ak.QPS > 0 {
+			qps = strconv.Itoa(ak.QPS)
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", ak.ID, ak.Name, qps,
+			prefixList(ak.AdvisoryPrefixes), prefixList(ak.AnalysisPrefixes), ak.CreatedAt.Format(time.RFC3339), revoked)
+	}
+	return tw.Flush()
+}
+
+// prefixList returns the module path prefixes of an API key, separated by
+// commas, or "-" if there are none.
+func prefixList(prefixes []string) string {
+	if len(prefixes) == 0 {
+		return "-"
+	}
+	return strings.Join(prefixes, ",")
+}
+
+// handleCreateAPIKey issues an API key to the client in the "name" query
+// param, with the optional per-second quota in the "qps" query param.
+// The key may publish advisories for the modules under the module path
+// prefixes in the "advisory_prefix" query params, and post analysis reports
+// for the modules under those in the "analysis_prefix" query params, if any.
+// The key is displayed only in the response.
+func (s *Server) handleCreateAPIKey(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "handleCreateAPIKey")
+	name := r.FormValue("name")
+	if name == "" {
+		return &serverError{http.StatusBadRequest, errors.New("must provide 'name' query param")}
+	}
+	qps := parseIntParam(r, "qps", 0)
+	if qps < 0 {
+		return &serverError{http.StatusBadRequest, errors.New("'qps' query param must not be negative")}
+	}
+	advisoryPrefixes, err := prefixParams(r, "advisory_prefix")
+	if err != nil {
+		return err
+	}
+	analysisPrefixes, err := prefixParams(r, "analysis_prefix")
+	if err != nil {
+		return err
+	}
+	key, ak, err := s.db.CreateAPIKey(r.Context(), name, qps, advisoryPrefixes, analysisPrefixes)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "created API key %d for %q; it will not be displayed again:\n%s\n", ak.ID, ak.Name, key)
+	return nil
+}
+
+// prefixParams returns the module path prefixes in the query params of r
+// named param.
+func prefixParams(r *http.Request, param string) ([]string, error) {
+	var prefixes []string
+	for _, p := range r.Form[param] {
+		p = strings.TrimSuffix(p, "/")
+		if err := module.CheckImportPath(p); err != nil {
+			return nil, &serverError{http.StatusBadRequest, fmt.Errorf("invalid '%s' query param: %v", param, err)}
+		}
+		prefixes = append(prefixes, p)
+	}
+	return prefixes, nil
+}
+
+// handleRevokeAPIKey revokes the API key whose ID is the "id" query param.
+func (s *Server) handleRevokeAPIKey(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "handleRevokeAPIKey")
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		return &serverError{http.StatusBadRequest, errors.New("must provide a numeric 'id' query param")}
+	}
+	if err := s.db.RevokeAPIKey(r.Context(), id); err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			return &serverError{http.StatusNotFound, err}
+		}
+		return err
+	}
+	fmt.Fprintf(w, "revoked API key %d", id)
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config/serverconfig"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/postgres"
+)
+
+// doDocVersionsPage displays the number of documentation rows processed by
+// each app version and toolchain. With the query param "app_version", it
+// also lists the module versions with documentation processed by an older
+// app version, or, if "toolchain" is also given, by a different toolchain.
+// With the query param "format=json", it writes them as JSON instead.
+func (s *Server) doDocVersionsPage(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "doDocVersionsPage")
+	ctx := r.Context()
+	versions, err := s.db.GetDocumentationVersions(ctx)
+	if err != nil {
+		return annotation{err, "error fetching documentation versions"}
+	}
+	appVersion := r.FormValue("app_version")
+	toolchain := r.FormValue("toolchain")
+	var outdated []internal.Modver
+	if appVersion != "" {
+		if err := serverconfig.ValidateAppVersion(appVersion); err != nil {
+			return &serverError{http.StatusBadRequest, err}
+		}
+		outdated, err = s.db.GetModulesWithOutdatedDocumentation(ctx, appVersion, toolchain, parseIntParam(r, "limit", 100))
+		if err != nil {
+			return annotation{err, "error fetching outdated documentation"}
+		}
+	}
+	page := struct {
+		Env        string                            `json:"-"`
+		AppVersion string                            `json:"appVersion,omitempty"`
+		Toolchain  string                            `json:"toolchain,omitempty"`
+		Versions   []*postgres.DocumentationVersions `json:"versions"`
+		Outdated   []internal.Modver                 `json:"outdated,omitempty"`
+	}{
+		Env:        env(s.cfg),
+		AppVersion: appVersion,
+		Toolchain:  toolchain,
+		Versions:   versions,
+		Outdated:   outdated,
+	}
+	if r.FormValue("format") == "json" {
+		if page.Versions == nil {
+			page.Versions = []*postgres.DocumentationVersions{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(page)
+	}
+	return renderPage(ctx, w, page, s.templates[docVersionsTemplate])
+}
@@ -5,7 +5,6 @@
 package worker
 
 import (
-	"bufio"
 	"context"
 	"errors"
 	"fmt"
@@ -16,14 +15,11 @@ import (
 
 	"cloud.google.com/go/storage"
 	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/config/dynconfig"
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/postgres"
 )
 
-type exclusion struct {
-	pattern, reason string
-}
-
 // PopulateExcluded adds each element of excludedPrefixes to the excluded_prefixes
 // table if it isn't already present.
 func PopulateExcluded(ctx context.Context, cfg *config.Config, db *postgres.DB) error {
@@ -55,9 +51,9 @@ func PopulateExcluded(ctx context.Context, cfg *config.Config, db *postgres.DB)
 		}
 	}
 	defer r.Close()
-	lines, err := readExcludedLines(ctx, r)
+	exs, err := dynconfig.ParseExclusions(r)
 	if err != nil {
-		return err
+		return fmt.Errorf("reading exclusions from %s: %w", location, err)
 	}
 	user := os.Getenv("USER")
 	if user == "" {
@@ -67,37 +63,12 @@ func PopulateExcluded(ctx context.Context, cfg *config.Config, db *postgres.DB)
 	if err != nil {
 		return err
 	}
-	for _, line := range lines {
-		if !slices.Contains(pats, line.pattern) {
-			if err := db.InsertExcludedPattern(ctx, line.pattern, user, line.reason); err != nil {
-				return fmt.Errorf("db.InsertExcludedPrefix(%q, %q, %q): %v", line.pattern, user, line.reason, err)
+	for _, ex := range exs {
+		if !slices.Contains(pats, ex.Pattern) {
+			if err := db.InsertExcludedPattern(ctx, ex.Pattern, user, ex.Reason); err != nil {
+				return fmt.Errorf("db.InsertExcludedPrefix(%q, %q, %q): %v", ex.Pattern, user, ex.Reason, err)
 			}
 		}
 	}
 	return nil
 }
-
-func readExcludedLines(ctx context.Context, r io.Reader) ([]exclusion, error) {
-	var lines []exclusion
-	s := bufio.NewScanner(r)
-	for s.Scan() {
-		line := strings.TrimSpace(s.Text())
-		if line == "" || strings.HasPrefix(line, "#") {
-			continue
-		}
-		var prefix, reason string
-		i := strings.IndexAny(line, " \t")
-		if i >= 0 {
-			prefix = line[:i]
-			reason = strings.TrimSpace(line[i+1:])
-		}
-		if reason == "" {
-			return nil, fmt.Errorf("missing reason in line %q", line)
-		}
-		lines = append(lines, exclusion{prefix, reason})
-	}
-	if s.Err() != nil {
-		return nil, s.Err()
-	}
-	return lines, nil
-}
@@ -0,0 +1,87 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// handleListFeatureReadiness lists the data features and the state of their
+// backfill.
+func (s *Server) handleListFeatureReadiness(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "handleListFeatureReadiness")
+	frs, err := s.db.GetFeatureReadiness(r.Context())
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "FEATURE\tALL MODULES READY\tMODULES READY")
+	for _, fr := range frs {
+		readyAt := "-"
+		if !fr.ReadyAt.IsZero() {
+			readyAt = fr.ReadyAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\n", fr.Feature, readyAt, fr.NumModules)
+	}
+	return tw.Flush()
+}
+
+// handleSetFeatureReady records that the data feature in the "feature" query
+// param has been backfilled for the module in the "module" query param, or
+// for all modules if it is omitted.
+func (s *Server) handleSetFeatureReady(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "handleSetFeatureReady")
+	f, modulePath, err := parseFeatureParams(r)
+	if err != nil {
+		return err
+	}
+	if err := s.db.SetFeatureReady(r.Context(), f, modulePath); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%s is ready for %s", f, modulesDescription(modulePath))
+	return nil
+}
+
+// handleClearFeatureReady removes the record that the data feature in the
+// "feature" query param has been backfilled for the module in the "module"
+// query param, or for all modules if it is omitted.
+func (s *Server) handleClearFeatureReady(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "handleClearFeatureReady")
+	f, modulePath, err := parseFeatureParams(r)
+	if err != nil {
+		return err
+	}
+	if err := s.db.ClearFeatureReady(r.Context(), f, modulePath); err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			return &serverError{http.StatusNotFound, err}
+		}
+		return err
+	}
+	fmt.Fprintf(w, "%s is no longer recorded as ready for %s", f, modulesDescription(modulePath))
+	return nil
+}
+
+func parseFeatureParams(r *http.Request) (internal.DataFeature, string, error) {
+	f, err := internal.ParseDataFeature(r.FormValue("feature"))
+	if err != nil {
+		return "", "", &serverError{http.StatusBadRequest, err}
+	}
+	return f, r.FormValue("module"), nil
+}
+
+func modulesDescription(modulePath string) string {
+	if modulePath == "" {
+		return "all modules"
+	}
+	return modulePath
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"runtime"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,11 +22,14 @@ import (
 	"go.opencensus.io/stats/view"
 	"go.opencensus.io/tag"
 	"go.opencensus.io/trace"
+	"golang.org/x/mod/module"
 	"golang.org/x/mod/semver"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/cache"
+	"golang.org/x/pkgsite/internal/checksum"
 	"golang.org/x/pkgsite/internal/dcensus"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docrender"
 	"golang.org/x/pkgsite/internal/experiment"
 	"golang.org/x/pkgsite/internal/fetch"
 	"golang.org/x/pkgsite/internal/log"
@@ -34,6 +38,7 @@ import (
 	"golang.org/x/pkgsite/internal/proxy"
 	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/stdlib"
+	"golang.org/x/pkgsite/internal/version"
 )
 
 var (
@@ -47,6 +52,11 @@ var (
 		"Latency of a fetch request.",
 		stats.UnitSeconds,
 	)
+	fetchesDeduplicated = stats.Int64(
+		"go-discovery/worker/fetch-deduplicated",
+		"Count of fetches skipped because the same module version was being fetched.",
+		stats.UnitDimensionless,
+	)
 	fetchedPackages = stats.Int64(
 		"go-discovery/worker/fetch-package-count",
 		"Count of successfully fetched packages.",
@@ -85,8 +95,21 @@ var (
 		Aggregation: view.Count(),
 		Description: "Count of shedded fetches",
 	}
+
+	// DeduplicatedFetchCount counts the number of fetches that were skipped
+	// because another fetch of the same module version was in progress.
+	DeduplicatedFetchCount = &view.View{
+		Name:        "go-discovery/worker/fetch-deduplicated",
+		Measure:     fetchesDeduplicated,
+		Aggregation: view.Count(),
+		Description: "Count of deduplicated fetches",
+	}
 )
 
+// defaultFetchClaimTTL is how long a fetch claims its module version if its
+// context has no deadline. See claimFetch.
+const defaultFetchClaimTTL = 15 * time.Minute
+
 // fetchTask represents the result of a fetch task that was processed.
 type fetchTask struct {
 	fetch.FetchResult
@@ -103,6 +126,28 @@ type Fetcher struct {
 	Cache        *cache.Cache
 	loadShedder  *loadShedder
 	Source       string
+
+	// ChecksumDB is the checksum database that module versions are checked
+	// against. If it is nil, they are not checked.
+	ChecksumDB *checksum.DB
+	// RequireVerifiedChecksums makes fetches of module versions whose
+	// checksums don't match ChecksumDB fail.
+	RequireVerifiedChecksums bool
+
+	// ContentScanner checks the files of module versions against the
+	// content policy. If it is nil, they are not checked.
+	ContentScanner *fetch.ContentScanner
+
+	// CommitHosts maps repository hosts to the kind of API they serve, as
+	// for source.FetchCommitInfo. The commits of pseudo-versions of modules
+	// whose repositories are on these hosts are looked up when they are
+	// fetched.
+	CommitHosts map[string]string
+
+	// ReadmeRenderer renders the READMEs of fetched module versions, which
+	// are stored so that the frontend does not have to render them. If it is
+	// nil, they are rendered by the frontend when they are served.
+	ReadmeRenderer docrender.ReadmeRenderer
 }
 
 // FetchAndUpdateState fetches and processes a module version, and then updates
@@ -148,6 +193,19 @@ func (f *Fetcher) FetchAndUpdateState(ctx context.Context, modulePath, requested
 	// the error state in the DB.
 	info, err := getInfo(ctx, modulePath, requestedVersion, f.ProxyClient)
 	if err == nil {
+		// Skip the fetch if the same module version is being fetched, as
+		// when it is enqueued both from the index and by a user.
+		claimed, release, err := f.claimFetch(ctx, modulePath, requestedVersion, info.Version)
+		if err != nil {
+			return derrors.ToStatus(err), "", err
+		}
+		if !claimed {
+			stats.Record(ctx, fetchesDeduplicated.M(1))
+			log.Infof(ctx, "%s@%s is already being fetched; skipping", modulePath, info.Version)
+			return derrors.ToStatus(derrors.DuplicateFetch), info.Version, derrors.DuplicateFetch
+		}
+		defer release()
+
 		// If we're overloaded, shed load by not processing this module.
 		// The zip endpoint requires a resolved version.
 		deferFunc, zipSize, err := f.maybeShed(ctx, modulePath, info.Version)
@@ -189,7 +247,7 @@ func (f *Fetcher) FetchAndUpdateState(ctx context.Context, modulePath, requested
 	if err != nil {
 		return derrors.ToStatus(err), "", err
 	}
-	ft := f.fetchAndInsertModule(ctx, modulePath, requestedVersion, lmv)
+	ft := f.fetchAndInsertModule(ctx, modulePath, requestedVersion, appVersionLabel, lmv)
 	nPackages = int64(len(ft.PackageVersionStates))
 	span.AddAttributes(trace.Int64Attribute("numPackages", nPackages))
 
@@ -258,6 +316,7 @@ func (f *Fetcher) FetchAndUpdateState(ctx context.Context, modulePath, requested
 		GoModPath:            ft.GoModPath,
 		FetchErr:             ft.Error,
 		PackageVersionStates: ft.PackageVersionStates,
+		Retry:                retryPolicy(ft.Status, ft.Error),
 	}
 	err = f.DB.UpdateModuleVersionState(ctx, mvs)
 	ft.timings["db.UpdateModuleVersionState"] = time.Since(startUpdate)
@@ -291,7 +350,7 @@ func getInfo(ctx context.Context, modulePath, requestedVersion string, prox *pro
 // fetchAndInsertModule fetches the given module version from the module proxy
 // or (in the case of the standard library) from the Go repo and writes the
 // resulting data to the database.
-func (f *Fetcher) fetchAndInsertModule(ctx context.Context, modulePath, requestedVersion string, lmv *internal.LatestModuleVersions) *fetchTask {
+func (f *Fetcher) fetchAndInsertModule(ctx context.Context, modulePath, requestedVersion, appVersionLabel string, lmv *internal.LatestModuleVersions) *fetchTask {
 	ft := &fetchTask{
 		FetchResult: fetch.FetchResult{
 			ModulePath:       modulePath,
@@ -313,6 +372,12 @@ func (f *Fetcher) fetchAndInsertModule(ctx context.Context, modulePath, requeste
 	}
 
 	moduleGetter := fetch.NewProxyModuleGetter(f.ProxyClient, f.SourceClient)
+	if f.ChecksumDB != nil {
+		moduleGetter = fetch.NewCheckedProxyModuleGetter(f.ProxyClient, f.SourceClient, f.ChecksumDB, f.RequireVerifiedChecksums)
+	}
+	if f.ContentScanner != nil {
+		moduleGetter = fetch.WithContentScanner(moduleGetter, f.ContentScanner)
+	}
 	if modulePath == "std" {
 		moduleGetter = fetch.NewStdlibZipModuleGetter()
 	}
@@ -367,6 +432,9 @@ func (f *Fetcher) fetchAndInsertModule(ctx context.Context, modulePath, requeste
 	// The module was successfully fetched.
 	log.Debugf(ctx, "fetch.FetchModule succeeded for %s@%s", ft.ModulePath, ft.RequestedVersion)
 
+	f.addCommitInfo(ctx, ft.Module)
+	setDocumentationVersions(ft.Module, appVersionLabel, runtime.Version())
+
 	// Determine the current latest-version information for this module.
 
 	start := time.Now()
@@ -383,6 +451,11 @@ func (f *Fetcher) fetchAndInsertModule(ctx context.Context, modulePath, requeste
 		return ft
 	}
 	log.Debugf(ctx, "db.InsertModule succeeded for %s@%s", ft.ModulePath, ft.RequestedVersion)
+	if f.ReadmeRenderer != nil {
+		start := time.Now()
+		f.storeRenderedReadmes(ctx, ft.Module)
+		ft.timings["worker.storeRenderedReadmes"] = time.Since(start)
+	}
 	// Invalidate the cache if we just processed the latest version of a module.
 	if isLatest {
 		if err := f.invalidateCache(ctx, ft.ModulePath); err != nil {
@@ -398,9 +471,8 @@ func (f *Fetcher) fetchAndInsertModule(ctx context.Context, modulePath, requeste
 }
 
 // invalidateCache deletes the series path for modulePath, as well as any
-// possible URL path of which it is a componentwise prefix. That is, it deletes
-// example.com/mod, example.com/mod@v1.2.3 and example.com/mod/pkg, but not the
-// unrelated example.com/module.
+// possible URL path of which it is a componentwise prefix. See
+// cache.Cache.DeletePath.
 //
 // We delete the series path, not the module path, because adding a v2 module
 // can affect v1 pages. For example, the first v2 module will add a "higher
@@ -412,22 +484,8 @@ func (f *Fetcher) invalidateCache(ctx context.Context, modulePath string) error
 	if f.Cache == nil {
 		return nil
 	}
-	var errs []error
-	seriesPath := internal.SeriesPathForModule(modulePath)
-	// All cache keys are request URLs, so they begin with "/".
-	if err := f.Cache.Delete(ctx, "/"+seriesPath); err != nil {
-		errs = append(errs, err)
-	}
-	// Delete all suffixes of the series path followed by a character that marks its end.
-	for _, end := range "/@?#" {
-		if err := f.Cache.DeletePrefix(ctx, fmt.Sprintf("/%s%c", seriesPath, end)); err != nil {
-			errs = append(errs, err)
-		}
-	}
-	if len(errs) > 0 {
-		return fmt.Errorf("%d errors, first is %w", len(errs), errs[0])
-	}
-	return nil
+	_, err := f.Cache.DeletePath(ctx, internal.SeriesPathForModule(modulePath))
+	return err
 }
 
 func resolvedVersion(ctx context.Context, modulePath, requestedVersion string, getter fetch.ModuleGetter) string {
@@ -552,6 +610,26 @@ func (f *Fetcher) FetchAndUpdateLatest(ctx context.Context, modulePath string) (
 	return f.DB.UpdateLatestModuleVersions(ctx, lmv)
 }
 
+// claimFetch claims the fetch of modulePath at resolvedVersion, so that
+// other fetches of it are skipped until it finishes. It reports whether the
+// claim succeeded, and returns a function that releases it.
+//
+// Only a fetch of a resolved version is claimed and skipped. A fetch of a
+// query such as "latest" must record what the query resolves to in
+// version_map itself, so it always proceeds.
+func (f *Fetcher) claimFetch(ctx context.Context, modulePath, requestedVersion, resolvedVersion string) (bool, func(), error) {
+	if requestedVersion != resolvedVersion {
+		return true, func() {}, nil
+	}
+	// The claim must outlive the fetch, so that it is not taken over while
+	// the fetch is in progress.
+	ttl := defaultFetchClaimTTL
+	if deadline, ok := ctx.Deadline(); ok {
+		ttl = time.Until(deadline) + time.Minute
+	}
+	return f.DB.ClaimFetch(ctx, modulePath, resolvedVersion, ttl)
+}
+
 func (f *Fetcher) maybeShed(ctx context.Context, modulePath, version string) (func(), int64, error) {
 	if f.loadShedder == nil {
 		return func() {}, 0, nil
@@ -583,3 +661,51 @@ func getZipSize(ctx context.Context, modulePath, resolvedVersion string, prox *p
 	}
 	return prox.ZipSize(ctx, modulePath, resolvedVersion)
 }
+
+// setDocumentationVersions records on all the documentation of m the app
+// version of the worker and the Go toolchain that processed it, so that
+// documentation processed by outdated versions can be found later.
+func setDocumentationVersions(m *internal.Module, appVersionLabel, toolchain string) {
+	for _, u := range m.Units {
+		for _, d := range u.Documentation {
+			d.AppVersion = appVersionLabel
+			d.Toolchain = toolchain
+		}
+	}
+}
+
+// storeRenderedReadmes renders the READMEs of m, as they were stored by
+// InsertModule, and stores the renderings. Pages can still be served without
+// them, so failures are logged and otherwise ignored.
+func (f *Fetcher) storeRenderedReadmes(ctx context.Context, m *internal.Module) {
+	rs, err := f.DB.GetModuleReadmesToRender(ctx, m.ModulePath, m.Version)
+	if err == nil {
+		err = renderReadmes(ctx, f.DB, f.ReadmeRenderer, rs)
+	}
+	if err != nil {
+		log.Errorf(ctx, "storeRenderedReadmes(%q, %q): %v", m.ModulePath, m.Version, err)
+	}
+}
+
+// addCommitInfo sets m.Commit to information about the commit that m's
+// version refers to, if it is a pseudo-version and its repository is on one
+// of f.CommitHosts. The information is only used for display, so failures
+// are logged and otherwise ignored.
+func (f *Fetcher) addCommitInfo(ctx context.Context, m *internal.Module) {
+	if len(f.CommitHosts) == 0 || m.SourceInfo == nil || !version.IsPseudo(m.Version) {
+		return
+	}
+	rev, err := module.PseudoVersionRev(m.Version)
+	if err != nil {
+		log.Errorf(ctx, "addCommitInfo: %v", err)
+		return
+	}
+	ci, err := source.FetchCommitInfo(ctx, f.SourceClient, f.CommitHosts, m.SourceInfo.RepoURL(), rev)
+	if err != nil {
+		if !errors.Is(err, derrors.NotFound) {
+			log.Warningf(ctx, "addCommitInfo: %v", err)
+		}
+		return
+	}
+	m.Commit = ci
+}
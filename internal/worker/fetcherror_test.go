@@ -325,7 +325,7 @@ func TestTrimLargeCode(t *testing.T) {
 
 func fetchAndCheckStatus(ctx context.Context, t *testing.T, proxyClient *proxy.Client, modulePath, version string, wantCode int) {
 	t.Helper()
-	f := Fetcher{proxyClient, source.NewClient(http.DefaultClient), testDB, nil, nil, ""}
+	f := Fetcher{proxyClient, source.NewClient(http.DefaultClient), testDB, nil, nil, "", nil, false, nil, nil, nil}
 	code, _, err := f.FetchAndUpdateState(ctx, modulePath, version, testAppVersion)
 	switch code {
 	case http.StatusOK:
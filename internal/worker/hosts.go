@@ -0,0 +1,54 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/postgres"
+)
+
+// handleUpdateHostStats recomputes the per-host statistics shown on the
+// hosts page.
+func (s *Server) handleUpdateHostStats(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "handleUpdateHostStats")
+	n, err := s.db.UpdateHostStats(r.Context())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "updated statistics for %d hosts", n)
+	return nil
+}
+
+// doHostsPage displays the statistics for the hosts with the most modules, as
+// of the last run of handleUpdateHostStats. With the query param
+// "format=json", it writes them as JSON instead.
+func (s *Server) doHostsPage(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "doHostsPage")
+	stats, err := s.db.GetHostStats(r.Context(), parseIntParam(r, "limit", 100))
+	if err != nil {
+		return annotation{err, "error fetching host stats"}
+	}
+	if r.FormValue("format") == "json" {
+		if stats == nil {
+			stats = []*postgres.HostStats{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+	page := struct {
+		Env   string
+		Hosts []*postgres.HostStats
+	}{
+		Env:   env(s.cfg),
+		Hosts: stats,
+	}
+	return renderPage(r.Context(), w, page, s.templates[hostsTemplate])
+}
@@ -33,6 +33,26 @@ var (
 		TagKeys:     []tag.Key{keyEnqueueStatus},
 	}
 
+	// keyEnqueueSkipPrefix and keyEnqueueSkipReason are census tags used to
+	// keep track of module versions that were not enqueued because of a
+	// dynconfig.EnqueueThrottle.
+	keyEnqueueSkipPrefix = tag.MustNewKey("enqueue.skip_prefix")
+	keyEnqueueSkipReason = tag.MustNewKey("enqueue.skip_reason")
+	enqueueSkipped       = stats.Int64(
+		"go-discovery/worker_enqueue_skipped_count",
+		"A module version skipped at enqueue time because of a throttle or deny entry.",
+		stats.UnitDimensionless,
+	)
+	// EnqueueSkippedCount counts module versions skipped at enqueue time, by
+	// prefix and reason.
+	EnqueueSkippedCount = &view.View{
+		Name:        "go-discovery/worker-enqueue-skipped/count",
+		Measure:     enqueueSkipped,
+		Aggregation: view.Count(),
+		Description: "Worker enqueue skipped count",
+		TagKeys:     []tag.Key{keyEnqueueSkipPrefix, keyEnqueueSkipReason},
+	}
+
 	processingLag = stats.Int64(
 		"go-discovery/worker_processing_lag",
 		"Time from appearing in the index to being processed.",
@@ -96,6 +116,64 @@ var (
 		Aggregation: view.LastValue(),
 		Description: "number of waiting DB worker processes",
 	}
+
+	importedByChanged = stats.Int64(
+		"go-discovery/worker_imported_by_changed_count",
+		"Number of packages whose imported-by counts were recomputed incrementally.",
+		stats.UnitDimensionless,
+	)
+
+	ImportedByChanged = &view.View{
+		Name:        "go-discovery/worker_imported_by_changed/count",
+		Measure:     importedByChanged,
+		Aggregation: view.Sum(),
+		Description: "number of packages whose imported-by counts were recomputed incrementally",
+	}
+
+	importedByUpdated = stats.Int64(
+		"go-discovery/worker_imported_by_updated_count",
+		"Number of imported-by counts changed by an incremental update.",
+		stats.UnitDimensionless,
+	)
+
+	ImportedByUpdated = &view.View{
+		Name:        "go-discovery/worker_imported_by_updated/count",
+		Measure:     importedByUpdated,
+		Aggregation: view.Sum(),
+		Description: "number of imported-by counts changed incrementally",
+	}
+
+	importedByPending = stats.Int64(
+		"go-discovery/worker_imported_by_pending_count",
+		"Number of recorded import changes not yet reflected in imported-by counts.",
+		stats.UnitDimensionless,
+	)
+
+	ImportedByPending = &view.View{
+		Name:        "go-discovery/worker_imported_by_pending/count",
+		Measure:     importedByPending,
+		Aggregation: view.LastValue(),
+		Description: "number of import changes waiting for an imported-by count update",
+	}
+
+	// keyVerifyEncodingResult is a census tag for the results of verifying
+	// the encoding of packages.
+	keyVerifyEncodingResult = tag.MustNewKey("verify-encoding.result")
+	verifiedEncodings       = stats.Int64(
+		"go-discovery/worker/verify-encoding-count",
+		"A package whose documentation encoding was verified.",
+		stats.UnitDimensionless,
+	)
+
+	// VerifyEncodingCount counts the packages whose documentation encoding
+	// was verified, by result.
+	VerifyEncodingCount = &view.View{
+		Name:        "go-discovery/worker/verify-encoding-count",
+		Measure:     verifiedEncodings,
+		Aggregation: view.Count(),
+		Description: "Count of packages whose documentation encoding was verified, by result",
+		TagKeys:     []tag.Key{keyVerifyEncodingResult},
+	}
 )
 
 func recordEnqueue(ctx context.Context, status int) {
@@ -104,6 +182,15 @@ func recordEnqueue(ctx context.Context, status int) {
 		enqueueStatus.M(int64(status)))
 }
 
+func recordEnqueueSkipped(ctx context.Context, prefix, reason string) {
+	stats.RecordWithTags(ctx,
+		[]tag.Mutator{
+			tag.Upsert(keyEnqueueSkipPrefix, prefix),
+			tag.Upsert(keyEnqueueSkipReason, reason),
+		},
+		enqueueSkipped.M(1))
+}
+
 func recordProcessingLag(ctx context.Context, d time.Duration) {
 	stats.Record(ctx, processingLag.M(d.Milliseconds()/1000))
 }
@@ -119,3 +206,18 @@ func recordWorkerDBInfo(ctx context.Context, dbi *postgres.UserInfo) {
 		stats.Record(ctx, dbWaitingProcesses.M(int64(dbi.NumWaiting)))
 	}
 }
+
+func recordImportedByUpdate(ctx context.Context, u *postgres.ImportedByUpdate) {
+	stats.Record(ctx,
+		importedByChanged.M(int64(u.NumChanged)),
+		importedByUpdated.M(u.NumUpdated),
+		importedByPending.M(int64(u.NumPending)))
+}
+
+// RecordVerifyEncoding records the result of verifying the encoding of a
+// package. See fetch.SetVerifyEncodingRecorder.
+func RecordVerifyEncoding(ctx context.Context, result string) {
+	stats.RecordWithTags(ctx,
+		[]tag.Mutator{tag.Upsert(keyVerifyEncodingResult, result)},
+		verifiedEncodings.M(1))
+}
@@ -0,0 +1,152 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/queue"
+	"golang.org/x/sync/errgroup"
+)
+
+// queueStatus is the information displayed by the queue page.
+type queueStatus struct {
+	Env string `json:"-"`
+	// Since is the start of the window for failures and retries.
+	Since time.Time `json:"since"`
+	// Queue is nil if the queue can't report statistics, or failed to.
+	Queue      *queue.Stats      `json:"queue"`
+	QueueError string            `json:"queueError,omitempty"`
+	InFlight   []*inFlightFetch  `json:"inFlight"`
+	Failures   []*failureClass   `json:"failures"`
+	Retried    []*retriedVersion `json:"retried"`
+}
+
+// inFlightFetch is a fetch in progress on this worker instance.
+type inFlightFetch struct {
+	ModulePath string        `json:"modulePath"`
+	Version    string        `json:"version"`
+	Start      time.Time     `json:"start"`
+	Duration   time.Duration `json:"durationNanos"`
+}
+
+// failureClass is a postgres.FailureClass with a description of its status.
+type failureClass struct {
+	*postgres.FailureClass
+	Desc string `json:"desc"`
+}
+
+// retriedVersion is a module version that has been tried more than once,
+// from the module_version_states table.
+type retriedVersion struct {
+	ModulePath      string     `json:"modulePath"`
+	Version         string     `json:"version"`
+	TryCount        int        `json:"tryCount"`
+	Status          int        `json:"status"`
+	LastProcessedAt *time.Time `json:"lastProcessedAt"`
+	// RetryClass, RetryAttempts and MaxRetryAttempts are as in
+	// internal.ModuleVersionState.
+	RetryClass       string `json:"retryClass,omitempty"`
+	RetryAttempts    int    `json:"retryAttempts"`
+	MaxRetryAttempts int    `json:"maxRetryAttempts,omitempty"`
+}
+
+// doQueuePage displays the state of the fetch queue: its depth, the fetches
+// in flight on this instance, and the failures and retries recorded in the
+// last "hours" hours (default 24). With the query param "format=json", it
+// writes them as JSON instead.
+func (s *Server) doQueuePage(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "doQueuePage")
+	ctx := r.Context()
+	status := &queueStatus{
+		Env:      env(s.cfg),
+		Since:    time.Now().Add(-time.Duration(parseIntParam(r, "hours", 24)) * time.Hour),
+		InFlight: inFlightFetches(time.Now()),
+		Failures: []*failureClass{},
+		Retried:  []*retriedVersion{},
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	if sr, ok := s.queue.(queue.StatsReporter); ok {
+		g.Go(func() error {
+			// Don't fail the page if the queue is unavailable.
+			qs, err := sr.Stats(gctx)
+			if err != nil {
+				log.Errorf(ctx, "queue stats: %v", err)
+				status.QueueError = err.Error()
+				return nil
+			}
+			status.Queue = qs
+			return nil
+		})
+	}
+	g.Go(func() error {
+		classes, err := s.db.GetRecentFailureClasses(gctx, status.Since)
+		if err != nil {
+			return annotation{err, "error fetching failures"}
+		}
+		for _, c := range classes {
+			fc := &failureClass{FailureClass: c}
+			if e := derrors.FromStatus(c.Status, ""); e != nil && e != derrors.Unknown {
+				fc.Desc = e.Error()
+			}
+			status.Failures = append(status.Failures, fc)
+		}
+		return nil
+	})
+	g.Go(func() error {
+		versions, err := s.db.GetMostRetriedVersions(gctx, status.Since, parseIntParam(r, "limit", 50))
+		if err != nil {
+			return annotation{err, "error fetching retried versions"}
+		}
+		for _, v := range versions {
+			status.Retried = append(status.Retried, &retriedVersion{
+				ModulePath:       v.ModulePath,
+				Version:          v.Version,
+				TryCount:         v.TryCount,
+				Status:           v.Status,
+				LastProcessedAt:  v.LastProcessedAt,
+				RetryClass:       v.RetryClass,
+				RetryAttempts:    v.RetryAttempts,
+				MaxRetryAttempts: v.MaxRetryAttempts,
+			})
+		}
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	if r.FormValue("format") == "json" {
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(status)
+	}
+	return renderPage(ctx, w, status, s.templates[queueTemplate])
+}
+
+// inFlightFetches returns the fetches in progress on this instance, oldest
+// first, with their durations as of now.
+func inFlightFetches(now time.Time) []*inFlightFetch {
+	fetches := []*inFlightFetch{}
+	for _, fi := range FetchInfos() {
+		if fi.Status != 0 {
+			continue
+		}
+		fetches = append(fetches, &inFlightFetch{
+			ModulePath: fi.ModulePath,
+			Version:    fi.Version,
+			Start:      fi.Start,
+			Duration:   now.Sub(fi.Start).Round(time.Millisecond),
+		})
+	}
+	return fetches
+}
@@ -0,0 +1,65 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"encoding/json"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docrender"
+	"golang.org/x/pkgsite/internal/postgres"
+)
+
+// renderReadmes renders rs, READMEs and docs pages, with rd and stores the
+// renderings under their hashes, where the frontend looks for them before
+// rendering a README itself.
+func renderReadmes(ctx context.Context, db *postgres.DB, rd docrender.ReadmeRenderer, rs []*postgres.ReadmeToRender) (err error) {
+	defer derrors.Wrap(&err, "renderReadmes(%d READMEs)", len(rs))
+
+	var rendered []*postgres.RenderedReadme
+	seen := map[string]bool{}
+	for _, r := range rs {
+		hash, render, err := readmeRequest(rd, r)
+		if err != nil {
+			return err
+		}
+		if seen[hash] {
+			continue
+		}
+		seen[hash] = true
+		resp, err := render(ctx)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(resp)
+		if err != nil {
+			return err
+		}
+		rendered = append(rendered, &postgres.RenderedReadme{Hash: hash, ModuleID: r.ModuleID, Rendered: data})
+	}
+	return db.InsertRenderedReadmes(ctx, rendered)
+}
+
+// readmeRequest returns the hash of the request to render r, as a docs page if
+// it is one, and a function that renders it with rd.
+func readmeRequest(rd docrender.ReadmeRenderer, r *postgres.ReadmeToRender) (hash string, render func(context.Context) (*docrender.ReadmeResponse, error), err error) {
+	req := &docrender.ReadmeRequest{
+		Readme:       r.Readme,
+		SourceInfo:   r.SourceInfo,
+		NoIssueLinks: r.NoIssueLinks,
+	}
+	if r.DocsPages == nil {
+		hash, err = docrender.ReadmeHash(req)
+		return hash, func(ctx context.Context) (*docrender.ReadmeResponse, error) {
+			return rd.RenderReadme(ctx, req)
+		}, err
+	}
+	preq := &docrender.DocsPageRequest{ReadmeRequest: *req, Pages: r.DocsPages}
+	hash, err = docrender.DocsPageHash(preq)
+	return hash, func(ctx context.Context) (*docrender.ReadmeResponse, error) {
+		return rd.RenderDocsPage(ctx, preq)
+	}, err
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/docrender"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+// fakeReadmeRenderer renders a README or docs page as its contents.
+type fakeReadmeRenderer struct{}
+
+func (fakeReadmeRenderer) RenderReadme(ctx context.Context, req *docrender.ReadmeRequest) (*docrender.ReadmeResponse, error) {
+	return &docrender.ReadmeResponse{HTML: req.Readme.Contents}, nil
+}
+
+func (fakeReadmeRenderer) RenderDocsPage(ctx context.Context, req *docrender.DocsPageRequest) (*docrender.ReadmeResponse, error) {
+	return &docrender.ReadmeResponse{HTML: req.Readme.Contents}, nil
+}
+
+func TestRenderReadmes(t *testing.T) {
+	ctx := context.Background()
+	defer postgres.ResetTestDB(testDB, t)
+
+	m := sample.Module("mod.com", "v1.2.3", "", "A")
+	m.Units[0].Readme = &internal.Readme{Filepath: "README.md", Contents: "module"}
+	m.Units[1].Readme = &internal.Readme{Filepath: "A/README.md", Contents: "A"}
+	page := &internal.Readme{Filepath: "docs/guide.md", Contents: "guide"}
+	m.Units[0].DocsPages = []*internal.Readme{page}
+	postgres.MustInsertModule(ctx, t, testDB, m)
+
+	rs, _, err := testDB.GetReadmesToRender(ctx, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := renderReadmes(ctx, testDB, fakeReadmeRenderer{}, rs); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range m.Units {
+		hash, err := docrender.ReadmeHash(&docrender.ReadmeRequest{
+			Readme:     u.Readme,
+			SourceInfo: m.SourceInfo,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := testDB.GetRenderedReadme(ctx, hash)
+		if err != nil {
+			t.Fatalf("%s: %v", u.Path, err)
+		}
+		var got docrender.ReadmeResponse
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.HTML != u.Readme.Contents {
+			t.Errorf("%s: got HTML %q, want %q", u.Path, got.HTML, u.Readme.Contents)
+		}
+	}
+
+	hash, err := docrender.DocsPageHash(&docrender.DocsPageRequest{
+		ReadmeRequest: docrender.ReadmeRequest{
+			Readme:     page,
+			SourceInfo: m.SourceInfo,
+		},
+		Pages: docrender.DocsPageURLs(m.Units[0].Path, m.ModulePath, []string{page.Filepath}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testDB.GetRenderedReadme(ctx, hash); err != nil {
+		t.Errorf("docs page: %v", err)
+	}
+}
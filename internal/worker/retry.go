@@ -0,0 +1,65 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/postgres"
+)
+
+// Classes of fetch results, which decide how failed fetches are retried.
+const (
+	// retryPermanent is for successes, and for errors that another fetch
+	// would repeat, such as an invalid or too large module.
+	retryPermanent = "permanent"
+	// retryProxy is for errors from the proxy, which usually go away once
+	// it or the origin of the module recovers.
+	retryProxy = "proxy"
+	// retryTimeout is for fetches that ran out of time, which may happen
+	// again for modules that are slow to fetch or process.
+	retryTimeout = "timeout"
+	// retryLoad is for fetches that were refused because the worker was
+	// overloaded, which says nothing about the module.
+	retryLoad = "load"
+	// retryInternal is for other errors, such as database failures and bugs.
+	retryInternal = "internal"
+)
+
+// retryPolicies are the retry policies of the classes of fetch results.
+var retryPolicies = map[string]*postgres.RetryPolicy{
+	retryPermanent: {Class: retryPermanent},
+	retryProxy:     {Class: retryProxy, Delay: 5 * time.Minute, MaxDelay: 6 * time.Hour, MaxAttempts: 12},
+	retryTimeout:   {Class: retryTimeout, Delay: 15 * time.Minute, MaxDelay: 24 * time.Hour, MaxAttempts: 6},
+	retryLoad:      {Class: retryLoad, Delay: time.Minute, MaxDelay: time.Hour},
+	retryInternal:  {Class: retryInternal, Delay: 10 * time.Minute, MaxDelay: 24 * time.Hour, MaxAttempts: 10},
+}
+
+// retryPolicy returns the retry policy for a fetch that ended with status
+// and err.
+func retryPolicy(status int, err error) *postgres.RetryPolicy {
+	return retryPolicies[retryClass(status, err)]
+}
+
+// retryClass classifies the result of a fetch that ended with status and err.
+func retryClass(status int, err error) string {
+	switch {
+	case status < http.StatusInternalServerError:
+		return retryPermanent
+	case status == derrors.ToStatus(derrors.SheddingLoad):
+		return retryLoad
+	case status == derrors.ToStatus(derrors.ProxyTimedOut),
+		errors.Is(err, context.DeadlineExceeded):
+		return retryTimeout
+	case status == derrors.ToStatus(derrors.ProxyError):
+		return retryProxy
+	default:
+		return retryInternal
+	}
+}
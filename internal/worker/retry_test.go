@@ -0,0 +1,55 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+func TestRetryClass(t *testing.T) {
+	for _, test := range []struct {
+		err  error
+		want string
+	}{
+		{nil, retryPermanent},
+		{derrors.NotFound, retryPermanent},
+		{derrors.BadModule, retryPermanent},
+		{derrors.ModuleTooLarge, retryPermanent},
+		{derrors.HasIncompletePackages, retryPermanent},
+		{derrors.SheddingLoad, retryLoad},
+		{fmt.Errorf("zip: %w", derrors.ProxyTimedOut), retryTimeout},
+		{fmt.Errorf("insert: %w", context.DeadlineExceeded), retryTimeout},
+		{derrors.ProxyError, retryProxy},
+		{errors.New("database is down"), retryInternal},
+	} {
+		status := derrors.ToStatus(test.err)
+		if got := retryClass(status, test.err); got != test.want {
+			t.Errorf("retryClass(%d, %v) = %q, want %q", status, test.err, got, test.want)
+		}
+	}
+}
+
+func TestRetryPolicies(t *testing.T) {
+	for class, p := range retryPolicies {
+		if p.Class != class {
+			t.Errorf("%s: policy has class %q", class, p.Class)
+		}
+		if (p.Delay == 0) != (class == retryPermanent) {
+			t.Errorf("%s: delay %s; only the permanent class should have none", class, p.Delay)
+		}
+		if p.MaxDelay < p.Delay {
+			t.Errorf("%s: max delay %s is less than delay %s", class, p.MaxDelay, p.Delay)
+		}
+	}
+	if p := retryPolicy(http.StatusOK, nil); p.Class != retryPermanent {
+		t.Errorf("retryPolicy(200, nil) has class %q, want %q", p.Class, retryPermanent)
+	}
+}
@@ -27,13 +27,18 @@ import (
 	"golang.org/x/mod/semver"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/cache"
+	"golang.org/x/pkgsite/internal/checksum"
 	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/config/dynconfig"
 	"golang.org/x/pkgsite/internal/config/serverconfig"
 	"golang.org/x/pkgsite/internal/dcensus"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/docrender"
+	"golang.org/x/pkgsite/internal/fetch"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
 	"golang.org/x/pkgsite/internal/index"
 	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/memory"
 	"golang.org/x/pkgsite/internal/middleware"
 	"golang.org/x/pkgsite/internal/poller"
 	"golang.org/x/pkgsite/internal/postgres"
@@ -50,6 +55,9 @@ type Server struct {
 	indexClient    *index.Client
 	proxyClient    *proxy.Client
 	sourceClient   *source.Client
+	checksumDB     *checksum.DB
+	contentScanner *fetch.ContentScanner
+	readmeRenderer docrender.ReadmeRenderer
 	cache          *cache.Cache
 	betaCache      *cache.Cache
 	db             *postgres.DB
@@ -60,33 +68,53 @@ type Server struct {
 	getExperiments func() []*internal.Experiment
 	workerDBInfo   func() *postgres.UserInfo
 	loadShedder    *loadShedder
+	allocs         *memory.AllocRecorder
+
+	getEnqueueThrottles func() []*dynconfig.EnqueueThrottle
 }
 
 // ServerConfig contains everything needed by a Server.
 type ServerConfig struct {
-	DB                   *postgres.DB
-	IndexClient          *index.Client
-	ProxyClient          *proxy.Client
-	SourceClient         *source.Client
+	DB           *postgres.DB
+	IndexClient  *index.Client
+	ProxyClient  *proxy.Client
+	SourceClient *source.Client
+	ChecksumDB   *checksum.DB // if nil, fetched modules are not checked
+	// ContentScanner, if non-nil, checks the files of fetched module
+	// versions against the content policy.
+	ContentScanner *fetch.ContentScanner
+	// ReadmeRenderer, if non-nil, renders the READMEs of fetched module
+	// versions, so that the frontend can serve them without rendering.
+	ReadmeRenderer       docrender.ReadmeRenderer
 	RedisCacheClient     *redis.Client
 	RedisBetaCacheClient *redis.Client
 	Queue                queue.Queue
 	Reporter             derrors.Reporter
 	StaticPath           template.TrustedSource
 	GetExperiments       func() []*internal.Experiment
+	// GetEnqueueThrottles returns the current throttles to apply when
+	// enqueuing module versions. It may be nil.
+	GetEnqueueThrottles func() []*dynconfig.EnqueueThrottle
+	// Allocs, if non-nil, holds the heap allocations of requests, which are
+	// shown on the allocs debug page.
+	Allocs *memory.AllocRecorder
 }
 
 const (
-	indexTemplate    = "index.tmpl"
-	versionsTemplate = "versions.tmpl"
-	excludedTemplate = "excluded.tmpl"
+	indexTemplate       = "index.tmpl"
+	versionsTemplate    = "versions.tmpl"
+	excludedTemplate    = "excluded.tmpl"
+	hostsTemplate       = "hosts.tmpl"
+	queueTemplate       = "queue.tmpl"
+	allocsTemplate      = "allocs.tmpl"
+	docVersionsTemplate = "docversions.tmpl"
 )
 
 // NewServer creates a new Server with the given dependencies.
 func NewServer(cfg *config.Config, scfg ServerConfig) (_ *Server, err error) {
 	defer derrors.Wrap(&err, "NewServer(db, %+v)", scfg)
 	templates := map[string]*template.Template{}
-	for _, templateName := range []string{indexTemplate, versionsTemplate, excludedTemplate} {
+	for _, templateName := range []string{indexTemplate, versionsTemplate, excludedTemplate, hostsTemplate, queueTemplate, allocsTemplate, docVersionsTemplate} {
 		t, err := parseTemplate(cfg, scfg.StaticPath, templateName)
 		if err != nil {
 			return nil, err
@@ -117,6 +145,9 @@ func NewServer(cfg *config.Config, scfg ServerConfig) (_ *Server, err error) {
 		indexClient:    scfg.IndexClient,
 		proxyClient:    scfg.ProxyClient,
 		sourceClient:   scfg.SourceClient,
+		checksumDB:     scfg.ChecksumDB,
+		contentScanner: scfg.ContentScanner,
+		readmeRenderer: scfg.ReadmeRenderer,
 		cache:          c,
 		betaCache:      bc,
 		queue:          scfg.Queue,
@@ -125,6 +156,9 @@ func NewServer(cfg *config.Config, scfg ServerConfig) (_ *Server, err error) {
 		staticPath:     scfg.StaticPath,
 		getExperiments: scfg.GetExperiments,
 		workerDBInfo:   func() *postgres.UserInfo { return p.Current().(*postgres.UserInfo) },
+		allocs:         scfg.Allocs,
+
+		getEnqueueThrottles: scfg.GetEnqueueThrottles,
 	}
 	s.setLoadShedder(context.Background())
 	return s, nil
@@ -164,6 +198,39 @@ func (s *Server) Install(handle func(string, http.Handler)) {
 	// This endpoint is intended to be invoked periodically by a scheduler.
 	handle("/update-imported-by-count", rmw(s.errorHandler(s.handleUpdateImportedByCount)))
 
+	// scheduled: update-imported-by-count-incremental updates the
+	// imported_by_count only for packages whose importers changed since the
+	// last run, as recorded in the imported_by_changes table. It is much
+	// cheaper than update-imported-by-count, so it can run more often.
+	// update-imported-by-count removes the changes it has counted, so the
+	// table does not grow if this endpoint is not scheduled.
+	// This endpoint is intended to be invoked periodically by a scheduler.
+	handle("/update-imported-by-count-incremental", rmw(s.errorHandler(s.handleUpdateImportedByCountIncremental)))
+
+	// scheduled: record-imported-by-history records the imported_by_count of
+	// every package for the current week, for the trend shown on unit pages.
+	// Running it again in the same week replaces the week's counts.
+	// This endpoint is intended to be invoked weekly by a scheduler.
+	handle("/record-imported-by-history", rmw(s.errorHandler(s.handleRecordImportedByHistory)))
+
+	// scheduled: update-repo-stats fetches statistics such as stars and open
+	// issues for the repositories of the latest module versions, if they
+	// have never been fetched or are older than config.RepoStatsTTL.
+	// This endpoint is intended to be invoked periodically by a scheduler.
+	handle("/update-repo-stats", rmw(s.errorHandler(s.handleUpdateRepoStats)))
+
+	// scheduled: update-dup-groups groups near-duplicate packages in
+	// search_documents, such as forks and vendored copies, so that search
+	// can collapse them. It processes packages whose group is unknown.
+	// This endpoint is intended to be invoked periodically by a scheduler.
+	handle("/update-dup-groups", rmw(s.errorHandler(s.handleUpdateDuplicateGroups)))
+
+	// scheduled: update-host-stats recomputes statistics about the modules
+	// of each hosting domain, such as their number and the share of fetch
+	// failures, for the hosts debug page.
+	// This endpoint is intended to be invoked periodically by a scheduler.
+	handle("/update-host-stats", rmw(s.errorHandler(s.handleUpdateHostStats)))
+
 	// task-queue: fetch fetches a module version from the Module Mirror, and
 	// processes the contents, and inserts it into the database. If a fetch
 	// request fails for any reason other than an http.StatusInternalServerError,
@@ -214,11 +281,66 @@ func (s *Server) Install(handle func(string, http.Handler)) {
 	// see the comments on duplicate tasks for "/requeue", above.
 	handle("/populate-stdlib", rmw(s.errorHandler(s.handlePopulateStdLib)))
 
-	// manual: populate-search-documents repopulates every row in the
-	// search_documents table that was last updated before the time in the
-	// "before" query parameter.
+	// manual: repopulate-search-documents repopulates the rows in the
+	// search_documents table that were last updated before the time in the
+	// "before" query parameter. It handles at most "limit" of them whose unit
+	// IDs are greater than the "after" query parameter, and reports the value
+	// of "after" to use for the next batch.
 	handle("/repopulate-search-documents", rmw(s.errorHandler(s.handleRepopulateSearchDocuments)))
 
+	// scheduled: update-search-synonyms rebuilds the search documents
+	// affected by changes to the search synonyms in the dynamic config,
+	// reading "limit" of them at a time. It does nothing if the synonyms have
+	// not changed since the last run.
+	handle("/update-search-synonyms", rmw(s.errorHandler(s.handleUpdateSearchSynonyms)))
+
+	// manual: reprocess-readmes re-runs README processing on stored READMEs,
+	// without refetching modules, to roll out changes to how READMEs are
+	// processed. It handles at most "limit" READMEs whose unit IDs are greater
+	// than the "after" query parameter, and reports the value of "after" to
+	// use for the next batch.
+	handle("/reprocess-readmes", rmw(s.errorHandler(s.handleReprocessReadmes)))
+
+	// manual: render-readmes renders stored READMEs and stores the
+	// renderings, which the frontend serves instead of rendering READMEs
+	// itself, to backfill READMEs of module versions processed before
+	// renderings were stored, or after ReadmeRenderVersion changed. It
+	// handles the READMEs of at most "limit" units whose IDs are greater than
+	// the "after" query parameter, and reports the value of "after" to use
+	// for the next batch.
+	handle("/render-readmes", rmw(s.errorHandler(s.handleRenderReadmes)))
+
+	// manual: backfill-symbol-name-tokens sets the words of the symbol names
+	// of symbol search documents that have none, because their module
+	// versions were processed before migration 000181 added them. It handles
+	// at most "limit" documents whose IDs are greater than the "after" query
+	// parameter, and reports the value of "after" to use for the next batch.
+	handle("/backfill-symbol-name-tokens", rmw(s.errorHandler(s.handleBackfillSymbolNameTokens)))
+
+	// scheduled: refresh-popular-searches computes the results of the
+	// "limit" most requested pages of search results in the last week, which
+	// frontends that cache popular searches serve in place of running the
+	// search. Results more than a day old are not served.
+	// This endpoint is intended to be invoked hourly by a scheduler.
+	handle("/refresh-popular-searches", rmw(s.errorHandler(s.handleRefreshPopularSearches)))
+
+	// scheduled: refresh-autocomplete replaces the packages and symbols that
+	// the frontend's /autocomplete suggests with the "limit" most imported
+	// packages and the most imported symbols. Until it first runs, nothing
+	// is suggested.
+	// This endpoint is intended to be invoked daily by a scheduler.
+	handle("/refresh-autocomplete", rmw(s.errorHandler(s.handleRefreshAutocomplete)))
+
+	// manual: check-consistency looks for inconsistencies of the "kind"
+	// query parameter among stored rows that the database schema does not
+	// prevent, such as units outside their module or search documents for
+	// deleted units. It checks at most "limit" rows whose IDs are greater
+	// than the "after" query parameter, and reports the value of "after" to
+	// use for the next batch. With "repair=true", it also repairs each
+	// inconsistency, by deleting rows or marking module versions to be
+	// reprocessed.
+	handle("/check-consistency", rmw(s.errorHandler(s.handleCheckConsistency)))
+
 	// manual: populate-excluded-prefixes inserts all excluded prefixes from
 	// the file private/config/excluded.txt into the databse.
 	handle("/populate-excluded-prefixes", rmw(s.errorHandler(s.handlePopulateExcludedPrefixes)))
@@ -239,6 +361,33 @@ func (s *Server) Install(handle func(string, http.Handler)) {
 	// manual: cancel an active request
 	handle("/cancel", rmw(s.errorHandler(s.handleCancel)))
 
+	// manual: api-keys lists the API keys issued for the JSON endpoints of the
+	// frontend. api-keys/create issues a key to the client in the "name" query
+	// param, with an optional "qps" quota, and displays it once.
+	// api-keys/revoke revokes the key with the given "id".
+	handle("/api-keys", rmw(s.errorHandler(s.handleListAPIKeys)))
+	handle("/api-keys/create", rmw(s.errorHandler(s.handleCreateAPIKey)))
+	handle("/api-keys/revoke", rmw(s.errorHandler(s.handleRevokeAPIKey)))
+
+	// manual: feature-readiness lists the data features and the state of
+	// their backfill. feature-readiness/set records that the "feature" query
+	// param has been backfilled for the "module" query param, or for all
+	// modules if it is omitted, and feature-readiness/clear removes that
+	// record. The frontend hides features that are not ready for a module.
+	handle("/feature-readiness", rmw(s.errorHandler(s.handleListFeatureReadiness)))
+	handle("/feature-readiness/set", rmw(s.errorHandler(s.handleSetFeatureReady)))
+	handle("/feature-readiness/clear", rmw(s.errorHandler(s.handleClearFeatureReady)))
+
+	// manual: takedowns lists the modules that have been taken down, usually
+	// because of a legal request, including lifted takedowns.
+	// takedowns/create takes down the "module" query param at the "version"
+	// query param, or at all versions, recording the "reason" query param and
+	// showing the optional "message" query param in place of its pages.
+	// takedowns/lift lifts the takedown with the given "id".
+	handle("/takedowns", rmw(s.errorHandler(s.handleListTakedowns)))
+	handle("/takedowns/create", rmw(s.errorHandler(s.handleCreateTakedown)))
+	handle("/takedowns/lift", rmw(s.errorHandler(s.handleLiftTakedown)))
+
 	handle("/static/", http.StripPrefix("/static/", http.FileServer(http.Dir(s.staticPath.String()))))
 
 	// Health check.
@@ -268,6 +417,23 @@ func (s *Server) DebugHandler() (http.Handler, error) {
 	// Serve a list of excluded prefixes and module versions.
 	mux.Handle("/excluded", http.HandlerFunc(s.handleHTMLPage(s.doExcludedPage)))
 
+	// Serve statistics about the modules of each hosting domain, as HTML or,
+	// with "format=json", as JSON.
+	mux.Handle("/hosts", http.HandlerFunc(s.handleHTMLPage(s.doHostsPage)))
+
+	// Serve the depth of the fetch queue, the fetches in flight, and recent
+	// failures and retries, as HTML or, with "format=json", as JSON.
+	mux.Handle("/queue", http.HandlerFunc(s.handleHTMLPage(s.doQueuePage)))
+
+	// Serve the heap allocations of requests by route, and the requests that
+	// allocated the most, as HTML or, with "format=json", as JSON.
+	mux.Handle("/allocs", http.HandlerFunc(s.handleHTMLPage(s.doAllocsPage)))
+
+	// Serve the number of documentation rows processed by each app version
+	// and toolchain, and the module versions whose documentation was
+	// processed by outdated ones, as HTML or, with "format=json", as JSON.
+	mux.Handle("/docversions", http.HandlerFunc(s.handleHTMLPage(s.doDocVersionsPage)))
+
 	return mux, nil
 }
 
@@ -278,14 +444,95 @@ func (s *Server) handleUpdateImportedByCount(w http.ResponseWriter, r *http.Requ
 	if err != nil {
 		return err
 	}
+	// Every package now has a count, so the scores are ready.
+	if err := s.db.SetFeatureReady(r.Context(), internal.FeatureScores, ""); err != nil {
+		return err
+	}
 	fmt.Fprintf(w, "updated %d packages", n)
 	return nil
 }
 
-// handleRepopulateSearchDocuments repopulates every row in the search_documents table
-// that was last updated before the given time.
-func (s *Server) handleRepopulateSearchDocuments(w http.ResponseWriter, r *http.Request) error {
+// handleUpdateImportedByCountIncremental updates imported_by_count for
+// packages whose importers changed, processing up to the "limit" query param
+// of recorded changes in batches of the "batch" query param.
+func (s *Server) handleUpdateImportedByCountIncremental(w http.ResponseWriter, r *http.Request) error {
+	batchSize := parseIntParam(r, "batch", 1000)
+	limit := parseIntParam(r, "limit", 100000)
+	u, err := s.db.UpdateImportedByCountsIncrementally(r.Context(), batchSize, limit)
+	if err != nil {
+		return err
+	}
+	recordImportedByUpdate(r.Context(), u)
+	fmt.Fprintf(w, "recomputed %d packages, updated %d, %d changes pending", u.NumChanged, u.NumUpdated, u.NumPending)
+	return nil
+}
+
+// handleRecordImportedByHistory records the imported_by_count of every
+// package for the current week.
+func (s *Server) handleRecordImportedByHistory(w http.ResponseWriter, r *http.Request) error {
+	now := time.Now()
+	n, err := s.db.RecordImportedByHistory(r.Context(), now)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "recorded %d imported-by counts for the week of %s", n, now.Format(time.DateOnly))
+	return nil
+}
+
+// handleUpdateDuplicateGroups computes duplicate groups for packages with up
+// to the "limit" query param of package names.
+func (s *Server) handleUpdateDuplicateGroups(w http.ResponseWriter, r *http.Request) error {
+	limit := parseIntParam(r, "limit", 100)
+	n, err := s.db.UpdateDuplicateGroups(r.Context(), limit)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "updated duplicate groups for %d package names", n)
+	return nil
+}
+
+// handleUpdateRepoStats fetches repository statistics for up to the "limit"
+// query param of repositories whose statistics are missing or stale.
+func (s *Server) handleUpdateRepoStats(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "handleUpdateRepoStats")
+	ctx := r.Context()
 	limit := parseIntParam(r, "limit", 100)
+	var hosts []string
+	for h := range s.cfg.RepoStatsHosts {
+		hosts = append(hosts, h)
+	}
+	repoURLs, err := s.db.GetRepoURLsForStatsUpdate(ctx, hosts, time.Now().Add(-config.RepoStatsTTL), limit)
+	if err != nil {
+		return err
+	}
+	var nUpdated, nNotFound, nErrors int
+	for _, u := range repoURLs {
+		rs, err := source.FetchRepoStats(ctx, s.sourceClient, s.cfg.RepoStatsHosts, u)
+		switch {
+		case errors.Is(err, derrors.NotFound):
+			if err := s.db.UpsertRepoStatsNotFound(ctx, u); err != nil {
+				return err
+			}
+			nNotFound++
+		case err != nil:
+			// Most likely rate limiting or a transient failure at the host.
+			// Try again on the next run.
+			log.Warningf(ctx, "%v", err)
+			nErrors++
+		default:
+			if err := s.db.UpsertRepoStats(ctx, rs); err != nil {
+				return err
+			}
+			nUpdated++
+		}
+	}
+	fmt.Fprintf(w, "updated %d repos, %d not found, %d errors", nUpdated, nNotFound, nErrors)
+	return nil
+}
+
+// handleRepopulateSearchDocuments repopulates a batch of the rows in the
+// search_documents table that were last updated before the given time.
+func (s *Server) handleRepopulateSearchDocuments(w http.ResponseWriter, r *http.Request) error {
 	beforeParam := r.FormValue("before")
 	if beforeParam == "" {
 		return &serverError{
@@ -298,17 +545,125 @@ func (s *Server) handleRepopulateSearchDocuments(w http.ResponseWriter, r *http.
 		return &serverError{http.StatusBadRequest, err}
 	}
 
-	ctx := r.Context()
-	log.Infof(ctx, "Repopulating search documents for %d packages", limit)
-	sdargs, err := s.db.GetPackagesForSearchDocumentUpsert(ctx, before, limit)
+	return handleBatch(w, r, "repopulated %d search documents", func(ctx context.Context, after, limit int) (int, int, error) {
+		return s.db.RepopulateSearchDocuments(ctx, before, after, limit)
+	})
+}
+
+// handleUpdateSearchSynonyms rebuilds the search documents that contain words
+// whose search synonyms changed.
+func (s *Server) handleUpdateSearchSynonyms(w http.ResponseWriter, r *http.Request) error {
+	words, n, err := s.db.UpdateSearchSynonyms(r.Context(), parseIntParam(r, "limit", 1000))
 	if err != nil {
 		return err
 	}
+	if len(words) == 0 {
+		fmt.Fprint(w, "search synonyms are unchanged")
+		return nil
+	}
+	fmt.Fprintf(w, "rebuilt %d search documents for changed synonyms %s", n, strings.Join(words, ", "))
+	return nil
+}
 
-	for _, args := range sdargs {
-		if err := postgres.UpsertSearchDocument(ctx, s.db.Underlying(), args); err != nil {
-			return err
+// handleReprocessReadmes re-runs README processing on a batch of stored READMEs.
+func (s *Server) handleReprocessReadmes(w http.ResponseWriter, r *http.Request) error {
+	return handleBatch(w, r, "reprocessed %d READMEs", s.db.ReprocessReadmes)
+}
+
+// handleBatch runs one batch of a job that processes stored rows in order of
+// ID, such as a backfill. run processes at most limit rows whose IDs are
+// greater than after, and returns the largest ID it saw and the number of rows
+// it processed. handleBatch passes it the "after" and "limit" query params,
+// and reports "done" if there were no rows left, or else the number of rows,
+// with the format desc, and the value of "after" to use for the next batch.
+func handleBatch(w http.ResponseWriter, r *http.Request, desc string,
+	run func(ctx context.Context, after, limit int) (last, n int, err error)) error {
+	limit := parseIntParam(r, "limit", 100)
+	after := parseIntParam(r, "after", 0)
+	last, n, err := run(r.Context(), after, limit)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		fmt.Fprint(w, "done")
+		return nil
+	}
+	fmt.Fprintf(w, desc+"; next after=%d", n, last)
+	return nil
+}
+
+// handleRenderReadmes renders and stores a batch of stored READMEs.
+func (s *Server) handleRenderReadmes(w http.ResponseWriter, r *http.Request) error {
+	if s.readmeRenderer == nil {
+		return &serverError{http.StatusNotImplemented, errors.New("READMEs are not rendered by this worker")}
+	}
+	return handleBatch(w, r, "rendered %d READMEs", func(ctx context.Context, after, limit int) (int, int, error) {
+		rs, last, err := s.db.GetReadmesToRender(ctx, after, limit)
+		if err != nil || len(rs) == 0 {
+			return 0, 0, err
+		}
+		if err := renderReadmes(ctx, s.db, s.readmeRenderer, rs); err != nil {
+			return 0, 0, err
 		}
+		return last, len(rs), nil
+	})
+}
+
+// handleBackfillSymbolNameTokens sets the symbol name words of a batch of
+// symbol search documents.
+func (s *Server) handleBackfillSymbolNameTokens(w http.ResponseWriter, r *http.Request) error {
+	return handleBatch(w, r, "backfilled the symbol name words of %d symbol search documents", s.db.BackfillSymbolNameTokens)
+}
+
+// handleRefreshPopularSearches refreshes the cached results of the most
+// popular searches.
+func (s *Server) handleRefreshPopularSearches(w http.ResponseWriter, r *http.Request) error {
+	limit := parseIntParam(r, "limit", 100)
+	n, err := s.db.RefreshPopularSearches(r.Context(), limit)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "refreshed %d popular searches", n)
+	return nil
+}
+
+// handleRefreshAutocomplete refreshes the packages and symbols suggested by
+// search autocompletion.
+func (s *Server) handleRefreshAutocomplete(w http.ResponseWriter, r *http.Request) error {
+	limit := parseIntParam(r, "limit", 100000)
+	n, err := s.db.RefreshAutocompleteCompletions(r.Context(), limit)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "stored %d autocomplete completions", n)
+	return nil
+}
+
+// handleCheckConsistency checks a batch of rows for inconsistencies, and
+// repairs them if asked.
+func (s *Server) handleCheckConsistency(w http.ResponseWriter, r *http.Request) error {
+	kind, err := internal.ParseInconsistencyKind(r.FormValue("kind"))
+	if err != nil {
+		return &serverError{http.StatusBadRequest, err}
+	}
+	limit := parseIntParam(r, "limit", 1000)
+	after := parseIntParam(r, "after", 0)
+	repair := r.FormValue("repair") == "true"
+	incs, last, err := s.db.CheckConsistency(r.Context(), kind, int64(after), limit, repair)
+	if err != nil {
+		return err
+	}
+	if last == 0 {
+		fmt.Fprint(w, "done")
+		return nil
+	}
+	for _, inc := range incs {
+		fmt.Fprintln(w, inc)
+	}
+	if repair {
+		fmt.Fprintf(w, "found and repaired %d; next after=%d", len(incs), last)
+	} else {
+		fmt.Fprintf(w, "found %d; next after=%d", len(incs), last)
 	}
 	return nil
 }
@@ -374,6 +729,12 @@ func (s *Server) doFetch(w http.ResponseWriter, r *http.Request) (string, int) {
 		DB:           s.db,
 		Cache:        s.cache,
 		loadShedder:  s.loadShedder,
+
+		ChecksumDB:               s.checksumDB,
+		RequireVerifiedChecksums: s.cfg.RequireVerifiedChecksums,
+		ContentScanner:           s.contentScanner,
+		CommitHosts:              s.cfg.RepoStatsHosts,
+		ReadmeRenderer:           s.readmeRenderer,
 	}
 	if r.FormValue(queue.DisableProxyFetchParam) == queue.DisableProxyFetchValue {
 		f.ProxyClient = f.ProxyClient.WithFetchDisabled()
@@ -386,6 +747,9 @@ func (s *Server) doFetch(w http.ResponseWriter, r *http.Request) (string, int) {
 		s.reportError(ctx, err, w, r)
 		return err.Error(), code
 	}
+	if code == derrors.ToStatus(derrors.DuplicateFetch) {
+		return fmt.Sprintf("skipped %s@%s: it is already being fetched", modulePath, resolvedVersion), code
+	}
 	return fmt.Sprintf("fetched and updated %s@%s", modulePath, resolvedVersion), code
 }
 
@@ -503,15 +867,29 @@ func (s *Server) handleEnqueue(w http.ResponseWriter, r *http.Request) (err erro
 	w.Header().Set("Content-Type", "text/plain")
 	log.Infof(ctx, "Scheduling modules to be fetched: queuing %d modules", len(modules))
 
+	var throttles []*dynconfig.EnqueueThrottle
+	if s.getEnqueueThrottles != nil {
+		throttles = s.getEnqueueThrottles()
+	}
+	throttler := newEnqueueThrottler(throttles)
+
 	// Enqueue concurrently, because sequentially takes a while.
 	const concurrentEnqueues = 10
 	var (
 		mu                 sync.Mutex
 		nEnqueued, nErrors int
+		nSkipped           int
 	)
 	sem := make(chan struct{}, concurrentEnqueues)
+	skipped := map[string][]internal.Modver{} // by reason
 	for _, m := range modules {
 		m := m
+		if ok, prefix, reason := throttler.allow(m.ModulePath); !ok {
+			recordEnqueueSkipped(ctx, prefix, reason)
+			skipped[reason] = append(skipped[reason], internal.Modver{Path: m.ModulePath, Version: m.Version})
+			nSkipped++
+			continue
+		}
 		opts := queue.Options{
 			Suffix:            suffixParam,
 			DisableProxyFetch: shouldDisableProxyFetch(m),
@@ -536,7 +914,13 @@ func (s *Server) handleEnqueue(w http.ResponseWriter, r *http.Request) (err erro
 	for i := 0; i < concurrentEnqueues; i++ {
 		sem <- struct{}{}
 	}
-	log.Infof(ctx, "Successfully scheduled modules to be fetched: %d modules enqueued, %d errors", nEnqueued, nErrors)
+	// Otherwise the skipped versions would come first again in the next run.
+	for reason, mvs := range skipped {
+		if _, err := s.db.DeferModuleVersions(ctx, mvs, enqueueDeferrals[reason]); err != nil {
+			log.Errorf(ctx, "deferring %s module versions: %v", reason, err)
+		}
+	}
+	log.Infof(ctx, "Successfully scheduled modules to be fetched: %d modules enqueued, %d skipped, %d errors", nEnqueued, nSkipped, nErrors)
 	return nil
 }
 
@@ -810,6 +1194,7 @@ func parseTemplate(cfg *config.Config, staticPath template.TrustedSource, filena
 		"timefmt":   formatTime,
 		"bytesToMi": bytesToMi,
 		"pct":       percentage,
+		"memfmt":    memory.Format,
 		"timeSince": func(t time.Time) time.Duration {
 			return time.Since(t).Round(time.Second)
 		},
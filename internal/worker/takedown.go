@@ -0,0 +1,146 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/pkgsite/internal/cache"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// handleListTakedowns lists all takedowns, including lifted ones.
+func (s *Server) handleListTakedowns(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "handleListTakedowns")
+	ts, err := s.db.GetTakedowns(r.Context())
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tMODULE\tVERSION\tCREATED\tBY\tLIFTED\tBY\tREASON")
+	for _, t := range ts {
+		version := t.Version
+		if version == "" {
+			version = "all"
+		}
+		lifted, liftedBy := "-", "-"
+		if !t.LiftedAt.IsZero() {
+			lifted = t.LiftedAt.Format(time.RFC3339)
+			liftedBy = t.LiftedBy
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", t.ID, t.ModulePath, version,
+			t.CreatedAt.Format(time.RFC3339), t.CreatedBy, lifted, liftedBy, t.Reason)
+	}
+	return tw.Flush()
+}
+
+// handleCreateTakedown takes down the module in the "module" query param at
+// the version in the "version" query param, or at all versions if it is
+// omitted. The "reason" query param is recorded, and the optional "message"
+// query param is shown in place of the module's pages.
+func (s *Server) handleCreateTakedown(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "handleCreateTakedown")
+	modulePath := r.FormValue("module")
+	if modulePath == "" {
+		return &serverError{http.StatusBadRequest, errors.New("must provide 'module' query param")}
+	}
+	reason := r.FormValue("reason")
+	if reason == "" {
+		return &serverError{http.StatusBadRequest, errors.New("must provide 'reason' query param")}
+	}
+	ctx := r.Context()
+	t, err := s.db.CreateTakedown(ctx, modulePath, r.FormValue("version"), requestUser(r), reason, r.FormValue("message"))
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "created takedown %d of %s\n", t.ID, takedownDescription(t.ModulePath, t.Version))
+	s.clearCachesAfterTakedown(ctx, w)
+	return nil
+}
+
+// handleLiftTakedown lifts the takedown whose ID is the "id" query param,
+// and restores the module in search.
+func (s *Server) handleLiftTakedown(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "handleLiftTakedown")
+	id, err := strconv.ParseInt(r.FormValue("id"), 10, 64)
+	if err != nil {
+		return &serverError{http.StatusBadRequest, errors.New("must provide a numeric 'id' query param")}
+	}
+	ctx := r.Context()
+	t, err := s.db.LiftTakedown(ctx, id, requestUser(r))
+	if err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			return &serverError{http.StatusNotFound, err}
+		}
+		return err
+	}
+	if err := s.db.ReconcileSearch(ctx, t.ModulePath, "", 0); err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "lifted takedown %d of %s\n", t.ID, takedownDescription(t.ModulePath, t.Version))
+	s.clearCachesAfterTakedown(ctx, w)
+	return nil
+}
+
+// clearCachesAfterTakedown clears the frontend caches and the cached results
+// of popular searches, so that a change to the takedowns is visible
+// immediately, and reports the result to w. Failing to clear them is not an
+// error, since the cached pages expire eventually, and frontends filter
+// taken-down modules out of the cached search results.
+func (s *Server) clearCachesAfterTakedown(ctx context.Context, w http.ResponseWriter) {
+	if err := s.db.ClearPopularSearchResults(ctx); err != nil {
+		log.Errorf(ctx, "clearing popular search results after takedown: %v", err)
+		fmt.Fprintln(w, "could not clear the popular search results; they are recomputed by the next /refresh-popular-searches")
+	}
+	for _, c := range []struct {
+		name  string
+		cache *cache.Cache
+	}{
+		{"cache", s.cache},
+		{"beta cache", s.betaCache},
+	} {
+		if c.cache == nil {
+			continue
+		}
+		if err := c.cache.Clear(ctx); err != nil {
+			log.Errorf(ctx, "clearing %s after takedown: %v", c.name, err)
+			fmt.Fprintf(w, "could not clear the %s; pages may be served from it until they expire\n", c.name)
+		}
+	}
+}
+
+// requestUser returns the email address of the user that the IAP
+// authenticated for r, or "worker" if there is none, as when the worker is
+// run locally.
+func requestUser(r *http.Request) string {
+	// See https://cloud.google.com/iap/docs/identity-howto.
+	email := r.Header.Get("X-Goog-Authenticated-User-Email")
+	if email == "" {
+		return "worker"
+	}
+	// The value has the form "accounts.google.com:user@example.com".
+	if _, after, ok := strings.Cut(email, ":"); ok {
+		return after
+	}
+	return email
+}
+
+func takedownDescription(modulePath, version string) string {
+	if version == "" {
+		return modulePath + " at all versions"
+	}
+	return modulePath + "@" + version
+}
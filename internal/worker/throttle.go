@@ -0,0 +1,81 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal/config/dynconfig"
+)
+
+// Reasons for skipping a module version at enqueue time, used as metric tag
+// values.
+const (
+	skipReasonDenied    = "denied"
+	skipReasonThrottled = "throttled"
+)
+
+// enqueueDeferrals are how long module versions skipped at enqueue time are
+// left out of the next modules to fetch, by reason. Throttled versions can be
+// enqueued by the next run, but denied ones only once their throttle is
+// changed.
+var enqueueDeferrals = map[string]time.Duration{
+	skipReasonDenied:    24 * time.Hour,
+	skipReasonThrottled: time.Hour,
+}
+
+// enqueueThrottler applies a set of dynconfig.EnqueueThrottles to the module
+// versions considered by a single run of handleEnqueue.
+//
+// handleEnqueue defers the module versions it skips for a while (see
+// enqueueDeferrals), so that they do not crowd out the others; they are
+// considered again by later runs.
+type enqueueThrottler struct {
+	throttles []*dynconfig.EnqueueThrottle
+	counts    map[string]int // number enqueued so far, by throttle prefix
+}
+
+func newEnqueueThrottler(throttles []*dynconfig.EnqueueThrottle) *enqueueThrottler {
+	return &enqueueThrottler{
+		throttles: throttles,
+		counts:    map[string]int{},
+	}
+}
+
+// allow reports whether the module path should be enqueued. If not, it also
+// returns the prefix of the throttle responsible and the reason.
+// If more than one throttle matches, the one with the longest prefix is used.
+// allow counts each allowed module path against its throttle's limit, if it
+// has one.
+func (t *enqueueThrottler) allow(modulePath string) (ok bool, prefix, reason string) {
+	var match *dynconfig.EnqueueThrottle
+	for _, et := range t.throttles {
+		if matchesPrefix(modulePath, et.Prefix) && (match == nil || len(et.Prefix) > len(match.Prefix)) {
+			match = et
+		}
+	}
+	switch {
+	case match == nil:
+		return true, "", ""
+	case match.Deny:
+		return false, match.Prefix, skipReasonDenied
+	case match.Limit > 0 && t.counts[match.Prefix] >= match.Limit:
+		return false, match.Prefix, skipReasonThrottled
+	default:
+		t.counts[match.Prefix]++
+		return true, "", ""
+	}
+}
+
+// matchesPrefix reports whether prefix is equal to modulePath or is a
+// componentwise prefix of it.
+func matchesPrefix(modulePath, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" {
+		return false
+	}
+	return modulePath == prefix || strings.HasPrefix(modulePath, prefix+"/")
+}
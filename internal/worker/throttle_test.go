@@ -0,0 +1,63 @@
+// Copyright 2026 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"testing"
+
+	"golang.org/x/pkgsite/internal/config/dynconfig"
+)
+
+func TestEnqueueThrottler(t *testing.T) {
+	throttles := []*dynconfig.EnqueueThrottle{
+		{Prefix: "github.com/spam", Deny: true},
+		{Prefix: "github.com/spam/ok", Limit: 1},
+		{Prefix: "gopkg.in", Limit: 2},
+		{Prefix: "gopkg.in/unlimited", Limit: 0},
+	}
+	et := newEnqueueThrottler(throttles)
+	for _, test := range []struct {
+		modulePath string
+		wantOK     bool
+		wantPrefix string
+		wantReason string
+	}{
+		{"example.com/m", true, "", ""},
+		{"github.com/spam", false, "github.com/spam", skipReasonDenied},
+		{"github.com/spam/a", false, "github.com/spam", skipReasonDenied},
+		{"github.com/spammer/a", true, "", ""},
+		{"github.com/spam/ok/a", true, "", ""},
+		{"github.com/spam/ok/b", false, "github.com/spam/ok", skipReasonThrottled},
+		{"gopkg.in/yaml.v2", true, "", ""},
+		{"gopkg.invalid/x", true, "", ""},
+		{"gopkg.in/yaml.v3", true, "", ""},
+		{"gopkg.in/check.v1", false, "gopkg.in", skipReasonThrottled},
+		{"gopkg.in/unlimited/a", true, "", ""},
+		{"gopkg.in/unlimited/b", true, "", ""},
+	} {
+		gotOK, gotPrefix, gotReason := et.allow(test.modulePath)
+		if gotOK != test.wantOK || gotPrefix != test.wantPrefix || gotReason != test.wantReason {
+			t.Errorf("allow(%q) = (%t, %q, %q), want (%t, %q, %q)",
+				test.modulePath, gotOK, gotPrefix, gotReason, test.wantOK, test.wantPrefix, test.wantReason)
+		}
+	}
+}
+
+func TestMatchesPrefix(t *testing.T) {
+	for _, test := range []struct {
+		modulePath, prefix string
+		want               bool
+	}{
+		{"gopkg.in/yaml.v2", "gopkg.in", true},
+		{"gopkg.in/yaml.v2", "gopkg.in/", true},
+		{"gopkg.in", "gopkg.in", true},
+		{"gopkg.invalid", "gopkg.in", false},
+		{"gopkg.in", "", false},
+	} {
+		if got := matchesPrefix(test.modulePath, test.prefix); got != test.want {
+			t.Errorf("matchesPrefix(%q, %q) = %t, want %t", test.modulePath, test.prefix, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE repo_stats (
+    repo_url TEXT PRIMARY KEY,
+    status INTEGER NOT NULL,
+    stars INTEGER NOT NULL DEFAULT 0,
+    open_issues INTEGER NOT NULL DEFAULT 0,
+    last_commit_at timestamp with time zone,
+    fetched_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+COMMENT ON TABLE repo_stats IS
+'TABLE repo_stats contains statistics about source repositories, fetched from the API of the repository host.
+Rows are refreshed by the worker once fetched_at is older than the configured TTL.';
+
+COMMENT ON COLUMN repo_stats.status IS
+'COLUMN status is the HTTP status of the last fetch: 200 if the statistics are valid, 404 if the host does not know the repository.';
+
+CREATE INDEX idx_repo_stats_fetched_at ON repo_stats (fetched_at);
+
+END;
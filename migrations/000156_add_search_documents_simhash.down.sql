@@ -0,0 +1,10 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE search_documents DROP COLUMN dup_group;
+ALTER TABLE search_documents DROP COLUMN simhash;
+
+END;
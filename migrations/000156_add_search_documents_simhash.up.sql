@@ -0,0 +1,16 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE search_documents ADD COLUMN simhash BIGINT;
+ALTER TABLE search_documents ADD COLUMN dup_group TEXT;
+
+COMMENT ON COLUMN search_documents.simhash IS
+'COLUMN simhash is a locality-sensitive hash of the package synopsis and exported symbol names. Packages whose hashes differ in few bits are near duplicates, such as forks and vendored copies.';
+
+COMMENT ON COLUMN search_documents.dup_group IS
+'COLUMN dup_group is the package path of the canonical package among the near duplicates of this package, or the package''s own path if it has none. It is NULL until computed by the worker.';
+
+END;
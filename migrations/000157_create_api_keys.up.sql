@@ -0,0 +1,29 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE api_keys (
+    id BIGSERIAL PRIMARY KEY,
+    key_hash TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    qps INTEGER NOT NULL DEFAULT 0,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    revoked_at timestamp with time zone
+);
+
+COMMENT ON TABLE api_keys IS
+'TABLE api_keys contains the keys issued to clients of the JSON endpoints of the frontend.
+Keys are issued and revoked by the worker.';
+
+COMMENT ON COLUMN api_keys.key_hash IS
+'COLUMN key_hash is the hex-encoded SHA-256 hash of the key. The key itself is not stored.';
+
+COMMENT ON COLUMN api_keys.qps IS
+'COLUMN qps is the number of requests per second allowed for the key. If it is zero, the default quota for API keys applies.';
+
+COMMENT ON COLUMN api_keys.revoked_at IS
+'COLUMN revoked_at is the time the key was revoked, or NULL if the key is valid.';
+
+END;
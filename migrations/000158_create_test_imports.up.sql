@@ -0,0 +1,18 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE test_imports (
+    unit_id BIGINT NOT NULL REFERENCES units(id) ON DELETE CASCADE,
+    to_path_id BIGINT NOT NULL REFERENCES paths(id) ON DELETE CASCADE,
+    PRIMARY KEY (unit_id, to_path_id)
+);
+
+COMMENT ON TABLE test_imports IS
+'TABLE test_imports contains the imports of the tests of a package in the units table
+that are not imports of the package itself.
+The tests of the package represented by unit_id import to_path_id.';
+
+END;
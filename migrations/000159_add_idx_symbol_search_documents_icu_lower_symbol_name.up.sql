@@ -0,0 +1,14 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+-- The database's LC_CTYPE is C, so lower(symbol_name) only folds the case of
+-- ASCII letters. Index symbol names folded with an ICU collation instead, so
+-- that symbols with non-ASCII names can be searched for case-insensitively.
+-- Migrations 000197 and 000198 add the other index and drop the old ones.
+--
+-- The symbol_search_documents table is large, so the index is built without
+-- locking it against writes. CREATE INDEX CONCURRENTLY cannot run in a
+-- transaction.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_symbol_search_documents_icu_lower_symbol_name
+    ON symbol_search_documents(lower(symbol_name COLLATE "und-x-icu"));
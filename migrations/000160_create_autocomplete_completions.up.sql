@@ -0,0 +1,35 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- autocomplete_completions holds the most imported packages and symbols, as
+-- computed by the worker. Search autocompletion matches prefixes against this
+-- table only, so that the rows a request reads are bounded by the size of the
+-- table rather than by the number of packages and symbols a prefix matches.
+CREATE TABLE autocomplete_completions (
+    package_path TEXT NOT NULL,
+    -- name is the package name of a package, and empty for a symbol.
+    name TEXT NOT NULL,
+    -- symbol_name is empty for a package.
+    symbol_name TEXT NOT NULL,
+    module_path TEXT NOT NULL,
+    imported_by_count INTEGER NOT NULL,
+    PRIMARY KEY (package_path, symbol_name)
+);
+
+-- text_pattern_ops is needed for LIKE 'prefix%' to use an index regardless of
+-- the database's collation.
+CREATE INDEX idx_autocomplete_completions_package_path
+    ON autocomplete_completions (package_path text_pattern_ops);
+CREATE INDEX idx_autocomplete_completions_name
+    ON autocomplete_completions (name text_pattern_ops);
+CREATE INDEX idx_autocomplete_completions_symbol_name
+    ON autocomplete_completions (symbol_name text_pattern_ops);
+CREATE INDEX idx_autocomplete_completions_imported_by_count_desc
+    ON autocomplete_completions (imported_by_count DESC);
+CREATE INDEX idx_autocomplete_completions_module_path
+    ON autocomplete_completions (module_path);
+
+END;
@@ -0,0 +1,18 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- unit_views holds the number of times each unit page was viewed on each
+-- day. Only the aggregate count is stored, nothing about the viewers.
+CREATE TABLE unit_views (
+    path TEXT NOT NULL,
+    day DATE NOT NULL,
+    views BIGINT NOT NULL,
+    PRIMARY KEY (path, day)
+);
+
+CREATE INDEX idx_unit_views_day ON unit_views (day);
+
+END;
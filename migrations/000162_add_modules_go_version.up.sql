@@ -0,0 +1,12 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- go_version is the version in the go directive of the module's go.mod file.
+-- It is NULL for modules without one, and for modules processed before this
+-- column was added.
+ALTER TABLE modules ADD COLUMN go_version TEXT;
+
+END;
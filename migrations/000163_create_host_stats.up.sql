@@ -0,0 +1,26 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE host_stats (
+    host TEXT PRIMARY KEY,
+    num_modules INTEGER NOT NULL,
+    num_versions INTEGER NOT NULL,
+    num_failed_versions INTEGER NOT NULL,
+    avg_doc_size INTEGER NOT NULL,
+    computed_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+COMMENT ON TABLE host_stats IS
+'TABLE host_stats contains statistics about the modules served from each hosting domain, the first element of their module paths.
+The worker recomputes all rows periodically.';
+
+COMMENT ON COLUMN host_stats.num_failed_versions IS
+'COLUMN num_failed_versions is the number of processed module versions whose last fetch failed with a 5xx status.';
+
+COMMENT ON COLUMN host_stats.avg_doc_size IS
+'COLUMN avg_doc_size is the average size in bytes of the encoded documentation of the packages in the host''s modules.';
+
+END;
@@ -0,0 +1,12 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- source_hash is a hash of source. Packages with identical documentation
+-- have the same hash, so it can be used to share rendered documentation
+-- across versions.
+ALTER TABLE documentation ADD COLUMN source_hash TEXT;
+
+END;
@@ -0,0 +1,11 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- author_metadata holds the links and badges declared in the module's
+-- pkgsite.yaml file, as JSON; see internal.AuthorMetadata.
+ALTER TABLE modules ADD COLUMN author_metadata JSONB;
+
+END;
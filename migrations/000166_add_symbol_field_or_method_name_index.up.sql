@@ -0,0 +1,13 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+-- Symbol search matches fields and methods by their name without the
+-- receiver, for example "Begin" for "DB.Begin". The expression must match
+-- lowerFieldOrMethodName in internal/postgres/search.
+--
+-- The symbol_search_documents table is large, so the index is built without
+-- locking it against writes. CREATE INDEX CONCURRENTLY cannot run in a
+-- transaction.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_symbol_search_documents_icu_lower_field_or_method_name
+    ON symbol_search_documents(lower(split_part(symbol_name, '.', 2) COLLATE "und-x-icu"));
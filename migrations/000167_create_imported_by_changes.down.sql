@@ -0,0 +1,11 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+DROP TRIGGER record_imported_by_change ON imports_unique;
+DROP FUNCTION trigger_record_imported_by_change;
+DROP TABLE imported_by_changes;
+
+END;
@@ -0,0 +1,33 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE imported_by_changes (
+    id BIGSERIAL PRIMARY KEY,
+    package_path TEXT NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+COMMENT ON TABLE imported_by_changes IS
+'TABLE imported_by_changes records packages whose importers may have changed since their imported_by_count was last computed.
+A trigger on imports_unique inserts a row for every import that is added or removed. The worker removes rows as it recomputes the counts.
+Rows are never updated, so that module inserts do not contend for them.';
+
+CREATE FUNCTION trigger_record_imported_by_change() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        INSERT INTO imported_by_changes (package_path) VALUES (OLD.to_path);
+        RETURN OLD;
+    END IF;
+    INSERT INTO imported_by_changes (package_path) VALUES (NEW.to_path);
+    RETURN NEW;
+END;
+$$ LANGUAGE PLPGSQL;
+
+CREATE TRIGGER record_imported_by_change
+AFTER INSERT OR DELETE ON imports_unique
+FOR EACH ROW EXECUTE PROCEDURE trigger_record_imported_by_change();
+
+END;
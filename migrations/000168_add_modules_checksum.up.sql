@@ -0,0 +1,12 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- checksum holds the provenance of the module version reported by the proxy,
+-- its go.sum hashes, and the result of checking them against the checksum
+-- database, as JSON; see internal.ModuleChecksum.
+ALTER TABLE modules ADD COLUMN checksum JSONB;
+
+END;
@@ -0,0 +1,19 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE localized_readmes (
+    unit_id BIGINT NOT NULL REFERENCES units(id) ON DELETE CASCADE,
+    lang TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    contents TEXT NOT NULL,
+    PRIMARY KEY (unit_id, lang)
+);
+
+COMMENT ON TABLE localized_readmes IS
+'TABLE localized_readmes contains translations of the README of a unit, such as README.zh-CN.md.
+lang is the BCP 47 language tag from the file name. The main README is in the readmes table.';
+
+END;
@@ -0,0 +1,12 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- commit holds the hash, subject line and author time of the commit that a
+-- pseudo-version refers to, as reported by the repository's host, as JSON;
+-- see source.CommitInfo.
+ALTER TABLE modules ADD COLUMN commit JSONB;
+
+END;
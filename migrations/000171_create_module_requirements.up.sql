@@ -0,0 +1,20 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE module_requirements (
+    module_id BIGINT NOT NULL REFERENCES modules(id) ON DELETE CASCADE,
+    module_path TEXT NOT NULL,
+    version TEXT NOT NULL,
+    indirect BOOLEAN NOT NULL,
+    PRIMARY KEY (module_id, module_path)
+);
+
+COMMENT ON TABLE module_requirements IS
+'TABLE module_requirements contains the require directives of the go.mod file of a module version.
+module_path and version are those of the required module; indirect is whether the requirement is
+marked "// indirect".';
+
+END;
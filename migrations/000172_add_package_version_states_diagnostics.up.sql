@@ -0,0 +1,11 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- diagnostics holds the problems found in the package's files when it failed
+-- to load, as JSON; see internal.PackageDiagnostic.
+ALTER TABLE package_version_states ADD COLUMN diagnostics JSONB;
+
+END;
@@ -0,0 +1,22 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE analysis_reports (
+    module_id BIGINT NOT NULL REFERENCES modules(id) ON DELETE CASCADE,
+    analyzer TEXT NOT NULL,
+    summary TEXT NOT NULL,
+    metrics JSONB NOT NULL,
+    url TEXT NOT NULL,
+    submitter TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (module_id, analyzer)
+);
+
+COMMENT ON TABLE analysis_reports IS
+'TABLE analysis_reports contains the latest report of each external analyzer for a module version.
+submitter is the name of the API key that posted the report.';
+
+END;
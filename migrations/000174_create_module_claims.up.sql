@@ -0,0 +1,25 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE module_claims (
+    id BIGSERIAL PRIMARY KEY,
+    module_path TEXT NOT NULL,
+    challenge TEXT NOT NULL,
+    secret_hash TEXT NOT NULL UNIQUE,
+    verified_by TEXT NOT NULL DEFAULT '',
+    contact_url TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    verified_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_module_claims_module_path ON module_claims(module_path);
+
+COMMENT ON TABLE module_claims IS
+'TABLE module_claims contains claims by users to own modules. A claim is verified by publishing its
+challenge in a DNS TXT record or a repository file. secret_hash is the hash of the secret that
+identifies the claimant; the secret itself is not stored.';
+
+END;
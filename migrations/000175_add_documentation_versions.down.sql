@@ -0,0 +1,10 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE documentation DROP COLUMN toolchain;
+ALTER TABLE documentation DROP COLUMN app_version;
+
+END;
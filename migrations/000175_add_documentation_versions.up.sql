@@ -0,0 +1,14 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- app_version is the version label of the worker that processed the
+-- documentation, and toolchain is the Go version it was built with. They
+-- are used to find documentation that should be reprocessed after the
+-- renderer or the toolchain changes.
+ALTER TABLE documentation ADD COLUMN app_version TEXT;
+ALTER TABLE documentation ADD COLUMN toolchain TEXT;
+
+END;
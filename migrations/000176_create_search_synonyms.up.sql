@@ -0,0 +1,17 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE search_synonyms (
+    word TEXT PRIMARY KEY,
+    replacements TEXT[] NOT NULL
+);
+
+COMMENT ON TABLE search_synonyms IS
+'TABLE search_synonyms contains the word replacements that the search documents were last built
+with. It is compared with the current replacements to find the search documents to rebuild.
+If it is empty, the search documents were built with the built-in replacements.';
+
+END;
@@ -0,0 +1,19 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE feature_readiness (
+    feature TEXT NOT NULL,
+    module_path TEXT NOT NULL,
+    ready_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (feature, module_path)
+);
+
+COMMENT ON TABLE feature_readiness IS
+'TABLE feature_readiness records the modules for which a data feature, such as symbol history,
+has been backfilled. A row with an empty module_path means that the feature has been backfilled
+for all modules. The frontend hides features that are not ready for a module.';
+
+END;
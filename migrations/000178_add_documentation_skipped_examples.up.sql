@@ -0,0 +1,12 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- skipped_examples is a JSON list of the example functions in the package's
+-- test files that are not displayed with its documentation, with the reason
+-- for each. It is NULL if there are none.
+ALTER TABLE documentation ADD COLUMN skipped_examples JSONB;
+
+END;
@@ -0,0 +1,33 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE takedowns (
+    id BIGSERIAL PRIMARY KEY,
+    module_path TEXT NOT NULL,
+    version TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    message TEXT NOT NULL,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    lifted_by TEXT,
+    lifted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_takedowns_module_path ON takedowns(module_path) WHERE lifted_at IS NULL;
+
+COMMENT ON TABLE takedowns IS
+'TABLE takedowns records the modules, or versions of them, that have been taken down, usually
+because of a legal request. The data of a module that is taken down is kept, but the frontend
+shows a tombstone page in place of its pages, and it is removed from search. Rows are never
+deleted, so that the table is an audit record; lifting a takedown sets lifted_at.';
+
+COMMENT ON COLUMN takedowns.version IS
+'COLUMN version is the version that is taken down, or empty if all versions are.';
+
+COMMENT ON COLUMN takedowns.message IS
+'COLUMN message is shown on the tombstone page. If it is empty, a default message is shown.';
+
+END;
@@ -0,0 +1,23 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE fetch_claims (
+    module_path TEXT NOT NULL,
+    version TEXT NOT NULL,
+    token TEXT NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (module_path, version)
+);
+
+COMMENT ON TABLE fetch_claims IS
+'TABLE fetch_claims records the module versions that a worker is fetching, so that other fetches
+of the same module version are skipped. A claim is deleted when its fetch finishes; a claim whose
+expires_at has passed belongs to a fetch that did not finish, and can be taken over.';
+
+COMMENT ON COLUMN fetch_claims.token IS
+'COLUMN token is a random string identifying the claim, so that only its fetch deletes it.';
+
+END;
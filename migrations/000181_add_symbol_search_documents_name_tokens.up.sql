@@ -0,0 +1,14 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- The words of a symbol name, split at dots, underscores and camelCase and
+-- acronym boundaries, so that multi-word searches like "http client do" can
+-- find Client.Do in net/http. The words are computed by search.SymbolTokens
+-- when the documents of a module are upserted; documents of modules that have
+-- not been processed since have none.
+ALTER TABLE symbol_search_documents ADD COLUMN tsv_symbol_name_tokens TSVECTOR;
+
+END;
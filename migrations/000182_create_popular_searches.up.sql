@@ -0,0 +1,38 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- search_query_counts holds the number of times each page of search results
+-- was requested on each day. A page is identified by the normalized query and
+-- the options that determine its results. Only the aggregate count is stored,
+-- nothing about the searchers.
+CREATE TABLE search_query_counts (
+    query TEXT NOT NULL,
+    max_results INTEGER NOT NULL,
+    max_result_count INTEGER NOT NULL,
+    day DATE NOT NULL,
+    count BIGINT NOT NULL,
+    PRIMARY KEY (query, max_results, max_result_count, day)
+);
+
+CREATE INDEX idx_search_query_counts_day ON search_query_counts (day);
+
+-- popular_search_results holds the results of the most requested pages of
+-- search results, as computed by the worker, which are served in place of
+-- running the search.
+CREATE TABLE popular_search_results (
+    query TEXT NOT NULL,
+    max_results INTEGER NOT NULL,
+    max_result_count INTEGER NOT NULL,
+    results JSONB NOT NULL,
+    -- bypass_license_check reports whether the results include the data of
+    -- non-redistributable packages. They are only served by frontends that
+    -- also bypass the license check.
+    bypass_license_check BOOLEAN NOT NULL,
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (query, max_results, max_result_count)
+);
+
+END;
@@ -0,0 +1,18 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- go_release_note_sections holds the anchors of the sections about packages
+-- in the release notes of Go releases, like "go1.16", as parsed from the Go
+-- repository when the standard library is processed. The "added in"
+-- annotations of standard library symbols link to them.
+CREATE TABLE go_release_note_sections (
+    go_version TEXT NOT NULL,
+    package_path TEXT NOT NULL,
+    anchor TEXT NOT NULL,
+    PRIMARY KEY (go_version, package_path)
+);
+
+END;
@@ -0,0 +1,11 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- content_warnings holds the files of the module version that break the
+-- content policy of the worker, as JSON; see internal.ContentWarning.
+ALTER TABLE modules ADD COLUMN content_warnings JSONB;
+
+END;
@@ -0,0 +1,19 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- rendered_readmes holds READMEs that were rendered when their modules were
+-- processed, so that the frontend does not have to parse their Markdown on
+-- every uncached page view. Each row is a docrender.ReadmeResponse, encoded
+-- as JSON, keyed by the docrender.ReadmeHash of the request that rendered it.
+-- Since the hash covers everything that affects the rendering, the same
+-- README in many versions of a module is stored once.
+CREATE TABLE rendered_readmes (
+    readme_hash TEXT PRIMARY KEY,
+    rendered JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+END;
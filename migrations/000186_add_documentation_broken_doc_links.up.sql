@@ -0,0 +1,12 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- broken_doc_links is a JSON list of the doc links in the package's doc
+-- comments, such as [Foo], to symbols that do not exist, with the reason for
+-- each. It is NULL if there are none.
+ALTER TABLE documentation ADD COLUMN broken_doc_links JSONB;
+
+END;
@@ -0,0 +1,20 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- imported_by_history holds weekly snapshots of the imported_by_count of
+-- search_documents, so that unit pages can show whether a package is gaining
+-- or losing importers. week is the Monday of the week of the snapshot.
+-- Packages that have never been imported have no rows.
+CREATE TABLE imported_by_history (
+    package_path TEXT NOT NULL,
+    week DATE NOT NULL,
+    imported_by_count INTEGER NOT NULL,
+    PRIMARY KEY (package_path, week)
+);
+
+CREATE INDEX idx_imported_by_history_week ON imported_by_history (week);
+
+END;
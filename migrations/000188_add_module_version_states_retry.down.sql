@@ -0,0 +1,12 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE module_version_states
+    DROP COLUMN retry_class,
+    DROP COLUMN retry_attempts,
+    DROP COLUMN max_retry_attempts;
+
+END;
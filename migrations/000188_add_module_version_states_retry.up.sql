@@ -0,0 +1,17 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- retry_class is the class of the result of the last fetch that decides how
+-- it is retried, such as "proxy" or "permanent"; it is empty for versions
+-- not fetched since it was added. retry_attempts is the number of consecutive
+-- retryable failures, and max_retry_attempts the number after which the
+-- version is no longer retried, or NULL for no limit.
+ALTER TABLE module_version_states
+    ADD COLUMN retry_class TEXT NOT NULL DEFAULT '',
+    ADD COLUMN retry_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN max_retry_attempts INTEGER;
+
+END;
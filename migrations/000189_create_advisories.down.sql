@@ -0,0 +1,11 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE api_keys DROP COLUMN advisory_prefixes;
+
+DROP TABLE advisories;
+
+END;
@@ -0,0 +1,30 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE advisories (
+    id TEXT PRIMARY KEY,
+    entry JSONB NOT NULL,
+    publisher TEXT NOT NULL,
+    created_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at timestamp with time zone DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+COMMENT ON TABLE advisories IS
+'TABLE advisories contains advisories for vulnerabilities in modules that the Go vulnerability database does not cover, such as private modules.
+They are published to the frontend by clients with API keys, and served along with the Go vulnerability database.';
+
+COMMENT ON COLUMN advisories.entry IS
+'COLUMN entry is the advisory as an OSV entry.';
+
+COMMENT ON COLUMN advisories.publisher IS
+'COLUMN publisher is the name of the API key that last published the advisory.';
+
+ALTER TABLE api_keys ADD COLUMN advisory_prefixes TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN api_keys.advisory_prefixes IS
+'COLUMN advisory_prefixes are the module path prefixes of the modules the key may publish advisories for.';
+
+END;
@@ -0,0 +1,12 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- excluded_files is a JSON list of the non-test .go files of the package that
+-- are not built in the documentation's build context, with the build
+-- constraint of each. It is NULL if there are none.
+ALTER TABLE documentation ADD COLUMN excluded_files JSONB;
+
+END;
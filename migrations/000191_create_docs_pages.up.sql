@@ -0,0 +1,18 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE docs_pages (
+    unit_id BIGINT NOT NULL REFERENCES units(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    contents TEXT NOT NULL,
+    PRIMARY KEY (unit_id, file_path)
+);
+
+COMMENT ON TABLE docs_pages IS
+'TABLE docs_pages contains the Markdown files under the docs directory of a unit, such as docs/guide.md.
+They are shown in the Docs tab of the unit page.';
+
+END;
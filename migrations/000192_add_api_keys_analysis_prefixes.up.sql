@@ -0,0 +1,12 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE api_keys ADD COLUMN analysis_prefixes TEXT[] NOT NULL DEFAULT '{}';
+
+COMMENT ON COLUMN api_keys.analysis_prefixes IS
+'COLUMN analysis_prefixes are the module path prefixes of the modules the key may post analysis reports for.';
+
+END;
@@ -0,0 +1,10 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+DELETE FROM feature_readiness
+WHERE module_path = '' AND feature IN ('symbol-history', 'api-diff', 'scores');
+
+END;
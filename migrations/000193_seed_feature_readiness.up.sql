@@ -0,0 +1,13 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- The worker computed these features for every module before their readiness
+-- was recorded, so they are ready for all modules.
+INSERT INTO feature_readiness (feature, module_path)
+VALUES ('symbol-history', ''), ('api-diff', ''), ('scores', '')
+ON CONFLICT DO NOTHING;
+
+END;
@@ -0,0 +1,15 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- Tie each rendered README to the module version that stored it, so that it
+-- is deleted along with that version. The existing rows are not tied to a
+-- module version, so they are dropped; /render-readmes renders them again.
+TRUNCATE rendered_readmes;
+ALTER TABLE rendered_readmes
+    ADD COLUMN module_id BIGINT NOT NULL REFERENCES modules(id) ON DELETE CASCADE;
+CREATE INDEX idx_rendered_readmes_module_id ON rendered_readmes(module_id);
+
+END;
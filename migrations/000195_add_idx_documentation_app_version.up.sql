@@ -0,0 +1,7 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+-- The documentation table is large, so the index is built without locking
+-- it against writes. CREATE INDEX CONCURRENTLY cannot run in a transaction.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_documentation_app_version ON documentation(app_version);
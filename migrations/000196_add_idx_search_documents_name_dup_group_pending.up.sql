@@ -0,0 +1,10 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+-- Used by the worker to find packages whose duplicate group must be computed.
+--
+-- The search_documents table is large, so the index is built without locking
+-- it against writes. CREATE INDEX CONCURRENTLY cannot run in a transaction.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_search_documents_name_dup_group_pending ON search_documents (name)
+    WHERE dup_group IS NULL AND simhash IS NOT NULL;
@@ -0,0 +1,12 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+-- Like idx_symbol_search_documents_icu_lower_symbol_name, added in migration
+-- 000159, ordered for search by popularity.
+--
+-- The symbol_search_documents table is large, so the index is built without
+-- locking it against writes. CREATE INDEX CONCURRENTLY cannot run in a
+-- transaction.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_symbol_search_documents_icu_lower_symbol_name_imported_by_count
+    ON symbol_search_documents(lower(symbol_name COLLATE "und-x-icu"), imported_by_count DESC);
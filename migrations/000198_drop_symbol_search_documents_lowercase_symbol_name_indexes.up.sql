@@ -0,0 +1,11 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- These are replaced by the ICU indexes added in migrations 000159 and 000197.
+DROP INDEX idx_symbol_search_documents_lowercase_symbol_name;
+DROP INDEX idx_symbol_search_documents_symbol_name_imported_by_count;
+
+END;
@@ -0,0 +1,11 @@
+-- Copyright 2026 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+-- Used to search the words of symbol names added in migration 000181.
+--
+-- The symbol_search_documents table is large, so the index is built without
+-- locking it against writes. CREATE INDEX CONCURRENTLY cannot run in a
+-- transaction.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_symbol_search_documents_tsv_symbol_name_tokens
+    ON symbol_search_documents USING gin (tsv_symbol_name_tokens);
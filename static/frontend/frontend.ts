@@ -12,6 +12,7 @@ import { ToolTipController } from 'static/shared/tooltip/tooltip';
 import { SelectNavController } from 'static/shared/outline/select';
 import { ModalController } from 'static/shared/modal/modal';
 import { initModals } from 'static/shared/jump/jump';
+import { AutocompleteController } from 'static/shared/autocomplete/autocomplete';
 
 import { keyboard } from 'static/shared/keyboard/keyboard';
 import * as analytics from 'static/shared/analytics/analytics';
@@ -34,6 +35,10 @@ window.addEventListener('load', () => {
     new SelectNavController(el);
   }
 
+  for (const el of document.querySelectorAll<HTMLInputElement>('.js-autocomplete')) {
+    new AutocompleteController(el);
+  }
+
   for (const el of document.querySelectorAll<HTMLSelectElement>('.js-carousel')) {
     new CarouselController(el);
   }
@@ -57,6 +62,7 @@ window.addEventListener('load', () => {
   initModals();
   initJumpLinks();
   registerCookieNotice();
+  registerStdlibOnlyToggle();
 });
 
 // Pressing '/' focuses the search box
@@ -156,3 +162,24 @@ function registerCookieNotice() {
     });
   }
 }
+
+/**
+ * registerStdlibOnlyToggle checks the "Standard library only" checkbox of the
+ * homepage search form if the user has turned the toggle on. The server sets
+ * the cookie when a search uses the toggle. If the user unchecks the box, the
+ * form asks the server to turn the toggle off.
+ */
+function registerStdlibOnlyToggle() {
+  const checkbox = document.querySelector<HTMLInputElement>('.js-stdlibOnly');
+  const clear = document.querySelector<HTMLInputElement>('.js-stdlibOnlyClear');
+  if (!checkbox || !clear) {
+    return;
+  }
+  const isSet = /(^|;\s*)search-stdlib-only=1(;|$)/.test(document.cookie);
+  checkbox.checked = isSet;
+  const update = () => {
+    clear.disabled = checkbox.checked || !isSet;
+  };
+  update();
+  checkbox.addEventListener('change', update);
+}
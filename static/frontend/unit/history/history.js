@@ -0,0 +1,8 @@
+var e=class{constructor(){this.slider=document.querySelector(".js-symbolHistorySlider");this.range=document.querySelector(".js-symbolHistoryRange");this.output=document.querySelector(".js-symbolHistoryOutput");this.entries=[...document.querySelectorAll(".js-symbolHistoryEntry")];!this.slider||!this.range||(this.slider.hidden=!1,this.range.addEventListener("input",()=>this.show()),this.show())}show(){var s,r,i,n;let t=Number((r=(s=this.range)==null?void 0:s.value)!=null?r:0);this.entries.forEach((o,u)=>{o.hidden=u!==t}),this.output&&(this.output.textContent=(n=(i=this.entries[t])==null?void 0:i.dataset.version)!=null?n:"")}};new e;export{e as SymbolHistoryController};
+/*!
+ * @license
+ * Copyright 2026 The Go Authors. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+//# sourceMappingURL=history.js.map
@@ -0,0 +1,39 @@
+/*!
+ * @license
+ * Copyright 2026 The Go Authors. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+/**
+ * SymbolHistoryController turns the list of versions on the symbol history
+ * page into a slider that shows one version at a time.
+ */
+export class SymbolHistoryController {
+  private slider = document.querySelector<HTMLLabelElement>('.js-symbolHistorySlider');
+  private range = document.querySelector<HTMLInputElement>('.js-symbolHistoryRange');
+  private output = document.querySelector<HTMLOutputElement>('.js-symbolHistoryOutput');
+  private entries = [...document.querySelectorAll<HTMLElement>('.js-symbolHistoryEntry')];
+
+  constructor() {
+    if (!this.slider || !this.range) return;
+    this.slider.hidden = false;
+    this.range.addEventListener('input', () => this.show());
+    this.show();
+  }
+
+  /**
+   * show displays the entry selected by the slider and hides the others.
+   */
+  private show() {
+    const selected = Number(this.range?.value ?? 0);
+    this.entries.forEach((e, i) => {
+      e.hidden = i !== selected;
+    });
+    if (this.output) {
+      this.output.textContent = this.entries[selected]?.dataset.version ?? '';
+    }
+  }
+}
+
+new SymbolHistoryController();
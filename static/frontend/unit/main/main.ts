@@ -1,6 +1,6 @@
 import { initPlaygrounds } from 'static/shared/playground/playground';
 import { SelectNavController, makeSelectNav } from 'static/shared/outline/select';
-import { TreeNavController } from 'static/shared/outline/tree';
+import { TreeNavController, decodeFragment } from 'static/shared/outline/tree';
 import { ExpandableRowsTableController } from 'static/shared/table/table';
 
 initPlaygrounds();
@@ -125,7 +125,7 @@ function expandReadme() {
  */
 function openDeprecatedSymbol() {
   if (!location.hash) return;
-  const heading = document.getElementById(location.hash.slice(1));
+  const heading = document.getElementById(decodeFragment(location.hash));
   const grandParent = heading?.parentElement?.parentElement as HTMLDetailsElement | null;
   if (grandParent?.nodeName === 'DETAILS') {
     grandParent.open = true;
@@ -135,10 +135,62 @@ openDeprecatedSymbol();
 window.addEventListener('hashchange', () => openDeprecatedSymbol());
 
 /**
- * Listen for changes in the build context dropdown.
+ * Hide or show symbols declared in generated files when the
+ * "Hide generated symbols" checkbox in the index is toggled.
+ */
+document.querySelectorAll<HTMLInputElement>('.js-generatedToggle').forEach(el => {
+  el.addEventListener('change', () => {
+    document.documentElement.toggleAttribute('data-hide-generated', el.checked);
+  });
+});
+
+/**
+ * Listen for changes in the build context dropdown. The submit button
+ * is only needed without JavaScript.
  */
 document.querySelectorAll('.js-buildContextSelect').forEach(el => {
   el.addEventListener('change', e => {
     window.location.search = `?GOOS=${(e.target as HTMLSelectElement).value}`;
   });
 });
+document.querySelectorAll('.js-buildContextSubmit').forEach(el => el.remove());
+
+/**
+ * Expand all examples in place. Without JavaScript, the link reloads the
+ * page with the examples query parameter, which renders them expanded.
+ */
+document.querySelectorAll('.js-expandAllExamples').forEach(el => {
+  el.addEventListener('click', e => {
+    e.preventDefault();
+    document.querySelectorAll<HTMLDetailsElement>('.js-exampleContainer').forEach(ex => {
+      ex.open = true;
+    });
+  });
+});
+
+/**
+ * If the fragment of the URL names a symbol that is not in the
+ * documentation, as with a link to a symbol that was renamed or removed,
+ * go to the nearest version of the package that has the symbol. The page
+ * there explains the redirect.
+ */
+async function redirectMissingSymbol() {
+  const vars = document.querySelector<HTMLElement>('.js-symbolVersionVars');
+  const symbol = decodeFragment(location.hash);
+  if (!vars || !/^[\p{L}_][\p{L}\p{N}_]*(\.[\p{L}_][\p{L}\p{N}_]*)?$/u.test(symbol)) return;
+  if (document.getElementById(symbol)) return;
+  const params = new URLSearchParams({
+    path: vars.dataset.path ?? '',
+    module: vars.dataset.modulepath ?? '',
+    version: vars.dataset.version ?? '',
+    symbol,
+  });
+  const response = await fetch(`/symbol-version?${params}`);
+  if (!response.ok) return;
+  const { url } = await response.json();
+  if (typeof url === 'string' && url.startsWith('/')) {
+    window.location.replace(url);
+  }
+}
+redirectMissingSymbol();
+window.addEventListener('hashchange', () => redirectMissingSymbol());
@@ -5,6 +5,8 @@
  * license that can be found in the LICENSE file.
  */
 
+import { decodeFragment } from 'static/shared/outline/tree';
+
 const headerHeight = 3.5;
 
 /**
@@ -107,3 +109,19 @@ export class MainLayoutController {
 
 const el = <T extends HTMLElement>(selector: string) => document.querySelector<T>(selector);
 new MainLayoutController(el('.js-mainHeader'), el('.js-mainNav'), el('.js-mainAside'));
+
+/**
+ * Carry the URL fragment of the page to the versions tab, whose links to
+ * other versions then keep it if the symbol it names is in those versions.
+ * The fragment is not sent to the server, so the link must add it.
+ */
+for (const a of document.querySelectorAll<HTMLAnchorElement>('.js-versionsLink')) {
+  a.addEventListener('click', () => {
+    const anchor = decodeFragment(location.hash);
+    if (!anchor) return;
+    const url = new URL(a.href);
+    url.searchParams.set('from', '');
+    url.searchParams.set('anchor', anchor);
+    a.href = url.toString();
+  });
+}
@@ -1,4 +1,4 @@
-var i=class{constructor(){this.expand=document.querySelector(".js-versionsExpand");this.collapse=document.querySelector(".js-versionsCollapse");this.details=[...document.querySelectorAll(".js-versionDetails")];var n,e,s;if((n=this.expand)!=null&&n.parentElement){this.details.some(t=>t.tagName==="DETAILS")&&(this.expand.parentElement.style.display="block");for(let t of this.details)t.addEventListener("click",()=>{this.updateButtons()});(e=this.expand)==null||e.addEventListener("click",()=>{this.details.map(t=>t.open=!0),this.updateButtons()}),(s=this.collapse)==null||s.addEventListener("click",()=>{this.details.map(t=>t.open=!1),this.updateButtons()}),this.updateButtons(),this.setCurrent()}}setCurrent(){var s,t;let n=(t=(s=document.querySelector(".js-canonicalURLPath"))==null?void 0:s.dataset)==null?void 0:t.canonicalUrlPath,e=document.querySelector(`.js-versionLink[href="${n}"]`);e&&(e.style.fontWeight="bold")}updateButtons(){setTimeout(()=>{if(!this.expand||!this.collapse)return;let n,e;for(let s of this.details)n=n||s.open,e=e||!s.open;this.expand.style.display=e?"inline-block":"none",this.collapse.style.display=e?"none":"inline-block"})}};new i;export{i as VersionsController};
+var l=class{constructor(){this.expand=document.querySelector(".js-versionsExpand");this.collapse=document.querySelector(".js-versionsCollapse");this.details=[...document.querySelectorAll(".js-versionDetails")];var t,e,s;if((t=this.expand)!=null&&t.parentElement){this.details.some(n=>n.tagName==="DETAILS")&&(this.expand.parentElement.style.display="block");for(let n of this.details)n.addEventListener("click",()=>{this.updateButtons()});(e=this.expand)==null||e.addEventListener("click",()=>{this.details.map(n=>n.open=!0),this.updateButtons()}),(s=this.collapse)==null||s.addEventListener("click",()=>{this.details.map(n=>n.open=!1),this.updateButtons()}),this.updateButtons(),this.setCurrent()}}setCurrent(){var s,n;let t=(n=(s=document.querySelector(".js-canonicalURLPath"))==null?void 0:s.dataset)==null?void 0:n.canonicalUrlPath,e=document.querySelector(`.js-versionLink[data-link="${t}"]`);e&&(e.style.fontWeight="bold")}updateButtons(){setTimeout(()=>{if(!this.expand||!this.collapse)return;let t,e;for(let s of this.details)t=t||s.open,e=e||!s.open;this.expand.style.display=e?"inline-block":"none",this.collapse.style.display=e?"none":"inline-block"})}};function o(){let i=document.querySelector(".js-versionsFilter");if(!i)return;let t=[...i.querySelectorAll("input, select")];i.addEventListener("submit",()=>{for(let e of t)e.disabled=e.value===""}),window.addEventListener("pageshow",()=>{for(let e of t)e.disabled=!1})}new l;o();export{l as VersionsController};
 /*!
  * @license
  * Copyright 2021 The Go Authors. All rights reserved.
@@ -52,7 +52,7 @@ export class VersionsController {
     const canonicalPath = document.querySelector<HTMLElement>('.js-canonicalURLPath')?.dataset
       ?.canonicalUrlPath;
     const versionLink = document.querySelector<HTMLElement>(
-      `.js-versionLink[href="${canonicalPath}"]`
+      `.js-versionLink[data-link="${canonicalPath}"]`
     );
     if (versionLink) {
       versionLink.style.fontWeight = 'bold';
@@ -73,4 +73,27 @@ export class VersionsController {
   }
 }
 
+/**
+ * omitEmptyFilterFields disables the blank fields of the form for filtering
+ * versions when it is submitted, so that the URL of the filtered list has
+ * only the params that are used.
+ */
+function omitEmptyFilterFields() {
+  const form = document.querySelector<HTMLFormElement>('.js-versionsFilter');
+  if (!form) return;
+  const fields = [...form.querySelectorAll<HTMLInputElement | HTMLSelectElement>('input, select')];
+  form.addEventListener('submit', () => {
+    for (const el of fields) {
+      el.disabled = el.value === '';
+    }
+  });
+  // Enable the fields again if the page is restored from the history.
+  window.addEventListener('pageshow', () => {
+    for (const el of fields) {
+      el.disabled = false;
+    }
+  });
+}
+
 new VersionsController();
+omitEmptyFilterFields();
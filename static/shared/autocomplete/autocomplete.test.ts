@@ -0,0 +1,65 @@
+/**
+ * @license
+ * Copyright 2026 The Go Authors. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+import { AutocompleteController, Suggestion } from './autocomplete';
+
+describe('AutocompleteController', () => {
+  let input: HTMLInputElement;
+  let datalist: HTMLDataListElement;
+  const suggestions: Suggestion[] = [
+    { text: 'encoding/json', url: '/encoding/json' },
+    { text: 'json.Marshal', url: '/encoding/json#Marshal' },
+  ];
+
+  beforeEach(() => {
+    document.body.innerHTML = `
+      <input class="js-autocomplete" list="Suggestions">
+      <datalist id="Suggestions"></datalist>`;
+    input = document.querySelector('input') as HTMLInputElement;
+    datalist = document.querySelector('datalist') as HTMLDataListElement;
+  });
+
+  afterEach(() => {
+    document.body.innerHTML = '';
+  });
+
+  const fakeFetch = jest.fn(() =>
+    Promise.resolve({ ok: true, json: () => Promise.resolve(suggestions) } as Response)
+  );
+
+  it('fills the datalist with suggestions', async () => {
+    const controller = new AutocompleteController(input, fakeFetch);
+    input.value = 'enc';
+    await controller.update();
+    expect(fakeFetch).toHaveBeenCalledWith('/autocomplete?q=enc');
+    const options = Array.from(datalist.querySelectorAll('option')).map(o => o.value);
+    expect(options).toEqual(['encoding/json', 'json.Marshal']);
+  });
+
+  it('requests suggestions from the namespace of the search form', async () => {
+    document.body.innerHTML = `
+      <form>
+        <input class="js-autocomplete" list="Suggestions">
+        <input name="ns" value="corp" hidden>
+      </form>
+      <datalist id="Suggestions"></datalist>`;
+    input = document.querySelector('input') as HTMLInputElement;
+    const controller = new AutocompleteController(input, fakeFetch);
+    input.value = 'enc';
+    await controller.update();
+    expect(fakeFetch).toHaveBeenCalledWith('/autocomplete?q=enc&ns=corp');
+  });
+
+  it('does not request suggestions for short queries', async () => {
+    fakeFetch.mockClear();
+    const controller = new AutocompleteController(input, fakeFetch);
+    input.value = 'e';
+    await controller.update();
+    expect(fakeFetch).not.toHaveBeenCalled();
+    expect(datalist.children.length).toBe(0);
+  });
+});
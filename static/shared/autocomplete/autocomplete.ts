@@ -0,0 +1,105 @@
+/**
+ * @license
+ * Copyright 2026 The Go Authors. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+/**
+ * A suggestion returned by the /autocomplete endpoint.
+ */
+export interface Suggestion {
+  text: string;
+  url: string;
+}
+
+/**
+ * The shortest query for which suggestions are requested. This matches the
+ * minimum used by the server.
+ */
+const MIN_PREFIX_LENGTH = 2;
+
+/**
+ * How long to wait after the user stops typing before requesting suggestions.
+ */
+const DEBOUNCE_MS = 200;
+
+/**
+ * AutocompleteController fills the datalist of a search input with
+ * suggestions from the /autocomplete endpoint as the user types. Choosing a
+ * suggestion navigates to its documentation.
+ */
+export class AutocompleteController {
+  private datalist: HTMLDataListElement | null;
+  private timeout: ReturnType<typeof setTimeout> | undefined;
+  private suggestions: Suggestion[] = [];
+  // The most recently requested query, so that stale responses are ignored.
+  private query = '';
+
+  /**
+   * @param input The search input. Its list attribute must name a datalist.
+   */
+  constructor(private input: HTMLInputElement, private fetchFn: typeof fetch = fetch) {
+    this.datalist = input.list as HTMLDataListElement | null;
+    if (!this.datalist) {
+      return;
+    }
+    input.addEventListener('input', e => this.handleInput(e));
+  }
+
+  private handleInput(e: Event): void {
+    // Selecting an option from the datalist fires an input event without
+    // an inputType.
+    if (!(e as InputEvent).inputType) {
+      const chosen = this.suggestions.find(s => s.text === this.input.value);
+      if (chosen) {
+        window.location.href = chosen.url;
+        return;
+      }
+    }
+    clearTimeout(this.timeout);
+    this.timeout = setTimeout(() => this.update(), DEBOUNCE_MS);
+  }
+
+  /**
+   * Requests suggestions for the current value of the input and displays
+   * them. On the pages of a namespace, the search form names the namespace
+   * in its ns input, and the suggestions come from that namespace.
+   */
+  async update(): Promise<void> {
+    const query = this.input.value.trim();
+    this.query = query;
+    if (query.length < MIN_PREFIX_LENGTH) {
+      this.render([]);
+      return;
+    }
+    try {
+      let url = '/autocomplete?q=' + encodeURIComponent(query);
+      const ns = this.input.form?.elements.namedItem('ns');
+      if (ns instanceof HTMLInputElement && ns.value) {
+        url += '&ns=' + encodeURIComponent(ns.value);
+      }
+      const resp = await this.fetchFn(url);
+      if (!resp.ok) {
+        return;
+      }
+      const suggestions = (await resp.json()) as Suggestion[];
+      if (query === this.query) {
+        this.render(suggestions);
+      }
+    } catch {
+      // Suggestions are optional; the user can still submit the search.
+    }
+  }
+
+  private render(suggestions: Suggestion[]): void {
+    this.suggestions = suggestions;
+    this.datalist?.replaceChildren(
+      ...suggestions.map(s => {
+        const option = document.createElement('option');
+        option.value = s.text;
+        return option;
+      })
+    );
+  }
+}
@@ -6,7 +6,7 @@
  */
 
 import { parse } from '../../markdown';
-import { TreeNavController } from './tree';
+import { TreeNavController, decodeFragment } from './tree';
 
 const observe = jest.fn();
 window.IntersectionObserver = jest.fn(() => ({
@@ -149,6 +149,12 @@ it('expands sibling items with * key', () => {
   expect(attr(a23, 'aria-expanded')).toBe('true');
 });
 
+it('decodes percent-encoded fragments', () => {
+  expect(decodeFragment('#%CE%94%CE%AD%CE%BB%CF%84%CE%B1')).toBe('Δέλτα');
+  expect(decodeFragment('Time.Equal')).toBe('Time.Equal');
+  expect(decodeFragment('#100%')).toBe('100%');
+});
+
 function a(href: string): HTMLElement {
   return document.querySelector(`[href="${href}"]`);
 }
@@ -66,8 +66,8 @@ export class TreeNavController {
         }
         for (const [id, isIntersecting] of targets) {
           if (isIntersecting) {
-            const active = this.treeitems.find(t =>
-              (t.el as HTMLAnchorElement)?.href.endsWith(`#${id}`)
+            const active = this.treeitems.find(
+              t => decodeFragment((t.el as HTMLAnchorElement)?.hash ?? '') === id
             );
             if (active) {
               for (const fn of this.observerCallbacks) {
@@ -87,7 +87,7 @@ export class TreeNavController {
     for (const href of this.treeitems.map(t => t.el.getAttribute('href'))) {
       if (href) {
         const id = href.replace(window.location.origin, '').replace('/', '').replace('#', '');
-        const target = document.getElementById(id);
+        const target = document.getElementById(decodeFragment(id));
         if (target) {
           observer.observe(target);
         }
@@ -481,3 +481,17 @@ function debounce<T extends (...args: any[]) => any>(func: T, wait: number) {
     timeout = setTimeout(later, wait);
   };
 }
+
+/**
+ * decodeFragment returns the element ID named by a URL fragment, with or
+ * without its leading '#'. Browsers percent-encode fragments that contain
+ * non-ASCII characters, such as anchors for Unicode identifiers.
+ */
+export function decodeFragment(fragment: string): string {
+  fragment = fragment.replace(/^#/, '');
+  try {
+    return decodeURIComponent(fragment);
+  } catch {
+    return fragment;
+  }
+}
@@ -90,6 +90,8 @@ export class PlaygroundExampleController {
     this.shareButtonEl?.addEventListener('click', () => this.handleShareButtonClick());
     this.formatButtonEl?.addEventListener('click', () => this.handleFormatButtonClick());
     this.runButtonEl?.addEventListener('click', () => this.handleRunButtonClick());
+    // The buttons are rendered hidden, since they do nothing without JS.
+    exampleEl.querySelector<HTMLElement>('.js-exampleButtons')?.removeAttribute('hidden');
 
     if (!this.inputEl) return;
 
@@ -37,6 +37,7 @@ var (
 		"Use the frontend host referred to by this URL for comparing data")
 	proxyURL = flag.String("proxy", "https://proxy.golang.org",
 		"Use the module proxy referred to by this URL for fetching packages")
+	clientFlags = client.AddFlags(flag.CommandLine)
 )
 
 func main() {
@@ -66,7 +67,11 @@ func main() {
 	if !*compareAll {
 		pkgPath, modulePath = parsePath(flag.Args()[1])
 	}
-	if err := run(ctx, cmd, pkgPath, modulePath, *frontendHost, *proxyURL, *compareAll); err != nil {
+	opts, err := clientFlags.Options(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := run(ctx, cmd, pkgPath, modulePath, client.NewWithOptions(*frontendHost, opts), *proxyURL, *compareAll); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -84,7 +89,7 @@ const (
 	tmpDir      = "/tmp/api"
 )
 
-func run(ctx context.Context, cmd, pkgPath, modulePath, frontendHost, proxyURL string, compareAll bool) error {
+func run(ctx context.Context, cmd, pkgPath, modulePath string, fc *client.Client, proxyURL string, compareAll bool) error {
 	switch cmd {
 	case "compare":
 		if compareAll {
@@ -93,13 +98,13 @@ func run(ctx context.Context, cmd, pkgPath, modulePath, frontendHost, proxyURL s
 				return err
 			}
 			for _, p := range pkgPaths {
-				if err := compare(frontendHost, p); err != nil {
+				if err := compare(fc, p); err != nil {
 					return err
 				}
 			}
 			return nil
 		}
-		return compare(frontendHost, pkgPath)
+		return compare(fc, pkgPath)
 	case "generate":
 		return generate(ctx, pkgPath, modulePath, tmpDir, proxyURL)
 	}
@@ -180,8 +185,8 @@ func allPackages() (_ []string, err error) {
 }
 
 // compare compares data from the testdata directory with the frontend.
-func compare(frontendHost, pkgPath string) (err error) {
-	defer derrors.Wrap(&err, "compare(ctx, %q, %q, %q)", frontendHost, pkgPath, testdataDir)
+func compare(fc *client.Client, pkgPath string) (err error) {
+	defer derrors.Wrap(&err, "compare(ctx, %q, %q)", pkgPath, testdataDir)
 	files, err := symbol.LoadAPIFiles(pkgPath, testdataDir)
 	if err != nil {
 		return err
@@ -192,8 +197,7 @@ func compare(frontendHost, pkgPath string) (err error) {
 	}
 
 	// Parse API data from the frontend versions page.
-	client := client.New(frontendHost)
-	vd, err := client.GetVersions(pkgPath)
+	vd, err := fc.GetVersions(pkgPath)
 	if err != nil {
 		return err
 	}
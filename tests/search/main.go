@@ -27,8 +27,11 @@ import (
 	"golang.org/x/pkgsite/internal/postgres"
 )
 
-var frontendHost = flag.String("frontend", "http://localhost:8080",
-	"Use the frontend host referred to by this URL for comparing data")
+var (
+	frontendHost = flag.String("frontend", "http://localhost:8080",
+		"Use the frontend host referred to by this URL for comparing data")
+	clientFlags = client.AddFlags(flag.CommandLine)
+)
 
 func main() {
 	flag.Parse()
@@ -43,7 +46,11 @@ func main() {
 	if err := runImportedByUpdates(ctx, cfg.DBConnInfo(), cfg.DBHost); err != nil {
 		log.Fatal(ctx, err)
 	}
-	if err := run(*frontendHost); err != nil {
+	opts, err := clientFlags.Options(ctx)
+	if err != nil {
+		log.Fatal(ctx, err)
+	}
+	if err := run(*frontendHost, opts); err != nil {
 		log.Fatal(ctx, err)
 	}
 }
@@ -72,7 +79,7 @@ func runImportedByUpdates(ctx context.Context, dbConnInfo, dbHost string) error
 	return err
 }
 
-func run(frontendHost string) error {
+func run(frontendHost string, opts *client.Options) error {
 	var tests []*searchTest
 	for _, testFile := range testFiles {
 		ts, err := readSearchTests(testFile)
@@ -81,7 +88,7 @@ func run(frontendHost string) error {
 		}
 		tests = append(tests, ts...)
 	}
-	client := client.New(frontendHost)
+	client := client.NewWithOptions(frontendHost, opts)
 	var failed bool
 	for _, st := range tests {
 		output, err := runTest(client, st)
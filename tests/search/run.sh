@@ -16,7 +16,7 @@ main() {
   export GO_DISCOVERY_DATABASE_NAME=discovery_symbol_test
   export GO_DISCOVERY_CONFIG_DYNAMIC=tests/search/config.yaml
   export GO_DISCOVERY_SEED_DB_FILE=tests/search/seed.txt
-  dockercompose build && dockercompose run --rm seeddb && dockercompose run --rm searchtest
+  dockercompose build && dockercompose run --rm seeddb && dockercompose run --rm queryplan && dockercompose run --rm searchtest
 
   local status=$?
   if [ $status -eq 0 ]